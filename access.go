@@ -0,0 +1,18 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package mainflux
+
+// File access.go contains the actions the adapters request the channel
+// access for, which are matched against the scopes of the thing keys.
+
+// ActionPublish represents publishing messages to the channel.
+const ActionPublish = "publish"
+
+// ActionSubscribe represents receiving messages published to the channel,
+// either as they are published or by reading the stored ones.
+const ActionSubscribe = "subscribe"
@@ -259,3 +259,94 @@ func stateEndpoint(svc bootstrap.Service) endpoint.Endpoint {
 		return stateRes{}, nil
 	}
 }
+
+func addClaimEndpoint(svc bootstrap.Service) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(addClaimReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		claim := bootstrap.Claim{
+			ExternalID:  req.ExternalID,
+			ExternalKey: req.ExternalKey,
+			Code:        req.Code,
+			Name:        req.Name,
+			Content:     req.Content,
+		}
+
+		if err := svc.AddClaim(req.key, claim); err != nil {
+			return nil, err
+		}
+
+		return claimRes{}, nil
+	}
+}
+
+func listClaimsEndpoint(svc bootstrap.Service) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(listClaimsReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		page, err := svc.ListClaims(req.key, req.offset, req.limit)
+		if err != nil {
+			return nil, err
+		}
+
+		res := listClaimsRes{
+			Total:  page.Total,
+			Offset: page.Offset,
+			Limit:  page.Limit,
+			Claims: []claimView{},
+		}
+
+		for _, c := range page.Claims {
+			res.Claims = append(res.Claims, claimView{
+				ExternalID:  c.ExternalID,
+				ExternalKey: c.ExternalKey,
+				Name:        c.Name,
+				Content:     c.Content,
+			})
+		}
+
+		return res, nil
+	}
+}
+
+func removeClaimEndpoint(svc bootstrap.Service) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(entityReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RemoveClaim(req.key, req.id); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func claimEndpoint(svc bootstrap.Service) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(claimReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		saved, err := svc.Claim(req.key, req.Code)
+		if err != nil {
+			return nil, err
+		}
+
+		res := configRes{
+			id:      saved.MFThing,
+			created: true,
+		}
+
+		return res, nil
+	}
+}
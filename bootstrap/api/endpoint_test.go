@@ -36,7 +36,7 @@ const (
 	unknown        = "unknown"
 	channelsNum    = 3
 	contentType    = "application/json"
-	wrongID        = "wrong_id"
+	wrongID        = "123e4567-e89b-12d3-a456-999999999999"
 	addExternalID  = "external-id"
 	addExternalKey = "external-key"
 	addName        = "name"
@@ -44,7 +44,7 @@ const (
 )
 
 var (
-	addChannels = []string{"1"}
+	addChannels = []string{chanID(1)}
 	metadata    = map[string]interface{}{"meta": "data"}
 	addReq      = struct {
 		ThingID     string   `json:"thing_id"`
@@ -56,7 +56,7 @@ var (
 	}{
 		ExternalID:  "external-id",
 		ExternalKey: "external-key",
-		Channels:    []string{"1"},
+		Channels:    []string{chanID(1)},
 		Name:        "name",
 		Content:     "config",
 	}
@@ -69,7 +69,7 @@ var (
 		ClientKey  string          `json:"client_key,omitempty"`
 		CACert     string          `json:"ca_cert,omitempty"`
 	}{
-		Channels:   []string{"2", "3"},
+		Channels:   []string{chanID(2), chanID(3)},
 		Content:    "config update",
 		State:      1,
 		ClientCert: "newcert",
@@ -122,13 +122,13 @@ func newService(users mainflux.UsersServiceClient, unknown map[string]string, ur
 	}
 
 	sdk := mfsdk.NewSDK(config)
-	return bootstrap.New(users, things, sdk)
+	return bootstrap.New(users, things, mocks.NewClaimsRepository(), sdk)
 }
 
 func generateChannels() map[string]things.Channel {
 	channels := make(map[string]things.Channel, channelsNum)
 	for i := 0; i < channelsNum; i++ {
-		id := strconv.Itoa(i + 1)
+		id := chanID(i + 1)
 		channels[id] = things.Channel{
 			ID:       id,
 			Owner:    email,
@@ -142,6 +142,11 @@ func newThingsService(users mainflux.UsersServiceClient) things.Service {
 	return mocks.NewThingsService(map[string]things.Thing{}, generateChannels(), users)
 }
 
+// chanID returns the identifier of the n-th channel of the Things service.
+func chanID(n int) string {
+	return fmt.Sprintf("123e4567-e89b-12d3-a456-%012d", n)
+}
+
 func newThingsServer(svc things.Service) *httptest.Server {
 	mux := thingsapi.MakeHandler(svc)
 	return httptest.NewServer(mux)
@@ -167,7 +172,7 @@ func TestAdd(t *testing.T) {
 	data := toJSON(addReq)
 
 	neID := addReq
-	neID.ThingID = "non-existent"
+	neID.ThingID = wrongID
 	neData := toJSON(neID)
 
 	invalidChannels := addReq
@@ -196,7 +201,7 @@ func TestAdd(t *testing.T) {
 			auth:        validToken,
 			contentType: contentType,
 			status:      http.StatusCreated,
-			location:    "/things/configs/1",
+			location:    "/things/configs/123e4567-e89b-12d3-a456-000000000001",
 		},
 		{
 			desc:        "add a config with wring content type",
@@ -345,6 +350,13 @@ func TestView(t *testing.T) {
 			status: http.StatusNotFound,
 			res:    config{},
 		},
+		{
+			desc:   "view a config with malformed id",
+			auth:   validToken,
+			id:     "invalid",
+			status: http.StatusBadRequest,
+			res:    config{},
+		},
 		{
 			desc:   "view a config with an empty token",
 			auth:   "",
@@ -385,7 +397,7 @@ func TestUpdate(t *testing.T) {
 	svc := newService(users, nil, ts.URL)
 	bs := newBootstrapServer(svc)
 
-	c := newConfig([]bootstrap.Channel{bootstrap.Channel{ID: "1"}})
+	c := newConfig([]bootstrap.Channel{bootstrap.Channel{ID: chanID(1)}})
 
 	saved, err := svc.Add(validToken, c)
 	require.Nil(t, err, fmt.Sprintf("Saving config expected to succeed: %s.\n", err))
@@ -479,7 +491,7 @@ func TestUpdateCert(t *testing.T) {
 	svc := newService(users, nil, ts.URL)
 	bs := newBootstrapServer(svc)
 
-	c := newConfig([]bootstrap.Channel{bootstrap.Channel{ID: "1"}})
+	c := newConfig([]bootstrap.Channel{bootstrap.Channel{ID: chanID(1)}})
 
 	saved, err := svc.Add(validToken, c)
 	require.Nil(t, err, fmt.Sprintf("Saving config expected to succeed: %s.\n", err))
@@ -574,7 +586,7 @@ func TestUpdateConnections(t *testing.T) {
 	svc := newService(users, nil, ts.URL)
 	bs := newBootstrapServer(svc)
 
-	c := newConfig([]bootstrap.Channel{bootstrap.Channel{ID: "1"}})
+	c := newConfig([]bootstrap.Channel{bootstrap.Channel{ID: chanID(1)}})
 
 	saved, err := svc.Add(validToken, c)
 	require.Nil(t, err, fmt.Sprintf("Saving config expected to succeed: %s.\n", err))
@@ -687,7 +699,7 @@ func TestList(t *testing.T) {
 	bs := newBootstrapServer(svc)
 	path := fmt.Sprintf("%s/%s", bs.URL, "things/configs")
 
-	c := newConfig([]bootstrap.Channel{bootstrap.Channel{ID: "1"}})
+	c := newConfig([]bootstrap.Channel{bootstrap.Channel{ID: chanID(1)}})
 
 	for i := 0; i < configNum; i++ {
 		c.ExternalID = strconv.Itoa(i)
@@ -935,7 +947,7 @@ func TestRemove(t *testing.T) {
 	svc := newService(users, nil, ts.URL)
 	bs := newBootstrapServer(svc)
 
-	c := newConfig([]bootstrap.Channel{bootstrap.Channel{ID: "1"}})
+	c := newConfig([]bootstrap.Channel{bootstrap.Channel{ID: chanID(1)}})
 
 	saved, err := svc.Add(validToken, c)
 	require.Nil(t, err, fmt.Sprintf("Saving config expected to succeed: %s.\n", err))
@@ -959,7 +971,7 @@ func TestRemove(t *testing.T) {
 		},
 		{
 			desc:   "remove non-existing config",
-			id:     "non-existing",
+			id:     wrongID,
 			auth:   validToken,
 			status: http.StatusNoContent,
 		},
@@ -1094,7 +1106,7 @@ func TestBootstrap(t *testing.T) {
 	svc := newService(users, map[string]string{}, ts.URL)
 	bs := newBootstrapServer(svc)
 
-	c := newConfig([]bootstrap.Channel{bootstrap.Channel{ID: "1"}})
+	c := newConfig([]bootstrap.Channel{bootstrap.Channel{ID: chanID(1)}})
 
 	saved, err := svc.Add(validToken, c)
 	require.Nil(t, err, fmt.Sprintf("Saving config expected to succeed: %s.\n", err))
@@ -1188,7 +1200,7 @@ func TestChangeState(t *testing.T) {
 	svc := newService(users, nil, ts.URL)
 	bs := newBootstrapServer(svc)
 
-	c := newConfig([]bootstrap.Channel{bootstrap.Channel{ID: "1"}})
+	c := newConfig([]bootstrap.Channel{bootstrap.Channel{ID: chanID(1)}})
 
 	saved, err := svc.Add(validToken, c)
 	require.Nil(t, err, fmt.Sprintf("Saving config expected to succeed: %s.\n", err))
@@ -1308,3 +1320,141 @@ type configPage struct {
 	Limit   uint64   `json:"limit"`
 	Configs []config `json:"configs"`
 }
+
+func TestClaim(t *testing.T) {
+	customerToken := "customerToken"
+	users := mocks.NewUsersService(map[string]string{validToken: email, customerToken: "customer@example.com"})
+
+	ts := newThingsServer(newThingsService(users))
+	svc := newService(users, nil, ts.URL)
+	bs := newBootstrapServer(svc)
+
+	claim := struct {
+		ExternalID  string `json:"external_id"`
+		ExternalKey string `json:"external_key"`
+		Code        string `json:"code"`
+		Content     string `json:"content"`
+	}{
+		ExternalID:  addExternalID,
+		ExternalKey: addExternalKey,
+		Code:        "claim-code",
+		Content:     addContent,
+	}
+	data := toJSON(claim)
+
+	shortCode := claim
+	shortCode.Code = "code"
+	shortData := toJSON(shortCode)
+
+	addCases := []struct {
+		desc        string
+		req         string
+		auth        string
+		contentType string
+		status      int
+	}{
+		{
+			desc:        "add a claim unauthorized",
+			req:         data,
+			auth:        invalidToken,
+			contentType: contentType,
+			status:      http.StatusForbidden,
+		},
+		{
+			desc:        "add a claim with too short code",
+			req:         shortData,
+			auth:        validToken,
+			contentType: contentType,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "add a claim with wrong content type",
+			req:         data,
+			auth:        validToken,
+			contentType: "",
+			status:      http.StatusUnsupportedMediaType,
+		},
+		{
+			desc:        "add a valid claim",
+			req:         data,
+			auth:        validToken,
+			contentType: contentType,
+			status:      http.StatusCreated,
+		},
+		{
+			desc:        "add an existing claim",
+			req:         data,
+			auth:        validToken,
+			contentType: contentType,
+			status:      http.StatusConflict,
+		},
+	}
+
+	for _, tc := range addCases {
+		req := testRequest{
+			client:      bs.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/things/claims", bs.URL),
+			contentType: tc.contentType,
+			token:       tc.auth,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+
+	claimCases := []struct {
+		desc     string
+		req      string
+		auth     string
+		status   int
+		location string
+	}{
+		{
+			desc:     "claim a device with a wrong code",
+			req:      toJSON(map[string]string{"code": "wrong-code"}),
+			auth:     customerToken,
+			status:   http.StatusNotFound,
+			location: "",
+		},
+		{
+			desc:     "claim a device with an empty code",
+			req:      "{}",
+			auth:     customerToken,
+			status:   http.StatusBadRequest,
+			location: "",
+		},
+		{
+			desc:     "claim a device",
+			req:      toJSON(map[string]string{"code": claim.Code}),
+			auth:     customerToken,
+			status:   http.StatusCreated,
+			location: "/things/configs/123e4567-e89b-12d3-a456-000000000001",
+		},
+		{
+			desc:     "claim an already claimed device",
+			req:      toJSON(map[string]string{"code": claim.Code}),
+			auth:     customerToken,
+			status:   http.StatusNotFound,
+			location: "",
+		},
+	}
+
+	for _, tc := range claimCases {
+		req := testRequest{
+			client:      bs.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/things/claim", bs.URL),
+			contentType: contentType,
+			token:       tc.auth,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+
+		location := res.Header.Get("Location")
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		assert.Equal(t, tc.location, location, fmt.Sprintf("%s: expected location '%s' got '%s'", tc.desc, tc.location, location))
+	}
+}
@@ -185,6 +185,45 @@ func (lm *loggingMiddleware) RemoveChannelHandler(id string) (err error) {
 	return lm.svc.RemoveChannelHandler(id)
 }
 
+func (lm *loggingMiddleware) RemoveUserHandler(owner string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method remove_user_handler for user %s took %s to complete", owner, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RemoveUserHandler(owner)
+}
+
+func (lm *loggingMiddleware) UpdateKeyHandler(thingID, key string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method update_key_handler for thing %s took %s to complete", thingID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.UpdateKeyHandler(thingID, key)
+}
+
+func (lm *loggingMiddleware) AddConfigHandler(cfg bootstrap.Config) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method add_config_handler for thing %s took %s to complete", cfg.MFThing, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.AddConfigHandler(cfg)
+}
+
 func (lm *loggingMiddleware) DisconnectThingHandler(channelID, thingID string) (err error) {
 	defer func(begin time.Time) {
 		message := fmt.Sprintf("Method disconnect_thing_handler for channel %s and thing %s took %s to complete", channelID, thingID, time.Since(begin))
@@ -197,3 +236,55 @@ func (lm *loggingMiddleware) DisconnectThingHandler(channelID, thingID string) (
 
 	return lm.svc.DisconnectThingHandler(channelID, thingID)
 }
+
+func (lm *loggingMiddleware) AddClaim(key string, claim bootstrap.Claim) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method add_claim for key %s and device %s took %s to complete", key, claim.ExternalID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.AddClaim(key, claim)
+}
+
+func (lm *loggingMiddleware) ListClaims(key string, offset, limit uint64) (page bootstrap.ClaimsPage, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_claims for key %s took %s to complete", key, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListClaims(key, offset, limit)
+}
+
+func (lm *loggingMiddleware) RemoveClaim(key, externalID string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method remove_claim for key %s and device %s took %s to complete", key, externalID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RemoveClaim(key, externalID)
+}
+
+func (lm *loggingMiddleware) Claim(key, code string) (cfg bootstrap.Config, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method claim for key %s and device %s took %s to complete", key, cfg.ExternalID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Claim(key, code)
+}
@@ -142,6 +142,33 @@ func (mm *metricsMiddleware) RemoveChannelHandler(id string) (err error) {
 	return mm.svc.RemoveChannelHandler(id)
 }
 
+func (mm *metricsMiddleware) RemoveUserHandler(owner string) (err error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "remove_user_handler").Add(1)
+		mm.latency.With("method", "remove_user_handler").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.RemoveUserHandler(owner)
+}
+
+func (mm *metricsMiddleware) UpdateKeyHandler(thingID, key string) (err error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "update_key_handler").Add(1)
+		mm.latency.With("method", "update_key_handler").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.UpdateKeyHandler(thingID, key)
+}
+
+func (mm *metricsMiddleware) AddConfigHandler(cfg bootstrap.Config) (err error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "add_config_handler").Add(1)
+		mm.latency.With("method", "add_config_handler").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.AddConfigHandler(cfg)
+}
+
 func (mm *metricsMiddleware) DisconnectThingHandler(channelID, thingID string) (err error) {
 	defer func(begin time.Time) {
 		mm.counter.With("method", "disconnect_thing_handler").Add(1)
@@ -150,3 +177,39 @@ func (mm *metricsMiddleware) DisconnectThingHandler(channelID, thingID string) (
 
 	return mm.svc.DisconnectThingHandler(channelID, thingID)
 }
+
+func (mm *metricsMiddleware) AddClaim(key string, claim bootstrap.Claim) (err error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "add_claim").Add(1)
+		mm.latency.With("method", "add_claim").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.AddClaim(key, claim)
+}
+
+func (mm *metricsMiddleware) ListClaims(key string, offset, limit uint64) (page bootstrap.ClaimsPage, err error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "list_claims").Add(1)
+		mm.latency.With("method", "list_claims").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.ListClaims(key, offset, limit)
+}
+
+func (mm *metricsMiddleware) RemoveClaim(key, externalID string) (err error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "remove_claim").Add(1)
+		mm.latency.With("method", "remove_claim").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.RemoveClaim(key, externalID)
+}
+
+func (mm *metricsMiddleware) Claim(key, code string) (cfg bootstrap.Config, err error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "claim").Add(1)
+		mm.latency.With("method", "claim").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.Claim(key, code)
+}
@@ -9,6 +9,10 @@ package api
 
 import "github.com/mainflux/mainflux/bootstrap"
 
+// Claim codes are the only secret a customer needs to take over a device,
+// so they must be long enough not to be guessed.
+const minCodeLen = 8
+
 type apiReq interface {
 	validate() error
 }
@@ -170,3 +174,59 @@ func (req changeStateReq) validate() error {
 
 	return nil
 }
+
+type addClaimReq struct {
+	key         string
+	ExternalID  string `json:"external_id"`
+	ExternalKey string `json:"external_key"`
+	Code        string `json:"code"`
+	Name        string `json:"name"`
+	Content     string `json:"content"`
+}
+
+func (req addClaimReq) validate() error {
+	if req.key == "" {
+		return bootstrap.ErrUnauthorizedAccess
+	}
+
+	if req.ExternalID == "" || req.ExternalKey == "" || len(req.Code) < minCodeLen {
+		return bootstrap.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type listClaimsReq struct {
+	key    string
+	offset uint64
+	limit  uint64
+}
+
+func (req listClaimsReq) validate() error {
+	if req.key == "" {
+		return bootstrap.ErrUnauthorizedAccess
+	}
+
+	if req.limit == 0 || req.limit > maxLimit {
+		return bootstrap.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type claimReq struct {
+	key  string
+	Code string `json:"code"`
+}
+
+func (req claimReq) validate() error {
+	if req.key == "" {
+		return bootstrap.ErrUnauthorizedAccess
+	}
+
+	if req.Code == "" {
+		return bootstrap.ErrMalformedEntity
+	}
+
+	return nil
+}
@@ -21,6 +21,8 @@ var (
 	_ mainflux.Response = (*stateRes)(nil)
 	_ mainflux.Response = (*viewRes)(nil)
 	_ mainflux.Response = (*listRes)(nil)
+	_ mainflux.Response = (*claimRes)(nil)
+	_ mainflux.Response = (*listClaimsRes)(nil)
 )
 
 type removeRes struct{}
@@ -146,3 +148,43 @@ func (res stateRes) Headers() map[string]string {
 func (res stateRes) Empty() bool {
 	return true
 }
+
+type claimRes struct{}
+
+func (res claimRes) Code() int {
+	return http.StatusCreated
+}
+
+func (res claimRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res claimRes) Empty() bool {
+	return true
+}
+
+type claimView struct {
+	ExternalID  string `json:"external_id"`
+	ExternalKey string `json:"external_key,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Content     string `json:"content,omitempty"`
+}
+
+type listClaimsRes struct {
+	Total  uint64      `json:"total"`
+	Offset uint64      `json:"offset"`
+	Limit  uint64      `json:"limit"`
+	Claims []claimView `json:"claims"`
+}
+
+func (res listClaimsRes) Code() int {
+	return http.StatusOK
+}
+
+func (res listClaimsRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res listClaimsRes) Empty() bool {
+	return false
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/go-zoo/bone"
 	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/bootstrap"
+	"github.com/mainflux/mainflux/identifiers"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
@@ -104,6 +105,30 @@ func MakeHandler(svc bootstrap.Service, reader bootstrap.ConfigReader) http.Hand
 		encodeResponse,
 		opts...))
 
+	r.Post("/things/claims", kithttp.NewServer(
+		addClaimEndpoint(svc),
+		decodeAddClaimRequest,
+		encodeResponse,
+		opts...))
+
+	r.Get("/things/claims", kithttp.NewServer(
+		listClaimsEndpoint(svc),
+		decodeListClaimsRequest,
+		encodeResponse,
+		opts...))
+
+	r.Delete("/things/claims/:id", kithttp.NewServer(
+		removeClaimEndpoint(svc),
+		decodeEntityRequest,
+		encodeResponse,
+		opts...))
+
+	r.Post("/things/claim", kithttp.NewServer(
+		claimEndpoint(svc),
+		decodeClaimRequest,
+		encodeResponse,
+		opts...))
+
 	r.GetFunc("/version", mainflux.Version("bootstrap"))
 	r.Handle("/metrics", promhttp.Handler())
 
@@ -128,8 +153,13 @@ func decodeUpdateRequest(_ context.Context, r *http.Request) (interface{}, error
 		return nil, errUnsupportedContentType
 	}
 
+	id, err := identifiers.Parse(bone.GetValue(r, "id"))
+	if err != nil {
+		return nil, err
+	}
+
 	req := updateReq{key: r.Header.Get("Authorization")}
-	req.id = bone.GetValue(r, "id")
+	req.id = id
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		return nil, err
 	}
@@ -156,8 +186,13 @@ func decodeUpdateConnRequest(_ context.Context, r *http.Request) (interface{}, e
 		return nil, errUnsupportedContentType
 	}
 
+	id, err := identifiers.Parse(bone.GetValue(r, "id"))
+	if err != nil {
+		return nil, err
+	}
+
 	req := updateConnReq{key: r.Header.Get("Authorization")}
-	req.id = bone.GetValue(r, "id")
+	req.id = id
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		return nil, err
 	}
@@ -223,8 +258,59 @@ func decodeStateRequest(_ context.Context, r *http.Request) (interface{}, error)
 		return nil, errUnsupportedContentType
 	}
 
+	id, err := identifiers.Parse(bone.GetValue(r, "id"))
+	if err != nil {
+		return nil, err
+	}
+
 	req := changeStateReq{key: r.Header.Get("Authorization")}
-	req.id = bone.GetValue(r, "id")
+	req.id = id
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func decodeAddClaimRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+
+	req := addClaimReq{key: r.Header.Get("Authorization")}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func decodeListClaimsRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	q, err := url.ParseQuery(r.URL.RawQuery)
+	if err != nil {
+		return nil, errInvalidQueryParams
+	}
+
+	offset, limit, err := parsePagePrams(q)
+	if err != nil {
+		return nil, err
+	}
+
+	req := listClaimsReq{
+		key:    r.Header.Get("Authorization"),
+		offset: offset,
+		limit:  limit,
+	}
+
+	return req, nil
+}
+
+func decodeClaimRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+
+	req := claimReq{key: r.Header.Get("Authorization")}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		return nil, err
 	}
@@ -233,9 +319,14 @@ func decodeStateRequest(_ context.Context, r *http.Request) (interface{}, error)
 }
 
 func decodeEntityRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	id, err := identifiers.Parse(bone.GetValue(r, "id"))
+	if err != nil {
+		return nil, err
+	}
+
 	req := entityReq{
 		key: r.Header.Get("Authorization"),
-		id:  bone.GetValue(r, "id"),
+		id:  id,
 	}
 
 	return req, nil
@@ -264,7 +355,7 @@ func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 	switch err {
 	case errUnsupportedContentType:
 		w.WriteHeader(http.StatusUnsupportedMediaType)
-	case errInvalidQueryParams, bootstrap.ErrMalformedEntity:
+	case errInvalidQueryParams, bootstrap.ErrMalformedEntity, identifiers.ErrMalformedID:
 		w.WriteHeader(http.StatusBadRequest)
 	case bootstrap.ErrNotFound:
 		w.WriteHeader(http.StatusNotFound)
@@ -0,0 +1,50 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package bootstrap
+
+// Claim represents a manufactured device waiting in the pool of unclaimed
+// devices. The device is transferred to the user presenting its claim code,
+// which is usually printed on the device or its packaging.
+// Manufacturer is the owner of the pool the device is added to.
+// Code holds the hash of the claim code once the claim is saved.
+type Claim struct {
+	ExternalID   string
+	ExternalKey  string
+	Code         string
+	Manufacturer string
+	Name         string
+	Content      string
+}
+
+// ClaimsPage contains page related metadata as well as list of Claims that
+// belong to this page.
+type ClaimsPage struct {
+	Total  uint64
+	Offset uint64
+	Limit  uint64
+	Claims []Claim
+}
+
+// ClaimRepository specifies a Claim persistence API.
+type ClaimRepository interface {
+	// Save persists the Claim. ErrConflict is returned if a device with the
+	// same external ID or claim code is already in the pool.
+	Save(Claim) error
+
+	// RetrieveAll retrieves a subset of Claims added by the specified
+	// manufacturer.
+	RetrieveAll(string, uint64, uint64) (ClaimsPage, error)
+
+	// Remove removes the Claim having the provided external ID, that is
+	// added by the specified manufacturer.
+	Remove(string, string) error
+
+	// Take removes the Claim having the provided claim code hash from the
+	// pool and returns it, so that the device can be claimed only once.
+	Take(string) (Claim, error)
+}
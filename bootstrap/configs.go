@@ -110,4 +110,10 @@ type ConfigRepository interface {
 	// DisconnectHandler changes state of the Config when the corresponding Thing is
 	// disconnected from the Channel.
 	DisconnectThing(string, string) error
+
+	// RemoveUser removes all Configs and Channels owned by the given user.
+	RemoveUser(string) error
+
+	// UpdateThingKey updates the key of the Thing with the given ID.
+	UpdateThingKey(string, string) error
 }
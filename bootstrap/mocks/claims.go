@@ -0,0 +1,105 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package mocks
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/mainflux/mainflux/bootstrap"
+)
+
+var _ bootstrap.ClaimRepository = (*claimRepositoryMock)(nil)
+
+type claimRepositoryMock struct {
+	mu     sync.Mutex
+	claims map[string]bootstrap.Claim
+}
+
+// NewClaimsRepository creates in-memory claim repository.
+func NewClaimsRepository() bootstrap.ClaimRepository {
+	return &claimRepositoryMock{
+		claims: make(map[string]bootstrap.Claim),
+	}
+}
+
+func (crm *claimRepositoryMock) Save(claim bootstrap.Claim) error {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	for _, c := range crm.claims {
+		if c.ExternalID == claim.ExternalID || c.Code == claim.Code {
+			return bootstrap.ErrConflict
+		}
+	}
+
+	crm.claims[claim.ExternalID] = claim
+
+	return nil
+}
+
+func (crm *claimRepositoryMock) RetrieveAll(manufacturer string, offset, limit uint64) (bootstrap.ClaimsPage, error) {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	claims := []bootstrap.Claim{}
+	for _, c := range crm.claims {
+		if c.Manufacturer == manufacturer {
+			claims = append(claims, c)
+		}
+	}
+
+	sort.SliceStable(claims, func(i, j int) bool {
+		return claims[i].ExternalID < claims[j].ExternalID
+	})
+
+	total := uint64(len(claims))
+	page := bootstrap.ClaimsPage{
+		Total:  total,
+		Offset: offset,
+		Limit:  limit,
+		Claims: []bootstrap.Claim{},
+	}
+
+	if offset >= total {
+		return page, nil
+	}
+
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+	page.Claims = claims[offset:end]
+
+	return page, nil
+}
+
+func (crm *claimRepositoryMock) Remove(manufacturer, externalID string) error {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	if c, ok := crm.claims[externalID]; ok && c.Manufacturer == manufacturer {
+		delete(crm.claims, externalID)
+	}
+
+	return nil
+}
+
+func (crm *claimRepositoryMock) Take(code string) (bootstrap.Claim, error) {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	for id, c := range crm.claims {
+		if c.Code == code {
+			delete(crm.claims, id)
+			return c, nil
+		}
+	}
+
+	return bootstrap.Claim{}, bootstrap.ErrNotFound
+}
@@ -0,0 +1,29 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package mocks
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// uuidPrefix starts the identifiers generated by the mocks, which are valid
+// UUIDs ending with the sequence number of the entity.
+const uuidPrefix = "123e4567-e89b-12d3-a456-"
+
+// newID returns the identifier of the entity having the sequence number.
+func newID(seq uint64) string {
+	return fmt.Sprintf("%s%012d", uuidPrefix, seq)
+}
+
+// seqNumber returns the sequence number of the entity having the identifier.
+func seqNumber(id string) uint64 {
+	seq, _ := strconv.ParseUint(strings.TrimPrefix(id, uuidPrefix), 10, 64)
+	return seq
+}
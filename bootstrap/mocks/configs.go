@@ -51,7 +51,7 @@ func (crm *configRepositoryMock) Save(config bootstrap.Config, connections []str
 	}
 
 	crm.counter++
-	config.MFThing = strconv.FormatUint(crm.counter, 10)
+	config.MFThing = newID(crm.counter)
 	crm.configs[config.MFThing] = config
 
 	for _, ch := range config.MFChannels {
@@ -111,7 +111,7 @@ func (crm *configRepositoryMock) RetrieveAll(key string, filter bootstrap.Filter
 
 	var total uint64
 	for _, v := range crm.configs {
-		id, _ := strconv.ParseUint(v.MFThing, 10, 64)
+		id := seqNumber(v.MFThing)
 		if (state == emptyState || v.State == state) &&
 			(name == "" || strings.Index(strings.ToLower(v.Name), name) != notFoundIdx) &&
 			v.Owner == key {
@@ -366,3 +366,35 @@ func (crm *configRepositoryMock) DisconnectThing(channelID, thingID string) erro
 
 	return nil
 }
+
+func (crm *configRepositoryMock) UpdateThingKey(id, key string) error {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	config, ok := crm.configs[id]
+	if !ok {
+		return nil
+	}
+	config.MFKey = key
+	crm.configs[id] = config
+
+	return nil
+}
+
+func (crm *configRepositoryMock) RemoveUser(owner string) error {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	for id, config := range crm.configs {
+		if config.Owner != owner {
+			continue
+		}
+
+		for _, ch := range config.MFChannels {
+			delete(crm.channels, ch.ID)
+		}
+		delete(crm.configs, id)
+	}
+
+	return nil
+}
@@ -32,3 +32,12 @@ func (svc usersServiceMock) Identify(ctx context.Context, in *mainflux.Token, op
 	}
 	return nil, users.ErrUnauthorizedAccess
 }
+
+func (svc usersServiceMock) Exists(ctx context.Context, in *mainflux.UserID, opts ...grpc.CallOption) (*mainflux.UserID, error) {
+	for _, id := range svc.users {
+		if id == in.Value {
+			return &mainflux.UserID{Value: id}, nil
+		}
+	}
+	return nil, users.ErrNotFound
+}
@@ -0,0 +1,129 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package postgres
+
+import (
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/mainflux/mainflux/bootstrap"
+)
+
+var _ bootstrap.ClaimRepository = (*claimRepository)(nil)
+
+type claimRepository struct {
+	db *sqlx.DB
+}
+
+// NewClaimRepository instantiates a PostgreSQL implementation of claim
+// repository.
+func NewClaimRepository(db *sqlx.DB) bootstrap.ClaimRepository {
+	return &claimRepository{db: db}
+}
+
+func (cr claimRepository) Save(claim bootstrap.Claim) error {
+	q := `INSERT INTO claims (external_id, external_key, code, manufacturer, name, content)
+		  VALUES (:external_id, :external_key, :code, :manufacturer, :name, :content)`
+
+	if _, err := cr.db.NamedExec(q, toDBClaim(claim)); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == duplicateErr {
+			return bootstrap.ErrConflict
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (cr claimRepository) RetrieveAll(manufacturer string, offset, limit uint64) (bootstrap.ClaimsPage, error) {
+	q := `SELECT external_id, external_key, code, manufacturer, name, content FROM claims
+		  WHERE manufacturer = $1 ORDER BY external_id LIMIT $2 OFFSET $3`
+
+	rows, err := cr.db.Queryx(q, manufacturer, limit, offset)
+	if err != nil {
+		return bootstrap.ClaimsPage{}, err
+	}
+	defer rows.Close()
+
+	items := []bootstrap.Claim{}
+	for rows.Next() {
+		dbc := dbClaim{}
+		if err := rows.StructScan(&dbc); err != nil {
+			return bootstrap.ClaimsPage{}, err
+		}
+
+		items = append(items, toClaim(dbc))
+	}
+
+	q = `SELECT COUNT(*) FROM claims WHERE manufacturer = $1`
+
+	var total uint64
+	if err := cr.db.QueryRow(q, manufacturer).Scan(&total); err != nil {
+		return bootstrap.ClaimsPage{}, err
+	}
+
+	return bootstrap.ClaimsPage{
+		Total:  total,
+		Offset: offset,
+		Limit:  limit,
+		Claims: items,
+	}, nil
+}
+
+func (cr claimRepository) Remove(manufacturer, externalID string) error {
+	q := `DELETE FROM claims WHERE external_id = $1 AND manufacturer = $2`
+	_, err := cr.db.Exec(q, externalID, manufacturer)
+	return err
+}
+
+func (cr claimRepository) Take(code string) (bootstrap.Claim, error) {
+	q := `DELETE FROM claims WHERE code = $1
+		  RETURNING external_id, external_key, code, manufacturer, name, content`
+
+	dbc := dbClaim{}
+	if err := cr.db.QueryRowx(q, code).StructScan(&dbc); err != nil {
+		if err == sql.ErrNoRows {
+			return bootstrap.Claim{}, bootstrap.ErrNotFound
+		}
+		return bootstrap.Claim{}, err
+	}
+
+	return toClaim(dbc), nil
+}
+
+type dbClaim struct {
+	ExternalID   string         `db:"external_id"`
+	ExternalKey  string         `db:"external_key"`
+	Code         string         `db:"code"`
+	Manufacturer string         `db:"manufacturer"`
+	Name         sql.NullString `db:"name"`
+	Content      sql.NullString `db:"content"`
+}
+
+func toDBClaim(claim bootstrap.Claim) dbClaim {
+	return dbClaim{
+		ExternalID:   claim.ExternalID,
+		ExternalKey:  claim.ExternalKey,
+		Code:         claim.Code,
+		Manufacturer: claim.Manufacturer,
+		Name:         nullString(claim.Name),
+		Content:      nullString(claim.Content),
+	}
+}
+
+func toClaim(dbc dbClaim) bootstrap.Claim {
+	return bootstrap.Claim{
+		ExternalID:   dbc.ExternalID,
+		ExternalKey:  dbc.ExternalKey,
+		Code:         dbc.Code,
+		Manufacturer: dbc.Manufacturer,
+		Name:         dbc.Name.String,
+		Content:      dbc.Content.String,
+	}
+}
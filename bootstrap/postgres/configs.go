@@ -441,6 +441,12 @@ func (cr configRepository) RemoveThing(id string) error {
 	return err
 }
 
+func (cr configRepository) UpdateThingKey(id, key string) error {
+	q := `UPDATE configs SET mainflux_key = $1 WHERE mainflux_thing = $2`
+	_, err := cr.db.Exec(q, key, id)
+	return err
+}
+
 func (cr configRepository) UpdateChannel(channel bootstrap.Channel) error {
 	dbch, err := toDBChannel("", channel)
 	if err != nil {
@@ -468,6 +474,25 @@ func (cr configRepository) DisconnectThing(channelID, thingID string) error {
 	return err
 }
 
+func (cr configRepository) RemoveUser(owner string) error {
+	tx, err := cr.db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM configs WHERE owner = $1`, owner); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM channels WHERE owner = $1`, owner); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
 func (cr configRepository) retrieveAll(key string, filter bootstrap.Filter) (string, []interface{}) {
 	template := `WHERE owner = $1 %s`
 	params := []interface{}{key}
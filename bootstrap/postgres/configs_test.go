@@ -761,6 +761,57 @@ func TestDisconnectThing(t *testing.T) {
 	assert.Equal(t, cfg.State, bootstrap.Inactive, fmt.Sprintf("expected ti be inactive when a connection is removed from %s", cfg))
 }
 
+func TestRemoveUser(t *testing.T) {
+	repo := postgres.NewConfigRepository(db, testLog)
+	err := deleteChannels(repo)
+	require.Nil(t, err, "Channels cleanup expected to succeed.")
+
+	c := config
+	// Use UUID to prevent conflicts.
+	uid, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("Got unexpected error: %s.\n", err))
+	c.MFKey = uid.String()
+	c.MFThing = uid.String()
+	c.ExternalID = uid.String()
+	c.ExternalKey = uid.String()
+	_, err = repo.Save(c, channels)
+	require.Nil(t, err, fmt.Sprintf("Saving config expected to succeed: %s.\n", err))
+
+	for i := 0; i < 2; i++ {
+		err := repo.RemoveUser(c.Owner)
+		assert.Nil(t, err, fmt.Sprintf("an unexpected error occured: %s\n", err))
+	}
+
+	_, err = repo.RetrieveByID(c.Owner, c.MFThing)
+	assert.Equal(t, bootstrap.ErrNotFound, err, fmt.Sprintf("expected %s got %s\n", bootstrap.ErrNotFound, err))
+}
+
+func TestUpdateThingKey(t *testing.T) {
+	repo := postgres.NewConfigRepository(db, testLog)
+	err := deleteChannels(repo)
+	require.Nil(t, err, "Channels cleanup expected to succeed.")
+
+	c := config
+	// Use UUID to prevent conflicts.
+	uid, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("Got unexpected error: %s.\n", err))
+	c.MFKey = uid.String()
+	c.MFThing = uid.String()
+	c.ExternalID = uid.String()
+	c.ExternalKey = uid.String()
+	_, err = repo.Save(c, channels)
+	require.Nil(t, err, fmt.Sprintf("Saving config expected to succeed: %s.\n", err))
+
+	key, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("Got unexpected error: %s.\n", err))
+	err = repo.UpdateThingKey(c.MFThing, key.String())
+	assert.Nil(t, err, fmt.Sprintf("an unexpected error occured: %s\n", err))
+
+	saved, err := repo.RetrieveByID(c.Owner, c.MFThing)
+	require.Nil(t, err, fmt.Sprintf("Retrieving config expected to succeed: %s.\n", err))
+	assert.Equal(t, key.String(), saved.MFKey, fmt.Sprintf("expected key %s got %s\n", key, saved.MFKey))
+}
+
 func deleteChannels(repo bootstrap.ConfigRepository) error {
 	for _, ch := range channels {
 		if err := repo.RemoveChannel(ch); err != nil {
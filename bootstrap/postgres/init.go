@@ -12,6 +12,7 @@ import (
 
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq" // required for SQL access
+	mfpostgres "github.com/mainflux/mainflux/postgres"
 	migrate "github.com/rubenv/sql-migrate"
 )
 
@@ -26,15 +27,23 @@ type Config struct {
 	SSLCert     string
 	SSLKey      string
 	SSLRootCert string
+	Target      string
+	SyncCommit  string
 }
 
 // Connect creates a connection to the PostgreSQL instance and applies any
 // unapplied database migrations. A non-nil error is returned to indicate
 // failure.
 func Connect(cfg Config) (*sqlx.DB, error) {
-	url := fmt.Sprintf("host=%s port=%s user=%s dbname=%s password=%s sslmode=%s sslcert=%s sslkey=%s sslrootcert=%s", cfg.Host, cfg.Port, cfg.User, cfg.Name, cfg.Pass, cfg.SSLMode, cfg.SSLCert, cfg.SSLKey, cfg.SSLRootCert)
+	url := fmt.Sprintf("user=%s dbname=%s password=%s sslmode=%s sslcert=%s sslkey=%s sslrootcert=%s", cfg.User, cfg.Name, cfg.Pass, cfg.SSLMode, cfg.SSLCert, cfg.SSLKey, cfg.SSLRootCert)
 
-	db, err := sqlx.Open("postgres", url)
+	db, err := mfpostgres.Open(mfpostgres.Config{
+		Hosts:      cfg.Host,
+		Port:       cfg.Port,
+		DSN:        url,
+		Target:     cfg.Target,
+		SyncCommit: cfg.SyncCommit,
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -95,6 +104,23 @@ func migrateDB(db *sqlx.DB) error {
 					"DROP TABLE unknown_configs",
 				},
 			},
+			{
+				Id: "configs_2",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS claims (
+						external_id  TEXT UNIQUE NOT NULL,
+						external_key TEXT NOT NULL,
+						code         CHAR(64) UNIQUE NOT NULL,
+						manufacturer VARCHAR(254) NOT NULL,
+						name         TEXT,
+						content      TEXT,
+						PRIMARY KEY (external_id)
+					)`,
+				},
+				Down: []string{
+					"DROP TABLE claims",
+				},
+			},
 		},
 	}
 
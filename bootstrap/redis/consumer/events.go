@@ -7,10 +7,30 @@
 
 package consumer
 
+type createThingEvent struct {
+	id        string
+	owner     string
+	name      string
+	key       string
+	bootstrap string
+	channels  []channel
+}
+
+type channel struct {
+	ID       string                 `json:"id"`
+	Name     string                 `json:"name,omitempty"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
 type removeEvent struct {
 	id string
 }
 
+type rotateKeyEvent struct {
+	id  string
+	key string
+}
+
 type updateChannelEvent struct {
 	id       string
 	name     string
@@ -9,10 +9,10 @@ package consumer
 
 import (
 	"encoding/json"
-	"fmt"
 
 	"github.com/go-redis/redis"
 	"github.com/mainflux/mainflux/bootstrap"
+	"github.com/mainflux/mainflux/events"
 	"github.com/mainflux/mainflux/logger"
 )
 
@@ -21,14 +21,14 @@ const (
 	group  = "mainflux.bootstrap"
 
 	thingPrefix     = "thing."
+	thingCreate     = thingPrefix + "create"
 	thingRemove     = thingPrefix + "remove"
+	thingRotateKey  = thingPrefix + "rotate_key"
 	thingDisconnect = thingPrefix + "disconnect"
 
 	channelPrefix = "channel."
 	channelUpdate = channelPrefix + "update"
 	channelRemove = channelPrefix + "remove"
-
-	exists = "BUSYGROUP Consumer Group name already exists"
 )
 
 // EventStore represents event source for things and channels provisioning.
@@ -39,13 +39,13 @@ type EventStore interface {
 
 type eventStore struct {
 	svc      bootstrap.Service
-	client   *redis.Client
+	client   redis.UniversalClient
 	consumer string
 	logger   logger.Logger
 }
 
 // NewEventStore returns new event store instance.
-func NewEventStore(svc bootstrap.Service, client *redis.Client, consumer string, log logger.Logger) EventStore {
+func NewEventStore(svc bootstrap.Service, client redis.UniversalClient, consumer string, log logger.Logger) EventStore {
 	return eventStore{
 		svc:      svc,
 		client:   client,
@@ -55,86 +55,135 @@ func NewEventStore(svc bootstrap.Service, client *redis.Client, consumer string,
 }
 
 func (es eventStore) Subscribe(subject string) error {
-	err := es.client.XGroupCreateMkStream(stream, group, "$").Err()
-	if err != nil && err.Error() != exists {
-		return err
+	sub := events.NewSubscriber(es.client, events.Config{
+		Stream:   stream,
+		Group:    group,
+		Consumer: es.consumer,
+	}, es.logger)
+
+	return sub.Subscribe(events.HandlerFunc(es.handle))
+}
+
+func (es eventStore) handle(event events.Event) error {
+	var err error
+	switch event.Read("operation", "") {
+	case thingCreate:
+		cte := decodeCreateThing(event)
+		err = es.handleCreateThing(cte)
+	case thingRemove:
+		rte := decodeRemoveThing(event)
+		err = es.handleRemoveThing(rte)
+	case thingRotateKey:
+		rke := decodeRotateKey(event)
+		err = es.handleRotateKey(rke)
+	case thingDisconnect:
+		dte := decodeDisconnectThing(event)
+		err = es.handleDisconnectThing(dte)
+	case channelUpdate:
+		uce := decodeUpdateChannel(event)
+		err = es.handleUpdateChannel(uce)
+	case channelRemove:
+		rce := decodeRemoveChannel(event)
+		err = es.handleRemoveChannel(rce)
+	}
+
+	return err
+}
+
+func decodeCreateThing(event events.Event) createThingEvent {
+	var channels []channel
+	if err := json.Unmarshal([]byte(event.Read("channels", "[]")), &channels); err != nil {
+		channels = []channel{}
 	}
 
-	for {
-		streams, err := es.client.XReadGroup(&redis.XReadGroupArgs{
-			Group:    group,
-			Consumer: es.consumer,
-			Streams:  []string{stream, ">"},
-			Count:    100,
-		}).Result()
-		if err != nil || len(streams) == 0 {
-			continue
-		}
-
-		for _, msg := range streams[0].Messages {
-			event := msg.Values
-
-			var err error
-			switch event["operation"] {
-			case thingRemove:
-				rte := decodeRemoveThing(event)
-				err = es.handleRemoveThing(rte)
-			case thingDisconnect:
-				dte := decodeDisconnectThing(event)
-				err = es.handleDisconnectThing(dte)
-			case channelUpdate:
-				uce := decodeUpdateChannel(event)
-				err = es.handleUpdateChannel(uce)
-			case channelRemove:
-				rce := decodeRemoveChannel(event)
-				err = es.handleRemoveChannel(rce)
-			}
-			if err != nil {
-				es.logger.Warn(fmt.Sprintf("Failed to handle event sourcing: %s", err.Error()))
-				break
-			}
-			es.client.XAck(stream, group, msg.ID)
-		}
+	return createThingEvent{
+		id:        event.Read("id", ""),
+		owner:     event.Read("owner", ""),
+		name:      event.Read("name", ""),
+		key:       event.Read("key", ""),
+		bootstrap: event.Read("bootstrap", ""),
+		channels:  channels,
 	}
 }
 
-func decodeRemoveThing(event map[string]interface{}) removeEvent {
+func decodeRemoveThing(event events.Event) removeEvent {
 	return removeEvent{
-		id: read(event, "id", ""),
+		id: event.Read("id", ""),
+	}
+}
+
+func decodeRotateKey(event events.Event) rotateKeyEvent {
+	return rotateKeyEvent{
+		id:  event.Read("id", ""),
+		key: event.Read("key", ""),
 	}
 }
 
-func decodeUpdateChannel(event map[string]interface{}) updateChannelEvent {
-	strmeta := read(event, "metadata", "{}")
+func decodeUpdateChannel(event events.Event) updateChannelEvent {
+	strmeta := event.Read("metadata", "{}")
 	var metadata map[string]interface{}
 	if err := json.Unmarshal([]byte(strmeta), metadata); err != nil {
 		metadata = map[string]interface{}{}
 	}
 
 	return updateChannelEvent{
-		id:       read(event, "id", ""),
-		name:     read(event, "name", ""),
+		id:       event.Read("id", ""),
+		name:     event.Read("name", ""),
 		metadata: metadata,
 	}
 }
 
-func decodeRemoveChannel(event map[string]interface{}) removeEvent {
+func decodeRemoveChannel(event events.Event) removeEvent {
 	return removeEvent{
-		id: read(event, "id", ""),
+		id: event.Read("id", ""),
 	}
 }
 
-func decodeDisconnectThing(event map[string]interface{}) disconnectEvent {
+func decodeDisconnectThing(event events.Event) disconnectEvent {
 	return disconnectEvent{
-		channelID: read(event, "chan_id", ""),
-		thingID:   read(event, "thing_id", ""),
+		channelID: event.Read("chan_id", ""),
+		thingID:   event.Read("thing_id", ""),
+	}
+}
+
+// handleCreateThing adds the Config of the things instantiated from the
+// profiles carrying the bootstrap content. The things are bootstrapped using
+// their own ID and key as external ones.
+func (es eventStore) handleCreateThing(cte createThingEvent) error {
+	if cte.bootstrap == "" {
+		return nil
+	}
+
+	var channels []bootstrap.Channel
+	for _, ch := range cte.channels {
+		channels = append(channels, bootstrap.Channel{
+			ID:       ch.ID,
+			Name:     ch.Name,
+			Metadata: ch.Metadata,
+		})
+	}
+
+	cfg := bootstrap.Config{
+		MFThing:     cte.id,
+		Owner:       cte.owner,
+		Name:        cte.name,
+		MFKey:       cte.key,
+		MFChannels:  channels,
+		ExternalID:  cte.id,
+		ExternalKey: cte.key,
+		Content:     cte.bootstrap,
 	}
+	return es.svc.AddConfigHandler(cfg)
 }
 
 func (es eventStore) handleRemoveThing(rte removeEvent) error {
 	return es.svc.RemoveConfigHandler(rte.id)
 }
 
+func (es eventStore) handleRotateKey(rke rotateKeyEvent) error {
+	return es.svc.UpdateKeyHandler(rke.id, rke.key)
+}
+
 func (es eventStore) handleUpdateChannel(uce updateChannelEvent) error {
 	channel := bootstrap.Channel{
 		ID:       uce.id,
@@ -151,12 +200,3 @@ func (es eventStore) handleRemoveChannel(rce removeEvent) error {
 func (es eventStore) handleDisconnectThing(dte disconnectEvent) error {
 	return es.svc.DisconnectThingHandler(dte.channelID, dte.thingID)
 }
-
-func read(event map[string]interface{}, key, def string) string {
-	val, ok := event[key].(string)
-	if !ok {
-		return def
-	}
-
-	return val
-}
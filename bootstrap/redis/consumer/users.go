@@ -0,0 +1,58 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package consumer
+
+import (
+	"github.com/go-redis/redis"
+	"github.com/mainflux/mainflux/bootstrap"
+	"github.com/mainflux/mainflux/events"
+	"github.com/mainflux/mainflux/logger"
+)
+
+const (
+	userPrefix = "user."
+	userRemove = userPrefix + "remove"
+)
+
+type usersEventStore struct {
+	svc      bootstrap.Service
+	client   redis.UniversalClient
+	consumer string
+	logger   logger.Logger
+}
+
+// NewUsersEventStore returns new event store instance that consumes users
+// service events.
+func NewUsersEventStore(svc bootstrap.Service, client redis.UniversalClient, consumer string, log logger.Logger) EventStore {
+	return usersEventStore{
+		svc:      svc,
+		client:   client,
+		consumer: consumer,
+		logger:   log,
+	}
+}
+
+func (es usersEventStore) Subscribe(stream string) error {
+	sub := events.NewSubscriber(es.client, events.Config{
+		Stream:   stream,
+		Group:    group,
+		Consumer: es.consumer,
+	}, es.logger)
+
+	return sub.Subscribe(events.HandlerFunc(es.handle))
+}
+
+func (es usersEventStore) handle(event events.Event) error {
+	var err error
+	switch event.Read("operation", "") {
+	case userRemove:
+		err = es.svc.RemoveUserHandler(event.Read("email", ""))
+	}
+
+	return err
+}
@@ -24,6 +24,7 @@ const (
 	thingBootstrap         = thingPrefix + "bootstrap"
 	thingStateChange       = thingPrefix + "state_change"
 	thingUpdateConnections = thingPrefix + "update_connections"
+	thingClaim             = thingPrefix + "claim"
 )
 
 type event interface {
@@ -37,6 +38,7 @@ var (
 	_ event = (*bootstrapEvent)(nil)
 	_ event = (*changeStateEvent)(nil)
 	_ event = (*updateConnectionsEvent)(nil)
+	_ event = (*claimEvent)(nil)
 )
 
 type createConfigEvent struct {
@@ -136,3 +138,20 @@ func (uce updateConnectionsEvent) encode() map[string]interface{} {
 		"operation": thingUpdateConnections,
 	}
 }
+
+type claimEvent struct {
+	mfThing    string
+	owner      string
+	externalID string
+	timestamp  time.Time
+}
+
+func (ce claimEvent) encode() map[string]interface{} {
+	return map[string]interface{}{
+		"thing_id":    ce.mfThing,
+		"owner":       ce.owner,
+		"external_id": ce.externalID,
+		"timestamp":   ce.timestamp.Unix(),
+		"operation":   thingClaim,
+	}
+}
@@ -23,12 +23,12 @@ var _ bootstrap.Service = (*eventStore)(nil)
 
 type eventStore struct {
 	svc    bootstrap.Service
-	client *redis.Client
+	client redis.UniversalClient
 }
 
 // NewEventStoreMiddleware returns wrapper around bootstrap service that sends
 // events to event store.
-func NewEventStoreMiddleware(svc bootstrap.Service, client *redis.Client) bootstrap.Service {
+func NewEventStoreMiddleware(svc bootstrap.Service, client redis.UniversalClient) bootstrap.Service {
 	return eventStore{
 		svc:    svc,
 		client: client,
@@ -171,6 +171,48 @@ func (es eventStore) DisconnectThingHandler(channelID, thingID string) error {
 	return es.svc.DisconnectThingHandler(channelID, thingID)
 }
 
+func (es eventStore) RemoveUserHandler(owner string) error {
+	return es.svc.RemoveUserHandler(owner)
+}
+
+func (es eventStore) UpdateKeyHandler(thingID, key string) error {
+	return es.svc.UpdateKeyHandler(thingID, key)
+}
+
+func (es eventStore) AddConfigHandler(cfg bootstrap.Config) error {
+	return es.svc.AddConfigHandler(cfg)
+}
+
+func (es eventStore) AddClaim(key string, claim bootstrap.Claim) error {
+	return es.svc.AddClaim(key, claim)
+}
+
+func (es eventStore) ListClaims(key string, offset, limit uint64) (bootstrap.ClaimsPage, error) {
+	return es.svc.ListClaims(key, offset, limit)
+}
+
+func (es eventStore) RemoveClaim(key, externalID string) error {
+	return es.svc.RemoveClaim(key, externalID)
+}
+
+func (es eventStore) Claim(key, code string) (bootstrap.Config, error) {
+	cfg, err := es.svc.Claim(key, code)
+	if err != nil {
+		return cfg, err
+	}
+
+	ev := claimEvent{
+		mfThing:    cfg.MFThing,
+		owner:      cfg.Owner,
+		externalID: cfg.ExternalID,
+		timestamp:  time.Now(),
+	}
+
+	es.add(ev)
+
+	return cfg, nil
+}
+
 func (es eventStore) add(ev event) error {
 	record := &redis.XAddArgs{
 		Stream:       streamID,
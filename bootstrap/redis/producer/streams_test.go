@@ -46,11 +46,12 @@ const (
 	thingStateChange       = thingPrefix + "state_change"
 	thingBootstrap         = thingPrefix + "bootstrap"
 	thingUpdateConnections = thingPrefix + "update_connections"
+	thingClaim             = thingPrefix + "claim"
 )
 
 var (
 	channel = bootstrap.Channel{
-		ID:       "1",
+		ID:       chanID(1),
 		Name:     "name",
 		Metadata: map[string]interface{}{"name": "value"},
 	}
@@ -70,13 +71,13 @@ func newService(users mainflux.UsersServiceClient, url string) bootstrap.Service
 	}
 
 	sdk := mfsdk.NewSDK(config)
-	return bootstrap.New(users, configs, sdk)
+	return bootstrap.New(users, configs, mocks.NewClaimsRepository(), sdk)
 }
 
 func newThingsService(users mainflux.UsersServiceClient) things.Service {
 	channels := make(map[string]things.Channel, channelsNum)
 	for i := 0; i < channelsNum; i++ {
-		id := strconv.Itoa(i + 1)
+		id := chanID(i + 1)
 		channels[id] = things.Channel{
 			ID:       id,
 			Owner:    email,
@@ -87,6 +88,11 @@ func newThingsService(users mainflux.UsersServiceClient) things.Service {
 	return mocks.NewThingsService(map[string]things.Thing{}, channels, users)
 }
 
+// chanID returns the identifier of the n-th channel of the Things service.
+func chanID(n int) string {
+	return fmt.Sprintf("123e4567-e89b-12d3-a456-%012d", n)
+}
+
 func newThingsServer(svc things.Service) *httptest.Server {
 	mux := httpapi.MakeHandler(svc)
 	return httptest.NewServer(mux)
@@ -120,7 +126,7 @@ func TestAdd(t *testing.T) {
 			key:    validToken,
 			err:    nil,
 			event: map[string]interface{}{
-				"thing_id":    "1",
+				"thing_id":    "123e4567-e89b-12d3-a456-000000000001",
 				"owner":       email,
 				"name":        config.Name,
 				"channels":    strings.Join(channels, ", "),
@@ -189,7 +195,7 @@ func TestUpdate(t *testing.T) {
 	c := config
 
 	ch := channel
-	ch.ID = "2"
+	ch.ID = chanID(2)
 	c.MFChannels = append(c.MFChannels, ch)
 	saved, err := svc.Add(validToken, c)
 	require.Nil(t, err, fmt.Sprintf("Saving config expected to succeed: %s.\n", err))
@@ -277,11 +283,11 @@ func TestUpdateConnections(t *testing.T) {
 			desc:        "update connections successfully",
 			id:          saved.MFThing,
 			key:         validToken,
-			connections: []string{"2"},
+			connections: []string{chanID(2)},
 			err:         nil,
 			event: map[string]interface{}{
 				"thing_id":  saved.MFThing,
-				"channels":  "2",
+				"channels":  chanID(2),
 				"timestamp": time.Now().Unix(),
 				"operation": thingUpdateConnections,
 			},
@@ -290,7 +296,7 @@ func TestUpdateConnections(t *testing.T) {
 			desc:        "update connections unsuccessfully",
 			id:          saved.MFThing,
 			key:         validToken,
-			connections: []string{"256"},
+			connections: []string{chanID(256)},
 			err:         bootstrap.ErrMalformedEntity,
 			event:       nil,
 		},
@@ -399,6 +405,73 @@ func TestRemove(t *testing.T) {
 	}
 }
 
+func TestClaim(t *testing.T) {
+	redisClient.FlushAll().Err()
+
+	users := mocks.NewUsersService(map[string]string{validToken: email})
+	server := newThingsServer(newThingsService(users))
+	svc := newService(users, server.URL)
+	svc = producer.NewEventStoreMiddleware(svc, redisClient)
+
+	claim := bootstrap.Claim{
+		ExternalID:  "claim_id",
+		ExternalKey: "claim_key",
+		Code:        "claim_code",
+	}
+	err := svc.AddClaim(validToken, claim)
+	require.Nil(t, err, fmt.Sprintf("Saving claim expected to succeed: %s.\n", err))
+
+	cases := []struct {
+		desc  string
+		code  string
+		key   string
+		err   error
+		event map[string]interface{}
+	}{
+		{
+			desc: "claim a device successfully",
+			code: claim.Code,
+			key:  validToken,
+			err:  nil,
+			event: map[string]interface{}{
+				"thing_id":    "123e4567-e89b-12d3-a456-000000000001",
+				"owner":       email,
+				"external_id": claim.ExternalID,
+				"timestamp":   time.Now().Unix(),
+				"operation":   thingClaim,
+			},
+		},
+		{
+			desc:  "claim an already claimed device",
+			code:  claim.Code,
+			key:   validToken,
+			err:   bootstrap.ErrNotFound,
+			event: nil,
+		},
+	}
+
+	lastID := "0"
+	for _, tc := range cases {
+		_, err := svc.Claim(tc.key, tc.code)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+
+		streams := redisClient.XRead(&redis.XReadArgs{
+			Streams: []string{streamID, lastID},
+			Count:   1,
+			Block:   time.Second,
+		}).Val()
+
+		var event map[string]interface{}
+		if len(streams) > 0 && len(streams[0].Messages) > 0 {
+			msg := streams[0].Messages[0]
+			event = msg.Values
+			lastID = msg.ID
+		}
+
+		test(t, tc.event, event, tc.desc)
+	}
+}
+
 func TestBootstrap(t *testing.T) {
 	redisClient.FlushAll().Err()
 
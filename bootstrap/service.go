@@ -9,6 +9,8 @@ package bootstrap
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"time"
 
@@ -83,6 +85,35 @@ type Service interface {
 
 	// DisconnectHandler changes state of the Config when connect/disconnect event occurs.
 	DisconnectThingHandler(string, string) error
+
+	// RemoveUserHandler removes all Configurations of the user whose removal
+	// is received from an event.
+	RemoveUserHandler(string) error
+
+	// UpdateKeyHandler updates the Thing key of the Configuration with the
+	// key rotated by the Things service, received from an event.
+	UpdateKeyHandler(string, string) error
+
+	// AddConfigHandler adds the Configuration of the Thing instantiated from
+	// a profile, received from an event.
+	AddConfigHandler(Config) error
+
+	// AddClaim adds the device to the pool of unclaimed devices of the
+	// manufacturer identified by the provided key.
+	AddClaim(string, Claim) error
+
+	// ListClaims returns a subset of the unclaimed devices added by the
+	// manufacturer identified by the provided key.
+	ListClaims(string, uint64, uint64) (ClaimsPage, error)
+
+	// RemoveClaim removes the unclaimed device with the given external ID
+	// from the pool of the manufacturer identified by the provided key.
+	RemoveClaim(string, string) error
+
+	// Claim transfers the device having the given claim code to the user
+	// identified by the provided key. The device is given a new Thing and
+	// a Config owned by the user, which it receives once it bootstraps.
+	Claim(string, string) (Config, error)
 }
 
 // ConfigReader is used to parse Config into format which will be encoded
@@ -96,13 +127,15 @@ type ConfigReader interface {
 type bootstrapService struct {
 	users   mainflux.UsersServiceClient
 	configs ConfigRepository
+	claims  ClaimRepository
 	sdk     mfsdk.SDK
 }
 
 // New returns new Bootstrap service.
-func New(users mainflux.UsersServiceClient, configs ConfigRepository, sdk mfsdk.SDK) Service {
+func New(users mainflux.UsersServiceClient, configs ConfigRepository, claims ClaimRepository, sdk mfsdk.SDK) Service {
 	return &bootstrapService{
 		configs: configs,
+		claims:  claims,
 		sdk:     sdk,
 		users:   users,
 	}
@@ -321,6 +354,100 @@ func (bs bootstrapService) DisconnectThingHandler(channelID, thingID string) err
 	return bs.configs.DisconnectThing(channelID, thingID)
 }
 
+func (bs bootstrapService) RemoveUserHandler(owner string) error {
+	return bs.configs.RemoveUser(owner)
+}
+
+func (bs bootstrapService) UpdateKeyHandler(thingID, key string) error {
+	return bs.configs.UpdateThingKey(thingID, key)
+}
+
+func (bs bootstrapService) AddConfigHandler(cfg Config) error {
+	toConnect := bs.toIDList(cfg.MFChannels)
+
+	existing, err := bs.configs.ListExisting(cfg.Owner, toConnect)
+	if err != nil {
+		return err
+	}
+
+	saved := make(map[string]bool, len(existing))
+	for _, ch := range existing {
+		saved[ch.ID] = true
+	}
+
+	var channels []Channel
+	for _, ch := range cfg.MFChannels {
+		if !saved[ch.ID] {
+			channels = append(channels, ch)
+		}
+	}
+
+	cfg.MFChannels = channels
+	cfg.State = Inactive
+	if _, err := bs.configs.Save(cfg, toConnect); err != nil && err != ErrConflict {
+		return err
+	}
+
+	return nil
+}
+
+func (bs bootstrapService) AddClaim(key string, claim Claim) error {
+	manufacturer, err := bs.identify(key)
+	if err != nil {
+		return err
+	}
+
+	claim.Manufacturer = manufacturer
+	claim.Code = hashCode(claim.Code)
+
+	return bs.claims.Save(claim)
+}
+
+func (bs bootstrapService) ListClaims(key string, offset, limit uint64) (ClaimsPage, error) {
+	manufacturer, err := bs.identify(key)
+	if err != nil {
+		return ClaimsPage{}, err
+	}
+
+	return bs.claims.RetrieveAll(manufacturer, offset, limit)
+}
+
+func (bs bootstrapService) RemoveClaim(key, externalID string) error {
+	manufacturer, err := bs.identify(key)
+	if err != nil {
+		return err
+	}
+
+	return bs.claims.Remove(manufacturer, externalID)
+}
+
+func (bs bootstrapService) Claim(key, code string) (Config, error) {
+	if _, err := bs.identify(key); err != nil {
+		return Config{}, err
+	}
+
+	claim, err := bs.claims.Take(hashCode(code))
+	if err != nil {
+		return Config{}, err
+	}
+
+	cfg := Config{
+		ExternalID:  claim.ExternalID,
+		ExternalKey: claim.ExternalKey,
+		Name:        claim.Name,
+		Content:     claim.Content,
+	}
+
+	saved, err := bs.Add(key, cfg)
+	if err != nil {
+		// Put the device back to the pool, so that claiming can be retried.
+		bs.claims.Save(claim)
+		return Config{}, err
+	}
+
+	return saved, nil
+}
+
 func (bs bootstrapService) identify(token string) (string, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 	defer cancel()
@@ -426,3 +553,9 @@ func (bs bootstrapService) toIDList(channels []Channel) []string {
 
 	return ret
 }
+
+// Claim codes are stored hashed, since they grant the ownership of a device.
+func hashCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
@@ -10,7 +10,6 @@ package bootstrap_test
 import (
 	"fmt"
 	"net/http/httptest"
-	"strconv"
 	"testing"
 
 	"github.com/gofrs/uuid"
@@ -36,7 +35,7 @@ const (
 
 var (
 	channel = bootstrap.Channel{
-		ID:       "1",
+		ID:       chanID(1),
 		Name:     "name",
 		Metadata: map[string]interface{}{"name": "value"},
 	}
@@ -56,13 +55,13 @@ func newService(users mainflux.UsersServiceClient, url string) bootstrap.Service
 	}
 
 	sdk := mfsdk.NewSDK(config)
-	return bootstrap.New(users, things, sdk)
+	return bootstrap.New(users, things, mocks.NewClaimsRepository(), sdk)
 }
 
 func newThingsService(users mainflux.UsersServiceClient) things.Service {
 	channels := make(map[string]things.Channel, channelsNum)
 	for i := 0; i < channelsNum; i++ {
-		id := strconv.Itoa(i + 1)
+		id := chanID(i + 1)
 		channels[id] = things.Channel{
 			ID:       id,
 			Owner:    email,
@@ -73,6 +72,11 @@ func newThingsService(users mainflux.UsersServiceClient) things.Service {
 	return mocks.NewThingsService(map[string]things.Thing{}, channels, users)
 }
 
+// chanID returns the identifier of the n-th channel of the Things service.
+func chanID(n int) string {
+	return fmt.Sprintf("123e4567-e89b-12d3-a456-%012d", n)
+}
+
 func newThingsServer(svc things.Service) *httptest.Server {
 	mux := httpapi.MakeHandler(svc)
 	return httptest.NewServer(mux)
@@ -85,7 +89,7 @@ func TestAdd(t *testing.T) {
 	svc := newService(users, server.URL)
 
 	neID := config
-	neID.MFThing = "non-existent"
+	neID.MFThing = "123e4567-e89b-12d3-a456-999999999999"
 
 	wrongChannels := config
 	ch := channel
@@ -179,7 +183,7 @@ func TestUpdate(t *testing.T) {
 	c := config
 
 	ch := channel
-	ch.ID = "2"
+	ch.ID = chanID(2)
 	c.MFChannels = append(c.MFChannels, ch)
 	saved, err := svc.Add(validToken, c)
 	require.Nil(t, err, fmt.Sprintf("Saving config expected to succeed: %s.\n", err))
@@ -231,7 +235,7 @@ func TestUpdateCert(t *testing.T) {
 	c := config
 
 	ch := channel
-	ch.ID = "2"
+	ch.ID = chanID(2)
 	c.MFChannels = append(c.MFChannels, ch)
 	saved, err := svc.Add(validToken, c)
 	require.Nil(t, err, fmt.Sprintf("Saving config expected to succeed: %s.\n", err))
@@ -289,7 +293,7 @@ func TestUpdateConnections(t *testing.T) {
 	c := config
 
 	ch := channel
-	ch.ID = "2"
+	ch.ID = chanID(2)
 	c.MFChannels = append(c.MFChannels, ch)
 	created, err := svc.Add(validToken, c)
 	require.Nil(t, err, fmt.Sprintf("Saving config expected to succeed: %s.\n", err))
@@ -316,21 +320,21 @@ func TestUpdateConnections(t *testing.T) {
 			desc:        "update connections for config with state Inactive",
 			key:         validToken,
 			id:          created.MFThing,
-			connections: []string{"2"},
+			connections: []string{chanID(2)},
 			err:         nil,
 		},
 		{
 			desc:        "update connections for config with state Active",
 			key:         validToken,
 			id:          active.MFThing,
-			connections: []string{"3"},
+			connections: []string{chanID(3)},
 			err:         nil,
 		},
 		{
 			desc:        "update connections for non-existing config",
 			key:         validToken,
 			id:          "",
-			connections: []string{"3"},
+			connections: []string{chanID(3)},
 			err:         bootstrap.ErrNotFound,
 		},
 		{
@@ -344,7 +348,7 @@ func TestUpdateConnections(t *testing.T) {
 			desc:        "update connections a config with wrong credentials",
 			key:         invalidToken,
 			id:          created.MFKey,
-			connections: []string{"2", "3"},
+			connections: []string{chanID(2), chanID(3)},
 			err:         bootstrap.ErrUnauthorizedAccess,
 		},
 	}
@@ -375,7 +379,7 @@ func TestList(t *testing.T) {
 		require.Nil(t, err, fmt.Sprintf("Saving config expected to succeed: %s.\n", err))
 	}
 	// Set one Thing to the different state
-	err := svc.ChangeState(validToken, "42", bootstrap.Active)
+	err := svc.ChangeState(validToken, "123e4567-e89b-12d3-a456-000000000042", bootstrap.Active)
 	require.Nil(t, err, fmt.Sprintf("Changing config state expected to succeed: %s.\n", err))
 	saved[41].State = bootstrap.Active
 
@@ -769,3 +773,239 @@ func TestDisconnectThingsHandler(t *testing.T) {
 		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
 	}
 }
+
+func TestRemoveUserHandler(t *testing.T) {
+	users := mocks.NewUsersService(map[string]string{validToken: email})
+
+	server := newThingsServer(newThingsService(users))
+	svc := newService(users, server.URL)
+
+	saved, err := svc.Add(validToken, config)
+	require.Nil(t, err, fmt.Sprintf("Saving config expected to succeed: %s.\n", err))
+
+	cases := []struct {
+		desc  string
+		owner string
+		err   error
+	}{
+		{
+			desc:  "remove configs of an existing user",
+			owner: email,
+			err:   nil,
+		},
+		{
+			desc:  "remove configs of a removed user",
+			owner: email,
+			err:   nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.RemoveUserHandler(tc.owner)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	_, err = svc.View(validToken, saved.MFThing)
+	assert.Equal(t, bootstrap.ErrNotFound, err, fmt.Sprintf("view removed user's config: expected %s got %s\n", bootstrap.ErrNotFound, err))
+}
+
+func TestUpdateKeyHandler(t *testing.T) {
+	users := mocks.NewUsersService(map[string]string{validToken: email})
+
+	server := newThingsServer(newThingsService(users))
+	svc := newService(users, server.URL)
+
+	saved, err := svc.Add(validToken, config)
+	require.Nil(t, err, fmt.Sprintf("Saving config expected to succeed: %s.\n", err))
+
+	cases := []struct {
+		desc    string
+		thingID string
+		key     string
+		err     error
+	}{
+		{
+			desc:    "update key of an existing config",
+			thingID: saved.MFThing,
+			key:     "rotated-key",
+			err:     nil,
+		},
+		{
+			desc:    "update key of a non-existing config",
+			thingID: unknown,
+			key:     "rotated-key",
+			err:     nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.UpdateKeyHandler(tc.thingID, tc.key)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	cfg, err := svc.View(validToken, saved.MFThing)
+	require.Nil(t, err, fmt.Sprintf("Viewing config expected to succeed: %s.\n", err))
+	assert.Equal(t, "rotated-key", cfg.MFKey, fmt.Sprintf("view config: expected key %s got %s\n", "rotated-key", cfg.MFKey))
+}
+
+func TestAddConfigHandler(t *testing.T) {
+	users := mocks.NewUsersService(map[string]string{validToken: email})
+
+	server := newThingsServer(newThingsService(users))
+	svc := newService(users, server.URL)
+
+	cfg := bootstrap.Config{
+		MFThing:     "profiled",
+		Owner:       email,
+		MFKey:       "profiled-key",
+		MFChannels:  []bootstrap.Channel{{ID: chanID(1), Name: "name"}},
+		ExternalID:  "profiled",
+		ExternalKey: "profiled-key",
+		Content:     "config",
+	}
+
+	cases := []struct {
+		desc string
+		cfg  bootstrap.Config
+		err  error
+	}{
+		{
+			desc: "add config received from an event",
+			cfg:  cfg,
+			err:  nil,
+		},
+		{
+			desc: "add already received config",
+			cfg:  cfg,
+			err:  nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.AddConfigHandler(tc.cfg)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	saved, err := svc.Bootstrap(cfg.ExternalKey, cfg.ExternalID)
+	require.Nil(t, err, fmt.Sprintf("Bootstrapping config expected to succeed: %s.\n", err))
+	assert.Equal(t, cfg.Content, saved.Content, fmt.Sprintf("bootstrap config: expected content %s got %s\n", cfg.Content, saved.Content))
+	assert.Equal(t, bootstrap.Inactive, saved.State, fmt.Sprintf("bootstrap config: expected state %d got %d\n", bootstrap.Inactive, saved.State))
+}
+
+func TestAddClaim(t *testing.T) {
+	users := mocks.NewUsersService(map[string]string{validToken: email})
+
+	server := newThingsServer(newThingsService(users))
+	svc := newService(users, server.URL)
+
+	claim := bootstrap.Claim{
+		ExternalID:  "claim_id",
+		ExternalKey: "claim_key",
+		Code:        "claim_code",
+	}
+
+	sameCode := claim
+	sameCode.ExternalID = "other_id"
+
+	cases := []struct {
+		desc  string
+		claim bootstrap.Claim
+		key   string
+		err   error
+	}{
+		{
+			desc:  "add a new claim",
+			claim: claim,
+			key:   validToken,
+			err:   nil,
+		},
+		{
+			desc:  "add an existing claim",
+			claim: claim,
+			key:   validToken,
+			err:   bootstrap.ErrConflict,
+		},
+		{
+			desc:  "add a claim with an existing code",
+			claim: sameCode,
+			key:   validToken,
+			err:   bootstrap.ErrConflict,
+		},
+		{
+			desc:  "add a claim with wrong credentials",
+			claim: claim,
+			key:   invalidToken,
+			err:   bootstrap.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.AddClaim(tc.key, tc.claim)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestClaim(t *testing.T) {
+	customerToken := "customerToken"
+	customer := "customer@example.com"
+	users := mocks.NewUsersService(map[string]string{validToken: email, customerToken: customer})
+
+	server := newThingsServer(newThingsService(users))
+	svc := newService(users, server.URL)
+
+	claim := bootstrap.Claim{
+		ExternalID:  "claim_id",
+		ExternalKey: "claim_key",
+		Code:        "claim_code",
+		Name:        "claim_name",
+		Content:     "claim_content",
+	}
+	err := svc.AddClaim(validToken, claim)
+	require.Nil(t, err, fmt.Sprintf("Saving claim expected to succeed: %s.\n", err))
+
+	cases := []struct {
+		desc string
+		code string
+		key  string
+		err  error
+	}{
+		{
+			desc: "claim a device with wrong credentials",
+			code: claim.Code,
+			key:  invalidToken,
+			err:  bootstrap.ErrUnauthorizedAccess,
+		},
+		{
+			desc: "claim a device with a wrong code",
+			code: "wrong_code",
+			key:  customerToken,
+			err:  bootstrap.ErrNotFound,
+		},
+		{
+			desc: "claim a device",
+			code: claim.Code,
+			key:  customerToken,
+			err:  nil,
+		},
+		{
+			desc: "claim an already claimed device",
+			code: claim.Code,
+			key:  customerToken,
+			err:  bootstrap.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := svc.Claim(tc.key, tc.code)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	page, err := svc.ListClaims(validToken, 0, 10)
+	require.Nil(t, err, fmt.Sprintf("Listing claims expected to succeed: %s.\n", err))
+	assert.Equal(t, uint64(0), page.Total, fmt.Sprintf("list claims after claiming: expected no claims got %d\n", page.Total))
+
+	cfg, err := svc.Bootstrap(claim.ExternalKey, claim.ExternalID)
+	require.Nil(t, err, fmt.Sprintf("Bootstrapping claimed device expected to succeed: %s.\n", err))
+	assert.Equal(t, customer, cfg.Owner, fmt.Sprintf("claimed config owner: expected %s got %s\n", customer, cfg.Owner))
+	assert.Equal(t, claim.Content, cfg.Content, fmt.Sprintf("claimed config content: expected %s got %s\n", claim.Content, cfg.Content))
+}
@@ -0,0 +1,9 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// Package api contains implementation of bridge service HTTP API.
+package api
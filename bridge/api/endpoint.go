@@ -0,0 +1,118 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package api
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/mainflux/mainflux/bridge"
+)
+
+func addRouteEndpoint(svc bridge.Service) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(addRouteReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		route := bridge.Route{
+			Channel:         req.Channel,
+			Subtopic:        req.Subtopic,
+			Provider:        req.Provider,
+			Topic:           req.Topic,
+			Commands:        req.Commands,
+			CommandSubtopic: req.CommandSubtopic,
+			Credentials: bridge.Credentials{
+				ServiceAccount: string(req.Credentials.ServiceAccount),
+				Endpoint:       req.Credentials.Endpoint,
+				Cert:           req.Credentials.Cert,
+				Key:            req.Credentials.Key,
+			},
+		}
+
+		saved, err := svc.AddRoute(req.key, route)
+		if err != nil {
+			return nil, err
+		}
+
+		return addRouteRes{id: saved.ID}, nil
+	}
+}
+
+func viewEndpoint(svc bridge.Service) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(entityReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		route, err := svc.ViewRoute(req.key, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		return toRouteRes(route), nil
+	}
+}
+
+func listEndpoint(svc bridge.Service) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(listReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		routes, err := svc.ListRoutes(req.key)
+		if err != nil {
+			return nil, err
+		}
+
+		res := listRes{
+			Routes: []routeRes{},
+		}
+		for _, route := range routes {
+			res.Routes = append(res.Routes, toRouteRes(route))
+		}
+
+		return res, nil
+	}
+}
+
+func removeEndpoint(svc bridge.Service) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(entityReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RemoveRoute(req.key, req.id); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func toRouteRes(route bridge.Route) routeRes {
+	res := routeRes{
+		ID:              route.ID,
+		Channel:         route.Channel,
+		Subtopic:        route.Subtopic,
+		Provider:        route.Provider,
+		Topic:           route.Topic,
+		Commands:        route.Commands,
+		CommandSubtopic: route.CommandSubtopic,
+		Endpoint:        route.Credentials.Endpoint,
+	}
+	if sa, err := route.Credentials.Account(); err == nil {
+		res.ClientEmail = sa.Email
+	}
+
+	return res
+}
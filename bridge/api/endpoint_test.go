@@ -0,0 +1,360 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package api_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mainflux/mainflux/bridge"
+	"github.com/mainflux/mainflux/bridge/api"
+	"github.com/mainflux/mainflux/bridge/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	validToken   = "validToken"
+	invalidToken = "invalidToken"
+	email        = "user@example.com"
+	contentType  = "application/json"
+	chanID       = "1"
+	topic        = "projects/mainflux/topics/telemetry"
+	clientEmail  = "bridge@mainflux.iam.gserviceaccount.com"
+	wrongID      = "123e4567-e89b-12d3-a456-999999999999"
+)
+
+var (
+	account = fmt.Sprintf(`{"client_email": "%s", "private_key": "key"}`, clientEmail)
+
+	route = bridge.Route{
+		Channel:     chanID,
+		Provider:    bridge.GCP,
+		Topic:       topic,
+		Credentials: bridge.Credentials{ServiceAccount: account},
+	}
+)
+
+type testRequest struct {
+	client      *http.Client
+	method      string
+	url         string
+	contentType string
+	token       string
+	body        io.Reader
+}
+
+func (tr testRequest) make() (*http.Response, error) {
+	req, err := http.NewRequest(tr.method, tr.url, tr.body)
+	if err != nil {
+		return nil, err
+	}
+
+	if tr.token != "" {
+		req.Header.Set("Authorization", tr.token)
+	}
+
+	if tr.contentType != "" {
+		req.Header.Set("Content-Type", tr.contentType)
+	}
+
+	return tr.client.Do(req)
+}
+
+type credentialsReq struct {
+	ServiceAccount json.RawMessage `json:"service_account,omitempty"`
+	Endpoint       string          `json:"endpoint,omitempty"`
+}
+
+type routeReq struct {
+	Channel     string         `json:"channel"`
+	Provider    string         `json:"provider"`
+	Topic       string         `json:"topic"`
+	Credentials credentialsReq `json:"credentials"`
+}
+
+type routeRes struct {
+	ID          string `json:"id"`
+	Channel     string `json:"channel"`
+	Provider    string `json:"provider"`
+	Topic       string `json:"topic"`
+	ClientEmail string `json:"client_email,omitempty"`
+}
+
+func newService() bridge.Service {
+	tokens := map[string]string{validToken: email}
+	users := mocks.NewUsersService(tokens)
+	things := mocks.NewThingsService(tokens, map[string]string{chanID: email})
+	routes := mocks.NewRouteRepository()
+	idp := mocks.NewIdentityProvider()
+	providers := map[string]bridge.Provider{bridge.GCP: mocks.NewProvider(nil)}
+
+	return bridge.New(users, things, routes, idp, providers, mocks.NewPublisher())
+}
+
+func newServer(svc bridge.Service) *httptest.Server {
+	return httptest.NewServer(api.MakeHandler(svc))
+}
+
+func toJSON(data interface{}) string {
+	jsonData, _ := json.Marshal(data)
+	return string(jsonData)
+}
+
+func TestAddRoute(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+
+	creds := credentialsReq{ServiceAccount: json.RawMessage(account)}
+	valid := toJSON(routeReq{Channel: chanID, Provider: bridge.GCP, Topic: topic, Credentials: creds})
+	invalid := toJSON(routeReq{Channel: chanID, Provider: bridge.GCP, Topic: "telemetry", Credentials: creds})
+	unsupported := toJSON(routeReq{Channel: chanID, Provider: "azure", Topic: topic, Credentials: creds})
+
+	cases := []struct {
+		desc        string
+		req         string
+		contentType string
+		token       string
+		status      int
+		location    string
+	}{
+		{
+			desc:        "add route with valid request",
+			req:         valid,
+			contentType: contentType,
+			token:       validToken,
+			status:      http.StatusCreated,
+			location:    "/routes/123e4567-e89b-12d3-a456-000000000001",
+		},
+		{
+			desc:        "add route with invalid token",
+			req:         valid,
+			contentType: contentType,
+			token:       invalidToken,
+			status:      http.StatusForbidden,
+		},
+		{
+			desc:        "add route with empty token",
+			req:         valid,
+			contentType: contentType,
+			token:       "",
+			status:      http.StatusForbidden,
+		},
+		{
+			desc:        "add route with invalid topic",
+			req:         invalid,
+			contentType: contentType,
+			token:       validToken,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "add route with unsupported provider",
+			req:         unsupported,
+			contentType: contentType,
+			token:       validToken,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "add route with invalid request format",
+			req:         "}",
+			contentType: contentType,
+			token:       validToken,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "add route without content type",
+			req:         valid,
+			contentType: "",
+			token:       validToken,
+			status:      http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/routes", ts.URL),
+			contentType: tc.contentType,
+			token:       tc.token,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		location := res.Header.Get("Location")
+		assert.Equal(t, tc.location, location, fmt.Sprintf("%s: expected location %s got %s", tc.desc, tc.location, location))
+	}
+}
+
+func TestView(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+
+	saved, err := svc.AddRoute(validToken, route)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	data := toJSON(routeRes{ID: saved.ID, Channel: chanID, Provider: bridge.GCP, Topic: topic, ClientEmail: clientEmail})
+
+	cases := []struct {
+		desc   string
+		id     string
+		token  string
+		status int
+		res    string
+	}{
+		{
+			desc:   "view existing route",
+			id:     saved.ID,
+			token:  validToken,
+			status: http.StatusOK,
+			res:    data,
+		},
+		{
+			desc:   "view route with invalid token",
+			id:     saved.ID,
+			token:  invalidToken,
+			status: http.StatusForbidden,
+			res:    "",
+		},
+		{
+			desc:   "view non-existing route",
+			id:     wrongID,
+			token:  validToken,
+			status: http.StatusNotFound,
+			res:    "",
+		},
+		{
+			desc:   "view route with malformed id",
+			id:     "unknown",
+			token:  validToken,
+			status: http.StatusBadRequest,
+			res:    "",
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/routes/%s", ts.URL, tc.id),
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		body, err := ioutil.ReadAll(res.Body)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.res, strings.Trim(string(body), "\n"), fmt.Sprintf("%s: expected body %s got %s", tc.desc, tc.res, body))
+	}
+}
+
+func TestList(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+
+	n := 3
+	for i := 0; i < n; i++ {
+		_, err := svc.AddRoute(validToken, route)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	cases := []struct {
+		desc   string
+		token  string
+		status int
+		size   int
+	}{
+		{
+			desc:   "list routes",
+			token:  validToken,
+			status: http.StatusOK,
+			size:   n,
+		},
+		{
+			desc:   "list routes with invalid token",
+			token:  invalidToken,
+			status: http.StatusForbidden,
+			size:   0,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/routes", ts.URL),
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+
+		var body struct {
+			Routes []routeRes `json:"routes"`
+		}
+		json.NewDecoder(res.Body).Decode(&body)
+		assert.Len(t, body.Routes, tc.size, fmt.Sprintf("%s: expected %d routes got %d", tc.desc, tc.size, len(body.Routes)))
+	}
+}
+
+func TestRemove(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+
+	saved, err := svc.AddRoute(validToken, route)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		id     string
+		token  string
+		status int
+	}{
+		{
+			desc:   "remove route with invalid token",
+			id:     saved.ID,
+			token:  invalidToken,
+			status: http.StatusForbidden,
+		},
+		{
+			desc:   "remove existing route",
+			id:     saved.ID,
+			token:  validToken,
+			status: http.StatusNoContent,
+		},
+		{
+			desc:   "remove removed route",
+			id:     saved.ID,
+			token:  validToken,
+			status: http.StatusNoContent,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodDelete,
+			url:    fmt.Sprintf("%s/routes/%s", ts.URL, tc.id),
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
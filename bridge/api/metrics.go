@@ -0,0 +1,108 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// +build !test
+
+package api
+
+import (
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/bridge"
+)
+
+var _ bridge.Service = (*metricsMiddleware)(nil)
+
+type metricsMiddleware struct {
+	counter metrics.Counter
+	latency metrics.Histogram
+	svc     bridge.Service
+}
+
+// MetricsMiddleware instruments core service by tracking request count and
+// latency.
+func MetricsMiddleware(svc bridge.Service, counter metrics.Counter, latency metrics.Histogram) bridge.Service {
+	return &metricsMiddleware{
+		counter: counter,
+		latency: latency,
+		svc:     svc,
+	}
+}
+
+func (mm *metricsMiddleware) AddRoute(key string, route bridge.Route) (bridge.Route, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "add_route").Add(1)
+		mm.latency.With("method", "add_route").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.AddRoute(key, route)
+}
+
+func (mm *metricsMiddleware) ViewRoute(key, id string) (bridge.Route, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "view_route").Add(1)
+		mm.latency.With("method", "view_route").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.ViewRoute(key, id)
+}
+
+func (mm *metricsMiddleware) ListRoutes(key string) ([]bridge.Route, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "list_routes").Add(1)
+		mm.latency.With("method", "list_routes").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.ListRoutes(key)
+}
+
+func (mm *metricsMiddleware) RemoveRoute(key, id string) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "remove_route").Add(1)
+		mm.latency.With("method", "remove_route").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.RemoveRoute(key, id)
+}
+
+func (mm *metricsMiddleware) Forward(msg mainflux.RawMessage) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "forward").Add(1)
+		mm.latency.With("method", "forward").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.Forward(msg)
+}
+
+func (mm *metricsMiddleware) Start() error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "start").Add(1)
+		mm.latency.With("method", "start").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.Start()
+}
+
+func (mm *metricsMiddleware) RemoveChannelHandler(id string) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "remove_channel_handler").Add(1)
+		mm.latency.With("method", "remove_channel_handler").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.RemoveChannelHandler(id)
+}
+
+func (mm *metricsMiddleware) RemoveUserHandler(owner string) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "remove_user_handler").Add(1)
+		mm.latency.With("method", "remove_user_handler").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.RemoveUserHandler(owner)
+}
@@ -0,0 +1,77 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package api
+
+import (
+	"encoding/json"
+
+	"github.com/mainflux/mainflux/bridge"
+)
+
+type apiReq interface {
+	validate() error
+}
+
+type credentialsReq struct {
+	ServiceAccount json.RawMessage `json:"service_account,omitempty"`
+	Endpoint       string          `json:"endpoint,omitempty"`
+	Cert           string          `json:"cert,omitempty"`
+	Key            string          `json:"key,omitempty"`
+}
+
+type addRouteReq struct {
+	key             string
+	Channel         string         `json:"channel"`
+	Subtopic        string         `json:"subtopic,omitempty"`
+	Provider        string         `json:"provider"`
+	Topic           string         `json:"topic"`
+	Commands        string         `json:"commands,omitempty"`
+	CommandSubtopic string         `json:"command_subtopic,omitempty"`
+	Credentials     credentialsReq `json:"credentials"`
+}
+
+func (req addRouteReq) validate() error {
+	if req.key == "" {
+		return bridge.ErrUnauthorizedAccess
+	}
+
+	if req.Channel == "" || req.Provider == "" || req.Topic == "" {
+		return bridge.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type entityReq struct {
+	key string
+	id  string
+}
+
+func (req entityReq) validate() error {
+	if req.key == "" {
+		return bridge.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" {
+		return bridge.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type listReq struct {
+	key string
+}
+
+func (req listReq) validate() error {
+	if req.key == "" {
+		return bridge.ErrUnauthorizedAccess
+	}
+
+	return nil
+}
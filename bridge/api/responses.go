@@ -0,0 +1,96 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/mainflux/mainflux"
+)
+
+var (
+	_ mainflux.Response = (*addRouteRes)(nil)
+	_ mainflux.Response = (*routeRes)(nil)
+	_ mainflux.Response = (*listRes)(nil)
+	_ mainflux.Response = (*removeRes)(nil)
+)
+
+type addRouteRes struct {
+	id string
+}
+
+func (res addRouteRes) Code() int {
+	return http.StatusCreated
+}
+
+func (res addRouteRes) Headers() map[string]string {
+	return map[string]string{
+		"Location": fmt.Sprintf("/routes/%s", res.id),
+	}
+}
+
+func (res addRouteRes) Empty() bool {
+	return true
+}
+
+// routeRes omits the route secrets, leaving only the service account email
+// and the data endpoint to identify the credentials.
+type routeRes struct {
+	ID              string `json:"id"`
+	Channel         string `json:"channel"`
+	Subtopic        string `json:"subtopic,omitempty"`
+	Provider        string `json:"provider"`
+	Topic           string `json:"topic"`
+	Commands        string `json:"commands,omitempty"`
+	CommandSubtopic string `json:"command_subtopic,omitempty"`
+	ClientEmail     string `json:"client_email,omitempty"`
+	Endpoint        string `json:"endpoint,omitempty"`
+}
+
+func (res routeRes) Code() int {
+	return http.StatusOK
+}
+
+func (res routeRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res routeRes) Empty() bool {
+	return false
+}
+
+type listRes struct {
+	Routes []routeRes `json:"routes"`
+}
+
+func (res listRes) Code() int {
+	return http.StatusOK
+}
+
+func (res listRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res listRes) Empty() bool {
+	return false
+}
+
+type removeRes struct{}
+
+func (res removeRes) Code() int {
+	return http.StatusNoContent
+}
+
+func (res removeRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res removeRes) Empty() bool {
+	return true
+}
@@ -0,0 +1,166 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// Package aws contains the bridge provider that forwards the messages to AWS
+// IoT Core topics, and consumes the commands from them, over MQTT.
+package aws
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/bridge"
+	log "github.com/mainflux/mainflux/logger"
+)
+
+const (
+	port = 8883
+	qos  = 1
+)
+
+// ErrTimeout indicates that AWS IoT Core didn't complete the operation in
+// time.
+var ErrTimeout = errors.New("aws iot core operation timed out")
+
+var _ bridge.Provider = (*provider)(nil)
+
+type provider struct {
+	timeout time.Duration
+	logger  log.Logger
+
+	mu       sync.Mutex
+	clients  map[string]mqtt.Client
+	handlers map[string]bridge.CommandHandler
+}
+
+// New instantiates the AWS IoT Core provider. Every route connects to its
+// data endpoint using the route identifier as the MQTT client ID, and the
+// route certificate for the mutual TLS authentication.
+func New(timeout time.Duration, logger log.Logger) bridge.Provider {
+	return &provider{
+		timeout:  timeout,
+		logger:   logger,
+		clients:  make(map[string]mqtt.Client),
+		handlers: make(map[string]bridge.CommandHandler),
+	}
+}
+
+// Publish sends the message payload to the route topic, having the subtopic
+// placeholder replaced with the message subtopic.
+func (p *provider) Publish(route bridge.Route, msg mainflux.RawMessage) error {
+	c, err := p.client(route)
+	if err != nil {
+		return err
+	}
+
+	subtopic := strings.Replace(msg.GetSubtopic(), ".", "/", -1)
+	topic := strings.Replace(route.Topic, bridge.SubtopicPlaceholder, subtopic, -1)
+	topic = strings.Replace(topic, "//", "/", -1)
+	topic = strings.TrimSuffix(topic, "/")
+
+	return p.wait(c.Publish(topic, qos, false, msg.GetPayload()))
+}
+
+// Subscribe subscribes to the route command topic filter. The subscription
+// is renewed whenever the client reconnects.
+func (p *provider) Subscribe(route bridge.Route, h bridge.CommandHandler) error {
+	p.mu.Lock()
+	p.handlers[route.ID] = h
+	p.mu.Unlock()
+
+	c, err := p.client(route)
+	if err != nil {
+		return err
+	}
+
+	return p.subscribe(c, route)
+}
+
+func (p *provider) Unsubscribe(id string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.handlers, id)
+	if c, ok := p.clients[id]; ok {
+		c.Disconnect(uint(p.timeout / time.Millisecond))
+		delete(p.clients, id)
+	}
+
+	return nil
+}
+
+func (p *provider) subscribe(c mqtt.Client, route bridge.Route) error {
+	p.mu.Lock()
+	h, ok := p.handlers[route.ID]
+	p.mu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	return p.wait(c.Subscribe(route.Commands, qos, func(_ mqtt.Client, m mqtt.Message) {
+		if err := h(m.Payload()); err != nil {
+			p.logger.Warn(fmt.Sprintf("Failed to handle command of route %s: %s", route.ID, err))
+		}
+	}))
+}
+
+// client returns the connected client of the route, connecting it first if
+// needed.
+func (p *provider) client(route bridge.Route) (mqtt.Client, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if c, ok := p.clients[route.ID]; ok {
+		return c, nil
+	}
+
+	cert, err := tls.X509KeyPair([]byte(route.Credentials.Cert), []byte(route.Credentials.Key))
+	if err != nil {
+		return nil, bridge.ErrMalformedEntity
+	}
+
+	opts := mqtt.NewClientOptions().
+		AddBroker(fmt.Sprintf("ssl://%s:%d", route.Credentials.Endpoint, port)).
+		SetClientID(route.ID).
+		SetTLSConfig(&tls.Config{Certificates: []tls.Certificate{cert}}).
+		SetConnectTimeout(p.timeout).
+		SetAutoReconnect(true).
+		SetOnConnectHandler(func(c mqtt.Client) {
+			if route.Commands == "" {
+				return
+			}
+			// Subscription is made asynchronously, since the handler is
+			// invoked holding the client lock.
+			go func() {
+				if err := p.subscribe(c, route); err != nil {
+					p.logger.Warn(fmt.Sprintf("Failed to subscribe to commands of route %s: %s", route.ID, err))
+				}
+			}()
+		})
+
+	c := mqtt.NewClient(opts)
+	if err := p.wait(c.Connect()); err != nil {
+		return nil, err
+	}
+
+	p.clients[route.ID] = c
+	return c, nil
+}
+
+func (p *provider) wait(t mqtt.Token) error {
+	if !t.WaitTimeout(p.timeout) {
+		return ErrTimeout
+	}
+
+	return t.Error()
+}
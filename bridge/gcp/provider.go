@@ -0,0 +1,328 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// Package gcp contains the bridge provider that forwards the messages to
+// Google Cloud Pub/Sub topics, and pulls the commands from Pub/Sub
+// subscriptions, using the Pub/Sub REST API.
+package gcp
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/bridge"
+	log "github.com/mainflux/mainflux/logger"
+)
+
+const (
+	// DefaultURL is the URL of the Pub/Sub REST API.
+	DefaultURL = "https://pubsub.googleapis.com/v1"
+
+	defTokenURI  = "https://oauth2.googleapis.com/token"
+	scope        = "https://www.googleapis.com/auth/pubsub"
+	grantType    = "urn:ietf:params:oauth:grant-type:jwt-bearer"
+	contentType  = "application/json"
+	tokenTTL     = time.Hour
+	tokenLeeway  = time.Minute
+	maxMessages  = 100
+	pullInterval = time.Second
+)
+
+var (
+	// ErrRequestFailed indicates that Pub/Sub API responded with a non-2xx
+	// status code.
+	ErrRequestFailed = errors.New("pub/sub responded with unexpected status")
+
+	// ErrAuthFailed indicates that the access token couldn't be obtained
+	// using the route service account.
+	ErrAuthFailed = errors.New("failed to obtain pub/sub access token")
+)
+
+var _ bridge.Provider = (*provider)(nil)
+
+type token struct {
+	value   string
+	expires time.Time
+}
+
+type provider struct {
+	url    string
+	client *http.Client
+	logger log.Logger
+
+	mu        sync.Mutex
+	tokens    map[string]token
+	consumers map[string]chan struct{}
+}
+
+type pubsubMessage struct {
+	Data       string            `json:"data"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+type publishReq struct {
+	Messages []pubsubMessage `json:"messages"`
+}
+
+type pullReq struct {
+	MaxMessages int `json:"maxMessages"`
+}
+
+type pullRes struct {
+	ReceivedMessages []struct {
+		AckID   string        `json:"ackId"`
+		Message pubsubMessage `json:"message"`
+	} `json:"receivedMessages"`
+}
+
+type ackReq struct {
+	AckIDs []string `json:"ackIds"`
+}
+
+type tokenRes struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// New instantiates the Pub/Sub provider using the API at the given URL.
+// Access tokens are obtained using the service accounts of the routes, and
+// cached until they expire.
+func New(url string, timeout time.Duration, logger log.Logger) bridge.Provider {
+	return &provider{
+		url:       strings.TrimSuffix(url, "/"),
+		client:    &http.Client{Timeout: timeout},
+		logger:    logger,
+		tokens:    make(map[string]token),
+		consumers: make(map[string]chan struct{}),
+	}
+}
+
+// Publish sends the message payload as the Pub/Sub message data, having the
+// message metadata set as the attributes.
+func (p *provider) Publish(route bridge.Route, msg mainflux.RawMessage) error {
+	attrs := map[string]string{}
+	for k, v := range map[string]string{
+		"channel":      msg.GetChannel(),
+		"subtopic":     msg.GetSubtopic(),
+		"publisher":    msg.GetPublisher(),
+		"protocol":     msg.GetProtocol(),
+		"content_type": msg.GetContentType(),
+	} {
+		if v != "" {
+			attrs[k] = v
+		}
+	}
+
+	req := publishReq{
+		Messages: []pubsubMessage{
+			{
+				Data:       base64.StdEncoding.EncodeToString(msg.GetPayload()),
+				Attributes: attrs,
+			},
+		},
+	}
+
+	return p.call(route, fmt.Sprintf("%s:publish", route.Topic), req, nil)
+}
+
+func (p *provider) Subscribe(route bridge.Route, h bridge.CommandHandler) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.consumers[route.ID]; ok {
+		return nil
+	}
+
+	done := make(chan struct{})
+	p.consumers[route.ID] = done
+	go p.consume(route, h, done)
+
+	return nil
+}
+
+func (p *provider) Unsubscribe(id string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if done, ok := p.consumers[id]; ok {
+		close(done)
+		delete(p.consumers, id)
+	}
+
+	return nil
+}
+
+// consume pulls the commands from the route subscription until the route
+// is unsubscribed. Commands that fail to be handled aren't acknowledged, so
+// Pub/Sub redelivers them once their acknowledgement deadline expires.
+func (p *provider) consume(route bridge.Route, h bridge.CommandHandler, done chan struct{}) {
+	for {
+		select {
+		case <-done:
+			return
+		default:
+		}
+
+		var res pullRes
+		if err := p.call(route, fmt.Sprintf("%s:pull", route.Commands), pullReq{MaxMessages: maxMessages}, &res); err != nil {
+			p.logger.Warn(fmt.Sprintf("Failed to pull commands of route %s: %s", route.ID, err))
+			p.wait(done)
+			continue
+		}
+
+		ack := ackReq{AckIDs: []string{}}
+		for _, rm := range res.ReceivedMessages {
+			payload, err := base64.StdEncoding.DecodeString(rm.Message.Data)
+			if err != nil {
+				p.logger.Warn(fmt.Sprintf("Failed to decode command of route %s: %s", route.ID, err))
+				ack.AckIDs = append(ack.AckIDs, rm.AckID)
+				continue
+			}
+
+			if err := h(payload); err != nil {
+				p.logger.Warn(fmt.Sprintf("Failed to handle command of route %s: %s", route.ID, err))
+				continue
+			}
+			ack.AckIDs = append(ack.AckIDs, rm.AckID)
+		}
+
+		if len(ack.AckIDs) > 0 {
+			if err := p.call(route, fmt.Sprintf("%s:acknowledge", route.Commands), ack, nil); err != nil {
+				p.logger.Warn(fmt.Sprintf("Failed to acknowledge commands of route %s: %s", route.ID, err))
+			}
+		}
+
+		if len(res.ReceivedMessages) == 0 {
+			p.wait(done)
+		}
+	}
+}
+
+func (p *provider) wait(done chan struct{}) {
+	select {
+	case <-done:
+	case <-time.After(pullInterval):
+	}
+}
+
+// call invokes the Pub/Sub API method on the resource, using the access
+// token of the route service account.
+func (p *provider) call(route bridge.Route, resource string, body, res interface{}) error {
+	tkn, err := p.token(route.Credentials)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/%s", p.url, resource), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", tkn))
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return ErrRequestFailed
+	}
+
+	if res == nil {
+		return nil
+	}
+
+	return json.NewDecoder(resp.Body).Decode(res)
+}
+
+// token returns the cached access token of the service account, or
+// exchanges the assertion signed using the service account key for the
+// new one. Tokens are cached by the whole service account key, so that the
+// routes don't share the token unless they hold the same key.
+func (p *provider) token(creds bridge.Credentials) (string, error) {
+	key := fmt.Sprintf("%x", sha256.Sum256([]byte(creds.ServiceAccount)))
+
+	p.mu.Lock()
+	tkn, ok := p.tokens[key]
+	p.mu.Unlock()
+	if ok && time.Now().Add(tokenLeeway).Before(tkn.expires) {
+		return tkn.value, nil
+	}
+
+	sa, err := creds.Account()
+	if err != nil {
+		return "", ErrAuthFailed
+	}
+	if sa.TokenURI == "" {
+		sa.TokenURI = defTokenURI
+	}
+
+	pk, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(sa.PrivateKey))
+	if err != nil {
+		return "", ErrAuthFailed
+	}
+
+	now := time.Now()
+	assertion, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":   sa.Email,
+		"scope": scope,
+		"aud":   sa.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(tokenTTL).Unix(),
+	}).SignedString(pk)
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{
+		"grant_type": []string{grantType},
+		"assertion":  []string{assertion},
+	}
+	resp, err := p.client.PostForm(sa.TokenURI, form)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", ErrAuthFailed
+	}
+
+	var tr tokenRes
+	if err := json.NewDecoder(resp.Body).Decode(&tr); err != nil {
+		return "", err
+	}
+
+	tkn = token{
+		value:   tr.AccessToken,
+		expires: now.Add(time.Duration(tr.ExpiresIn) * time.Second),
+	}
+
+	p.mu.Lock()
+	p.tokens[key] = tkn
+	p.mu.Unlock()
+
+	return tkn.value, nil
+}
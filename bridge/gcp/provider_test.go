@@ -0,0 +1,198 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package gcp_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/bridge"
+	"github.com/mainflux/mainflux/bridge/gcp"
+	"github.com/mainflux/mainflux/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	accessToken = "access-token"
+	topic       = "projects/mainflux/topics/telemetry"
+	commands    = "projects/mainflux/subscriptions/commands"
+	ackID       = "ack"
+)
+
+type pubsub struct {
+	mu        sync.Mutex
+	published []map[string]interface{}
+	acked     []string
+	pulled    bool
+}
+
+func (ps *pubsub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	if r.URL.Path == "/token" {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": accessToken,
+			"expires_in":   3600,
+		})
+		return
+	}
+
+	if r.Header.Get("Authorization") != fmt.Sprintf("Bearer %s", accessToken) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var body map[string]interface{}
+	json.NewDecoder(r.Body).Decode(&body)
+
+	switch r.URL.Path {
+	case fmt.Sprintf("/%s:publish", topic):
+		ps.published = append(ps.published, body)
+		w.Write([]byte(`{"messageIds": ["1"]}`))
+	case fmt.Sprintf("/%s:pull", commands):
+		if ps.pulled {
+			w.Write([]byte(`{}`))
+			return
+		}
+		ps.pulled = true
+		data := base64.StdEncoding.EncodeToString([]byte("command"))
+		fmt.Fprintf(w, `{"receivedMessages": [{"ackId": "%s", "message": {"data": "%s"}}]}`, ackID, data)
+	case fmt.Sprintf("/%s:acknowledge", commands):
+		for _, id := range body["ackIds"].([]interface{}) {
+			ps.acked = append(ps.acked, id.(string))
+		}
+		w.Write([]byte(`{}`))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (ps *pubsub) acknowledged() []string {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	return ps.acked
+}
+
+func newAccount(t *testing.T, tokenURI string) string {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	pk := pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	})
+
+	sa, err := json.Marshal(bridge.ServiceAccount{
+		Email:      "bridge@mainflux.iam.gserviceaccount.com",
+		PrivateKey: string(pk),
+		TokenURI:   tokenURI,
+	})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	return string(sa)
+}
+
+func newProvider(url string) bridge.Provider {
+	log, _ := logger.New(os.Stdout, logger.Info.String())
+	return gcp.New(url, time.Second, log)
+}
+
+func TestPublish(t *testing.T) {
+	ps := &pubsub{}
+	ts := httptest.NewServer(ps)
+	defer ts.Close()
+
+	account := newAccount(t, fmt.Sprintf("%s/token", ts.URL))
+	invalidKey := fmt.Sprintf(`{"client_email": "bridge@mainflux.iam.gserviceaccount.com", "private_key": "key", "token_uri": "%s/token"}`, ts.URL)
+
+	provider := newProvider(ts.URL)
+	msg := mainflux.RawMessage{Channel: "1", Subtopic: "temperature", Payload: []byte("payload")}
+
+	cases := []struct {
+		desc  string
+		route bridge.Route
+		err   error
+	}{
+		{
+			desc:  "publish message to existing topic",
+			route: bridge.Route{ID: "1", Topic: topic, Credentials: bridge.Credentials{ServiceAccount: account}},
+			err:   nil,
+		},
+		{
+			desc:  "publish message to non-existing topic",
+			route: bridge.Route{ID: "2", Topic: "projects/mainflux/topics/unknown", Credentials: bridge.Credentials{ServiceAccount: account}},
+			err:   gcp.ErrRequestFailed,
+		},
+		{
+			desc:  "publish message using invalid private key",
+			route: bridge.Route{ID: "3", Topic: topic, Credentials: bridge.Credentials{ServiceAccount: invalidKey}},
+			err:   gcp.ErrAuthFailed,
+		},
+	}
+
+	for _, tc := range cases {
+		err := provider.Publish(tc.route, msg)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	require.Len(t, ps.published, 1, fmt.Sprintf("expected 1 published message got %d", len(ps.published)))
+	sent := ps.published[0]["messages"].([]interface{})[0].(map[string]interface{})
+	data := base64.StdEncoding.EncodeToString(msg.Payload)
+	assert.Equal(t, data, sent["data"], fmt.Sprintf("expected data %s got %s", data, sent["data"]))
+	attrs := sent["attributes"].(map[string]interface{})
+	assert.Equal(t, msg.Subtopic, attrs["subtopic"], fmt.Sprintf("expected subtopic %s got %s", msg.Subtopic, attrs["subtopic"]))
+}
+
+func TestSubscribe(t *testing.T) {
+	ps := &pubsub{}
+	ts := httptest.NewServer(ps)
+	defer ts.Close()
+
+	provider := newProvider(ts.URL)
+	route := bridge.Route{
+		ID:          "1",
+		Topic:       topic,
+		Commands:    commands,
+		Credentials: bridge.Credentials{ServiceAccount: newAccount(t, fmt.Sprintf("%s/token", ts.URL))},
+	}
+
+	received := make(chan []byte, 1)
+	err := provider.Subscribe(route, func(payload []byte) error {
+		received <- payload
+		return nil
+	})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	defer provider.Unsubscribe(route.ID)
+
+	select {
+	case payload := <-received:
+		assert.Equal(t, []byte("command"), payload, fmt.Sprintf("expected command got %s", payload))
+	case <-time.After(5 * time.Second):
+		t.Fatal("command wasn't received")
+	}
+
+	for i := 0; i < 50 && len(ps.acknowledged()) == 0; i++ {
+		time.Sleep(100 * time.Millisecond)
+	}
+	assert.Equal(t, []string{ackID}, ps.acknowledged(), "expected handled command to be acknowledged")
+}
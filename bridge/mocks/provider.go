@@ -0,0 +1,86 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package mocks
+
+import (
+	"sync"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/bridge"
+)
+
+var _ bridge.Provider = (*ProviderMock)(nil)
+
+// ProviderMock records the forwarded messages per route topic, and holds
+// the command handlers of the subscribed routes.
+type ProviderMock struct {
+	mu        sync.Mutex
+	published map[string][]mainflux.RawMessage
+	handlers  map[string]bridge.CommandHandler
+	fail      map[string]error
+}
+
+// NewProvider creates the provider mock failing to publish to the given
+// topics.
+func NewProvider(fail map[string]error) *ProviderMock {
+	return &ProviderMock{
+		published: make(map[string][]mainflux.RawMessage),
+		handlers:  make(map[string]bridge.CommandHandler),
+		fail:      fail,
+	}
+}
+
+func (pm *ProviderMock) Publish(route bridge.Route, msg mainflux.RawMessage) error {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	if err, ok := pm.fail[route.Topic]; ok {
+		return err
+	}
+
+	pm.published[route.Topic] = append(pm.published[route.Topic], msg)
+	return nil
+}
+
+func (pm *ProviderMock) Subscribe(route bridge.Route, h bridge.CommandHandler) error {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	pm.handlers[route.ID] = h
+	return nil
+}
+
+func (pm *ProviderMock) Unsubscribe(id string) error {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	delete(pm.handlers, id)
+	return nil
+}
+
+// Published returns the messages forwarded to the topic.
+func (pm *ProviderMock) Published(topic string) []mainflux.RawMessage {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	return pm.published[topic]
+}
+
+// Command delivers the command to the route, returning false if the route
+// isn't subscribed.
+func (pm *ProviderMock) Command(id string, payload []byte) (bool, error) {
+	pm.mu.Lock()
+	h, ok := pm.handlers[id]
+	pm.mu.Unlock()
+
+	if !ok {
+		return false, nil
+	}
+
+	return true, h(payload)
+}
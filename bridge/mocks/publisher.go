@@ -0,0 +1,43 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package mocks
+
+import (
+	"sync"
+
+	"github.com/mainflux/mainflux"
+)
+
+var _ mainflux.MessagePublisher = (*PublisherMock)(nil)
+
+// PublisherMock records the published messages.
+type PublisherMock struct {
+	mu       sync.Mutex
+	messages []mainflux.RawMessage
+}
+
+// NewPublisher creates the message publisher mock.
+func NewPublisher() *PublisherMock {
+	return &PublisherMock{}
+}
+
+func (pm *PublisherMock) Publish(msg mainflux.RawMessage) error {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	pm.messages = append(pm.messages, msg)
+	return nil
+}
+
+// Messages returns the published messages.
+func (pm *PublisherMock) Messages() []mainflux.RawMessage {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	return pm.messages
+}
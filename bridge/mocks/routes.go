@@ -0,0 +1,111 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package mocks
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/mainflux/mainflux/bridge"
+)
+
+var _ bridge.RouteRepository = (*routeRepositoryMock)(nil)
+
+type routeRepositoryMock struct {
+	mu     sync.Mutex
+	routes map[string]bridge.Route
+}
+
+// NewRouteRepository creates in-memory route repository.
+func NewRouteRepository() bridge.RouteRepository {
+	return &routeRepositoryMock{
+		routes: make(map[string]bridge.Route),
+	}
+}
+
+func (rrm *routeRepositoryMock) Save(route bridge.Route) (string, error) {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	rrm.routes[route.ID] = route
+	return route.ID, nil
+}
+
+func (rrm *routeRepositoryMock) RetrieveByID(owner, id string) (bridge.Route, error) {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	route, ok := rrm.routes[id]
+	if !ok || route.Owner != owner {
+		return bridge.Route{}, bridge.ErrNotFound
+	}
+
+	return route, nil
+}
+
+func (rrm *routeRepositoryMock) RetrieveAll(owner string) ([]bridge.Route, error) {
+	return rrm.filter(func(r bridge.Route) bool { return r.Owner == owner }), nil
+}
+
+func (rrm *routeRepositoryMock) RetrieveByChannel(id string) ([]bridge.Route, error) {
+	return rrm.filter(func(r bridge.Route) bool { return r.Channel == id }), nil
+}
+
+func (rrm *routeRepositoryMock) RetrieveWithCommands() ([]bridge.Route, error) {
+	return rrm.filter(func(r bridge.Route) bool { return r.Commands != "" }), nil
+}
+
+func (rrm *routeRepositoryMock) Remove(owner, id string) error {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	if route, ok := rrm.routes[id]; ok && route.Owner == owner {
+		delete(rrm.routes, id)
+	}
+
+	return nil
+}
+
+func (rrm *routeRepositoryMock) RemoveByChannel(id string) error {
+	rrm.remove(func(r bridge.Route) bool { return r.Channel == id })
+	return nil
+}
+
+func (rrm *routeRepositoryMock) RemoveAll(owner string) error {
+	rrm.remove(func(r bridge.Route) bool { return r.Owner == owner })
+	return nil
+}
+
+func (rrm *routeRepositoryMock) filter(match func(bridge.Route) bool) []bridge.Route {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	routes := []bridge.Route{}
+	for _, route := range rrm.routes {
+		if match(route) {
+			routes = append(routes, route)
+		}
+	}
+
+	sort.Slice(routes, func(i, j int) bool {
+		return routes[i].ID < routes[j].ID
+	})
+
+	return routes
+}
+
+func (rrm *routeRepositoryMock) remove(match func(bridge.Route) bool) {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	for id, route := range rrm.routes {
+		if match(route) {
+			delete(rrm.routes, id)
+		}
+	}
+}
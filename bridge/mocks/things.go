@@ -0,0 +1,58 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package mocks
+
+import (
+	"context"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/bridge"
+	"google.golang.org/grpc"
+)
+
+var _ mainflux.ThingsServiceClient = (*thingsServiceMock)(nil)
+
+type thingsServiceMock struct {
+	channels map[string]string
+	users    map[string]string
+}
+
+// NewThingsService creates the things service mock granting the users
+// identified by the tokens read access to the channels they own. Channels
+// are mapped to their owners.
+func NewThingsService(users, channels map[string]string) mainflux.ThingsServiceClient {
+	return thingsServiceMock{
+		channels: channels,
+		users:    users,
+	}
+}
+
+func (svc thingsServiceMock) CanAccess(context.Context, *mainflux.AccessReq, ...grpc.CallOption) (*mainflux.ThingID, error) {
+	return nil, bridge.ErrUnauthorizedAccess
+}
+
+func (svc thingsServiceMock) Identify(context.Context, *mainflux.Token, ...grpc.CallOption) (*mainflux.ThingID, error) {
+	return nil, bridge.ErrUnauthorizedAccess
+}
+
+func (svc thingsServiceMock) IdentifyBatch(_ context.Context, in *mainflux.Tokens, _ ...grpc.CallOption) (*mainflux.ThingIDs, error) {
+	return &mainflux.ThingIDs{Values: make([]string, len(in.GetValues()))}, nil
+}
+
+func (svc thingsServiceMock) CanRead(_ context.Context, in *mainflux.AccessReq, _ ...grpc.CallOption) (*mainflux.UserID, error) {
+	user, ok := svc.users[in.GetToken()]
+	if !ok || svc.channels[in.GetChanID()] != user {
+		return nil, bridge.ErrUnauthorizedAccess
+	}
+
+	return &mainflux.UserID{Value: user}, nil
+}
+
+func (svc thingsServiceMock) ListChannelsByThing(_ context.Context, _ *mainflux.ThingID, _ ...grpc.CallOption) (*mainflux.ChannelIDs, error) {
+	return &mainflux.ChannelIDs{}, nil
+}
@@ -0,0 +1,43 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package mocks
+
+import (
+	"context"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/bridge"
+	"google.golang.org/grpc"
+)
+
+var _ mainflux.UsersServiceClient = (*usersServiceMock)(nil)
+
+type usersServiceMock struct {
+	users map[string]string
+}
+
+// NewUsersService creates mock of users service.
+func NewUsersService(users map[string]string) mainflux.UsersServiceClient {
+	return &usersServiceMock{users}
+}
+
+func (svc usersServiceMock) Identify(ctx context.Context, in *mainflux.Token, opts ...grpc.CallOption) (*mainflux.UserID, error) {
+	if id, ok := svc.users[in.Value]; ok {
+		return &mainflux.UserID{Value: id}, nil
+	}
+	return nil, bridge.ErrUnauthorizedAccess
+}
+
+func (svc usersServiceMock) Exists(ctx context.Context, in *mainflux.UserID, opts ...grpc.CallOption) (*mainflux.UserID, error) {
+	for _, id := range svc.users {
+		if id == in.Value {
+			return &mainflux.UserID{Value: id}, nil
+		}
+	}
+	return nil, bridge.ErrNotFound
+}
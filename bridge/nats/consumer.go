@@ -0,0 +1,53 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package nats
+
+import (
+	"fmt"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/bridge"
+	log "github.com/mainflux/mainflux/logger"
+	broker "github.com/nats-io/go-nats"
+)
+
+const (
+	queue = "bridge"
+	input = "channel.>"
+)
+
+type consumer struct {
+	svc    bridge.Service
+	logger log.Logger
+}
+
+// Subscribe subscribes to the messages published by the adapters and
+// forwards them. Messages are consumed in the queue group, so that every
+// message is forwarded once regardless of the number of service instances.
+func Subscribe(svc bridge.Service, nc *broker.Conn, logger log.Logger) error {
+	c := consumer{
+		svc:    svc,
+		logger: logger,
+	}
+
+	_, err := nc.QueueSubscribe(input, queue, c.consume)
+	return err
+}
+
+func (c consumer) consume(m *broker.Msg) {
+	var msg mainflux.RawMessage
+	if err := proto.Unmarshal(m.Data, &msg); err != nil {
+		c.logger.Warn(fmt.Sprintf("Failed to unmarshal received message: %s", err))
+		return
+	}
+
+	if err := c.svc.Forward(msg); err != nil {
+		c.logger.Warn(fmt.Sprintf("Failed to forward message: %s", err))
+	}
+}
@@ -0,0 +1,11 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// Package nats contains the consumer that forwards the messages published by
+// the protocol adapters to the bridge routes, and the publisher of the
+// commands received from the cloud providers.
+package nats
@@ -0,0 +1,89 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq" // required for SQL access
+	mfpostgres "github.com/mainflux/mainflux/postgres"
+	migrate "github.com/rubenv/sql-migrate"
+)
+
+// Config defines the options that are used when connecting to a PostgreSQL instance
+type Config struct {
+	Host        string
+	Port        string
+	User        string
+	Pass        string
+	Name        string
+	SSLMode     string
+	SSLCert     string
+	SSLKey      string
+	SSLRootCert string
+	Target      string
+	SyncCommit  string
+}
+
+// Connect creates a connection to the PostgreSQL instance and applies any
+// unapplied database migrations. A non-nil error is returned to indicate
+// failure.
+func Connect(cfg Config) (*sqlx.DB, error) {
+	url := fmt.Sprintf("user=%s dbname=%s password=%s sslmode=%s sslcert=%s sslkey=%s sslrootcert=%s", cfg.User, cfg.Name, cfg.Pass, cfg.SSLMode, cfg.SSLCert, cfg.SSLKey, cfg.SSLRootCert)
+
+	db, err := mfpostgres.Open(mfpostgres.Config{
+		Hosts:      cfg.Host,
+		Port:       cfg.Port,
+		DSN:        url,
+		Target:     cfg.Target,
+		SyncCommit: cfg.SyncCommit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := migrateDB(db); err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+func migrateDB(db *sqlx.DB) error {
+	migrations := &migrate.MemoryMigrationSource{
+		Migrations: []*migrate.Migration{
+			{
+				Id: "routes_1",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS routes (
+						id               UUID,
+						owner            VARCHAR(254) NOT NULL,
+						channel          VARCHAR(254) NOT NULL,
+						subtopic         VARCHAR(1024) NOT NULL,
+						provider         VARCHAR(32) NOT NULL,
+						topic            TEXT NOT NULL,
+						commands         TEXT NOT NULL,
+						command_subtopic VARCHAR(1024) NOT NULL,
+						credentials      JSONB NOT NULL,
+						PRIMARY KEY (id, owner)
+					)`,
+					`CREATE INDEX IF NOT EXISTS routes_owner_idx ON routes (owner)`,
+					`CREATE INDEX IF NOT EXISTS routes_channel_idx ON routes (channel)`,
+				},
+				Down: []string{
+					"DROP TABLE routes",
+				},
+			},
+		},
+	}
+
+	_, err := migrate.Exec(db.DB, "postgres", migrations, migrate.Up)
+
+	return err
+}
@@ -0,0 +1,202 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/mainflux/mainflux/bridge"
+)
+
+const (
+	duplicateErr = "unique_violation"
+	uuidErr      = "invalid input syntax for type uuid"
+)
+
+var _ bridge.RouteRepository = (*routeRepository)(nil)
+
+type routeRepository struct {
+	db *sqlx.DB
+}
+
+// NewRouteRepository instantiates a PostgreSQL implementation of route
+// repository.
+func NewRouteRepository(db *sqlx.DB) bridge.RouteRepository {
+	return &routeRepository{db: db}
+}
+
+func (rr routeRepository) Save(route bridge.Route) (string, error) {
+	q := `INSERT INTO routes (id, owner, channel, subtopic, provider, topic, commands, command_subtopic, credentials)
+	      VALUES (:id, :owner, :channel, :subtopic, :provider, :topic, :commands, :command_subtopic, :credentials)`
+
+	dbr, err := toDBRoute(route)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := rr.db.NamedExec(q, dbr); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == duplicateErr {
+			return "", bridge.ErrMalformedEntity
+		}
+		return "", err
+	}
+
+	return route.ID, nil
+}
+
+func (rr routeRepository) RetrieveByID(owner, id string) (bridge.Route, error) {
+	q := `SELECT id, owner, channel, subtopic, provider, topic, commands, command_subtopic, credentials
+	      FROM routes WHERE id = $1 AND owner = $2`
+
+	dbr := dbRoute{}
+	if err := rr.db.QueryRowx(q, id, owner).StructScan(&dbr); err != nil {
+		if err == sql.ErrNoRows {
+			return bridge.Route{}, bridge.ErrNotFound
+		}
+
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Message == uuidErr {
+			return bridge.Route{}, bridge.ErrNotFound
+		}
+
+		return bridge.Route{}, err
+	}
+
+	return toRoute(dbr)
+}
+
+func (rr routeRepository) RetrieveAll(owner string) ([]bridge.Route, error) {
+	q := `SELECT id, owner, channel, subtopic, provider, topic, commands, command_subtopic, credentials
+	      FROM routes WHERE owner = $1 ORDER BY id`
+
+	return rr.retrieve(q, owner)
+}
+
+func (rr routeRepository) RetrieveByChannel(id string) ([]bridge.Route, error) {
+	q := `SELECT id, owner, channel, subtopic, provider, topic, commands, command_subtopic, credentials
+	      FROM routes WHERE channel = $1 ORDER BY id`
+
+	return rr.retrieve(q, id)
+}
+
+func (rr routeRepository) RetrieveWithCommands() ([]bridge.Route, error) {
+	q := `SELECT id, owner, channel, subtopic, provider, topic, commands, command_subtopic, credentials
+	      FROM routes WHERE commands <> '' ORDER BY id`
+
+	return rr.retrieve(q)
+}
+
+func (rr routeRepository) Remove(owner, id string) error {
+	q := `DELETE FROM routes WHERE id = $1 AND owner = $2`
+
+	if _, err := rr.db.Exec(q, id, owner); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Message == uuidErr {
+			return nil
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (rr routeRepository) RemoveByChannel(id string) error {
+	q := `DELETE FROM routes WHERE channel = $1`
+
+	_, err := rr.db.Exec(q, id)
+	return err
+}
+
+func (rr routeRepository) RemoveAll(owner string) error {
+	q := `DELETE FROM routes WHERE owner = $1`
+
+	_, err := rr.db.Exec(q, owner)
+	return err
+}
+
+func (rr routeRepository) retrieve(q string, args ...interface{}) ([]bridge.Route, error) {
+	rows, err := rr.db.Queryx(q, args...)
+	if err != nil {
+		return []bridge.Route{}, err
+	}
+	defer rows.Close()
+
+	routes := []bridge.Route{}
+	for rows.Next() {
+		dbr := dbRoute{}
+		if err := rows.StructScan(&dbr); err != nil {
+			return []bridge.Route{}, err
+		}
+
+		route, err := toRoute(dbr)
+		if err != nil {
+			return []bridge.Route{}, err
+		}
+		routes = append(routes, route)
+	}
+
+	return routes, nil
+}
+
+type dbRoute struct {
+	ID              string `db:"id"`
+	Owner           string `db:"owner"`
+	Channel         string `db:"channel"`
+	Subtopic        string `db:"subtopic"`
+	Provider        string `db:"provider"`
+	Topic           string `db:"topic"`
+	Commands        string `db:"commands"`
+	CommandSubtopic string `db:"command_subtopic"`
+	Credentials     []byte `db:"credentials"`
+}
+
+type dbCredentials struct {
+	ServiceAccount string `json:"service_account,omitempty"`
+	Endpoint       string `json:"endpoint,omitempty"`
+	Cert           string `json:"cert,omitempty"`
+	Key            string `json:"key,omitempty"`
+}
+
+func toDBRoute(route bridge.Route) (dbRoute, error) {
+	creds, err := json.Marshal(dbCredentials(route.Credentials))
+	if err != nil {
+		return dbRoute{}, err
+	}
+
+	return dbRoute{
+		ID:              route.ID,
+		Owner:           route.Owner,
+		Channel:         route.Channel,
+		Subtopic:        route.Subtopic,
+		Provider:        route.Provider,
+		Topic:           route.Topic,
+		Commands:        route.Commands,
+		CommandSubtopic: route.CommandSubtopic,
+		Credentials:     creds,
+	}, nil
+}
+
+func toRoute(dbr dbRoute) (bridge.Route, error) {
+	var creds dbCredentials
+	if err := json.Unmarshal(dbr.Credentials, &creds); err != nil {
+		return bridge.Route{}, err
+	}
+
+	return bridge.Route{
+		ID:              dbr.ID,
+		Owner:           dbr.Owner,
+		Channel:         dbr.Channel,
+		Subtopic:        dbr.Subtopic,
+		Provider:        dbr.Provider,
+		Topic:           dbr.Topic,
+		Commands:        dbr.Commands,
+		CommandSubtopic: dbr.CommandSubtopic,
+		Credentials:     bridge.Credentials(creds),
+	}, nil
+}
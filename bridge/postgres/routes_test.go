@@ -0,0 +1,169 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package postgres_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/mainflux/mainflux/bridge"
+	"github.com/mainflux/mainflux/bridge/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	owner    = "user@example.com"
+	topic    = "projects/mainflux/topics/telemetry"
+	commands = "projects/mainflux/subscriptions/commands"
+)
+
+func newRoute(t *testing.T, owner, channel string) bridge.Route {
+	id, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	return bridge.Route{
+		ID:       id.String(),
+		Owner:    owner,
+		Channel:  channel,
+		Provider: bridge.GCP,
+		Topic:    topic,
+		Credentials: bridge.Credentials{
+			ServiceAccount: `{"client_email": "bridge@mainflux.iam.gserviceaccount.com"}`,
+		},
+	}
+}
+
+func TestRouteSave(t *testing.T) {
+	repo := postgres.NewRouteRepository(db)
+	route := newRoute(t, owner, "1")
+
+	cases := []struct {
+		desc  string
+		route bridge.Route
+		err   error
+	}{
+		{
+			desc:  "save new route",
+			route: route,
+			err:   nil,
+		},
+		{
+			desc:  "save existing route",
+			route: route,
+			err:   bridge.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := repo.Save(tc.route)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestRouteRetrieveByID(t *testing.T) {
+	repo := postgres.NewRouteRepository(db)
+	route := newRoute(t, owner, "1")
+	id, err := repo.Save(route)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		owner string
+		id    string
+		err   error
+	}{
+		{
+			desc:  "retrieve existing route",
+			owner: owner,
+			id:    id,
+			err:   nil,
+		},
+		{
+			desc:  "retrieve route of another user",
+			owner: "other@example.com",
+			id:    id,
+			err:   bridge.ErrNotFound,
+		},
+		{
+			desc:  "retrieve route with malformed id",
+			owner: owner,
+			id:    "malformed",
+			err:   bridge.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		r, err := repo.RetrieveByID(tc.owner, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err == nil {
+			assert.Equal(t, route, r, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, route, r))
+		}
+	}
+}
+
+func TestRouteRetrieveByChannel(t *testing.T) {
+	repo := postgres.NewRouteRepository(db)
+	channel := newRoute(t, owner, "").ID
+
+	for i := 0; i < 3; i++ {
+		route := newRoute(t, owner, channel)
+		if i == 0 {
+			route.Commands = commands
+		}
+		_, err := repo.Save(route)
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	}
+
+	routes, err := repo.RetrieveByChannel(channel)
+	assert.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.Len(t, routes, 3, fmt.Sprintf("expected 3 routes got %d", len(routes)))
+
+	routes, err = repo.RetrieveWithCommands()
+	assert.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.NotEmpty(t, routes, "expected routes having the command source")
+	for _, route := range routes {
+		assert.Equal(t, commands, route.Commands, fmt.Sprintf("expected command source %s got %s", commands, route.Commands))
+	}
+
+	err = repo.RemoveByChannel(channel)
+	assert.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	routes, err = repo.RetrieveByChannel(channel)
+	assert.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.Len(t, routes, 0, fmt.Sprintf("expected no routes got %d", len(routes)))
+}
+
+func TestRouteRemoveAll(t *testing.T) {
+	repo := postgres.NewRouteRepository(db)
+	user := "removed@example.com"
+
+	for i := 0; i < 2; i++ {
+		_, err := repo.Save(newRoute(t, user, "1"))
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	}
+
+	routes, err := repo.RetrieveAll(user)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	require.Len(t, routes, 2, fmt.Sprintf("expected 2 routes got %d", len(routes)))
+
+	err = repo.Remove(user, routes[0].ID)
+	assert.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	routes, err = repo.RetrieveAll(user)
+	assert.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.Len(t, routes, 1, fmt.Sprintf("expected 1 route got %d", len(routes)))
+
+	err = repo.RemoveAll(user)
+	assert.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	routes, err = repo.RetrieveAll(user)
+	assert.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.Len(t, routes, 0, fmt.Sprintf("expected no routes got %d", len(routes)))
+}
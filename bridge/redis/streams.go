@@ -0,0 +1,69 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package redis
+
+import (
+	"github.com/go-redis/redis"
+	"github.com/mainflux/mainflux/bridge"
+	"github.com/mainflux/mainflux/events"
+	"github.com/mainflux/mainflux/logger"
+)
+
+const (
+	stream = "mainflux.things"
+	group  = "mainflux.bridge"
+
+	channelPrefix = "channel."
+	channelRemove = channelPrefix + "remove"
+)
+
+// EventStore represents event source for things and channels provisioning.
+type EventStore interface {
+	// Subscribes to given subject and receives events.
+	Subscribe(string) error
+}
+
+type eventStore struct {
+	svc      bridge.Service
+	client   redis.UniversalClient
+	consumer string
+	logger   logger.Logger
+}
+
+// NewEventStore returns new event store instance.
+func NewEventStore(svc bridge.Service, client redis.UniversalClient, consumer string, log logger.Logger) EventStore {
+	return eventStore{
+		svc:      svc,
+		client:   client,
+		consumer: consumer,
+		logger:   log,
+	}
+}
+
+// Subscribe notifies the owners about the consumed events. Events are
+// acknowledged even if the delivery fails, since redelivering them would
+// repeat the notifications to the subscribers that already received them.
+func (es eventStore) Subscribe(subject string) error {
+	sub := events.NewSubscriber(es.client, events.Config{
+		Stream:   stream,
+		Group:    group,
+		Consumer: es.consumer,
+	}, es.logger)
+
+	return sub.Subscribe(events.HandlerFunc(es.handle))
+}
+
+func (es eventStore) handle(event events.Event) error {
+	var err error
+	switch event.Read("operation", "") {
+	case channelRemove:
+		err = es.svc.RemoveChannelHandler(event.Read("id", ""))
+	}
+
+	return err
+}
@@ -0,0 +1,177 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package bridge
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/mainflux/mainflux"
+)
+
+// Supported route providers.
+const (
+	GCP = "gcp"
+	AWS = "aws"
+)
+
+// SubtopicPlaceholder is replaced with the message subtopic, having dots
+// replaced with slashes, in the AWS IoT Core route topic.
+const SubtopicPlaceholder = "{subtopic}"
+
+var (
+	gcpTopic        = regexp.MustCompile(`^projects/[^/]+/topics/[^/]+$`)
+	gcpSubscription = regexp.MustCompile(`^projects/[^/]+/subscriptions/[^/]+$`)
+)
+
+// Route represents the forwarding of the messages published to the channel,
+// optionally narrowed down to the single subtopic, to the topic of the cloud
+// messaging service. Commands received on the route's command source, if
+// any, are published back to the channel, on the command subtopic.
+type Route struct {
+	ID              string
+	Owner           string
+	Channel         string
+	Subtopic        string
+	Provider        string
+	Topic           string
+	Commands        string
+	CommandSubtopic string
+	Credentials     Credentials
+}
+
+// Credentials used to access the cloud messaging service on behalf of the
+// route owner. GCP routes are authorized using the service account key in
+// JSON format, while AWS routes connect to the IoT Core data endpoint using
+// the PEM encoded client certificate and key.
+type Credentials struct {
+	ServiceAccount string
+	Endpoint       string
+	Cert           string
+	Key            string
+}
+
+// ServiceAccount represents the fields of the GCP service account key used
+// to obtain the access tokens.
+type ServiceAccount struct {
+	Email      string `json:"client_email"`
+	PrivateKey string `json:"private_key"`
+	TokenURI   string `json:"token_uri"`
+}
+
+// Validate returns an error if route representation is invalid.
+func (r Route) Validate() error {
+	if r.Channel == "" || r.Topic == "" {
+		return ErrMalformedEntity
+	}
+
+	if strings.ContainsAny(r.Subtopic+r.CommandSubtopic, "*>") {
+		return ErrMalformedEntity
+	}
+
+	switch r.Provider {
+	case GCP:
+		if !gcpTopic.MatchString(r.Topic) {
+			return ErrMalformedEntity
+		}
+		if r.Commands != "" && !gcpSubscription.MatchString(r.Commands) {
+			return ErrMalformedEntity
+		}
+		if _, err := r.Credentials.Account(); err != nil {
+			return ErrMalformedEntity
+		}
+	case AWS:
+		if strings.ContainsAny(r.Topic, "+#") || r.Credentials.Endpoint == "" {
+			return ErrMalformedEntity
+		}
+		if _, err := tls.X509KeyPair([]byte(r.Credentials.Cert), []byte(r.Credentials.Key)); err != nil {
+			return ErrMalformedEntity
+		}
+	default:
+		return ErrMalformedEntity
+	}
+
+	return nil
+}
+
+// Matches returns true if the message is forwarded by the route.
+func (r Route) Matches(msg mainflux.RawMessage) bool {
+	return msg.GetChannel() == r.Channel && (r.Subtopic == "" || msg.GetSubtopic() == r.Subtopic)
+}
+
+// Account parses the GCP service account key.
+func (c Credentials) Account() (ServiceAccount, error) {
+	var sa ServiceAccount
+	if err := json.Unmarshal([]byte(c.ServiceAccount), &sa); err != nil {
+		return ServiceAccount{}, err
+	}
+
+	if sa.Email == "" || sa.PrivateKey == "" {
+		return ServiceAccount{}, ErrMalformedEntity
+	}
+
+	return sa, nil
+}
+
+// RouteRepository specifies a route persistence API.
+type RouteRepository interface {
+	// Save persists the route. Successful operation is indicated by unique
+	// identifier accompanied by nil error response. A non-nil error is
+	// returned to indicate operation failure.
+	Save(Route) (string, error)
+
+	// RetrieveByID retrieves the route having the provided identifier, that
+	// is owned by the specified user.
+	RetrieveByID(string, string) (Route, error)
+
+	// RetrieveAll retrieves all the routes owned by the specified user.
+	RetrieveAll(string) ([]Route, error)
+
+	// RetrieveByChannel retrieves all the routes of the specified channel.
+	RetrieveByChannel(string) ([]Route, error)
+
+	// RetrieveWithCommands retrieves all the routes having the command
+	// source.
+	RetrieveWithCommands() ([]Route, error)
+
+	// Remove removes the route having the provided identifier, that is
+	// owned by the specified user.
+	Remove(string, string) error
+
+	// RemoveByChannel removes all the routes of the specified channel.
+	RemoveByChannel(string) error
+
+	// RemoveAll removes all the routes owned by the specified user.
+	RemoveAll(string) error
+}
+
+// CommandHandler handles the command payload received for the route.
+type CommandHandler func([]byte) error
+
+// Provider specifies an API of the cloud messaging service the messages are
+// forwarded to, and the commands are consumed from.
+type Provider interface {
+	// Publish sends the message to the route topic.
+	Publish(Route, mainflux.RawMessage) error
+
+	// Subscribe starts consuming the commands from the route command
+	// source, passing them to the handler.
+	Subscribe(Route, CommandHandler) error
+
+	// Unsubscribe stops consuming the commands of the route having the
+	// provided identifier, and releases the resources held for the route.
+	Unsubscribe(string) error
+}
+
+// IdentityProvider specifies an API for generating unique identifiers.
+type IdentityProvider interface {
+	// ID generates the unique identifier.
+	ID() (string, error)
+}
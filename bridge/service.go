@@ -0,0 +1,304 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package bridge
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/mainflux/mainflux"
+)
+
+// Protocol is the protocol of the commands published to the channels. The
+// messages published using the protocol are not forwarded, so that commands
+// don't loop back to the cloud.
+const Protocol = "bridge"
+
+var (
+	// ErrMalformedEntity indicates malformed entity specification (e.g.
+	// invalid route topic or credentials).
+	ErrMalformedEntity = errors.New("malformed entity specification")
+
+	// ErrUnauthorizedAccess indicates missing or invalid credentials provided
+	// when accessing a protected resource.
+	ErrUnauthorizedAccess = errors.New("missing or invalid credentials provided")
+
+	// ErrNotFound indicates a non-existent entity request.
+	ErrNotFound = errors.New("non-existent entity")
+
+	// ErrUnsupportedProvider indicates the route provider that isn't
+	// enabled.
+	ErrUnsupportedProvider = errors.New("unsupported route provider")
+)
+
+// Service specifies an API that must be fullfiled by the domain service
+// implementation, and all of its decorators (e.g. logging & metrics).
+type Service interface {
+	// AddRoute adds the route of the channel owned by the user identified
+	// by the provided key, and starts consuming its commands.
+	AddRoute(string, Route) (Route, error)
+
+	// ViewRoute retrieves data about the route identified with the
+	// provided ID, that belongs to the user identified by the provided key.
+	ViewRoute(string, string) (Route, error)
+
+	// ListRoutes retrieves all the routes that belong to the user
+	// identified by the provided key.
+	ListRoutes(string) ([]Route, error)
+
+	// RemoveRoute removes the route identified with the provided ID, that
+	// belongs to the user identified by the provided key.
+	RemoveRoute(string, string) error
+
+	// Forward sends the message to all the matching routes of its channel.
+	// Forwarding to the remaining routes is attempted even if some of them
+	// fail, in which case the last error is returned.
+	Forward(mainflux.RawMessage) error
+
+	// Start starts consuming the commands of the existing routes.
+	Start() error
+
+	// RemoveChannelHandler removes all the routes of the removed channel.
+	RemoveChannelHandler(string) error
+
+	// RemoveUserHandler removes all the routes of the removed user.
+	RemoveUserHandler(string) error
+}
+
+var _ Service = (*bridgeService)(nil)
+
+type bridgeService struct {
+	users     mainflux.UsersServiceClient
+	things    mainflux.ThingsServiceClient
+	routes    RouteRepository
+	idp       IdentityProvider
+	providers map[string]Provider
+	publisher mainflux.MessagePublisher
+}
+
+// New instantiates the bridge service implementation. Messages are
+// forwarded using the provider of the route type, while the commands are
+// published to the channels using the given publisher.
+func New(users mainflux.UsersServiceClient, things mainflux.ThingsServiceClient, routes RouteRepository, idp IdentityProvider, providers map[string]Provider, publisher mainflux.MessagePublisher) Service {
+	return &bridgeService{
+		users:     users,
+		things:    things,
+		routes:    routes,
+		idp:       idp,
+		providers: providers,
+		publisher: publisher,
+	}
+}
+
+func (bs *bridgeService) AddRoute(key string, route Route) (Route, error) {
+	if err := route.Validate(); err != nil {
+		return Route{}, err
+	}
+
+	provider, ok := bs.providers[route.Provider]
+	if !ok {
+		return Route{}, ErrUnsupportedProvider
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	res, err := bs.things.CanRead(ctx, &mainflux.AccessReq{Token: key, ChanID: route.Channel})
+	if err != nil {
+		return Route{}, ErrUnauthorizedAccess
+	}
+
+	route.ID, err = bs.idp.ID()
+	if err != nil {
+		return Route{}, err
+	}
+	route.Owner = res.GetValue()
+
+	if route.Commands != "" {
+		if err := provider.Subscribe(route, bs.command(route)); err != nil {
+			return Route{}, err
+		}
+	}
+
+	if _, err := bs.routes.Save(route); err != nil {
+		provider.Unsubscribe(route.ID)
+		return Route{}, err
+	}
+
+	return route, nil
+}
+
+func (bs *bridgeService) ViewRoute(key, id string) (Route, error) {
+	owner, err := bs.identify(key)
+	if err != nil {
+		return Route{}, err
+	}
+
+	return bs.routes.RetrieveByID(owner, id)
+}
+
+func (bs *bridgeService) ListRoutes(key string) ([]Route, error) {
+	owner, err := bs.identify(key)
+	if err != nil {
+		return []Route{}, err
+	}
+
+	return bs.routes.RetrieveAll(owner)
+}
+
+func (bs *bridgeService) RemoveRoute(key, id string) error {
+	owner, err := bs.identify(key)
+	if err != nil {
+		return err
+	}
+
+	route, err := bs.routes.RetrieveByID(owner, id)
+	if err == ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := bs.routes.Remove(owner, id); err != nil {
+		return err
+	}
+
+	return bs.release([]Route{route})
+}
+
+func (bs *bridgeService) Forward(msg mainflux.RawMessage) error {
+	if msg.GetProtocol() == Protocol {
+		return nil
+	}
+
+	routes, err := bs.routes.RetrieveByChannel(msg.GetChannel())
+	if err != nil {
+		return err
+	}
+
+	var lastErr error
+	for _, route := range routes {
+		if !route.Matches(msg) {
+			continue
+		}
+
+		provider, ok := bs.providers[route.Provider]
+		if !ok {
+			lastErr = ErrUnsupportedProvider
+			continue
+		}
+
+		if err := provider.Publish(route, msg); err != nil {
+			lastErr = err
+		}
+	}
+
+	return lastErr
+}
+
+func (bs *bridgeService) Start() error {
+	routes, err := bs.routes.RetrieveWithCommands()
+	if err != nil {
+		return err
+	}
+
+	var lastErr error
+	for _, route := range routes {
+		provider, ok := bs.providers[route.Provider]
+		if !ok {
+			lastErr = ErrUnsupportedProvider
+			continue
+		}
+
+		if err := provider.Subscribe(route, bs.command(route)); err != nil {
+			lastErr = err
+		}
+	}
+
+	return lastErr
+}
+
+func (bs *bridgeService) RemoveChannelHandler(id string) error {
+	routes, err := bs.routes.RetrieveByChannel(id)
+	if err != nil {
+		return err
+	}
+
+	if err := bs.routes.RemoveByChannel(id); err != nil {
+		return err
+	}
+
+	return bs.release(routes)
+}
+
+func (bs *bridgeService) RemoveUserHandler(owner string) error {
+	routes, err := bs.routes.RetrieveAll(owner)
+	if err != nil {
+		return err
+	}
+
+	if err := bs.routes.RemoveAll(owner); err != nil {
+		return err
+	}
+
+	return bs.release(routes)
+}
+
+// release stops consuming the commands of the removed routes.
+func (bs *bridgeService) release(routes []Route) error {
+	var lastErr error
+	for _, route := range routes {
+		provider, ok := bs.providers[route.Provider]
+		if !ok {
+			continue
+		}
+
+		if err := provider.Unsubscribe(route.ID); err != nil {
+			lastErr = err
+		}
+	}
+
+	return lastErr
+}
+
+// command returns the handler publishing the commands received for the
+// route to its channel.
+func (bs *bridgeService) command(route Route) CommandHandler {
+	return func(payload []byte) error {
+		id, err := bs.idp.ID()
+		if err != nil {
+			return err
+		}
+
+		msg := mainflux.RawMessage{
+			Channel:   route.Channel,
+			Subtopic:  route.CommandSubtopic,
+			Publisher: route.ID,
+			Protocol:  Protocol,
+			Payload:   payload,
+			Received:  float64(time.Now().UnixNano()) / 1e9,
+			Id:        id,
+		}
+
+		return bs.publisher.Publish(msg)
+	}
+}
+
+func (bs *bridgeService) identify(key string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	res, err := bs.users.Identify(ctx, &mainflux.Token{Value: key})
+	if err != nil {
+		return "", ErrUnauthorizedAccess
+	}
+
+	return res.GetValue(), nil
+}
@@ -0,0 +1,402 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package bridge_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/bridge"
+	"github.com/mainflux/mainflux/bridge/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	validToken   = "validToken"
+	otherToken   = "otherToken"
+	invalidToken = "invalidToken"
+	email        = "user@example.com"
+	otherEmail   = "other@example.com"
+	chanID       = "1"
+	otherChanID  = "2"
+	topic        = "projects/mainflux/topics/telemetry"
+	failingTopic = "projects/mainflux/topics/failing"
+	commands     = "projects/mainflux/subscriptions/commands"
+	account      = `{"client_email": "bridge@mainflux.iam.gserviceaccount.com", "private_key": "key"}`
+)
+
+var (
+	errPublish = errors.New("failed to publish")
+
+	route = bridge.Route{
+		Channel:     chanID,
+		Provider:    bridge.GCP,
+		Topic:       topic,
+		Credentials: bridge.Credentials{ServiceAccount: account},
+	}
+)
+
+func newService(provider bridge.Provider, publisher mainflux.MessagePublisher) bridge.Service {
+	tokens := map[string]string{validToken: email, otherToken: otherEmail}
+	users := mocks.NewUsersService(tokens)
+	things := mocks.NewThingsService(tokens, map[string]string{chanID: email, otherChanID: otherEmail})
+	routes := mocks.NewRouteRepository()
+	idp := mocks.NewIdentityProvider()
+	providers := map[string]bridge.Provider{bridge.GCP: provider}
+
+	return bridge.New(users, things, routes, idp, providers, publisher)
+}
+
+func TestAddRoute(t *testing.T) {
+	svc := newService(mocks.NewProvider(nil), mocks.NewPublisher())
+
+	malformed := route
+	malformed.Topic = "telemetry"
+
+	wildcard := route
+	wildcard.Subtopic = "sensors.*"
+
+	invalidCert := route
+	invalidCert.Provider = bridge.AWS
+	invalidCert.Topic = "mainflux/telemetry"
+	invalidCert.Credentials = bridge.Credentials{Endpoint: "example.iot.eu-west-1.amazonaws.com"}
+
+	foreign := route
+	foreign.Channel = otherChanID
+
+	cases := []struct {
+		desc  string
+		token string
+		route bridge.Route
+		err   error
+	}{
+		{
+			desc:  "add route with valid token",
+			token: validToken,
+			route: route,
+			err:   nil,
+		},
+		{
+			desc:  "add route with invalid token",
+			token: invalidToken,
+			route: route,
+			err:   bridge.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "add route of another user's channel",
+			token: validToken,
+			route: foreign,
+			err:   bridge.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "add route with malformed topic",
+			token: validToken,
+			route: malformed,
+			err:   bridge.ErrMalformedEntity,
+		},
+		{
+			desc:  "add route with wildcard subtopic",
+			token: validToken,
+			route: wildcard,
+			err:   bridge.ErrMalformedEntity,
+		},
+		{
+			desc:  "add route with invalid certificate",
+			token: validToken,
+			route: invalidCert,
+			err:   bridge.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := svc.AddRoute(tc.token, tc.route)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestViewRoute(t *testing.T) {
+	svc := newService(mocks.NewProvider(nil), mocks.NewPublisher())
+	saved, err := svc.AddRoute(validToken, route)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		token string
+		id    string
+		err   error
+	}{
+		{
+			desc:  "view existing route",
+			token: validToken,
+			id:    saved.ID,
+			err:   nil,
+		},
+		{
+			desc:  "view route with invalid token",
+			token: invalidToken,
+			id:    saved.ID,
+			err:   bridge.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "view route of another user",
+			token: otherToken,
+			id:    saved.ID,
+			err:   bridge.ErrNotFound,
+		},
+		{
+			desc:  "view non-existing route",
+			token: validToken,
+			id:    "unknown",
+			err:   bridge.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		r, err := svc.ViewRoute(tc.token, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err == nil {
+			assert.Equal(t, saved, r, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, saved, r))
+		}
+	}
+}
+
+func TestListRoutes(t *testing.T) {
+	svc := newService(mocks.NewProvider(nil), mocks.NewPublisher())
+
+	n := 3
+	for i := 0; i < n; i++ {
+		_, err := svc.AddRoute(validToken, route)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	cases := []struct {
+		desc  string
+		token string
+		size  int
+		err   error
+	}{
+		{
+			desc:  "list routes of the user",
+			token: validToken,
+			size:  n,
+			err:   nil,
+		},
+		{
+			desc:  "list routes of the user without routes",
+			token: otherToken,
+			size:  0,
+			err:   nil,
+		},
+		{
+			desc:  "list routes with invalid token",
+			token: invalidToken,
+			size:  0,
+			err:   bridge.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		routes, err := svc.ListRoutes(tc.token)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Len(t, routes, tc.size, fmt.Sprintf("%s: expected %d routes got %d\n", tc.desc, tc.size, len(routes)))
+	}
+}
+
+func TestRemoveRoute(t *testing.T) {
+	provider := mocks.NewProvider(nil)
+	svc := newService(provider, mocks.NewPublisher())
+
+	r := route
+	r.Commands = commands
+	saved, err := svc.AddRoute(validToken, r)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		token string
+		id    string
+		err   error
+	}{
+		{
+			desc:  "remove route with invalid token",
+			token: invalidToken,
+			id:    saved.ID,
+			err:   bridge.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "remove existing route",
+			token: validToken,
+			id:    saved.ID,
+			err:   nil,
+		},
+		{
+			desc:  "remove removed route",
+			token: validToken,
+			id:    saved.ID,
+			err:   nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.RemoveRoute(tc.token, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	_, err = svc.ViewRoute(validToken, saved.ID)
+	assert.Equal(t, bridge.ErrNotFound, err, fmt.Sprintf("view removed route: expected %s got %s\n", bridge.ErrNotFound, err))
+
+	ok, _ := provider.Command(saved.ID, []byte("on"))
+	assert.False(t, ok, "expected commands of the removed route not to be consumed")
+}
+
+func TestForward(t *testing.T) {
+	provider := mocks.NewProvider(map[string]error{failingTopic: errPublish})
+	svc := newService(provider, mocks.NewPublisher())
+
+	all, err := svc.AddRoute(validToken, route)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	narrowed := route
+	narrowed.Topic = "projects/mainflux/topics/temperature"
+	narrowed.Subtopic = "temperature"
+	narrowed, err = svc.AddRoute(validToken, narrowed)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		msg    mainflux.RawMessage
+		topics map[string]int
+		err    error
+	}{
+		{
+			desc:   "forward message without subtopic",
+			msg:    mainflux.RawMessage{Channel: chanID, Payload: []byte("1")},
+			topics: map[string]int{all.Topic: 1, narrowed.Topic: 0},
+			err:    nil,
+		},
+		{
+			desc:   "forward message on the route subtopic",
+			msg:    mainflux.RawMessage{Channel: chanID, Subtopic: "temperature", Payload: []byte("2")},
+			topics: map[string]int{all.Topic: 2, narrowed.Topic: 1},
+			err:    nil,
+		},
+		{
+			desc:   "forward message of the channel without routes",
+			msg:    mainflux.RawMessage{Channel: otherChanID, Payload: []byte("3")},
+			topics: map[string]int{all.Topic: 2, narrowed.Topic: 1},
+			err:    nil,
+		},
+		{
+			desc:   "forward command published by the bridge",
+			msg:    mainflux.RawMessage{Channel: chanID, Protocol: bridge.Protocol, Payload: []byte("4")},
+			topics: map[string]int{all.Topic: 2, narrowed.Topic: 1},
+			err:    nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.Forward(tc.msg)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		for topic, n := range tc.topics {
+			assert.Len(t, provider.Published(topic), n, fmt.Sprintf("%s: expected %d messages on %s got %d\n", tc.desc, n, topic, len(provider.Published(topic))))
+		}
+	}
+
+	failing := route
+	failing.Topic = failingTopic
+	_, err = svc.AddRoute(validToken, failing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	err = svc.Forward(mainflux.RawMessage{Channel: chanID, Payload: []byte("5")})
+	assert.Equal(t, errPublish, err, fmt.Sprintf("forward to failing route: expected %s got %s\n", errPublish, err))
+	assert.Len(t, provider.Published(all.Topic), 3, fmt.Sprintf("expected forwarding to the remaining routes, got %d messages", len(provider.Published(all.Topic))))
+}
+
+func TestCommands(t *testing.T) {
+	provider := mocks.NewProvider(nil)
+	publisher := mocks.NewPublisher()
+	svc := newService(provider, publisher)
+
+	r := route
+	r.Commands = commands
+	r.CommandSubtopic = "commands"
+	saved, err := svc.AddRoute(validToken, r)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	ok, err := provider.Command(saved.ID, []byte("on"))
+	require.True(t, ok, "expected commands of the route to be consumed")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	msgs := publisher.Messages()
+	require.Len(t, msgs, 1, fmt.Sprintf("expected 1 published command got %d", len(msgs)))
+	assert.Equal(t, chanID, msgs[0].Channel, fmt.Sprintf("expected command on channel %s got %s", chanID, msgs[0].Channel))
+	assert.Equal(t, "commands", msgs[0].Subtopic, fmt.Sprintf("expected command on subtopic %s got %s", "commands", msgs[0].Subtopic))
+	assert.Equal(t, bridge.Protocol, msgs[0].Protocol, fmt.Sprintf("expected command protocol %s got %s", bridge.Protocol, msgs[0].Protocol))
+	assert.Equal(t, []byte("on"), msgs[0].Payload, fmt.Sprintf("expected command payload %s got %s", "on", msgs[0].Payload))
+
+	// Restarted service resumes consuming the commands of the saved routes.
+	provider.Unsubscribe(saved.ID)
+	err = svc.Start()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	ok, _ = provider.Command(saved.ID, []byte("off"))
+	assert.True(t, ok, "expected commands of the route to be consumed after start")
+}
+
+func TestRemoveChannelHandler(t *testing.T) {
+	provider := mocks.NewProvider(nil)
+	svc := newService(provider, mocks.NewPublisher())
+
+	r := route
+	r.Commands = commands
+	saved, err := svc.AddRoute(validToken, r)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	err = svc.RemoveChannelHandler(chanID)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	_, err = svc.ViewRoute(validToken, saved.ID)
+	assert.Equal(t, bridge.ErrNotFound, err, fmt.Sprintf("view route of removed channel: expected %s got %s\n", bridge.ErrNotFound, err))
+
+	ok, _ := provider.Command(saved.ID, []byte("on"))
+	assert.False(t, ok, "expected commands of the removed channel not to be consumed")
+}
+
+func TestRemoveUserHandler(t *testing.T) {
+	svc := newService(mocks.NewProvider(nil), mocks.NewPublisher())
+
+	saved, err := svc.AddRoute(validToken, route)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		owner string
+		err   error
+	}{
+		{
+			desc:  "remove routes of an existing user",
+			owner: email,
+			err:   nil,
+		},
+		{
+			desc:  "remove routes of a removed user",
+			owner: email,
+			err:   nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.RemoveUserHandler(tc.owner)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	_, err = svc.ViewRoute(validToken, saved.ID)
+	assert.Equal(t, bridge.ErrNotFound, err, fmt.Sprintf("view removed user's route: expected %s got %s\n", bridge.ErrNotFound, err))
+}
@@ -0,0 +1,32 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// Package uuid provides a UUID identity provider.
+package uuid
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/mainflux/mainflux/bridge"
+)
+
+var _ bridge.IdentityProvider = (*uuidIdentityProvider)(nil)
+
+type uuidIdentityProvider struct{}
+
+// New instantiates a UUID identity provider.
+func New() bridge.IdentityProvider {
+	return &uuidIdentityProvider{}
+}
+
+func (idp *uuidIdentityProvider) ID() (string, error) {
+	id, err := uuid.NewV4()
+	if err != nil {
+		return "", err
+	}
+
+	return id.String(), nil
+}
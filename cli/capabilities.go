@@ -0,0 +1,66 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	thingKind   = "thing"
+	channelKind = "channel"
+)
+
+// NewCapabilitiesCmd returns capabilities command.
+func NewCapabilitiesCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "capabilities",
+		Short: "capabilities [<thing | channel> <entity_id>] <user_auth_token>",
+		Long:  `Get operations the user may perform on the account, or on the thing or channel`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) != 1 && len(args) != 3 {
+				logUsage(cmd.Short)
+				return
+			}
+
+			kind, id, token := "", "", args[0]
+			if len(args) == 3 {
+				kind, id, token = args[0], args[1], args[2]
+			}
+
+			c, err := sdk.Capabilities(kind, id, token)
+			if err != nil {
+				logError(err)
+				return
+			}
+
+			logJSON(c)
+		},
+	}
+}
+
+// allowed returns false and warns if the capabilities of the entity forbid
+// the operation. Since the services decide on the operation anyway, it's
+// allowed if the capabilities can't be retrieved, e.g. from an older
+// version of the services.
+func allowed(kind, id, op, token string) bool {
+	c, err := sdk.Capabilities(kind, id, token)
+	if err != nil {
+		return true
+	}
+
+	if !c.Allows(op) {
+		exitCode = ExitUnauthorized
+		logWarning(fmt.Sprintf("operation %s is not allowed on %s %s", op, kind, id))
+		return false
+	}
+
+	return true
+}
@@ -86,6 +86,10 @@ var cmdChannels = []cobra.Command{
 				return
 			}
 
+			if !allowed(channelKind, channel.ID, "update", args[1]) {
+				return
+			}
+
 			if err := sdk.UpdateChannel(channel, args[1]); err != nil {
 				logError(err)
 				return
@@ -104,6 +108,10 @@ var cmdChannels = []cobra.Command{
 				return
 			}
 
+			if !allowed(channelKind, args[0], "remove", args[1]) {
+				return
+			}
+
 			if err := sdk.DeleteChannel(args[0], args[1]); err != nil {
 				logError(err)
 				return
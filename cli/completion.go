@@ -0,0 +1,45 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package cli
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// NewCompletionCmd returns shell completion command.
+func NewCompletionCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:       "completion",
+		Short:     "completion <bash | zsh>",
+		Long:      `Generate shell completion script and write it to the standard output`,
+		ValidArgs: []string{"bash", "zsh"},
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) != 1 {
+				logUsage(cmd.Short)
+				return
+			}
+
+			var err error
+			switch args[0] {
+			case "bash":
+				err = cmd.Root().GenBashCompletion(os.Stdout)
+			case "zsh":
+				err = cmd.Root().GenZshCompletion(os.Stdout)
+			default:
+				logUsage(cmd.Short)
+				return
+			}
+
+			if err != nil {
+				logError(err)
+			}
+		},
+	}
+}
@@ -81,6 +81,10 @@ var cmdThings = []cobra.Command{
 				return
 			}
 
+			if !allowed(thingKind, args[0], "remove", args[1]) {
+				return
+			}
+
 			if err := sdk.DeleteThing(args[0], args[1]); err != nil {
 				logError(err)
 				return
@@ -105,6 +109,10 @@ var cmdThings = []cobra.Command{
 				return
 			}
 
+			if !allowed(thingKind, thing.ID, "update", args[1]) {
+				return
+			}
+
 			if err := sdk.UpdateThing(thing, args[1]); err != nil {
 				logError(err)
 				return
@@ -123,6 +131,10 @@ var cmdThings = []cobra.Command{
 				return
 			}
 
+			if !allowed(thingKind, args[0], "connect", args[2]) || !allowed(channelKind, args[1], "connect", args[2]) {
+				return
+			}
+
 			if err := sdk.ConnectThing(args[0], args[1], args[2]); err != nil {
 				logError(err)
 				return
@@ -141,6 +153,10 @@ var cmdThings = []cobra.Command{
 				return
 			}
 
+			if !allowed(thingKind, args[0], "disconnect", args[2]) || !allowed(channelKind, args[1], "disconnect", args[2]) {
+				return
+			}
+
 			if err := sdk.DisconnectThing(args[0], args[1], args[2]); err != nil {
 				logError(err)
 				return
@@ -10,9 +10,28 @@ package cli
 import (
 	"encoding/json"
 	"fmt"
+	"os"
 
 	"github.com/fatih/color"
 	prettyjson "github.com/hokaccha/go-prettyjson"
+	mfxsdk "github.com/mainflux/mainflux/sdk/go"
+)
+
+// Exit codes of the CLI, telling the scripts why the command failed.
+const (
+	// ExitOK indicates that the command succeeded.
+	ExitOK = 0
+	// ExitFailure indicates that the command failed for any other reason.
+	ExitFailure = 1
+	// ExitUsage indicates that the command was invoked with invalid arguments.
+	ExitUsage = 2
+	// ExitUnauthorized indicates that the user isn't allowed to perform the
+	// command, e.g. because the token is invalid or expired.
+	ExitUnauthorized = 3
+	// ExitNotFound indicates that the entity doesn't exist.
+	ExitNotFound = 4
+	// ExitConflict indicates that the entity already exists.
+	ExitConflict = 5
 )
 
 var (
@@ -22,8 +41,17 @@ var (
 	Offset uint = 0
 	// Name query parameter
 	Name string = ""
+	// Quiet prints only the command result, with errors written to stderr
+	Quiet bool = false
+
+	exitCode = ExitOK
 )
 
+// ExitCode returns the code the CLI should exit with after the command.
+func ExitCode() int {
+	return exitCode
+}
+
 func logJSON(iList ...interface{}) {
 	for _, i := range iList {
 		m, err := json.Marshal(i)
@@ -32,6 +60,11 @@ func logJSON(iList ...interface{}) {
 			return
 		}
 
+		if Quiet {
+			fmt.Println(string(m))
+			continue
+		}
+
 		pj, err := prettyjson.Format(m)
 		if err != nil {
 			logError(err)
@@ -43,17 +76,57 @@ func logJSON(iList ...interface{}) {
 }
 
 func logUsage(u string) {
+	exitCode = ExitUsage
+	if Quiet {
+		fmt.Fprintf(os.Stderr, "usage: %s\n", u)
+		return
+	}
 	fmt.Printf(color.YellowString("\nusage: %s\n\n"), u)
 }
 
 func logError(err error) {
+	exitCode = errorCode(err)
+	if Quiet {
+		fmt.Fprintln(os.Stderr, err.Error())
+		return
+	}
 	fmt.Printf("\n%s\n\n", color.RedString(err.Error()))
 }
 
+func logWarning(w string) {
+	if Quiet {
+		fmt.Fprintln(os.Stderr, w)
+		return
+	}
+	fmt.Printf("\n%s\n\n", color.YellowString(w))
+}
+
 func logOK() {
+	if Quiet {
+		return
+	}
 	fmt.Printf("\n%s\n\n", color.BlueString("ok"))
 }
 
 func logCreated(e string) {
+	if Quiet {
+		fmt.Println(e)
+		return
+	}
 	fmt.Printf(color.BlueString("\ncreated: %s\n\n"), e)
 }
+
+func errorCode(err error) int {
+	switch err {
+	case mfxsdk.ErrInvalidArgs, mfxsdk.ErrInvalidContentType:
+		return ExitUsage
+	case mfxsdk.ErrUnauthorized:
+		return ExitUnauthorized
+	case mfxsdk.ErrNotFound:
+		return ExitNotFound
+	case mfxsdk.ErrConflict:
+		return ExitConflict
+	default:
+		return ExitFailure
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"os/signal"
 	"strconv"
 	"syscall"
+	"time"
 
 	rediscons "github.com/mainflux/mainflux/bootstrap/redis/consumer"
 	redisprod "github.com/mainflux/mainflux/bootstrap/redis/producer"
@@ -26,11 +27,13 @@ import (
 	api "github.com/mainflux/mainflux/bootstrap/api"
 	"github.com/mainflux/mainflux/bootstrap/postgres"
 	mflog "github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/mtls"
+	"github.com/mainflux/mainflux/ratelimit"
+	mfredis "github.com/mainflux/mainflux/redis"
 	mfsdk "github.com/mainflux/mainflux/sdk/go"
 	usersapi "github.com/mainflux/mainflux/users/api/grpc"
 	stdprometheus "github.com/prometheus/client_golang/prometheus"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
 )
 
 const (
@@ -44,8 +47,12 @@ const (
 	defDBSSLCert     = ""
 	defDBSSLKey      = ""
 	defDBSSLRootCert = ""
+	defDBTarget      = "read-write"
+	defDBSyncCommit  = ""
 	defClientTLS     = "false"
 	defCACerts       = ""
+	defClientCert    = ""
+	defClientKey     = ""
 	defPort          = "8180"
 	defServerCert    = ""
 	defServerKey     = ""
@@ -55,10 +62,15 @@ const (
 	defThingsESURL   = "localhost:6379"
 	defThingsESPass  = ""
 	defThingsESDB    = "0"
+	defUsersESURL    = "localhost:6379"
+	defUsersESPass   = ""
+	defUsersESDB     = "0"
 	defESURL         = "localhost:6379"
 	defESPass        = ""
 	defESDB          = "0"
 	defInstanceName  = "bootstrap"
+	defRateLimit     = "0"
+	defRateWindow    = "1m"
 
 	envLogLevel      = "MF_BOOTSTRAP_LOG_LEVEL"
 	envDBHost        = "MF_BOOTSTRAP_DB_HOST"
@@ -70,8 +82,12 @@ const (
 	envDBSSLCert     = "MF_BOOTSTRAP_DB_SSL_CERT"
 	envDBSSLKey      = "MF_BOOTSTRAP_DB_SSL_KEY"
 	envDBSSLRootCert = "MF_BOOTSTRAP_DB_SSL_ROOT_CERT"
+	envDBTarget      = "MF_BOOTSTRAP_DB_TARGET"
+	envDBSyncCommit  = "MF_BOOTSTRAP_DB_SYNC_COMMIT"
 	envClientTLS     = "MF_BOOTSTRAP_CLIENT_TLS"
 	envCACerts       = "MF_BOOTSTRAP_CA_CERTS"
+	envClientCert    = "MF_BOOTSTRAP_CLIENT_CERT"
+	envClientKey     = "MF_BOOTSTRAP_CLIENT_KEY"
 	envPort          = "MF_BOOTSTRAP_PORT"
 	envServerCert    = "MF_BOOTSTRAP_SERVER_CERT"
 	envServerKey     = "MF_BOOTSTRAP_SERVER_KEY"
@@ -81,10 +97,15 @@ const (
 	envThingsESURL   = "MF_THINGS_ES_URL"
 	envThingsESPass  = "MF_THINGS_ES_PASS"
 	envThingsESDB    = "MF_THINGS_ES_DB"
+	envUsersESURL    = "MF_USERS_ES_URL"
+	envUsersESPass   = "MF_USERS_ES_PASS"
+	envUsersESDB     = "MF_USERS_ES_DB"
 	envESURL         = "MF_BOOTSTRAP_ES_URL"
 	envESPass        = "MF_BOOTSTRAP_ES_PASS"
 	envESDB          = "MF_BOOTSTRAP_ES_DB"
 	envInstanceName  = "MF_BOOTSTRAP_INSTANCE_NAME"
+	envRateLimit     = "MF_BOOTSTRAP_API_RATE_LIMIT"
+	envRateWindow    = "MF_BOOTSTRAP_API_RATE_WINDOW"
 )
 
 type config struct {
@@ -92,6 +113,8 @@ type config struct {
 	dbConfig     postgres.Config
 	clientTLS    bool
 	caCerts      string
+	clientCert   string
+	clientKey    string
 	httpPort     string
 	serverCert   string
 	serverKey    string
@@ -101,10 +124,15 @@ type config struct {
 	esThingsURL  string
 	esThingsPass string
 	esThingsDB   string
+	esUsersURL   string
+	esUsersPass  string
+	esUsersDB    string
 	esURL        string
 	esPass       string
 	esDB         string
 	instanceName string
+	rateLimit    uint64
+	rateWindow   time.Duration
 }
 
 func main() {
@@ -124,14 +152,18 @@ func main() {
 	thingsESConn := connectToRedis(cfg.esThingsURL, cfg.esThingsPass, cfg.esThingsDB, logger)
 	defer thingsESConn.Close()
 
+	usersESConn := connectToRedis(cfg.esUsersURL, cfg.esUsersPass, cfg.esUsersDB, logger)
+	defer usersESConn.Close()
+
 	esClient := connectToRedis(cfg.esURL, cfg.esPass, cfg.esDB, logger)
 	defer esClient.Close()
 
 	svc := newService(conn, db, logger, esClient, cfg)
 	errs := make(chan error, 2)
 
-	go startHTTPServer(svc, cfg, logger, errs)
+	go startHTTPServer(svc, usersapi.NewClient(conn), newRateLimiter(cfg, esClient), cfg, logger, errs)
 	go subscribeToThingsES(svc, thingsESConn, cfg.instanceName, logger)
+	go subscribeToUsersES(svc, usersESConn, cfg.instanceName, logger)
 
 	go func() {
 		c := make(chan os.Signal)
@@ -158,6 +190,18 @@ func loadConfig() config {
 		SSLCert:     mainflux.Env(envDBSSLCert, defDBSSLCert),
 		SSLKey:      mainflux.Env(envDBSSLKey, defDBSSLKey),
 		SSLRootCert: mainflux.Env(envDBSSLRootCert, defDBSSLRootCert),
+		Target:      mainflux.Env(envDBTarget, defDBTarget),
+		SyncCommit:  mainflux.Env(envDBSyncCommit, defDBSyncCommit),
+	}
+
+	rateLimit, err := strconv.ParseUint(mainflux.Env(envRateLimit, defRateLimit), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envRateLimit)
+	}
+
+	rateWindow, err := time.ParseDuration(mainflux.Env(envRateWindow, defRateWindow))
+	if err != nil || rateWindow <= 0 {
+		log.Fatalf("Invalid value passed for %s\n", envRateWindow)
 	}
 
 	return config{
@@ -165,6 +209,8 @@ func loadConfig() config {
 		dbConfig:     dbConfig,
 		clientTLS:    tls,
 		caCerts:      mainflux.Env(envCACerts, defCACerts),
+		clientCert:   mainflux.Env(envClientCert, defClientCert),
+		clientKey:    mainflux.Env(envClientKey, defClientKey),
 		httpPort:     mainflux.Env(envPort, defPort),
 		serverCert:   mainflux.Env(envServerCert, defServerCert),
 		serverKey:    mainflux.Env(envServerKey, defServerKey),
@@ -174,10 +220,15 @@ func loadConfig() config {
 		esThingsURL:  mainflux.Env(envThingsESURL, defThingsESURL),
 		esThingsPass: mainflux.Env(envThingsESPass, defThingsESPass),
 		esThingsDB:   mainflux.Env(envThingsESDB, defThingsESDB),
+		esUsersURL:   mainflux.Env(envUsersESURL, defUsersESURL),
+		esUsersPass:  mainflux.Env(envUsersESPass, defUsersESPass),
+		esUsersDB:    mainflux.Env(envUsersESDB, defUsersESDB),
 		esURL:        mainflux.Env(envESURL, defESURL),
 		esPass:       mainflux.Env(envESPass, defESPass),
 		esDB:         mainflux.Env(envESDB, defESDB),
 		instanceName: mainflux.Env(envInstanceName, defInstanceName),
+		rateLimit:    rateLimit,
+		rateWindow:   rateWindow,
 	}
 }
 
@@ -190,22 +241,19 @@ func connectToDB(cfg postgres.Config, logger mflog.Logger) *sqlx.DB {
 	return db
 }
 
-func connectToRedis(redisURL, redisPass, redisDB string, logger mflog.Logger) *r.Client {
-	db, err := strconv.Atoi(redisDB)
+func connectToRedis(redisURL, redisPass, redisDB string, logger mflog.Logger) r.UniversalClient {
+	client, err := mfredis.Connect(redisURL, redisPass, redisDB)
 	if err != nil {
 		logger.Error(fmt.Sprintf("Failed to connect to redis: %s", err))
 		os.Exit(1)
 	}
 
-	return r.NewClient(&r.Options{
-		Addr:     redisURL,
-		Password: redisPass,
-		DB:       db,
-	})
+	return client
 }
 
-func newService(conn *grpc.ClientConn, db *sqlx.DB, logger mflog.Logger, esClient *r.Client, cfg config) bootstrap.Service {
+func newService(conn *grpc.ClientConn, db *sqlx.DB, logger mflog.Logger, esClient r.UniversalClient, cfg config) bootstrap.Service {
 	thingsRepo := postgres.NewConfigRepository(db, logger)
+	claimsRepo := postgres.NewClaimRepository(db)
 
 	config := mfsdk.Config{
 		BaseURL:      cfg.baseURL,
@@ -215,7 +263,7 @@ func newService(conn *grpc.ClientConn, db *sqlx.DB, logger mflog.Logger, esClien
 	sdk := mfsdk.NewSDK(config)
 	users := usersapi.NewClient(conn)
 
-	svc := bootstrap.New(users, thingsRepo, sdk)
+	svc := bootstrap.New(users, thingsRepo, claimsRepo, sdk)
 	svc = redisprod.NewEventStoreMiddleware(svc, esClient)
 	svc = api.NewLoggingMiddleware(svc, logger)
 	svc = api.MetricsMiddleware(
@@ -239,14 +287,12 @@ func newService(conn *grpc.ClientConn, db *sqlx.DB, logger mflog.Logger, esClien
 func connectToUsers(cfg config, logger mflog.Logger) *grpc.ClientConn {
 	var opts []grpc.DialOption
 	if cfg.clientTLS {
-		if cfg.caCerts != "" {
-			tpc, err := credentials.NewClientTLSFromFile(cfg.caCerts, "")
-			if err != nil {
-				logger.Error(fmt.Sprintf("Failed to create tls credentials: %s", err))
-				os.Exit(1)
-			}
-			opts = append(opts, grpc.WithTransportCredentials(tpc))
+		tpc, err := mtls.ClientCredentials(cfg.caCerts, cfg.clientCert, cfg.clientKey)
+		if err != nil {
+			logger.Error(fmt.Sprintf("Failed to create tls credentials: %s", err))
+			os.Exit(1)
 		}
+		opts = append(opts, grpc.WithTransportCredentials(tpc))
 	} else {
 		opts = append(opts, grpc.WithInsecure())
 		logger.Info("gRPC communication is not encrypted")
@@ -261,22 +307,39 @@ func connectToUsers(cfg config, logger mflog.Logger) *grpc.ClientConn {
 	return conn
 }
 
-func startHTTPServer(svc bootstrap.Service, cfg config, logger mflog.Logger, errs chan error) {
+func newRateLimiter(cfg config, client r.UniversalClient) ratelimit.Limiter {
+	if cfg.rateLimit == 0 {
+		return nil
+	}
+
+	return ratelimit.NewLimiter(client, "bootstrap", cfg.rateLimit, cfg.rateWindow)
+}
+
+func startHTTPServer(svc bootstrap.Service, users mainflux.UsersServiceClient, limiter ratelimit.Limiter, cfg config, logger mflog.Logger, errs chan error) {
 	p := fmt.Sprintf(":%s", cfg.httpPort)
+	handler := ratelimit.Handler(limiter, ratelimit.UsersIdentifier(users), api.MakeHandler(svc, bootstrap.NewConfigReader()))
 	if cfg.serverCert != "" || cfg.serverKey != "" {
 		logger.Info(fmt.Sprintf("Bootstrap service started using https on port %s with cert %s key %s",
 			cfg.httpPort, cfg.serverCert, cfg.serverKey))
-		errs <- http.ListenAndServeTLS(p, cfg.serverCert, cfg.serverKey, api.MakeHandler(svc, bootstrap.NewConfigReader()))
+		errs <- http.ListenAndServeTLS(p, cfg.serverCert, cfg.serverKey, handler)
 		return
 	}
 	logger.Info(fmt.Sprintf("Bootstrap service started using http on port %s", cfg.httpPort))
-	errs <- http.ListenAndServe(p, api.MakeHandler(svc, bootstrap.NewConfigReader()))
+	errs <- http.ListenAndServe(p, handler)
 }
 
-func subscribeToThingsES(svc bootstrap.Service, client *r.Client, consumer string, logger mflog.Logger) {
+func subscribeToThingsES(svc bootstrap.Service, client r.UniversalClient, consumer string, logger mflog.Logger) {
 	eventStore := rediscons.NewEventStore(svc, client, consumer, logger)
 	logger.Info("Subscribed to Redis Event Store")
 	if err := eventStore.Subscribe("mainflux.things"); err != nil {
 		logger.Warn(fmt.Sprintf("Botstrap service failed to subscribe to event sourcing: %s", err))
 	}
 }
+
+func subscribeToUsersES(svc bootstrap.Service, client r.UniversalClient, consumer string, logger mflog.Logger) {
+	eventStore := rediscons.NewUsersEventStore(svc, client, consumer, logger)
+	logger.Info("Subscribed to Redis Event Store")
+	if err := eventStore.Subscribe("mainflux.users"); err != nil {
+		logger.Warn(fmt.Sprintf("Bootstrap service failed to subscribe to event sourcing: %s", err))
+	}
+}
@@ -0,0 +1,338 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	r "github.com/go-redis/redis"
+	"github.com/jmoiron/sqlx"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/bridge"
+	"github.com/mainflux/mainflux/bridge/api"
+	"github.com/mainflux/mainflux/bridge/aws"
+	"github.com/mainflux/mainflux/bridge/gcp"
+	"github.com/mainflux/mainflux/bridge/nats"
+	"github.com/mainflux/mainflux/bridge/postgres"
+	"github.com/mainflux/mainflux/bridge/redis"
+	"github.com/mainflux/mainflux/bridge/uuid"
+	mflog "github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/mtls"
+	mfredis "github.com/mainflux/mainflux/redis"
+	thingsapi "github.com/mainflux/mainflux/things/api/grpc"
+	usersapi "github.com/mainflux/mainflux/users/api/grpc"
+	broker "github.com/nats-io/go-nats"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+)
+
+const (
+	defLogLevel      = "error"
+	defDBHost        = "localhost"
+	defDBPort        = "5432"
+	defDBUser        = "mainflux"
+	defDBPass        = "mainflux"
+	defDBName        = "bridge"
+	defDBSSLMode     = "disable"
+	defDBSSLCert     = ""
+	defDBSSLKey      = ""
+	defDBSSLRootCert = ""
+	defDBTarget      = "read-write"
+	defDBSyncCommit  = ""
+	defClientTLS     = "false"
+	defCACerts       = ""
+	defClientCert    = ""
+	defClientKey     = ""
+	defPort          = "8250"
+	defServerCert    = ""
+	defServerKey     = ""
+	defGCPURL        = gcp.DefaultURL
+	defTimeout       = "10" // in seconds
+	defNatsURL       = broker.DefaultURL
+	defUsersURL      = "localhost:8181"
+	defThingsURL     = "localhost:8181"
+	defThingsESURL   = "localhost:6379"
+	defThingsESPass  = ""
+	defThingsESDB    = "0"
+	defUsersESURL    = "localhost:6379"
+	defUsersESPass   = ""
+	defUsersESDB     = "0"
+	defInstanceName  = "bridge"
+
+	envLogLevel      = "MF_BRIDGE_LOG_LEVEL"
+	envDBHost        = "MF_BRIDGE_DB_HOST"
+	envDBPort        = "MF_BRIDGE_DB_PORT"
+	envDBUser        = "MF_BRIDGE_DB_USER"
+	envDBPass        = "MF_BRIDGE_DB_PASS"
+	envDBName        = "MF_BRIDGE_DB"
+	envDBSSLMode     = "MF_BRIDGE_DB_SSL_MODE"
+	envDBSSLCert     = "MF_BRIDGE_DB_SSL_CERT"
+	envDBSSLKey      = "MF_BRIDGE_DB_SSL_KEY"
+	envDBSSLRootCert = "MF_BRIDGE_DB_SSL_ROOT_CERT"
+	envDBTarget      = "MF_BRIDGE_DB_TARGET"
+	envDBSyncCommit  = "MF_BRIDGE_DB_SYNC_COMMIT"
+	envClientTLS     = "MF_BRIDGE_CLIENT_TLS"
+	envCACerts       = "MF_BRIDGE_CA_CERTS"
+	envClientCert    = "MF_BRIDGE_CLIENT_CERT"
+	envClientKey     = "MF_BRIDGE_CLIENT_KEY"
+	envPort          = "MF_BRIDGE_PORT"
+	envServerCert    = "MF_BRIDGE_SERVER_CERT"
+	envServerKey     = "MF_BRIDGE_SERVER_KEY"
+	envGCPURL        = "MF_BRIDGE_GCP_URL"
+	envTimeout       = "MF_BRIDGE_TIMEOUT"
+	envNatsURL       = "MF_NATS_URL"
+	envUsersURL      = "MF_USERS_URL"
+	envThingsURL     = "MF_THINGS_URL"
+	envThingsESURL   = "MF_THINGS_ES_URL"
+	envThingsESPass  = "MF_THINGS_ES_PASS"
+	envThingsESDB    = "MF_THINGS_ES_DB"
+	envUsersESURL    = "MF_USERS_ES_URL"
+	envUsersESPass   = "MF_USERS_ES_PASS"
+	envUsersESDB     = "MF_USERS_ES_DB"
+	envInstanceName  = "MF_BRIDGE_INSTANCE_NAME"
+)
+
+type config struct {
+	logLevel     string
+	dbConfig     postgres.Config
+	clientTLS    bool
+	caCerts      string
+	clientCert   string
+	clientKey    string
+	httpPort     string
+	serverCert   string
+	serverKey    string
+	gcpURL       string
+	timeout      time.Duration
+	natsURL      string
+	usersURL     string
+	thingsURL    string
+	esThingsURL  string
+	esThingsPass string
+	esThingsDB   string
+	esUsersURL   string
+	esUsersPass  string
+	esUsersDB    string
+	instanceName string
+}
+
+func main() {
+	cfg := loadConfig()
+
+	logger, err := mflog.New(os.Stdout, cfg.logLevel)
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+
+	db := connectToDB(cfg.dbConfig, logger)
+	defer db.Close()
+
+	nc, err := broker.Connect(cfg.natsURL)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to NATS: %s", err))
+		os.Exit(1)
+	}
+	defer nc.Close()
+
+	usersConn := connectToGRPC(cfg, cfg.usersURL, "users", logger)
+	defer usersConn.Close()
+
+	thingsConn := connectToGRPC(cfg, cfg.thingsURL, "things", logger)
+	defer thingsConn.Close()
+
+	thingsESConn := connectToRedis(cfg.esThingsURL, cfg.esThingsPass, cfg.esThingsDB, logger)
+	defer thingsESConn.Close()
+
+	usersESConn := connectToRedis(cfg.esUsersURL, cfg.esUsersPass, cfg.esUsersDB, logger)
+	defer usersESConn.Close()
+
+	svc := newService(usersConn, thingsConn, db, nc, logger, cfg)
+	errs := make(chan error, 2)
+
+	go startHTTPServer(svc, cfg, logger, errs)
+	go subscribeToThingsES(svc, thingsESConn, cfg.instanceName, logger)
+	go subscribeToUsersES(svc, usersESConn, cfg.instanceName, logger)
+
+	if err := nats.Subscribe(svc, nc, logger); err != nil {
+		logger.Error(fmt.Sprintf("Failed to subscribe to NATS: %s", err))
+		os.Exit(1)
+	}
+
+	if err := svc.Start(); err != nil {
+		logger.Warn(fmt.Sprintf("Failed to consume commands of some routes: %s", err))
+	}
+
+	go func() {
+		c := make(chan os.Signal)
+		signal.Notify(c, syscall.SIGINT)
+		errs <- fmt.Errorf("%s", <-c)
+	}()
+
+	err = <-errs
+	logger.Error(fmt.Sprintf("Bridge service terminated: %s", err))
+}
+
+func loadConfig() config {
+	tls, err := strconv.ParseBool(mainflux.Env(envClientTLS, defClientTLS))
+	if err != nil {
+		tls = false
+	}
+
+	timeout, err := strconv.ParseInt(mainflux.Env(envTimeout, defTimeout), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envTimeout, err.Error())
+	}
+
+	dbConfig := postgres.Config{
+		Host:        mainflux.Env(envDBHost, defDBHost),
+		Port:        mainflux.Env(envDBPort, defDBPort),
+		User:        mainflux.Env(envDBUser, defDBUser),
+		Pass:        mainflux.Env(envDBPass, defDBPass),
+		Name:        mainflux.Env(envDBName, defDBName),
+		SSLMode:     mainflux.Env(envDBSSLMode, defDBSSLMode),
+		SSLCert:     mainflux.Env(envDBSSLCert, defDBSSLCert),
+		SSLKey:      mainflux.Env(envDBSSLKey, defDBSSLKey),
+		SSLRootCert: mainflux.Env(envDBSSLRootCert, defDBSSLRootCert),
+		Target:      mainflux.Env(envDBTarget, defDBTarget),
+		SyncCommit:  mainflux.Env(envDBSyncCommit, defDBSyncCommit),
+	}
+
+	return config{
+		logLevel:     mainflux.Env(envLogLevel, defLogLevel),
+		dbConfig:     dbConfig,
+		clientTLS:    tls,
+		caCerts:      mainflux.Env(envCACerts, defCACerts),
+		clientCert:   mainflux.Env(envClientCert, defClientCert),
+		clientKey:    mainflux.Env(envClientKey, defClientKey),
+		httpPort:     mainflux.Env(envPort, defPort),
+		serverCert:   mainflux.Env(envServerCert, defServerCert),
+		serverKey:    mainflux.Env(envServerKey, defServerKey),
+		gcpURL:       mainflux.Env(envGCPURL, defGCPURL),
+		timeout:      time.Duration(timeout) * time.Second,
+		natsURL:      mainflux.Env(envNatsURL, defNatsURL),
+		usersURL:     mainflux.Env(envUsersURL, defUsersURL),
+		thingsURL:    mainflux.Env(envThingsURL, defThingsURL),
+		esThingsURL:  mainflux.Env(envThingsESURL, defThingsESURL),
+		esThingsPass: mainflux.Env(envThingsESPass, defThingsESPass),
+		esThingsDB:   mainflux.Env(envThingsESDB, defThingsESDB),
+		esUsersURL:   mainflux.Env(envUsersESURL, defUsersESURL),
+		esUsersPass:  mainflux.Env(envUsersESPass, defUsersESPass),
+		esUsersDB:    mainflux.Env(envUsersESDB, defUsersESDB),
+		instanceName: mainflux.Env(envInstanceName, defInstanceName),
+	}
+}
+
+func connectToDB(cfg postgres.Config, logger mflog.Logger) *sqlx.DB {
+	db, err := postgres.Connect(cfg)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to postgres: %s", err))
+		os.Exit(1)
+	}
+	return db
+}
+
+func connectToRedis(redisURL, redisPass, redisDB string, logger mflog.Logger) r.UniversalClient {
+	client, err := mfredis.Connect(redisURL, redisPass, redisDB)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to redis: %s", err))
+		os.Exit(1)
+	}
+
+	return client
+}
+
+func newService(usersConn, thingsConn *grpc.ClientConn, db *sqlx.DB, nc *broker.Conn, logger mflog.Logger, cfg config) bridge.Service {
+	routes := postgres.NewRouteRepository(db)
+	users := usersapi.NewClient(usersConn)
+	things := thingsapi.NewClient(thingsConn)
+	idp := uuid.New()
+	publisher := nats.NewMessagePublisher(nc)
+
+	providers := map[string]bridge.Provider{
+		bridge.GCP: gcp.New(cfg.gcpURL, cfg.timeout, logger),
+		bridge.AWS: aws.New(cfg.timeout, logger),
+	}
+
+	svc := bridge.New(users, things, routes, idp, providers, publisher)
+	svc = api.NewLoggingMiddleware(svc, logger)
+	svc = api.MetricsMiddleware(
+		svc,
+		kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: "bridge",
+			Subsystem: "api",
+			Name:      "request_count",
+			Help:      "Number of requests received.",
+		}, []string{"method"}),
+		kitprometheus.NewSummaryFrom(stdprometheus.SummaryOpts{
+			Namespace: "bridge",
+			Subsystem: "api",
+			Name:      "request_latency_microseconds",
+			Help:      "Total duration of requests in microseconds.",
+		}, []string{"method"}),
+	)
+	return svc
+}
+
+func connectToGRPC(cfg config, url, name string, logger mflog.Logger) *grpc.ClientConn {
+	var opts []grpc.DialOption
+	if cfg.clientTLS {
+		tpc, err := mtls.ClientCredentials(cfg.caCerts, cfg.clientCert, cfg.clientKey)
+		if err != nil {
+			logger.Error(fmt.Sprintf("Failed to create tls credentials: %s", err))
+			os.Exit(1)
+		}
+		opts = append(opts, grpc.WithTransportCredentials(tpc))
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+		logger.Info("gRPC communication is not encrypted")
+	}
+
+	conn, err := grpc.Dial(url, opts...)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to %s service: %s", name, err))
+		os.Exit(1)
+	}
+
+	return conn
+}
+
+func startHTTPServer(svc bridge.Service, cfg config, logger mflog.Logger, errs chan error) {
+	p := fmt.Sprintf(":%s", cfg.httpPort)
+	if cfg.serverCert != "" || cfg.serverKey != "" {
+		logger.Info(fmt.Sprintf("Bridge service started using https on port %s with cert %s key %s",
+			cfg.httpPort, cfg.serverCert, cfg.serverKey))
+		errs <- http.ListenAndServeTLS(p, cfg.serverCert, cfg.serverKey, api.MakeHandler(svc))
+		return
+	}
+	logger.Info(fmt.Sprintf("Bridge service started using http on port %s", cfg.httpPort))
+	errs <- http.ListenAndServe(p, api.MakeHandler(svc))
+}
+
+func subscribeToThingsES(svc bridge.Service, client r.UniversalClient, consumer string, logger mflog.Logger) {
+	eventStore := redis.NewEventStore(svc, client, consumer, logger)
+	logger.Info("Subscribed to Redis Event Store")
+	if err := eventStore.Subscribe("mainflux.things"); err != nil {
+		logger.Warn(fmt.Sprintf("Bridge service failed to subscribe to event sourcing: %s", err))
+	}
+}
+
+func subscribeToUsersES(svc bridge.Service, client r.UniversalClient, consumer string, logger mflog.Logger) {
+	eventStore := redis.NewUsersEventStore(svc, client, consumer, logger)
+	logger.Info("Subscribed to Redis Event Store")
+	if err := eventStore.Subscribe("mainflux.users"); err != nil {
+		logger.Warn(fmt.Sprintf("Bridge service failed to subscribe to event sourcing: %s", err))
+	}
+}
@@ -21,13 +21,15 @@ import (
 	"github.com/gocql/gocql"
 	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/mtls"
 	"github.com/mainflux/mainflux/readers"
 	"github.com/mainflux/mainflux/readers/api"
 	"github.com/mainflux/mainflux/readers/cassandra"
+	"github.com/mainflux/mainflux/readers/decompression"
 	thingsapi "github.com/mainflux/mainflux/things/api/grpc"
+	"github.com/mainflux/mainflux/things/jwt"
 	stdprometheus "github.com/prometheus/client_golang/prometheus"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
 )
 
 const (
@@ -41,8 +43,11 @@ const (
 	defDBPassword = ""
 	defDBPort     = "9042"
 	defThingsURL  = "localhost:8181"
+	defKeysSecret = ""
 	defClientTLS  = "false"
 	defCACerts    = ""
+	defClientCert = ""
+	defClientKey  = ""
 
 	envLogLevel   = "MF_CASSANDRA_READER_LOG_LEVEL"
 	envPort       = "MF_CASSANDRA_READER_PORT"
@@ -52,17 +57,23 @@ const (
 	envDBPassword = "MF_CASSANDRA_READER_DB_PASSWORD"
 	envDBPort     = "MF_CASSANDRA_READER_DB_PORT"
 	envThingsURL  = "MF_THINGS_URL"
+	envKeysSecret = "MF_THINGS_KEYS_SECRET"
 	envClientTLS  = "MF_CASSANDRA_READER_CLIENT_TLS"
 	envCACerts    = "MF_CASSANDRA_READER_CA_CERTS"
+	envClientCert = "MF_CASSANDRA_READER_CLIENT_CERT"
+	envClientKey  = "MF_CASSANDRA_READER_CLIENT_KEY"
 )
 
 type config struct {
-	logLevel  string
-	port      string
-	dbCfg     cassandra.DBConfig
-	thingsURL string
-	clientTLS bool
-	caCerts   string
+	logLevel   string
+	port       string
+	dbCfg      cassandra.DBConfig
+	thingsURL  string
+	keysSecret string
+	clientTLS  bool
+	caCerts    string
+	clientCert string
+	clientKey  string
 }
 
 func main() {
@@ -80,6 +91,9 @@ func main() {
 	defer conn.Close()
 
 	tc := thingsapi.NewClient(conn)
+	if cfg.keysSecret != "" {
+		tc = jwt.NewInvitationsClient(tc, jwt.NewKeyProvider(cfg.keysSecret))
+	}
 	repo := newService(session, logger)
 
 	errs := make(chan error, 2)
@@ -116,12 +130,15 @@ func loadConfig() config {
 	}
 
 	return config{
-		logLevel:  mainflux.Env(envLogLevel, defLogLevel),
-		port:      mainflux.Env(envPort, defPort),
-		dbCfg:     dbCfg,
-		thingsURL: mainflux.Env(envThingsURL, defThingsURL),
-		clientTLS: tls,
-		caCerts:   mainflux.Env(envCACerts, defCACerts),
+		logLevel:   mainflux.Env(envLogLevel, defLogLevel),
+		port:       mainflux.Env(envPort, defPort),
+		dbCfg:      dbCfg,
+		thingsURL:  mainflux.Env(envThingsURL, defThingsURL),
+		keysSecret: mainflux.Env(envKeysSecret, defKeysSecret),
+		clientTLS:  tls,
+		caCerts:    mainflux.Env(envCACerts, defCACerts),
+		clientCert: mainflux.Env(envClientCert, defClientCert),
+		clientKey:  mainflux.Env(envClientKey, defClientKey),
 	}
 }
 
@@ -138,14 +155,12 @@ func connectToCassandra(dbCfg cassandra.DBConfig, logger logger.Logger) *gocql.S
 func connectToThings(cfg config, logger logger.Logger) *grpc.ClientConn {
 	var opts []grpc.DialOption
 	if cfg.clientTLS {
-		if cfg.caCerts != "" {
-			tpc, err := credentials.NewClientTLSFromFile(cfg.caCerts, "")
-			if err != nil {
-				logger.Error(fmt.Sprintf("Failed to load certs: %s", err))
-				os.Exit(1)
-			}
-			opts = append(opts, grpc.WithTransportCredentials(tpc))
+		tpc, err := mtls.ClientCredentials(cfg.caCerts, cfg.clientCert, cfg.clientKey)
+		if err != nil {
+			logger.Error(fmt.Sprintf("Failed to load certs: %s", err))
+			os.Exit(1)
 		}
+		opts = append(opts, grpc.WithTransportCredentials(tpc))
 	} else {
 		logger.Info("gRPC communication is not encrypted")
 		opts = append(opts, grpc.WithInsecure())
@@ -160,7 +175,7 @@ func connectToThings(cfg config, logger logger.Logger) *grpc.ClientConn {
 }
 
 func newService(session *gocql.Session, logger logger.Logger) readers.MessageRepository {
-	repo := cassandra.New(session)
+	repo := decompression.New(cassandra.New(session))
 	repo = api.LoggingMiddleware(repo, logger)
 	repo = api.MetricsMiddleware(
 		repo,
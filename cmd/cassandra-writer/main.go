@@ -17,6 +17,7 @@ import (
 	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/BurntSushi/toml"
 	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
@@ -56,11 +57,13 @@ const (
 )
 
 type config struct {
-	natsURL  string
-	logLevel string
-	port     string
-	dbCfg    cassandra.DBConfig
-	channels map[string]bool
+	natsURL     string
+	logLevel    string
+	port        string
+	dbCfg       cassandra.DBConfig
+	channels    map[string]bool
+	sampling    map[string]writers.SamplingRule
+	compression map[string]string
 }
 
 func main() {
@@ -78,7 +81,7 @@ func main() {
 	defer session.Close()
 
 	repo := newService(session, logger)
-	if err := writers.Start(nc, repo, svcName, cfg.channels, logger); err != nil {
+	if err := writers.Start(nc, repo, svcName, cfg.channels, cfg.sampling, cfg.compression, logger); err != nil {
 		logger.Error(fmt.Sprintf("Failed to create Cassandra writer: %s", err))
 	}
 
@@ -111,12 +114,15 @@ func loadConfig() config {
 	}
 
 	chanCfgPath := mainflux.Env(envChanCfgPath, defChanCfgPath)
+	chans, sampling, compression := loadChansConfig(chanCfgPath)
 	return config{
-		natsURL:  mainflux.Env(envNatsURL, defNatsURL),
-		logLevel: mainflux.Env(envLogLevel, defLogLevel),
-		port:     mainflux.Env(envPort, defPort),
-		dbCfg:    dbCfg,
-		channels: loadChansConfig(chanCfgPath),
+		natsURL:     mainflux.Env(envNatsURL, defNatsURL),
+		logLevel:    mainflux.Env(envLogLevel, defLogLevel),
+		port:        mainflux.Env(envPort, defPort),
+		dbCfg:       dbCfg,
+		channels:    chans,
+		sampling:    sampling,
+		compression: compression,
 	}
 }
 
@@ -124,11 +130,18 @@ type channels struct {
 	List []string `toml:"filter"`
 }
 
+type sampling struct {
+	Every    uint64 `toml:"every"`
+	Interval string `toml:"interval"`
+}
+
 type chanConfig struct {
-	Channels channels `toml:"channels"`
+	Channels    channels            `toml:"channels"`
+	Sampling    map[string]sampling `toml:"sampling"`
+	Compression map[string]string   `toml:"compression"`
 }
 
-func loadChansConfig(chanConfigPath string) map[string]bool {
+func loadChansConfig(chanConfigPath string) (map[string]bool, map[string]writers.SamplingRule, map[string]string) {
 	data, err := ioutil.ReadFile(chanConfigPath)
 	if err != nil {
 		log.Fatal(err)
@@ -144,7 +157,18 @@ func loadChansConfig(chanConfigPath string) map[string]bool {
 		chans[ch] = true
 	}
 
-	return chans
+	rules := map[string]writers.SamplingRule{}
+	for ch, s := range chanCfg.Sampling {
+		rule := writers.SamplingRule{Every: s.Every}
+		if s.Interval != "" {
+			if rule.Interval, err = time.ParseDuration(s.Interval); err != nil {
+				log.Fatal(err)
+			}
+		}
+		rules[ch] = rule
+	}
+
+	return chans, rules, chanCfg.Compression
 }
 
 func connectToNATS(url string, logger logger.Logger) *nats.Conn {
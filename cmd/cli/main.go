@@ -8,7 +8,7 @@
 package main
 
 import (
-	"log"
+	"os"
 
 	"github.com/mainflux/mainflux/cli"
 	"github.com/mainflux/mainflux/sdk/go"
@@ -27,6 +27,7 @@ func main() {
 		MsgContentType:    sdk.ContentType(msgContentType),
 		TLSVerification:   false,
 	}
+	user := sdk.User{}
 
 	// Root
 	var rootCmd = &cobra.Command{
@@ -34,6 +35,9 @@ func main() {
 		PersistentPreRun: func(cmd *cobra.Command, args []string) {
 			sdkConf.MsgContentType = sdk.ContentType(msgContentType)
 			s := sdk.NewSDK(sdkConf)
+			if user.Email != "" && user.Password != "" {
+				s.SetCredentials(user)
+			}
 			cli.SetSDK(s)
 		},
 	}
@@ -45,6 +49,8 @@ func main() {
 	channelsCmd := cli.NewChannelsCmd()
 	messagesCmd := cli.NewMessagesCmd()
 	provisionCmd := cli.NewProvisionCmd()
+	capabilitiesCmd := cli.NewCapabilitiesCmd()
+	completionCmd := cli.NewCompletionCmd()
 
 	// Root Commands
 	rootCmd.AddCommand(versionCmd)
@@ -53,6 +59,8 @@ func main() {
 	rootCmd.AddCommand(channelsCmd)
 	rootCmd.AddCommand(messagesCmd)
 	rootCmd.AddCommand(provisionCmd)
+	rootCmd.AddCommand(capabilitiesCmd)
+	rootCmd.AddCommand(completionCmd)
 
 	// Root Flags
 	rootCmd.PersistentFlags().StringVarP(
@@ -103,6 +111,28 @@ func main() {
 		"Do not check for TLS cert",
 	)
 
+	rootCmd.PersistentFlags().StringVar(
+		&user.Email,
+		"email",
+		"",
+		"User email used to renew expired tokens",
+	)
+
+	rootCmd.PersistentFlags().StringVar(
+		&user.Password,
+		"password",
+		"",
+		"User password used to renew expired tokens",
+	)
+
+	rootCmd.PersistentFlags().BoolVarP(
+		&cli.Quiet,
+		"quiet",
+		"q",
+		false,
+		"Print only the command result, and errors to stderr",
+	)
+
 	// Client and Channels Flags
 	rootCmd.PersistentFlags().UintVarP(
 		&cli.Limit,
@@ -129,6 +159,8 @@ func main() {
 	)
 
 	if err := rootCmd.Execute(); err != nil {
-		log.Fatal(err)
+		os.Exit(cli.ExitUsage)
 	}
+
+	os.Exit(cli.ExitCode())
 }
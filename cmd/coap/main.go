@@ -14,50 +14,95 @@ import (
 	"os"
 	"os/signal"
 	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
 	gocoap "github.com/dustin/go-coap"
 	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	r "github.com/go-redis/redis"
 	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/coap"
 	"github.com/mainflux/mainflux/coap/api"
 	"github.com/mainflux/mainflux/coap/nats"
 	logger "github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/mtls"
+	"github.com/mainflux/mainflux/publish"
+	mfredis "github.com/mainflux/mainflux/redis"
 	thingsapi "github.com/mainflux/mainflux/things/api/grpc"
+	"github.com/mainflux/mainflux/things/jwt"
+	thingsnats "github.com/mainflux/mainflux/things/nats"
 	stdprometheus "github.com/prometheus/client_golang/prometheus"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
 
 	broker "github.com/nats-io/go-nats"
 )
 
+const revocationsTimeout = 5 * time.Second
+
 const (
 	defPort       = "5683"
 	defNatsURL    = broker.DefaultURL
 	defThingsURL  = "localhost:8181"
+	defKeysSecret = ""
 	defLogLevel   = "error"
 	defClientTLS  = "false"
 	defCACerts    = ""
+	defClientCert = ""
+	defClientKey  = ""
 	defPingPeriod = "12"
+	defSecProfile = api.NoSecProfile
+	defNoSecNets  = ""
+	defDTLSProxy  = ""
+	defRateLimit  = "0"
+	defRateBurst  = "10"
+	defCacheURL   = ""
+	defCachePass  = ""
+	defCacheDB    = "0"
+	defAnonymize  = "false"
+	defPubTimeout = "5s"
 
 	envPort       = "MF_COAP_ADAPTER_PORT"
 	envNatsURL    = "MF_NATS_URL"
 	envThingsURL  = "MF_THINGS_URL"
+	envKeysSecret = "MF_THINGS_KEYS_SECRET"
 	envLogLevel   = "MF_COAP_ADAPTER_LOG_LEVEL"
 	envClientTLS  = "MF_COAP_ADAPTER_CLIENT_TLS"
 	envCACerts    = "MF_COAP_ADAPTER_CA_CERTS"
+	envClientCert = "MF_COAP_ADAPTER_CLIENT_CERT"
+	envClientKey  = "MF_COAP_ADAPTER_CLIENT_KEY"
 	envPingPeriod = "MF_COAP_ADAPTER_PING_PERIOD"
+	envSecProfile = "MF_COAP_ADAPTER_SECURITY_PROFILE"
+	envNoSecNets  = "MF_COAP_ADAPTER_NOSEC_NETWORKS"
+	envDTLSProxy  = "MF_COAP_ADAPTER_DTLS_PROXIES"
+	envRateLimit  = "MF_COAP_ADAPTER_RATE_LIMIT"
+	envRateBurst  = "MF_COAP_ADAPTER_RATE_BURST"
+	envCacheURL   = "MF_COAP_ADAPTER_CACHE_URL"
+	envCachePass  = "MF_COAP_ADAPTER_CACHE_PASS"
+	envCacheDB    = "MF_COAP_ADAPTER_CACHE_DB"
+	envAnonymize  = "MF_COAP_ADAPTER_ANONYMIZE_ADDR"
+	envPubTimeout = "MF_COAP_ADAPTER_PUBLISH_TIMEOUT"
 )
 
 type config struct {
 	port       string
 	natsURL    string
 	thingsURL  string
+	keysSecret string
 	logLevel   string
 	clientTLS  bool
 	caCerts    string
+	clientCert string
+	clientKey  string
 	pingPeriod time.Duration
+	profile    api.SecurityProfile
+	rateLimit  float64
+	rateBurst  uint
+	cacheURL   string
+	cachePass  string
+	cacheDB    string
+	anonymize  bool
+	pubTimeout time.Duration
 }
 
 func main() {
@@ -79,9 +124,31 @@ func main() {
 	defer conn.Close()
 
 	cc := thingsapi.NewClient(conn)
+	if cfg.keysSecret != "" {
+		revocations, err := thingsnats.NewRevocationList(nc, revocationsTimeout, logger)
+		if err != nil {
+			logger.Error(fmt.Sprintf("Failed to retrieve revocations: %s", err))
+			os.Exit(1)
+		}
+		cc = jwt.NewThingsClient(cc, jwt.NewKeyProvider(cfg.keysSecret), revocations)
+	}
+
 	respChan := make(chan string, 10000)
-	pubsub := nats.New(nc)
-	svc := coap.New(pubsub, respChan)
+	pubsub := nats.New(nc, cfg.pubTimeout)
+	cache := connectToCache(cfg, logger)
+	svc := coap.New(
+		pubsub,
+		respChan,
+		publish.Ingress(cfg.anonymize),
+		publish.Validate(0),
+		publish.RateLimit(cfg.rateLimit, cfg.rateBurst),
+		publish.ChannelRateLimit(cache),
+		publish.ChannelProtocols(cache),
+		publish.Transform(cache),
+		publish.ContentType(publish.SenMLJSON),
+		publish.Delivery(cache),
+		publish.Sequence(cache),
+	)
 	svc = api.LoggingMiddleware(svc, logger)
 
 	svc = api.MetricsMiddleware(
@@ -130,28 +197,80 @@ func loadConfig() config {
 		log.Fatalf("Value of %s must be between 1 and 24", envPingPeriod)
 	}
 
+	profile, err := api.NewSecurityProfile(
+		mainflux.Env(envSecProfile, defSecProfile),
+		strings.Split(mainflux.Env(envNoSecNets, defNoSecNets), ","),
+		strings.Split(mainflux.Env(envDTLSProxy, defDTLSProxy), ","),
+	)
+	if err != nil {
+		log.Fatalf("Invalid security profile: %s", err)
+	}
+
+	rateLimit, err := strconv.ParseFloat(mainflux.Env(envRateLimit, defRateLimit), 64)
+	if err != nil || rateLimit < 0 {
+		log.Fatalf("Invalid value passed for %s\n", envRateLimit)
+	}
+
+	rateBurst, err := strconv.ParseUint(mainflux.Env(envRateBurst, defRateBurst), 10, 32)
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envRateBurst)
+	}
+
+	anonymize, err := strconv.ParseBool(mainflux.Env(envAnonymize, defAnonymize))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envAnonymize)
+	}
+
+	pubTimeout, err := time.ParseDuration(mainflux.Env(envPubTimeout, defPubTimeout))
+	if err != nil || pubTimeout <= 0 {
+		log.Fatalf("Invalid value passed for %s\n", envPubTimeout)
+	}
+
 	return config{
 		thingsURL:  mainflux.Env(envThingsURL, defThingsURL),
+		keysSecret: mainflux.Env(envKeysSecret, defKeysSecret),
 		natsURL:    mainflux.Env(envNatsURL, defNatsURL),
 		port:       mainflux.Env(envPort, defPort),
 		logLevel:   mainflux.Env(envLogLevel, defLogLevel),
 		clientTLS:  tls,
 		caCerts:    mainflux.Env(envCACerts, defCACerts),
+		clientCert: mainflux.Env(envClientCert, defClientCert),
+		clientKey:  mainflux.Env(envClientKey, defClientKey),
 		pingPeriod: time.Duration(pp),
+		profile:    profile,
+		rateLimit:  rateLimit,
+		rateBurst:  uint(rateBurst),
+		cacheURL:   mainflux.Env(envCacheURL, defCacheURL),
+		cachePass:  mainflux.Env(envCachePass, defCachePass),
+		cacheDB:    mainflux.Env(envCacheDB, defCacheDB),
+		anonymize:  anonymize,
+		pubTimeout: pubTimeout,
+	}
+}
+
+func connectToCache(cfg config, logger logger.Logger) r.UniversalClient {
+	if cfg.cacheURL == "" {
+		return nil
 	}
+
+	client, err := mfredis.Connect(cfg.cacheURL, cfg.cachePass, cfg.cacheDB)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to cache: %s", err))
+		os.Exit(1)
+	}
+
+	return client
 }
 
 func connectToThings(cfg config, logger logger.Logger) *grpc.ClientConn {
 	var opts []grpc.DialOption
 	if cfg.clientTLS {
-		if cfg.caCerts != "" {
-			tpc, err := credentials.NewClientTLSFromFile(cfg.caCerts, "")
-			if err != nil {
-				logger.Error(fmt.Sprintf("Failed to load certs: %s", err))
-				os.Exit(1)
-			}
-			opts = append(opts, grpc.WithTransportCredentials(tpc))
+		tpc, err := mtls.ClientCredentials(cfg.caCerts, cfg.clientCert, cfg.clientKey)
+		if err != nil {
+			logger.Error(fmt.Sprintf("Failed to load certs: %s", err))
+			os.Exit(1)
 		}
+		opts = append(opts, grpc.WithTransportCredentials(tpc))
 	} else {
 		logger.Info("gRPC communication is not encrypted")
 		opts = append(opts, grpc.WithInsecure())
@@ -174,5 +293,8 @@ func startHTTPServer(port string, logger logger.Logger, errs chan error) {
 func startCOAPServer(cfg config, svc coap.Service, auth mainflux.ThingsServiceClient, respChan chan<- string, l logger.Logger, errs chan error) {
 	p := fmt.Sprintf(":%s", cfg.port)
 	l.Info(fmt.Sprintf("CoAP adapter service started, exposed port %s", cfg.port))
-	errs <- gocoap.ListenAndServe("udp", p, api.MakeCOAPHandler(svc, auth, l, respChan, cfg.pingPeriod))
+	if cfg.profile.Mode == api.NoSecProfile {
+		l.Warn("CoAP adapter accepts NoSec access from all networks")
+	}
+	errs <- gocoap.ListenAndServe("udp", p, api.MakeCOAPHandler(svc, auth, l, respChan, cfg.pingPeriod, cfg.profile))
 }
@@ -0,0 +1,223 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+
+	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	"github.com/jmoiron/sqlx"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/flags"
+	"github.com/mainflux/mainflux/flags/api"
+	"github.com/mainflux/mainflux/flags/postgres"
+	mflog "github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/mtls"
+	usersapi "github.com/mainflux/mainflux/users/api/grpc"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+)
+
+const (
+	defLogLevel      = "error"
+	defDBHost        = "localhost"
+	defDBPort        = "5432"
+	defDBUser        = "mainflux"
+	defDBPass        = "mainflux"
+	defDBName        = "flags"
+	defDBSSLMode     = "disable"
+	defDBSSLCert     = ""
+	defDBSSLKey      = ""
+	defDBSSLRootCert = ""
+	defDBTarget      = "read-write"
+	defDBSyncCommit  = ""
+	defClientTLS     = "false"
+	defCACerts       = ""
+	defClientCert    = ""
+	defClientKey     = ""
+	defPort          = "8220"
+	defServerCert    = ""
+	defServerKey     = ""
+	defAdmins        = ""
+	defUsersURL      = "localhost:8181"
+
+	envLogLevel      = "MF_FLAGS_LOG_LEVEL"
+	envDBHost        = "MF_FLAGS_DB_HOST"
+	envDBPort        = "MF_FLAGS_DB_PORT"
+	envDBUser        = "MF_FLAGS_DB_USER"
+	envDBPass        = "MF_FLAGS_DB_PASS"
+	envDBName        = "MF_FLAGS_DB"
+	envDBSSLMode     = "MF_FLAGS_DB_SSL_MODE"
+	envDBSSLCert     = "MF_FLAGS_DB_SSL_CERT"
+	envDBSSLKey      = "MF_FLAGS_DB_SSL_KEY"
+	envDBSSLRootCert = "MF_FLAGS_DB_SSL_ROOT_CERT"
+	envDBTarget      = "MF_FLAGS_DB_TARGET"
+	envDBSyncCommit  = "MF_FLAGS_DB_SYNC_COMMIT"
+	envClientTLS     = "MF_FLAGS_CLIENT_TLS"
+	envCACerts       = "MF_FLAGS_CA_CERTS"
+	envClientCert    = "MF_FLAGS_CLIENT_CERT"
+	envClientKey     = "MF_FLAGS_CLIENT_KEY"
+	envPort          = "MF_FLAGS_PORT"
+	envServerCert    = "MF_FLAGS_SERVER_CERT"
+	envServerKey     = "MF_FLAGS_SERVER_KEY"
+	envAdmins        = "MF_FLAGS_ADMINS"
+	envUsersURL      = "MF_USERS_URL"
+)
+
+type config struct {
+	logLevel   string
+	dbConfig   postgres.Config
+	clientTLS  bool
+	caCerts    string
+	clientCert string
+	clientKey  string
+	httpPort   string
+	serverCert string
+	serverKey  string
+	admins     []string
+	usersURL   string
+}
+
+func main() {
+	cfg := loadConfig()
+
+	logger, err := mflog.New(os.Stdout, cfg.logLevel)
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+
+	db := connectToDB(cfg.dbConfig, logger)
+	defer db.Close()
+
+	conn := connectToUsers(cfg, logger)
+	defer conn.Close()
+
+	svc := newService(conn, db, logger, cfg)
+	errs := make(chan error, 2)
+
+	go startHTTPServer(svc, cfg, logger, errs)
+
+	go func() {
+		c := make(chan os.Signal)
+		signal.Notify(c, syscall.SIGINT)
+		errs <- fmt.Errorf("%s", <-c)
+	}()
+
+	err = <-errs
+	logger.Error(fmt.Sprintf("Flags service terminated: %s", err))
+}
+
+func loadConfig() config {
+	tls, err := strconv.ParseBool(mainflux.Env(envClientTLS, defClientTLS))
+	if err != nil {
+		tls = false
+	}
+
+	dbConfig := postgres.Config{
+		Host:        mainflux.Env(envDBHost, defDBHost),
+		Port:        mainflux.Env(envDBPort, defDBPort),
+		User:        mainflux.Env(envDBUser, defDBUser),
+		Pass:        mainflux.Env(envDBPass, defDBPass),
+		Name:        mainflux.Env(envDBName, defDBName),
+		SSLMode:     mainflux.Env(envDBSSLMode, defDBSSLMode),
+		SSLCert:     mainflux.Env(envDBSSLCert, defDBSSLCert),
+		SSLKey:      mainflux.Env(envDBSSLKey, defDBSSLKey),
+		SSLRootCert: mainflux.Env(envDBSSLRootCert, defDBSSLRootCert),
+		Target:      mainflux.Env(envDBTarget, defDBTarget),
+		SyncCommit:  mainflux.Env(envDBSyncCommit, defDBSyncCommit),
+	}
+
+	return config{
+		logLevel:   mainflux.Env(envLogLevel, defLogLevel),
+		dbConfig:   dbConfig,
+		clientTLS:  tls,
+		caCerts:    mainflux.Env(envCACerts, defCACerts),
+		clientCert: mainflux.Env(envClientCert, defClientCert),
+		clientKey:  mainflux.Env(envClientKey, defClientKey),
+		httpPort:   mainflux.Env(envPort, defPort),
+		serverCert: mainflux.Env(envServerCert, defServerCert),
+		serverKey:  mainflux.Env(envServerKey, defServerKey),
+		admins:     strings.Split(mainflux.Env(envAdmins, defAdmins), ","),
+		usersURL:   mainflux.Env(envUsersURL, defUsersURL),
+	}
+}
+
+func connectToDB(cfg postgres.Config, logger mflog.Logger) *sqlx.DB {
+	db, err := postgres.Connect(cfg)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to postgres: %s", err))
+		os.Exit(1)
+	}
+	return db
+}
+
+func newService(conn *grpc.ClientConn, db *sqlx.DB, logger mflog.Logger, cfg config) flags.Service {
+	repo := postgres.NewFlagRepository(db)
+	users := usersapi.NewClient(conn)
+
+	svc := flags.New(users, repo, postgres.NewOverrideRepository(db), cfg.admins)
+	svc = api.NewLoggingMiddleware(svc, logger)
+	svc = api.MetricsMiddleware(
+		svc,
+		kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: "flags",
+			Subsystem: "api",
+			Name:      "request_count",
+			Help:      "Number of requests received.",
+		}, []string{"method"}),
+		kitprometheus.NewSummaryFrom(stdprometheus.SummaryOpts{
+			Namespace: "flags",
+			Subsystem: "api",
+			Name:      "request_latency_microseconds",
+			Help:      "Total duration of requests in microseconds.",
+		}, []string{"method"}),
+	)
+	return svc
+}
+
+func connectToUsers(cfg config, logger mflog.Logger) *grpc.ClientConn {
+	var opts []grpc.DialOption
+	if cfg.clientTLS {
+		tpc, err := mtls.ClientCredentials(cfg.caCerts, cfg.clientCert, cfg.clientKey)
+		if err != nil {
+			logger.Error(fmt.Sprintf("Failed to create tls credentials: %s", err))
+			os.Exit(1)
+		}
+		opts = append(opts, grpc.WithTransportCredentials(tpc))
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+		logger.Info("gRPC communication is not encrypted")
+	}
+
+	conn, err := grpc.Dial(cfg.usersURL, opts...)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to users service: %s", err))
+		os.Exit(1)
+	}
+
+	return conn
+}
+
+func startHTTPServer(svc flags.Service, cfg config, logger mflog.Logger, errs chan error) {
+	p := fmt.Sprintf(":%s", cfg.httpPort)
+	if cfg.serverCert != "" || cfg.serverKey != "" {
+		logger.Info(fmt.Sprintf("Flags service started using https on port %s with cert %s key %s",
+			cfg.httpPort, cfg.serverCert, cfg.serverKey))
+		errs <- http.ListenAndServeTLS(p, cfg.serverCert, cfg.serverKey, api.MakeHandler(svc))
+		return
+	}
+	logger.Info(fmt.Sprintf("Flags service started using http on port %s", cfg.httpPort))
+	errs <- http.ListenAndServe(p, api.MakeHandler(svc))
+}
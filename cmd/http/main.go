@@ -15,43 +15,91 @@ import (
 	"os/signal"
 	"strconv"
 	"syscall"
-
-	"google.golang.org/grpc/credentials"
+	"time"
 
 	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	r "github.com/go-redis/redis"
 	"github.com/mainflux/mainflux"
 	adapter "github.com/mainflux/mainflux/http"
 	"github.com/mainflux/mainflux/http/api"
 	"github.com/mainflux/mainflux/http/nats"
 	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/mtls"
+	"github.com/mainflux/mainflux/publish"
+	mfredis "github.com/mainflux/mainflux/redis"
 	thingsapi "github.com/mainflux/mainflux/things/api/grpc"
+	"github.com/mainflux/mainflux/things/jwt"
+	thingsnats "github.com/mainflux/mainflux/things/nats"
+	"github.com/mainflux/mainflux/ws"
+	wsnats "github.com/mainflux/mainflux/ws/nats"
 	broker "github.com/nats-io/go-nats"
 	stdprometheus "github.com/prometheus/client_golang/prometheus"
 	"google.golang.org/grpc"
 )
 
+const revocationsTimeout = 5 * time.Second
+
 const (
-	defClientTLS = "false"
-	defCACerts   = ""
-	defPort      = "8180"
-	defLogLevel  = "error"
-	defNatsURL   = broker.DefaultURL
-	defThingsURL = "localhost:8181"
-	envClientTLS = "MF_HTTP_ADAPTER_CLIENT_TLS"
-	envCACerts   = "MF_HTTP_ADAPTER_CA_CERTS"
-	envPort      = "MF_HTTP_ADAPTER_PORT"
-	envLogLevel  = "MF_HTTP_ADAPTER_LOG_LEVEL"
-	envNatsURL   = "MF_NATS_URL"
-	envThingsURL = "MF_THINGS_URL"
+	defClientTLS      = "false"
+	defCACerts        = ""
+	defClientCert     = ""
+	defClientKey      = ""
+	defPort           = "8180"
+	defLogLevel       = "error"
+	defNatsURL        = broker.DefaultURL
+	defThingsURL      = "localhost:8181"
+	defKeysSecret     = ""
+	defMaxPayloadSize = "1048576"
+	defRateLimit      = "0"
+	defRateBurst      = "10"
+	defCacheURL       = ""
+	defCachePass      = ""
+	defCacheDB        = "0"
+	defAnonymize      = "false"
+	defPollWindow     = "30s"
+	defPollBuffer     = "100"
+	defAckTimeout     = "5s"
+	envClientTLS      = "MF_HTTP_ADAPTER_CLIENT_TLS"
+	envCACerts        = "MF_HTTP_ADAPTER_CA_CERTS"
+	envClientCert     = "MF_HTTP_ADAPTER_CLIENT_CERT"
+	envClientKey      = "MF_HTTP_ADAPTER_CLIENT_KEY"
+	envPort           = "MF_HTTP_ADAPTER_PORT"
+	envLogLevel       = "MF_HTTP_ADAPTER_LOG_LEVEL"
+	envNatsURL        = "MF_NATS_URL"
+	envThingsURL      = "MF_THINGS_URL"
+	envKeysSecret     = "MF_THINGS_KEYS_SECRET"
+	envMaxPayloadSize = "MF_HTTP_ADAPTER_MAX_PAYLOAD_SIZE"
+	envRateLimit      = "MF_HTTP_ADAPTER_RATE_LIMIT"
+	envRateBurst      = "MF_HTTP_ADAPTER_RATE_BURST"
+	envCacheURL       = "MF_HTTP_ADAPTER_CACHE_URL"
+	envCachePass      = "MF_HTTP_ADAPTER_CACHE_PASS"
+	envCacheDB        = "MF_HTTP_ADAPTER_CACHE_DB"
+	envAnonymize      = "MF_HTTP_ADAPTER_ANONYMIZE_ADDR"
+	envPollWindow     = "MF_HTTP_ADAPTER_POLL_WINDOW"
+	envPollBuffer     = "MF_HTTP_ADAPTER_POLL_BUFFER"
+	envAckTimeout     = "MF_HTTP_ADAPTER_ACK_TIMEOUT"
 )
 
 type config struct {
-	thingsURL string
-	natsURL   string
-	logLevel  string
-	port      string
-	clientTLS bool
-	caCerts   string
+	thingsURL      string
+	keysSecret     string
+	natsURL        string
+	logLevel       string
+	port           string
+	clientTLS      bool
+	caCerts        string
+	clientCert     string
+	clientKey      string
+	maxPayloadSize int64
+	rateLimit      float64
+	rateBurst      uint
+	cacheURL       string
+	cachePass      string
+	cacheDB        string
+	anonymize      bool
+	pollWindow     time.Duration
+	pollBuffer     int
+	ackTimeout     time.Duration
 }
 
 func main() {
@@ -74,9 +122,30 @@ func main() {
 	defer conn.Close()
 
 	cc := thingsapi.NewClient(conn)
-	pub := nats.NewMessagePublisher(nc)
+	if cfg.keysSecret != "" {
+		revocations, err := thingsnats.NewRevocationList(nc, revocationsTimeout, logger)
+		if err != nil {
+			logger.Error(fmt.Sprintf("Failed to retrieve revocations: %s", err))
+			os.Exit(1)
+		}
+		cc = jwt.NewThingsClient(cc, jwt.NewKeyProvider(cfg.keysSecret), revocations)
+	}
 
-	svc := adapter.New(pub)
+	pub := nats.NewMessagePublisher(nc, cfg.ackTimeout)
+	cache := connectToCache(cfg, logger)
+
+	svc := adapter.New(
+		pub,
+		wsnats.New(nc, cfg.ackTimeout),
+		publish.Ingress(cfg.anonymize),
+		publish.Validate(cfg.maxPayloadSize),
+		publish.RateLimit(cfg.rateLimit, cfg.rateBurst),
+		publish.ChannelRateLimit(cache),
+		publish.ChannelProtocols(cache),
+		publish.Transform(cache),
+		publish.Delivery(cache),
+		publish.Sequence(cache),
+	)
 	svc = api.LoggingMiddleware(svc, logger)
 	svc = api.MetricsMiddleware(
 		svc,
@@ -94,12 +163,14 @@ func main() {
 		}, []string{"method"}),
 	)
 
+	sessions := ws.NewSessions(cfg.pollWindow, cfg.pollBuffer)
+
 	errs := make(chan error, 2)
 
 	go func() {
 		p := fmt.Sprintf(":%s", cfg.port)
 		logger.Info(fmt.Sprintf("HTTP adapter service started on port %s", cfg.port))
-		errs <- http.ListenAndServe(p, api.MakeHandler(svc, cc))
+		errs <- http.ListenAndServe(p, api.MakeHandler(svc, sessions, cc, cfg.maxPayloadSize))
 	}()
 
 	go func() {
@@ -118,27 +189,87 @@ func loadConfig() config {
 		log.Fatalf("Invalid value passed for %s\n", envClientTLS)
 	}
 
+	maxPayloadSize, err := strconv.ParseInt(mainflux.Env(envMaxPayloadSize, defMaxPayloadSize), 10, 64)
+	if err != nil || maxPayloadSize <= 0 {
+		log.Fatalf("Invalid value passed for %s\n", envMaxPayloadSize)
+	}
+
+	rateLimit, err := strconv.ParseFloat(mainflux.Env(envRateLimit, defRateLimit), 64)
+	if err != nil || rateLimit < 0 {
+		log.Fatalf("Invalid value passed for %s\n", envRateLimit)
+	}
+
+	rateBurst, err := strconv.ParseUint(mainflux.Env(envRateBurst, defRateBurst), 10, 32)
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envRateBurst)
+	}
+
+	anonymize, err := strconv.ParseBool(mainflux.Env(envAnonymize, defAnonymize))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envAnonymize)
+	}
+
+	pollWindow, err := time.ParseDuration(mainflux.Env(envPollWindow, defPollWindow))
+	if err != nil || pollWindow < 0 {
+		log.Fatalf("Invalid value passed for %s\n", envPollWindow)
+	}
+
+	pollBuffer, err := strconv.Atoi(mainflux.Env(envPollBuffer, defPollBuffer))
+	if err != nil || pollBuffer <= 0 {
+		log.Fatalf("Invalid value passed for %s\n", envPollBuffer)
+	}
+
+	ackTimeout, err := time.ParseDuration(mainflux.Env(envAckTimeout, defAckTimeout))
+	if err != nil || ackTimeout <= 0 {
+		log.Fatalf("Invalid value passed for %s\n", envAckTimeout)
+	}
+
 	return config{
-		thingsURL: mainflux.Env(envThingsURL, defThingsURL),
-		natsURL:   mainflux.Env(envNatsURL, defNatsURL),
-		logLevel:  mainflux.Env(envLogLevel, defLogLevel),
-		port:      mainflux.Env(envPort, defPort),
-		clientTLS: tls,
-		caCerts:   mainflux.Env(envCACerts, defCACerts),
+		thingsURL:      mainflux.Env(envThingsURL, defThingsURL),
+		keysSecret:     mainflux.Env(envKeysSecret, defKeysSecret),
+		natsURL:        mainflux.Env(envNatsURL, defNatsURL),
+		logLevel:       mainflux.Env(envLogLevel, defLogLevel),
+		port:           mainflux.Env(envPort, defPort),
+		clientTLS:      tls,
+		caCerts:        mainflux.Env(envCACerts, defCACerts),
+		clientCert:     mainflux.Env(envClientCert, defClientCert),
+		clientKey:      mainflux.Env(envClientKey, defClientKey),
+		maxPayloadSize: maxPayloadSize,
+		rateLimit:      rateLimit,
+		rateBurst:      uint(rateBurst),
+		cacheURL:       mainflux.Env(envCacheURL, defCacheURL),
+		cachePass:      mainflux.Env(envCachePass, defCachePass),
+		cacheDB:        mainflux.Env(envCacheDB, defCacheDB),
+		anonymize:      anonymize,
+		pollWindow:     pollWindow,
+		pollBuffer:     pollBuffer,
+		ackTimeout:     ackTimeout,
 	}
 }
 
+func connectToCache(cfg config, logger logger.Logger) r.UniversalClient {
+	if cfg.cacheURL == "" {
+		return nil
+	}
+
+	client, err := mfredis.Connect(cfg.cacheURL, cfg.cachePass, cfg.cacheDB)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to cache: %s", err))
+		os.Exit(1)
+	}
+
+	return client
+}
+
 func connectToThings(cfg config, logger logger.Logger) *grpc.ClientConn {
 	var opts []grpc.DialOption
 	if cfg.clientTLS {
-		if cfg.caCerts != "" {
-			tpc, err := credentials.NewClientTLSFromFile(cfg.caCerts, "")
-			if err != nil {
-				logger.Error(fmt.Sprintf("Failed to load certs: %s", err))
-				os.Exit(1)
-			}
-			opts = append(opts, grpc.WithTransportCredentials(tpc))
+		tpc, err := mtls.ClientCredentials(cfg.caCerts, cfg.clientCert, cfg.clientKey)
+		if err != nil {
+			logger.Error(fmt.Sprintf("Failed to load certs: %s", err))
+			os.Exit(1)
 		}
+		opts = append(opts, grpc.WithTransportCredentials(tpc))
 	} else {
 		logger.Info("gRPC communication is not encrypted")
 		opts = append(opts, grpc.WithInsecure())
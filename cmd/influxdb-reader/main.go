@@ -8,55 +8,74 @@ import (
 	"os/signal"
 	"strconv"
 	"syscall"
+	"time"
 
 	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
 	influxdata "github.com/influxdata/influxdb/client/v2"
 	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/mtls"
 	"github.com/mainflux/mainflux/readers"
 	"github.com/mainflux/mainflux/readers/api"
+	"github.com/mainflux/mainflux/readers/cache"
+	"github.com/mainflux/mainflux/readers/decompression"
 	"github.com/mainflux/mainflux/readers/influxdb"
 	thingsapi "github.com/mainflux/mainflux/things/api/grpc"
+	"github.com/mainflux/mainflux/things/jwt"
 	stdprometheus "github.com/prometheus/client_golang/prometheus"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
 )
 
 const (
-	defThingsURL = "localhost:8181"
-	defLogLevel  = "error"
-	defPort      = "8180"
-	defDBName    = "mainflux"
-	defDBHost    = "localhost"
-	defDBPort    = "8086"
-	defDBUser    = "mainflux"
-	defDBPass    = "mainflux"
-	defClientTLS = "false"
-	defCACerts   = ""
-
-	envThingsURL = "MF_THINGS_URL"
-	envLogLevel  = "MF_INFLUX_READER_LOG_LEVEL"
-	envPort      = "MF_INFLUX_READER_PORT"
-	envDBName    = "MF_INFLUX_READER_DB_NAME"
-	envDBHost    = "MF_INFLUX_READER_DB_HOST"
-	envDBPort    = "MF_INFLUX_READER_DB_PORT"
-	envDBUser    = "MF_INFLUX_READER_DB_USER"
-	envDBPass    = "MF_INFLUX_READER_DB_PASS"
-	envClientTLS = "MF_INFLUX_READER_CLIENT_TLS"
-	envCACerts   = "MF_INFLUX_READER_CA_CERTS"
+	defThingsURL  = "localhost:8181"
+	defKeysSecret = ""
+	defLogLevel   = "error"
+	defPort       = "8180"
+	defDBName     = "mainflux"
+	defDBHost     = "localhost"
+	defDBPort     = "8086"
+	defDBUser     = "mainflux"
+	defDBPass     = "mainflux"
+	defClientTLS  = "false"
+	defCACerts    = ""
+	defClientCert = ""
+	defClientKey  = ""
+	defCacheTTL   = "5s"
+	defCacheSize  = "1000"
+
+	envThingsURL  = "MF_THINGS_URL"
+	envKeysSecret = "MF_THINGS_KEYS_SECRET"
+	envLogLevel   = "MF_INFLUX_READER_LOG_LEVEL"
+	envPort       = "MF_INFLUX_READER_PORT"
+	envDBName     = "MF_INFLUX_READER_DB_NAME"
+	envDBHost     = "MF_INFLUX_READER_DB_HOST"
+	envDBPort     = "MF_INFLUX_READER_DB_PORT"
+	envDBUser     = "MF_INFLUX_READER_DB_USER"
+	envDBPass     = "MF_INFLUX_READER_DB_PASS"
+	envClientTLS  = "MF_INFLUX_READER_CLIENT_TLS"
+	envCACerts    = "MF_INFLUX_READER_CA_CERTS"
+	envClientCert = "MF_INFLUX_READER_CLIENT_CERT"
+	envClientKey  = "MF_INFLUX_READER_CLIENT_KEY"
+	envCacheTTL   = "MF_INFLUX_READER_CACHE_TTL"
+	envCacheSize  = "MF_INFLUX_READER_CACHE_SIZE"
 )
 
 type config struct {
-	thingsURL string
-	logLevel  string
-	port      string
-	dbName    string
-	dbHost    string
-	dbPort    string
-	dbUser    string
-	dbPass    string
-	clientTLS bool
-	caCerts   string
+	thingsURL  string
+	keysSecret string
+	logLevel   string
+	port       string
+	dbName     string
+	dbHost     string
+	dbPort     string
+	dbUser     string
+	dbPass     string
+	clientTLS  bool
+	caCerts    string
+	clientCert string
+	clientKey  string
+	cacheTTL   time.Duration
+	cacheSize  int
 }
 
 func main() {
@@ -69,6 +88,9 @@ func main() {
 	defer conn.Close()
 
 	tc := thingsapi.NewClient(conn)
+	if cfg.keysSecret != "" {
+		tc = jwt.NewInvitationsClient(tc, jwt.NewKeyProvider(cfg.keysSecret))
+	}
 
 	client, err := influxdata.NewHTTPClient(clientCfg)
 	if err != nil {
@@ -77,7 +99,7 @@ func main() {
 	}
 	defer client.Close()
 
-	repo := newService(client, cfg.dbName, logger)
+	repo := newService(client, cfg, logger)
 
 	errs := make(chan error, 2)
 	go func() {
@@ -98,17 +120,32 @@ func loadConfigs() (config, influxdata.HTTPConfig) {
 		log.Fatalf("Invalid value passed for %s\n", envClientTLS)
 	}
 
+	ttl, err := time.ParseDuration(mainflux.Env(envCacheTTL, defCacheTTL))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envCacheTTL)
+	}
+
+	size, err := strconv.Atoi(mainflux.Env(envCacheSize, defCacheSize))
+	if err != nil || size < 1 {
+		log.Fatalf("Invalid value passed for %s\n", envCacheSize)
+	}
+
 	cfg := config{
-		thingsURL: mainflux.Env(envThingsURL, defThingsURL),
-		logLevel:  mainflux.Env(envLogLevel, defLogLevel),
-		port:      mainflux.Env(envPort, defPort),
-		dbName:    mainflux.Env(envDBName, defDBName),
-		dbHost:    mainflux.Env(envDBHost, defDBHost),
-		dbPort:    mainflux.Env(envDBPort, defDBPort),
-		dbUser:    mainflux.Env(envDBUser, defDBUser),
-		dbPass:    mainflux.Env(envDBPass, defDBPass),
-		clientTLS: tls,
-		caCerts:   mainflux.Env(envCACerts, defCACerts),
+		thingsURL:  mainflux.Env(envThingsURL, defThingsURL),
+		keysSecret: mainflux.Env(envKeysSecret, defKeysSecret),
+		logLevel:   mainflux.Env(envLogLevel, defLogLevel),
+		port:       mainflux.Env(envPort, defPort),
+		dbName:     mainflux.Env(envDBName, defDBName),
+		dbHost:     mainflux.Env(envDBHost, defDBHost),
+		dbPort:     mainflux.Env(envDBPort, defDBPort),
+		dbUser:     mainflux.Env(envDBUser, defDBUser),
+		dbPass:     mainflux.Env(envDBPass, defDBPass),
+		clientTLS:  tls,
+		caCerts:    mainflux.Env(envCACerts, defCACerts),
+		clientCert: mainflux.Env(envClientCert, defClientCert),
+		clientKey:  mainflux.Env(envClientKey, defClientKey),
+		cacheTTL:   ttl,
+		cacheSize:  size,
 	}
 
 	clientCfg := influxdata.HTTPConfig{
@@ -123,14 +160,12 @@ func loadConfigs() (config, influxdata.HTTPConfig) {
 func connectToThings(cfg config, logger logger.Logger) *grpc.ClientConn {
 	var opts []grpc.DialOption
 	if cfg.clientTLS {
-		if cfg.caCerts != "" {
-			tpc, err := credentials.NewClientTLSFromFile(cfg.caCerts, "")
-			if err != nil {
-				logger.Error(fmt.Sprintf("Failed to load certs: %s", err))
-				os.Exit(1)
-			}
-			opts = append(opts, grpc.WithTransportCredentials(tpc))
+		tpc, err := mtls.ClientCredentials(cfg.caCerts, cfg.clientCert, cfg.clientKey)
+		if err != nil {
+			logger.Error(fmt.Sprintf("Failed to load certs: %s", err))
+			os.Exit(1)
 		}
+		opts = append(opts, grpc.WithTransportCredentials(tpc))
 	} else {
 		logger.Info("gRPC communication is not encrypted")
 		opts = append(opts, grpc.WithInsecure())
@@ -144,8 +179,21 @@ func connectToThings(cfg config, logger logger.Logger) *grpc.ClientConn {
 	return conn
 }
 
-func newService(client influxdata.Client, dbName string, logger logger.Logger) readers.MessageRepository {
-	repo := influxdb.New(client, dbName)
+func newService(client influxdata.Client, cfg config, logger logger.Logger) readers.MessageRepository {
+	repo := decompression.New(influxdb.New(client, cfg.dbName))
+	if cfg.cacheTTL > 0 {
+		repo = cache.New(
+			repo,
+			cfg.cacheTTL,
+			cfg.cacheSize,
+			kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+				Namespace: "influxdb",
+				Subsystem: "message_reader",
+				Name:      "cache_count",
+				Help:      "Number of cache hits and misses.",
+			}, []string{"result"}),
+		)
+	}
 	repo = api.LoggingMiddleware(repo, logger)
 	repo = api.MetricsMiddleware(
 		repo,
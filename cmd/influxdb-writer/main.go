@@ -70,6 +70,8 @@ type config struct {
 	dbUser       string
 	dbPass       string
 	channels     map[string]bool
+	sampling     map[string]writers.SamplingRule
+	compression  map[string]string
 }
 
 func main() {
@@ -116,7 +118,7 @@ func main() {
 	counter, latency := makeMetrics()
 	repo = api.LoggingMiddleware(repo, logger)
 	repo = api.MetricsMiddleware(repo, counter, latency)
-	if err := writers.Start(nc, repo, svcName, cfg.channels, logger); err != nil {
+	if err := writers.Start(nc, repo, svcName, cfg.channels, cfg.sampling, cfg.compression, logger); err != nil {
 		logger.Error(fmt.Sprintf("Failed to start InfluxDB writer: %s", err))
 		os.Exit(1)
 	}
@@ -136,6 +138,7 @@ func main() {
 
 func loadConfigs() (config, influxdata.HTTPConfig) {
 	chanCfgPath := mainflux.Env(envChanCfgPath, defChanCfgPath)
+	chans, sampling, compression := loadChansConfig(chanCfgPath)
 	cfg := config{
 		natsURL:      mainflux.Env(envNatsURL, defNatsURL),
 		logLevel:     mainflux.Env(envLogLevel, defLogLevel),
@@ -147,7 +150,9 @@ func loadConfigs() (config, influxdata.HTTPConfig) {
 		dbPort:       mainflux.Env(envDBPort, defDBPort),
 		dbUser:       mainflux.Env(envDBUser, defDBUser),
 		dbPass:       mainflux.Env(envDBPass, defDBPass),
-		channels:     loadChansConfig(chanCfgPath),
+		channels:     chans,
+		sampling:     sampling,
+		compression:  compression,
 	}
 
 	clientCfg := influxdata.HTTPConfig{
@@ -163,11 +168,18 @@ type channels struct {
 	List []string `toml:"filter"`
 }
 
+type sampling struct {
+	Every    uint64 `toml:"every"`
+	Interval string `toml:"interval"`
+}
+
 type chanConfig struct {
-	Channels channels `toml:"channels"`
+	Channels    channels            `toml:"channels"`
+	Sampling    map[string]sampling `toml:"sampling"`
+	Compression map[string]string   `toml:"compression"`
 }
 
-func loadChansConfig(chanConfigPath string) map[string]bool {
+func loadChansConfig(chanConfigPath string) (map[string]bool, map[string]writers.SamplingRule, map[string]string) {
 	data, err := ioutil.ReadFile(chanConfigPath)
 	if err != nil {
 		log.Fatal(err)
@@ -183,7 +195,18 @@ func loadChansConfig(chanConfigPath string) map[string]bool {
 		chans[ch] = true
 	}
 
-	return chans
+	rules := map[string]writers.SamplingRule{}
+	for ch, s := range chanCfg.Sampling {
+		rule := writers.SamplingRule{Every: s.Every}
+		if s.Interval != "" {
+			if rule.Interval, err = time.ParseDuration(s.Interval); err != nil {
+				log.Fatal(err)
+			}
+		}
+		rules[ch] = rule
+	}
+
+	return chans, rules, chanCfg.Compression
 }
 
 func makeMetrics() (*kitprometheus.Counter, *kitprometheus.Summary) {
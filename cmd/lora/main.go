@@ -6,7 +6,6 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
-	"strconv"
 	"syscall"
 
 	mqtt "github.com/eclipse/paho.mqtt.golang"
@@ -17,6 +16,7 @@ import (
 	"github.com/mainflux/mainflux/lora/api"
 	pub "github.com/mainflux/mainflux/lora/nats"
 	mqttBroker "github.com/mainflux/mainflux/lora/paho"
+	mfredis "github.com/mainflux/mainflux/redis"
 
 	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
 	"github.com/mainflux/mainflux/lora/redis"
@@ -177,18 +177,14 @@ func connectToMQTTBroker(loraURL string, logger logger.Logger) mqtt.Client {
 	return client
 }
 
-func connectToRedis(redisURL, redisPass, redisDB string, logger logger.Logger) *r.Client {
-	db, err := strconv.Atoi(redisDB)
+func connectToRedis(redisURL, redisPass, redisDB string, logger logger.Logger) r.UniversalClient {
+	client, err := mfredis.Connect(redisURL, redisPass, redisDB)
 	if err != nil {
 		logger.Error(fmt.Sprintf("Failed to connect to redis: %s", err))
 		os.Exit(1)
 	}
 
-	return r.NewClient(&r.Options{
-		Addr:     redisURL,
-		Password: redisPass,
-		DB:       db,
-	})
+	return client
 }
 
 func subscribeToLoRaBroker(svc lora.Service, mc mqtt.Client, logger logger.Logger) {
@@ -200,7 +196,7 @@ func subscribeToLoRaBroker(svc lora.Service, mc mqtt.Client, logger logger.Logge
 	}
 }
 
-func subscribeToThingsES(svc lora.Service, client *r.Client, consumer string, logger logger.Logger) {
+func subscribeToThingsES(svc lora.Service, client r.UniversalClient, consumer string, logger logger.Logger) {
 	eventStore := redis.NewEventStore(svc, client, consumer, logger)
 	logger.Info("Subscribed to Redis Event Store")
 	if err := eventStore.Subscribe("mainflux.things"); err != nil {
@@ -208,7 +204,7 @@ func subscribeToThingsES(svc lora.Service, client *r.Client, consumer string, lo
 	}
 }
 
-func newRouteMapRepositoy(client *r.Client, prefix string, logger logger.Logger) lora.RouteMapRepository {
+func newRouteMapRepositoy(client r.UniversalClient, prefix string, logger logger.Logger) lora.RouteMapRepository {
 	logger.Info("Connected to Redis Route map")
 	return redis.NewRouteMapRepository(client, prefix)
 }
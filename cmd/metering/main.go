@@ -0,0 +1,346 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	r "github.com/go-redis/redis"
+	"github.com/jmoiron/sqlx"
+	"github.com/mainflux/mainflux"
+	mflog "github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/metering"
+	"github.com/mainflux/mainflux/metering/api"
+	natsconsumer "github.com/mainflux/mainflux/metering/nats"
+	"github.com/mainflux/mainflux/metering/postgres"
+	"github.com/mainflux/mainflux/metering/redis"
+	"github.com/mainflux/mainflux/metering/uuid"
+	"github.com/mainflux/mainflux/metering/webhook"
+	"github.com/mainflux/mainflux/mtls"
+	mfredis "github.com/mainflux/mainflux/redis"
+	usersapi "github.com/mainflux/mainflux/users/api/grpc"
+	broker "github.com/nats-io/go-nats"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+)
+
+const (
+	defLogLevel       = "error"
+	defDBHost         = "localhost"
+	defDBPort         = "5432"
+	defDBUser         = "mainflux"
+	defDBPass         = "mainflux"
+	defDBName         = "metering"
+	defDBSSLMode      = "disable"
+	defDBSSLCert      = ""
+	defDBSSLKey       = ""
+	defDBSSLRootCert  = ""
+	defDBTarget       = "read-write"
+	defDBSyncCommit   = ""
+	defClientTLS      = "false"
+	defCACerts        = ""
+	defClientCert     = ""
+	defClientKey      = ""
+	defPort           = "8240"
+	defServerCert     = ""
+	defServerKey      = ""
+	defWebhookTimeout = "5" // in seconds
+	defFlushInterval  = "10s"
+	defExportKey      = ""
+	defNatsURL        = broker.DefaultURL
+	defUsersURL       = "localhost:8181"
+	defThingsESURL    = "localhost:6379"
+	defThingsESPass   = ""
+	defThingsESDB     = "0"
+	defUsersESURL     = "localhost:6379"
+	defUsersESPass    = ""
+	defUsersESDB      = "0"
+	defInstanceName   = "metering"
+
+	envLogLevel       = "MF_METERING_LOG_LEVEL"
+	envDBHost         = "MF_METERING_DB_HOST"
+	envDBPort         = "MF_METERING_DB_PORT"
+	envDBUser         = "MF_METERING_DB_USER"
+	envDBPass         = "MF_METERING_DB_PASS"
+	envDBName         = "MF_METERING_DB"
+	envDBSSLMode      = "MF_METERING_DB_SSL_MODE"
+	envDBSSLCert      = "MF_METERING_DB_SSL_CERT"
+	envDBSSLKey       = "MF_METERING_DB_SSL_KEY"
+	envDBSSLRootCert  = "MF_METERING_DB_SSL_ROOT_CERT"
+	envDBTarget       = "MF_METERING_DB_TARGET"
+	envDBSyncCommit   = "MF_METERING_DB_SYNC_COMMIT"
+	envClientTLS      = "MF_METERING_CLIENT_TLS"
+	envCACerts        = "MF_METERING_CA_CERTS"
+	envClientCert     = "MF_METERING_CLIENT_CERT"
+	envClientKey      = "MF_METERING_CLIENT_KEY"
+	envPort           = "MF_METERING_PORT"
+	envServerCert     = "MF_METERING_SERVER_CERT"
+	envServerKey      = "MF_METERING_SERVER_KEY"
+	envWebhookTimeout = "MF_METERING_WEBHOOK_TIMEOUT"
+	envFlushInterval  = "MF_METERING_FLUSH_INTERVAL"
+	envExportKey      = "MF_METERING_EXPORT_KEY"
+	envNatsURL        = "MF_NATS_URL"
+	envUsersURL       = "MF_USERS_URL"
+	envThingsESURL    = "MF_THINGS_ES_URL"
+	envThingsESPass   = "MF_THINGS_ES_PASS"
+	envThingsESDB     = "MF_THINGS_ES_DB"
+	envUsersESURL     = "MF_USERS_ES_URL"
+	envUsersESPass    = "MF_USERS_ES_PASS"
+	envUsersESDB      = "MF_USERS_ES_DB"
+	envInstanceName   = "MF_METERING_INSTANCE_NAME"
+)
+
+type config struct {
+	logLevel       string
+	dbConfig       postgres.Config
+	clientTLS      bool
+	caCerts        string
+	clientCert     string
+	clientKey      string
+	httpPort       string
+	serverCert     string
+	serverKey      string
+	webhookTimeout time.Duration
+	flushInterval  time.Duration
+	exportKey      string
+	natsURL        string
+	usersURL       string
+	esThingsURL    string
+	esThingsPass   string
+	esThingsDB     string
+	esUsersURL     string
+	esUsersPass    string
+	esUsersDB      string
+	instanceName   string
+}
+
+func main() {
+	cfg := loadConfig()
+
+	logger, err := mflog.New(os.Stdout, cfg.logLevel)
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+
+	db := connectToDB(cfg.dbConfig, logger)
+	defer db.Close()
+
+	nc, err := broker.Connect(cfg.natsURL)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to NATS: %s", err))
+		os.Exit(1)
+	}
+	defer nc.Close()
+
+	conn := connectToUsers(cfg, logger)
+	defer conn.Close()
+
+	thingsESConn := connectToRedis(cfg.esThingsURL, cfg.esThingsPass, cfg.esThingsDB, logger)
+	defer thingsESConn.Close()
+
+	usersESConn := connectToRedis(cfg.esUsersURL, cfg.esUsersPass, cfg.esUsersDB, logger)
+	defer usersESConn.Close()
+
+	svc := newService(conn, db, logger, cfg)
+	errs := make(chan error, 2)
+
+	go startHTTPServer(svc, cfg, logger, errs)
+	go subscribeToThingsES(svc, thingsESConn, cfg.instanceName, logger)
+	go subscribeToUsersES(svc, usersESConn, cfg.instanceName, logger)
+	go flush(svc, cfg.flushInterval, logger)
+
+	if err := natsconsumer.Subscribe(svc, nc, logger); err != nil {
+		logger.Error(fmt.Sprintf("Failed to subscribe to NATS: %s", err))
+		os.Exit(1)
+	}
+
+	go func() {
+		c := make(chan os.Signal)
+		signal.Notify(c, syscall.SIGINT)
+		errs <- fmt.Errorf("%s", <-c)
+	}()
+
+	err = <-errs
+	if err := svc.Flush(); err != nil {
+		logger.Warn(fmt.Sprintf("Failed to flush usage: %s", err))
+	}
+	logger.Error(fmt.Sprintf("Metering service terminated: %s", err))
+}
+
+func loadConfig() config {
+	tls, err := strconv.ParseBool(mainflux.Env(envClientTLS, defClientTLS))
+	if err != nil {
+		tls = false
+	}
+
+	timeout, err := strconv.ParseInt(mainflux.Env(envWebhookTimeout, defWebhookTimeout), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envWebhookTimeout, err.Error())
+	}
+
+	interval, err := time.ParseDuration(mainflux.Env(envFlushInterval, defFlushInterval))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envFlushInterval, err.Error())
+	}
+
+	dbConfig := postgres.Config{
+		Host:        mainflux.Env(envDBHost, defDBHost),
+		Port:        mainflux.Env(envDBPort, defDBPort),
+		User:        mainflux.Env(envDBUser, defDBUser),
+		Pass:        mainflux.Env(envDBPass, defDBPass),
+		Name:        mainflux.Env(envDBName, defDBName),
+		SSLMode:     mainflux.Env(envDBSSLMode, defDBSSLMode),
+		SSLCert:     mainflux.Env(envDBSSLCert, defDBSSLCert),
+		SSLKey:      mainflux.Env(envDBSSLKey, defDBSSLKey),
+		SSLRootCert: mainflux.Env(envDBSSLRootCert, defDBSSLRootCert),
+		Target:      mainflux.Env(envDBTarget, defDBTarget),
+		SyncCommit:  mainflux.Env(envDBSyncCommit, defDBSyncCommit),
+	}
+
+	return config{
+		logLevel:       mainflux.Env(envLogLevel, defLogLevel),
+		dbConfig:       dbConfig,
+		clientTLS:      tls,
+		caCerts:        mainflux.Env(envCACerts, defCACerts),
+		clientCert:     mainflux.Env(envClientCert, defClientCert),
+		clientKey:      mainflux.Env(envClientKey, defClientKey),
+		httpPort:       mainflux.Env(envPort, defPort),
+		serverCert:     mainflux.Env(envServerCert, defServerCert),
+		serverKey:      mainflux.Env(envServerKey, defServerKey),
+		webhookTimeout: time.Duration(timeout) * time.Second,
+		flushInterval:  interval,
+		exportKey:      mainflux.Env(envExportKey, defExportKey),
+		natsURL:        mainflux.Env(envNatsURL, defNatsURL),
+		usersURL:       mainflux.Env(envUsersURL, defUsersURL),
+		esThingsURL:    mainflux.Env(envThingsESURL, defThingsESURL),
+		esThingsPass:   mainflux.Env(envThingsESPass, defThingsESPass),
+		esThingsDB:     mainflux.Env(envThingsESDB, defThingsESDB),
+		esUsersURL:     mainflux.Env(envUsersESURL, defUsersESURL),
+		esUsersPass:    mainflux.Env(envUsersESPass, defUsersESPass),
+		esUsersDB:      mainflux.Env(envUsersESDB, defUsersESDB),
+		instanceName:   mainflux.Env(envInstanceName, defInstanceName),
+	}
+}
+
+func connectToDB(cfg postgres.Config, logger mflog.Logger) *sqlx.DB {
+	db, err := postgres.Connect(cfg)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to postgres: %s", err))
+		os.Exit(1)
+	}
+	return db
+}
+
+func connectToRedis(redisURL, redisPass, redisDB string, logger mflog.Logger) r.UniversalClient {
+	client, err := mfredis.Connect(redisURL, redisPass, redisDB)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to redis: %s", err))
+		os.Exit(1)
+	}
+
+	return client
+}
+
+func newService(conn *grpc.ClientConn, db *sqlx.DB, logger mflog.Logger, cfg config) metering.Service {
+	records := postgres.NewRecordRepository(db)
+	entities := postgres.NewEntityRepository(db)
+	thresholds := postgres.NewThresholdRepository(db)
+	users := usersapi.NewClient(conn)
+	idp := uuid.New()
+	sender := webhook.New(cfg.webhookTimeout)
+
+	if cfg.exportKey == "" {
+		logger.Info("Export key is not set, records export is disabled")
+	}
+
+	svc := metering.New(users, records, entities, thresholds, idp, sender, cfg.exportKey)
+	svc = api.NewLoggingMiddleware(svc, logger)
+	svc = api.MetricsMiddleware(
+		svc,
+		kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: "metering",
+			Subsystem: "api",
+			Name:      "request_count",
+			Help:      "Number of requests received.",
+		}, []string{"method"}),
+		kitprometheus.NewSummaryFrom(stdprometheus.SummaryOpts{
+			Namespace: "metering",
+			Subsystem: "api",
+			Name:      "request_latency_microseconds",
+			Help:      "Total duration of requests in microseconds.",
+		}, []string{"method"}),
+	)
+	return svc
+}
+
+func connectToUsers(cfg config, logger mflog.Logger) *grpc.ClientConn {
+	var opts []grpc.DialOption
+	if cfg.clientTLS {
+		tpc, err := mtls.ClientCredentials(cfg.caCerts, cfg.clientCert, cfg.clientKey)
+		if err != nil {
+			logger.Error(fmt.Sprintf("Failed to create tls credentials: %s", err))
+			os.Exit(1)
+		}
+		opts = append(opts, grpc.WithTransportCredentials(tpc))
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+		logger.Info("gRPC communication is not encrypted")
+	}
+
+	conn, err := grpc.Dial(cfg.usersURL, opts...)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to users service: %s", err))
+		os.Exit(1)
+	}
+
+	return conn
+}
+
+func startHTTPServer(svc metering.Service, cfg config, logger mflog.Logger, errs chan error) {
+	p := fmt.Sprintf(":%s", cfg.httpPort)
+	if cfg.serverCert != "" || cfg.serverKey != "" {
+		logger.Info(fmt.Sprintf("Metering service started using https on port %s with cert %s key %s",
+			cfg.httpPort, cfg.serverCert, cfg.serverKey))
+		errs <- http.ListenAndServeTLS(p, cfg.serverCert, cfg.serverKey, api.MakeHandler(svc))
+		return
+	}
+	logger.Info(fmt.Sprintf("Metering service started using http on port %s", cfg.httpPort))
+	errs <- http.ListenAndServe(p, api.MakeHandler(svc))
+}
+
+func subscribeToThingsES(svc metering.Service, client r.UniversalClient, consumer string, logger mflog.Logger) {
+	eventStore := redis.NewEventStore(svc, client, consumer, logger)
+	logger.Info("Subscribed to Redis Event Store")
+	if err := eventStore.Subscribe("mainflux.things"); err != nil {
+		logger.Warn(fmt.Sprintf("Metering service failed to subscribe to event sourcing: %s", err))
+	}
+}
+
+func subscribeToUsersES(svc metering.Service, client r.UniversalClient, consumer string, logger mflog.Logger) {
+	eventStore := redis.NewUsersEventStore(svc, client, consumer, logger)
+	logger.Info("Subscribed to Redis Event Store")
+	if err := eventStore.Subscribe("mainflux.users"); err != nil {
+		logger.Warn(fmt.Sprintf("Metering service failed to subscribe to event sourcing: %s", err))
+	}
+}
+
+func flush(svc metering.Service, interval time.Duration, logger mflog.Logger) {
+	for range time.Tick(interval) {
+		if err := svc.Flush(); err != nil {
+			logger.Warn(fmt.Sprintf("Failed to flush usage: %s", err))
+		}
+	}
+}
@@ -20,46 +20,57 @@ import (
 	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
 	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/mtls"
 	"github.com/mainflux/mainflux/readers"
 	"github.com/mainflux/mainflux/readers/api"
+	"github.com/mainflux/mainflux/readers/decompression"
 	"github.com/mainflux/mainflux/readers/mongodb"
 	thingsapi "github.com/mainflux/mainflux/things/api/grpc"
+	"github.com/mainflux/mainflux/things/jwt"
 	stdprometheus "github.com/prometheus/client_golang/prometheus"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
 )
 
 const (
-	defThingsURL = "localhost:8181"
-	defLogLevel  = "error"
-	defPort      = "8180"
-	defDBName    = "mainflux"
-	defDBHost    = "localhost"
-	defDBPort    = "27017"
-	defClientTLS = "false"
-	defCACerts   = ""
-
-	envThingsURL = "MF_THINGS_URL"
-	envLogLevel  = "MF_MONGO_READER_LOG_LEVEL"
-	envPort      = "MF_MONGO_READER_PORT"
-	envDBName    = "MF_MONGO_READER_DB_NAME"
-	envDBHost    = "MF_MONGO_READER_DB_HOST"
-	envDBPort    = "MF_MONGO_READER_DB_PORT"
-	envClientTLS = "MF_MONGO_READER_CLIENT_TLS"
-	envCACerts   = "MF_MONGO_READER_CA_CERTS"
+	defThingsURL  = "localhost:8181"
+	defKeysSecret = ""
+	defLogLevel   = "error"
+	defPort       = "8180"
+	defDBName     = "mainflux"
+	defDBHost     = "localhost"
+	defDBPort     = "27017"
+	defClientTLS  = "false"
+	defCACerts    = ""
+	defClientCert = ""
+	defClientKey  = ""
+
+	envThingsURL  = "MF_THINGS_URL"
+	envKeysSecret = "MF_THINGS_KEYS_SECRET"
+	envLogLevel   = "MF_MONGO_READER_LOG_LEVEL"
+	envPort       = "MF_MONGO_READER_PORT"
+	envDBName     = "MF_MONGO_READER_DB_NAME"
+	envDBHost     = "MF_MONGO_READER_DB_HOST"
+	envDBPort     = "MF_MONGO_READER_DB_PORT"
+	envClientTLS  = "MF_MONGO_READER_CLIENT_TLS"
+	envCACerts    = "MF_MONGO_READER_CA_CERTS"
+	envClientCert = "MF_MONGO_READER_CLIENT_CERT"
+	envClientKey  = "MF_MONGO_READER_CLIENT_KEY"
 )
 
 type config struct {
-	thingsURL string
-	logLevel  string
-	port      string
-	dbName    string
-	dbHost    string
-	dbPort    string
-	clientTLS bool
-	caCerts   string
+	thingsURL  string
+	keysSecret string
+	logLevel   string
+	port       string
+	dbName     string
+	dbHost     string
+	dbPort     string
+	clientTLS  bool
+	caCerts    string
+	clientCert string
+	clientKey  string
 }
 
 func main() {
@@ -73,6 +84,9 @@ func main() {
 	defer conn.Close()
 
 	tc := thingsapi.NewClient(conn)
+	if cfg.keysSecret != "" {
+		tc = jwt.NewInvitationsClient(tc, jwt.NewKeyProvider(cfg.keysSecret))
+	}
 
 	db := connectToMongoDB(cfg.dbHost, cfg.dbPort, cfg.dbName, logger)
 
@@ -98,14 +112,17 @@ func loadConfigs() config {
 	}
 
 	return config{
-		thingsURL: mainflux.Env(envThingsURL, defThingsURL),
-		logLevel:  mainflux.Env(envLogLevel, defLogLevel),
-		port:      mainflux.Env(envPort, defPort),
-		dbName:    mainflux.Env(envDBName, defDBName),
-		dbHost:    mainflux.Env(envDBHost, defDBHost),
-		dbPort:    mainflux.Env(envDBPort, defDBPort),
-		clientTLS: tls,
-		caCerts:   mainflux.Env(envCACerts, defCACerts),
+		thingsURL:  mainflux.Env(envThingsURL, defThingsURL),
+		keysSecret: mainflux.Env(envKeysSecret, defKeysSecret),
+		logLevel:   mainflux.Env(envLogLevel, defLogLevel),
+		port:       mainflux.Env(envPort, defPort),
+		dbName:     mainflux.Env(envDBName, defDBName),
+		dbHost:     mainflux.Env(envDBHost, defDBHost),
+		dbPort:     mainflux.Env(envDBPort, defDBPort),
+		clientTLS:  tls,
+		caCerts:    mainflux.Env(envCACerts, defCACerts),
+		clientCert: mainflux.Env(envClientCert, defClientCert),
+		clientKey:  mainflux.Env(envClientKey, defClientKey),
 	}
 }
 
@@ -123,14 +140,12 @@ func connectToMongoDB(host, port, name string, logger logger.Logger) *mongo.Data
 func connectToThings(cfg config, logger logger.Logger) *grpc.ClientConn {
 	var opts []grpc.DialOption
 	if cfg.clientTLS {
-		if cfg.caCerts != "" {
-			tpc, err := credentials.NewClientTLSFromFile(cfg.caCerts, "")
-			if err != nil {
-				logger.Error(fmt.Sprintf("Failed to load certs: %s", err))
-				os.Exit(1)
-			}
-			opts = append(opts, grpc.WithTransportCredentials(tpc))
+		tpc, err := mtls.ClientCredentials(cfg.caCerts, cfg.clientCert, cfg.clientKey)
+		if err != nil {
+			logger.Error(fmt.Sprintf("Failed to load certs: %s", err))
+			os.Exit(1)
 		}
+		opts = append(opts, grpc.WithTransportCredentials(tpc))
 	} else {
 		logger.Info("gRPC communication is not encrypted")
 		opts = append(opts, grpc.WithInsecure())
@@ -145,7 +160,7 @@ func connectToThings(cfg config, logger logger.Logger) *grpc.ClientConn {
 }
 
 func newService(db *mongo.Database, logger logger.Logger) readers.MessageRepository {
-	repo := mongodb.New(db)
+	repo := decompression.New(mongodb.New(db))
 	repo = api.LoggingMiddleware(repo, logger)
 	repo = api.MetricsMiddleware(
 		repo,
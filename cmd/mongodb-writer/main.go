@@ -16,6 +16,7 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/BurntSushi/toml"
 	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
@@ -51,13 +52,15 @@ const (
 )
 
 type config struct {
-	natsURL  string
-	logLevel string
-	port     string
-	dbName   string
-	dbHost   string
-	dbPort   string
-	channels map[string]bool
+	natsURL     string
+	logLevel    string
+	port        string
+	dbName      string
+	dbHost      string
+	dbPort      string
+	channels    map[string]bool
+	sampling    map[string]writers.SamplingRule
+	compression map[string]string
 }
 
 func main() {
@@ -88,7 +91,7 @@ func main() {
 	counter, latency := makeMetrics()
 	repo = api.LoggingMiddleware(repo, logger)
 	repo = api.MetricsMiddleware(repo, counter, latency)
-	if err := writers.Start(nc, repo, svcName, cfg.channels, logger); err != nil {
+	if err := writers.Start(nc, repo, svcName, cfg.channels, cfg.sampling, cfg.compression, logger); err != nil {
 		logger.Error(fmt.Sprintf("Failed to start MongoDB writer: %s", err))
 		os.Exit(1)
 	}
@@ -108,14 +111,17 @@ func main() {
 
 func loadConfigs() config {
 	chanCfgPath := mainflux.Env(envChanCfgPath, defChanCfgPath)
+	chans, sampling, compression := loadChansConfig(chanCfgPath)
 	return config{
-		natsURL:  mainflux.Env(envNatsURL, defNatsURL),
-		logLevel: mainflux.Env(envLogLevel, defLogLevel),
-		port:     mainflux.Env(envPort, defPort),
-		dbName:   mainflux.Env(envDBName, defDBName),
-		dbHost:   mainflux.Env(envDBHost, defDBHost),
-		dbPort:   mainflux.Env(envDBPort, defDBPort),
-		channels: loadChansConfig(chanCfgPath),
+		natsURL:     mainflux.Env(envNatsURL, defNatsURL),
+		logLevel:    mainflux.Env(envLogLevel, defLogLevel),
+		port:        mainflux.Env(envPort, defPort),
+		dbName:      mainflux.Env(envDBName, defDBName),
+		dbHost:      mainflux.Env(envDBHost, defDBHost),
+		dbPort:      mainflux.Env(envDBPort, defDBPort),
+		channels:    chans,
+		sampling:    sampling,
+		compression: compression,
 	}
 }
 
@@ -123,11 +129,18 @@ type channels struct {
 	List []string `toml:"filter"`
 }
 
+type sampling struct {
+	Every    uint64 `toml:"every"`
+	Interval string `toml:"interval"`
+}
+
 type chanConfig struct {
-	Channels channels `toml:"channels"`
+	Channels    channels            `toml:"channels"`
+	Sampling    map[string]sampling `toml:"sampling"`
+	Compression map[string]string   `toml:"compression"`
 }
 
-func loadChansConfig(chanConfigPath string) map[string]bool {
+func loadChansConfig(chanConfigPath string) (map[string]bool, map[string]writers.SamplingRule, map[string]string) {
 	data, err := ioutil.ReadFile(chanConfigPath)
 	if err != nil {
 		log.Fatal(err)
@@ -143,7 +156,18 @@ func loadChansConfig(chanConfigPath string) map[string]bool {
 		chans[ch] = true
 	}
 
-	return chans
+	rules := map[string]writers.SamplingRule{}
+	for ch, s := range chanCfg.Sampling {
+		rule := writers.SamplingRule{Every: s.Every}
+		if s.Interval != "" {
+			if rule.Interval, err = time.ParseDuration(s.Interval); err != nil {
+				log.Fatal(err)
+			}
+		}
+		rules[ch] = rule
+	}
+
+	return chans, rules, chanCfg.Compression
 }
 
 func makeMetrics() (*kitprometheus.Counter, *kitprometheus.Summary) {
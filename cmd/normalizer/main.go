@@ -13,13 +13,18 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 
 	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/logger"
 	"github.com/mainflux/mainflux/normalizer"
 	"github.com/mainflux/mainflux/normalizer/api"
+	"github.com/mainflux/mainflux/normalizer/memory"
 	"github.com/mainflux/mainflux/normalizer/nats"
+	"github.com/mainflux/mainflux/normalizer/redis"
+	mfredis "github.com/mainflux/mainflux/redis"
+	"github.com/mainflux/mainflux/things"
 	broker "github.com/nats-io/go-nats"
 
 	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
@@ -27,18 +32,33 @@ import (
 )
 
 const (
-	defNatsURL  string = broker.DefaultURL
-	defLogLevel string = "error"
-	defPort     string = "8180"
-	envNatsURL  string = "MF_NATS_URL"
-	envLogLevel string = "MF_NORMALIZER_LOG_LEVEL"
-	envPort     string = "MF_NORMALIZER_PORT"
+	defNatsURL         string = broker.DefaultURL
+	defLogLevel        string = "error"
+	defPort            string = "8180"
+	defDeadLettersSize string = "1000"
+	defCacheURL        string = ""
+	defCachePass       string = ""
+	defCacheDB         string = "0"
+	defMode            string = things.StrictMode
+	envNatsURL         string = "MF_NATS_URL"
+	envLogLevel        string = "MF_NORMALIZER_LOG_LEVEL"
+	envPort            string = "MF_NORMALIZER_PORT"
+	envDeadLettersSize string = "MF_NORMALIZER_DEAD_LETTERS_SIZE"
+	envCacheURL        string = "MF_NORMALIZER_CACHE_URL"
+	envCachePass       string = "MF_NORMALIZER_CACHE_PASS"
+	envCacheDB         string = "MF_NORMALIZER_CACHE_DB"
+	envMode            string = "MF_NORMALIZER_MODE"
 )
 
 type config struct {
-	NatsURL  string
-	LogLevel string
-	Port     string
+	NatsURL         string
+	LogLevel        string
+	Port            string
+	DeadLettersSize int
+	CacheURL        string
+	CachePass       string
+	CacheDB         string
+	Mode            string
 }
 
 func main() {
@@ -55,7 +75,8 @@ func main() {
 	}
 	defer nc.Close()
 
-	svc := normalizer.New()
+	deadLetters := memory.NewDeadLetterRepository(cfg.DeadLettersSize)
+	svc := normalizer.New(deadLetters, nc, newValidationCache(cfg, logger), cfg.Mode)
 	svc = api.LoggingMiddleware(svc, logger)
 	svc = api.MetricsMiddleware(
 		svc,
@@ -78,7 +99,7 @@ func main() {
 	go func() {
 		p := fmt.Sprintf(":%s", cfg.Port)
 		logger.Info(fmt.Sprintf("Normalizer service started, exposed port %s", cfg.Port))
-		errs <- http.ListenAndServe(p, api.MakeHandler())
+		errs <- http.ListenAndServe(p, api.MakeHandler(svc))
 	}()
 
 	go func() {
@@ -94,9 +115,38 @@ func main() {
 }
 
 func loadConfig() config {
+	size, err := strconv.Atoi(mainflux.Env(envDeadLettersSize, defDeadLettersSize))
+	if err != nil || size <= 0 {
+		log.Fatalf("Invalid value passed for %s\n", envDeadLettersSize)
+	}
+
+	mode := mainflux.Env(envMode, defMode)
+	if mode != things.StrictMode && mode != things.LenientMode {
+		log.Fatalf("Invalid value passed for %s\n", envMode)
+	}
+
 	return config{
-		NatsURL:  mainflux.Env(envNatsURL, defNatsURL),
-		LogLevel: mainflux.Env(envLogLevel, defLogLevel),
-		Port:     mainflux.Env(envPort, defPort),
+		NatsURL:         mainflux.Env(envNatsURL, defNatsURL),
+		LogLevel:        mainflux.Env(envLogLevel, defLogLevel),
+		Port:            mainflux.Env(envPort, defPort),
+		DeadLettersSize: size,
+		CacheURL:        mainflux.Env(envCacheURL, defCacheURL),
+		CachePass:       mainflux.Env(envCachePass, defCachePass),
+		CacheDB:         mainflux.Env(envCacheDB, defCacheDB),
+		Mode:            mode,
+	}
+}
+
+func newValidationCache(cfg config, logger logger.Logger) normalizer.ValidationCache {
+	if cfg.CacheURL == "" {
+		return nil
+	}
+
+	client, err := mfredis.Connect(cfg.CacheURL, cfg.CachePass, cfg.CacheDB)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to cache: %s", err))
+		os.Exit(1)
 	}
+
+	return redis.NewValidationCache(client)
 }
@@ -0,0 +1,347 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	r "github.com/go-redis/redis"
+	"github.com/jmoiron/sqlx"
+	"github.com/mainflux/mainflux"
+	mflog "github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/mtls"
+	"github.com/mainflux/mainflux/notifier"
+	"github.com/mainflux/mainflux/notifier/api"
+	"github.com/mainflux/mainflux/notifier/postgres"
+	"github.com/mainflux/mainflux/notifier/redis"
+	"github.com/mainflux/mainflux/notifier/smtp"
+	"github.com/mainflux/mainflux/notifier/uuid"
+	"github.com/mainflux/mainflux/notifier/webhook"
+	mfredis "github.com/mainflux/mainflux/redis"
+	usersapi "github.com/mainflux/mainflux/users/api/grpc"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+)
+
+const (
+	defLogLevel       = "error"
+	defDBHost         = "localhost"
+	defDBPort         = "5432"
+	defDBUser         = "mainflux"
+	defDBPass         = "mainflux"
+	defDBName         = "notifier"
+	defDBSSLMode      = "disable"
+	defDBSSLCert      = ""
+	defDBSSLKey       = ""
+	defDBSSLRootCert  = ""
+	defDBTarget       = "read-write"
+	defDBSyncCommit   = ""
+	defClientTLS      = "false"
+	defCACerts        = ""
+	defClientCert     = ""
+	defClientKey      = ""
+	defPort           = "8210"
+	defServerCert     = ""
+	defServerKey      = ""
+	defSMTPHost       = ""
+	defSMTPPort       = "25"
+	defSMTPUser       = ""
+	defSMTPPass       = ""
+	defSMTPFrom       = "notifier@mainflux.com"
+	defWebhookTimeout = "5"  // in seconds
+	defRetryInterval  = "10" // in seconds
+	defUsersURL       = "localhost:8181"
+	defThingsESURL    = "localhost:6379"
+	defThingsESPass   = ""
+	defThingsESDB     = "0"
+	defUsersESURL     = "localhost:6379"
+	defUsersESPass    = ""
+	defUsersESDB      = "0"
+	defInstanceName   = "notifier"
+
+	envLogLevel       = "MF_NOTIFIER_LOG_LEVEL"
+	envDBHost         = "MF_NOTIFIER_DB_HOST"
+	envDBPort         = "MF_NOTIFIER_DB_PORT"
+	envDBUser         = "MF_NOTIFIER_DB_USER"
+	envDBPass         = "MF_NOTIFIER_DB_PASS"
+	envDBName         = "MF_NOTIFIER_DB"
+	envDBSSLMode      = "MF_NOTIFIER_DB_SSL_MODE"
+	envDBSSLCert      = "MF_NOTIFIER_DB_SSL_CERT"
+	envDBSSLKey       = "MF_NOTIFIER_DB_SSL_KEY"
+	envDBSSLRootCert  = "MF_NOTIFIER_DB_SSL_ROOT_CERT"
+	envDBTarget       = "MF_NOTIFIER_DB_TARGET"
+	envDBSyncCommit   = "MF_NOTIFIER_DB_SYNC_COMMIT"
+	envClientTLS      = "MF_NOTIFIER_CLIENT_TLS"
+	envCACerts        = "MF_NOTIFIER_CA_CERTS"
+	envClientCert     = "MF_NOTIFIER_CLIENT_CERT"
+	envClientKey      = "MF_NOTIFIER_CLIENT_KEY"
+	envPort           = "MF_NOTIFIER_PORT"
+	envServerCert     = "MF_NOTIFIER_SERVER_CERT"
+	envServerKey      = "MF_NOTIFIER_SERVER_KEY"
+	envSMTPHost       = "MF_NOTIFIER_SMTP_HOST"
+	envSMTPPort       = "MF_NOTIFIER_SMTP_PORT"
+	envSMTPUser       = "MF_NOTIFIER_SMTP_USER"
+	envSMTPPass       = "MF_NOTIFIER_SMTP_PASS"
+	envSMTPFrom       = "MF_NOTIFIER_SMTP_FROM"
+	envWebhookTimeout = "MF_NOTIFIER_WEBHOOK_TIMEOUT"
+	envRetryInterval  = "MF_NOTIFIER_RETRY_INTERVAL"
+	envUsersURL       = "MF_USERS_URL"
+	envThingsESURL    = "MF_THINGS_ES_URL"
+	envThingsESPass   = "MF_THINGS_ES_PASS"
+	envThingsESDB     = "MF_THINGS_ES_DB"
+	envUsersESURL     = "MF_USERS_ES_URL"
+	envUsersESPass    = "MF_USERS_ES_PASS"
+	envUsersESDB      = "MF_USERS_ES_DB"
+	envInstanceName   = "MF_NOTIFIER_INSTANCE_NAME"
+)
+
+type config struct {
+	logLevel       string
+	dbConfig       postgres.Config
+	clientTLS      bool
+	caCerts        string
+	clientCert     string
+	clientKey      string
+	httpPort       string
+	serverCert     string
+	serverKey      string
+	smtpConfig     smtp.Config
+	webhookTimeout time.Duration
+	retryInterval  time.Duration
+	usersURL       string
+	esThingsURL    string
+	esThingsPass   string
+	esThingsDB     string
+	esUsersURL     string
+	esUsersPass    string
+	esUsersDB      string
+	instanceName   string
+}
+
+func main() {
+	cfg := loadConfig()
+
+	logger, err := mflog.New(os.Stdout, cfg.logLevel)
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+
+	db := connectToDB(cfg.dbConfig, logger)
+	defer db.Close()
+
+	conn := connectToUsers(cfg, logger)
+	defer conn.Close()
+
+	thingsESConn := connectToRedis(cfg.esThingsURL, cfg.esThingsPass, cfg.esThingsDB, logger)
+	defer thingsESConn.Close()
+
+	usersESConn := connectToRedis(cfg.esUsersURL, cfg.esUsersPass, cfg.esUsersDB, logger)
+	defer usersESConn.Close()
+
+	svc := newService(conn, db, logger, cfg)
+	errs := make(chan error, 2)
+
+	go startHTTPServer(svc, cfg, logger, errs)
+	go subscribeToThingsES(svc, thingsESConn, cfg.instanceName, logger)
+	go subscribeToUsersES(svc, usersESConn, cfg.instanceName, logger)
+	go retryDeliveries(svc, cfg.retryInterval, logger)
+
+	go func() {
+		c := make(chan os.Signal)
+		signal.Notify(c, syscall.SIGINT)
+		errs <- fmt.Errorf("%s", <-c)
+	}()
+
+	err = <-errs
+	logger.Error(fmt.Sprintf("Notifier service terminated: %s", err))
+}
+
+func loadConfig() config {
+	tls, err := strconv.ParseBool(mainflux.Env(envClientTLS, defClientTLS))
+	if err != nil {
+		tls = false
+	}
+
+	timeout, err := strconv.ParseInt(mainflux.Env(envWebhookTimeout, defWebhookTimeout), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envWebhookTimeout, err.Error())
+	}
+
+	retryInterval, err := strconv.ParseInt(mainflux.Env(envRetryInterval, defRetryInterval), 10, 64)
+	if err != nil || retryInterval <= 0 {
+		log.Fatalf("Invalid %s value: %s", envRetryInterval, mainflux.Env(envRetryInterval, defRetryInterval))
+	}
+
+	dbConfig := postgres.Config{
+		Host:        mainflux.Env(envDBHost, defDBHost),
+		Port:        mainflux.Env(envDBPort, defDBPort),
+		User:        mainflux.Env(envDBUser, defDBUser),
+		Pass:        mainflux.Env(envDBPass, defDBPass),
+		Name:        mainflux.Env(envDBName, defDBName),
+		SSLMode:     mainflux.Env(envDBSSLMode, defDBSSLMode),
+		SSLCert:     mainflux.Env(envDBSSLCert, defDBSSLCert),
+		SSLKey:      mainflux.Env(envDBSSLKey, defDBSSLKey),
+		SSLRootCert: mainflux.Env(envDBSSLRootCert, defDBSSLRootCert),
+		Target:      mainflux.Env(envDBTarget, defDBTarget),
+		SyncCommit:  mainflux.Env(envDBSyncCommit, defDBSyncCommit),
+	}
+
+	smtpConfig := smtp.Config{
+		Host:     mainflux.Env(envSMTPHost, defSMTPHost),
+		Port:     mainflux.Env(envSMTPPort, defSMTPPort),
+		Username: mainflux.Env(envSMTPUser, defSMTPUser),
+		Password: mainflux.Env(envSMTPPass, defSMTPPass),
+		From:     mainflux.Env(envSMTPFrom, defSMTPFrom),
+	}
+
+	return config{
+		logLevel:       mainflux.Env(envLogLevel, defLogLevel),
+		dbConfig:       dbConfig,
+		clientTLS:      tls,
+		caCerts:        mainflux.Env(envCACerts, defCACerts),
+		clientCert:     mainflux.Env(envClientCert, defClientCert),
+		clientKey:      mainflux.Env(envClientKey, defClientKey),
+		httpPort:       mainflux.Env(envPort, defPort),
+		serverCert:     mainflux.Env(envServerCert, defServerCert),
+		serverKey:      mainflux.Env(envServerKey, defServerKey),
+		smtpConfig:     smtpConfig,
+		webhookTimeout: time.Duration(timeout) * time.Second,
+		retryInterval:  time.Duration(retryInterval) * time.Second,
+		usersURL:       mainflux.Env(envUsersURL, defUsersURL),
+		esThingsURL:    mainflux.Env(envThingsESURL, defThingsESURL),
+		esThingsPass:   mainflux.Env(envThingsESPass, defThingsESPass),
+		esThingsDB:     mainflux.Env(envThingsESDB, defThingsESDB),
+		esUsersURL:     mainflux.Env(envUsersESURL, defUsersESURL),
+		esUsersPass:    mainflux.Env(envUsersESPass, defUsersESPass),
+		esUsersDB:      mainflux.Env(envUsersESDB, defUsersESDB),
+		instanceName:   mainflux.Env(envInstanceName, defInstanceName),
+	}
+}
+
+func connectToDB(cfg postgres.Config, logger mflog.Logger) *sqlx.DB {
+	db, err := postgres.Connect(cfg)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to postgres: %s", err))
+		os.Exit(1)
+	}
+	return db
+}
+
+func connectToRedis(redisURL, redisPass, redisDB string, logger mflog.Logger) r.UniversalClient {
+	client, err := mfredis.Connect(redisURL, redisPass, redisDB)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to redis: %s", err))
+		os.Exit(1)
+	}
+
+	return client
+}
+
+func newService(conn *grpc.ClientConn, db *sqlx.DB, logger mflog.Logger, cfg config) notifier.Service {
+	subs := postgres.NewSubscriptionRepository(db)
+	deliveries := postgres.NewDeliveryRepository(db)
+	users := usersapi.NewClient(conn)
+	idp := uuid.New()
+
+	senders := map[string]notifier.Sender{
+		notifier.Webhook: webhook.New(cfg.webhookTimeout),
+	}
+	if cfg.smtpConfig.Host != "" {
+		senders[notifier.Email] = smtp.New(cfg.smtpConfig)
+	} else {
+		logger.Info("SMTP host is not set, email notifications are disabled")
+	}
+
+	svc := notifier.New(users, subs, deliveries, idp, senders)
+	svc = api.NewLoggingMiddleware(svc, logger)
+	svc = api.MetricsMiddleware(
+		svc,
+		kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: "notifier",
+			Subsystem: "api",
+			Name:      "request_count",
+			Help:      "Number of requests received.",
+		}, []string{"method"}),
+		kitprometheus.NewSummaryFrom(stdprometheus.SummaryOpts{
+			Namespace: "notifier",
+			Subsystem: "api",
+			Name:      "request_latency_microseconds",
+			Help:      "Total duration of requests in microseconds.",
+		}, []string{"method"}),
+	)
+	return svc
+}
+
+func connectToUsers(cfg config, logger mflog.Logger) *grpc.ClientConn {
+	var opts []grpc.DialOption
+	if cfg.clientTLS {
+		tpc, err := mtls.ClientCredentials(cfg.caCerts, cfg.clientCert, cfg.clientKey)
+		if err != nil {
+			logger.Error(fmt.Sprintf("Failed to create tls credentials: %s", err))
+			os.Exit(1)
+		}
+		opts = append(opts, grpc.WithTransportCredentials(tpc))
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+		logger.Info("gRPC communication is not encrypted")
+	}
+
+	conn, err := grpc.Dial(cfg.usersURL, opts...)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to users service: %s", err))
+		os.Exit(1)
+	}
+
+	return conn
+}
+
+func startHTTPServer(svc notifier.Service, cfg config, logger mflog.Logger, errs chan error) {
+	p := fmt.Sprintf(":%s", cfg.httpPort)
+	if cfg.serverCert != "" || cfg.serverKey != "" {
+		logger.Info(fmt.Sprintf("Notifier service started using https on port %s with cert %s key %s",
+			cfg.httpPort, cfg.serverCert, cfg.serverKey))
+		errs <- http.ListenAndServeTLS(p, cfg.serverCert, cfg.serverKey, api.MakeHandler(svc))
+		return
+	}
+	logger.Info(fmt.Sprintf("Notifier service started using http on port %s", cfg.httpPort))
+	errs <- http.ListenAndServe(p, api.MakeHandler(svc))
+}
+
+func subscribeToThingsES(svc notifier.Service, client r.UniversalClient, consumer string, logger mflog.Logger) {
+	eventStore := redis.NewEventStore(svc, client, consumer, logger)
+	logger.Info("Subscribed to Redis Event Store")
+	if err := eventStore.Subscribe("mainflux.things"); err != nil {
+		logger.Warn(fmt.Sprintf("Notifier service failed to subscribe to event sourcing: %s", err))
+	}
+}
+
+func subscribeToUsersES(svc notifier.Service, client r.UniversalClient, consumer string, logger mflog.Logger) {
+	eventStore := redis.NewUsersEventStore(svc, client, consumer, logger)
+	logger.Info("Subscribed to Redis Event Store")
+	if err := eventStore.Subscribe("mainflux.users"); err != nil {
+		logger.Warn(fmt.Sprintf("Notifier service failed to subscribe to event sourcing: %s", err))
+	}
+}
+
+// retryDeliveries periodically attempts the failed deliveries that are due
+// once again.
+func retryDeliveries(svc notifier.Service, interval time.Duration, logger mflog.Logger) {
+	for now := range time.Tick(interval) {
+		if err := svc.RetryDeliveries(now); err != nil {
+			logger.Warn(fmt.Sprintf("Failed to retry deliveries: %s", err))
+		}
+	}
+}
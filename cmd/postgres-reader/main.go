@@ -13,19 +13,22 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 
 	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
 	"github.com/jmoiron/sqlx"
 	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/mtls"
 	"github.com/mainflux/mainflux/readers"
 	"github.com/mainflux/mainflux/readers/api"
+	"github.com/mainflux/mainflux/readers/decompression"
 	"github.com/mainflux/mainflux/readers/postgres"
 	thingsapi "github.com/mainflux/mainflux/things/api/grpc"
+	"github.com/mainflux/mainflux/things/jwt"
 	stdprometheus "github.com/prometheus/client_golang/prometheus"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
 )
 
 const (
@@ -33,10 +36,13 @@ const (
 	sep     = ","
 
 	defThingsURL     = "localhost:8183"
+	defKeysSecret    = ""
 	defLogLevel      = "debug"
 	defPort          = "9204"
 	defClientTLS     = "false"
 	defCACerts       = ""
+	defClientCert    = ""
+	defClientKey     = ""
 	defDBHost        = "localhost"
 	defDBPort        = "5432"
 	defDBUser        = "mainflux"
@@ -46,12 +52,17 @@ const (
 	defDBSSLCert     = ""
 	defDBSSLKey      = ""
 	defDBSSLRootCert = ""
+	defDBTarget      = "read-write"
+	defDBSyncCommit  = ""
 
 	envThingsURL     = "MF_THINGS_URL"
+	envKeysSecret    = "MF_THINGS_KEYS_SECRET"
 	envLogLevel      = "MF_POSTGRES_READER_LOG_LEVEL"
 	envPort          = "MF_POSTGRES_READER_PORT"
 	envClientTLS     = "MF_POSTGRES_READER_CLIENT_TLS"
 	envCACerts       = "MF_POSTGRES_READER_CA_CERTS"
+	envClientCert    = "MF_POSTGRES_READER_CLIENT_CERT"
+	envClientKey     = "MF_POSTGRES_READER_CLIENT_KEY"
 	envDBHost        = "MF_POSTGRES_READER_DB_HOST"
 	envDBPort        = "MF_POSTGRES_READER_DB_PORT"
 	envDBUser        = "MF_POSTGRES_READER_DB_USER"
@@ -61,15 +72,20 @@ const (
 	envDBSSLCert     = "MF_POSTGRES_READER_DB_SSL_CERT"
 	envDBSSLKey      = "MF_POSTGRES_READER_DB_SSL_KEY"
 	envDBSSLRootCert = "MF_POSTGRES_READER_DB_SSL_ROOT_CERT"
+	envDBTarget      = "MF_POSTGRES_READER_DB_TARGET"
+	envDBSyncCommit  = "MF_POSTGRES_READER_DB_SYNC_COMMIT"
 )
 
 type config struct {
-	thingsURL string
-	logLevel  string
-	port      string
-	clientTLS bool
-	caCerts   string
-	dbConfig  postgres.Config
+	thingsURL  string
+	keysSecret string
+	logLevel   string
+	port       string
+	clientTLS  bool
+	caCerts    string
+	clientCert string
+	clientKey  string
+	dbConfig   postgres.Config
 }
 
 func main() {
@@ -84,6 +100,9 @@ func main() {
 	defer conn.Close()
 
 	tc := thingsapi.NewClient(conn)
+	if cfg.keysSecret != "" {
+		tc = jwt.NewInvitationsClient(tc, jwt.NewKeyProvider(cfg.keysSecret))
+	}
 
 	db := connectToDB(cfg.dbConfig, logger)
 	defer db.Close()
@@ -115,13 +134,25 @@ func loadConfig() config {
 		SSLCert:     mainflux.Env(envDBSSLCert, defDBSSLCert),
 		SSLKey:      mainflux.Env(envDBSSLKey, defDBSSLKey),
 		SSLRootCert: mainflux.Env(envDBSSLRootCert, defDBSSLRootCert),
+		Target:      mainflux.Env(envDBTarget, defDBTarget),
+		SyncCommit:  mainflux.Env(envDBSyncCommit, defDBSyncCommit),
+	}
+
+	tls, err := strconv.ParseBool(mainflux.Env(envClientTLS, defClientTLS))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envClientTLS)
 	}
 
 	return config{
-		thingsURL: mainflux.Env(envThingsURL, defThingsURL),
-		logLevel:  mainflux.Env(envLogLevel, defLogLevel),
-		port:      mainflux.Env(envPort, defPort),
-		dbConfig:  dbConfig,
+		thingsURL:  mainflux.Env(envThingsURL, defThingsURL),
+		keysSecret: mainflux.Env(envKeysSecret, defKeysSecret),
+		logLevel:   mainflux.Env(envLogLevel, defLogLevel),
+		port:       mainflux.Env(envPort, defPort),
+		clientTLS:  tls,
+		caCerts:    mainflux.Env(envCACerts, defCACerts),
+		clientCert: mainflux.Env(envClientCert, defClientCert),
+		clientKey:  mainflux.Env(envClientKey, defClientKey),
+		dbConfig:   dbConfig,
 	}
 }
 
@@ -137,14 +168,12 @@ func connectToDB(dbConfig postgres.Config, logger logger.Logger) *sqlx.DB {
 func connectToThings(cfg config, logger logger.Logger) *grpc.ClientConn {
 	var opts []grpc.DialOption
 	if cfg.clientTLS {
-		if cfg.caCerts != "" {
-			tpc, err := credentials.NewClientTLSFromFile(cfg.caCerts, "")
-			if err != nil {
-				logger.Error(fmt.Sprintf("Failed to load certs: %s", err))
-				os.Exit(1)
-			}
-			opts = append(opts, grpc.WithTransportCredentials(tpc))
+		tpc, err := mtls.ClientCredentials(cfg.caCerts, cfg.clientCert, cfg.clientKey)
+		if err != nil {
+			logger.Error(fmt.Sprintf("Failed to load certs: %s", err))
+			os.Exit(1)
 		}
+		opts = append(opts, grpc.WithTransportCredentials(tpc))
 	} else {
 		logger.Info("gRPC communication is not encrypted")
 		opts = append(opts, grpc.WithInsecure())
@@ -159,7 +188,7 @@ func connectToThings(cfg config, logger logger.Logger) *grpc.ClientConn {
 }
 
 func newService(db *sqlx.DB, logger logger.Logger) readers.MessageRepository {
-	svc := postgres.New(db)
+	svc := decompression.New(postgres.New(db))
 	svc = api.LoggingMiddleware(svc, logger)
 	svc = api.MetricsMiddleware(
 		svc,
@@ -15,6 +15,7 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/BurntSushi/toml"
 	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
@@ -44,6 +45,8 @@ const (
 	defDBSSLCert     = ""
 	defDBSSLKey      = ""
 	defDBSSLRootCert = ""
+	defDBTarget      = "read-write"
+	defDBSyncCommit  = ""
 	defChanCfgPath   = "/config/channels.toml"
 
 	envNatsURL       = "MF_NATS_URL"
@@ -58,15 +61,19 @@ const (
 	envDBSSLCert     = "MF_POSTGRES_WRITER_DB_SSL_CERT"
 	envDBSSLKey      = "MF_POSTGRES_WRITER_DB_SSL_KEY"
 	envDBSSLRootCert = "MF_POSTGRES_WRITER_DB_SSL_ROOT_CERT"
+	envDBTarget      = "MF_POSTGRES_WRITER_DB_TARGET"
+	envDBSyncCommit  = "MF_POSTGRES_WRITER_DB_SYNC_COMMIT"
 	envChanCfgPath   = "MF_POSTGRES_WRITER_CHANNELS_CONFIG"
 )
 
 type config struct {
-	natsURL  string
-	logLevel string
-	port     string
-	dbConfig postgres.Config
-	channels map[string]bool
+	natsURL     string
+	logLevel    string
+	port        string
+	dbConfig    postgres.Config
+	channels    map[string]bool
+	sampling    map[string]writers.SamplingRule
+	compression map[string]string
 }
 
 func main() {
@@ -84,7 +91,7 @@ func main() {
 	defer db.Close()
 
 	repo := newService(db, logger)
-	if err = writers.Start(nc, repo, svcName, cfg.channels, logger); err != nil {
+	if err = writers.Start(nc, repo, svcName, cfg.channels, cfg.sampling, cfg.compression, logger); err != nil {
 		logger.Error(fmt.Sprintf("Failed to create Postgres writer: %s", err))
 	}
 
@@ -104,6 +111,7 @@ func main() {
 
 func loadConfig() config {
 	chanCfgPath := mainflux.Env(envChanCfgPath, defChanCfgPath)
+	chans, sampling, compression := loadChansConfig(chanCfgPath)
 	dbConfig := postgres.Config{
 		Host:        mainflux.Env(envDBHost, defDBHost),
 		Port:        mainflux.Env(envDBPort, defDBPort),
@@ -114,14 +122,18 @@ func loadConfig() config {
 		SSLCert:     mainflux.Env(envDBSSLCert, defDBSSLCert),
 		SSLKey:      mainflux.Env(envDBSSLKey, defDBSSLKey),
 		SSLRootCert: mainflux.Env(envDBSSLRootCert, defDBSSLRootCert),
+		Target:      mainflux.Env(envDBTarget, defDBTarget),
+		SyncCommit:  mainflux.Env(envDBSyncCommit, defDBSyncCommit),
 	}
 
 	return config{
-		natsURL:  mainflux.Env(envNatsURL, defNatsURL),
-		logLevel: mainflux.Env(envLogLevel, defLogLevel),
-		port:     mainflux.Env(envPort, defPort),
-		dbConfig: dbConfig,
-		channels: loadChansConfig(chanCfgPath),
+		natsURL:     mainflux.Env(envNatsURL, defNatsURL),
+		logLevel:    mainflux.Env(envLogLevel, defLogLevel),
+		port:        mainflux.Env(envPort, defPort),
+		dbConfig:    dbConfig,
+		channels:    chans,
+		sampling:    sampling,
+		compression: compression,
 	}
 }
 
@@ -129,11 +141,18 @@ type channels struct {
 	List []string `toml:"filter"`
 }
 
+type sampling struct {
+	Every    uint64 `toml:"every"`
+	Interval string `toml:"interval"`
+}
+
 type chanConfig struct {
-	Channels channels `toml:"channels"`
+	Channels    channels            `toml:"channels"`
+	Sampling    map[string]sampling `toml:"sampling"`
+	Compression map[string]string   `toml:"compression"`
 }
 
-func loadChansConfig(chanConfigPath string) map[string]bool {
+func loadChansConfig(chanConfigPath string) (map[string]bool, map[string]writers.SamplingRule, map[string]string) {
 	data, err := ioutil.ReadFile(chanConfigPath)
 	if err != nil {
 		log.Fatal(err)
@@ -149,7 +168,18 @@ func loadChansConfig(chanConfigPath string) map[string]bool {
 		chans[ch] = true
 	}
 
-	return chans
+	rules := map[string]writers.SamplingRule{}
+	for ch, s := range chanCfg.Sampling {
+		rule := writers.SamplingRule{Every: s.Every}
+		if s.Interval != "" {
+			if rule.Interval, err = time.ParseDuration(s.Interval); err != nil {
+				log.Fatal(err)
+			}
+		}
+		rules[ch] = rule
+	}
+
+	return chans, rules, chanCfg.Compression
 }
 
 func connectToNATS(url string, logger logger.Logger) *nats.Conn {
@@ -0,0 +1,300 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	"github.com/jmoiron/sqlx"
+	"github.com/mainflux/mainflux"
+	mflog "github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/mtls"
+	"github.com/mainflux/mainflux/reports"
+	"github.com/mainflux/mainflux/reports/api"
+	"github.com/mainflux/mainflux/reports/postgres"
+	"github.com/mainflux/mainflux/reports/reader"
+	"github.com/mainflux/mainflux/reports/smtp"
+	"github.com/mainflux/mainflux/reports/uuid"
+	"github.com/mainflux/mainflux/reports/webhook"
+	thingsapi "github.com/mainflux/mainflux/things/api/grpc"
+	usersapi "github.com/mainflux/mainflux/users/api/grpc"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+)
+
+const (
+	defLogLevel         = "error"
+	defDBHost           = "localhost"
+	defDBPort           = "5432"
+	defDBUser           = "mainflux"
+	defDBPass           = "mainflux"
+	defDBName           = "reports"
+	defDBSSLMode        = "disable"
+	defDBSSLCert        = ""
+	defDBSSLKey         = ""
+	defDBSSLRootCert    = ""
+	defDBTarget         = "read-write"
+	defDBSyncCommit     = ""
+	defClientTLS        = "false"
+	defCACerts          = ""
+	defClientCert       = ""
+	defClientKey        = ""
+	defPort             = "8230"
+	defServerCert       = ""
+	defServerKey        = ""
+	defSMTPHost         = ""
+	defSMTPPort         = "25"
+	defSMTPUser         = ""
+	defSMTPPass         = ""
+	defSMTPFrom         = "reports@mainflux.com"
+	defWebhookTimeout   = "5" // in seconds
+	defReaderURL        = "http://localhost:8180"
+	defReaderTimeout    = "30" // in seconds
+	defScheduleInterval = "60" // in seconds
+	defUsersURL         = "localhost:8181"
+	defThingsURL        = "localhost:8183"
+
+	envLogLevel         = "MF_REPORTS_LOG_LEVEL"
+	envDBHost           = "MF_REPORTS_DB_HOST"
+	envDBPort           = "MF_REPORTS_DB_PORT"
+	envDBUser           = "MF_REPORTS_DB_USER"
+	envDBPass           = "MF_REPORTS_DB_PASS"
+	envDBName           = "MF_REPORTS_DB"
+	envDBSSLMode        = "MF_REPORTS_DB_SSL_MODE"
+	envDBSSLCert        = "MF_REPORTS_DB_SSL_CERT"
+	envDBSSLKey         = "MF_REPORTS_DB_SSL_KEY"
+	envDBSSLRootCert    = "MF_REPORTS_DB_SSL_ROOT_CERT"
+	envDBTarget         = "MF_REPORTS_DB_TARGET"
+	envDBSyncCommit     = "MF_REPORTS_DB_SYNC_COMMIT"
+	envClientTLS        = "MF_REPORTS_CLIENT_TLS"
+	envCACerts          = "MF_REPORTS_CA_CERTS"
+	envClientCert       = "MF_REPORTS_CLIENT_CERT"
+	envClientKey        = "MF_REPORTS_CLIENT_KEY"
+	envPort             = "MF_REPORTS_PORT"
+	envServerCert       = "MF_REPORTS_SERVER_CERT"
+	envServerKey        = "MF_REPORTS_SERVER_KEY"
+	envSMTPHost         = "MF_REPORTS_SMTP_HOST"
+	envSMTPPort         = "MF_REPORTS_SMTP_PORT"
+	envSMTPUser         = "MF_REPORTS_SMTP_USER"
+	envSMTPPass         = "MF_REPORTS_SMTP_PASS"
+	envSMTPFrom         = "MF_REPORTS_SMTP_FROM"
+	envWebhookTimeout   = "MF_REPORTS_WEBHOOK_TIMEOUT"
+	envReaderURL        = "MF_REPORTS_READER_URL"
+	envReaderTimeout    = "MF_REPORTS_READER_TIMEOUT"
+	envScheduleInterval = "MF_REPORTS_SCHEDULE_INTERVAL"
+	envUsersURL         = "MF_USERS_URL"
+	envThingsURL        = "MF_THINGS_URL"
+)
+
+type config struct {
+	logLevel         string
+	dbConfig         postgres.Config
+	clientTLS        bool
+	caCerts          string
+	clientCert       string
+	clientKey        string
+	httpPort         string
+	serverCert       string
+	serverKey        string
+	smtpConfig       smtp.Config
+	webhookTimeout   time.Duration
+	readerURL        string
+	readerTimeout    time.Duration
+	scheduleInterval time.Duration
+	usersURL         string
+	thingsURL        string
+}
+
+func main() {
+	cfg := loadConfig()
+
+	logger, err := mflog.New(os.Stdout, cfg.logLevel)
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+
+	db := connectToDB(cfg.dbConfig, logger)
+	defer db.Close()
+
+	usersConn := connectToGRPC(cfg, cfg.usersURL, "users", logger)
+	defer usersConn.Close()
+
+	thingsConn := connectToGRPC(cfg, cfg.thingsURL, "things", logger)
+	defer thingsConn.Close()
+
+	svc := newService(usersConn, thingsConn, db, logger, cfg)
+	errs := make(chan error, 2)
+
+	go startHTTPServer(svc, cfg, logger, errs)
+	go schedule(svc, cfg.scheduleInterval, logger)
+
+	go func() {
+		c := make(chan os.Signal)
+		signal.Notify(c, syscall.SIGINT)
+		errs <- fmt.Errorf("%s", <-c)
+	}()
+
+	err = <-errs
+	logger.Error(fmt.Sprintf("Reports service terminated: %s", err))
+}
+
+func loadConfig() config {
+	tls, err := strconv.ParseBool(mainflux.Env(envClientTLS, defClientTLS))
+	if err != nil {
+		tls = false
+	}
+
+	dbConfig := postgres.Config{
+		Host:        mainflux.Env(envDBHost, defDBHost),
+		Port:        mainflux.Env(envDBPort, defDBPort),
+		User:        mainflux.Env(envDBUser, defDBUser),
+		Pass:        mainflux.Env(envDBPass, defDBPass),
+		Name:        mainflux.Env(envDBName, defDBName),
+		SSLMode:     mainflux.Env(envDBSSLMode, defDBSSLMode),
+		SSLCert:     mainflux.Env(envDBSSLCert, defDBSSLCert),
+		SSLKey:      mainflux.Env(envDBSSLKey, defDBSSLKey),
+		SSLRootCert: mainflux.Env(envDBSSLRootCert, defDBSSLRootCert),
+		Target:      mainflux.Env(envDBTarget, defDBTarget),
+		SyncCommit:  mainflux.Env(envDBSyncCommit, defDBSyncCommit),
+	}
+
+	smtpConfig := smtp.Config{
+		Host:     mainflux.Env(envSMTPHost, defSMTPHost),
+		Port:     mainflux.Env(envSMTPPort, defSMTPPort),
+		Username: mainflux.Env(envSMTPUser, defSMTPUser),
+		Password: mainflux.Env(envSMTPPass, defSMTPPass),
+		From:     mainflux.Env(envSMTPFrom, defSMTPFrom),
+	}
+
+	return config{
+		logLevel:         mainflux.Env(envLogLevel, defLogLevel),
+		dbConfig:         dbConfig,
+		clientTLS:        tls,
+		caCerts:          mainflux.Env(envCACerts, defCACerts),
+		clientCert:       mainflux.Env(envClientCert, defClientCert),
+		clientKey:        mainflux.Env(envClientKey, defClientKey),
+		httpPort:         mainflux.Env(envPort, defPort),
+		serverCert:       mainflux.Env(envServerCert, defServerCert),
+		serverKey:        mainflux.Env(envServerKey, defServerKey),
+		smtpConfig:       smtpConfig,
+		webhookTimeout:   loadSeconds(envWebhookTimeout, defWebhookTimeout),
+		readerURL:        mainflux.Env(envReaderURL, defReaderURL),
+		readerTimeout:    loadSeconds(envReaderTimeout, defReaderTimeout),
+		scheduleInterval: loadSeconds(envScheduleInterval, defScheduleInterval),
+		usersURL:         mainflux.Env(envUsersURL, defUsersURL),
+		thingsURL:        mainflux.Env(envThingsURL, defThingsURL),
+	}
+}
+
+func loadSeconds(key, fallback string) time.Duration {
+	value, err := strconv.ParseInt(mainflux.Env(key, fallback), 10, 64)
+	if err != nil || value <= 0 {
+		log.Fatalf("Invalid %s value: %s", key, mainflux.Env(key, fallback))
+	}
+
+	return time.Duration(value) * time.Second
+}
+
+func connectToDB(cfg postgres.Config, logger mflog.Logger) *sqlx.DB {
+	db, err := postgres.Connect(cfg)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to postgres: %s", err))
+		os.Exit(1)
+	}
+	return db
+}
+
+func newService(usersConn, thingsConn *grpc.ClientConn, db *sqlx.DB, logger mflog.Logger, cfg config) reports.Service {
+	repo := postgres.NewReportRepository(db)
+	users := usersapi.NewClient(usersConn)
+	things := thingsapi.NewClient(thingsConn)
+	messages := reader.New(cfg.readerURL, cfg.readerTimeout)
+	idp := uuid.New()
+
+	senders := map[string]reports.Sender{
+		reports.Webhook: webhook.New(cfg.webhookTimeout),
+	}
+	if cfg.smtpConfig.Host != "" {
+		senders[reports.Email] = smtp.New(cfg.smtpConfig)
+	} else {
+		logger.Info("SMTP host is not set, email delivery is disabled")
+	}
+
+	svc := reports.New(users, things, repo, messages, idp, senders)
+	svc = api.NewLoggingMiddleware(svc, logger)
+	svc = api.MetricsMiddleware(
+		svc,
+		kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: "reports",
+			Subsystem: "api",
+			Name:      "request_count",
+			Help:      "Number of requests received.",
+		}, []string{"method"}),
+		kitprometheus.NewSummaryFrom(stdprometheus.SummaryOpts{
+			Namespace: "reports",
+			Subsystem: "api",
+			Name:      "request_latency_microseconds",
+			Help:      "Total duration of requests in microseconds.",
+		}, []string{"method"}),
+	)
+	return svc
+}
+
+func connectToGRPC(cfg config, url, name string, logger mflog.Logger) *grpc.ClientConn {
+	var opts []grpc.DialOption
+	if cfg.clientTLS {
+		tpc, err := mtls.ClientCredentials(cfg.caCerts, cfg.clientCert, cfg.clientKey)
+		if err != nil {
+			logger.Error(fmt.Sprintf("Failed to create tls credentials: %s", err))
+			os.Exit(1)
+		}
+		opts = append(opts, grpc.WithTransportCredentials(tpc))
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+		logger.Info("gRPC communication is not encrypted")
+	}
+
+	conn, err := grpc.Dial(url, opts...)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to %s service: %s", name, err))
+		os.Exit(1)
+	}
+
+	return conn
+}
+
+func startHTTPServer(svc reports.Service, cfg config, logger mflog.Logger, errs chan error) {
+	p := fmt.Sprintf(":%s", cfg.httpPort)
+	if cfg.serverCert != "" || cfg.serverKey != "" {
+		logger.Info(fmt.Sprintf("Reports service started using https on port %s with cert %s key %s",
+			cfg.httpPort, cfg.serverCert, cfg.serverKey))
+		errs <- http.ListenAndServeTLS(p, cfg.serverCert, cfg.serverKey, api.MakeHandler(svc))
+		return
+	}
+	logger.Info(fmt.Sprintf("Reports service started using http on port %s", cfg.httpPort))
+	errs <- http.ListenAndServe(p, api.MakeHandler(svc))
+}
+
+func schedule(svc reports.Service, interval time.Duration, logger mflog.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		if err := svc.SendDue(now); err != nil {
+			logger.Warn(fmt.Sprintf("Failed to deliver due reports: %s", err))
+		}
+	}
+}
@@ -0,0 +1,269 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	r "github.com/go-redis/redis"
+	"github.com/mainflux/mainflux"
+	mflog "github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/mtls"
+	mfredis "github.com/mainflux/mainflux/redis"
+	"github.com/mainflux/mainflux/search"
+	"github.com/mainflux/mainflux/search/api"
+	"github.com/mainflux/mainflux/search/elastic"
+	"github.com/mainflux/mainflux/search/redis"
+	usersapi "github.com/mainflux/mainflux/users/api/grpc"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+)
+
+const (
+	defLogLevel       = "error"
+	defElasticURL     = "http://localhost:9200"
+	defElasticIndex   = "mainflux-entities"
+	defElasticUser    = ""
+	defElasticPass    = ""
+	defElasticTimeout = "5" // in seconds
+	defClientTLS      = "false"
+	defCACerts        = ""
+	defClientCert     = ""
+	defClientKey      = ""
+	defPort           = "8260"
+	defServerCert     = ""
+	defServerKey      = ""
+	defUsersURL       = "localhost:8181"
+	defThingsESURL    = "localhost:6379"
+	defThingsESPass   = ""
+	defThingsESDB     = "0"
+	defUsersESURL     = "localhost:6379"
+	defUsersESPass    = ""
+	defUsersESDB      = "0"
+	defInstanceName   = "search"
+
+	envLogLevel       = "MF_SEARCH_LOG_LEVEL"
+	envElasticURL     = "MF_SEARCH_ELASTIC_URL"
+	envElasticIndex   = "MF_SEARCH_ELASTIC_INDEX"
+	envElasticUser    = "MF_SEARCH_ELASTIC_USER"
+	envElasticPass    = "MF_SEARCH_ELASTIC_PASS"
+	envElasticTimeout = "MF_SEARCH_ELASTIC_TIMEOUT"
+	envClientTLS      = "MF_SEARCH_CLIENT_TLS"
+	envCACerts        = "MF_SEARCH_CA_CERTS"
+	envClientCert     = "MF_SEARCH_CLIENT_CERT"
+	envClientKey      = "MF_SEARCH_CLIENT_KEY"
+	envPort           = "MF_SEARCH_PORT"
+	envServerCert     = "MF_SEARCH_SERVER_CERT"
+	envServerKey      = "MF_SEARCH_SERVER_KEY"
+	envUsersURL       = "MF_USERS_URL"
+	envThingsESURL    = "MF_THINGS_ES_URL"
+	envThingsESPass   = "MF_THINGS_ES_PASS"
+	envThingsESDB     = "MF_THINGS_ES_DB"
+	envUsersESURL     = "MF_USERS_ES_URL"
+	envUsersESPass    = "MF_USERS_ES_PASS"
+	envUsersESDB      = "MF_USERS_ES_DB"
+	envInstanceName   = "MF_SEARCH_INSTANCE_NAME"
+)
+
+type config struct {
+	logLevel      string
+	elasticConfig elastic.Config
+	clientTLS     bool
+	caCerts       string
+	clientCert    string
+	clientKey     string
+	httpPort      string
+	serverCert    string
+	serverKey     string
+	usersURL      string
+	esThingsURL   string
+	esThingsPass  string
+	esThingsDB    string
+	esUsersURL    string
+	esUsersPass   string
+	esUsersDB     string
+	instanceName  string
+}
+
+func main() {
+	cfg := loadConfig()
+
+	logger, err := mflog.New(os.Stdout, cfg.logLevel)
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+
+	index := connectToElastic(cfg.elasticConfig, logger)
+
+	conn := connectToUsers(cfg, logger)
+	defer conn.Close()
+
+	thingsESConn := connectToRedis(cfg.esThingsURL, cfg.esThingsPass, cfg.esThingsDB, logger)
+	defer thingsESConn.Close()
+
+	usersESConn := connectToRedis(cfg.esUsersURL, cfg.esUsersPass, cfg.esUsersDB, logger)
+	defer usersESConn.Close()
+
+	svc := newService(conn, index, logger)
+	errs := make(chan error, 2)
+
+	go startHTTPServer(svc, cfg, logger, errs)
+	go subscribeToThingsES(svc, thingsESConn, cfg.instanceName, logger)
+	go subscribeToUsersES(svc, usersESConn, cfg.instanceName, logger)
+
+	go func() {
+		c := make(chan os.Signal)
+		signal.Notify(c, syscall.SIGINT)
+		errs <- fmt.Errorf("%s", <-c)
+	}()
+
+	err = <-errs
+	logger.Error(fmt.Sprintf("Search service terminated: %s", err))
+}
+
+func loadConfig() config {
+	tls, err := strconv.ParseBool(mainflux.Env(envClientTLS, defClientTLS))
+	if err != nil {
+		tls = false
+	}
+
+	timeout, err := strconv.ParseInt(mainflux.Env(envElasticTimeout, defElasticTimeout), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envElasticTimeout, err.Error())
+	}
+
+	elasticConfig := elastic.Config{
+		URL:     mainflux.Env(envElasticURL, defElasticURL),
+		Index:   mainflux.Env(envElasticIndex, defElasticIndex),
+		User:    mainflux.Env(envElasticUser, defElasticUser),
+		Pass:    mainflux.Env(envElasticPass, defElasticPass),
+		Timeout: time.Duration(timeout) * time.Second,
+	}
+
+	return config{
+		logLevel:      mainflux.Env(envLogLevel, defLogLevel),
+		elasticConfig: elasticConfig,
+		clientTLS:     tls,
+		caCerts:       mainflux.Env(envCACerts, defCACerts),
+		clientCert:    mainflux.Env(envClientCert, defClientCert),
+		clientKey:     mainflux.Env(envClientKey, defClientKey),
+		httpPort:      mainflux.Env(envPort, defPort),
+		serverCert:    mainflux.Env(envServerCert, defServerCert),
+		serverKey:     mainflux.Env(envServerKey, defServerKey),
+		usersURL:      mainflux.Env(envUsersURL, defUsersURL),
+		esThingsURL:   mainflux.Env(envThingsESURL, defThingsESURL),
+		esThingsPass:  mainflux.Env(envThingsESPass, defThingsESPass),
+		esThingsDB:    mainflux.Env(envThingsESDB, defThingsESDB),
+		esUsersURL:    mainflux.Env(envUsersESURL, defUsersESURL),
+		esUsersPass:   mainflux.Env(envUsersESPass, defUsersESPass),
+		esUsersDB:     mainflux.Env(envUsersESDB, defUsersESDB),
+		instanceName:  mainflux.Env(envInstanceName, defInstanceName),
+	}
+}
+
+func connectToElastic(cfg elastic.Config, logger mflog.Logger) search.Index {
+	index, err := elastic.Connect(cfg)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to Elasticsearch: %s", err))
+		os.Exit(1)
+	}
+
+	return index
+}
+
+func connectToRedis(redisURL, redisPass, redisDB string, logger mflog.Logger) r.UniversalClient {
+	client, err := mfredis.Connect(redisURL, redisPass, redisDB)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to redis: %s", err))
+		os.Exit(1)
+	}
+
+	return client
+}
+
+func newService(conn *grpc.ClientConn, index search.Index, logger mflog.Logger) search.Service {
+	users := usersapi.NewClient(conn)
+
+	svc := search.New(users, index)
+	svc = api.NewLoggingMiddleware(svc, logger)
+	svc = api.MetricsMiddleware(
+		svc,
+		kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: "search",
+			Subsystem: "api",
+			Name:      "request_count",
+			Help:      "Number of requests received.",
+		}, []string{"method"}),
+		kitprometheus.NewSummaryFrom(stdprometheus.SummaryOpts{
+			Namespace: "search",
+			Subsystem: "api",
+			Name:      "request_latency_microseconds",
+			Help:      "Total duration of requests in microseconds.",
+		}, []string{"method"}),
+	)
+	return svc
+}
+
+func connectToUsers(cfg config, logger mflog.Logger) *grpc.ClientConn {
+	var opts []grpc.DialOption
+	if cfg.clientTLS {
+		tpc, err := mtls.ClientCredentials(cfg.caCerts, cfg.clientCert, cfg.clientKey)
+		if err != nil {
+			logger.Error(fmt.Sprintf("Failed to create tls credentials: %s", err))
+			os.Exit(1)
+		}
+		opts = append(opts, grpc.WithTransportCredentials(tpc))
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+		logger.Info("gRPC communication is not encrypted")
+	}
+
+	conn, err := grpc.Dial(cfg.usersURL, opts...)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to users service: %s", err))
+		os.Exit(1)
+	}
+
+	return conn
+}
+
+func startHTTPServer(svc search.Service, cfg config, logger mflog.Logger, errs chan error) {
+	p := fmt.Sprintf(":%s", cfg.httpPort)
+	if cfg.serverCert != "" || cfg.serverKey != "" {
+		logger.Info(fmt.Sprintf("Search service started using https on port %s with cert %s key %s",
+			cfg.httpPort, cfg.serverCert, cfg.serverKey))
+		errs <- http.ListenAndServeTLS(p, cfg.serverCert, cfg.serverKey, api.MakeHandler(svc))
+		return
+	}
+	logger.Info(fmt.Sprintf("Search service started using http on port %s", cfg.httpPort))
+	errs <- http.ListenAndServe(p, api.MakeHandler(svc))
+}
+
+func subscribeToThingsES(svc search.Service, client r.UniversalClient, consumer string, logger mflog.Logger) {
+	eventStore := redis.NewEventStore(svc, client, consumer, logger)
+	logger.Info("Subscribed to Redis Event Store")
+	if err := eventStore.Subscribe("mainflux.things"); err != nil {
+		logger.Warn(fmt.Sprintf("Search service failed to subscribe to event sourcing: %s", err))
+	}
+}
+
+func subscribeToUsersES(svc search.Service, client r.UniversalClient, consumer string, logger mflog.Logger) {
+	eventStore := redis.NewUsersEventStore(svc, client, consumer, logger)
+	logger.Info("Subscribed to Redis Event Store")
+	if err := eventStore.Subscribe("mainflux.users"); err != nil {
+		logger.Warn(fmt.Sprintf("Search service failed to subscribe to event sourcing: %s", err))
+	}
+}
@@ -8,6 +8,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net"
@@ -15,100 +16,197 @@ import (
 	"os"
 	"os/signal"
 	"strconv"
+	"strings"
 	"syscall"
+	"time"
 
 	"github.com/jmoiron/sqlx"
-	"google.golang.org/grpc/credentials"
 
 	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
 	"github.com/go-redis/redis"
 	"github.com/mainflux/mainflux"
+	flagsapi "github.com/mainflux/mainflux/flags/api"
 	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/mtls"
+	"github.com/mainflux/mainflux/ratelimit"
+	mfredis "github.com/mainflux/mainflux/redis"
 	"github.com/mainflux/mainflux/things"
 	"github.com/mainflux/mainflux/things/api"
 	grpcapi "github.com/mainflux/mainflux/things/api/grpc"
 	httpapi "github.com/mainflux/mainflux/things/api/http"
+	thingsflags "github.com/mainflux/mainflux/things/flags"
+	"github.com/mainflux/mainflux/things/jwt"
+	"github.com/mainflux/mainflux/things/lru"
+	natsconsumer "github.com/mainflux/mainflux/things/nats"
 	"github.com/mainflux/mainflux/things/postgres"
 	rediscache "github.com/mainflux/mainflux/things/redis"
 	localusers "github.com/mainflux/mainflux/things/users"
 	"github.com/mainflux/mainflux/things/uuid"
 	usersapi "github.com/mainflux/mainflux/users/api/grpc"
+	broker "github.com/nats-io/go-nats"
 	stdprometheus "github.com/prometheus/client_golang/prometheus"
 	"google.golang.org/grpc"
 )
 
 const (
-	defLogLevel        = "error"
-	defDBHost          = "localhost"
-	defDBPort          = "5432"
-	defDBUser          = "mainflux"
-	defDBPass          = "mainflux"
-	defDBName          = "things"
-	defDBSSLMode       = "disable"
-	defDBSSLCert       = ""
-	defDBSSLKey        = ""
-	defDBSSLRootCert   = ""
-	defClientTLS       = "false"
-	defCACerts         = ""
-	defCacheURL        = "localhost:6379"
-	defCachePass       = ""
-	defCacheDB         = "0"
-	defESURL           = "localhost:6379"
-	defESPass          = ""
-	defESDB            = "0"
-	defHTTPPort        = "8180"
-	defGRPCPort        = "8181"
-	defServerCert      = ""
-	defServerKey       = ""
-	defUsersURL        = "localhost:8181"
-	defSingleUserEmail = ""
-	defSingleUserToken = ""
-
-	envLogLevel        = "MF_THINGS_LOG_LEVEL"
-	envDBHost          = "MF_THINGS_DB_HOST"
-	envDBPort          = "MF_THINGS_DB_PORT"
-	envDBUser          = "MF_THINGS_DB_USER"
-	envDBPass          = "MF_THINGS_DB_PASS"
-	envDBName          = "MF_THINGS_DB"
-	envDBSSLMode       = "MF_THINGS_DB_SSL_MODE"
-	envDBSSLCert       = "MF_THINGS_DB_SSL_CERT"
-	envDBSSLKey        = "MF_THINGS_DB_SSL_KEY"
-	envDBSSLRootCert   = "MF_THINGS_DB_SSL_ROOT_CERT"
-	envClientTLS       = "MF_THINGS_CLIENT_TLS"
-	envCACerts         = "MF_THINGS_CA_CERTS"
-	envCacheURL        = "MF_THINGS_CACHE_URL"
-	envCachePass       = "MF_THINGS_CACHE_PASS"
-	envCacheDB         = "MF_THINGS_CACHE_DB"
-	envESURL           = "MF_THINGS_ES_URL"
-	envESPass          = "MF_THINGS_ES_PASS"
-	envESDB            = "MF_THINGS_ES_DB"
-	envHTTPPort        = "MF_THINGS_HTTP_PORT"
-	envGRPCPort        = "MF_THINGS_GRPC_PORT"
-	envUsersURL        = "MF_USERS_URL"
-	envServerCert      = "MF_THINGS_SERVER_CERT"
-	envServerKey       = "MF_THINGS_SERVER_KEY"
-	envSingleUserEmail = "MF_THINGS_SINGLE_USER_EMAIL"
-	envSingleUserToken = "MF_THINGS_SINGLE_USER_TOKEN"
+	defLogLevel         = "error"
+	defDBHost           = "localhost"
+	defDBPort           = "5432"
+	defDBUser           = "mainflux"
+	defDBPass           = "mainflux"
+	defDBName           = "things"
+	defDBSSLMode        = "disable"
+	defDBSSLCert        = ""
+	defDBSSLKey         = ""
+	defDBSSLRootCert    = ""
+	defDBTarget         = "read-write"
+	defDBSyncCommit     = ""
+	defDBFlavor         = "postgres"
+	defUniqueNames      = "false"
+	defClientTLS        = "false"
+	defCACerts          = ""
+	defClientCert       = ""
+	defClientKey        = ""
+	defCacheURL         = "localhost:6379"
+	defCachePass        = ""
+	defCacheDB          = "0"
+	defCacheBackend     = "redis"
+	defCacheSize        = "100000"
+	defCacheTTL         = "10m"
+	defESURL            = "localhost:6379"
+	defESPass           = ""
+	defESDB             = "0"
+	defESNats           = "false"
+	defUsersESURL       = "localhost:6379"
+	defUsersESPass      = ""
+	defUsersESDB        = "0"
+	defInstanceName     = "things"
+	defNatsURL          = broker.DefaultURL
+	defHTTPPort         = "8180"
+	defGRPCPort         = "8181"
+	defServerCert       = ""
+	defServerKey        = ""
+	defServerCACerts    = ""
+	defServerIDs        = ""
+	defUsersURL         = "localhost:8181"
+	defSingleUserEmail  = ""
+	defSingleUserToken  = ""
+	defOnboardSecret    = "things"
+	defOnboardEndpts    = ""
+	defOnboardDuration  = "24h"
+	defKeysSecret       = "things-keys"
+	defNameLength       = "1024"
+	defKeyLength        = "4096"
+	defMetadataSize     = "0"
+	defRotationInterval = "1m"
+	defStaleAfter       = "5m"
+	defFlagsURL         = ""
+	defFlagsRefresh     = "30s"
+	defRateLimit        = "0"
+	defRateWindow       = "1m"
+
+	envLogLevel         = "MF_THINGS_LOG_LEVEL"
+	envDBHost           = "MF_THINGS_DB_HOST"
+	envDBPort           = "MF_THINGS_DB_PORT"
+	envDBUser           = "MF_THINGS_DB_USER"
+	envDBPass           = "MF_THINGS_DB_PASS"
+	envDBName           = "MF_THINGS_DB"
+	envDBSSLMode        = "MF_THINGS_DB_SSL_MODE"
+	envDBSSLCert        = "MF_THINGS_DB_SSL_CERT"
+	envDBSSLKey         = "MF_THINGS_DB_SSL_KEY"
+	envDBSSLRootCert    = "MF_THINGS_DB_SSL_ROOT_CERT"
+	envDBTarget         = "MF_THINGS_DB_TARGET"
+	envDBSyncCommit     = "MF_THINGS_DB_SYNC_COMMIT"
+	envDBFlavor         = "MF_THINGS_DB_FLAVOR"
+	envUniqueNames      = "MF_THINGS_UNIQUE_NAMES"
+	envClientTLS        = "MF_THINGS_CLIENT_TLS"
+	envCACerts          = "MF_THINGS_CA_CERTS"
+	envClientCert       = "MF_THINGS_CLIENT_CERT"
+	envClientKey        = "MF_THINGS_CLIENT_KEY"
+	envCacheURL         = "MF_THINGS_CACHE_URL"
+	envCachePass        = "MF_THINGS_CACHE_PASS"
+	envCacheDB          = "MF_THINGS_CACHE_DB"
+	envCacheBackend     = "MF_THINGS_CACHE_BACKEND"
+	envCacheSize        = "MF_THINGS_CACHE_SIZE"
+	envCacheTTL         = "MF_THINGS_CACHE_TTL"
+	envESURL            = "MF_THINGS_ES_URL"
+	envESPass           = "MF_THINGS_ES_PASS"
+	envESDB             = "MF_THINGS_ES_DB"
+	envESNats           = "MF_THINGS_ES_NATS"
+	envUsersESURL       = "MF_USERS_ES_URL"
+	envUsersESPass      = "MF_USERS_ES_PASS"
+	envUsersESDB        = "MF_USERS_ES_DB"
+	envInstanceName     = "MF_THINGS_INSTANCE_NAME"
+	envNatsURL          = "MF_NATS_URL"
+	envHTTPPort         = "MF_THINGS_HTTP_PORT"
+	envGRPCPort         = "MF_THINGS_GRPC_PORT"
+	envUsersURL         = "MF_USERS_URL"
+	envServerCert       = "MF_THINGS_SERVER_CERT"
+	envServerKey        = "MF_THINGS_SERVER_KEY"
+	envServerCACerts    = "MF_THINGS_SERVER_CA_CERTS"
+	envServerIDs        = "MF_THINGS_SERVER_CLIENT_IDS"
+	envSingleUserEmail  = "MF_THINGS_SINGLE_USER_EMAIL"
+	envSingleUserToken  = "MF_THINGS_SINGLE_USER_TOKEN"
+	envOnboardSecret    = "MF_THINGS_ONBOARDING_SECRET"
+	envOnboardEndpts    = "MF_THINGS_ONBOARDING_ENDPOINTS"
+	envOnboardDuration  = "MF_THINGS_ONBOARDING_DURATION"
+	envKeysSecret       = "MF_THINGS_KEYS_SECRET"
+	envNameLength       = "MF_THINGS_NAME_LENGTH"
+	envKeyLength        = "MF_THINGS_KEY_LENGTH"
+	envMetadataSize     = "MF_THINGS_METADATA_SIZE"
+	envRotationInterval = "MF_THINGS_KEY_ROTATION_INTERVAL"
+	envStaleAfter       = "MF_THINGS_STALE_AFTER"
+	envFlagsURL         = "MF_THINGS_FLAGS_URL"
+	envFlagsRefresh     = "MF_THINGS_FLAGS_REFRESH"
+	envRateLimit        = "MF_THINGS_API_RATE_LIMIT"
+	envRateWindow       = "MF_THINGS_API_RATE_WINDOW"
+
+	redisBackend  = "redis"
+	memoryBackend = "memory"
 )
 
 type config struct {
-	logLevel        string
-	dbConfig        postgres.Config
-	clientTLS       bool
-	caCerts         string
-	cacheURL        string
-	cachePass       string
-	cacheDB         string
-	esURL           string
-	esPass          string
-	esDB            string
-	httpPort        string
-	grpcPort        string
-	usersURL        string
-	serverCert      string
-	serverKey       string
-	singleUserEmail string
-	singleUserToken string
+	logLevel         string
+	dbConfig         postgres.Config
+	clientTLS        bool
+	caCerts          string
+	clientCert       string
+	clientKey        string
+	cacheURL         string
+	cachePass        string
+	cacheDB          string
+	cacheBackend     string
+	cacheSize        int
+	cacheTTL         time.Duration
+	esURL            string
+	esPass           string
+	esDB             string
+	esNats           bool
+	usersESURL       string
+	usersESPass      string
+	usersESDB        string
+	instanceName     string
+	natsURL          string
+	httpPort         string
+	grpcPort         string
+	usersURL         string
+	serverCert       string
+	serverKey        string
+	serverCACerts    string
+	serverIDs        []string
+	singleUserEmail  string
+	singleUserToken  string
+	onboardSecret    string
+	onboardEndpts    map[string]string
+	onboardDuration  time.Duration
+	keysSecret       string
+	limits           things.Limits
+	rotationInterval time.Duration
+	staleAfter       time.Duration
+	flagsURL         string
+	flagsRefresh     time.Duration
+	rateLimit        uint64
+	rateWindow       time.Duration
 }
 
 func main() {
@@ -122,19 +220,40 @@ func main() {
 
 	esClient := connectToRedis(cfg.esURL, cfg.esPass, cfg.esDB, logger)
 
+	usersESClient := connectToRedis(cfg.usersESURL, cfg.usersESPass, cfg.usersESDB, logger)
+	defer usersESClient.Close()
+
 	db := connectToDB(cfg.dbConfig, logger)
 	defer db.Close()
 
+	nc, err := broker.Connect(cfg.natsURL)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to NATS: %s", err))
+		os.Exit(1)
+	}
+	defer nc.Close()
+
 	users, close := createUsersClient(cfg, logger)
 	if close != nil {
 		defer close()
 	}
 
-	svc := newService(users, db, cacheClient, esClient, logger)
+	onboarding := jwt.New(cfg.onboardSecret, cfg.onboardEndpts, cfg.onboardDuration)
+	keys := jwt.NewKeyProvider(cfg.keysSecret)
+	overrides := newOverrideProvider(cfg, logger)
+	thingCache, chanCache := newCaches(cfg, cacheClient, esClient, logger)
+	svc := newService(users, db, nc, cacheClient, esClient, thingCache, chanCache, onboarding, keys, overrides, cfg.limits, cfg.staleAfter, cfg.esNats, logger)
 	errs := make(chan error, 2)
 
-	go startHTTPServer(svc, cfg, logger, errs)
+	go startHTTPServer(svc, users, newRateLimiter(cfg, cacheClient), cfg, logger, errs)
 	go startGRPCServer(svc, cfg, logger, errs)
+	go subscribeToUsersES(svc, usersESClient, cfg.instanceName, logger)
+	go rotateKeys(svc, cfg.rotationInterval, logger)
+
+	if err := natsconsumer.Subscribe(svc, nc, logger); err != nil {
+		logger.Error(fmt.Sprintf("Failed to subscribe to NATS: %s", err))
+		os.Exit(1)
+	}
 
 	go func() {
 		c := make(chan os.Signal)
@@ -152,6 +271,16 @@ func loadConfig() config {
 		log.Fatalf("Invalid value passed for %s\n", envClientTLS)
 	}
 
+	uniqueNames, err := strconv.ParseBool(mainflux.Env(envUniqueNames, defUniqueNames))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envUniqueNames)
+	}
+
+	esNats, err := strconv.ParseBool(mainflux.Env(envESNats, defESNats))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envESNats)
+	}
+
 	dbConfig := postgres.Config{
 		Host:        mainflux.Env(envDBHost, defDBHost),
 		Port:        mainflux.Env(envDBPort, defDBPort),
@@ -162,41 +291,138 @@ func loadConfig() config {
 		SSLCert:     mainflux.Env(envDBSSLCert, defDBSSLCert),
 		SSLKey:      mainflux.Env(envDBSSLKey, defDBSSLKey),
 		SSLRootCert: mainflux.Env(envDBSSLRootCert, defDBSSLRootCert),
+		Target:      mainflux.Env(envDBTarget, defDBTarget),
+		SyncCommit:  mainflux.Env(envDBSyncCommit, defDBSyncCommit),
+		UniqueNames: uniqueNames,
+		Flavor:      mainflux.Env(envDBFlavor, defDBFlavor),
+	}
+
+	endpoints := map[string]string{}
+	for _, endpoint := range strings.Split(mainflux.Env(envOnboardEndpts, defOnboardEndpts), ",") {
+		if endpoint == "" {
+			continue
+		}
+
+		kv := strings.SplitN(endpoint, "=", 2)
+		if len(kv) != 2 {
+			log.Fatalf("Invalid value passed for %s\n", envOnboardEndpts)
+		}
+		endpoints[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+	}
+
+	duration, err := time.ParseDuration(mainflux.Env(envOnboardDuration, defOnboardDuration))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envOnboardDuration)
+	}
+
+	rotationInterval, err := time.ParseDuration(mainflux.Env(envRotationInterval, defRotationInterval))
+	if err != nil || rotationInterval <= 0 {
+		log.Fatalf("Invalid value passed for %s\n", envRotationInterval)
+	}
+
+	staleAfter, err := time.ParseDuration(mainflux.Env(envStaleAfter, defStaleAfter))
+	if err != nil || staleAfter <= 0 {
+		log.Fatalf("Invalid value passed for %s\n", envStaleAfter)
+	}
+
+	flagsRefresh, err := time.ParseDuration(mainflux.Env(envFlagsRefresh, defFlagsRefresh))
+	if err != nil || flagsRefresh <= 0 {
+		log.Fatalf("Invalid value passed for %s\n", envFlagsRefresh)
+	}
+
+	rateLimit, err := strconv.ParseUint(mainflux.Env(envRateLimit, defRateLimit), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envRateLimit)
+	}
+
+	rateWindow, err := time.ParseDuration(mainflux.Env(envRateWindow, defRateWindow))
+	if err != nil || rateWindow <= 0 {
+		log.Fatalf("Invalid value passed for %s\n", envRateWindow)
+	}
+
+	cacheBackend := mainflux.Env(envCacheBackend, defCacheBackend)
+	if cacheBackend != redisBackend && cacheBackend != memoryBackend {
+		log.Fatalf("Invalid value passed for %s\n", envCacheBackend)
+	}
+
+	cacheSize, err := strconv.Atoi(mainflux.Env(envCacheSize, defCacheSize))
+	if err != nil || cacheSize <= 0 {
+		log.Fatalf("Invalid value passed for %s\n", envCacheSize)
+	}
+
+	cacheTTL, err := time.ParseDuration(mainflux.Env(envCacheTTL, defCacheTTL))
+	if err != nil || cacheTTL < 0 {
+		log.Fatalf("Invalid value passed for %s\n", envCacheTTL)
+	}
+
+	limits := things.Limits{
+		NameLength:   loadLimit(envNameLength, defNameLength),
+		KeyLength:    loadLimit(envKeyLength, defKeyLength),
+		MetadataSize: loadLimit(envMetadataSize, defMetadataSize),
 	}
 
 	return config{
-		logLevel:        mainflux.Env(envLogLevel, defLogLevel),
-		dbConfig:        dbConfig,
-		clientTLS:       tls,
-		caCerts:         mainflux.Env(envCACerts, defCACerts),
-		cacheURL:        mainflux.Env(envCacheURL, defCacheURL),
-		cachePass:       mainflux.Env(envCachePass, defCachePass),
-		cacheDB:         mainflux.Env(envCacheDB, defCacheDB),
-		esURL:           mainflux.Env(envESURL, defESURL),
-		esPass:          mainflux.Env(envESPass, defESPass),
-		esDB:            mainflux.Env(envESDB, defESDB),
-		httpPort:        mainflux.Env(envHTTPPort, defHTTPPort),
-		grpcPort:        mainflux.Env(envGRPCPort, defGRPCPort),
-		usersURL:        mainflux.Env(envUsersURL, defUsersURL),
-		serverCert:      mainflux.Env(envServerCert, defServerCert),
-		serverKey:       mainflux.Env(envServerKey, defServerKey),
-		singleUserEmail: mainflux.Env(envSingleUserEmail, defSingleUserEmail),
-		singleUserToken: mainflux.Env(envSingleUserToken, defSingleUserToken),
+		logLevel:         mainflux.Env(envLogLevel, defLogLevel),
+		dbConfig:         dbConfig,
+		clientTLS:        tls,
+		caCerts:          mainflux.Env(envCACerts, defCACerts),
+		clientCert:       mainflux.Env(envClientCert, defClientCert),
+		clientKey:        mainflux.Env(envClientKey, defClientKey),
+		cacheURL:         mainflux.Env(envCacheURL, defCacheURL),
+		cachePass:        mainflux.Env(envCachePass, defCachePass),
+		cacheDB:          mainflux.Env(envCacheDB, defCacheDB),
+		cacheBackend:     cacheBackend,
+		cacheSize:        cacheSize,
+		cacheTTL:         cacheTTL,
+		esURL:            mainflux.Env(envESURL, defESURL),
+		esPass:           mainflux.Env(envESPass, defESPass),
+		esDB:             mainflux.Env(envESDB, defESDB),
+		esNats:           esNats,
+		usersESURL:       mainflux.Env(envUsersESURL, defUsersESURL),
+		usersESPass:      mainflux.Env(envUsersESPass, defUsersESPass),
+		usersESDB:        mainflux.Env(envUsersESDB, defUsersESDB),
+		instanceName:     mainflux.Env(envInstanceName, defInstanceName),
+		natsURL:          mainflux.Env(envNatsURL, defNatsURL),
+		httpPort:         mainflux.Env(envHTTPPort, defHTTPPort),
+		grpcPort:         mainflux.Env(envGRPCPort, defGRPCPort),
+		usersURL:         mainflux.Env(envUsersURL, defUsersURL),
+		serverCert:       mainflux.Env(envServerCert, defServerCert),
+		serverKey:        mainflux.Env(envServerKey, defServerKey),
+		serverCACerts:    mainflux.Env(envServerCACerts, defServerCACerts),
+		serverIDs:        strings.Split(mainflux.Env(envServerIDs, defServerIDs), ","),
+		singleUserEmail:  mainflux.Env(envSingleUserEmail, defSingleUserEmail),
+		singleUserToken:  mainflux.Env(envSingleUserToken, defSingleUserToken),
+		onboardSecret:    mainflux.Env(envOnboardSecret, defOnboardSecret),
+		onboardEndpts:    endpoints,
+		onboardDuration:  duration,
+		keysSecret:       mainflux.Env(envKeysSecret, defKeysSecret),
+		limits:           limits,
+		rotationInterval: rotationInterval,
+		staleAfter:       staleAfter,
+		flagsURL:         mainflux.Env(envFlagsURL, defFlagsURL),
+		flagsRefresh:     flagsRefresh,
+		rateLimit:        rateLimit,
+		rateWindow:       rateWindow,
+	}
+}
+
+func loadLimit(key, fallback string) int {
+	limit, err := strconv.Atoi(mainflux.Env(key, fallback))
+	if err != nil || limit < 0 {
+		log.Fatalf("Invalid value passed for %s\n", key)
 	}
+
+	return limit
 }
 
-func connectToRedis(cacheURL, cachePass string, cacheDB string, logger logger.Logger) *redis.Client {
-	db, err := strconv.Atoi(cacheDB)
+func connectToRedis(cacheURL, cachePass string, cacheDB string, logger logger.Logger) redis.UniversalClient {
+	client, err := mfredis.Connect(cacheURL, cachePass, cacheDB)
 	if err != nil {
 		logger.Error(fmt.Sprintf("Failed to connect to cache: %s", err))
 		os.Exit(1)
 	}
 
-	return redis.NewClient(&redis.Options{
-		Addr:     cacheURL,
-		Password: cachePass,
-		DB:       db,
-	})
+	return client
 }
 
 func connectToDB(dbConfig postgres.Config, logger logger.Logger) *sqlx.DB {
@@ -220,14 +446,12 @@ func createUsersClient(cfg config, logger logger.Logger) (mainflux.UsersServiceC
 func connectToUsers(cfg config, logger logger.Logger) *grpc.ClientConn {
 	var opts []grpc.DialOption
 	if cfg.clientTLS {
-		if cfg.caCerts != "" {
-			tpc, err := credentials.NewClientTLSFromFile(cfg.caCerts, "")
-			if err != nil {
-				logger.Error(fmt.Sprintf("Failed to create tls credentials: %s", err))
-				os.Exit(1)
-			}
-			opts = append(opts, grpc.WithTransportCredentials(tpc))
+		tpc, err := mtls.ClientCredentials(cfg.caCerts, cfg.clientCert, cfg.clientKey)
+		if err != nil {
+			logger.Error(fmt.Sprintf("Failed to create tls credentials: %s", err))
+			os.Exit(1)
 		}
+		opts = append(opts, grpc.WithTransportCredentials(tpc))
 	} else {
 		opts = append(opts, grpc.WithInsecure())
 		logger.Info("gRPC communication is not encrypted")
@@ -242,15 +466,71 @@ func connectToUsers(cfg config, logger logger.Logger) *grpc.ClientConn {
 	return conn
 }
 
-func newService(users mainflux.UsersServiceClient, db *sqlx.DB, cacheClient *redis.Client, esClient *redis.Client, logger logger.Logger) things.Service {
+// newCaches returns the thing and channel caches of the configured backend.
+// The in-memory caches of the instance are kept in sync with the changes
+// made through the other instances by reading the things events.
+func newCaches(cfg config, cacheClient, esClient redis.UniversalClient, logger logger.Logger) (things.ThingCache, things.ChannelCache) {
+	thingCache := rediscache.NewThingCache(cacheClient, cfg.cacheTTL)
+	chanCache := rediscache.NewChannelCache(cacheClient, cfg.cacheTTL)
+	if cfg.cacheBackend == redisBackend {
+		return thingCache, chanCache
+	}
+
+	thingCache = lru.NewThingCache(thingCache, cfg.cacheSize, cfg.cacheTTL)
+	chanCache = lru.NewChannelCache(chanCache, cfg.cacheSize, cfg.cacheTTL)
+
+	invalidator := rediscache.NewCacheInvalidator(esClient, thingCache, chanCache)
+	go func() {
+		if err := invalidator.Subscribe(); err != nil {
+			logger.Warn(fmt.Sprintf("Failed to subscribe to cache invalidation events: %s", err))
+		}
+	}()
+
+	return thingCache, chanCache
+}
+
+// newOverrideProvider returns the provider of the owners' configuration
+// overrides kept by the flags service, that provides no overrides unless
+// the flags service URL is set.
+func newOverrideProvider(cfg config, logger logger.Logger) things.OverrideProvider {
+	if cfg.flagsURL == "" {
+		return thingsflags.NewOverrideProvider(nil)
+	}
+
+	return thingsflags.NewOverrideProvider(flagsapi.NewClient(cfg.flagsURL, cfg.flagsRefresh, logger))
+}
+
+func newService(users mainflux.UsersServiceClient, db *sqlx.DB, nc *broker.Conn, cacheClient redis.UniversalClient, esClient redis.UniversalClient, thingCache things.ThingCache, chanCache things.ChannelCache, onboarding things.OnboardingProvider, keys things.KeyProvider, overrides things.OverrideProvider, limits things.Limits, staleAfter time.Duration, esNats bool, logger logger.Logger) things.Service {
 	thingsRepo := postgres.NewThingRepository(db)
 	channelsRepo := postgres.NewChannelRepository(db)
-	chanCache := rediscache.NewChannelCache(cacheClient)
-	thingCache := rediscache.NewThingCache(cacheClient)
+	reservationsRepo := postgres.NewReservationRepository(db)
+	usageRepo := rediscache.NewUsageRepository(cacheClient)
+	historyRepo := postgres.NewHistoryRepository(db)
+	auditRepo := postgres.NewAuditRepository(db)
+	templatesRepo := postgres.NewTemplateRepository(db)
+	groupsRepo := postgres.NewGroupRepository(db)
+	subtopicsRepo := postgres.NewSubtopicRepository(db)
+	observationsRepo := rediscache.NewObservationRepository(cacheClient)
+	thingKeysRepo := postgres.NewThingKeyRepository(db)
+	sharesRepo := postgres.NewShareRepository(db)
+	profilesRepo := postgres.NewProfileRepository(db)
+	rulesRepo := postgres.NewSubtopicRuleRepository(db)
+	healthRepo := postgres.NewHealthRepository(db)
 	idp := uuid.New()
 
-	svc := things.New(users, thingsRepo, channelsRepo, chanCache, thingCache, idp)
-	svc = rediscache.NewEventStoreMiddleware(svc, esClient)
+	revocations, err := natsconsumer.NewRevocationRepository(postgres.NewRevocationRepository(db), nc, logger)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to subscribe to NATS: %s", err))
+		os.Exit(1)
+	}
+
+	svc := things.New(users, thingsRepo, channelsRepo, reservationsRepo, usageRepo, historyRepo, auditRepo, chanCache, thingCache, idp, onboarding, keys, revocations, templatesRepo, groupsRepo, subtopicsRepo, observationsRepo, thingKeysRepo, sharesRepo, profilesRepo, rulesRepo, healthRepo, postgres.NewChannelTokenRepository(db), overrides, limits, staleAfter)
+	publishers := []things.EventPublisher{}
+	if esNats {
+		publishers = append(publishers, natsconsumer.NewEventPublisher(nc))
+	}
+
+	svc = rediscache.NewEventStoreMiddleware(svc, esClient, publishers...)
 	svc = api.LoggingMiddleware(svc, logger)
 	svc = api.MetricsMiddleware(
 		svc,
@@ -270,16 +550,25 @@ func newService(users mainflux.UsersServiceClient, db *sqlx.DB, cacheClient *red
 	return svc
 }
 
-func startHTTPServer(svc things.Service, cfg config, logger logger.Logger, errs chan error) {
+func newRateLimiter(cfg config, client redis.UniversalClient) ratelimit.Limiter {
+	if cfg.rateLimit == 0 {
+		return nil
+	}
+
+	return ratelimit.NewLimiter(client, "things", cfg.rateLimit, cfg.rateWindow)
+}
+
+func startHTTPServer(svc things.Service, users mainflux.UsersServiceClient, limiter ratelimit.Limiter, cfg config, logger logger.Logger, errs chan error) {
 	p := fmt.Sprintf(":%s", cfg.httpPort)
+	handler := ratelimit.Handler(limiter, ratelimit.UsersIdentifier(users), httpapi.MakeHandler(svc))
 	if cfg.serverCert != "" || cfg.serverKey != "" {
 		logger.Info(fmt.Sprintf("Things service started using https on port %s with cert %s key %s",
 			cfg.httpPort, cfg.serverCert, cfg.serverKey))
-		errs <- http.ListenAndServeTLS(p, cfg.serverCert, cfg.serverKey, httpapi.MakeHandler(svc))
+		errs <- http.ListenAndServeTLS(p, cfg.serverCert, cfg.serverKey, handler)
 		return
 	}
 	logger.Info(fmt.Sprintf("Things service started using http on port %s", cfg.httpPort))
-	errs <- http.ListenAndServe(p, httpapi.MakeHandler(svc))
+	errs <- http.ListenAndServe(p, handler)
 }
 
 func startGRPCServer(svc things.Service, cfg config, logger logger.Logger, errs chan error) {
@@ -292,7 +581,7 @@ func startGRPCServer(svc things.Service, cfg config, logger logger.Logger, errs
 
 	var server *grpc.Server
 	if cfg.serverCert != "" || cfg.serverKey != "" {
-		creds, err := credentials.NewServerTLSFromFile(cfg.serverCert, cfg.serverKey)
+		creds, err := mtls.ServerCredentials(cfg.serverCert, cfg.serverKey, cfg.serverCACerts, cfg.serverIDs)
 		if err != nil {
 			logger.Error(fmt.Sprintf("Failed to load things certificates: %s", err))
 			os.Exit(1)
@@ -308,3 +597,26 @@ func startGRPCServer(svc things.Service, cfg config, logger logger.Logger, errs
 	mainflux.RegisterThingsServiceServer(server, grpcapi.NewServer(svc))
 	errs <- server.Serve(listener)
 }
+
+func subscribeToUsersES(svc things.Service, client redis.UniversalClient, consumer string, logger logger.Logger) {
+	eventStore := rediscache.NewUsersEventStore(svc, client, consumer, logger)
+	logger.Info("Subscribed to Redis Event Store")
+	if err := eventStore.Subscribe("mainflux.users"); err != nil {
+		logger.Warn(fmt.Sprintf("Things service failed to subscribe to event sourcing: %s", err))
+	}
+}
+
+// rotateKeys periodically rotates the expired thing keys. Each run covers
+// the keys expired since the previous one, so that they are evicted from
+// the cache once.
+func rotateKeys(svc things.Service, interval time.Duration, logger logger.Logger) {
+	since := time.Now().Add(-interval)
+	for range time.Tick(interval) {
+		now := time.Now()
+		if _, err := svc.RotateKeys(context.Background(), since); err != nil {
+			logger.Warn(fmt.Sprintf("Failed to rotate thing keys: %s", err))
+			continue
+		}
+		since = now
+	}
+}
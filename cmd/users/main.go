@@ -8,27 +8,36 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
-
-	"google.golang.org/grpc/credentials"
+	"time"
 
 	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	"github.com/go-redis/redis"
 	"github.com/jmoiron/sqlx"
 	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/mtls"
+	"github.com/mainflux/mainflux/ratelimit"
+	mfredis "github.com/mainflux/mainflux/redis"
 	"github.com/mainflux/mainflux/users"
 	"github.com/mainflux/mainflux/users/api"
 	grpcapi "github.com/mainflux/mainflux/users/api/grpc"
 	httpapi "github.com/mainflux/mainflux/users/api/http"
 	"github.com/mainflux/mainflux/users/bcrypt"
 	"github.com/mainflux/mainflux/users/jwt"
+	"github.com/mainflux/mainflux/users/ldap"
 	"github.com/mainflux/mainflux/users/postgres"
+	redisprod "github.com/mainflux/mainflux/users/redis"
+	"github.com/mainflux/mainflux/users/smtp"
 	stdprometheus "github.com/prometheus/client_golang/prometheus"
 	"google.golang.org/grpc"
 )
@@ -44,11 +53,35 @@ const (
 	defDBSSLCert     = ""
 	defDBSSLKey      = ""
 	defDBSSLRootCert = ""
+	defDBTarget      = "read-write"
+	defDBSyncCommit  = ""
+	defESURL         = "localhost:6379"
+	defESPass        = ""
+	defESDB          = "0"
 	defHTTPPort      = "8180"
 	defGRPCPort      = "8181"
 	defSecret        = "users"
 	defServerCert    = ""
 	defServerKey     = ""
+	defServerCACerts = ""
+	defServerIDs     = ""
+	defAdmins        = ""
+	defImpersonation = "15m"
+	defLDAPURL       = ""
+	defLDAPBindDN    = ""
+	defLDAPBindPass  = ""
+	defLDAPBaseDN    = ""
+	defLDAPEmailAttr = "mail"
+	defLDAPGroupAttr = "memberOf"
+	defLDAPAdmins    = ""
+	defRateLimit     = "0"
+	defRateWindow    = "1m"
+	defSMTPHost      = ""
+	defSMTPPort      = "25"
+	defSMTPUser      = ""
+	defSMTPPass      = ""
+	defSMTPFrom      = "users@mainflux.com"
+	defResetURL      = ""
 	envLogLevel      = "MF_USERS_LOG_LEVEL"
 	envDBHost        = "MF_USERS_DB_HOST"
 	envDBPort        = "MF_USERS_DB_PORT"
@@ -59,21 +92,57 @@ const (
 	envDBSSLCert     = "MF_USERS_DB_SSL_CERT"
 	envDBSSLKey      = "MF_USERS_DB_SSL_KEY"
 	envDBSSLRootCert = "MF_USERS_DB_SSL_ROOT_CERT"
+	envDBTarget      = "MF_USERS_DB_TARGET"
+	envDBSyncCommit  = "MF_USERS_DB_SYNC_COMMIT"
+	envESURL         = "MF_USERS_ES_URL"
+	envESPass        = "MF_USERS_ES_PASS"
+	envESDB          = "MF_USERS_ES_DB"
 	envHTTPPort      = "MF_USERS_HTTP_PORT"
 	envGRPCPort      = "MF_USERS_GRPC_PORT"
 	envSecret        = "MF_USERS_SECRET"
 	envServerCert    = "MF_USERS_SERVER_CERT"
 	envServerKey     = "MF_USERS_SERVER_KEY"
+	envServerCACerts = "MF_USERS_SERVER_CA_CERTS"
+	envServerIDs     = "MF_USERS_SERVER_CLIENT_IDS"
+	envAdmins        = "MF_USERS_ADMINS"
+	envImpersonation = "MF_USERS_IMPERSONATION_DURATION"
+	envLDAPURL       = "MF_USERS_LDAP_URL"
+	envLDAPBindDN    = "MF_USERS_LDAP_BIND_DN"
+	envLDAPBindPass  = "MF_USERS_LDAP_BIND_PASSWORD"
+	envLDAPBaseDN    = "MF_USERS_LDAP_BASE_DN"
+	envLDAPEmailAttr = "MF_USERS_LDAP_EMAIL_ATTR"
+	envLDAPGroupAttr = "MF_USERS_LDAP_GROUPS_ATTR"
+	envLDAPAdmins    = "MF_USERS_LDAP_ADMIN_GROUPS"
+	envRateLimit     = "MF_USERS_API_RATE_LIMIT"
+	envRateWindow    = "MF_USERS_API_RATE_WINDOW"
+	envSMTPHost      = "MF_USERS_SMTP_HOST"
+	envSMTPPort      = "MF_USERS_SMTP_PORT"
+	envSMTPUser      = "MF_USERS_SMTP_USER"
+	envSMTPPass      = "MF_USERS_SMTP_PASS"
+	envSMTPFrom      = "MF_USERS_SMTP_FROM"
+	envResetURL      = "MF_USERS_RESET_URL"
 )
 
 type config struct {
-	logLevel   string
-	dbConfig   postgres.Config
-	httpPort   string
-	grpcPort   string
-	secret     string
-	serverCert string
-	serverKey  string
+	logLevel      string
+	dbConfig      postgres.Config
+	esURL         string
+	esPass        string
+	esDB          string
+	httpPort      string
+	grpcPort      string
+	secret        string
+	serverCert    string
+	serverKey     string
+	serverCACerts string
+	serverIDs     []string
+	admins        []string
+	impersonation time.Duration
+	ldapConfig    ldap.Config
+	ldapAdmins    []string
+	rateLimit     uint64
+	rateWindow    time.Duration
+	smtpConfig    smtp.Config
 }
 
 func main() {
@@ -83,14 +152,16 @@ func main() {
 	if err != nil {
 		log.Fatalf(err.Error())
 	}
+	esClient := connectToRedis(cfg.esURL, cfg.esPass, cfg.esDB, logger)
+
 	db := connectToDB(cfg.dbConfig, logger)
 	defer db.Close()
 
-	svc := newService(db, cfg.secret, logger)
+	svc := newService(db, esClient, cfg, logger)
 	errs := make(chan error, 2)
 
-	go startHTTPServer(svc, cfg.httpPort, cfg.serverCert, cfg.serverKey, logger, errs)
-	go startGRPCServer(svc, cfg.grpcPort, cfg.serverCert, cfg.serverKey, logger, errs)
+	go startHTTPServer(svc, newRateLimiter(cfg, esClient), cfg.httpPort, cfg.serverCert, cfg.serverKey, logger, errs)
+	go startGRPCServer(svc, cfg, logger, errs)
 
 	go func() {
 		c := make(chan os.Signal)
@@ -114,17 +185,74 @@ func loadConfig() config {
 		SSLCert:     mainflux.Env(envDBSSLCert, defDBSSLCert),
 		SSLKey:      mainflux.Env(envDBSSLKey, defDBSSLKey),
 		SSLRootCert: mainflux.Env(envDBSSLRootCert, defDBSSLRootCert),
+		Target:      mainflux.Env(envDBTarget, defDBTarget),
+		SyncCommit:  mainflux.Env(envDBSyncCommit, defDBSyncCommit),
+	}
+
+	ldapConfig := ldap.Config{
+		URL:          mainflux.Env(envLDAPURL, defLDAPURL),
+		BindDN:       mainflux.Env(envLDAPBindDN, defLDAPBindDN),
+		BindPassword: mainflux.Env(envLDAPBindPass, defLDAPBindPass),
+		BaseDN:       mainflux.Env(envLDAPBaseDN, defLDAPBaseDN),
+		EmailAttr:    mainflux.Env(envLDAPEmailAttr, defLDAPEmailAttr),
+		GroupsAttr:   mainflux.Env(envLDAPGroupAttr, defLDAPGroupAttr),
+	}
+
+	smtpConfig := smtp.Config{
+		Host:     mainflux.Env(envSMTPHost, defSMTPHost),
+		Port:     mainflux.Env(envSMTPPort, defSMTPPort),
+		Username: mainflux.Env(envSMTPUser, defSMTPUser),
+		Password: mainflux.Env(envSMTPPass, defSMTPPass),
+		From:     mainflux.Env(envSMTPFrom, defSMTPFrom),
+		ResetURL: mainflux.Env(envResetURL, defResetURL),
+	}
+
+	impersonation, err := time.ParseDuration(mainflux.Env(envImpersonation, defImpersonation))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envImpersonation)
+	}
+
+	rateLimit, err := strconv.ParseUint(mainflux.Env(envRateLimit, defRateLimit), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envRateLimit)
+	}
+
+	rateWindow, err := time.ParseDuration(mainflux.Env(envRateWindow, defRateWindow))
+	if err != nil || rateWindow <= 0 {
+		log.Fatalf("Invalid value passed for %s\n", envRateWindow)
 	}
 
 	return config{
-		logLevel:   mainflux.Env(envLogLevel, defLogLevel),
-		dbConfig:   dbConfig,
-		httpPort:   mainflux.Env(envHTTPPort, defHTTPPort),
-		grpcPort:   mainflux.Env(envGRPCPort, defGRPCPort),
-		secret:     mainflux.Env(envSecret, defSecret),
-		serverCert: mainflux.Env(envServerCert, defServerCert),
-		serverKey:  mainflux.Env(envServerKey, defServerKey),
+		logLevel:      mainflux.Env(envLogLevel, defLogLevel),
+		dbConfig:      dbConfig,
+		esURL:         mainflux.Env(envESURL, defESURL),
+		esPass:        mainflux.Env(envESPass, defESPass),
+		esDB:          mainflux.Env(envESDB, defESDB),
+		httpPort:      mainflux.Env(envHTTPPort, defHTTPPort),
+		grpcPort:      mainflux.Env(envGRPCPort, defGRPCPort),
+		secret:        mainflux.Env(envSecret, defSecret),
+		serverCert:    mainflux.Env(envServerCert, defServerCert),
+		serverKey:     mainflux.Env(envServerKey, defServerKey),
+		serverCACerts: mainflux.Env(envServerCACerts, defServerCACerts),
+		serverIDs:     strings.Split(mainflux.Env(envServerIDs, defServerIDs), ","),
+		admins:        strings.Split(mainflux.Env(envAdmins, defAdmins), ","),
+		impersonation: impersonation,
+		ldapConfig:    ldapConfig,
+		ldapAdmins:    strings.Split(mainflux.Env(envLDAPAdmins, defLDAPAdmins), ";"),
+		rateLimit:     rateLimit,
+		rateWindow:    rateWindow,
+		smtpConfig:    smtpConfig,
+	}
+}
+
+func connectToRedis(esURL, esPass string, esDB string, logger logger.Logger) redis.UniversalClient {
+	client, err := mfredis.Connect(esURL, esPass, esDB)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to event store: %s", err))
+		os.Exit(1)
 	}
+
+	return client
 }
 
 func connectToDB(dbConfig postgres.Config, logger logger.Logger) *sqlx.DB {
@@ -136,12 +264,26 @@ func connectToDB(dbConfig postgres.Config, logger logger.Logger) *sqlx.DB {
 	return db
 }
 
-func newService(db *sqlx.DB, secret string, logger logger.Logger) users.Service {
+func newService(db *sqlx.DB, esClient redis.UniversalClient, cfg config, logger logger.Logger) users.Service {
 	repo := postgres.New(db)
 	hasher := bcrypt.New()
-	idp := jwt.New(secret)
+	idp := jwt.New(cfg.secret)
+
+	var dir users.Directory
+	if cfg.ldapConfig.URL != "" {
+		dir = ldap.New(cfg.ldapConfig)
+		logger.Info(fmt.Sprintf("Users not having local accounts are authenticated against %s", cfg.ldapConfig.URL))
+	}
+
+	var mailer users.Mailer
+	if cfg.smtpConfig.Host != "" {
+		mailer = smtp.New(cfg.smtpConfig)
+	} else {
+		logger.Info("SMTP host is not set, password reset is disabled")
+	}
 
-	svc := users.New(repo, hasher, idp)
+	svc := users.NewWithDirectory(repo, postgres.NewSessionRepository(db), postgres.NewResetTokenRepository(db), hasher, idp, mailer, dir, cfg.admins, cfg.ldapAdmins, cfg.impersonation)
+	svc = redisprod.NewEventStoreMiddleware(svc, esClient)
 	svc = api.LoggingMiddleware(svc, logger)
 	svc = api.MetricsMiddleware(
 		svc,
@@ -161,39 +303,51 @@ func newService(db *sqlx.DB, secret string, logger logger.Logger) users.Service
 	return svc
 }
 
-func startHTTPServer(svc users.Service, port string, certFile string, keyFile string, logger logger.Logger, errs chan error) {
+func newRateLimiter(cfg config, client redis.UniversalClient) ratelimit.Limiter {
+	if cfg.rateLimit == 0 {
+		return nil
+	}
+
+	return ratelimit.NewLimiter(client, "users", cfg.rateLimit, cfg.rateWindow)
+}
+
+func startHTTPServer(svc users.Service, limiter ratelimit.Limiter, port string, certFile string, keyFile string, logger logger.Logger, errs chan error) {
 	p := fmt.Sprintf(":%s", port)
+	identify := func(_ context.Context, token string) (string, error) {
+		return svc.Identify(token)
+	}
+	handler := ratelimit.Handler(limiter, identify, httpapi.MakeHandler(svc, logger))
 	if certFile != "" || keyFile != "" {
 		logger.Info(fmt.Sprintf("Users service started using https, cert %s key %s, exposed port %s", certFile, keyFile, port))
-		errs <- http.ListenAndServeTLS(p, certFile, keyFile, httpapi.MakeHandler(svc, logger))
+		errs <- http.ListenAndServeTLS(p, certFile, keyFile, handler)
 	} else {
 		logger.Info(fmt.Sprintf("Users service started using http, exposed port %s", port))
-		errs <- http.ListenAndServe(p, httpapi.MakeHandler(svc, logger))
+		errs <- http.ListenAndServe(p, handler)
 	}
 }
 
-func startGRPCServer(svc users.Service, port string, certFile string, keyFile string, logger logger.Logger, errs chan error) {
-	p := fmt.Sprintf(":%s", port)
+func startGRPCServer(svc users.Service, cfg config, logger logger.Logger, errs chan error) {
+	p := fmt.Sprintf(":%s", cfg.grpcPort)
 	listener, err := net.Listen("tcp", p)
 	if err != nil {
-		logger.Error(fmt.Sprintf("Failed to listen on port %s: %s", port, err))
+		logger.Error(fmt.Sprintf("Failed to listen on port %s: %s", cfg.grpcPort, err))
 	}
 
 	var server *grpc.Server
-	if certFile != "" || keyFile != "" {
-		creds, err := credentials.NewServerTLSFromFile(certFile, keyFile)
+	if cfg.serverCert != "" || cfg.serverKey != "" {
+		creds, err := mtls.ServerCredentials(cfg.serverCert, cfg.serverKey, cfg.serverCACerts, cfg.serverIDs)
 		if err != nil {
 			logger.Error(fmt.Sprintf("Failed to load users certificates: %s", err))
 			os.Exit(1)
 		}
-		logger.Info(fmt.Sprintf("Users gRPC service started using https on port %s with cert %s key %s", port, certFile, keyFile))
+		logger.Info(fmt.Sprintf("Users gRPC service started using https on port %s with cert %s key %s", cfg.grpcPort, cfg.serverCert, cfg.serverKey))
 		server = grpc.NewServer(grpc.Creds(creds))
 	} else {
-		logger.Info(fmt.Sprintf("Users gRPC service started using http on port %s", port))
+		logger.Info(fmt.Sprintf("Users gRPC service started using http on port %s", cfg.grpcPort))
 		server = grpc.NewServer()
 	}
 
 	mainflux.RegisterUsersServiceServer(server, grpcapi.NewServer(svc))
-	logger.Info(fmt.Sprintf("Users gRPC service started, exposed port %s", port))
+	logger.Info(fmt.Sprintf("Users gRPC service started, exposed port %s", cfg.grpcPort))
 	errs <- server.Serve(listener)
 }
@@ -15,42 +15,87 @@ import (
 	"os/signal"
 	"strconv"
 	"syscall"
+	"time"
 
 	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	r "github.com/go-redis/redis"
 	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/mtls"
+	"github.com/mainflux/mainflux/publish"
+	mfredis "github.com/mainflux/mainflux/redis"
+	"github.com/mainflux/mainflux/things"
 	thingsapi "github.com/mainflux/mainflux/things/api/grpc"
+	"github.com/mainflux/mainflux/things/jwt"
+	thingsnats "github.com/mainflux/mainflux/things/nats"
 	adapter "github.com/mainflux/mainflux/ws"
 	"github.com/mainflux/mainflux/ws/api"
 	"github.com/mainflux/mainflux/ws/nats"
 	broker "github.com/nats-io/go-nats"
 	stdprometheus "github.com/prometheus/client_golang/prometheus"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
 )
 
+const revocationsTimeout = 5 * time.Second
+
 const (
-	defClientTLS = "false"
-	defCACerts   = ""
-	defPort      = "8180"
-	defLogLevel  = "error"
-	defNatsURL   = broker.DefaultURL
-	defThingsURL = "localhost:8181"
-	envClientTLS = "MF_WS_ADAPTER_CLIENT_TLS"
-	envCACerts   = "MF_WS_ADAPTER_CA_CERTS"
-	envPort      = "MF_WS_ADAPTER_PORT"
-	envLogLevel  = "MF_WS_ADAPTER_LOG_LEVEL"
-	envNatsURL   = "MF_NATS_URL"
-	envThingsURL = "MF_THINGS_URL"
+	defClientTLS  = "false"
+	defCACerts    = ""
+	defClientCert = ""
+	defClientKey  = ""
+	defPort       = "8180"
+	defLogLevel   = "error"
+	defNatsURL    = broker.DefaultURL
+	defThingsURL  = "localhost:8181"
+	defKeysSecret = ""
+	defResumeWin  = "30s"
+	defResumeBuf  = "100"
+	defRateLimit  = "0"
+	defRateBurst  = "10"
+	defCacheURL   = ""
+	defCachePass  = ""
+	defCacheDB    = "0"
+	defAnonymize  = "false"
+	defAckTimeout = "5s"
+	envClientTLS  = "MF_WS_ADAPTER_CLIENT_TLS"
+	envCACerts    = "MF_WS_ADAPTER_CA_CERTS"
+	envClientCert = "MF_WS_ADAPTER_CLIENT_CERT"
+	envClientKey  = "MF_WS_ADAPTER_CLIENT_KEY"
+	envPort       = "MF_WS_ADAPTER_PORT"
+	envLogLevel   = "MF_WS_ADAPTER_LOG_LEVEL"
+	envNatsURL    = "MF_NATS_URL"
+	envThingsURL  = "MF_THINGS_URL"
+	envKeysSecret = "MF_THINGS_KEYS_SECRET"
+	envResumeWin  = "MF_WS_ADAPTER_RESUME_WINDOW"
+	envResumeBuf  = "MF_WS_ADAPTER_RESUME_BUFFER"
+	envRateLimit  = "MF_WS_ADAPTER_RATE_LIMIT"
+	envRateBurst  = "MF_WS_ADAPTER_RATE_BURST"
+	envCacheURL   = "MF_WS_ADAPTER_CACHE_URL"
+	envCachePass  = "MF_WS_ADAPTER_CACHE_PASS"
+	envCacheDB    = "MF_WS_ADAPTER_CACHE_DB"
+	envAnonymize  = "MF_WS_ADAPTER_ANONYMIZE_ADDR"
+	envAckTimeout = "MF_WS_ADAPTER_ACK_TIMEOUT"
 )
 
 type config struct {
-	clientTLS bool
-	caCerts   string
-	thingsURL string
-	natsURL   string
-	logLevel  string
-	port      string
+	clientTLS  bool
+	caCerts    string
+	clientCert string
+	clientKey  string
+	thingsURL  string
+	keysSecret string
+	natsURL    string
+	logLevel   string
+	port       string
+	resumeWin  time.Duration
+	resumeBuf  int
+	rateLimit  float64
+	rateBurst  uint
+	cacheURL   string
+	cachePass  string
+	cacheDB    string
+	anonymize  bool
+	ackTimeout time.Duration
 }
 
 func main() {
@@ -72,15 +117,28 @@ func main() {
 	defer conn.Close()
 
 	cc := thingsapi.NewClient(conn)
-	pubsub := nats.New(nc)
-	svc := newService(pubsub, logger)
+	var keys things.KeyProvider
+	if cfg.keysSecret != "" {
+		revocations, err := thingsnats.NewRevocationList(nc, revocationsTimeout, logger)
+		if err != nil {
+			logger.Error(fmt.Sprintf("Failed to retrieve revocations: %s", err))
+			os.Exit(1)
+		}
+		keys = jwt.NewKeyProvider(cfg.keysSecret)
+		cc = jwt.NewThingsClient(cc, keys, revocations)
+	}
+
+	pubsub := nats.New(nc, cfg.ackTimeout)
+	cache := connectToCache(cfg, logger)
+	svc := newService(pubsub, cache, cfg, logger)
+	sessions := adapter.NewSessions(cfg.resumeWin, cfg.resumeBuf)
 
 	errs := make(chan error, 2)
 
 	go func() {
 		p := fmt.Sprintf(":%s", cfg.port)
 		logger.Info(fmt.Sprintf("WebSocket adapter service started, exposed port %s", cfg.port))
-		errs <- http.ListenAndServe(p, api.MakeHandler(svc, cc, logger))
+		errs <- http.ListenAndServe(p, api.MakeHandler(svc, sessions, cc, keys, logger))
 	}()
 
 	go func() {
@@ -99,27 +157,81 @@ func loadConfig() config {
 		log.Fatalf("Invalid value passed for %s\n", envClientTLS)
 	}
 
+	win, err := time.ParseDuration(mainflux.Env(envResumeWin, defResumeWin))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envResumeWin)
+	}
+
+	buf, err := strconv.Atoi(mainflux.Env(envResumeBuf, defResumeBuf))
+	if err != nil || buf < 1 {
+		log.Fatalf("Invalid value passed for %s\n", envResumeBuf)
+	}
+
+	rateLimit, err := strconv.ParseFloat(mainflux.Env(envRateLimit, defRateLimit), 64)
+	if err != nil || rateLimit < 0 {
+		log.Fatalf("Invalid value passed for %s\n", envRateLimit)
+	}
+
+	rateBurst, err := strconv.ParseUint(mainflux.Env(envRateBurst, defRateBurst), 10, 32)
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envRateBurst)
+	}
+
+	anonymize, err := strconv.ParseBool(mainflux.Env(envAnonymize, defAnonymize))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envAnonymize)
+	}
+
+	ackTimeout, err := time.ParseDuration(mainflux.Env(envAckTimeout, defAckTimeout))
+	if err != nil || ackTimeout <= 0 {
+		log.Fatalf("Invalid value passed for %s\n", envAckTimeout)
+	}
+
 	return config{
-		clientTLS: tls,
-		caCerts:   mainflux.Env(envCACerts, defCACerts),
-		thingsURL: mainflux.Env(envThingsURL, defThingsURL),
-		natsURL:   mainflux.Env(envNatsURL, defNatsURL),
-		logLevel:  mainflux.Env(envLogLevel, defLogLevel),
-		port:      mainflux.Env(envPort, defPort),
+		clientTLS:  tls,
+		caCerts:    mainflux.Env(envCACerts, defCACerts),
+		clientCert: mainflux.Env(envClientCert, defClientCert),
+		clientKey:  mainflux.Env(envClientKey, defClientKey),
+		thingsURL:  mainflux.Env(envThingsURL, defThingsURL),
+		keysSecret: mainflux.Env(envKeysSecret, defKeysSecret),
+		natsURL:    mainflux.Env(envNatsURL, defNatsURL),
+		logLevel:   mainflux.Env(envLogLevel, defLogLevel),
+		port:       mainflux.Env(envPort, defPort),
+		resumeWin:  win,
+		resumeBuf:  buf,
+		rateLimit:  rateLimit,
+		rateBurst:  uint(rateBurst),
+		cacheURL:   mainflux.Env(envCacheURL, defCacheURL),
+		cachePass:  mainflux.Env(envCachePass, defCachePass),
+		cacheDB:    mainflux.Env(envCacheDB, defCacheDB),
+		anonymize:  anonymize,
+		ackTimeout: ackTimeout,
+	}
+}
+
+func connectToCache(cfg config, logger logger.Logger) r.UniversalClient {
+	if cfg.cacheURL == "" {
+		return nil
 	}
+
+	client, err := mfredis.Connect(cfg.cacheURL, cfg.cachePass, cfg.cacheDB)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to cache: %s", err))
+		os.Exit(1)
+	}
+
+	return client
 }
 
 func connectToThings(cfg config, logger logger.Logger) *grpc.ClientConn {
 	var opts []grpc.DialOption
 	if cfg.clientTLS {
-		if cfg.caCerts != "" {
-			tpc, err := credentials.NewClientTLSFromFile(cfg.caCerts, "")
-			if err != nil {
-				logger.Error(fmt.Sprintf("Failed to load certs: %s", err))
-				os.Exit(1)
-			}
-			opts = append(opts, grpc.WithTransportCredentials(tpc))
+		tpc, err := mtls.ClientCredentials(cfg.caCerts, cfg.clientCert, cfg.clientKey)
+		if err != nil {
+			logger.Error(fmt.Sprintf("Failed to load certs: %s", err))
+			os.Exit(1)
 		}
+		opts = append(opts, grpc.WithTransportCredentials(tpc))
 	} else {
 		logger.Info("gRPC communication is not encrypted")
 		opts = append(opts, grpc.WithInsecure())
@@ -133,8 +245,19 @@ func connectToThings(cfg config, logger logger.Logger) *grpc.ClientConn {
 	return conn
 }
 
-func newService(pubsub adapter.Service, logger logger.Logger) adapter.Service {
-	svc := adapter.New(pubsub)
+func newService(pubsub adapter.Service, cache r.UniversalClient, cfg config, logger logger.Logger) adapter.Service {
+	svc := adapter.New(
+		pubsub,
+		publish.Ingress(cfg.anonymize),
+		publish.Validate(0),
+		publish.RateLimit(cfg.rateLimit, cfg.rateBurst),
+		publish.ChannelRateLimit(cache),
+		publish.ChannelProtocols(cache),
+		publish.Transform(cache),
+		publish.ContentType(publish.SenMLJSON),
+		publish.Delivery(cache),
+		publish.Sequence(cache),
+	)
 	svc = api.LoggingMiddleware(svc, logger)
 	svc = api.MetricsMiddleware(
 		svc,
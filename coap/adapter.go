@@ -16,6 +16,7 @@ import (
 	"time"
 
 	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/publish"
 	broker "github.com/nats-io/go-nats"
 )
 
@@ -36,6 +37,10 @@ var (
 	// ErrFailedMessagePublish indicates that message publishing failed.
 	ErrFailedMessagePublish = errors.New("failed to publish message")
 
+	// ErrPublishTimeout indicates that message broker didn't confirm the
+	// message in time.
+	ErrPublishTimeout = errors.New("message publish timed out")
+
 	// ErrFailedSubscription indicates that client couldn't subscribe to specified channel.
 	ErrFailedSubscription = errors.New("failed to subscribe to a channel")
 
@@ -63,17 +68,20 @@ var _ Service = (*adapterService)(nil)
 
 type adapterService struct {
 	pubsub  Broker
+	pub     mainflux.MessagePublisher
 	obs     map[string]*Observer
 	obsLock sync.Mutex
 }
 
-// New instantiates the CoAP adapter implementation.
-func New(pubsub Broker, responses <-chan string) Service {
+// New instantiates the CoAP adapter implementation. Messages are passed
+// through the publish hooks, in the given order, before being published.
+func New(pubsub Broker, responses <-chan string, hooks ...mainflux.PublishHook) Service {
 	as := &adapterService{
 		pubsub:  pubsub,
 		obs:     make(map[string]*Observer),
 		obsLock: sync.Mutex{},
 	}
+	as.pub = mainflux.Chain(mainflux.PublisherFunc(as.publish), hooks...)
 
 	go as.listenResponses(responses)
 	return as
@@ -123,10 +131,16 @@ func (svc *adapterService) listenResponses(responses <-chan string) {
 }
 
 func (svc *adapterService) Publish(msg mainflux.RawMessage) error {
+	return svc.pub.Publish(msg)
+}
+
+func (svc *adapterService) publish(msg mainflux.RawMessage) error {
 	if err := svc.pubsub.Publish(msg); err != nil {
 		switch err {
 		case broker.ErrConnectionClosed, broker.ErrInvalidConnection:
 			return ErrFailedConnection
+		case broker.ErrTimeout, publish.ErrNotAcknowledged:
+			return ErrPublishTimeout
 		default:
 			return ErrFailedMessagePublish
 		}
@@ -0,0 +1,130 @@
+//
+// Copyright (c) 2018
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	gocoap "github.com/dustin/go-coap"
+)
+
+const (
+	// NoSecProfile allows NoSec access from any network.
+	NoSecProfile = "nosec"
+
+	// DTLSProfile allows NoSec access only from the configured networks.
+	// All the other clients must reach the adapter through a DTLS
+	// terminating proxy.
+	DTLSProfile = "dtls"
+)
+
+var (
+	errUnknownProfile = errors.New("unknown security profile")
+	errInvalidNetwork = errors.New("invalid network")
+)
+
+// SecurityProfile represents the adapter security profile. Since the adapter
+// itself serves plain CoAP only, DTLS is expected to be terminated by a proxy
+// running on one of the DTLS proxy networks.
+type SecurityProfile struct {
+	Mode          string
+	NoSecNetworks []*net.IPNet
+	DTLSProxies   []*net.IPNet
+}
+
+// NewSecurityProfile creates security profile using the provided mode and
+// lists of networks in CIDR notation.
+func NewSecurityProfile(mode string, noSecNetworks, dtlsProxies []string) (SecurityProfile, error) {
+	if mode != NoSecProfile && mode != DTLSProfile {
+		return SecurityProfile{}, errUnknownProfile
+	}
+
+	nosec, err := parseNetworks(noSecNetworks)
+	if err != nil {
+		return SecurityProfile{}, err
+	}
+
+	proxies, err := parseNetworks(dtlsProxies)
+	if err != nil {
+		return SecurityProfile{}, err
+	}
+
+	return SecurityProfile{
+		Mode:          mode,
+		NoSecNetworks: nosec,
+		DTLSProxies:   proxies,
+	}, nil
+}
+
+func parseNetworks(cidrs []string) ([]*net.IPNet, error) {
+	networks := []*net.IPNet{}
+	for _, cidr := range cidrs {
+		cidr = strings.TrimSpace(cidr)
+		if cidr == "" {
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, errInvalidNetwork
+		}
+		networks = append(networks, network)
+	}
+
+	return networks, nil
+}
+
+func (sp SecurityProfile) secure(ip net.IP) bool {
+	return contains(sp.DTLSProxies, ip)
+}
+
+func (sp SecurityProfile) allowNoSec(ip net.IP) bool {
+	return sp.Mode == NoSecProfile || contains(sp.NoSecNetworks, ip)
+}
+
+func contains(networks []*net.IPNet, ip net.IP) bool {
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// gate rejects NoSec access from networks that are not allowed by the
+// security profile. Every insecure access is written to the audit log.
+func gate(h handler) handler {
+	return func(conn *net.UDPConn, addr *net.UDPAddr, msg *gocoap.Message) *gocoap.Message {
+		if profile.secure(addr.IP) {
+			return h(conn, addr, msg)
+		}
+
+		path := msg.PathString()
+		if !profile.allowNoSec(addr.IP) {
+			logger.Warn(fmt.Sprintf("Audit: rejected NoSec access to %s from %s", path, addr))
+			if !msg.IsConfirmable() {
+				return nil
+			}
+
+			return &gocoap.Message{
+				Type:      gocoap.Acknowledgement,
+				Code:      gocoap.Unauthorized,
+				MessageID: msg.MessageID,
+				Token:     msg.Token,
+				Payload:   []byte{},
+			}
+		}
+
+		logger.Info(fmt.Sprintf("Audit: NoSec access to %s from %s", path, addr))
+		return h(conn, addr, msg)
+	}
+}
@@ -14,14 +14,18 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math/rand"
 	"net"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-zoo/bone"
 	"github.com/mainflux/mainflux/coap"
+	"github.com/mainflux/mainflux/identifiers"
 	log "github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/publish"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -31,7 +35,13 @@ import (
 	"github.com/mainflux/mainflux"
 )
 
-const protocol = "coap"
+const (
+	protocol = "coap"
+
+	// separateAfter is the time to wait for the broker to confirm the
+	// message before the request is acknowledged separately.
+	separateAfter = coap.AckTimeout / 2
+)
 
 var (
 	errBadRequest        = errors.New("bad request")
@@ -43,6 +53,11 @@ var (
 	auth       mainflux.ThingsServiceClient
 	logger     log.Logger
 	pingPeriod time.Duration
+	profile    SecurityProfile
+
+	// Message ID of separate responses.
+	msgID     = uint16(rand.Uint32())
+	msgIDLock sync.Mutex
 )
 
 type handler func(conn *net.UDPConn, addr *net.UDPAddr, msg *gocoap.Message) *gocoap.Message
@@ -67,21 +82,22 @@ func MakeHTTPHandler() http.Handler {
 }
 
 // MakeCOAPHandler creates handler for CoAP messages.
-func MakeCOAPHandler(svc coap.Service, tc mainflux.ThingsServiceClient, l log.Logger, responses chan<- string, pp time.Duration) gocoap.Handler {
+func MakeCOAPHandler(svc coap.Service, tc mainflux.ThingsServiceClient, l log.Logger, responses chan<- string, pp time.Duration, sp SecurityProfile) gocoap.Handler {
 	auth = tc
 	logger = l
 	pingPeriod = pp
+	profile = sp
 	r := mux.NewRouter()
-	r.Handle("/channels/{id}/messages", gocoap.FuncHandler(receive(svc))).Methods(gocoap.POST)
-	r.Handle("/channels/{id}/messages/{subtopic:[^?]*}", gocoap.FuncHandler(receive(svc))).Methods(gocoap.POST)
-	r.Handle("/channels/{id}/messages", gocoap.FuncHandler(observe(svc, responses)))
-	r.Handle("/channels/{id}/messages/{subtopic:[^?]*}", gocoap.FuncHandler(observe(svc, responses)))
+	r.Handle("/channels/{id}/messages", gocoap.FuncHandler(gate(receive(svc)))).Methods(gocoap.POST)
+	r.Handle("/channels/{id}/messages/{subtopic:[^?]*}", gocoap.FuncHandler(gate(receive(svc)))).Methods(gocoap.POST)
+	r.Handle("/channels/{id}/messages", gocoap.FuncHandler(gate(observe(svc, responses))))
+	r.Handle("/channels/{id}/messages/{subtopic:[^?]*}", gocoap.FuncHandler(gate(observe(svc, responses))))
 	r.NotFoundHandler = gocoap.FuncHandler(notFoundHandler)
 
 	return r
 }
 
-func authorize(msg *gocoap.Message, res *gocoap.Message, cid string) (string, error) {
+func authorize(msg *gocoap.Message, res *gocoap.Message, cid, subtopic, action string) (string, error) {
 	// Device Key is passed as Uri-Query parameter, which option ID is 15 (0xf).
 	key, err := authKey(msg.Option(gocoap.URIQuery))
 	if err != nil {
@@ -98,7 +114,7 @@ func authorize(msg *gocoap.Message, res *gocoap.Message, cid string) (string, er
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 	defer cancel()
 
-	id, err := auth.CanAccess(ctx, &mainflux.AccessReq{Token: key, ChanID: cid})
+	id, err := auth.CanAccess(ctx, &mainflux.AccessReq{Token: key, ChanID: cid, Action: action, Subtopic: subtopic})
 
 	if err != nil {
 		e, ok := status.FromError(err)
@@ -172,34 +188,99 @@ func receive(svc coap.Service) handler {
 			return res
 		}
 
+		chanID, err := identifiers.Parse(chanID)
+		if err != nil {
+			res.Code = gocoap.BadRequest
+			return res
+		}
+
 		subtopic, err := fmtSubtopic(mux.Var(msg, "subtopic"))
 		if err != nil {
 			res.Code = gocoap.BadRequest
 			return res
 		}
 
-		publisher, err := authorize(msg, res, chanID)
+		publisher, err := authorize(msg, res, chanID, subtopic, mainflux.ActionPublish)
 		if err != nil {
 			res.Code = gocoap.Forbidden
 			return res
 		}
 
 		rawMsg := mainflux.RawMessage{
-			Channel:   chanID,
-			Subtopic:  subtopic,
-			Publisher: publisher,
-			Protocol:  protocol,
-			Payload:   msg.Payload,
+			Channel:    chanID,
+			Subtopic:   subtopic,
+			Publisher:  publisher,
+			Protocol:   protocol,
+			Payload:    msg.Payload,
+			RemoteAddr: addr.String(),
 		}
 
-		if err := svc.Publish(rawMsg); err != nil {
-			res.Code = gocoap.InternalServerError
+		if !msg.IsConfirmable() {
+			res.Code = publishCode(svc.Publish(rawMsg))
+			return res
+		}
+
+		done := make(chan error, 1)
+		go func() {
+			done <- svc.Publish(rawMsg)
+		}()
+
+		t := time.NewTimer(separateAfter)
+		defer t.Stop()
+
+		select {
+		case err := <-done:
+			res.Code = publishCode(err)
+			return res
+		case <-t.C:
 		}
 
+		// The broker did not confirm the message before the client would
+		// retransmit the request, so the request is acknowledged with an
+		// empty ACK and the result is sent back as a separate response.
+		// See https://tools.ietf.org/html/rfc7252#section-5.2.2.
+		ack := gocoap.Message{
+			Type:      gocoap.Acknowledgement,
+			MessageID: msg.MessageID,
+		}
+		if err := gocoap.Transmit(conn, addr, ack); err != nil {
+			logger.Warn(fmt.Sprintf("Failed to acknowledge message: %s", err))
+		}
+
+		res.Type = gocoap.Confirmable
+		res.MessageID = nextMessageID()
+		res.Code = publishCode(<-done)
 		return res
 	}
 }
 
+func publishCode(err error) gocoap.COAPCode {
+	switch err {
+	case nil:
+		return gocoap.Changed
+	case publish.ErrMalformedMessage, publish.ErrTransformFailed:
+		return gocoap.BadRequest
+	case publish.ErrMessageTooLarge:
+		return gocoap.RequestEntityTooLarge
+	case publish.ErrProtocolNotAllowed:
+		return gocoap.Forbidden
+	case publish.ErrRateLimited, coap.ErrFailedConnection, coap.ErrFailedMessagePublish:
+		return gocoap.ServiceUnavailable
+	case coap.ErrPublishTimeout:
+		return gocoap.GatewayTimeout
+	default:
+		return gocoap.InternalServerError
+	}
+}
+
+func nextMessageID() uint16 {
+	msgIDLock.Lock()
+	defer msgIDLock.Unlock()
+
+	msgID++
+	return msgID
+}
+
 func observe(svc coap.Service, responses chan<- string) handler {
 	return func(conn *net.UDPConn, addr *net.UDPAddr, msg *gocoap.Message) *gocoap.Message {
 		res := &gocoap.Message{
@@ -217,13 +298,19 @@ func observe(svc coap.Service, responses chan<- string) handler {
 			return res
 		}
 
+		chanID, err := identifiers.Parse(chanID)
+		if err != nil {
+			res.Code = gocoap.BadRequest
+			return res
+		}
+
 		subtopic, err := fmtSubtopic(mux.Var(msg, "subtopic"))
 		if err != nil {
 			res.Code = gocoap.BadRequest
 			return res
 		}
 
-		publisher, err := authorize(msg, res, chanID)
+		publisher, err := authorize(msg, res, chanID, subtopic, mainflux.ActionSubscribe)
 		if err != nil {
 			res.Code = gocoap.Forbidden
 			logger.Warn(fmt.Sprintf("Failed to authorize: %s", err))
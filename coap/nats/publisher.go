@@ -10,10 +10,12 @@ package nats
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/gogo/protobuf/proto"
 	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/coap"
+	"github.com/mainflux/mainflux/publish"
 	broker "github.com/nats-io/go-nats"
 )
 
@@ -22,12 +24,19 @@ const prefix = "channel"
 var _ mainflux.MessagePublisher = (*natsPublisher)(nil)
 
 type natsPublisher struct {
-	nc *broker.Conn
+	nc      *broker.Conn
+	timeout time.Duration
 }
 
-// New instantiates NATS message publisher.
-func New(nc *broker.Conn) coap.Broker {
-	return &natsPublisher{nc}
+// New instantiates NATS message publisher. Every published message is
+// confirmed by a round trip to the broker or, if its channel has the
+// at-least-once delivery, acknowledged, which has to complete within the
+// given timeout.
+func New(nc *broker.Conn, timeout time.Duration) coap.Broker {
+	return &natsPublisher{
+		nc:      nc,
+		timeout: timeout,
+	}
 }
 
 func (pubsub *natsPublisher) fmtSubject(chanID, subtopic string) string {
@@ -39,13 +48,23 @@ func (pubsub *natsPublisher) fmtSubject(chanID, subtopic string) string {
 }
 
 func (pubsub *natsPublisher) Publish(msg mainflux.RawMessage) error {
+	subject := pubsub.fmtSubject(msg.Channel, msg.Subtopic)
+	if msg.Delivery == mainflux.DeliveryAtLeastOnce {
+		return publish.Deliver(pubsub.nc, subject, msg, pubsub.timeout)
+	}
+
 	data, err := proto.Marshal(&msg)
 	if err != nil {
 		return err
 	}
 
-	subject := pubsub.fmtSubject(msg.Channel, msg.Subtopic)
-	return pubsub.nc.Publish(subject, data)
+	if err := pubsub.nc.Publish(subject, data); err != nil {
+		return err
+	}
+
+	// Broker processes the messages of a single connection in order, so
+	// once the flush completes, the message is accepted by the broker.
+	return pubsub.nc.FlushTimeout(pubsub.timeout)
 }
 
 func (pubsub *natsPublisher) Subscribe(chanID, subtopic, obsID string, observer *coap.Observer) error {
@@ -0,0 +1,115 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package compression
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"errors"
+	"io/ioutil"
+	"strings"
+
+	"github.com/golang/snappy"
+)
+
+const (
+	// Snappy compresses the payloads fast, at a moderate ratio.
+	Snappy = "snappy"
+
+	// Gzip compresses the payloads at a higher ratio, at the cost of CPU.
+	Gzip = "gzip"
+
+	// marker starts every compressed value. It's followed by the algorithm
+	// and the base64 encoded compressed value, since the compressed bytes
+	// can't be stored in the text columns as they are.
+	marker = "\x01"
+	sep    = ":"
+)
+
+// ErrUnsupported indicates the unknown compression algorithm.
+var ErrUnsupported = errors.New("unsupported compression algorithm")
+
+// Validate returns ErrUnsupported if the algorithm isn't supported.
+func Validate(alg string) error {
+	switch alg {
+	case Snappy, Gzip:
+		return nil
+	default:
+		return ErrUnsupported
+	}
+}
+
+// Compress compresses the value using the given algorithm. The value is
+// returned as is if compressing it doesn't make it shorter.
+func Compress(alg, value string) (string, error) {
+	var data []byte
+	switch alg {
+	case Snappy:
+		data = snappy.Encode(nil, []byte(value))
+	case Gzip:
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write([]byte(value)); err != nil {
+			return "", err
+		}
+		if err := w.Close(); err != nil {
+			return "", err
+		}
+		data = buf.Bytes()
+	default:
+		return "", ErrUnsupported
+	}
+
+	compressed := marker + alg + sep + base64.StdEncoding.EncodeToString(data)
+	if len(compressed) >= len(value) {
+		return value, nil
+	}
+
+	return compressed, nil
+}
+
+// Compressed returns true if the value was compressed by Compress.
+func Compressed(value string) bool {
+	return strings.HasPrefix(value, marker)
+}
+
+// Decompress returns the original value of the compressed one. Values that
+// aren't compressed are returned as they are.
+func Decompress(value string) (string, error) {
+	if !Compressed(value) {
+		return value, nil
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(value, marker), sep, 2)
+	if len(parts) != 2 {
+		return "", ErrUnsupported
+	}
+
+	data, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", err
+	}
+
+	switch parts[0] {
+	case Snappy:
+		data, err = snappy.Decode(nil, data)
+	case Gzip:
+		var r *gzip.Reader
+		if r, err = gzip.NewReader(bytes.NewReader(data)); err == nil {
+			data, err = ioutil.ReadAll(r)
+		}
+	default:
+		return "", ErrUnsupported
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
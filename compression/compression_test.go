@@ -0,0 +1,99 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package compression_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mainflux/mainflux/compression"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompress(t *testing.T) {
+	verbose := fmt.Sprintf(`{"readings":[%s]}`, strings.Repeat(`{"temperature":21.5,"humidity":40},`, 50))
+
+	cases := []struct {
+		desc       string
+		alg        string
+		value      string
+		compressed bool
+		err        error
+	}{
+		{
+			desc:       "compress verbose payload using snappy",
+			alg:        compression.Snappy,
+			value:      verbose,
+			compressed: true,
+		},
+		{
+			desc:       "compress verbose payload using gzip",
+			alg:        compression.Gzip,
+			value:      verbose,
+			compressed: true,
+		},
+		{
+			desc:       "compress short payload",
+			alg:        compression.Snappy,
+			value:      "on",
+			compressed: false,
+		},
+		{
+			desc:  "compress using unsupported algorithm",
+			alg:   "lzw",
+			value: verbose,
+			err:   compression.ErrUnsupported,
+		},
+	}
+
+	for _, tc := range cases {
+		value, err := compression.Compress(tc.alg, tc.value)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+		if err != nil {
+			continue
+		}
+		assert.Equal(t, tc.compressed, compression.Compressed(value), fmt.Sprintf("%s: expected compressed %t", tc.desc, tc.compressed))
+
+		decompressed, err := compression.Decompress(value)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		assert.Equal(t, tc.value, decompressed, fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.value, decompressed))
+	}
+}
+
+func TestDecompress(t *testing.T) {
+	cases := []struct {
+		desc  string
+		value string
+		res   string
+		err   error
+	}{
+		{
+			desc:  "decompress uncompressed value",
+			value: "plain",
+			res:   "plain",
+		},
+		{
+			desc:  "decompress value compressed using unsupported algorithm",
+			value: "\x01lzw:AAAA",
+			err:   compression.ErrUnsupported,
+		},
+		{
+			desc:  "decompress value without algorithm",
+			value: "\x01AAAA",
+			err:   compression.ErrUnsupported,
+		},
+	}
+
+	for _, tc := range cases {
+		res, err := compression.Decompress(tc.value)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+		assert.Equal(t, tc.res, res, fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.res, res))
+	}
+}
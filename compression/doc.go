@@ -0,0 +1,10 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// Package compression contains the codec the writers use to compress the
+// stored message payloads, and the readers to transparently decompress them.
+package compression
@@ -0,0 +1,131 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package conformance
+
+import (
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/mainflux/mainflux"
+)
+
+// ErrNotSubscribed indicates that the subscription doesn't exist.
+var ErrNotSubscribed = errors.New("not subscribed")
+
+var _ mainflux.MessagePublisher = (*Broker)(nil)
+
+// Broker is the in-memory message broker the adapter under test publishes
+// the messages to and subscribes to the channels on. Subjects are matched
+// the same way NATS matches the channel subjects, so that the subscription
+// without the subtopic receives only the messages published without one,
+// and the "*" and ">" wildcards of the subscribed subtopic match any single
+// element and the rest of the subtopic respectively.
+type Broker struct {
+	mu        sync.Mutex
+	published []mainflux.RawMessage
+	subs      map[int]subscription
+	counter   int
+}
+
+type subscription struct {
+	chanID   string
+	subtopic string
+	handler  func(mainflux.RawMessage)
+}
+
+// NewBroker returns the empty in-memory broker.
+func NewBroker() *Broker {
+	return &Broker{subs: map[int]subscription{}}
+}
+
+// Publish records the message and passes it to the matching subscriptions.
+func (b *Broker) Publish(msg mainflux.RawMessage) error {
+	b.mu.Lock()
+	b.published = append(b.published, msg)
+	handlers := []func(mainflux.RawMessage){}
+	for _, sub := range b.subs {
+		if sub.chanID == msg.Channel && matches(sub.subtopic, msg.Subtopic) {
+			handlers = append(handlers, sub.handler)
+		}
+	}
+	b.mu.Unlock()
+
+	for _, h := range handlers {
+		h(msg)
+	}
+
+	return nil
+}
+
+// Subscribe passes the messages published to the channel's subtopic to the
+// handler, until the subscription identified by the returned ID is removed.
+func (b *Broker) Subscribe(chanID, subtopic string, handler func(mainflux.RawMessage)) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.counter++
+	b.subs[b.counter] = subscription{
+		chanID:   chanID,
+		subtopic: subtopic,
+		handler:  handler,
+	}
+
+	return b.counter
+}
+
+// Unsubscribe removes the subscription identified by the provided ID.
+func (b *Broker) Unsubscribe(id int) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.subs[id]; !ok {
+		return ErrNotSubscribed
+	}
+	delete(b.subs, id)
+
+	return nil
+}
+
+// Published returns the messages published so far.
+func (b *Broker) Published() []mainflux.RawMessage {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return append([]mainflux.RawMessage{}, b.published...)
+}
+
+// Subscriptions returns the number of the active subscriptions.
+func (b *Broker) Subscriptions() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return len(b.subs)
+}
+
+// matches determines whether the message published to the subtopic is
+// delivered to the subscription to the pattern.
+func matches(pattern, subtopic string) bool {
+	if pattern == "" || subtopic == "" {
+		return pattern == subtopic
+	}
+
+	pelems := strings.Split(pattern, ".")
+	elems := strings.Split(subtopic, ".")
+	for i, p := range pelems {
+		if p == ">" {
+			return len(elems) > i
+		}
+
+		if i >= len(elems) || p != "*" && p != elems[i] {
+			return false
+		}
+	}
+
+	return len(pelems) == len(elems)
+}
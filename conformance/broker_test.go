@@ -0,0 +1,50 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package conformance_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/conformance"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBrokerSubscribe(t *testing.T) {
+	cases := []struct {
+		desc      string
+		pattern   string
+		subtopic  string
+		delivered bool
+	}{
+		{desc: "deliver message without subtopic", pattern: "", subtopic: "", delivered: true},
+		{desc: "skip message with subtopic", pattern: "", subtopic: "a", delivered: false},
+		{desc: "deliver message to subtopic", pattern: "a.b", subtopic: "a.b", delivered: true},
+		{desc: "skip message to parent subtopic", pattern: "a.b", subtopic: "a", delivered: false},
+		{desc: "deliver message matching single element wildcard", pattern: "a.*", subtopic: "a.b", delivered: true},
+		{desc: "skip message exceeding single element wildcard", pattern: "a.*", subtopic: "a.b.c", delivered: false},
+		{desc: "deliver message matching trailing wildcard", pattern: "a.>", subtopic: "a.b.c", delivered: true},
+		{desc: "skip message not reaching trailing wildcard", pattern: "a.>", subtopic: "a", delivered: false},
+	}
+
+	for _, tc := range cases {
+		broker := conformance.NewBroker()
+		delivered := false
+		id := broker.Subscribe(conformance.ChanID, tc.pattern, func(mainflux.RawMessage) {
+			delivered = true
+		})
+
+		broker.Publish(mainflux.RawMessage{Channel: conformance.ChanID, Subtopic: tc.subtopic})
+		assert.Equal(t, tc.delivered, delivered, fmt.Sprintf("%s: expected delivered %t got %t", tc.desc, tc.delivered, delivered))
+
+		err := broker.Unsubscribe(id)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		assert.Equal(t, 0, broker.Subscriptions(), fmt.Sprintf("%s: expected no subscriptions", tc.desc))
+	}
+}
@@ -0,0 +1,23 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// Package conformance contains the test suite the protocol adapters are
+// checked against, so that the adapters developed outside of the repository
+// authorize the things, publish the messages and interpret the subtopics the
+// same way the bundled adapters do.
+//
+// The adapter under test is wired to the fake things service and the
+// in-memory broker provided by the suite, and driven through the Client the
+// adapter author implements on top of the adapter's protocol, e.g.
+//
+//	func TestConformance(t *testing.T) {
+//		conformance.Run(t, func(env conformance.Env) conformance.Client {
+//			svc := adapter.New(env.Broker)
+//			return newClient(svc, env.Things)
+//		})
+//	}
+package conformance
@@ -0,0 +1,329 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package conformance
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Timeout is the time the suite waits for the adapter to deliver a message
+// or to remove a subscription.
+var Timeout = time.Second
+
+var (
+	// ErrUnauthorized is reported by the client when the adapter rejects the
+	// request due to the missing or invalid key, e.g. 403 over HTTP.
+	ErrUnauthorized = errors.New("unauthorized access")
+
+	// ErrMalformed is reported by the client when the adapter rejects the
+	// request as malformed, e.g. 400 over HTTP.
+	ErrMalformed = errors.New("malformed request")
+
+	// ErrUnavailable is reported by the client when the adapter fails to
+	// reach the things service, e.g. 503 over HTTP.
+	ErrUnavailable = errors.New("service unavailable")
+)
+
+// Env is the environment the adapter under test is wired to.
+type Env struct {
+	Things *Things
+	Broker *Broker
+}
+
+// Client drives the adapter under test over its protocol, the same way the
+// devices do. Subtopics are passed in their canonical form, i.e. with the
+// elements separated by dots, and it's up to the client to encode them the
+// way the protocol does. The rejected requests are reported using the
+// errors of this package. The client implementing io.Closer is closed once
+// the test case is over.
+type Client interface {
+	// Publish publishes the payload to the channel's subtopic using the
+	// provided thing key.
+	Publish(key, chanID, subtopic string, payload []byte) error
+}
+
+// Subscriber is implemented by the clients of the adapters that support
+// subscribing to the channels.
+type Subscriber interface {
+	// Subscribe subscribes to the channel's subtopic using the provided
+	// thing key.
+	Subscribe(key, chanID, subtopic string) (Subscription, error)
+}
+
+// Subscription delivers the payloads of the messages received over the
+// adapter's protocol.
+type Subscription interface {
+	// Messages returns the channel the received payloads are sent to.
+	Messages() <-chan []byte
+
+	// Close cancels the subscription.
+	Close() error
+}
+
+// Factory wires the adapter under test to the environment and returns the
+// client driving it. It is called once per test case.
+type Factory func(Env) Client
+
+// Run checks the adapter against the suite. Subscription cases are skipped
+// unless the client implements Subscriber.
+func Run(t *testing.T, factory Factory) {
+	t.Run("publish", func(t *testing.T) {
+		runPublish(t, factory)
+	})
+	t.Run("subscribe", func(t *testing.T) {
+		runSubscribe(t, factory)
+	})
+}
+
+func runPublish(t *testing.T, factory Factory) {
+	cases := []struct {
+		desc     string
+		key      string
+		chanID   string
+		subtopic string
+		err      error
+	}{
+		{
+			desc:   "publish using key granting all actions",
+			key:    Key,
+			chanID: ChanID,
+			err:    nil,
+		},
+		{
+			desc:   "publish using publish key",
+			key:    PublishKey,
+			chanID: ChanID,
+			err:    nil,
+		},
+		{
+			desc:     "publish to subtopic",
+			key:      Key,
+			chanID:   ChanID,
+			subtopic: "sensors.temperature",
+			err:      nil,
+		},
+		{
+			desc:   "publish using subscribe key",
+			key:    SubscribeKey,
+			chanID: ChanID,
+			err:    ErrUnauthorized,
+		},
+		{
+			desc:   "publish using invalid key",
+			key:    "invalid",
+			chanID: ChanID,
+			err:    ErrUnauthorized,
+		},
+		{
+			desc:   "publish without key",
+			key:    "",
+			chanID: ChanID,
+			err:    ErrUnauthorized,
+		},
+		{
+			desc:   "publish to channel thing isn't connected to",
+			key:    Key,
+			chanID: OtherChanID,
+			err:    ErrUnauthorized,
+		},
+		{
+			desc:     "publish to subtopic containing wildcard within element",
+			key:      Key,
+			chanID:   ChanID,
+			subtopic: "sensors.temp>",
+			err:      ErrMalformed,
+		},
+		{
+			desc:   "publish while things service is unavailable",
+			key:    UnavailableKey,
+			chanID: ChanID,
+			err:    ErrUnavailable,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			env := Env{Things: NewThings(), Broker: NewBroker()}
+			client := factory(env)
+			defer closeClient(client)
+
+			payload := []byte(fmt.Sprintf(`[{"n":"%s","v":1}]`, tc.desc))
+			err := client.Publish(tc.key, tc.chanID, tc.subtopic, payload)
+			assert.Equal(t, tc.err, err, fmt.Sprintf("expected %s got %s", tc.err, err))
+
+			published := env.Broker.Published()
+			if tc.err != nil {
+				assert.Empty(t, published, "expected rejected message not to be published")
+				return
+			}
+
+			require.Len(t, published, 1, "expected message to be published once")
+			msg := published[0]
+			assert.Equal(t, tc.chanID, msg.Channel, fmt.Sprintf("expected channel %s got %s", tc.chanID, msg.Channel))
+			assert.Equal(t, tc.subtopic, msg.Subtopic, fmt.Sprintf("expected subtopic %s got %s", tc.subtopic, msg.Subtopic))
+			assert.Equal(t, ThingID, msg.Publisher, fmt.Sprintf("expected publisher %s got %s", ThingID, msg.Publisher))
+			assert.NotEmpty(t, msg.Protocol, "expected protocol to be set")
+			assert.Equal(t, payload, msg.Payload, fmt.Sprintf("expected payload %s got %s", payload, msg.Payload))
+			assertAction(t, env.Things, tc.key, mainflux.ActionPublish)
+		})
+	}
+}
+
+func runSubscribe(t *testing.T, factory Factory) {
+	client := factory(Env{Things: NewThings(), Broker: NewBroker()})
+	closeClient(client)
+	if _, ok := client.(Subscriber); !ok {
+		t.Skip("adapter doesn't support subscriptions")
+	}
+
+	cases := []struct {
+		desc      string
+		key       string
+		chanID    string
+		subtopic  string
+		published string
+		err       error
+	}{
+		{
+			desc:   "subscribe using key granting all actions",
+			key:    Key,
+			chanID: ChanID,
+			err:    nil,
+		},
+		{
+			desc:   "subscribe using subscribe key",
+			key:    SubscribeKey,
+			chanID: ChanID,
+			err:    nil,
+		},
+		{
+			desc:      "subscribe to subtopic",
+			key:       Key,
+			chanID:    ChanID,
+			subtopic:  "commands.valve",
+			published: "commands.valve",
+			err:       nil,
+		},
+		{
+			desc:      "subscribe to subtopic using single element wildcard",
+			key:       Key,
+			chanID:    ChanID,
+			subtopic:  "commands.*",
+			published: "commands.valve",
+			err:       nil,
+		},
+		{
+			desc:      "subscribe to subtopic using trailing wildcard",
+			key:       Key,
+			chanID:    ChanID,
+			subtopic:  "commands.>",
+			published: "commands.valve.open",
+			err:       nil,
+		},
+		{
+			desc:   "subscribe using publish key",
+			key:    PublishKey,
+			chanID: ChanID,
+			err:    ErrUnauthorized,
+		},
+		{
+			desc:   "subscribe using invalid key",
+			key:    "invalid",
+			chanID: ChanID,
+			err:    ErrUnauthorized,
+		},
+		{
+			desc:   "subscribe without key",
+			key:    "",
+			chanID: ChanID,
+			err:    ErrUnauthorized,
+		},
+		{
+			desc:   "subscribe to channel thing isn't connected to",
+			key:    Key,
+			chanID: OtherChanID,
+			err:    ErrUnauthorized,
+		},
+		{
+			desc:   "subscribe while things service is unavailable",
+			key:    UnavailableKey,
+			chanID: ChanID,
+			err:    ErrUnavailable,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			env := Env{Things: NewThings(), Broker: NewBroker()}
+			client := factory(env)
+			defer closeClient(client)
+
+			sub, err := client.(Subscriber).Subscribe(tc.key, tc.chanID, tc.subtopic)
+			assert.Equal(t, tc.err, err, fmt.Sprintf("expected %s got %s", tc.err, err))
+			if tc.err != nil {
+				assert.Zero(t, env.Broker.Subscriptions(), "expected rejected subscription not to be made")
+				return
+			}
+			require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+			assertAction(t, env.Things, tc.key, mainflux.ActionSubscribe)
+
+			// The message published to the other subtopic must be skipped.
+			env.Broker.Publish(mainflux.RawMessage{Channel: tc.chanID, Subtopic: "other", Publisher: ThingID, Payload: []byte("other")})
+			payload := []byte(tc.desc)
+			env.Broker.Publish(mainflux.RawMessage{Channel: tc.chanID, Subtopic: tc.published, Publisher: ThingID, Payload: payload})
+
+			select {
+			case received := <-sub.Messages():
+				assert.Equal(t, payload, received, fmt.Sprintf("expected payload %s got %s", payload, received))
+			case <-time.After(Timeout):
+				t.Errorf("expected message to be delivered within %s", Timeout)
+			}
+
+			err = sub.Close()
+			assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+			assert.True(t, unsubscribed(env.Broker), "expected subscription to be removed once closed")
+		})
+	}
+}
+
+// assertAction checks that the adapter requested the access for the action
+// the request performs.
+func assertAction(t *testing.T, things *Things, key, action string) {
+	for _, req := range things.Requests() {
+		if req.Token == key && req.Action == action {
+			return
+		}
+	}
+	t.Errorf("expected access to be requested for %s action", action)
+}
+
+func unsubscribed(broker *Broker) bool {
+	deadline := time.Now().Add(Timeout)
+	for time.Now().Before(deadline) {
+		if broker.Subscriptions() == 0 {
+			return true
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	return false
+}
+
+func closeClient(client Client) {
+	if c, ok := client.(io.Closer); ok {
+		c.Close()
+	}
+}
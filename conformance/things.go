@@ -0,0 +1,138 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package conformance
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mainflux/mainflux"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// ThingID identifies the thing the keys belong to.
+	ThingID = "123e4567-e89b-12d3-a456-000000000001"
+
+	// ChanID identifies the channel the thing is connected to.
+	ChanID = "123e4567-e89b-12d3-a456-000000000002"
+
+	// OtherChanID identifies the channel the thing isn't connected to.
+	OtherChanID = "123e4567-e89b-12d3-a456-000000000003"
+
+	// Key grants both publishing and subscribing on the channel.
+	Key = "conformance-key"
+
+	// PublishKey grants publishing only.
+	PublishKey = "conformance-publish-key"
+
+	// SubscribeKey grants subscribing only.
+	SubscribeKey = "conformance-subscribe-key"
+
+	// UnavailableKey makes the things service fail with the internal error.
+	UnavailableKey = "conformance-unavailable-key"
+)
+
+var _ mainflux.ThingsServiceClient = (*Things)(nil)
+
+// Things is the fake things service client authorizing the keys defined by
+// the suite, which records the access requests it receives.
+type Things struct {
+	mu       sync.Mutex
+	requests []mainflux.AccessReq
+}
+
+// NewThings returns the fake things service client.
+func NewThings() *Things {
+	return &Things{}
+}
+
+// Requests returns the access requests received so far.
+func (t *Things) Requests() []mainflux.AccessReq {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return append([]mainflux.AccessReq{}, t.requests...)
+}
+
+// CanAccess grants the actions of the suite's keys on the connected channel.
+// The denied requests fail with the PermissionDenied status, the same as in
+// the things service.
+func (t *Things) CanAccess(_ context.Context, req *mainflux.AccessReq, _ ...grpc.CallOption) (*mainflux.ThingID, error) {
+	t.mu.Lock()
+	t.requests = append(t.requests, mainflux.AccessReq{Token: req.GetToken(), ChanID: req.GetChanID(), Action: req.GetAction()})
+	t.mu.Unlock()
+
+	if req.GetToken() == UnavailableKey {
+		return nil, status.Error(codes.Internal, "internal server error")
+	}
+
+	if req.GetChanID() != ChanID || !allows(req.GetToken(), req.GetAction()) {
+		return nil, status.Error(codes.PermissionDenied, "invalid credentials provided")
+	}
+
+	return &mainflux.ThingID{Value: ThingID}, nil
+}
+
+// Identify returns the thing the suite's keys belong to.
+func (t *Things) Identify(_ context.Context, req *mainflux.Token, _ ...grpc.CallOption) (*mainflux.ThingID, error) {
+	switch req.GetValue() {
+	case UnavailableKey:
+		return nil, status.Error(codes.Internal, "internal server error")
+	case Key, PublishKey, SubscribeKey:
+		return &mainflux.ThingID{Value: ThingID}, nil
+	default:
+		return nil, status.Error(codes.PermissionDenied, "invalid credentials provided")
+	}
+}
+
+// IdentifyBatch identifies each of the keys the way Identify does, leaving
+// the IDs of the invalid keys empty.
+func (t *Things) IdentifyBatch(ctx context.Context, req *mainflux.Tokens, _ ...grpc.CallOption) (*mainflux.ThingIDs, error) {
+	ids := make([]string, len(req.GetValues()))
+	for i, key := range req.GetValues() {
+		if key == UnavailableKey {
+			return nil, status.Error(codes.Internal, "internal server error")
+		}
+
+		if res, err := t.Identify(ctx, &mainflux.Token{Value: key}); err == nil {
+			ids[i] = res.GetValue()
+		}
+	}
+
+	return &mainflux.ThingIDs{Values: ids}, nil
+}
+
+// CanRead denies all the user tokens, since the suite covers the things only.
+func (t *Things) CanRead(_ context.Context, _ *mainflux.AccessReq, _ ...grpc.CallOption) (*mainflux.UserID, error) {
+	return nil, status.Error(codes.PermissionDenied, "invalid credentials provided")
+}
+
+// ListChannelsByThing returns the channel the suite's thing is connected to.
+func (t *Things) ListChannelsByThing(_ context.Context, req *mainflux.ThingID, _ ...grpc.CallOption) (*mainflux.ChannelIDs, error) {
+	if req.GetValue() != ThingID {
+		return &mainflux.ChannelIDs{}, nil
+	}
+
+	return &mainflux.ChannelIDs{Values: []string{ChanID}}, nil
+}
+
+func allows(key, action string) bool {
+	switch key {
+	case Key:
+		return true
+	case PublishKey:
+		return action == mainflux.ActionPublish
+	case SubscribeKey:
+		return action == mainflux.ActionSubscribe
+	default:
+		return false
+	}
+}
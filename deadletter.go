@@ -0,0 +1,28 @@
+//
+// Copyright (c) 2018
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package mainflux
+
+import "time"
+
+// DeadLetter represents a message that failed processing in one of the
+// message pipeline stages, along with the subject it should be re-driven to.
+type DeadLetter struct {
+	ID      string    `json:"id,omitempty"`
+	Stage   string    `json:"stage"`
+	Reason  string    `json:"reason"`
+	Subject string    `json:"subject"`
+	Payload []byte    `json:"payload"`
+	Created time.Time `json:"created"`
+}
+
+// WarnedMessage represents the raw message that failed normalization and
+// was passed through nonetheless, along with the warning telling why.
+type WarnedMessage struct {
+	Warning string     `json:"warning"`
+	Message RawMessage `json:"message"`
+}
@@ -0,0 +1,23 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package mainflux
+
+// File delivery.go contains the delivery modes of the channels, which the
+// adapters stamp the messages with, so that every processing stage handles
+// them accordingly. Messages of the channels without the delivery mode are
+// published without waiting for the acknowledgement, and kept as dead
+// letters if their processing fails.
+
+// DeliveryAtMostOnce represents the fire-and-forget delivery. Messages that
+// fail processing are dropped, rather than kept as dead letters.
+const DeliveryAtMostOnce = "at-most-once"
+
+// DeliveryAtLeastOnce represents the acknowledged delivery. Adapters report
+// a message as published only once the normalizer has passed it on or kept
+// it as a dead letter, so that the clients can publish it again otherwise.
+const DeliveryAtLeastOnce = "at-least-once"
@@ -0,0 +1,15 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// Package events contains the Redis Streams subscriber that the services use
+// to consume the events of the other services, such as things provisioning
+// and users removal. The subscribers read the streams in consumer groups, so
+// that each event is handled by a single instance of the service, and
+// acknowledge the events once they are handled, which checkpoints the
+// group's progress. The events that weren't handled, because the handler
+// failed or the instance stopped, are redelivered.
+package events
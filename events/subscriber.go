@@ -0,0 +1,232 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package events
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/mainflux/mainflux/logger"
+)
+
+const (
+	// Beginning is the start ID of the groups that handle all the events
+	// in the stream, including the ones added before the group was created.
+	Beginning = "0"
+
+	// Latest is the start ID of the groups that handle only the events
+	// added after the group was created.
+	Latest = "$"
+
+	// ids read from the streams: pendingEvents selects the events delivered
+	// to the consumer but not acknowledged, while newEvents selects the ones
+	// not delivered to any consumer of the group.
+	pendingEvents = "0"
+	newEvents     = ">"
+
+	errGroupExists = "BUSYGROUP Consumer Group name already exists"
+
+	defBatchSize     = 100
+	defMaxRetries    = 3
+	defRetryInterval = time.Second
+	maxRetryInterval = 30 * time.Second
+)
+
+// Event represents the event read from the stream, mapping its fields to
+// their values.
+type Event map[string]interface{}
+
+// Read returns the string value of the event field, or the provided default
+// if the event doesn't contain it.
+func (e Event) Read(key, def string) string {
+	val, ok := e[key].(string)
+	if !ok {
+		return def
+	}
+
+	return val
+}
+
+// Handler handles the events read from the stream. The events the handler
+// fails to handle are redelivered, so handling has to be idempotent.
+type Handler interface {
+	// Handle handles the event. Non-nil error indicates that the event
+	// should be redelivered.
+	Handle(Event) error
+}
+
+// HandlerFunc is an adapter allowing the functions to be used as handlers.
+type HandlerFunc func(Event) error
+
+// Handle calls f(event).
+func (f HandlerFunc) Handle(event Event) error {
+	return f(event)
+}
+
+// Config describes the subscription to the stream.
+type Config struct {
+	// Stream is the name of the stream.
+	Stream string
+
+	// Group is the consumer group, shared by all the instances of the
+	// service, that the events are distributed across.
+	Group string
+
+	// Consumer uniquely identifies the instance within the group.
+	Consumer string
+
+	// Start is the ID the group is created at, which is either Beginning or
+	// Latest. Latest is used if not set. It is ignored if the group exists.
+	Start string
+
+	// BatchSize is the maximal number of events read at once. Defaults to
+	// 100.
+	BatchSize int64
+
+	// MaxRetries is the number of times the failed event is redelivered
+	// before it is dropped. Defaults to 3.
+	MaxRetries int
+
+	// RetryInterval is the time waited before the failed event is
+	// redelivered, or the stream is read again after the failed read. The
+	// wait doubles with every consecutive failed read, up to 30s. Defaults
+	// to 1s.
+	RetryInterval time.Duration
+}
+
+// Subscriber represents the stream subscriber.
+type Subscriber interface {
+	// Subscribe creates the consumer group, unless it exists, and passes the
+	// events of the stream to the handler in the order they were added. The
+	// events delivered to the consumer before it was restarted, but not
+	// acknowledged, are handled first. The failed event is redelivered,
+	// together with the events following it in the batch, until it is
+	// handled or it runs out of retries. Subscribe blocks as long as the
+	// stream is consumed, and returns the error if the group can't be
+	// created.
+	Subscribe(Handler) error
+}
+
+var _ Subscriber = (*subscriber)(nil)
+
+type subscriber struct {
+	client redis.UniversalClient
+	cfg    Config
+	logger logger.Logger
+}
+
+// NewSubscriber returns new Redis Streams subscriber instance.
+func NewSubscriber(client redis.UniversalClient, cfg Config, logger logger.Logger) Subscriber {
+	if cfg.Start == "" {
+		cfg.Start = Latest
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defBatchSize
+	}
+	if cfg.MaxRetries <= 0 {
+		cfg.MaxRetries = defMaxRetries
+	}
+	if cfg.RetryInterval <= 0 {
+		cfg.RetryInterval = defRetryInterval
+	}
+
+	return subscriber{
+		client: client,
+		cfg:    cfg,
+		logger: logger,
+	}
+}
+
+func (s subscriber) Subscribe(h Handler) error {
+	err := s.client.XGroupCreateMkStream(s.cfg.Stream, s.cfg.Group, s.cfg.Start).Err()
+	if err != nil && err.Error() != errGroupExists {
+		return err
+	}
+
+	retries := map[string]int{}
+	pending := true
+	failures := 0
+	for {
+		id := newEvents
+		if pending {
+			id = pendingEvents
+		}
+
+		streams, err := s.client.XReadGroup(&redis.XReadGroupArgs{
+			Group:    s.cfg.Group,
+			Consumer: s.cfg.Consumer,
+			Streams:  []string{s.cfg.Stream, id},
+			Count:    s.cfg.BatchSize,
+		}).Result()
+		if err != nil && err != redis.Nil {
+			failures++
+			wait := backoff(s.cfg.RetryInterval, failures)
+			s.logger.Warn(fmt.Sprintf("Failed to read %s stream, retrying in %s: %s", s.cfg.Stream, wait, err))
+			time.Sleep(wait)
+			continue
+		}
+		failures = 0
+
+		if len(streams) == 0 || len(streams[0].Messages) == 0 {
+			pending = false
+			continue
+		}
+
+		if pending = !s.handle(h, streams[0].Messages, retries); pending {
+			time.Sleep(s.cfg.RetryInterval)
+		}
+	}
+}
+
+// handle passes the messages to the handler and acknowledges the handled
+// ones. It stops at the first failure, leaving the failed message and the
+// ones following it pending, and returns false. The messages that run out of
+// retries are acknowledged, so that they don't block the stream.
+func (s subscriber) handle(h Handler, msgs []redis.XMessage, retries map[string]int) bool {
+	for _, msg := range msgs {
+		if err := h.Handle(Event(msg.Values)); err != nil {
+			retries[msg.ID]++
+			if retries[msg.ID] <= s.cfg.MaxRetries {
+				s.logger.Warn(fmt.Sprintf("Failed to handle %s event %s: %s", s.cfg.Stream, msg.ID, err))
+				return false
+			}
+			s.logger.Error(fmt.Sprintf("Dropped %s event %s after %d retries: %s", s.cfg.Stream, msg.ID, s.cfg.MaxRetries, err))
+		}
+		delete(retries, msg.ID)
+
+		if err := s.client.XAck(s.cfg.Stream, s.cfg.Group, msg.ID).Err(); err != nil {
+			s.logger.Warn(fmt.Sprintf("Failed to acknowledge %s event %s: %s", s.cfg.Stream, msg.ID, err))
+			return false
+		}
+	}
+
+	return true
+}
+
+// backoff returns the time waited before the stream is read again after the
+// given number of consecutive failed reads, doubling the retry interval with
+// every failure up to maxRetryInterval.
+func backoff(interval time.Duration, failures int) time.Duration {
+	for i := 1; i < failures && interval < maxRetryInterval; i++ {
+		interval *= 2
+	}
+
+	if interval > maxRetryInterval {
+		return maxRetryInterval
+	}
+
+	return interval
+}
+
+// Rewind moves the group back to the provided ID, so that the events added
+// after it are delivered again, e.g. to rebuild the state derived from the
+// stream. Use Beginning to replay the whole stream.
+func Rewind(client redis.UniversalClient, stream, group, id string) error {
+	return client.XGroupSetID(stream, group, id).Err()
+}
@@ -0,0 +1,201 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package events_test
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/mainflux/mainflux/events"
+	"github.com/mainflux/mainflux/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	group    = "mainflux.events"
+	consumer = "consumer"
+	timeout  = 5 * time.Second
+)
+
+var errHandle = errors.New("failed to handle event")
+
+func newSubscriber(t *testing.T, cfg events.Config) events.Subscriber {
+	log, err := logger.New(ioutil.Discard, "error")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cfg.Group = group
+	cfg.Consumer = consumer
+	cfg.RetryInterval = 10 * time.Millisecond
+	return events.NewSubscriber(redisClient, cfg, log)
+}
+
+func publish(t *testing.T, stream string, values ...string) {
+	for _, v := range values {
+		err := redisClient.XAdd(&redis.XAddArgs{
+			Stream: stream,
+			Values: map[string]interface{}{"value": v},
+		}).Err()
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+}
+
+// subscribe starts consuming the stream, passing the event values to the
+// returned channel. The handler fails the events the fail function returns
+// true for.
+func subscribe(t *testing.T, sub events.Subscriber, fail func(string) bool) chan string {
+	values := make(chan string, 100)
+	go sub.Subscribe(events.HandlerFunc(func(event events.Event) error {
+		v := event.Read("value", "")
+		values <- v
+		if fail(v) {
+			return errHandle
+		}
+		return nil
+	}))
+
+	return values
+}
+
+func receive(t *testing.T, values chan string, n int) []string {
+	received := []string{}
+	for i := 0; i < n; i++ {
+		select {
+		case v := <-values:
+			received = append(received, v)
+		case <-time.After(timeout):
+			return received
+		}
+	}
+
+	return received
+}
+
+func waitGroup(t *testing.T, stream string) {
+	for i := 0; i < 100; i++ {
+		if err := redisClient.XPending(stream, group).Err(); err == nil {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("group of %s stream not created", stream)
+}
+
+func pending(t *testing.T, stream string) int64 {
+	p, err := redisClient.XPending(stream, group).Result()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	return p.Count
+}
+
+func TestSubscribe(t *testing.T) {
+	stream := "events.subscribe"
+	publish(t, stream, "old")
+
+	sub := newSubscriber(t, events.Config{Stream: stream})
+	values := subscribe(t, sub, func(string) bool { return false })
+	waitGroup(t, stream)
+	publish(t, stream, "a", "b", "c")
+
+	received := receive(t, values, 3)
+	assert.Equal(t, []string{"a", "b", "c"}, received, fmt.Sprintf("expected %v got %v", []string{"a", "b", "c"}, received))
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, int64(0), pending(t, stream), "expected handled events to be acknowledged")
+}
+
+func TestSubscribeFromBeginning(t *testing.T) {
+	stream := "events.beginning"
+	publish(t, stream, "a", "b")
+
+	sub := newSubscriber(t, events.Config{Stream: stream, Start: events.Beginning})
+	values := subscribe(t, sub, func(string) bool { return false })
+
+	received := receive(t, values, 2)
+	assert.Equal(t, []string{"a", "b"}, received, fmt.Sprintf("expected %v got %v", []string{"a", "b"}, received))
+}
+
+func TestSubscribeRetry(t *testing.T) {
+	cases := []struct {
+		desc       string
+		stream     string
+		maxRetries int
+		expected   []string
+	}{
+		{
+			desc:       "redeliver failed event",
+			stream:     "events.retry",
+			maxRetries: 3,
+			expected:   []string{"a", "b", "b", "b", "c"},
+		},
+		{
+			desc:       "drop event out of retries",
+			stream:     "events.drop",
+			maxRetries: 1,
+			expected:   []string{"a", "b", "b", "c"},
+		},
+	}
+
+	for _, tc := range cases {
+		failures := 0
+		sub := newSubscriber(t, events.Config{Stream: tc.stream, MaxRetries: tc.maxRetries})
+		values := subscribe(t, sub, func(v string) bool {
+			// Event b fails twice, which exceeds the retries in the
+			// second case, so it is dropped instead of redelivered.
+			if v == "b" && failures < 2 {
+				failures++
+				return true
+			}
+			return false
+		})
+		waitGroup(t, tc.stream)
+		publish(t, tc.stream, "a", "b", "c")
+
+		received := receive(t, values, len(tc.expected))
+		assert.Equal(t, tc.expected, received, fmt.Sprintf("%s: expected %v got %v", tc.desc, tc.expected, received))
+	}
+}
+
+func TestSubscribePending(t *testing.T) {
+	stream := "events.pending"
+	err := redisClient.XGroupCreateMkStream(stream, group, events.Latest).Err()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	publish(t, stream, "a", "b")
+
+	// Simulate the consumer that stopped before acknowledging the events.
+	err = redisClient.XReadGroup(&redis.XReadGroupArgs{
+		Group:    group,
+		Consumer: consumer,
+		Streams:  []string{stream, ">"},
+	}).Err()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	sub := newSubscriber(t, events.Config{Stream: stream})
+	values := subscribe(t, sub, func(string) bool { return false })
+
+	received := receive(t, values, 2)
+	assert.Equal(t, []string{"a", "b"}, received, fmt.Sprintf("expected %v got %v", []string{"a", "b"}, received))
+}
+
+func TestRewind(t *testing.T) {
+	stream := "events.rewind"
+	sub := newSubscriber(t, events.Config{Stream: stream})
+	values := subscribe(t, sub, func(string) bool { return false })
+	waitGroup(t, stream)
+	publish(t, stream, "a")
+	receive(t, values, 1)
+
+	err := events.Rewind(redisClient, stream, group, events.Beginning)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	publish(t, stream, "b")
+
+	received := receive(t, values, 2)
+	assert.Equal(t, []string{"a", "b"}, received, fmt.Sprintf("expected %v got %v", []string{"a", "b"}, received))
+}
@@ -0,0 +1,132 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux/flags"
+	log "github.com/mainflux/mainflux/logger"
+)
+
+var _ flags.Client = (*client)(nil)
+
+type client struct {
+	url       string
+	http      *http.Client
+	logger    log.Logger
+	mu        sync.RWMutex
+	flags     map[string]flags.Flag
+	overrides map[string]flags.Override
+}
+
+// NewClient instantiates the feature flags client that keeps the state of
+// the flags service at the provided URL in memory, refreshing it on every
+// interval. Until the state is fetched all the flags are disabled and no
+// overrides apply, while a failed refresh keeps the last fetched state.
+func NewClient(url string, interval time.Duration, logger log.Logger) flags.Client {
+	c := &client{
+		url:       url,
+		http:      &http.Client{Timeout: interval},
+		logger:    logger,
+		flags:     make(map[string]flags.Flag),
+		overrides: make(map[string]flags.Override),
+	}
+
+	c.refresh()
+	go func() {
+		for range time.Tick(interval) {
+			c.refresh()
+		}
+	}()
+
+	return c
+}
+
+func (c *client) Enabled(name, subject string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	flag, ok := c.flags[name]
+	if !ok {
+		return false
+	}
+
+	return flag.EnabledFor(subject)
+}
+
+func (c *client) Override(owner string) flags.Override {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	override, ok := c.overrides[owner]
+	if !ok {
+		return flags.Override{Owner: owner}
+	}
+
+	return override
+}
+
+func (c *client) refresh() {
+	all, overrides, err := c.state()
+	if err != nil {
+		c.logger.Warn(fmt.Sprintf("Failed to refresh feature flags: %s", err))
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.flags = all
+	c.overrides = overrides
+}
+
+func (c *client) state() (map[string]flags.Flag, map[string]flags.Override, error) {
+	resp, err := c.http.Get(fmt.Sprintf("%s/state", c.url))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+
+	var res stateRes
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, nil, err
+	}
+
+	all := make(map[string]flags.Flag)
+	for _, fr := range res.Flags {
+		all[fr.Name] = flags.Flag{
+			Name:    fr.Name,
+			Enabled: fr.Enabled,
+			Rollout: fr.Rollout,
+		}
+	}
+
+	overrides := make(map[string]flags.Override)
+	for _, or := range res.Overrides {
+		overrides[or.Owner] = flags.Override{
+			Owner:       or.Owner,
+			RateLimit:   or.RateLimit,
+			RateBurst:   or.RateBurst,
+			MaxThings:   or.MaxThings,
+			MaxChannels: or.MaxChannels,
+			Retention:   time.Duration(or.Retention) * time.Second,
+			Protocols:   or.Protocols,
+		}
+	}
+
+	return all, overrides, nil
+}
@@ -0,0 +1,103 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package api_test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/flags"
+	"github.com/mainflux/mainflux/flags/api"
+	"github.com/mainflux/mainflux/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientEnabled(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+
+	disabled := flag
+	disabled.Name = "disabled"
+	disabled.Enabled = false
+
+	for _, f := range []flags.Flag{flag, disabled} {
+		_, err := svc.SaveFlag(adminToken, f)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	log, _ := logger.New(os.Stdout, logger.Info.String())
+	client := api.NewClient(ts.URL, time.Minute, log)
+
+	cases := []struct {
+		desc    string
+		name    string
+		enabled bool
+	}{
+		{
+			desc:    "consult enabled flag",
+			name:    flag.Name,
+			enabled: true,
+		},
+		{
+			desc:    "consult disabled flag",
+			name:    disabled.Name,
+			enabled: false,
+		},
+		{
+			desc:    "consult unknown flag",
+			name:    "unknown",
+			enabled: false,
+		},
+	}
+
+	for _, tc := range cases {
+		enabled := client.Enabled(tc.name, user)
+		assert.Equal(t, tc.enabled, enabled, fmt.Sprintf("%s: expected %t got %t", tc.desc, tc.enabled, enabled))
+	}
+}
+
+func TestClientOverride(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+
+	override := flags.Override{
+		Owner:     user,
+		RateLimit: 10,
+		MaxThings: 100,
+		Retention: time.Hour,
+		Protocols: []string{"http"},
+	}
+	_, err := svc.SaveOverride(adminToken, override)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	log, _ := logger.New(os.Stdout, logger.Info.String())
+	client := api.NewClient(ts.URL, time.Minute, log)
+
+	got := client.Override(user)
+	assert.Equal(t, override.RateLimit, got.RateLimit, fmt.Sprintf("expected rate limit %f got %f", override.RateLimit, got.RateLimit))
+	assert.Equal(t, override.MaxThings, got.MaxThings, fmt.Sprintf("expected max things %d got %d", override.MaxThings, got.MaxThings))
+	assert.Equal(t, override.Retention, got.Retention, fmt.Sprintf("expected retention %s got %s", override.Retention, got.Retention))
+	assert.False(t, got.Allows("mqtt"), "expected unlisted protocol to be disallowed")
+
+	got = client.Override(admin)
+	assert.True(t, got.Allows("mqtt"), "expected owner without override to be allowed any protocol")
+	assert.Zero(t, got.MaxThings, fmt.Sprintf("expected no things limit got %d", got.MaxThings))
+}
+
+func TestClientUnavailable(t *testing.T) {
+	log, _ := logger.New(os.Stdout, logger.Info.String())
+	client := api.NewClient("http://localhost:1", time.Minute, log)
+
+	enabled := client.Enabled(name, user)
+	assert.False(t, enabled, "expected flags to be disabled when the service is unavailable")
+}
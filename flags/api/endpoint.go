@@ -0,0 +1,223 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/mainflux/mainflux/flags"
+)
+
+func saveFlagEndpoint(svc flags.Service) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(saveFlagReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		flag := flags.Flag{
+			Name:        req.name,
+			Description: req.Description,
+			Enabled:     req.Enabled,
+			Rollout:     flags.MaxRollout,
+		}
+		if req.Rollout != nil {
+			flag.Rollout = *req.Rollout
+		}
+
+		saved, err := svc.SaveFlag(req.key, flag)
+		if err != nil {
+			return nil, err
+		}
+
+		return toFlagRes(saved), nil
+	}
+}
+
+func viewFlagEndpoint(svc flags.Service) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(flagReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		flag, err := svc.ViewFlag(req.key, req.name)
+		if err != nil {
+			return nil, err
+		}
+
+		return toFlagRes(flag), nil
+	}
+}
+
+func listFlagsEndpoint(svc flags.Service) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(listReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		all, err := svc.ListFlags(req.key)
+		if err != nil {
+			return nil, err
+		}
+
+		return toListRes(all), nil
+	}
+}
+
+func removeFlagEndpoint(svc flags.Service) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(flagReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RemoveFlag(req.key, req.name); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func stateEndpoint(svc flags.Service) endpoint.Endpoint {
+	return func(_ context.Context, _ interface{}) (interface{}, error) {
+		state, err := svc.State()
+		if err != nil {
+			return nil, err
+		}
+
+		res := stateRes{
+			Flags:     toListRes(state.Flags).Flags,
+			Overrides: toListOverridesRes(state.Overrides).Overrides,
+		}
+
+		return res, nil
+	}
+}
+
+func saveOverrideEndpoint(svc flags.Service) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(saveOverrideReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		override := flags.Override{
+			Owner:       req.owner,
+			RateLimit:   req.RateLimit,
+			RateBurst:   req.RateBurst,
+			MaxThings:   req.MaxThings,
+			MaxChannels: req.MaxChannels,
+			Retention:   time.Duration(req.Retention) * time.Second,
+			Protocols:   req.Protocols,
+		}
+
+		saved, err := svc.SaveOverride(req.key, override)
+		if err != nil {
+			return nil, err
+		}
+
+		return toOverrideRes(saved), nil
+	}
+}
+
+func viewOverrideEndpoint(svc flags.Service) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(overrideReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		override, err := svc.ViewOverride(req.key, req.owner)
+		if err != nil {
+			return nil, err
+		}
+
+		return toOverrideRes(override), nil
+	}
+}
+
+func listOverridesEndpoint(svc flags.Service) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(listReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		all, err := svc.ListOverrides(req.key)
+		if err != nil {
+			return nil, err
+		}
+
+		return toListOverridesRes(all), nil
+	}
+}
+
+func removeOverrideEndpoint(svc flags.Service) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(overrideReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RemoveOverride(req.key, req.owner); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func toFlagRes(flag flags.Flag) flagRes {
+	return flagRes{
+		Name:        flag.Name,
+		Description: flag.Description,
+		Enabled:     flag.Enabled,
+		Rollout:     flag.Rollout,
+		UpdatedAt:   flag.UpdatedAt,
+	}
+}
+
+func toListRes(all []flags.Flag) listRes {
+	res := listRes{
+		Flags: []flagRes{},
+	}
+	for _, flag := range all {
+		res.Flags = append(res.Flags, toFlagRes(flag))
+	}
+
+	return res
+}
+
+func toOverrideRes(override flags.Override) overrideRes {
+	return overrideRes{
+		Owner:       override.Owner,
+		RateLimit:   override.RateLimit,
+		RateBurst:   override.RateBurst,
+		MaxThings:   override.MaxThings,
+		MaxChannels: override.MaxChannels,
+		Retention:   uint64(override.Retention / time.Second),
+		Protocols:   override.Protocols,
+		UpdatedAt:   override.UpdatedAt,
+	}
+}
+
+func toListOverridesRes(all []flags.Override) listOverridesRes {
+	res := listOverridesRes{
+		Overrides: []overrideRes{},
+	}
+	for _, override := range all {
+		res.Overrides = append(res.Overrides, toOverrideRes(override))
+	}
+
+	return res
+}
@@ -0,0 +1,519 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package api_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/flags"
+	"github.com/mainflux/mainflux/flags/api"
+	"github.com/mainflux/mainflux/flags/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	adminToken   = "adminToken"
+	userToken    = "userToken"
+	invalidToken = "invalidToken"
+	admin        = "admin@example.com"
+	user         = "user@example.com"
+	contentType  = "application/json"
+	name         = "new-pagination"
+)
+
+var flag = flags.Flag{
+	Name:    name,
+	Enabled: true,
+	Rollout: flags.MaxRollout,
+}
+
+type testRequest struct {
+	client      *http.Client
+	method      string
+	url         string
+	contentType string
+	token       string
+	body        io.Reader
+}
+
+func (tr testRequest) make() (*http.Response, error) {
+	req, err := http.NewRequest(tr.method, tr.url, tr.body)
+	if err != nil {
+		return nil, err
+	}
+
+	if tr.token != "" {
+		req.Header.Set("Authorization", tr.token)
+	}
+
+	if tr.contentType != "" {
+		req.Header.Set("Content-Type", tr.contentType)
+	}
+
+	return tr.client.Do(req)
+}
+
+type flagRes struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Enabled     bool   `json:"enabled"`
+	Rollout     *uint  `json:"rollout,omitempty"`
+}
+
+type listRes struct {
+	Flags []flagRes `json:"flags"`
+}
+
+func newService() flags.Service {
+	users := mocks.NewUsersService(map[string]string{
+		adminToken: admin,
+		userToken:  user,
+	})
+	repo := mocks.NewFlagRepository()
+
+	return flags.New(users, repo, mocks.NewOverrideRepository(), []string{admin})
+}
+
+func newServer(svc flags.Service) *httptest.Server {
+	return httptest.NewServer(api.MakeHandler(svc))
+}
+
+func toJSON(data interface{}) string {
+	jsonData, _ := json.Marshal(data)
+	return string(jsonData)
+}
+
+func TestSaveFlag(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+
+	rollout := uint(25)
+	invalidRollout := uint(flags.MaxRollout + 1)
+
+	cases := []struct {
+		desc        string
+		name        string
+		req         string
+		contentType string
+		token       string
+		status      int
+		rollout     uint
+	}{
+		{
+			desc:        "save flag without rollout",
+			name:        name,
+			req:         toJSON(flagRes{Enabled: true}),
+			contentType: contentType,
+			token:       adminToken,
+			status:      http.StatusOK,
+			rollout:     flags.MaxRollout,
+		},
+		{
+			desc:        "save flag with rollout",
+			name:        name,
+			req:         toJSON(flagRes{Enabled: true, Rollout: &rollout}),
+			contentType: contentType,
+			token:       adminToken,
+			status:      http.StatusOK,
+			rollout:     rollout,
+		},
+		{
+			desc:        "save flag with invalid rollout",
+			name:        name,
+			req:         toJSON(flagRes{Enabled: true, Rollout: &invalidRollout}),
+			contentType: contentType,
+			token:       adminToken,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "save flag with invalid name",
+			name:        "Invalid$Name",
+			req:         toJSON(flagRes{Enabled: true}),
+			contentType: contentType,
+			token:       adminToken,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "save flag as non-admin user",
+			name:        name,
+			req:         toJSON(flagRes{Enabled: true}),
+			contentType: contentType,
+			token:       userToken,
+			status:      http.StatusForbidden,
+		},
+		{
+			desc:        "save flag with invalid token",
+			name:        name,
+			req:         toJSON(flagRes{Enabled: true}),
+			contentType: contentType,
+			token:       invalidToken,
+			status:      http.StatusForbidden,
+		},
+		{
+			desc:        "save flag with empty token",
+			name:        name,
+			req:         toJSON(flagRes{Enabled: true}),
+			contentType: contentType,
+			token:       "",
+			status:      http.StatusForbidden,
+		},
+		{
+			desc:        "save flag with invalid request format",
+			name:        name,
+			req:         "}",
+			contentType: contentType,
+			token:       adminToken,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "save flag without content type",
+			name:        name,
+			req:         toJSON(flagRes{Enabled: true}),
+			contentType: "",
+			token:       adminToken,
+			status:      http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPut,
+			url:         fmt.Sprintf("%s/flags/%s", ts.URL, tc.name),
+			contentType: tc.contentType,
+			token:       tc.token,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		if tc.status != http.StatusOK {
+			continue
+		}
+
+		var body flagRes
+		json.NewDecoder(res.Body).Decode(&body)
+		require.NotNil(t, body.Rollout, fmt.Sprintf("%s: expected rollout in response", tc.desc))
+		assert.Equal(t, tc.rollout, *body.Rollout, fmt.Sprintf("%s: expected rollout %d got %d", tc.desc, tc.rollout, *body.Rollout))
+	}
+}
+
+func TestViewFlag(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+
+	_, err := svc.SaveFlag(adminToken, flag)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		name   string
+		token  string
+		status int
+	}{
+		{
+			desc:   "view existing flag",
+			name:   name,
+			token:  adminToken,
+			status: http.StatusOK,
+		},
+		{
+			desc:   "view flag as non-admin user",
+			name:   name,
+			token:  userToken,
+			status: http.StatusForbidden,
+		},
+		{
+			desc:   "view non-existing flag",
+			name:   "unknown",
+			token:  adminToken,
+			status: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/flags/%s", ts.URL, tc.name),
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestListFlags(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+
+	n := 3
+	for i := 0; i < n; i++ {
+		f := flag
+		f.Name = fmt.Sprintf("%s-%d", name, i)
+		_, err := svc.SaveFlag(adminToken, f)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	cases := []struct {
+		desc   string
+		url    string
+		token  string
+		status int
+		size   int
+	}{
+		{
+			desc:   "list flags",
+			url:    "flags",
+			token:  adminToken,
+			status: http.StatusOK,
+			size:   n,
+		},
+		{
+			desc:   "list flags as non-admin user",
+			url:    "flags",
+			token:  userToken,
+			status: http.StatusForbidden,
+			size:   0,
+		},
+		{
+			desc:   "retrieve state without token",
+			url:    "state",
+			token:  "",
+			status: http.StatusOK,
+			size:   n,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/%s", ts.URL, tc.url),
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+
+		var body listRes
+		json.NewDecoder(res.Body).Decode(&body)
+		assert.Len(t, body.Flags, tc.size, fmt.Sprintf("%s: expected %d flags got %d", tc.desc, tc.size, len(body.Flags)))
+	}
+}
+
+func TestRemoveFlag(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+
+	_, err := svc.SaveFlag(adminToken, flag)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		name   string
+		token  string
+		status int
+	}{
+		{
+			desc:   "remove flag as non-admin user",
+			name:   name,
+			token:  userToken,
+			status: http.StatusForbidden,
+		},
+		{
+			desc:   "remove existing flag",
+			name:   name,
+			token:  adminToken,
+			status: http.StatusNoContent,
+		},
+		{
+			desc:   "remove removed flag",
+			name:   name,
+			token:  adminToken,
+			status: http.StatusNoContent,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodDelete,
+			url:    fmt.Sprintf("%s/flags/%s", ts.URL, tc.name),
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestSaveOverride(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+
+	override := map[string]interface{}{
+		"rate_limit":   10,
+		"rate_burst":   20,
+		"max_things":   100,
+		"max_channels": 10,
+		"retention":    86400,
+		"protocols":    []string{"http", "mqtt"},
+	}
+	invalid := map[string]interface{}{
+		"protocols": []string{"MQTT"},
+	}
+
+	cases := []struct {
+		desc        string
+		req         string
+		contentType string
+		token       string
+		status      int
+	}{
+		{
+			desc:        "save override",
+			req:         toJSON(override),
+			contentType: contentType,
+			token:       adminToken,
+			status:      http.StatusOK,
+		},
+		{
+			desc:        "save override as non-admin user",
+			req:         toJSON(override),
+			contentType: contentType,
+			token:       userToken,
+			status:      http.StatusForbidden,
+		},
+		{
+			desc:        "save override with invalid protocol",
+			req:         toJSON(invalid),
+			contentType: contentType,
+			token:       adminToken,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "save override with invalid request format",
+			req:         "}",
+			contentType: contentType,
+			token:       adminToken,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "save override without content type",
+			req:         toJSON(override),
+			contentType: "",
+			token:       adminToken,
+			status:      http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPut,
+			url:         fmt.Sprintf("%s/overrides/%s", ts.URL, user),
+			contentType: tc.contentType,
+			token:       tc.token,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+
+	saved, err := svc.ViewOverride(adminToken, user)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, 24*time.Hour, saved.Retention, fmt.Sprintf("expected retention %s got %s", 24*time.Hour, saved.Retention))
+}
+
+func TestOverrides(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+
+	_, err := svc.SaveOverride(adminToken, flags.Override{Owner: user, MaxThings: 100})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		method string
+		url    string
+		token  string
+		status int
+	}{
+		{
+			desc:   "view existing override",
+			method: http.MethodGet,
+			url:    fmt.Sprintf("overrides/%s", user),
+			token:  adminToken,
+			status: http.StatusOK,
+		},
+		{
+			desc:   "view non-existing override",
+			method: http.MethodGet,
+			url:    "overrides/unknown",
+			token:  adminToken,
+			status: http.StatusNotFound,
+		},
+		{
+			desc:   "list overrides",
+			method: http.MethodGet,
+			url:    "overrides",
+			token:  adminToken,
+			status: http.StatusOK,
+		},
+		{
+			desc:   "list overrides as non-admin user",
+			method: http.MethodGet,
+			url:    "overrides",
+			token:  userToken,
+			status: http.StatusForbidden,
+		},
+		{
+			desc:   "remove override as non-admin user",
+			method: http.MethodDelete,
+			url:    fmt.Sprintf("overrides/%s", user),
+			token:  userToken,
+			status: http.StatusForbidden,
+		},
+		{
+			desc:   "remove existing override",
+			method: http.MethodDelete,
+			url:    fmt.Sprintf("overrides/%s", user),
+			token:  adminToken,
+			status: http.StatusNoContent,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: tc.method,
+			url:    fmt.Sprintf("%s/%s", ts.URL, tc.url),
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
@@ -0,0 +1,147 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// +build !test
+
+package api
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mainflux/mainflux/flags"
+	log "github.com/mainflux/mainflux/logger"
+)
+
+var _ flags.Service = (*loggingMiddleware)(nil)
+
+type loggingMiddleware struct {
+	logger log.Logger
+	svc    flags.Service
+}
+
+// NewLoggingMiddleware adds logging facilities to the core service.
+func NewLoggingMiddleware(svc flags.Service, logger log.Logger) flags.Service {
+	return &loggingMiddleware{logger, svc}
+}
+
+func (lm *loggingMiddleware) SaveFlag(key string, flag flags.Flag) (saved flags.Flag, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method save_flag for key %s and flag %s took %s to complete", key, flag.Name, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.SaveFlag(key, flag)
+}
+
+func (lm *loggingMiddleware) ViewFlag(key, name string) (flag flags.Flag, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method view_flag for key %s and flag %s took %s to complete", key, name, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ViewFlag(key, name)
+}
+
+func (lm *loggingMiddleware) ListFlags(key string) (all []flags.Flag, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_flags for key %s took %s to complete", key, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListFlags(key)
+}
+
+func (lm *loggingMiddleware) RemoveFlag(key, name string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method remove_flag for key %s and flag %s took %s to complete", key, name, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RemoveFlag(key, name)
+}
+
+func (lm *loggingMiddleware) SaveOverride(key string, override flags.Override) (saved flags.Override, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method save_override for key %s and owner %s took %s to complete", key, override.Owner, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.SaveOverride(key, override)
+}
+
+func (lm *loggingMiddleware) ViewOverride(key, owner string) (override flags.Override, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method view_override for key %s and owner %s took %s to complete", key, owner, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ViewOverride(key, owner)
+}
+
+func (lm *loggingMiddleware) ListOverrides(key string) (all []flags.Override, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_overrides for key %s took %s to complete", key, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListOverrides(key)
+}
+
+func (lm *loggingMiddleware) RemoveOverride(key, owner string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method remove_override for key %s and owner %s took %s to complete", key, owner, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RemoveOverride(key, owner)
+}
+
+func (lm *loggingMiddleware) State() (state flags.State, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method state took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.State()
+}
@@ -0,0 +1,116 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// +build !test
+
+package api
+
+import (
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/mainflux/mainflux/flags"
+)
+
+var _ flags.Service = (*metricsMiddleware)(nil)
+
+type metricsMiddleware struct {
+	counter metrics.Counter
+	latency metrics.Histogram
+	svc     flags.Service
+}
+
+// MetricsMiddleware instruments core service by tracking request count and
+// latency.
+func MetricsMiddleware(svc flags.Service, counter metrics.Counter, latency metrics.Histogram) flags.Service {
+	return &metricsMiddleware{
+		counter: counter,
+		latency: latency,
+		svc:     svc,
+	}
+}
+
+func (mm *metricsMiddleware) SaveFlag(key string, flag flags.Flag) (flags.Flag, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "save_flag").Add(1)
+		mm.latency.With("method", "save_flag").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.SaveFlag(key, flag)
+}
+
+func (mm *metricsMiddleware) ViewFlag(key, name string) (flags.Flag, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "view_flag").Add(1)
+		mm.latency.With("method", "view_flag").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.ViewFlag(key, name)
+}
+
+func (mm *metricsMiddleware) ListFlags(key string) ([]flags.Flag, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "list_flags").Add(1)
+		mm.latency.With("method", "list_flags").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.ListFlags(key)
+}
+
+func (mm *metricsMiddleware) RemoveFlag(key, name string) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "remove_flag").Add(1)
+		mm.latency.With("method", "remove_flag").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.RemoveFlag(key, name)
+}
+
+func (mm *metricsMiddleware) SaveOverride(key string, override flags.Override) (flags.Override, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "save_override").Add(1)
+		mm.latency.With("method", "save_override").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.SaveOverride(key, override)
+}
+
+func (mm *metricsMiddleware) ViewOverride(key, owner string) (flags.Override, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "view_override").Add(1)
+		mm.latency.With("method", "view_override").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.ViewOverride(key, owner)
+}
+
+func (mm *metricsMiddleware) ListOverrides(key string) ([]flags.Override, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "list_overrides").Add(1)
+		mm.latency.With("method", "list_overrides").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.ListOverrides(key)
+}
+
+func (mm *metricsMiddleware) RemoveOverride(key, owner string) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "remove_override").Add(1)
+		mm.latency.With("method", "remove_override").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.RemoveOverride(key, owner)
+}
+
+func (mm *metricsMiddleware) State() (flags.State, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "state").Add(1)
+		mm.latency.With("method", "state").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.State()
+}
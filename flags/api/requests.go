@@ -0,0 +1,103 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package api
+
+import "github.com/mainflux/mainflux/flags"
+
+type apiReq interface {
+	validate() error
+}
+
+type saveFlagReq struct {
+	key         string
+	name        string
+	Description string `json:"description"`
+	Enabled     bool   `json:"enabled"`
+	Rollout     *uint  `json:"rollout,omitempty"`
+}
+
+func (req saveFlagReq) validate() error {
+	if req.key == "" {
+		return flags.ErrUnauthorizedAccess
+	}
+
+	if req.name == "" {
+		return flags.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type flagReq struct {
+	key  string
+	name string
+}
+
+func (req flagReq) validate() error {
+	if req.key == "" {
+		return flags.ErrUnauthorizedAccess
+	}
+
+	if req.name == "" {
+		return flags.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type listReq struct {
+	key string
+}
+
+func (req listReq) validate() error {
+	if req.key == "" {
+		return flags.ErrUnauthorizedAccess
+	}
+
+	return nil
+}
+
+type saveOverrideReq struct {
+	key         string
+	owner       string
+	RateLimit   float64  `json:"rate_limit"`
+	RateBurst   uint     `json:"rate_burst"`
+	MaxThings   uint64   `json:"max_things"`
+	MaxChannels uint64   `json:"max_channels"`
+	Retention   uint64   `json:"retention"`
+	Protocols   []string `json:"protocols,omitempty"`
+}
+
+func (req saveOverrideReq) validate() error {
+	if req.key == "" {
+		return flags.ErrUnauthorizedAccess
+	}
+
+	if req.owner == "" {
+		return flags.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type overrideReq struct {
+	key   string
+	owner string
+}
+
+func (req overrideReq) validate() error {
+	if req.key == "" {
+		return flags.ErrUnauthorizedAccess
+	}
+
+	if req.owner == "" {
+		return flags.ErrMalformedEntity
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/mainflux/mainflux"
+)
+
+var (
+	_ mainflux.Response = (*flagRes)(nil)
+	_ mainflux.Response = (*listRes)(nil)
+	_ mainflux.Response = (*removeRes)(nil)
+	_ mainflux.Response = (*overrideRes)(nil)
+	_ mainflux.Response = (*listOverridesRes)(nil)
+	_ mainflux.Response = (*stateRes)(nil)
+)
+
+type flagRes struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Enabled     bool      `json:"enabled"`
+	Rollout     uint      `json:"rollout"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (res flagRes) Code() int {
+	return http.StatusOK
+}
+
+func (res flagRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res flagRes) Empty() bool {
+	return false
+}
+
+type listRes struct {
+	Flags []flagRes `json:"flags"`
+}
+
+func (res listRes) Code() int {
+	return http.StatusOK
+}
+
+func (res listRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res listRes) Empty() bool {
+	return false
+}
+
+type overrideRes struct {
+	Owner       string    `json:"owner"`
+	RateLimit   float64   `json:"rate_limit,omitempty"`
+	RateBurst   uint      `json:"rate_burst,omitempty"`
+	MaxThings   uint64    `json:"max_things,omitempty"`
+	MaxChannels uint64    `json:"max_channels,omitempty"`
+	Retention   uint64    `json:"retention,omitempty"`
+	Protocols   []string  `json:"protocols,omitempty"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (res overrideRes) Code() int {
+	return http.StatusOK
+}
+
+func (res overrideRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res overrideRes) Empty() bool {
+	return false
+}
+
+type listOverridesRes struct {
+	Overrides []overrideRes `json:"overrides"`
+}
+
+func (res listOverridesRes) Code() int {
+	return http.StatusOK
+}
+
+func (res listOverridesRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res listOverridesRes) Empty() bool {
+	return false
+}
+
+type stateRes struct {
+	Flags     []flagRes     `json:"flags"`
+	Overrides []overrideRes `json:"overrides"`
+}
+
+func (res stateRes) Code() int {
+	return http.StatusOK
+}
+
+func (res stateRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res stateRes) Empty() bool {
+	return false
+}
+
+type removeRes struct{}
+
+func (res removeRes) Code() int {
+	return http.StatusNoContent
+}
+
+func (res removeRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res removeRes) Empty() bool {
+	return true
+}
@@ -0,0 +1,197 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/go-zoo/bone"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/flags"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const contentType = "application/json"
+
+var errUnsupportedContentType = errors.New("unsupported content type")
+
+// MakeHandler returns a HTTP handler for API endpoints.
+func MakeHandler(svc flags.Service) http.Handler {
+	opts := []kithttp.ServerOption{
+		kithttp.ServerErrorEncoder(encodeError),
+	}
+	r := bone.New()
+
+	r.Put("/flags/:name", kithttp.NewServer(
+		saveFlagEndpoint(svc),
+		decodeSaveFlagRequest,
+		encodeResponse,
+		opts...))
+
+	r.Get("/flags/:name", kithttp.NewServer(
+		viewFlagEndpoint(svc),
+		decodeFlagRequest,
+		encodeResponse,
+		opts...))
+
+	r.Get("/flags", kithttp.NewServer(
+		listFlagsEndpoint(svc),
+		decodeListRequest,
+		encodeResponse,
+		opts...))
+
+	r.Delete("/flags/:name", kithttp.NewServer(
+		removeFlagEndpoint(svc),
+		decodeFlagRequest,
+		encodeResponse,
+		opts...))
+
+	r.Put("/overrides/:owner", kithttp.NewServer(
+		saveOverrideEndpoint(svc),
+		decodeSaveOverrideRequest,
+		encodeResponse,
+		opts...))
+
+	r.Get("/overrides/:owner", kithttp.NewServer(
+		viewOverrideEndpoint(svc),
+		decodeOverrideRequest,
+		encodeResponse,
+		opts...))
+
+	r.Get("/overrides", kithttp.NewServer(
+		listOverridesEndpoint(svc),
+		decodeListRequest,
+		encodeResponse,
+		opts...))
+
+	r.Delete("/overrides/:owner", kithttp.NewServer(
+		removeOverrideEndpoint(svc),
+		decodeOverrideRequest,
+		encodeResponse,
+		opts...))
+
+	r.Get("/state", kithttp.NewServer(
+		stateEndpoint(svc),
+		decodeStateRequest,
+		encodeResponse,
+		opts...))
+
+	r.GetFunc("/version", mainflux.Version("flags"))
+	r.Handle("/metrics", promhttp.Handler())
+
+	return r
+}
+
+func decodeSaveFlagRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+
+	req := saveFlagReq{
+		key:  r.Header.Get("Authorization"),
+		name: bone.GetValue(r, "name"),
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func decodeFlagRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	req := flagReq{
+		key:  r.Header.Get("Authorization"),
+		name: bone.GetValue(r, "name"),
+	}
+
+	return req, nil
+}
+
+func decodeSaveOverrideRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+
+	req := saveOverrideReq{
+		key:   r.Header.Get("Authorization"),
+		owner: bone.GetValue(r, "owner"),
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func decodeOverrideRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	req := overrideReq{
+		key:   r.Header.Get("Authorization"),
+		owner: bone.GetValue(r, "owner"),
+	}
+
+	return req, nil
+}
+
+func decodeListRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	req := listReq{key: r.Header.Get("Authorization")}
+
+	return req, nil
+}
+
+func decodeStateRequest(_ context.Context, _ *http.Request) (interface{}, error) {
+	return nil, nil
+}
+
+func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", contentType)
+	if ar, ok := response.(mainflux.Response); ok {
+		for k, v := range ar.Headers() {
+			w.Header().Set(k, v)
+		}
+
+		w.WriteHeader(ar.Code())
+
+		if ar.Empty() {
+			return nil
+		}
+	}
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	w.Header().Set("Content-Type", contentType)
+
+	switch err {
+	case errUnsupportedContentType:
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+	case flags.ErrMalformedEntity:
+		w.WriteHeader(http.StatusBadRequest)
+	case flags.ErrNotFound:
+		w.WriteHeader(http.StatusNotFound)
+	case flags.ErrUnauthorizedAccess:
+		w.WriteHeader(http.StatusForbidden)
+	case io.EOF:
+		w.WriteHeader(http.StatusBadRequest)
+	default:
+		switch err.(type) {
+		case *json.SyntaxError:
+			w.WriteHeader(http.StatusBadRequest)
+		case *json.UnmarshalTypeError:
+			w.WriteHeader(http.StatusBadRequest)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}
+}
@@ -0,0 +1,10 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// Package flags contains the domain concept definitions needed to support
+// Mainflux feature flags service functionality.
+package flags
@@ -0,0 +1,84 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package flags
+
+import (
+	"hash/fnv"
+	"regexp"
+	"time"
+)
+
+// MaxRollout represents the rollout percentage that enables the flag for
+// all the subjects.
+const MaxRollout = 100
+
+var nameRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{0,63}$`)
+
+// Flag represents a feature flag. Enabled flag applies to the share of the
+// subjects (e.g. users or things) defined by its rollout percentage.
+type Flag struct {
+	Name        string
+	Description string
+	Enabled     bool
+	Rollout     uint
+	UpdatedAt   time.Time
+}
+
+// Validate returns an error if the flag name or rollout percentage are
+// invalid.
+func (f Flag) Validate() error {
+	if !nameRegexp.MatchString(f.Name) || f.Rollout > MaxRollout {
+		return ErrMalformedEntity
+	}
+
+	return nil
+}
+
+// EnabledFor returns true if the flag is enabled for the subject. The same
+// subject always falls in or out of the rollout, and raising the rollout
+// percentage only adds subjects to the ones the flag is already enabled for.
+func (f Flag) EnabledFor(subject string) bool {
+	if !f.Enabled {
+		return false
+	}
+
+	if f.Rollout >= MaxRollout {
+		return true
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(f.Name + ":" + subject))
+	return uint(h.Sum32()%MaxRollout) < f.Rollout
+}
+
+// FlagRepository specifies a feature flag persistence API.
+type FlagRepository interface {
+	// Save persists the flag, replacing the existing flag with the same name.
+	Save(Flag) error
+
+	// RetrieveByName retrieves the flag having the provided name.
+	RetrieveByName(string) (Flag, error)
+
+	// RetrieveAll retrieves all the flags ordered by name.
+	RetrieveAll() ([]Flag, error)
+
+	// Remove removes the flag having the provided name.
+	Remove(string) error
+}
+
+// Client specifies an API the services use to consult the feature flags at
+// runtime.
+type Client interface {
+	// Enabled returns true if the flag having the provided name is enabled
+	// for the subject. Unknown flags are disabled.
+	Enabled(string, string) bool
+
+	// Override returns the configuration override of the specified owner,
+	// that is empty if the owner has none.
+	Override(string) Override
+}
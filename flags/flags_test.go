@@ -0,0 +1,82 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package flags_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux/flags"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnabledFor(t *testing.T) {
+	subjects := 1000
+
+	cases := []struct {
+		desc    string
+		enabled bool
+		rollout uint
+		min     int
+		max     int
+	}{
+		{
+			desc:    "disabled flag",
+			enabled: false,
+			rollout: flags.MaxRollout,
+			min:     0,
+			max:     0,
+		},
+		{
+			desc:    "enabled flag without rollout",
+			enabled: true,
+			rollout: 0,
+			min:     0,
+			max:     0,
+		},
+		{
+			desc:    "enabled flag with partial rollout",
+			enabled: true,
+			rollout: 30,
+			min:     250,
+			max:     350,
+		},
+		{
+			desc:    "enabled flag with full rollout",
+			enabled: true,
+			rollout: flags.MaxRollout,
+			min:     subjects,
+			max:     subjects,
+		},
+	}
+
+	for _, tc := range cases {
+		flag := flags.Flag{Name: "rollout", Enabled: tc.enabled, Rollout: tc.rollout}
+
+		n := 0
+		for i := 0; i < subjects; i++ {
+			if flag.EnabledFor(fmt.Sprintf("subject-%d", i)) {
+				n++
+			}
+		}
+		assert.True(t, n >= tc.min && n <= tc.max, fmt.Sprintf("%s: expected between %d and %d subjects got %d", tc.desc, tc.min, tc.max, n))
+	}
+}
+
+func TestEnabledForRaisedRollout(t *testing.T) {
+	flag := flags.Flag{Name: "rollout", Enabled: true, Rollout: 10}
+	raised := flag
+	raised.Rollout = 60
+
+	for i := 0; i < 1000; i++ {
+		subject := fmt.Sprintf("subject-%d", i)
+		if flag.EnabledFor(subject) {
+			assert.True(t, raised.EnabledFor(subject), fmt.Sprintf("expected %s to stay enabled after raising rollout", subject))
+		}
+	}
+}
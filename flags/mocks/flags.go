@@ -0,0 +1,73 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package mocks
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/mainflux/mainflux/flags"
+)
+
+var _ flags.FlagRepository = (*flagRepositoryMock)(nil)
+
+type flagRepositoryMock struct {
+	mu    sync.Mutex
+	flags map[string]flags.Flag
+}
+
+// NewFlagRepository creates in-memory flag repository.
+func NewFlagRepository() flags.FlagRepository {
+	return &flagRepositoryMock{
+		flags: make(map[string]flags.Flag),
+	}
+}
+
+func (frm *flagRepositoryMock) Save(flag flags.Flag) error {
+	frm.mu.Lock()
+	defer frm.mu.Unlock()
+
+	frm.flags[flag.Name] = flag
+	return nil
+}
+
+func (frm *flagRepositoryMock) RetrieveByName(name string) (flags.Flag, error) {
+	frm.mu.Lock()
+	defer frm.mu.Unlock()
+
+	flag, ok := frm.flags[name]
+	if !ok {
+		return flags.Flag{}, flags.ErrNotFound
+	}
+
+	return flag, nil
+}
+
+func (frm *flagRepositoryMock) RetrieveAll() ([]flags.Flag, error) {
+	frm.mu.Lock()
+	defer frm.mu.Unlock()
+
+	all := []flags.Flag{}
+	for _, flag := range frm.flags {
+		all = append(all, flag)
+	}
+
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].Name < all[j].Name
+	})
+
+	return all, nil
+}
+
+func (frm *flagRepositoryMock) Remove(name string) error {
+	frm.mu.Lock()
+	defer frm.mu.Unlock()
+
+	delete(frm.flags, name)
+	return nil
+}
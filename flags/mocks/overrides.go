@@ -0,0 +1,73 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package mocks
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/mainflux/mainflux/flags"
+)
+
+var _ flags.OverrideRepository = (*overrideRepositoryMock)(nil)
+
+type overrideRepositoryMock struct {
+	mu        sync.Mutex
+	overrides map[string]flags.Override
+}
+
+// NewOverrideRepository creates in-memory override repository.
+func NewOverrideRepository() flags.OverrideRepository {
+	return &overrideRepositoryMock{
+		overrides: make(map[string]flags.Override),
+	}
+}
+
+func (orm *overrideRepositoryMock) Save(override flags.Override) error {
+	orm.mu.Lock()
+	defer orm.mu.Unlock()
+
+	orm.overrides[override.Owner] = override
+	return nil
+}
+
+func (orm *overrideRepositoryMock) RetrieveByOwner(owner string) (flags.Override, error) {
+	orm.mu.Lock()
+	defer orm.mu.Unlock()
+
+	override, ok := orm.overrides[owner]
+	if !ok {
+		return flags.Override{}, flags.ErrNotFound
+	}
+
+	return override, nil
+}
+
+func (orm *overrideRepositoryMock) RetrieveAll() ([]flags.Override, error) {
+	orm.mu.Lock()
+	defer orm.mu.Unlock()
+
+	all := []flags.Override{}
+	for _, override := range orm.overrides {
+		all = append(all, override)
+	}
+
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].Owner < all[j].Owner
+	})
+
+	return all, nil
+}
+
+func (orm *overrideRepositoryMock) Remove(owner string) error {
+	orm.mu.Lock()
+	defer orm.mu.Unlock()
+
+	delete(orm.overrides, owner)
+	return nil
+}
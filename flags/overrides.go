@@ -0,0 +1,87 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package flags
+
+import (
+	"regexp"
+	"time"
+)
+
+const maxOwnerLength = 254
+
+var protocolRegexp = regexp.MustCompile(`^[a-z][a-z0-9-]{0,31}$`)
+
+// Override represents the configuration of the owner (user or organization)
+// overriding the deployment defaults, so that the customers of different
+// tiers get different limits. Zero values keep the defaults of the services.
+// RateLimit and RateBurst are the message rate of the owner's channels in
+// messages per second, MaxThings and MaxChannels the number of things and
+// channels the owner can have, Retention the period the owner's messages are
+// kept for, and Protocols the protocols the owner's things can publish over.
+type Override struct {
+	Owner       string
+	RateLimit   float64
+	RateBurst   uint
+	MaxThings   uint64
+	MaxChannels uint64
+	Retention   time.Duration
+	Protocols   []string
+	UpdatedAt   time.Time
+}
+
+// Validate returns an error if the owner is missing or too long, or if any
+// of the limits or protocols is invalid.
+func (o Override) Validate() error {
+	if o.Owner == "" || len(o.Owner) > maxOwnerLength {
+		return ErrMalformedEntity
+	}
+
+	if o.RateLimit < 0 || o.Retention < 0 {
+		return ErrMalformedEntity
+	}
+
+	for _, p := range o.Protocols {
+		if !protocolRegexp.MatchString(p) {
+			return ErrMalformedEntity
+		}
+	}
+
+	return nil
+}
+
+// Allows returns true if the owner's things can publish over the protocol.
+// All the protocols are allowed unless the override lists them.
+func (o Override) Allows(protocol string) bool {
+	if len(o.Protocols) == 0 {
+		return true
+	}
+
+	for _, p := range o.Protocols {
+		if p == protocol {
+			return true
+		}
+	}
+
+	return false
+}
+
+// OverrideRepository specifies a configuration override persistence API.
+type OverrideRepository interface {
+	// Save persists the override, replacing the existing override of the
+	// same owner.
+	Save(Override) error
+
+	// RetrieveByOwner retrieves the override of the specified owner.
+	RetrieveByOwner(string) (Override, error)
+
+	// RetrieveAll retrieves all the overrides ordered by owner.
+	RetrieveAll() ([]Override, error)
+
+	// Remove removes the override of the specified owner.
+	Remove(string) error
+}
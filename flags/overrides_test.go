@@ -0,0 +1,50 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package flags_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux/flags"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOverrideAllows(t *testing.T) {
+	cases := []struct {
+		desc      string
+		protocols []string
+		protocol  string
+		allowed   bool
+	}{
+		{
+			desc:      "allow any protocol without protocols listed",
+			protocols: nil,
+			protocol:  "coap",
+			allowed:   true,
+		},
+		{
+			desc:      "allow listed protocol",
+			protocols: []string{"http", "mqtt"},
+			protocol:  "mqtt",
+			allowed:   true,
+		},
+		{
+			desc:      "disallow unlisted protocol",
+			protocols: []string{"http", "mqtt"},
+			protocol:  "coap",
+			allowed:   false,
+		},
+	}
+
+	for _, tc := range cases {
+		override := flags.Override{Owner: "user@example.com", Protocols: tc.protocols}
+		allowed := override.Allows(tc.protocol)
+		assert.Equal(t, tc.allowed, allowed, fmt.Sprintf("%s: expected %t got %t", tc.desc, tc.allowed, allowed))
+	}
+}
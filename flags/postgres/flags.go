@@ -0,0 +1,108 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/mainflux/mainflux/flags"
+)
+
+var _ flags.FlagRepository = (*flagRepository)(nil)
+
+type flagRepository struct {
+	db *sqlx.DB
+}
+
+// NewFlagRepository instantiates a PostgreSQL implementation of flag
+// repository.
+func NewFlagRepository(db *sqlx.DB) flags.FlagRepository {
+	return &flagRepository{db: db}
+}
+
+func (fr flagRepository) Save(flag flags.Flag) error {
+	q := `INSERT INTO flags (name, description, enabled, rollout, updated_at)
+		VALUES (:name, :description, :enabled, :rollout, :updated_at)
+		ON CONFLICT (name) DO UPDATE SET description = :description, enabled = :enabled,
+		rollout = :rollout, updated_at = :updated_at`
+
+	_, err := fr.db.NamedExec(q, toDBFlag(flag))
+	return err
+}
+
+func (fr flagRepository) RetrieveByName(name string) (flags.Flag, error) {
+	q := `SELECT name, description, enabled, rollout, updated_at FROM flags WHERE name = $1`
+
+	dbf := dbFlag{}
+	if err := fr.db.QueryRowx(q, name).StructScan(&dbf); err != nil {
+		if err == sql.ErrNoRows {
+			return flags.Flag{}, flags.ErrNotFound
+		}
+		return flags.Flag{}, err
+	}
+
+	return toFlag(dbf), nil
+}
+
+func (fr flagRepository) RetrieveAll() ([]flags.Flag, error) {
+	q := `SELECT name, description, enabled, rollout, updated_at FROM flags ORDER BY name`
+
+	rows, err := fr.db.Queryx(q)
+	if err != nil {
+		return []flags.Flag{}, err
+	}
+	defer rows.Close()
+
+	all := []flags.Flag{}
+	for rows.Next() {
+		dbf := dbFlag{}
+		if err := rows.StructScan(&dbf); err != nil {
+			return []flags.Flag{}, err
+		}
+		all = append(all, toFlag(dbf))
+	}
+
+	return all, nil
+}
+
+func (fr flagRepository) Remove(name string) error {
+	q := `DELETE FROM flags WHERE name = $1`
+
+	_, err := fr.db.Exec(q, name)
+	return err
+}
+
+type dbFlag struct {
+	Name        string    `db:"name"`
+	Description string    `db:"description"`
+	Enabled     bool      `db:"enabled"`
+	Rollout     int       `db:"rollout"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
+func toDBFlag(flag flags.Flag) dbFlag {
+	return dbFlag{
+		Name:        flag.Name,
+		Description: flag.Description,
+		Enabled:     flag.Enabled,
+		Rollout:     int(flag.Rollout),
+		UpdatedAt:   flag.UpdatedAt,
+	}
+}
+
+func toFlag(dbf dbFlag) flags.Flag {
+	return flags.Flag{
+		Name:        dbf.Name,
+		Description: dbf.Description,
+		Enabled:     dbf.Enabled,
+		Rollout:     uint(dbf.Rollout),
+		UpdatedAt:   dbf.UpdatedAt,
+	}
+}
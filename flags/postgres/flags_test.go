@@ -0,0 +1,126 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package postgres_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/flags"
+	"github.com/mainflux/mainflux/flags/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newFlag(name string) flags.Flag {
+	return flags.Flag{
+		Name:        name,
+		Description: "test flag",
+		Enabled:     true,
+		Rollout:     50,
+		UpdatedAt:   time.Now().UTC().Truncate(time.Millisecond),
+	}
+}
+
+func TestFlagSave(t *testing.T) {
+	repo := postgres.NewFlagRepository(db)
+	flag := newFlag("save")
+
+	updated := flag
+	updated.Enabled = false
+	updated.Rollout = flags.MaxRollout
+
+	cases := []struct {
+		desc string
+		flag flags.Flag
+	}{
+		{
+			desc: "save new flag",
+			flag: flag,
+		},
+		{
+			desc: "save existing flag",
+			flag: updated,
+		},
+	}
+
+	for _, tc := range cases {
+		err := repo.Save(tc.flag)
+		assert.Nil(t, err, fmt.Sprintf("%s: got unexpected error: %s\n", tc.desc, err))
+
+		saved, err := repo.RetrieveByName(tc.flag.Name)
+		require.Nil(t, err, fmt.Sprintf("%s: got unexpected error: %s\n", tc.desc, err))
+		assert.Equal(t, tc.flag.Enabled, saved.Enabled, fmt.Sprintf("%s: expected %t got %t\n", tc.desc, tc.flag.Enabled, saved.Enabled))
+		assert.Equal(t, tc.flag.Rollout, saved.Rollout, fmt.Sprintf("%s: expected %d got %d\n", tc.desc, tc.flag.Rollout, saved.Rollout))
+	}
+}
+
+func TestFlagRetrieveByName(t *testing.T) {
+	repo := postgres.NewFlagRepository(db)
+	flag := newFlag("retrieve")
+	err := repo.Save(flag)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := []struct {
+		desc string
+		name string
+		err  error
+	}{
+		{
+			desc: "retrieve existing flag",
+			name: flag.Name,
+			err:  nil,
+		},
+		{
+			desc: "retrieve non-existing flag",
+			name: "unknown",
+			err:  flags.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := repo.RetrieveByName(tc.name)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestFlagRetrieveAll(t *testing.T) {
+	_, err := db.Exec("DELETE FROM flags")
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	repo := postgres.NewFlagRepository(db)
+	for _, name := range []string{"b", "a", "c"} {
+		err := repo.Save(newFlag(name))
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	}
+
+	all, err := repo.RetrieveAll()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	names := []string{}
+	for _, flag := range all {
+		names = append(names, flag.Name)
+	}
+	assert.Equal(t, []string{"a", "b", "c"}, names, fmt.Sprintf("expected flags ordered by name got %v", names))
+}
+
+func TestFlagRemove(t *testing.T) {
+	repo := postgres.NewFlagRepository(db)
+	flag := newFlag("remove")
+	err := repo.Save(flag)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	for i := 0; i < 2; i++ {
+		err := repo.Remove(flag.Name)
+		assert.Nil(t, err, fmt.Sprintf("#%d: got unexpected error: %s", i, err))
+
+		_, err = repo.RetrieveByName(flag.Name)
+		assert.Equal(t, flags.ErrNotFound, err, fmt.Sprintf("#%d: expected %s got %s", i, flags.ErrNotFound, err))
+	}
+}
@@ -0,0 +1,100 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq" // required for SQL access
+	mfpostgres "github.com/mainflux/mainflux/postgres"
+	migrate "github.com/rubenv/sql-migrate"
+)
+
+// Config defines the options that are used when connecting to a PostgreSQL instance
+type Config struct {
+	Host        string
+	Port        string
+	User        string
+	Pass        string
+	Name        string
+	SSLMode     string
+	SSLCert     string
+	SSLKey      string
+	SSLRootCert string
+	Target      string
+	SyncCommit  string
+}
+
+// Connect creates a connection to the PostgreSQL instance and applies any
+// unapplied database migrations. A non-nil error is returned to indicate
+// failure.
+func Connect(cfg Config) (*sqlx.DB, error) {
+	url := fmt.Sprintf("user=%s dbname=%s password=%s sslmode=%s sslcert=%s sslkey=%s sslrootcert=%s", cfg.User, cfg.Name, cfg.Pass, cfg.SSLMode, cfg.SSLCert, cfg.SSLKey, cfg.SSLRootCert)
+
+	db, err := mfpostgres.Open(mfpostgres.Config{
+		Hosts:      cfg.Host,
+		Port:       cfg.Port,
+		DSN:        url,
+		Target:     cfg.Target,
+		SyncCommit: cfg.SyncCommit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := migrateDB(db); err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+func migrateDB(db *sqlx.DB) error {
+	migrations := &migrate.MemoryMigrationSource{
+		Migrations: []*migrate.Migration{
+			{
+				Id: "flags_1",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS flags (
+						name        VARCHAR(64) PRIMARY KEY,
+						description TEXT NOT NULL DEFAULT '',
+						enabled     BOOLEAN NOT NULL DEFAULT FALSE,
+						rollout     SMALLINT NOT NULL DEFAULT 100,
+						updated_at  TIMESTAMP NOT NULL
+					)`,
+				},
+				Down: []string{
+					"DROP TABLE flags",
+				},
+			},
+			{
+				Id: "flags_2",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS overrides (
+						owner        VARCHAR(254) PRIMARY KEY,
+						rate_limit   DOUBLE PRECISION NOT NULL DEFAULT 0,
+						rate_burst   BIGINT NOT NULL DEFAULT 0,
+						max_things   BIGINT NOT NULL DEFAULT 0,
+						max_channels BIGINT NOT NULL DEFAULT 0,
+						retention    BIGINT NOT NULL DEFAULT 0,
+						protocols    TEXT[] NOT NULL DEFAULT '{}',
+						updated_at   TIMESTAMP NOT NULL
+					)`,
+				},
+				Down: []string{
+					"DROP TABLE overrides",
+				},
+			},
+		},
+	}
+
+	_, err := migrate.Exec(db.DB, "postgres", migrations, migrate.Up)
+
+	return err
+}
@@ -0,0 +1,130 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/mainflux/mainflux/flags"
+)
+
+var _ flags.OverrideRepository = (*overrideRepository)(nil)
+
+type overrideRepository struct {
+	db *sqlx.DB
+}
+
+// NewOverrideRepository instantiates a PostgreSQL implementation of the
+// override repository.
+func NewOverrideRepository(db *sqlx.DB) flags.OverrideRepository {
+	return &overrideRepository{db: db}
+}
+
+func (or overrideRepository) Save(override flags.Override) error {
+	q := `INSERT INTO overrides (owner, rate_limit, rate_burst, max_things, max_channels, retention, protocols, updated_at)
+		VALUES (:owner, :rate_limit, :rate_burst, :max_things, :max_channels, :retention, :protocols, :updated_at)
+		ON CONFLICT (owner) DO UPDATE SET rate_limit = :rate_limit, rate_burst = :rate_burst, max_things = :max_things,
+		max_channels = :max_channels, retention = :retention, protocols = :protocols, updated_at = :updated_at`
+
+	_, err := or.db.NamedExec(q, toDBOverride(override))
+	return err
+}
+
+func (or overrideRepository) RetrieveByOwner(owner string) (flags.Override, error) {
+	q := `SELECT owner, rate_limit, rate_burst, max_things, max_channels, retention, protocols, updated_at
+		FROM overrides WHERE owner = $1`
+
+	dbo := dbOverride{}
+	if err := or.db.QueryRowx(q, owner).StructScan(&dbo); err != nil {
+		if err == sql.ErrNoRows {
+			return flags.Override{}, flags.ErrNotFound
+		}
+		return flags.Override{}, err
+	}
+
+	return toOverride(dbo), nil
+}
+
+func (or overrideRepository) RetrieveAll() ([]flags.Override, error) {
+	q := `SELECT owner, rate_limit, rate_burst, max_things, max_channels, retention, protocols, updated_at
+		FROM overrides ORDER BY owner`
+
+	rows, err := or.db.Queryx(q)
+	if err != nil {
+		return []flags.Override{}, err
+	}
+	defer rows.Close()
+
+	all := []flags.Override{}
+	for rows.Next() {
+		dbo := dbOverride{}
+		if err := rows.StructScan(&dbo); err != nil {
+			return []flags.Override{}, err
+		}
+		all = append(all, toOverride(dbo))
+	}
+
+	return all, nil
+}
+
+func (or overrideRepository) Remove(owner string) error {
+	q := `DELETE FROM overrides WHERE owner = $1`
+
+	_, err := or.db.Exec(q, owner)
+	return err
+}
+
+type dbOverride struct {
+	Owner       string         `db:"owner"`
+	RateLimit   float64        `db:"rate_limit"`
+	RateBurst   int64          `db:"rate_burst"`
+	MaxThings   int64          `db:"max_things"`
+	MaxChannels int64          `db:"max_channels"`
+	Retention   int64          `db:"retention"`
+	Protocols   pq.StringArray `db:"protocols"`
+	UpdatedAt   time.Time      `db:"updated_at"`
+}
+
+// Retention period is stored in seconds.
+func toDBOverride(o flags.Override) dbOverride {
+	protocols := pq.StringArray{}
+	if o.Protocols != nil {
+		protocols = pq.StringArray(o.Protocols)
+	}
+
+	return dbOverride{
+		Owner:       o.Owner,
+		RateLimit:   o.RateLimit,
+		RateBurst:   int64(o.RateBurst),
+		MaxThings:   int64(o.MaxThings),
+		MaxChannels: int64(o.MaxChannels),
+		Retention:   int64(o.Retention / time.Second),
+		Protocols:   protocols,
+		UpdatedAt:   o.UpdatedAt,
+	}
+}
+
+func toOverride(dbo dbOverride) flags.Override {
+	o := flags.Override{
+		Owner:       dbo.Owner,
+		RateLimit:   dbo.RateLimit,
+		RateBurst:   uint(dbo.RateBurst),
+		MaxThings:   uint64(dbo.MaxThings),
+		MaxChannels: uint64(dbo.MaxChannels),
+		Retention:   time.Duration(dbo.Retention) * time.Second,
+		UpdatedAt:   dbo.UpdatedAt,
+	}
+	if len(dbo.Protocols) > 0 {
+		o.Protocols = []string(dbo.Protocols)
+	}
+
+	return o
+}
@@ -0,0 +1,76 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package postgres_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/flags"
+	"github.com/mainflux/mainflux/flags/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newOverride(owner string) flags.Override {
+	return flags.Override{
+		Owner:       owner,
+		RateLimit:   2.5,
+		RateBurst:   5,
+		MaxThings:   100,
+		MaxChannels: 10,
+		Retention:   24 * time.Hour,
+		Protocols:   []string{"http", "mqtt"},
+		UpdatedAt:   time.Now().UTC().Truncate(time.Millisecond),
+	}
+}
+
+func TestOverrideSave(t *testing.T) {
+	repo := postgres.NewOverrideRepository(db)
+	override := newOverride("save@example.com")
+
+	updated := override
+	updated.MaxThings = 0
+	updated.Protocols = nil
+
+	for _, o := range []flags.Override{override, updated} {
+		err := repo.Save(o)
+		assert.Nil(t, err, fmt.Sprintf("got unexpected error: %s\n", err))
+
+		saved, err := repo.RetrieveByOwner(o.Owner)
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s\n", err))
+		assert.Equal(t, o.MaxThings, saved.MaxThings, fmt.Sprintf("expected %d got %d\n", o.MaxThings, saved.MaxThings))
+		assert.Equal(t, o.Retention, saved.Retention, fmt.Sprintf("expected %s got %s\n", o.Retention, saved.Retention))
+		assert.Equal(t, o.Protocols, saved.Protocols, fmt.Sprintf("expected %v got %v\n", o.Protocols, saved.Protocols))
+	}
+}
+
+func TestOverrideRetrieveAll(t *testing.T) {
+	repo := postgres.NewOverrideRepository(db)
+	override := newOverride("list@example.com")
+	err := repo.Save(override)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s\n", err))
+
+	all, err := repo.RetrieveAll()
+	assert.Nil(t, err, fmt.Sprintf("got unexpected error: %s\n", err))
+	assert.NotEmpty(t, all, "expected saved override to be retrieved")
+}
+
+func TestOverrideRemove(t *testing.T) {
+	repo := postgres.NewOverrideRepository(db)
+	override := newOverride("remove@example.com")
+	err := repo.Save(override)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s\n", err))
+
+	err = repo.Remove(override.Owner)
+	assert.Nil(t, err, fmt.Sprintf("got unexpected error: %s\n", err))
+
+	_, err = repo.RetrieveByOwner(override.Owner)
+	assert.Equal(t, flags.ErrNotFound, err, fmt.Sprintf("expected %s got %s\n", flags.ErrNotFound, err))
+}
@@ -0,0 +1,212 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package flags
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/mainflux/mainflux"
+)
+
+var (
+	// ErrMalformedEntity indicates malformed entity specification (e.g.
+	// invalid flag name or rollout percentage).
+	ErrMalformedEntity = errors.New("malformed entity specification")
+
+	// ErrUnauthorizedAccess indicates missing or invalid credentials provided
+	// when accessing a protected resource.
+	ErrUnauthorizedAccess = errors.New("missing or invalid credentials provided")
+
+	// ErrNotFound indicates a non-existent entity request.
+	ErrNotFound = errors.New("non-existent entity")
+)
+
+// Service specifies an API that must be fullfiled by the domain service
+// implementation, and all of its decorators (e.g. logging & metrics).
+type Service interface {
+	// SaveFlag creates the flag or replaces the existing flag with the same
+	// name. Only the admins are allowed to manage the flags.
+	SaveFlag(string, Flag) (Flag, error)
+
+	// ViewFlag retrieves the flag having the provided name.
+	ViewFlag(string, string) (Flag, error)
+
+	// ListFlags retrieves all the flags.
+	ListFlags(string) ([]Flag, error)
+
+	// RemoveFlag removes the flag having the provided name.
+	RemoveFlag(string, string) error
+
+	// SaveOverride creates the configuration override of the owner or
+	// replaces the existing one. Only the admins are allowed to manage the
+	// overrides.
+	SaveOverride(string, Override) (Override, error)
+
+	// ViewOverride retrieves the configuration override of the owner.
+	ViewOverride(string, string) (Override, error)
+
+	// ListOverrides retrieves all the configuration overrides.
+	ListOverrides(string) ([]Override, error)
+
+	// RemoveOverride removes the configuration override of the owner.
+	RemoveOverride(string, string) error
+
+	// State retrieves all the flags and overrides without authorization.
+	// The services consult the flags and overrides using the state, so it
+	// must not be exposed outside of the deployment.
+	State() (State, error)
+}
+
+// State represents all the flags and the configuration overrides the
+// services consult.
+type State struct {
+	Flags     []Flag
+	Overrides []Override
+}
+
+var _ Service = (*flagsService)(nil)
+
+type flagsService struct {
+	users     mainflux.UsersServiceClient
+	flags     FlagRepository
+	overrides OverrideRepository
+	admins    map[string]bool
+}
+
+// New instantiates the feature flags service implementation. The flags and
+// the overrides can be managed by the users having the provided admin
+// emails only.
+func New(users mainflux.UsersServiceClient, flags FlagRepository, overrides OverrideRepository, admins []string) Service {
+	am := make(map[string]bool)
+	for _, admin := range admins {
+		if admin != "" {
+			am[admin] = true
+		}
+	}
+
+	return &flagsService{
+		users:     users,
+		flags:     flags,
+		overrides: overrides,
+		admins:    am,
+	}
+}
+
+func (fs *flagsService) SaveFlag(key string, flag Flag) (Flag, error) {
+	if err := fs.authorize(key); err != nil {
+		return Flag{}, err
+	}
+
+	if err := flag.Validate(); err != nil {
+		return Flag{}, err
+	}
+
+	flag.UpdatedAt = time.Now().UTC()
+	if err := fs.flags.Save(flag); err != nil {
+		return Flag{}, err
+	}
+
+	return flag, nil
+}
+
+func (fs *flagsService) ViewFlag(key, name string) (Flag, error) {
+	if err := fs.authorize(key); err != nil {
+		return Flag{}, err
+	}
+
+	return fs.flags.RetrieveByName(name)
+}
+
+func (fs *flagsService) ListFlags(key string) ([]Flag, error) {
+	if err := fs.authorize(key); err != nil {
+		return []Flag{}, err
+	}
+
+	return fs.flags.RetrieveAll()
+}
+
+func (fs *flagsService) RemoveFlag(key, name string) error {
+	if err := fs.authorize(key); err != nil {
+		return err
+	}
+
+	return fs.flags.Remove(name)
+}
+
+func (fs *flagsService) SaveOverride(key string, override Override) (Override, error) {
+	if err := fs.authorize(key); err != nil {
+		return Override{}, err
+	}
+
+	if err := override.Validate(); err != nil {
+		return Override{}, err
+	}
+
+	override.UpdatedAt = time.Now().UTC()
+	if err := fs.overrides.Save(override); err != nil {
+		return Override{}, err
+	}
+
+	return override, nil
+}
+
+func (fs *flagsService) ViewOverride(key, owner string) (Override, error) {
+	if err := fs.authorize(key); err != nil {
+		return Override{}, err
+	}
+
+	return fs.overrides.RetrieveByOwner(owner)
+}
+
+func (fs *flagsService) ListOverrides(key string) ([]Override, error) {
+	if err := fs.authorize(key); err != nil {
+		return []Override{}, err
+	}
+
+	return fs.overrides.RetrieveAll()
+}
+
+func (fs *flagsService) RemoveOverride(key, owner string) error {
+	if err := fs.authorize(key); err != nil {
+		return err
+	}
+
+	return fs.overrides.Remove(owner)
+}
+
+func (fs *flagsService) State() (State, error) {
+	all, err := fs.flags.RetrieveAll()
+	if err != nil {
+		return State{}, err
+	}
+
+	overrides, err := fs.overrides.RetrieveAll()
+	if err != nil {
+		return State{}, err
+	}
+
+	return State{Flags: all, Overrides: overrides}, nil
+}
+
+func (fs *flagsService) authorize(key string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	res, err := fs.users.Identify(ctx, &mainflux.Token{Value: key})
+	if err != nil {
+		return ErrUnauthorizedAccess
+	}
+
+	if !fs.admins[res.GetValue()] {
+		return ErrUnauthorizedAccess
+	}
+
+	return nil
+}
@@ -0,0 +1,347 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package flags_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/flags"
+	"github.com/mainflux/mainflux/flags/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	adminToken   = "adminToken"
+	userToken    = "userToken"
+	invalidToken = "invalidToken"
+	admin        = "admin@example.com"
+	user         = "user@example.com"
+)
+
+var flag = flags.Flag{
+	Name:        "new-pagination",
+	Description: "cursor based pagination",
+	Enabled:     true,
+	Rollout:     flags.MaxRollout,
+}
+
+var override = flags.Override{
+	Owner:       user,
+	RateLimit:   10,
+	RateBurst:   20,
+	MaxThings:   100,
+	MaxChannels: 10,
+	Retention:   24 * time.Hour,
+	Protocols:   []string{"http", "mqtt"},
+}
+
+func newService() flags.Service {
+	users := mocks.NewUsersService(map[string]string{
+		adminToken: admin,
+		userToken:  user,
+	})
+	repo := mocks.NewFlagRepository()
+
+	return flags.New(users, repo, mocks.NewOverrideRepository(), []string{admin})
+}
+
+func TestSaveFlag(t *testing.T) {
+	svc := newService()
+
+	invalidName := flag
+	invalidName.Name = "New Pagination"
+
+	invalidRollout := flag
+	invalidRollout.Rollout = flags.MaxRollout + 1
+
+	cases := []struct {
+		desc  string
+		token string
+		flag  flags.Flag
+		err   error
+	}{
+		{
+			desc:  "save flag as admin",
+			token: adminToken,
+			flag:  flag,
+			err:   nil,
+		},
+		{
+			desc:  "save existing flag as admin",
+			token: adminToken,
+			flag:  flag,
+			err:   nil,
+		},
+		{
+			desc:  "save flag as non-admin user",
+			token: userToken,
+			flag:  flag,
+			err:   flags.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "save flag with invalid token",
+			token: invalidToken,
+			flag:  flag,
+			err:   flags.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "save flag with invalid name",
+			token: adminToken,
+			flag:  invalidName,
+			err:   flags.ErrMalformedEntity,
+		},
+		{
+			desc:  "save flag with invalid rollout",
+			token: adminToken,
+			flag:  invalidRollout,
+			err:   flags.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		saved, err := svc.SaveFlag(tc.token, tc.flag)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err == nil {
+			assert.False(t, saved.UpdatedAt.IsZero(), fmt.Sprintf("%s: expected update time to be set\n", tc.desc))
+		}
+	}
+}
+
+func TestViewFlag(t *testing.T) {
+	svc := newService()
+	_, err := svc.SaveFlag(adminToken, flag)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		token string
+		name  string
+		err   error
+	}{
+		{
+			desc:  "view existing flag",
+			token: adminToken,
+			name:  flag.Name,
+			err:   nil,
+		},
+		{
+			desc:  "view flag as non-admin user",
+			token: userToken,
+			name:  flag.Name,
+			err:   flags.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "view non-existing flag",
+			token: adminToken,
+			name:  "unknown",
+			err:   flags.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := svc.ViewFlag(tc.token, tc.name)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestListFlags(t *testing.T) {
+	svc := newService()
+	_, err := svc.SaveFlag(adminToken, flag)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		token string
+		size  int
+		err   error
+	}{
+		{
+			desc:  "list flags as admin",
+			token: adminToken,
+			size:  1,
+			err:   nil,
+		},
+		{
+			desc:  "list flags as non-admin user",
+			token: userToken,
+			size:  0,
+			err:   flags.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		all, err := svc.ListFlags(tc.token)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Len(t, all, tc.size, fmt.Sprintf("%s: expected %d flags got %d\n", tc.desc, tc.size, len(all)))
+	}
+}
+
+func TestRemoveFlag(t *testing.T) {
+	svc := newService()
+	_, err := svc.SaveFlag(adminToken, flag)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		token string
+		err   error
+	}{
+		{
+			desc:  "remove flag as non-admin user",
+			token: userToken,
+			err:   flags.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "remove existing flag",
+			token: adminToken,
+			err:   nil,
+		},
+		{
+			desc:  "remove removed flag",
+			token: adminToken,
+			err:   nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.RemoveFlag(tc.token, flag.Name)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	_, err = svc.ViewFlag(adminToken, flag.Name)
+	assert.Equal(t, flags.ErrNotFound, err, fmt.Sprintf("expected %s got %s", flags.ErrNotFound, err))
+}
+
+func TestState(t *testing.T) {
+	svc := newService()
+	_, err := svc.SaveFlag(adminToken, flag)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	_, err = svc.SaveOverride(adminToken, override)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	state, err := svc.State()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Len(t, state.Flags, 1, fmt.Sprintf("expected 1 flag got %d", len(state.Flags)))
+	assert.Len(t, state.Overrides, 1, fmt.Sprintf("expected 1 override got %d", len(state.Overrides)))
+}
+
+func TestSaveOverride(t *testing.T) {
+	svc := newService()
+
+	invalidProtocol := override
+	invalidProtocol.Protocols = []string{"MQTT"}
+
+	cases := []struct {
+		desc     string
+		token    string
+		override flags.Override
+		err      error
+	}{
+		{
+			desc:     "save override as admin",
+			token:    adminToken,
+			override: override,
+			err:      nil,
+		},
+		{
+			desc:     "save override as non-admin user",
+			token:    userToken,
+			override: override,
+			err:      flags.ErrUnauthorizedAccess,
+		},
+		{
+			desc:     "save override without owner",
+			token:    adminToken,
+			override: flags.Override{RateLimit: 1},
+			err:      flags.ErrMalformedEntity,
+		},
+		{
+			desc:     "save override with invalid protocol",
+			token:    adminToken,
+			override: invalidProtocol,
+			err:      flags.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		saved, err := svc.SaveOverride(tc.token, tc.override)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err == nil {
+			assert.False(t, saved.UpdatedAt.IsZero(), fmt.Sprintf("%s: expected update time to be set\n", tc.desc))
+		}
+	}
+}
+
+func TestViewOverride(t *testing.T) {
+	svc := newService()
+	_, err := svc.SaveOverride(adminToken, override)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		token string
+		owner string
+		err   error
+	}{
+		{
+			desc:  "view existing override",
+			token: adminToken,
+			owner: override.Owner,
+			err:   nil,
+		},
+		{
+			desc:  "view non-existing override",
+			token: adminToken,
+			owner: "unknown",
+			err:   flags.ErrNotFound,
+		},
+		{
+			desc:  "view override as non-admin user",
+			token: userToken,
+			owner: override.Owner,
+			err:   flags.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := svc.ViewOverride(tc.token, tc.owner)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestListOverrides(t *testing.T) {
+	svc := newService()
+	_, err := svc.SaveOverride(adminToken, override)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	all, err := svc.ListOverrides(adminToken)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Len(t, all, 1, fmt.Sprintf("expected 1 override got %d", len(all)))
+
+	_, err = svc.ListOverrides(userToken)
+	assert.Equal(t, flags.ErrUnauthorizedAccess, err, fmt.Sprintf("expected %s got %s", flags.ErrUnauthorizedAccess, err))
+}
+
+func TestRemoveOverride(t *testing.T) {
+	svc := newService()
+	_, err := svc.SaveOverride(adminToken, override)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	err = svc.RemoveOverride(userToken, override.Owner)
+	assert.Equal(t, flags.ErrUnauthorizedAccess, err, fmt.Sprintf("expected %s got %s", flags.ErrUnauthorizedAccess, err))
+
+	err = svc.RemoveOverride(adminToken, override.Owner)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	_, err = svc.ViewOverride(adminToken, override.Owner)
+	assert.Equal(t, flags.ErrNotFound, err, fmt.Sprintf("expected %s got %s", flags.ErrNotFound, err))
+}
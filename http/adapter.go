@@ -9,19 +9,52 @@
 // Mainflux http adapter service functionality.
 package http
 
-import "github.com/mainflux/mainflux"
+import (
+	"errors"
 
-var _ mainflux.MessagePublisher = (*adapterService)(nil)
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/ws"
+)
+
+// ErrFailedSubscription indicates that client couldn't subscribe to specified channel.
+var ErrFailedSubscription = errors.New("failed to subscribe to a channel")
+
+// Service specifies HTTP adapter API.
+type Service interface {
+	mainflux.MessagePublisher
+	Subscriber
+}
+
+// Subscriber specifies an API for receiving messages published to channels.
+type Subscriber interface {
+	// Subscribe delivers messages published to the channel with the given
+	// id and subtopic over the provided channel, until it is closed.
+	Subscribe(string, string, *ws.Channel) error
+}
+
+var _ Service = (*adapterService)(nil)
 
 type adapterService struct {
 	pub mainflux.MessagePublisher
+	sub Subscriber
 }
 
-// New instantiates the HTTP adapter implementation.
-func New(pub mainflux.MessagePublisher) mainflux.MessagePublisher {
-	return &adapterService{pub}
+// New instantiates the HTTP adapter implementation. Messages are passed
+// through the publish hooks, in the given order, before being published.
+func New(pub mainflux.MessagePublisher, sub Subscriber, hooks ...mainflux.PublishHook) Service {
+	return &adapterService{
+		pub: mainflux.Chain(pub, hooks...),
+		sub: sub,
+	}
 }
 
 func (as *adapterService) Publish(msg mainflux.RawMessage) error {
 	return as.pub.Publish(msg)
 }
+
+func (as *adapterService) Subscribe(chanID, subtopic string, channel *ws.Channel) error {
+	if err := as.sub.Subscribe(chanID, subtopic, channel); err != nil {
+		return ErrFailedSubscription
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package api_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/conformance"
+	adapter "github.com/mainflux/mainflux/http"
+	"github.com/mainflux/mainflux/ws"
+)
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, func(env conformance.Env) conformance.Client {
+		pubsub := brokerPubSub{env.Broker}
+		svc := adapter.New(pubsub, pubsub)
+		return conformanceClient{newHTTPServer(svc, nil, env.Things)}
+	})
+}
+
+// brokerPubSub wires the adapter to the conformance suite's broker.
+type brokerPubSub struct {
+	broker *conformance.Broker
+}
+
+func (ps brokerPubSub) Publish(msg mainflux.RawMessage) error {
+	return ps.broker.Publish(msg)
+}
+
+func (ps brokerPubSub) Subscribe(chanID, subtopic string, channel *ws.Channel) error {
+	id := ps.broker.Subscribe(chanID, subtopic, channel.Send)
+	go func() {
+		<-channel.Closed
+		ps.broker.Unsubscribe(id)
+	}()
+
+	return nil
+}
+
+type conformanceClient struct {
+	ts *httptest.Server
+}
+
+func (c conformanceClient) Publish(key, chanID, subtopic string, payload []byte) error {
+	req, err := http.NewRequest(http.MethodPost, c.url(chanID, subtopic), bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/senml+json")
+	if key != "" {
+		req.Header.Set("Authorization", key)
+	}
+
+	res, err := c.ts.Client().Do(req)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+
+	return conformanceError(res.StatusCode)
+}
+
+func (c conformanceClient) Subscribe(key, chanID, subtopic string) (conformance.Subscription, error) {
+	req, err := http.NewRequest(http.MethodGet, c.url(chanID, subtopic), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	if key != "" {
+		req.Header.Set("Authorization", key)
+	}
+
+	res, err := c.ts.Client().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := conformanceError(res.StatusCode); err != nil {
+		res.Body.Close()
+		return nil, err
+	}
+
+	sub := eventStream{
+		res:      res,
+		messages: make(chan []byte, 10),
+	}
+	go sub.read()
+
+	return sub, nil
+}
+
+func (c conformanceClient) Close() error {
+	c.ts.Close()
+	return nil
+}
+
+func (c conformanceClient) url(chanID, subtopic string) string {
+	url := fmt.Sprintf("%s/channels/%s/messages", c.ts.URL, chanID)
+	if subtopic != "" {
+		url = fmt.Sprintf("%s/%s", url, strings.Replace(subtopic, ".", "/", -1))
+	}
+
+	return url
+}
+
+// eventStream reads the payloads of the messages delivered as Server-Sent
+// Events.
+type eventStream struct {
+	res      *http.Response
+	messages chan []byte
+}
+
+func (es eventStream) read() {
+	defer close(es.messages)
+
+	scanner := bufio.NewScanner(es.res.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data: ") {
+			continue
+		}
+
+		var msg struct {
+			Payload []byte `json:"payload"`
+		}
+		if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &msg); err != nil {
+			continue
+		}
+		es.messages <- msg.Payload
+	}
+}
+
+func (es eventStream) Messages() <-chan []byte {
+	return es.messages
+}
+
+func (es eventStream) Close() error {
+	return es.res.Body.Close()
+}
+
+func conformanceError(status int) error {
+	switch status {
+	case http.StatusOK, http.StatusAccepted:
+		return nil
+	case http.StatusBadRequest:
+		return conformance.ErrMalformed
+	case http.StatusForbidden:
+		return conformance.ErrUnauthorized
+	case http.StatusServiceUnavailable:
+		return conformance.ErrUnavailable
+	default:
+		return fmt.Errorf("unexpected status %d", status)
+	}
+}
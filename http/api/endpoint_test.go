@@ -8,37 +8,47 @@
 package api_test
 
 import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/mainflux/mainflux"
 	adapter "github.com/mainflux/mainflux/http"
 	"github.com/mainflux/mainflux/http/api"
 	"github.com/mainflux/mainflux/http/mocks"
+	"github.com/mainflux/mainflux/ws"
 	"github.com/stretchr/testify/assert"
 )
 
-func newService() mainflux.MessagePublisher {
-	pub := mocks.NewPublisher()
-	return adapter.New(pub)
+const maxPayloadSize = 1024
+
+func newService() adapter.Service {
+	pubsub := mocks.NewPubSub()
+	return adapter.New(pubsub, pubsub)
 }
 
-func newHTTPServer(pub mainflux.MessagePublisher, cc mainflux.ThingsServiceClient) *httptest.Server {
-	mux := api.MakeHandler(pub, cc)
+func newHTTPServer(svc adapter.Service, sessions *ws.Sessions, cc mainflux.ThingsServiceClient) *httptest.Server {
+	mux := api.MakeHandler(svc, sessions, cc, maxPayloadSize)
 	return httptest.NewServer(mux)
 }
 
 type testRequest struct {
-	client      *http.Client
-	method      string
-	url         string
-	contentType string
-	token       string
-	body        io.Reader
+	client          *http.Client
+	method          string
+	url             string
+	contentType     string
+	contentEncoding string
+	token           string
+	body            io.Reader
 }
 
 func (tr testRequest) make() (*http.Response, error) {
@@ -52,24 +62,46 @@ func (tr testRequest) make() (*http.Response, error) {
 	if tr.contentType != "" {
 		req.Header.Set("Content-Type", tr.contentType)
 	}
+	if tr.contentEncoding != "" {
+		req.Header.Set("Content-Encoding", tr.contentEncoding)
+	}
 	return tr.client.Do(req)
 }
 
+func compress(encoding, data string) string {
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch encoding {
+	case "gzip":
+		w = gzip.NewWriter(&buf)
+	case "deflate":
+		w = zlib.NewWriter(&buf)
+	default:
+		return data
+	}
+	w.Write([]byte(data))
+	w.Close()
+	return buf.String()
+}
+
 func TestPublish(t *testing.T) {
-	chanID := "1"
+	chanID := "123e4567-e89b-12d3-a456-000000000001"
 	contentType := "application/senml+json"
 	token := "auth_token"
 	invalidToken := "invalid_token"
 	msg := `[{"n":"current","t":-1,"v":1.6}]`
 	thingsClient := mocks.NewThingsClient(map[string]string{token: chanID})
 	pub := newService()
-	ts := newHTTPServer(pub, thingsClient)
+	ts := newHTTPServer(pub, nil, thingsClient)
 	defer ts.Close()
 
+	largeMsg := strings.Repeat("a", maxPayloadSize+1)
+
 	cases := map[string]struct {
 		chanID      string
 		msg         string
 		contentType string
+		encoding    string
 		auth        string
 		status      int
 	}{
@@ -80,6 +112,13 @@ func TestPublish(t *testing.T) {
 			auth:        token,
 			status:      http.StatusAccepted,
 		},
+		"publish message to malformed channel": {
+			chanID:      "invalid",
+			msg:         msg,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
 		"publish message without authorization token": {
 			chanID:      chanID,
 			msg:         msg,
@@ -108,6 +147,53 @@ func TestPublish(t *testing.T) {
 			auth:        token,
 			status:      http.StatusBadRequest,
 		},
+		"publish gzip encoded message": {
+			chanID:      chanID,
+			msg:         compress("gzip", msg),
+			contentType: contentType,
+			encoding:    "gzip",
+			auth:        token,
+			status:      http.StatusAccepted,
+		},
+		"publish deflate encoded message": {
+			chanID:      chanID,
+			msg:         compress("deflate", msg),
+			contentType: contentType,
+			encoding:    "deflate",
+			auth:        token,
+			status:      http.StatusAccepted,
+		},
+		"publish message with malformed gzip encoding": {
+			chanID:      chanID,
+			msg:         msg,
+			contentType: contentType,
+			encoding:    "gzip",
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		"publish message with unsupported encoding": {
+			chanID:      chanID,
+			msg:         msg,
+			contentType: contentType,
+			encoding:    "br",
+			auth:        token,
+			status:      http.StatusUnsupportedMediaType,
+		},
+		"publish too large message": {
+			chanID:      chanID,
+			msg:         largeMsg,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusRequestEntityTooLarge,
+		},
+		"publish gzip encoded message too large after decompression": {
+			chanID:      chanID,
+			msg:         compress("gzip", largeMsg),
+			contentType: contentType,
+			encoding:    "gzip",
+			auth:        token,
+			status:      http.StatusRequestEntityTooLarge,
+		},
 		"publish message unable to authorize": {
 			chanID:      chanID,
 			msg:         msg,
@@ -119,15 +205,159 @@ func TestPublish(t *testing.T) {
 
 	for desc, tc := range cases {
 		req := testRequest{
-			client:      ts.Client(),
-			method:      http.MethodPost,
-			url:         fmt.Sprintf("%s/channels/%s/messages", ts.URL, tc.chanID),
-			contentType: tc.contentType,
-			token:       tc.auth,
-			body:        strings.NewReader(tc.msg),
+			client:          ts.Client(),
+			method:          http.MethodPost,
+			url:             fmt.Sprintf("%s/channels/%s/messages", ts.URL, tc.chanID),
+			contentType:     tc.contentType,
+			contentEncoding: tc.encoding,
+			token:           tc.auth,
+			body:            strings.NewReader(tc.msg),
 		}
 		res, err := req.make()
 		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", desc, err))
 		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", desc, tc.status, res.StatusCode))
 	}
 }
+
+func TestPoll(t *testing.T) {
+	chanID := "123e4567-e89b-12d3-a456-000000000001"
+	token := "auth_token"
+	payload := `[{"n":"current","t":-1,"v":1.6}]`
+	thingsClient := mocks.NewThingsClient(map[string]string{token: chanID})
+	svc := newService()
+	ts := newHTTPServer(svc, ws.NewSessions(time.Minute, 10), thingsClient)
+	defer ts.Close()
+
+	cases := []struct {
+		desc     string
+		auth     string
+		query    string
+		publish  bool
+		status   int
+		messages int
+	}{
+		{
+			desc:   "poll messages without authorization token",
+			auth:   "",
+			query:  "timeout=0",
+			status: http.StatusForbidden,
+		},
+		{
+			desc:   "poll messages with invalid authorization token",
+			auth:   "invalid_token",
+			query:  "timeout=0",
+			status: http.StatusForbidden,
+		},
+		{
+			desc:   "poll messages with invalid timeout",
+			auth:   token,
+			query:  "timeout=invalid",
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "poll messages with too long timeout",
+			auth:   token,
+			query:  "timeout=3600",
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "poll messages without pending messages",
+			auth:   token,
+			query:  "timeout=0",
+			status: http.StatusNoContent,
+		},
+		{
+			desc:     "poll messages published since the last poll",
+			auth:     token,
+			query:    "timeout=0",
+			publish:  true,
+			status:   http.StatusOK,
+			messages: 2,
+		},
+		{
+			desc:   "poll messages after all messages are received",
+			auth:   token,
+			query:  "timeout=0",
+			status: http.StatusNoContent,
+		},
+	}
+
+	resume := ""
+	for _, tc := range cases {
+		if tc.publish {
+			for i := 0; i < tc.messages; i++ {
+				err := svc.Publish(mainflux.RawMessage{Channel: chanID, Payload: []byte(payload)})
+				assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+			}
+		}
+
+		query := tc.query
+		if resume != "" {
+			query = fmt.Sprintf("%s&resume=%s", query, resume)
+		}
+
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/channels/%s/messages?%s", ts.URL, chanID, query),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+
+		if token := res.Header.Get("Resume-Token"); token != "" {
+			resume = token
+		}
+
+		var body struct {
+			Messages []struct {
+				Channel string `json:"channel"`
+				Payload []byte `json:"payload"`
+			} `json:"messages"`
+		}
+		json.NewDecoder(res.Body).Decode(&body)
+		res.Body.Close()
+
+		assert.Equal(t, tc.messages, len(body.Messages), fmt.Sprintf("%s: expected %d messages got %d", tc.desc, tc.messages, len(body.Messages)))
+		for _, msg := range body.Messages {
+			assert.Equal(t, payload, string(msg.Payload), fmt.Sprintf("%s: expected payload %s got %s", tc.desc, payload, msg.Payload))
+		}
+	}
+}
+
+func TestStream(t *testing.T) {
+	chanID := "123e4567-e89b-12d3-a456-000000000001"
+	token := "auth_token"
+	payload := `[{"n":"current","t":-1,"v":1.6}]`
+	thingsClient := mocks.NewThingsClient(map[string]string{token: chanID})
+	svc := newService()
+	ts := newHTTPServer(svc, nil, thingsClient)
+	defer ts.Close()
+
+	url := fmt.Sprintf("%s/channels/%s/messages?authorization=%s", ts.URL, chanID, token)
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	req.Header.Set("Accept", "text/event-stream")
+
+	res, err := ts.Client().Do(req)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode, fmt.Sprintf("expected status code %d got %d", http.StatusOK, res.StatusCode))
+	assert.Equal(t, "text/event-stream", res.Header.Get("Content-Type"), fmt.Sprintf("expected event stream content type got %s", res.Header.Get("Content-Type")))
+
+	err = svc.Publish(mainflux.RawMessage{Channel: chanID, Payload: []byte(payload)})
+	assert.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+
+	line, err := bufio.NewReader(res.Body).ReadString('\n')
+	assert.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+
+	var msg struct {
+		Channel string `json:"channel"`
+		Payload []byte `json:"payload"`
+	}
+	err = json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &msg)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	assert.Equal(t, chanID, msg.Channel, fmt.Sprintf("expected channel %s got %s", chanID, msg.Channel))
+	assert.Equal(t, payload, string(msg.Payload), fmt.Sprintf("expected payload %s got %s", payload, msg.Payload))
+}
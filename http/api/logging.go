@@ -14,18 +14,20 @@ import (
 	"time"
 
 	"github.com/mainflux/mainflux"
+	adapter "github.com/mainflux/mainflux/http"
 	log "github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/ws"
 )
 
-var _ mainflux.MessagePublisher = (*loggingMiddleware)(nil)
+var _ adapter.Service = (*loggingMiddleware)(nil)
 
 type loggingMiddleware struct {
 	logger log.Logger
-	svc    mainflux.MessagePublisher
+	svc    adapter.Service
 }
 
 // LoggingMiddleware adds logging facilities to the adapter.
-func LoggingMiddleware(svc mainflux.MessagePublisher, logger log.Logger) mainflux.MessagePublisher {
+func LoggingMiddleware(svc adapter.Service, logger log.Logger) adapter.Service {
 	return &loggingMiddleware{logger, svc}
 }
 
@@ -45,3 +47,20 @@ func (lm *loggingMiddleware) Publish(msg mainflux.RawMessage) (err error) {
 
 	return lm.svc.Publish(msg)
 }
+
+func (lm *loggingMiddleware) Subscribe(chanID, subtopic string, channel *ws.Channel) (err error) {
+	defer func(begin time.Time) {
+		destChannel := chanID
+		if subtopic != "" {
+			destChannel = fmt.Sprintf("%s.%s", destChannel, subtopic)
+		}
+		message := fmt.Sprintf("Method subscribe to channel %s took %s to complete", destChannel, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Subscribe(chanID, subtopic, channel)
+}
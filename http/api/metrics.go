@@ -14,18 +14,20 @@ import (
 
 	"github.com/go-kit/kit/metrics"
 	"github.com/mainflux/mainflux"
+	adapter "github.com/mainflux/mainflux/http"
+	"github.com/mainflux/mainflux/ws"
 )
 
-var _ mainflux.MessagePublisher = (*metricsMiddleware)(nil)
+var _ adapter.Service = (*metricsMiddleware)(nil)
 
 type metricsMiddleware struct {
 	counter metrics.Counter
 	latency metrics.Histogram
-	svc     mainflux.MessagePublisher
+	svc     adapter.Service
 }
 
 // MetricsMiddleware instruments adapter by tracking request count and latency.
-func MetricsMiddleware(svc mainflux.MessagePublisher, counter metrics.Counter, latency metrics.Histogram) mainflux.MessagePublisher {
+func MetricsMiddleware(svc adapter.Service, counter metrics.Counter, latency metrics.Histogram) adapter.Service {
 	return &metricsMiddleware{
 		counter: counter,
 		latency: latency,
@@ -41,3 +43,12 @@ func (mm *metricsMiddleware) Publish(msg mainflux.RawMessage) error {
 
 	return mm.svc.Publish(msg)
 }
+
+func (mm *metricsMiddleware) Subscribe(chanID, subtopic string, channel *ws.Channel) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "subscribe").Add(1)
+		mm.latency.With("method", "subscribe").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.Subscribe(chanID, subtopic, channel)
+}
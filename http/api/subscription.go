@@ -0,0 +1,361 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-zoo/bone"
+	"github.com/mainflux/mainflux"
+	adapter "github.com/mainflux/mainflux/http"
+	"github.com/mainflux/mainflux/identifiers"
+	"github.com/mainflux/mainflux/things"
+	"github.com/mainflux/mainflux/ws"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	contentType    = "application/json"
+	resumeQuery    = "resume"
+	resumeToken    = "Resume-Token"
+	lastEventID    = "Last-Event-ID"
+	timeoutQuery   = "timeout"
+	eventStream    = "text/event-stream"
+	defPollTimeout = 30 * time.Second
+	maxPollTimeout = 60 * time.Second
+	maxPollBatch   = 100
+	keepAlive      = 15 * time.Second
+)
+
+type message struct {
+	Channel     string  `json:"channel"`
+	Subtopic    string  `json:"subtopic,omitempty"`
+	Publisher   string  `json:"publisher"`
+	Protocol    string  `json:"protocol"`
+	ContentType string  `json:"content_type,omitempty"`
+	Payload     []byte  `json:"payload"`
+	Received    float64 `json:"received,omitempty"`
+	Seq         uint64  `json:"seq,omitempty"`
+}
+
+type pollRes struct {
+	Messages []message `json:"messages"`
+}
+
+type subscription struct {
+	pubID    string
+	chanID   string
+	subtopic string
+	token    string
+	timeout  time.Duration
+	sessions *ws.Sessions
+}
+
+// subscribe serves long-polling requests and, for clients accepting
+// text/event-stream, Server-Sent Events streams.
+func subscribe(svc adapter.Service, sessions *ws.Sessions) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		sub, err := decodeSubscription(r, sessions)
+		if err != nil {
+			encodeError(r.Context(), err, w)
+			return
+		}
+
+		if strings.Contains(r.Header.Get("Accept"), eventStream) {
+			sub.stream(svc, w, r)
+			return
+		}
+		sub.poll(svc, w, r)
+	}
+}
+
+func decodeSubscription(r *http.Request, sessions *ws.Sessions) (subscription, error) {
+	channelParts := channelPartRegExp.FindStringSubmatch(r.RequestURI)
+	if len(channelParts) < 2 {
+		return subscription{}, errMalformedData
+	}
+
+	chanID, err := identifiers.Parse(bone.GetValue(r, "id"))
+	if err != nil {
+		return subscription{}, err
+	}
+
+	subtopic, err := parseSubtopic(channelParts[2])
+	if err != nil {
+		return subscription{}, err
+	}
+
+	timeout := defPollTimeout
+	if t := bone.GetQuery(r, timeoutQuery); len(t) > 0 {
+		secs, err := strconv.ParseUint(t[0], 10, 32)
+		if err != nil || time.Duration(secs)*time.Second > maxPollTimeout {
+			return subscription{}, errMalformedData
+		}
+		timeout = time.Duration(secs) * time.Second
+	}
+
+	pubID, err := authorizeSubscriber(r, chanID, subtopic)
+	if err != nil {
+		return subscription{}, err
+	}
+
+	sub := subscription{
+		pubID:    pubID,
+		chanID:   chanID,
+		subtopic: subtopic,
+		timeout:  timeout,
+		sessions: sessions,
+	}
+
+	if sessions.Enabled() {
+		sub.token = r.Header.Get(lastEventID)
+		if t := bone.GetQuery(r, resumeQuery); len(t) > 0 {
+			sub.token = t[0]
+		}
+		if sub.token == "" {
+			if sub.token, err = sessions.Token(); err != nil {
+				return subscription{}, err
+			}
+		}
+	}
+
+	return sub, nil
+}
+
+// authorizeSubscriber checks whether the thing, or the user owning the
+// channel, identified by the provided key can receive channel messages.
+// Since browsers can't set headers of event stream requests, the key can
+// be passed as the authorization query parameter too.
+func authorizeSubscriber(r *http.Request, chanID, subtopic string) (string, error) {
+	key := r.Header.Get("Authorization")
+	if key == "" {
+		keys := bone.GetQuery(r, "authorization")
+		if len(keys) == 0 {
+			return "", things.ErrUnauthorizedAccess
+		}
+		key = keys[0]
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	req := &mainflux.AccessReq{Token: key, ChanID: chanID, Action: mainflux.ActionSubscribe, Subtopic: subtopic}
+	id, err := auth.CanAccess(ctx, req)
+	if err == nil {
+		return id.GetValue(), nil
+	}
+
+	if e, ok := status.FromError(err); !ok || e.Code() != codes.PermissionDenied {
+		return "", err
+	}
+
+	user, err := auth.CanRead(ctx, req)
+	if err != nil {
+		return "", err
+	}
+
+	return user.GetValue(), nil
+}
+
+func (sub subscription) key() string {
+	return fmt.Sprintf("%s:%s:%s", sub.pubID, sub.chanID, sub.subtopic)
+}
+
+// open resumes the subscription identified by the resume token, returning
+// the messages buffered since the previous request, or subscribes anew.
+func (sub subscription) open(svc adapter.Service) (*ws.Channel, []mainflux.RawMessage, error) {
+	if sub.sessions.Enabled() {
+		if channel, missed, err := sub.sessions.Resume(sub.token, sub.key()); err == nil {
+			return channel, missed, nil
+		}
+	}
+
+	channel := ws.NewChannel()
+	if err := svc.Subscribe(sub.chanID, sub.subtopic, channel); err != nil {
+		return nil, nil, err
+	}
+
+	return channel, nil, nil
+}
+
+// close keeps the subscription alive until the next request, if sessions
+// are enabled, or closes it otherwise.
+func (sub subscription) close(channel *ws.Channel) {
+	if sub.sessions.Enabled() {
+		sub.sessions.Detach(sub.token, sub.key(), channel)
+		return
+	}
+
+	// Messages are drained until the channel is closed, so that pending
+	// sends don't block closing.
+	go channel.Close()
+	for range channel.Messages {
+	}
+}
+
+// poll responds with the pending messages or, if there are none, with the
+// first batch of messages received within the poll timeout.
+func (sub subscription) poll(svc adapter.Service, w http.ResponseWriter, r *http.Request) {
+	channel, msgs, err := sub.open(svc)
+	if err != nil {
+		encodeError(r.Context(), err, w)
+		return
+	}
+
+	msgs, ok := sub.wait(r.Context(), channel, msgs)
+	if ok {
+		sub.close(channel)
+	}
+
+	if sub.token != "" {
+		w.Header().Set(resumeToken, sub.token)
+	}
+
+	if len(msgs) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	res := pollRes{Messages: []message{}}
+	for _, msg := range msgs {
+		res.Messages = append(res.Messages, toMessage(msg))
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(res)
+}
+
+// wait waits for the first message unless some are already pending, and
+// then collects the messages received in the meantime without blocking.
+// It returns false if the subscription has been closed.
+func (sub subscription) wait(ctx context.Context, channel *ws.Channel, msgs []mainflux.RawMessage) ([]mainflux.RawMessage, bool) {
+	if len(msgs) == 0 {
+		timer := time.NewTimer(sub.timeout)
+		defer timer.Stop()
+
+		select {
+		case msg, ok := <-channel.Messages:
+			if !ok {
+				return msgs, false
+			}
+			msgs = append(msgs, msg)
+		case <-timer.C:
+			return msgs, true
+		case <-ctx.Done():
+			return msgs, true
+		}
+	}
+
+	for len(msgs) < maxPollBatch {
+		select {
+		case msg, ok := <-channel.Messages:
+			if !ok {
+				return msgs, false
+			}
+			msgs = append(msgs, msg)
+		default:
+			return msgs, true
+		}
+	}
+
+	return msgs, true
+}
+
+// stream writes received messages as Server-Sent Events until the client
+// disconnects. The resume token is sent as the event ID, so that browsers
+// resume the subscription when they reconnect.
+func (sub subscription) stream(svc adapter.Service, w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	channel, missed, err := sub.open(svc)
+	if err != nil {
+		encodeError(r.Context(), err, w)
+		return
+	}
+
+	h := w.Header()
+	h.Set("Content-Type", eventStream)
+	h.Set("Cache-Control", "no-cache")
+	// Prevents reverse proxies, such as NGINX, from buffering the stream.
+	h.Set("X-Accel-Buffering", "no")
+	if sub.token != "" {
+		h.Set(resumeToken, sub.token)
+	}
+	w.WriteHeader(http.StatusOK)
+
+	for _, msg := range missed {
+		sub.writeEvent(w, msg)
+	}
+	flusher.Flush()
+
+	ticker := time.NewTicker(keepAlive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case msg, ok := <-channel.Messages:
+			if !ok {
+				return
+			}
+			if err := sub.writeEvent(w, msg); err != nil {
+				sub.close(channel)
+				return
+			}
+		case <-ticker.C:
+			// Comments keep idle connections from being dropped by proxies.
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				sub.close(channel)
+				return
+			}
+		case <-r.Context().Done():
+			sub.close(channel)
+			return
+		}
+		flusher.Flush()
+	}
+}
+
+func (sub subscription) writeEvent(w http.ResponseWriter, msg mainflux.RawMessage) error {
+	data, err := json.Marshal(toMessage(msg))
+	if err != nil {
+		return err
+	}
+
+	if sub.token != "" {
+		if _, err := fmt.Fprintf(w, "id: %s\n", sub.token); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(w, "data: %s\n\n", data)
+	return err
+}
+
+func toMessage(msg mainflux.RawMessage) message {
+	return message{
+		Channel:     msg.Channel,
+		Subtopic:    msg.Subtopic,
+		Publisher:   msg.Publisher,
+		Protocol:    msg.Protocol,
+		ContentType: msg.ContentType,
+		Payload:     msg.Payload,
+		Received:    msg.Received,
+		Seq:         msg.Seq,
+	}
+}
@@ -8,6 +8,8 @@
 package api
 
 import (
+	"compress/gzip"
+	"compress/zlib"
 	"context"
 	"errors"
 	"io"
@@ -21,7 +23,11 @@ import (
 	kithttp "github.com/go-kit/kit/transport/http"
 	"github.com/go-zoo/bone"
 	"github.com/mainflux/mainflux"
+	adapter "github.com/mainflux/mainflux/http"
+	"github.com/mainflux/mainflux/identifiers"
+	"github.com/mainflux/mainflux/publish"
 	"github.com/mainflux/mainflux/things"
+	"github.com/mainflux/mainflux/ws"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -30,22 +36,31 @@ import (
 const protocol = "http"
 
 var (
-	errMalformedData     = errors.New("malformed request data")
-	errMalformedSubtopic = errors.New("malformed subtopic")
+	errMalformedData       = errors.New("malformed request data")
+	errMalformedSubtopic   = errors.New("malformed subtopic")
+	errUnsupportedEncoding = errors.New("unsupported content encoding")
+	errPayloadTooLarge     = errors.New("payload too large")
 )
 
 var (
 	auth              mainflux.ThingsServiceClient
+	maxPayloadSize    int64
 	channelPartRegExp = regexp.MustCompile(`^/channels/([\w\-]+)/messages(/[^?]*)?(\?.*)?$`)
 )
 
-// MakeHandler returns a HTTP handler for API endpoints.
-func MakeHandler(svc mainflux.MessagePublisher, tc mainflux.ThingsServiceClient) http.Handler {
+// MakeHandler returns a HTTP handler for API endpoints. Request bodies
+// exceeding maxSize bytes, measured after decompression, are rejected.
+// Subscriptions are kept alive between polls by the provided sessions, if
+// enabled.
+func MakeHandler(svc adapter.Service, sessions *ws.Sessions, tc mainflux.ThingsServiceClient, maxSize int64) http.Handler {
 	auth = tc
+	maxPayloadSize = maxSize
 
 	r := bone.New()
 	r.Post("/channels/:id/messages", handshake(svc))
 	r.Post("/channels/:id/messages/*", handshake(svc))
+	r.GetFunc("/channels/:id/messages", subscribe(svc, sessions))
+	r.GetFunc("/channels/:id/messages/*", subscribe(svc, sessions))
 
 	r.GetFunc("/version", mainflux.Version("http"))
 	r.Handle("/metrics", promhttp.Handler())
@@ -102,18 +117,22 @@ func decodeRequest(_ context.Context, r *http.Request) (interface{}, error) {
 		return nil, errMalformedData
 	}
 
-	chanID := bone.GetValue(r, "id")
+	chanID, err := identifiers.Parse(bone.GetValue(r, "id"))
+	if err != nil {
+		return nil, err
+	}
+
 	subtopic, err := parseSubtopic(channelParts[2])
 	if err != nil {
 		return nil, err
 	}
 
-	publisher, err := authorize(r, chanID)
+	publisher, err := authorize(r, chanID, subtopic)
 	if err != nil {
 		return nil, err
 	}
 
-	payload, err := decodePayload(r.Body)
+	payload, err := decodePayload(r.Body, r.Header.Get("Content-Encoding"))
 	if err != nil {
 		return nil, err
 	}
@@ -125,12 +144,22 @@ func decodeRequest(_ context.Context, r *http.Request) (interface{}, error) {
 		Channel:     chanID,
 		Subtopic:    subtopic,
 		Payload:     payload,
+		RemoteAddr:  remoteAddr(r),
 	}
 
 	return msg, nil
 }
 
-func authorize(r *http.Request, chanID string) (string, error) {
+// remoteAddr returns the address of the client, as forwarded by the reverse
+// proxy in front of the adapter, or the address of the peer otherwise.
+func remoteAddr(r *http.Request) string {
+	if ip := r.Header.Get("X-Real-IP"); ip != "" {
+		return ip
+	}
+	return r.RemoteAddr
+}
+
+func authorize(r *http.Request, chanID, subtopic string) (string, error) {
 	apiKey := r.Header.Get("Authorization")
 
 	if apiKey == "" {
@@ -140,7 +169,7 @@ func authorize(r *http.Request, chanID string) (string, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 	defer cancel()
 
-	id, err := auth.CanAccess(ctx, &mainflux.AccessReq{Token: apiKey, ChanID: chanID})
+	id, err := auth.CanAccess(ctx, &mainflux.AccessReq{Token: apiKey, ChanID: chanID, Action: mainflux.ActionPublish, Subtopic: subtopic})
 	if err != nil {
 		return "", err
 	}
@@ -148,12 +177,41 @@ func authorize(r *http.Request, chanID string) (string, error) {
 	return id.GetValue(), nil
 }
 
-func decodePayload(body io.ReadCloser) ([]byte, error) {
-	payload, err := ioutil.ReadAll(body)
+func decodePayload(body io.ReadCloser, encoding string) ([]byte, error) {
+	defer body.Close()
+
+	var reader io.Reader
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "", "identity":
+		reader = body
+	case "gzip":
+		gr, err := gzip.NewReader(body)
+		if err != nil {
+			return nil, errMalformedData
+		}
+		defer gr.Close()
+		reader = gr
+	case "deflate":
+		zr, err := zlib.NewReader(body)
+		if err != nil {
+			return nil, errMalformedData
+		}
+		defer zr.Close()
+		reader = zr
+	default:
+		return nil, errUnsupportedEncoding
+	}
+
+	// Read one byte past the limit to detect oversized payloads without
+	// decompressing the whole body into memory.
+	payload, err := ioutil.ReadAll(io.LimitReader(reader, maxPayloadSize+1))
 	if err != nil {
 		return nil, errMalformedData
 	}
-	defer body.Close()
+
+	if int64(len(payload)) > maxPayloadSize {
+		return nil, errPayloadTooLarge
+	}
 
 	return payload, nil
 }
@@ -165,10 +223,24 @@ func encodeResponse(_ context.Context, w http.ResponseWriter, response interface
 
 func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 	switch err {
-	case errMalformedData, errMalformedSubtopic:
+	case errMalformedData, errMalformedSubtopic, identifiers.ErrMalformedID:
 		w.WriteHeader(http.StatusBadRequest)
 	case things.ErrUnauthorizedAccess:
 		w.WriteHeader(http.StatusForbidden)
+	case errUnsupportedEncoding:
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+	case adapter.ErrFailedSubscription:
+		w.WriteHeader(http.StatusServiceUnavailable)
+	case errPayloadTooLarge, publish.ErrMessageTooLarge:
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+	case publish.ErrMalformedMessage, publish.ErrTransformFailed:
+		w.WriteHeader(http.StatusBadRequest)
+	case publish.ErrRateLimited:
+		w.WriteHeader(http.StatusTooManyRequests)
+	case publish.ErrProtocolNotAllowed:
+		w.WriteHeader(http.StatusForbidden)
+	case publish.ErrNotAcknowledged:
+		w.WriteHeader(http.StatusGatewayTimeout)
 	default:
 		if e, ok := status.FromError(err); ok {
 			switch e.Code() {
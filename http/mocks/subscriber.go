@@ -0,0 +1,67 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package mocks
+
+import (
+	"sync"
+
+	"github.com/mainflux/mainflux"
+	adapter "github.com/mainflux/mainflux/http"
+	"github.com/mainflux/mainflux/ws"
+)
+
+var _ adapter.Subscriber = (*mockPubSub)(nil)
+
+type mockPubSub struct {
+	mu   sync.Mutex
+	subs map[string][]*ws.Channel
+}
+
+// NewPubSub returns mock message broker which delivers published messages
+// to the subscribers of their channel.
+func NewPubSub() interface {
+	mainflux.MessagePublisher
+	adapter.Subscriber
+} {
+	return &mockPubSub{subs: make(map[string][]*ws.Channel)}
+}
+
+func (ps *mockPubSub) Publish(msg mainflux.RawMessage) error {
+	ps.mu.Lock()
+	subs := ps.subs[msg.Channel+msg.Subtopic]
+	ps.mu.Unlock()
+
+	for _, channel := range subs {
+		channel.Send(msg)
+	}
+
+	return nil
+}
+
+func (ps *mockPubSub) Subscribe(chanID, subtopic string, channel *ws.Channel) error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	key := chanID + subtopic
+	ps.subs[key] = append(ps.subs[key], channel)
+
+	go func() {
+		<-channel.Closed
+		ps.mu.Lock()
+		defer ps.mu.Unlock()
+
+		for i, c := range ps.subs[key] {
+			if c == channel {
+				ps.subs[key] = append(ps.subs[key][:i], ps.subs[key][i+1:]...)
+				break
+			}
+		}
+	}()
+
+	return nil
+}
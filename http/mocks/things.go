@@ -56,3 +56,15 @@ func (tc thingsClient) CanAccess(ctx context.Context, req *mainflux.AccessReq, o
 func (tc thingsClient) Identify(ctx context.Context, req *mainflux.Token, opts ...grpc.CallOption) (*mainflux.ThingID, error) {
 	return nil, nil
 }
+
+func (tc thingsClient) IdentifyBatch(ctx context.Context, req *mainflux.Tokens, opts ...grpc.CallOption) (*mainflux.ThingIDs, error) {
+	return nil, nil
+}
+
+func (tc thingsClient) CanRead(ctx context.Context, req *mainflux.AccessReq, opts ...grpc.CallOption) (*mainflux.UserID, error) {
+	return nil, status.Error(codes.PermissionDenied, "invalid credentials provided")
+}
+
+func (tc thingsClient) ListChannelsByThing(ctx context.Context, req *mainflux.ThingID, opts ...grpc.CallOption) (*mainflux.ChannelIDs, error) {
+	return &mainflux.ChannelIDs{}, nil
+}
@@ -10,9 +10,10 @@ package nats
 
 import (
 	"fmt"
+	"time"
 
-	"github.com/gogo/protobuf/proto"
 	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/publish"
 	broker "github.com/nats-io/go-nats"
 )
 
@@ -21,23 +22,24 @@ const prefix = "channel"
 var _ mainflux.MessagePublisher = (*natsPublisher)(nil)
 
 type natsPublisher struct {
-	nc *broker.Conn
+	nc         *broker.Conn
+	ackTimeout time.Duration
 }
 
-// NewMessagePublisher instantiates NATS message publisher.
-func NewMessagePublisher(nc *broker.Conn) mainflux.MessagePublisher {
-	return &natsPublisher{nc}
+// NewMessagePublisher instantiates NATS message publisher. Messages of the
+// channels with the at-least-once delivery are published only once they are
+// acknowledged, which has to happen within the given timeout.
+func NewMessagePublisher(nc *broker.Conn, ackTimeout time.Duration) mainflux.MessagePublisher {
+	return &natsPublisher{
+		nc:         nc,
+		ackTimeout: ackTimeout,
+	}
 }
 
 func (pub *natsPublisher) Publish(msg mainflux.RawMessage) error {
-	data, err := proto.Marshal(&msg)
-	if err != nil {
-		return err
-	}
-
 	subject := fmt.Sprintf("%s.%s", prefix, msg.Channel)
 	if msg.Subtopic != "" {
 		subject = fmt.Sprintf("%s.%s", subject, msg.Subtopic)
 	}
-	return pub.nc.Publish(subject, data)
+	return publish.Deliver(pub.nc, subject, msg, pub.ackTimeout)
 }
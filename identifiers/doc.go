@@ -0,0 +1,11 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// Package identifiers contains the parsing of the entity identifiers the
+// service APIs receive, shared by the services so that the malformed ones
+// are rejected the same way before they reach the services.
+package identifiers
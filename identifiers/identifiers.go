@@ -0,0 +1,70 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package identifiers
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gofrs/uuid"
+)
+
+const (
+	ulidLength = 26
+
+	// crockford is the base32 alphabet the ULIDs are encoded with.
+	crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+)
+
+// ErrMalformedID indicates the identifier that is neither a UUID nor a ULID.
+var ErrMalformedID = errors.New("malformed identifier")
+
+// Parse validates the identifier and returns its canonical form, so that
+// the differently written forms of the same identifier are treated as one.
+// The UUIDs are canonicalized to the lowercase hyphenated form, and the
+// ULIDs to the uppercase form.
+func Parse(id string) (string, error) {
+	if u, err := uuid.FromString(id); err == nil {
+		return u.String(), nil
+	}
+
+	if IsULID(id) {
+		return strings.ToUpper(id), nil
+	}
+
+	return "", ErrMalformedID
+}
+
+// IsUUID returns true if the identifier is a UUID written in any of the
+// accepted forms.
+func IsUUID(id string) bool {
+	_, err := uuid.FromString(id)
+	return err == nil
+}
+
+// IsULID returns true if the identifier is a ULID, regardless of the case.
+func IsULID(id string) bool {
+	if len(id) != ulidLength {
+		return false
+	}
+
+	id = strings.ToUpper(id)
+	// The first character holds the 3 most significant bits only, since the
+	// ULIDs are 128 bits long.
+	if id[0] > '7' {
+		return false
+	}
+
+	for i := 0; i < len(id); i++ {
+		if strings.IndexByte(crockford, id[i]) < 0 {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,135 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package identifiers_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux/identifiers"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	canonicalUUID = "123e4567-e89b-12d3-a456-426655440000"
+	canonicalULID = "01ARZ3NDEKTSV4RRFFQ69G5FAV"
+)
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		desc string
+		id   string
+		res  string
+		err  error
+	}{
+		{
+			desc: "parse canonical UUID",
+			id:   canonicalUUID,
+			res:  canonicalUUID,
+		},
+		{
+			desc: "parse uppercase UUID",
+			id:   "123E4567-E89B-12D3-A456-426655440000",
+			res:  canonicalUUID,
+		},
+		{
+			desc: "parse UUID without hyphens",
+			id:   "123e4567e89b12d3a456426655440000",
+			res:  canonicalUUID,
+		},
+		{
+			desc: "parse braced UUID",
+			id:   "{123e4567-e89b-12d3-a456-426655440000}",
+			res:  canonicalUUID,
+		},
+		{
+			desc: "parse UUID URN",
+			id:   "urn:uuid:123e4567-e89b-12d3-a456-426655440000",
+			res:  canonicalUUID,
+		},
+		{
+			desc: "parse canonical ULID",
+			id:   canonicalULID,
+			res:  canonicalULID,
+		},
+		{
+			desc: "parse lowercase ULID",
+			id:   "01arz3ndektsv4rrffq69g5fav",
+			res:  canonicalULID,
+		},
+		{
+			desc: "parse UUID with invalid character",
+			id:   "123e4567-e89b-12d3-a456-42665544000g",
+			err:  identifiers.ErrMalformedID,
+		},
+		{
+			desc: "parse truncated UUID",
+			id:   "123e4567-e89b-12d3-a456-42665544000",
+			err:  identifiers.ErrMalformedID,
+		},
+		{
+			desc: "parse ULID with excluded character",
+			id:   "01ARZ3NDEKTSV4RRFFQ69G5FAU",
+			err:  identifiers.ErrMalformedID,
+		},
+		{
+			desc: "parse overflowing ULID",
+			id:   "81ARZ3NDEKTSV4RRFFQ69G5FAV",
+			err:  identifiers.ErrMalformedID,
+		},
+		{
+			desc: "parse numeric identifier",
+			id:   "1",
+			err:  identifiers.ErrMalformedID,
+		},
+		{
+			desc: "parse empty identifier",
+			id:   "",
+			err:  identifiers.ErrMalformedID,
+		},
+	}
+
+	for _, tc := range cases {
+		res, err := identifiers.Parse(tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.res, res, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.res, res))
+	}
+}
+
+func TestIsUUID(t *testing.T) {
+	cases := map[string]struct {
+		id  string
+		res bool
+	}{
+		"check UUID":           {canonicalUUID, true},
+		"check ULID":           {canonicalULID, false},
+		"check malformed UUID": {"123e4567-e89b", false},
+	}
+
+	for desc, tc := range cases {
+		res := identifiers.IsUUID(tc.id)
+		assert.Equal(t, tc.res, res, fmt.Sprintf("%s: expected %t got %t\n", desc, tc.res, res))
+	}
+}
+
+func TestIsULID(t *testing.T) {
+	cases := map[string]struct {
+		id  string
+		res bool
+	}{
+		"check ULID":           {canonicalULID, true},
+		"check lowercase ULID": {"01arz3ndektsv4rrffq69g5fav", true},
+		"check UUID":           {canonicalUUID, false},
+		"check long ULID":      {canonicalULID + "0", false},
+	}
+
+	for desc, tc := range cases {
+		res := identifiers.IsULID(tc.id)
+		assert.Equal(t, tc.res, res, fmt.Sprintf("%s: expected %t got %t\n", desc, tc.res, res))
+	}
+}
@@ -26,6 +26,8 @@ const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 type AccessReq struct {
 	Token                string   `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
 	ChanID               string   `protobuf:"bytes,2,opt,name=chanID,proto3" json:"chanID,omitempty"`
+	Action               string   `protobuf:"bytes,3,opt,name=action,proto3" json:"action,omitempty"`
+	Subtopic             string   `protobuf:"bytes,4,opt,name=subtopic,proto3" json:"subtopic,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -78,6 +80,20 @@ func (m *AccessReq) GetChanID() string {
 	return ""
 }
 
+func (m *AccessReq) GetAction() string {
+	if m != nil {
+		return m.Action
+	}
+	return ""
+}
+
+func (m *AccessReq) GetSubtopic() string {
+	if m != nil {
+		return m.Subtopic
+	}
+	return ""
+}
+
 type ThingID struct {
 	Value                string   `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -174,6 +190,7 @@ func (m *Token) GetValue() string {
 
 type UserID struct {
 	Value                string   `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	Actor                string   `protobuf:"bytes,2,opt,name=actor,proto3" json:"actor,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -219,33 +236,192 @@ func (m *UserID) GetValue() string {
 	return ""
 }
 
+func (m *UserID) GetActor() string {
+	if m != nil {
+		return m.Actor
+	}
+	return ""
+}
+
+type ChannelIDs struct {
+	Values               []string `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ChannelIDs) Reset()         { *m = ChannelIDs{} }
+func (m *ChannelIDs) String() string { return proto.CompactTextString(m) }
+func (*ChannelIDs) ProtoMessage()    {}
+func (*ChannelIDs) Descriptor() ([]byte, []int) {
+	return fileDescriptor_41f4a519b878ee3b, []int{4}
+}
+func (m *ChannelIDs) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ChannelIDs) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ChannelIDs.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ChannelIDs) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChannelIDs.Merge(m, src)
+}
+func (m *ChannelIDs) XXX_Size() int {
+	return m.Size()
+}
+func (m *ChannelIDs) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChannelIDs.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChannelIDs proto.InternalMessageInfo
+
+func (m *ChannelIDs) GetValues() []string {
+	if m != nil {
+		return m.Values
+	}
+	return nil
+}
+
+type Tokens struct {
+	Values               []string `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Tokens) Reset()         { *m = Tokens{} }
+func (m *Tokens) String() string { return proto.CompactTextString(m) }
+func (*Tokens) ProtoMessage()    {}
+func (*Tokens) Descriptor() ([]byte, []int) {
+	return fileDescriptor_41f4a519b878ee3b, []int{5}
+}
+func (m *Tokens) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Tokens) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Tokens.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Tokens) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Tokens.Merge(m, src)
+}
+func (m *Tokens) XXX_Size() int {
+	return m.Size()
+}
+func (m *Tokens) XXX_DiscardUnknown() {
+	xxx_messageInfo_Tokens.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Tokens proto.InternalMessageInfo
+
+func (m *Tokens) GetValues() []string {
+	if m != nil {
+		return m.Values
+	}
+	return nil
+}
+
+type ThingIDs struct {
+	Values               []string `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ThingIDs) Reset()         { *m = ThingIDs{} }
+func (m *ThingIDs) String() string { return proto.CompactTextString(m) }
+func (*ThingIDs) ProtoMessage()    {}
+func (*ThingIDs) Descriptor() ([]byte, []int) {
+	return fileDescriptor_41f4a519b878ee3b, []int{6}
+}
+func (m *ThingIDs) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ThingIDs) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ThingIDs.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ThingIDs) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ThingIDs.Merge(m, src)
+}
+func (m *ThingIDs) XXX_Size() int {
+	return m.Size()
+}
+func (m *ThingIDs) XXX_DiscardUnknown() {
+	xxx_messageInfo_ThingIDs.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ThingIDs proto.InternalMessageInfo
+
+func (m *ThingIDs) GetValues() []string {
+	if m != nil {
+		return m.Values
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*AccessReq)(nil), "mainflux.AccessReq")
 	proto.RegisterType((*ThingID)(nil), "mainflux.ThingID")
 	proto.RegisterType((*Token)(nil), "mainflux.Token")
 	proto.RegisterType((*UserID)(nil), "mainflux.UserID")
+	proto.RegisterType((*ChannelIDs)(nil), "mainflux.ChannelIDs")
+	proto.RegisterType((*Tokens)(nil), "mainflux.Tokens")
+	proto.RegisterType((*ThingIDs)(nil), "mainflux.ThingIDs")
 }
 
 func init() { proto.RegisterFile("internal.proto", fileDescriptor_41f4a519b878ee3b) }
 
 var fileDescriptor_41f4a519b878ee3b = []byte{
-	// 241 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xe2, 0xcb, 0xcc, 0x2b, 0x49,
-	0x2d, 0xca, 0x4b, 0xcc, 0xd1, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0xe2, 0xc8, 0x4d, 0xcc, 0xcc,
-	0x4b, 0xcb, 0x29, 0xad, 0x50, 0xb2, 0xe4, 0xe2, 0x74, 0x4c, 0x4e, 0x4e, 0x2d, 0x2e, 0x0e, 0x4a,
-	0x2d, 0x14, 0x12, 0xe1, 0x62, 0x2d, 0xc9, 0xcf, 0x4e, 0xcd, 0x93, 0x60, 0x54, 0x60, 0xd4, 0xe0,
-	0x0c, 0x82, 0x70, 0x84, 0xc4, 0xb8, 0xd8, 0x92, 0x33, 0x12, 0xf3, 0x3c, 0x5d, 0x24, 0x98, 0xc0,
-	0xc2, 0x50, 0x9e, 0x92, 0x3c, 0x17, 0x7b, 0x48, 0x46, 0x66, 0x5e, 0xba, 0xa7, 0x0b, 0x48, 0x63,
-	0x59, 0x62, 0x4e, 0x69, 0x2a, 0x4c, 0x23, 0x98, 0xa3, 0x24, 0xcb, 0xc5, 0x1a, 0x02, 0x36, 0x01,
-	0xbb, 0xb4, 0x1c, 0x17, 0x5b, 0x68, 0x71, 0x6a, 0x11, 0x2e, 0xed, 0x46, 0x15, 0x5c, 0xbc, 0x60,
-	0xf3, 0x8b, 0x83, 0x53, 0x8b, 0xca, 0x32, 0x93, 0x53, 0x85, 0x4c, 0xb9, 0x38, 0x9d, 0x13, 0xf3,
-	0x20, 0xce, 0x15, 0x12, 0xd6, 0x83, 0xf9, 0x41, 0x0f, 0xee, 0x01, 0x29, 0x41, 0x84, 0x20, 0xd4,
-	0x69, 0x4a, 0x0c, 0x42, 0x06, 0x5c, 0x1c, 0x9e, 0x29, 0xa9, 0x79, 0x25, 0x99, 0x69, 0x95, 0x42,
-	0xfc, 0x48, 0x0a, 0x40, 0x4e, 0xc3, 0xaa, 0xc3, 0xc8, 0x9e, 0x8b, 0x07, 0xe4, 0x32, 0xb8, 0xc5,
-	0xfa, 0xf8, 0x4c, 0x10, 0x40, 0x08, 0x40, 0xbc, 0xa3, 0xc4, 0xe0, 0x24, 0x70, 0xe2, 0x91, 0x1c,
-	0xe3, 0x85, 0x47, 0x72, 0x8c, 0x0f, 0x1e, 0xc9, 0x31, 0xce, 0x78, 0x2c, 0xc7, 0x90, 0xc4, 0x06,
-	0x0e, 0x78, 0x63, 0x40, 0x00, 0x00, 0x00, 0xff, 0xff, 0xec, 0xdf, 0x28, 0xda, 0x8a, 0x01, 0x00,
-	0x00,
+	// 379 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7d, 0x53, 0xcd, 0x4e, 0xc2, 0x40,
+	0x18, 0xe4, 0x47, 0x4a, 0xf9, 0x22, 0x8a, 0x0b, 0x31, 0x4d, 0x13, 0x91, 0x34, 0x1e, 0x3c, 0x55,
+	0x83, 0x1a, 0xaf, 0x0a, 0x78, 0x20, 0xf1, 0x84, 0xf8, 0x00, 0xcb, 0xb2, 0xc8, 0xc6, 0xb2, 0xc5,
+	0xee, 0x42, 0xe0, 0x4d, 0x7c, 0x03, 0x5f, 0xc5, 0xa3, 0x8f, 0x60, 0xf4, 0x45, 0xdc, 0x6e, 0x0b,
+	0x35, 0x52, 0x3c, 0xec, 0x61, 0xe6, 0x9b, 0xef, 0x9b, 0xe9, 0x24, 0x85, 0x3d, 0xc6, 0x25, 0x0d,
+	0x38, 0xf6, 0xdc, 0x69, 0xe0, 0x4b, 0x1f, 0x99, 0x13, 0xcc, 0xf8, 0xc8, 0x9b, 0x2d, 0x9c, 0x09,
+	0x94, 0x6e, 0x09, 0xa1, 0x42, 0xf4, 0xe8, 0x0b, 0xaa, 0x41, 0x41, 0xfa, 0xcf, 0x94, 0x5b, 0xd9,
+	0x46, 0xf6, 0xb4, 0xd4, 0x8b, 0x00, 0x3a, 0x04, 0x83, 0x8c, 0x31, 0xef, 0x76, 0xac, 0x9c, 0xa6,
+	0x63, 0x14, 0xf2, 0x98, 0x48, 0xe6, 0x73, 0x2b, 0x1f, 0xf1, 0x11, 0x42, 0x36, 0x98, 0x62, 0x36,
+	0x90, 0xfe, 0x94, 0x11, 0x6b, 0x47, 0x4f, 0xd6, 0xd8, 0x39, 0x86, 0x62, 0x7f, 0xcc, 0xf8, 0x93,
+	0x5a, 0x57, 0x66, 0x73, 0xec, 0xcd, 0xe8, 0xca, 0x4c, 0x03, 0xe7, 0x08, 0x0a, 0x7d, 0xed, 0x9a,
+	0x3e, 0xbe, 0x04, 0xe3, 0x51, 0xd0, 0x60, 0xdb, 0x7a, 0xc8, 0xaa, 0x14, 0x7e, 0x10, 0x47, 0x8d,
+	0x80, 0x73, 0x02, 0xd0, 0x56, 0x99, 0x39, 0xf5, 0xba, 0x1d, 0x11, 0xe6, 0xd6, 0x62, 0xa1, 0x56,
+	0xf3, 0x61, 0xee, 0x08, 0x39, 0x0d, 0x30, 0xb4, 0xf5, 0x76, 0x85, 0x03, 0x66, 0x9c, 0x7e, 0xab,
+	0xa6, 0xf9, 0x96, 0x83, 0xb2, 0x16, 0x89, 0x07, 0x1a, 0xcc, 0x19, 0xa1, 0xe8, 0x0a, 0x4a, 0x6d,
+	0xcc, 0xa3, 0x96, 0x51, 0xd5, 0x5d, 0x55, 0xef, 0xae, 0x7b, 0xb7, 0x0f, 0x12, 0x32, 0xbe, 0xef,
+	0x64, 0xd0, 0x39, 0x98, 0xdd, 0x21, 0xe5, 0x92, 0x8d, 0x96, 0x68, 0xff, 0x97, 0x20, 0x8c, 0x98,
+	0xbe, 0xd1, 0x84, 0xa2, 0x32, 0xea, 0x51, 0x3c, 0x4c, 0xb7, 0xa9, 0x24, 0x64, 0x54, 0xa2, 0xda,
+	0xb9, 0x81, 0xea, 0x3d, 0x13, 0x32, 0xae, 0x47, 0xb4, 0x96, 0xfa, 0x1c, 0xda, 0xbc, 0x6f, 0xd7,
+	0x12, 0x2a, 0x29, 0x53, 0x5d, 0xb8, 0x86, 0xf2, 0x2a, 0x67, 0x0b, 0x4b, 0x32, 0x46, 0x95, 0x3f,
+	0x61, 0x85, 0x8d, 0x36, 0xae, 0xa9, 0xc5, 0xa6, 0x0f, 0xbb, 0x61, 0x8c, 0x75, 0x4f, 0x67, 0xff,
+	0x7d, 0x70, 0x5a, 0x76, 0x17, 0x8c, 0xbb, 0x85, 0x0a, 0x2f, 0xd0, 0xc6, 0x34, 0x4d, 0xdf, 0xaa,
+	0xbc, 0x7f, 0xd5, 0xb3, 0x1f, 0xea, 0x7d, 0xaa, 0xf7, 0xfa, 0x5d, 0xcf, 0x0c, 0x0c, 0xfd, 0x3b,
+	0x5c, 0xfc, 0x00, 0xa4, 0x44, 0xd2, 0x67, 0x20, 0x03, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -262,6 +438,9 @@ const _ = grpc.SupportPackageIsVersion4
 type ThingsServiceClient interface {
 	CanAccess(ctx context.Context, in *AccessReq, opts ...grpc.CallOption) (*ThingID, error)
 	Identify(ctx context.Context, in *Token, opts ...grpc.CallOption) (*ThingID, error)
+	CanRead(ctx context.Context, in *AccessReq, opts ...grpc.CallOption) (*UserID, error)
+	ListChannelsByThing(ctx context.Context, in *ThingID, opts ...grpc.CallOption) (*ChannelIDs, error)
+	IdentifyBatch(ctx context.Context, in *Tokens, opts ...grpc.CallOption) (*ThingIDs, error)
 }
 
 type thingsServiceClient struct {
@@ -290,10 +469,40 @@ func (c *thingsServiceClient) Identify(ctx context.Context, in *Token, opts ...g
 	return out, nil
 }
 
+func (c *thingsServiceClient) CanRead(ctx context.Context, in *AccessReq, opts ...grpc.CallOption) (*UserID, error) {
+	out := new(UserID)
+	err := c.cc.Invoke(ctx, "/mainflux.ThingsService/CanRead", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *thingsServiceClient) ListChannelsByThing(ctx context.Context, in *ThingID, opts ...grpc.CallOption) (*ChannelIDs, error) {
+	out := new(ChannelIDs)
+	err := c.cc.Invoke(ctx, "/mainflux.ThingsService/ListChannelsByThing", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *thingsServiceClient) IdentifyBatch(ctx context.Context, in *Tokens, opts ...grpc.CallOption) (*ThingIDs, error) {
+	out := new(ThingIDs)
+	err := c.cc.Invoke(ctx, "/mainflux.ThingsService/IdentifyBatch", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ThingsServiceServer is the server API for ThingsService service.
 type ThingsServiceServer interface {
 	CanAccess(context.Context, *AccessReq) (*ThingID, error)
 	Identify(context.Context, *Token) (*ThingID, error)
+	CanRead(context.Context, *AccessReq) (*UserID, error)
+	ListChannelsByThing(context.Context, *ThingID) (*ChannelIDs, error)
+	IdentifyBatch(context.Context, *Tokens) (*ThingIDs, error)
 }
 
 func RegisterThingsServiceServer(s *grpc.Server, srv ThingsServiceServer) {
@@ -336,6 +545,60 @@ func _ThingsService_Identify_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ThingsService_CanRead_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AccessReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ThingsServiceServer).CanRead(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mainflux.ThingsService/CanRead",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ThingsServiceServer).CanRead(ctx, req.(*AccessReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ThingsService_ListChannelsByThing_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ThingID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ThingsServiceServer).ListChannelsByThing(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mainflux.ThingsService/ListChannelsByThing",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ThingsServiceServer).ListChannelsByThing(ctx, req.(*ThingID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ThingsService_IdentifyBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Tokens)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ThingsServiceServer).IdentifyBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mainflux.ThingsService/IdentifyBatch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ThingsServiceServer).IdentifyBatch(ctx, req.(*Tokens))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _ThingsService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "mainflux.ThingsService",
 	HandlerType: (*ThingsServiceServer)(nil),
@@ -348,6 +611,18 @@ var _ThingsService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Identify",
 			Handler:    _ThingsService_Identify_Handler,
 		},
+		{
+			MethodName: "CanRead",
+			Handler:    _ThingsService_CanRead_Handler,
+		},
+		{
+			MethodName: "ListChannelsByThing",
+			Handler:    _ThingsService_ListChannelsByThing_Handler,
+		},
+		{
+			MethodName: "IdentifyBatch",
+			Handler:    _ThingsService_IdentifyBatch_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "internal.proto",
@@ -358,6 +633,7 @@ var _ThingsService_serviceDesc = grpc.ServiceDesc{
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
 type UsersServiceClient interface {
 	Identify(ctx context.Context, in *Token, opts ...grpc.CallOption) (*UserID, error)
+	Exists(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*UserID, error)
 }
 
 type usersServiceClient struct {
@@ -377,9 +653,19 @@ func (c *usersServiceClient) Identify(ctx context.Context, in *Token, opts ...gr
 	return out, nil
 }
 
+func (c *usersServiceClient) Exists(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*UserID, error) {
+	out := new(UserID)
+	err := c.cc.Invoke(ctx, "/mainflux.UsersService/Exists", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // UsersServiceServer is the server API for UsersService service.
 type UsersServiceServer interface {
 	Identify(context.Context, *Token) (*UserID, error)
+	Exists(context.Context, *UserID) (*UserID, error)
 }
 
 func RegisterUsersServiceServer(s *grpc.Server, srv UsersServiceServer) {
@@ -404,6 +690,24 @@ func _UsersService_Identify_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _UsersService_Exists_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServiceServer).Exists(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mainflux.UsersService/Exists",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServiceServer).Exists(ctx, req.(*UserID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _UsersService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "mainflux.UsersService",
 	HandlerType: (*UsersServiceServer)(nil),
@@ -412,6 +716,10 @@ var _UsersService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Identify",
 			Handler:    _UsersService_Identify_Handler,
 		},
+		{
+			MethodName: "Exists",
+			Handler:    _UsersService_Exists_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "internal.proto",
@@ -444,6 +752,18 @@ func (m *AccessReq) MarshalTo(dAtA []byte) (int, error) {
 		i = encodeVarintInternal(dAtA, i, uint64(len(m.ChanID)))
 		i += copy(dAtA[i:], m.ChanID)
 	}
+	if len(m.Action) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintInternal(dAtA, i, uint64(len(m.Action)))
+		i += copy(dAtA[i:], m.Action)
+	}
+	if len(m.Subtopic) > 0 {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintInternal(dAtA, i, uint64(len(m.Subtopic)))
+		i += copy(dAtA[i:], m.Subtopic)
+	}
 	if m.XXX_unrecognized != nil {
 		i += copy(dAtA[i:], m.XXX_unrecognized)
 	}
@@ -525,62 +845,184 @@ func (m *UserID) MarshalTo(dAtA []byte) (int, error) {
 		i = encodeVarintInternal(dAtA, i, uint64(len(m.Value)))
 		i += copy(dAtA[i:], m.Value)
 	}
+	if len(m.Actor) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintInternal(dAtA, i, uint64(len(m.Actor)))
+		i += copy(dAtA[i:], m.Actor)
+	}
 	if m.XXX_unrecognized != nil {
 		i += copy(dAtA[i:], m.XXX_unrecognized)
 	}
 	return i, nil
 }
 
-func encodeVarintInternal(dAtA []byte, offset int, v uint64) int {
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
+func (m *ChannelIDs) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
 	}
-	dAtA[offset] = uint8(v)
-	return offset + 1
+	return dAtA[:n], nil
 }
-func (m *AccessReq) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+
+func (m *ChannelIDs) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
-	l = len(m.Token)
-	if l > 0 {
-		n += 1 + l + sovInternal(uint64(l))
-	}
-	l = len(m.ChanID)
-	if l > 0 {
-		n += 1 + l + sovInternal(uint64(l))
+	if len(m.Values) > 0 {
+		for _, s := range m.Values {
+			dAtA[i] = 0xa
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
 	}
 	if m.XXX_unrecognized != nil {
-		n += len(m.XXX_unrecognized)
+		i += copy(dAtA[i:], m.XXX_unrecognized)
 	}
-	return n
+	return i, nil
 }
 
-func (m *ThingID) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *Tokens) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *Tokens) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
-	l = len(m.Value)
-	if l > 0 {
-		n += 1 + l + sovInternal(uint64(l))
+	if len(m.Values) > 0 {
+		for _, s := range m.Values {
+			dAtA[i] = 0xa
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
 	}
 	if m.XXX_unrecognized != nil {
-		n += len(m.XXX_unrecognized)
+		i += copy(dAtA[i:], m.XXX_unrecognized)
 	}
-	return n
+	return i, nil
 }
 
-func (m *Token) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
+func (m *ThingIDs) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ThingIDs) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Values) > 0 {
+		for _, s := range m.Values {
+			dAtA[i] = 0xa
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func encodeVarintInternal(dAtA []byte, offset int, v uint64) int {
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return offset + 1
+}
+func (m *AccessReq) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Token)
+	if l > 0 {
+		n += 1 + l + sovInternal(uint64(l))
+	}
+	l = len(m.ChanID)
+	if l > 0 {
+		n += 1 + l + sovInternal(uint64(l))
+	}
+	l = len(m.Action)
+	if l > 0 {
+		n += 1 + l + sovInternal(uint64(l))
+	}
+	l = len(m.Subtopic)
+	if l > 0 {
+		n += 1 + l + sovInternal(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *ThingID) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Value)
+	if l > 0 {
+		n += 1 + l + sovInternal(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *Token) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
 	_ = l
 	l = len(m.Value)
 	if l > 0 {
@@ -602,6 +1044,64 @@ func (m *UserID) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovInternal(uint64(l))
 	}
+	l = len(m.Actor)
+	if l > 0 {
+		n += 1 + l + sovInternal(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *ChannelIDs) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Values) > 0 {
+		for _, s := range m.Values {
+			l = len(s)
+			n += 1 + l + sovInternal(uint64(l))
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *Tokens) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Values) > 0 {
+		for _, s := range m.Values {
+			l = len(s)
+			n += 1 + l + sovInternal(uint64(l))
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *ThingIDs) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Values) > 0 {
+		for _, s := range m.Values {
+			l = len(s)
+			n += 1 + l + sovInternal(uint64(l))
+		}
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -714,6 +1214,70 @@ func (m *AccessReq) Unmarshal(dAtA []byte) error {
 			}
 			m.ChanID = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Action", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowInternal
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthInternal
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthInternal
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Action = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Subtopic", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowInternal
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthInternal
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthInternal
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Subtopic = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipInternal(dAtA[iNdEx:])
@@ -972,6 +1536,296 @@ func (m *UserID) Unmarshal(dAtA []byte) error {
 			}
 			m.Value = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Actor", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowInternal
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthInternal
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthInternal
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Actor = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipInternal(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthInternal
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthInternal
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ChannelIDs) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowInternal
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ChannelIDs: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ChannelIDs: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Values", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowInternal
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthInternal
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthInternal
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Values = append(m.Values, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipInternal(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthInternal
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthInternal
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Tokens) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowInternal
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Tokens: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Tokens: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Values", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowInternal
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthInternal
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthInternal
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Values = append(m.Values, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipInternal(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthInternal
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthInternal
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ThingIDs) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowInternal
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ThingIDs: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ThingIDs: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Values", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowInternal
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthInternal
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthInternal
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Values = append(m.Values, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipInternal(dAtA[iNdEx:])
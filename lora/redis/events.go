@@ -1,37 +1,31 @@
 package redis
 
+import "github.com/mainflux/mainflux/things"
+
 type createThingEvent struct {
 	id       string
-	metadata thingMetadata
+	metadata things.LoraThing
 }
 
 type updateThingEvent struct {
 	id       string
-	metadata thingMetadata
+	metadata things.LoraThing
 }
 
 type removeThingEvent struct {
 	id string
 }
 
-type thingMetadata struct {
-	DevEUI string `json:"devEUI"`
-}
-
 type createChannelEvent struct {
 	id       string
-	metadata channelMetadata
+	metadata things.LoraChannel
 }
 
 type updateChannelEvent struct {
 	id       string
-	metadata channelMetadata
+	metadata things.LoraChannel
 }
 
 type removeChannelEvent struct {
 	id string
 }
-
-type channelMetadata struct {
-	AppID string `json:"appID"`
-}
@@ -22,12 +22,12 @@ const (
 var _ lora.RouteMapRepository = (*routerMap)(nil)
 
 type routerMap struct {
-	client *redis.Client
+	client redis.UniversalClient
 	prefix string
 }
 
 // NewRouteMapRepository returns redis thing cache implementation.
-func NewRouteMapRepository(client *redis.Client, prefix string) lora.RouteMapRepository {
+func NewRouteMapRepository(client redis.UniversalClient, prefix string) lora.RouteMapRepository {
 	return &routerMap{
 		client: client,
 		prefix: prefix,
@@ -3,11 +3,12 @@ package redis
 import (
 	"encoding/json"
 	"errors"
-	"fmt"
 
 	"github.com/go-redis/redis"
+	"github.com/mainflux/mainflux/events"
 	"github.com/mainflux/mainflux/logger"
 	"github.com/mainflux/mainflux/lora"
+	"github.com/mainflux/mainflux/things"
 )
 
 const (
@@ -25,16 +26,14 @@ const (
 	channelCreate = channelPrefix + "create"
 	channelUpdate = channelPrefix + "update"
 	channelRemove = channelPrefix + "remove"
-
-	exists = "BUSYGROUP Consumer Group name already exists"
 )
 
 var (
 	errMetadataType = errors.New("metadatada is not of type lora")
 
-	errMetadataAppID = errors.New("application ID not found in channel metadatada")
+	errMetadataAppID = errors.New("malformed application ID in channel metadatada")
 
-	errMetadataDevEUI = errors.New("device EUI not found in thing metadatada")
+	errMetadataDevEUI = errors.New("malformed device EUI in thing metadatada")
 )
 
 // EventStore represents event source for things and channels provisioning.
@@ -45,13 +44,13 @@ type EventStore interface {
 
 type eventStore struct {
 	svc      lora.Service
-	client   *redis.Client
+	client   redis.UniversalClient
 	consumer string
 	logger   logger.Logger
 }
 
 // NewEventStore returns new event store instance.
-func NewEventStore(svc lora.Service, client *redis.Client, consumer string, log logger.Logger) EventStore {
+func NewEventStore(svc lora.Service, client redis.UniversalClient, consumer string, log logger.Logger) EventStore {
 	return eventStore{
 		svc:      svc,
 		client:   client,
@@ -61,87 +60,79 @@ func NewEventStore(svc lora.Service, client *redis.Client, consumer string, log
 }
 
 func (es eventStore) Subscribe(subject string) error {
-	err := es.client.XGroupCreateMkStream(stream, group, "$").Err()
-	if err != nil && err.Error() != exists {
-		return err
-	}
-
-	for {
-		streams, err := es.client.XReadGroup(&redis.XReadGroupArgs{
-			Group:    group,
-			Consumer: es.consumer,
-			Streams:  []string{stream, ">"},
-			Count:    100,
-		}).Result()
-		if err != nil || len(streams) == 0 {
-			continue
+	sub := events.NewSubscriber(es.client, events.Config{
+		Stream:   stream,
+		Group:    group,
+		Consumer: es.consumer,
+	}, es.logger)
+
+	return sub.Subscribe(events.HandlerFunc(es.handle))
+}
+
+func (es eventStore) handle(event events.Event) error {
+	var err error
+	switch event.Read("operation", "") {
+	case thingCreate:
+		cte, derr := decodeCreateThing(event)
+		if derr != nil {
+			err = derr
+			break
 		}
-
-		for _, msg := range streams[0].Messages {
-			event := msg.Values
-
-			var err error
-			switch event["operation"] {
-			case thingCreate:
-				cte, derr := decodeCreateThing(event)
-				if derr != nil {
-					err = derr
-					break
-				}
-				err = es.handleCreateThing(cte)
-			case thingUpdate:
-				ute, derr := decodeUpdateThing(event)
-				if derr != nil {
-					err = derr
-					break
-				}
-				err = es.handleUpdateThing(ute)
-			case thingRemove:
-				rte := decodeRemoveThing(event)
-				err = es.handleRemoveThing(rte)
-			case channelCreate:
-				cce, derr := decodeCreateChannel(event)
-				if derr != nil {
-					err = derr
-					break
-				}
-				err = es.handleCreateChannel(cce)
-			case channelUpdate:
-				uce, derr := decodeUpdateChannel(event)
-				if derr != nil {
-					err = derr
-					break
-				}
-				err = es.handleUpdateChannel(uce)
-			case channelRemove:
-				rce := decodeRemoveChannel(event)
-				err = es.handleRemoveChannel(rce)
-			}
-			if err != nil && err != errMetadataType {
-				es.logger.Warn(fmt.Sprintf("Failed to handle event sourcing: %s", err.Error()))
-				break
-			}
-			es.client.XAck(stream, group, msg.ID)
+		err = es.handleCreateThing(cte)
+	case thingUpdate:
+		ute, derr := decodeUpdateThing(event)
+		if derr != nil {
+			err = derr
+			break
+		}
+		err = es.handleUpdateThing(ute)
+	case thingRemove:
+		rte := decodeRemoveThing(event)
+		err = es.handleRemoveThing(rte)
+	case channelCreate:
+		cce, derr := decodeCreateChannel(event)
+		if derr != nil {
+			err = derr
+			break
 		}
+		err = es.handleCreateChannel(cce)
+	case channelUpdate:
+		uce, derr := decodeUpdateChannel(event)
+		if derr != nil {
+			err = derr
+			break
+		}
+		err = es.handleUpdateChannel(uce)
+	case channelRemove:
+		rce := decodeRemoveChannel(event)
+		err = es.handleRemoveChannel(rce)
+	}
+
+	// Events of the entities that aren't LoRa ones are skipped.
+	if err == errMetadataType {
+		return nil
 	}
+
+	return err
 }
 
-func decodeCreateThing(event map[string]interface{}) (createThingEvent, error) {
-	strmeta := read(event, "metadata", "{}")
-	var metadata map[string]thingMetadata
+func decodeCreateThing(event events.Event) (createThingEvent, error) {
+	strmeta := event.Read("metadata", "{}")
+	var metadata map[string]interface{}
 	if err := json.Unmarshal([]byte(strmeta), &metadata); err != nil {
 		return createThingEvent{}, err
 	}
 
 	cte := createThingEvent{
-		id: read(event, "id", ""),
+		id: event.Read("id", ""),
 	}
 
-	val, ok := metadata["lora"]
-	if !ok {
+	val, err := things.Thing{Metadata: metadata}.Lora()
+	switch err {
+	case nil:
+	case things.ErrMetadataNotFound:
 		return createThingEvent{}, errMetadataType
-	}
-	if val.DevEUI == "" {
+	default:
 		return createThingEvent{}, errMetadataDevEUI
 	}
 
@@ -149,22 +140,23 @@ func decodeCreateThing(event map[string]interface{}) (createThingEvent, error) {
 	return cte, nil
 }
 
-func decodeUpdateThing(event map[string]interface{}) (updateThingEvent, error) {
-	strmeta := read(event, "metadata", "{}")
-	var metadata map[string]thingMetadata
+func decodeUpdateThing(event events.Event) (updateThingEvent, error) {
+	strmeta := event.Read("metadata", "{}")
+	var metadata map[string]interface{}
 	if err := json.Unmarshal([]byte(strmeta), &metadata); err != nil {
 		return updateThingEvent{}, errMetadataType
 	}
 
 	ute := updateThingEvent{
-		id: read(event, "id", ""),
+		id: event.Read("id", ""),
 	}
 
-	val, ok := metadata["lora"]
-	if !ok {
+	val, err := things.Thing{Metadata: metadata}.Lora()
+	switch err {
+	case nil:
+	case things.ErrMetadataNotFound:
 		return updateThingEvent{}, errMetadataType
-	}
-	if val.DevEUI == "" {
+	default:
 		return updateThingEvent{}, errMetadataDevEUI
 	}
 
@@ -172,29 +164,29 @@ func decodeUpdateThing(event map[string]interface{}) (updateThingEvent, error) {
 	return ute, nil
 }
 
-func decodeRemoveThing(event map[string]interface{}) removeThingEvent {
+func decodeRemoveThing(event events.Event) removeThingEvent {
 	return removeThingEvent{
-		id: read(event, "id", ""),
+		id: event.Read("id", ""),
 	}
 }
 
-func decodeCreateChannel(event map[string]interface{}) (createChannelEvent, error) {
-	strmeta := read(event, "metadata", "{}")
-
-	var metadata map[string]channelMetadata
+func decodeCreateChannel(event events.Event) (createChannelEvent, error) {
+	strmeta := event.Read("metadata", "{}")
+	var metadata map[string]interface{}
 	if err := json.Unmarshal([]byte(strmeta), &metadata); err != nil {
 		return createChannelEvent{}, err
 	}
 
 	cce := createChannelEvent{
-		id: read(event, "id", ""),
+		id: event.Read("id", ""),
 	}
 
-	val, ok := metadata["lora"]
-	if !ok {
+	val, err := things.Channel{Metadata: metadata}.Lora()
+	switch err {
+	case nil:
+	case things.ErrMetadataNotFound:
 		return createChannelEvent{}, errMetadataType
-	}
-	if val.AppID == "" {
+	default:
 		return createChannelEvent{}, errMetadataAppID
 	}
 
@@ -202,22 +194,23 @@ func decodeCreateChannel(event map[string]interface{}) (createChannelEvent, erro
 	return cce, nil
 }
 
-func decodeUpdateChannel(event map[string]interface{}) (updateChannelEvent, error) {
-	strmeta := read(event, "metadata", "{}")
-	var metadata map[string]channelMetadata
+func decodeUpdateChannel(event events.Event) (updateChannelEvent, error) {
+	strmeta := event.Read("metadata", "{}")
+	var metadata map[string]interface{}
 	if err := json.Unmarshal([]byte(strmeta), &metadata); err != nil {
 		return updateChannelEvent{}, err
 	}
 
 	uce := updateChannelEvent{
-		id: read(event, "id", ""),
+		id: event.Read("id", ""),
 	}
 
-	val, ok := metadata["lora"]
-	if !ok {
+	val, err := things.Channel{Metadata: metadata}.Lora()
+	switch err {
+	case nil:
+	case things.ErrMetadataNotFound:
 		return updateChannelEvent{}, errMetadataType
-	}
-	if val.AppID == "" {
+	default:
 		return updateChannelEvent{}, errMetadataAppID
 	}
 
@@ -225,9 +218,9 @@ func decodeUpdateChannel(event map[string]interface{}) (updateChannelEvent, erro
 	return uce, nil
 }
 
-func decodeRemoveChannel(event map[string]interface{}) removeChannelEvent {
+func decodeRemoveChannel(event events.Event) removeChannelEvent {
 	return removeChannelEvent{
-		id: read(event, "id", ""),
+		id: event.Read("id", ""),
 	}
 }
 
@@ -254,12 +247,3 @@ func (es eventStore) handleUpdateChannel(uce updateChannelEvent) error {
 func (es eventStore) handleRemoveChannel(rce removeChannelEvent) error {
 	return es.svc.RemoveChannel(rce.id)
 }
-
-func read(event map[string]interface{}, key, def string) string {
-	val, ok := event[key].(string)
-	if !ok {
-		return def
-	}
-
-	return val
-}
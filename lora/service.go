@@ -4,7 +4,9 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"errors"
+	"time"
 
+	"github.com/gofrs/uuid"
 	"github.com/mainflux/mainflux"
 )
 
@@ -102,6 +104,11 @@ func (as *adapterService) Publish(m Message) error {
 		payload = []byte(jo)
 	}
 
+	id, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+
 	// Publish on Mainflux NATS broker
 	msg := mainflux.RawMessage{
 		Publisher:   thing,
@@ -109,6 +116,8 @@ func (as *adapterService) Publish(m Message) error {
 		ContentType: "Content-Type",
 		Channel:     channel,
 		Payload:     payload,
+		Received:    float64(time.Now().UnixNano()) / 1e9,
+		Id:          id.String(),
 	}
 
 	return as.publisher.Publish(msg)
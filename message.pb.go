@@ -30,6 +30,11 @@ type RawMessage struct {
 	Protocol             string   `protobuf:"bytes,4,opt,name=protocol,proto3" json:"protocol,omitempty"`
 	ContentType          string   `protobuf:"bytes,5,opt,name=contentType,proto3" json:"contentType,omitempty"`
 	Payload              []byte   `protobuf:"bytes,6,opt,name=payload,proto3" json:"payload,omitempty"`
+	RemoteAddr           string   `protobuf:"bytes,7,opt,name=remoteAddr,proto3" json:"remoteAddr,omitempty"`
+	Received             float64  `protobuf:"fixed64,8,opt,name=received,proto3" json:"received,omitempty"`
+	Id                   string   `protobuf:"bytes,9,opt,name=id,proto3" json:"id,omitempty"`
+	Seq                  uint64   `protobuf:"varint,10,opt,name=seq,proto3" json:"seq,omitempty"`
+	Delivery             string   `protobuf:"bytes,11,opt,name=delivery,proto3" json:"delivery,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -110,6 +115,41 @@ func (m *RawMessage) GetPayload() []byte {
 	return nil
 }
 
+func (m *RawMessage) GetRemoteAddr() string {
+	if m != nil {
+		return m.RemoteAddr
+	}
+	return ""
+}
+
+func (m *RawMessage) GetReceived() float64 {
+	if m != nil {
+		return m.Received
+	}
+	return 0
+}
+
+func (m *RawMessage) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *RawMessage) GetSeq() uint64 {
+	if m != nil {
+		return m.Seq
+	}
+	return 0
+}
+
+func (m *RawMessage) GetDelivery() string {
+	if m != nil {
+		return m.Delivery
+	}
+	return ""
+}
+
 // Message represents a resolved (normalized) raw message.
 type Message struct {
 	Channel   string `protobuf:"bytes,1,opt,name=channel,proto3" json:"channel,omitempty"`
@@ -128,6 +168,11 @@ type Message struct {
 	Time                 float64         `protobuf:"fixed64,12,opt,name=time,proto3" json:"time,omitempty"`
 	UpdateTime           float64         `protobuf:"fixed64,13,opt,name=updateTime,proto3" json:"updateTime,omitempty"`
 	Link                 string          `protobuf:"bytes,14,opt,name=link,proto3" json:"link,omitempty"`
+	RemoteAddr           string          `protobuf:"bytes,15,opt,name=remoteAddr,proto3" json:"remoteAddr,omitempty"`
+	Received             float64         `protobuf:"fixed64,16,opt,name=received,proto3" json:"received,omitempty"`
+	Id                   string          `protobuf:"bytes,17,opt,name=id,proto3" json:"id,omitempty"`
+	Seq                  uint64          `protobuf:"varint,18,opt,name=seq,proto3" json:"seq,omitempty"`
+	Delivery             string          `protobuf:"bytes,19,opt,name=delivery,proto3" json:"delivery,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
 	XXX_unrecognized     []byte          `json:"-"`
 	XXX_sizecache        int32           `json:"-"`
@@ -295,6 +340,41 @@ func (m *Message) GetLink() string {
 	return ""
 }
 
+func (m *Message) GetRemoteAddr() string {
+	if m != nil {
+		return m.RemoteAddr
+	}
+	return ""
+}
+
+func (m *Message) GetReceived() float64 {
+	if m != nil {
+		return m.Received
+	}
+	return 0
+}
+
+func (m *Message) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Message) GetSeq() uint64 {
+	if m != nil {
+		return m.Seq
+	}
+	return 0
+}
+
+func (m *Message) GetDelivery() string {
+	if m != nil {
+		return m.Delivery
+	}
+	return ""
+}
+
 // XXX_OneofFuncs is for the internal use of the proto package.
 func (*Message) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
 	return _Message_OneofMarshaler, _Message_OneofUnmarshaler, _Message_OneofSizer, []interface{}{
@@ -450,30 +530,35 @@ func init() {
 func init() { proto.RegisterFile("message.proto", fileDescriptor_33c57e4bae7b9afd) }
 
 var fileDescriptor_33c57e4bae7b9afd = []byte{
-	// 368 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x92, 0x4f, 0x8e, 0xda, 0x30,
-	0x18, 0xc5, 0xe3, 0xf2, 0x27, 0xc9, 0x17, 0xa8, 0x2a, 0xab, 0x0b, 0xab, 0xaa, 0x22, 0x2b, 0xab,
-	0xac, 0xb2, 0x68, 0x6f, 0xc0, 0x8a, 0x4d, 0x37, 0x06, 0x75, 0xef, 0x04, 0x03, 0x51, 0x1d, 0x3b,
-	0x22, 0x76, 0x5b, 0x6e, 0x32, 0x47, 0x98, 0x23, 0xcc, 0x11, 0x66, 0x39, 0x47, 0x18, 0x31, 0x17,
-	0x19, 0xd9, 0x21, 0x90, 0x1b, 0xcc, 0xee, 0xbd, 0xdf, 0xfb, 0x3e, 0xfb, 0x19, 0x02, 0xcb, 0x46,
-	0x74, 0x1d, 0x3f, 0x88, 0xa2, 0x3d, 0x69, 0xa3, 0x71, 0xd4, 0xf0, 0x5a, 0xed, 0xa5, 0xfd, 0x9f,
-	0x3d, 0x21, 0x00, 0xc6, 0xff, 0xfd, 0xea, 0x63, 0x4c, 0x20, 0xac, 0x8e, 0x5c, 0x29, 0x21, 0x09,
-	0xa2, 0x28, 0x8f, 0xd9, 0x60, 0xf1, 0x37, 0x88, 0x3a, 0x5b, 0x1a, 0xdd, 0xd6, 0x15, 0xf9, 0xe4,
-	0xa3, 0x9b, 0xc7, 0xdf, 0x21, 0x6e, 0x6d, 0x29, 0xeb, 0xee, 0x28, 0x4e, 0x64, 0xe2, 0xc3, 0x3b,
-	0x70, 0x9b, 0xfe, 0xd6, 0x4a, 0x4b, 0x32, 0xed, 0x37, 0x07, 0x8f, 0x29, 0x24, 0x95, 0x56, 0x46,
-	0x28, 0xb3, 0x3d, 0xb7, 0x82, 0xcc, 0x7c, 0x3c, 0x46, 0xae, 0x51, 0xcb, 0xcf, 0x52, 0xf3, 0x1d,
-	0x99, 0x53, 0x94, 0x2f, 0xd8, 0x60, 0xb3, 0xc7, 0x09, 0x84, 0x1f, 0xd5, 0x1b, 0xc3, 0x54, 0xf1,
-	0x66, 0x28, 0xec, 0xb5, 0x63, 0x56, 0xd5, 0xc6, 0xd7, 0x8c, 0x99, 0xd7, 0x98, 0x02, 0xec, 0xa5,
-	0xe6, 0xe6, 0x37, 0x97, 0x56, 0x90, 0x90, 0xa2, 0x1c, 0xad, 0x03, 0x36, 0x62, 0x38, 0x83, 0xa4,
-	0x33, 0xa7, 0x5a, 0x1d, 0xfa, 0x91, 0xc8, 0x2d, 0xaf, 0x03, 0x36, 0x86, 0x38, 0x85, 0xb8, 0xd4,
-	0x5a, 0xf6, 0x13, 0x31, 0x45, 0x79, 0xb4, 0x0e, 0xd8, 0x1d, 0xb9, 0x7c, 0xc7, 0x0d, 0xef, 0x73,
-	0xb8, 0x9e, 0x70, 0x47, 0xb8, 0x80, 0xe8, 0xaf, 0x13, 0x1b, 0xdb, 0x90, 0x84, 0xa2, 0x3c, 0xf9,
-	0x81, 0x8b, 0xe1, 0x0b, 0x28, 0x36, 0xb6, 0xf1, 0x53, 0xec, 0x36, 0xe3, 0x5e, 0x62, 0xea, 0x46,
-	0x90, 0x85, 0xeb, 0xcb, 0xbc, 0xc6, 0x29, 0x80, 0x6d, 0x77, 0xdc, 0x88, 0xad, 0x4b, 0x96, 0x3e,
-	0x19, 0x11, 0xb7, 0x23, 0x6b, 0xf5, 0x87, 0x7c, 0xee, 0x5f, 0xef, 0xf4, 0x2a, 0x84, 0x99, 0x3f,
-	0x33, 0xa3, 0x10, 0x0d, 0xd7, 0xe0, 0xaf, 0x57, 0xe8, 0xff, 0x28, 0xc4, 0x7a, 0xb3, 0xfa, 0xf2,
-	0x7c, 0x49, 0xd1, 0xcb, 0x25, 0x45, 0xaf, 0x97, 0x14, 0x3d, 0xbc, 0xa5, 0x41, 0x39, 0xf7, 0x3f,
-	0xf6, 0xcf, 0xf7, 0x00, 0x00, 0x00, 0xff, 0xff, 0x21, 0x23, 0xc7, 0x3c, 0xbb, 0x02, 0x00, 0x00,
+	// 434 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc5, 0x52, 0xb1, 0x4e, 0xc3, 0x30,
+	0x10, 0x25, 0xa5, 0xa5, 0xc9, 0xb5, 0x85, 0x62, 0x18, 0x2c, 0x84, 0xaa, 0xaa, 0x13, 0x53, 0x07,
+	0xf8, 0x02, 0x98, 0x58, 0x58, 0x02, 0x62, 0x77, 0x13, 0xb7, 0xb5, 0x70, 0xec, 0x90, 0x38, 0x85,
+	0xfe, 0x09, 0x33, 0x9f, 0xc0, 0x57, 0x30, 0xf2, 0x09, 0x08, 0x7e, 0x04, 0xfb, 0x42, 0xda, 0x80,
+	0x2a, 0x56, 0x06, 0x4b, 0x77, 0xef, 0xdd, 0xd9, 0xbe, 0x77, 0x0f, 0x7a, 0x09, 0xcf, 0x73, 0x36,
+	0xe3, 0xe3, 0x34, 0xd3, 0x46, 0x13, 0x3f, 0x61, 0x42, 0x4d, 0x65, 0xf1, 0x38, 0x7a, 0x69, 0x00,
+	0x84, 0xec, 0xe1, 0xaa, 0xa4, 0x09, 0x85, 0x76, 0x34, 0x67, 0x4a, 0x71, 0x49, 0xbd, 0xa1, 0x77,
+	0x12, 0x84, 0x55, 0x4a, 0x8e, 0xc0, 0xcf, 0x8b, 0x89, 0xd1, 0xa9, 0x88, 0x68, 0x03, 0xa9, 0x55,
+	0x4e, 0x8e, 0x21, 0x48, 0x8b, 0x89, 0x14, 0xf9, 0x9c, 0x67, 0x74, 0x1b, 0xc9, 0x35, 0xe0, 0x3a,
+	0xf1, 0xd5, 0x48, 0x4b, 0xda, 0x2c, 0x3b, 0xab, 0x9c, 0x0c, 0xa1, 0x13, 0x69, 0x65, 0xb8, 0x32,
+	0x37, 0xcb, 0x94, 0xd3, 0x16, 0xd2, 0x75, 0xc8, 0xfd, 0x28, 0x65, 0x4b, 0xa9, 0x59, 0x4c, 0x77,
+	0x2c, 0xdb, 0x0d, 0xab, 0x94, 0x0c, 0x00, 0x32, 0x9e, 0x68, 0xc3, 0xcf, 0xe3, 0x38, 0xa3, 0x6d,
+	0x6c, 0xad, 0x21, 0xee, 0xdd, 0x8c, 0x47, 0x5c, 0x2c, 0x78, 0x4c, 0x7d, 0xcb, 0x7a, 0xe1, 0x2a,
+	0x27, 0xbb, 0xd0, 0x10, 0x31, 0x0d, 0xb0, 0xc7, 0x46, 0xa4, 0x0f, 0xdb, 0x39, 0xbf, 0xa7, 0x60,
+	0x81, 0x66, 0xe8, 0x42, 0xd7, 0x1d, 0x73, 0x69, 0x8b, 0xb3, 0x25, 0xed, 0x94, 0xbf, 0xae, 0xf2,
+	0xd1, 0x73, 0x13, 0xda, 0xff, 0xa5, 0x18, 0x81, 0xa6, 0x62, 0x49, 0x25, 0x15, 0xc6, 0x0e, 0x2b,
+	0x94, 0x30, 0x28, 0x90, 0xc5, 0x5c, 0x6c, 0x95, 0x85, 0xa9, 0x95, 0xc9, 0xdc, 0x32, 0x59, 0x70,
+	0x54, 0xc7, 0xbb, 0xdc, 0x0a, 0x6b, 0x18, 0x19, 0x41, 0x27, 0x37, 0x99, 0x50, 0xb3, 0xb2, 0xc4,
+	0x49, 0x14, 0xd8, 0x92, 0x3a, 0x68, 0x35, 0x0e, 0x26, 0x5a, 0xcb, 0xb2, 0xc2, 0xc9, 0xe5, 0xdb,
+	0x8a, 0x35, 0xe4, 0xf8, 0x98, 0x19, 0x56, 0xf2, 0xf0, 0x7d, 0xc3, 0x1a, 0x22, 0x63, 0xf0, 0x17,
+	0x2e, 0xb8, 0x2e, 0x12, 0x54, 0xb1, 0x73, 0x4a, 0xc6, 0x95, 0xf7, 0xc6, 0x16, 0xc4, 0xaa, 0x70,
+	0x55, 0xe3, 0x26, 0x31, 0xc2, 0x4e, 0xd7, 0xc5, 0x7d, 0x61, 0xec, 0xf6, 0x5c, 0xa4, 0xf6, 0x4a,
+	0x7e, 0xe3, 0x98, 0x1e, 0x32, 0x35, 0xc4, 0xf5, 0x48, 0xa1, 0xee, 0xe8, 0x6e, 0x39, 0xbd, 0x8b,
+	0x7f, 0x79, 0x63, 0xef, 0x4f, 0x6f, 0xf4, 0x37, 0x7a, 0x63, 0xff, 0xb7, 0x37, 0xc8, 0x66, 0x6f,
+	0x1c, 0xfc, 0xf4, 0xc6, 0x45, 0x1b, 0x5a, 0x38, 0xcd, 0x68, 0x08, 0x7e, 0x35, 0x20, 0x39, 0xfc,
+	0x06, 0xd1, 0x22, 0x5e, 0x58, 0x26, 0x17, 0xfd, 0xd7, 0x8f, 0x81, 0xf7, 0x66, 0xcf, 0xbb, 0x3d,
+	0x4f, 0x9f, 0x83, 0xad, 0xc9, 0x0e, 0xae, 0xf9, 0xec, 0x0b, 0x9e, 0xe5, 0xe5, 0x3d, 0xaf, 0x03,
+	0x00, 0x00,
 }
 
 func (m *RawMessage) Marshal() (dAtA []byte, err error) {
@@ -527,6 +612,35 @@ func (m *RawMessage) MarshalTo(dAtA []byte) (int, error) {
 		i = encodeVarintMessage(dAtA, i, uint64(len(m.Payload)))
 		i += copy(dAtA[i:], m.Payload)
 	}
+	if len(m.RemoteAddr) > 0 {
+		dAtA[i] = 0x3a
+		i++
+		i = encodeVarintMessage(dAtA, i, uint64(len(m.RemoteAddr)))
+		i += copy(dAtA[i:], m.RemoteAddr)
+	}
+	if m.Received != 0 {
+		dAtA[i] = 0x41
+		i++
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Received))))
+		i += 8
+	}
+	if len(m.Id) > 0 {
+		dAtA[i] = 0x4a
+		i++
+		i = encodeVarintMessage(dAtA, i, uint64(len(m.Id)))
+		i += copy(dAtA[i:], m.Id)
+	}
+	if m.Seq != 0 {
+		dAtA[i] = 0x50
+		i++
+		i = encodeVarintMessage(dAtA, i, uint64(m.Seq))
+	}
+	if len(m.Delivery) > 0 {
+		dAtA[i] = 0x5a
+		i++
+		i = encodeVarintMessage(dAtA, i, uint64(len(m.Delivery)))
+		i += copy(dAtA[i:], m.Delivery)
+	}
 	if m.XXX_unrecognized != nil {
 		i += copy(dAtA[i:], m.XXX_unrecognized)
 	}
@@ -619,6 +733,43 @@ func (m *Message) MarshalTo(dAtA []byte) (int, error) {
 		i = encodeVarintMessage(dAtA, i, uint64(len(m.Link)))
 		i += copy(dAtA[i:], m.Link)
 	}
+	if len(m.RemoteAddr) > 0 {
+		dAtA[i] = 0x7a
+		i++
+		i = encodeVarintMessage(dAtA, i, uint64(len(m.RemoteAddr)))
+		i += copy(dAtA[i:], m.RemoteAddr)
+	}
+	if m.Received != 0 {
+		dAtA[i] = 0x81
+		i++
+		dAtA[i] = 0x1
+		i++
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Received))))
+		i += 8
+	}
+	if len(m.Id) > 0 {
+		dAtA[i] = 0x8a
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintMessage(dAtA, i, uint64(len(m.Id)))
+		i += copy(dAtA[i:], m.Id)
+	}
+	if m.Seq != 0 {
+		dAtA[i] = 0x90
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintMessage(dAtA, i, uint64(m.Seq))
+	}
+	if len(m.Delivery) > 0 {
+		dAtA[i] = 0x9a
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintMessage(dAtA, i, uint64(len(m.Delivery)))
+		i += copy(dAtA[i:], m.Delivery)
+	}
 	if m.XXX_unrecognized != nil {
 		i += copy(dAtA[i:], m.XXX_unrecognized)
 	}
@@ -727,6 +878,24 @@ func (m *RawMessage) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovMessage(uint64(l))
 	}
+	l = len(m.RemoteAddr)
+	if l > 0 {
+		n += 1 + l + sovMessage(uint64(l))
+	}
+	if m.Received != 0 {
+		n += 9
+	}
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovMessage(uint64(l))
+	}
+	if m.Seq != 0 {
+		n += 1 + sovMessage(uint64(m.Seq))
+	}
+	l = len(m.Delivery)
+	if l > 0 {
+		n += 1 + l + sovMessage(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -780,6 +949,24 @@ func (m *Message) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovMessage(uint64(l))
 	}
+	l = len(m.RemoteAddr)
+	if l > 0 {
+		n += 1 + l + sovMessage(uint64(l))
+	}
+	if m.Received != 0 {
+		n += 10
+	}
+	l = len(m.Id)
+	if l > 0 {
+		n += 2 + l + sovMessage(uint64(l))
+	}
+	if m.Seq != 0 {
+		n += 2 + sovMessage(uint64(m.Seq))
+	}
+	l = len(m.Delivery)
+	if l > 0 {
+		n += 2 + l + sovMessage(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -1075,6 +1262,132 @@ func (m *RawMessage) Unmarshal(dAtA []byte) error {
 				m.Payload = []byte{}
 			}
 			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RemoteAddr", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMessage
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMessage
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RemoteAddr = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 8:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Received", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.Received = float64(math.Float64frombits(v))
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMessage
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMessage
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 10:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Seq", wireType)
+			}
+			m.Seq = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Seq |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Delivery", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMessage
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMessage
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Delivery = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipMessage(dAtA[iNdEx:])
@@ -1507,6 +1820,132 @@ func (m *Message) Unmarshal(dAtA []byte) error {
 			}
 			m.Link = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 15:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RemoteAddr", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMessage
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMessage
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RemoteAddr = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 16:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Received", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.Received = float64(math.Float64frombits(v))
+		case 17:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMessage
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMessage
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 18:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Seq", wireType)
+			}
+			m.Seq = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Seq |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 19:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Delivery", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMessage
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMessage
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Delivery = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipMessage(dAtA[iNdEx:])
@@ -0,0 +1,179 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/mainflux/mainflux/metering"
+)
+
+func listRecordsEndpoint(svc metering.Service) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(listRecordsReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		recs, err := svc.ListRecords(req.key, req.from, req.to)
+		if err != nil {
+			return nil, err
+		}
+
+		return toRecordsRes(recs), nil
+	}
+}
+
+func exportEndpoint(svc metering.Service) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(exportReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		recs, err := svc.ExportRecords(req.key, req.month)
+		if err != nil {
+			return nil, err
+		}
+
+		if !req.csv {
+			return toRecordsRes(recs), nil
+		}
+
+		content, err := toCSV(recs)
+		if err != nil {
+			return nil, err
+		}
+
+		return documentRes{
+			name:        fmt.Sprintf("usage-%s.csv", req.month.Format(monthFormat)),
+			contentType: csvContentType,
+			content:     content,
+		}, nil
+	}
+}
+
+func addThresholdEndpoint(svc metering.Service) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(addThresholdReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		th := metering.Threshold{
+			Metric: req.Metric,
+			Limit:  req.Limit,
+			URL:    req.URL,
+		}
+
+		saved, err := svc.AddThreshold(req.key, th)
+		if err != nil {
+			return nil, err
+		}
+
+		return addThresholdRes{id: saved.ID}, nil
+	}
+}
+
+func listThresholdsEndpoint(svc metering.Service) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(listReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		ths, err := svc.ListThresholds(req.key)
+		if err != nil {
+			return nil, err
+		}
+
+		res := listThresholdsRes{
+			Thresholds: []thresholdRes{},
+		}
+		for _, th := range ths {
+			res.Thresholds = append(res.Thresholds, thresholdRes{
+				ID:     th.ID,
+				Metric: th.Metric,
+				Limit:  th.Limit,
+				URL:    th.URL,
+			})
+		}
+
+		return res, nil
+	}
+}
+
+func removeThresholdEndpoint(svc metering.Service) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(entityReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RemoveThreshold(req.key, req.id); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func toRecordsRes(recs []metering.Record) recordsRes {
+	res := recordsRes{
+		Records: []recordRes{},
+	}
+	for _, rec := range recs {
+		res.Records = append(res.Records, recordRes{
+			Owner:         rec.Owner,
+			Month:         rec.Month.Format(monthFormat),
+			MessagesIn:    rec.MessagesIn,
+			MessagesOut:   rec.MessagesOut,
+			StorageBytes:  rec.StorageBytes,
+			ActiveDevices: rec.ActiveDevices,
+		})
+	}
+
+	return res
+}
+
+func toCSV(recs []metering.Record) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	header := []string{"owner", "month", metering.MessagesIn, metering.MessagesOut, metering.StorageBytes, metering.ActiveDevices}
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+
+	for _, rec := range recs {
+		row := []string{
+			rec.Owner,
+			rec.Month.Format(monthFormat),
+			strconv.FormatUint(rec.MessagesIn, 10),
+			strconv.FormatUint(rec.MessagesOut, 10),
+			strconv.FormatUint(rec.StorageBytes, 10),
+			strconv.FormatUint(rec.ActiveDevices, 10),
+		}
+		if err := w.Write(row); err != nil {
+			return nil, err
+		}
+	}
+
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
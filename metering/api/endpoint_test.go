@@ -0,0 +1,424 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package api_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/metering"
+	"github.com/mainflux/mainflux/metering/api"
+	"github.com/mainflux/mainflux/metering/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	validToken   = "validToken"
+	invalidToken = "invalidToken"
+	exportKey    = "exportKey"
+	email        = "user@example.com"
+	contentType  = "application/json"
+	hook         = "https://example.com/hook"
+	thingID      = "thing"
+	chanID       = "channel"
+)
+
+var threshold = metering.Threshold{
+	Metric: metering.MessagesIn,
+	Limit:  10,
+	URL:    hook,
+}
+
+type testRequest struct {
+	client      *http.Client
+	method      string
+	url         string
+	contentType string
+	accept      string
+	token       string
+	body        io.Reader
+}
+
+func (tr testRequest) make() (*http.Response, error) {
+	req, err := http.NewRequest(tr.method, tr.url, tr.body)
+	if err != nil {
+		return nil, err
+	}
+	if tr.token != "" {
+		req.Header.Set("Authorization", tr.token)
+	}
+	if tr.contentType != "" {
+		req.Header.Set("Content-Type", tr.contentType)
+	}
+	if tr.accept != "" {
+		req.Header.Set("Accept", tr.accept)
+	}
+	return tr.client.Do(req)
+}
+
+type recordRes struct {
+	Owner         string `json:"owner"`
+	Month         string `json:"month"`
+	MessagesIn    uint64 `json:"messages_in"`
+	MessagesOut   uint64 `json:"messages_out"`
+	StorageBytes  uint64 `json:"storage_bytes"`
+	ActiveDevices uint64 `json:"active_devices"`
+}
+
+type thresholdRes struct {
+	ID     string `json:"id,omitempty"`
+	Metric string `json:"metric"`
+	Limit  uint64 `json:"limit"`
+	URL    string `json:"url"`
+}
+
+func newService() metering.Service {
+	users := mocks.NewUsersService(map[string]string{validToken: email})
+	records := mocks.NewRecordRepository()
+	entities := mocks.NewEntityRepository()
+	thresholds := mocks.NewThresholdRepository()
+	idp := mocks.NewIdentityProvider()
+	return metering.New(users, records, entities, thresholds, idp, mocks.NewSender(nil), exportKey)
+}
+
+func newServer(svc metering.Service) *httptest.Server {
+	return httptest.NewServer(api.MakeHandler(svc))
+}
+
+func toJSON(data interface{}) string {
+	jsonData, _ := json.Marshal(data)
+	return string(jsonData)
+}
+
+func meter(t *testing.T, svc metering.Service) {
+	for _, id := range []string{thingID, chanID} {
+		err := svc.SaveEntityHandler(id, email)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	err := svc.RecordUsage(metering.Usage{Publisher: thingID, Channel: chanID, Size: 10, Occurred: time.Now()})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	err = svc.Flush()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+}
+
+func TestListRecords(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+
+	meter(t, svc)
+	month := time.Now().UTC().Format("2006-01")
+	last := time.Now().UTC().AddDate(0, -1, 0).Format("2006-01")
+
+	rec := recordRes{
+		Owner:         email,
+		Month:         month,
+		MessagesIn:    1,
+		MessagesOut:   1,
+		StorageBytes:  10,
+		ActiveDevices: 1,
+	}
+
+	cases := []struct {
+		desc   string
+		query  string
+		token  string
+		status int
+		res    string
+	}{
+		{
+			desc:   "list records of current month",
+			query:  "",
+			token:  validToken,
+			status: http.StatusOK,
+			res:    toJSON(map[string][]recordRes{"records": {rec}}),
+		},
+		{
+			desc:   "list records of last month",
+			query:  fmt.Sprintf("?from=%s&to=%s", last, last),
+			token:  validToken,
+			status: http.StatusOK,
+			res:    toJSON(map[string][]recordRes{"records": {}}),
+		},
+		{
+			desc:   "list records with invalid month",
+			query:  "?from=2019-13",
+			token:  validToken,
+			status: http.StatusBadRequest,
+			res:    "",
+		},
+		{
+			desc:   "list records with invalid range",
+			query:  fmt.Sprintf("?from=%s&to=%s", month, last),
+			token:  validToken,
+			status: http.StatusBadRequest,
+			res:    "",
+		},
+		{
+			desc:   "list records with invalid token",
+			query:  "",
+			token:  invalidToken,
+			status: http.StatusForbidden,
+			res:    "",
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/records%s", ts.URL, tc.query),
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		body, err := ioutil.ReadAll(res.Body)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.res, strings.Trim(string(body), "\n"), fmt.Sprintf("%s: expected body %s got %s", tc.desc, tc.res, body))
+	}
+}
+
+func TestExport(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+
+	meter(t, svc)
+	month := time.Now().UTC().Format("2006-01")
+
+	cases := []struct {
+		desc        string
+		token       string
+		accept      string
+		status      int
+		contentType string
+		res         string
+	}{
+		{
+			desc:        "export records as JSON",
+			token:       exportKey,
+			status:      http.StatusOK,
+			contentType: contentType,
+			res:         toJSON(map[string][]recordRes{"records": {{Owner: email, Month: month, MessagesIn: 1, MessagesOut: 1, StorageBytes: 10, ActiveDevices: 1}}}),
+		},
+		{
+			desc:        "export records as CSV",
+			token:       exportKey,
+			accept:      "text/csv",
+			status:      http.StatusOK,
+			contentType: "text/csv",
+			res:         fmt.Sprintf("owner,month,messages_in,messages_out,storage_bytes,active_devices\n%s,%s,1,1,10,1", email, month),
+		},
+		{
+			desc:        "export records with user token",
+			token:       validToken,
+			status:      http.StatusForbidden,
+			contentType: contentType,
+			res:         "",
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/export?month=%s", ts.URL, month),
+			token:  tc.token,
+			accept: tc.accept,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		ct := res.Header.Get("Content-Type")
+		assert.Equal(t, tc.contentType, ct, fmt.Sprintf("%s: expected content type %s got %s", tc.desc, tc.contentType, ct))
+		body, err := ioutil.ReadAll(res.Body)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.res, strings.Trim(string(body), "\n"), fmt.Sprintf("%s: expected body %s got %s", tc.desc, tc.res, body))
+	}
+}
+
+func TestAddThreshold(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+
+	valid := toJSON(thresholdRes{Metric: threshold.Metric, Limit: threshold.Limit, URL: threshold.URL})
+	invalid := toJSON(thresholdRes{Metric: "unknown", Limit: threshold.Limit, URL: threshold.URL})
+
+	cases := []struct {
+		desc        string
+		req         string
+		contentType string
+		token       string
+		status      int
+		location    string
+	}{
+		{
+			desc:        "add threshold with valid request",
+			req:         valid,
+			contentType: contentType,
+			token:       validToken,
+			status:      http.StatusCreated,
+			location:    "/thresholds/123e4567-e89b-12d3-a456-000000000001",
+		},
+		{
+			desc:        "add threshold with invalid token",
+			req:         valid,
+			contentType: contentType,
+			token:       invalidToken,
+			status:      http.StatusForbidden,
+		},
+		{
+			desc:        "add threshold of unknown metric",
+			req:         invalid,
+			contentType: contentType,
+			token:       validToken,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "add threshold with invalid request format",
+			req:         "}",
+			contentType: contentType,
+			token:       validToken,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "add threshold without content type",
+			req:         valid,
+			contentType: "",
+			token:       validToken,
+			status:      http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/thresholds", ts.URL),
+			contentType: tc.contentType,
+			token:       tc.token,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		location := res.Header.Get("Location")
+		assert.Equal(t, tc.location, location, fmt.Sprintf("%s: expected location %s got %s", tc.desc, tc.location, location))
+	}
+}
+
+func TestListThresholds(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+
+	saved, err := svc.AddThreshold(validToken, threshold)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	data := toJSON(map[string][]thresholdRes{"thresholds": {{ID: saved.ID, Metric: saved.Metric, Limit: saved.Limit, URL: saved.URL}}})
+
+	cases := []struct {
+		desc   string
+		token  string
+		status int
+		res    string
+	}{
+		{
+			desc:   "list thresholds",
+			token:  validToken,
+			status: http.StatusOK,
+			res:    data,
+		},
+		{
+			desc:   "list thresholds with invalid token",
+			token:  invalidToken,
+			status: http.StatusForbidden,
+			res:    "",
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/thresholds", ts.URL),
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		body, err := ioutil.ReadAll(res.Body)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.res, strings.Trim(string(body), "\n"), fmt.Sprintf("%s: expected body %s got %s", tc.desc, tc.res, body))
+	}
+}
+
+func TestRemoveThreshold(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+
+	saved, err := svc.AddThreshold(validToken, threshold)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		id     string
+		token  string
+		status int
+	}{
+		{
+			desc:   "remove threshold with invalid token",
+			id:     saved.ID,
+			token:  invalidToken,
+			status: http.StatusForbidden,
+		},
+		{
+			desc:   "remove existing threshold",
+			id:     saved.ID,
+			token:  validToken,
+			status: http.StatusNoContent,
+		},
+		{
+			desc:   "remove removed threshold",
+			id:     saved.ID,
+			token:  validToken,
+			status: http.StatusNoContent,
+		},
+		{
+			desc:   "remove threshold with malformed id",
+			id:     "invalid",
+			token:  validToken,
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodDelete,
+			url:    fmt.Sprintf("%s/thresholds/%s", ts.URL, tc.id),
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
@@ -0,0 +1,160 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// +build !test
+
+package api
+
+import (
+	"fmt"
+	"time"
+
+	log "github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/metering"
+)
+
+var _ metering.Service = (*loggingMiddleware)(nil)
+
+type loggingMiddleware struct {
+	logger log.Logger
+	svc    metering.Service
+}
+
+// NewLoggingMiddleware adds logging facilities to the core service.
+func NewLoggingMiddleware(svc metering.Service, logger log.Logger) metering.Service {
+	return &loggingMiddleware{logger, svc}
+}
+
+func (lm *loggingMiddleware) RecordUsage(u metering.Usage) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method record_usage for thing %s and channel %s took %s to complete", u.Publisher, u.Channel, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RecordUsage(u)
+}
+
+func (lm *loggingMiddleware) Flush() (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method flush took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Flush()
+}
+
+func (lm *loggingMiddleware) ListRecords(key string, from, to time.Time) (recs []metering.Record, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_records for key %s from %s to %s took %s to complete", key, from.Format("2006-01"), to.Format("2006-01"), time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListRecords(key, from, to)
+}
+
+func (lm *loggingMiddleware) ExportRecords(key string, month time.Time) (recs []metering.Record, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method export_records for month %s took %s to complete", month.Format("2006-01"), time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ExportRecords(key, month)
+}
+
+func (lm *loggingMiddleware) AddThreshold(key string, th metering.Threshold) (saved metering.Threshold, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method add_threshold for key %s and threshold %s took %s to complete", key, saved.ID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.AddThreshold(key, th)
+}
+
+func (lm *loggingMiddleware) ListThresholds(key string) (ths []metering.Threshold, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_thresholds for key %s took %s to complete", key, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListThresholds(key)
+}
+
+func (lm *loggingMiddleware) RemoveThreshold(key, id string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method remove_threshold for key %s and threshold %s took %s to complete", key, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RemoveThreshold(key, id)
+}
+
+func (lm *loggingMiddleware) SaveEntityHandler(id, owner string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method save_entity_handler for entity %s and user %s took %s to complete", id, owner, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.SaveEntityHandler(id, owner)
+}
+
+func (lm *loggingMiddleware) RemoveEntityHandler(id string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method remove_entity_handler for entity %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RemoveEntityHandler(id)
+}
+
+func (lm *loggingMiddleware) RemoveUserHandler(owner string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method remove_user_handler for user %s took %s to complete", owner, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RemoveUserHandler(owner)
+}
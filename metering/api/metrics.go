@@ -0,0 +1,125 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// +build !test
+
+package api
+
+import (
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/mainflux/mainflux/metering"
+)
+
+var _ metering.Service = (*metricsMiddleware)(nil)
+
+type metricsMiddleware struct {
+	counter metrics.Counter
+	latency metrics.Histogram
+	svc     metering.Service
+}
+
+// MetricsMiddleware instruments core service by tracking request count and
+// latency.
+func MetricsMiddleware(svc metering.Service, counter metrics.Counter, latency metrics.Histogram) metering.Service {
+	return &metricsMiddleware{
+		counter: counter,
+		latency: latency,
+		svc:     svc,
+	}
+}
+
+func (mm *metricsMiddleware) RecordUsage(u metering.Usage) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "record_usage").Add(1)
+		mm.latency.With("method", "record_usage").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.RecordUsage(u)
+}
+
+func (mm *metricsMiddleware) Flush() error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "flush").Add(1)
+		mm.latency.With("method", "flush").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.Flush()
+}
+
+func (mm *metricsMiddleware) ListRecords(key string, from, to time.Time) ([]metering.Record, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "list_records").Add(1)
+		mm.latency.With("method", "list_records").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.ListRecords(key, from, to)
+}
+
+func (mm *metricsMiddleware) ExportRecords(key string, month time.Time) ([]metering.Record, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "export_records").Add(1)
+		mm.latency.With("method", "export_records").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.ExportRecords(key, month)
+}
+
+func (mm *metricsMiddleware) AddThreshold(key string, th metering.Threshold) (metering.Threshold, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "add_threshold").Add(1)
+		mm.latency.With("method", "add_threshold").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.AddThreshold(key, th)
+}
+
+func (mm *metricsMiddleware) ListThresholds(key string) ([]metering.Threshold, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "list_thresholds").Add(1)
+		mm.latency.With("method", "list_thresholds").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.ListThresholds(key)
+}
+
+func (mm *metricsMiddleware) RemoveThreshold(key, id string) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "remove_threshold").Add(1)
+		mm.latency.With("method", "remove_threshold").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.RemoveThreshold(key, id)
+}
+
+func (mm *metricsMiddleware) SaveEntityHandler(id, owner string) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "save_entity_handler").Add(1)
+		mm.latency.With("method", "save_entity_handler").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.SaveEntityHandler(id, owner)
+}
+
+func (mm *metricsMiddleware) RemoveEntityHandler(id string) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "remove_entity_handler").Add(1)
+		mm.latency.With("method", "remove_entity_handler").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.RemoveEntityHandler(id)
+}
+
+func (mm *metricsMiddleware) RemoveUserHandler(owner string) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "remove_user_handler").Add(1)
+		mm.latency.With("method", "remove_user_handler").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.RemoveUserHandler(owner)
+}
@@ -0,0 +1,98 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package api
+
+import (
+	"time"
+
+	"github.com/mainflux/mainflux/metering"
+)
+
+type apiReq interface {
+	validate() error
+}
+
+type listRecordsReq struct {
+	key  string
+	from time.Time
+	to   time.Time
+}
+
+func (req listRecordsReq) validate() error {
+	if req.key == "" {
+		return metering.ErrUnauthorizedAccess
+	}
+
+	if req.from.After(req.to) {
+		return metering.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type exportReq struct {
+	key   string
+	month time.Time
+	csv   bool
+}
+
+func (req exportReq) validate() error {
+	if req.key == "" {
+		return metering.ErrUnauthorizedAccess
+	}
+
+	return nil
+}
+
+type addThresholdReq struct {
+	key    string
+	Metric string `json:"metric"`
+	Limit  uint64 `json:"limit"`
+	URL    string `json:"url"`
+}
+
+func (req addThresholdReq) validate() error {
+	if req.key == "" {
+		return metering.ErrUnauthorizedAccess
+	}
+
+	if req.Metric == "" || req.Limit == 0 || req.URL == "" {
+		return metering.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type entityReq struct {
+	key string
+	id  string
+}
+
+func (req entityReq) validate() error {
+	if req.key == "" {
+		return metering.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" {
+		return metering.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type listReq struct {
+	key string
+}
+
+func (req listReq) validate() error {
+	if req.key == "" {
+		return metering.ErrUnauthorizedAccess
+	}
+
+	return nil
+}
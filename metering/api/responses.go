@@ -0,0 +1,124 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/mainflux/mainflux"
+)
+
+var (
+	_ mainflux.Response = (*recordsRes)(nil)
+	_ mainflux.Response = (*documentRes)(nil)
+	_ mainflux.Response = (*addThresholdRes)(nil)
+	_ mainflux.Response = (*listThresholdsRes)(nil)
+	_ mainflux.Response = (*removeRes)(nil)
+)
+
+type recordRes struct {
+	Owner         string `json:"owner"`
+	Month         string `json:"month"`
+	MessagesIn    uint64 `json:"messages_in"`
+	MessagesOut   uint64 `json:"messages_out"`
+	StorageBytes  uint64 `json:"storage_bytes"`
+	ActiveDevices uint64 `json:"active_devices"`
+}
+
+type recordsRes struct {
+	Records []recordRes `json:"records"`
+}
+
+func (res recordsRes) Code() int {
+	return http.StatusOK
+}
+
+func (res recordsRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res recordsRes) Empty() bool {
+	return false
+}
+
+type documentRes struct {
+	name        string
+	contentType string
+	content     []byte
+}
+
+func (res documentRes) Code() int {
+	return http.StatusOK
+}
+
+func (res documentRes) Headers() map[string]string {
+	return map[string]string{
+		"Content-Type":        res.contentType,
+		"Content-Disposition": fmt.Sprintf("attachment; filename=%q", res.name),
+	}
+}
+
+func (res documentRes) Empty() bool {
+	return false
+}
+
+type addThresholdRes struct {
+	id string
+}
+
+func (res addThresholdRes) Code() int {
+	return http.StatusCreated
+}
+
+func (res addThresholdRes) Headers() map[string]string {
+	return map[string]string{
+		"Location": fmt.Sprintf("/thresholds/%s", res.id),
+	}
+}
+
+func (res addThresholdRes) Empty() bool {
+	return true
+}
+
+type thresholdRes struct {
+	ID     string `json:"id"`
+	Metric string `json:"metric"`
+	Limit  uint64 `json:"limit"`
+	URL    string `json:"url"`
+}
+
+type listThresholdsRes struct {
+	Thresholds []thresholdRes `json:"thresholds"`
+}
+
+func (res listThresholdsRes) Code() int {
+	return http.StatusOK
+}
+
+func (res listThresholdsRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res listThresholdsRes) Empty() bool {
+	return false
+}
+
+type removeRes struct{}
+
+func (res removeRes) Code() int {
+	return http.StatusNoContent
+}
+
+func (res removeRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res removeRes) Empty() bool {
+	return true
+}
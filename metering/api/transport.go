@@ -0,0 +1,208 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/go-zoo/bone"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/identifiers"
+	"github.com/mainflux/mainflux/metering"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	contentType    = "application/json"
+	csvContentType = "text/csv"
+	monthFormat    = "2006-01"
+)
+
+var errUnsupportedContentType = errors.New("unsupported content type")
+
+// MakeHandler returns a HTTP handler for API endpoints.
+func MakeHandler(svc metering.Service) http.Handler {
+	opts := []kithttp.ServerOption{
+		kithttp.ServerErrorEncoder(encodeError),
+	}
+	r := bone.New()
+
+	r.Get("/records", kithttp.NewServer(
+		listRecordsEndpoint(svc),
+		decodeListRecordsRequest,
+		encodeResponse,
+		opts...))
+
+	r.Get("/export", kithttp.NewServer(
+		exportEndpoint(svc),
+		decodeExportRequest,
+		encodeResponse,
+		opts...))
+
+	r.Post("/thresholds", kithttp.NewServer(
+		addThresholdEndpoint(svc),
+		decodeAddThresholdRequest,
+		encodeResponse,
+		opts...))
+
+	r.Get("/thresholds", kithttp.NewServer(
+		listThresholdsEndpoint(svc),
+		decodeListRequest,
+		encodeResponse,
+		opts...))
+
+	r.Delete("/thresholds/:id", kithttp.NewServer(
+		removeThresholdEndpoint(svc),
+		decodeEntityRequest,
+		encodeResponse,
+		opts...))
+
+	r.GetFunc("/version", mainflux.Version("metering"))
+	r.Handle("/metrics", promhttp.Handler())
+
+	return r
+}
+
+func decodeListRecordsRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	from, err := readMonth(r, "from")
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := readMonth(r, "to")
+	if err != nil {
+		return nil, err
+	}
+
+	req := listRecordsReq{
+		key:  r.Header.Get("Authorization"),
+		from: from,
+		to:   to,
+	}
+
+	return req, nil
+}
+
+func decodeExportRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	month, err := readMonth(r, "month")
+	if err != nil {
+		return nil, err
+	}
+
+	req := exportReq{
+		key:   r.Header.Get("Authorization"),
+		month: month,
+		csv:   strings.Contains(r.Header.Get("Accept"), csvContentType),
+	}
+
+	return req, nil
+}
+
+func decodeAddThresholdRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+
+	req := addThresholdReq{key: r.Header.Get("Authorization")}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func decodeEntityRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	id, err := identifiers.Parse(bone.GetValue(r, "id"))
+	if err != nil {
+		return nil, err
+	}
+
+	req := entityReq{
+		key: r.Header.Get("Authorization"),
+		id:  id,
+	}
+
+	return req, nil
+}
+
+func decodeListRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	req := listReq{key: r.Header.Get("Authorization")}
+
+	return req, nil
+}
+
+// readMonth parses the query parameter formatted as YYYY-MM. The current
+// month is used if the parameter is missing.
+func readMonth(r *http.Request, key string) (time.Time, error) {
+	val := r.URL.Query().Get(key)
+	if val == "" {
+		return metering.Month(time.Now()), nil
+	}
+
+	month, err := time.Parse(monthFormat, val)
+	if err != nil {
+		return time.Time{}, metering.ErrMalformedEntity
+	}
+
+	return month, nil
+}
+
+func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", contentType)
+	if ar, ok := response.(mainflux.Response); ok {
+		for k, v := range ar.Headers() {
+			w.Header().Set(k, v)
+		}
+
+		w.WriteHeader(ar.Code())
+
+		if ar.Empty() {
+			return nil
+		}
+	}
+
+	if doc, ok := response.(documentRes); ok {
+		_, err := w.Write(doc.content)
+		return err
+	}
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	w.Header().Set("Content-Type", contentType)
+
+	switch err {
+	case errUnsupportedContentType:
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+	case metering.ErrMalformedEntity, identifiers.ErrMalformedID:
+		w.WriteHeader(http.StatusBadRequest)
+	case metering.ErrNotFound:
+		w.WriteHeader(http.StatusNotFound)
+	case metering.ErrUnauthorizedAccess:
+		w.WriteHeader(http.StatusForbidden)
+	case io.EOF:
+		w.WriteHeader(http.StatusBadRequest)
+	default:
+		switch err.(type) {
+		case *json.SyntaxError:
+			w.WriteHeader(http.StatusBadRequest)
+		case *json.UnmarshalTypeError:
+			w.WriteHeader(http.StatusBadRequest)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package metering
+
+import (
+	"net/url"
+	"time"
+)
+
+// Supported usage metrics.
+const (
+	MessagesIn    = "messages_in"
+	MessagesOut   = "messages_out"
+	StorageBytes  = "storage_bytes"
+	ActiveDevices = "active_devices"
+)
+
+var metrics = map[string]bool{
+	MessagesIn:    true,
+	MessagesOut:   true,
+	StorageBytes:  true,
+	ActiveDevices: true,
+}
+
+// Record represents the usage of a single owner during a calendar month.
+// Messages in are the messages published by the owner's things, while
+// messages out and storage bytes are the messages and the payload bytes
+// received on the owner's channels. Active devices is the number of distinct
+// owner's things that published at least once during the month.
+type Record struct {
+	Owner         string
+	Month         time.Time
+	MessagesIn    uint64
+	MessagesOut   uint64
+	StorageBytes  uint64
+	ActiveDevices uint64
+}
+
+// Value returns the record value of the given metric.
+func (r Record) Value(metric string) uint64 {
+	switch metric {
+	case MessagesIn:
+		return r.MessagesIn
+	case MessagesOut:
+		return r.MessagesOut
+	case StorageBytes:
+		return r.StorageBytes
+	case ActiveDevices:
+		return r.ActiveDevices
+	default:
+		return 0
+	}
+}
+
+func (r *Record) add(delta Record) {
+	r.MessagesIn += delta.MessagesIn
+	r.MessagesOut += delta.MessagesOut
+	r.StorageBytes += delta.StorageBytes
+	r.ActiveDevices += delta.ActiveDevices
+}
+
+// Month returns the first instant of the calendar month the given time
+// belongs to, in UTC.
+func Month(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// Usage represents a single message published by the thing to the channel.
+type Usage struct {
+	Publisher string
+	Channel   string
+	Size      uint64
+	Occurred  time.Time
+}
+
+// Threshold represents the user request to be notified, by the webhook
+// invoked on the URL, once the monthly value of the metric reaches the limit.
+type Threshold struct {
+	ID     string
+	Owner  string
+	Metric string
+	Limit  uint64
+	URL    string
+}
+
+// Validate returns an error if threshold representation is invalid.
+func (t Threshold) Validate() error {
+	if !metrics[t.Metric] || t.Limit == 0 {
+		return ErrMalformedEntity
+	}
+
+	u, err := url.Parse(t.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrMalformedEntity
+	}
+
+	return nil
+}
+
+// Alert represents the notification about the reached threshold.
+type Alert struct {
+	Threshold Threshold
+	Month     time.Time
+	Value     uint64
+}
+
+// RecordRepository specifies a usage record persistence API.
+type RecordRepository interface {
+	// Add adds the delta to the record of the delta owner and month, and
+	// counts the devices that were not active in that month yet. The
+	// record value before and after the update is returned.
+	Add(Record, []string) (Record, Record, error)
+
+	// RetrieveAll retrieves the records of the specified owner, for the
+	// months between the provided ones, inclusive.
+	RetrieveAll(string, time.Time, time.Time) ([]Record, error)
+
+	// RetrieveMonth retrieves the records of all the owners for the
+	// provided month.
+	RetrieveMonth(time.Time) ([]Record, error)
+}
+
+// EntityRepository specifies an API for keeping track of the owners of
+// things and channels.
+type EntityRepository interface {
+	// Save persists the owner of the thing or channel having the provided
+	// identifier.
+	Save(string, string) error
+
+	// RetrieveOwner retrieves the owner of the thing or channel having the
+	// provided identifier.
+	RetrieveOwner(string) (string, error)
+
+	// Remove removes the thing or channel having the provided identifier.
+	Remove(string) error
+
+	// RemoveAll removes all the things and channels of the specified owner.
+	RemoveAll(string) error
+}
+
+// ThresholdRepository specifies a threshold persistence API.
+type ThresholdRepository interface {
+	// Save persists the threshold. Successful operation is indicated by
+	// unique identifier accompanied by nil error response. A non-nil error
+	// is returned to indicate operation failure.
+	Save(Threshold) (string, error)
+
+	// RetrieveAll retrieves all the thresholds of the specified owner.
+	RetrieveAll(string) ([]Threshold, error)
+
+	// Remove removes the threshold having the provided identifier, that is
+	// owned by the specified user.
+	Remove(string, string) error
+
+	// RemoveAll removes all the thresholds of the specified owner.
+	RemoveAll(string) error
+}
+
+// Sender specifies an API for the delivery of threshold alerts.
+type Sender interface {
+	// Send delivers the alert to the threshold URL.
+	Send(Alert) error
+}
+
+// IdentityProvider specifies an API for generating unique identifiers.
+type IdentityProvider interface {
+	// ID generates the unique identifier.
+	ID() (string, error)
+}
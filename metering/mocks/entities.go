@@ -0,0 +1,69 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package mocks
+
+import (
+	"sync"
+
+	"github.com/mainflux/mainflux/metering"
+)
+
+var _ metering.EntityRepository = (*entityRepositoryMock)(nil)
+
+type entityRepositoryMock struct {
+	mu     sync.Mutex
+	owners map[string]string
+}
+
+// NewEntityRepository creates in-memory entity repository.
+func NewEntityRepository() metering.EntityRepository {
+	return &entityRepositoryMock{
+		owners: make(map[string]string),
+	}
+}
+
+func (erm *entityRepositoryMock) Save(id, owner string) error {
+	erm.mu.Lock()
+	defer erm.mu.Unlock()
+
+	erm.owners[id] = owner
+	return nil
+}
+
+func (erm *entityRepositoryMock) RetrieveOwner(id string) (string, error) {
+	erm.mu.Lock()
+	defer erm.mu.Unlock()
+
+	owner, ok := erm.owners[id]
+	if !ok {
+		return "", metering.ErrNotFound
+	}
+
+	return owner, nil
+}
+
+func (erm *entityRepositoryMock) Remove(id string) error {
+	erm.mu.Lock()
+	defer erm.mu.Unlock()
+
+	delete(erm.owners, id)
+	return nil
+}
+
+func (erm *entityRepositoryMock) RemoveAll(owner string) error {
+	erm.mu.Lock()
+	defer erm.mu.Unlock()
+
+	for id, o := range erm.owners {
+		if o == owner {
+			delete(erm.owners, id)
+		}
+	}
+
+	return nil
+}
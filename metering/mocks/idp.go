@@ -0,0 +1,36 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package mocks
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/mainflux/mainflux/metering"
+)
+
+var _ metering.IdentityProvider = (*identityProviderMock)(nil)
+
+type identityProviderMock struct {
+	mu      sync.Mutex
+	counter int
+}
+
+// NewIdentityProvider creates "mirror" identity provider, i.e. generated
+// identifiers are valid UUIDs ending with incremented counter values.
+func NewIdentityProvider() metering.IdentityProvider {
+	return &identityProviderMock{}
+}
+
+func (idp *identityProviderMock) ID() (string, error) {
+	idp.mu.Lock()
+	defer idp.mu.Unlock()
+
+	idp.counter++
+	return fmt.Sprintf("123e4567-e89b-12d3-a456-%012d", idp.counter), nil
+}
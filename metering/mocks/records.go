@@ -0,0 +1,104 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package mocks
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux/metering"
+)
+
+var _ metering.RecordRepository = (*recordRepositoryMock)(nil)
+
+type recordKey struct {
+	owner string
+	month time.Time
+}
+
+type recordRepositoryMock struct {
+	mu      sync.Mutex
+	records map[recordKey]metering.Record
+	devices map[recordKey]map[string]bool
+}
+
+// NewRecordRepository creates in-memory record repository.
+func NewRecordRepository() metering.RecordRepository {
+	return &recordRepositoryMock{
+		records: make(map[recordKey]metering.Record),
+		devices: make(map[recordKey]map[string]bool),
+	}
+}
+
+func (rrm *recordRepositoryMock) Add(delta metering.Record, devices []string) (metering.Record, metering.Record, error) {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	key := recordKey{owner: delta.Owner, month: delta.Month}
+	prev, ok := rrm.records[key]
+	if !ok {
+		prev = metering.Record{Owner: delta.Owner, Month: delta.Month}
+	}
+
+	active, ok := rrm.devices[key]
+	if !ok {
+		active = make(map[string]bool)
+		rrm.devices[key] = active
+	}
+
+	cur := prev
+	cur.MessagesIn += delta.MessagesIn
+	cur.MessagesOut += delta.MessagesOut
+	cur.StorageBytes += delta.StorageBytes
+	for _, id := range devices {
+		if !active[id] {
+			active[id] = true
+			cur.ActiveDevices++
+		}
+	}
+
+	rrm.records[key] = cur
+	return prev, cur, nil
+}
+
+func (rrm *recordRepositoryMock) RetrieveAll(owner string, from, to time.Time) ([]metering.Record, error) {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	recs := []metering.Record{}
+	for key, rec := range rrm.records {
+		if key.owner == owner && !key.month.Before(from) && !key.month.After(to) {
+			recs = append(recs, rec)
+		}
+	}
+
+	sort.Slice(recs, func(i, j int) bool {
+		return recs[i].Month.Before(recs[j].Month)
+	})
+
+	return recs, nil
+}
+
+func (rrm *recordRepositoryMock) RetrieveMonth(month time.Time) ([]metering.Record, error) {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	recs := []metering.Record{}
+	for key, rec := range rrm.records {
+		if key.month.Equal(month) {
+			recs = append(recs, rec)
+		}
+	}
+
+	sort.Slice(recs, func(i, j int) bool {
+		return recs[i].Owner < recs[j].Owner
+	})
+
+	return recs, nil
+}
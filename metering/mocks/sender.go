@@ -0,0 +1,53 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package mocks
+
+import (
+	"sync"
+
+	"github.com/mainflux/mainflux/metering"
+)
+
+var _ metering.Sender = (*SenderMock)(nil)
+
+// SenderMock records the alerts instead of delivering them.
+type SenderMock struct {
+	mu   sync.Mutex
+	sent map[string][]metering.Alert
+	fail map[string]error
+}
+
+// NewSender creates the sender mock. Sending to the URLs present in the
+// provided map fails with the corresponding error.
+func NewSender(fail map[string]error) *SenderMock {
+	return &SenderMock{
+		sent: make(map[string][]metering.Alert),
+		fail: fail,
+	}
+}
+
+// Send records the alert sent to the threshold URL.
+func (sm *SenderMock) Send(alert metering.Alert) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	if err, ok := sm.fail[alert.Threshold.URL]; ok {
+		return err
+	}
+
+	sm.sent[alert.Threshold.URL] = append(sm.sent[alert.Threshold.URL], alert)
+	return nil
+}
+
+// Sent returns the alerts sent to the URL.
+func (sm *SenderMock) Sent(url string) []metering.Alert {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	return sm.sent[url]
+}
@@ -0,0 +1,79 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package mocks
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/mainflux/mainflux/metering"
+)
+
+var _ metering.ThresholdRepository = (*thresholdRepositoryMock)(nil)
+
+type thresholdRepositoryMock struct {
+	mu         sync.Mutex
+	thresholds map[string]metering.Threshold
+}
+
+// NewThresholdRepository creates in-memory threshold repository.
+func NewThresholdRepository() metering.ThresholdRepository {
+	return &thresholdRepositoryMock{
+		thresholds: make(map[string]metering.Threshold),
+	}
+}
+
+func (trm *thresholdRepositoryMock) Save(th metering.Threshold) (string, error) {
+	trm.mu.Lock()
+	defer trm.mu.Unlock()
+
+	trm.thresholds[th.ID] = th
+	return th.ID, nil
+}
+
+func (trm *thresholdRepositoryMock) RetrieveAll(owner string) ([]metering.Threshold, error) {
+	trm.mu.Lock()
+	defer trm.mu.Unlock()
+
+	ths := []metering.Threshold{}
+	for _, th := range trm.thresholds {
+		if th.Owner == owner {
+			ths = append(ths, th)
+		}
+	}
+
+	sort.Slice(ths, func(i, j int) bool {
+		return ths[i].ID < ths[j].ID
+	})
+
+	return ths, nil
+}
+
+func (trm *thresholdRepositoryMock) Remove(owner, id string) error {
+	trm.mu.Lock()
+	defer trm.mu.Unlock()
+
+	if th, ok := trm.thresholds[id]; ok && th.Owner == owner {
+		delete(trm.thresholds, id)
+	}
+
+	return nil
+}
+
+func (trm *thresholdRepositoryMock) RemoveAll(owner string) error {
+	trm.mu.Lock()
+	defer trm.mu.Unlock()
+
+	for id, th := range trm.thresholds {
+		if th.Owner == owner {
+			delete(trm.thresholds, id)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package nats
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/mainflux/mainflux"
+	log "github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/metering"
+	broker "github.com/nats-io/go-nats"
+)
+
+const (
+	queue = "metering"
+	input = "channel.>"
+)
+
+type consumer struct {
+	svc    metering.Service
+	logger log.Logger
+}
+
+// Subscribe subscribes to the messages published by the adapters and meters
+// them. Messages are consumed in the queue group, so that every message is
+// metered once regardless of the number of service instances.
+func Subscribe(svc metering.Service, nc *broker.Conn, logger log.Logger) error {
+	c := consumer{
+		svc:    svc,
+		logger: logger,
+	}
+
+	_, err := nc.QueueSubscribe(input, queue, c.consume)
+	return err
+}
+
+func (c consumer) consume(m *broker.Msg) {
+	var msg mainflux.RawMessage
+	if err := proto.Unmarshal(m.Data, &msg); err != nil {
+		c.logger.Warn(fmt.Sprintf("Failed to unmarshal received message: %s", err))
+		return
+	}
+
+	u := metering.Usage{
+		Publisher: msg.GetPublisher(),
+		Channel:   msg.GetChannel(),
+		Size:      uint64(len(msg.GetPayload())),
+		Occurred:  time.Now(),
+	}
+	if err := c.svc.RecordUsage(u); err != nil {
+		c.logger.Warn(fmt.Sprintf("Failed to record usage: %s", err))
+	}
+}
@@ -0,0 +1,62 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package postgres
+
+import (
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/mainflux/mainflux/metering"
+)
+
+var _ metering.EntityRepository = (*entityRepository)(nil)
+
+type entityRepository struct {
+	db *sqlx.DB
+}
+
+// NewEntityRepository instantiates a PostgreSQL implementation of entity
+// repository.
+func NewEntityRepository(db *sqlx.DB) metering.EntityRepository {
+	return &entityRepository{db: db}
+}
+
+func (er entityRepository) Save(id, owner string) error {
+	q := `INSERT INTO entities (id, owner) VALUES ($1, $2) ON CONFLICT (id) DO UPDATE SET owner = EXCLUDED.owner`
+
+	_, err := er.db.Exec(q, id, owner)
+	return err
+}
+
+func (er entityRepository) RetrieveOwner(id string) (string, error) {
+	q := `SELECT owner FROM entities WHERE id = $1`
+
+	var owner string
+	if err := er.db.QueryRowx(q, id).Scan(&owner); err != nil {
+		if err == sql.ErrNoRows {
+			return "", metering.ErrNotFound
+		}
+		return "", err
+	}
+
+	return owner, nil
+}
+
+func (er entityRepository) Remove(id string) error {
+	q := `DELETE FROM entities WHERE id = $1`
+
+	_, err := er.db.Exec(q, id)
+	return err
+}
+
+func (er entityRepository) RemoveAll(owner string) error {
+	q := `DELETE FROM entities WHERE owner = $1`
+
+	_, err := er.db.Exec(q, owner)
+	return err
+}
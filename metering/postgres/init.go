@@ -0,0 +1,108 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq" // required for SQL access
+	mfpostgres "github.com/mainflux/mainflux/postgres"
+	migrate "github.com/rubenv/sql-migrate"
+)
+
+// Config defines the options that are used when connecting to a PostgreSQL instance
+type Config struct {
+	Host        string
+	Port        string
+	User        string
+	Pass        string
+	Name        string
+	SSLMode     string
+	SSLCert     string
+	SSLKey      string
+	SSLRootCert string
+	Target      string
+	SyncCommit  string
+}
+
+// Connect creates a connection to the PostgreSQL instance and applies any
+// unapplied database migrations. A non-nil error is returned to indicate
+// failure.
+func Connect(cfg Config) (*sqlx.DB, error) {
+	url := fmt.Sprintf("user=%s dbname=%s password=%s sslmode=%s sslcert=%s sslkey=%s sslrootcert=%s", cfg.User, cfg.Name, cfg.Pass, cfg.SSLMode, cfg.SSLCert, cfg.SSLKey, cfg.SSLRootCert)
+
+	db, err := mfpostgres.Open(mfpostgres.Config{
+		Hosts:      cfg.Host,
+		Port:       cfg.Port,
+		DSN:        url,
+		Target:     cfg.Target,
+		SyncCommit: cfg.SyncCommit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := migrateDB(db); err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+func migrateDB(db *sqlx.DB) error {
+	migrations := &migrate.MemoryMigrationSource{
+		Migrations: []*migrate.Migration{
+			{
+				Id: "metering_1",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS records (
+						owner          VARCHAR(254) NOT NULL,
+						month          DATE NOT NULL,
+						messages_in    BIGINT NOT NULL DEFAULT 0,
+						messages_out   BIGINT NOT NULL DEFAULT 0,
+						storage_bytes  BIGINT NOT NULL DEFAULT 0,
+						active_devices BIGINT NOT NULL DEFAULT 0,
+						PRIMARY KEY (owner, month)
+					)`,
+					`CREATE INDEX IF NOT EXISTS records_month_idx ON records (month)`,
+					`CREATE TABLE IF NOT EXISTS active_devices (
+						owner VARCHAR(254) NOT NULL,
+						month DATE NOT NULL,
+						thing VARCHAR(254) NOT NULL,
+						PRIMARY KEY (owner, month, thing)
+					)`,
+					`CREATE TABLE IF NOT EXISTS entities (
+						id    VARCHAR(254) PRIMARY KEY,
+						owner VARCHAR(254) NOT NULL
+					)`,
+					`CREATE INDEX IF NOT EXISTS entities_owner_idx ON entities (owner)`,
+					`CREATE TABLE IF NOT EXISTS thresholds (
+						id     UUID,
+						owner  VARCHAR(254) NOT NULL,
+						metric VARCHAR(32) NOT NULL,
+						lim    BIGINT NOT NULL,
+						url    TEXT NOT NULL,
+						PRIMARY KEY (id, owner)
+					)`,
+					`CREATE INDEX IF NOT EXISTS thresholds_owner_idx ON thresholds (owner)`,
+				},
+				Down: []string{
+					"DROP TABLE thresholds",
+					"DROP TABLE entities",
+					"DROP TABLE active_devices",
+					"DROP TABLE records",
+				},
+			},
+		},
+	}
+
+	_, err := migrate.Exec(db.DB, "postgres", migrations, migrate.Up)
+
+	return err
+}
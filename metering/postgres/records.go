@@ -0,0 +1,137 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package postgres
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/mainflux/mainflux/metering"
+)
+
+var _ metering.RecordRepository = (*recordRepository)(nil)
+
+type recordRepository struct {
+	db *sqlx.DB
+}
+
+// NewRecordRepository instantiates a PostgreSQL implementation of record
+// repository.
+func NewRecordRepository(db *sqlx.DB) metering.RecordRepository {
+	return &recordRepository{db: db}
+}
+
+func (rr recordRepository) Add(delta metering.Record, devices []string) (metering.Record, metering.Record, error) {
+	tx, err := rr.db.Beginx()
+	if err != nil {
+		return metering.Record{}, metering.Record{}, err
+	}
+
+	dq := `INSERT INTO active_devices (owner, month, thing) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`
+
+	delta.ActiveDevices = 0
+	for _, id := range devices {
+		res, err := tx.Exec(dq, delta.Owner, delta.Month, id)
+		if err != nil {
+			tx.Rollback()
+			return metering.Record{}, metering.Record{}, err
+		}
+
+		cnt, err := res.RowsAffected()
+		if err != nil {
+			tx.Rollback()
+			return metering.Record{}, metering.Record{}, err
+		}
+		delta.ActiveDevices += uint64(cnt)
+	}
+
+	rq := `INSERT INTO records (owner, month, messages_in, messages_out, storage_bytes, active_devices)
+	       VALUES ($1, $2, $3, $4, $5, $6)
+	       ON CONFLICT (owner, month) DO UPDATE SET
+	       messages_in = records.messages_in + EXCLUDED.messages_in,
+	       messages_out = records.messages_out + EXCLUDED.messages_out,
+	       storage_bytes = records.storage_bytes + EXCLUDED.storage_bytes,
+	       active_devices = records.active_devices + EXCLUDED.active_devices
+	       RETURNING owner, month, messages_in, messages_out, storage_bytes, active_devices`
+
+	dbr := dbRecord{}
+	row := tx.QueryRowx(rq, delta.Owner, delta.Month, delta.MessagesIn, delta.MessagesOut, delta.StorageBytes, delta.ActiveDevices)
+	if err := row.StructScan(&dbr); err != nil {
+		tx.Rollback()
+		return metering.Record{}, metering.Record{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return metering.Record{}, metering.Record{}, err
+	}
+
+	cur := toRecord(dbr)
+	prev := metering.Record{
+		Owner:         cur.Owner,
+		Month:         cur.Month,
+		MessagesIn:    cur.MessagesIn - delta.MessagesIn,
+		MessagesOut:   cur.MessagesOut - delta.MessagesOut,
+		StorageBytes:  cur.StorageBytes - delta.StorageBytes,
+		ActiveDevices: cur.ActiveDevices - delta.ActiveDevices,
+	}
+
+	return prev, cur, nil
+}
+
+func (rr recordRepository) RetrieveAll(owner string, from, to time.Time) ([]metering.Record, error) {
+	q := `SELECT owner, month, messages_in, messages_out, storage_bytes, active_devices FROM records
+	      WHERE owner = $1 AND month >= $2 AND month <= $3 ORDER BY month`
+
+	return rr.retrieve(q, owner, from, to)
+}
+
+func (rr recordRepository) RetrieveMonth(month time.Time) ([]metering.Record, error) {
+	q := `SELECT owner, month, messages_in, messages_out, storage_bytes, active_devices FROM records
+	      WHERE month = $1 ORDER BY owner`
+
+	return rr.retrieve(q, month)
+}
+
+func (rr recordRepository) retrieve(q string, args ...interface{}) ([]metering.Record, error) {
+	rows, err := rr.db.Queryx(q, args...)
+	if err != nil {
+		return []metering.Record{}, err
+	}
+	defer rows.Close()
+
+	recs := []metering.Record{}
+	for rows.Next() {
+		dbr := dbRecord{}
+		if err := rows.StructScan(&dbr); err != nil {
+			return []metering.Record{}, err
+		}
+		recs = append(recs, toRecord(dbr))
+	}
+
+	return recs, nil
+}
+
+type dbRecord struct {
+	Owner         string    `db:"owner"`
+	Month         time.Time `db:"month"`
+	MessagesIn    uint64    `db:"messages_in"`
+	MessagesOut   uint64    `db:"messages_out"`
+	StorageBytes  uint64    `db:"storage_bytes"`
+	ActiveDevices uint64    `db:"active_devices"`
+}
+
+func toRecord(dbr dbRecord) metering.Record {
+	return metering.Record{
+		Owner:         dbr.Owner,
+		Month:         metering.Month(dbr.Month),
+		MessagesIn:    dbr.MessagesIn,
+		MessagesOut:   dbr.MessagesOut,
+		StorageBytes:  dbr.StorageBytes,
+		ActiveDevices: dbr.ActiveDevices,
+	}
+}
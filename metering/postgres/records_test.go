@@ -0,0 +1,90 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package postgres_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/metering"
+	"github.com/mainflux/mainflux/metering/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const owner = "user@example.com"
+
+var (
+	may  = time.Date(2019, time.May, 1, 0, 0, 0, 0, time.UTC)
+	june = time.Date(2019, time.June, 1, 0, 0, 0, 0, time.UTC)
+)
+
+func TestRecordAdd(t *testing.T) {
+	repo := postgres.NewRecordRepository(db)
+	delta := metering.Record{
+		Owner:        owner,
+		Month:        may,
+		MessagesIn:   2,
+		MessagesOut:  1,
+		StorageBytes: 10,
+	}
+
+	cases := []struct {
+		desc    string
+		devices []string
+		prev    metering.Record
+		cur     metering.Record
+	}{
+		{
+			desc:    "add usage to new record",
+			devices: []string{"1", "2"},
+			prev:    metering.Record{Owner: owner, Month: may},
+			cur:     metering.Record{Owner: owner, Month: may, MessagesIn: 2, MessagesOut: 1, StorageBytes: 10, ActiveDevices: 2},
+		},
+		{
+			desc:    "add usage to existing record",
+			devices: []string{"2", "3"},
+			prev:    metering.Record{Owner: owner, Month: may, MessagesIn: 2, MessagesOut: 1, StorageBytes: 10, ActiveDevices: 2},
+			cur:     metering.Record{Owner: owner, Month: may, MessagesIn: 4, MessagesOut: 2, StorageBytes: 20, ActiveDevices: 3},
+		},
+	}
+
+	for _, tc := range cases {
+		prev, cur, err := repo.Add(delta, tc.devices)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		assert.Equal(t, tc.prev, prev, fmt.Sprintf("%s: expected previous %v got %v", tc.desc, tc.prev, prev))
+		assert.Equal(t, tc.cur, cur, fmt.Sprintf("%s: expected current %v got %v", tc.desc, tc.cur, cur))
+	}
+}
+
+func TestRecordRetrieve(t *testing.T) {
+	repo := postgres.NewRecordRepository(db)
+	other := "other@example.com"
+
+	for _, rec := range []metering.Record{
+		{Owner: other, Month: may, MessagesIn: 1},
+		{Owner: other, Month: june, MessagesIn: 1},
+		{Owner: owner, Month: june, MessagesIn: 1},
+	} {
+		_, _, err := repo.Add(rec, []string{})
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	recs, err := repo.RetrieveAll(other, may, june)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Len(t, recs, 2, fmt.Sprintf("expected 2 records got %d", len(recs)))
+
+	recs, err = repo.RetrieveAll(other, june, june)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Len(t, recs, 1, fmt.Sprintf("expected 1 record got %d", len(recs)))
+
+	recs, err = repo.RetrieveMonth(june)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Len(t, recs, 2, fmt.Sprintf("expected 2 records got %d", len(recs)))
+}
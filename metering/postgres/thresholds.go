@@ -0,0 +1,113 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package postgres
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/mainflux/mainflux/metering"
+)
+
+const (
+	duplicateErr = "unique_violation"
+	uuidErr      = "invalid input syntax for type uuid"
+)
+
+var _ metering.ThresholdRepository = (*thresholdRepository)(nil)
+
+type thresholdRepository struct {
+	db *sqlx.DB
+}
+
+// NewThresholdRepository instantiates a PostgreSQL implementation of
+// threshold repository.
+func NewThresholdRepository(db *sqlx.DB) metering.ThresholdRepository {
+	return &thresholdRepository{db: db}
+}
+
+func (tr thresholdRepository) Save(th metering.Threshold) (string, error) {
+	q := `INSERT INTO thresholds (id, owner, metric, lim, url) VALUES (:id, :owner, :metric, :lim, :url)`
+
+	if _, err := tr.db.NamedExec(q, toDBThreshold(th)); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == duplicateErr {
+			return "", metering.ErrMalformedEntity
+		}
+		return "", err
+	}
+
+	return th.ID, nil
+}
+
+func (tr thresholdRepository) RetrieveAll(owner string) ([]metering.Threshold, error) {
+	q := `SELECT id, owner, metric, lim, url FROM thresholds WHERE owner = $1 ORDER BY id`
+
+	rows, err := tr.db.Queryx(q, owner)
+	if err != nil {
+		return []metering.Threshold{}, err
+	}
+	defer rows.Close()
+
+	ths := []metering.Threshold{}
+	for rows.Next() {
+		dbt := dbThreshold{}
+		if err := rows.StructScan(&dbt); err != nil {
+			return []metering.Threshold{}, err
+		}
+		ths = append(ths, toThreshold(dbt))
+	}
+
+	return ths, nil
+}
+
+func (tr thresholdRepository) Remove(owner, id string) error {
+	q := `DELETE FROM thresholds WHERE id = $1 AND owner = $2`
+
+	if _, err := tr.db.Exec(q, id, owner); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Message == uuidErr {
+			return nil
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (tr thresholdRepository) RemoveAll(owner string) error {
+	q := `DELETE FROM thresholds WHERE owner = $1`
+
+	_, err := tr.db.Exec(q, owner)
+	return err
+}
+
+type dbThreshold struct {
+	ID     string `db:"id"`
+	Owner  string `db:"owner"`
+	Metric string `db:"metric"`
+	Limit  uint64 `db:"lim"`
+	URL    string `db:"url"`
+}
+
+func toDBThreshold(th metering.Threshold) dbThreshold {
+	return dbThreshold{
+		ID:     th.ID,
+		Owner:  th.Owner,
+		Metric: th.Metric,
+		Limit:  th.Limit,
+		URL:    th.URL,
+	}
+}
+
+func toThreshold(dbt dbThreshold) metering.Threshold {
+	return metering.Threshold{
+		ID:     dbt.ID,
+		Owner:  dbt.Owner,
+		Metric: dbt.Metric,
+		Limit:  dbt.Limit,
+		URL:    dbt.URL,
+	}
+}
@@ -0,0 +1,76 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package redis
+
+import (
+	"github.com/go-redis/redis"
+	"github.com/mainflux/mainflux/events"
+	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/metering"
+)
+
+const (
+	stream = "mainflux.things"
+	group  = "mainflux.metering"
+
+	thingPrefix   = "thing."
+	thingCreate   = thingPrefix + "create"
+	thingRemove   = thingPrefix + "remove"
+	channelPrefix = "channel."
+	channelCreate = channelPrefix + "create"
+	channelRemove = channelPrefix + "remove"
+)
+
+// EventStore represents event source for things and channels provisioning.
+type EventStore interface {
+	// Subscribes to given subject and receives events.
+	Subscribe(string) error
+}
+
+type eventStore struct {
+	svc      metering.Service
+	client   redis.UniversalClient
+	consumer string
+	logger   logger.Logger
+}
+
+// NewEventStore returns new event store instance.
+func NewEventStore(svc metering.Service, client redis.UniversalClient, consumer string, log logger.Logger) EventStore {
+	return eventStore{
+		svc:      svc,
+		client:   client,
+		consumer: consumer,
+		logger:   log,
+	}
+}
+
+// Subscribe keeps track of the owners of things and channels. The consumer
+// group is created at the beginning of the stream, so that the entities
+// provisioned before the service was deployed are metered too.
+func (es eventStore) Subscribe(subject string) error {
+	sub := events.NewSubscriber(es.client, events.Config{
+		Stream:   stream,
+		Group:    group,
+		Consumer: es.consumer,
+		Start:    events.Beginning,
+	}, es.logger)
+
+	return sub.Subscribe(events.HandlerFunc(es.handle))
+}
+
+func (es eventStore) handle(event events.Event) error {
+	var err error
+	switch event.Read("operation", "") {
+	case thingCreate, channelCreate:
+		err = es.svc.SaveEntityHandler(event.Read("id", ""), event.Read("owner", ""))
+	case thingRemove, channelRemove:
+		err = es.svc.RemoveEntityHandler(event.Read("id", ""))
+	}
+
+	return err
+}
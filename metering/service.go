@@ -0,0 +1,386 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package metering
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux"
+)
+
+var (
+	// ErrMalformedEntity indicates malformed entity specification (e.g.
+	// invalid threshold metric or month range).
+	ErrMalformedEntity = errors.New("malformed entity specification")
+
+	// ErrUnauthorizedAccess indicates missing or invalid credentials provided
+	// when accessing a protected resource.
+	ErrUnauthorizedAccess = errors.New("missing or invalid credentials provided")
+
+	// ErrNotFound indicates a non-existent entity request.
+	ErrNotFound = errors.New("non-existent entity")
+)
+
+// Service specifies an API that must be fullfiled by the domain service
+// implementation, and all of its decorators (e.g. logging & metrics).
+type Service interface {
+	// RecordUsage meters the published message. Usage is buffered until
+	// the next flush. Messages of unknown things and channels are not
+	// metered.
+	RecordUsage(Usage) error
+
+	// Flush adds the buffered usage to the monthly records and alerts the
+	// owners whose thresholds were reached. Usage that failed to be saved
+	// is kept for the next flush.
+	Flush() error
+
+	// ListRecords retrieves the monthly records of the user identified by
+	// the provided key, for the months between the provided ones.
+	ListRecords(string, time.Time, time.Time) ([]Record, error)
+
+	// ExportRecords retrieves the records of all the owners for the
+	// provided month. Export is authorized by the operator export key.
+	ExportRecords(string, time.Time) ([]Record, error)
+
+	// AddThreshold adds the threshold to the usage of the user identified
+	// by the provided key.
+	AddThreshold(string, Threshold) (Threshold, error)
+
+	// ListThresholds retrieves all the thresholds of the user identified by
+	// the provided key.
+	ListThresholds(string) ([]Threshold, error)
+
+	// RemoveThreshold removes the threshold identified with the provided
+	// ID, that belongs to the user identified by the provided key.
+	RemoveThreshold(string, string) error
+
+	// SaveEntityHandler keeps track of the owner of the created thing or
+	// channel.
+	SaveEntityHandler(string, string) error
+
+	// RemoveEntityHandler stops metering the removed thing or channel.
+	RemoveEntityHandler(string) error
+
+	// RemoveUserHandler removes the thresholds and stops metering the
+	// entities of the removed user. Monthly records are kept, since they
+	// are needed for billing after the user is gone.
+	RemoveUserHandler(string) error
+}
+
+var _ Service = (*meteringService)(nil)
+
+type pendingKey struct {
+	owner string
+	month time.Time
+}
+
+type pending struct {
+	delta   Record
+	devices map[string]bool
+}
+
+type meteringService struct {
+	users      mainflux.UsersServiceClient
+	records    RecordRepository
+	entities   EntityRepository
+	thresholds ThresholdRepository
+	idp        IdentityProvider
+	sender     Sender
+	exportKey  string
+
+	mu      sync.Mutex
+	pending map[pendingKey]*pending
+
+	ownersMu sync.RWMutex
+	owners   map[string]string
+}
+
+// New instantiates the metering service implementation. Records are
+// exported only if the export key is set.
+func New(users mainflux.UsersServiceClient, records RecordRepository, entities EntityRepository, thresholds ThresholdRepository, idp IdentityProvider, sender Sender, exportKey string) Service {
+	return &meteringService{
+		users:      users,
+		records:    records,
+		entities:   entities,
+		thresholds: thresholds,
+		idp:        idp,
+		sender:     sender,
+		exportKey:  exportKey,
+		pending:    make(map[pendingKey]*pending),
+		owners:     make(map[string]string),
+	}
+}
+
+func (ms *meteringService) RecordUsage(u Usage) error {
+	publisher, err := ms.owner(u.Publisher)
+	if err != nil {
+		return err
+	}
+
+	channel, err := ms.owner(u.Channel)
+	if err != nil {
+		return err
+	}
+
+	month := Month(u.Occurred)
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if publisher != "" {
+		p := ms.buffer(publisher, month)
+		p.delta.MessagesIn++
+		p.devices[u.Publisher] = true
+	}
+
+	if channel != "" {
+		p := ms.buffer(channel, month)
+		p.delta.MessagesOut++
+		p.delta.StorageBytes += u.Size
+	}
+
+	return nil
+}
+
+func (ms *meteringService) Flush() error {
+	ms.mu.Lock()
+	buffered := ms.pending
+	ms.pending = make(map[pendingKey]*pending)
+	ms.mu.Unlock()
+
+	var lastErr error
+	for key, p := range buffered {
+		devices := []string{}
+		for id := range p.devices {
+			devices = append(devices, id)
+		}
+
+		prev, cur, err := ms.records.Add(p.delta, devices)
+		if err != nil {
+			ms.restore(key, p)
+			lastErr = err
+			continue
+		}
+
+		if err := ms.alert(prev, cur); err != nil {
+			lastErr = err
+		}
+	}
+
+	return lastErr
+}
+
+func (ms *meteringService) ListRecords(key string, from, to time.Time) ([]Record, error) {
+	from, to = Month(from), Month(to)
+	if from.After(to) {
+		return []Record{}, ErrMalformedEntity
+	}
+
+	owner, err := ms.identify(key)
+	if err != nil {
+		return []Record{}, err
+	}
+
+	return ms.records.RetrieveAll(owner, from, to)
+}
+
+func (ms *meteringService) ExportRecords(key string, month time.Time) ([]Record, error) {
+	if ms.exportKey == "" || subtle.ConstantTimeCompare([]byte(key), []byte(ms.exportKey)) != 1 {
+		return []Record{}, ErrUnauthorizedAccess
+	}
+
+	return ms.records.RetrieveMonth(Month(month))
+}
+
+func (ms *meteringService) AddThreshold(key string, th Threshold) (Threshold, error) {
+	if err := th.Validate(); err != nil {
+		return Threshold{}, err
+	}
+
+	owner, err := ms.identify(key)
+	if err != nil {
+		return Threshold{}, err
+	}
+
+	th.ID, err = ms.idp.ID()
+	if err != nil {
+		return Threshold{}, err
+	}
+	th.Owner = owner
+
+	id, err := ms.thresholds.Save(th)
+	if err != nil {
+		return Threshold{}, err
+	}
+
+	th.ID = id
+	return th, nil
+}
+
+func (ms *meteringService) ListThresholds(key string) ([]Threshold, error) {
+	owner, err := ms.identify(key)
+	if err != nil {
+		return []Threshold{}, err
+	}
+
+	return ms.thresholds.RetrieveAll(owner)
+}
+
+func (ms *meteringService) RemoveThreshold(key, id string) error {
+	owner, err := ms.identify(key)
+	if err != nil {
+		return err
+	}
+
+	return ms.thresholds.Remove(owner, id)
+}
+
+func (ms *meteringService) SaveEntityHandler(id, owner string) error {
+	if id == "" || owner == "" {
+		return ErrMalformedEntity
+	}
+
+	if err := ms.entities.Save(id, owner); err != nil {
+		return err
+	}
+
+	ms.ownersMu.Lock()
+	ms.owners[id] = owner
+	ms.ownersMu.Unlock()
+
+	return nil
+}
+
+func (ms *meteringService) RemoveEntityHandler(id string) error {
+	if err := ms.entities.Remove(id); err != nil {
+		return err
+	}
+
+	ms.ownersMu.Lock()
+	delete(ms.owners, id)
+	ms.ownersMu.Unlock()
+
+	return nil
+}
+
+func (ms *meteringService) RemoveUserHandler(owner string) error {
+	if err := ms.thresholds.RemoveAll(owner); err != nil {
+		return err
+	}
+
+	if err := ms.entities.RemoveAll(owner); err != nil {
+		return err
+	}
+
+	ms.ownersMu.Lock()
+	for id, o := range ms.owners {
+		if o == owner {
+			delete(ms.owners, id)
+		}
+	}
+	ms.ownersMu.Unlock()
+
+	return nil
+}
+
+// owner returns the owner of the thing or channel, or an empty string if
+// the entity is unknown. Owners are cached, since they are looked up for
+// every metered message.
+func (ms *meteringService) owner(id string) (string, error) {
+	if id == "" {
+		return "", nil
+	}
+
+	ms.ownersMu.RLock()
+	owner, ok := ms.owners[id]
+	ms.ownersMu.RUnlock()
+	if ok {
+		return owner, nil
+	}
+
+	owner, err := ms.entities.RetrieveOwner(id)
+	if err == ErrNotFound {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	ms.ownersMu.Lock()
+	ms.owners[id] = owner
+	ms.ownersMu.Unlock()
+
+	return owner, nil
+}
+
+func (ms *meteringService) buffer(owner string, month time.Time) *pending {
+	key := pendingKey{owner: owner, month: month}
+	p, ok := ms.pending[key]
+	if !ok {
+		p = &pending{
+			delta:   Record{Owner: owner, Month: month},
+			devices: make(map[string]bool),
+		}
+		ms.pending[key] = p
+	}
+
+	return p
+}
+
+func (ms *meteringService) restore(key pendingKey, p *pending) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	b := ms.buffer(key.owner, key.month)
+	b.delta.add(p.delta)
+	for id := range p.devices {
+		b.devices[id] = true
+	}
+}
+
+// alert notifies the owner about the thresholds crossed by the record
+// update. Since records only grow during the month, every threshold is
+// alerted at most once per month. Delivery to the remaining thresholds is
+// attempted even if some of them fail, in which case the last error is
+// returned.
+func (ms *meteringService) alert(prev, cur Record) error {
+	ths, err := ms.thresholds.RetrieveAll(cur.Owner)
+	if err != nil {
+		return err
+	}
+
+	var lastErr error
+	for _, th := range ths {
+		val := cur.Value(th.Metric)
+		if prev.Value(th.Metric) >= th.Limit || val < th.Limit {
+			continue
+		}
+
+		if err := ms.sender.Send(Alert{Threshold: th, Month: cur.Month, Value: val}); err != nil {
+			lastErr = err
+		}
+	}
+
+	return lastErr
+}
+
+func (ms *meteringService) identify(key string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	res, err := ms.users.Identify(ctx, &mainflux.Token{Value: key})
+	if err != nil {
+		return "", ErrUnauthorizedAccess
+	}
+
+	return res.GetValue(), nil
+}
@@ -0,0 +1,327 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package metering_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/metering"
+	"github.com/mainflux/mainflux/metering/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	validToken   = "validToken"
+	invalidToken = "invalidToken"
+	exportKey    = "exportKey"
+	email        = "user@example.com"
+	otherEmail   = "other@example.com"
+	hook         = "https://example.com/hook"
+	thingID      = "thing"
+	otherThingID = "other-thing"
+	chanID       = "channel"
+)
+
+var (
+	now   = time.Date(2019, time.June, 15, 12, 0, 0, 0, time.UTC)
+	month = metering.Month(now)
+
+	threshold = metering.Threshold{
+		Metric: metering.MessagesIn,
+		Limit:  2,
+		URL:    hook,
+	}
+)
+
+func newService(sender metering.Sender) metering.Service {
+	users := mocks.NewUsersService(map[string]string{validToken: email})
+	records := mocks.NewRecordRepository()
+	entities := mocks.NewEntityRepository()
+	thresholds := mocks.NewThresholdRepository()
+	idp := mocks.NewIdentityProvider()
+
+	return metering.New(users, records, entities, thresholds, idp, sender, exportKey)
+}
+
+func provision(t *testing.T, svc metering.Service) {
+	for id, owner := range map[string]string{thingID: email, otherThingID: otherEmail, chanID: email} {
+		err := svc.SaveEntityHandler(id, owner)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+}
+
+func publish(t *testing.T, svc metering.Service, publisher string, n int) {
+	for i := 0; i < n; i++ {
+		err := svc.RecordUsage(metering.Usage{Publisher: publisher, Channel: chanID, Size: 10, Occurred: now})
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+}
+
+func TestRecordUsage(t *testing.T) {
+	svc := newService(mocks.NewSender(nil))
+	provision(t, svc)
+
+	publish(t, svc, thingID, 2)
+	publish(t, svc, otherThingID, 1)
+	publish(t, svc, "unknown", 1)
+	err := svc.Flush()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	publish(t, svc, thingID, 1)
+	err = svc.Flush()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc string
+		key  string
+		rec  metering.Record
+	}{
+		{
+			desc: "record usage of channel owner",
+			key:  email,
+			rec: metering.Record{
+				Owner:         email,
+				Month:         month,
+				MessagesIn:    3,
+				MessagesOut:   5,
+				StorageBytes:  50,
+				ActiveDevices: 1,
+			},
+		},
+		{
+			desc: "record usage of publisher owner",
+			key:  otherEmail,
+			rec: metering.Record{
+				Owner:         otherEmail,
+				Month:         month,
+				MessagesIn:    1,
+				ActiveDevices: 1,
+			},
+		},
+	}
+
+	recs, err := svc.ExportRecords(exportKey, now)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	require.Len(t, recs, len(cases), fmt.Sprintf("expected %d records got %d", len(cases), len(recs)))
+
+	for _, tc := range cases {
+		var rec metering.Record
+		for _, r := range recs {
+			if r.Owner == tc.key {
+				rec = r
+			}
+		}
+		assert.Equal(t, tc.rec, rec, fmt.Sprintf("%s: expected %v got %v", tc.desc, tc.rec, rec))
+	}
+}
+
+func TestFlushAlerts(t *testing.T) {
+	sender := mocks.NewSender(nil)
+	svc := newService(sender)
+	provision(t, svc)
+
+	_, err := svc.AddThreshold(validToken, threshold)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc     string
+		messages int
+		alerts   int
+	}{
+		{
+			desc:     "flush usage below threshold",
+			messages: 1,
+			alerts:   0,
+		},
+		{
+			desc:     "flush usage reaching threshold",
+			messages: 1,
+			alerts:   1,
+		},
+		{
+			desc:     "flush usage above reached threshold",
+			messages: 1,
+			alerts:   1,
+		},
+	}
+
+	for _, tc := range cases {
+		publish(t, svc, thingID, tc.messages)
+		err := svc.Flush()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		alerts := sender.Sent(hook)
+		assert.Len(t, alerts, tc.alerts, fmt.Sprintf("%s: expected %d alerts got %d", tc.desc, tc.alerts, len(alerts)))
+	}
+
+	alert := sender.Sent(hook)[0]
+	assert.Equal(t, uint64(2), alert.Value, fmt.Sprintf("expected alerted value 2 got %d", alert.Value))
+	assert.Equal(t, month, alert.Month, fmt.Sprintf("expected alerted month %s got %s", month, alert.Month))
+}
+
+func TestListRecords(t *testing.T) {
+	svc := newService(mocks.NewSender(nil))
+	provision(t, svc)
+
+	publish(t, svc, thingID, 1)
+	err := svc.Flush()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		token string
+		from  time.Time
+		to    time.Time
+		size  int
+		err   error
+	}{
+		{
+			desc:  "list records of the month",
+			token: validToken,
+			from:  now,
+			to:    now,
+			size:  1,
+			err:   nil,
+		},
+		{
+			desc:  "list records before the month",
+			token: validToken,
+			from:  now.AddDate(0, -3, 0),
+			to:    now.AddDate(0, -1, 0),
+			size:  0,
+			err:   nil,
+		},
+		{
+			desc:  "list records with invalid range",
+			token: validToken,
+			from:  now,
+			to:    now.AddDate(0, -1, 0),
+			size:  0,
+			err:   metering.ErrMalformedEntity,
+		},
+		{
+			desc:  "list records with invalid token",
+			token: invalidToken,
+			from:  now,
+			to:    now,
+			size:  0,
+			err:   metering.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		recs, err := svc.ListRecords(tc.token, tc.from, tc.to)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+		assert.Len(t, recs, tc.size, fmt.Sprintf("%s: expected %d records got %d", tc.desc, tc.size, len(recs)))
+	}
+}
+
+func TestExportRecords(t *testing.T) {
+	cases := []struct {
+		desc string
+		svc  metering.Service
+		key  string
+		err  error
+	}{
+		{
+			desc: "export records with valid key",
+			svc:  newService(mocks.NewSender(nil)),
+			key:  exportKey,
+			err:  nil,
+		},
+		{
+			desc: "export records with invalid key",
+			svc:  newService(mocks.NewSender(nil)),
+			key:  validToken,
+			err:  metering.ErrUnauthorizedAccess,
+		},
+		{
+			desc: "export records with export disabled",
+			svc:  metering.New(nil, mocks.NewRecordRepository(), nil, nil, nil, nil, ""),
+			key:  "",
+			err:  metering.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := tc.svc.ExportRecords(tc.key, now)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+	}
+}
+
+func TestAddThreshold(t *testing.T) {
+	svc := newService(mocks.NewSender(nil))
+
+	unknown := threshold
+	unknown.Metric = "unknown"
+
+	invalidURL := threshold
+	invalidURL.URL = "invalid"
+
+	cases := []struct {
+		desc  string
+		token string
+		th    metering.Threshold
+		err   error
+	}{
+		{
+			desc:  "add threshold with valid token",
+			token: validToken,
+			th:    threshold,
+			err:   nil,
+		},
+		{
+			desc:  "add threshold with invalid token",
+			token: invalidToken,
+			th:    threshold,
+			err:   metering.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "add threshold of unknown metric",
+			token: validToken,
+			th:    unknown,
+			err:   metering.ErrMalformedEntity,
+		},
+		{
+			desc:  "add threshold with invalid URL",
+			token: validToken,
+			th:    invalidURL,
+			err:   metering.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := svc.AddThreshold(tc.token, tc.th)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+	}
+}
+
+func TestRemoveUserHandler(t *testing.T) {
+	svc := newService(mocks.NewSender(nil))
+	provision(t, svc)
+
+	_, err := svc.AddThreshold(validToken, threshold)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	err = svc.RemoveUserHandler(email)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	ths, err := svc.ListThresholds(validToken)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Empty(t, ths, "expected thresholds of removed user to be removed")
+
+	publish(t, svc, thingID, 1)
+	err = svc.Flush()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	recs, err := svc.ExportRecords(exportKey, now)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Empty(t, recs, "expected entities of removed user not to be metered")
+}
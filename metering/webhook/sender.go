@@ -0,0 +1,78 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// Package webhook contains the alert sender that delivers the reached
+// thresholds by invoking the threshold URLs.
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/mainflux/mainflux/metering"
+)
+
+const (
+	contentType = "application/json"
+	monthFormat = "2006-01"
+)
+
+// ErrDeliveryFailed indicates that the webhook responded with a non-2xx
+// status code.
+var ErrDeliveryFailed = errors.New("webhook responded with unexpected status")
+
+var _ metering.Sender = (*sender)(nil)
+
+type sender struct {
+	client *http.Client
+}
+
+type payload struct {
+	Threshold string `json:"threshold"`
+	Owner     string `json:"owner"`
+	Metric    string `json:"metric"`
+	Limit     uint64 `json:"limit"`
+	Value     uint64 `json:"value"`
+	Month     string `json:"month"`
+}
+
+// New instantiates the webhook alert sender. Every alert is POSTed to the
+// threshold URL as JSON document.
+func New(timeout time.Duration) metering.Sender {
+	return &sender{
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (s *sender) Send(alert metering.Alert) error {
+	data, err := json.Marshal(payload{
+		Threshold: alert.Threshold.ID,
+		Owner:     alert.Threshold.Owner,
+		Metric:    alert.Threshold.Metric,
+		Limit:     alert.Threshold.Limit,
+		Value:     alert.Value,
+		Month:     alert.Month.Format(monthFormat),
+	})
+	if err != nil {
+		return err
+	}
+
+	res, err := s.client.Post(alert.Threshold.URL, contentType, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return ErrDeliveryFailed
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package webhook_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/metering"
+	"github.com/mainflux/mainflux/metering/webhook"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSend(t *testing.T) {
+	received := map[string]interface{}{}
+	ok := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ok.Close()
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	th := metering.Threshold{
+		ID:     "1",
+		Owner:  "user@example.com",
+		Metric: metering.MessagesIn,
+		Limit:  10,
+	}
+
+	cases := []struct {
+		desc string
+		url  string
+		err  error
+	}{
+		{
+			desc: "send alert to available webhook",
+			url:  ok.URL,
+			err:  nil,
+		},
+		{
+			desc: "send alert to failing webhook",
+			url:  failing.URL,
+			err:  webhook.ErrDeliveryFailed,
+		},
+	}
+
+	sender := webhook.New(time.Second)
+	for _, tc := range cases {
+		th.URL = tc.url
+		alert := metering.Alert{
+			Threshold: th,
+			Month:     time.Date(2019, time.June, 1, 0, 0, 0, 0, time.UTC),
+			Value:     12,
+		}
+		err := sender.Send(alert)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	expected := map[string]interface{}{
+		"threshold": "1",
+		"owner":     "user@example.com",
+		"metric":    metering.MessagesIn,
+		"limit":     float64(10),
+		"value":     float64(12),
+		"month":     "2019-06",
+	}
+	assert.Equal(t, expected, received, fmt.Sprintf("expected %v got %v", expected, received))
+}
@@ -13,8 +13,10 @@ var http = require('http'),
     grpc = require('grpc'),
     protoLoader = require('@grpc/proto-loader'),
     fs = require('fs'),
+    crypto = require('crypto'),
     bunyan = require('bunyan'),
-    logging = require('aedes-logging');
+    logging = require('aedes-logging'),
+    prometheus = require('prom-client');
 
 // pass a proto file as a buffer/string or pass a parsed protobuf-schema object
 var config = {
@@ -32,10 +34,22 @@ var config = {
         es_host: process.env.MF_MQTT_ADAPTER_ES_HOST || 'localhost',
         es_pass: process.env.MF_MQTT_ADAPTER_ES_PASS || 'mqtt',
         es_db: Number(process.env.MF_MQTT_ADAPTER_ES_DB) || 0,
+        cache_port: Number(process.env.MF_MQTT_ADAPTER_CACHE_PORT) || 6379,
+        cache_host: process.env.MF_MQTT_ADAPTER_CACHE_HOST || '',
+        cache_pass: process.env.MF_MQTT_ADAPTER_CACHE_PASS || '',
+        cache_db: Number(process.env.MF_MQTT_ADAPTER_CACHE_DB) || 0,
+        anonymize: process.env.MF_MQTT_ADAPTER_ANONYMIZE_ADDR === 'true',
+        ack_timeout: Number(process.env.MF_MQTT_ADAPTER_ACK_TIMEOUT) || 5000,
         client_tls: (process.env.MF_MQTT_ADAPTER_CLIENT_TLS == 'true') || false,
     	ca_certs: process.env.MF_MQTT_ADAPTER_CA_CERTS || '',
+        client_cert: process.env.MF_MQTT_ADAPTER_CLIENT_CERT || '',
+        client_key: process.env.MF_MQTT_ADAPTER_CLIENT_KEY || '',
         concurrency: Number(process.env.MF_MQTT_CONCURRENT_MESSAGES) || 100,
+        max_inflight: Number(process.env.MF_MQTT_ADAPTER_MAX_INFLIGHT) || 20,
+        receive_max: Number(process.env.MF_MQTT_ADAPTER_RECEIVE_MAX) || 20,
+        max_queued: Number(process.env.MF_MQTT_ADAPTER_MAX_QUEUED) || 42,
         auth_url: process.env.MF_THINGS_URL || 'localhost:8181',
+        users_url: process.env.MF_USERS_URL || '',
         schema_dir: process.argv[2] || '.',
     },
     logger = bunyan.createLogger({name: 'mqtt', level: config.log_level}),
@@ -72,27 +86,79 @@ var config = {
     aedes = require('aedes')({
         mq: mqRedis,
         persistence: aedesRedis,
-        concurrency: config.concurrency
+        concurrency: config.concurrency,
+        queueLimit: config.max_queued
     }),
-    things = (function() {
-        var certs;
+    certs = (function() {
         if (config.client_tls) {
-            certs = grpc.credentials.createSsl(config.ca_certs);
-        } else {
-            certs = grpc.credentials.createInsecure();
+            var read = function(file) {
+                return file ? fs.readFileSync(file) : null;
+            };
+            return grpc.credentials.createSsl(read(config.ca_certs), read(config.client_key), read(config.client_cert));
         }
-        return new thingsSchema.ThingsService(config.auth_url, certs);
+        return grpc.credentials.createInsecure();
     })(),
+    things = new thingsSchema.ThingsService(config.auth_url, certs),
+    // Users are authenticated only if the users service URL is configured.
+    users = config.users_url ? new thingsSchema.UsersService(config.users_url, certs) : null,
     esclient = redis.createClient({
         port: config.es_port, 
         host: config.es_host,
         password: config.es_pass,
         db: config.es_db
     }),
+    // Channel rate limits and payload transformations are applied only if the
+    // things cache is configured.
+    cacheclient = config.cache_host ? redis.createClient({
+        port: config.cache_port,
+        host: config.cache_host,
+        password: config.cache_pass,
+        db: config.cache_db
+    }) : null,
+    // Same token bucket as the one of the Go adapters, see publish/ratelimit.go.
+    takeTokenScript = [
+        "local limit = redis.call('HMGET', KEYS[1], 'rate', 'burst', 'tokens', 'last')",
+        "local rate = tonumber(limit[1])",
+        "if not rate then return 1 end",
+        "local burst = math.max(tonumber(limit[2]) or 1, 1)",
+        "local now = tonumber(ARGV[1])",
+        "local tokens = tonumber(limit[3]) or burst",
+        "local last = tonumber(limit[4]) or now",
+        "tokens = math.min(burst, tokens + math.max(now - last, 0) * rate)",
+        "local taken = 0",
+        "if tokens >= 1 then tokens = tokens - 1; taken = 1 end",
+        "redis.call('HMSET', KEYS[1], 'tokens', tokens, 'last', now)",
+        "return taken"
+    ].join('\n'),
     servers = [
         startMqtt(),
         startWs()
-    ];
+    ],
+    // Number of unacknowledged QoS 1 and 2 messages per client, delivered
+    // to the client (outgoing) and received from the client (incoming).
+    inflight = {},
+    metrics = {
+        clients: new prometheus.Gauge({
+            name: 'mqtt_adapter_connected_clients',
+            help: 'Number of connected clients.'
+        }),
+        inflight: new prometheus.Gauge({
+            name: 'mqtt_adapter_inflight_messages',
+            help: 'Number of unacknowledged QoS 1 and 2 messages delivered to clients.'
+        }),
+        downgraded: new prometheus.Counter({
+            name: 'mqtt_adapter_downgraded_messages_total',
+            help: 'Number of messages delivered with QoS 0 due to the full inflight window.'
+        }),
+        rejected: new prometheus.Counter({
+            name: 'mqtt_adapter_rejected_messages_total',
+            help: 'Number of messages rejected due to the exceeded receive maximum.'
+        }),
+        throttled: new prometheus.Counter({
+            name: 'mqtt_adapter_throttled_messages_total',
+            help: 'Number of messages rejected due to the exceeded channel rate limit.'
+        })
+    };
 
 logging({
     instance: aedes,
@@ -102,13 +168,28 @@ logging({
 
 logger.level(config.log_level);
 
+if (cacheclient) {
+    cacheclient.on('error', function(err) {
+        logger.warn('error on cache connection: %s', err.message);
+    });
+}
+
 esclient.on('error', function(err) {
     logger.warn('error on redis connection: %s', err.message);
 });
 
-// MQTT over WebSocket
+// MQTT over WebSocket; metrics are exposed on the same port.
 function startWs() {
-    var server = http.createServer();
+    var server = http.createServer(function (req, res) {
+        if (req.url !== '/metrics') {
+            res.writeHead(404);
+            res.end();
+            return;
+        }
+
+        res.writeHead(200, {'Content-Type': prometheus.register.contentType});
+        res.end(prometheus.register.metrics());
+    });
     websocket.createServer({server: server}, aedes.handle);
     server.listen(config.ws_port);
     return server;
@@ -136,24 +217,265 @@ nats.subscribe('channel.>', {'queue':'mqtts'}, function (msg) {
     }
 });
 
+function clientInflight(client) {
+    if (!inflight[client.id]) {
+        inflight[client.id] = {outgoing: 0, incoming: 0};
+    }
+    return inflight[client.id];
+}
+
+// Takes a token from the channel's bucket. Messages are let through if the
+// cache is unavailable.
+function takeToken(channelId, done) {
+    if (!cacheclient) {
+        done(true);
+        return;
+    }
+    cacheclient.eval(takeTokenScript, 1, 'ratelimit:' + channelId, Date.now() / 1000, function (err, taken) {
+        if (err) {
+            logger.warn('failed to check channel rate limit: %s', err.message);
+            done(true);
+            return;
+        }
+        done(taken === 1);
+    });
+}
+
+// Checks whether the channel can be published to over MQTT, according to the
+// protocols the things service caches, see publish/protocols.go. Messages are
+// let through if the channel has no protocols cached or the cache is
+// unavailable.
+function allowedProtocol(channelId, done) {
+    if (!cacheclient) {
+        done(true);
+        return;
+    }
+    cacheclient.get('protocols:' + channelId, function (err, protocols) {
+        if (err) {
+            logger.warn('failed to check channel protocols: %s', err.message);
+            done(true);
+            return;
+        }
+        done(!protocols || protocols.split(',').indexOf('mqtt') !== -1);
+    });
+}
+
+// Takes the next sequence number of the channel, shared with the Go adapters,
+// see publish/sequence.go. Messages are numbered 0, i.e. unknown, if the cache
+// is unavailable.
+function nextSeq(channelId, done) {
+    if (!cacheclient) {
+        done(0);
+        return;
+    }
+    cacheclient.incr('seq:' + channelId, function (err, seq) {
+        if (err) {
+            logger.warn('failed to number message: %s', err.message);
+            done(0);
+            return;
+        }
+        done(seq);
+    });
+}
+
+// Retrieves the delivery mode of the channel, shared with the Go adapters,
+// see publish/delivery.go. Messages are left without the mode if the cache is
+// unavailable.
+function deliveryMode(channelId, done) {
+    if (!cacheclient) {
+        done('');
+        return;
+    }
+    cacheclient.get('delivery:' + channelId, function (err, mode) {
+        if (err) {
+            logger.warn('failed to retrieve delivery mode: %s', err.message);
+            done('');
+            return;
+        }
+        done(mode || '');
+    });
+}
+
+// Publishes the message the way its delivery mode requires. Messages delivered
+// at least once are published as requests, and acknowledged to the client only
+// once the normalizer replies, so that the client sends them again otherwise.
+function deliver(subject, rawMsg, delivery, done) {
+    if (delivery !== 'at-least-once') {
+        nats.publish(subject, rawMsg);
+        done(0);
+        return;
+    }
+    nats.requestOne(subject, rawMsg, {}, config.ack_timeout, function (reply) {
+        if (reply instanceof Error) {
+            logger.warn('message not acknowledged: %s', reply.message);
+            done(new Error('message not acknowledged'));
+            return;
+        }
+        done(0);
+    });
+}
+
+// Transforms the payload using the template of the thing or, if the thing
+// has none, of the channel, the same way the Go adapters do, see
+// things/transform.go. Unlike the rate limits, transformations can't be
+// skipped, so messages are rejected if the cache is unavailable.
+function transform(thingId, channelId, payload, done) {
+    if (!cacheclient) {
+        done(null, {payload: payload, contentType: ''});
+        return;
+    }
+    cacheclient.mget('thing_transform:' + thingId, 'transform:' + channelId, function (err, vals) {
+        var tr;
+        if (err) {
+            done(err);
+            return;
+        }
+        if (!vals[0] && !vals[1]) {
+            done(null, {payload: payload, contentType: ''});
+            return;
+        }
+        try {
+            tr = JSON.parse(vals[0] || vals[1]);
+            done(null, {
+                payload: Buffer.from(applyTemplate(tr.template, JSON.parse(payload.toString()))),
+                contentType: tr.contentType || ''
+            });
+        } catch (e) {
+            done(e);
+        }
+    });
+}
+
+// Replaces each {{path}} placeholder of the template with the JSON encoding
+// of the value the dot separated path selects from the document.
+function applyTemplate(template, doc) {
+    var out = '',
+        start, end, val;
+    while (template) {
+        start = template.indexOf('{{');
+        if (start < 0) {
+            out += template;
+            break;
+        }
+        out += template.substr(0, start);
+        template = template.substr(start + 2);
+        end = template.indexOf('}}');
+        if (end < 0) {
+            throw new Error('unterminated placeholder');
+        }
+        val = template.substr(0, end).trim().split('.').filter(function (elem, i, path) {
+            return path.length > 1 || elem !== '';
+        }).reduce(function (val, elem) {
+            if (val === null || typeof val !== 'object' || !val.hasOwnProperty(elem) ||
+                Array.isArray(val) && !/^\d+$/.test(elem)) {
+                throw new Error('missing value: ' + elem);
+            }
+            return val[elem];
+        }, doc);
+        out += JSON.stringify(val);
+        template = template.substr(end + 2);
+    }
+    return out;
+}
+
+// Returns the address of the client, in the format used by the Go adapters,
+// or its network only if anonymization is turned on. WebSocket clients are
+// identified by the address forwarded by the reverse proxy.
+function remoteAddr(client) {
+    var req = client.req,
+        host = client.conn.remoteAddress,
+        port = client.conn.remotePort;
+    if (req) {
+        host = req.headers['x-real-ip'] || req.connection.remoteAddress;
+        port = req.headers['x-real-ip'] ? '' : req.connection.remotePort;
+    }
+    if (!host) {
+        return '';
+    }
+    if (config.anonymize) {
+        return anonymize(host);
+    }
+    if (!port) {
+        return host;
+    }
+    return net.isIPv6(host) ? '[' + host + ']:' + port : host + ':' + port;
+}
+
+// Reduces the address to its /24 (IPv4) or /48 (IPv6) network.
+function anonymize(host) {
+    var mapped = host.match(/^::ffff:(\d+\.\d+\.\d+\.\d+)$/i),
+        halves, head, tail, groups;
+    if (mapped) {
+        host = mapped[1];
+    }
+    if (net.isIPv4(host)) {
+        return host.split('.').slice(0, 3).concat('0').join('.');
+    }
+    if (!net.isIPv6(host)) {
+        return '';
+    }
+    halves = host.split('::');
+    head = halves[0] ? halves[0].split(':') : [];
+    tail = halves[1] ? halves[1].split(':') : [];
+    groups = head.concat(new Array(8 - head.length - tail.length).fill('0'), tail).slice(0, 3).map(function (g) {
+        return parseInt(g, 16).toString(16);
+    });
+    while (groups.length && groups[groups.length - 1] === '0') {
+        groups.pop();
+    }
+    return groups.join(':') + '::';
+}
+
+// Returns a random (version 4) UUID identifying the published message, so
+// that writers can recognize its redeliveries.
+function messageId() {
+    var b = crypto.randomBytes(16),
+        hex;
+    b[6] = (b[6] & 0x0f) | 0x40;
+    b[8] = (b[8] & 0x3f) | 0x80;
+    hex = b.toString('hex');
+    return [hex.substr(0, 8), hex.substr(8, 4), hex.substr(12, 4), hex.substr(16, 4), hex.substr(20)].join('-');
+}
+
 function parseTopic(topic) {
     // Topics are in the form `channels/<channel_id>/messages`
     // Subtopic's are in the form `channels/<channel_id>/messages/<subtopic>`
     return /^channels\/(.+?)\/messages\/?.*$/.exec(topic);
 }
 
+// Converts the subtopic part of the topic filter into the subtopic the
+// things service checks the access to, replacing the MQTT wildcards with
+// the NATS ones.
+function parseSubtopicFilter(topic) {
+    return topic.split('/').slice(3).join('.').split('.').filter(function (value) {
+        return value !== '';
+    }).map(function (value) {
+        switch (value) {
+        case '+':
+            return '*';
+        case '#':
+            return '>';
+        default:
+            return value;
+        }
+    }).join('.');
+}
+
 aedes.authorizePublish = function (client, packet, publish) {
-    var channel = parseTopic(packet.topic);
+    var channel = parseTopic(packet.topic),
+        window = clientInflight(client),
+        received = Date.now() / 1000;
     if (!channel) {
         logger.warn('unknown topic');
         publish(4); // Bad username or password
         return;
     }
+    if (client.userId) {
+        logger.warn('unauthorized publish: user %s is subscribe-only', client.userId);
+        publish(4); // Bad username or password
+        return;
+    }
     var channelId = channel[1],
-        accessReq = {
-            token: client.password,
-            chanID: channelId
-        },
         // Parse unlimited subtopics
         baseLength = 3, // First 3 elements which represents the base part of topic.
         isEmpty = function(value) { 
@@ -169,21 +491,72 @@ aedes.authorizePublish = function (client, packet, publish) {
             return;
         }
     }
+    if (packet.qos > 0 && window.incoming >= config.receive_max) {
+        logger.warn('receive maximum exceeded: client: %s', client.id);
+        metrics.rejected.inc();
+        publish(new Error('receive maximum exceeded'));
+        return;
+    }
+    if (packet.qos > 0) {
+        window.incoming++;
+    }
     var channelTopic = elements.length ? baseTopic + '.' + elements.join('.') : baseTopic,
+        accessReq = {
+            token: client.password,
+            chanID: channelId,
+            action: 'publish',
+            subtopic: elements.join('.')
+        },
         onAuthorize = function (err, res) {
             var rawMsg;
+            if (packet.qos > 0) {
+                window.incoming--;
+            }
             if (!err) {
-                rawMsg = RawMessage.encode({
-                    publisher: client.thingId,
-                    channel: channelId,
-                    subtopic: elements.join('.'),
-                    protocol: 'mqtt',
-                    payload: packet.payload
-                }).finish();
+                allowedProtocol(channelId, function (allowed) {
+                    if (!allowed) {
+                        logger.warn('protocol not allowed: channel: %s', channelId);
+                        publish(new Error('protocol not allowed'));
+                        return;
+                    }
+
+                    takeToken(channelId, function (taken) {
+                        if (!taken) {
+                            logger.warn('channel rate limit exceeded: channel: %s', channelId);
+                            metrics.throttled.inc();
+                            publish(new Error('channel rate limit exceeded'));
+                            return;
+                        }
 
-                nats.publish(channelTopic, rawMsg);
+                        transform(client.thingId, channelId, packet.payload, function (err, msg) {
+                            if (err) {
+                                logger.warn('payload transformation failed: channel: %s: %s', channelId, err.message);
+                                publish(new Error('payload transformation failed'));
+                                return;
+                            }
 
-                publish(0);
+                            deliveryMode(channelId, function (delivery) {
+                                nextSeq(channelId, function (seq) {
+                                    rawMsg = RawMessage.encode({
+                                        publisher: client.thingId,
+                                        channel: channelId,
+                                        subtopic: elements.join('.'),
+                                        protocol: 'mqtt',
+                                        contentType: msg.contentType,
+                                        payload: msg.payload,
+                                        remoteAddr: remoteAddr(client),
+                                        received: received,
+                                        id: messageId(),
+                                        seq: seq,
+                                        delivery: delivery
+                                    }).finish();
+
+                                    deliver(channelTopic, rawMsg, delivery, publish);
+                                });
+                            });
+                        });
+                    });
+                });
             } else {
                 logger.warn('unauthorized publish: %s', err.message);
                 publish(4); // Bad username or password
@@ -204,7 +577,9 @@ aedes.authorizeSubscribe = function (client, packet, subscribe) {
     var channelId = channel[1],
         accessReq = {
             token: client.password,
-            chanID: channelId
+            chanID: channelId,
+            action: 'subscribe',
+            subtopic: parseSubtopicFilter(packet.topic)
         },
         onAuthorize = function (err, res) {
             if (!err) {
@@ -215,9 +590,31 @@ aedes.authorizeSubscribe = function (client, packet, subscribe) {
             }
         };
 
+    // Users can subscribe to the channels they own.
+    if (client.userId) {
+        things.canRead(accessReq, onAuthorize);
+        return;
+    }
     things.canAccess(accessReq, onAuthorize);
 };
 
+aedes.authorizeForward = function (client, packet) {
+    var window;
+    if (packet.qos === 0) {
+        return packet;
+    }
+
+    window = clientInflight(client);
+    if (window.outgoing >= config.max_inflight) {
+        metrics.downgraded.inc();
+        return Object.assign({}, packet, {qos: 0});
+    }
+
+    window.outgoing++;
+    metrics.inflight.inc();
+    return packet;
+};
+
 aedes.authenticate = function (client, username, password, acknowledge) {
     var pass = (password || '').toString(),
         identity = {value: pass},
@@ -228,6 +625,18 @@ aedes.authenticate = function (client, username, password, acknowledge) {
                 client.password = pass;
                 acknowledge(null, true);
                 publishConnEvent(client.thingId, 'connect');
+            } else if (users) {
+                users.identify(identity, onIdentifyUser);
+            } else {
+                logger.warn('failed to authenticate client with key %s', pass);
+                acknowledge(err, false);
+            }
+        },
+        onIdentifyUser = function(err, res) {
+            if (!err) {
+                client.userId = res.value.toString() || '';
+                client.password = pass;
+                acknowledge(null, true);
             } else {
                 logger.warn('failed to authenticate client with key %s', pass);
                 acknowledge(err, false);
@@ -237,10 +646,30 @@ aedes.authenticate = function (client, username, password, acknowledge) {
     things.identify(identity, onIdentify);
 };
 
+aedes.on('client', function () {
+    metrics.clients.inc();
+});
+
+aedes.on('ack', function (packet, client) {
+    var window = inflight[client.id];
+    if (window && window.outgoing > 0) {
+        window.outgoing--;
+        metrics.inflight.dec();
+    }
+});
+
 aedes.on('clientDisconnect', function (client) {
+    var window = inflight[client.id];
     logger.info('disconnect client %s', client.id);
     client.password = null;
-    publishConnEvent(client.thingId, 'disconnect');
+    metrics.clients.dec();
+    if (window) {
+        metrics.inflight.dec(window.outgoing);
+        delete inflight[client.id];
+    }
+    if (client.thingId) {
+        publishConnEvent(client.thingId, 'disconnect');
+    }
 });
 
 aedes.on('clientError', function (client, err) {
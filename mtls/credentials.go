@@ -0,0 +1,171 @@
+//
+// Copyright (c) 2018
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package mtls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"strings"
+
+	"google.golang.org/grpc/credentials"
+)
+
+var (
+	// ErrMissingKeyPair indicates that only one of the certificate and the
+	// key files is provided.
+	ErrMissingKeyPair = errors.New("both certificate and key files must be provided")
+
+	// ErrUnknownPeer indicates that the peer certificate doesn't hold any of
+	// the allowed identities.
+	ErrUnknownPeer = errors.New("peer certificate holds no allowed identity")
+)
+
+// ServerConfig returns the TLS configuration of the gRPC server. The server
+// certificate is reloaded once its files are modified, so that it can be
+// rotated without a restart. If the client CA file is provided, clients
+// are required to present a certificate issued by one of its CAs, which is
+// reloaded the same way. If the allowed IDs are provided as well, client
+// certificates must hold one of them as URI SAN, e.g. a SPIFFE ID. Empty
+// IDs are ignored.
+func ServerConfig(certFile, keyFile, clientCAFile string, allowedIDs []string) (*tls.Config, error) {
+	if certFile == "" || keyFile == "" {
+		return nil, ErrMissingKeyPair
+	}
+
+	kp, err := newKeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var cas *caPool
+	if clientCAFile != "" {
+		if cas, err = newCAPool(clientCAFile); err != nil {
+			return nil, err
+		}
+	}
+
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cert, err := kp.load()
+			if err != nil {
+				return nil, err
+			}
+
+			c := &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*cert},
+				NextProtos:   []string{"h2"},
+			}
+
+			if cas != nil {
+				pool, err := cas.load()
+				if err != nil {
+					return nil, err
+				}
+				c.ClientCAs = pool
+				c.ClientAuth = tls.RequireAndVerifyClientCert
+				c.VerifyPeerCertificate = verifyIDs(allowedIDs)
+			}
+
+			return c, nil
+		},
+	}
+
+	return cfg, nil
+}
+
+// ClientConfig returns the TLS configuration of the gRPC client. Server
+// certificate is verified using the provided CA file, or the system pool
+// if the file is not provided. If the certificate and key files are
+// provided, they are presented to the server and reloaded once modified.
+func ClientConfig(caFile, certFile, keyFile string) (*tls.Config, error) {
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if caFile != "" {
+		pool, err := readCAs(caFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.RootCAs = pool
+	}
+
+	if certFile == "" && keyFile == "" {
+		return cfg, nil
+	}
+
+	if certFile == "" || keyFile == "" {
+		return nil, ErrMissingKeyPair
+	}
+
+	kp, err := newKeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+		return kp.load()
+	}
+
+	return cfg, nil
+}
+
+// ServerCredentials returns gRPC server transport credentials. See
+// ServerConfig for the description of the arguments.
+func ServerCredentials(certFile, keyFile, clientCAFile string, allowedIDs []string) (credentials.TransportCredentials, error) {
+	cfg, err := ServerConfig(certFile, keyFile, clientCAFile, allowedIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return credentials.NewTLS(cfg), nil
+}
+
+// ClientCredentials returns gRPC client transport credentials. See
+// ClientConfig for the description of the arguments.
+func ClientCredentials(caFile, certFile, keyFile string) (credentials.TransportCredentials, error) {
+	cfg, err := ClientConfig(caFile, certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return credentials.NewTLS(cfg), nil
+}
+
+func verifyIDs(allowed []string) func([][]byte, [][]*x509.Certificate) error {
+	var ids []string
+	for _, id := range allowed {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	return func(_ [][]byte, chains [][]*x509.Certificate) error {
+		for _, chain := range chains {
+			if len(chain) == 0 {
+				continue
+			}
+			for _, uri := range chain[0].URIs {
+				for _, id := range ids {
+					if uri.String() == id {
+						return nil
+					}
+				}
+			}
+		}
+
+		return ErrUnknownPeer
+	}
+}
@@ -0,0 +1,247 @@
+//
+// Copyright (c) 2018
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package mtls_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/mtls"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	clientID = "spiffe://mainflux/http-adapter"
+	otherID  = "spiffe://mainflux/other"
+)
+
+type authority struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newAuthority(t *testing.T) authority {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Mainflux CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	cert, err := x509.ParseCertificate(der)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	return authority{cert: cert, key: key}
+}
+
+func (a authority) issue(t *testing.T, dir, name string, serial int64, id string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		DNSNames:     []string{"localhost"},
+	}
+	if id != "" {
+		u, err := url.Parse(id)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		tmpl.URIs = []*url.URL{u}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, a.cert, &key.PublicKey, a.key)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	certFile := filepath.Join(dir, name+".crt")
+	keyFile := filepath.Join(dir, name+".key")
+	write(t, certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	write(t, keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+
+	return certFile, keyFile
+}
+
+func (a authority) save(t *testing.T, dir string) string {
+	file := filepath.Join(dir, "ca.crt")
+	write(t, file, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: a.cert.Raw}))
+	return file
+}
+
+func write(t *testing.T, file string, data []byte) {
+	err := ioutil.WriteFile(file, data, 0600)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+}
+
+// handshake performs TLS handshake and returns serial number of the server
+// certificate.
+func handshake(t *testing.T, server, client *tls.Config) (int64, error) {
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", server)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	defer ln.Close()
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.(*tls.Conn).Handshake()
+		conn.Read(make([]byte, 1))
+	}()
+
+	client.ServerName = "localhost"
+	conn, err := tls.Dial("tcp", ln.Addr().String(), client)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	// With TLS 1.3 client certificate is verified after the client
+	// handshake completes, so wait for the server verdict.
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	if _, err := conn.Read(make([]byte, 1)); err != nil {
+		if ne, ok := err.(net.Error); !ok || !ne.Timeout() {
+			return 0, err
+		}
+	}
+
+	return conn.ConnectionState().PeerCertificates[0].SerialNumber.Int64(), nil
+}
+
+func TestMutualTLS(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mtls")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	defer os.RemoveAll(dir)
+
+	ca := newAuthority(t)
+	caFile := ca.save(t, dir)
+	serverCert, serverKey := ca.issue(t, dir, "server", 10, "")
+	clientCert, clientKey := ca.issue(t, dir, "client", 20, clientID)
+	otherCert, otherKey := ca.issue(t, dir, "other", 30, otherID)
+
+	untrusted := newAuthority(t)
+	untrustedDir := filepath.Join(dir, "untrusted")
+	require.Nil(t, os.Mkdir(untrustedDir, 0700))
+	untrustedCert, untrustedKey := untrusted.issue(t, untrustedDir, "client", 40, clientID)
+
+	cases := []struct {
+		desc       string
+		allowedIDs []string
+		certFile   string
+		keyFile    string
+		err        bool
+	}{
+		{
+			desc:     "connect with trusted client certificate",
+			certFile: clientCert,
+			keyFile:  clientKey,
+			err:      false,
+		},
+		{
+			desc: "connect without client certificate",
+			err:  true,
+		},
+		{
+			desc:     "connect with untrusted client certificate",
+			certFile: untrustedCert,
+			keyFile:  untrustedKey,
+			err:      true,
+		},
+		{
+			desc:       "connect with allowed client identity",
+			allowedIDs: []string{otherID, clientID},
+			certFile:   clientCert,
+			keyFile:    clientKey,
+			err:        false,
+		},
+		{
+			desc:       "connect with disallowed client identity",
+			allowedIDs: []string{clientID},
+			certFile:   otherCert,
+			keyFile:    otherKey,
+			err:        true,
+		},
+	}
+
+	for _, tc := range cases {
+		server, err := mtls.ServerConfig(serverCert, serverKey, caFile, tc.allowedIDs)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		client, err := mtls.ClientConfig(caFile, tc.certFile, tc.keyFile)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+
+		_, err = handshake(t, server, client)
+		assert.Equal(t, tc.err, err != nil, fmt.Sprintf("%s: expected error %t got %s", tc.desc, tc.err, err))
+	}
+}
+
+func TestCertificateRotation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mtls")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	defer os.RemoveAll(dir)
+
+	ca := newAuthority(t)
+	caFile := ca.save(t, dir)
+	serverCert, serverKey := ca.issue(t, dir, "server", 10, "")
+	clientCert, clientKey := ca.issue(t, dir, "client", 20, clientID)
+
+	server, err := mtls.ServerConfig(serverCert, serverKey, caFile, nil)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	client, err := mtls.ClientConfig(caFile, clientCert, clientKey)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	serial, err := handshake(t, server, client)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, int64(10), serial, fmt.Sprintf("expected serial %d got %d", 10, serial))
+
+	ca.issue(t, dir, "server", 11, "")
+	later := time.Now().Add(time.Minute)
+	require.Nil(t, os.Chtimes(serverCert, later, later))
+	require.Nil(t, os.Chtimes(serverKey, later, later))
+
+	serial, err = handshake(t, server, client)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, int64(11), serial, fmt.Sprintf("expected rotated serial %d got %d", 11, serial))
+}
+
+func TestConfig(t *testing.T) {
+	_, err := mtls.ServerConfig("", "", "", nil)
+	assert.Equal(t, mtls.ErrMissingKeyPair, err, fmt.Sprintf("expected %s got %s", mtls.ErrMissingKeyPair, err))
+
+	_, err = mtls.ClientConfig("", "cert", "")
+	assert.Equal(t, mtls.ErrMissingKeyPair, err, fmt.Sprintf("expected %s got %s", mtls.ErrMissingKeyPair, err))
+
+	_, err = mtls.ClientConfig("", "", "")
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+}
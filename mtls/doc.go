@@ -0,0 +1,10 @@
+//
+// Copyright (c) 2018
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// Package mtls contains transport credentials used to secure gRPC
+// connections between the services using mutual TLS.
+package mtls
@@ -0,0 +1,147 @@
+//
+// Copyright (c) 2018
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package mtls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+)
+
+var errNoCerts = errors.New("no certificates found in CA file")
+
+// keyPair holds the certificate and key loaded from the files, and reloads
+// them once the files are modified.
+type keyPair struct {
+	certFile string
+	keyFile  string
+
+	mu      sync.Mutex
+	modTime time.Time
+	cert    *tls.Certificate
+}
+
+func newKeyPair(certFile, keyFile string) (*keyPair, error) {
+	kp := &keyPair{
+		certFile: certFile,
+		keyFile:  keyFile,
+	}
+
+	if _, err := kp.load(); err != nil {
+		return nil, err
+	}
+
+	return kp, nil
+}
+
+func (kp *keyPair) load() (*tls.Certificate, error) {
+	kp.mu.Lock()
+	defer kp.mu.Unlock()
+
+	mod, err := lastModified(kp.certFile, kp.keyFile)
+	if err != nil && kp.cert == nil {
+		return nil, err
+	}
+	if err != nil || mod.Equal(kp.modTime) {
+		return kp.cert, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(kp.certFile, kp.keyFile)
+	if err != nil {
+		// Files may be in the middle of being rotated, so keep using the
+		// previous pair and retry on the next handshake.
+		if kp.cert != nil {
+			return kp.cert, nil
+		}
+		return nil, err
+	}
+
+	kp.cert = &cert
+	kp.modTime = mod
+
+	return kp.cert, nil
+}
+
+// caPool holds the CA certificates pool loaded from the file, and reloads
+// it once the file is modified.
+type caPool struct {
+	file string
+
+	mu      sync.Mutex
+	modTime time.Time
+	pool    *x509.CertPool
+}
+
+func newCAPool(file string) (*caPool, error) {
+	cp := &caPool{file: file}
+
+	if _, err := cp.load(); err != nil {
+		return nil, err
+	}
+
+	return cp, nil
+}
+
+func (cp *caPool) load() (*x509.CertPool, error) {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	mod, err := lastModified(cp.file)
+	if err != nil && cp.pool == nil {
+		return nil, err
+	}
+	if err != nil || mod.Equal(cp.modTime) {
+		return cp.pool, nil
+	}
+
+	pool, err := readCAs(cp.file)
+	if err != nil {
+		if cp.pool != nil {
+			return cp.pool, nil
+		}
+		return nil, err
+	}
+
+	cp.pool = pool
+	cp.modTime = mod
+
+	return cp.pool, nil
+}
+
+func readCAs(file string) (*x509.CertPool, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, errNoCerts
+	}
+
+	return pool, nil
+}
+
+func lastModified(files ...string) (time.Time, error) {
+	var last time.Time
+	for _, f := range files {
+		info, err := os.Stat(f)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if info.ModTime().After(last) {
+			last = info.ModTime()
+		}
+	}
+
+	return last, nil
+}
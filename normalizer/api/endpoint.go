@@ -0,0 +1,129 @@
+//
+// Copyright (c) 2018
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package api
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/normalizer"
+)
+
+func listDeadLettersEndpoint(svc normalizer.Service) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(listDeadLettersReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		page, err := svc.ListDeadLetters(req.offset, req.limit)
+		if err != nil {
+			return nil, err
+		}
+
+		res := deadLettersPageRes{
+			Total:       page.Total,
+			Offset:      page.Offset,
+			Limit:       page.Limit,
+			DeadLetters: []viewDeadLetterRes{},
+		}
+		for _, dl := range page.DeadLetters {
+			res.DeadLetters = append(res.DeadLetters, toDeadLetterRes(dl))
+		}
+
+		return res, nil
+	}
+}
+
+func viewDeadLetterEndpoint(svc normalizer.Service) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewDeadLetterReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		dl, err := svc.ViewDeadLetter(req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		return toDeadLetterRes(dl), nil
+	}
+}
+
+func redriveDeadLetterEndpoint(svc normalizer.Service) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewDeadLetterReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RedriveDeadLetter(req.id); err != nil {
+			return nil, err
+		}
+
+		return redriveRes{}, nil
+	}
+}
+
+func removeDeadLetterEndpoint(svc normalizer.Service) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewDeadLetterReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RemoveDeadLetter(req.id); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func importEndpoint(svc normalizer.Service) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(importReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		msg := mainflux.RawMessage{
+			Channel:     req.channel,
+			Subtopic:    req.subtopic,
+			Publisher:   req.publisher,
+			Protocol:    protocol,
+			ContentType: senMLType,
+			Payload:     req.payload,
+		}
+
+		n, err := svc.Import(msg)
+		if err != nil {
+			return nil, err
+		}
+
+		return importRes{Messages: n}, nil
+	}
+}
+
+func toDeadLetterRes(dl mainflux.DeadLetter) viewDeadLetterRes {
+	return viewDeadLetterRes{
+		ID:      dl.ID,
+		Stage:   dl.Stage,
+		Reason:  dl.Reason,
+		Subject: dl.Subject,
+		Payload: dl.Payload,
+		Created: dl.Created,
+	}
+}
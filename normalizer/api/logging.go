@@ -43,3 +43,81 @@ func (lm loggingMiddleware) Normalize(msg mainflux.RawMessage) (nd normalizer.No
 
 	return lm.svc.Normalize(msg)
 }
+
+func (lm loggingMiddleware) SaveDeadLetter(dl mainflux.DeadLetter) (id string, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method save_dead_letter for stage %s took %s to complete", dl.Stage, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.SaveDeadLetter(dl)
+}
+
+func (lm loggingMiddleware) ListDeadLetters(offset, limit uint64) (page normalizer.DeadLetterPage, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_dead_letters took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListDeadLetters(offset, limit)
+}
+
+func (lm loggingMiddleware) ViewDeadLetter(id string) (dl mainflux.DeadLetter, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method view_dead_letter for id %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ViewDeadLetter(id)
+}
+
+func (lm loggingMiddleware) RedriveDeadLetter(id string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method redrive_dead_letter for id %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RedriveDeadLetter(id)
+}
+
+func (lm loggingMiddleware) RemoveDeadLetter(id string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method remove_dead_letter for id %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RemoveDeadLetter(id)
+}
+
+func (lm loggingMiddleware) Import(msg mainflux.RawMessage) (n int, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method import for channel %s and publisher %s imported %d messages and took %s to complete", msg.Channel, msg.Publisher, n, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Import(msg)
+}
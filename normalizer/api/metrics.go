@@ -41,3 +41,57 @@ func (mm *metricsMiddleware) Normalize(msg mainflux.RawMessage) (normalizer.Norm
 
 	return mm.svc.Normalize(msg)
 }
+
+func (mm *metricsMiddleware) SaveDeadLetter(dl mainflux.DeadLetter) (string, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "save_dead_letter").Add(1)
+		mm.latency.With("method", "save_dead_letter").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.SaveDeadLetter(dl)
+}
+
+func (mm *metricsMiddleware) ListDeadLetters(offset, limit uint64) (normalizer.DeadLetterPage, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "list_dead_letters").Add(1)
+		mm.latency.With("method", "list_dead_letters").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.ListDeadLetters(offset, limit)
+}
+
+func (mm *metricsMiddleware) ViewDeadLetter(id string) (mainflux.DeadLetter, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "view_dead_letter").Add(1)
+		mm.latency.With("method", "view_dead_letter").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.ViewDeadLetter(id)
+}
+
+func (mm *metricsMiddleware) RedriveDeadLetter(id string) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "redrive_dead_letter").Add(1)
+		mm.latency.With("method", "redrive_dead_letter").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.RedriveDeadLetter(id)
+}
+
+func (mm *metricsMiddleware) RemoveDeadLetter(id string) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "remove_dead_letter").Add(1)
+		mm.latency.With("method", "remove_dead_letter").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.RemoveDeadLetter(id)
+}
+
+func (mm *metricsMiddleware) Import(msg mainflux.RawMessage) (int, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "import").Add(1)
+		mm.latency.With("method", "import").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.Import(msg)
+}
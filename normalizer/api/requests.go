@@ -0,0 +1,60 @@
+//
+// Copyright (c) 2018
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package api
+
+import "github.com/mainflux/mainflux/normalizer"
+
+const maxLimitSize = 100
+
+type apiReq interface {
+	validate() error
+}
+
+type listDeadLettersReq struct {
+	offset uint64
+	limit  uint64
+}
+
+func (req listDeadLettersReq) validate() error {
+	if req.limit == 0 || req.limit > maxLimitSize {
+		return errInvalidQueryParams
+	}
+
+	return nil
+}
+
+type viewDeadLetterReq struct {
+	id string
+}
+
+func (req viewDeadLetterReq) validate() error {
+	if req.id == "" {
+		return normalizer.ErrNotFound
+	}
+
+	return nil
+}
+
+type importReq struct {
+	channel   string
+	publisher string
+	subtopic  string
+	payload   []byte
+}
+
+func (req importReq) validate() error {
+	if req.channel == "" || req.publisher == "" {
+		return errInvalidQueryParams
+	}
+
+	if len(req.payload) == 0 {
+		return normalizer.ErrMalformedEntity
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+//
+// Copyright (c) 2018
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/mainflux/mainflux"
+)
+
+var (
+	_ mainflux.Response = (*viewDeadLetterRes)(nil)
+	_ mainflux.Response = (*deadLettersPageRes)(nil)
+	_ mainflux.Response = (*redriveRes)(nil)
+	_ mainflux.Response = (*removeRes)(nil)
+	_ mainflux.Response = (*importRes)(nil)
+)
+
+type viewDeadLetterRes struct {
+	ID      string    `json:"id"`
+	Stage   string    `json:"stage"`
+	Reason  string    `json:"reason"`
+	Subject string    `json:"subject"`
+	Payload []byte    `json:"payload"`
+	Created time.Time `json:"created"`
+}
+
+func (res viewDeadLetterRes) Code() int {
+	return http.StatusOK
+}
+
+func (res viewDeadLetterRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res viewDeadLetterRes) Empty() bool {
+	return false
+}
+
+type deadLettersPageRes struct {
+	Total       uint64              `json:"total"`
+	Offset      uint64              `json:"offset"`
+	Limit       uint64              `json:"limit"`
+	DeadLetters []viewDeadLetterRes `json:"dead_letters"`
+}
+
+func (res deadLettersPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res deadLettersPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res deadLettersPageRes) Empty() bool {
+	return false
+}
+
+type redriveRes struct{}
+
+func (res redriveRes) Code() int {
+	return http.StatusAccepted
+}
+
+func (res redriveRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res redriveRes) Empty() bool {
+	return true
+}
+
+type removeRes struct{}
+
+func (res removeRes) Code() int {
+	return http.StatusNoContent
+}
+
+func (res removeRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res removeRes) Empty() bool {
+	return true
+}
+
+type importRes struct {
+	Messages int `json:"messages"`
+}
+
+func (res importRes) Code() int {
+	return http.StatusAccepted
+}
+
+func (res importRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res importRes) Empty() bool {
+	return false
+}
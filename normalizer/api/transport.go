@@ -0,0 +1,202 @@
+//
+// Copyright (c) 2018
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/go-zoo/bone"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/identifiers"
+	"github.com/mainflux/mainflux/normalizer"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	contentType = "application/json"
+	senMLType   = "application/senml+json"
+	protocol    = "import"
+	offset      = "offset"
+	limit       = "limit"
+
+	defOffset = 0
+	defLimit  = 10
+)
+
+var (
+	errInvalidQueryParams     = errors.New("invalid query params")
+	errUnsupportedContentType = errors.New("unsupported content type")
+)
+
+// MakeHandler returns a HTTP handler for API endpoints.
+func MakeHandler(svc normalizer.Service) http.Handler {
+	opts := []kithttp.ServerOption{
+		kithttp.ServerErrorEncoder(encodeError),
+	}
+
+	r := bone.New()
+
+	r.Get("/deadletters", kithttp.NewServer(
+		listDeadLettersEndpoint(svc),
+		decodeList,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/deadletters/:id", kithttp.NewServer(
+		viewDeadLetterEndpoint(svc),
+		decodeView,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Post("/deadletters/:id/redrive", kithttp.NewServer(
+		redriveDeadLetterEndpoint(svc),
+		decodeView,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Delete("/deadletters/:id", kithttp.NewServer(
+		removeDeadLetterEndpoint(svc),
+		decodeView,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Post("/channels/:id/import", kithttp.NewServer(
+		importEndpoint(svc),
+		decodeImport,
+		encodeResponse,
+		opts...,
+	))
+
+	r.GetFunc("/version", mainflux.Version("normalizer"))
+	r.Handle("/metrics", promhttp.Handler())
+
+	return r
+}
+
+func decodeList(_ context.Context, r *http.Request) (interface{}, error) {
+	o, err := readUintQuery(r, offset, defOffset)
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := readUintQuery(r, limit, defLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	req := listDeadLettersReq{
+		offset: o,
+		limit:  l,
+	}
+
+	return req, nil
+}
+
+func decodeView(_ context.Context, r *http.Request) (interface{}, error) {
+	id, err := identifiers.Parse(bone.GetValue(r, "id"))
+	if err != nil {
+		return nil, err
+	}
+
+	req := viewDeadLetterReq{
+		id: id,
+	}
+
+	return req, nil
+}
+
+func decodeImport(_ context.Context, r *http.Request) (interface{}, error) {
+	ct := r.Header.Get("Content-Type")
+	if !strings.Contains(ct, senMLType) && !strings.Contains(ct, contentType) {
+		return nil, errUnsupportedContentType
+	}
+
+	chanID, err := identifiers.Parse(bone.GetValue(r, "id"))
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	req := importReq{
+		channel:   chanID,
+		publisher: r.URL.Query().Get("publisher"),
+		subtopic:  r.URL.Query().Get("subtopic"),
+		payload:   payload,
+	}
+
+	return req, nil
+}
+
+func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", contentType)
+
+	if ar, ok := response.(mainflux.Response); ok {
+		for k, v := range ar.Headers() {
+			w.Header().Set(k, v)
+		}
+
+		w.WriteHeader(ar.Code())
+
+		if ar.Empty() {
+			return nil
+		}
+	}
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	w.Header().Set("Content-Type", contentType)
+
+	switch err {
+	case normalizer.ErrNotFound:
+		w.WriteHeader(http.StatusNotFound)
+	case errInvalidQueryParams, normalizer.ErrMalformedEntity, identifiers.ErrMalformedID:
+		w.WriteHeader(http.StatusBadRequest)
+	case normalizer.ErrInvalidReading:
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	case errUnsupportedContentType:
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+func readUintQuery(r *http.Request, key string, def uint64) (uint64, error) {
+	vals := bone.GetQuery(r, key)
+	if len(vals) > 1 {
+		return 0, errInvalidQueryParams
+	}
+
+	if len(vals) == 0 {
+		return def, nil
+	}
+
+	strval := vals[0]
+	val, err := strconv.ParseUint(strval, 10, 64)
+	if err != nil {
+		return 0, errInvalidQueryParams
+	}
+
+	return val, nil
+}
@@ -0,0 +1,54 @@
+//
+// Copyright (c) 2018
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package normalizer
+
+import (
+	"errors"
+
+	"github.com/mainflux/mainflux"
+)
+
+// ErrNotFound indicates a non-existent dead letter request.
+var ErrNotFound = errors.New("non-existent dead letter")
+
+// ErrMalformedEntity indicates malformed imported messages (e.g. invalid
+// SenML or relative timestamps).
+var ErrMalformedEntity = errors.New("malformed entity specification")
+
+// DeadLetterPage contains page related metadata as well as list of dead
+// letters that belong to this page.
+type DeadLetterPage struct {
+	Total       uint64
+	Offset      uint64
+	Limit       uint64
+	DeadLetters []mainflux.DeadLetter
+}
+
+// DeadLetterRepository specifies a dead letter persistence API.
+type DeadLetterRepository interface {
+	// Save persists the dead letter. Successful operation is indicated by
+	// unique identifier accompanied by nil error response.
+	Save(mainflux.DeadLetter) (string, error)
+
+	// RetrieveByID retrieves the dead letter having the provided identifier.
+	RetrieveByID(string) (mainflux.DeadLetter, error)
+
+	// RetrieveAll retrieves the subset of stored dead letters, starting
+	// with the oldest one.
+	RetrieveAll(uint64, uint64) DeadLetterPage
+
+	// Remove removes the dead letter having the provided identifier.
+	Remove(string) error
+}
+
+// Publisher specifies an API for publishing raw payloads to the message
+// broker subjects.
+type Publisher interface {
+	// Publish publishes the payload to the given subject.
+	Publish(string, []byte) error
+}
@@ -0,0 +1,104 @@
+//
+// Copyright (c) 2018
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package memory
+
+import (
+	"sync"
+
+	"github.com/gofrs/uuid"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/normalizer"
+)
+
+var _ normalizer.DeadLetterRepository = (*deadLetterRepository)(nil)
+
+type deadLetterRepository struct {
+	mu          sync.Mutex
+	size        int
+	deadLetters []mainflux.DeadLetter
+}
+
+// NewDeadLetterRepository instantiates an in-memory dead letter repository
+// holding at most size dead letters. Once the repository is full, the oldest
+// dead letter is evicted to make room for the new one.
+func NewDeadLetterRepository(size int) normalizer.DeadLetterRepository {
+	return &deadLetterRepository{
+		size:        size,
+		deadLetters: []mainflux.DeadLetter{},
+	}
+}
+
+func (repo *deadLetterRepository) Save(dl mainflux.DeadLetter) (string, error) {
+	id, err := uuid.NewV4()
+	if err != nil {
+		return "", err
+	}
+	dl.ID = id.String()
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if len(repo.deadLetters) >= repo.size {
+		repo.deadLetters = repo.deadLetters[len(repo.deadLetters)-repo.size+1:]
+	}
+	repo.deadLetters = append(repo.deadLetters, dl)
+
+	return dl.ID, nil
+}
+
+func (repo *deadLetterRepository) RetrieveByID(id string) (mainflux.DeadLetter, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for _, dl := range repo.deadLetters {
+		if dl.ID == id {
+			return dl, nil
+		}
+	}
+
+	return mainflux.DeadLetter{}, normalizer.ErrNotFound
+}
+
+func (repo *deadLetterRepository) RetrieveAll(offset, limit uint64) normalizer.DeadLetterPage {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	total := uint64(len(repo.deadLetters))
+	page := normalizer.DeadLetterPage{
+		Total:       total,
+		Offset:      offset,
+		Limit:       limit,
+		DeadLetters: []mainflux.DeadLetter{},
+	}
+
+	if offset >= total {
+		return page
+	}
+
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+
+	page.DeadLetters = append(page.DeadLetters, repo.deadLetters[offset:end]...)
+	return page
+}
+
+func (repo *deadLetterRepository) Remove(id string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for i, dl := range repo.deadLetters {
+		if dl.ID == id {
+			repo.deadLetters = append(repo.deadLetters[:i], repo.deadLetters[i+1:]...)
+			return nil
+		}
+	}
+
+	return nil
+}
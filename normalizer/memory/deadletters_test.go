@@ -0,0 +1,118 @@
+//
+// Copyright (c) 2018
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package memory_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/normalizer"
+	"github.com/mainflux/mainflux/normalizer/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const size = 10
+
+var deadLetter = mainflux.DeadLetter{
+	Stage:   "normalizer",
+	Reason:  "failed",
+	Subject: "redrive.normalizers",
+	Payload: []byte("payload"),
+}
+
+func TestSave(t *testing.T) {
+	repo := memory.NewDeadLetterRepository(size)
+
+	for i := 0; i < size+5; i++ {
+		id, err := repo.Save(deadLetter)
+		assert.Nil(t, err, fmt.Sprintf("save dead letter: unexpected error %s", err))
+		assert.NotEmpty(t, id, "save dead letter: expected non-empty id")
+	}
+
+	page := repo.RetrieveAll(0, size+5)
+	assert.Equal(t, uint64(size), page.Total, fmt.Sprintf("expected total %d got %d", size, page.Total))
+}
+
+func TestRetrieveByID(t *testing.T) {
+	repo := memory.NewDeadLetterRepository(size)
+	id, err := repo.Save(deadLetter)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := map[string]struct {
+		id  string
+		err error
+	}{
+		"retrieve existing dead letter": {
+			id:  id,
+			err: nil,
+		},
+		"retrieve non-existing dead letter": {
+			id:  "non-existing",
+			err: normalizer.ErrNotFound,
+		},
+	}
+
+	for desc, tc := range cases {
+		_, err := repo.RetrieveByID(tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
+func TestRetrieveAll(t *testing.T) {
+	repo := memory.NewDeadLetterRepository(size)
+	for i := 0; i < size; i++ {
+		_, err := repo.Save(deadLetter)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	cases := map[string]struct {
+		offset uint64
+		limit  uint64
+		size   int
+	}{
+		"retrieve all dead letters": {
+			offset: 0,
+			limit:  size,
+			size:   size,
+		},
+		"retrieve subset of dead letters": {
+			offset: 5,
+			limit:  3,
+			size:   3,
+		},
+		"retrieve last page of dead letters": {
+			offset: 8,
+			limit:  5,
+			size:   2,
+		},
+		"retrieve dead letters with offset out of range": {
+			offset: size + 1,
+			limit:  5,
+			size:   0,
+		},
+	}
+
+	for desc, tc := range cases {
+		page := repo.RetrieveAll(tc.offset, tc.limit)
+		assert.Equal(t, tc.size, len(page.DeadLetters), fmt.Sprintf("%s: expected %d got %d\n", desc, tc.size, len(page.DeadLetters)))
+	}
+}
+
+func TestRemove(t *testing.T) {
+	repo := memory.NewDeadLetterRepository(size)
+	id, err := repo.Save(deadLetter)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	err = repo.Remove(id)
+	assert.Nil(t, err, fmt.Sprintf("remove dead letter: unexpected error %s", err))
+
+	_, err = repo.RetrieveByID(id)
+	assert.Equal(t, normalizer.ErrNotFound, err, fmt.Sprintf("retrieve removed dead letter: expected %s got %s", normalizer.ErrNotFound, err))
+}
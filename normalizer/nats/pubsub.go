@@ -8,6 +8,7 @@
 package nats
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/gogo/protobuf/proto"
@@ -22,6 +23,12 @@ const (
 	input         = "channel.>"
 	outputUnknown = "out.unknown"
 	senML         = "application/senml+json"
+	stage         = "normalizer"
+)
+
+var (
+	redrive     = fmt.Sprintf("%s.%s", mainflux.Redrive, queue)
+	deadLetters = fmt.Sprintf("%s.>", mainflux.DeadLetters)
 )
 
 type pubsub struct {
@@ -31,6 +38,12 @@ type pubsub struct {
 }
 
 // Subscribe to appropriate NATS topic and normalizes received messages.
+// Messages that fail normalization or validation, as well as the ones
+// reported by the other processing stages, are stored as dead letters,
+// unless they are delivered at most once, in which case they are dropped.
+// Malformed SenML messages passed in the lenient mode are published to the
+// raw output subject along with the warning. Messages published as requests
+// are acknowledged once they are passed on or stored as dead letters.
 func Subscribe(svc normalizer.Service, nc *nats.Conn, logger log.Logger) {
 	ps := pubsub{
 		nc:     nc,
@@ -38,24 +51,94 @@ func Subscribe(svc normalizer.Service, nc *nats.Conn, logger log.Logger) {
 		logger: logger,
 	}
 	ps.nc.QueueSubscribe(input, queue, ps.handleMsg)
+	ps.nc.QueueSubscribe(redrive, queue, ps.handleMsg)
+	ps.nc.QueueSubscribe(deadLetters, queue, ps.handleDeadLetter)
 }
 
 func (ps pubsub) handleMsg(m *nats.Msg) {
 	var msg mainflux.RawMessage
 	if err := proto.Unmarshal(m.Data, &msg); err != nil {
 		ps.logger.Warn(fmt.Sprintf("Unmarshalling failed: %s", err))
+		if ps.saveDeadLetter(m.Data, err) {
+			ps.ack(m)
+		}
 		return
 	}
 
 	if err := ps.publish(msg); err != nil {
 		ps.logger.Warn(fmt.Sprintf("Publishing failed: %s", err))
+		if msg.Delivery == mainflux.DeliveryAtMostOnce {
+			return
+		}
+		if ps.saveDeadLetter(m.Data, err) {
+			ps.ack(m)
+		}
+		return
+	}
+
+	// Normalized messages are flushed before the acknowledgement, so that
+	// the acknowledged message is already accepted by the broker.
+	if m.Reply != "" {
+		if err := ps.nc.Flush(); err != nil {
+			ps.logger.Warn(fmt.Sprintf("Flushing failed: %s", err))
+			return
+		}
+	}
+	ps.ack(m)
+}
+
+// ack replies to the message published as a request.
+func (ps pubsub) ack(m *nats.Msg) {
+	if m.Reply == "" {
 		return
 	}
+
+	if err := ps.nc.Publish(m.Reply, nil); err != nil {
+		ps.logger.Warn(fmt.Sprintf("Acknowledging failed: %s", err))
+	}
+}
+
+func (ps pubsub) handleDeadLetter(m *nats.Msg) {
+	var dl mainflux.DeadLetter
+	if err := json.Unmarshal(m.Data, &dl); err != nil {
+		ps.logger.Warn(fmt.Sprintf("Failed to unmarshal dead letter: %s", err))
+		return
+	}
+
+	if _, err := ps.svc.SaveDeadLetter(dl); err != nil {
+		ps.logger.Warn(fmt.Sprintf("Failed to save dead letter: %s", err))
+	}
+}
+
+// saveDeadLetter stores the message data as a dead letter and reports
+// whether it succeeded.
+func (ps pubsub) saveDeadLetter(data []byte, reason error) bool {
+	dl := mainflux.DeadLetter{
+		Stage:   stage,
+		Reason:  reason.Error(),
+		Subject: redrive,
+		Payload: data,
+	}
+
+	if _, err := ps.svc.SaveDeadLetter(dl); err != nil {
+		ps.logger.Warn(fmt.Sprintf("Failed to save dead letter: %s", err))
+		return false
+	}
+
+	return true
 }
 
 func (ps pubsub) publish(msg mainflux.RawMessage) error {
 	output := mainflux.OutputSenML
 	normalized, err := ps.svc.Normalize(msg)
+	if err == normalizer.ErrInvalidReading {
+		return err
+	}
+
+	if err == nil && normalized.Warning != "" {
+		return ps.publishRaw(msg, normalized.Warning)
+	}
+
 	if err != nil {
 		switch ct := msg.ContentType; ct {
 		case senML:
@@ -72,14 +155,39 @@ func (ps pubsub) publish(msg mainflux.RawMessage) error {
 		}
 	}
 
-	for _, v := range normalized.Messages {
+	if err := ps.publishAll(output, normalized.Messages); err != nil {
+		return err
+	}
+
+	return ps.publishAll(mainflux.OutputFlagged, normalized.Flagged)
+}
+
+func (ps pubsub) publishRaw(msg mainflux.RawMessage, warning string) error {
+	ps.logger.Warn(fmt.Sprintf("Passing malformed message through: %s", warning))
+
+	data, err := json.Marshal(mainflux.WarnedMessage{Warning: warning, Message: msg})
+	if err != nil {
+		ps.logger.Warn(fmt.Sprintf("Marshalling failed: %s", err))
+		return err
+	}
+
+	if err := ps.nc.Publish(mainflux.OutputRaw, data); err != nil {
+		ps.logger.Warn(fmt.Sprintf("Publishing failed: %s", err))
+		return err
+	}
+
+	return nil
+}
+
+func (ps pubsub) publishAll(subject string, msgs []mainflux.Message) error {
+	for _, v := range msgs {
 		data, err := proto.Marshal(&v)
 		if err != nil {
 			ps.logger.Warn(fmt.Sprintf("Marshalling failed: %s", err))
 			return err
 		}
 
-		if err := ps.nc.Publish(output, data); err != nil {
+		if err := ps.nc.Publish(subject, data); err != nil {
 			ps.logger.Warn(fmt.Sprintf("Publishing failed: %s", err))
 			return err
 		}
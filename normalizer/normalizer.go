@@ -9,24 +9,138 @@ package normalizer
 
 import (
 	"strings"
+	"time"
 
 	"github.com/cisco/senml"
+	"github.com/gogo/protobuf/proto"
 	"github.com/mainflux/mainflux"
 )
 
-type normalizer struct{}
+const (
+	// SenML times lower than 2^28 are relative to the current time.
+	relativeTime = 1 << 28
 
-// New returns normalizer service implementation.
-func New() Service {
-	return normalizer{}
+	senMLContentType = "application/senml+json"
+)
+
+type normalizer struct {
+	deadLetters DeadLetterRepository
+	publisher   Publisher
+	validations ValidationCache
+	mode        string
+}
+
+// New returns normalizer service implementation. Messages are validated
+// against the rules of their channel, retrieved from the provided cache,
+// unless it is nil. Malformed SenML messages are handled according to the
+// provided mode, either things.StrictMode or things.LenientMode, unless the
+// rules of their channel override it.
+func New(deadLetters DeadLetterRepository, publisher Publisher, validations ValidationCache, mode string) Service {
+	return normalizer{
+		deadLetters: deadLetters,
+		publisher:   publisher,
+		validations: validations,
+		mode:        mode,
+	}
 }
 
 func (n normalizer) Normalize(msg mainflux.RawMessage) (NormalizedData, error) {
+	output := strings.ToLower(msg.ContentType)
+
 	raw, err := senml.Decode(msg.Payload, senml.JSON)
 	if err != nil {
+		if output == senMLContentType && n.lenient(msg.Channel) {
+			return NormalizedData{ContentType: output, Warning: err.Error()}, nil
+		}
 		return NormalizedData{}, err
 	}
 
+	valid, flagged, err := n.validate(msg.Channel, toMessages(msg, raw))
+	if err != nil {
+		return NormalizedData{}, err
+	}
+
+	return NormalizedData{
+		ContentType: output,
+		Messages:    valid,
+		Flagged:     flagged,
+	}, nil
+}
+
+func (n normalizer) SaveDeadLetter(dl mainflux.DeadLetter) (string, error) {
+	if dl.Created.IsZero() {
+		dl.Created = time.Now()
+	}
+
+	return n.deadLetters.Save(dl)
+}
+
+func (n normalizer) ListDeadLetters(offset, limit uint64) (DeadLetterPage, error) {
+	return n.deadLetters.RetrieveAll(offset, limit), nil
+}
+
+func (n normalizer) ViewDeadLetter(id string) (mainflux.DeadLetter, error) {
+	return n.deadLetters.RetrieveByID(id)
+}
+
+func (n normalizer) RedriveDeadLetter(id string) error {
+	dl, err := n.deadLetters.RetrieveByID(id)
+	if err != nil {
+		return err
+	}
+
+	if err := n.publisher.Publish(dl.Subject, dl.Payload); err != nil {
+		return err
+	}
+
+	return n.deadLetters.Remove(id)
+}
+
+func (n normalizer) RemoveDeadLetter(id string) error {
+	return n.deadLetters.Remove(id)
+}
+
+func (n normalizer) Import(msg mainflux.RawMessage) (int, error) {
+	raw, err := senml.Decode(msg.Payload, senml.JSON)
+	if err != nil {
+		return 0, ErrMalformedEntity
+	}
+
+	if !absolute(raw) {
+		return 0, ErrMalformedEntity
+	}
+
+	msgs, flagged, err := n.validate(msg.Channel, toMessages(msg, raw))
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range msgs {
+		data, err := proto.Marshal(&msgs[i])
+		if err != nil {
+			return i, err
+		}
+
+		if err := n.publisher.Publish(mainflux.Import, data); err != nil {
+			return i, err
+		}
+	}
+
+	for i := range flagged {
+		data, err := proto.Marshal(&flagged[i])
+		if err != nil {
+			return len(msgs), err
+		}
+
+		if err := n.publisher.Publish(mainflux.OutputFlagged, data); err != nil {
+			return len(msgs), err
+		}
+	}
+
+	return len(msgs), nil
+}
+
+func toMessages(msg mainflux.RawMessage, raw senml.SenML) []mainflux.Message {
 	normalized := senml.Normalize(raw)
 
 	msgs := make([]mainflux.Message, len(normalized.Records))
@@ -36,6 +150,11 @@ func (n normalizer) Normalize(msg mainflux.RawMessage) (NormalizedData, error) {
 			Subtopic:   msg.Subtopic,
 			Publisher:  msg.Publisher,
 			Protocol:   msg.Protocol,
+			RemoteAddr: msg.RemoteAddr,
+			Received:   msg.Received,
+			Id:         msg.Id,
+			Seq:        msg.Seq,
+			Delivery:   msg.Delivery,
 			Name:       v.Name,
 			Unit:       v.Unit,
 			Time:       v.Time,
@@ -61,10 +180,27 @@ func (n normalizer) Normalize(msg mainflux.RawMessage) (NormalizedData, error) {
 		msgs[k] = m
 	}
 
-	output := strings.ToLower(msg.ContentType)
+	return msgs
 
-	return NormalizedData{
-		ContentType: output,
-		Messages:    msgs,
-	}, nil
+}
+
+// absolute reports whether all the records holding a value have an absolute
+// timestamp, since relative ones would be resolved against the import time.
+func absolute(raw senml.SenML) bool {
+	var btime float64
+	for _, r := range raw.Records {
+		if r.BaseTime != 0 {
+			btime = r.BaseTime
+		}
+
+		if r.Value == nil && r.StringValue == "" && r.DataValue == "" && r.BoolValue == nil {
+			continue
+		}
+
+		if btime+r.Time < relativeTime {
+			return false
+		}
+	}
+
+	return true
 }
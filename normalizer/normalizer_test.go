@@ -0,0 +1,249 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package normalizer_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/normalizer"
+	"github.com/mainflux/mainflux/normalizer/memory"
+	"github.com/mainflux/mainflux/things"
+	"github.com/stretchr/testify/assert"
+)
+
+const size = 10
+
+type publisherMock struct {
+	subjects []string
+}
+
+func (pm *publisherMock) Publish(subject string, _ []byte) error {
+	pm.subjects = append(pm.subjects, subject)
+	return nil
+}
+
+type validationCacheMock map[string]things.Validation
+
+func (vcm validationCacheMock) Validation(chanID string) (things.Validation, error) {
+	return vcm[chanID], nil
+}
+
+func TestImport(t *testing.T) {
+	cases := map[string]struct {
+		payload string
+		count   int
+		err     error
+	}{
+		"import messages with absolute time": {
+			payload: `[{"bn":"sensor:","bt":1.5e9,"n":"temp","v":20},{"n":"temp","t":60,"v":21}]`,
+			count:   2,
+			err:     nil,
+		},
+		"import messages with relative time": {
+			payload: `[{"n":"temp","t":-60,"v":20}]`,
+			count:   0,
+			err:     normalizer.ErrMalformedEntity,
+		},
+		"import invalid senml": {
+			payload: `{"n":"temp"`,
+			count:   0,
+			err:     normalizer.ErrMalformedEntity,
+		},
+	}
+
+	for desc, tc := range cases {
+		pub := &publisherMock{}
+		svc := normalizer.New(memory.NewDeadLetterRepository(size), pub, nil, things.StrictMode)
+
+		msg := mainflux.RawMessage{
+			Channel:   "1",
+			Publisher: "2",
+			Payload:   []byte(tc.payload),
+		}
+		count, err := svc.Import(msg)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+		assert.Equal(t, tc.count, count, fmt.Sprintf("%s: expected %d got %d\n", desc, tc.count, count))
+		assert.Equal(t, tc.count, len(pub.subjects), fmt.Sprintf("%s: expected %d published messages got %d\n", desc, tc.count, len(pub.subjects)))
+		for _, s := range pub.subjects {
+			assert.Equal(t, mainflux.Import, s, fmt.Sprintf("%s: expected subject %s got %s\n", desc, mainflux.Import, s))
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	svc := normalizer.New(memory.NewDeadLetterRepository(size), &publisherMock{}, nil, things.StrictMode)
+
+	msg := mainflux.RawMessage{
+		Channel:    "1",
+		Publisher:  "2",
+		Protocol:   "http",
+		RemoteAddr: "192.168.1.0",
+		Received:   1.5e9,
+		Seq:        7,
+		Delivery:   mainflux.DeliveryAtLeastOnce,
+		Payload:    []byte(`[{"n":"temp","v":20},{"n":"hum","v":40}]`),
+	}
+
+	data, err := svc.Normalize(msg)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Len(t, data.Messages, 2, fmt.Sprintf("expected 2 messages got %d", len(data.Messages)))
+	for _, m := range data.Messages {
+		assert.Equal(t, msg.Protocol, m.Protocol, fmt.Sprintf("expected protocol %s got %s", msg.Protocol, m.Protocol))
+		assert.Equal(t, msg.RemoteAddr, m.RemoteAddr, fmt.Sprintf("expected remote address %s got %s", msg.RemoteAddr, m.RemoteAddr))
+		assert.Equal(t, msg.Received, m.Received, fmt.Sprintf("expected receive time %f got %f", msg.Received, m.Received))
+		assert.Equal(t, msg.Seq, m.Seq, fmt.Sprintf("expected sequence number %d got %d", msg.Seq, m.Seq))
+		assert.Equal(t, msg.Delivery, m.Delivery, fmt.Sprintf("expected delivery mode %s got %s", msg.Delivery, m.Delivery))
+	}
+}
+
+func TestValidate(t *testing.T) {
+	min, max := -50.0, 150.0
+	rules := things.Validation{
+		Units:  []string{"Cel", "%RH"},
+		Ranges: map[string]things.Range{"Cel": {Min: &min, Max: &max}},
+	}
+	flagging := rules
+	flagging.Flag = true
+
+	cache := validationCacheMock{"reject": rules, "flag": flagging}
+	svc := normalizer.New(memory.NewDeadLetterRepository(size), &publisherMock{}, cache, things.StrictMode)
+
+	cases := map[string]struct {
+		channel  string
+		payload  string
+		messages int
+		flagged  int
+		err      error
+	}{
+		"normalize valid readings": {
+			channel:  "reject",
+			payload:  `[{"n":"temp","u":"Cel","v":20},{"n":"hum","u":"%RH","v":40},{"n":"on","vb":true}]`,
+			messages: 3,
+			err:      nil,
+		},
+		"normalize reading out of range": {
+			channel: "reject",
+			payload: `[{"n":"temp","u":"Cel","v":20},{"n":"temp","u":"Cel","v":-400}]`,
+			err:     normalizer.ErrInvalidReading,
+		},
+		"normalize reading with sum out of range": {
+			channel: "reject",
+			payload: `[{"n":"temp","u":"Cel","v":20,"s":200}]`,
+			err:     normalizer.ErrInvalidReading,
+		},
+		"normalize reading with unit not allowed": {
+			channel: "reject",
+			payload: `[{"n":"temp","u":"K","v":300}]`,
+			err:     normalizer.ErrInvalidReading,
+		},
+		"normalize invalid readings of flagging channel": {
+			channel:  "flag",
+			payload:  `[{"n":"temp","u":"Cel","v":20},{"n":"temp","u":"Cel","v":-400},{"n":"temp","u":"K","v":300}]`,
+			messages: 1,
+			flagged:  2,
+			err:      nil,
+		},
+		"normalize readings of channel without rules": {
+			channel:  "none",
+			payload:  `[{"n":"temp","u":"Cel","v":-400}]`,
+			messages: 1,
+			err:      nil,
+		},
+	}
+
+	for desc, tc := range cases {
+		msg := mainflux.RawMessage{
+			Channel:   tc.channel,
+			Publisher: "2",
+			Payload:   []byte(tc.payload),
+		}
+
+		data, err := svc.Normalize(msg)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+		assert.Len(t, data.Messages, tc.messages, fmt.Sprintf("%s: expected %d messages got %d\n", desc, tc.messages, len(data.Messages)))
+		assert.Len(t, data.Flagged, tc.flagged, fmt.Sprintf("%s: expected %d flagged messages got %d\n", desc, tc.flagged, len(data.Flagged)))
+	}
+}
+
+func TestNormalizeMode(t *testing.T) {
+	cache := validationCacheMock{
+		"strict":  things.Validation{Mode: things.StrictMode},
+		"lenient": things.Validation{Mode: things.LenientMode},
+	}
+
+	cases := map[string]struct {
+		mode        string
+		channel     string
+		contentType string
+		payload     string
+		messages    int
+		warning     bool
+		err         bool
+	}{
+		"normalize malformed senml in strict mode": {
+			mode:        things.StrictMode,
+			channel:     "none",
+			contentType: "application/senml+json",
+			payload:     `{"n":"temp"`,
+			err:         true,
+		},
+		"normalize malformed senml in lenient mode": {
+			mode:        things.LenientMode,
+			channel:     "none",
+			contentType: "application/senml+json",
+			payload:     `{"n":"temp"`,
+			warning:     true,
+		},
+		"normalize malformed senml of lenient channel in strict mode": {
+			mode:        things.StrictMode,
+			channel:     "lenient",
+			contentType: "application/senml+json",
+			payload:     `{"n":"temp"`,
+			warning:     true,
+		},
+		"normalize malformed senml of strict channel in lenient mode": {
+			mode:        things.LenientMode,
+			channel:     "strict",
+			contentType: "application/senml+json",
+			payload:     `{"n":"temp"`,
+			err:         true,
+		},
+		"normalize non-senml payload in lenient mode": {
+			mode:        things.LenientMode,
+			channel:     "none",
+			contentType: "application/json",
+			payload:     `{"temp":20}`,
+			err:         true,
+		},
+		"normalize valid senml in lenient mode": {
+			mode:        things.LenientMode,
+			channel:     "none",
+			contentType: "application/senml+json",
+			payload:     `[{"n":"temp","v":20}]`,
+			messages:    1,
+		},
+	}
+
+	for desc, tc := range cases {
+		svc := normalizer.New(memory.NewDeadLetterRepository(size), &publisherMock{}, cache, tc.mode)
+
+		msg := mainflux.RawMessage{
+			Channel:     tc.channel,
+			Publisher:   "2",
+			ContentType: tc.contentType,
+			Payload:     []byte(tc.payload),
+		}
+
+		data, err := svc.Normalize(msg)
+		assert.Equal(t, tc.err, err != nil, fmt.Sprintf("%s: expected error %t got %s\n", desc, tc.err, err))
+		assert.Equal(t, tc.warning, data.Warning != "", fmt.Sprintf("%s: expected warning %t got %q\n", desc, tc.warning, data.Warning))
+		assert.Len(t, data.Messages, tc.messages, fmt.Sprintf("%s: expected %d messages got %d\n", desc, tc.messages, len(data.Messages)))
+	}
+}
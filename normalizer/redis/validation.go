@@ -0,0 +1,53 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// Package redis contains the cache of the channels' validation rules, that
+// the things service maintains.
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-redis/redis"
+	"github.com/mainflux/mainflux/normalizer"
+	"github.com/mainflux/mainflux/things"
+)
+
+// validationPrefix prefixes the keys of the channels' validation rules, that
+// are kept as JSON documents.
+const validationPrefix = "validation"
+
+var _ normalizer.ValidationCache = (*validationCache)(nil)
+
+type validationCache struct {
+	client redis.UniversalClient
+}
+
+// NewValidationCache returns redis validation rules cache implementation.
+func NewValidationCache(client redis.UniversalClient) normalizer.ValidationCache {
+	return validationCache{client: client}
+}
+
+func (vc validationCache) Validation(chanID string) (things.Validation, error) {
+	key := fmt.Sprintf("%s:%s", validationPrefix, chanID)
+
+	data, err := vc.client.Get(key).Bytes()
+	if err == redis.Nil {
+		return things.Validation{}, nil
+	}
+	if err != nil {
+		return things.Validation{}, err
+	}
+
+	var v things.Validation
+	if err := json.Unmarshal(data, &v); err != nil {
+		return things.Validation{}, err
+	}
+
+	return v, nil
+}
@@ -9,14 +9,48 @@ package normalizer
 
 import "github.com/mainflux/mainflux"
 
-// Service specifies API for normalizing messages.
+// Service specifies API for normalizing messages and managing the messages
+// that failed processing.
 type Service interface {
-	// Normalizes raw message to array of standard SenML messages.
+	// Normalizes raw message to array of standard SenML messages. Messages
+	// violating the validation rules of their channel are rejected with
+	// ErrInvalidReading, or flagged if the rules say so. Malformed SenML
+	// messages are rejected in the strict mode, while in the lenient one
+	// only the warning is returned.
 	Normalize(mainflux.RawMessage) (NormalizedData, error)
+
+	// SaveDeadLetter stores the message that failed processing.
+	SaveDeadLetter(mainflux.DeadLetter) (string, error)
+
+	// ListDeadLetters retrieves the subset of stored dead letters.
+	ListDeadLetters(uint64, uint64) (DeadLetterPage, error)
+
+	// ViewDeadLetter retrieves the dead letter identified by the provided ID.
+	ViewDeadLetter(string) (mainflux.DeadLetter, error)
+
+	// RedriveDeadLetter republishes the dead letter identified by the
+	// provided ID to the stage it failed in, and removes it from the store.
+	RedriveDeadLetter(string) error
+
+	// RemoveDeadLetter removes the dead letter identified by the provided ID.
+	RemoveDeadLetter(string) error
+
+	// Import normalizes the historical messages and publishes them directly
+	// to the writers, bypassing the protocol adapters. Every record must
+	// carry its original absolute timestamp. Number of the published
+	// messages is returned. Validation rules of the channel apply to the
+	// historical messages as well.
+	Import(mainflux.RawMessage) (int, error)
 }
 
 // NormalizedData contains normalized messages and their content type.
+// Messages violating the validation rules of their channel, which is set to
+// flag them, are kept apart from the valid ones. Warning is set instead of
+// the messages if the malformed SenML message is to be passed through as
+// raw, according to the lenient mode.
 type NormalizedData struct {
 	ContentType string
 	Messages    []mainflux.Message
+	Flagged     []mainflux.Message
+	Warning     string
 }
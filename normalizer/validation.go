@@ -0,0 +1,118 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package normalizer
+
+import (
+	"errors"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/things"
+)
+
+// ErrInvalidReading indicates SenML records violating the validation rules
+// of the channel they were published to.
+var ErrInvalidReading = errors.New("reading violates channel validation rules")
+
+// ValidationCache specifies an API for retrieving the channels' SenML
+// validation rules, that the things service keeps in its cache.
+type ValidationCache interface {
+	// Validation retrieves the validation rules of the channel. The zero
+	// validation is returned for the channels without the rules.
+	Validation(string) (things.Validation, error)
+}
+
+// validate splits the messages into the ones complying with the rules of
+// their channel and the flagged ones. Unless the rules are set to flag the
+// invalid messages, ErrInvalidReading is returned if there are any. Rules
+// that can't be retrieved are not enforced, so that the cache being
+// unavailable doesn't stop the messages.
+func (n normalizer) validate(chanID string, msgs []mainflux.Message) ([]mainflux.Message, []mainflux.Message, error) {
+	if n.validations == nil {
+		return msgs, nil, nil
+	}
+
+	rules, err := n.validations.Validation(chanID)
+	if err != nil || (len(rules.Units) == 0 && len(rules.Ranges) == 0) {
+		return msgs, nil, nil
+	}
+
+	valid := []mainflux.Message{}
+	flagged := []mainflux.Message{}
+	for _, msg := range msgs {
+		if !complies(rules, msg) {
+			if !rules.Flag {
+				return nil, nil, ErrInvalidReading
+			}
+			flagged = append(flagged, msg)
+			continue
+		}
+		valid = append(valid, msg)
+	}
+
+	return valid, flagged, nil
+}
+
+// lenient reports whether the malformed SenML messages of the channel are
+// passed through as raw. The mode the normalizer is deployed with applies
+// if the channel's rules don't override it or can't be retrieved.
+func (n normalizer) lenient(chanID string) bool {
+	mode := n.mode
+	if n.validations != nil {
+		if rules, err := n.validations.Validation(chanID); err == nil && rules.Mode != "" {
+			mode = rules.Mode
+		}
+	}
+
+	return mode == things.LenientMode
+}
+
+// complies checks the message unit against the allowed ones and its numeric
+// values against the range of the unit. Records without the unit are always
+// allowed, since SenML doesn't require one.
+func complies(rules things.Validation, msg mainflux.Message) bool {
+	if msg.Unit != "" && len(rules.Units) > 0 && !contains(rules.Units, msg.Unit) {
+		return false
+	}
+
+	r, ok := rules.Ranges[msg.Unit]
+	if !ok {
+		return true
+	}
+
+	if v, ok := msg.Value.(*mainflux.Message_FloatValue); ok && !within(r, v.FloatValue) {
+		return false
+	}
+
+	if msg.ValueSum != nil && !within(r, msg.ValueSum.Value) {
+		return false
+	}
+
+	return true
+}
+
+func within(r things.Range, v float64) bool {
+	if r.Min != nil && v < *r.Min {
+		return false
+	}
+
+	if r.Max != nil && v > *r.Max {
+		return false
+	}
+
+	return true
+}
+
+func contains(units []string, unit string) bool {
+	for _, u := range units {
+		if u == unit {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,148 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package api
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/mainflux/mainflux/notifier"
+)
+
+func subscribeEndpoint(svc notifier.Service) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(subscribeReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		sub := notifier.Subscription{
+			Type:   req.Type,
+			Target: req.Target,
+			Events: req.Events,
+		}
+
+		saved, err := svc.Subscribe(req.key, sub)
+		if err != nil {
+			return nil, err
+		}
+
+		return subscribeRes{id: saved.ID}, nil
+	}
+}
+
+func viewEndpoint(svc notifier.Service) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(entityReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		sub, err := svc.ViewSubscription(req.key, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		return toSubscriptionRes(sub), nil
+	}
+}
+
+func listEndpoint(svc notifier.Service) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(listReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		subs, err := svc.ListSubscriptions(req.key)
+		if err != nil {
+			return nil, err
+		}
+
+		res := listRes{
+			Subscriptions: []subscriptionRes{},
+		}
+		for _, sub := range subs {
+			res.Subscriptions = append(res.Subscriptions, toSubscriptionRes(sub))
+		}
+
+		return res, nil
+	}
+}
+
+func removeEndpoint(svc notifier.Service) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(entityReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.Unsubscribe(req.key, req.id); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func listDeliveriesEndpoint(svc notifier.Service) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(listDeliveriesReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		page, err := svc.ListDeliveries(req.key, req.id, req.offset, req.limit)
+		if err != nil {
+			return nil, err
+		}
+
+		res := deliveriesPageRes{
+			Total:      page.Total,
+			Offset:     page.Offset,
+			Limit:      page.Limit,
+			Deliveries: []deliveryRes{},
+		}
+		for _, d := range page.Deliveries {
+			res.Deliveries = append(res.Deliveries, toDeliveryRes(d))
+		}
+
+		return res, nil
+	}
+}
+
+func toSubscriptionRes(sub notifier.Subscription) subscriptionRes {
+	return subscriptionRes{
+		ID:     sub.ID,
+		Type:   sub.Type,
+		Target: sub.Target,
+		Events: sub.Events,
+		Secret: sub.Secret,
+	}
+}
+
+func toDeliveryRes(d notifier.Delivery) deliveryRes {
+	res := deliveryRes{
+		ID:        d.ID,
+		Operation: d.Event.Operation,
+		EntityID:  d.Event.EntityID,
+		ChannelID: d.Event.ChannelID,
+		Occurred:  d.Event.Occurred,
+		Status:    d.Status,
+		Attempts:  d.Attempts,
+		Error:     d.Error,
+		Updated:   d.Updated,
+	}
+
+	if d.Status == notifier.Pending {
+		next := d.NextAttempt
+		res.NextAttempt = &next
+	}
+
+	return res
+}
@@ -0,0 +1,437 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package api_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/notifier"
+	"github.com/mainflux/mainflux/notifier/api"
+	"github.com/mainflux/mainflux/notifier/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	validToken   = "validToken"
+	invalidToken = "invalidToken"
+	email        = "user@example.com"
+	contentType  = "application/json"
+	hook         = "https://example.com/hook"
+	wrongID      = "123e4567-e89b-12d3-a456-999999999999"
+)
+
+var sub = notifier.Subscription{
+	Type:   notifier.Webhook,
+	Target: hook,
+	Events: []string{notifier.ThingCreate},
+}
+
+type testRequest struct {
+	client      *http.Client
+	method      string
+	url         string
+	contentType string
+	token       string
+	body        io.Reader
+}
+
+func (tr testRequest) make() (*http.Response, error) {
+	req, err := http.NewRequest(tr.method, tr.url, tr.body)
+	if err != nil {
+		return nil, err
+	}
+
+	if tr.token != "" {
+		req.Header.Set("Authorization", tr.token)
+	}
+
+	if tr.contentType != "" {
+		req.Header.Set("Content-Type", tr.contentType)
+	}
+
+	return tr.client.Do(req)
+}
+
+type subscriptionRes struct {
+	ID     string   `json:"id"`
+	Type   string   `json:"type"`
+	Target string   `json:"target"`
+	Events []string `json:"events"`
+	Secret string   `json:"secret,omitempty"`
+}
+
+func newService() notifier.Service {
+	users := mocks.NewUsersService(map[string]string{validToken: email})
+	subs := mocks.NewSubscriptionRepository()
+	deliveries := mocks.NewDeliveryRepository()
+	idp := mocks.NewIdentityProvider()
+	senders := map[string]notifier.Sender{notifier.Webhook: mocks.NewSender(nil)}
+
+	return notifier.New(users, subs, deliveries, idp, senders)
+}
+
+func newServer(svc notifier.Service) *httptest.Server {
+	return httptest.NewServer(api.MakeHandler(svc))
+}
+
+func toJSON(data interface{}) string {
+	jsonData, _ := json.Marshal(data)
+	return string(jsonData)
+}
+
+func TestSubscribe(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+
+	valid := toJSON(subscriptionRes{Type: sub.Type, Target: sub.Target, Events: sub.Events})
+	invalid := toJSON(subscriptionRes{Type: sub.Type, Target: "invalid", Events: sub.Events})
+	unsupported := toJSON(subscriptionRes{Type: notifier.Email, Target: email, Events: sub.Events})
+
+	cases := []struct {
+		desc        string
+		req         string
+		contentType string
+		token       string
+		status      int
+		location    string
+	}{
+		{
+			desc:        "subscribe with valid request",
+			req:         valid,
+			contentType: contentType,
+			token:       validToken,
+			status:      http.StatusCreated,
+			location:    "/subscriptions/123e4567-e89b-12d3-a456-000000000001",
+		},
+		{
+			desc:        "subscribe with invalid token",
+			req:         valid,
+			contentType: contentType,
+			token:       invalidToken,
+			status:      http.StatusForbidden,
+		},
+		{
+			desc:        "subscribe with empty token",
+			req:         valid,
+			contentType: contentType,
+			token:       "",
+			status:      http.StatusForbidden,
+		},
+		{
+			desc:        "subscribe with invalid target",
+			req:         invalid,
+			contentType: contentType,
+			token:       validToken,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "subscribe with unsupported type",
+			req:         unsupported,
+			contentType: contentType,
+			token:       validToken,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "subscribe with invalid request format",
+			req:         "}",
+			contentType: contentType,
+			token:       validToken,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "subscribe without content type",
+			req:         valid,
+			contentType: "",
+			token:       validToken,
+			status:      http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/subscriptions", ts.URL),
+			contentType: tc.contentType,
+			token:       tc.token,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		location := res.Header.Get("Location")
+		assert.Equal(t, tc.location, location, fmt.Sprintf("%s: expected location %s got %s", tc.desc, tc.location, location))
+	}
+}
+
+func TestView(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+
+	saved, err := svc.Subscribe(validToken, sub)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	data := toJSON(subscriptionRes{ID: saved.ID, Type: saved.Type, Target: saved.Target, Events: saved.Events, Secret: saved.Secret})
+
+	cases := []struct {
+		desc   string
+		id     string
+		token  string
+		status int
+		res    string
+	}{
+		{
+			desc:   "view existing subscription",
+			id:     saved.ID,
+			token:  validToken,
+			status: http.StatusOK,
+			res:    data,
+		},
+		{
+			desc:   "view subscription with invalid token",
+			id:     saved.ID,
+			token:  invalidToken,
+			status: http.StatusForbidden,
+			res:    "",
+		},
+		{
+			desc:   "view non-existing subscription",
+			id:     wrongID,
+			token:  validToken,
+			status: http.StatusNotFound,
+			res:    "",
+		},
+		{
+			desc:   "view subscription with malformed id",
+			id:     "unknown",
+			token:  validToken,
+			status: http.StatusBadRequest,
+			res:    "",
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/subscriptions/%s", ts.URL, tc.id),
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		body, err := ioutil.ReadAll(res.Body)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.res, strings.Trim(string(body), "\n"), fmt.Sprintf("%s: expected body %s got %s", tc.desc, tc.res, body))
+	}
+}
+
+func TestList(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+
+	n := 3
+	for i := 0; i < n; i++ {
+		_, err := svc.Subscribe(validToken, sub)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	cases := []struct {
+		desc   string
+		token  string
+		status int
+		size   int
+	}{
+		{
+			desc:   "list subscriptions",
+			token:  validToken,
+			status: http.StatusOK,
+			size:   n,
+		},
+		{
+			desc:   "list subscriptions with invalid token",
+			token:  invalidToken,
+			status: http.StatusForbidden,
+			size:   0,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/subscriptions", ts.URL),
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+
+		var body struct {
+			Subscriptions []subscriptionRes `json:"subscriptions"`
+		}
+		json.NewDecoder(res.Body).Decode(&body)
+		assert.Len(t, body.Subscriptions, tc.size, fmt.Sprintf("%s: expected %d subscriptions got %d", tc.desc, tc.size, len(body.Subscriptions)))
+	}
+}
+
+func TestListDeliveries(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+
+	saved, err := svc.Subscribe(validToken, sub)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	n := 3
+	for i := 0; i < n; i++ {
+		event := notifier.Event{Operation: sub.Events[0], EntityID: fmt.Sprintf("%d", i), Owner: email, Occurred: time.Now()}
+		err := svc.Notify(event)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	cases := []struct {
+		desc   string
+		id     string
+		token  string
+		query  string
+		status int
+		size   int
+	}{
+		{
+			desc:   "list deliveries",
+			id:     saved.ID,
+			token:  validToken,
+			query:  "",
+			status: http.StatusOK,
+			size:   n,
+		},
+		{
+			desc:   "list deliveries with offset and limit",
+			id:     saved.ID,
+			token:  validToken,
+			query:  "?offset=1&limit=1",
+			status: http.StatusOK,
+			size:   1,
+		},
+		{
+			desc:   "list deliveries with invalid limit",
+			id:     saved.ID,
+			token:  validToken,
+			query:  "?limit=0",
+			status: http.StatusBadRequest,
+			size:   0,
+		},
+		{
+			desc:   "list deliveries with invalid offset",
+			id:     saved.ID,
+			token:  validToken,
+			query:  "?offset=invalid",
+			status: http.StatusBadRequest,
+			size:   0,
+		},
+		{
+			desc:   "list deliveries with invalid token",
+			id:     saved.ID,
+			token:  invalidToken,
+			query:  "",
+			status: http.StatusForbidden,
+			size:   0,
+		},
+		{
+			desc:   "list deliveries of non-existing subscription",
+			id:     wrongID,
+			token:  validToken,
+			query:  "",
+			status: http.StatusNotFound,
+			size:   0,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/subscriptions/%s/deliveries%s", ts.URL, tc.id, tc.query),
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+
+		var body struct {
+			Deliveries []struct {
+				Status string `json:"status"`
+			} `json:"deliveries"`
+		}
+		json.NewDecoder(res.Body).Decode(&body)
+		assert.Len(t, body.Deliveries, tc.size, fmt.Sprintf("%s: expected %d deliveries got %d", tc.desc, tc.size, len(body.Deliveries)))
+		for _, d := range body.Deliveries {
+			assert.Equal(t, notifier.Delivered, d.Status, fmt.Sprintf("%s: expected status %s got %s", tc.desc, notifier.Delivered, d.Status))
+		}
+	}
+}
+
+func TestRemove(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+
+	saved, err := svc.Subscribe(validToken, sub)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		id     string
+		token  string
+		status int
+	}{
+		{
+			desc:   "remove subscription with invalid token",
+			id:     saved.ID,
+			token:  invalidToken,
+			status: http.StatusForbidden,
+		},
+		{
+			desc:   "remove existing subscription",
+			id:     saved.ID,
+			token:  validToken,
+			status: http.StatusNoContent,
+		},
+		{
+			desc:   "remove removed subscription",
+			id:     saved.ID,
+			token:  validToken,
+			status: http.StatusNoContent,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodDelete,
+			url:    fmt.Sprintf("%s/subscriptions/%s", ts.URL, tc.id),
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
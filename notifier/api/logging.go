@@ -0,0 +1,134 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// +build !test
+
+package api
+
+import (
+	"fmt"
+	"time"
+
+	log "github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/notifier"
+)
+
+var _ notifier.Service = (*loggingMiddleware)(nil)
+
+type loggingMiddleware struct {
+	logger log.Logger
+	svc    notifier.Service
+}
+
+// NewLoggingMiddleware adds logging facilities to the core service.
+func NewLoggingMiddleware(svc notifier.Service, logger log.Logger) notifier.Service {
+	return &loggingMiddleware{logger, svc}
+}
+
+func (lm *loggingMiddleware) Subscribe(key string, sub notifier.Subscription) (saved notifier.Subscription, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method subscribe for key %s and subscription %s took %s to complete", key, saved.ID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Subscribe(key, sub)
+}
+
+func (lm *loggingMiddleware) ViewSubscription(key, id string) (sub notifier.Subscription, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method view_subscription for key %s and subscription %s took %s to complete", key, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ViewSubscription(key, id)
+}
+
+func (lm *loggingMiddleware) ListSubscriptions(key string) (subs []notifier.Subscription, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_subscriptions for key %s took %s to complete", key, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListSubscriptions(key)
+}
+
+func (lm *loggingMiddleware) Unsubscribe(key, id string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method unsubscribe for key %s and subscription %s took %s to complete", key, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Unsubscribe(key, id)
+}
+
+func (lm *loggingMiddleware) ListDeliveries(key, id string, offset, limit uint64) (page notifier.DeliveriesPage, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_deliveries for key %s and subscription %s took %s to complete", key, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListDeliveries(key, id, offset, limit)
+}
+
+func (lm *loggingMiddleware) Notify(event notifier.Event) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method notify for event %s of entity %s took %s to complete", event.Operation, event.EntityID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Notify(event)
+}
+
+func (lm *loggingMiddleware) RetryDeliveries(now time.Time) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method retry_deliveries took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RetryDeliveries(now)
+}
+
+func (lm *loggingMiddleware) RemoveUserHandler(owner string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method remove_user_handler for user %s took %s to complete", owner, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RemoveUserHandler(owner)
+}
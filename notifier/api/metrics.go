@@ -0,0 +1,107 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// +build !test
+
+package api
+
+import (
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/mainflux/mainflux/notifier"
+)
+
+var _ notifier.Service = (*metricsMiddleware)(nil)
+
+type metricsMiddleware struct {
+	counter metrics.Counter
+	latency metrics.Histogram
+	svc     notifier.Service
+}
+
+// MetricsMiddleware instruments core service by tracking request count and
+// latency.
+func MetricsMiddleware(svc notifier.Service, counter metrics.Counter, latency metrics.Histogram) notifier.Service {
+	return &metricsMiddleware{
+		counter: counter,
+		latency: latency,
+		svc:     svc,
+	}
+}
+
+func (mm *metricsMiddleware) Subscribe(key string, sub notifier.Subscription) (notifier.Subscription, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "subscribe").Add(1)
+		mm.latency.With("method", "subscribe").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.Subscribe(key, sub)
+}
+
+func (mm *metricsMiddleware) ViewSubscription(key, id string) (notifier.Subscription, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "view_subscription").Add(1)
+		mm.latency.With("method", "view_subscription").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.ViewSubscription(key, id)
+}
+
+func (mm *metricsMiddleware) ListSubscriptions(key string) ([]notifier.Subscription, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "list_subscriptions").Add(1)
+		mm.latency.With("method", "list_subscriptions").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.ListSubscriptions(key)
+}
+
+func (mm *metricsMiddleware) Unsubscribe(key, id string) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "unsubscribe").Add(1)
+		mm.latency.With("method", "unsubscribe").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.Unsubscribe(key, id)
+}
+
+func (mm *metricsMiddleware) ListDeliveries(key, id string, offset, limit uint64) (notifier.DeliveriesPage, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "list_deliveries").Add(1)
+		mm.latency.With("method", "list_deliveries").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.ListDeliveries(key, id, offset, limit)
+}
+
+func (mm *metricsMiddleware) Notify(event notifier.Event) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "notify").Add(1)
+		mm.latency.With("method", "notify").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.Notify(event)
+}
+
+func (mm *metricsMiddleware) RetryDeliveries(now time.Time) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "retry_deliveries").Add(1)
+		mm.latency.With("method", "retry_deliveries").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.RetryDeliveries(now)
+}
+
+func (mm *metricsMiddleware) RemoveUserHandler(owner string) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "remove_user_handler").Add(1)
+		mm.latency.With("method", "remove_user_handler").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.RemoveUserHandler(owner)
+}
@@ -0,0 +1,83 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package api
+
+import "github.com/mainflux/mainflux/notifier"
+
+const maxLimitSize = 100
+
+type apiReq interface {
+	validate() error
+}
+
+type subscribeReq struct {
+	key    string
+	Type   string   `json:"type"`
+	Target string   `json:"target"`
+	Events []string `json:"events"`
+}
+
+func (req subscribeReq) validate() error {
+	if req.key == "" {
+		return notifier.ErrUnauthorizedAccess
+	}
+
+	if req.Type == "" || req.Target == "" || len(req.Events) == 0 {
+		return notifier.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type entityReq struct {
+	key string
+	id  string
+}
+
+func (req entityReq) validate() error {
+	if req.key == "" {
+		return notifier.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" {
+		return notifier.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type listReq struct {
+	key string
+}
+
+func (req listReq) validate() error {
+	if req.key == "" {
+		return notifier.ErrUnauthorizedAccess
+	}
+
+	return nil
+}
+
+type listDeliveriesReq struct {
+	key    string
+	id     string
+	offset uint64
+	limit  uint64
+}
+
+func (req listDeliveriesReq) validate() error {
+	if req.key == "" {
+		return notifier.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" || req.limit == 0 || req.limit > maxLimitSize {
+		return notifier.ErrMalformedEntity
+	}
+
+	return nil
+}
@@ -0,0 +1,124 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/mainflux/mainflux"
+)
+
+var (
+	_ mainflux.Response = (*subscribeRes)(nil)
+	_ mainflux.Response = (*subscriptionRes)(nil)
+	_ mainflux.Response = (*listRes)(nil)
+	_ mainflux.Response = (*removeRes)(nil)
+	_ mainflux.Response = (*deliveriesPageRes)(nil)
+)
+
+type subscribeRes struct {
+	id string
+}
+
+func (res subscribeRes) Code() int {
+	return http.StatusCreated
+}
+
+func (res subscribeRes) Headers() map[string]string {
+	return map[string]string{
+		"Location": fmt.Sprintf("/subscriptions/%s", res.id),
+	}
+}
+
+func (res subscribeRes) Empty() bool {
+	return true
+}
+
+type subscriptionRes struct {
+	ID     string   `json:"id"`
+	Type   string   `json:"type"`
+	Target string   `json:"target"`
+	Events []string `json:"events"`
+	Secret string   `json:"secret,omitempty"`
+}
+
+func (res subscriptionRes) Code() int {
+	return http.StatusOK
+}
+
+func (res subscriptionRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res subscriptionRes) Empty() bool {
+	return false
+}
+
+type listRes struct {
+	Subscriptions []subscriptionRes `json:"subscriptions"`
+}
+
+func (res listRes) Code() int {
+	return http.StatusOK
+}
+
+func (res listRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res listRes) Empty() bool {
+	return false
+}
+
+type removeRes struct{}
+
+func (res removeRes) Code() int {
+	return http.StatusNoContent
+}
+
+func (res removeRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res removeRes) Empty() bool {
+	return true
+}
+
+type deliveryRes struct {
+	ID          string     `json:"id"`
+	Operation   string     `json:"operation"`
+	EntityID    string     `json:"entity_id"`
+	ChannelID   string     `json:"channel_id,omitempty"`
+	Occurred    time.Time  `json:"occurred"`
+	Status      string     `json:"status"`
+	Attempts    int        `json:"attempts"`
+	Error       string     `json:"error,omitempty"`
+	Updated     time.Time  `json:"updated_at"`
+	NextAttempt *time.Time `json:"next_attempt_at,omitempty"`
+}
+
+type deliveriesPageRes struct {
+	Total      uint64        `json:"total"`
+	Offset     uint64        `json:"offset"`
+	Limit      uint64        `json:"limit"`
+	Deliveries []deliveryRes `json:"deliveries"`
+}
+
+func (res deliveriesPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res deliveriesPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res deliveriesPageRes) Empty() bool {
+	return false
+}
@@ -0,0 +1,65 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package notifier
+
+import "time"
+
+// Delivery statuses.
+const (
+	// Pending marks the delivery that failed, but is to be attempted again.
+	Pending = "pending"
+
+	// Delivered marks the delivery that succeeded.
+	Delivered = "delivered"
+
+	// Failed marks the delivery that failed and ran out of attempts.
+	Failed = "failed"
+)
+
+// Delivery represents the notification of the event sent to the
+// subscription target, along with the outcome of its latest attempt.
+type Delivery struct {
+	ID             string
+	SubscriptionID string
+	Owner          string
+	Event          Event
+	Status         string
+	Attempts       int
+	Error          string
+	Updated        time.Time
+	NextAttempt    time.Time
+}
+
+// DeliveriesPage contains a page of the subscription deliveries.
+type DeliveriesPage struct {
+	Total      uint64
+	Offset     uint64
+	Limit      uint64
+	Deliveries []Delivery
+}
+
+// DeliveryRepository specifies a delivery log persistence API.
+type DeliveryRepository interface {
+	// Save persists the delivery.
+	Save(Delivery) error
+
+	// Update updates the status, attempts, error and the time of the next
+	// attempt of the delivery.
+	Update(Delivery) error
+
+	// RetrieveAll retrieves the page of the deliveries of the subscription
+	// identified with the provided ID, that is owned by the specified user,
+	// starting with the most recent one.
+	RetrieveAll(string, string, uint64, uint64) (DeliveriesPage, error)
+
+	// RetrieveDue retrieves at most the given number of the pending
+	// deliveries whose next attempt is due by the provided time. Retrieved
+	// deliveries are postponed, so that they aren't retrieved again while
+	// being attempted.
+	RetrieveDue(time.Time, uint64) ([]Delivery, error)
+}
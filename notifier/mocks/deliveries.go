@@ -0,0 +1,112 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package mocks
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux/notifier"
+)
+
+var _ notifier.DeliveryRepository = (*deliveryRepositoryMock)(nil)
+
+type deliveryRepositoryMock struct {
+	mu         sync.Mutex
+	deliveries map[string]notifier.Delivery
+	order      []string
+}
+
+// NewDeliveryRepository creates in-memory delivery repository.
+func NewDeliveryRepository() notifier.DeliveryRepository {
+	return &deliveryRepositoryMock{
+		deliveries: make(map[string]notifier.Delivery),
+	}
+}
+
+func (drm *deliveryRepositoryMock) Save(d notifier.Delivery) error {
+	drm.mu.Lock()
+	defer drm.mu.Unlock()
+
+	drm.deliveries[d.ID] = d
+	drm.order = append(drm.order, d.ID)
+	return nil
+}
+
+func (drm *deliveryRepositoryMock) Update(d notifier.Delivery) error {
+	drm.mu.Lock()
+	defer drm.mu.Unlock()
+
+	saved, ok := drm.deliveries[d.ID]
+	if !ok {
+		return notifier.ErrNotFound
+	}
+
+	saved.Status = d.Status
+	saved.Attempts = d.Attempts
+	saved.Error = d.Error
+	saved.Updated = d.Updated
+	saved.NextAttempt = d.NextAttempt
+	drm.deliveries[d.ID] = saved
+	return nil
+}
+
+func (drm *deliveryRepositoryMock) RetrieveAll(owner, subID string, offset, limit uint64) (notifier.DeliveriesPage, error) {
+	drm.mu.Lock()
+	defer drm.mu.Unlock()
+
+	items := []notifier.Delivery{}
+	for i := len(drm.order) - 1; i >= 0; i-- {
+		d := drm.deliveries[drm.order[i]]
+		if d.Owner == owner && d.SubscriptionID == subID {
+			items = append(items, d)
+		}
+	}
+
+	page := notifier.DeliveriesPage{
+		Total:      uint64(len(items)),
+		Offset:     offset,
+		Limit:      limit,
+		Deliveries: []notifier.Delivery{},
+	}
+
+	if offset >= uint64(len(items)) {
+		return page, nil
+	}
+
+	end := offset + limit
+	if end > uint64(len(items)) {
+		end = uint64(len(items))
+	}
+	page.Deliveries = items[offset:end]
+
+	return page, nil
+}
+
+func (drm *deliveryRepositoryMock) RetrieveDue(now time.Time, limit uint64) ([]notifier.Delivery, error) {
+	drm.mu.Lock()
+	defer drm.mu.Unlock()
+
+	due := []notifier.Delivery{}
+	for _, d := range drm.deliveries {
+		if d.Status == notifier.Pending && !d.NextAttempt.After(now) {
+			due = append(due, d)
+		}
+	}
+
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].NextAttempt.Before(due[j].NextAttempt)
+	})
+
+	if uint64(len(due)) > limit {
+		due = due[:limit]
+	}
+
+	return due, nil
+}
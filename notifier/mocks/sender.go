@@ -0,0 +1,70 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package mocks
+
+import (
+	"sync"
+
+	"github.com/mainflux/mainflux/notifier"
+)
+
+var _ notifier.Sender = (*SenderMock)(nil)
+
+// SenderMock records the notifications instead of delivering them.
+type SenderMock struct {
+	mu   sync.Mutex
+	sent map[string][]notifier.Event
+	fail map[string]error
+}
+
+// NewSender creates the sender mock. Sending to the targets present in the
+// provided map fails with the corresponding error.
+func NewSender(fail map[string]error) *SenderMock {
+	if fail == nil {
+		fail = make(map[string]error)
+	}
+
+	return &SenderMock{
+		sent: make(map[string][]notifier.Event),
+		fail: fail,
+	}
+}
+
+// Send records the event sent to the target.
+func (sm *SenderMock) Send(sub notifier.Subscription, d notifier.Delivery) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	if err, ok := sm.fail[sub.Target]; ok {
+		return err
+	}
+
+	sm.sent[sub.Target] = append(sm.sent[sub.Target], d.Event)
+	return nil
+}
+
+// Fail changes the error the sender fails to deliver to the target with.
+// Nil error makes the deliveries to the target succeed.
+func (sm *SenderMock) Fail(target string, err error) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	if err == nil {
+		delete(sm.fail, target)
+		return
+	}
+	sm.fail[target] = err
+}
+
+// Sent returns the events sent to the target.
+func (sm *SenderMock) Sent(target string) []notifier.Event {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	return sm.sent[target]
+}
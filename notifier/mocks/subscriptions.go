@@ -0,0 +1,91 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package mocks
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/mainflux/mainflux/notifier"
+)
+
+var _ notifier.SubscriptionRepository = (*subscriptionRepositoryMock)(nil)
+
+type subscriptionRepositoryMock struct {
+	mu   sync.Mutex
+	subs map[string]notifier.Subscription
+}
+
+// NewSubscriptionRepository creates in-memory subscription repository.
+func NewSubscriptionRepository() notifier.SubscriptionRepository {
+	return &subscriptionRepositoryMock{
+		subs: make(map[string]notifier.Subscription),
+	}
+}
+
+func (srm *subscriptionRepositoryMock) Save(sub notifier.Subscription) (string, error) {
+	srm.mu.Lock()
+	defer srm.mu.Unlock()
+
+	srm.subs[sub.ID] = sub
+	return sub.ID, nil
+}
+
+func (srm *subscriptionRepositoryMock) RetrieveByID(owner, id string) (notifier.Subscription, error) {
+	srm.mu.Lock()
+	defer srm.mu.Unlock()
+
+	sub, ok := srm.subs[id]
+	if !ok || sub.Owner != owner {
+		return notifier.Subscription{}, notifier.ErrNotFound
+	}
+
+	return sub, nil
+}
+
+func (srm *subscriptionRepositoryMock) RetrieveAll(owner string) ([]notifier.Subscription, error) {
+	srm.mu.Lock()
+	defer srm.mu.Unlock()
+
+	subs := []notifier.Subscription{}
+	for _, sub := range srm.subs {
+		if sub.Owner == owner {
+			subs = append(subs, sub)
+		}
+	}
+
+	sort.Slice(subs, func(i, j int) bool {
+		return subs[i].ID < subs[j].ID
+	})
+
+	return subs, nil
+}
+
+func (srm *subscriptionRepositoryMock) Remove(owner, id string) error {
+	srm.mu.Lock()
+	defer srm.mu.Unlock()
+
+	if sub, ok := srm.subs[id]; ok && sub.Owner == owner {
+		delete(srm.subs, id)
+	}
+
+	return nil
+}
+
+func (srm *subscriptionRepositoryMock) RemoveAll(owner string) error {
+	srm.mu.Lock()
+	defer srm.mu.Unlock()
+
+	for id, sub := range srm.subs {
+		if sub.Owner == owner {
+			delete(srm.subs, id)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,181 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package postgres
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/mainflux/mainflux/notifier"
+)
+
+// claimLease is the period the retrieved due deliveries are postponed by,
+// so that the other service instances don't attempt them meanwhile.
+const claimLease = 5 * time.Minute
+
+var _ notifier.DeliveryRepository = (*deliveryRepository)(nil)
+
+type deliveryRepository struct {
+	db *sqlx.DB
+}
+
+// NewDeliveryRepository instantiates a PostgreSQL implementation of the
+// delivery repository.
+func NewDeliveryRepository(db *sqlx.DB) notifier.DeliveryRepository {
+	return &deliveryRepository{db: db}
+}
+
+func (dr deliveryRepository) Save(d notifier.Delivery) error {
+	q := `INSERT INTO deliveries (id, subscription_id, owner, operation, entity_id, channel_id, occurred, status, attempts, error, updated, next_attempt)
+	      VALUES (:id, :subscription_id, :owner, :operation, :entity_id, :channel_id, :occurred, :status, :attempts, :error, :updated, :next_attempt)`
+
+	if _, err := dr.db.NamedExec(q, toDBDelivery(d)); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == fkViolationErr {
+			return notifier.ErrNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (dr deliveryRepository) Update(d notifier.Delivery) error {
+	q := `UPDATE deliveries SET status = :status, attempts = :attempts, error = :error, updated = :updated, next_attempt = :next_attempt WHERE id = :id`
+
+	res, err := dr.db.NamedExec(q, toDBDelivery(d))
+	if err != nil {
+		return err
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if cnt == 0 {
+		return notifier.ErrNotFound
+	}
+
+	return nil
+}
+
+func (dr deliveryRepository) RetrieveAll(owner, subID string, offset, limit uint64) (notifier.DeliveriesPage, error) {
+	q := `SELECT id, subscription_id, owner, operation, entity_id, channel_id, occurred, status, attempts, error, updated, next_attempt
+	      FROM deliveries WHERE subscription_id = $1 AND owner = $2 ORDER BY occurred DESC, id LIMIT $3 OFFSET $4`
+
+	rows, err := dr.db.Queryx(q, subID, owner, limit, offset)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Message == uuidErr {
+			return notifier.DeliveriesPage{}, notifier.ErrNotFound
+		}
+		return notifier.DeliveriesPage{}, err
+	}
+	defer rows.Close()
+
+	items, err := scanDeliveries(rows)
+	if err != nil {
+		return notifier.DeliveriesPage{}, err
+	}
+
+	cq := `SELECT COUNT(*) FROM deliveries WHERE subscription_id = $1 AND owner = $2`
+
+	var total uint64
+	if err := dr.db.Get(&total, cq, subID, owner); err != nil {
+		return notifier.DeliveriesPage{}, err
+	}
+
+	return notifier.DeliveriesPage{
+		Total:      total,
+		Offset:     offset,
+		Limit:      limit,
+		Deliveries: items,
+	}, nil
+}
+
+func (dr deliveryRepository) RetrieveDue(now time.Time, limit uint64) ([]notifier.Delivery, error) {
+	q := `UPDATE deliveries SET next_attempt = $2
+	      WHERE id IN (
+	          SELECT id FROM deliveries WHERE status = $3 AND next_attempt <= $1
+	          ORDER BY next_attempt LIMIT $4 FOR UPDATE SKIP LOCKED
+	      )
+	      RETURNING id, subscription_id, owner, operation, entity_id, channel_id, occurred, status, attempts, error, updated, next_attempt`
+
+	rows, err := dr.db.Queryx(q, now, now.Add(claimLease), notifier.Pending, limit)
+	if err != nil {
+		return []notifier.Delivery{}, err
+	}
+	defer rows.Close()
+
+	return scanDeliveries(rows)
+}
+
+func scanDeliveries(rows *sqlx.Rows) ([]notifier.Delivery, error) {
+	items := []notifier.Delivery{}
+	for rows.Next() {
+		dbd := dbDelivery{}
+		if err := rows.StructScan(&dbd); err != nil {
+			return []notifier.Delivery{}, err
+		}
+		items = append(items, toDelivery(dbd))
+	}
+
+	return items, nil
+}
+
+type dbDelivery struct {
+	ID             string    `db:"id"`
+	SubscriptionID string    `db:"subscription_id"`
+	Owner          string    `db:"owner"`
+	Operation      string    `db:"operation"`
+	EntityID       string    `db:"entity_id"`
+	ChannelID      string    `db:"channel_id"`
+	Occurred       time.Time `db:"occurred"`
+	Status         string    `db:"status"`
+	Attempts       int       `db:"attempts"`
+	Error          string    `db:"error"`
+	Updated        time.Time `db:"updated"`
+	NextAttempt    time.Time `db:"next_attempt"`
+}
+
+func toDBDelivery(d notifier.Delivery) dbDelivery {
+	return dbDelivery{
+		ID:             d.ID,
+		SubscriptionID: d.SubscriptionID,
+		Owner:          d.Owner,
+		Operation:      d.Event.Operation,
+		EntityID:       d.Event.EntityID,
+		ChannelID:      d.Event.ChannelID,
+		Occurred:       d.Event.Occurred,
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		Error:          d.Error,
+		Updated:        d.Updated,
+		NextAttempt:    d.NextAttempt,
+	}
+}
+
+func toDelivery(dbd dbDelivery) notifier.Delivery {
+	return notifier.Delivery{
+		ID:             dbd.ID,
+		SubscriptionID: dbd.SubscriptionID,
+		Owner:          dbd.Owner,
+		Event: notifier.Event{
+			Operation: dbd.Operation,
+			EntityID:  dbd.EntityID,
+			ChannelID: dbd.ChannelID,
+			Owner:     dbd.Owner,
+			Occurred:  dbd.Occurred,
+		},
+		Status:      dbd.Status,
+		Attempts:    dbd.Attempts,
+		Error:       dbd.Error,
+		Updated:     dbd.Updated,
+		NextAttempt: dbd.NextAttempt,
+	}
+}
@@ -0,0 +1,153 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package postgres_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/mainflux/mainflux/notifier"
+	"github.com/mainflux/mainflux/notifier/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newDelivery(t *testing.T, sub notifier.Subscription, status string, next time.Time) notifier.Delivery {
+	id, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	now := time.Now().Round(time.Second)
+	return notifier.Delivery{
+		ID:             id.String(),
+		SubscriptionID: sub.ID,
+		Owner:          sub.Owner,
+		Event: notifier.Event{
+			Operation: notifier.ThingCreate,
+			EntityID:  "thing",
+			Owner:     sub.Owner,
+			Occurred:  now,
+		},
+		Status:      status,
+		Attempts:    1,
+		Updated:     now,
+		NextAttempt: next,
+	}
+}
+
+func TestDeliverySave(t *testing.T) {
+	subs := postgres.NewSubscriptionRepository(db)
+	repo := postgres.NewDeliveryRepository(db)
+
+	sub := newSubscription(t, "delivery-save@example.com")
+	_, err := subs.Save(sub)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := []struct {
+		desc     string
+		delivery notifier.Delivery
+		err      error
+	}{
+		{
+			desc:     "save delivery of existing subscription",
+			delivery: newDelivery(t, sub, notifier.Delivered, time.Time{}),
+			err:      nil,
+		},
+		{
+			desc:     "save delivery of non-existing subscription",
+			delivery: newDelivery(t, newSubscription(t, owner), notifier.Delivered, time.Time{}),
+			err:      notifier.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := repo.Save(tc.delivery)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestDeliveryRetrieveAll(t *testing.T) {
+	subs := postgres.NewSubscriptionRepository(db)
+	repo := postgres.NewDeliveryRepository(db)
+
+	sub := newSubscription(t, "delivery-list@example.com")
+	_, err := subs.Save(sub)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	n := uint64(5)
+	for i := uint64(0); i < n; i++ {
+		err := repo.Save(newDelivery(t, sub, notifier.Delivered, time.Time{}))
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	}
+
+	cases := map[string]struct {
+		owner  string
+		offset uint64
+		limit  uint64
+		size   uint64
+		total  uint64
+	}{
+		"retrieve all deliveries":              {sub.Owner, 0, n, n, n},
+		"retrieve deliveries page":             {sub.Owner, 1, 2, 2, n},
+		"retrieve deliveries out of range":     {sub.Owner, n, n, 0, n},
+		"retrieve deliveries of another owner": {owner, 0, n, 0, 0},
+	}
+
+	for desc, tc := range cases {
+		page, err := repo.RetrieveAll(tc.owner, sub.ID, tc.offset, tc.limit)
+		assert.Nil(t, err, fmt.Sprintf("%s: got unexpected error: %s", desc, err))
+		size := uint64(len(page.Deliveries))
+		assert.Equal(t, tc.size, size, fmt.Sprintf("%s: expected %d got %d\n", desc, tc.size, size))
+		assert.Equal(t, tc.total, page.Total, fmt.Sprintf("%s: expected total %d got %d\n", desc, tc.total, page.Total))
+	}
+}
+
+func TestDeliveryRetrieveDue(t *testing.T) {
+	subs := postgres.NewSubscriptionRepository(db)
+	repo := postgres.NewDeliveryRepository(db)
+
+	sub := newSubscription(t, "delivery-due@example.com")
+	_, err := subs.Save(sub)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	now := time.Now().Round(time.Second)
+	due := newDelivery(t, sub, notifier.Pending, now.Add(-time.Minute))
+	later := newDelivery(t, sub, notifier.Pending, now.Add(time.Hour))
+	delivered := newDelivery(t, sub, notifier.Delivered, now.Add(-time.Minute))
+	for _, d := range []notifier.Delivery{due, later, delivered} {
+		err := repo.Save(d)
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	}
+
+	retrieved, err := repo.RetrieveDue(now, 10)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	require.Len(t, retrieved, 1, fmt.Sprintf("expected single due delivery got %d", len(retrieved)))
+	assert.Equal(t, due.ID, retrieved[0].ID, fmt.Sprintf("expected delivery %s got %s", due.ID, retrieved[0].ID))
+
+	retrieved, err = repo.RetrieveDue(now, 10)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.Len(t, retrieved, 0, "expected retrieved delivery to be postponed")
+
+	due.Status = notifier.Delivered
+	due.Attempts = 2
+	err = repo.Update(due)
+	assert.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	page, err := repo.RetrieveAll(sub.Owner, sub.ID, 0, 10)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	for _, d := range page.Deliveries {
+		if d.ID == due.ID {
+			assert.Equal(t, notifier.Delivered, d.Status, fmt.Sprintf("expected status %s got %s", notifier.Delivered, d.Status))
+			assert.Equal(t, 2, d.Attempts, fmt.Sprintf("expected 2 attempts got %d", d.Attempts))
+		}
+	}
+
+	err = repo.Update(newDelivery(t, sub, notifier.Delivered, time.Time{}))
+	assert.Equal(t, notifier.ErrNotFound, err, fmt.Sprintf("expected %s got %s", notifier.ErrNotFound, err))
+}
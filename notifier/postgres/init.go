@@ -0,0 +1,111 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq" // required for SQL access
+	mfpostgres "github.com/mainflux/mainflux/postgres"
+	migrate "github.com/rubenv/sql-migrate"
+)
+
+// Config defines the options that are used when connecting to a PostgreSQL instance
+type Config struct {
+	Host        string
+	Port        string
+	User        string
+	Pass        string
+	Name        string
+	SSLMode     string
+	SSLCert     string
+	SSLKey      string
+	SSLRootCert string
+	Target      string
+	SyncCommit  string
+}
+
+// Connect creates a connection to the PostgreSQL instance and applies any
+// unapplied database migrations. A non-nil error is returned to indicate
+// failure.
+func Connect(cfg Config) (*sqlx.DB, error) {
+	url := fmt.Sprintf("user=%s dbname=%s password=%s sslmode=%s sslcert=%s sslkey=%s sslrootcert=%s", cfg.User, cfg.Name, cfg.Pass, cfg.SSLMode, cfg.SSLCert, cfg.SSLKey, cfg.SSLRootCert)
+
+	db, err := mfpostgres.Open(mfpostgres.Config{
+		Hosts:      cfg.Host,
+		Port:       cfg.Port,
+		DSN:        url,
+		Target:     cfg.Target,
+		SyncCommit: cfg.SyncCommit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := migrateDB(db); err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+func migrateDB(db *sqlx.DB) error {
+	migrations := &migrate.MemoryMigrationSource{
+		Migrations: []*migrate.Migration{
+			{
+				Id: "subscriptions_1",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS subscriptions (
+						id     UUID,
+						owner  VARCHAR(254) NOT NULL,
+						type   VARCHAR(32) NOT NULL,
+						target TEXT NOT NULL,
+						events TEXT[] NOT NULL,
+						PRIMARY KEY (id, owner)
+					)`,
+					`CREATE INDEX IF NOT EXISTS subscriptions_owner_idx ON subscriptions (owner)`,
+				},
+				Down: []string{
+					"DROP TABLE subscriptions",
+				},
+			},
+			{
+				Id: "subscriptions_2",
+				Up: []string{
+					`ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS secret VARCHAR(64) NOT NULL DEFAULT ''`,
+					`CREATE TABLE IF NOT EXISTS deliveries (
+						id              UUID PRIMARY KEY,
+						subscription_id UUID NOT NULL,
+						owner           VARCHAR(254) NOT NULL,
+						operation       VARCHAR(32) NOT NULL,
+						entity_id       VARCHAR(254) NOT NULL,
+						channel_id      VARCHAR(254) NOT NULL DEFAULT '',
+						occurred        TIMESTAMP NOT NULL,
+						status          VARCHAR(16) NOT NULL,
+						attempts        INT NOT NULL,
+						error           TEXT NOT NULL DEFAULT '',
+						updated         TIMESTAMP NOT NULL,
+						next_attempt    TIMESTAMP NOT NULL,
+						FOREIGN KEY (subscription_id, owner) REFERENCES subscriptions (id, owner) ON DELETE CASCADE
+					)`,
+					`CREATE INDEX IF NOT EXISTS deliveries_subscription_idx ON deliveries (subscription_id, owner, occurred)`,
+					`CREATE INDEX IF NOT EXISTS deliveries_pending_idx ON deliveries (next_attempt) WHERE status = 'pending'`,
+				},
+				Down: []string{
+					"DROP TABLE deliveries",
+					"ALTER TABLE subscriptions DROP COLUMN secret",
+				},
+			},
+		},
+	}
+
+	_, err := migrate.Exec(db.DB, "postgres", migrations, migrate.Up)
+
+	return err
+}
@@ -0,0 +1,138 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package postgres
+
+import (
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/mainflux/mainflux/notifier"
+)
+
+const (
+	duplicateErr   = "unique_violation"
+	fkViolationErr = "foreign_key_violation"
+	uuidErr        = "invalid input syntax for type uuid"
+)
+
+var _ notifier.SubscriptionRepository = (*subscriptionRepository)(nil)
+
+type subscriptionRepository struct {
+	db *sqlx.DB
+}
+
+// NewSubscriptionRepository instantiates a PostgreSQL implementation of
+// subscription repository.
+func NewSubscriptionRepository(db *sqlx.DB) notifier.SubscriptionRepository {
+	return &subscriptionRepository{db: db}
+}
+
+func (sr subscriptionRepository) Save(sub notifier.Subscription) (string, error) {
+	q := `INSERT INTO subscriptions (id, owner, type, target, events, secret) VALUES (:id, :owner, :type, :target, :events, :secret)`
+
+	if _, err := sr.db.NamedExec(q, toDBSubscription(sub)); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == duplicateErr {
+			return "", notifier.ErrMalformedEntity
+		}
+		return "", err
+	}
+
+	return sub.ID, nil
+}
+
+func (sr subscriptionRepository) RetrieveByID(owner, id string) (notifier.Subscription, error) {
+	q := `SELECT id, owner, type, target, events, secret FROM subscriptions WHERE id = $1 AND owner = $2`
+
+	dbs := dbSubscription{}
+	if err := sr.db.QueryRowx(q, id, owner).StructScan(&dbs); err != nil {
+		if err == sql.ErrNoRows {
+			return notifier.Subscription{}, notifier.ErrNotFound
+		}
+
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Message == uuidErr {
+			return notifier.Subscription{}, notifier.ErrNotFound
+		}
+
+		return notifier.Subscription{}, err
+	}
+
+	return toSubscription(dbs), nil
+}
+
+func (sr subscriptionRepository) RetrieveAll(owner string) ([]notifier.Subscription, error) {
+	q := `SELECT id, owner, type, target, events, secret FROM subscriptions WHERE owner = $1 ORDER BY id`
+
+	rows, err := sr.db.Queryx(q, owner)
+	if err != nil {
+		return []notifier.Subscription{}, err
+	}
+	defer rows.Close()
+
+	subs := []notifier.Subscription{}
+	for rows.Next() {
+		dbs := dbSubscription{}
+		if err := rows.StructScan(&dbs); err != nil {
+			return []notifier.Subscription{}, err
+		}
+		subs = append(subs, toSubscription(dbs))
+	}
+
+	return subs, nil
+}
+
+func (sr subscriptionRepository) Remove(owner, id string) error {
+	q := `DELETE FROM subscriptions WHERE id = $1 AND owner = $2`
+
+	if _, err := sr.db.Exec(q, id, owner); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Message == uuidErr {
+			return nil
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (sr subscriptionRepository) RemoveAll(owner string) error {
+	q := `DELETE FROM subscriptions WHERE owner = $1`
+
+	_, err := sr.db.Exec(q, owner)
+	return err
+}
+
+type dbSubscription struct {
+	ID     string         `db:"id"`
+	Owner  string         `db:"owner"`
+	Type   string         `db:"type"`
+	Target string         `db:"target"`
+	Events pq.StringArray `db:"events"`
+	Secret string         `db:"secret"`
+}
+
+func toDBSubscription(sub notifier.Subscription) dbSubscription {
+	return dbSubscription{
+		ID:     sub.ID,
+		Owner:  sub.Owner,
+		Type:   sub.Type,
+		Target: sub.Target,
+		Events: pq.StringArray(sub.Events),
+		Secret: sub.Secret,
+	}
+}
+
+func toSubscription(dbs dbSubscription) notifier.Subscription {
+	return notifier.Subscription{
+		ID:     dbs.ID,
+		Owner:  dbs.Owner,
+		Type:   dbs.Type,
+		Target: dbs.Target,
+		Events: []string(dbs.Events),
+		Secret: dbs.Secret,
+	}
+}
@@ -0,0 +1,162 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package postgres_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/mainflux/mainflux/notifier"
+	"github.com/mainflux/mainflux/notifier/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	owner  = "user@example.com"
+	target = "https://example.com/hook"
+)
+
+func newSubscription(t *testing.T, owner string) notifier.Subscription {
+	id, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	return notifier.Subscription{
+		ID:     id.String(),
+		Owner:  owner,
+		Type:   notifier.Webhook,
+		Target: target,
+		Events: []string{notifier.ThingCreate, notifier.ChannelCreate},
+	}
+}
+
+func TestSubscriptionSave(t *testing.T) {
+	repo := postgres.NewSubscriptionRepository(db)
+	sub := newSubscription(t, owner)
+
+	cases := []struct {
+		desc string
+		sub  notifier.Subscription
+		err  error
+	}{
+		{
+			desc: "save new subscription",
+			sub:  sub,
+			err:  nil,
+		},
+		{
+			desc: "save existing subscription",
+			sub:  sub,
+			err:  notifier.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := repo.Save(tc.sub)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestSubscriptionRetrieveByID(t *testing.T) {
+	repo := postgres.NewSubscriptionRepository(db)
+	sub := newSubscription(t, owner)
+	id, err := repo.Save(sub)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		owner string
+		id    string
+		err   error
+	}{
+		{
+			desc:  "retrieve existing subscription",
+			owner: owner,
+			id:    id,
+			err:   nil,
+		},
+		{
+			desc:  "retrieve subscription of another user",
+			owner: "other@example.com",
+			id:    id,
+			err:   notifier.ErrNotFound,
+		},
+		{
+			desc:  "retrieve subscription with malformed ID",
+			owner: owner,
+			id:    "malformed",
+			err:   notifier.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		saved, err := repo.RetrieveByID(tc.owner, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err == nil {
+			assert.Equal(t, sub, saved, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, sub, saved))
+		}
+	}
+}
+
+func TestSubscriptionRetrieveAll(t *testing.T) {
+	repo := postgres.NewSubscriptionRepository(db)
+	email := "all@example.com"
+
+	n := 5
+	for i := 0; i < n; i++ {
+		_, err := repo.Save(newSubscription(t, email))
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	}
+
+	cases := map[string]struct {
+		owner string
+		size  int
+	}{
+		"retrieve all subscriptions of the user":     {owner: email, size: n},
+		"retrieve all subscriptions of unknown user": {owner: "unknown@example.com", size: 0},
+	}
+
+	for desc, tc := range cases {
+		subs, err := repo.RetrieveAll(tc.owner)
+		assert.Nil(t, err, fmt.Sprintf("%s: got unexpected error: %s\n", desc, err))
+		assert.Len(t, subs, tc.size, fmt.Sprintf("%s: expected %d got %d\n", desc, tc.size, len(subs)))
+	}
+}
+
+func TestSubscriptionRemove(t *testing.T) {
+	repo := postgres.NewSubscriptionRepository(db)
+	sub := newSubscription(t, owner)
+	id, err := repo.Save(sub)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	for i := 0; i < 2; i++ {
+		err := repo.Remove(owner, id)
+		assert.Nil(t, err, fmt.Sprintf("#%d: got unexpected error: %s\n", i, err))
+
+		_, err = repo.RetrieveByID(owner, id)
+		assert.Equal(t, notifier.ErrNotFound, err, fmt.Sprintf("#%d: expected %s got %s\n", i, notifier.ErrNotFound, err))
+	}
+}
+
+func TestSubscriptionRemoveAll(t *testing.T) {
+	repo := postgres.NewSubscriptionRepository(db)
+	email := "removed@example.com"
+
+	for i := 0; i < 3; i++ {
+		_, err := repo.Save(newSubscription(t, email))
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	}
+
+	err := repo.RemoveAll(email)
+	assert.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	subs, err := repo.RetrieveAll(email)
+	assert.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.Len(t, subs, 0, fmt.Sprintf("expected no subscriptions got %d", len(subs)))
+}
@@ -0,0 +1,94 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package redis
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/mainflux/mainflux/events"
+	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/notifier"
+)
+
+const (
+	stream = "mainflux.things"
+	group  = "mainflux.notifier"
+)
+
+// EventStore represents event source for things and channels provisioning.
+type EventStore interface {
+	// Subscribes to given subject and receives events.
+	Subscribe(string) error
+}
+
+type eventStore struct {
+	svc      notifier.Service
+	client   redis.UniversalClient
+	consumer string
+	logger   logger.Logger
+}
+
+// NewEventStore returns new event store instance.
+func NewEventStore(svc notifier.Service, client redis.UniversalClient, consumer string, log logger.Logger) EventStore {
+	return eventStore{
+		svc:      svc,
+		client:   client,
+		consumer: consumer,
+		logger:   log,
+	}
+}
+
+// Subscribe notifies the owners about the consumed events. Events are
+// acknowledged even if the delivery fails, since redelivering them would
+// repeat the notifications to the subscribers that already received them.
+// Failed deliveries are retried by the service instead.
+func (es eventStore) Subscribe(subject string) error {
+	sub := events.NewSubscriber(es.client, events.Config{
+		Stream:   stream,
+		Group:    group,
+		Consumer: es.consumer,
+	}, es.logger)
+
+	return sub.Subscribe(events.HandlerFunc(es.handle))
+}
+
+func (es eventStore) handle(event events.Event) error {
+	op := event.Read("operation", "")
+	if !notifier.Supported(op) {
+		return nil
+	}
+
+	if err := es.svc.Notify(decodeEvent(op, event)); err != nil {
+		es.logger.Warn(fmt.Sprintf("Failed to deliver %s notification: %s", op, err))
+	}
+
+	return nil
+}
+
+// decodeEvent decodes the things service event. Connection events refer to
+// the thing and the channel, rather than to a single entity.
+func decodeEvent(op string, event events.Event) notifier.Event {
+	if op == notifier.ThingConnect || op == notifier.ThingDisconnect {
+		return notifier.Event{
+			Operation: op,
+			EntityID:  event.Read("thing_id", ""),
+			ChannelID: event.Read("chan_id", ""),
+			Owner:     event.Read("owner", ""),
+			Occurred:  time.Now(),
+		}
+	}
+
+	return notifier.Event{
+		Operation: op,
+		EntityID:  event.Read("id", ""),
+		Owner:     event.Read("owner", ""),
+		Occurred:  time.Now(),
+	}
+}
@@ -0,0 +1,314 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package notifier
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/mainflux/mainflux"
+)
+
+const (
+	// maxAttempts is the number of times the notification is attempted
+	// before its delivery is given up on.
+	maxAttempts = 5
+
+	// retryBackoff is the period waited before the first retry of the
+	// failed delivery, doubled for every following one.
+	retryBackoff = 30 * time.Second
+
+	retryBatchSize = 100
+	secretSize     = 32
+)
+
+var (
+	// ErrMalformedEntity indicates malformed entity specification (e.g.
+	// invalid subscription target or event).
+	ErrMalformedEntity = errors.New("malformed entity specification")
+
+	// ErrUnauthorizedAccess indicates missing or invalid credentials provided
+	// when accessing a protected resource.
+	ErrUnauthorizedAccess = errors.New("missing or invalid credentials provided")
+
+	// ErrNotFound indicates a non-existent entity request.
+	ErrNotFound = errors.New("non-existent entity")
+
+	// ErrUnsupportedType indicates the subscription type that has no
+	// configured sender.
+	ErrUnsupportedType = errors.New("unsupported subscription type")
+)
+
+// Service specifies an API that must be fullfiled by the domain service
+// implementation, and all of its decorators (e.g. logging & metrics).
+type Service interface {
+	// Subscribe adds the subscription to the lifecycle events of the
+	// entities owned by the user identified by the provided key.
+	Subscribe(string, Subscription) (Subscription, error)
+
+	// ViewSubscription retrieves data about the subscription identified
+	// with the provided ID, that belongs to the user identified by the
+	// provided key.
+	ViewSubscription(string, string) (Subscription, error)
+
+	// ListSubscriptions retrieves all the subscriptions that belong to the
+	// user identified by the provided key.
+	ListSubscriptions(string) ([]Subscription, error)
+
+	// Unsubscribe removes the subscription identified with the provided
+	// ID, that belongs to the user identified by the provided key.
+	Unsubscribe(string, string) error
+
+	// ListDeliveries retrieves the page of the deliveries of the
+	// subscription identified with the provided ID, that belongs to the
+	// user identified by the provided key.
+	ListDeliveries(string, string, uint64, uint64) (DeliveriesPage, error)
+
+	// Notify delivers the event to all the subscriptions of the entity
+	// owner that cover the event operation, recording every delivery in
+	// the delivery log. Delivery to the remaining subscriptions is
+	// attempted even if some of them fail, in which case the last error is
+	// returned. Failed deliveries are retried by RetryDeliveries.
+	Notify(Event) error
+
+	// RetryDeliveries attempts the failed deliveries that are due by the
+	// provided time once again, waiting exponentially longer after every
+	// failed attempt, until they succeed or run out of attempts. The
+	// outcome of every attempt is recorded in the delivery log, so only
+	// the delivery log access errors are returned.
+	RetryDeliveries(time.Time) error
+
+	// RemoveUserHandler removes all the subscriptions of the removed user.
+	RemoveUserHandler(string) error
+}
+
+var _ Service = (*notifierService)(nil)
+
+type notifierService struct {
+	users      mainflux.UsersServiceClient
+	subs       SubscriptionRepository
+	deliveries DeliveryRepository
+	idp        IdentityProvider
+	senders    map[string]Sender
+}
+
+// New instantiates the notifier service implementation. Notifications are
+// delivered using the sender registered for the subscription type.
+func New(users mainflux.UsersServiceClient, subs SubscriptionRepository, deliveries DeliveryRepository, idp IdentityProvider, senders map[string]Sender) Service {
+	return &notifierService{
+		users:      users,
+		subs:       subs,
+		deliveries: deliveries,
+		idp:        idp,
+		senders:    senders,
+	}
+}
+
+func (ns *notifierService) Subscribe(key string, sub Subscription) (Subscription, error) {
+	if err := sub.Validate(); err != nil {
+		return Subscription{}, err
+	}
+
+	owner, err := ns.identify(key)
+	if err != nil {
+		return Subscription{}, err
+	}
+
+	if _, ok := ns.senders[sub.Type]; !ok {
+		return Subscription{}, ErrUnsupportedType
+	}
+
+	sub.ID, err = ns.idp.ID()
+	if err != nil {
+		return Subscription{}, err
+	}
+	sub.Owner = owner
+
+	sub.Secret = ""
+	if sub.Type == Webhook {
+		if sub.Secret, err = secret(); err != nil {
+			return Subscription{}, err
+		}
+	}
+
+	id, err := ns.subs.Save(sub)
+	if err != nil {
+		return Subscription{}, err
+	}
+
+	sub.ID = id
+	return sub, nil
+}
+
+func (ns *notifierService) ViewSubscription(key, id string) (Subscription, error) {
+	owner, err := ns.identify(key)
+	if err != nil {
+		return Subscription{}, err
+	}
+
+	return ns.subs.RetrieveByID(owner, id)
+}
+
+func (ns *notifierService) ListSubscriptions(key string) ([]Subscription, error) {
+	owner, err := ns.identify(key)
+	if err != nil {
+		return []Subscription{}, err
+	}
+
+	return ns.subs.RetrieveAll(owner)
+}
+
+func (ns *notifierService) Unsubscribe(key, id string) error {
+	owner, err := ns.identify(key)
+	if err != nil {
+		return err
+	}
+
+	return ns.subs.Remove(owner, id)
+}
+
+func (ns *notifierService) ListDeliveries(key, id string, offset, limit uint64) (DeliveriesPage, error) {
+	owner, err := ns.identify(key)
+	if err != nil {
+		return DeliveriesPage{}, err
+	}
+
+	if _, err := ns.subs.RetrieveByID(owner, id); err != nil {
+		return DeliveriesPage{}, err
+	}
+
+	return ns.deliveries.RetrieveAll(owner, id, offset, limit)
+}
+
+func (ns *notifierService) Notify(event Event) error {
+	if event.Owner == "" {
+		return nil
+	}
+
+	subs, err := ns.subs.RetrieveAll(event.Owner)
+	if err != nil {
+		return err
+	}
+
+	var lastErr error
+	for _, sub := range subs {
+		if !sub.Matches(event.Operation) {
+			continue
+		}
+
+		id, err := ns.idp.ID()
+		if err != nil {
+			return err
+		}
+
+		d := Delivery{
+			ID:             id,
+			SubscriptionID: sub.ID,
+			Owner:          sub.Owner,
+			Event:          event,
+		}
+
+		d, err = ns.attempt(sub, d, time.Now())
+		if err != nil {
+			lastErr = err
+		}
+
+		if err := ns.deliveries.Save(d); err != nil {
+			lastErr = err
+		}
+	}
+
+	return lastErr
+}
+
+func (ns *notifierService) RetryDeliveries(now time.Time) error {
+	due, err := ns.deliveries.RetrieveDue(now, retryBatchSize)
+	if err != nil {
+		return err
+	}
+
+	var lastErr error
+	for _, d := range due {
+		sub, err := ns.subs.RetrieveByID(d.Owner, d.SubscriptionID)
+		switch err {
+		case nil:
+			d, _ = ns.attempt(sub, d, now)
+		case ErrNotFound:
+			d.Status = Failed
+			d.Error = err.Error()
+			d.Updated = now
+		default:
+			lastErr = err
+			continue
+		}
+
+		if err := ns.deliveries.Update(d); err != nil {
+			lastErr = err
+		}
+	}
+
+	return lastErr
+}
+
+func (ns *notifierService) RemoveUserHandler(owner string) error {
+	return ns.subs.RemoveAll(owner)
+}
+
+// attempt sends the notification and updates the delivery with the outcome.
+// The failed delivery is scheduled for the retry, unless it ran out of
+// attempts or the subscription type has no sender.
+func (ns *notifierService) attempt(sub Subscription, d Delivery, now time.Time) (Delivery, error) {
+	d.Attempts++
+	d.Updated = now
+	d.NextAttempt = time.Time{}
+
+	sender, ok := ns.senders[sub.Type]
+	err := ErrUnsupportedType
+	if ok {
+		err = sender.Send(sub, d)
+	}
+
+	switch {
+	case err == nil:
+		d.Status = Delivered
+		d.Error = ""
+	case ok && d.Attempts < maxAttempts:
+		d.Status = Pending
+		d.Error = err.Error()
+		d.NextAttempt = now.Add(retryBackoff << uint(d.Attempts-1))
+	default:
+		d.Status = Failed
+		d.Error = err.Error()
+	}
+
+	return d, err
+}
+
+func (ns *notifierService) identify(key string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	res, err := ns.users.Identify(ctx, &mainflux.Token{Value: key})
+	if err != nil {
+		return "", ErrUnauthorizedAccess
+	}
+
+	return res.GetValue(), nil
+}
+
+func secret() (string, error) {
+	b := make([]byte, secretSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
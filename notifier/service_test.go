@@ -0,0 +1,374 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package notifier_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/notifier"
+	"github.com/mainflux/mainflux/notifier/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	validToken   = "validToken"
+	invalidToken = "invalidToken"
+	email        = "user@example.com"
+	hook         = "https://example.com/hook"
+	failingHook  = "https://example.com/failing"
+)
+
+var (
+	errSend = errors.New("failed to send")
+
+	sub = notifier.Subscription{
+		Type:   notifier.Webhook,
+		Target: hook,
+		Events: []string{notifier.ThingCreate},
+	}
+)
+
+func newService(sender notifier.Sender) notifier.Service {
+	users := mocks.NewUsersService(map[string]string{validToken: email})
+	subs := mocks.NewSubscriptionRepository()
+	deliveries := mocks.NewDeliveryRepository()
+	idp := mocks.NewIdentityProvider()
+	senders := map[string]notifier.Sender{notifier.Webhook: sender}
+
+	return notifier.New(users, subs, deliveries, idp, senders)
+}
+
+func TestSubscribe(t *testing.T) {
+	svc := newService(mocks.NewSender(nil))
+
+	unsupported := sub
+	unsupported.Type = notifier.Email
+	unsupported.Target = email
+
+	malformed := sub
+	malformed.Events = []string{"thing.unknown"}
+
+	cases := []struct {
+		desc  string
+		token string
+		sub   notifier.Subscription
+		err   error
+	}{
+		{
+			desc:  "subscribe with valid token",
+			token: validToken,
+			sub:   sub,
+			err:   nil,
+		},
+		{
+			desc:  "subscribe with invalid token",
+			token: invalidToken,
+			sub:   sub,
+			err:   notifier.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "subscribe to unknown event",
+			token: validToken,
+			sub:   malformed,
+			err:   notifier.ErrMalformedEntity,
+		},
+		{
+			desc:  "subscribe with type without sender",
+			token: validToken,
+			sub:   unsupported,
+			err:   notifier.ErrUnsupportedType,
+		},
+	}
+
+	for _, tc := range cases {
+		saved, err := svc.Subscribe(tc.token, tc.sub)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err == nil {
+			assert.Equal(t, email, saved.Owner, fmt.Sprintf("%s: expected owner %s got %s\n", tc.desc, email, saved.Owner))
+			assert.NotEmpty(t, saved.ID, fmt.Sprintf("%s: expected non-empty ID\n", tc.desc))
+			assert.NotEmpty(t, saved.Secret, fmt.Sprintf("%s: expected non-empty webhook secret\n", tc.desc))
+		}
+	}
+}
+
+func TestViewSubscription(t *testing.T) {
+	svc := newService(mocks.NewSender(nil))
+	saved, err := svc.Subscribe(validToken, sub)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := map[string]struct {
+		token string
+		id    string
+		err   error
+	}{
+		"view existing subscription":           {validToken, saved.ID, nil},
+		"view subscription with invalid token": {invalidToken, saved.ID, notifier.ErrUnauthorizedAccess},
+		"view non-existing subscription":       {validToken, "unknown", notifier.ErrNotFound},
+	}
+
+	for desc, tc := range cases {
+		_, err := svc.ViewSubscription(tc.token, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
+func TestListSubscriptions(t *testing.T) {
+	svc := newService(mocks.NewSender(nil))
+
+	n := 3
+	for i := 0; i < n; i++ {
+		_, err := svc.Subscribe(validToken, sub)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	cases := map[string]struct {
+		token string
+		size  int
+		err   error
+	}{
+		"list subscriptions":                    {validToken, n, nil},
+		"list subscriptions with invalid token": {invalidToken, 0, notifier.ErrUnauthorizedAccess},
+	}
+
+	for desc, tc := range cases {
+		subs, err := svc.ListSubscriptions(tc.token)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+		assert.Len(t, subs, tc.size, fmt.Sprintf("%s: expected %d got %d\n", desc, tc.size, len(subs)))
+	}
+}
+
+func TestUnsubscribe(t *testing.T) {
+	svc := newService(mocks.NewSender(nil))
+	saved, err := svc.Subscribe(validToken, sub)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		token string
+		id    string
+		err   error
+	}{
+		{
+			desc:  "unsubscribe with invalid token",
+			token: invalidToken,
+			id:    saved.ID,
+			err:   notifier.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "unsubscribe existing subscription",
+			token: validToken,
+			id:    saved.ID,
+			err:   nil,
+		},
+		{
+			desc:  "unsubscribe removed subscription",
+			token: validToken,
+			id:    saved.ID,
+			err:   nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.Unsubscribe(tc.token, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	_, err = svc.ViewSubscription(validToken, saved.ID)
+	assert.Equal(t, notifier.ErrNotFound, err, fmt.Sprintf("expected %s got %s", notifier.ErrNotFound, err))
+}
+
+func TestNotify(t *testing.T) {
+	sender := mocks.NewSender(map[string]error{failingHook: errSend})
+	svc := newService(sender)
+
+	_, err := svc.Subscribe(validToken, sub)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	failing := sub
+	failing.Target = failingHook
+	_, err = svc.Subscribe(validToken, failing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		event notifier.Event
+		sent  int
+		err   error
+	}{
+		{
+			desc:  "notify about subscribed event",
+			event: notifier.Event{Operation: notifier.ThingCreate, EntityID: "1", Owner: email, Occurred: time.Now()},
+			sent:  1,
+			err:   errSend,
+		},
+		{
+			desc:  "notify about event without subscription",
+			event: notifier.Event{Operation: notifier.ChannelCreate, EntityID: "1", Owner: email, Occurred: time.Now()},
+			sent:  1,
+			err:   nil,
+		},
+		{
+			desc:  "notify about event of another owner",
+			event: notifier.Event{Operation: notifier.ThingCreate, EntityID: "2", Owner: "other@example.com", Occurred: time.Now()},
+			sent:  1,
+			err:   nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.Notify(tc.event)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		sent := len(sender.Sent(hook))
+		assert.Equal(t, tc.sent, sent, fmt.Sprintf("%s: expected %d sent notifications got %d\n", tc.desc, tc.sent, sent))
+	}
+}
+
+func TestListDeliveries(t *testing.T) {
+	sender := mocks.NewSender(nil)
+	svc := newService(sender)
+
+	saved, err := svc.Subscribe(validToken, sub)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	n := uint64(5)
+	for i := uint64(0); i < n; i++ {
+		event := notifier.Event{Operation: notifier.ThingCreate, EntityID: fmt.Sprintf("%d", i), Owner: email, Occurred: time.Now()}
+		err := svc.Notify(event)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	cases := []struct {
+		desc   string
+		token  string
+		id     string
+		offset uint64
+		limit  uint64
+		size   uint64
+		err    error
+	}{
+		{
+			desc:   "list all deliveries",
+			token:  validToken,
+			id:     saved.ID,
+			offset: 0,
+			limit:  n,
+			size:   n,
+			err:    nil,
+		},
+		{
+			desc:   "list last page of deliveries",
+			token:  validToken,
+			id:     saved.ID,
+			offset: n - 2,
+			limit:  n,
+			size:   2,
+			err:    nil,
+		},
+		{
+			desc:   "list deliveries with invalid token",
+			token:  invalidToken,
+			id:     saved.ID,
+			offset: 0,
+			limit:  n,
+			size:   0,
+			err:    notifier.ErrUnauthorizedAccess,
+		},
+		{
+			desc:   "list deliveries of non-existing subscription",
+			token:  validToken,
+			id:     "unknown",
+			offset: 0,
+			limit:  n,
+			size:   0,
+			err:    notifier.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		page, err := svc.ListDeliveries(tc.token, tc.id, tc.offset, tc.limit)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		size := uint64(len(page.Deliveries))
+		assert.Equal(t, tc.size, size, fmt.Sprintf("%s: expected %d got %d\n", tc.desc, tc.size, size))
+	}
+
+	page, err := svc.ListDeliveries(validToken, saved.ID, 0, 1)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	d := page.Deliveries[0]
+	assert.Equal(t, notifier.Delivered, d.Status, fmt.Sprintf("expected status %s got %s", notifier.Delivered, d.Status))
+	assert.Equal(t, fmt.Sprintf("%d", n-1), d.Event.EntityID, fmt.Sprintf("expected the most recent delivery first got %s", d.Event.EntityID))
+}
+
+func TestRetryDeliveries(t *testing.T) {
+	sender := mocks.NewSender(map[string]error{hook: errSend})
+	svc := newService(sender)
+
+	saved, err := svc.Subscribe(validToken, sub)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	now := time.Now()
+	err = svc.Notify(notifier.Event{Operation: notifier.ThingCreate, EntityID: "1", Owner: email, Occurred: now})
+	assert.Equal(t, errSend, err, fmt.Sprintf("expected %s got %s", errSend, err))
+
+	delivery := func() notifier.Delivery {
+		page, err := svc.ListDeliveries(validToken, saved.ID, 0, 1)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		require.Len(t, page.Deliveries, 1, "expected single delivery")
+		return page.Deliveries[0]
+	}
+
+	d := delivery()
+	assert.Equal(t, notifier.Pending, d.Status, fmt.Sprintf("expected status %s got %s", notifier.Pending, d.Status))
+	assert.Equal(t, errSend.Error(), d.Error, fmt.Sprintf("expected error %s got %s", errSend, d.Error))
+	assert.True(t, d.NextAttempt.After(now), "expected next attempt to be scheduled")
+
+	err = svc.RetryDeliveries(now)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	d = delivery()
+	assert.Equal(t, 1, d.Attempts, fmt.Sprintf("expected delivery not due to be left alone, got %d attempts", d.Attempts))
+
+	err = svc.RetryDeliveries(d.NextAttempt)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	prev := d
+	d = delivery()
+	assert.Equal(t, 2, d.Attempts, fmt.Sprintf("expected 2 attempts got %d", d.Attempts))
+	assert.True(t, d.NextAttempt.Sub(prev.NextAttempt) > prev.NextAttempt.Sub(now), "expected backoff to grow")
+
+	sender.Fail(hook, nil)
+	err = svc.RetryDeliveries(d.NextAttempt)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	d = delivery()
+	assert.Equal(t, notifier.Delivered, d.Status, fmt.Sprintf("expected status %s got %s", notifier.Delivered, d.Status))
+	assert.Len(t, sender.Sent(hook), 1, "expected notification to be delivered once")
+
+	sender.Fail(hook, errSend)
+	err = svc.Notify(notifier.Event{Operation: notifier.ThingCreate, EntityID: "2", Owner: email, Occurred: now})
+	assert.Equal(t, errSend, err, fmt.Sprintf("expected %s got %s", errSend, err))
+	for d = delivery(); d.Status == notifier.Pending; d = delivery() {
+		err := svc.RetryDeliveries(d.NextAttempt)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+	assert.Equal(t, notifier.Failed, d.Status, fmt.Sprintf("expected status %s got %s", notifier.Failed, d.Status))
+	assert.Equal(t, 5, d.Attempts, fmt.Sprintf("expected 5 attempts got %d", d.Attempts))
+}
+
+func TestRemoveUserHandler(t *testing.T) {
+	svc := newService(mocks.NewSender(nil))
+	_, err := svc.Subscribe(validToken, sub)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	err = svc.RemoveUserHandler(email)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	subs, err := svc.ListSubscriptions(validToken)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Len(t, subs, 0, fmt.Sprintf("expected no subscriptions got %d", len(subs)))
+}
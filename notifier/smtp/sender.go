@@ -0,0 +1,72 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// Package smtp contains the notification sender that delivers the events
+// by email.
+package smtp
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/smtp"
+	"time"
+
+	"github.com/mainflux/mainflux/notifier"
+)
+
+var _ notifier.Sender = (*sender)(nil)
+
+// Config defines the options that are used when connecting to the SMTP
+// server.
+type Config struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+type sender struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+// New instantiates the email notification sender. Plain authentication is
+// used only if the username is provided.
+func New(cfg Config) notifier.Sender {
+	var auth smtp.Auth
+	if cfg.Username != "" {
+		auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+
+	return &sender{
+		addr: net.JoinHostPort(cfg.Host, cfg.Port),
+		auth: auth,
+		from: cfg.From,
+	}
+}
+
+func (s *sender) Send(sub notifier.Subscription, d notifier.Delivery) error {
+	return smtp.SendMail(s.addr, s.auth, s.from, []string{sub.Target}, s.message(sub.Target, d.Event))
+}
+
+func (s *sender) message(to string, event notifier.Event) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", s.from)
+	fmt.Fprintf(&buf, "To: %s\r\n", to)
+	fmt.Fprintf(&buf, "Subject: Mainflux: %s\r\n", event.Operation)
+	fmt.Fprint(&buf, "Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	fmt.Fprintf(&buf, "Operation: %s\r\n", event.Operation)
+	fmt.Fprintf(&buf, "Entity: %s\r\n", event.EntityID)
+	if event.ChannelID != "" {
+		fmt.Fprintf(&buf, "Channel: %s\r\n", event.ChannelID)
+	}
+	fmt.Fprintf(&buf, "Occurred: %s\r\n", event.Occurred.UTC().Format(time.RFC3339))
+	return buf.Bytes()
+}
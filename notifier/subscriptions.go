@@ -0,0 +1,152 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package notifier
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/asaskevich/govalidator"
+)
+
+// Supported subscription types.
+const (
+	Email   = "email"
+	Webhook = "webhook"
+)
+
+// Supported entity lifecycle events.
+const (
+	ThingCreate     = "thing.create"
+	ThingUpdate     = "thing.update"
+	ThingUpdateKey  = "thing.update_key"
+	ThingRemove     = "thing.remove"
+	ThingConnect    = "thing.connect"
+	ThingDisconnect = "thing.disconnect"
+	ChannelCreate   = "channel.create"
+	ChannelUpdate   = "channel.update"
+	ChannelRemove   = "channel.remove"
+)
+
+var operations = map[string]bool{
+	ThingCreate:     true,
+	ThingUpdate:     true,
+	ThingUpdateKey:  true,
+	ThingRemove:     true,
+	ThingConnect:    true,
+	ThingDisconnect: true,
+	ChannelCreate:   true,
+	ChannelUpdate:   true,
+	ChannelRemove:   true,
+}
+
+// Supported reports whether the operation is the lifecycle event that can
+// be subscribed to.
+func Supported(operation string) bool {
+	return operations[operation]
+}
+
+// Subscription represents the user preference to be notified about the
+// lifecycle events of the owned entities, by the email sent to the target
+// address or by the webhook invoked on the target URL. Webhook requests are
+// signed using the secret, so that the receiver can verify their origin.
+type Subscription struct {
+	ID     string
+	Owner  string
+	Type   string
+	Target string
+	Events []string
+	Secret string
+}
+
+// Validate returns an error if subscription representation is invalid.
+func (s Subscription) Validate() error {
+	switch s.Type {
+	case Email:
+		if !govalidator.IsEmail(s.Target) {
+			return ErrMalformedEntity
+		}
+	case Webhook:
+		u, err := url.Parse(s.Target)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return ErrMalformedEntity
+		}
+	default:
+		return ErrMalformedEntity
+	}
+
+	if len(s.Events) == 0 {
+		return ErrMalformedEntity
+	}
+
+	for _, op := range s.Events {
+		if !operations[op] {
+			return ErrMalformedEntity
+		}
+	}
+
+	return nil
+}
+
+// Matches returns true if the subscription covers the event operation.
+func (s Subscription) Matches(operation string) bool {
+	for _, op := range s.Events {
+		if op == operation {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Event represents the lifecycle event of the entity owned by the user. The
+// connection events refer to the thing by EntityID and to the channel by
+// ChannelID.
+type Event struct {
+	Operation string
+	EntityID  string
+	ChannelID string
+	Owner     string
+	Occurred  time.Time
+}
+
+// SubscriptionRepository specifies a subscription persistence API.
+type SubscriptionRepository interface {
+	// Save persists the subscription. Successful operation is indicated by
+	// unique identifier accompanied by nil error response. A non-nil error
+	// is returned to indicate operation failure.
+	Save(Subscription) (string, error)
+
+	// RetrieveByID retrieves the subscription having the provided
+	// identifier, that is owned by the specified user.
+	RetrieveByID(string, string) (Subscription, error)
+
+	// RetrieveAll retrieves all the subscriptions owned by the specified
+	// user.
+	RetrieveAll(string) ([]Subscription, error)
+
+	// Remove removes the subscription having the provided identifier, that
+	// is owned by the specified user.
+	Remove(string, string) error
+
+	// RemoveAll removes all the subscriptions owned by the specified user.
+	RemoveAll(string) error
+}
+
+// Sender specifies an API for the delivery of notifications.
+type Sender interface {
+	// Send delivers the event notification to the subscription target.
+	// Every attempt of the same delivery carries the same delivery ID.
+	Send(Subscription, Delivery) error
+}
+
+// IdentityProvider specifies an API for generating unique identifiers.
+type IdentityProvider interface {
+	// ID generates the unique identifier.
+	ID() (string, error)
+}
@@ -0,0 +1,108 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// Package webhook contains the notification sender that delivers the events
+// by invoking the subscribed HTTP endpoints.
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/mainflux/mainflux/notifier"
+)
+
+const (
+	contentType = "application/json"
+
+	// SignatureHeader carries the hex encoded HMAC-SHA256 of the request
+	// body, keyed with the subscription secret and prefixed with
+	// "sha256=".
+	SignatureHeader = "X-Mainflux-Signature"
+
+	// DeliveryHeader carries the delivery ID, which is the same for every
+	// attempt of the delivery, so that the receivers can drop duplicates.
+	DeliveryHeader = "X-Mainflux-Delivery"
+
+	signaturePrefix = "sha256="
+)
+
+// ErrDeliveryFailed indicates that the webhook responded with a non-2xx
+// status code.
+var ErrDeliveryFailed = errors.New("webhook responded with unexpected status")
+
+var _ notifier.Sender = (*sender)(nil)
+
+type sender struct {
+	client *http.Client
+}
+
+type payload struct {
+	Operation string `json:"operation"`
+	ID        string `json:"id"`
+	ChannelID string `json:"channel_id,omitempty"`
+	Owner     string `json:"owner"`
+	Occurred  int64  `json:"occurred"`
+}
+
+// New instantiates the webhook notification sender. Every event is POSTed
+// to the target URL as JSON document, signed using the subscription secret.
+func New(timeout time.Duration) notifier.Sender {
+	return &sender{
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (s *sender) Send(sub notifier.Subscription, d notifier.Delivery) error {
+	data, err := json.Marshal(payload{
+		Operation: d.Event.Operation,
+		ID:        d.Event.EntityID,
+		ChannelID: d.Event.ChannelID,
+		Owner:     d.Event.Owner,
+		Occurred:  d.Event.Occurred.Unix(),
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, sub.Target, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set(DeliveryHeader, d.ID)
+	if sub.Secret != "" {
+		req.Header.Set(SignatureHeader, signaturePrefix+Sign(sub.Secret, data))
+	}
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return ErrDeliveryFailed
+	}
+
+	return nil
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of the body, keyed with the
+// secret, which the receivers compare to the signature header value.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,98 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package webhook_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/notifier"
+	"github.com/mainflux/mainflux/notifier/webhook"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSend(t *testing.T) {
+	secret := "secret"
+	received := map[string]interface{}{}
+	headers := http.Header{}
+	ok := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		json.Unmarshal(body, &received)
+		headers = r.Header
+		if r.Header.Get(webhook.SignatureHeader) != "sha256="+webhook.Sign(secret, body) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ok.Close()
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	delivery := notifier.Delivery{
+		ID: "delivery",
+		Event: notifier.Event{
+			Operation: notifier.ThingConnect,
+			EntityID:  "1",
+			ChannelID: "2",
+			Owner:     "user@example.com",
+			Occurred:  time.Unix(100, 0),
+		},
+	}
+
+	cases := []struct {
+		desc   string
+		target string
+		secret string
+		err    error
+	}{
+		{
+			desc:   "send event to available webhook",
+			target: ok.URL,
+			secret: secret,
+			err:    nil,
+		},
+		{
+			desc:   "send event signed with wrong secret",
+			target: ok.URL,
+			secret: "wrong",
+			err:    webhook.ErrDeliveryFailed,
+		},
+		{
+			desc:   "send event to failing webhook",
+			target: failing.URL,
+			secret: secret,
+			err:    webhook.ErrDeliveryFailed,
+		},
+	}
+
+	sender := webhook.New(time.Second)
+	for _, tc := range cases {
+		sub := notifier.Subscription{Type: notifier.Webhook, Target: tc.target, Secret: tc.secret}
+		err := sender.Send(sub, delivery)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	expected := map[string]interface{}{
+		"operation":  notifier.ThingConnect,
+		"id":         "1",
+		"channel_id": "2",
+		"owner":      "user@example.com",
+		"occurred":   float64(100),
+	}
+	assert.Equal(t, expected, received, fmt.Sprintf("expected %v got %v", expected, received))
+	assert.Equal(t, delivery.ID, headers.Get(webhook.DeliveryHeader), fmt.Sprintf("expected delivery ID %s got %s", delivery.ID, headers.Get(webhook.DeliveryHeader)))
+}
@@ -0,0 +1,302 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const (
+	// TargetReadWrite connects only to the primary.
+	TargetReadWrite = "read-write"
+
+	// TargetAny connects to the first reachable host.
+	TargetAny = "any"
+
+	// TargetPreferStandby connects to a standby, or to the primary if
+	// none of the standbys is reachable.
+	TargetPreferStandby = "prefer-standby"
+
+	// readOnlyTransaction is the code of the error returned by a standby,
+	// or a demoted primary, on write.
+	readOnlyTransaction = "25006"
+)
+
+var (
+	// ErrMalformedConfig indicates that the connection options are malformed.
+	ErrMalformedConfig = errors.New("malformed PostgreSQL connection options")
+
+	// ErrNoHost indicates that none of the hosts matches the target.
+	ErrNoHost = errors.New("none of the PostgreSQL hosts matches the target")
+
+	syncCommitLevels = map[string]bool{
+		"on":           true,
+		"off":          true,
+		"local":        true,
+		"remote_write": true,
+		"remote_apply": true,
+	}
+)
+
+// Config defines the options of the connection to a PostgreSQL deployment.
+type Config struct {
+	// Hosts is a comma separated list of host[:port] addresses, that are
+	// tried in the given order.
+	Hosts string
+
+	// Port is used for the hosts without an explicit port.
+	Port string
+
+	// DSN holds the remaining key=value connection parameters.
+	DSN string
+
+	// Target is one of TargetReadWrite (default), TargetAny and
+	// TargetPreferStandby.
+	Target string
+
+	// SyncCommit is the synchronous_commit level of the sessions. Setting
+	// it to remote_apply makes the committed writes visible on the
+	// synchronous standbys, which gives read-after-write consistency to
+	// the services reading from them.
+	SyncCommit string
+}
+
+// Open returns the database handle whose connections are made to the first
+// host matching the target. The pool discards the connections broken by a
+// failover, and the new ones are made to the promoted host.
+func Open(cfg Config) (*sqlx.DB, error) {
+	c, err := newConnector(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return sqlx.NewDb(sql.OpenDB(c), "postgres"), nil
+}
+
+// Addrs returns the addresses of the hosts, completed with the default port.
+func Addrs(hosts, port string) ([]string, error) {
+	addrs := []string{}
+	for _, host := range strings.Split(hosts, ",") {
+		host = strings.TrimSpace(host)
+		if host == "" {
+			continue
+		}
+
+		if _, _, err := net.SplitHostPort(host); err != nil {
+			host = net.JoinHostPort(host, port)
+		}
+		addrs = append(addrs, host)
+	}
+
+	if len(addrs) == 0 {
+		return nil, ErrMalformedConfig
+	}
+
+	return addrs, nil
+}
+
+var _ driver.Connector = (*connector)(nil)
+
+type connector struct {
+	dsns       []string
+	target     string
+	syncCommit string
+}
+
+func newConnector(cfg Config) (*connector, error) {
+	target := cfg.Target
+	if target == "" {
+		target = TargetReadWrite
+	}
+
+	switch target {
+	case TargetReadWrite, TargetAny, TargetPreferStandby:
+	default:
+		return nil, ErrMalformedConfig
+	}
+
+	if cfg.SyncCommit != "" && !syncCommitLevels[cfg.SyncCommit] {
+		return nil, ErrMalformedConfig
+	}
+
+	addrs, err := Addrs(cfg.Hosts, cfg.Port)
+	if err != nil {
+		return nil, err
+	}
+
+	dsns := []string{}
+	for _, addr := range addrs {
+		host, port, _ := net.SplitHostPort(addr)
+		dsns = append(dsns, fmt.Sprintf("host=%s port=%s %s", host, port, cfg.DSN))
+	}
+
+	return &connector{
+		dsns:       dsns,
+		target:     target,
+		syncCommit: cfg.SyncCommit,
+	}, nil
+}
+
+func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
+	var fallback driver.Conn
+	err := ErrNoHost
+
+	for _, dsn := range c.dsns {
+		dc, e := pq.DialOpen(dialer{ctx}, dsn)
+		if e != nil {
+			err = e
+			continue
+		}
+
+		if c.target == TargetAny {
+			return c.init(ctx, dc)
+		}
+
+		ro, e := readOnly(ctx, dc)
+		if e != nil {
+			dc.Close()
+			err = e
+			continue
+		}
+
+		switch {
+		case c.target == TargetReadWrite && !ro,
+			c.target == TargetPreferStandby && ro:
+			if fallback != nil {
+				fallback.Close()
+			}
+			return c.init(ctx, dc)
+		case c.target == TargetPreferStandby && fallback == nil:
+			fallback = dc
+		default:
+			dc.Close()
+		}
+	}
+
+	if fallback != nil {
+		return c.init(ctx, fallback)
+	}
+
+	return nil, err
+}
+
+func (c *connector) Driver() driver.Driver {
+	return &pq.Driver{}
+}
+
+func (c *connector) init(ctx context.Context, dc driver.Conn) (driver.Conn, error) {
+	if c.syncCommit != "" {
+		q := fmt.Sprintf("SET synchronous_commit TO %s", c.syncCommit)
+		if _, err := dc.(driver.ExecerContext).ExecContext(ctx, q, nil); err != nil {
+			dc.Close()
+			return nil, err
+		}
+	}
+
+	return &conn{Conn: dc, primary: c.target == TargetReadWrite}, nil
+}
+
+func readOnly(ctx context.Context, dc driver.Conn) (bool, error) {
+	rows, err := dc.(driver.QueryerContext).QueryContext(ctx, "SHOW transaction_read_only", nil)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	vals := make([]driver.Value, 1)
+	if err := rows.Next(vals); err != nil {
+		return false, err
+	}
+
+	switch v := vals[0].(type) {
+	case []byte:
+		return string(v) == "on", nil
+	case string:
+		return v == "on", nil
+	default:
+		return false, nil
+	}
+}
+
+// conn marks itself invalid once the host it's connected to stops accepting
+// writes, so that the pool replaces it with a connection to the new primary.
+type conn struct {
+	driver.Conn
+	primary bool
+	bad     int32
+}
+
+var (
+	_ driver.QueryerContext  = (*conn)(nil)
+	_ driver.ExecerContext   = (*conn)(nil)
+	_ driver.ConnBeginTx     = (*conn)(nil)
+	_ driver.SessionResetter = (*conn)(nil)
+	_ driver.Validator       = (*conn)(nil)
+)
+
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	rows, err := c.Conn.(driver.QueryerContext).QueryContext(ctx, query, args)
+	return rows, c.check(err)
+}
+
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	res, err := c.Conn.(driver.ExecerContext).ExecContext(ctx, query, args)
+	return res, c.check(err)
+}
+
+func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	tx, err := c.Conn.(driver.ConnBeginTx).BeginTx(ctx, opts)
+	return tx, c.check(err)
+}
+
+func (c *conn) ResetSession(ctx context.Context) error {
+	if !c.IsValid() {
+		return driver.ErrBadConn
+	}
+	return nil
+}
+
+func (c *conn) IsValid() bool {
+	return atomic.LoadInt32(&c.bad) == 0
+}
+
+func (c *conn) check(err error) error {
+	if e, ok := err.(*pq.Error); ok && c.primary && e.Code == readOnlyTransaction {
+		atomic.StoreInt32(&c.bad, 1)
+	}
+	return err
+}
+
+// dialer makes the connection attempts cancelable by the pool.
+type dialer struct {
+	ctx context.Context
+}
+
+func (d dialer) Dial(network, address string) (net.Conn, error) {
+	var nd net.Dialer
+	return nd.DialContext(d.ctx, network, address)
+}
+
+func (d dialer) DialTimeout(network, address string, timeout time.Duration) (net.Conn, error) {
+	ctx, cancel := context.WithTimeout(d.ctx, timeout)
+	defer cancel()
+
+	var nd net.Dialer
+	return nd.DialContext(ctx, network, address)
+}
@@ -0,0 +1,92 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package postgres_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux/postgres"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddrs(t *testing.T) {
+	cases := []struct {
+		desc  string
+		hosts string
+		addrs []string
+		err   error
+	}{
+		{
+			desc:  "single host",
+			hosts: "localhost",
+			addrs: []string{"localhost:5432"},
+		},
+		{
+			desc:  "multiple hosts with ports",
+			hosts: "pg-1, pg-2:5433,pg-3",
+			addrs: []string{"pg-1:5432", "pg-2:5433", "pg-3:5432"},
+		},
+		{
+			desc:  "IPv6 host",
+			hosts: "[::1]:5433",
+			addrs: []string{"[::1]:5433"},
+		},
+		{
+			desc:  "empty hosts",
+			hosts: " , ",
+			err:   postgres.ErrMalformedConfig,
+		},
+	}
+
+	for _, tc := range cases {
+		addrs, err := postgres.Addrs(tc.hosts, "5432")
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.addrs, addrs, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.addrs, addrs))
+	}
+}
+
+func TestOpen(t *testing.T) {
+	cases := []struct {
+		desc string
+		cfg  postgres.Config
+		err  error
+	}{
+		{
+			desc: "open with default target",
+			cfg:  postgres.Config{Hosts: "pg-1,pg-2", Port: "5432"},
+		},
+		{
+			desc: "open standby preferring handle with remote apply",
+			cfg:  postgres.Config{Hosts: "pg-1,pg-2", Port: "5432", Target: postgres.TargetPreferStandby, SyncCommit: "remote_apply"},
+		},
+		{
+			desc: "open with unknown target",
+			cfg:  postgres.Config{Hosts: "pg-1", Port: "5432", Target: "primary"},
+			err:  postgres.ErrMalformedConfig,
+		},
+		{
+			desc: "open with unknown commit level",
+			cfg:  postgres.Config{Hosts: "pg-1", Port: "5432", SyncCommit: "on; DROP TABLE things"},
+			err:  postgres.ErrMalformedConfig,
+		},
+		{
+			desc: "open without hosts",
+			cfg:  postgres.Config{Port: "5432"},
+			err:  postgres.ErrMalformedConfig,
+		},
+	}
+
+	for _, tc := range cases {
+		db, err := postgres.Open(tc.cfg)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if db != nil {
+			db.Close()
+		}
+	}
+}
@@ -0,0 +1,11 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// Package postgres contains the PostgreSQL connection setup shared by the
+// services, that supports the multi-host deployments with the primary
+// failover.
+package postgres
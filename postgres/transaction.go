@@ -0,0 +1,70 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const (
+	// serializationFailure is the code of the error returned when the
+	// transaction conflicts with the concurrent one. CockroachDB runs all
+	// the transactions serializably, and expects the clients to retry the
+	// ones failing due to the contention.
+	serializationFailure = "40001"
+
+	maxRetries   = 5
+	retryBackoff = 10 * time.Millisecond
+)
+
+// Transact runs fn within the transaction, that is committed if fn succeeds
+// and rolled back otherwise. The transaction failing due to the
+// serialization conflict is run again, up to five times, waiting for the
+// exponentially growing period in between, so fn must not depend on the
+// state left by its previous runs.
+func Transact(ctx context.Context, db *sqlx.DB, fn func(*sqlx.Tx) error) error {
+	backoff := retryBackoff
+	for i := 0; ; i++ {
+		err := transact(ctx, db, fn)
+		if !Retryable(err) || i == maxRetries {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+			backoff *= 2
+		}
+	}
+}
+
+// Retryable returns true if the error is caused by the serialization
+// conflict, i.e. if the failed transaction can be run again.
+func Retryable(err error) bool {
+	e, ok := err.(*pq.Error)
+	return ok && e.Code == serializationFailure
+}
+
+func transact(ctx context.Context, db *sqlx.DB, fn func(*sqlx.Tx) error) error {
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
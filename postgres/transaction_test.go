@@ -0,0 +1,52 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package postgres_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/mainflux/mainflux/postgres"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryable(t *testing.T) {
+	cases := []struct {
+		desc      string
+		err       error
+		retryable bool
+	}{
+		{
+			desc:      "serialization failure",
+			err:       &pq.Error{Code: "40001"},
+			retryable: true,
+		},
+		{
+			desc:      "unique violation",
+			err:       &pq.Error{Code: "23505"},
+			retryable: false,
+		},
+		{
+			desc:      "non-database error",
+			err:       errors.New("connection refused"),
+			retryable: false,
+		},
+		{
+			desc:      "no error",
+			err:       nil,
+			retryable: false,
+		},
+	}
+
+	for _, tc := range cases {
+		retryable := postgres.Retryable(tc.err)
+		assert.Equal(t, tc.retryable, retryable, fmt.Sprintf("%s: expected %t got %t\n", tc.desc, tc.retryable, retryable))
+	}
+}
@@ -0,0 +1,79 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package publish
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/gogo/protobuf/proto"
+	"github.com/mainflux/mainflux"
+	broker "github.com/nats-io/go-nats"
+)
+
+// deliveryPrefix prefixes the keys of the channels' delivery modes, that the
+// things service maintains.
+const deliveryPrefix = "delivery"
+
+// ErrNotAcknowledged indicates that the message published with the
+// at-least-once delivery wasn't acknowledged in time, so it may be lost.
+var ErrNotAcknowledged = errors.New("message not acknowledged")
+
+// Delivery returns the hook stamping the messages with the delivery mode of
+// their channel, that the things service keeps in the Redis the client is
+// connected to. Messages whose channel has no delivery mode, or whose mode
+// can't be retrieved because Redis is unavailable, are left unstamped. The
+// hook is a no-op if client is nil.
+func Delivery(client redis.UniversalClient) mainflux.PublishHook {
+	if client == nil {
+		return func(next mainflux.MessagePublisher) mainflux.MessagePublisher {
+			return next
+		}
+	}
+
+	return func(next mainflux.MessagePublisher) mainflux.MessagePublisher {
+		return mainflux.PublisherFunc(func(msg mainflux.RawMessage) error {
+			if msg.Delivery == "" {
+				key := fmt.Sprintf("%s:%s", deliveryPrefix, msg.Channel)
+				if mode, err := client.Get(key).Result(); err == nil {
+					msg.Delivery = mode
+				}
+			}
+
+			return next.Publish(msg)
+		})
+	}
+}
+
+// Deliver publishes the message to the subject according to its delivery
+// mode. Messages delivered at least once are published as requests, which
+// the normalizer replies to once it has passed the message on or kept it as
+// a dead letter. ErrNotAcknowledged is returned if the reply doesn't arrive
+// within the timeout. All the other messages are published without waiting
+// for the reply.
+func Deliver(nc *broker.Conn, subject string, msg mainflux.RawMessage, timeout time.Duration) error {
+	data, err := proto.Marshal(&msg)
+	if err != nil {
+		return err
+	}
+
+	if msg.Delivery != mainflux.DeliveryAtLeastOnce {
+		return nc.Publish(subject, data)
+	}
+
+	if _, err := nc.Request(subject, data, timeout); err != nil {
+		if err == broker.ErrTimeout {
+			return ErrNotAcknowledged
+		}
+		return err
+	}
+
+	return nil
+}
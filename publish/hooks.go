@@ -0,0 +1,130 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// Package publish contains the publish hooks shared by the Mainflux adapters.
+// Hooks are composed using mainflux.Chain, so that every adapter applies the
+// same validation, enrichment and rate limiting to the messages it publishes.
+//
+// Authentication remains the responsibility of the adapters' transports,
+// since credentials are protocol specific and the publisher ID they resolve
+// is already part of the message that enters the chain.
+package publish
+
+import (
+	"errors"
+	"net"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/mainflux/mainflux"
+)
+
+// SenMLJSON is the content type of the SenML JSON messages, assumed for the
+// protocols that don't carry the content type.
+const SenMLJSON = "application/senml+json"
+
+var (
+	// ErrMalformedMessage indicates that the message lacks the channel or
+	// the publisher.
+	ErrMalformedMessage = errors.New("malformed message")
+
+	// ErrMessageTooLarge indicates that the message payload exceeds the
+	// allowed size.
+	ErrMessageTooLarge = errors.New("message payload too large")
+
+	// ErrRateLimited indicates that the publisher exceeded its message rate.
+	ErrRateLimited = errors.New("publish rate limit exceeded")
+
+	// ErrProtocolNotAllowed indicates that the channel's owner isn't allowed
+	// to publish over the message protocol.
+	ErrProtocolNotAllowed = errors.New("protocol not allowed")
+
+	// ErrTransformFailed indicates that the message payload doesn't match
+	// the transformation template of its publisher or channel.
+	ErrTransformFailed = errors.New("payload transformation failed")
+)
+
+// Validate returns the hook rejecting messages without the channel or the
+// publisher, as well as the ones whose payload exceeds maxSize bytes. Size
+// isn't checked if maxSize is not positive.
+func Validate(maxSize int64) mainflux.PublishHook {
+	return func(next mainflux.MessagePublisher) mainflux.MessagePublisher {
+		return mainflux.PublisherFunc(func(msg mainflux.RawMessage) error {
+			if msg.Channel == "" || msg.Publisher == "" {
+				return ErrMalformedMessage
+			}
+
+			if maxSize > 0 && int64(len(msg.Payload)) > maxSize {
+				return ErrMessageTooLarge
+			}
+
+			return next.Publish(msg)
+		})
+	}
+}
+
+// ContentType returns the hook setting the content type of the messages
+// that are published without one.
+func ContentType(contentType string) mainflux.PublishHook {
+	return func(next mainflux.MessagePublisher) mainflux.MessagePublisher {
+		return mainflux.PublisherFunc(func(msg mainflux.RawMessage) error {
+			if msg.ContentType == "" {
+				msg.ContentType = contentType
+			}
+
+			return next.Publish(msg)
+		})
+	}
+}
+
+// Ingress returns the hook stamping the messages with the time they were
+// received at and a unique ID, unless the adapter already did so. The ID
+// follows the message through the pipeline, so that the writers recognize
+// its redeliveries. If anonymize is set, the publisher address is reduced to
+// its /24 (IPv4) or /48 (IPv6) network and the port is dropped, so that the
+// stored messages can't identify a client.
+func Ingress(anonymize bool) mainflux.PublishHook {
+	return func(next mainflux.MessagePublisher) mainflux.MessagePublisher {
+		return mainflux.PublisherFunc(func(msg mainflux.RawMessage) error {
+			if msg.Received == 0 {
+				msg.Received = float64(time.Now().UnixNano()) / 1e9
+			}
+
+			if msg.Id == "" {
+				id, err := uuid.NewV4()
+				if err != nil {
+					return err
+				}
+				msg.Id = id.String()
+			}
+
+			if anonymize && msg.RemoteAddr != "" {
+				msg.RemoteAddr = mask(msg.RemoteAddr)
+			}
+
+			return next.Publish(msg)
+		})
+	}
+}
+
+func mask(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return ""
+	}
+
+	if ip4 := ip.To4(); ip4 != nil {
+		return ip4.Mask(net.CIDRMask(24, 32)).String()
+	}
+
+	return ip.Mask(net.CIDRMask(48, 128)).String()
+}
@@ -0,0 +1,297 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package publish_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/publish"
+	"github.com/stretchr/testify/assert"
+)
+
+var msg = mainflux.RawMessage{
+	Channel:   "1",
+	Publisher: "1",
+	Protocol:  "http",
+	Payload:   []byte(`[{"n":"current","t":-5,"v":1.2}]`),
+}
+
+type recorder struct {
+	msgs []mainflux.RawMessage
+}
+
+func (r *recorder) Publish(msg mainflux.RawMessage) error {
+	r.msgs = append(r.msgs, msg)
+	return nil
+}
+
+func TestChain(t *testing.T) {
+	var order []string
+	hook := func(name string) mainflux.PublishHook {
+		return func(next mainflux.MessagePublisher) mainflux.MessagePublisher {
+			return mainflux.PublisherFunc(func(msg mainflux.RawMessage) error {
+				order = append(order, name)
+				return next.Publish(msg)
+			})
+		}
+	}
+
+	rec := &recorder{}
+	pub := mainflux.Chain(rec, hook("first"), hook("second"), hook("third"))
+
+	err := pub.Publish(msg)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, []string{"first", "second", "third"}, order, "hooks invoked out of order")
+	assert.Len(t, rec.msgs, 1, "message not published")
+}
+
+func TestValidate(t *testing.T) {
+	pub := mainflux.Chain(&recorder{}, publish.Validate(int64(len(msg.Payload))))
+
+	large := msg
+	large.Payload = append(msg.Payload, ' ')
+
+	noChannel := msg
+	noChannel.Channel = ""
+
+	noPublisher := msg
+	noPublisher.Publisher = ""
+
+	cases := []struct {
+		desc string
+		msg  mainflux.RawMessage
+		err  error
+	}{
+		{
+			desc: "publish valid message",
+			msg:  msg,
+			err:  nil,
+		},
+		{
+			desc: "publish too large message",
+			msg:  large,
+			err:  publish.ErrMessageTooLarge,
+		},
+		{
+			desc: "publish message without channel",
+			msg:  noChannel,
+			err:  publish.ErrMalformedMessage,
+		},
+		{
+			desc: "publish message without publisher",
+			msg:  noPublisher,
+			err:  publish.ErrMalformedMessage,
+		},
+	}
+
+	for _, tc := range cases {
+		err := pub.Publish(tc.msg)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+	}
+}
+
+func TestContentType(t *testing.T) {
+	rec := &recorder{}
+	pub := mainflux.Chain(rec, publish.ContentType("application/senml+json"))
+
+	typed := msg
+	typed.ContentType = "application/json"
+
+	cases := []struct {
+		desc        string
+		msg         mainflux.RawMessage
+		contentType string
+	}{
+		{
+			desc:        "publish message without content type",
+			msg:         msg,
+			contentType: "application/senml+json",
+		},
+		{
+			desc:        "publish message with content type",
+			msg:         typed,
+			contentType: "application/json",
+		},
+	}
+
+	for i, tc := range cases {
+		err := pub.Publish(tc.msg)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		ct := rec.msgs[i].ContentType
+		assert.Equal(t, tc.contentType, ct, fmt.Sprintf("%s: expected content type %s got %s", tc.desc, tc.contentType, ct))
+	}
+}
+
+func TestIngress(t *testing.T) {
+	stamped := msg
+	stamped.Received = 1
+	stamped.Id = "123e4567-e89b-12d3-a456-426655440000"
+
+	cases := []struct {
+		desc       string
+		anonymize  bool
+		remoteAddr string
+		expected   string
+	}{
+		{
+			desc:       "publish message from IPv4 address",
+			anonymize:  false,
+			remoteAddr: "192.168.1.17:50321",
+			expected:   "192.168.1.17:50321",
+		},
+		{
+			desc:       "publish message from anonymized IPv4 address",
+			anonymize:  true,
+			remoteAddr: "192.168.1.17:50321",
+			expected:   "192.168.1.0",
+		},
+		{
+			desc:       "publish message from anonymized IPv6 address",
+			anonymize:  true,
+			remoteAddr: "[2001:db8:85a3:8d3:1319:8a2e:370:7348]:443",
+			expected:   "2001:db8:85a3::",
+		},
+		{
+			desc:       "publish message from anonymized address without port",
+			anonymize:  true,
+			remoteAddr: "10.0.0.5",
+			expected:   "10.0.0.0",
+		},
+		{
+			desc:       "publish message from anonymized malformed address",
+			anonymize:  true,
+			remoteAddr: "localhost",
+			expected:   "",
+		},
+	}
+
+	for _, tc := range cases {
+		rec := &recorder{}
+		pub := mainflux.Chain(rec, publish.Ingress(tc.anonymize))
+
+		m := msg
+		m.RemoteAddr = tc.remoteAddr
+		err := pub.Publish(m)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		assert.Equal(t, tc.expected, rec.msgs[0].RemoteAddr, fmt.Sprintf("%s: expected address %s got %s", tc.desc, tc.expected, rec.msgs[0].RemoteAddr))
+		assert.NotZero(t, rec.msgs[0].Received, fmt.Sprintf("%s: message not stamped", tc.desc))
+		assert.NotEmpty(t, rec.msgs[0].Id, fmt.Sprintf("%s: message ID not set", tc.desc))
+	}
+
+	rec := &recorder{}
+	pub := mainflux.Chain(rec, publish.Ingress(false))
+	err := pub.Publish(stamped)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, stamped.Received, rec.msgs[0].Received, "adapter receive time overwritten")
+	assert.Equal(t, stamped.Id, rec.msgs[0].Id, "adapter message ID overwritten")
+}
+
+func TestRateLimit(t *testing.T) {
+	pub := mainflux.Chain(&recorder{}, publish.RateLimit(1, 2))
+
+	other := msg
+	other.Publisher = "2"
+
+	cases := []struct {
+		desc string
+		msg  mainflux.RawMessage
+		err  error
+	}{
+		{
+			desc: "publish first message within burst",
+			msg:  msg,
+			err:  nil,
+		},
+		{
+			desc: "publish second message within burst",
+			msg:  msg,
+			err:  nil,
+		},
+		{
+			desc: "publish message over the limit",
+			msg:  msg,
+			err:  publish.ErrRateLimited,
+		},
+		{
+			desc: "publish message as another publisher",
+			msg:  other,
+			err:  nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := pub.Publish(tc.msg)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+	}
+}
+
+func TestRateLimitDisabled(t *testing.T) {
+	pub := mainflux.Chain(&recorder{}, publish.RateLimit(0, 0))
+
+	for i := 0; i < 10; i++ {
+		err := pub.Publish(msg)
+		assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+}
+
+func TestChannelRateLimitDisabled(t *testing.T) {
+	pub := mainflux.Chain(&recorder{}, publish.ChannelRateLimit(nil))
+
+	for i := 0; i < 10; i++ {
+		err := pub.Publish(msg)
+		assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+}
+
+func TestChannelProtocolsDisabled(t *testing.T) {
+	pub := mainflux.Chain(&recorder{}, publish.ChannelProtocols(nil))
+
+	err := pub.Publish(msg)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+}
+
+func TestTransformDisabled(t *testing.T) {
+	rec := &recorder{}
+	pub := mainflux.Chain(rec, publish.Transform(nil))
+
+	err := pub.Publish(msg)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, msg.Payload, rec.msgs[0].Payload, "message payload changed")
+}
+
+func TestSequenceDisabled(t *testing.T) {
+	rec := &recorder{}
+	pub := mainflux.Chain(rec, publish.Sequence(nil))
+
+	numbered := msg
+	numbered.Seq = 42
+
+	for _, m := range []mainflux.RawMessage{msg, numbered} {
+		err := pub.Publish(m)
+		assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+	assert.Equal(t, uint64(0), rec.msgs[0].Seq, "message numbered without counters")
+	assert.Equal(t, numbered.Seq, rec.msgs[1].Seq, "message sequence number overwritten")
+}
+
+func TestDeliveryDisabled(t *testing.T) {
+	rec := &recorder{}
+	pub := mainflux.Chain(rec, publish.Delivery(nil))
+
+	stamped := msg
+	stamped.Delivery = mainflux.DeliveryAtLeastOnce
+
+	for _, m := range []mainflux.RawMessage{msg, stamped} {
+		err := pub.Publish(m)
+		assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+	assert.Equal(t, "", rec.msgs[0].Delivery, "message stamped without delivery modes")
+	assert.Equal(t, stamped.Delivery, rec.msgs[1].Delivery, "message delivery mode overwritten")
+}
@@ -0,0 +1,56 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package publish
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-redis/redis"
+	"github.com/mainflux/mainflux"
+)
+
+// protocolsPrefix prefixes the keys of the comma separated protocols the
+// channels can be published to over, that the things service maintains
+// according to the owners' configuration overrides.
+const protocolsPrefix = "protocols"
+
+// ChannelProtocols returns the hook rejecting the messages published over
+// the protocol their channel's owner isn't allowed to use, with
+// ErrProtocolNotAllowed. The allowed protocols are kept by the things service
+// in the Redis the client is connected to. Messages whose channel has no
+// allowed protocols, or whose protocols can't be retrieved because Redis is
+// unavailable, are let through. The hook is a no-op if client is nil.
+func ChannelProtocols(client redis.UniversalClient) mainflux.PublishHook {
+	if client == nil {
+		return func(next mainflux.MessagePublisher) mainflux.MessagePublisher {
+			return next
+		}
+	}
+
+	return func(next mainflux.MessagePublisher) mainflux.MessagePublisher {
+		return mainflux.PublisherFunc(func(msg mainflux.RawMessage) error {
+			key := fmt.Sprintf("%s:%s", protocolsPrefix, msg.Channel)
+			if protocols, err := client.Get(key).Result(); err == nil && !allowed(protocols, msg.Protocol) {
+				return ErrProtocolNotAllowed
+			}
+
+			return next.Publish(msg)
+		})
+	}
+}
+
+func allowed(protocols, protocol string) bool {
+	for _, p := range strings.Split(protocols, ",") {
+		if p == protocol {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,144 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package publish
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/mainflux/mainflux"
+)
+
+// rateLimitPrefix prefixes the keys of the hashes holding the channels' rate
+// limits, that the things service maintains. The hook keeps the state of the
+// channel's token bucket in the same hash.
+const rateLimitPrefix = "ratelimit"
+
+// takeToken takes a token from the channel's bucket, refilled at the rate
+// since the last take, and returns 0 if there's none left. Channels without
+// the rate limit always get a token.
+var takeToken = redis.NewScript(`
+local limit = redis.call('HMGET', KEYS[1], 'rate', 'burst', 'tokens', 'last')
+local rate = tonumber(limit[1])
+if not rate then
+	return 1
+end
+
+local burst = math.max(tonumber(limit[2]) or 1, 1)
+local now = tonumber(ARGV[1])
+local tokens = tonumber(limit[3]) or burst
+local last = tonumber(limit[4]) or now
+
+tokens = math.min(burst, tokens + math.max(now - last, 0) * rate)
+local taken = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	taken = 1
+end
+
+redis.call('HMSET', KEYS[1], 'tokens', tokens, 'last', now)
+return taken
+`)
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+type limiter struct {
+	rate    float64
+	burst   float64
+	buckets map[string]*bucket
+	mu      sync.Mutex
+}
+
+// RateLimit returns the hook allowing each publisher to publish at most rate
+// messages per second, with bursts of up to burst messages. Messages over
+// the limit are rejected with ErrRateLimited. The hook is a no-op if rate is
+// not positive.
+func RateLimit(rate float64, burst uint) mainflux.PublishHook {
+	if rate <= 0 {
+		return func(next mainflux.MessagePublisher) mainflux.MessagePublisher {
+			return next
+		}
+	}
+
+	if burst == 0 {
+		burst = 1
+	}
+
+	l := &limiter{
+		rate:    rate,
+		burst:   float64(burst),
+		buckets: make(map[string]*bucket),
+	}
+
+	return func(next mainflux.MessagePublisher) mainflux.MessagePublisher {
+		return mainflux.PublisherFunc(func(msg mainflux.RawMessage) error {
+			if !l.allow(msg.Publisher, time.Now()) {
+				return ErrRateLimited
+			}
+
+			return next.Publish(msg)
+		})
+	}
+}
+
+func (l *limiter) allow(key string, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens += now.Sub(b.last).Seconds() * l.rate
+	if b.tokens > l.burst {
+		b.tokens = l.burst
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+
+	b.tokens--
+	return true
+}
+
+// ChannelRateLimit returns the hook enforcing the channels' message rate
+// limits, that the things service keeps in the Redis the client is connected
+// to. Token buckets are kept in Redis as well, so that the limits hold across
+// the adapter instances. Messages over the limit are rejected with
+// ErrRateLimited, while the ones that can't be checked because Redis is
+// unavailable are let through. The hook is a no-op if client is nil.
+func ChannelRateLimit(client redis.UniversalClient) mainflux.PublishHook {
+	if client == nil {
+		return func(next mainflux.MessagePublisher) mainflux.MessagePublisher {
+			return next
+		}
+	}
+
+	return func(next mainflux.MessagePublisher) mainflux.MessagePublisher {
+		return mainflux.PublisherFunc(func(msg mainflux.RawMessage) error {
+			key := fmt.Sprintf("%s:%s", rateLimitPrefix, msg.Channel)
+			now := float64(time.Now().UnixNano()) / float64(time.Second)
+
+			taken, err := takeToken.Run(client, []string{key}, now).Int64()
+			if err == nil && taken == 0 {
+				return ErrRateLimited
+			}
+
+			return next.Publish(msg)
+		})
+	}
+}
@@ -0,0 +1,48 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package publish
+
+import (
+	"fmt"
+
+	"github.com/go-redis/redis"
+	"github.com/mainflux/mainflux"
+)
+
+// seqPrefix prefixes the keys of the channels' sequence counters.
+const seqPrefix = "seq"
+
+// Sequence returns the hook numbering the messages of each channel with
+// consecutive sequence numbers, starting from 1, so that the consumers can
+// detect the missing messages and request them precisely. Counters are kept
+// in the Redis the client is connected to, so that the numbers are unique
+// across the adapter instances. The hook has to be the last one in the
+// chain, so that the rejected messages don't take up numbers. Messages that
+// can't be numbered because Redis is unavailable are let through with zero
+// sequence number, which the consumers treat as unknown. The hook is a no-op
+// if client is nil.
+func Sequence(client redis.UniversalClient) mainflux.PublishHook {
+	if client == nil {
+		return func(next mainflux.MessagePublisher) mainflux.MessagePublisher {
+			return next
+		}
+	}
+
+	return func(next mainflux.MessagePublisher) mainflux.MessagePublisher {
+		return mainflux.PublisherFunc(func(msg mainflux.RawMessage) error {
+			if msg.Seq == 0 {
+				key := fmt.Sprintf("%s:%s", seqPrefix, msg.Channel)
+				if seq, err := client.Incr(key).Result(); err == nil {
+					msg.Seq = uint64(seq)
+				}
+			}
+
+			return next.Publish(msg)
+		})
+	}
+}
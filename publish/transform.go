@@ -0,0 +1,76 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package publish
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-redis/redis"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/things"
+)
+
+// Prefixes of the keys of the things' and the channels' payload
+// transformations, that the things service maintains.
+const (
+	thingTransformPrefix   = "thing_transform"
+	channelTransformPrefix = "transform"
+)
+
+// Transform returns the hook replacing the payloads of the messages with the
+// ones produced by the transformation templates of their publishers or, if
+// the publisher has none, of their channels, that the things service keeps
+// in the Redis the client is connected to. Messages that don't match the
+// template are rejected with ErrTransformFailed. Unlike the rate limits, the
+// transformations can't be skipped, so the messages are rejected if Redis is
+// unavailable. The hook is a no-op if client is nil.
+func Transform(client redis.UniversalClient) mainflux.PublishHook {
+	if client == nil {
+		return func(next mainflux.MessagePublisher) mainflux.MessagePublisher {
+			return next
+		}
+	}
+
+	return func(next mainflux.MessagePublisher) mainflux.MessagePublisher {
+		return mainflux.PublisherFunc(func(msg mainflux.RawMessage) error {
+			tkey := fmt.Sprintf("%s:%s", thingTransformPrefix, msg.Publisher)
+			ckey := fmt.Sprintf("%s:%s", channelTransformPrefix, msg.Channel)
+
+			vals, err := client.MGet(tkey, ckey).Result()
+			if err != nil {
+				return err
+			}
+
+			for _, val := range vals {
+				data, ok := val.(string)
+				if !ok {
+					continue
+				}
+
+				var tr things.Transform
+				if err := json.Unmarshal([]byte(data), &tr); err != nil {
+					return err
+				}
+
+				payload, err := tr.Apply(msg.Payload)
+				if err != nil {
+					return ErrTransformFailed
+				}
+
+				msg.Payload = payload
+				if tr.ContentType != "" {
+					msg.ContentType = tr.ContentType
+				}
+				break
+			}
+
+			return next.Publish(msg)
+		})
+	}
+}
@@ -13,3 +13,27 @@ type MessagePublisher interface {
 	// operation failure.
 	Publish(RawMessage) error
 }
+
+// PublishHook wraps the message publisher with a cross-cutting concern of
+// the adapters' publish path, such as validation or rate limiting. A hook
+// either passes the (possibly modified) message on to the next publisher or
+// rejects it by returning an error.
+type PublishHook func(MessagePublisher) MessagePublisher
+
+// PublisherFunc is an adapter allowing the use of an ordinary function as
+// the message publisher.
+type PublisherFunc func(RawMessage) error
+
+// Publish calls f(msg).
+func (f PublisherFunc) Publish(msg RawMessage) error {
+	return f(msg)
+}
+
+// Chain returns the publisher that passes messages through the given hooks,
+// in order, before handing them over to pub.
+func Chain(pub MessagePublisher, hooks ...PublishHook) MessagePublisher {
+	for i := len(hooks) - 1; i >= 0; i-- {
+		pub = hooks[i](pub)
+	}
+	return pub
+}
@@ -0,0 +1,10 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// Package ratelimit contains the HTTP middleware limiting the number of the
+// requests each client makes to the service API, shared by the services.
+package ratelimit
@@ -0,0 +1,178 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/mainflux/mainflux"
+)
+
+const keyPrefix = "api_ratelimit"
+
+// count counts the request within the window started by the first request
+// of the key, and returns the count along with the time left until the
+// window ends, in milliseconds.
+var count = redis.NewScript(`
+local count = redis.call('INCR', KEYS[1])
+if count == 1 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+local ttl = redis.call('PTTL', KEYS[1])
+if ttl < 0 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+	ttl = tonumber(ARGV[1])
+end
+return {count, ttl}
+`)
+
+// Result describes the state of the client's limit after the request.
+type Result struct {
+	// Allowed is false if the request is over the limit.
+	Allowed bool
+
+	// Limit is the number of the requests allowed within the window.
+	Limit uint64
+
+	// Remaining is the number of the requests left within the window.
+	Remaining uint64
+
+	// Reset is the time left until the window ends.
+	Reset time.Duration
+}
+
+// Limiter specifies the API counting the requests of the clients.
+type Limiter interface {
+	// Take counts the request of the client identified by the key, and
+	// returns whether it's allowed.
+	Take(string) (Result, error)
+}
+
+// Identifier verifies the access token and returns the identity of the
+// client holding it.
+type Identifier func(context.Context, string) (string, error)
+
+// UsersIdentifier returns the identifier verifying the user tokens using
+// the users service.
+func UsersIdentifier(users mainflux.UsersServiceClient) Identifier {
+	return func(ctx context.Context, token string) (string, error) {
+		id, err := users.Identify(ctx, &mainflux.Token{Value: token})
+		if err != nil {
+			return "", err
+		}
+
+		return id.GetValue(), nil
+	}
+}
+
+var _ Limiter = (*redisLimiter)(nil)
+
+type redisLimiter struct {
+	client redis.UniversalClient
+	prefix string
+	limit  uint64
+	window time.Duration
+}
+
+// NewLimiter returns the limiter allowing each client at most limit
+// requests within the window, keeping the counters in Redis so that the
+// limit holds across the service instances. The prefix separates the
+// counters of the services sharing the Redis instance.
+func NewLimiter(client redis.UniversalClient, prefix string, limit uint64, window time.Duration) Limiter {
+	return &redisLimiter{
+		client: client,
+		prefix: prefix,
+		limit:  limit,
+		window: window,
+	}
+}
+
+func (rl *redisLimiter) Take(key string) (Result, error) {
+	k := fmt.Sprintf("%s:%s:%s", keyPrefix, rl.prefix, key)
+	vals, err := count.Run(rl.client, []string{k}, int64(rl.window/time.Millisecond)).Result()
+	if err != nil {
+		return Result{}, err
+	}
+
+	res, ok := vals.([]interface{})
+	if !ok || len(res) != 2 {
+		return Result{}, fmt.Errorf("unexpected rate limit counter result %v", vals)
+	}
+	n, _ := res[0].(int64)
+	ttl, _ := res[1].(int64)
+
+	r := Result{
+		Allowed: uint64(n) <= rl.limit,
+		Limit:   rl.limit,
+		Reset:   time.Duration(ttl) * time.Millisecond,
+	}
+	if uint64(n) < rl.limit {
+		r.Remaining = rl.limit - uint64(n)
+	}
+
+	return r, nil
+}
+
+// Handler returns the handler rejecting the requests of the clients over the
+// limit with 429 Too Many Requests. Clients are identified by the identity
+// the identifier verifies their access token to belong to, or by the address
+// if they don't provide a valid one, so that the unverified tokens can't be
+// used to get a fresh quota. Every response holds the RateLimit-Limit,
+// RateLimit-Remaining and RateLimit-Reset headers, and the rejected ones hold
+// Retry-After as well. Requests that can't be counted because the limiter is
+// unavailable are let through. The handler is a no-op if limiter is nil.
+func Handler(limiter Limiter, identify Identifier, next http.Handler) http.Handler {
+	if limiter == nil {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		res, err := limiter.Take(clientKey(r, identify))
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		reset := strconv.FormatInt(int64(math.Ceil(res.Reset.Seconds())), 10)
+		w.Header().Set("RateLimit-Limit", strconv.FormatUint(res.Limit, 10))
+		w.Header().Set("RateLimit-Remaining", strconv.FormatUint(res.Remaining, 10))
+		w.Header().Set("RateLimit-Reset", reset)
+
+		if !res.Allowed {
+			w.Header().Set("Retry-After", reset)
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// clientKey identifies the client by the identity its access token is
+// verified to belong to, or by the address if the token is missing or
+// invalid. The tokens themselves are never kept in Redis.
+func clientKey(r *http.Request, identify Identifier) string {
+	if token := r.Header.Get("Authorization"); token != "" && identify != nil {
+		if id, err := identify(r.Context(), token); err == nil && id != "" {
+			return fmt.Sprintf("id:%s", id)
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return fmt.Sprintf("addr:%s", host)
+}
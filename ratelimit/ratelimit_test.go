@@ -0,0 +1,127 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package ratelimit_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/ratelimit"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	token      = "secret-token"
+	otherToken = "other-token"
+	limit      = 2
+)
+
+var _ ratelimit.Limiter = (*limiterMock)(nil)
+
+type limiterMock struct {
+	counts map[string]uint64
+	err    error
+}
+
+func (lm *limiterMock) Take(key string) (ratelimit.Result, error) {
+	if lm.err != nil {
+		return ratelimit.Result{}, lm.err
+	}
+
+	lm.counts[key]++
+	res := ratelimit.Result{
+		Allowed: lm.counts[key] <= limit,
+		Limit:   limit,
+		Reset:   1500 * time.Millisecond,
+	}
+	if lm.counts[key] < limit {
+		res.Remaining = limit - lm.counts[key]
+	}
+
+	return res, nil
+}
+
+// identify accepts the token and the other token, which belong to the
+// different users.
+func identify(_ context.Context, tok string) (string, error) {
+	switch tok {
+	case token:
+		return "user@example.com", nil
+	case otherToken:
+		return "other@example.com", nil
+	default:
+		return "", errors.New("invalid token")
+	}
+}
+
+func TestHandler(t *testing.T) {
+	limiter := &limiterMock{counts: map[string]uint64{}}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	h := ratelimit.Handler(limiter, identify, next)
+
+	cases := []struct {
+		desc      string
+		token     string
+		addr      string
+		status    int
+		remaining string
+		retry     string
+	}{
+		{"first request of the client", token, "10.0.0.1:1234", http.StatusOK, "1", ""},
+		{"last allowed request of the client", token, "10.0.0.1:1234", http.StatusOK, "0", ""},
+		{"request of the client over the limit", token, "10.0.0.2:1234", http.StatusTooManyRequests, "0", "2"},
+		{"request of another client", otherToken, "10.0.0.1:1234", http.StatusOK, "1", ""},
+		{"request without token", "", "10.0.0.3:1234", http.StatusOK, "1", ""},
+		{"request with invalid token", "invalid-1", "10.0.0.4:1234", http.StatusOK, "1", ""},
+		{"request with another invalid token", "invalid-2", "10.0.0.4:1234", http.StatusOK, "0", ""},
+		{"request with invalid token over the limit", "invalid-3", "10.0.0.4:1234", http.StatusTooManyRequests, "0", "2"},
+	}
+
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/things", nil)
+		req.RemoteAddr = tc.addr
+		if tc.token != "" {
+			req.Header.Set("Authorization", tc.token)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		assert.Equal(t, tc.status, w.Code, fmt.Sprintf("%s: expected status %d got %d", tc.desc, tc.status, w.Code))
+		assert.Equal(t, "2", w.Header().Get("RateLimit-Limit"), fmt.Sprintf("%s: unexpected limit header", tc.desc))
+		assert.Equal(t, tc.remaining, w.Header().Get("RateLimit-Remaining"), fmt.Sprintf("%s: unexpected remaining header", tc.desc))
+		assert.Equal(t, "2", w.Header().Get("RateLimit-Reset"), fmt.Sprintf("%s: unexpected reset header", tc.desc))
+		assert.Equal(t, tc.retry, w.Header().Get("Retry-After"), fmt.Sprintf("%s: unexpected retry header", tc.desc))
+	}
+
+	for key := range limiter.counts {
+		assert.False(t, strings.Contains(key, token), fmt.Sprintf("expected token to be hashed in key %s", key))
+	}
+}
+
+func TestHandlerUnavailableLimiter(t *testing.T) {
+	limiter := &limiterMock{err: errors.New("unavailable")}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/things", nil)
+	req.Header.Set("Authorization", token)
+	w := httptest.NewRecorder()
+	ratelimit.Handler(limiter, identify, next).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code, fmt.Sprintf("expected status %d got %d", http.StatusOK, w.Code))
+	assert.Empty(t, w.Header().Get("RateLimit-Limit"), "expected no rate limit headers")
+}
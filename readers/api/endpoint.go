@@ -35,3 +35,45 @@ func listMessagesEndpoint(svc readers.MessageRepository) endpoint.Endpoint {
 		}, nil
 	}
 }
+
+func latestMessagesEndpoint(svc readers.MessageRepository) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(latestMessagesReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		msgs, err := svc.ReadLatest(req.chanID, req.query)
+		if err != nil {
+			return nil, err
+		}
+
+		return latestRes{Messages: msgs}, nil
+	}
+}
+
+func topPublishersEndpoint(svc readers.MessageRepository) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(topPublishersReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		counts, err := svc.ReadTopPublishers(req.chanID, req.n, req.from, req.to)
+		if err != nil {
+			return nil, err
+		}
+
+		res := topRes{Publishers: []publisherRes{}}
+		for _, pc := range counts {
+			res.Publishers = append(res.Publishers, publisherRes{
+				Publisher: pc.Publisher,
+				Count:     pc.Count,
+			})
+		}
+
+		return res, nil
+	}
+}
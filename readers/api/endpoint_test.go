@@ -8,6 +8,7 @@
 package api_test
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -25,7 +26,7 @@ const (
 	token         = "1"
 	invalid       = "invalid"
 	numOfMessages = 42
-	chanID        = "1"
+	chanID        = "123e4567-e89b-12d3-a456-000000000001"
 	valueFields   = 6
 )
 
@@ -131,6 +132,11 @@ func TestReadAll(t *testing.T) {
 			token:  token,
 			status: http.StatusBadRequest,
 		},
+		"read page with malformed channel id": {
+			url:    fmt.Sprintf("%s/channels/%s/messages?offset=0&limit=10", ts.URL, invalid),
+			token:  token,
+			status: http.StatusBadRequest,
+		},
 		"read page with invalid token": {
 			url:    fmt.Sprintf("%s/channels/%s/messages?offset=0&limit=10", ts.URL, chanID),
 			token:  invalid,
@@ -175,3 +181,139 @@ func TestReadAll(t *testing.T) {
 		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected %d got %d", desc, tc.status, res.StatusCode))
 	}
 }
+
+func TestReadLatest(t *testing.T) {
+	svc := newService()
+	tc := mocks.NewThingsService()
+	ts := newServer(svc, tc)
+	defer ts.Close()
+
+	cases := map[string]struct {
+		url    string
+		token  string
+		status int
+		size   int
+	}{
+		"read latest messages": {
+			url:    fmt.Sprintf("%s/channels/%s/messages/latest", ts.URL, chanID),
+			token:  token,
+			status: http.StatusOK,
+			size:   1,
+		},
+		"read latest messages of non-existent publisher": {
+			url:    fmt.Sprintf("%s/channels/%s/messages/latest?publisher=2", ts.URL, chanID),
+			token:  token,
+			status: http.StatusOK,
+			size:   0,
+		},
+		"read latest messages with invalid token": {
+			url:    fmt.Sprintf("%s/channels/%s/messages/latest", ts.URL, chanID),
+			token:  invalid,
+			status: http.StatusForbidden,
+		},
+		"read latest messages with empty token": {
+			url:    fmt.Sprintf("%s/channels/%s/messages/latest", ts.URL, chanID),
+			token:  "",
+			status: http.StatusForbidden,
+		},
+	}
+
+	for desc, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    tc.url,
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected %d got %d", desc, tc.status, res.StatusCode))
+
+		var body struct {
+			Messages []mainflux.Message `json:"messages"`
+		}
+		json.NewDecoder(res.Body).Decode(&body)
+		assert.Len(t, body.Messages, tc.size, fmt.Sprintf("%s: expected %d messages got %d", desc, tc.size, len(body.Messages)))
+	}
+}
+
+func TestReadTopPublishers(t *testing.T) {
+	svc := newService()
+	tc := mocks.NewThingsService()
+	ts := newServer(svc, tc)
+	defer ts.Close()
+
+	cases := map[string]struct {
+		url    string
+		token  string
+		status int
+		count  uint64
+	}{
+		"read top publishers": {
+			url:    fmt.Sprintf("%s/channels/%s/publishers/top?n=5", ts.URL, chanID),
+			token:  token,
+			status: http.StatusOK,
+			count:  numOfMessages,
+		},
+		"read top publishers with default n": {
+			url:    fmt.Sprintf("%s/channels/%s/publishers/top", ts.URL, chanID),
+			token:  token,
+			status: http.StatusOK,
+			count:  numOfMessages,
+		},
+		"read top publishers with time range": {
+			url:    fmt.Sprintf("%s/channels/%s/publishers/top?from=0&to=1.5", ts.URL, chanID),
+			token:  token,
+			status: http.StatusOK,
+			count:  numOfMessages,
+		},
+		"read top publishers with zero n": {
+			url:    fmt.Sprintf("%s/channels/%s/publishers/top?n=0", ts.URL, chanID),
+			token:  token,
+			status: http.StatusBadRequest,
+		},
+		"read top publishers with invalid range": {
+			url:    fmt.Sprintf("%s/channels/%s/publishers/top?from=10&to=5", ts.URL, chanID),
+			token:  token,
+			status: http.StatusBadRequest,
+		},
+		"read top publishers with non-numeric range": {
+			url:    fmt.Sprintf("%s/channels/%s/publishers/top?from=abc", ts.URL, chanID),
+			token:  token,
+			status: http.StatusBadRequest,
+		},
+		"read top publishers with invalid token": {
+			url:    fmt.Sprintf("%s/channels/%s/publishers/top", ts.URL, chanID),
+			token:  invalid,
+			status: http.StatusForbidden,
+		},
+	}
+
+	for desc, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    tc.url,
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected %d got %d", desc, tc.status, res.StatusCode))
+		if tc.status != http.StatusOK {
+			continue
+		}
+
+		var body struct {
+			Publishers []struct {
+				Publisher string `json:"publisher"`
+				Count     uint64 `json:"count"`
+			} `json:"publishers"`
+		}
+		err = json.NewDecoder(res.Body).Decode(&body)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", desc, err))
+		assert.Len(t, body.Publishers, 1, fmt.Sprintf("%s: expected single publisher got %d", desc, len(body.Publishers)))
+		if len(body.Publishers) == 1 {
+			assert.Equal(t, tc.count, body.Publishers[0].Count, fmt.Sprintf("%s: expected count %d got %d", desc, tc.count, body.Publishers[0].Count))
+		}
+	}
+}
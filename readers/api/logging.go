@@ -13,6 +13,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/logger"
 	"github.com/mainflux/mainflux/readers"
 )
@@ -39,3 +40,19 @@ func (lm *loggingMiddleware) ReadAll(chanID string, offset, limit uint64, query
 
 	return lm.svc.ReadAll(chanID, offset, limit, query)
 }
+
+func (lm *loggingMiddleware) ReadLatest(chanID string, query map[string]string) ([]mainflux.Message, error) {
+	defer func(begin time.Time) {
+		lm.logger.Info(fmt.Sprintf(`Method read_latest for channel %s took %s to complete without errors.`, chanID, time.Since(begin)))
+	}(time.Now())
+
+	return lm.svc.ReadLatest(chanID, query)
+}
+
+func (lm *loggingMiddleware) ReadTopPublishers(chanID string, n uint64, from, to float64) ([]readers.PublisherCount, error) {
+	defer func(begin time.Time) {
+		lm.logger.Info(fmt.Sprintf(`Method read_top_publishers for channel %s and n %d took %s to complete without errors.`, chanID, n, time.Since(begin)))
+	}(time.Now())
+
+	return lm.svc.ReadTopPublishers(chanID, n, from, to)
+}
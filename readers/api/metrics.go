@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/go-kit/kit/metrics"
+	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/readers"
 )
 
@@ -42,3 +43,21 @@ func (mm *metricsMiddleware) ReadAll(chanID string, offset, limit uint64, query
 
 	return mm.svc.ReadAll(chanID, offset, limit, query)
 }
+
+func (mm *metricsMiddleware) ReadLatest(chanID string, query map[string]string) ([]mainflux.Message, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "read_latest").Add(1)
+		mm.latency.With("method", "read_latest").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.ReadLatest(chanID, query)
+}
+
+func (mm *metricsMiddleware) ReadTopPublishers(chanID string, n uint64, from, to float64) ([]readers.PublisherCount, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "read_top_publishers").Add(1)
+		mm.latency.With("method", "read_top_publishers").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.ReadTopPublishers(chanID, n, from, to)
+}
@@ -25,3 +25,31 @@ func (req listMessagesReq) validate() error {
 
 	return nil
 }
+
+type latestMessagesReq struct {
+	chanID string
+	query  map[string]string
+}
+
+func (req latestMessagesReq) validate() error {
+	return nil
+}
+
+type topPublishersReq struct {
+	chanID string
+	n      uint64
+	from   float64
+	to     float64
+}
+
+func (req topPublishersReq) validate() error {
+	if req.n < 1 {
+		return errInvalidRequest
+	}
+
+	if req.from < 0 || req.to < 0 || (req.to > 0 && req.to < req.from) {
+		return errInvalidRequest
+	}
+
+	return nil
+}
@@ -13,7 +13,11 @@ import (
 	"github.com/mainflux/mainflux"
 )
 
-var _ mainflux.Response = (*pageRes)(nil)
+var (
+	_ mainflux.Response = (*pageRes)(nil)
+	_ mainflux.Response = (*latestRes)(nil)
+	_ mainflux.Response = (*topRes)(nil)
+)
 
 type pageRes struct {
 	Total    uint64             `json:"total"`
@@ -33,3 +37,40 @@ func (res pageRes) Code() int {
 func (res pageRes) Empty() bool {
 	return false
 }
+
+type latestRes struct {
+	Messages []mainflux.Message `json:"messages"`
+}
+
+func (res latestRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res latestRes) Code() int {
+	return http.StatusOK
+}
+
+func (res latestRes) Empty() bool {
+	return false
+}
+
+type publisherRes struct {
+	Publisher string `json:"publisher"`
+	Count     uint64 `json:"count"`
+}
+
+type topRes struct {
+	Publishers []publisherRes `json:"publishers"`
+}
+
+func (res topRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res topRes) Code() int {
+	return http.StatusOK
+}
+
+func (res topRes) Empty() bool {
+	return false
+}
@@ -18,6 +18,7 @@ import (
 	kithttp "github.com/go-kit/kit/transport/http"
 	"github.com/go-zoo/bone"
 	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/identifiers"
 	"github.com/mainflux/mainflux/readers"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"google.golang.org/grpc/codes"
@@ -28,6 +29,7 @@ const (
 	contentType = "application/json"
 	defLimit    = 10
 	defOffset   = 0
+	defTop      = 10
 )
 
 var (
@@ -53,6 +55,20 @@ func MakeHandler(svc readers.MessageRepository, tc mainflux.ThingsServiceClient,
 		opts...,
 	))
 
+	mux.Get("/channels/:chanID/messages/latest", kithttp.NewServer(
+		latestMessagesEndpoint(svc),
+		decodeLatest,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Get("/channels/:chanID/publishers/top", kithttp.NewServer(
+		topPublishersEndpoint(svc),
+		decodeTop,
+		encodeResponse,
+		opts...,
+	))
+
 	mux.GetFunc("/version", mainflux.Version(svcName))
 	mux.Handle("/metrics", promhttp.Handler())
 
@@ -60,9 +76,9 @@ func MakeHandler(svc readers.MessageRepository, tc mainflux.ThingsServiceClient,
 }
 
 func decodeList(_ context.Context, r *http.Request) (interface{}, error) {
-	chanID := bone.GetValue(r, "chanID")
-	if chanID == "" {
-		return nil, errInvalidRequest
+	chanID, err := identifiers.Parse(bone.GetValue(r, "chanID"))
+	if err != nil {
+		return nil, err
 	}
 
 	if err := authorize(r, chanID); err != nil {
@@ -79,18 +95,64 @@ func decodeList(_ context.Context, r *http.Request) (interface{}, error) {
 		return nil, err
 	}
 
-	query := map[string]string{}
-	for _, name := range queryFields {
-		if value := bone.GetQuery(r, name); len(value) == 1 {
-			query[name] = value[0]
-		}
-	}
-
 	req := listMessagesReq{
 		chanID: chanID,
 		offset: offset,
 		limit:  limit,
-		query:  query,
+		query:  parseQuery(r),
+	}
+
+	return req, nil
+}
+
+func decodeLatest(_ context.Context, r *http.Request) (interface{}, error) {
+	chanID, err := identifiers.Parse(bone.GetValue(r, "chanID"))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorize(r, chanID); err != nil {
+		return nil, err
+	}
+
+	req := latestMessagesReq{
+		chanID: chanID,
+		query:  parseQuery(r),
+	}
+
+	return req, nil
+}
+
+func decodeTop(_ context.Context, r *http.Request) (interface{}, error) {
+	chanID, err := identifiers.Parse(bone.GetValue(r, "chanID"))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorize(r, chanID); err != nil {
+		return nil, err
+	}
+
+	n, err := getQuery(r, "n", defTop)
+	if err != nil {
+		return nil, err
+	}
+
+	from, err := getFloatQuery(r, "from")
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := getFloatQuery(r, "to")
+	if err != nil {
+		return nil, err
+	}
+
+	req := topPublishersReq{
+		chanID: chanID,
+		n:      n,
+		from:   from,
+		to:     to,
 	}
 
 	return req, nil
@@ -117,7 +179,7 @@ func encodeResponse(_ context.Context, w http.ResponseWriter, response interface
 func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 	switch err {
 	case nil:
-	case errInvalidRequest:
+	case errInvalidRequest, identifiers.ErrMalformedID:
 		w.WriteHeader(http.StatusBadRequest)
 	case errUnauthorizedAccess:
 		w.WriteHeader(http.StatusForbidden)
@@ -135,7 +197,7 @@ func authorize(r *http.Request, chanID string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 	defer cancel()
 
-	_, err := auth.CanAccess(ctx, &mainflux.AccessReq{Token: token, ChanID: chanID})
+	_, err := auth.CanAccess(ctx, &mainflux.AccessReq{Token: token, ChanID: chanID, Action: mainflux.ActionSubscribe})
 	if err != nil {
 		e, ok := status.FromError(err)
 		if ok && e.Code() == codes.PermissionDenied {
@@ -147,6 +209,17 @@ func authorize(r *http.Request, chanID string) error {
 	return nil
 }
 
+func parseQuery(r *http.Request) map[string]string {
+	query := map[string]string{}
+	for _, name := range queryFields {
+		if value := bone.GetQuery(r, name); len(value) == 1 {
+			query[name] = value[0]
+		}
+	}
+
+	return query
+}
+
 func getQuery(req *http.Request, name string, fallback uint64) (uint64, error) {
 	vals := bone.GetQuery(req, name)
 	if len(vals) == 0 {
@@ -164,3 +237,21 @@ func getQuery(req *http.Request, name string, fallback uint64) (uint64, error) {
 
 	return uint64(val), nil
 }
+
+func getFloatQuery(req *http.Request, name string) (float64, error) {
+	vals := bone.GetQuery(req, name)
+	if len(vals) == 0 {
+		return 0, nil
+	}
+
+	if len(vals) > 1 {
+		return 0, errInvalidRequest
+	}
+
+	val, err := strconv.ParseFloat(vals[0], 64)
+	if err != nil {
+		return 0, errInvalidRequest
+	}
+
+	return val, nil
+}
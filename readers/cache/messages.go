@@ -0,0 +1,121 @@
+//
+// Copyright (c) 2018
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package cache
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/readers"
+)
+
+var _ readers.MessageRepository = (*messageCache)(nil)
+
+type entry struct {
+	page    readers.MessagesPage
+	expires time.Time
+}
+
+type messageCache struct {
+	mu      sync.Mutex
+	repo    readers.MessageRepository
+	ttl     time.Duration
+	size    int
+	entries map[string]entry
+	counter metrics.Counter
+}
+
+// New returns message repository that caches results of the wrapped
+// repository. Results are kept for the provided TTL and at most size results
+// are kept at once. Cache hits and misses are tracked by the provided counter.
+func New(repo readers.MessageRepository, ttl time.Duration, size int, counter metrics.Counter) readers.MessageRepository {
+	return &messageCache{
+		repo:    repo,
+		ttl:     ttl,
+		size:    size,
+		entries: make(map[string]entry),
+		counter: counter,
+	}
+}
+
+func (mc *messageCache) ReadAll(chanID string, offset, limit uint64, query map[string]string) (readers.MessagesPage, error) {
+	now := time.Now()
+	k := key(chanID, offset, limit, query, now.Truncate(mc.ttl))
+
+	mc.mu.Lock()
+	e, ok := mc.entries[k]
+	mc.mu.Unlock()
+
+	if ok && now.Before(e.expires) {
+		mc.counter.With("result", "hit").Add(1)
+		return e.page, nil
+	}
+	mc.counter.With("result", "miss").Add(1)
+
+	page, err := mc.repo.ReadAll(chanID, offset, limit, query)
+	if err != nil {
+		return page, err
+	}
+
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	if len(mc.entries) >= mc.size {
+		mc.evict(now)
+	}
+	mc.entries[k] = entry{
+		page:    page,
+		expires: now.Add(mc.ttl),
+	}
+
+	return page, nil
+}
+
+// evict removes expired entries. If none of the entries expired, an
+// arbitrary one is removed to make room for the new entry.
+func (mc *messageCache) evict(now time.Time) {
+	for k, e := range mc.entries {
+		if !now.Before(e.expires) {
+			delete(mc.entries, k)
+		}
+	}
+
+	for k := range mc.entries {
+		if len(mc.entries) < mc.size {
+			return
+		}
+		delete(mc.entries, k)
+	}
+}
+
+// key identifies the query by the channel, page, query parameters and the
+// range bucket the query time belongs to.
+func key(chanID string, offset, limit uint64, query map[string]string, bucket time.Time) string {
+	params := []string{}
+	for name, value := range query {
+		params = append(params, fmt.Sprintf("%s=%s", name, value))
+	}
+	sort.Strings(params)
+
+	return fmt.Sprintf("%s:%d:%d:%d:%s", chanID, offset, limit, bucket.Unix(), strings.Join(params, "&"))
+}
+
+// ReadLatest isn't cached since the latest messages are expected to change
+// between the consecutive reads.
+func (mc *messageCache) ReadLatest(chanID string, query map[string]string) ([]mainflux.Message, error) {
+	return mc.repo.ReadLatest(chanID, query)
+}
+
+func (mc *messageCache) ReadTopPublishers(chanID string, n uint64, from, to float64) ([]readers.PublisherCount, error) {
+	return mc.repo.ReadTopPublishers(chanID, n, from, to)
+}
@@ -0,0 +1,114 @@
+//
+// Copyright (c) 2018
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package cache_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/readers"
+	"github.com/mainflux/mainflux/readers/cache"
+	"github.com/mainflux/mainflux/readers/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	chanID = "1"
+	size   = 2
+)
+
+var _ metrics.Counter = (*counter)(nil)
+
+type counter struct{}
+
+func (c counter) With(...string) metrics.Counter { return c }
+
+func (c counter) Add(float64) {}
+
+type countingRepository struct {
+	repo  readers.MessageRepository
+	calls int
+}
+
+func (cr *countingRepository) ReadAll(chanID string, offset, limit uint64, query map[string]string) (readers.MessagesPage, error) {
+	cr.calls++
+	return cr.repo.ReadAll(chanID, offset, limit, query)
+}
+
+func (cr *countingRepository) ReadLatest(chanID string, query map[string]string) ([]mainflux.Message, error) {
+	cr.calls++
+	return cr.repo.ReadLatest(chanID, query)
+}
+
+func (cr *countingRepository) ReadTopPublishers(chanID string, n uint64, from, to float64) ([]readers.PublisherCount, error) {
+	cr.calls++
+	return cr.repo.ReadTopPublishers(chanID, n, from, to)
+}
+
+func TestReadAll(t *testing.T) {
+	messages := map[string][]mainflux.Message{
+		chanID: {{Channel: chanID, Publisher: "1", Protocol: "mqtt"}},
+	}
+	repo := &countingRepository{repo: mocks.NewMessageRepository(messages)}
+	mc := cache.New(repo, time.Hour, size, counter{})
+
+	cases := []struct {
+		desc  string
+		query map[string]string
+		calls int
+	}{
+		{
+			desc:  "read messages for the first time",
+			query: map[string]string{"publisher": "1"},
+			calls: 1,
+		},
+		{
+			desc:  "read cached messages",
+			query: map[string]string{"publisher": "1"},
+			calls: 1,
+		},
+		{
+			desc:  "read messages with different query",
+			query: map[string]string{"publisher": "2"},
+			calls: 2,
+		},
+		{
+			desc:  "read messages with third query exceeding cache size",
+			query: map[string]string{"protocol": "mqtt"},
+			calls: 3,
+		},
+	}
+
+	for _, tc := range cases {
+		page, err := mc.ReadAll(chanID, 0, 10, tc.query)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		assert.Equal(t, 1, len(page.Messages), fmt.Sprintf("%s: expected 1 message got %d", tc.desc, len(page.Messages)))
+		assert.Equal(t, tc.calls, repo.calls, fmt.Sprintf("%s: expected %d repository calls got %d", tc.desc, tc.calls, repo.calls))
+	}
+}
+
+func TestReadAllExpired(t *testing.T) {
+	messages := map[string][]mainflux.Message{
+		chanID: {{Channel: chanID, Publisher: "1", Protocol: "mqtt"}},
+	}
+	repo := &countingRepository{repo: mocks.NewMessageRepository(messages)}
+	mc := cache.New(repo, 10*time.Millisecond, size, counter{})
+
+	_, err := mc.ReadAll(chanID, 0, 10, nil)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	time.Sleep(20 * time.Millisecond)
+
+	_, err = mc.ReadAll(chanID, 0, 10, nil)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, 2, repo.calls, fmt.Sprintf("expected 2 repository calls got %d", repo.calls))
+}
@@ -51,39 +51,17 @@ func (cr cassandraRepository) ReadAll(chanID string, offset, limit uint64, query
 		}
 	}
 
-	var floatVal, valueSum *float64
-	var strVal, dataVal *string
-	var boolVal *bool
-
 	page := readers.MessagesPage{
 		Offset:   offset,
 		Limit:    limit,
 		Messages: []mainflux.Message{},
 	}
 	for scanner.Next() {
-		var msg mainflux.Message
-		err := scanner.Scan(&msg.Channel, &msg.Subtopic, &msg.Publisher, &msg.Protocol,
-			&msg.Name, &msg.Unit, &floatVal, &strVal, &boolVal,
-			&dataVal, &valueSum, &msg.Time, &msg.UpdateTime, &msg.Link)
+		msg, err := scanMessage(scanner)
 		if err != nil {
 			return readers.MessagesPage{}, err
 		}
 
-		switch {
-		case floatVal != nil:
-			msg.Value = &mainflux.Message_FloatValue{FloatValue: *floatVal}
-		case strVal != nil:
-			msg.Value = &mainflux.Message_StringValue{StringValue: *strVal}
-		case boolVal != nil:
-			msg.Value = &mainflux.Message_BoolValue{BoolValue: *boolVal}
-		case dataVal != nil:
-			msg.Value = &mainflux.Message_DataValue{DataValue: *dataVal}
-		}
-
-		if valueSum != nil {
-			msg.ValueSum = &mainflux.SumValue{Value: *valueSum}
-		}
-
 		page.Messages = append(page.Messages, msg)
 	}
 
@@ -94,11 +72,105 @@ func (cr cassandraRepository) ReadAll(chanID string, offset, limit uint64, query
 	return page, nil
 }
 
+// ReadLatest scans the channel partition in its clustering order, i.e. from
+// the latest message, and keeps the first message of every publisher, since
+// Cassandra can't group rows by a column that is not part of the primary key.
+func (cr cassandraRepository) ReadLatest(chanID string, query map[string]string) ([]mainflux.Message, error) {
+	names := []string{}
+	vals := []interface{}{chanID}
+	for name, val := range query {
+		if !filterable(name) {
+			continue
+		}
+		names = append(names, name)
+		vals = append(vals, val)
+	}
+
+	cql := buildLatestQuery(names)
+
+	iter := cr.session.Query(cql, vals...).Iter()
+	defer iter.Close()
+	scanner := iter.Scanner()
+
+	seen := map[string]bool{}
+	msgs := []mainflux.Message{}
+	for scanner.Next() {
+		msg, err := scanMessage(scanner)
+		if err != nil {
+			return nil, err
+		}
+
+		if seen[msg.Publisher] {
+			continue
+		}
+		seen[msg.Publisher] = true
+		msgs = append(msgs, msg)
+	}
+
+	return msgs, nil
+}
+
+// ReadTopPublishers counts the messages of the time range of the channel
+// partition. Time is the clustering column, so the range is read without
+// filtering.
+func (cr cassandraRepository) ReadTopPublishers(chanID string, n uint64, from, to float64) ([]readers.PublisherCount, error) {
+	cql := `SELECT publisher FROM messages WHERE channel = ? AND time >= ?`
+	vals := []interface{}{chanID, from}
+	if to > 0 {
+		cql = fmt.Sprintf(`%s AND time <= ?`, cql)
+		vals = append(vals, to)
+	}
+
+	iter := cr.session.Query(cql, vals...).Iter()
+	counts := map[string]uint64{}
+	var pub string
+	for iter.Scan(&pub) {
+		counts[pub]++
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+
+	return readers.TopPublishers(counts, n), nil
+}
+
+func scanMessage(scanner gocql.Scanner) (mainflux.Message, error) {
+	var floatVal, valueSum *float64
+	var strVal, dataVal *string
+	var boolVal *bool
+
+	var msg mainflux.Message
+	err := scanner.Scan(&msg.Channel, &msg.Subtopic, &msg.Publisher, &msg.Protocol,
+		&msg.Name, &msg.Unit, &floatVal, &strVal, &boolVal,
+		&dataVal, &valueSum, &msg.Time, &msg.UpdateTime, &msg.Link,
+		&msg.RemoteAddr, &msg.Received, &msg.Seq)
+	if err != nil {
+		return mainflux.Message{}, err
+	}
+
+	switch {
+	case floatVal != nil:
+		msg.Value = &mainflux.Message_FloatValue{FloatValue: *floatVal}
+	case strVal != nil:
+		msg.Value = &mainflux.Message_StringValue{StringValue: *strVal}
+	case boolVal != nil:
+		msg.Value = &mainflux.Message_BoolValue{BoolValue: *boolVal}
+	case dataVal != nil:
+		msg.Value = &mainflux.Message_DataValue{DataValue: *dataVal}
+	}
+
+	if valueSum != nil {
+		msg.ValueSum = &mainflux.SumValue{Value: *valueSum}
+	}
+
+	return msg, nil
+}
+
 func buildSelectQuery(chanID string, offset, limit uint64, names []string) string {
 	var condCQL string
 	cql := `SELECT channel, subtopic, publisher, protocol, name, unit,
 	        value, string_value, bool_value, data_value, value_sum, time,
-			update_time, link FROM messages WHERE channel = ? %s LIMIT ?
+			update_time, link, remote_addr, received, seq FROM messages WHERE channel = ? %s LIMIT ?
 			ALLOW FILTERING`
 
 	for _, name := range names {
@@ -134,3 +206,31 @@ func buildCountQuery(chanID string, names []string) string {
 
 	return fmt.Sprintf(cql, condCQL)
 }
+
+func buildLatestQuery(names []string) string {
+	var condCQL string
+	cql := `SELECT channel, subtopic, publisher, protocol, name, unit,
+	        value, string_value, bool_value, data_value, value_sum, time,
+			update_time, link, remote_addr, received, seq FROM messages WHERE channel = ? %s
+			ALLOW FILTERING`
+
+	for _, name := range names {
+		condCQL = fmt.Sprintf(`%s AND %s = ?`, condCQL, name)
+	}
+
+	return fmt.Sprintf(cql, condCQL)
+}
+
+func filterable(name string) bool {
+	switch name {
+	case
+		"channel",
+		"subtopic",
+		"publisher",
+		"name",
+		"protocol":
+		return true
+	}
+
+	return false
+}
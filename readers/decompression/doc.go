@@ -0,0 +1,10 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// Package decompression contains message repository decorator that
+// decompresses the payloads the writers stored compressed.
+package decompression
@@ -0,0 +1,71 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package decompression
+
+import (
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/compression"
+	"github.com/mainflux/mainflux/readers"
+)
+
+var _ readers.MessageRepository = (*decompressingRepository)(nil)
+
+type decompressingRepository struct {
+	repo readers.MessageRepository
+}
+
+// New returns message repository that decompresses the string and data
+// values of the messages read from the wrapped repository. Values that
+// aren't compressed, or can't be decompressed, are returned as stored.
+func New(repo readers.MessageRepository) readers.MessageRepository {
+	return &decompressingRepository{repo: repo}
+}
+
+func (dr *decompressingRepository) ReadAll(chanID string, offset, limit uint64, query map[string]string) (readers.MessagesPage, error) {
+	page, err := dr.repo.ReadAll(chanID, offset, limit, query)
+	if err != nil {
+		return page, err
+	}
+
+	page.Messages = decompress(page.Messages)
+	return page, nil
+}
+
+func (dr *decompressingRepository) ReadLatest(chanID string, query map[string]string) ([]mainflux.Message, error) {
+	msgs, err := dr.repo.ReadLatest(chanID, query)
+	if err != nil {
+		return msgs, err
+	}
+
+	return decompress(msgs), nil
+}
+
+func (dr *decompressingRepository) ReadTopPublishers(chanID string, n uint64, from, to float64) ([]readers.PublisherCount, error) {
+	return dr.repo.ReadTopPublishers(chanID, n, from, to)
+}
+
+// decompress returns the copy of the messages holding the decompressed
+// values, leaving the messages of the wrapped repository intact.
+func decompress(msgs []mainflux.Message) []mainflux.Message {
+	ret := make([]mainflux.Message, len(msgs))
+	copy(ret, msgs)
+	for i, msg := range ret {
+		switch v := msg.Value.(type) {
+		case *mainflux.Message_StringValue:
+			if val, err := compression.Decompress(v.StringValue); err == nil {
+				ret[i].Value = &mainflux.Message_StringValue{StringValue: val}
+			}
+		case *mainflux.Message_DataValue:
+			if val, err := compression.Decompress(v.DataValue); err == nil {
+				ret[i].Value = &mainflux.Message_DataValue{DataValue: val}
+			}
+		}
+	}
+
+	return ret
+}
@@ -0,0 +1,49 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package decompression_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/compression"
+	"github.com/mainflux/mainflux/readers/decompression"
+	"github.com/mainflux/mainflux/readers/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const chanID = "1"
+
+func TestReadAll(t *testing.T) {
+	payload := strings.Repeat(`{"temperature":21.5,"humidity":40}`, 20)
+	compressed, err := compression.Compress(compression.Snappy, payload)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	messages := map[string][]mainflux.Message{
+		chanID: {
+			{Channel: chanID, Value: &mainflux.Message_StringValue{StringValue: compressed}},
+			{Channel: chanID, Value: &mainflux.Message_DataValue{DataValue: compressed}},
+			{Channel: chanID, Value: &mainflux.Message_StringValue{StringValue: "plain"}},
+			{Channel: chanID, Value: &mainflux.Message_FloatValue{FloatValue: 5}},
+		},
+	}
+	repo := decompression.New(mocks.NewMessageRepository(messages))
+
+	page, err := repo.ReadAll(chanID, 0, 10, nil)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	require.Len(t, page.Messages, 4, fmt.Sprintf("expected 4 messages got %d", len(page.Messages)))
+
+	assert.Equal(t, payload, page.Messages[0].GetStringValue(), "expected string value to be decompressed")
+	assert.Equal(t, payload, page.Messages[1].GetDataValue(), "expected data value to be decompressed")
+	assert.Equal(t, "plain", page.Messages[2].GetStringValue(), "expected uncompressed value to be kept")
+	assert.Equal(t, float64(5), page.Messages[3].GetFloatValue(), "expected float value to be kept")
+	assert.Equal(t, compressed, messages[chanID][0].GetStringValue(), "expected stored message to be kept intact")
+}
@@ -78,6 +78,86 @@ func (repo *influxRepository) ReadAll(chanID string, offset, limit uint64, query
 	}, nil
 }
 
+// ReadLatest relies on publisher being a tag, so that the messages are
+// grouped into a series per publisher and only the latest point of every
+// series is returned.
+func (repo *influxRepository) ReadLatest(chanID string, query map[string]string) ([]mainflux.Message, error) {
+	condition := fmtCondition(chanID, query)
+	cmd := fmt.Sprintf(`SELECT * FROM messages WHERE %s GROUP BY publisher ORDER BY time DESC LIMIT 1`, condition)
+
+	resp, err := repo.query(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := []mainflux.Message{}
+	if len(resp.Results) < 1 {
+		return ret, nil
+	}
+
+	for _, series := range resp.Results[0].Series {
+		for _, v := range series.Values {
+			msg := parseMessage(series.Columns, v)
+			msg.Publisher = series.Tags["publisher"]
+			ret = append(ret, msg)
+		}
+	}
+
+	return ret, nil
+}
+
+// ReadTopPublishers counts the messages per publisher on the database side.
+// Since InfluxDB can't order the series by the aggregated value, the counts
+// are ordered by the repository.
+func (repo *influxRepository) ReadTopPublishers(chanID string, n uint64, from, to float64) ([]readers.PublisherCount, error) {
+	condition := fmt.Sprintf(`%s AND time >= %d`, fmtCondition(chanID, nil), int64(from*1e9))
+	if to > 0 {
+		condition = fmt.Sprintf(`%s AND time <= %d`, condition, int64(to*1e9))
+	}
+	cmd := fmt.Sprintf(`SELECT COUNT(protocol) FROM messages WHERE %s GROUP BY publisher`, condition)
+
+	resp, err := repo.query(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[string]uint64{}
+	if len(resp.Results) < 1 {
+		return readers.TopPublishers(counts, n), nil
+	}
+
+	for _, series := range resp.Results[0].Series {
+		if len(series.Values) < 1 {
+			continue
+		}
+
+		count, err := parseCount(series.Columns, series.Values[0])
+		if err != nil {
+			return nil, err
+		}
+		counts[series.Tags["publisher"]] = count
+	}
+
+	return readers.TopPublishers(counts, n), nil
+}
+
+func (repo *influxRepository) query(cmd string) (*influxdata.Response, error) {
+	q := influxdata.Query{
+		Command:  cmd,
+		Database: repo.database,
+	}
+
+	resp, err := repo.client.Query(q)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Error() != nil {
+		return nil, resp.Error()
+	}
+
+	return resp, nil
+}
+
 func (repo *influxRepository) count(condition string) (uint64, error) {
 	cmd := fmt.Sprintf(`SELECT COUNT(protocol) FROM messages WHERE %s`, condition)
 	q := influxdata.Query{
@@ -99,15 +179,18 @@ func (repo *influxRepository) count(condition string) (uint64, error) {
 		return 0, nil
 	}
 
+	return parseCount(resp.Results[0].Series[0].Columns, resp.Results[0].Series[0].Values[0])
+}
+
+func parseCount(columns []string, result []interface{}) (uint64, error) {
 	countIndex := 0
-	for i, col := range resp.Results[0].Series[0].Columns {
+	for i, col := range columns {
 		if col == countCol {
 			countIndex = i
 			break
 		}
 	}
 
-	result := resp.Results[0].Series[0].Values[0]
 	if len(result) < countIndex+1 {
 		return 0, nil
 	}
@@ -210,6 +293,11 @@ func parseMessage(names []string, fields []interface{}) mainflux.Message {
 
 			val, _ := strconv.ParseFloat(fields[i].(string), 64)
 			msgField.SetFloat(val)
+		case uint64:
+			if s, ok := fields[i].(string); ok {
+				val, _ := strconv.ParseUint(s, 10, 64)
+				msgField.SetUint(val)
+			}
 		}
 	}
 
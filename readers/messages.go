@@ -9,6 +9,7 @@ package readers
 
 import (
 	"errors"
+	"sort"
 
 	"github.com/mainflux/mainflux"
 )
@@ -21,6 +22,15 @@ type MessageRepository interface {
 	// ReadAll skips given number of messages for given channel and returns next
 	// limited number of messages.
 	ReadAll(string, uint64, uint64, map[string]string) (MessagesPage, error)
+
+	// ReadLatest returns the latest message of every publisher of the given
+	// channel that matches the given query.
+	ReadLatest(string, map[string]string) ([]mainflux.Message, error)
+
+	// ReadTopPublishers returns at most given number of publishers of the
+	// given channel that sent the most messages within the given time range,
+	// ordered by the message count. Zero upper bound leaves range unbounded.
+	ReadTopPublishers(string, uint64, float64, float64) ([]PublisherCount, error)
 }
 
 // MessagesPage contains page related metadata as well as list of messages that
@@ -31,3 +41,32 @@ type MessagesPage struct {
 	Limit    uint64
 	Messages []mainflux.Message
 }
+
+// PublisherCount represents the number of messages sent by the publisher.
+type PublisherCount struct {
+	Publisher string
+	Count     uint64
+}
+
+// TopPublishers returns at most n publishers with the highest message counts,
+// ordered by the count and then by the publisher. It's used by the
+// repositories whose underlying database can't sort aggregated results.
+func TopPublishers(counts map[string]uint64, n uint64) []PublisherCount {
+	ret := []PublisherCount{}
+	for pub, count := range counts {
+		ret = append(ret, PublisherCount{Publisher: pub, Count: count})
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Count != ret[j].Count {
+			return ret[i].Count > ret[j].Count
+		}
+		return ret[i].Publisher < ret[j].Publisher
+	})
+
+	if uint64(len(ret)) > n {
+		ret = ret[:n]
+	}
+
+	return ret
+}
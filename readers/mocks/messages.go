@@ -55,3 +55,72 @@ func (repo *messageRepositoryMock) ReadAll(chanID string, offset, limit uint64,
 		Messages: repo.messages[chanID][offset:end],
 	}, nil
 }
+
+func (repo *messageRepositoryMock) ReadLatest(chanID string, query map[string]string) ([]mainflux.Message, error) {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+
+	latest := map[string]int{}
+	order := []string{}
+	msgs := repo.messages[chanID]
+	for i, msg := range msgs {
+		if !matches(msg, query) {
+			continue
+		}
+
+		j, ok := latest[msg.Publisher]
+		if !ok {
+			order = append(order, msg.Publisher)
+		}
+		if !ok || msg.Time >= msgs[j].Time {
+			latest[msg.Publisher] = i
+		}
+	}
+
+	ret := []mainflux.Message{}
+	for _, pub := range order {
+		ret = append(ret, msgs[latest[pub]])
+	}
+
+	return ret, nil
+}
+
+func (repo *messageRepositoryMock) ReadTopPublishers(chanID string, n uint64, from, to float64) ([]readers.PublisherCount, error) {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+
+	counts := map[string]uint64{}
+	for _, msg := range repo.messages[chanID] {
+		if msg.Time < from || (to > 0 && msg.Time > to) {
+			continue
+		}
+		counts[msg.Publisher]++
+	}
+
+	return readers.TopPublishers(counts, n), nil
+}
+
+func matches(msg mainflux.Message, query map[string]string) bool {
+	for name, value := range query {
+		switch name {
+		case "subtopic":
+			if msg.Subtopic != value {
+				return false
+			}
+		case "publisher":
+			if msg.Publisher != value {
+				return false
+			}
+		case "name":
+			if msg.Name != value {
+				return false
+			}
+		case "protocol":
+			if msg.Protocol != value {
+				return false
+			}
+		}
+	}
+
+	return true
+}
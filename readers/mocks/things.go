@@ -43,3 +43,15 @@ func (svc thingsServiceMock) CanAccess(ctx context.Context, in *mainflux.AccessR
 func (svc thingsServiceMock) Identify(_ context.Context, _ *mainflux.Token, _ ...grpc.CallOption) (*mainflux.ThingID, error) {
 	return nil, nil
 }
+
+func (svc thingsServiceMock) IdentifyBatch(_ context.Context, _ *mainflux.Tokens, _ ...grpc.CallOption) (*mainflux.ThingIDs, error) {
+	return nil, nil
+}
+
+func (svc thingsServiceMock) CanRead(_ context.Context, _ *mainflux.AccessReq, _ ...grpc.CallOption) (*mainflux.UserID, error) {
+	return nil, errUnauthorized
+}
+
+func (svc thingsServiceMock) ListChannelsByThing(_ context.Context, _ *mainflux.ThingID, _ ...grpc.CallOption) (*mainflux.ChannelIDs, error) {
+	return &mainflux.ChannelIDs{}, nil
+}
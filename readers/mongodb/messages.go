@@ -41,6 +41,9 @@ type message struct {
 	Time        float64  `bson:"time,omitempty"`
 	UpdateTime  float64  `bson:"updateTime,omitempty"`
 	Link        string   `bson:"link,omitempty"`
+	RemoteAddr  string   `bson:"remoteAddr,omitempty"`
+	Received    float64  `bson:"received,omitempty"`
+	Seq         uint64   `bson:"seq,omitempty"`
 }
 
 // New returns new MongoDB reader.
@@ -70,34 +73,7 @@ func (repo mongoRepository) ReadAll(chanID string, offset, limit uint64, query m
 			return readers.MessagesPage{}, err
 		}
 
-		msg := mainflux.Message{
-			Channel:    m.Channel,
-			Subtopic:   m.Subtopic,
-			Publisher:  m.Publisher,
-			Protocol:   m.Protocol,
-			Name:       m.Name,
-			Unit:       m.Unit,
-			Time:       m.Time,
-			UpdateTime: m.UpdateTime,
-			Link:       m.Link,
-		}
-
-		switch {
-		case m.FloatValue != nil:
-			msg.Value = &mainflux.Message_FloatValue{FloatValue: *m.FloatValue}
-		case m.StringValue != nil:
-			msg.Value = &mainflux.Message_StringValue{StringValue: *m.StringValue}
-		case m.DataValue != nil:
-			msg.Value = &mainflux.Message_DataValue{DataValue: *m.DataValue}
-		case m.BoolValue != nil:
-			msg.Value = &mainflux.Message_BoolValue{BoolValue: *m.BoolValue}
-		}
-
-		if m.ValueSum != nil {
-			msg.ValueSum = &mainflux.SumValue{Value: *m.ValueSum}
-		}
-
-		messages = append(messages, msg)
+		messages = append(messages, toMessage(m))
 	}
 
 	total, err := col.CountDocuments(context.Background(), filter)
@@ -116,6 +92,110 @@ func (repo mongoRepository) ReadAll(chanID string, offset, limit uint64, query m
 	}, nil
 }
 
+// ReadLatest groups the channel messages by the publisher on the database
+// side, keeping only the first message of every group in descending time
+// order.
+func (repo mongoRepository) ReadLatest(chanID string, query map[string]string) ([]mainflux.Message, error) {
+	col := repo.db.Collection(collection)
+	pipeline := []bson.M{
+		{"$match": fmtCondition(chanID, query)},
+		{"$sort": bson.M{"time": -1}},
+		{"$group": bson.M{"_id": "$publisher", "msg": bson.M{"$first": "$$ROOT"}}},
+		{"$replaceRoot": bson.M{"newRoot": "$msg"}},
+	}
+
+	cursor, err := col.Aggregate(context.Background(), pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	messages := []mainflux.Message{}
+	for cursor.Next(context.Background()) {
+		var m message
+		if err := cursor.Decode(&m); err != nil {
+			return nil, err
+		}
+
+		messages = append(messages, toMessage(m))
+	}
+
+	return messages, nil
+}
+
+func (repo mongoRepository) ReadTopPublishers(chanID string, n uint64, from, to float64) ([]readers.PublisherCount, error) {
+	col := repo.db.Collection(collection)
+	timeRange := bson.M{"$gte": from}
+	if to > 0 {
+		timeRange["$lte"] = to
+	}
+
+	pipeline := []bson.M{
+		{"$match": bson.M{"channel": chanID, "time": timeRange}},
+		{"$group": bson.M{"_id": "$publisher", "count": bson.M{"$sum": 1}}},
+		{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}},
+		{"$limit": int64(n)},
+	}
+
+	cursor, err := col.Aggregate(context.Background(), pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	counts := []readers.PublisherCount{}
+	for cursor.Next(context.Background()) {
+		var pc struct {
+			Publisher string `bson:"_id"`
+			Count     int64  `bson:"count"`
+		}
+		if err := cursor.Decode(&pc); err != nil {
+			return nil, err
+		}
+
+		counts = append(counts, readers.PublisherCount{
+			Publisher: pc.Publisher,
+			Count:     uint64(pc.Count),
+		})
+	}
+
+	return counts, nil
+}
+
+func toMessage(m message) mainflux.Message {
+	msg := mainflux.Message{
+		Channel:    m.Channel,
+		Subtopic:   m.Subtopic,
+		Publisher:  m.Publisher,
+		Protocol:   m.Protocol,
+		Name:       m.Name,
+		Unit:       m.Unit,
+		Time:       m.Time,
+		UpdateTime: m.UpdateTime,
+		Link:       m.Link,
+		RemoteAddr: m.RemoteAddr,
+		Received:   m.Received,
+		Seq:        m.Seq,
+	}
+
+	switch {
+	case m.FloatValue != nil:
+		msg.Value = &mainflux.Message_FloatValue{FloatValue: *m.FloatValue}
+	case m.StringValue != nil:
+		msg.Value = &mainflux.Message_StringValue{StringValue: *m.StringValue}
+	case m.DataValue != nil:
+		msg.Value = &mainflux.Message_DataValue{DataValue: *m.DataValue}
+	case m.BoolValue != nil:
+		msg.Value = &mainflux.Message_BoolValue{BoolValue: *m.BoolValue}
+	}
+
+	if m.ValueSum != nil {
+		msg.ValueSum = &mainflux.SumValue{Value: *m.ValueSum}
+	}
+
+	return msg
+}
+
 func fmtCondition(chanID string, query map[string]string) *bson.D {
 	filter := bson.D{
 		bson.E{
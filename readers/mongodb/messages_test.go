@@ -40,9 +40,12 @@ var (
 	port string
 	addr string
 	msg  = mainflux.Message{
-		Channel:   chanID,
-		Publisher: "1",
-		Protocol:  "mqtt",
+		Channel:    chanID,
+		Publisher:  "1",
+		Protocol:   "mqtt",
+		RemoteAddr: "192.168.1.0",
+		Received:   1234,
+		Seq:        1,
 	}
 	testLog, _ = log.New(os.Stdout, log.Info.String())
 )
@@ -161,3 +164,81 @@ func TestReadAll(t *testing.T) {
 		assert.Equal(t, tc.page.Total, result.Total, fmt.Sprintf("%s: expected %v got %v", desc, tc.page.Total, result.Total))
 	}
 }
+
+func TestReadLatest(t *testing.T) {
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI(addr))
+	require.Nil(t, err, fmt.Sprintf("Creating new MongoDB client expected to succeed: %s.\n", err))
+
+	db := client.Database(testDB)
+	writer := mwriters.New(db)
+
+	chID := "latest"
+	pubs := []string{"1", "2", "3"}
+	latest := []mainflux.Message{}
+	now := float64(time.Now().Unix())
+	for i, pub := range pubs {
+		// Publisher i sends len(pubs)-i messages, the last one being the latest.
+		for j := 0; j < len(pubs)-i; j++ {
+			m := mainflux.Message{
+				Channel:   chID,
+				Publisher: pub,
+				Protocol:  "mqtt",
+				Value:     &mainflux.Message_FloatValue{FloatValue: float64(j)},
+				Time:      now - float64(10*i) + float64(j),
+			}
+			err := writer.Save(m)
+			require.Nil(t, err, fmt.Sprintf("failed to store message: %s", err))
+			if j == len(pubs)-i-1 {
+				latest = append(latest, m)
+			}
+		}
+	}
+
+	reader := mreaders.New(db)
+
+	msgs, err := reader.ReadLatest(chID, nil)
+	assert.Nil(t, err, fmt.Sprintf("expected no error got %s", err))
+	assert.ElementsMatch(t, latest, msgs, fmt.Sprintf("expected %v got %v", latest, msgs))
+
+	msgs, err = reader.ReadLatest(chID, map[string]string{"subtopic": "not-present"})
+	assert.Nil(t, err, fmt.Sprintf("expected no error got %s", err))
+	assert.Len(t, msgs, 0, fmt.Sprintf("expected no messages got %d", len(msgs)))
+
+	cases := map[string]struct {
+		n      uint64
+		from   float64
+		to     float64
+		counts []readers.PublisherCount
+	}{
+		"read top publishers": {
+			n:    2,
+			from: 0,
+			to:   0,
+			counts: []readers.PublisherCount{
+				{Publisher: pubs[0], Count: 3},
+				{Publisher: pubs[1], Count: 2},
+			},
+		},
+		"read top publishers within time range": {
+			n:    10,
+			from: now - 9.5,
+			to:   now + 10,
+			counts: []readers.PublisherCount{
+				{Publisher: pubs[0], Count: 3},
+				{Publisher: pubs[1], Count: 1},
+			},
+		},
+		"read top publishers outside of time range": {
+			n:      10,
+			from:   now + 100,
+			to:     0,
+			counts: []readers.PublisherCount{},
+		},
+	}
+
+	for desc, tc := range cases {
+		counts, err := reader.ReadTopPublishers(chID, tc.n, tc.from, tc.to)
+		assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %s", desc, err))
+		assert.Equal(t, tc.counts, counts, fmt.Sprintf("%s: expected %v got %v", desc, tc.counts, counts))
+	}
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq" // required for SQL access
+	mfpostgres "github.com/mainflux/mainflux/postgres"
 	migrate "github.com/rubenv/sql-migrate"
 )
 
@@ -26,19 +27,32 @@ type Config struct {
 	SSLCert     string
 	SSLKey      string
 	SSLRootCert string
+	Target      string
+	SyncCommit  string
 }
 
 // Connect creates a connection to the PostgreSQL instance and applies any
-// unapplied database migrations. A non-nil error is returned to indicate
-// failure.
+// unapplied database migrations. The migrations are skipped when connecting
+// to standbys, which get the schema from the primary. A non-nil error is
+// returned to indicate failure.
 func Connect(cfg Config) (*sqlx.DB, error) {
-	url := fmt.Sprintf("host=%s port=%s user=%s dbname=%s password=%s sslmode=%s sslcert=%s sslkey=%s sslrootcert=%s", cfg.Host, cfg.Port, cfg.User, cfg.Name, cfg.Pass, cfg.SSLMode, cfg.SSLCert, cfg.SSLKey, cfg.SSLRootCert)
+	url := fmt.Sprintf("user=%s dbname=%s password=%s sslmode=%s sslcert=%s sslkey=%s sslrootcert=%s", cfg.User, cfg.Name, cfg.Pass, cfg.SSLMode, cfg.SSLCert, cfg.SSLKey, cfg.SSLRootCert)
 
-	db, err := sqlx.Open("postgres", url)
+	db, err := mfpostgres.Open(mfpostgres.Config{
+		Hosts:      cfg.Host,
+		Port:       cfg.Port,
+		DSN:        url,
+		Target:     cfg.Target,
+		SyncCommit: cfg.SyncCommit,
+	})
 	if err != nil {
 		return nil, err
 	}
 
+	if cfg.Target != "" && cfg.Target != mfpostgres.TargetReadWrite {
+		return db, nil
+	}
+
 	if err := migrateDB(db); err != nil {
 		return nil, err
 	}
@@ -75,6 +89,26 @@ func migrateDB(db *sqlx.DB) error {
 					"DROP TABLE messages",
 				},
 			},
+			{
+				Id: "messages_2",
+				Up: []string{
+					`ALTER TABLE messages ADD COLUMN remote_addr TEXT NOT NULL DEFAULT ''`,
+					`ALTER TABLE messages ADD COLUMN received FLOAT NOT NULL DEFAULT 0`,
+				},
+				Down: []string{
+					"ALTER TABLE messages DROP COLUMN remote_addr",
+					"ALTER TABLE messages DROP COLUMN received",
+				},
+			},
+			{
+				Id: "messages_3",
+				Up: []string{
+					`ALTER TABLE messages ADD COLUMN seq BIGINT NOT NULL DEFAULT 0`,
+				},
+				Down: []string{
+					"ALTER TABLE messages DROP COLUMN seq",
+				},
+			},
 		},
 	}
 
@@ -89,6 +89,79 @@ func (tr postgresRepository) ReadAll(chanID string, offset, limit uint64, query
 	return page, nil
 }
 
+// ReadLatest relies on DISTINCT ON to pick the latest message of every
+// publisher in a single pass over the channel messages.
+func (tr postgresRepository) ReadLatest(chanID string, query map[string]string) ([]mainflux.Message, error) {
+	subtopicQuery := ""
+	if query["subtopic"] != "" {
+		subtopicQuery = `AND subtopic = :subtopic`
+	}
+	q := fmt.Sprintf(`SELECT DISTINCT ON (publisher) * FROM messages
+    WHERE channel = :channel %s ORDER BY publisher, time DESC;`, subtopicQuery)
+
+	params := map[string]interface{}{
+		"channel":  chanID,
+		"subtopic": query["subtopic"],
+	}
+
+	rows, err := tr.db.NamedQuery(q, params)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	msgs := []mainflux.Message{}
+	for rows.Next() {
+		dbm := dbMessage{Channel: chanID}
+		if err := rows.StructScan(&dbm); err != nil {
+			return nil, err
+		}
+
+		msg, err := toMessage(dbm)
+		if err != nil {
+			return nil, err
+		}
+
+		msgs = append(msgs, msg)
+	}
+
+	return msgs, nil
+}
+
+func (tr postgresRepository) ReadTopPublishers(chanID string, n uint64, from, to float64) ([]readers.PublisherCount, error) {
+	toQuery := ""
+	if to > 0 {
+		toQuery = `AND time <= :to`
+	}
+	q := fmt.Sprintf(`SELECT publisher, COUNT(*) AS count FROM messages
+    WHERE channel = :channel AND time >= :from %s
+    GROUP BY publisher ORDER BY count DESC, publisher LIMIT :limit;`, toQuery)
+
+	params := map[string]interface{}{
+		"channel": chanID,
+		"from":    from,
+		"to":      to,
+		"limit":   n,
+	}
+
+	rows, err := tr.db.NamedQuery(q, params)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := []readers.PublisherCount{}
+	for rows.Next() {
+		var pc readers.PublisherCount
+		if err := rows.Scan(&pc.Publisher, &pc.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, pc)
+	}
+
+	return counts, nil
+}
+
 type dbMessage struct {
 	ID          string   `db:"id"`
 	Channel     string   `db:"channel"`
@@ -105,6 +178,9 @@ type dbMessage struct {
 	Time        float64  `db:"time"`
 	UpdateTime  float64  `db:"update_time"`
 	Link        string   `db:"link"`
+	RemoteAddr  string   `db:"remote_addr"`
+	Received    float64  `db:"received"`
+	Seq         uint64   `db:"seq"`
 }
 
 func toMessage(dbm dbMessage) (mainflux.Message, error) {
@@ -118,6 +194,9 @@ func toMessage(dbm dbMessage) (mainflux.Message, error) {
 		Time:       dbm.Time,
 		UpdateTime: dbm.UpdateTime,
 		Link:       dbm.Link,
+		RemoteAddr: dbm.RemoteAddr,
+		Received:   dbm.Received,
+		Seq:        dbm.Seq,
 	}
 
 	switch {
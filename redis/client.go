@@ -0,0 +1,87 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package redis
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	r "github.com/go-redis/redis"
+)
+
+const sentinelScheme = "sentinel://"
+
+var (
+	// ErrMalformedURL indicates that the Redis URL is malformed.
+	ErrMalformedURL = errors.New("malformed Redis URL")
+
+	// ErrClusterDB indicates that the database other than 0 is selected,
+	// which Redis Cluster doesn't support.
+	ErrClusterDB = errors.New("database selection is not supported by Redis Cluster")
+)
+
+// Options returns the client options for the deployment described by the
+// URL, which is one of:
+//
+//	host:port                              - single node
+//	host:port,host:port,...                - Redis Cluster seed nodes
+//	sentinel://master@host:port,host:port  - Sentinels monitoring the master
+//
+// The Sentinel clients follow the master failovers, while the Cluster
+// clients follow the slot migrations.
+func Options(url, pass, db string) (*r.UniversalOptions, error) {
+	index, err := strconv.Atoi(db)
+	if err != nil {
+		return nil, ErrMalformedURL
+	}
+
+	master := ""
+	if strings.HasPrefix(url, sentinelScheme) {
+		parts := strings.SplitN(strings.TrimPrefix(url, sentinelScheme), "@", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, ErrMalformedURL
+		}
+		master, url = parts[0], parts[1]
+	}
+
+	addrs := []string{}
+	for _, addr := range strings.Split(url, ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			addrs = append(addrs, addr)
+		}
+	}
+
+	if len(addrs) == 0 {
+		return nil, ErrMalformedURL
+	}
+
+	if master == "" && len(addrs) > 1 && index != 0 {
+		return nil, ErrClusterDB
+	}
+
+	opts := &r.UniversalOptions{
+		Addrs:      addrs,
+		MasterName: master,
+		Password:   pass,
+		DB:         index,
+	}
+
+	return opts, nil
+}
+
+// Connect returns the client connected to the deployment described by the
+// URL. See Options for the supported URL formats.
+func Connect(url, pass, db string) (r.UniversalClient, error) {
+	opts, err := Options(url, pass, db)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.NewUniversalClient(opts), nil
+}
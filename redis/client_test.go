@@ -0,0 +1,89 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package redis_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptions(t *testing.T) {
+	cases := []struct {
+		desc   string
+		url    string
+		db     string
+		addrs  []string
+		master string
+		err    error
+	}{
+		{
+			desc:  "single node",
+			url:   "localhost:6379",
+			db:    "1",
+			addrs: []string{"localhost:6379"},
+		},
+		{
+			desc:  "cluster seed nodes",
+			url:   "redis-1:6379, redis-2:6379,redis-3:6379",
+			db:    "0",
+			addrs: []string{"redis-1:6379", "redis-2:6379", "redis-3:6379"},
+		},
+		{
+			desc:   "sentinels",
+			url:    "sentinel://mymaster@sentinel-1:26379,sentinel-2:26379",
+			db:     "2",
+			addrs:  []string{"sentinel-1:26379", "sentinel-2:26379"},
+			master: "mymaster",
+		},
+		{
+			desc:   "single sentinel",
+			url:    "sentinel://mymaster@sentinel-1:26379",
+			db:     "0",
+			addrs:  []string{"sentinel-1:26379"},
+			master: "mymaster",
+		},
+		{
+			desc: "cluster with database other than 0",
+			url:  "redis-1:6379,redis-2:6379",
+			db:   "1",
+			err:  redis.ErrClusterDB,
+		},
+		{
+			desc: "sentinels without master name",
+			url:  "sentinel://sentinel-1:26379",
+			db:   "0",
+			err:  redis.ErrMalformedURL,
+		},
+		{
+			desc: "empty URL",
+			url:  "",
+			db:   "0",
+			err:  redis.ErrMalformedURL,
+		},
+		{
+			desc: "invalid database",
+			url:  "localhost:6379",
+			db:   "first",
+			err:  redis.ErrMalformedURL,
+		},
+	}
+
+	for _, tc := range cases {
+		opts, err := redis.Options(tc.url, "pass", tc.db)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+		if err != nil {
+			continue
+		}
+		assert.Equal(t, tc.addrs, opts.Addrs, fmt.Sprintf("%s: expected %v got %v", tc.desc, tc.addrs, opts.Addrs))
+		assert.Equal(t, tc.master, opts.MasterName, fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.master, opts.MasterName))
+		assert.Equal(t, "pass", opts.Password, fmt.Sprintf("%s: expected password to be set", tc.desc))
+	}
+}
@@ -0,0 +1,10 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// Package redis contains the Redis client setup shared by the services, that
+// supports single node, Sentinel and Cluster deployments.
+package redis
@@ -0,0 +1,138 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/mainflux/mainflux/reports"
+)
+
+func createEndpoint(svc reports.Service) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(createReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		interval, err := time.ParseDuration(req.Interval)
+		if err != nil {
+			return nil, reports.ErrMalformedEntity
+		}
+
+		r := reports.Report{
+			Name:      req.Name,
+			ChannelID: req.ChannelID,
+			ThingKey:  req.ThingKey,
+			Format:    req.Format,
+			Template:  req.Template,
+			Interval:  interval,
+			Delivery:  req.Delivery,
+			Target:    req.Target,
+		}
+
+		saved, err := svc.CreateReport(req.key, r)
+		if err != nil {
+			return nil, err
+		}
+
+		return createRes{id: saved.ID}, nil
+	}
+}
+
+func viewEndpoint(svc reports.Service) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(entityReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		r, err := svc.ViewReport(req.key, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		return toReportRes(r), nil
+	}
+}
+
+func listEndpoint(svc reports.Service) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(listReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		rs, err := svc.ListReports(req.key)
+		if err != nil {
+			return nil, err
+		}
+
+		res := listRes{
+			Reports: []reportRes{},
+		}
+		for _, r := range rs {
+			res.Reports = append(res.Reports, toReportRes(r))
+		}
+
+		return res, nil
+	}
+}
+
+func removeEndpoint(svc reports.Service) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(entityReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RemoveReport(req.key, req.id); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func generateEndpoint(svc reports.Service) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(entityReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		doc, err := svc.GenerateReport(req.key, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		res := documentRes{
+			name:        doc.Name,
+			contentType: doc.ContentType,
+			content:     doc.Content,
+		}
+
+		return res, nil
+	}
+}
+
+func toReportRes(r reports.Report) reportRes {
+	return reportRes{
+		ID:        r.ID,
+		Name:      r.Name,
+		ChannelID: r.ChannelID,
+		Format:    r.Format,
+		Template:  r.Template,
+		Interval:  r.Interval.String(),
+		Delivery:  r.Delivery,
+		Target:    r.Target,
+		NextRun:   r.NextRun,
+	}
+}
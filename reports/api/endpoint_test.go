@@ -0,0 +1,444 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package api_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/reports"
+	"github.com/mainflux/mainflux/reports/api"
+	"github.com/mainflux/mainflux/reports/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	validToken   = "validToken"
+	invalidToken = "invalidToken"
+	email        = "user@example.com"
+	contentType  = "application/json"
+	chanID       = "1"
+	thingKey     = "thingKey"
+	target       = "reports@example.com"
+	wrongID      = "123e4567-e89b-12d3-a456-999999999999"
+)
+
+var report = reports.Report{
+	Name:      "consumption",
+	ChannelID: chanID,
+	ThingKey:  thingKey,
+	Format:    reports.CSV,
+	Interval:  24 * time.Hour,
+	Delivery:  reports.Email,
+	Target:    target,
+}
+
+type testRequest struct {
+	client      *http.Client
+	method      string
+	url         string
+	contentType string
+	token       string
+	body        io.Reader
+}
+
+func (tr testRequest) make() (*http.Response, error) {
+	req, err := http.NewRequest(tr.method, tr.url, tr.body)
+	if err != nil {
+		return nil, err
+	}
+
+	if tr.token != "" {
+		req.Header.Set("Authorization", tr.token)
+	}
+
+	if tr.contentType != "" {
+		req.Header.Set("Content-Type", tr.contentType)
+	}
+
+	return tr.client.Do(req)
+}
+
+type reportReq struct {
+	Name      string `json:"name"`
+	ChannelID string `json:"channel_id"`
+	ThingKey  string `json:"thing_key,omitempty"`
+	Format    string `json:"format"`
+	Template  string `json:"template,omitempty"`
+	Interval  string `json:"interval"`
+	Delivery  string `json:"delivery"`
+	Target    string `json:"target"`
+}
+
+func newService() reports.Service {
+	users := mocks.NewUsersService(map[string]string{validToken: email})
+	things := mocks.NewThingsService(map[string]string{thingKey: chanID})
+	repo := mocks.NewReportRepository()
+	reader := mocks.NewMessageReader(nil)
+	idp := mocks.NewIdentityProvider()
+	senders := map[string]reports.Sender{reports.Email: mocks.NewSender(nil)}
+
+	return reports.New(users, things, repo, reader, idp, senders)
+}
+
+func newServer(svc reports.Service) *httptest.Server {
+	return httptest.NewServer(api.MakeHandler(svc))
+}
+
+func toJSON(data interface{}) string {
+	jsonData, _ := json.Marshal(data)
+	return string(jsonData)
+}
+
+func TestCreateReport(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+
+	req := reportReq{
+		Name:      report.Name,
+		ChannelID: report.ChannelID,
+		ThingKey:  report.ThingKey,
+		Format:    report.Format,
+		Interval:  report.Interval.String(),
+		Delivery:  report.Delivery,
+		Target:    report.Target,
+	}
+	valid := toJSON(req)
+
+	invalidInterval := req
+	invalidInterval.Interval = "daily"
+
+	shortInterval := req
+	shortInterval.Interval = "1m"
+
+	invalidKey := req
+	invalidKey.ThingKey = "invalid"
+
+	unsupported := req
+	unsupported.Delivery = reports.Webhook
+	unsupported.Target = "https://example.com/reports"
+
+	cases := []struct {
+		desc        string
+		req         string
+		contentType string
+		token       string
+		status      int
+		location    string
+	}{
+		{
+			desc:        "create report with valid request",
+			req:         valid,
+			contentType: contentType,
+			token:       validToken,
+			status:      http.StatusCreated,
+			location:    "/reports/123e4567-e89b-12d3-a456-000000000001",
+		},
+		{
+			desc:        "create report with invalid token",
+			req:         valid,
+			contentType: contentType,
+			token:       invalidToken,
+			status:      http.StatusForbidden,
+		},
+		{
+			desc:        "create report with empty token",
+			req:         valid,
+			contentType: contentType,
+			token:       "",
+			status:      http.StatusForbidden,
+		},
+		{
+			desc:        "create report with invalid interval",
+			req:         toJSON(invalidInterval),
+			contentType: contentType,
+			token:       validToken,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "create report with too short interval",
+			req:         toJSON(shortInterval),
+			contentType: contentType,
+			token:       validToken,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "create report with invalid thing key",
+			req:         toJSON(invalidKey),
+			contentType: contentType,
+			token:       validToken,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "create report with unsupported delivery",
+			req:         toJSON(unsupported),
+			contentType: contentType,
+			token:       validToken,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "create report with invalid request format",
+			req:         "}",
+			contentType: contentType,
+			token:       validToken,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "create report without content type",
+			req:         valid,
+			contentType: "",
+			token:       validToken,
+			status:      http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/reports", ts.URL),
+			contentType: tc.contentType,
+			token:       tc.token,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		location := res.Header.Get("Location")
+		assert.Equal(t, tc.location, location, fmt.Sprintf("%s: expected location %s got %s", tc.desc, tc.location, location))
+	}
+}
+
+func TestViewReport(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+
+	saved, err := svc.CreateReport(validToken, report)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		id     string
+		token  string
+		status int
+	}{
+		{
+			desc:   "view existing report",
+			id:     saved.ID,
+			token:  validToken,
+			status: http.StatusOK,
+		},
+		{
+			desc:   "view report with invalid token",
+			id:     saved.ID,
+			token:  invalidToken,
+			status: http.StatusForbidden,
+		},
+		{
+			desc:   "view non-existing report",
+			id:     wrongID,
+			token:  validToken,
+			status: http.StatusNotFound,
+		},
+		{
+			desc:   "view report with malformed id",
+			id:     "unknown",
+			token:  validToken,
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/reports/%s", ts.URL, tc.id),
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		if tc.status != http.StatusOK {
+			continue
+		}
+
+		var body map[string]interface{}
+		json.NewDecoder(res.Body).Decode(&body)
+		assert.Equal(t, saved.ID, body["id"], fmt.Sprintf("%s: expected id %s got %v", tc.desc, saved.ID, body["id"]))
+		assert.Equal(t, report.Interval.String(), body["interval"], fmt.Sprintf("%s: expected interval %s got %v", tc.desc, report.Interval, body["interval"]))
+		_, ok := body["thing_key"]
+		assert.False(t, ok, fmt.Sprintf("%s: thing key must not be exposed", tc.desc))
+	}
+}
+
+func TestListReports(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+
+	n := 3
+	for i := 0; i < n; i++ {
+		_, err := svc.CreateReport(validToken, report)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	cases := []struct {
+		desc   string
+		token  string
+		status int
+		size   int
+	}{
+		{
+			desc:   "list reports",
+			token:  validToken,
+			status: http.StatusOK,
+			size:   n,
+		},
+		{
+			desc:   "list reports with invalid token",
+			token:  invalidToken,
+			status: http.StatusForbidden,
+			size:   0,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/reports", ts.URL),
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+
+		var body struct {
+			Reports []map[string]interface{} `json:"reports"`
+		}
+		json.NewDecoder(res.Body).Decode(&body)
+		assert.Len(t, body.Reports, tc.size, fmt.Sprintf("%s: expected %d reports got %d", tc.desc, tc.size, len(body.Reports)))
+	}
+}
+
+func TestGenerateReport(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+
+	saved, err := svc.CreateReport(validToken, report)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc        string
+		id          string
+		token       string
+		status      int
+		contentType string
+	}{
+		{
+			desc:        "generate existing report",
+			id:          saved.ID,
+			token:       validToken,
+			status:      http.StatusOK,
+			contentType: "text/csv",
+		},
+		{
+			desc:        "generate report with invalid token",
+			id:          saved.ID,
+			token:       invalidToken,
+			status:      http.StatusForbidden,
+			contentType: contentType,
+		},
+		{
+			desc:        "generate non-existing report",
+			id:          wrongID,
+			token:       validToken,
+			status:      http.StatusNotFound,
+			contentType: contentType,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/reports/%s/document", ts.URL, tc.id),
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		ct := res.Header.Get("Content-Type")
+		assert.Equal(t, tc.contentType, ct, fmt.Sprintf("%s: expected content type %s got %s", tc.desc, tc.contentType, ct))
+		if tc.status != http.StatusOK {
+			continue
+		}
+
+		body, err := ioutil.ReadAll(res.Body)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.True(t, strings.HasPrefix(string(body), "time,"), fmt.Sprintf("%s: expected CSV header got %s", tc.desc, body))
+	}
+}
+
+func TestRemoveReport(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+
+	saved, err := svc.CreateReport(validToken, report)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		id     string
+		token  string
+		status int
+	}{
+		{
+			desc:   "remove report with invalid token",
+			id:     saved.ID,
+			token:  invalidToken,
+			status: http.StatusForbidden,
+		},
+		{
+			desc:   "remove existing report",
+			id:     saved.ID,
+			token:  validToken,
+			status: http.StatusNoContent,
+		},
+		{
+			desc:   "remove removed report",
+			id:     saved.ID,
+			token:  validToken,
+			status: http.StatusNoContent,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodDelete,
+			url:    fmt.Sprintf("%s/reports/%s", ts.URL, tc.id),
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
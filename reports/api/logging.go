@@ -0,0 +1,109 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// +build !test
+
+package api
+
+import (
+	"fmt"
+	"time"
+
+	log "github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/reports"
+)
+
+var _ reports.Service = (*loggingMiddleware)(nil)
+
+type loggingMiddleware struct {
+	logger log.Logger
+	svc    reports.Service
+}
+
+// NewLoggingMiddleware adds logging facilities to the core service.
+func NewLoggingMiddleware(svc reports.Service, logger log.Logger) reports.Service {
+	return &loggingMiddleware{logger, svc}
+}
+
+
+func (lm *loggingMiddleware) CreateReport(key string, r reports.Report) (saved reports.Report, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method create_report for key %s and report %s took %s to complete", key, saved.ID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.CreateReport(key, r)
+}
+
+func (lm *loggingMiddleware) ViewReport(key, id string) (r reports.Report, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method view_report for key %s and report %s took %s to complete", key, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ViewReport(key, id)
+}
+
+func (lm *loggingMiddleware) ListReports(key string) (rs []reports.Report, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_reports for key %s took %s to complete", key, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListReports(key)
+}
+
+func (lm *loggingMiddleware) RemoveReport(key, id string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method remove_report for key %s and report %s took %s to complete", key, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RemoveReport(key, id)
+}
+
+func (lm *loggingMiddleware) GenerateReport(key, id string) (doc reports.Document, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method generate_report for key %s and report %s took %s to complete", key, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.GenerateReport(key, id)
+}
+
+func (lm *loggingMiddleware) SendDue(now time.Time) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method send_due for time %s took %s to complete", now.Format(time.RFC3339), time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.SendDue(now)
+}
@@ -0,0 +1,89 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// +build !test
+
+package api
+
+import (
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/mainflux/mainflux/reports"
+)
+
+var _ reports.Service = (*metricsMiddleware)(nil)
+
+type metricsMiddleware struct {
+	counter metrics.Counter
+	latency metrics.Histogram
+	svc     reports.Service
+}
+
+// MetricsMiddleware instruments core service by tracking request count and
+// latency.
+func MetricsMiddleware(svc reports.Service, counter metrics.Counter, latency metrics.Histogram) reports.Service {
+	return &metricsMiddleware{
+		counter: counter,
+		latency: latency,
+		svc:     svc,
+	}
+}
+
+func (mm *metricsMiddleware) CreateReport(key string, r reports.Report) (reports.Report, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "create_report").Add(1)
+		mm.latency.With("method", "create_report").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.CreateReport(key, r)
+}
+
+func (mm *metricsMiddleware) ViewReport(key, id string) (reports.Report, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "view_report").Add(1)
+		mm.latency.With("method", "view_report").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.ViewReport(key, id)
+}
+
+func (mm *metricsMiddleware) ListReports(key string) ([]reports.Report, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "list_reports").Add(1)
+		mm.latency.With("method", "list_reports").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.ListReports(key)
+}
+
+func (mm *metricsMiddleware) RemoveReport(key, id string) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "remove_report").Add(1)
+		mm.latency.With("method", "remove_report").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.RemoveReport(key, id)
+}
+
+func (mm *metricsMiddleware) GenerateReport(key, id string) (reports.Document, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "generate_report").Add(1)
+		mm.latency.With("method", "generate_report").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.GenerateReport(key, id)
+}
+
+func (mm *metricsMiddleware) SendDue(now time.Time) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "send_due").Add(1)
+		mm.latency.With("method", "send_due").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.SendDue(now)
+}
@@ -0,0 +1,67 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package api
+
+import "github.com/mainflux/mainflux/reports"
+
+type apiReq interface {
+	validate() error
+}
+
+type createReq struct {
+	key       string
+	Name      string `json:"name"`
+	ChannelID string `json:"channel_id"`
+	ThingKey  string `json:"thing_key"`
+	Format    string `json:"format"`
+	Template  string `json:"template"`
+	Interval  string `json:"interval"`
+	Delivery  string `json:"delivery"`
+	Target    string `json:"target"`
+}
+
+func (req createReq) validate() error {
+	if req.key == "" {
+		return reports.ErrUnauthorizedAccess
+	}
+
+	if req.Name == "" || req.ChannelID == "" || req.ThingKey == "" || req.Interval == "" {
+		return reports.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type entityReq struct {
+	key string
+	id  string
+}
+
+func (req entityReq) validate() error {
+	if req.key == "" {
+		return reports.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" {
+		return reports.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type listReq struct {
+	key string
+}
+
+func (req listReq) validate() error {
+	if req.key == "" {
+		return reports.ErrUnauthorizedAccess
+	}
+
+	return nil
+}
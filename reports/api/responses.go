@@ -0,0 +1,117 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/mainflux/mainflux"
+)
+
+var (
+	_ mainflux.Response = (*createRes)(nil)
+	_ mainflux.Response = (*reportRes)(nil)
+	_ mainflux.Response = (*listRes)(nil)
+	_ mainflux.Response = (*removeRes)(nil)
+	_ mainflux.Response = (*documentRes)(nil)
+)
+
+type createRes struct {
+	id string
+}
+
+func (res createRes) Code() int {
+	return http.StatusCreated
+}
+
+func (res createRes) Headers() map[string]string {
+	return map[string]string{
+		"Location": fmt.Sprintf("/reports/%s", res.id),
+	}
+}
+
+func (res createRes) Empty() bool {
+	return true
+}
+
+type reportRes struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	ChannelID string    `json:"channel_id"`
+	Format    string    `json:"format"`
+	Template  string    `json:"template,omitempty"`
+	Interval  string    `json:"interval"`
+	Delivery  string    `json:"delivery"`
+	Target    string    `json:"target"`
+	NextRun   time.Time `json:"next_run"`
+}
+
+func (res reportRes) Code() int {
+	return http.StatusOK
+}
+
+func (res reportRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res reportRes) Empty() bool {
+	return false
+}
+
+type listRes struct {
+	Reports []reportRes `json:"reports"`
+}
+
+func (res listRes) Code() int {
+	return http.StatusOK
+}
+
+func (res listRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res listRes) Empty() bool {
+	return false
+}
+
+type removeRes struct{}
+
+func (res removeRes) Code() int {
+	return http.StatusNoContent
+}
+
+func (res removeRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res removeRes) Empty() bool {
+	return true
+}
+
+type documentRes struct {
+	name        string
+	contentType string
+	content     []byte
+}
+
+func (res documentRes) Code() int {
+	return http.StatusOK
+}
+
+func (res documentRes) Headers() map[string]string {
+	return map[string]string{
+		"Content-Type":        res.contentType,
+		"Content-Disposition": fmt.Sprintf("attachment; filename=%q", res.name),
+	}
+}
+
+func (res documentRes) Empty() bool {
+	return false
+}
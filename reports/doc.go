@@ -0,0 +1,12 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// Package reports contains the domain concept definitions needed to support
+// Mainflux reports service functionality. Reports are periodically rendered
+// from the channel messages stored by the readers and delivered by email or
+// webhook.
+package reports
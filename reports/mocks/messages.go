@@ -0,0 +1,40 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package mocks
+
+import (
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/reports"
+)
+
+var _ reports.MessageReader = (*messageReaderMock)(nil)
+
+type messageReaderMock struct {
+	messages map[string][]mainflux.Message
+}
+
+// NewMessageReader creates the message reader mock holding the provided
+// messages of each channel.
+func NewMessageReader(messages map[string][]mainflux.Message) reports.MessageReader {
+	return messageReaderMock{messages}
+}
+
+func (mrm messageReaderMock) ReadMessages(chanID, _ string, from, to time.Time) ([]mainflux.Message, error) {
+	start, end := float64(from.UnixNano())/1e9, float64(to.UnixNano())/1e9
+
+	msgs := []mainflux.Message{}
+	for _, msg := range mrm.messages[chanID] {
+		if msg.Time >= start && msg.Time < end {
+			msgs = append(msgs, msg)
+		}
+	}
+
+	return msgs, nil
+}
@@ -0,0 +1,107 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package mocks
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux/reports"
+)
+
+var _ reports.ReportRepository = (*reportRepositoryMock)(nil)
+
+type reportRepositoryMock struct {
+	mu      sync.Mutex
+	reports map[string]reports.Report
+}
+
+// NewReportRepository creates in-memory report repository.
+func NewReportRepository() reports.ReportRepository {
+	return &reportRepositoryMock{
+		reports: make(map[string]reports.Report),
+	}
+}
+
+func (rrm *reportRepositoryMock) Save(r reports.Report) (string, error) {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	rrm.reports[r.ID] = r
+	return r.ID, nil
+}
+
+func (rrm *reportRepositoryMock) RetrieveByID(owner, id string) (reports.Report, error) {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	r, ok := rrm.reports[id]
+	if !ok || r.Owner != owner {
+		return reports.Report{}, reports.ErrNotFound
+	}
+
+	return r, nil
+}
+
+func (rrm *reportRepositoryMock) RetrieveAll(owner string) ([]reports.Report, error) {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	rs := []reports.Report{}
+	for _, r := range rrm.reports {
+		if r.Owner == owner {
+			rs = append(rs, r)
+		}
+	}
+
+	sort.Slice(rs, func(i, j int) bool {
+		return rs[i].ID < rs[j].ID
+	})
+
+	return rs, nil
+}
+
+func (rrm *reportRepositoryMock) RetrieveDue(now time.Time) ([]reports.Report, error) {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	rs := []reports.Report{}
+	for _, r := range rrm.reports {
+		if !r.NextRun.After(now) {
+			rs = append(rs, r)
+		}
+	}
+
+	return rs, nil
+}
+
+func (rrm *reportRepositoryMock) UpdateNextRun(id string, next time.Time) error {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	r, ok := rrm.reports[id]
+	if !ok {
+		return reports.ErrNotFound
+	}
+
+	r.NextRun = next
+	rrm.reports[id] = r
+	return nil
+}
+
+func (rrm *reportRepositoryMock) Remove(owner, id string) error {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	if r, ok := rrm.reports[id]; ok && r.Owner == owner {
+		delete(rrm.reports, id)
+	}
+
+	return nil
+}
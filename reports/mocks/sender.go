@@ -0,0 +1,53 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package mocks
+
+import (
+	"sync"
+
+	"github.com/mainflux/mainflux/reports"
+)
+
+var _ reports.Sender = (*SenderMock)(nil)
+
+// SenderMock records the delivered reports.
+type SenderMock struct {
+	mu   sync.Mutex
+	sent map[string][]reports.Document
+	fail map[string]error
+}
+
+// NewSender creates the sender mock failing the delivery to the provided
+// targets with the mapped errors.
+func NewSender(fail map[string]error) *SenderMock {
+	return &SenderMock{
+		sent: make(map[string][]reports.Document),
+		fail: fail,
+	}
+}
+
+// Send records the document delivered to the target.
+func (sm *SenderMock) Send(target string, _ reports.Report, doc reports.Document) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	if err, ok := sm.fail[target]; ok {
+		return err
+	}
+
+	sm.sent[target] = append(sm.sent[target], doc)
+	return nil
+}
+
+// Sent returns the documents delivered to the target.
+func (sm *SenderMock) Sent(target string) []reports.Document {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	return sm.sent[target]
+}
@@ -0,0 +1,66 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package mocks
+
+import (
+	"context"
+
+	"github.com/mainflux/mainflux"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var errUnauthorized = status.Error(codes.PermissionDenied, "missing or invalid credentials provided")
+
+var _ mainflux.ThingsServiceClient = (*thingsServiceMock)(nil)
+
+type thingsServiceMock struct {
+	channels map[string]string
+}
+
+// NewThingsService creates the things service mock granting the thing keys
+// access to the channels they are mapped to.
+func NewThingsService(channels map[string]string) mainflux.ThingsServiceClient {
+	return thingsServiceMock{channels}
+}
+
+func (svc thingsServiceMock) CanAccess(ctx context.Context, in *mainflux.AccessReq, opts ...grpc.CallOption) (*mainflux.ThingID, error) {
+	if ch, ok := svc.channels[in.GetToken()]; !ok || ch != in.GetChanID() {
+		return nil, errUnauthorized
+	}
+
+	return &mainflux.ThingID{Value: in.GetToken()}, nil
+}
+
+func (svc thingsServiceMock) Identify(ctx context.Context, in *mainflux.Token, opts ...grpc.CallOption) (*mainflux.ThingID, error) {
+	if _, ok := svc.channels[in.GetValue()]; !ok {
+		return nil, errUnauthorized
+	}
+
+	return &mainflux.ThingID{Value: in.GetValue()}, nil
+}
+
+func (svc thingsServiceMock) IdentifyBatch(ctx context.Context, in *mainflux.Tokens, opts ...grpc.CallOption) (*mainflux.ThingIDs, error) {
+	ids := make([]string, len(in.GetValues()))
+	for i, key := range in.GetValues() {
+		if _, ok := svc.channels[key]; ok {
+			ids[i] = key
+		}
+	}
+
+	return &mainflux.ThingIDs{Values: ids}, nil
+}
+
+func (svc thingsServiceMock) CanRead(_ context.Context, _ *mainflux.AccessReq, _ ...grpc.CallOption) (*mainflux.UserID, error) {
+	return nil, errUnauthorized
+}
+
+func (svc thingsServiceMock) ListChannelsByThing(_ context.Context, _ *mainflux.ThingID, _ ...grpc.CallOption) (*mainflux.ChannelIDs, error) {
+	return &mainflux.ChannelIDs{}, nil
+}
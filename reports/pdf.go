@@ -0,0 +1,116 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package reports
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// A4 page layout, in points, of the text-only PDF documents.
+const (
+	pageWidth    = 595
+	pageHeight   = 842
+	pageMargin   = 50
+	fontSize     = 10
+	lineHeight   = 14
+	linesPerPage = (pageHeight - 2*pageMargin) / lineHeight
+	charsPerLine = 95
+)
+
+// pdf renders the text as the PDF document using the standard Courier
+// font, wrapping long lines and breaking pages as needed. Characters outside
+// of the printable ASCII range are replaced by question marks.
+func pdf(text []byte) []byte {
+	lines := wrap(string(text))
+
+	var pages [][]string
+	for len(lines) > linesPerPage {
+		pages = append(pages, lines[:linesPerPage])
+		lines = lines[linesPerPage:]
+	}
+	pages = append(pages, lines)
+
+	// Objects 1 and 2 are the catalog and the page tree, object 3 is the
+	// font, followed by the page and the content objects of each page.
+	var objs []string
+	var kids []string
+	for i := range pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", 4+2*i))
+	}
+
+	objs = append(objs,
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier >>",
+	)
+
+	for i, page := range pages {
+		var content bytes.Buffer
+		fmt.Fprintf(&content, "BT /F1 %d Tf %d TL %d %d Td\n", fontSize, lineHeight, pageMargin, pageHeight-pageMargin)
+		for _, line := range page {
+			fmt.Fprintf(&content, "(%s) '\n", escape(line))
+		}
+		content.WriteString("ET")
+
+		objs = append(objs,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>", pageWidth, pageHeight, 5+2*i),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", content.Len(), content.String()),
+		)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+
+	offsets := make([]int, len(objs))
+	for i, obj := range objs {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objs)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objs)+1, xref)
+
+	return buf.Bytes()
+}
+
+func wrap(text string) []string {
+	var lines []string
+	for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		line = strings.TrimRight(line, "\r")
+		for len(line) > charsPerLine {
+			lines = append(lines, line[:charsPerLine])
+			line = line[charsPerLine:]
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func escape(line string) string {
+	var b strings.Builder
+	for _, r := range line {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteRune('\\')
+			b.WriteRune(r)
+		case r == '\t':
+			b.WriteString("    ")
+		case r < ' ' || r > '~':
+			b.WriteRune('?')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,91 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq" // required for SQL access
+	mfpostgres "github.com/mainflux/mainflux/postgres"
+	migrate "github.com/rubenv/sql-migrate"
+)
+
+// Config defines the options that are used when connecting to a PostgreSQL instance
+type Config struct {
+	Host        string
+	Port        string
+	User        string
+	Pass        string
+	Name        string
+	SSLMode     string
+	SSLCert     string
+	SSLKey      string
+	SSLRootCert string
+	Target      string
+	SyncCommit  string
+}
+
+// Connect creates a connection to the PostgreSQL instance and applies any
+// unapplied database migrations. A non-nil error is returned to indicate
+// failure.
+func Connect(cfg Config) (*sqlx.DB, error) {
+	url := fmt.Sprintf("user=%s dbname=%s password=%s sslmode=%s sslcert=%s sslkey=%s sslrootcert=%s", cfg.User, cfg.Name, cfg.Pass, cfg.SSLMode, cfg.SSLCert, cfg.SSLKey, cfg.SSLRootCert)
+
+	db, err := mfpostgres.Open(mfpostgres.Config{
+		Hosts:      cfg.Host,
+		Port:       cfg.Port,
+		DSN:        url,
+		Target:     cfg.Target,
+		SyncCommit: cfg.SyncCommit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := migrateDB(db); err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+func migrateDB(db *sqlx.DB) error {
+	migrations := &migrate.MemoryMigrationSource{
+		Migrations: []*migrate.Migration{
+			{
+				Id: "reports_1",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS reports (
+						id         UUID,
+						owner      VARCHAR(254) NOT NULL,
+						name       VARCHAR(1024) NOT NULL,
+						channel_id VARCHAR(254) NOT NULL,
+						thing_key  VARCHAR(4096) NOT NULL,
+						format     VARCHAR(16) NOT NULL,
+						template   TEXT NOT NULL,
+						period     BIGINT NOT NULL,
+						delivery   VARCHAR(32) NOT NULL,
+						target     TEXT NOT NULL,
+						next_run   TIMESTAMPTZ NOT NULL,
+						PRIMARY KEY (id)
+					)`,
+					`CREATE INDEX IF NOT EXISTS reports_owner_idx ON reports (owner)`,
+					`CREATE INDEX IF NOT EXISTS reports_next_run_idx ON reports (next_run)`,
+				},
+				Down: []string{
+					"DROP TABLE reports",
+				},
+			},
+		},
+	}
+
+	_, err := migrate.Exec(db.DB, "postgres", migrations, migrate.Up)
+
+	return err
+}
@@ -0,0 +1,183 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/mainflux/mainflux/reports"
+)
+
+const (
+	duplicateErr = "unique_violation"
+	uuidErr      = "invalid input syntax for type uuid"
+)
+
+var _ reports.ReportRepository = (*reportRepository)(nil)
+
+type reportRepository struct {
+	db *sqlx.DB
+}
+
+// NewReportRepository instantiates a PostgreSQL implementation of report
+// repository.
+func NewReportRepository(db *sqlx.DB) reports.ReportRepository {
+	return &reportRepository{db: db}
+}
+
+func (rr reportRepository) Save(r reports.Report) (string, error) {
+	q := `INSERT INTO reports (id, owner, name, channel_id, thing_key, format, template, period, delivery, target, next_run)
+	      VALUES (:id, :owner, :name, :channel_id, :thing_key, :format, :template, :period, :delivery, :target, :next_run)`
+
+	if _, err := rr.db.NamedExec(q, toDBReport(r)); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == duplicateErr {
+			return "", reports.ErrMalformedEntity
+		}
+		return "", err
+	}
+
+	return r.ID, nil
+}
+
+func (rr reportRepository) RetrieveByID(owner, id string) (reports.Report, error) {
+	q := `SELECT id, owner, name, channel_id, thing_key, format, template, period, delivery, target, next_run
+	      FROM reports WHERE id = $1 AND owner = $2`
+
+	dbr := dbReport{}
+	if err := rr.db.QueryRowx(q, id, owner).StructScan(&dbr); err != nil {
+		if err == sql.ErrNoRows {
+			return reports.Report{}, reports.ErrNotFound
+		}
+
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Message == uuidErr {
+			return reports.Report{}, reports.ErrNotFound
+		}
+
+		return reports.Report{}, err
+	}
+
+	return toReport(dbr), nil
+}
+
+func (rr reportRepository) RetrieveAll(owner string) ([]reports.Report, error) {
+	q := `SELECT id, owner, name, channel_id, thing_key, format, template, period, delivery, target, next_run
+	      FROM reports WHERE owner = $1 ORDER BY id`
+
+	return rr.retrieve(q, owner)
+}
+
+func (rr reportRepository) RetrieveDue(now time.Time) ([]reports.Report, error) {
+	q := `SELECT id, owner, name, channel_id, thing_key, format, template, period, delivery, target, next_run
+	      FROM reports WHERE next_run <= $1 ORDER BY next_run`
+
+	return rr.retrieve(q, now)
+}
+
+func (rr reportRepository) UpdateNextRun(id string, next time.Time) error {
+	q := `UPDATE reports SET next_run = $1 WHERE id = $2`
+
+	res, err := rr.db.Exec(q, next, id)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Message == uuidErr {
+			return reports.ErrNotFound
+		}
+		return err
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if cnt == 0 {
+		return reports.ErrNotFound
+	}
+
+	return nil
+}
+
+func (rr reportRepository) Remove(owner, id string) error {
+	q := `DELETE FROM reports WHERE id = $1 AND owner = $2`
+
+	if _, err := rr.db.Exec(q, id, owner); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Message == uuidErr {
+			return nil
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (rr reportRepository) retrieve(q string, arg interface{}) ([]reports.Report, error) {
+	rows, err := rr.db.Queryx(q, arg)
+	if err != nil {
+		return []reports.Report{}, err
+	}
+	defer rows.Close()
+
+	rs := []reports.Report{}
+	for rows.Next() {
+		dbr := dbReport{}
+		if err := rows.StructScan(&dbr); err != nil {
+			return []reports.Report{}, err
+		}
+		rs = append(rs, toReport(dbr))
+	}
+
+	return rs, nil
+}
+
+type dbReport struct {
+	ID        string    `db:"id"`
+	Owner     string    `db:"owner"`
+	Name      string    `db:"name"`
+	ChannelID string    `db:"channel_id"`
+	ThingKey  string    `db:"thing_key"`
+	Format    string    `db:"format"`
+	Template  string    `db:"template"`
+	Period    int64     `db:"period"`
+	Delivery  string    `db:"delivery"`
+	Target    string    `db:"target"`
+	NextRun   time.Time `db:"next_run"`
+}
+
+func toDBReport(r reports.Report) dbReport {
+	return dbReport{
+		ID:        r.ID,
+		Owner:     r.Owner,
+		Name:      r.Name,
+		ChannelID: r.ChannelID,
+		ThingKey:  r.ThingKey,
+		Format:    r.Format,
+		Template:  r.Template,
+		Period:    int64(r.Interval / time.Second),
+		Delivery:  r.Delivery,
+		Target:    r.Target,
+		NextRun:   r.NextRun,
+	}
+}
+
+func toReport(dbr dbReport) reports.Report {
+	return reports.Report{
+		ID:        dbr.ID,
+		Owner:     dbr.Owner,
+		Name:      dbr.Name,
+		ChannelID: dbr.ChannelID,
+		ThingKey:  dbr.ThingKey,
+		Format:    dbr.Format,
+		Template:  dbr.Template,
+		Interval:  time.Duration(dbr.Period) * time.Second,
+		Delivery:  dbr.Delivery,
+		Target:    dbr.Target,
+		NextRun:   dbr.NextRun.UTC(),
+	}
+}
@@ -0,0 +1,229 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package postgres_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/mainflux/mainflux/reports"
+	"github.com/mainflux/mainflux/reports/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const owner = "user@example.com"
+
+func newReport(t *testing.T, owner string, next time.Time) reports.Report {
+	id, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	return reports.Report{
+		ID:        id.String(),
+		Owner:     owner,
+		Name:      "consumption",
+		ChannelID: "1",
+		ThingKey:  "key",
+		Format:    reports.CSV,
+		Template:  "{{range .Series}}{{.Name}},{{.Sum}}\n{{end}}",
+		Interval:  24 * time.Hour,
+		Delivery:  reports.Webhook,
+		Target:    "https://example.com/reports",
+		NextRun:   next.UTC().Truncate(time.Microsecond),
+	}
+}
+
+func TestReportSave(t *testing.T) {
+	repo := postgres.NewReportRepository(db)
+	r := newReport(t, owner, time.Now())
+
+	cases := []struct {
+		desc   string
+		report reports.Report
+		err    error
+	}{
+		{
+			desc:   "save new report",
+			report: r,
+			err:    nil,
+		},
+		{
+			desc:   "save existing report",
+			report: r,
+			err:    reports.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := repo.Save(tc.report)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestReportRetrieveByID(t *testing.T) {
+	repo := postgres.NewReportRepository(db)
+	r := newReport(t, owner, time.Now())
+	id, err := repo.Save(r)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		owner string
+		id    string
+		err   error
+	}{
+		{
+			desc:  "retrieve existing report",
+			owner: owner,
+			id:    id,
+			err:   nil,
+		},
+		{
+			desc:  "retrieve report of another user",
+			owner: "other@example.com",
+			id:    id,
+			err:   reports.ErrNotFound,
+		},
+		{
+			desc:  "retrieve report with malformed id",
+			owner: owner,
+			id:    "malformed",
+			err:   reports.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		saved, err := repo.RetrieveByID(tc.owner, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err == nil {
+			assert.Equal(t, r, saved, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, r, saved))
+		}
+	}
+}
+
+func TestReportRetrieveAll(t *testing.T) {
+	repo := postgres.NewReportRepository(db)
+	user := "all@example.com"
+
+	n := 3
+	for i := 0; i < n; i++ {
+		_, err := repo.Save(newReport(t, user, time.Now()))
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	}
+
+	cases := []struct {
+		desc  string
+		owner string
+		size  int
+	}{
+		{
+			desc:  "retrieve all reports of the user",
+			owner: user,
+			size:  n,
+		},
+		{
+			desc:  "retrieve all reports of the user without reports",
+			owner: "none@example.com",
+			size:  0,
+		},
+	}
+
+	for _, tc := range cases {
+		rs, err := repo.RetrieveAll(tc.owner)
+		assert.Nil(t, err, fmt.Sprintf("%s: got unexpected error: %s\n", tc.desc, err))
+		assert.Len(t, rs, tc.size, fmt.Sprintf("%s: expected %d reports got %d\n", tc.desc, tc.size, len(rs)))
+	}
+}
+
+func TestReportRetrieveDue(t *testing.T) {
+	repo := postgres.NewReportRepository(db)
+	now := time.Now().Add(10 * 365 * 24 * time.Hour)
+
+	due := newReport(t, "due@example.com", now.Add(-time.Minute))
+	_, err := repo.Save(due)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	later := newReport(t, "due@example.com", now.Add(time.Hour))
+	_, err = repo.Save(later)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	rs, err := repo.RetrieveDue(now)
+	assert.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.True(t, contains(rs, due.ID), "due report not retrieved")
+	assert.False(t, contains(rs, later.ID), "report retrieved before it's due")
+
+	err = repo.UpdateNextRun(due.ID, now.Add(time.Hour))
+	assert.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	rs, err = repo.RetrieveDue(now)
+	assert.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.False(t, contains(rs, due.ID), "rescheduled report retrieved")
+}
+
+func TestReportUpdateNextRun(t *testing.T) {
+	repo := postgres.NewReportRepository(db)
+	r := newReport(t, owner, time.Now())
+	_, err := repo.Save(r)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	unknown, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := []struct {
+		desc string
+		id   string
+		err  error
+	}{
+		{
+			desc: "update next run of existing report",
+			id:   r.ID,
+			err:  nil,
+		},
+		{
+			desc: "update next run of non-existing report",
+			id:   unknown.String(),
+			err:  reports.ErrNotFound,
+		},
+		{
+			desc: "update next run of report with malformed id",
+			id:   "malformed",
+			err:  reports.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := repo.UpdateNextRun(tc.id, time.Now())
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestReportRemove(t *testing.T) {
+	repo := postgres.NewReportRepository(db)
+	r := newReport(t, owner, time.Now())
+	id, err := repo.Save(r)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	for i := 0; i < 2; i++ {
+		err := repo.Remove(owner, id)
+		assert.Nil(t, err, fmt.Sprintf("#%d: got unexpected error: %s\n", i, err))
+	}
+
+	_, err = repo.RetrieveByID(owner, id)
+	assert.Equal(t, reports.ErrNotFound, err, fmt.Sprintf("expected %s got %s\n", reports.ErrNotFound, err))
+}
+
+func contains(rs []reports.Report, id string) bool {
+	for _, r := range rs {
+		if r.ID == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// Package reader contains the message reader fetching the channel messages
+// from the readers HTTP API.
+package reader
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/reports"
+)
+
+const (
+	pageSize = 100
+
+	// maxMessages bounds the number of messages included in the single
+	// report.
+	maxMessages = 100000
+)
+
+// ErrReadFailed indicates that the reader responded with non-200 status.
+var ErrReadFailed = errors.New("reader responded with unexpected status")
+
+var _ reports.MessageReader = (*reader)(nil)
+
+type reader struct {
+	url    string
+	client *http.Client
+}
+
+type messagesPage struct {
+	Total    uint64             `json:"total"`
+	Messages []mainflux.Message `json:"messages"`
+}
+
+// New instantiates the message reader using the readers HTTP API available
+// at the provided URL.
+func New(url string, timeout time.Duration) reports.MessageReader {
+	return reader{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// ReadMessages pages through the channel messages, which the readers return
+// starting from the latest one, until it reaches the messages published
+// before the time range.
+func (r reader) ReadMessages(chanID, key string, from, to time.Time) ([]mainflux.Message, error) {
+	start, end := toSeconds(from), toSeconds(to)
+
+	msgs := []mainflux.Message{}
+	for offset := uint64(0); len(msgs) < maxMessages; offset += pageSize {
+		page, err := r.page(chanID, key, offset)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, msg := range page.Messages {
+			if msg.Time >= start && msg.Time < end {
+				msgs = append(msgs, msg)
+			}
+		}
+
+		n := len(page.Messages)
+		if n == 0 || page.Messages[n-1].Time < start || offset+pageSize >= page.Total {
+			break
+		}
+	}
+
+	return msgs, nil
+}
+
+func (r reader) page(chanID, key string, offset uint64) (messagesPage, error) {
+	u := fmt.Sprintf("%s/channels/%s/messages?offset=%d&limit=%d", r.url, url.PathEscape(chanID), offset, pageSize)
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return messagesPage{}, err
+	}
+	req.Header.Set("Authorization", key)
+
+	res, err := r.client.Do(req)
+	if err != nil {
+		return messagesPage{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return messagesPage{}, ErrReadFailed
+	}
+
+	var page messagesPage
+	if err := json.NewDecoder(res.Body).Decode(&page); err != nil {
+		return messagesPage{}, err
+	}
+
+	return page, nil
+}
+
+func toSeconds(t time.Time) float64 {
+	return float64(t.UnixNano()) / 1e9
+}
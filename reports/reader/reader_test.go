@@ -0,0 +1,109 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package reader_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/reports/reader"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	chanID = "1"
+	key    = "key"
+)
+
+func newServer(msgs []mainflux.Message, requests *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		if r.Header.Get("Authorization") != key {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+		page := msgs[min(offset, len(msgs)):min(offset+limit, len(msgs))]
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"total":    len(msgs),
+			"offset":   offset,
+			"limit":    limit,
+			"messages": page,
+		})
+	}))
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func TestReadMessages(t *testing.T) {
+	to := time.Unix(100000, 0)
+	from := to.Add(-time.Hour)
+
+	// Messages are ordered from the latest one, one every minute, starting
+	// half an hour after the time range.
+	var msgs []mainflux.Message
+	for i := 0; i < 500; i++ {
+		msgs = append(msgs, mainflux.Message{
+			Channel: chanID,
+			Name:    "energy",
+			Time:    float64(to.Add(30*time.Minute).Unix() - int64(i*60)),
+			Value:   &mainflux.Message_FloatValue{FloatValue: 1},
+		})
+	}
+
+	requests := 0
+	ts := newServer(msgs, &requests)
+	defer ts.Close()
+
+	r := reader.New(ts.URL, time.Second)
+
+	cases := []struct {
+		desc     string
+		key      string
+		size     int
+		requests int
+		err      error
+	}{
+		{
+			desc:     "read messages within time range",
+			key:      key,
+			size:     60,
+			requests: 1,
+			err:      nil,
+		},
+		{
+			desc:     "read messages with invalid key",
+			key:      "invalid",
+			size:     0,
+			requests: 1,
+			err:      reader.ErrReadFailed,
+		},
+	}
+
+	for _, tc := range cases {
+		requests = 0
+		res, err := r.ReadMessages(chanID, tc.key, from, to)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+		assert.Len(t, res, tc.size, fmt.Sprintf("%s: expected %d messages got %d", tc.desc, tc.size, len(res)))
+		assert.Equal(t, tc.requests, requests, fmt.Sprintf("%s: expected %d requests got %d", tc.desc, tc.requests, requests))
+	}
+}
@@ -0,0 +1,188 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package reports
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"text/template"
+	"time"
+
+	"github.com/mainflux/mainflux"
+)
+
+const (
+	csvContentType = "text/csv"
+	pdfContentType = "application/pdf"
+	timeFormat     = "2006-01-02 15:04:05"
+)
+
+const defaultTemplate = `{{.Name}}
+{{.From.Format "2006-01-02 15:04"}} - {{.To.Format "2006-01-02 15:04"}} UTC
+
+{{range .Series}}{{.Name}} [{{.Unit}}]: count {{.Count}}, min {{.Min}}, max {{.Max}}, sum {{.Sum}}, avg {{printf "%.3f" .Avg}}
+{{else}}No messages.
+{{end}}`
+
+// Data is the data the report templates are executed against.
+type Data struct {
+	Name      string
+	ChannelID string
+	From      time.Time
+	To        time.Time
+	Messages  []mainflux.Message
+	Series    []Series
+}
+
+// Series summarizes the numeric values of the messages sharing the name and
+// the unit (e.g. the daily consumption is the sum of the series).
+type Series struct {
+	Name  string
+	Unit  string
+	Count uint64
+	Min   float64
+	Max   float64
+	Sum   float64
+	Avg   float64
+}
+
+// Render renders the report covering the provided time range. Without the
+// template, the CSV report lists the messages, while the PDF report
+// summarizes the series.
+func Render(r Report, from, to time.Time, msgs []mainflux.Message) (Document, error) {
+	data := Data{
+		Name:      r.Name,
+		ChannelID: r.ChannelID,
+		From:      from.UTC(),
+		To:        to.UTC(),
+		Messages:  msgs,
+		Series:    summarize(msgs),
+	}
+
+	name := fmt.Sprintf("%s-%s.%s", r.Name, data.To.Format("20060102T1504"), r.Format)
+
+	switch r.Format {
+	case CSV:
+		content, err := renderCSV(r.Template, data)
+		if err != nil {
+			return Document{}, err
+		}
+		return Document{Name: name, ContentType: csvContentType, Content: content}, nil
+	case PDF:
+		tmpl := r.Template
+		if tmpl == "" {
+			tmpl = defaultTemplate
+		}
+
+		text, err := execute(r.Name, tmpl, data)
+		if err != nil {
+			return Document{}, err
+		}
+		return Document{Name: name, ContentType: pdfContentType, Content: pdf(text)}, nil
+	default:
+		return Document{}, ErrMalformedEntity
+	}
+}
+
+func renderCSV(tmpl string, data Data) ([]byte, error) {
+	if tmpl != "" {
+		return execute(data.Name, tmpl, data)
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"time", "publisher", "subtopic", "name", "unit", "value"})
+	for _, msg := range data.Messages {
+		w.Write([]string{
+			toTime(msg.Time).Format(timeFormat),
+			msg.Publisher,
+			msg.Subtopic,
+			msg.Name,
+			msg.Unit,
+			value(msg),
+		})
+	}
+	w.Flush()
+
+	return buf.Bytes(), w.Error()
+}
+
+func execute(name, tmpl string, data Data) ([]byte, error) {
+	t, err := template.New(name).Parse(tmpl)
+	if err != nil {
+		return nil, ErrMalformedEntity
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func summarize(msgs []mainflux.Message) []Series {
+	index := map[[2]string]*Series{}
+	for _, msg := range msgs {
+		v, ok := msg.Value.(*mainflux.Message_FloatValue)
+		if !ok {
+			continue
+		}
+
+		key := [2]string{msg.Name, msg.Unit}
+		s, ok := index[key]
+		if !ok {
+			s = &Series{Name: msg.Name, Unit: msg.Unit, Min: math.Inf(1), Max: math.Inf(-1)}
+			index[key] = s
+		}
+
+		s.Count++
+		s.Sum += v.FloatValue
+		s.Min = math.Min(s.Min, v.FloatValue)
+		s.Max = math.Max(s.Max, v.FloatValue)
+	}
+
+	series := []Series{}
+	for _, s := range index {
+		s.Avg = s.Sum / float64(s.Count)
+		series = append(series, *s)
+	}
+
+	sort.Slice(series, func(i, j int) bool {
+		if series[i].Name != series[j].Name {
+			return series[i].Name < series[j].Name
+		}
+		return series[i].Unit < series[j].Unit
+	})
+
+	return series
+}
+
+func value(msg mainflux.Message) string {
+	switch v := msg.Value.(type) {
+	case *mainflux.Message_FloatValue:
+		return strconv.FormatFloat(v.FloatValue, 'f', -1, 64)
+	case *mainflux.Message_StringValue:
+		return v.StringValue
+	case *mainflux.Message_BoolValue:
+		return strconv.FormatBool(v.BoolValue)
+	case *mainflux.Message_DataValue:
+		return v.DataValue
+	default:
+		return ""
+	}
+}
+
+func toTime(t float64) time.Time {
+	sec, frac := math.Modf(t)
+	return time.Unix(int64(sec), int64(frac*1e9)).UTC()
+}
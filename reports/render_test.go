@@ -0,0 +1,92 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package reports_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/reports"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRender(t *testing.T) {
+	to := time.Date(2019, 5, 1, 0, 0, 0, 0, time.UTC)
+	from := to.Add(-24 * time.Hour)
+	ts := float64(from.Add(time.Hour).Unix())
+
+	msgs := []mainflux.Message{
+		{Publisher: "1", Name: "energy", Unit: "kWh", Time: ts, Value: &mainflux.Message_FloatValue{FloatValue: 1.5}},
+		{Publisher: "2", Name: "energy", Unit: "kWh", Time: ts, Value: &mainflux.Message_FloatValue{FloatValue: 2.5}},
+		{Publisher: "2", Name: "alarm", Time: ts, Value: &mainflux.Message_BoolValue{BoolValue: true}},
+	}
+
+	consumption := `{{range .Series}}{{.Name}},{{.Sum}}
+{{end}}`
+	alarms := `{{range .Messages}}{{if eq .Name "alarm"}}{{.Publisher}}
+{{end}}{{end}}`
+
+	many := strings.Repeat("line\n", 200)
+
+	cases := []struct {
+		desc        string
+		format      string
+		template    string
+		contentType string
+		contains    []string
+	}{
+		{
+			desc:        "render CSV report without template",
+			format:      reports.CSV,
+			contentType: "text/csv",
+			contains:    []string{"time,publisher,subtopic,name,unit,value", "2019-04-30 01:00:00,1,,energy,kWh,1.5", ",alarm,,true"},
+		},
+		{
+			desc:        "render CSV report with consumption template",
+			format:      reports.CSV,
+			template:    consumption,
+			contentType: "text/csv",
+			contains:    []string{"energy,4"},
+		},
+		{
+			desc:        "render PDF report without template",
+			format:      reports.PDF,
+			contentType: "application/pdf",
+			contains:    []string{"%PDF-1.4", "(energy [kWh]: count 2, min 1.5, max 2.5, sum 4, avg 2.000) '", "/Count 1", "%%EOF"},
+		},
+		{
+			desc:        "render PDF report with alarms template",
+			format:      reports.PDF,
+			template:    alarms,
+			contentType: "application/pdf",
+			contains:    []string{"(2) '"},
+		},
+		{
+			desc:        "render multi-page PDF report",
+			format:      reports.PDF,
+			template:    many,
+			contentType: "application/pdf",
+			contains:    []string{"/Count 4"},
+		},
+	}
+
+	for _, tc := range cases {
+		r := reports.Report{Name: "daily", Format: tc.format, Template: tc.template}
+		doc, err := reports.Render(r, from, to, msgs)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		assert.Equal(t, tc.contentType, doc.ContentType, fmt.Sprintf("%s: expected content type %s got %s", tc.desc, tc.contentType, doc.ContentType))
+		assert.Equal(t, fmt.Sprintf("daily-20190501T0000.%s", tc.format), doc.Name, fmt.Sprintf("%s: unexpected document name %s", tc.desc, doc.Name))
+		for _, c := range tc.contains {
+			assert.True(t, bytes.Contains(doc.Content, []byte(c)), fmt.Sprintf("%s: expected content to contain %q", tc.desc, c))
+		}
+	}
+}
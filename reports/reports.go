@@ -0,0 +1,138 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package reports
+
+import (
+	"net/url"
+	"text/template"
+	"time"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/mainflux/mainflux"
+)
+
+// Supported report formats.
+const (
+	CSV = "csv"
+	PDF = "pdf"
+)
+
+// Supported report delivery types.
+const (
+	Email   = "email"
+	Webhook = "webhook"
+)
+
+// MinInterval is the shortest allowed period covered by the report.
+const MinInterval = time.Hour
+
+// Report represents the periodic report on the messages published to the
+// channel, rendered using the template and delivered to the target address
+// or URL. Messages are read using the key of the thing connected to the
+// channel.
+type Report struct {
+	ID        string
+	Owner     string
+	Name      string
+	ChannelID string
+	ThingKey  string
+	Format    string
+	Template  string
+	Interval  time.Duration
+	Delivery  string
+	Target    string
+	NextRun   time.Time
+}
+
+// Validate returns an error if report representation is invalid.
+func (r Report) Validate() error {
+	if r.Name == "" || r.ChannelID == "" || r.ThingKey == "" {
+		return ErrMalformedEntity
+	}
+
+	if r.Format != CSV && r.Format != PDF {
+		return ErrMalformedEntity
+	}
+
+	if r.Interval < MinInterval {
+		return ErrMalformedEntity
+	}
+
+	switch r.Delivery {
+	case Email:
+		if !govalidator.IsEmail(r.Target) {
+			return ErrMalformedEntity
+		}
+	case Webhook:
+		u, err := url.Parse(r.Target)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return ErrMalformedEntity
+		}
+	default:
+		return ErrMalformedEntity
+	}
+
+	if _, err := template.New(r.Name).Parse(r.Template); err != nil {
+		return ErrMalformedEntity
+	}
+
+	return nil
+}
+
+// Document represents the rendered report.
+type Document struct {
+	Name        string
+	ContentType string
+	Content     []byte
+}
+
+// ReportRepository specifies a report persistence API.
+type ReportRepository interface {
+	// Save persists the report. Successful operation is indicated by unique
+	// identifier accompanied by nil error response. A non-nil error is
+	// returned to indicate operation failure.
+	Save(Report) (string, error)
+
+	// RetrieveByID retrieves the report having the provided identifier,
+	// that is owned by the specified user.
+	RetrieveByID(string, string) (Report, error)
+
+	// RetrieveAll retrieves all the reports owned by the specified user.
+	RetrieveAll(string) ([]Report, error)
+
+	// RetrieveDue retrieves all the reports whose next run is not after the
+	// provided time.
+	RetrieveDue(time.Time) ([]Report, error)
+
+	// UpdateNextRun sets the next run of the report having the provided
+	// identifier.
+	UpdateNextRun(string, time.Time) error
+
+	// Remove removes the report having the provided identifier, that is
+	// owned by the specified user.
+	Remove(string, string) error
+}
+
+// MessageReader specifies an API for reading the stored channel messages.
+type MessageReader interface {
+	// ReadMessages retrieves the messages published to the channel within
+	// the provided time range, using the key of the connected thing.
+	ReadMessages(string, string, time.Time, time.Time) ([]mainflux.Message, error)
+}
+
+// Sender specifies an API for the delivery of reports.
+type Sender interface {
+	// Send delivers the rendered report to the target.
+	Send(string, Report, Document) error
+}
+
+// IdentityProvider specifies an API for generating unique identifiers.
+type IdentityProvider interface {
+	// ID generates the unique identifier.
+	ID() (string, error)
+}
@@ -0,0 +1,232 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package reports
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/mainflux/mainflux"
+)
+
+var (
+	// ErrMalformedEntity indicates malformed entity specification (e.g.
+	// invalid report format or template).
+	ErrMalformedEntity = errors.New("malformed entity specification")
+
+	// ErrUnauthorizedAccess indicates missing or invalid credentials provided
+	// when accessing a protected resource.
+	ErrUnauthorizedAccess = errors.New("missing or invalid credentials provided")
+
+	// ErrNotFound indicates a non-existent entity request.
+	ErrNotFound = errors.New("non-existent entity")
+
+	// ErrUnsupportedDelivery indicates the report delivery type that has no
+	// configured sender.
+	ErrUnsupportedDelivery = errors.New("unsupported report delivery type")
+)
+
+// Service specifies an API that must be fullfiled by the domain service
+// implementation, and all of its decorators (e.g. logging & metrics).
+type Service interface {
+	// CreateReport schedules the report for the user identified by the
+	// provided key. The first report is delivered after the report
+	// interval elapses.
+	CreateReport(string, Report) (Report, error)
+
+	// ViewReport retrieves data about the report identified with the
+	// provided ID, that belongs to the user identified by the provided key.
+	ViewReport(string, string) (Report, error)
+
+	// ListReports retrieves all the reports that belong to the user
+	// identified by the provided key.
+	ListReports(string) ([]Report, error)
+
+	// RemoveReport removes the report identified with the provided ID, that
+	// belongs to the user identified by the provided key.
+	RemoveReport(string, string) error
+
+	// GenerateReport renders the report identified with the provided ID,
+	// covering the last report interval, without delivering it.
+	GenerateReport(string, string) (Document, error)
+
+	// SendDue renders and delivers all the reports that are due at the
+	// provided time and schedules their next runs. Delivery of the
+	// remaining reports is attempted even if some of them fail, in which
+	// case the last error is returned.
+	SendDue(time.Time) error
+}
+
+var _ Service = (*reportsService)(nil)
+
+type reportsService struct {
+	users    mainflux.UsersServiceClient
+	things   mainflux.ThingsServiceClient
+	reports  ReportRepository
+	messages MessageReader
+	idp      IdentityProvider
+	senders  map[string]Sender
+}
+
+// New instantiates the reports service implementation. Reports are
+// delivered using the sender registered for the report delivery type.
+func New(users mainflux.UsersServiceClient, things mainflux.ThingsServiceClient, reports ReportRepository, messages MessageReader, idp IdentityProvider, senders map[string]Sender) Service {
+	return &reportsService{
+		users:    users,
+		things:   things,
+		reports:  reports,
+		messages: messages,
+		idp:      idp,
+		senders:  senders,
+	}
+}
+
+func (rs *reportsService) CreateReport(key string, r Report) (Report, error) {
+	if err := r.Validate(); err != nil {
+		return Report{}, err
+	}
+
+	owner, err := rs.identify(key)
+	if err != nil {
+		return Report{}, err
+	}
+
+	if _, ok := rs.senders[r.Delivery]; !ok {
+		return Report{}, ErrUnsupportedDelivery
+	}
+
+	// The thing key has to grant the access to the reported channel,
+	// otherwise the reader would reject every run.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if _, err := rs.things.CanAccess(ctx, &mainflux.AccessReq{Token: r.ThingKey, ChanID: r.ChannelID, Action: mainflux.ActionSubscribe}); err != nil {
+		return Report{}, ErrMalformedEntity
+	}
+
+	r.ID, err = rs.idp.ID()
+	if err != nil {
+		return Report{}, err
+	}
+	r.Owner = owner
+	r.NextRun = time.Now().Add(r.Interval).UTC()
+
+	id, err := rs.reports.Save(r)
+	if err != nil {
+		return Report{}, err
+	}
+
+	r.ID = id
+	return r, nil
+}
+
+func (rs *reportsService) ViewReport(key, id string) (Report, error) {
+	owner, err := rs.identify(key)
+	if err != nil {
+		return Report{}, err
+	}
+
+	return rs.reports.RetrieveByID(owner, id)
+}
+
+func (rs *reportsService) ListReports(key string) ([]Report, error) {
+	owner, err := rs.identify(key)
+	if err != nil {
+		return []Report{}, err
+	}
+
+	return rs.reports.RetrieveAll(owner)
+}
+
+func (rs *reportsService) RemoveReport(key, id string) error {
+	owner, err := rs.identify(key)
+	if err != nil {
+		return err
+	}
+
+	return rs.reports.Remove(owner, id)
+}
+
+func (rs *reportsService) GenerateReport(key, id string) (Document, error) {
+	owner, err := rs.identify(key)
+	if err != nil {
+		return Document{}, err
+	}
+
+	r, err := rs.reports.RetrieveByID(owner, id)
+	if err != nil {
+		return Document{}, err
+	}
+
+	to := time.Now()
+	return rs.render(r, to.Add(-r.Interval), to)
+}
+
+func (rs *reportsService) SendDue(now time.Time) error {
+	due, err := rs.reports.RetrieveDue(now)
+	if err != nil {
+		return err
+	}
+
+	var lastErr error
+	for _, r := range due {
+		// Runs missed while the service was down are skipped, instead of
+		// being delivered all at once.
+		next := r.NextRun.Add(r.Interval)
+		for !next.After(now) {
+			next = next.Add(r.Interval)
+		}
+
+		if err := rs.reports.UpdateNextRun(r.ID, next); err != nil {
+			lastErr = err
+			continue
+		}
+
+		if err := rs.send(r); err != nil {
+			lastErr = err
+		}
+	}
+
+	return lastErr
+}
+
+func (rs *reportsService) send(r Report) error {
+	sender, ok := rs.senders[r.Delivery]
+	if !ok {
+		return ErrUnsupportedDelivery
+	}
+
+	doc, err := rs.render(r, r.NextRun.Add(-r.Interval), r.NextRun)
+	if err != nil {
+		return err
+	}
+
+	return sender.Send(r.Target, r, doc)
+}
+
+func (rs *reportsService) render(r Report, from, to time.Time) (Document, error) {
+	msgs, err := rs.messages.ReadMessages(r.ChannelID, r.ThingKey, from, to)
+	if err != nil {
+		return Document{}, err
+	}
+
+	return Render(r, from, to, msgs)
+}
+
+func (rs *reportsService) identify(key string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	res, err := rs.users.Identify(ctx, &mainflux.Token{Value: key})
+	if err != nil {
+		return "", ErrUnauthorizedAccess
+	}
+
+	return res.GetValue(), nil
+}
@@ -0,0 +1,241 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package reports_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/reports"
+	"github.com/mainflux/mainflux/reports/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	token    = "token"
+	wrong    = "wrong"
+	email    = "user@example.com"
+	chanID   = "1"
+	thingKey = "key"
+	target   = "reports@example.com"
+	failing  = "failing@example.com"
+)
+
+var report = reports.Report{
+	Name:      "consumption",
+	ChannelID: chanID,
+	ThingKey:  thingKey,
+	Format:    reports.CSV,
+	Interval:  24 * time.Hour,
+	Delivery:  reports.Email,
+	Target:    target,
+}
+
+func newService(sender reports.Sender, msgs []mainflux.Message) reports.Service {
+	users := mocks.NewUsersService(map[string]string{token: email})
+	things := mocks.NewThingsService(map[string]string{thingKey: chanID})
+	repo := mocks.NewReportRepository()
+	reader := mocks.NewMessageReader(map[string][]mainflux.Message{chanID: msgs})
+	idp := mocks.NewIdentityProvider()
+	senders := map[string]reports.Sender{reports.Email: sender}
+
+	return reports.New(users, things, repo, reader, idp, senders)
+}
+
+func TestCreateReport(t *testing.T) {
+	svc := newService(mocks.NewSender(nil), nil)
+
+	invalidFormat := report
+	invalidFormat.Format = "xls"
+
+	shortInterval := report
+	shortInterval.Interval = time.Minute
+
+	invalidTemplate := report
+	invalidTemplate.Template = "{{.Name"
+
+	invalidKey := report
+	invalidKey.ThingKey = wrong
+
+	webhook := report
+	webhook.Delivery = reports.Webhook
+	webhook.Target = "http://example.com/reports"
+
+	cases := []struct {
+		desc   string
+		token  string
+		report reports.Report
+		err    error
+	}{
+		{"create valid report", token, report, nil},
+		{"create report with invalid token", wrong, report, reports.ErrUnauthorizedAccess},
+		{"create report with invalid format", token, invalidFormat, reports.ErrMalformedEntity},
+		{"create report with too short interval", token, shortInterval, reports.ErrMalformedEntity},
+		{"create report with invalid template", token, invalidTemplate, reports.ErrMalformedEntity},
+		{"create report with thing key not granting channel access", token, invalidKey, reports.ErrMalformedEntity},
+		{"create report with unsupported delivery", token, webhook, reports.ErrUnsupportedDelivery},
+	}
+
+	for _, tc := range cases {
+		r, err := svc.CreateReport(tc.token, tc.report)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+		if err == nil {
+			assert.Equal(t, email, r.Owner, fmt.Sprintf("%s: expected owner %s got %s", tc.desc, email, r.Owner))
+			assert.True(t, r.NextRun.After(time.Now()), fmt.Sprintf("%s: expected next run in the future", tc.desc))
+		}
+	}
+}
+
+func TestViewReport(t *testing.T) {
+	svc := newService(mocks.NewSender(nil), nil)
+	saved, err := svc.CreateReport(token, report)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		token string
+		id    string
+		err   error
+	}{
+		{"view existing report", token, saved.ID, nil},
+		{"view report with invalid token", wrong, saved.ID, reports.ErrUnauthorizedAccess},
+		{"view non-existing report", token, wrong, reports.ErrNotFound},
+	}
+
+	for _, tc := range cases {
+		_, err := svc.ViewReport(tc.token, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+	}
+}
+
+func TestListReports(t *testing.T) {
+	svc := newService(mocks.NewSender(nil), nil)
+
+	n := 3
+	for i := 0; i < n; i++ {
+		_, err := svc.CreateReport(token, report)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	cases := []struct {
+		desc  string
+		token string
+		size  int
+		err   error
+	}{
+		{"list reports", token, n, nil},
+		{"list reports with invalid token", wrong, 0, reports.ErrUnauthorizedAccess},
+	}
+
+	for _, tc := range cases {
+		rs, err := svc.ListReports(tc.token)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+		assert.Len(t, rs, tc.size, fmt.Sprintf("%s: expected %d reports got %d", tc.desc, tc.size, len(rs)))
+	}
+}
+
+func TestRemoveReport(t *testing.T) {
+	svc := newService(mocks.NewSender(nil), nil)
+	saved, err := svc.CreateReport(token, report)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		token string
+		id    string
+		err   error
+	}{
+		{"remove report with invalid token", wrong, saved.ID, reports.ErrUnauthorizedAccess},
+		{"remove existing report", token, saved.ID, nil},
+		{"remove removed report", token, saved.ID, nil},
+	}
+
+	for _, tc := range cases {
+		err := svc.RemoveReport(tc.token, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+	}
+
+	_, err = svc.ViewReport(token, saved.ID)
+	assert.Equal(t, reports.ErrNotFound, err, fmt.Sprintf("expected %s got %s", reports.ErrNotFound, err))
+}
+
+func TestGenerateReport(t *testing.T) {
+	now := float64(time.Now().Unix())
+	msgs := []mainflux.Message{
+		{Channel: chanID, Publisher: "1", Name: "energy", Unit: "kWh", Time: now - 60, Value: &mainflux.Message_FloatValue{FloatValue: 1.5}},
+		{Channel: chanID, Publisher: "1", Name: "energy", Unit: "kWh", Time: now - 30, Value: &mainflux.Message_FloatValue{FloatValue: 2.5}},
+		{Channel: chanID, Publisher: "1", Name: "energy", Unit: "kWh", Time: now - 48*3600, Value: &mainflux.Message_FloatValue{FloatValue: 10}},
+	}
+	svc := newService(mocks.NewSender(nil), msgs)
+	saved, err := svc.CreateReport(token, report)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		token string
+		id    string
+		lines int
+		err   error
+	}{
+		{"generate existing report", token, saved.ID, 3, nil},
+		{"generate report with invalid token", wrong, saved.ID, 0, reports.ErrUnauthorizedAccess},
+		{"generate non-existing report", token, wrong, 0, reports.ErrNotFound},
+	}
+
+	for _, tc := range cases {
+		doc, err := svc.GenerateReport(tc.token, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+		if err != nil {
+			continue
+		}
+
+		lines := countLines(doc.Content)
+		assert.Equal(t, tc.lines, lines, fmt.Sprintf("%s: expected %d lines got %d", tc.desc, tc.lines, lines))
+	}
+}
+
+func TestSendDue(t *testing.T) {
+	sender := mocks.NewSender(map[string]error{failing: errors.New("delivery failed")})
+	svc := newService(sender, nil)
+
+	failingReport := report
+	failingReport.Target = failing
+
+	saved, err := svc.CreateReport(token, report)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	_, err = svc.CreateReport(token, failingReport)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	err = svc.SendDue(time.Now())
+	assert.Nil(t, err, fmt.Sprintf("sending reports before they are due: unexpected error: %s", err))
+	assert.Len(t, sender.Sent(target), 0, "report sent before it's due")
+
+	// Several missed runs result in a single delivery.
+	err = svc.SendDue(saved.NextRun.Add(3 * report.Interval))
+	assert.NotNil(t, err, "sending reports: expected error from the failing report")
+	assert.Len(t, sender.Sent(target), 1, "due report not sent")
+
+	r, err := svc.ViewReport(token, saved.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	expected := saved.NextRun.Add(4 * report.Interval)
+	assert.True(t, r.NextRun.Equal(expected), fmt.Sprintf("expected next run %s got %s", expected, r.NextRun))
+}
+
+func countLines(data []byte) int {
+	n := 0
+	for _, b := range data {
+		if b == '\n' {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,110 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// Package smtp contains the report sender that delivers the reports by
+// email, as attachments.
+package smtp
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"mime/multipart"
+	"net"
+	"net/smtp"
+	"net/textproto"
+
+	"github.com/mainflux/mainflux/reports"
+)
+
+// lineLength is the maximum length of the base64 encoded attachment line.
+const lineLength = 76
+
+var _ reports.Sender = (*sender)(nil)
+
+// Config defines the options that are used when connecting to the SMTP
+// server.
+type Config struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+type sender struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+// New instantiates the email report sender. Plain authentication is used
+// if the username is provided.
+func New(cfg Config) reports.Sender {
+	var auth smtp.Auth
+	if cfg.Username != "" {
+		auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+
+	return &sender{
+		addr: net.JoinHostPort(cfg.Host, cfg.Port),
+		auth: auth,
+		from: cfg.From,
+	}
+}
+
+func (s *sender) Send(target string, r reports.Report, doc reports.Document) error {
+	msg, err := s.message(target, r, doc)
+	if err != nil {
+		return err
+	}
+
+	return smtp.SendMail(s.addr, s.auth, s.from, []string{target}, msg)
+}
+
+func (s *sender) message(to string, r reports.Report, doc reports.Document) ([]byte, error) {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+
+	text, err := w.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {"text/plain; charset=UTF-8"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(text, "Report %s is attached.\r\n", r.Name)
+
+	att, err := w.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {doc.ContentType},
+		"Content-Transfer-Encoding": {"base64"},
+		"Content-Disposition":       {fmt.Sprintf("attachment; filename=%q", doc.Name)},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(doc.Content)
+	for len(encoded) > lineLength {
+		fmt.Fprintf(att, "%s\r\n", encoded[:lineLength])
+		encoded = encoded[lineLength:]
+	}
+	fmt.Fprintf(att, "%s\r\n", encoded)
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", s.from)
+	fmt.Fprintf(&buf, "To: %s\r\n", to)
+	fmt.Fprintf(&buf, "Subject: Mainflux report: %s\r\n", r.Name)
+	fmt.Fprint(&buf, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/mixed; boundary=%s\r\n\r\n", w.Boundary())
+	buf.Write(body.Bytes())
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,61 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// Package webhook contains the report sender that delivers the reports by
+// posting them to the HTTP endpoints.
+package webhook
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/mainflux/mainflux/reports"
+)
+
+// ErrDeliveryFailed indicates that the webhook responded with non-2xx
+// status.
+var ErrDeliveryFailed = errors.New("webhook responded with unexpected status")
+
+var _ reports.Sender = (*sender)(nil)
+
+type sender struct {
+	client *http.Client
+}
+
+// New instantiates the webhook report sender. The report is sent as the
+// request body, while the report ID is sent as the X-Report-Id header.
+func New(timeout time.Duration) reports.Sender {
+	return &sender{
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (s *sender) Send(target string, r reports.Report, doc reports.Document) error {
+	req, err := http.NewRequest(http.MethodPost, target, bytes.NewReader(doc.Content))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", doc.ContentType)
+	req.Header.Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", doc.Name))
+	req.Header.Set("X-Report-Id", r.ID)
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return ErrDeliveryFailed
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package webhook_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/reports"
+	"github.com/mainflux/mainflux/reports/webhook"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSend(t *testing.T) {
+	var received *http.Request
+	var body []byte
+	ok := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = ioutil.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ok.Close()
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	report := reports.Report{ID: "1", Name: "daily"}
+	doc := reports.Document{
+		Name:        "daily-20190501T0000.csv",
+		ContentType: "text/csv",
+		Content:     []byte("energy,4\n"),
+	}
+
+	cases := []struct {
+		desc   string
+		target string
+		err    error
+	}{
+		{
+			desc:   "send report to available webhook",
+			target: ok.URL,
+			err:    nil,
+		},
+		{
+			desc:   "send report to failing webhook",
+			target: failing.URL,
+			err:    webhook.ErrDeliveryFailed,
+		},
+	}
+
+	sender := webhook.New(time.Second)
+	for _, tc := range cases {
+		err := sender.Send(tc.target, report, doc)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	assert.Equal(t, doc.Content, body, fmt.Sprintf("expected body %s got %s", doc.Content, body))
+	assert.Equal(t, doc.ContentType, received.Header.Get("Content-Type"), "unexpected content type")
+	assert.Equal(t, report.ID, received.Header.Get("X-Report-Id"), "unexpected report ID")
+}
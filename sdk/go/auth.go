@@ -0,0 +1,148 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package sdk
+
+import (
+	"net/http"
+	"sync"
+)
+
+// authenticator keeps the credentials used to obtain user tokens, so that an
+// expired token can be replaced by a fresh one without caller involvement.
+type authenticator struct {
+	mu     sync.Mutex
+	user   *User
+	any    bool
+	token  string
+	issued map[string]bool
+	stale  map[string]bool
+}
+
+func newAuthenticator() *authenticator {
+	return &authenticator{
+		issued: make(map[string]bool),
+		stale:  make(map[string]bool),
+	}
+}
+
+// store records the credentials along with the token issued for them.
+func (a *authenticator) store(user User, token string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.user == nil || *a.user != user {
+		a.issued = make(map[string]bool)
+		a.stale = make(map[string]bool)
+		a.any = false
+	}
+
+	a.user = &user
+	a.token = token
+	a.issued[token] = true
+}
+
+// setCredentials makes any token rejected by the services subject to refresh.
+func (a *authenticator) setCredentials(user User) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.user = &user
+	a.any = true
+}
+
+// current returns the token that superseded the given one, if any.
+func (a *authenticator) current(token string) string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.stale[token] {
+		return a.token
+	}
+
+	return token
+}
+
+func (a *authenticator) refreshable(token string) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.user != nil && (a.any || a.issued[token])
+}
+
+// refresh obtains a new token in place of the rejected one. Concurrent
+// callers holding the same rejected token share a single re-authentication.
+func (a *authenticator) refresh(token string, create func(User) (string, error)) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.stale[token] {
+		return a.token, nil
+	}
+
+	fresh, err := create(*a.user)
+	if err != nil {
+		return "", err
+	}
+
+	a.stale[token] = true
+	a.issued[fresh] = true
+	a.token = fresh
+
+	return fresh, nil
+}
+
+func (sdk mfSDK) SetCredentials(user User) {
+	sdk.auth.setCredentials(user)
+}
+
+func (sdk mfSDK) sendRequest(req *http.Request, token, contentType string) (*http.Response, error) {
+	token = sdk.auth.current(token)
+
+	resp, err := sdk.doRequest(req, token, contentType)
+	if err != nil || !rejected(resp.StatusCode) || !sdk.auth.refreshable(token) {
+		return resp, err
+	}
+
+	if req.Body != nil {
+		if req.GetBody == nil {
+			return resp, nil
+		}
+
+		body, err := req.GetBody()
+		if err != nil {
+			return resp, nil
+		}
+		req.Body = body
+	}
+
+	fresh, err := sdk.auth.refresh(token, sdk.requestToken)
+	if err != nil {
+		return resp, nil
+	}
+	resp.Body.Close()
+
+	return sdk.doRequest(req, fresh, contentType)
+}
+
+func (sdk mfSDK) doRequest(req *http.Request, token, contentType string) (*http.Response, error) {
+	if token != "" {
+		req.Header.Set("Authorization", token)
+	}
+
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	return sdk.client.Do(req)
+}
+
+// rejected reports whether the services refused the request token. Services
+// respond to invalid and expired tokens with 403, hence both codes apply.
+func rejected(status int) bool {
+	return status == http.StatusUnauthorized || status == http.StatusForbidden
+}
@@ -0,0 +1,138 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package sdk_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	sdk "github.com/mainflux/mainflux/sdk/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// authServer issues sequential tokens and accepts only the latest one,
+// until it is expired.
+type authServer struct {
+	mu     sync.Mutex
+	issued int
+	valid  string
+}
+
+func (as *authServer) expire() {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+
+	as.valid = ""
+}
+
+func (as *authServer) count() int {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+
+	return as.issued
+}
+
+func (as *authServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+
+	if r.URL.Path == "/tokens" {
+		as.issued++
+		as.valid = fmt.Sprintf("token-%d", as.issued)
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]string{"token": as.valid})
+		return
+	}
+
+	if as.valid == "" || r.Header.Get("Authorization") != as.valid {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	body, _ := ioutil.ReadAll(r.Body)
+	if len(body) == 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Location", "/things/1")
+	w.WriteHeader(http.StatusCreated)
+}
+
+func TestTokenRefresh(t *testing.T) {
+	as := &authServer{}
+	ts := httptest.NewServer(as)
+	defer ts.Close()
+
+	mainfluxSDK := sdk.NewSDK(sdk.Config{BaseURL: ts.URL})
+	user := sdk.User{Email: email, Password: "password"}
+
+	tkn, err := mainfluxSDK.CreateToken(user)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		token  string
+		expire bool
+		creds  bool
+		issued int
+		err    error
+	}{
+		{
+			desc:   "create thing with valid token",
+			token:  tkn,
+			issued: 1,
+			err:    nil,
+		},
+		{
+			desc:   "create thing with expired token",
+			token:  tkn,
+			expire: true,
+			issued: 2,
+			err:    nil,
+		},
+		{
+			desc:   "create thing with superseded token",
+			token:  tkn,
+			issued: 2,
+			err:    nil,
+		},
+		{
+			desc:   "create thing with token not issued by sdk",
+			token:  wrongValue,
+			issued: 2,
+			err:    sdk.ErrUnauthorized,
+		},
+		{
+			desc:   "create thing with token not issued by sdk and credentials set",
+			token:  wrongValue,
+			creds:  true,
+			issued: 3,
+			err:    nil,
+		},
+	}
+
+	for _, tc := range cases {
+		if tc.expire {
+			as.expire()
+		}
+		if tc.creds {
+			mainfluxSDK.SetCredentials(user)
+		}
+
+		_, err := mainfluxSDK.CreateThing(thing, tc.token)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected error %s got %s", tc.desc, tc.err, err))
+		assert.Equal(t, tc.issued, as.count(), fmt.Sprintf("%s: expected %d issued tokens got %d", tc.desc, tc.issued, as.count()))
+	}
+}
@@ -41,6 +41,8 @@ func (sdk mfSDK) CreateChannel(channel Channel, token string) (string, error) {
 			return "", ErrInvalidArgs
 		case http.StatusForbidden:
 			return "", ErrUnauthorized
+		case http.StatusUnprocessableEntity:
+			return "", ErrConflict
 		default:
 			return "", ErrFailedCreation
 		}
@@ -204,6 +206,8 @@ func (sdk mfSDK) UpdateChannel(channel Channel, token string) error {
 			return ErrUnauthorized
 		case http.StatusNotFound:
 			return ErrNotFound
+		case http.StatusUnprocessableEntity:
+			return ErrConflict
 		default:
 			return ErrFailedUpdate
 		}
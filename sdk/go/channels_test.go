@@ -9,7 +9,6 @@ package sdk_test
 
 import (
 	"fmt"
-	"strconv"
 	"testing"
 
 	sdk "github.com/mainflux/mainflux/sdk/go"
@@ -18,7 +17,7 @@ import (
 )
 
 var (
-	channel      = sdk.Channel{ID: "1", Name: "test"}
+	channel      = sdk.Channel{ID: mockID(1), Name: "test"}
 	emptyChannel = sdk.Channel{}
 )
 
@@ -115,7 +114,7 @@ func TestChannel(t *testing.T) {
 		},
 		{
 			desc:     "get non-existent channel",
-			chanID:   "43",
+			chanID:   wrongID,
 			token:    token,
 			err:      sdk.ErrNotFound,
 			response: sdk.Channel{},
@@ -152,7 +151,7 @@ func TestChannels(t *testing.T) {
 	var channels []sdk.Channel
 	mainfluxSDK := sdk.NewSDK(sdkConf)
 	for i := 1; i < 101; i++ {
-		ch := sdk.Channel{ID: strconv.Itoa(i), Name: "test"}
+		ch := sdk.Channel{ID: mockID(i), Name: "test"}
 		mainfluxSDK.CreateChannel(ch, token)
 		channels = append(channels, ch)
 	}
@@ -250,7 +249,7 @@ func TestChannelsByThing(t *testing.T) {
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	for i := 1; i < 101; i++ {
-		ch := sdk.Channel{ID: strconv.Itoa(i), Name: "test"}
+		ch := sdk.Channel{ID: mockID(i), Name: "test"}
 		cid, err := mainfluxSDK.CreateChannel(ch, token)
 		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 		err = mainfluxSDK.ConnectThing(tid, cid, token)
@@ -369,7 +368,7 @@ func TestUpdateChannel(t *testing.T) {
 		},
 		{
 			desc:    "update non-existing channel",
-			channel: sdk.Channel{ID: "0", Name: "test2"},
+			channel: sdk.Channel{ID: wrongID, Name: "test2"},
 			token:   token,
 			err:     sdk.ErrNotFound,
 		},
@@ -430,7 +429,7 @@ func TestDeleteChannel(t *testing.T) {
 		},
 		{
 			desc:   "delete non-existing channel",
-			chanID: "2",
+			chanID: wrongID,
 			token:  token,
 			err:    nil,
 		},
@@ -20,18 +20,18 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-func newMessageService() mainflux.MessagePublisher {
-	pub := mocks.NewPublisher()
-	return adapter.New(pub)
+func newMessageService() adapter.Service {
+	pubsub := mocks.NewPubSub()
+	return adapter.New(pubsub, pubsub)
 }
 
-func newMessageServer(pub mainflux.MessagePublisher, cc mainflux.ThingsServiceClient) *httptest.Server {
-	mux := api.MakeHandler(pub, cc)
+func newMessageServer(svc adapter.Service, cc mainflux.ThingsServiceClient) *httptest.Server {
+	mux := api.MakeHandler(svc, nil, cc, 1024)
 	return httptest.NewServer(mux)
 }
 
 func TestSendMessage(t *testing.T) {
-	chanID := "1"
+	chanID := mockID(1)
 	atoken := "auth_token"
 	invalidToken := "invalid_token"
 	msg := `[{"n":"current","t":-1,"v":1.6}]`
@@ -100,7 +100,7 @@ func TestSendMessage(t *testing.T) {
 }
 
 func TestSetContentType(t *testing.T) {
-	chanID := "1"
+	chanID := mockID(1)
 	atoken := "auth_token"
 	thingsClient := mocks.NewThingsClient(map[string]string{atoken: chanID})
 
@@ -83,10 +83,14 @@ type User struct {
 
 // Thing represents mainflux thing.
 type Thing struct {
-	ID       string                 `json:"id,omitempty"`
-	Name     string                 `json:"name,omitempty"`
-	Key      string                 `json:"key,omitempty"`
-	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	ID         string                 `json:"id,omitempty"`
+	ExternalID string                 `json:"external_id,omitempty"`
+	Name       string                 `json:"name,omitempty"`
+	Key        string                 `json:"key,omitempty"`
+	Tags       []string               `json:"tags,omitempty"`
+	Metadata   map[string]interface{} `json:"metadata,omitempty"`
+	LastSeen   int64                  `json:"last_seen,omitempty"`
+	ProfileID  string                 `json:"profile_id,omitempty"`
 }
 
 // ThingsPage contains list of things in a page with proper metadata.
@@ -95,12 +99,14 @@ type ThingsPage struct {
 	Total  uint64  `json:"total"`
 	Offset uint64  `json:"offset"`
 	Limit  uint64  `json:"limit"`
+	Cursor string  `json:"cursor,omitempty"`
 }
 
 // Channel represents mainflux channel.
 type Channel struct {
 	ID       string                 `json:"id,omitempty"`
 	Name     string                 `json:"name"`
+	Tags     []string               `json:"tags,omitempty"`
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
 }
 
@@ -110,6 +116,36 @@ type ChannelsPage struct {
 	Total    uint64    `json:"total"`
 	Offset   uint64    `json:"offset"`
 	Limit    uint64    `json:"limit"`
+	Cursor   string    `json:"cursor,omitempty"`
+}
+
+// Connections contains the channels and the things connected or
+// disconnected in bulk, each of the channels to each of the things. Access
+// restricts the connected things to either publishing or subscribing, and
+// is ignored when disconnecting.
+type Connections struct {
+	ChanIDs  []string `json:"channels"`
+	ThingIDs []string `json:"things"`
+	Access   string   `json:"access,omitempty"`
+}
+
+// Capabilities contains the operations the user may perform on the thing or
+// the channel, or on their account if neither is set.
+type Capabilities struct {
+	Kind       string   `json:"kind,omitempty"`
+	Entity     string   `json:"entity,omitempty"`
+	Operations []string `json:"operations"`
+}
+
+// Allows returns true if the operation is among the capabilities.
+func (c Capabilities) Allows(op string) bool {
+	for _, o := range c.Operations {
+		if o == op {
+			return true
+		}
+	}
+
+	return false
 }
 
 // MessagesPage contains list of messages in a page with proper metadata.
@@ -125,10 +161,17 @@ type SDK interface {
 	// CreateUser registers mainflux user.
 	CreateUser(user User) error
 
-	// CreateToken receives credentials and returns user token.
+	// CreateToken receives credentials and returns user token. The
+	// credentials are kept, so that the returned token is transparently
+	// renewed once the services reject it.
 	CreateToken(user User) (string, error)
 
-	// CreateThing registers new thing and returns its id.
+	// SetCredentials sets credentials used to renew any token rejected by
+	// the services, e.g. the token passed to a long running script.
+	SetCredentials(user User)
+
+	// CreateThing registers new thing and returns its id. If the thing of
+	// the same external ID already exists, its id is returned instead.
 	CreateThing(thing Thing, token string) (string, error)
 
 	// Things returns page of things.
@@ -153,6 +196,19 @@ type SDK interface {
 	// DisconnectThing disconnect thing from specified channel by id.
 	DisconnectThing(thingID, chanID, token string) error
 
+	// Connect connects each of the things to each of the channels. Either
+	// all of the pairs are connected or none of them.
+	Connect(conns Connections, token string) error
+
+	// Disconnect disconnects each of the things from each of the channels.
+	// Either all of the pairs are disconnected or none of them.
+	Disconnect(conns Connections, token string) error
+
+	// Capabilities returns the operations the user may perform on the
+	// entity of the given kind, i.e. "thing" or "channel", having the given
+	// id, or on their account if both are empty.
+	Capabilities(kind, id, token string) (Capabilities, error)
+
 	// CreateChannel creates new channel and returns its id.
 	CreateChannel(channel Channel, token string) (string, error)
 
@@ -194,6 +250,7 @@ type mfSDK struct {
 	httpAdapterPrefix string
 	msgContentType    ContentType
 	client            *http.Client
+	auth              *authenticator
 }
 
 // Config contains sdk configuration parameters.
@@ -225,21 +282,10 @@ func NewSDK(conf Config) SDK {
 				},
 			},
 		},
+		auth: newAuthenticator(),
 	}
 }
 
-func (sdk mfSDK) sendRequest(req *http.Request, token, contentType string) (*http.Response, error) {
-	if token != "" {
-		req.Header.Set("Authorization", token)
-	}
-
-	if contentType != "" {
-		req.Header.Add("Content-Type", contentType)
-	}
-
-	return sdk.client.Do(req)
-}
-
 func createURL(baseURL, prefix, endpoint string) string {
 	if prefix == "" {
 		return fmt.Sprintf("%s/%s", baseURL, endpoint)
@@ -16,7 +16,11 @@ import (
 	"strings"
 )
 
-const thingsEndpoint = "things"
+const (
+	thingsEndpoint     = "things"
+	connectEndpoint    = "connect"
+	disconnectEndpoint = "disconnect"
+)
 
 func (sdk mfSDK) CreateThing(thing Thing, token string) (string, error) {
 	data, err := json.Marshal(thing)
@@ -37,19 +41,26 @@ func (sdk mfSDK) CreateThing(thing Thing, token string) (string, error) {
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusCreated {
-		switch resp.StatusCode {
-		case http.StatusBadRequest:
-			return "", ErrInvalidArgs
-		case http.StatusForbidden:
-			return "", ErrUnauthorized
-		default:
-			return "", ErrFailedCreation
+	switch resp.StatusCode {
+	case http.StatusCreated:
+		id := strings.TrimPrefix(resp.Header.Get("Location"), fmt.Sprintf("/%s/", thingsEndpoint))
+		return id, nil
+	case http.StatusOK:
+		// The thing of the same external ID already exists.
+		var t Thing
+		if err := json.NewDecoder(resp.Body).Decode(&t); err != nil {
+			return "", err
 		}
+		return t.ID, nil
+	case http.StatusBadRequest:
+		return "", ErrInvalidArgs
+	case http.StatusForbidden:
+		return "", ErrUnauthorized
+	case http.StatusUnprocessableEntity:
+		return "", ErrConflict
+	default:
+		return "", ErrFailedCreation
 	}
-
-	id := strings.TrimPrefix(resp.Header.Get("Location"), fmt.Sprintf("/%s/", thingsEndpoint))
-	return id, nil
 }
 
 func (sdk mfSDK) Things(token string, offset, limit uint64, name string) (ThingsPage, error) {
@@ -206,6 +217,8 @@ func (sdk mfSDK) UpdateThing(thing Thing, token string) error {
 			return ErrUnauthorized
 		case http.StatusNotFound:
 			return ErrNotFound
+		case http.StatusUnprocessableEntity:
+			return ErrConflict
 		default:
 			return ErrFailedUpdate
 		}
@@ -297,3 +310,112 @@ func (sdk mfSDK) DisconnectThing(thingID, chanID, token string) error {
 
 	return nil
 }
+
+func (sdk mfSDK) Connect(conns Connections, token string) error {
+	data, err := json.Marshal(conns)
+	if err != nil {
+		return ErrInvalidArgs
+	}
+
+	url := createURL(sdk.baseURL, sdk.thingsPrefix, connectEndpoint)
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		switch resp.StatusCode {
+		case http.StatusBadRequest:
+			return ErrInvalidArgs
+		case http.StatusForbidden:
+			return ErrUnauthorized
+		case http.StatusNotFound:
+			return ErrNotFound
+		default:
+			return ErrFailedConnection
+		}
+	}
+
+	return nil
+}
+
+func (sdk mfSDK) Disconnect(conns Connections, token string) error {
+	data, err := json.Marshal(conns)
+	if err != nil {
+		return ErrInvalidArgs
+	}
+
+	url := createURL(sdk.baseURL, sdk.thingsPrefix, disconnectEndpoint)
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusNoContent {
+		switch resp.StatusCode {
+		case http.StatusBadRequest:
+			return ErrInvalidArgs
+		case http.StatusForbidden:
+			return ErrUnauthorized
+		case http.StatusNotFound:
+			return ErrNotFound
+		default:
+			return ErrFailedDisconnect
+		}
+	}
+
+	return nil
+}
+
+func (sdk mfSDK) Capabilities(kind, id, token string) (Capabilities, error) {
+	endpoint := fmt.Sprintf("capabilities?kind=%s&entity=%s", kind, id)
+	url := createURL(sdk.baseURL, sdk.thingsPrefix, endpoint)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return Capabilities{}, err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return Capabilities{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return Capabilities{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		switch resp.StatusCode {
+		case http.StatusBadRequest:
+			return Capabilities{}, ErrInvalidArgs
+		case http.StatusForbidden:
+			return Capabilities{}, ErrUnauthorized
+		case http.StatusNotFound:
+			return Capabilities{}, ErrNotFound
+		default:
+			return Capabilities{}, ErrFetchFailed
+		}
+	}
+
+	var c Capabilities
+	if err := json.Unmarshal(body, &c); err != nil {
+		return Capabilities{}, err
+	}
+
+	return c, nil
+}
@@ -10,7 +10,6 @@ package sdk_test
 import (
 	"fmt"
 	"net/http/httptest"
-	"strconv"
 	"testing"
 
 	sdk "github.com/mainflux/mainflux/sdk/go"
@@ -29,6 +28,7 @@ const (
 	token       = "token"
 	otherToken  = "other_token"
 	wrongValue  = "wrong_value"
+	wrongID     = "123e4567-e89b-12d3-a456-999999999999"
 
 	keyPrefix = "123e4567-e89b-12d3-a456-"
 )
@@ -36,20 +36,41 @@ const (
 var (
 	metadata   = map[string]interface{}{"meta": "data"}
 	metadata2  = map[string]interface{}{"meta": "data2"}
-	thing      = sdk.Thing{ID: "1", Name: "test_device", Metadata: metadata}
+	thing      = sdk.Thing{ID: mockID(1), Name: "test_device", Metadata: metadata}
 	emptyThing = sdk.Thing{}
 )
 
+// mockID returns the identifier the mocks generate for the n-th entity.
+func mockID(n int) string {
+	return fmt.Sprintf("%s%012d", keyPrefix, n)
+}
+
 func newThingsService(tokens map[string]string) things.Service {
 	users := mocks.NewUsersService(tokens)
 	conns := make(chan mocks.Connection)
 	thingsRepo := mocks.NewThingRepository(conns)
 	channelsRepo := mocks.NewChannelRepository(thingsRepo, conns)
+	reservationsRepo := mocks.NewReservationRepository()
+	usageRepo := mocks.NewUsageRepository()
+	historyRepo := mocks.NewHistoryRepository()
+	auditRepo := mocks.NewAuditRepository()
 	chanCache := mocks.NewChannelCache()
 	thingCache := mocks.NewThingCache()
 	idp := mocks.NewIdentityProvider()
-
-	return things.New(users, thingsRepo, channelsRepo, chanCache, thingCache, idp)
+	onboarding := mocks.NewOnboardingProvider()
+	keys := mocks.NewKeyProvider()
+	revocations := mocks.NewRevocationRepository()
+	templates := mocks.NewTemplateRepository()
+	groups := mocks.NewGroupRepository(thingsRepo, channelsRepo)
+	subtopics := mocks.NewSubtopicRepository()
+	observations := mocks.NewObservationRepository()
+	thingKeys := mocks.NewThingKeyRepository(thingsRepo, channelsRepo)
+	shares := mocks.NewShareRepository()
+	profiles := mocks.NewProfileRepository()
+	rules := mocks.NewSubtopicRuleRepository()
+	health := mocks.NewHealthRepository()
+
+	return things.New(users, thingsRepo, channelsRepo, reservationsRepo, usageRepo, historyRepo, auditRepo, chanCache, thingCache, idp, onboarding, keys, revocations, templates, groups, subtopics, observations, thingKeys, shares, profiles, rules, health, mocks.NewChannelTokenRepository(channelsRepo), mocks.NewOverrideProvider(nil), things.Limits{}, 0)
 }
 
 func newThingsServer(svc things.Service) *httptest.Server {
@@ -85,14 +106,14 @@ func TestCreateThing(t *testing.T) {
 			thing:    thing,
 			token:    token,
 			err:      nil,
-			location: "1",
+			location: mockID(1),
 		},
 		{
 			desc:     "create new empty thing",
 			thing:    emptyThing,
 			token:    token,
 			err:      nil,
-			location: "2",
+			location: mockID(2),
 		},
 		{
 			desc:     "create new thing with empty token",
@@ -152,7 +173,7 @@ func TestThing(t *testing.T) {
 		},
 		{
 			desc:     "get non-existent thing",
-			thId:     "43",
+			thId:     wrongID,
 			token:    token,
 			err:      sdk.ErrNotFound,
 			response: sdk.Thing{},
@@ -191,7 +212,7 @@ func TestThings(t *testing.T) {
 	mainfluxSDK := sdk.NewSDK(sdkConf)
 	for i := 1; i < 101; i++ {
 
-		th := sdk.Thing{ID: strconv.Itoa(i), Name: "test_device", Metadata: metadata}
+		th := sdk.Thing{ID: mockID(i), Name: "test_device", Metadata: metadata}
 		mainfluxSDK.CreateThing(th, token)
 		th.Key = fmt.Sprintf("%s%012d", keyPrefix, 2*i)
 		things = append(things, th)
@@ -418,7 +439,7 @@ func TestUpdateThing(t *testing.T) {
 		{
 			desc: "update non-existing thing",
 			thing: sdk.Thing{
-				ID:       "0",
+				ID:       wrongID,
 				Name:     "test_device",
 				Metadata: metadata,
 			},
@@ -494,7 +515,7 @@ func TestDeleteThing(t *testing.T) {
 		},
 		{
 			desc:    "delete non-existing thing",
-			thingID: "2",
+			thingID: wrongID,
 			token:   token,
 			err:     nil,
 		},
@@ -575,13 +596,13 @@ func TestConnectThing(t *testing.T) {
 		{
 			desc:    "connect existing thing to non-existing channel",
 			thingID: thingID,
-			chanID:  "9",
+			chanID:  wrongID,
 			token:   token,
 			err:     sdk.ErrNotFound,
 		},
 		{
 			desc:    "connect non-existing thing to existing channel",
-			thingID: "9",
+			thingID: wrongID,
 			chanID:  chanID1,
 			token:   token,
 			err:     sdk.ErrNotFound,
@@ -678,13 +699,13 @@ func TestDisconnectThing(t *testing.T) {
 		{
 			desc:    "disconnect existing thing from non-existing channel",
 			thingID: thingID,
-			chanID:  "9",
+			chanID:  wrongID,
 			token:   token,
 			err:     sdk.ErrNotFound,
 		},
 		{
 			desc:    "disconnect non-existing thing from existing channel",
-			thingID: "9",
+			thingID: wrongID,
 			chanID:  chanID1,
 			token:   token,
 			err:     sdk.ErrNotFound,
@@ -731,3 +752,160 @@ func TestDisconnectThing(t *testing.T) {
 		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
 	}
 }
+
+func TestConnect(t *testing.T) {
+	svc := newThingsService(map[string]string{
+		token:      email,
+		otherToken: otherEmail,
+	})
+
+	ts := newThingsServer(svc)
+	defer ts.Close()
+	sdkConf := sdk.Config{
+		BaseURL:           ts.URL,
+		UsersPrefix:       "",
+		ThingsPrefix:      "",
+		HTTPAdapterPrefix: "",
+		MsgContentType:    contentType,
+		TLSVerification:   false,
+	}
+
+	mainfluxSDK := sdk.NewSDK(sdkConf)
+
+	thingID1, err := mainfluxSDK.CreateThing(sdk.Thing{Name: "test_device_1"}, token)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	thingID2, err := mainfluxSDK.CreateThing(sdk.Thing{Name: "test_device_2"}, token)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	chanID1, err := mainfluxSDK.CreateChannel(channel, token)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	chanID2, err := mainfluxSDK.CreateChannel(channel, otherToken)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		conns sdk.Connections
+		token string
+		err   error
+	}{
+		{
+			desc:  "connect existing things to existing channel",
+			conns: sdk.Connections{ChanIDs: []string{chanID1}, ThingIDs: []string{thingID1, thingID2}},
+			token: token,
+			err:   nil,
+		},
+		{
+			desc:  "connect existing things to channel of other user",
+			conns: sdk.Connections{ChanIDs: []string{chanID2}, ThingIDs: []string{thingID1, thingID2}},
+			token: token,
+			err:   sdk.ErrNotFound,
+		},
+		{
+			desc:  "connect existing thing to existing and non-existing channel",
+			conns: sdk.Connections{ChanIDs: []string{chanID1, wrongID}, ThingIDs: []string{thingID1}},
+			token: token,
+			err:   sdk.ErrNotFound,
+		},
+		{
+			desc:  "connect without things",
+			conns: sdk.Connections{ChanIDs: []string{chanID1}},
+			token: token,
+			err:   sdk.ErrInvalidArgs,
+		},
+		{
+			desc:  "connect existing things to existing channel with invalid token",
+			conns: sdk.Connections{ChanIDs: []string{chanID1}, ThingIDs: []string{thingID1, thingID2}},
+			token: wrongValue,
+			err:   sdk.ErrUnauthorized,
+		},
+	}
+
+	for _, tc := range cases {
+		err := mainfluxSDK.Connect(tc.conns, tc.token)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+	}
+
+	err = mainfluxSDK.Disconnect(sdk.Connections{ChanIDs: []string{chanID1}, ThingIDs: []string{thingID1, thingID2}}, token)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+}
+
+func TestCapabilities(t *testing.T) {
+	svc := newThingsService(map[string]string{token: email, otherToken: otherEmail})
+	ts := newThingsServer(svc)
+	defer ts.Close()
+
+	sdkConf := sdk.Config{
+		BaseURL:           ts.URL,
+		UsersPrefix:       "",
+		ThingsPrefix:      "",
+		HTTPAdapterPrefix: "",
+		MsgContentType:    contentType,
+		TLSVerification:   false,
+	}
+
+	mainfluxSDK := sdk.NewSDK(sdkConf)
+	id, err := mainfluxSDK.CreateThing(sdk.Thing{Name: "owned"}, token)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc     string
+		kind     string
+		id       string
+		token    string
+		err      error
+		response sdk.Capabilities
+	}{
+		{
+			desc:     "get account capabilities",
+			token:    token,
+			err:      nil,
+			response: sdk.Capabilities{Operations: []string{"create", "view"}},
+		},
+		{
+			desc:  "get capabilities of owned thing",
+			kind:  "thing",
+			id:    id,
+			token: token,
+			err:   nil,
+			response: sdk.Capabilities{
+				Kind:       "thing",
+				Entity:     id,
+				Operations: []string{"view", "update", "connect", "disconnect", "remove", "share", "transfer", "update_key"},
+			},
+		},
+		{
+			desc:     "get capabilities of thing owned by other user",
+			kind:     "thing",
+			id:       id,
+			token:    otherToken,
+			err:      sdk.ErrNotFound,
+			response: sdk.Capabilities{},
+		},
+		{
+			desc:     "get capabilities of entity without kind",
+			id:       id,
+			token:    token,
+			err:      sdk.ErrInvalidArgs,
+			response: sdk.Capabilities{},
+		},
+		{
+			desc:     "get capabilities with invalid token",
+			token:    wrongValue,
+			err:      sdk.ErrUnauthorized,
+			response: sdk.Capabilities{},
+		},
+	}
+
+	for _, tc := range cases {
+		caps, err := mainfluxSDK.Capabilities(tc.kind, tc.id, tc.token)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+		assert.Equal(t, tc.response, caps, fmt.Sprintf("%s: expected response %v, got %v", tc.desc, tc.response, caps))
+	}
+
+	caps, err := mainfluxSDK.Capabilities("thing", id, token)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.True(t, caps.Allows("remove"), "expected owner to be allowed to remove the thing")
+	assert.False(t, caps.Allows("archive"), "expected unknown operation not to be allowed")
+}
@@ -42,6 +42,16 @@ func (sdk mfSDK) CreateUser(user User) error {
 }
 
 func (sdk mfSDK) CreateToken(user User) (string, error) {
+	token, err := sdk.requestToken(user)
+	if err != nil {
+		return "", err
+	}
+
+	sdk.auth.store(user, token)
+	return token, nil
+}
+
+func (sdk mfSDK) requestToken(user User) (string, error) {
 	data, err := json.Marshal(user)
 	if err != nil {
 		return "", ErrInvalidArgs
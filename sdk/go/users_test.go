@@ -12,6 +12,7 @@ import (
 	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 
 	log "github.com/mainflux/mainflux/logger"
 	sdk "github.com/mainflux/mainflux/sdk/go"
@@ -32,7 +33,7 @@ func newUserService() users.Service {
 	hasher := mocks.NewHasher()
 	idp := mocks.NewIdentityProvider()
 
-	return users.New(repo, hasher, idp)
+	return users.New(repo, mocks.NewSessionRepository(), mocks.NewResetTokenRepository(), hasher, idp, mocks.NewMailer(), []string{}, time.Minute)
 }
 
 func newUserServer(svc users.Service) *httptest.Server {
@@ -123,31 +124,32 @@ func TestCreateToken(t *testing.T) {
 	cases := []struct {
 		desc  string
 		user  sdk.User
-		token string
+		email string
 		err   error
 	}{
 		{
 			desc:  "create token for user",
 			user:  user,
-			token: user.Email,
+			email: user.Email,
 			err:   nil,
 		},
 		{
 			desc:  "create token for non existing user",
 			user:  sdk.User{Email: "user2@example.com", Password: "password"},
-			token: "",
+			email: "",
 			err:   sdk.ErrUnauthorized,
 		},
 		{
 			desc:  "create user with empty email",
 			user:  sdk.User{Email: "", Password: "password"},
-			token: "",
+			email: "",
 			err:   sdk.ErrInvalidArgs,
 		},
 	}
 	for _, tc := range cases {
 		token, err := mainfluxSDK.CreateToken(tc.user)
 		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
-		assert.Equal(t, tc.token, token, fmt.Sprintf("%s: expected response: %s, got:  %s", tc.desc, token, tc.token))
+		email, _ := svc.Identify(token)
+		assert.Equal(t, tc.email, email, fmt.Sprintf("%s: expected token of %s, got token of %s", tc.desc, tc.email, email))
 	}
 }
@@ -0,0 +1,11 @@
+// Copyright (c) 2015-2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+
+'use strict';
+
+module.exports = Object.assign({},
+    require('./lib/errors'),
+    require('./lib/sdk'),
+    require('./lib/stream'));
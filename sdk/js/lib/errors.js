@@ -0,0 +1,36 @@
+// Copyright (c) 2015-2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+
+'use strict';
+
+class MainfluxError extends Error {
+    constructor(message, status) {
+        super(message);
+        this.name = this.constructor.name;
+        this.status = status;
+    }
+}
+
+const errors = { MainfluxError };
+
+[
+    'ConflictError',
+    'FailedCreationError',
+    'FailedUpdateError',
+    'FailedPublishError',
+    'FailedReadError',
+    'FailedRemovalError',
+    'FailedConnectionError',
+    'FailedDisconnectError',
+    'InvalidArgsError',
+    'FetchFailedError',
+    'UnauthorizedError',
+    'NotFoundError',
+    'InvalidContentTypeError',
+].forEach((name) => {
+    errors[name] = { [name]: class extends MainfluxError {} }[name];
+});
+
+module.exports = errors;
@@ -0,0 +1,230 @@
+// Copyright (c) 2015-2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+
+'use strict';
+
+const errors = require('./errors');
+
+const CT_JSON = 'application/json',
+    CT_JSON_SENML = 'application/senml+json',
+    CT_BINARY = 'application/octet-stream';
+
+const things = 'things',
+    channels = 'channels';
+
+// SDK is a client of the Mainflux HTTP API, mirroring the Go SDK. Entities
+// are plain objects using the JSON field names of the API. All the methods
+// return promises, rejected with a subclass of MainfluxError on failure.
+class SDK {
+    constructor({
+        baseURL,
+        readerURL = '',
+        readerPrefix = '',
+        usersPrefix = '',
+        thingsPrefix = '',
+        httpAdapterPrefix = '',
+        msgContentType = CT_JSON_SENML,
+        fetch = globalThis.fetch,
+    }) {
+        this.baseURL = baseURL.replace(/\/+$/, '');
+        this.readerURL = readerURL.replace(/\/+$/, '');
+        this.readerPrefix = readerPrefix;
+        this.usersPrefix = usersPrefix;
+        this.thingsPrefix = thingsPrefix;
+        this.httpAdapterPrefix = httpAdapterPrefix;
+        this.msgContentType = msgContentType;
+        this.fetch = fetch;
+    }
+
+    async createUser(email, password) {
+        const url = createURL(this.baseURL, this.usersPrefix, 'users');
+        await this.expect(this.request('POST', url, '', { email, password }), 201,
+            { 400: errors.InvalidArgsError, 409: errors.ConflictError },
+            errors.FailedCreationError);
+    }
+
+    async createToken(email, password) {
+        const url = createURL(this.baseURL, this.usersPrefix, 'tokens');
+        const res = await this.expect(this.request('POST', url, '', { email, password }), 201,
+            { 400: errors.InvalidArgsError, 403: errors.UnauthorizedError },
+            errors.FailedCreationError);
+        return (await res.json()).token;
+    }
+
+    async createThing(thing, token) {
+        const url = createURL(this.baseURL, this.thingsPrefix, things);
+        const res = await this.expect(this.request('POST', url, token, thing), 201,
+            { 400: errors.InvalidArgsError, 403: errors.UnauthorizedError },
+            errors.FailedCreationError);
+        return createdID(res, things);
+    }
+
+    things(token, offset = 0, limit = 10, name = '') {
+        const query = new URLSearchParams({ offset, limit, name });
+        return this.get(createURL(this.baseURL, this.thingsPrefix, `${things}?${query}`), token);
+    }
+
+    thingsByChannel(token, chanID, offset = 0, limit = 10) {
+        const endpoint = `${channels}/${chanID}/${things}?offset=${offset}&limit=${limit}`;
+        return this.get(createURL(this.baseURL, this.thingsPrefix, endpoint), token);
+    }
+
+    thing(id, token) {
+        return this.get(createURL(this.baseURL, this.thingsPrefix, `${things}/${id}`), token);
+    }
+
+    async updateThing(thing, token) {
+        const url = createURL(this.baseURL, this.thingsPrefix, `${things}/${thing.id}`);
+        await this.expect(this.request('PUT', url, token, thing), 200,
+            { 400: errors.InvalidArgsError, 403: errors.UnauthorizedError, 404: errors.NotFoundError },
+            errors.FailedUpdateError);
+    }
+
+    async deleteThing(id, token) {
+        const url = createURL(this.baseURL, this.thingsPrefix, `${things}/${id}`);
+        await this.expect(this.request('DELETE', url, token), 204,
+            { 400: errors.InvalidArgsError, 403: errors.UnauthorizedError },
+            errors.FailedRemovalError);
+    }
+
+    async connectThing(thingID, chanID, token) {
+        const url = createURL(this.baseURL, this.thingsPrefix, `${channels}/${chanID}/${things}/${thingID}`);
+        await this.expect(this.request('PUT', url, token), 200,
+            { 403: errors.UnauthorizedError, 404: errors.NotFoundError },
+            errors.FailedConnectionError);
+    }
+
+    async disconnectThing(thingID, chanID, token) {
+        const url = createURL(this.baseURL, this.thingsPrefix, `${channels}/${chanID}/${things}/${thingID}`);
+        await this.expect(this.request('DELETE', url, token), 204,
+            { 403: errors.UnauthorizedError, 404: errors.NotFoundError },
+            errors.FailedDisconnectError);
+    }
+
+    async createChannel(channel, token) {
+        const url = createURL(this.baseURL, this.thingsPrefix, channels);
+        const res = await this.expect(this.request('POST', url, token, channel), 201,
+            { 400: errors.InvalidArgsError, 403: errors.UnauthorizedError },
+            errors.FailedCreationError);
+        return createdID(res, channels);
+    }
+
+    channels(token, offset = 0, limit = 10, name = '') {
+        const query = new URLSearchParams({ offset, limit, name });
+        return this.get(createURL(this.baseURL, this.thingsPrefix, `${channels}?${query}`), token);
+    }
+
+    channelsByThing(token, thingID, offset = 0, limit = 10) {
+        const endpoint = `${things}/${thingID}/${channels}?offset=${offset}&limit=${limit}`;
+        return this.get(createURL(this.baseURL, this.thingsPrefix, endpoint), token);
+    }
+
+    channel(id, token) {
+        return this.get(createURL(this.baseURL, this.thingsPrefix, `${channels}/${id}`), token);
+    }
+
+    async updateChannel(channel, token) {
+        const url = createURL(this.baseURL, this.thingsPrefix, `${channels}/${channel.id}`);
+        await this.expect(this.request('PUT', url, token, channel), 200,
+            { 400: errors.InvalidArgsError, 403: errors.UnauthorizedError, 404: errors.NotFoundError },
+            errors.FailedUpdateError);
+    }
+
+    async deleteChannel(id, token) {
+        const url = createURL(this.baseURL, this.thingsPrefix, `${channels}/${id}`);
+        await this.expect(this.request('DELETE', url, token), 204,
+            { 400: errors.InvalidArgsError, 403: errors.UnauthorizedError },
+            errors.FailedRemovalError);
+    }
+
+    // sendMessage publishes the message using the thing key. Subtopic is
+    // passed as the dot separated suffix of the channel name, e.g.
+    // "<channel_id>.room.temperature".
+    async sendMessage(chanName, msg, key) {
+        const [chanID, subtopic] = splitChannel(chanName);
+        let endpoint = `${channels}/${chanID}/messages`;
+        if (subtopic) {
+            endpoint += `/${subtopic}`;
+        }
+        const url = createURL(this.baseURL, this.httpAdapterPrefix, endpoint);
+        await this.expect(this.request('POST', url, key, msg, this.msgContentType), 202,
+            { 400: errors.InvalidArgsError, 403: errors.UnauthorizedError },
+            errors.FailedPublishError);
+    }
+
+    async readMessages(chanName, token, offset = 0, limit = 10) {
+        const [chanID, subtopic] = splitChannel(chanName);
+        const query = new URLSearchParams({ offset, limit });
+        if (subtopic) {
+            query.set('subtopic', subtopic);
+        }
+        const url = createURL(this.readerURL, this.readerPrefix, `${channels}/${chanID}/messages?${query}`);
+        const res = await this.expect(this.request('GET', url, token), 200,
+            { 400: errors.InvalidArgsError, 403: errors.UnauthorizedError },
+            errors.FailedReadError);
+        return res.json();
+    }
+
+    setContentType(ct) {
+        if (![CT_JSON, CT_JSON_SENML, CT_BINARY].includes(ct)) {
+            throw new errors.InvalidContentTypeError(ct);
+        }
+        this.msgContentType = ct;
+    }
+
+    async version() {
+        const res = await this.expect(this.request('GET', `${this.baseURL}/version`), 200, {},
+            errors.FetchFailedError);
+        return (await res.json()).version;
+    }
+
+    async get(url, token) {
+        const res = await this.expect(this.request('GET', url, token), 200,
+            { 400: errors.InvalidArgsError, 403: errors.UnauthorizedError, 404: errors.NotFoundError },
+            errors.FetchFailedError);
+        return res.json();
+    }
+
+    request(method, url, token = '', body = undefined, contentType = CT_JSON) {
+        const headers = { 'Content-Type': contentType };
+        if (token) {
+            headers.Authorization = token;
+        }
+        if (body !== undefined && contentType === CT_JSON) {
+            body = JSON.stringify(body);
+        }
+        return this.fetch(url, { method, headers, body });
+    }
+
+    async expect(pending, status, mapping, fallback) {
+        const res = await pending;
+        if (res.status === status) {
+            return res;
+        }
+        const Err = mapping[res.status] || fallback;
+        throw new Err(`unexpected status code ${res.status}`, res.status);
+    }
+}
+
+function createURL(base, prefix, endpoint) {
+    if (!prefix) {
+        return `${base}/${endpoint}`;
+    }
+    return `${base}/${prefix}/${endpoint}`;
+}
+
+function createdID(res, endpoint) {
+    return (res.headers.get('Location') || '').slice(`/${endpoint}/`.length);
+}
+
+function splitChannel(chanName) {
+    const idx = chanName.indexOf('.');
+    if (idx < 0) {
+        return [chanName, ''];
+    }
+    return [chanName.slice(0, idx), chanName.slice(idx + 1).replace(/\./g, '/')];
+}
+
+module.exports = { SDK, CT_JSON, CT_JSON_SENML, CT_BINARY };
@@ -0,0 +1,96 @@
+// Copyright (c) 2015-2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+
+'use strict';
+
+const { EventEmitter } = require('events');
+
+const resumeHeader = 'resume-token';
+
+// Subscription streams the messages published to the channel through the
+// WebSocket adapter, emitting a 'message' event per payload. Dropped
+// connections are re-established with exponential backoff. If the adapter
+// supports subscription resumption, the resume token received on the first
+// handshake is passed on reconnect, so the messages published while the
+// client was disconnected are replayed.
+class Subscription extends EventEmitter {
+    constructor({
+        wsURL,
+        chanID,
+        key,
+        subtopic = '',
+        reconnect = true,
+        backoff = 1000,
+        maxBackoff = 30000,
+        WebSocket = require('ws'),
+    }) {
+        super();
+        this.wsURL = wsURL.replace(/\/+$/, '');
+        this.chanID = chanID;
+        this.key = key;
+        this.subtopic = subtopic;
+        this.reconnect = reconnect;
+        this.backoff = backoff;
+        this.maxBackoff = maxBackoff;
+        this.WebSocket = WebSocket;
+        this.resumeToken = '';
+        this.delay = backoff;
+        this.closed = false;
+        this.conn = null;
+    }
+
+    url() {
+        let path = `${this.wsURL}/channels/${this.chanID}/messages`;
+        if (this.subtopic) {
+            path += `/${this.subtopic.replace(/\./g, '/')}`;
+        }
+        const query = new URLSearchParams({ authorization: this.key });
+        if (this.resumeToken) {
+            query.set('resume', this.resumeToken);
+        }
+        return `${path}?${query}`;
+    }
+
+    open() {
+        const conn = new this.WebSocket(this.url());
+        this.conn = conn;
+
+        conn.on('upgrade', (res) => {
+            const token = res.headers[resumeHeader];
+            if (token) {
+                this.resumeToken = token;
+            }
+        });
+        conn.on('open', () => {
+            this.delay = this.backoff;
+            this.emit('open');
+        });
+        conn.on('message', (data) => this.emit('message', data));
+        conn.on('error', (err) => this.emit('error', err));
+        conn.on('close', () => {
+            if (this.closed || !this.reconnect) {
+                this.emit('close');
+                return;
+            }
+            setTimeout(() => this.open(), this.delay);
+            this.delay = Math.min(this.delay * 2, this.maxBackoff);
+        });
+
+        return this;
+    }
+
+    publish(payload) {
+        this.conn.send(payload);
+    }
+
+    close() {
+        this.closed = true;
+        if (this.conn) {
+            this.conn.close();
+        }
+    }
+}
+
+module.exports = { Subscription };
@@ -0,0 +1,125 @@
+// Copyright (c) 2015-2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+
+'use strict';
+
+const assert = require('assert');
+const { EventEmitter } = require('events');
+const { test } = require('node:test');
+const mainflux = require('..');
+
+const token = 'token',
+    key = 'key';
+
+function server(routes) {
+    const requests = [];
+    const fetch = async (url, opts) => {
+        const path = new URL(url).pathname;
+        requests.push({ url, path, opts });
+        const route = routes[`${opts.method} ${path}`];
+        if (!route) {
+            return new Response(null, { status: 404 });
+        }
+        if (opts.headers.Authorization !== (route.auth || token)) {
+            return new Response(null, { status: 403 });
+        }
+        const body = route.body === undefined ? null : JSON.stringify(route.body);
+        return new Response(body, { status: route.status, headers: route.headers });
+    };
+    const sdk = new mainflux.SDK({ baseURL: 'http://localhost', readerURL: 'http://localhost:8905', fetch });
+    return { sdk, requests };
+}
+
+test('create thing', async () => {
+    const { sdk } = server({ 'POST /things': { status: 201, headers: { Location: '/things/1' } } });
+
+    assert.strictEqual(await sdk.createThing({ name: 'test' }, token), '1');
+    await assert.rejects(sdk.createThing({ name: 'test' }, 'wrong'), mainflux.UnauthorizedError);
+});
+
+test('view thing', async () => {
+    const thing = { id: '1', name: 'test', key: 'key' };
+    const { sdk } = server({ 'GET /things/1': { status: 200, body: thing } });
+
+    assert.deepStrictEqual(await sdk.thing('1', token), thing);
+    await assert.rejects(sdk.thing('2', token), mainflux.NotFoundError);
+});
+
+test('connect thing', async () => {
+    const { sdk } = server({ 'PUT /channels/2/things/1': { status: 200 } });
+
+    await sdk.connectThing('1', '2', token);
+    await assert.rejects(sdk.connectThing('1', '3', token), mainflux.NotFoundError);
+});
+
+test('send message', async () => {
+    const { sdk, requests } = server({ 'POST /channels/1/messages/a/b': { status: 202, auth: key } });
+    const msg = '[{"n":"v","v":1}]';
+
+    await sdk.sendMessage('1.a.b', msg, key);
+    const req = requests[requests.length - 1];
+    assert.strictEqual(req.opts.headers['Content-Type'], mainflux.CT_JSON_SENML);
+    assert.strictEqual(req.opts.body, msg);
+    await assert.rejects(sdk.sendMessage('1.a.b', msg, 'wrong'), mainflux.UnauthorizedError);
+});
+
+test('read messages', async () => {
+    const page = { total: 1, offset: 0, limit: 10, messages: [{ channel: '1', name: 'v', value: 1 }] };
+    const { sdk, requests } = server({ 'GET /channels/1/messages': { status: 200, body: page } });
+
+    assert.deepStrictEqual(await sdk.readMessages('1.a', token), page);
+    assert.match(requests[requests.length - 1].url, /subtopic=a/);
+});
+
+test('set content type', () => {
+    const { sdk } = server({});
+
+    sdk.setContentType(mainflux.CT_BINARY);
+    assert.strictEqual(sdk.msgContentType, mainflux.CT_BINARY);
+    assert.throws(() => sdk.setContentType('text/plain'), mainflux.InvalidContentTypeError);
+});
+
+test('subscription resume', async () => {
+    const urls = [];
+    class FakeWebSocket extends EventEmitter {
+        constructor(url) {
+            super();
+            urls.push(url);
+            setImmediate(() => {
+                this.emit('upgrade', { headers: { 'resume-token': 'abc' } });
+                this.emit('open');
+                this.emit('message', `msg-${urls.length}`);
+                this.emit('close');
+            });
+        }
+
+        close() {}
+    }
+
+    const sub = new mainflux.Subscription({
+        wsURL: 'ws://localhost:8180',
+        chanID: '1',
+        key,
+        subtopic: 'a.b',
+        backoff: 0,
+        WebSocket: FakeWebSocket,
+    });
+
+    const received = await new Promise((resolve) => {
+        const msgs = [];
+        sub.on('message', (msg) => {
+            msgs.push(msg);
+            if (msgs.length === 2) {
+                sub.close();
+                resolve(msgs);
+            }
+        });
+        sub.open();
+    });
+
+    assert.deepStrictEqual(received, ['msg-1', 'msg-2']);
+    assert.strictEqual(urls[0], 'ws://localhost:8180/channels/1/messages/a/b?authorization=key');
+    assert.match(urls[1], /&resume=abc$/);
+});
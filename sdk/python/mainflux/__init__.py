@@ -0,0 +1,14 @@
+#
+# Copyright (c) 2018
+# Mainflux
+#
+# SPDX-License-Identifier: Apache-2.0
+#
+
+"""Python SDK for the Mainflux HTTP and WebSocket APIs."""
+
+from .errors import *  # noqa: F401,F403
+from .sdk import CT_BINARY, CT_JSON, CT_JSON_SENML, SDK  # noqa: F401
+from .stream import Subscription  # noqa: F401
+
+__version__ = "0.8.0"
@@ -0,0 +1,64 @@
+#
+# Copyright (c) 2018
+# Mainflux
+#
+# SPDX-License-Identifier: Apache-2.0
+#
+
+"""Errors raised by the Mainflux SDK."""
+
+
+class MainfluxError(Exception):
+    """Base class of all the SDK errors."""
+
+
+class ConflictError(MainfluxError):
+    """Entity with the same identity already exists."""
+
+
+class FailedCreationError(MainfluxError):
+    """Entity creation failed."""
+
+
+class FailedUpdateError(MainfluxError):
+    """Entity update failed."""
+
+
+class FailedPublishError(MainfluxError):
+    """Publishing message failed."""
+
+
+class FailedReadError(MainfluxError):
+    """Reading messages failed."""
+
+
+class FailedRemovalError(MainfluxError):
+    """Entity removal failed."""
+
+
+class FailedConnectionError(MainfluxError):
+    """Connecting thing to channel failed."""
+
+
+class FailedDisconnectError(MainfluxError):
+    """Disconnecting thing from channel failed."""
+
+
+class InvalidArgsError(MainfluxError):
+    """Invalid argument passed."""
+
+
+class FetchFailedError(MainfluxError):
+    """Fetching entity data failed."""
+
+
+class UnauthorizedError(MainfluxError):
+    """Missing or invalid credentials provided."""
+
+
+class NotFoundError(MainfluxError):
+    """Entity doesn't exist."""
+
+
+class InvalidContentTypeError(MainfluxError):
+    """Unknown message content type passed."""
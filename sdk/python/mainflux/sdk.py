@@ -0,0 +1,287 @@
+#
+# Copyright (c) 2018
+# Mainflux
+#
+# SPDX-License-Identifier: Apache-2.0
+#
+
+"""Client of the Mainflux HTTP API, mirroring the Go SDK."""
+
+import json
+import ssl
+import urllib.error
+import urllib.parse
+import urllib.request
+
+from . import errors
+
+CT_JSON = "application/json"
+CT_JSON_SENML = "application/senml+json"
+CT_BINARY = "application/octet-stream"
+
+_CONTENT_TYPES = (CT_JSON, CT_JSON_SENML, CT_BINARY)
+
+_THINGS = "things"
+_CHANNELS = "channels"
+
+
+class SDK:
+    """Mainflux HTTP API client.
+
+    Provides both system administration (provisioning) and messaging. Entities
+    are represented as plain dicts, using the JSON field names of the API.
+    """
+
+    def __init__(self, base_url, reader_url="", reader_prefix="",
+                 users_prefix="", things_prefix="", http_adapter_prefix="",
+                 msg_content_type=CT_JSON_SENML, tls_verification=True,
+                 timeout=10):
+        self.base_url = base_url.rstrip("/")
+        self.reader_url = reader_url.rstrip("/")
+        self.reader_prefix = reader_prefix
+        self.users_prefix = users_prefix
+        self.things_prefix = things_prefix
+        self.http_adapter_prefix = http_adapter_prefix
+        self.msg_content_type = msg_content_type
+        self.timeout = timeout
+        self._ssl = None
+        if not tls_verification:
+            self._ssl = ssl._create_unverified_context()
+
+    def create_user(self, email, password):
+        """Registers Mainflux user."""
+        url = _url(self.base_url, self.users_prefix, "users")
+        self._expect(self._request("POST", url, body=_json(
+            {"email": email, "password": password})), 201,
+            {400: errors.InvalidArgsError, 409: errors.ConflictError},
+            errors.FailedCreationError)
+
+    def create_token(self, email, password):
+        """Receives credentials and returns user token."""
+        url = _url(self.base_url, self.users_prefix, "tokens")
+        res = self._expect(self._request("POST", url, body=_json(
+            {"email": email, "password": password})), 201,
+            {400: errors.InvalidArgsError, 403: errors.UnauthorizedError},
+            errors.FailedCreationError)
+        return json.loads(res.body)["token"]
+
+    def create_thing(self, thing, token):
+        """Registers new thing and returns its ID."""
+        url = _url(self.base_url, self.things_prefix, _THINGS)
+        res = self._expect(self._request(
+            "POST", url, token, body=_json(thing)), 201,
+            {400: errors.InvalidArgsError, 403: errors.UnauthorizedError},
+            errors.FailedCreationError)
+        return _created_id(res, _THINGS)
+
+    def things(self, token, offset=0, limit=10, name=""):
+        """Returns page of things."""
+        query = urllib.parse.urlencode(
+            {"offset": offset, "limit": limit, "name": name})
+        url = _url(self.base_url, self.things_prefix,
+                   "%s?%s" % (_THINGS, query))
+        return self._fetch(url, token)
+
+    def things_by_channel(self, token, chan_id, offset=0, limit=10):
+        """Returns page of things connected to the specified channel."""
+        url = _url(self.base_url, self.things_prefix,
+                   "%s/%s/%s?offset=%d&limit=%d" %
+                   (_CHANNELS, chan_id, _THINGS, offset, limit))
+        return self._fetch(url, token)
+
+    def thing(self, thing_id, token):
+        """Returns thing by ID."""
+        url = _url(self.base_url, self.things_prefix,
+                   "%s/%s" % (_THINGS, thing_id))
+        return self._fetch(url, token)
+
+    def update_thing(self, thing, token):
+        """Updates existing thing."""
+        url = _url(self.base_url, self.things_prefix,
+                   "%s/%s" % (_THINGS, thing["id"]))
+        self._expect(self._request("PUT", url, token, body=_json(thing)), 200,
+                     {400: errors.InvalidArgsError,
+                      403: errors.UnauthorizedError,
+                      404: errors.NotFoundError},
+                     errors.FailedUpdateError)
+
+    def delete_thing(self, thing_id, token):
+        """Removes existing thing."""
+        url = _url(self.base_url, self.things_prefix,
+                   "%s/%s" % (_THINGS, thing_id))
+        self._expect(self._request("DELETE", url, token), 204,
+                     {400: errors.InvalidArgsError,
+                      403: errors.UnauthorizedError},
+                     errors.FailedRemovalError)
+
+    def connect_thing(self, thing_id, chan_id, token):
+        """Connects thing to the specified channel."""
+        url = _url(self.base_url, self.things_prefix, "%s/%s/%s/%s" %
+                   (_CHANNELS, chan_id, _THINGS, thing_id))
+        self._expect(self._request("PUT", url, token), 200,
+                     {403: errors.UnauthorizedError,
+                      404: errors.NotFoundError},
+                     errors.FailedConnectionError)
+
+    def disconnect_thing(self, thing_id, chan_id, token):
+        """Disconnects thing from the specified channel."""
+        url = _url(self.base_url, self.things_prefix, "%s/%s/%s/%s" %
+                   (_CHANNELS, chan_id, _THINGS, thing_id))
+        self._expect(self._request("DELETE", url, token), 204,
+                     {403: errors.UnauthorizedError,
+                      404: errors.NotFoundError},
+                     errors.FailedDisconnectError)
+
+    def create_channel(self, channel, token):
+        """Creates new channel and returns its ID."""
+        url = _url(self.base_url, self.things_prefix, _CHANNELS)
+        res = self._expect(self._request(
+            "POST", url, token, body=_json(channel)), 201,
+            {400: errors.InvalidArgsError, 403: errors.UnauthorizedError},
+            errors.FailedCreationError)
+        return _created_id(res, _CHANNELS)
+
+    def channels(self, token, offset=0, limit=10, name=""):
+        """Returns page of channels."""
+        query = urllib.parse.urlencode(
+            {"offset": offset, "limit": limit, "name": name})
+        url = _url(self.base_url, self.things_prefix,
+                   "%s?%s" % (_CHANNELS, query))
+        return self._fetch(url, token)
+
+    def channels_by_thing(self, token, thing_id, offset=0, limit=10):
+        """Returns page of channels the specified thing is connected to."""
+        url = _url(self.base_url, self.things_prefix,
+                   "%s/%s/%s?offset=%d&limit=%d" %
+                   (_THINGS, thing_id, _CHANNELS, offset, limit))
+        return self._fetch(url, token)
+
+    def channel(self, chan_id, token):
+        """Returns channel by ID."""
+        url = _url(self.base_url, self.things_prefix,
+                   "%s/%s" % (_CHANNELS, chan_id))
+        return self._fetch(url, token)
+
+    def update_channel(self, channel, token):
+        """Updates existing channel."""
+        url = _url(self.base_url, self.things_prefix,
+                   "%s/%s" % (_CHANNELS, channel["id"]))
+        self._expect(self._request("PUT", url, token, body=_json(channel)),
+                     200,
+                     {400: errors.InvalidArgsError,
+                      403: errors.UnauthorizedError,
+                      404: errors.NotFoundError},
+                     errors.FailedUpdateError)
+
+    def delete_channel(self, chan_id, token):
+        """Removes existing channel."""
+        url = _url(self.base_url, self.things_prefix,
+                   "%s/%s" % (_CHANNELS, chan_id))
+        self._expect(self._request("DELETE", url, token), 204,
+                     {400: errors.InvalidArgsError,
+                      403: errors.UnauthorizedError},
+                     errors.FailedRemovalError)
+
+    def send_message(self, chan_name, msg, key):
+        """Publishes the message to the channel using the thing key.
+
+        Subtopic is passed as the dot separated suffix of the channel name,
+        e.g. "<channel_id>.room.temperature".
+        """
+        chan_id, subtopic = _split_channel(chan_name)
+        endpoint = "%s/%s/messages" % (_CHANNELS, chan_id)
+        if subtopic:
+            endpoint += "/" + subtopic
+        url = _url(self.base_url, self.http_adapter_prefix, endpoint)
+        if isinstance(msg, str):
+            msg = msg.encode()
+        self._expect(self._request("POST", url, key, self.msg_content_type,
+                                   msg), 202,
+                     {400: errors.InvalidArgsError,
+                      403: errors.UnauthorizedError},
+                     errors.FailedPublishError)
+
+    def read_messages(self, chan_name, token, offset=0, limit=10):
+        """Returns page of messages stored by the reader."""
+        chan_id, subtopic = _split_channel(chan_name)
+        query = {"offset": offset, "limit": limit}
+        if subtopic:
+            query["subtopic"] = subtopic
+        url = _url(self.reader_url, self.reader_prefix, "%s/%s/messages?%s" %
+                   (_CHANNELS, chan_id, urllib.parse.urlencode(query)))
+        res = self._expect(self._request("GET", url, token), 200,
+                           {400: errors.InvalidArgsError,
+                            403: errors.UnauthorizedError},
+                           errors.FailedReadError)
+        return json.loads(res.body)
+
+    def set_content_type(self, content_type):
+        """Sets content type of the published messages."""
+        if content_type not in _CONTENT_TYPES:
+            raise errors.InvalidContentTypeError(content_type)
+        self.msg_content_type = content_type
+
+    def version(self):
+        """Returns version of the service behind the base URL."""
+        url = "%s/version" % self.base_url
+        res = self._expect(self._request("GET", url), 200, {},
+                           errors.FetchFailedError)
+        return json.loads(res.body)["version"]
+
+    def _fetch(self, url, token):
+        res = self._expect(self._request("GET", url, token), 200,
+                           {400: errors.InvalidArgsError,
+                            403: errors.UnauthorizedError,
+                            404: errors.NotFoundError},
+                           errors.FetchFailedError)
+        return json.loads(res.body)
+
+    def _request(self, method, url, token="", content_type=CT_JSON,
+                 body=None):
+        req = urllib.request.Request(url, data=body, method=method)
+        if token:
+            req.add_header("Authorization", token)
+        if content_type:
+            req.add_header("Content-Type", content_type)
+
+        try:
+            with urllib.request.urlopen(req, timeout=self.timeout,
+                                        context=self._ssl) as res:
+                return _Response(res.status, res.headers, res.read())
+        except urllib.error.HTTPError as err:
+            return _Response(err.code, err.headers, err.read())
+
+    @staticmethod
+    def _expect(res, status, mapping, default):
+        if res.status == status:
+            return res
+        raise mapping.get(res.status, default)(
+            "unexpected status code %d" % res.status)
+
+
+class _Response:
+    def __init__(self, status, headers, body):
+        self.status = status
+        self.headers = headers
+        self.body = body
+
+
+def _url(base, prefix, endpoint):
+    if not prefix:
+        return "%s/%s" % (base, endpoint)
+    return "%s/%s/%s" % (base, prefix, endpoint)
+
+
+def _json(entity):
+    return json.dumps(entity).encode()
+
+
+def _created_id(res, endpoint):
+    location = res.headers.get("Location", "")
+    return location[len("/%s/" % endpoint):]
+
+
+def _split_channel(chan_name):
+    parts = chan_name.split(".", 1)
+    subtopic = parts[1].replace(".", "/") if len(parts) == 2 else ""
+    return parts[0], subtopic
@@ -0,0 +1,92 @@
+#
+# Copyright (c) 2018
+# Mainflux
+#
+# SPDX-License-Identifier: Apache-2.0
+#
+
+"""Streaming of channel messages over the WebSocket adapter."""
+
+import time
+import urllib.parse
+
+RESUME_HEADER = "resume-token"
+
+
+class Subscription:
+    """Iterable stream of messages published to the channel.
+
+    The stream transparently reconnects when the connection drops. If the
+    adapter supports subscription resumption, the resume token received on
+    the first handshake is passed on reconnect, so the messages published
+    while the client was disconnected are replayed.
+
+    Requires the ``websocket-client`` package.
+    """
+
+    def __init__(self, ws_url, chan_id, key, subtopic="", reconnect=True,
+                 backoff=1.0, max_backoff=30.0, connect=None):
+        self.ws_url = ws_url.rstrip("/")
+        self.chan_id = chan_id
+        self.key = key
+        self.subtopic = subtopic
+        self.reconnect = reconnect
+        self.backoff = backoff
+        self.max_backoff = max_backoff
+        self.resume_token = ""
+        self._connect = connect or _connect
+        self._conn = None
+        self._closed = False
+
+    def url(self):
+        """Returns the WebSocket URL of the subscription."""
+        path = "%s/channels/%s/messages" % (self.ws_url, self.chan_id)
+        if self.subtopic:
+            path += "/" + self.subtopic.replace(".", "/")
+        query = {"authorization": self.key}
+        if self.resume_token:
+            query["resume"] = self.resume_token
+        return "%s?%s" % (path, urllib.parse.urlencode(query))
+
+    def __iter__(self):
+        delay = self.backoff
+        while not self._closed:
+            try:
+                self._conn = self._connect(self.url())
+                token = _header(self._conn, RESUME_HEADER)
+                if token:
+                    self.resume_token = token
+                delay = self.backoff
+                while not self._closed:
+                    yield self._conn.recv()
+            except Exception:
+                if self._closed or not self.reconnect:
+                    raise
+                time.sleep(delay)
+                delay = min(delay * 2, self.max_backoff)
+
+    def publish(self, payload):
+        """Publishes the payload to the subscribed channel."""
+        if self._conn is None:
+            self._conn = self._connect(self.url())
+        self._conn.send(payload)
+
+    def close(self):
+        """Closes the subscription."""
+        self._closed = True
+        if self._conn is not None:
+            self._conn.close()
+
+
+def _connect(url):
+    import websocket  # pylint: disable=import-outside-toplevel
+
+    return websocket.create_connection(url)
+
+
+def _header(conn, name):
+    headers = getattr(conn, "getheaders", lambda: {})() or {}
+    for key, value in headers.items():
+        if key.lower() == name:
+            return value
+    return ""
@@ -0,0 +1,21 @@
+#
+# Copyright (c) 2018
+# Mainflux
+#
+# SPDX-License-Identifier: Apache-2.0
+#
+
+from setuptools import find_packages, setup
+
+setup(
+    name="mainflux",
+    version="0.8.0",
+    description="Python SDK for the Mainflux IoT platform",
+    url="https://github.com/mainflux/mainflux",
+    license="Apache-2.0",
+    packages=find_packages(exclude=["tests"]),
+    python_requires=">=3.5",
+    extras_require={
+        "stream": ["websocket-client>=0.56"],
+    },
+)
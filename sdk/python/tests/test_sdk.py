@@ -0,0 +1,164 @@
+#
+# Copyright (c) 2018
+# Mainflux
+#
+# SPDX-License-Identifier: Apache-2.0
+#
+
+import json
+import threading
+import unittest
+from http.server import BaseHTTPRequestHandler, HTTPServer
+
+import mainflux
+
+TOKEN = "token"
+KEY = "key"
+
+
+class _Handler(BaseHTTPRequestHandler):
+    routes = {}
+
+    def _serve(self):
+        length = int(self.headers.get("Content-Length") or 0)
+        self.server.requests.append(
+            (self.command, self.path, self.headers, self.rfile.read(length)))
+        route = self.routes.get((self.command, self.path.split("?")[0]))
+        if route is None:
+            self.send_response(404)
+            self.end_headers()
+            return
+        if self.headers.get("Authorization") != route.get("auth"):
+            self.send_response(403)
+            self.end_headers()
+            return
+        self.send_response(route["status"])
+        for name, value in route.get("headers", {}).items():
+            self.send_header(name, value)
+        body = json.dumps(route["body"]).encode() if "body" in route else b""
+        self.send_header("Content-Length", str(len(body)))
+        self.end_headers()
+        self.wfile.write(body)
+
+    do_GET = do_POST = do_PUT = do_DELETE = _serve
+
+    def log_message(self, *args):
+        pass
+
+
+class SDKTest(unittest.TestCase):
+    def setUp(self):
+        self.server = HTTPServer(("127.0.0.1", 0), _Handler)
+        self.server.requests = []
+        thread = threading.Thread(target=self.server.serve_forever)
+        thread.daemon = True
+        thread.start()
+        url = "http://127.0.0.1:%d" % self.server.server_port
+        self.sdk = mainflux.SDK(url, reader_url=url)
+
+    def tearDown(self):
+        self.server.shutdown()
+        self.server.server_close()
+
+    def route(self, method, path, status, auth=TOKEN, **kwargs):
+        _Handler.routes = {(method, path): dict(status=status, auth=auth,
+                                                **kwargs)}
+
+    def test_create_thing(self):
+        self.route("POST", "/things", 201, headers={"Location": "/things/1"})
+        cases = [
+            ("create thing", TOKEN, "1", None),
+            ("create thing with wrong credentials", "wrong", None,
+             mainflux.UnauthorizedError),
+        ]
+        for desc, token, want, err in cases:
+            with self.subTest(desc):
+                if err is not None:
+                    with self.assertRaises(err):
+                        self.sdk.create_thing({"name": "test"}, token)
+                    continue
+                self.assertEqual(want,
+                                 self.sdk.create_thing({"name": "test"}, token))
+
+    def test_thing(self):
+        thing = {"id": "1", "name": "test", "key": "key"}
+        self.route("GET", "/things/1", 200, body=thing)
+        self.assertEqual(thing, self.sdk.thing("1", TOKEN))
+        with self.assertRaises(mainflux.NotFoundError):
+            self.sdk.thing("2", TOKEN)
+
+    def test_connect_thing(self):
+        self.route("PUT", "/channels/2/things/1", 200)
+        self.sdk.connect_thing("1", "2", TOKEN)
+        with self.assertRaises(mainflux.NotFoundError):
+            self.sdk.connect_thing("1", "3", TOKEN)
+
+    def test_send_message(self):
+        self.route("POST", "/channels/1/messages/a/b", 202, auth=KEY)
+        self.sdk.send_message("1.a.b", '[{"n":"v","v":1}]', KEY)
+        method, path, headers, body = self.server.requests[-1]
+        self.assertEqual("application/senml+json", headers["Content-Type"])
+        self.assertEqual(b'[{"n":"v","v":1}]', body)
+        with self.assertRaises(mainflux.UnauthorizedError):
+            self.sdk.send_message("1.a.b", "{}", "wrong")
+
+    def test_read_messages(self):
+        page = {"total": 1, "offset": 0, "limit": 10,
+                "messages": [{"channel": "1", "name": "v", "value": 1}]}
+        self.route("GET", "/channels/1/messages", 200, body=page)
+        self.assertEqual(page, self.sdk.read_messages("1.a", TOKEN))
+        self.assertIn("subtopic=a", self.server.requests[-1][1])
+
+    def test_set_content_type(self):
+        self.sdk.set_content_type(mainflux.CT_BINARY)
+        self.assertEqual(mainflux.CT_BINARY, self.sdk.msg_content_type)
+        with self.assertRaises(mainflux.InvalidContentTypeError):
+            self.sdk.set_content_type("text/plain")
+
+
+class _Conn:
+    def __init__(self, headers, messages):
+        self.headers = headers
+        self.messages = list(messages)
+
+    def getheaders(self):
+        return self.headers
+
+    def recv(self):
+        if not self.messages:
+            raise ConnectionError("connection closed")
+        return self.messages.pop(0)
+
+    def close(self):
+        pass
+
+
+class SubscriptionTest(unittest.TestCase):
+    def test_resume(self):
+        urls = []
+        conns = [_Conn({"Resume-Token": "abc"}, ["1", "2"]),
+                 _Conn({"Resume-Token": "abc"}, ["3"])]
+
+        def connect(url):
+            urls.append(url)
+            return conns.pop(0)
+
+        sub = mainflux.Subscription("ws://localhost:8180", "1", KEY,
+                                    subtopic="a.b", backoff=0,
+                                    connect=connect)
+        received = []
+        for msg in sub:
+            received.append(msg)
+            if len(received) == 3:
+                sub.close()
+                break
+
+        self.assertEqual(["1", "2", "3"], received)
+        self.assertEqual(
+            "ws://localhost:8180/channels/1/messages/a/b?authorization=key",
+            urls[0])
+        self.assertTrue(urls[1].endswith("&resume=abc"))
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,56 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package api
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/mainflux/mainflux/search"
+)
+
+func searchEndpoint(svc search.Service) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(searchReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		query := search.Query{
+			Text:   req.text,
+			Type:   req.typ,
+			Offset: req.offset,
+			Limit:  req.limit,
+		}
+
+		page, err := svc.Search(req.key, query)
+		if err != nil {
+			return nil, err
+		}
+
+		res := searchRes{
+			pageRes: pageRes{
+				Total:  page.Total,
+				Offset: page.Offset,
+				Limit:  page.Limit,
+			},
+			Entities: []entityRes{},
+		}
+		for _, e := range page.Entities {
+			res.Entities = append(res.Entities, entityRes{
+				ID:       e.ID,
+				Type:     e.Type,
+				Name:     e.Name,
+				Tags:     e.Tags,
+				Metadata: e.Metadata,
+			})
+		}
+
+		return res, nil
+	}
+}
@@ -0,0 +1,196 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package api_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mainflux/mainflux/search"
+	"github.com/mainflux/mainflux/search/api"
+	"github.com/mainflux/mainflux/search/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	validToken   = "validToken"
+	invalidToken = "invalidToken"
+	email        = "user@example.com"
+)
+
+type testRequest struct {
+	client *http.Client
+	method string
+	url    string
+	token  string
+	body   io.Reader
+}
+
+func (tr testRequest) make() (*http.Response, error) {
+	req, err := http.NewRequest(tr.method, tr.url, tr.body)
+	if err != nil {
+		return nil, err
+	}
+
+	if tr.token != "" {
+		req.Header.Set("Authorization", tr.token)
+	}
+
+	return tr.client.Do(req)
+}
+
+type entityRes struct {
+	ID       string                 `json:"id"`
+	Type     string                 `json:"type"`
+	Name     string                 `json:"name,omitempty"`
+	Tags     []string               `json:"tags,omitempty"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+type searchRes struct {
+	Total    uint64      `json:"total"`
+	Offset   uint64      `json:"offset"`
+	Limit    uint64      `json:"limit"`
+	Entities []entityRes `json:"entities"`
+}
+
+func newService() search.Service {
+	users := mocks.NewUsersService(map[string]string{validToken: email})
+	return search.New(users, mocks.NewIndex())
+}
+
+func newServer(svc search.Service) *httptest.Server {
+	return httptest.NewServer(api.MakeHandler(svc))
+}
+
+func toJSON(data interface{}) string {
+	jsonData, _ := json.Marshal(data)
+	return string(jsonData)
+}
+
+func TestSearch(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+
+	entities := []search.Entity{
+		{ID: "1", Type: search.ThingType, Owner: email, Name: "temperature sensor", Tags: []string{"kitchen"}},
+		{ID: "2", Type: search.ChannelType, Owner: email, Name: "sensors", Metadata: map[string]interface{}{"floor": "2"}},
+		{ID: "3", Type: search.ThingType, Owner: email, Name: "gateway"},
+	}
+	for _, e := range entities {
+		err := svc.SaveEntityHandler(e)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	thing := entityRes{ID: "1", Type: search.ThingType, Name: "temperature sensor", Tags: []string{"kitchen"}}
+	channel := entityRes{ID: "2", Type: search.ChannelType, Name: "sensors", Metadata: map[string]interface{}{"floor": "2"}}
+
+	cases := []struct {
+		desc   string
+		url    string
+		token  string
+		status int
+		res    string
+	}{
+		{
+			desc:   "search by text",
+			url:    "/search?q=sens",
+			token:  validToken,
+			status: http.StatusOK,
+			res:    toJSON(searchRes{Total: 2, Limit: 10, Entities: []entityRes{thing, channel}}),
+		},
+		{
+			desc:   "search by text and type",
+			url:    "/search?q=sens&type=thing",
+			token:  validToken,
+			status: http.StatusOK,
+			res:    toJSON(searchRes{Total: 1, Limit: 10, Entities: []entityRes{thing}}),
+		},
+		{
+			desc:   "search with offset and limit",
+			url:    "/search?q=sens&offset=1&limit=1",
+			token:  validToken,
+			status: http.StatusOK,
+			res:    toJSON(searchRes{Total: 2, Offset: 1, Limit: 1, Entities: []entityRes{channel}}),
+		},
+		{
+			desc:   "search without matches",
+			url:    "/search?q=humid",
+			token:  validToken,
+			status: http.StatusOK,
+			res:    toJSON(searchRes{Total: 0, Limit: 10, Entities: []entityRes{}}),
+		},
+		{
+			desc:   "search with invalid type",
+			url:    "/search?q=sens&type=user",
+			token:  validToken,
+			status: http.StatusBadRequest,
+			res:    "",
+		},
+		{
+			desc:   "search with invalid limit",
+			url:    "/search?q=sens&limit=invalid",
+			token:  validToken,
+			status: http.StatusBadRequest,
+			res:    "",
+		},
+		{
+			desc:   "search with too big limit",
+			url:    "/search?q=sens&limit=1000",
+			token:  validToken,
+			status: http.StatusBadRequest,
+			res:    "",
+		},
+		{
+			desc:   "search with duplicated query",
+			url:    "/search?q=sens&q=gate",
+			token:  validToken,
+			status: http.StatusBadRequest,
+			res:    "",
+		},
+		{
+			desc:   "search with invalid token",
+			url:    "/search?q=sens",
+			token:  invalidToken,
+			status: http.StatusForbidden,
+			res:    "",
+		},
+		{
+			desc:   "search with empty token",
+			url:    "/search?q=sens",
+			token:  "",
+			status: http.StatusForbidden,
+			res:    "",
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s%s", ts.URL, tc.url),
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+
+		body, err := ioutil.ReadAll(res.Body)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		data := strings.Trim(string(body), "\n")
+		assert.Equal(t, tc.res, data, fmt.Sprintf("%s: expected body %s got %s", tc.desc, tc.res, data))
+	}
+}
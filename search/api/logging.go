@@ -0,0 +1,95 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// +build !test
+
+package api
+
+import (
+	"fmt"
+	"time"
+
+	log "github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/search"
+)
+
+var _ search.Service = (*loggingMiddleware)(nil)
+
+type loggingMiddleware struct {
+	logger log.Logger
+	svc    search.Service
+}
+
+// NewLoggingMiddleware adds logging facilities to the core service.
+func NewLoggingMiddleware(svc search.Service, logger log.Logger) search.Service {
+	return &loggingMiddleware{logger, svc}
+}
+
+func (lm *loggingMiddleware) Search(key string, query search.Query) (page search.EntitiesPage, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method search for key %s and text %s took %s to complete", key, query.Text, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Search(key, query)
+}
+
+func (lm *loggingMiddleware) SaveEntityHandler(entity search.Entity) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method save_entity_handler for %s %s took %s to complete", entity.Type, entity.ID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.SaveEntityHandler(entity)
+}
+
+func (lm *loggingMiddleware) UpdateEntityHandler(entity search.Entity) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method update_entity_handler for %s %s took %s to complete", entity.Type, entity.ID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.UpdateEntityHandler(entity)
+}
+
+func (lm *loggingMiddleware) RemoveEntityHandler(id string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method remove_entity_handler for entity %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RemoveEntityHandler(id)
+}
+
+func (lm *loggingMiddleware) RemoveUserHandler(owner string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method remove_user_handler for user %s took %s to complete", owner, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RemoveUserHandler(owner)
+}
@@ -0,0 +1,80 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// +build !test
+
+package api
+
+import (
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/mainflux/mainflux/search"
+)
+
+var _ search.Service = (*metricsMiddleware)(nil)
+
+type metricsMiddleware struct {
+	counter metrics.Counter
+	latency metrics.Histogram
+	svc     search.Service
+}
+
+// MetricsMiddleware instruments core service by tracking request count and
+// latency.
+func MetricsMiddleware(svc search.Service, counter metrics.Counter, latency metrics.Histogram) search.Service {
+	return &metricsMiddleware{
+		counter: counter,
+		latency: latency,
+		svc:     svc,
+	}
+}
+
+func (mm *metricsMiddleware) Search(key string, query search.Query) (search.EntitiesPage, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "search").Add(1)
+		mm.latency.With("method", "search").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.Search(key, query)
+}
+
+func (mm *metricsMiddleware) SaveEntityHandler(entity search.Entity) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "save_entity_handler").Add(1)
+		mm.latency.With("method", "save_entity_handler").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.SaveEntityHandler(entity)
+}
+
+func (mm *metricsMiddleware) UpdateEntityHandler(entity search.Entity) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "update_entity_handler").Add(1)
+		mm.latency.With("method", "update_entity_handler").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.UpdateEntityHandler(entity)
+}
+
+func (mm *metricsMiddleware) RemoveEntityHandler(id string) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "remove_entity_handler").Add(1)
+		mm.latency.With("method", "remove_entity_handler").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.RemoveEntityHandler(id)
+}
+
+func (mm *metricsMiddleware) RemoveUserHandler(owner string) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "remove_user_handler").Add(1)
+		mm.latency.With("method", "remove_user_handler").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.RemoveUserHandler(owner)
+}
@@ -0,0 +1,30 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package api
+
+import "github.com/mainflux/mainflux/search"
+
+type apiReq interface {
+	validate() error
+}
+
+type searchReq struct {
+	key    string
+	text   string
+	typ    string
+	offset uint64
+	limit  uint64
+}
+
+func (req searchReq) validate() error {
+	if req.key == "" {
+		return search.ErrUnauthorizedAccess
+	}
+
+	return nil
+}
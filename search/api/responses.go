@@ -0,0 +1,47 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/mainflux/mainflux"
+)
+
+var _ mainflux.Response = (*searchRes)(nil)
+
+type pageRes struct {
+	Total  uint64 `json:"total"`
+	Offset uint64 `json:"offset"`
+	Limit  uint64 `json:"limit"`
+}
+
+type entityRes struct {
+	ID       string                 `json:"id"`
+	Type     string                 `json:"type"`
+	Name     string                 `json:"name,omitempty"`
+	Tags     []string               `json:"tags,omitempty"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+type searchRes struct {
+	pageRes
+	Entities []entityRes `json:"entities"`
+}
+
+func (res searchRes) Code() int {
+	return http.StatusOK
+}
+
+func (res searchRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res searchRes) Empty() bool {
+	return false
+}
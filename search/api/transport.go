@@ -0,0 +1,148 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/go-zoo/bone"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/search"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	contentType = "application/json"
+
+	text   = "q"
+	typ    = "type"
+	offset = "offset"
+	limit  = "limit"
+
+	defOffset = 0
+	defLimit  = 10
+)
+
+var errInvalidQueryParams = errors.New("invalid query params")
+
+// MakeHandler returns a HTTP handler for API endpoints.
+func MakeHandler(svc search.Service) http.Handler {
+	opts := []kithttp.ServerOption{
+		kithttp.ServerErrorEncoder(encodeError),
+	}
+	r := bone.New()
+
+	r.Get("/search", kithttp.NewServer(
+		searchEndpoint(svc),
+		decodeSearchRequest,
+		encodeResponse,
+		opts...))
+
+	r.GetFunc("/version", mainflux.Version("search"))
+	r.Handle("/metrics", promhttp.Handler())
+
+	return r
+}
+
+func decodeSearchRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	q, err := readStringQuery(r, text)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := readStringQuery(r, typ)
+	if err != nil {
+		return nil, err
+	}
+
+	o, err := readUintQuery(r, offset, defOffset)
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := readUintQuery(r, limit, defLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	req := searchReq{
+		key:    r.Header.Get("Authorization"),
+		text:   q,
+		typ:    t,
+		offset: o,
+		limit:  l,
+	}
+
+	return req, nil
+}
+
+func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", contentType)
+	if ar, ok := response.(mainflux.Response); ok {
+		for k, v := range ar.Headers() {
+			w.Header().Set(k, v)
+		}
+
+		w.WriteHeader(ar.Code())
+
+		if ar.Empty() {
+			return nil
+		}
+	}
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	w.Header().Set("Content-Type", contentType)
+
+	switch err {
+	case search.ErrMalformedEntity, errInvalidQueryParams:
+		w.WriteHeader(http.StatusBadRequest)
+	case search.ErrUnauthorizedAccess:
+		w.WriteHeader(http.StatusForbidden)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+func readUintQuery(r *http.Request, key string, def uint64) (uint64, error) {
+	vals := bone.GetQuery(r, key)
+	if len(vals) > 1 {
+		return 0, errInvalidQueryParams
+	}
+
+	if len(vals) == 0 {
+		return def, nil
+	}
+
+	val, err := strconv.ParseUint(vals[0], 10, 64)
+	if err != nil {
+		return 0, errInvalidQueryParams
+	}
+
+	return val, nil
+}
+
+func readStringQuery(r *http.Request, key string) (string, error) {
+	vals := bone.GetQuery(r, key)
+	if len(vals) > 1 {
+		return "", errInvalidQueryParams
+	}
+
+	if len(vals) == 0 {
+		return "", nil
+	}
+
+	return vals[0], nil
+}
@@ -0,0 +1,10 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// Package search contains the domain concept definitions needed to support
+// Mainflux search service functionality.
+package search
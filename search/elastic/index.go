@@ -0,0 +1,387 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// Package elastic contains the search index implementation backed by
+// Elasticsearch, or the compatible OpenSearch, accessed over its REST API.
+package elastic
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mainflux/mainflux/search"
+)
+
+const (
+	contentType   = "application/json"
+	alreadyExists = "resource_already_exists_exception"
+
+	// mapping indexes the names and tags with the edge n-grams of their
+	// words, so that the words being typed match as prefixes. Metadata is
+	// stored as is, and its values are indexed as separate field, so that
+	// the metadata fields of different types don't conflict.
+	mapping = `{
+	"settings": {
+		"analysis": {
+			"analyzer": {
+				"autocomplete": {
+					"tokenizer": "autocomplete",
+					"filter": ["lowercase"]
+				}
+			},
+			"tokenizer": {
+				"autocomplete": {
+					"type": "edge_ngram",
+					"min_gram": 1,
+					"max_gram": 20,
+					"token_chars": ["letter", "digit"]
+				}
+			}
+		}
+	},
+	"mappings": {
+		"properties": {
+			"type": {"type": "keyword"},
+			"owner": {"type": "keyword"},
+			"name": {"type": "text", "analyzer": "autocomplete", "search_analyzer": "standard"},
+			"tags": {"type": "text", "analyzer": "autocomplete", "search_analyzer": "standard"},
+			"metadata": {"type": "object", "enabled": false},
+			"metadata_values": {"type": "text", "analyzer": "autocomplete", "search_analyzer": "standard"}
+		}
+	}
+}`
+)
+
+// ErrUnexpectedResponse indicates that Elasticsearch responded with an
+// unexpected status code.
+var ErrUnexpectedResponse = errors.New("unexpected Elasticsearch response")
+
+// Config defines the options that are used when connecting to Elasticsearch.
+type Config struct {
+	URL     string
+	Index   string
+	User    string
+	Pass    string
+	Timeout time.Duration
+}
+
+var _ search.Index = (*index)(nil)
+
+type index struct {
+	client *http.Client
+	cfg    Config
+}
+
+type document struct {
+	Type     string                 `json:"type"`
+	Owner    string                 `json:"owner"`
+	Name     string                 `json:"name"`
+	Tags     []string               `json:"tags"`
+	Metadata map[string]interface{} `json:"metadata"`
+	Values   []string               `json:"metadata_values"`
+}
+
+type searchRes struct {
+	Hits struct {
+		Total json.RawMessage `json:"total"`
+		Hits  []struct {
+			ID     string   `json:"_id"`
+			Source document `json:"_source"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
+
+// Connect creates the index described by the configuration, unless it
+// exists, and returns the search index backed by it.
+func Connect(cfg Config) (search.Index, error) {
+	idx := index{
+		client: &http.Client{Timeout: cfg.Timeout},
+		cfg:    cfg,
+	}
+
+	if err := idx.create(); err != nil {
+		return nil, err
+	}
+
+	return idx, nil
+}
+
+func (idx index) Save(entity search.Entity) error {
+	doc := document{
+		Type:     entity.Type,
+		Owner:    entity.Owner,
+		Name:     entity.Name,
+		Tags:     entity.Tags,
+		Metadata: entity.Metadata,
+		Values:   values(entity.Metadata),
+	}
+
+	res, err := idx.do(http.MethodPut, fmt.Sprintf("/_doc/%s", url.PathEscape(entity.ID)), doc)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusCreated {
+		return ErrUnexpectedResponse
+	}
+
+	return nil
+}
+
+func (idx index) Update(entity search.Entity) error {
+	doc := map[string]interface{}{}
+	if entity.Name != "" {
+		doc["name"] = entity.Name
+	}
+	if entity.Tags != nil {
+		doc["tags"] = entity.Tags
+	}
+	if entity.Metadata != nil {
+		doc["metadata"] = entity.Metadata
+		doc["metadata_values"] = values(entity.Metadata)
+	}
+
+	body := map[string]interface{}{"doc": doc}
+	res, err := idx.do(http.MethodPost, fmt.Sprintf("/_update/%s", url.PathEscape(entity.ID)), body)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+		return search.ErrNotFound
+	default:
+		return ErrUnexpectedResponse
+	}
+}
+
+func (idx index) Remove(id string) error {
+	res, err := idx.do(http.MethodDelete, fmt.Sprintf("/_doc/%s", url.PathEscape(id)), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusNotFound {
+		return ErrUnexpectedResponse
+	}
+
+	return nil
+}
+
+func (idx index) RemoveAll(owner string) error {
+	body := map[string]interface{}{
+		"query": map[string]interface{}{
+			"term": map[string]interface{}{"owner": owner},
+		},
+	}
+
+	res, err := idx.do(http.MethodPost, "/_delete_by_query?conflicts=proceed", body)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return ErrUnexpectedResponse
+	}
+
+	return nil
+}
+
+func (idx index) Search(owner string, query search.Query) (search.EntitiesPage, error) {
+	filter := []interface{}{
+		map[string]interface{}{"term": map[string]interface{}{"owner": owner}},
+	}
+	if query.Type != "" {
+		filter = append(filter, map[string]interface{}{"term": map[string]interface{}{"type": query.Type}})
+	}
+
+	must := []interface{}{}
+	if text := strings.TrimSpace(query.Text); text != "" {
+		must = append(must, map[string]interface{}{
+			"multi_match": map[string]interface{}{
+				"query":     text,
+				"fields":    []string{"name^3", "tags^2", "metadata_values"},
+				"fuzziness": "AUTO",
+				"operator":  "and",
+			},
+		})
+	}
+
+	body := map[string]interface{}{
+		"from":             query.Offset,
+		"size":             query.Limit,
+		"track_total_hits": true,
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"filter": filter,
+				"must":   must,
+			},
+		},
+	}
+
+	res, err := idx.do(http.MethodPost, "/_search", body)
+	if err != nil {
+		return search.EntitiesPage{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return search.EntitiesPage{}, ErrUnexpectedResponse
+	}
+
+	var sr searchRes
+	if err := json.NewDecoder(res.Body).Decode(&sr); err != nil {
+		return search.EntitiesPage{}, err
+	}
+
+	total, err := decodeTotal(sr.Hits.Total)
+	if err != nil {
+		return search.EntitiesPage{}, err
+	}
+
+	entities := []search.Entity{}
+	for _, hit := range sr.Hits.Hits {
+		entities = append(entities, search.Entity{
+			ID:       hit.ID,
+			Type:     hit.Source.Type,
+			Owner:    hit.Source.Owner,
+			Name:     hit.Source.Name,
+			Tags:     hit.Source.Tags,
+			Metadata: hit.Source.Metadata,
+		})
+	}
+
+	return search.EntitiesPage{
+		Total:    total,
+		Offset:   query.Offset,
+		Limit:    query.Limit,
+		Entities: entities,
+	}, nil
+}
+
+func (idx index) create() error {
+	res, err := idx.do(http.MethodHead, "", nil)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+	default:
+		return ErrUnexpectedResponse
+	}
+
+	res, err = idx.do(http.MethodPut, "", json.RawMessage(mapping))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	// The index may have been created by the other instance meanwhile.
+	data, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode == http.StatusBadRequest && bytes.Contains(data, []byte(alreadyExists)) {
+		return nil
+	}
+
+	return ErrUnexpectedResponse
+}
+
+func (idx index) do(method, path string, body interface{}) (*http.Response, error) {
+	var r io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		r = bytes.NewReader(data)
+	}
+
+	u := fmt.Sprintf("%s/%s%s", strings.TrimSuffix(idx.cfg.URL, "/"), idx.cfg.Index, path)
+	req, err := http.NewRequest(method, u, r)
+	if err != nil {
+		return nil, err
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if idx.cfg.User != "" {
+		req.SetBasicAuth(idx.cfg.User, idx.cfg.Pass)
+	}
+
+	return idx.client.Do(req)
+}
+
+// decodeTotal decodes the number of hits, which Elasticsearch 7 and newer
+// return as object, and the older versions as number.
+func decodeTotal(data json.RawMessage) (uint64, error) {
+	var total uint64
+	if err := json.Unmarshal(data, &total); err == nil {
+		return total, nil
+	}
+
+	var obj struct {
+		Value uint64 `json:"value"`
+	}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return 0, err
+	}
+
+	return obj.Value, nil
+}
+
+// values returns the values of the metadata fields, including the nested
+// ones, in the order of their keys.
+func values(metadata map[string]interface{}) []string {
+	keys := []string{}
+	for k := range metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	vals := []string{}
+	for _, k := range keys {
+		switch v := metadata[k].(type) {
+		case nil:
+		case map[string]interface{}:
+			vals = append(vals, values(v)...)
+		case []interface{}:
+			for _, item := range v {
+				vals = append(vals, values(map[string]interface{}{"": item})...)
+			}
+		default:
+			vals = append(vals, fmt.Sprint(v))
+		}
+	}
+
+	return vals
+}
@@ -0,0 +1,254 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package elastic_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/search"
+	"github.com/mainflux/mainflux/search/elastic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	indexName = "entities"
+	owner     = "user@example.com"
+)
+
+type request struct {
+	method string
+	path   string
+	body   map[string]interface{}
+}
+
+// server fakes Elasticsearch, recording the requests and responding with
+// the status and body of the first matching route.
+type server struct {
+	requests []request
+	routes   map[string]route
+}
+
+type route struct {
+	status int
+	body   string
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	req := request{method: r.Method, path: r.URL.Path}
+	if data, err := ioutil.ReadAll(r.Body); err == nil && len(data) > 0 {
+		json.Unmarshal(data, &req.body)
+	}
+	s.requests = append(s.requests, req)
+
+	rt, ok := s.routes[fmt.Sprintf("%s %s", r.Method, r.URL.Path)]
+	if !ok {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	w.WriteHeader(rt.status)
+	w.Write([]byte(rt.body))
+}
+
+func connect(t *testing.T, routes map[string]route) (search.Index, *server, func()) {
+	srv := &server{routes: routes}
+	ts := httptest.NewServer(srv)
+
+	idx, err := elastic.Connect(elastic.Config{
+		URL:     ts.URL,
+		Index:   indexName,
+		Timeout: time.Second,
+	})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	return idx, srv, ts.Close
+}
+
+func TestConnect(t *testing.T) {
+	cases := []struct {
+		desc    string
+		routes  map[string]route
+		methods []string
+		err     error
+	}{
+		{
+			desc:    "connect to existing index",
+			routes:  map[string]route{},
+			methods: []string{http.MethodHead},
+			err:     nil,
+		},
+		{
+			desc: "connect to missing index",
+			routes: map[string]route{
+				"HEAD /entities": {status: http.StatusNotFound},
+			},
+			methods: []string{http.MethodHead, http.MethodPut},
+			err:     nil,
+		},
+		{
+			desc: "connect to index created meanwhile",
+			routes: map[string]route{
+				"HEAD /entities": {status: http.StatusNotFound},
+				"PUT /entities":  {status: http.StatusBadRequest, body: `{"error":{"type":"resource_already_exists_exception"}}`},
+			},
+			methods: []string{http.MethodHead, http.MethodPut},
+			err:     nil,
+		},
+		{
+			desc: "connect to failing Elasticsearch",
+			routes: map[string]route{
+				"HEAD /entities": {status: http.StatusInternalServerError},
+			},
+			methods: []string{http.MethodHead},
+			err:     elastic.ErrUnexpectedResponse,
+		},
+	}
+
+	for _, tc := range cases {
+		srv := &server{routes: tc.routes}
+		ts := httptest.NewServer(srv)
+
+		_, err := elastic.Connect(elastic.Config{URL: ts.URL, Index: indexName, Timeout: time.Second})
+		ts.Close()
+
+		methods := []string{}
+		for _, req := range srv.requests {
+			methods = append(methods, req.method)
+		}
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.methods, methods, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.methods, methods))
+	}
+}
+
+func TestSave(t *testing.T) {
+	idx, srv, closer := connect(t, map[string]route{
+		"PUT /entities/_doc/1": {status: http.StatusCreated},
+	})
+	defer closer()
+
+	err := idx.Save(search.Entity{
+		ID:       "1",
+		Type:     search.ThingType,
+		Owner:    owner,
+		Name:     "temperature sensor",
+		Tags:     []string{"kitchen"},
+		Metadata: map[string]interface{}{"model": "ts-100", "location": map[string]interface{}{"floor": 2}},
+	})
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	req := srv.requests[len(srv.requests)-1]
+	expected := map[string]interface{}{
+		"type":            search.ThingType,
+		"owner":           owner,
+		"name":            "temperature sensor",
+		"tags":            []interface{}{"kitchen"},
+		"metadata":        map[string]interface{}{"model": "ts-100", "location": map[string]interface{}{"floor": float64(2)}},
+		"metadata_values": []interface{}{"2", "ts-100"},
+	}
+	assert.Equal(t, "/entities/_doc/1", req.path, fmt.Sprintf("expected %s got %s", "/entities/_doc/1", req.path))
+	assert.Equal(t, expected, req.body, fmt.Sprintf("expected %v got %v", expected, req.body))
+}
+
+func TestUpdate(t *testing.T) {
+	idx, srv, closer := connect(t, map[string]route{
+		"POST /entities/_update/2": {status: http.StatusNotFound},
+	})
+	defer closer()
+
+	cases := []struct {
+		desc   string
+		entity search.Entity
+		doc    map[string]interface{}
+		err    error
+	}{
+		{
+			desc:   "update name of indexed entity",
+			entity: search.Entity{ID: "1", Name: "humidity sensor"},
+			doc:    map[string]interface{}{"name": "humidity sensor"},
+			err:    nil,
+		},
+		{
+			desc:   "update tags and metadata of indexed entity",
+			entity: search.Entity{ID: "1", Tags: []string{}, Metadata: map[string]interface{}{"model": "hs-1"}},
+			doc: map[string]interface{}{
+				"tags":            []interface{}{},
+				"metadata":        map[string]interface{}{"model": "hs-1"},
+				"metadata_values": []interface{}{"hs-1"},
+			},
+			err: nil,
+		},
+		{
+			desc:   "update entity that isn't indexed",
+			entity: search.Entity{ID: "2", Name: "sensor"},
+			doc:    map[string]interface{}{"name": "sensor"},
+			err:    search.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := idx.Update(tc.entity)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+
+		req := srv.requests[len(srv.requests)-1]
+		assert.Equal(t, tc.doc, req.body["doc"], fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.doc, req.body["doc"]))
+	}
+}
+
+func TestSearch(t *testing.T) {
+	hits := `"hits": [
+		{"_id": "1", "_source": {"type": "thing", "owner": "user@example.com", "name": "sensor", "tags": ["kitchen"]}},
+		{"_id": "2", "_source": {"type": "channel", "owner": "user@example.com", "name": "sensors"}}
+	]`
+
+	cases := []struct {
+		desc  string
+		body  string
+		query search.Query
+		total uint64
+	}{
+		{
+			desc:  "search Elasticsearch 7",
+			body:  fmt.Sprintf(`{"hits": {"total": {"value": 12, "relation": "eq"}, %s}}`, hits),
+			query: search.Query{Text: "sens", Offset: 0, Limit: 2},
+			total: 12,
+		},
+		{
+			desc:  "search Elasticsearch 6",
+			body:  fmt.Sprintf(`{"hits": {"total": 12, %s}}`, hits),
+			query: search.Query{Text: "sens", Type: search.ThingType, Offset: 0, Limit: 2},
+			total: 12,
+		},
+	}
+
+	for _, tc := range cases {
+		idx, srv, closer := connect(t, map[string]route{
+			"POST /entities/_search": {status: http.StatusOK, body: tc.body},
+		})
+
+		page, err := idx.Search(owner, tc.query)
+		closer()
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+
+		assert.Equal(t, tc.total, page.Total, fmt.Sprintf("%s: expected %d got %d\n", tc.desc, tc.total, page.Total))
+		assert.Equal(t, 2, len(page.Entities), fmt.Sprintf("%s: expected %d got %d\n", tc.desc, 2, len(page.Entities)))
+		assert.Equal(t, search.Entity{ID: "1", Type: search.ThingType, Owner: owner, Name: "sensor", Tags: []string{"kitchen"}}, page.Entities[0], fmt.Sprintf("%s: unexpected entity %v", tc.desc, page.Entities[0]))
+
+		filter := srv.requests[len(srv.requests)-1].body["query"].(map[string]interface{})["bool"].(map[string]interface{})["filter"].([]interface{})
+		expected := 1
+		if tc.query.Type != "" {
+			expected = 2
+		}
+		assert.Equal(t, expected, len(filter), fmt.Sprintf("%s: expected %d filters got %d\n", tc.desc, expected, len(filter)))
+	}
+}
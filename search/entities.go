@@ -0,0 +1,86 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package search
+
+const (
+	// ThingType is the type of the indexed things.
+	ThingType = "thing"
+
+	// ChannelType is the type of the indexed channels.
+	ChannelType = "channel"
+
+	maxLimit = 100
+)
+
+// Entity represents the indexed thing or channel.
+type Entity struct {
+	ID       string
+	Type     string
+	Owner    string
+	Name     string
+	Tags     []string
+	Metadata map[string]interface{}
+}
+
+// Query represents the search of the owner's entities. Entities matching the
+// text by their name, tags or metadata values are returned, starting with
+// the best matches. The text is matched as typed, i.e. the last word is
+// matched as prefix, and the misspelled words are tolerated.
+type Query struct {
+	Text   string
+	Type   string
+	Offset uint64
+	Limit  uint64
+}
+
+// Validate returns an error if the query is invalid, i.e. if its type is
+// neither empty nor one of the entity types, or its limit is out of range.
+func (q Query) Validate() error {
+	if q.Type != "" && q.Type != ThingType && q.Type != ChannelType {
+		return ErrMalformedEntity
+	}
+
+	if q.Limit == 0 || q.Limit > maxLimit {
+		return ErrMalformedEntity
+	}
+
+	return nil
+}
+
+// EntitiesPage contains page related metadata as well as list of entities
+// that belong to this page.
+type EntitiesPage struct {
+	Total    uint64
+	Offset   uint64
+	Limit    uint64
+	Entities []Entity
+}
+
+// Index specifies the search index API.
+type Index interface {
+	// Save indexes the entity, replacing the one having the same
+	// identifier.
+	Save(Entity) error
+
+	// Update updates the name, tags and metadata of the indexed entity.
+	// The fields left unset keep their values. ErrNotFound is returned if
+	// the entity isn't indexed.
+	Update(Entity) error
+
+	// Remove removes the entity having the provided identifier from the
+	// index.
+	Remove(string) error
+
+	// RemoveAll removes all the entities of the provided owner from the
+	// index.
+	RemoveAll(string) error
+
+	// Search retrieves the subset of the owner's entities matching the
+	// query.
+	Search(string, Query) (EntitiesPage, error)
+}
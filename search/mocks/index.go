@@ -0,0 +1,142 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package mocks
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/mainflux/mainflux/search"
+)
+
+var _ search.Index = (*indexMock)(nil)
+
+type indexMock struct {
+	mu       sync.Mutex
+	entities map[string]search.Entity
+}
+
+// NewIndex creates in-memory search index. Unlike the real index, it matches
+// the query words as prefixes of the entity's words, without tolerating the
+// misspellings.
+func NewIndex() search.Index {
+	return &indexMock{
+		entities: make(map[string]search.Entity),
+	}
+}
+
+func (im *indexMock) Save(entity search.Entity) error {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	im.entities[entity.ID] = entity
+	return nil
+}
+
+func (im *indexMock) Update(entity search.Entity) error {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	e, ok := im.entities[entity.ID]
+	if !ok {
+		return search.ErrNotFound
+	}
+
+	if entity.Name != "" {
+		e.Name = entity.Name
+	}
+	if entity.Tags != nil {
+		e.Tags = entity.Tags
+	}
+	if entity.Metadata != nil {
+		e.Metadata = entity.Metadata
+	}
+
+	im.entities[entity.ID] = e
+	return nil
+}
+
+func (im *indexMock) Remove(id string) error {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	delete(im.entities, id)
+	return nil
+}
+
+func (im *indexMock) RemoveAll(owner string) error {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	for id, e := range im.entities {
+		if e.Owner == owner {
+			delete(im.entities, id)
+		}
+	}
+
+	return nil
+}
+
+func (im *indexMock) Search(owner string, query search.Query) (search.EntitiesPage, error) {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	all := []search.Entity{}
+	for _, e := range im.entities {
+		if e.Owner != owner || query.Type != "" && e.Type != query.Type {
+			continue
+		}
+		if matches(e, query.Text) {
+			all = append(all, e)
+		}
+	}
+
+	sort.SliceStable(all, func(i, j int) bool {
+		return all[i].ID < all[j].ID
+	})
+
+	items := []search.Entity{}
+	if query.Offset < uint64(len(all)) {
+		end := query.Offset + query.Limit
+		if end > uint64(len(all)) {
+			end = uint64(len(all))
+		}
+		items = all[query.Offset:end]
+	}
+
+	return search.EntitiesPage{
+		Total:    uint64(len(all)),
+		Offset:   query.Offset,
+		Limit:    query.Limit,
+		Entities: items,
+	}, nil
+}
+
+func matches(e search.Entity, text string) bool {
+	words := strings.Fields(strings.ToLower(e.Name + " " + strings.Join(e.Tags, " ")))
+	for _, v := range e.Metadata {
+		words = append(words, strings.Fields(strings.ToLower(fmt.Sprint(v)))...)
+	}
+
+	for _, q := range strings.Fields(strings.ToLower(text)) {
+		found := false
+		for _, w := range words {
+			if strings.HasPrefix(w, q) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
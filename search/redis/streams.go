@@ -0,0 +1,112 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package redis
+
+import (
+	"encoding/json"
+
+	"github.com/go-redis/redis"
+	"github.com/mainflux/mainflux/events"
+	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/search"
+)
+
+const (
+	stream = "mainflux.things"
+	group  = "mainflux.search"
+
+	thingPrefix = "thing."
+	thingCreate = thingPrefix + "create"
+	thingUpdate = thingPrefix + "update"
+	thingRemove = thingPrefix + "remove"
+
+	channelPrefix = "channel."
+	channelCreate = channelPrefix + "create"
+	channelUpdate = channelPrefix + "update"
+	channelRemove = channelPrefix + "remove"
+)
+
+// EventStore represents event source for things and channels provisioning.
+type EventStore interface {
+	// Subscribes to given subject and receives events.
+	Subscribe(string) error
+}
+
+type eventStore struct {
+	svc      search.Service
+	client   redis.UniversalClient
+	consumer string
+	logger   logger.Logger
+}
+
+// NewEventStore returns new event store instance.
+func NewEventStore(svc search.Service, client redis.UniversalClient, consumer string, log logger.Logger) EventStore {
+	return eventStore{
+		svc:      svc,
+		client:   client,
+		consumer: consumer,
+		logger:   log,
+	}
+}
+
+// Subscribe indexes the things and channels. The consumer group is created
+// at the beginning of the stream, so that the entities provisioned before
+// the service was deployed are indexed too.
+func (es eventStore) Subscribe(subject string) error {
+	sub := events.NewSubscriber(es.client, events.Config{
+		Stream:   stream,
+		Group:    group,
+		Consumer: es.consumer,
+		Start:    events.Beginning,
+	}, es.logger)
+
+	return sub.Subscribe(events.HandlerFunc(es.handle))
+}
+
+func (es eventStore) handle(event events.Event) error {
+	switch event.Read("operation", "") {
+	case thingCreate:
+		return es.svc.SaveEntityHandler(decodeEntity(search.ThingType, event))
+	case channelCreate:
+		return es.svc.SaveEntityHandler(decodeEntity(search.ChannelType, event))
+	case thingUpdate:
+		return es.svc.UpdateEntityHandler(decodeEntity(search.ThingType, event))
+	case channelUpdate:
+		return es.svc.UpdateEntityHandler(decodeEntity(search.ChannelType, event))
+	case thingRemove, channelRemove:
+		return es.svc.RemoveEntityHandler(event.Read("id", ""))
+	}
+
+	return nil
+}
+
+// decodeEntity decodes the entity from the create or update event. The
+// fields the event doesn't carry are left unset, so that the update keeps
+// their indexed values.
+func decodeEntity(typ string, event events.Event) search.Entity {
+	entity := search.Entity{
+		ID:    event.Read("id", ""),
+		Type:  typ,
+		Owner: event.Read("owner", ""),
+		Name:  event.Read("name", ""),
+	}
+
+	if tags := event.Read("tags", ""); tags != "" {
+		if err := json.Unmarshal([]byte(tags), &entity.Tags); err != nil {
+			entity.Tags = nil
+		}
+	}
+
+	if metadata := event.Read("metadata", ""); metadata != "" {
+		if err := json.Unmarshal([]byte(metadata), &entity.Metadata); err != nil {
+			entity.Metadata = nil
+		}
+	}
+
+	return entity
+}
@@ -0,0 +1,58 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package redis
+
+import (
+	"github.com/go-redis/redis"
+	"github.com/mainflux/mainflux/events"
+	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/search"
+)
+
+const (
+	userPrefix = "user."
+	userRemove = userPrefix + "remove"
+)
+
+type usersEventStore struct {
+	svc      search.Service
+	client   redis.UniversalClient
+	consumer string
+	logger   logger.Logger
+}
+
+// NewUsersEventStore returns new event store instance that consumes users
+// service events.
+func NewUsersEventStore(svc search.Service, client redis.UniversalClient, consumer string, log logger.Logger) EventStore {
+	return usersEventStore{
+		svc:      svc,
+		client:   client,
+		consumer: consumer,
+		logger:   log,
+	}
+}
+
+func (es usersEventStore) Subscribe(stream string) error {
+	sub := events.NewSubscriber(es.client, events.Config{
+		Stream:   stream,
+		Group:    group,
+		Consumer: es.consumer,
+	}, es.logger)
+
+	return sub.Subscribe(events.HandlerFunc(es.handle))
+}
+
+func (es usersEventStore) handle(event events.Event) error {
+	var err error
+	switch event.Read("operation", "") {
+	case userRemove:
+		err = es.svc.RemoveUserHandler(event.Read("email", ""))
+	}
+
+	return err
+}
@@ -0,0 +1,111 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package search
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/mainflux/mainflux"
+)
+
+var (
+	// ErrMalformedEntity indicates malformed entity specification (e.g.
+	// invalid query type or limit).
+	ErrMalformedEntity = errors.New("malformed entity specification")
+
+	// ErrUnauthorizedAccess indicates missing or invalid credentials provided
+	// when accessing a protected resource.
+	ErrUnauthorizedAccess = errors.New("missing or invalid credentials provided")
+
+	// ErrNotFound indicates a non-existent entity request.
+	ErrNotFound = errors.New("non-existent entity")
+)
+
+// Service specifies an API that must be fullfiled by the domain service
+// implementation, and all of its decorators (e.g. logging & metrics).
+type Service interface {
+	// Search retrieves the entities of the user identified by the provided
+	// key that match the query.
+	Search(string, Query) (EntitiesPage, error)
+
+	// SaveEntityHandler indexes the created entity.
+	SaveEntityHandler(Entity) error
+
+	// UpdateEntityHandler updates the indexed entity. The updates of the
+	// entities that aren't indexed are ignored.
+	UpdateEntityHandler(Entity) error
+
+	// RemoveEntityHandler removes the entity from the index.
+	RemoveEntityHandler(string) error
+
+	// RemoveUserHandler removes all the entities of the removed user from
+	// the index.
+	RemoveUserHandler(string) error
+}
+
+var _ Service = (*searchService)(nil)
+
+type searchService struct {
+	users mainflux.UsersServiceClient
+	index Index
+}
+
+// New instantiates the search service implementation.
+func New(users mainflux.UsersServiceClient, index Index) Service {
+	return &searchService{
+		users: users,
+		index: index,
+	}
+}
+
+func (ss *searchService) Search(key string, query Query) (EntitiesPage, error) {
+	if err := query.Validate(); err != nil {
+		return EntitiesPage{}, err
+	}
+
+	owner, err := ss.identify(key)
+	if err != nil {
+		return EntitiesPage{}, err
+	}
+
+	return ss.index.Search(owner, query)
+}
+
+func (ss *searchService) SaveEntityHandler(entity Entity) error {
+	return ss.index.Save(entity)
+}
+
+func (ss *searchService) UpdateEntityHandler(entity Entity) error {
+	if err := ss.index.Update(entity); err != nil && err != ErrNotFound {
+		return err
+	}
+
+	return nil
+}
+
+func (ss *searchService) RemoveEntityHandler(id string) error {
+	return ss.index.Remove(id)
+}
+
+func (ss *searchService) RemoveUserHandler(owner string) error {
+	return ss.index.RemoveAll(owner)
+}
+
+func (ss *searchService) identify(key string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	res, err := ss.users.Identify(ctx, &mainflux.Token{Value: key})
+	if err != nil {
+		return "", ErrUnauthorizedAccess
+	}
+
+	return res.GetValue(), nil
+}
@@ -0,0 +1,203 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package search_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux/search"
+	"github.com/mainflux/mainflux/search/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	validToken   = "validToken"
+	invalidToken = "invalidToken"
+	email        = "user@example.com"
+	otherEmail   = "other@example.com"
+)
+
+var (
+	thing = search.Entity{
+		ID:       "1",
+		Type:     search.ThingType,
+		Owner:    email,
+		Name:     "temperature sensor",
+		Tags:     []string{"kitchen"},
+		Metadata: map[string]interface{}{"model": "ts-100"},
+	}
+	channel = search.Entity{
+		ID:    "2",
+		Type:  search.ChannelType,
+		Owner: email,
+		Name:  "sensors",
+	}
+	other = search.Entity{
+		ID:    "3",
+		Type:  search.ThingType,
+		Owner: otherEmail,
+		Name:  "temperature sensor",
+	}
+)
+
+func newService() search.Service {
+	users := mocks.NewUsersService(map[string]string{validToken: email})
+	return search.New(users, mocks.NewIndex())
+}
+
+func save(t *testing.T, svc search.Service, entities ...search.Entity) {
+	for _, e := range entities {
+		err := svc.SaveEntityHandler(e)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+}
+
+func TestSearch(t *testing.T) {
+	svc := newService()
+	save(t, svc, thing, channel, other)
+
+	cases := []struct {
+		desc  string
+		token string
+		query search.Query
+		ids   []string
+		err   error
+	}{
+		{
+			desc:  "search by name prefix",
+			token: validToken,
+			query: search.Query{Text: "sens", Limit: 10},
+			ids:   []string{"1", "2"},
+			err:   nil,
+		},
+		{
+			desc:  "search by name prefix and type",
+			token: validToken,
+			query: search.Query{Text: "sens", Type: search.ChannelType, Limit: 10},
+			ids:   []string{"2"},
+			err:   nil,
+		},
+		{
+			desc:  "search by tag",
+			token: validToken,
+			query: search.Query{Text: "kitch", Limit: 10},
+			ids:   []string{"1"},
+			err:   nil,
+		},
+		{
+			desc:  "search by metadata value",
+			token: validToken,
+			query: search.Query{Text: "ts-100", Limit: 10},
+			ids:   []string{"1"},
+			err:   nil,
+		},
+		{
+			desc:  "search without text",
+			token: validToken,
+			query: search.Query{Limit: 10},
+			ids:   []string{"1", "2"},
+			err:   nil,
+		},
+		{
+			desc:  "search with invalid type",
+			token: validToken,
+			query: search.Query{Text: "sens", Type: "user", Limit: 10},
+			err:   search.ErrMalformedEntity,
+		},
+		{
+			desc:  "search with zero limit",
+			token: validToken,
+			query: search.Query{Text: "sens"},
+			err:   search.ErrMalformedEntity,
+		},
+		{
+			desc:  "search with too big limit",
+			token: validToken,
+			query: search.Query{Text: "sens", Limit: 101},
+			err:   search.ErrMalformedEntity,
+		},
+		{
+			desc:  "search with invalid token",
+			token: invalidToken,
+			query: search.Query{Text: "sens", Limit: 10},
+			err:   search.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		page, err := svc.Search(tc.token, tc.query)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err != nil {
+			continue
+		}
+
+		ids := []string{}
+		for _, e := range page.Entities {
+			ids = append(ids, e.ID)
+		}
+		assert.Equal(t, tc.ids, ids, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.ids, ids))
+	}
+}
+
+func TestUpdateEntityHandler(t *testing.T) {
+	svc := newService()
+	save(t, svc, thing)
+
+	cases := []struct {
+		desc   string
+		entity search.Entity
+		err    error
+	}{
+		{
+			desc:   "update indexed entity",
+			entity: search.Entity{ID: thing.ID, Name: "humidity sensor"},
+			err:    nil,
+		},
+		{
+			desc:   "update entity that isn't indexed",
+			entity: search.Entity{ID: "non-existing", Name: "humidity sensor"},
+			err:    nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.UpdateEntityHandler(tc.entity)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	page, err := svc.Search(validToken, search.Query{Text: "humid", Limit: 10})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	require.Equal(t, 1, len(page.Entities), fmt.Sprintf("expected %d got %d\n", 1, len(page.Entities)))
+	assert.Equal(t, thing.Tags, page.Entities[0].Tags, fmt.Sprintf("expected unset fields to be kept, got %v\n", page.Entities[0]))
+}
+
+func TestRemoveEntityHandler(t *testing.T) {
+	svc := newService()
+	save(t, svc, thing, channel)
+
+	err := svc.RemoveEntityHandler(thing.ID)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	page, err := svc.Search(validToken, search.Query{Limit: 10})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, uint64(1), page.Total, fmt.Sprintf("expected %d got %d\n", 1, page.Total))
+}
+
+func TestRemoveUserHandler(t *testing.T) {
+	svc := newService()
+	save(t, svc, thing, channel)
+
+	err := svc.RemoveUserHandler(email)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	page, err := svc.Search(validToken, search.Query{Limit: 10})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, uint64(0), page.Total, fmt.Sprintf("expected %d got %d\n", 0, page.Total))
+}
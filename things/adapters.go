@@ -0,0 +1,328 @@
+//
+// Copyright (c) 2018
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package things
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/url"
+
+	"github.com/mainflux/mainflux"
+)
+
+// Metadata keys reserved for the protocol adapters. Values stored under these
+// keys must conform to the corresponding adapter metadata schema.
+const (
+	LoraKey   = "lora"
+	OPCUAKey  = "opcua"
+	ModbusKey = "modbus"
+)
+
+// RateLimitKey is the channel metadata key reserved for the message rate
+// limit, that's enforced by the adapters.
+const RateLimitKey = "rateLimit"
+
+// ValidationKey is the channel metadata key reserved for the SenML
+// validation rules, that are enforced by the normalizer.
+const ValidationKey = "validation"
+
+// DeliveryKey is the channel metadata key reserved for the delivery mode of
+// the channel's messages, that's honored by the adapters, the normalizer
+// and the writers.
+const DeliveryKey = "delivery"
+
+// Normalization modes of the malformed SenML messages. The strict mode stores
+// such messages as dead letters, while the lenient one passes them through
+// as raw, along with the warning.
+const (
+	StrictMode  = "strict"
+	LenientMode = "lenient"
+)
+
+const (
+	devEUILen    = 8
+	maxModbusUID = 247
+)
+
+// ErrMetadataNotFound indicates that the entity metadata doesn't contain
+// the requested adapter section.
+var ErrMetadataNotFound = errors.New("adapter metadata not found")
+
+// LoraThing represents the reserved lora section of the thing metadata.
+type LoraThing struct {
+	DevEUI string `json:"devEUI"`
+}
+
+// LoraChannel represents the reserved lora section of the channel metadata.
+type LoraChannel struct {
+	AppID string `json:"appID"`
+}
+
+// OPCUAThing represents the reserved opcua section of the thing metadata.
+type OPCUAThing struct {
+	NodeID string `json:"nodeID"`
+}
+
+// OPCUAChannel represents the reserved opcua section of the channel metadata.
+type OPCUAChannel struct {
+	ServerURI string `json:"serverURI"`
+}
+
+// ModbusThing represents the reserved modbus section of the thing metadata.
+type ModbusThing struct {
+	UnitID uint8 `json:"unitID"`
+}
+
+// ModbusChannel represents the reserved modbus section of the channel
+// metadata.
+type ModbusChannel struct {
+	Address string `json:"address"`
+}
+
+// RateLimit represents the reserved rateLimit section of the channel
+// metadata. Rate is the number of messages per second the adapters accept on
+// the channel, and Burst the number of messages they accept at once. Burst
+// defaults to one.
+type RateLimit struct {
+	Rate  float64 `json:"rate"`
+	Burst uint    `json:"burst"`
+}
+
+// Validation represents the reserved validation section of the channel
+// metadata. Units lists the SenML units the channel's records can carry,
+// any unit being accepted if it's empty, while Ranges holds the bounds of
+// the values per unit. Records violating the rules are rejected, unless Flag
+// is set, in which case they are only kept apart from the valid ones. Mode,
+// if set, overrides the normalization mode the normalizer is deployed with.
+type Validation struct {
+	Units  []string         `json:"units,omitempty"`
+	Ranges map[string]Range `json:"ranges,omitempty"`
+	Flag   bool             `json:"flag,omitempty"`
+	Mode   string           `json:"mode,omitempty"`
+}
+
+// Delivery represents the reserved delivery section of the channel metadata.
+// Mode is either mainflux.DeliveryAtMostOnce or mainflux.DeliveryAtLeastOnce.
+type Delivery struct {
+	Mode string `json:"mode"`
+}
+
+// Range represents the bounds of the values having the same unit. Missing
+// bound leaves the range open on that side.
+type Range struct {
+	Min *float64 `json:"min,omitempty"`
+	Max *float64 `json:"max,omitempty"`
+}
+
+type adapterMetadata interface {
+	validate() error
+}
+
+func (lt LoraThing) validate() error {
+	eui, err := hex.DecodeString(lt.DevEUI)
+	if err != nil || len(eui) != devEUILen {
+		return ErrMalformedEntity
+	}
+
+	return nil
+}
+
+func (lc LoraChannel) validate() error {
+	if lc.AppID == "" {
+		return ErrMalformedEntity
+	}
+
+	return nil
+}
+
+func (ot OPCUAThing) validate() error {
+	if ot.NodeID == "" {
+		return ErrMalformedEntity
+	}
+
+	return nil
+}
+
+func (oc OPCUAChannel) validate() error {
+	u, err := url.Parse(oc.ServerURI)
+	if err != nil || u.Scheme != "opc.tcp" || u.Host == "" {
+		return ErrMalformedEntity
+	}
+
+	return nil
+}
+
+func (mt ModbusThing) validate() error {
+	if mt.UnitID == 0 || mt.UnitID > maxModbusUID {
+		return ErrMalformedEntity
+	}
+
+	return nil
+}
+
+func (mc ModbusChannel) validate() error {
+	if _, _, err := net.SplitHostPort(mc.Address); err != nil {
+		return ErrMalformedEntity
+	}
+
+	return nil
+}
+
+func (rl RateLimit) validate() error {
+	if rl.Rate <= 0 {
+		return ErrMalformedEntity
+	}
+
+	return nil
+}
+
+func (v Validation) validate() error {
+	if len(v.Units) == 0 && len(v.Ranges) == 0 && v.Mode == "" {
+		return ErrMalformedEntity
+	}
+
+	if v.Mode != "" && v.Mode != StrictMode && v.Mode != LenientMode {
+		return ErrMalformedEntity
+	}
+
+	for _, r := range v.Ranges {
+		if r.Min != nil && r.Max != nil && *r.Min > *r.Max {
+			return ErrMalformedEntity
+		}
+	}
+
+	return nil
+}
+
+func (d Delivery) validate() error {
+	if d.Mode != mainflux.DeliveryAtMostOnce && d.Mode != mainflux.DeliveryAtLeastOnce {
+		return ErrMalformedEntity
+	}
+
+	return nil
+}
+
+// Lora returns the lora section of the thing metadata.
+func (t Thing) Lora() (LoraThing, error) {
+	var lt LoraThing
+	err := decodeSection(t.Metadata, LoraKey, &lt)
+	return lt, err
+}
+
+// OPCUA returns the opcua section of the thing metadata.
+func (t Thing) OPCUA() (OPCUAThing, error) {
+	var ot OPCUAThing
+	err := decodeSection(t.Metadata, OPCUAKey, &ot)
+	return ot, err
+}
+
+// Modbus returns the modbus section of the thing metadata.
+func (t Thing) Modbus() (ModbusThing, error) {
+	var mt ModbusThing
+	err := decodeSection(t.Metadata, ModbusKey, &mt)
+	return mt, err
+}
+
+// Lora returns the lora section of the channel metadata.
+func (c Channel) Lora() (LoraChannel, error) {
+	var lc LoraChannel
+	err := decodeSection(c.Metadata, LoraKey, &lc)
+	return lc, err
+}
+
+// OPCUA returns the opcua section of the channel metadata.
+func (c Channel) OPCUA() (OPCUAChannel, error) {
+	var oc OPCUAChannel
+	err := decodeSection(c.Metadata, OPCUAKey, &oc)
+	return oc, err
+}
+
+// Modbus returns the modbus section of the channel metadata.
+func (c Channel) Modbus() (ModbusChannel, error) {
+	var mc ModbusChannel
+	err := decodeSection(c.Metadata, ModbusKey, &mc)
+	return mc, err
+}
+
+// RateLimit returns the rateLimit section of the channel metadata.
+func (c Channel) RateLimit() (RateLimit, error) {
+	var rl RateLimit
+	err := decodeSection(c.Metadata, RateLimitKey, &rl)
+	return rl, err
+}
+
+// Validation returns the validation section of the channel metadata.
+func (c Channel) Validation() (Validation, error) {
+	var v Validation
+	err := decodeSection(c.Metadata, ValidationKey, &v)
+	return v, err
+}
+
+// Delivery returns the delivery section of the channel metadata.
+func (c Channel) Delivery() (Delivery, error) {
+	var d Delivery
+	err := decodeSection(c.Metadata, DeliveryKey, &d)
+	return d, err
+}
+
+func validateThingMetadata(metadata map[string]interface{}) error {
+	return validateSections(metadata, map[string]adapterMetadata{
+		LoraKey:      &LoraThing{},
+		OPCUAKey:     &OPCUAThing{},
+		ModbusKey:    &ModbusThing{},
+		TransformKey: &Transform{},
+	})
+}
+
+func validateChannelMetadata(metadata map[string]interface{}) error {
+	return validateSections(metadata, map[string]adapterMetadata{
+		LoraKey:       &LoraChannel{},
+		OPCUAKey:      &OPCUAChannel{},
+		ModbusKey:     &ModbusChannel{},
+		RateLimitKey:  &RateLimit{},
+		ValidationKey: &Validation{},
+		DeliveryKey:   &Delivery{},
+		TransformKey:  &Transform{},
+	})
+}
+
+func validateSections(metadata map[string]interface{}, sections map[string]adapterMetadata) error {
+	for key, section := range sections {
+		if err := decodeSection(metadata, key, section); err != nil && err != ErrMetadataNotFound {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// decodeSection decodes the reserved metadata section into the provided
+// adapter metadata and validates it.
+func decodeSection(metadata map[string]interface{}, key string, section adapterMetadata) error {
+	val, ok := metadata[key]
+	if !ok || val == nil {
+		return ErrMetadataNotFound
+	}
+
+	if _, ok := val.(map[string]interface{}); !ok {
+		return ErrMalformedEntity
+	}
+
+	data, err := json.Marshal(val)
+	if err != nil {
+		return ErrMalformedEntity
+	}
+
+	if err := json.Unmarshal(data, section); err != nil {
+		return ErrMalformedEntity
+	}
+
+	return section.validate()
+}
@@ -0,0 +1,230 @@
+//
+// Copyright (c) 2018
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package things_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/things"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThingAdapterMetadata(t *testing.T) {
+	cases := []struct {
+		desc     string
+		metadata map[string]interface{}
+		lora     things.LoraThing
+		modbus   things.ModbusThing
+		err      error
+	}{
+		{
+			desc: "retrieve valid adapter metadata",
+			metadata: map[string]interface{}{
+				"lora":   map[string]interface{}{"devEUI": "0123456789abcdef"},
+				"modbus": map[string]interface{}{"unitID": 17},
+			},
+			lora:   things.LoraThing{DevEUI: "0123456789abcdef"},
+			modbus: things.ModbusThing{UnitID: 17},
+			err:    nil,
+		},
+		{
+			desc:     "retrieve missing adapter metadata",
+			metadata: map[string]interface{}{"model": "sensor"},
+			err:      things.ErrMetadataNotFound,
+		},
+		{
+			desc:     "retrieve adapter metadata of wrong type",
+			metadata: map[string]interface{}{"lora": "0123456789abcdef", "modbus": []interface{}{17}},
+			err:      things.ErrMalformedEntity,
+		},
+		{
+			desc: "retrieve malformed adapter metadata",
+			metadata: map[string]interface{}{
+				"lora":   map[string]interface{}{"devEUI": "0123"},
+				"modbus": map[string]interface{}{"unitID": 300},
+			},
+			err: things.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		th := things.Thing{Metadata: tc.metadata}
+
+		lora, err := th.Lora()
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err == nil {
+			assert.Equal(t, tc.lora, lora, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.lora, lora))
+		}
+
+		modbus, err := th.Modbus()
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err == nil {
+			assert.Equal(t, tc.modbus, modbus, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.modbus, modbus))
+		}
+	}
+}
+
+func TestChannelAdapterMetadata(t *testing.T) {
+	minTemp, maxTemp := -50.0, 150.0
+
+	cases := []struct {
+		desc     string
+		metadata map[string]interface{}
+		opcua    things.OPCUAChannel
+		modbus   things.ModbusChannel
+		limit    things.RateLimit
+		rules    things.Validation
+		err      error
+	}{
+		{
+			desc: "retrieve valid adapter metadata",
+			metadata: map[string]interface{}{
+				"opcua":     map[string]interface{}{"serverURI": "opc.tcp://localhost:4840"},
+				"modbus":    map[string]interface{}{"address": "localhost:502"},
+				"rateLimit": map[string]interface{}{"rate": 0.5, "burst": 5},
+				"validation": map[string]interface{}{
+					"units":  []string{"Cel"},
+					"ranges": map[string]interface{}{"Cel": map[string]interface{}{"min": -50, "max": 150}},
+					"mode":   "lenient",
+				},
+			},
+			opcua:  things.OPCUAChannel{ServerURI: "opc.tcp://localhost:4840"},
+			modbus: things.ModbusChannel{Address: "localhost:502"},
+			limit:  things.RateLimit{Rate: 0.5, Burst: 5},
+			rules: things.Validation{
+				Units:  []string{"Cel"},
+				Ranges: map[string]things.Range{"Cel": {Min: &minTemp, Max: &maxTemp}},
+				Mode:   things.LenientMode,
+			},
+			err: nil,
+		},
+		{
+			desc:     "retrieve missing adapter metadata",
+			metadata: map[string]interface{}{},
+			err:      things.ErrMetadataNotFound,
+		},
+		{
+			desc: "retrieve malformed adapter metadata",
+			metadata: map[string]interface{}{
+				"opcua":     map[string]interface{}{"serverURI": "http://localhost:4840"},
+				"modbus":    map[string]interface{}{"address": "localhost"},
+				"rateLimit": map[string]interface{}{"rate": 0},
+				"validation": map[string]interface{}{
+					"ranges": map[string]interface{}{"Cel": map[string]interface{}{"min": 150, "max": -50}},
+				},
+			},
+			err: things.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		ch := things.Channel{Metadata: tc.metadata}
+
+		opcua, err := ch.OPCUA()
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err == nil {
+			assert.Equal(t, tc.opcua, opcua, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.opcua, opcua))
+		}
+
+		modbus, err := ch.Modbus()
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err == nil {
+			assert.Equal(t, tc.modbus, modbus, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.modbus, modbus))
+		}
+
+		limit, err := ch.RateLimit()
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err == nil {
+			assert.Equal(t, tc.limit, limit, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.limit, limit))
+		}
+
+		rules, err := ch.Validation()
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err == nil {
+			assert.Equal(t, tc.rules, rules, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.rules, rules))
+		}
+	}
+}
+
+func TestValidationMode(t *testing.T) {
+	cases := []struct {
+		desc  string
+		rules map[string]interface{}
+		mode  string
+		err   error
+	}{
+		{
+			desc:  "retrieve validation overriding mode only",
+			rules: map[string]interface{}{"mode": "strict"},
+			mode:  things.StrictMode,
+			err:   nil,
+		},
+		{
+			desc:  "retrieve validation having unknown mode",
+			rules: map[string]interface{}{"units": []string{"Cel"}, "mode": "loose"},
+			err:   things.ErrMalformedEntity,
+		},
+		{
+			desc:  "retrieve empty validation",
+			rules: map[string]interface{}{"flag": true},
+			err:   things.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		ch := things.Channel{Metadata: map[string]interface{}{"validation": tc.rules}}
+		rules, err := ch.Validation()
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err == nil {
+			assert.Equal(t, tc.mode, rules.Mode, fmt.Sprintf("%s: expected mode %s got %s\n", tc.desc, tc.mode, rules.Mode))
+		}
+	}
+}
+
+func TestDeliveryMode(t *testing.T) {
+	cases := []struct {
+		desc     string
+		delivery map[string]interface{}
+		mode     string
+		err      error
+	}{
+		{
+			desc:     "retrieve at-most-once delivery",
+			delivery: map[string]interface{}{"mode": mainflux.DeliveryAtMostOnce},
+			mode:     mainflux.DeliveryAtMostOnce,
+			err:      nil,
+		},
+		{
+			desc:     "retrieve at-least-once delivery",
+			delivery: map[string]interface{}{"mode": mainflux.DeliveryAtLeastOnce},
+			mode:     mainflux.DeliveryAtLeastOnce,
+			err:      nil,
+		},
+		{
+			desc:     "retrieve delivery having unknown mode",
+			delivery: map[string]interface{}{"mode": "exactly-once"},
+			err:      things.ErrMalformedEntity,
+		},
+		{
+			desc:     "retrieve delivery without mode",
+			delivery: map[string]interface{}{},
+			err:      things.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		ch := things.Channel{Metadata: map[string]interface{}{"delivery": tc.delivery}}
+		d, err := ch.Delivery()
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err == nil {
+			assert.Equal(t, tc.mode, d.Mode, fmt.Sprintf("%s: expected mode %s got %s\n", tc.desc, tc.mode, d.Mode))
+		}
+	}
+}
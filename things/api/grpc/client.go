@@ -18,8 +18,11 @@ import (
 var _ mainflux.ThingsServiceClient = (*grpcClient)(nil)
 
 type grpcClient struct {
-	canAccess endpoint.Endpoint
-	identify  endpoint.Endpoint
+	canAccess     endpoint.Endpoint
+	identify      endpoint.Endpoint
+	identifyBatch endpoint.Endpoint
+	canRead       endpoint.Endpoint
+	connected     endpoint.Endpoint
 }
 
 // NewClient returns new gRPC client instance.
@@ -43,11 +46,35 @@ func NewClient(conn *grpc.ClientConn) mainflux.ThingsServiceClient {
 			decodeIdentityResponse,
 			mainflux.ThingID{},
 		).Endpoint(),
+		identifyBatch: kitgrpc.NewClient(
+			conn,
+			svcName,
+			"IdentifyBatch",
+			encodeIdentifyBatchRequest,
+			decodeIdentifyBatchResponse,
+			mainflux.ThingIDs{},
+		).Endpoint(),
+		canRead: kitgrpc.NewClient(
+			conn,
+			svcName,
+			"CanRead",
+			encodeCanReadRequest,
+			decodeUserIdentityResponse,
+			mainflux.UserID{},
+		).Endpoint(),
+		connected: kitgrpc.NewClient(
+			conn,
+			svcName,
+			"ListChannelsByThing",
+			encodeListChannelsByThingRequest,
+			decodeListChannelsByThingResponse,
+			mainflux.ChannelIDs{},
+		).Endpoint(),
 	}
 }
 
 func (client grpcClient) CanAccess(ctx context.Context, req *mainflux.AccessReq, _ ...grpc.CallOption) (*mainflux.ThingID, error) {
-	ar := accessReq{thingKey: req.GetToken(), chanID: req.GetChanID()}
+	ar := accessReq{thingKey: req.GetToken(), chanID: req.GetChanID(), action: req.GetAction(), subtopic: req.GetSubtopic()}
 	res, err := client.canAccess(ctx, ar)
 	if err != nil {
 		return nil, err
@@ -67,9 +94,40 @@ func (client grpcClient) Identify(ctx context.Context, req *mainflux.Token, _ ..
 	return &mainflux.ThingID{Value: ir.id}, ir.err
 }
 
+func (client grpcClient) IdentifyBatch(ctx context.Context, req *mainflux.Tokens, _ ...grpc.CallOption) (*mainflux.ThingIDs, error) {
+	res, err := client.identifyBatch(ctx, identifyBatchReq{keys: req.GetValues()})
+	if err != nil {
+		return nil, err
+	}
+
+	ir := res.(identityBatchRes)
+	return &mainflux.ThingIDs{Values: ir.ids}, ir.err
+}
+
+func (client grpcClient) CanRead(ctx context.Context, req *mainflux.AccessReq, _ ...grpc.CallOption) (*mainflux.UserID, error) {
+	rr := readReq{token: req.GetToken(), chanID: req.GetChanID()}
+	res, err := client.canRead(ctx, rr)
+	if err != nil {
+		return nil, err
+	}
+
+	ir := res.(identityRes)
+	return &mainflux.UserID{Value: ir.id}, ir.err
+}
+
+func (client grpcClient) ListChannelsByThing(ctx context.Context, req *mainflux.ThingID, _ ...grpc.CallOption) (*mainflux.ChannelIDs, error) {
+	res, err := client.connected(ctx, connectedReq{thingID: req.GetValue()})
+	if err != nil {
+		return nil, err
+	}
+
+	cr := res.(connectedRes)
+	return &mainflux.ChannelIDs{Values: cr.chanIDs}, cr.err
+}
+
 func encodeCanAccessRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
 	req := grpcReq.(accessReq)
-	return &mainflux.AccessReq{Token: req.thingKey, ChanID: req.chanID}, nil
+	return &mainflux.AccessReq{Token: req.thingKey, ChanID: req.chanID, Action: req.action, Subtopic: req.subtopic}, nil
 }
 
 func encodeIdentifyRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
@@ -77,7 +135,37 @@ func encodeIdentifyRequest(_ context.Context, grpcReq interface{}) (interface{},
 	return &mainflux.Token{Value: req.key}, nil
 }
 
+func encodeIdentifyBatchRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(identifyBatchReq)
+	return &mainflux.Tokens{Values: req.keys}, nil
+}
+
+func encodeCanReadRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(readReq)
+	return &mainflux.AccessReq{Token: req.token, ChanID: req.chanID}, nil
+}
+
+func encodeListChannelsByThingRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(connectedReq)
+	return &mainflux.ThingID{Value: req.thingID}, nil
+}
+
 func decodeIdentityResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
 	res := grpcRes.(*mainflux.ThingID)
 	return identityRes{id: res.GetValue(), err: nil}, nil
 }
+
+func decodeIdentifyBatchResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
+	res := grpcRes.(*mainflux.ThingIDs)
+	return identityBatchRes{ids: res.GetValues(), err: nil}, nil
+}
+
+func decodeUserIdentityResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
+	res := grpcRes.(*mainflux.UserID)
+	return identityRes{id: res.GetValue(), err: nil}, nil
+}
+
+func decodeListChannelsByThingResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
+	res := grpcRes.(*mainflux.ChannelIDs)
+	return connectedRes{chanIDs: res.GetValues(), err: nil}, nil
+}
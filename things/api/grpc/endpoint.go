@@ -20,7 +20,22 @@ func canAccessEndpoint(svc things.Service) endpoint.Endpoint {
 			return nil, err
 		}
 
-		id, err := svc.CanAccess(req.chanID, req.thingKey)
+		id, err := svc.CanAccess(ctx, req.chanID, req.thingKey, req.action, req.subtopic)
+		if err != nil {
+			return identityRes{err: err}, err
+		}
+		return identityRes{id: id, err: nil}, nil
+	}
+}
+
+func canReadEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(readReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		id, err := svc.CanRead(ctx, req.chanID, req.token)
 		if err != nil {
 			return identityRes{err: err}, err
 		}
@@ -31,10 +46,40 @@ func canAccessEndpoint(svc things.Service) endpoint.Endpoint {
 func identifyEndpoint(svc things.Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(identifyReq)
-		id, err := svc.Identify(req.key)
+		id, err := svc.Identify(ctx, req.key)
 		if err != nil {
 			return identityRes{err: err}, err
 		}
 		return identityRes{id: id, err: nil}, nil
 	}
 }
+
+func identifyBatchEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(identifyBatchReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		ids, err := svc.IdentifyBatch(ctx, req.keys)
+		if err != nil {
+			return identityBatchRes{err: err}, err
+		}
+		return identityBatchRes{ids: ids, err: nil}, nil
+	}
+}
+
+func listChannelsByThingEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(connectedReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		chanIDs, err := svc.ConnectedChannels(ctx, req.thingID)
+		if err != nil {
+			return connectedRes{err: err}, err
+		}
+		return connectedRes{chanIDs: chanIDs, err: nil}, nil
+	}
+}
@@ -30,10 +30,10 @@ var (
 )
 
 func TestCanAccess(t *testing.T) {
-	oth, _ := svc.AddThing(token, thing)
-	cth, _ := svc.AddThing(token, thing)
-	sch, _ := svc.CreateChannel(token, channel)
-	svc.Connect(token, sch.ID, cth.ID)
+	oth, _ := svc.AddThing(context.Background(), token, thing)
+	cth, _ := svc.AddThing(context.Background(), token, thing)
+	sch, _ := svc.CreateChannel(context.Background(), token, channel)
+	svc.Connect(context.Background(), token, []string{sch.ID}, []string{cth.ID}, things.FullAccess)
 
 	usersAddr := fmt.Sprintf("localhost:%d", port)
 	conn, _ := grpc.Dial(usersAddr, grpc.WithInsecure())
@@ -71,6 +71,12 @@ func TestCanAccess(t *testing.T) {
 			thingID: wrongID,
 			code:    codes.InvalidArgument,
 		},
+		"check if connected thing can access channel with malformed id": {
+			key:     cth.Key,
+			chanID:  wrong,
+			thingID: wrongID,
+			code:    codes.InvalidArgument,
+		},
 	}
 
 	for desc, tc := range cases {
@@ -82,8 +88,112 @@ func TestCanAccess(t *testing.T) {
 	}
 }
 
+func TestCanRead(t *testing.T) {
+	sch, _ := svc.CreateChannel(context.Background(), token, channel)
+
+	usersAddr := fmt.Sprintf("localhost:%d", port)
+	conn, _ := grpc.Dial(usersAddr, grpc.WithInsecure())
+	cli := grpcapi.NewClient(conn)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	cases := map[string]struct {
+		token  string
+		chanID string
+		userID string
+		code   codes.Code
+	}{
+		"check if owner can read existing channel": {
+			token:  token,
+			chanID: sch.ID,
+			userID: email,
+			code:   codes.OK,
+		},
+		"check if user with wrong token can read existing channel": {
+			token:  wrong,
+			chanID: sch.ID,
+			userID: wrongID,
+			code:   codes.PermissionDenied,
+		},
+		"check if owner can read non-existent channel": {
+			token:  token,
+			chanID: "123e4567-e89b-12d3-a456-999999999999",
+			userID: wrongID,
+			code:   codes.PermissionDenied,
+		},
+		"check if owner can read channel with malformed id": {
+			token:  token,
+			chanID: wrong,
+			userID: wrongID,
+			code:   codes.InvalidArgument,
+		},
+		"check if owner can read channel with empty id": {
+			token:  token,
+			chanID: wrongID,
+			userID: wrongID,
+			code:   codes.InvalidArgument,
+		},
+	}
+
+	for desc, tc := range cases {
+		id, err := cli.CanRead(ctx, &mainflux.AccessReq{Token: tc.token, ChanID: tc.chanID})
+		e, ok := status.FromError(err)
+		assert.True(t, ok, "OK expected to be true")
+		assert.Equal(t, tc.userID, id.GetValue(), fmt.Sprintf("%s: expected %s got %s", desc, tc.userID, id.GetValue()))
+		assert.Equal(t, tc.code, e.Code(), fmt.Sprintf("%s: expected %s got %s", desc, tc.code, e.Code()))
+	}
+}
+
+func TestListChannelsByThing(t *testing.T) {
+	cth, _ := svc.AddThing(context.Background(), token, thing)
+	uth, _ := svc.AddThing(context.Background(), token, thing)
+	sch, _ := svc.CreateChannel(context.Background(), token, channel)
+	svc.Connect(context.Background(), token, []string{sch.ID}, []string{cth.ID}, things.FullAccess)
+
+	usersAddr := fmt.Sprintf("localhost:%d", port)
+	conn, _ := grpc.Dial(usersAddr, grpc.WithInsecure())
+	cli := grpcapi.NewClient(conn)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	cases := map[string]struct {
+		thingID string
+		chanIDs []string
+		code    codes.Code
+	}{
+		"list channels of connected thing": {
+			thingID: cth.ID,
+			chanIDs: []string{sch.ID},
+			code:    codes.OK,
+		},
+		"list channels of unconnected thing": {
+			thingID: uth.ID,
+			chanIDs: nil,
+			code:    codes.OK,
+		},
+		"list channels of thing with empty id": {
+			thingID: wrongID,
+			chanIDs: nil,
+			code:    codes.InvalidArgument,
+		},
+		"list channels of thing with malformed id": {
+			thingID: wrong,
+			chanIDs: nil,
+			code:    codes.InvalidArgument,
+		},
+	}
+
+	for desc, tc := range cases {
+		res, err := cli.ListChannelsByThing(ctx, &mainflux.ThingID{Value: tc.thingID})
+		e, ok := status.FromError(err)
+		assert.True(t, ok, "OK expected to be true")
+		assert.Equal(t, tc.chanIDs, res.GetValues(), fmt.Sprintf("%s: expected %v got %v", desc, tc.chanIDs, res.GetValues()))
+		assert.Equal(t, tc.code, e.Code(), fmt.Sprintf("%s: expected %s got %s", desc, tc.code, e.Code()))
+	}
+}
+
 func TestIdentify(t *testing.T) {
-	sth, _ := svc.AddThing(token, thing)
+	sth, _ := svc.AddThing(context.Background(), token, thing)
 
 	usersAddr := fmt.Sprintf("localhost:%d", port)
 	conn, _ := grpc.Dial(usersAddr, grpc.WithInsecure())
@@ -116,3 +226,44 @@ func TestIdentify(t *testing.T) {
 		assert.Equal(t, tc.code, e.Code(), fmt.Sprintf("%s: expected %s got %s", desc, tc.code, e.Code()))
 	}
 }
+
+func TestIdentifyBatch(t *testing.T) {
+	sth1, _ := svc.AddThing(context.Background(), token, thing)
+	sth2, _ := svc.AddThing(context.Background(), token, thing)
+
+	usersAddr := fmt.Sprintf("localhost:%d", port)
+	conn, _ := grpc.Dial(usersAddr, grpc.WithInsecure())
+	cli := grpcapi.NewClient(conn)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	cases := map[string]struct {
+		keys []string
+		ids  []string
+		code codes.Code
+	}{
+		"identify batch of existing things": {
+			keys: []string{sth1.Key, sth2.Key},
+			ids:  []string{sth1.ID, sth2.ID},
+			code: codes.OK,
+		},
+		"identify batch with non-existent thing": {
+			keys: []string{wrong, sth2.Key},
+			ids:  []string{wrongID, sth2.ID},
+			code: codes.OK,
+		},
+		"identify empty batch": {
+			keys: []string{},
+			ids:  nil,
+			code: codes.InvalidArgument,
+		},
+	}
+
+	for desc, tc := range cases {
+		ids, err := cli.IdentifyBatch(ctx, &mainflux.Tokens{Values: tc.keys})
+		e, ok := status.FromError(err)
+		assert.True(t, ok, "OK expected to be true")
+		assert.Equal(t, tc.ids, ids.GetValues(), fmt.Sprintf("%s: expected %v got %v", desc, tc.ids, ids.GetValues()))
+		assert.Equal(t, tc.code, e.Code(), fmt.Sprintf("%s: expected %s got %s", desc, tc.code, e.Code()))
+	}
+}
@@ -12,6 +12,8 @@ import "github.com/mainflux/mainflux/things"
 type accessReq struct {
 	thingKey string
 	chanID   string
+	action   string
+	subtopic string
 }
 
 func (req accessReq) validate() error {
@@ -21,6 +23,40 @@ func (req accessReq) validate() error {
 	return nil
 }
 
+type readReq struct {
+	token  string
+	chanID string
+}
+
+func (req readReq) validate() error {
+	if req.chanID == "" || req.token == "" {
+		return things.ErrMalformedEntity
+	}
+	return nil
+}
+
 type identifyReq struct {
 	key string
 }
+
+type identifyBatchReq struct {
+	keys []string
+}
+
+func (req identifyBatchReq) validate() error {
+	if len(req.keys) == 0 {
+		return things.ErrMalformedEntity
+	}
+	return nil
+}
+
+type connectedReq struct {
+	thingID string
+}
+
+func (req connectedReq) validate() error {
+	if req.thingID == "" {
+		return things.ErrMalformedEntity
+	}
+	return nil
+}
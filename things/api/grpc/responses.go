@@ -11,3 +11,13 @@ type identityRes struct {
 	id  string
 	err error
 }
+
+type identityBatchRes struct {
+	ids []string
+	err error
+}
+
+type connectedRes struct {
+	chanIDs []string
+	err     error
+}
@@ -10,6 +10,7 @@ package grpc
 import (
 	kitgrpc "github.com/go-kit/kit/transport/grpc"
 	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/identifiers"
 	"github.com/mainflux/mainflux/things"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc/codes"
@@ -19,8 +20,11 @@ import (
 var _ mainflux.ThingsServiceServer = (*grpcServer)(nil)
 
 type grpcServer struct {
-	canAccess kitgrpc.Handler
-	identify  kitgrpc.Handler
+	canAccess     kitgrpc.Handler
+	identify      kitgrpc.Handler
+	identifyBatch kitgrpc.Handler
+	canRead       kitgrpc.Handler
+	connected     kitgrpc.Handler
 }
 
 // NewServer returns new ThingsServiceServer instance.
@@ -36,6 +40,21 @@ func NewServer(svc things.Service) mainflux.ThingsServiceServer {
 			decodeIdentifyRequest,
 			encodeIdentityResponse,
 		),
+		identifyBatch: kitgrpc.NewServer(
+			identifyBatchEndpoint(svc),
+			decodeIdentifyBatchRequest,
+			encodeIdentifyBatchResponse,
+		),
+		canRead: kitgrpc.NewServer(
+			canReadEndpoint(svc),
+			decodeCanReadRequest,
+			encodeUserIdentityResponse,
+		),
+		connected: kitgrpc.NewServer(
+			listChannelsByThingEndpoint(svc),
+			decodeListChannelsByThingRequest,
+			encodeListChannelsByThingResponse,
+		),
 	}
 }
 
@@ -57,9 +76,41 @@ func (gs *grpcServer) Identify(ctx context.Context, req *mainflux.Token) (*mainf
 	return res.(*mainflux.ThingID), nil
 }
 
+func (gs *grpcServer) IdentifyBatch(ctx context.Context, req *mainflux.Tokens) (*mainflux.ThingIDs, error) {
+	_, res, err := gs.identifyBatch.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, encodeError(err)
+	}
+
+	return res.(*mainflux.ThingIDs), nil
+}
+
+func (gs *grpcServer) CanRead(ctx context.Context, req *mainflux.AccessReq) (*mainflux.UserID, error) {
+	_, res, err := gs.canRead.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, encodeError(err)
+	}
+
+	return res.(*mainflux.UserID), nil
+}
+
+func (gs *grpcServer) ListChannelsByThing(ctx context.Context, req *mainflux.ThingID) (*mainflux.ChannelIDs, error) {
+	_, res, err := gs.connected.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, encodeError(err)
+	}
+
+	return res.(*mainflux.ChannelIDs), nil
+}
+
 func decodeCanAccessRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
 	req := grpcReq.(*mainflux.AccessReq)
-	return accessReq{thingKey: req.GetToken(), chanID: req.GetChanID()}, nil
+	chanID, err := identifiers.Parse(req.GetChanID())
+	if err != nil {
+		return nil, err
+	}
+
+	return accessReq{thingKey: req.GetToken(), chanID: chanID, action: req.GetAction(), subtopic: req.GetSubtopic()}, nil
 }
 
 func decodeIdentifyRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
@@ -67,19 +118,63 @@ func decodeIdentifyRequest(_ context.Context, grpcReq interface{}) (interface{},
 	return identifyReq{key: req.GetValue()}, nil
 }
 
+func decodeIdentifyBatchRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*mainflux.Tokens)
+	return identifyBatchReq{keys: req.GetValues()}, nil
+}
+
+func decodeCanReadRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*mainflux.AccessReq)
+	chanID, err := identifiers.Parse(req.GetChanID())
+	if err != nil {
+		return nil, err
+	}
+
+	return readReq{token: req.GetToken(), chanID: chanID}, nil
+}
+
+func decodeListChannelsByThingRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*mainflux.ThingID)
+	thingID, err := identifiers.Parse(req.GetValue())
+	if err != nil {
+		return nil, err
+	}
+
+	return connectedReq{thingID: thingID}, nil
+}
+
 func encodeIdentityResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
 	res := grpcRes.(identityRes)
 	return &mainflux.ThingID{Value: res.id}, encodeError(res.err)
 }
 
+func encodeIdentifyBatchResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
+	res := grpcRes.(identityBatchRes)
+	return &mainflux.ThingIDs{Values: res.ids}, encodeError(res.err)
+}
+
+func encodeUserIdentityResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
+	res := grpcRes.(identityRes)
+	return &mainflux.UserID{Value: res.id}, encodeError(res.err)
+}
+
+func encodeListChannelsByThingResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
+	res := grpcRes.(connectedRes)
+	return &mainflux.ChannelIDs{Values: res.chanIDs}, encodeError(res.err)
+}
+
 func encodeError(err error) error {
 	switch err {
 	case nil:
 		return nil
 	case things.ErrMalformedEntity:
 		return status.Error(codes.InvalidArgument, "received invalid can access request")
+	case identifiers.ErrMalformedID:
+		return status.Error(codes.InvalidArgument, "received malformed identifier")
 	case things.ErrUnauthorizedAccess:
 		return status.Error(codes.PermissionDenied, "missing or invalid credentials provided")
+	case things.ErrNotFound:
+		return status.Error(codes.NotFound, "entity does not exist")
 	default:
 		return status.Error(codes.Internal, "internal server error")
 	}
@@ -48,9 +48,25 @@ func newService(tokens map[string]string) things.Service {
 	conns := make(chan mocks.Connection)
 	thingsRepo := mocks.NewThingRepository(conns)
 	channelsRepo := mocks.NewChannelRepository(thingsRepo, conns)
+	reservationsRepo := mocks.NewReservationRepository()
+	usageRepo := mocks.NewUsageRepository()
+	historyRepo := mocks.NewHistoryRepository()
+	auditRepo := mocks.NewAuditRepository()
 	chanCache := mocks.NewChannelCache()
 	thingCache := mocks.NewThingCache()
 	idp := mocks.NewIdentityProvider()
+	onboarding := mocks.NewOnboardingProvider()
+	keys := mocks.NewKeyProvider()
+	revocations := mocks.NewRevocationRepository()
+	templates := mocks.NewTemplateRepository()
+	groups := mocks.NewGroupRepository(thingsRepo, channelsRepo)
+	subtopics := mocks.NewSubtopicRepository()
+	observations := mocks.NewObservationRepository()
+	thingKeys := mocks.NewThingKeyRepository(thingsRepo, channelsRepo)
+	shares := mocks.NewShareRepository()
+	profiles := mocks.NewProfileRepository()
+	rules := mocks.NewSubtopicRuleRepository()
+	health := mocks.NewHealthRepository()
 
-	return things.New(users, thingsRepo, channelsRepo, chanCache, thingCache, idp)
+	return things.New(users, thingsRepo, channelsRepo, reservationsRepo, usageRepo, historyRepo, auditRepo, chanCache, thingCache, idp, onboarding, keys, revocations, templates, groups, subtopics, observations, thingKeys, shares, profiles, rules, health, mocks.NewChannelTokenRepository(channelsRepo), mocks.NewOverrideProvider(nil), things.Limits{}, 0)
 }
@@ -8,14 +8,22 @@
 package http
 
 import (
+	"bytes"
 	"context"
+	"encoding/csv"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-kit/kit/endpoint"
 	"github.com/mainflux/mainflux/things"
 )
 
+const defUsagePeriod = 30 * 24 * time.Hour
+
 func addThingEndpoint(svc things.Service) endpoint.Endpoint {
-	return func(_ context.Context, request interface{}) (interface{}, error) {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(addThingReq)
 
 		if err := req.validate(); err != nil {
@@ -23,25 +31,197 @@ func addThingEndpoint(svc things.Service) endpoint.Endpoint {
 		}
 
 		thing := things.Thing{
-			Key:      req.Key,
-			Name:     req.Name,
-			Metadata: req.Metadata,
+			ExternalID: req.ExternalID,
+			Key:        req.Key,
+			Name:       req.Name,
+			Tags:       req.Tags,
+			Metadata:   req.Metadata,
 		}
-		saved, err := svc.AddThing(req.token, thing)
+		top, err := svc.ProvisionThing(ctx, req.token, req.ProfileID, thing)
 		if err != nil {
 			return nil, err
 		}
 
-		res := thingRes{
-			id:      saved.ID,
-			created: true,
+		if top.Existing {
+			return toThingRes(top.Thing, nil), nil
+		}
+
+		if len(top.Channels) == 0 && len(top.Connected) == 0 {
+			res := thingRes{
+				id:      top.Thing.ID,
+				created: true,
+			}
+			return res, nil
+		}
+
+		res := topologyRes{
+			Thing:    toThingRes(top.Thing, nil),
+			Channels: []viewChannelRes{},
+		}
+		for _, channel := range append(top.Channels, top.Connected...) {
+			res.Channels = append(res.Channels, toChannelRes(channel, nil))
+		}
+
+		return res, nil
+	}
+}
+
+func createThingsEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(createThingsReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		ths := []things.Thing{}
+		for _, t := range req.Things {
+			ths = append(ths, things.Thing{
+				ExternalID: t.ExternalID,
+				Key:        t.Key,
+				Name:       t.Name,
+				Tags:       t.Tags,
+				Metadata:   t.Metadata,
+			})
+		}
+
+		saved, err := svc.CreateThings(ctx, req.token, ths...)
+		if err != nil {
+			return nil, err
+		}
+
+		res := createThingsRes{Things: []viewThingRes{}}
+		for _, thing := range saved {
+			res.Things = append(res.Things, toThingRes(thing, nil))
+		}
+
+		return res, nil
+	}
+}
+
+func saveTemplateEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(saveTemplateReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		tmpl := things.Template{}
+		for _, ct := range req.Channels {
+			tmpl.Channels = append(tmpl.Channels, things.ChannelTemplate(ct))
+		}
+
+		if err := svc.SaveTemplate(ctx, req.token, tmpl); err != nil {
+			return nil, err
+		}
+
+		return saveTemplateRes{}, nil
+	}
+}
+
+func viewTemplateEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(templateReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		tmpl, err := svc.ViewTemplate(ctx, req.token)
+		if err != nil {
+			return nil, err
+		}
+
+		res := templateRes{Channels: []channelTemplateRes{}}
+		for _, ct := range tmpl.Channels {
+			res.Channels = append(res.Channels, channelTemplateRes(ct))
+		}
+
+		return res, nil
+	}
+}
+
+func removeTemplateEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(templateReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RemoveTemplate(ctx, req.token); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func reserveThingsEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(reserveThingsReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		reservations, err := svc.ReserveThings(ctx, req.token, req.Count)
+		if err != nil {
+			return nil, err
+		}
+
+		res := reservationsRes{
+			Reservations: []reservationRes{},
+		}
+		for _, r := range reservations {
+			res.Reservations = append(res.Reservations, reservationRes{
+				ID:  r.ID,
+				Key: r.Key,
+			})
+		}
+
+		return res, nil
+	}
+}
+
+func updateThingsMetadataEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(updateThingsMetadataReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		updated, err := svc.UpdateThingsMetadata(ctx, req.token, req.Selector, req.Patch)
+		if err != nil {
+			return nil, err
+		}
+
+		return updateMetadataRes{Updated: len(updated)}, nil
+	}
+}
+
+func patchThingEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(patchReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		thing, err := svc.PatchThing(ctx, req.token, req.id, req.patch)
+		if err != nil {
+			return nil, err
 		}
+
+		res := toThingRes(thing, nil)
 		return res, nil
 	}
 }
 
 func updateThingEndpoint(svc things.Service) endpoint.Endpoint {
-	return func(_ context.Context, request interface{}) (interface{}, error) {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(updateThingReq)
 
 		if err := req.validate(); err != nil {
@@ -51,10 +231,11 @@ func updateThingEndpoint(svc things.Service) endpoint.Endpoint {
 		thing := things.Thing{
 			ID:       req.id,
 			Name:     req.Name,
+			Tags:     req.Tags,
 			Metadata: req.Metadata,
 		}
 
-		if err := svc.UpdateThing(req.token, thing); err != nil {
+		if err := svc.UpdateThing(ctx, req.token, thing); err != nil {
 			return nil, err
 		}
 
@@ -64,14 +245,20 @@ func updateThingEndpoint(svc things.Service) endpoint.Endpoint {
 }
 
 func updateKeyEndpoint(svc things.Service) endpoint.Endpoint {
-	return func(_ context.Context, request interface{}) (interface{}, error) {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(updateKeyReq)
 
 		if err := req.validate(); err != nil {
 			return nil, err
 		}
 
-		if err := svc.UpdateKey(req.token, req.id, req.Key); err != nil {
+		var expiresAt time.Time
+		if req.ExpiresAt > 0 {
+			expiresAt = time.Unix(req.ExpiresAt, 0)
+		}
+		rotation := time.Duration(req.Rotation) * time.Second
+
+		if err := svc.UpdateKey(ctx, req.token, req.id, req.Key, expiresAt, rotation); err != nil {
 			return nil, err
 		}
 
@@ -81,38 +268,57 @@ func updateKeyEndpoint(svc things.Service) endpoint.Endpoint {
 }
 
 func viewThingEndpoint(svc things.Service) endpoint.Endpoint {
-	return func(_ context.Context, request interface{}) (interface{}, error) {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewResourceReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		thing, err := svc.ViewThing(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		res := toThingRes(thing, req.fields)
+		return res, nil
+	}
+}
+
+func onboardThingEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(viewResourceReq)
 
 		if err := req.validate(); err != nil {
 			return nil, err
 		}
 
-		thing, err := svc.ViewThing(req.token, req.id)
+		onboarding, code, err := svc.OnboardThing(ctx, req.token, req.id)
 		if err != nil {
 			return nil, err
 		}
 
-		res := viewThingRes{
-			ID:       thing.ID,
-			Owner:    thing.Owner,
-			Name:     thing.Name,
-			Key:      thing.Key,
-			Metadata: thing.Metadata,
+		res := onboardingRes{
+			ThingID:   onboarding.ThingID,
+			ThingKey:  onboarding.ThingKey,
+			Channels:  onboarding.Channels,
+			Endpoints: onboarding.Endpoints,
+			ExpiresAt: onboarding.ExpiresAt.Unix(),
+			Encoded:   code,
 		}
 		return res, nil
 	}
 }
 
 func listThingsEndpoint(svc things.Service) endpoint.Endpoint {
-	return func(_ context.Context, request interface{}) (interface{}, error) {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(listResourcesReq)
 
 		if err := req.validate(); err != nil {
 			return nil, err
 		}
 
-		page, err := svc.ListThings(req.token, req.offset, req.limit, req.name)
+		page, err := svc.ListThings(ctx, req.token, req.offset, req.limit, req.name, req.metadata, req.tags, req.status, req.cursor)
 		if err != nil {
 			return nil, err
 		}
@@ -122,17 +328,12 @@ func listThingsEndpoint(svc things.Service) endpoint.Endpoint {
 				Total:  page.Total,
 				Offset: page.Offset,
 				Limit:  page.Limit,
+				Cursor: page.Cursor,
 			},
 			Things: []viewThingRes{},
 		}
 		for _, thing := range page.Things {
-			view := viewThingRes{
-				ID:       thing.ID,
-				Owner:    thing.Owner,
-				Name:     thing.Name,
-				Key:      thing.Key,
-				Metadata: thing.Metadata,
-			}
+			view := toThingRes(thing, req.fields)
 			res.Things = append(res.Things, view)
 		}
 
@@ -140,15 +341,32 @@ func listThingsEndpoint(svc things.Service) endpoint.Endpoint {
 	}
 }
 
+func countThingsEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(countResourcesReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		total, err := svc.CountThings(ctx, req.token, req.name, req.metadata, req.tags, req.status)
+		if err != nil {
+			return nil, err
+		}
+
+		return countRes{Total: total}, nil
+	}
+}
+
 func listThingsByChannelEndpoint(svc things.Service) endpoint.Endpoint {
-	return func(_ context.Context, request interface{}) (interface{}, error) {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(listByConnectionReq)
 
 		if err := req.validate(); err != nil {
 			return nil, err
 		}
 
-		page, err := svc.ListThingsByChannel(req.token, req.id, req.offset, req.limit)
+		page, err := svc.ListThingsByChannel(ctx, req.token, req.id, req.offset, req.limit)
 		if err != nil {
 			return nil, err
 		}
@@ -162,13 +380,7 @@ func listThingsByChannelEndpoint(svc things.Service) endpoint.Endpoint {
 			Things: []viewThingRes{},
 		}
 		for _, thing := range page.Things {
-			view := viewThingRes{
-				ID:       thing.ID,
-				Owner:    thing.Owner,
-				Key:      thing.Key,
-				Name:     thing.Name,
-				Metadata: thing.Metadata,
-			}
+			view := toThingRes(thing, req.fields)
 			res.Things = append(res.Things, view)
 		}
 
@@ -177,7 +389,7 @@ func listThingsByChannelEndpoint(svc things.Service) endpoint.Endpoint {
 }
 
 func removeThingEndpoint(svc things.Service) endpoint.Endpoint {
-	return func(_ context.Context, request interface{}) (interface{}, error) {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(viewResourceReq)
 
 		err := req.validate()
@@ -189,7 +401,7 @@ func removeThingEndpoint(svc things.Service) endpoint.Endpoint {
 			return nil, err
 		}
 
-		if err := svc.RemoveThing(req.token, req.id); err != nil {
+		if err := svc.RemoveThing(ctx, req.token, req.id); err != nil {
 			return nil, err
 		}
 
@@ -198,15 +410,15 @@ func removeThingEndpoint(svc things.Service) endpoint.Endpoint {
 }
 
 func createChannelEndpoint(svc things.Service) endpoint.Endpoint {
-	return func(_ context.Context, request interface{}) (interface{}, error) {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(createChannelReq)
 
 		if err := req.validate(); err != nil {
 			return nil, err
 		}
 
-		channel := things.Channel{Name: req.Name, Metadata: req.Metadata}
-		saved, err := svc.CreateChannel(req.token, channel)
+		channel := things.Channel{Name: req.Name, Tags: req.Tags, Metadata: req.Metadata}
+		saved, err := svc.CreateChannel(ctx, req.token, channel)
 		if err != nil {
 			return nil, err
 		}
@@ -220,7 +432,7 @@ func createChannelEndpoint(svc things.Service) endpoint.Endpoint {
 }
 
 func updateChannelEndpoint(svc things.Service) endpoint.Endpoint {
-	return func(_ context.Context, request interface{}) (interface{}, error) {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(updateChannelReq)
 
 		if err := req.validate(); err != nil {
@@ -230,9 +442,10 @@ func updateChannelEndpoint(svc things.Service) endpoint.Endpoint {
 		channel := things.Channel{
 			ID:       req.id,
 			Name:     req.Name,
+			Tags:     req.Tags,
 			Metadata: req.Metadata,
 		}
-		if err := svc.UpdateChannel(req.token, channel); err != nil {
+		if err := svc.UpdateChannel(ctx, req.token, channel); err != nil {
 			return nil, err
 		}
 
@@ -244,39 +457,53 @@ func updateChannelEndpoint(svc things.Service) endpoint.Endpoint {
 	}
 }
 
+func patchChannelEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(patchReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		channel, err := svc.PatchChannel(ctx, req.token, req.id, req.patch)
+		if err != nil {
+			return nil, err
+		}
+
+		res := toChannelRes(channel, nil)
+
+		return res, nil
+	}
+}
+
 func viewChannelEndpoint(svc things.Service) endpoint.Endpoint {
-	return func(_ context.Context, request interface{}) (interface{}, error) {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(viewResourceReq)
 
 		if err := req.validate(); err != nil {
 			return nil, err
 		}
 
-		channel, err := svc.ViewChannel(req.token, req.id)
+		channel, err := svc.ViewChannel(ctx, req.token, req.id)
 		if err != nil {
 			return nil, err
 		}
 
-		res := viewChannelRes{
-			ID:       channel.ID,
-			Owner:    channel.Owner,
-			Name:     channel.Name,
-			Metadata: channel.Metadata,
-		}
+		res := toChannelRes(channel, req.fields)
 
 		return res, nil
 	}
 }
 
 func listChannelsEndpoint(svc things.Service) endpoint.Endpoint {
-	return func(_ context.Context, request interface{}) (interface{}, error) {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(listResourcesReq)
 
 		if err := req.validate(); err != nil {
 			return nil, err
 		}
 
-		page, err := svc.ListChannels(req.token, req.offset, req.limit, req.name)
+		page, err := svc.ListChannels(ctx, req.token, req.offset, req.limit, req.name, req.metadata, req.tags, req.cursor)
 		if err != nil {
 			return nil, err
 		}
@@ -286,17 +513,13 @@ func listChannelsEndpoint(svc things.Service) endpoint.Endpoint {
 				Total:  page.Total,
 				Offset: page.Offset,
 				Limit:  page.Limit,
+				Cursor: page.Cursor,
 			},
 			Channels: []viewChannelRes{},
 		}
 		// Cast channels
 		for _, channel := range page.Channels {
-			view := viewChannelRes{
-				ID:       channel.ID,
-				Owner:    channel.Owner,
-				Name:     channel.Name,
-				Metadata: channel.Metadata,
-			}
+			view := toChannelRes(channel, req.fields)
 
 			res.Channels = append(res.Channels, view)
 		}
@@ -305,15 +528,32 @@ func listChannelsEndpoint(svc things.Service) endpoint.Endpoint {
 	}
 }
 
+func countChannelsEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(countResourcesReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		total, err := svc.CountChannels(ctx, req.token, req.name, req.metadata, req.tags)
+		if err != nil {
+			return nil, err
+		}
+
+		return countRes{Total: total}, nil
+	}
+}
+
 func listChannelsByThingEndpoint(svc things.Service) endpoint.Endpoint {
-	return func(_ context.Context, request interface{}) (interface{}, error) {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(listByConnectionReq)
 
 		if err := req.validate(); err != nil {
 			return nil, err
 		}
 
-		page, err := svc.ListChannelsByThing(req.token, req.id, req.offset, req.limit)
+		page, err := svc.ListChannelsByThing(ctx, req.token, req.id, req.offset, req.limit)
 		if err != nil {
 			return nil, err
 		}
@@ -327,12 +567,7 @@ func listChannelsByThingEndpoint(svc things.Service) endpoint.Endpoint {
 			Channels: []viewChannelRes{},
 		}
 		for _, channel := range page.Channels {
-			view := viewChannelRes{
-				ID:       channel.ID,
-				Owner:    channel.Owner,
-				Name:     channel.Name,
-				Metadata: channel.Metadata,
-			}
+			view := toChannelRes(channel, req.fields)
 			res.Channels = append(res.Channels, view)
 		}
 
@@ -340,8 +575,24 @@ func listChannelsByThingEndpoint(svc things.Service) endpoint.Endpoint {
 	}
 }
 
+func transferChannelEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(transferReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.TransferChannel(ctx, req.token, req.id, req.Owner, req.KeepConnections); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
 func removeChannelEndpoint(svc things.Service) endpoint.Endpoint {
-	return func(_ context.Context, request interface{}) (interface{}, error) {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(viewResourceReq)
 
 		if err := req.validate(); err != nil {
@@ -351,7 +602,7 @@ func removeChannelEndpoint(svc things.Service) endpoint.Endpoint {
 			return nil, err
 		}
 
-		if err := svc.RemoveChannel(req.token, req.id); err != nil {
+		if err := svc.RemoveChannel(ctx, req.token, req.id); err != nil {
 			return nil, err
 		}
 
@@ -360,14 +611,14 @@ func removeChannelEndpoint(svc things.Service) endpoint.Endpoint {
 }
 
 func connectEndpoint(svc things.Service) endpoint.Endpoint {
-	return func(_ context.Context, request interface{}) (interface{}, error) {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		cr := request.(connectionReq)
 
 		if err := cr.validate(); err != nil {
 			return nil, err
 		}
 
-		if err := svc.Connect(cr.token, cr.chanID, cr.thingID); err != nil {
+		if err := svc.Connect(ctx, cr.token, []string{cr.chanID}, []string{cr.thingID}, cr.access); err != nil {
 			return nil, err
 		}
 
@@ -376,17 +627,1432 @@ func connectEndpoint(svc things.Service) endpoint.Endpoint {
 }
 
 func disconnectEndpoint(svc things.Service) endpoint.Endpoint {
-	return func(_ context.Context, request interface{}) (interface{}, error) {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		cr := request.(connectionReq)
 
 		if err := cr.validate(); err != nil {
 			return nil, err
 		}
 
-		if err := svc.Disconnect(cr.token, cr.chanID, cr.thingID); err != nil {
+		if err := svc.Disconnect(ctx, cr.token, []string{cr.chanID}, []string{cr.thingID}); err != nil {
+			return nil, err
+		}
+
+		return disconnectionRes{}, nil
+	}
+}
+
+func capabilitiesEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(capabilitiesReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		caps, err := svc.Capabilities(ctx, req.token, req.kind, req.entity)
+		if err != nil {
+			return nil, err
+		}
+
+		res := capabilitiesRes{
+			Kind:       caps.Kind,
+			Entity:     caps.EntityID,
+			Operations: caps.Operations,
+		}
+
+		return res, nil
+	}
+}
+
+func auditEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(auditReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		query := things.AuditQuery{
+			Actor:      req.actor,
+			Operation:  req.operation,
+			EntityKind: req.kind,
+			EntityID:   req.entity,
+		}
+		if req.from != 0 {
+			query.From = time.Unix(int64(req.from), 0)
+		}
+		if req.to != 0 {
+			query.To = time.Unix(int64(req.to), 0)
+		}
+
+		page, err := svc.Audit(ctx, req.token, query, req.offset, req.limit)
+		if err != nil {
+			return nil, err
+		}
+
+		res := auditRes{
+			pageRes: pageRes{
+				Total:  page.Total,
+				Offset: page.Offset,
+				Limit:  page.Limit,
+			},
+			Events: []auditEventRes{},
+		}
+		for _, e := range page.Events {
+			view := auditEventRes{
+				Actor:      e.Actor,
+				Operation:  e.Operation,
+				Kind:       e.EntityKind,
+				Entity:     e.EntityID,
+				Diff:       map[string]diffRes{},
+				OccurredAt: e.OccurredAt.Unix(),
+			}
+			for field, d := range e.Diff {
+				view.Diff[field] = diffRes(d)
+			}
+			res.Events = append(res.Events, view)
+		}
+
+		return res, nil
+	}
+}
+
+func connectAllEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(connectionsReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.Connect(ctx, req.token, req.ChanIDs, req.ThingIDs, req.Access); err != nil {
+			return nil, err
+		}
+
+		return connectionRes{}, nil
+	}
+}
+
+func disconnectAllEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(connectionsReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.Disconnect(ctx, req.token, req.ChanIDs, req.ThingIDs); err != nil {
 			return nil, err
 		}
 
 		return disconnectionRes{}, nil
 	}
 }
+
+func createGroupEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(createGroupReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		group := things.Group{
+			Parent:   req.Parent,
+			Name:     req.Name,
+			Metadata: req.Metadata,
+		}
+		saved, err := svc.CreateGroup(ctx, req.token, group)
+		if err != nil {
+			return nil, err
+		}
+
+		res := groupRes{
+			id:      saved.ID,
+			created: true,
+		}
+		return res, nil
+	}
+}
+
+func updateGroupEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(updateGroupReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		group := things.Group{
+			ID:       req.id,
+			Name:     req.Name,
+			Metadata: req.Metadata,
+		}
+		if err := svc.UpdateGroup(ctx, req.token, group); err != nil {
+			return nil, err
+		}
+
+		res := groupRes{
+			id:      req.id,
+			created: false,
+		}
+		return res, nil
+	}
+}
+
+func viewGroupEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewResourceReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		group, err := svc.ViewGroup(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		return toGroupRes(group), nil
+	}
+}
+
+func listGroupsEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listGroupsReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		page, err := svc.ListGroups(ctx, req.token, req.parent, req.offset, req.limit)
+		if err != nil {
+			return nil, err
+		}
+
+		res := groupsPageRes{
+			pageRes: pageRes{
+				Total:  page.Total,
+				Offset: page.Offset,
+				Limit:  page.Limit,
+			},
+			Groups: []viewGroupRes{},
+		}
+		for _, group := range page.Groups {
+			res.Groups = append(res.Groups, toGroupRes(group))
+		}
+
+		return res, nil
+	}
+}
+
+func listThingsByGroupEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listByConnectionReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		page, err := svc.ListThingsByGroup(ctx, req.token, req.id, req.offset, req.limit)
+		if err != nil {
+			return nil, err
+		}
+
+		res := thingsPageRes{
+			pageRes: pageRes{
+				Total:  page.Total,
+				Offset: page.Offset,
+				Limit:  page.Limit,
+			},
+			Things: []viewThingRes{},
+		}
+		for _, thing := range page.Things {
+			res.Things = append(res.Things, toThingRes(thing, req.fields))
+		}
+
+		return res, nil
+	}
+}
+
+func removeGroupEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewResourceReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RemoveGroup(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func assignThingsEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(assignThingsReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.AssignThings(ctx, req.token, req.id, req.Things...); err != nil {
+			return nil, err
+		}
+
+		return connectionRes{}, nil
+	}
+}
+
+func unassignThingEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(groupThingReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.UnassignThing(ctx, req.token, req.id, req.thingID); err != nil {
+			return nil, err
+		}
+
+		return disconnectionRes{}, nil
+	}
+}
+
+func connectGroupEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(groupConnectionReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		ids, err := svc.ConnectGroup(ctx, req.token, req.groupID, req.chanID)
+		if err != nil {
+			return nil, err
+		}
+
+		return groupConnectionRes{Things: ids}, nil
+	}
+}
+
+func toGroupRes(group things.Group) viewGroupRes {
+	return viewGroupRes{
+		ID:       group.ID,
+		Parent:   group.Parent,
+		Name:     group.Name,
+		Metadata: group.Metadata,
+	}
+}
+
+func createProfileEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(profileReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		profile := things.Profile{
+			Name:      req.Name,
+			Metadata:  req.Metadata,
+			Channels:  req.Channels,
+			Bootstrap: req.Bootstrap,
+		}
+		saved, err := svc.CreateProfile(ctx, req.token, profile)
+		if err != nil {
+			return nil, err
+		}
+
+		res := profileRes{
+			id:      saved.ID,
+			created: true,
+		}
+		return res, nil
+	}
+}
+
+func updateProfileEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(profileReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if req.id == "" {
+			return nil, things.ErrMalformedEntity
+		}
+
+		profile := things.Profile{
+			ID:        req.id,
+			Name:      req.Name,
+			Metadata:  req.Metadata,
+			Channels:  req.Channels,
+			Bootstrap: req.Bootstrap,
+		}
+		if err := svc.UpdateProfile(ctx, req.token, profile); err != nil {
+			return nil, err
+		}
+
+		res := profileRes{
+			id:      req.id,
+			created: false,
+		}
+		return res, nil
+	}
+}
+
+func viewProfileEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewResourceReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		profile, err := svc.ViewProfile(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		return toProfileRes(profile), nil
+	}
+}
+
+func listProfilesEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listProfilesReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		page, err := svc.ListProfiles(ctx, req.token, req.offset, req.limit)
+		if err != nil {
+			return nil, err
+		}
+
+		res := profilesPageRes{
+			pageRes: pageRes{
+				Total:  page.Total,
+				Offset: page.Offset,
+				Limit:  page.Limit,
+			},
+			Profiles: []viewProfileRes{},
+		}
+		for _, profile := range page.Profiles {
+			res.Profiles = append(res.Profiles, toProfileRes(profile))
+		}
+
+		return res, nil
+	}
+}
+
+func removeProfileEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewResourceReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RemoveProfile(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func toProfileRes(profile things.Profile) viewProfileRes {
+	channels := profile.Channels
+	if channels == nil {
+		channels = []string{}
+	}
+
+	return viewProfileRes{
+		ID:        profile.ID,
+		Name:      profile.Name,
+		Metadata:  profile.Metadata,
+		Channels:  channels,
+		Bootstrap: profile.Bootstrap,
+	}
+}
+
+func thingUsageEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(usageReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		from, to := usagePeriod(req)
+		usage, err := svc.ThingUsage(ctx, req.token, req.id, from, to)
+		if err != nil {
+			return nil, err
+		}
+
+		return toUsageRes(usage), nil
+	}
+}
+
+func channelUsageEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(usageReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		from, to := usagePeriod(req)
+		usage, err := svc.ChannelUsage(ctx, req.token, req.id, from, to)
+		if err != nil {
+			return nil, err
+		}
+
+		return toUsageRes(usage), nil
+	}
+}
+
+// usagePeriod returns the requested usage period. If not provided, the
+// period ends now and starts defUsagePeriod before its end.
+func usagePeriod(req usageReq) (time.Time, time.Time) {
+	to := time.Now()
+	if req.to != 0 {
+		to = time.Unix(int64(req.to), 0)
+	}
+
+	from := to.Add(-defUsagePeriod)
+	if req.from != 0 {
+		from = time.Unix(int64(req.from), 0)
+	}
+
+	return from, to
+}
+
+func toUsageRes(usage things.Usage) usageRes {
+	return usageRes{
+		From:     usage.From.Unix(),
+		To:       usage.To.Unix(),
+		Messages: usage.Messages,
+		Bytes:    usage.Bytes,
+	}
+}
+
+func viewHealthEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewResourceReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		health, err := svc.ViewHealth(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		return toHealthRes(health), nil
+	}
+}
+
+func listUnhealthyEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listUnhealthyReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		page, err := svc.ListUnhealthy(ctx, req.token, req.thresholds, req.offset, req.limit)
+		if err != nil {
+			return nil, err
+		}
+
+		res := healthPageRes{
+			pageRes: pageRes{
+				Total:  page.Total,
+				Offset: page.Offset,
+				Limit:  page.Limit,
+			},
+			Things: []healthRes{},
+		}
+		for _, health := range page.Records {
+			res.Things = append(res.Things, toHealthRes(health))
+		}
+
+		return res, nil
+	}
+}
+
+func toHealthRes(health things.Health) healthRes {
+	return healthRes{
+		ThingID:  health.ThingID,
+		Battery:  health.Battery,
+		RSSI:     health.RSSI,
+		Firmware: health.Firmware,
+		Reported: health.Reported.Unix(),
+	}
+}
+
+func registerSubtopicEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(registerSubtopicReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		subtopic := things.Subtopic{
+			Name:        req.name,
+			Description: req.Description,
+			Schema:      req.Schema,
+		}
+		if err := svc.RegisterSubtopic(ctx, req.token, req.chanID, subtopic); err != nil {
+			return nil, err
+		}
+
+		return registerSubtopicRes{}, nil
+	}
+}
+
+func listSubtopicsEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewResourceReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		subtopics, err := svc.ListSubtopics(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		res := subtopicsRes{Subtopics: []subtopicRes{}}
+		for _, st := range subtopics {
+			res.Subtopics = append(res.Subtopics, subtopicRes{
+				Name:        st.Name,
+				Description: st.Description,
+				Schema:      st.Schema,
+			})
+		}
+
+		return res, nil
+	}
+}
+
+func removeSubtopicEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(subtopicReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RemoveSubtopic(ctx, req.token, req.chanID, req.name); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func addSubtopicRuleEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(addSubtopicRuleReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		rule, err := svc.AddSubtopicRule(ctx, req.token, req.chanID, req.rule())
+		if err != nil {
+			return nil, err
+		}
+
+		res := subtopicRuleRes{
+			ID:      rule.ID,
+			ThingID: rule.ThingID,
+			Action:  rule.Action,
+			Pattern: rule.Pattern,
+			created: true,
+		}
+
+		return res, nil
+	}
+}
+
+func listSubtopicRulesEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewResourceReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		rules, err := svc.ListSubtopicRules(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		res := subtopicRulesRes{Rules: []subtopicRuleRes{}}
+		for _, r := range rules {
+			res.Rules = append(res.Rules, subtopicRuleRes{
+				ID:      r.ID,
+				ThingID: r.ThingID,
+				Action:  r.Action,
+				Pattern: r.Pattern,
+			})
+		}
+
+		return res, nil
+	}
+}
+
+func removeSubtopicRuleEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(subtopicRuleReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RemoveSubtopicRule(ctx, req.token, req.chanID, req.ruleID); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func shareEndpoint(svc things.Service, kind string) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(shareReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		share := things.Share{
+			Kind:       kind,
+			EntityID:   req.id,
+			User:       req.user,
+			Permission: things.Permission(req.Permission),
+		}
+		if err := svc.Share(ctx, req.token, share); err != nil {
+			return nil, err
+		}
+
+		return shareRes{}, nil
+	}
+}
+
+func unshareEndpoint(svc things.Service, kind string) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(unshareReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.Unshare(ctx, req.token, kind, req.id, req.user); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func listSharesEndpoint(svc things.Service, kind string) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewResourceReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		shares, err := svc.ListShares(ctx, req.token, kind, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		res := sharesRes{Shares: []sharedWithRes{}}
+		for _, share := range shares {
+			res.Shares = append(res.Shares, sharedWithRes{
+				User:       share.User,
+				Permission: string(share.Permission),
+			})
+		}
+
+		return res, nil
+	}
+}
+
+func observedSubtopicsEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewResourceReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		observed, err := svc.ObservedSubtopics(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		res := observedSubtopicsRes{Subtopics: []observedSubtopicRes{}}
+		for _, st := range observed {
+			res.Subtopics = append(res.Subtopics, observedSubtopicRes{
+				Name:       st.Name,
+				Messages:   st.Messages,
+				LastSeen:   st.LastSeen.Unix(),
+				Registered: st.Registered,
+			})
+		}
+
+		return res, nil
+	}
+}
+
+func issueKeyEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(issueReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		key, signed, err := svc.IssueKey(ctx, req.token, req.id, time.Duration(req.TTL)*time.Second)
+		if err != nil {
+			return nil, err
+		}
+
+		res := signedKeyRes{
+			ThingID:   key.ThingID,
+			Key:       signed,
+			Channels:  key.Channels,
+			ExpiresAt: key.ExpiresAt.Unix(),
+		}
+
+		return res, nil
+	}
+}
+
+func openSessionEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(openSessionReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		session, signed, err := svc.OpenSession(ctx, req.key, time.Duration(req.TTL)*time.Second)
+		if err != nil {
+			return nil, err
+		}
+
+		res := signedKeyRes{
+			ThingID:   session.ThingID,
+			Key:       signed,
+			Channels:  session.Channels,
+			ExpiresAt: session.ExpiresAt.Unix(),
+		}
+
+		return res, nil
+	}
+}
+
+func revokeKeysEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewResourceReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RevokeKeys(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func issueThingKeyEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(issueThingKeyReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		tk, err := svc.IssueThingKey(ctx, req.token, req.id, things.KeyScope(req.Scope))
+		if err != nil {
+			return nil, err
+		}
+
+		res := thingKeyRes{
+			ID:       tk.ID,
+			Key:      tk.Key,
+			Scope:    string(tk.Scope),
+			IssuedAt: tk.IssuedAt.Unix(),
+			created:  true,
+		}
+
+		return res, nil
+	}
+}
+
+func listThingKeysEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewResourceReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		keys, err := svc.ListThingKeys(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		res := thingKeysRes{Keys: []thingKeyRes{}}
+		for _, tk := range keys {
+			res.Keys = append(res.Keys, thingKeyRes{
+				ID:       tk.ID,
+				Key:      tk.Key,
+				Scope:    string(tk.Scope),
+				IssuedAt: tk.IssuedAt.Unix(),
+			})
+		}
+
+		return res, nil
+	}
+}
+
+func revokeThingKeyEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(thingKeyReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RevokeThingKey(ctx, req.token, req.id, req.keyID); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func enableThingEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewResourceReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.EnableThing(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func transferThingEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(transferReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.TransferThing(ctx, req.token, req.id, req.Owner, req.KeepConnections); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func disableThingEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewResourceReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.DisableThing(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func archiveChannelEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewResourceReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.ArchiveChannel(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func unarchiveChannelEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewResourceReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.UnarchiveChannel(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func archiveChannelsEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(archiveChannelsReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		tags := things.TagFilter{Tags: req.Tags, Any: req.Match == matchAny}
+		archived, err := svc.ArchiveChannels(ctx, req.token, req.Selector, tags)
+		if err != nil {
+			return nil, err
+		}
+
+		return archiveChannelsRes{Archived: len(archived)}, nil
+	}
+}
+
+func inviteToChannelEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(issueReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		inv, signed, err := svc.InviteToChannel(ctx, req.token, req.id, time.Duration(req.TTL)*time.Second)
+		if err != nil {
+			return nil, err
+		}
+
+		res := invitationRes{
+			ID:         inv.ID,
+			ChannelID:  inv.ChannelID,
+			Invitation: signed,
+			ExpiresAt:  inv.ExpiresAt.Unix(),
+		}
+
+		return res, nil
+	}
+}
+
+func issueChannelTokenEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(issueChannelTokenReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		ct, err := svc.IssueChannelToken(ctx, req.token, req.id, things.KeyScope(req.Scope), time.Duration(req.TTL)*time.Second)
+		if err != nil {
+			return nil, err
+		}
+
+		res := channelTokenRes{
+			ID:        ct.ID,
+			Token:     ct.Token,
+			Scope:     string(ct.Scope),
+			IssuedAt:  ct.IssuedAt.Unix(),
+			ExpiresAt: ct.ExpiresAt.Unix(),
+			created:   true,
+		}
+
+		return res, nil
+	}
+}
+
+func listChannelTokensEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewResourceReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		tokens, err := svc.ListChannelTokens(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		res := channelTokensRes{Tokens: []channelTokenRes{}}
+		for _, ct := range tokens {
+			res.Tokens = append(res.Tokens, channelTokenRes{
+				ID:        ct.ID,
+				Token:     ct.Token,
+				Scope:     string(ct.Scope),
+				IssuedAt:  ct.IssuedAt.Unix(),
+				ExpiresAt: ct.ExpiresAt.Unix(),
+			})
+		}
+
+		return res, nil
+	}
+}
+
+func revokeChannelTokenEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(channelTokenReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RevokeChannelToken(ctx, req.token, req.id, req.tokenID); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func thingHistoryEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listByConnectionReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		page, err := svc.ThingHistory(ctx, req.token, req.id, req.offset, req.limit)
+		if err != nil {
+			return nil, err
+		}
+
+		return toHistoryRes(page), nil
+	}
+}
+
+func channelHistoryEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listByConnectionReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		page, err := svc.ChannelHistory(ctx, req.token, req.id, req.offset, req.limit)
+		if err != nil {
+			return nil, err
+		}
+
+		return toHistoryRes(page), nil
+	}
+}
+
+func toHistoryRes(page things.ChangesPage) historyRes {
+	res := historyRes{
+		pageRes: pageRes{
+			Total:  page.Total,
+			Offset: page.Offset,
+			Limit:  page.Limit,
+		},
+		Changes: []changeRes{},
+	}
+	for _, c := range page.Changes {
+		view := changeRes{
+			Field:     c.Field,
+			OldValue:  c.OldValue,
+			NewValue:  c.NewValue,
+			ChangedBy: c.Owner,
+			ChangedAt: c.ChangedAt.Unix(),
+		}
+		res.Changes = append(res.Changes, view)
+	}
+
+	return res
+}
+
+func exportEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(exportReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		snapshot, err := svc.Export(ctx, req.token)
+		if err != nil {
+			return nil, err
+		}
+
+		return toSnapshotRes(snapshot, req.csv, false)
+	}
+}
+
+func importEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(importReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		snapshot := things.Snapshot{}
+		for _, th := range req.Things {
+			thing := things.Thing{
+				ID:          th.ID,
+				ExternalID:  th.ExternalID,
+				Name:        th.Name,
+				Key:         th.Key,
+				KeyRotation: time.Duration(th.KeyRotation) * time.Second,
+				State:       things.State(th.State),
+				Tags:        th.Tags,
+				Metadata:    th.Metadata,
+			}
+			if th.KeyExpiresAt > 0 {
+				thing.KeyExpiresAt = time.Unix(th.KeyExpiresAt, 0)
+			}
+			snapshot.Things = append(snapshot.Things, thing)
+		}
+		for _, ch := range req.Channels {
+			snapshot.Channels = append(snapshot.Channels, things.Channel{
+				ID:       ch.ID,
+				Name:     ch.Name,
+				State:    things.State(ch.State),
+				Tags:     ch.Tags,
+				Metadata: ch.Metadata,
+			})
+		}
+		for _, conn := range req.Connections {
+			snapshot.Connections = append(snapshot.Connections, things.Connection{
+				ChanID:  conn.ChanID,
+				ThingID: conn.ThingID,
+				Access:  things.ConnectionAccess(conn.Access),
+			})
+		}
+
+		imported, err := svc.Import(ctx, req.token, snapshot, req.preserve)
+		if err != nil {
+			return nil, err
+		}
+
+		return toSnapshotRes(imported, req.csv, true)
+	}
+}
+
+// toSnapshotRes returns the snapshot represented either as JSON or, if
+// requested, as the CSV document.
+func toSnapshotRes(snapshot things.Snapshot, asCSV, created bool) (interface{}, error) {
+	res := snapshotRes{
+		created:     created,
+		Things:      []snapshotThing{},
+		Channels:    []snapshotChannel{},
+		Connections: []snapshotConnection{},
+	}
+	for _, thing := range snapshot.Things {
+		th := snapshotThing{
+			ID:          thing.ID,
+			ExternalID:  thing.ExternalID,
+			Name:        thing.Name,
+			Key:         thing.Key,
+			KeyRotation: int64(thing.KeyRotation / time.Second),
+			State:       string(thing.State),
+			Tags:        thing.Tags,
+			Metadata:    thing.Metadata,
+		}
+		if !thing.KeyExpiresAt.IsZero() {
+			th.KeyExpiresAt = thing.KeyExpiresAt.Unix()
+		}
+		res.Things = append(res.Things, th)
+	}
+	for _, channel := range snapshot.Channels {
+		res.Channels = append(res.Channels, snapshotChannel{
+			ID:       channel.ID,
+			Name:     channel.Name,
+			State:    string(channel.State),
+			Tags:     channel.Tags,
+			Metadata: channel.Metadata,
+		})
+	}
+	for _, conn := range snapshot.Connections {
+		res.Connections = append(res.Connections, snapshotConnection{
+			ChanID:  conn.ChanID,
+			ThingID: conn.ThingID,
+			Access:  string(conn.Access),
+		})
+	}
+
+	if !asCSV {
+		return res, nil
+	}
+
+	content, err := snapshotToCSV(res)
+	if err != nil {
+		return nil, err
+	}
+
+	return documentRes{
+		name:        "snapshot.csv",
+		contentType: csvContentType,
+		created:     created,
+		content:     content,
+	}, nil
+}
+
+// snapshotToCSV writes each thing, channel and connection of the snapshot
+// as the row of the kind it is of, leaving the columns of the other kinds
+// empty. Tags are comma-separated, while metadata is written as JSON.
+func snapshotToCSV(res snapshotRes) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	if err := w.Write(snapshotColumns); err != nil {
+		return nil, err
+	}
+
+	for _, th := range res.Things {
+		md, err := metadataToCSV(th.Metadata)
+		if err != nil {
+			return nil, err
+		}
+
+		expires, rotation := "", ""
+		if th.KeyExpiresAt > 0 {
+			expires = strconv.FormatInt(th.KeyExpiresAt, 10)
+		}
+		if th.KeyRotation > 0 {
+			rotation = strconv.FormatInt(th.KeyRotation, 10)
+		}
+
+		row := []string{thingRow, th.ID, th.Name, th.Key, expires, rotation, th.State, strings.Join(th.Tags, ","), md, "", "", ""}
+		if err := w.Write(row); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, ch := range res.Channels {
+		md, err := metadataToCSV(ch.Metadata)
+		if err != nil {
+			return nil, err
+		}
+
+		row := []string{channelRow, ch.ID, ch.Name, "", "", "", ch.State, strings.Join(ch.Tags, ","), md, "", "", ""}
+		if err := w.Write(row); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, conn := range res.Connections {
+		row := []string{connectionRow, "", "", "", "", "", "", "", "", conn.ChanID, conn.ThingID, conn.Access}
+		if err := w.Write(row); err != nil {
+			return nil, err
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func metadataToCSV(metadata map[string]interface{}) (string, error) {
+	if len(metadata) == 0 {
+		return "", nil
+	}
+
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+func toThingRes(thing things.Thing, fields fieldSet) viewThingRes {
+	res := viewThingRes{
+		ID:    thing.ID,
+		Owner: thing.Owner,
+	}
+	if fields.has("external_id") {
+		res.ExternalID = thing.ExternalID
+	}
+	if fields.has("name") {
+		res.Name = thing.Name
+	}
+	if fields.has("key") {
+		res.Key = thing.Key
+		if !thing.KeyExpiresAt.IsZero() {
+			res.KeyExpiresAt = thing.KeyExpiresAt.Unix()
+		}
+		res.KeyRotation = int64(thing.KeyRotation / time.Second)
+	}
+	if fields.has("state") {
+		res.State = string(thing.State)
+	}
+	if fields.has("tags") {
+		res.Tags = thing.Tags
+	}
+	if fields.has("metadata") {
+		res.Metadata = thing.Metadata
+	}
+	if fields.has("last_seen") && !thing.LastSeen.IsZero() {
+		res.LastSeen = thing.LastSeen.Unix()
+	}
+
+	return res
+}
+
+func toChannelRes(channel things.Channel, fields fieldSet) viewChannelRes {
+	res := viewChannelRes{
+		ID:    channel.ID,
+		Owner: channel.Owner,
+	}
+	if fields.has("name") {
+		res.Name = channel.Name
+	}
+	if fields.has("state") {
+		res.State = string(channel.State)
+	}
+	if fields.has("tags") {
+		res.Tags = channel.Tags
+	}
+	if fields.has("metadata") {
+		res.Metadata = channel.Metadata
+	}
+
+	return res
+}
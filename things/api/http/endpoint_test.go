@@ -8,13 +8,14 @@
 package http_test
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
-	"strconv"
+	"net/url"
 	"strings"
 	"testing"
 	"time"
@@ -28,11 +29,13 @@ import (
 
 const (
 	contentType = "application/json"
+	csvType     = "text/csv"
 	email       = "user@example.com"
 	token       = "token"
 	wrongValue  = "wrong_value"
-	wrongID     = 0
+	wrongID     = "123e4567-e89b-12d3-a456-999999999999"
 	maxNameSize = 1024
+	staleAfter  = time.Minute
 )
 
 var (
@@ -45,6 +48,7 @@ var (
 		Metadata: map[string]interface{}{"test": "data"},
 	}
 	invalidName = strings.Repeat("m", maxNameSize+1)
+	limits      = things.Limits{NameLength: maxNameSize}
 )
 
 type testRequest struct {
@@ -52,6 +56,7 @@ type testRequest struct {
 	method      string
 	url         string
 	contentType string
+	accept      string
 	token       string
 	body        io.Reader
 }
@@ -67,6 +72,9 @@ func (tr testRequest) make() (*http.Response, error) {
 	if tr.contentType != "" {
 		req.Header.Set("Content-Type", tr.contentType)
 	}
+	if tr.accept != "" {
+		req.Header.Set("Accept", tr.accept)
+	}
 	return tr.client.Do(req)
 }
 
@@ -75,11 +83,27 @@ func newService(tokens map[string]string) things.Service {
 	conns := make(chan mocks.Connection)
 	thingsRepo := mocks.NewThingRepository(conns)
 	channelsRepo := mocks.NewChannelRepository(thingsRepo, conns)
+	reservationsRepo := mocks.NewReservationRepository()
+	usageRepo := mocks.NewUsageRepository()
+	historyRepo := mocks.NewHistoryRepository()
+	auditRepo := mocks.NewAuditRepository()
 	chanCache := mocks.NewChannelCache()
 	thingCache := mocks.NewThingCache()
 	idp := mocks.NewIdentityProvider()
+	onboarding := mocks.NewOnboardingProvider()
+	keys := mocks.NewKeyProvider()
+	revocations := mocks.NewRevocationRepository()
+	templates := mocks.NewTemplateRepository()
+	groups := mocks.NewGroupRepository(thingsRepo, channelsRepo)
+	subtopics := mocks.NewSubtopicRepository()
+	observations := mocks.NewObservationRepository()
+	thingKeys := mocks.NewThingKeyRepository(thingsRepo, channelsRepo)
+	shares := mocks.NewShareRepository()
+	profiles := mocks.NewProfileRepository()
+	rules := mocks.NewSubtopicRuleRepository()
+	health := mocks.NewHealthRepository()
 
-	return things.New(users, thingsRepo, channelsRepo, chanCache, thingCache, idp)
+	return things.New(users, thingsRepo, channelsRepo, reservationsRepo, usageRepo, historyRepo, auditRepo, chanCache, thingCache, idp, onboarding, keys, revocations, templates, groups, subtopics, observations, thingKeys, shares, profiles, rules, health, mocks.NewChannelTokenRepository(channelsRepo), mocks.NewOverrideProvider(nil), limits, staleAfter)
 }
 
 func newServer(svc things.Service) *httptest.Server {
@@ -118,7 +142,7 @@ func TestAddThing(t *testing.T) {
 			contentType: contentType,
 			auth:        token,
 			status:      http.StatusCreated,
-			location:    "/things/1",
+			location:    "/things/123e4567-e89b-12d3-a456-000000000001",
 		},
 		{
 			desc:        "add thing with existing key",
@@ -134,7 +158,23 @@ func TestAddThing(t *testing.T) {
 			contentType: contentType,
 			auth:        token,
 			status:      http.StatusCreated,
-			location:    "/things/2",
+			location:    "/things/123e4567-e89b-12d3-a456-000000000002",
+		},
+		{
+			desc:        "add thing with external ID",
+			req:         `{"name":"serial","external_id":"serial-1"}`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusCreated,
+			location:    "/things/123e4567-e89b-12d3-a456-000000000003",
+		},
+		{
+			desc:        "add thing with existing external ID",
+			req:         `{"name":"retry","external_id":"serial-1"}`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusOK,
+			location:    "",
 		},
 		{
 			desc:        "add thing with invalid auth token",
@@ -202,114 +242,117 @@ func TestAddThing(t *testing.T) {
 		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
 		assert.Equal(t, tc.location, location, fmt.Sprintf("%s: expected location %s got %s", tc.desc, tc.location, location))
 	}
+
+	req := testRequest{
+		client:      ts.Client(),
+		method:      http.MethodPost,
+		url:         fmt.Sprintf("%s/things", ts.URL),
+		contentType: contentType,
+		token:       token,
+		body:        strings.NewReader(invalidData),
+	}
+	res, err := req.make()
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+
+	var body struct {
+		Field string `json:"field"`
+	}
+	err = json.NewDecoder(res.Body).Decode(&body)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	assert.Equal(t, "name", body.Field, fmt.Sprintf("expected invalid field name got %s", body.Field))
 }
 
-func TestUpdateThing(t *testing.T) {
+func TestCreateThings(t *testing.T) {
 	svc := newService(map[string]string{token: email})
 	ts := newServer(svc)
 	defer ts.Close()
 
-	data := toJSON(thing)
-	sth, _ := svc.AddThing(token, thing)
-
 	th := thing
+	th.Key = "bulk-key"
+	data := fmt.Sprintf("[%s,%s]", toJSON(th), toJSON(things.Thing{Name: "other"}))
+
 	th.Name = invalidName
-	invalidData := toJSON(th)
+	invalidData := fmt.Sprintf("[%s]", toJSON(th))
 
 	cases := []struct {
 		desc        string
 		req         string
-		id          string
 		contentType string
 		auth        string
 		status      int
+		count       int
 	}{
 		{
-			desc:        "update existing thing",
+			desc:        "create valid things",
 			req:         data,
-			id:          sth.ID,
-			contentType: contentType,
-			auth:        token,
-			status:      http.StatusOK,
-		},
-		{
-			desc:        "update thing with empty JSON request",
-			req:         "{}",
-			id:          sth.ID,
 			contentType: contentType,
 			auth:        token,
-			status:      http.StatusOK,
+			status:      http.StatusCreated,
+			count:       2,
 		},
 		{
-			desc:        "update non-existent thing",
+			desc:        "create things with existing key",
 			req:         data,
-			id:          strconv.FormatUint(wrongID, 10),
 			contentType: contentType,
 			auth:        token,
-			status:      http.StatusNotFound,
+			status:      http.StatusUnprocessableEntity,
+			count:       0,
 		},
 		{
-			desc:        "update thing with invalid id",
-			req:         data,
-			id:          "invalid",
+			desc:        "create empty list of things",
+			req:         "[]",
 			contentType: contentType,
 			auth:        token,
-			status:      http.StatusNotFound,
+			status:      http.StatusBadRequest,
+			count:       0,
 		},
 		{
-			desc:        "update thing with invalid user token",
+			desc:        "create things with invalid auth token",
 			req:         data,
-			id:          sth.ID,
 			contentType: contentType,
 			auth:        wrongValue,
 			status:      http.StatusForbidden,
+			count:       0,
 		},
 		{
-			desc:        "update thing with empty user token",
+			desc:        "create things with empty auth token",
 			req:         data,
-			id:          sth.ID,
 			contentType: contentType,
 			auth:        "",
 			status:      http.StatusForbidden,
+			count:       0,
 		},
 		{
-			desc:        "update thing with invalid data format",
-			req:         "{",
-			id:          sth.ID,
-			contentType: contentType,
-			auth:        token,
-			status:      http.StatusBadRequest,
-		},
-		{
-			desc:        "update thing with empty request",
-			req:         "",
-			id:          sth.ID,
+			desc:        "create things with invalid request format",
+			req:         "{}",
 			contentType: contentType,
 			auth:        token,
 			status:      http.StatusBadRequest,
+			count:       0,
 		},
 		{
-			desc:        "update thing without content type",
+			desc:        "create things without content type",
 			req:         data,
-			id:          sth.ID,
 			contentType: "",
 			auth:        token,
 			status:      http.StatusUnsupportedMediaType,
+			count:       0,
 		},
 		{
-			desc:        "update thing with invalid name",
+			desc:        "create things with invalid name",
 			req:         invalidData,
 			contentType: contentType,
 			auth:        token,
 			status:      http.StatusBadRequest,
+			count:       0,
 		},
 	}
 
 	for _, tc := range cases {
 		req := testRequest{
 			client:      ts.Client(),
-			method:      http.MethodPut,
-			url:         fmt.Sprintf("%s/things/%s", ts.URL, tc.id),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/things/bulk", ts.URL),
 			contentType: tc.contentType,
 			token:       tc.auth,
 			body:        strings.NewReader(tc.req),
@@ -317,108 +360,172 @@ func TestUpdateThing(t *testing.T) {
 		res, err := req.make()
 		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
 		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+
+		var body createThingsRes
+		json.NewDecoder(res.Body).Decode(&body)
+		assert.Equal(t, tc.count, len(body.Things), fmt.Sprintf("%s: expected %d things got %d", tc.desc, tc.count, len(body.Things)))
+		for _, th := range body.Things {
+			assert.NotEmpty(t, th.ID, fmt.Sprintf("%s: expected non-empty ID", tc.desc))
+			assert.NotEmpty(t, th.Key, fmt.Sprintf("%s: expected non-empty key", tc.desc))
+		}
 	}
 }
 
-func TestUpdateKey(t *testing.T) {
+func TestProvisionThing(t *testing.T) {
 	svc := newService(map[string]string{token: email})
 	ts := newServer(svc)
 	defer ts.Close()
 
-	th := thing
-	th.Key = "key"
-	sth, _ := svc.AddThing(token, th)
+	tmpl := things.Template{
+		Channels: []things.ChannelTemplate{
+			{Name: "{name}-data"},
+			{Name: "{name}-control", Metadata: map[string]interface{}{"role": "control"}},
+		},
+	}
+	err := svc.SaveTemplate(context.Background(), token, tmpl)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
-	sth.Key = "new-key"
-	data := toJSON(sth)
+	req := testRequest{
+		client:      ts.Client(),
+		method:      http.MethodPost,
+		url:         fmt.Sprintf("%s/things", ts.URL),
+		contentType: contentType,
+		token:       token,
+		body:        strings.NewReader(toJSON(thing)),
+	}
+	res, err := req.make()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, http.StatusCreated, res.StatusCode, fmt.Sprintf("expected status code %d got %d", http.StatusCreated, res.StatusCode))
 
-	sth.Key = "key"
-	dummyData := toJSON(sth)
+	var top topologyRes
+	err = json.NewDecoder(res.Body).Decode(&top)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	location := res.Header.Get("Location")
+	expected := fmt.Sprintf("/things/%s", top.Thing.ID)
+	assert.Equal(t, expected, location, fmt.Sprintf("expected location %s got %s", expected, location))
+	assert.Equal(t, thing.Name, top.Thing.Name, fmt.Sprintf("expected thing name %s got %s", thing.Name, top.Thing.Name))
+	assert.NotEmpty(t, top.Thing.Key, "expected thing key")
+
+	names := []string{}
+	for _, ch := range top.Channels {
+		names = append(names, ch.Name)
+	}
+	channels := []string{"test_app-data", "test_app-control"}
+	assert.Equal(t, channels, names, fmt.Sprintf("expected channels %v got %v", channels, names))
+}
+
+func TestProvisionThingWithProfile(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	sch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	profile, err := svc.CreateProfile(context.Background(), token, things.Profile{Metadata: map[string]interface{}{"model": "a"}, Channels: []string{sch.ID}})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc     string
+		req      string
+		status   int
+		channels []string
+	}{
+		{
+			desc:     "provision thing using profile",
+			req:      toJSON(map[string]interface{}{"name": "sensor", "profile_id": profile.ID}),
+			status:   http.StatusCreated,
+			channels: []string{sch.ID},
+		},
+		{
+			desc:   "provision thing using non-existing profile",
+			req:    toJSON(map[string]interface{}{"name": "sensor", "profile_id": wrongValue}),
+			status: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/things", ts.URL),
+			contentType: contentType,
+			token:       token,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		if tc.status != http.StatusCreated {
+			continue
+		}
+
+		var top topologyRes
+		err = json.NewDecoder(res.Body).Decode(&top)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+
+		ids := []string{}
+		for _, ch := range top.Channels {
+			ids = append(ids, ch.ID)
+		}
+		assert.Equal(t, tc.channels, ids, fmt.Sprintf("%s: expected channels %v got %v", tc.desc, tc.channels, ids))
+		assert.Equal(t, profile.Metadata, top.Thing.Metadata, fmt.Sprintf("%s: expected metadata %v got %v", tc.desc, profile.Metadata, top.Thing.Metadata))
+	}
+}
+
+func TestSaveTemplate(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	data := toJSON(templateRes{Channels: []channelTemplateRes{{Name: "{name}-data"}}})
+	invalidData := toJSON(templateRes{Channels: []channelTemplateRes{{Name: invalidName}}})
 
 	cases := []struct {
 		desc        string
 		req         string
-		id          string
 		contentType string
 		auth        string
 		status      int
 	}{
 		{
-			desc:        "update key for an existing thing",
+			desc:        "save valid template",
 			req:         data,
-			id:          sth.ID,
 			contentType: contentType,
 			auth:        token,
 			status:      http.StatusOK,
 		},
 		{
-			desc:        "update thing with conflicting key",
-			req:         data,
-			id:          sth.ID,
-			contentType: contentType,
-			auth:        token,
-			status:      http.StatusUnprocessableEntity,
-		},
-		{
-			desc:        "update key with empty JSON request",
-			req:         "{}",
-			id:          sth.ID,
+			desc:        "save template without channels",
+			req:         `{"channels":[]}`,
 			contentType: contentType,
 			auth:        token,
 			status:      http.StatusBadRequest,
 		},
 		{
-			desc:        "update key of non-existent thing",
-			req:         dummyData,
-			id:          strconv.FormatUint(wrongID, 10),
-			contentType: contentType,
-			auth:        token,
-			status:      http.StatusNotFound,
-		},
-		{
-			desc:        "update thing with invalid id",
-			req:         dummyData,
-			id:          "invalid",
+			desc:        "save template with invalid channel name",
+			req:         invalidData,
 			contentType: contentType,
 			auth:        token,
-			status:      http.StatusNotFound,
+			status:      http.StatusBadRequest,
 		},
 		{
-			desc:        "update thing with invalid user token",
+			desc:        "save template with invalid auth token",
 			req:         data,
-			id:          sth.ID,
 			contentType: contentType,
 			auth:        wrongValue,
 			status:      http.StatusForbidden,
 		},
 		{
-			desc:        "update thing with empty user token",
-			req:         data,
-			id:          sth.ID,
-			contentType: contentType,
-			auth:        "",
-			status:      http.StatusForbidden,
-		},
-		{
-			desc:        "update thing with invalid data format",
-			req:         "{",
-			id:          sth.ID,
-			contentType: contentType,
-			auth:        token,
-			status:      http.StatusBadRequest,
-		},
-		{
-			desc:        "update thing with empty request",
-			req:         "",
-			id:          sth.ID,
+			desc:        "save template with invalid request format",
+			req:         "}",
 			contentType: contentType,
 			auth:        token,
 			status:      http.StatusBadRequest,
 		},
 		{
-			desc:        "update thing without content type",
+			desc:        "save template without content type",
 			req:         data,
-			id:          sth.ID,
 			contentType: "",
 			auth:        token,
 			status:      http.StatusUnsupportedMediaType,
@@ -428,8 +535,8 @@ func TestUpdateKey(t *testing.T) {
 	for _, tc := range cases {
 		req := testRequest{
 			client:      ts.Client(),
-			method:      http.MethodPatch,
-			url:         fmt.Sprintf("%s/things/%s/key", ts.URL, tc.id),
+			method:      http.MethodPut,
+			url:         fmt.Sprintf("%s/things/template", ts.URL),
 			contentType: tc.contentType,
 			token:       tc.auth,
 			body:        strings.NewReader(tc.req),
@@ -440,71 +547,50 @@ func TestUpdateKey(t *testing.T) {
 	}
 }
 
-func TestViewThing(t *testing.T) {
-	svc := newService(map[string]string{token: email})
+func TestViewTemplate(t *testing.T) {
+	svc := newService(map[string]string{token: email, "other": "other@example.com"})
 	ts := newServer(svc)
 	defer ts.Close()
 
-	sth, err := svc.AddThing(token, thing)
+	tmpl := things.Template{
+		Channels: []things.ChannelTemplate{{Name: "{id}", Metadata: map[string]interface{}{"role": "data"}}},
+	}
+	err := svc.SaveTemplate(context.Background(), token, tmpl)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
-	thres := thingRes{
-		ID:       sth.ID,
-		Name:     sth.Name,
-		Key:      sth.Key,
-		Metadata: sth.Metadata,
-	}
-	data := toJSON(thres)
+	data := toJSON(templateRes{Channels: []channelTemplateRes{channelTemplateRes(tmpl.Channels[0])}})
 
 	cases := []struct {
 		desc   string
-		id     string
 		auth   string
 		status int
 		res    string
 	}{
 		{
-			desc:   "view existing thing",
-			id:     sth.ID,
+			desc:   "view existing template",
 			auth:   token,
 			status: http.StatusOK,
 			res:    data,
 		},
 		{
-			desc:   "view non-existent thing",
-			id:     strconv.FormatUint(wrongID, 10),
-			auth:   token,
+			desc:   "view template of user without one",
+			auth:   "other",
 			status: http.StatusNotFound,
 			res:    "",
 		},
 		{
-			desc:   "view thing by passing invalid token",
-			id:     sth.ID,
+			desc:   "view template with invalid auth token",
 			auth:   wrongValue,
 			status: http.StatusForbidden,
 			res:    "",
 		},
-		{
-			desc:   "view thing by passing empty token",
-			id:     sth.ID,
-			auth:   "",
-			status: http.StatusForbidden,
-			res:    "",
-		},
-		{
-			desc:   "view thing by passing invalid id",
-			id:     "invalid",
-			auth:   token,
-			status: http.StatusNotFound,
-			res:    "",
-		},
 	}
 
 	for _, tc := range cases {
 		req := testRequest{
 			client: ts.Client(),
 			method: http.MethodGet,
-			url:    fmt.Sprintf("%s/things/%s", ts.URL, tc.id),
+			url:    fmt.Sprintf("%s/things/template", ts.URL),
 			token:  tc.auth,
 		}
 		res, err := req.make()
@@ -517,136 +603,4133 @@ func TestViewThing(t *testing.T) {
 	}
 }
 
-func TestListThings(t *testing.T) {
+func TestRemoveTemplate(t *testing.T) {
 	svc := newService(map[string]string{token: email})
 	ts := newServer(svc)
 	defer ts.Close()
 
-	data := []thingRes{}
-	for i := 0; i < 100; i++ {
-		sth, err := svc.AddThing(token, thing)
-		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
-		thres := thingRes{
-			ID:       sth.ID,
-			Name:     sth.Name,
-			Key:      sth.Key,
-			Metadata: sth.Metadata,
-		}
-		data = append(data, thres)
-	}
+	err := svc.SaveTemplate(context.Background(), token, things.Template{Channels: []things.ChannelTemplate{{Name: "{id}"}}})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
-	thingURL := fmt.Sprintf("%s/things", ts.URL)
 	cases := []struct {
 		desc   string
 		auth   string
 		status int
-		url    string
-		res    []thingRes
 	}{
 		{
-			desc:   "get a list of things",
-			auth:   token,
-			status: http.StatusOK,
-			url:    fmt.Sprintf("%s?offset=%d&limit=%d", thingURL, 0, 5),
-			res:    data[0:5],
-		},
-		{
-			desc:   "get a list of things with invalid token",
+			desc:   "remove template with invalid auth token",
 			auth:   wrongValue,
 			status: http.StatusForbidden,
-			url:    fmt.Sprintf("%s?offset=%d&limit=%d", thingURL, 0, 1),
-			res:    nil,
-		},
-		{
-			desc:   "get a list of things with empty token",
-			auth:   "",
-			status: http.StatusForbidden,
-			url:    fmt.Sprintf("%s?offset=%d&limit=%d", thingURL, 0, 1),
-			res:    nil,
 		},
 		{
-			desc:   "get a list of things with negative offset",
+			desc:   "remove existing template",
 			auth:   token,
-			status: http.StatusBadRequest,
-			url:    fmt.Sprintf("%s?offset=%d&limit=%d", thingURL, -1, 5),
-			res:    nil,
+			status: http.StatusNoContent,
 		},
 		{
-			desc:   "get a list of things with negative limit",
+			desc:   "remove removed template",
 			auth:   token,
-			status: http.StatusBadRequest,
-			url:    fmt.Sprintf("%s?offset=%d&limit=%d", thingURL, 1, -5),
-			res:    nil,
+			status: http.StatusNoContent,
 		},
-		{
-			desc:   "get a list of things with zero limit",
-			auth:   token,
-			status: http.StatusBadRequest,
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodDelete,
+			url:    fmt.Sprintf("%s/things/template", ts.URL),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestReserveThings(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	cases := []struct {
+		desc        string
+		req         string
+		contentType string
+		auth        string
+		status      int
+		size        int
+	}{
+		{
+			desc:        "reserve things",
+			req:         `{"count":3}`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusCreated,
+			size:        3,
+		},
+		{
+			desc:        "reserve zero things",
+			req:         `{"count":0}`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+			size:        0,
+		},
+		{
+			desc:        "reserve too many things",
+			req:         `{"count":1001}`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+			size:        0,
+		},
+		{
+			desc:        "reserve things with invalid auth token",
+			req:         `{"count":3}`,
+			contentType: contentType,
+			auth:        wrongValue,
+			status:      http.StatusForbidden,
+			size:        0,
+		},
+		{
+			desc:        "reserve things with empty auth token",
+			req:         `{"count":3}`,
+			contentType: contentType,
+			auth:        "",
+			status:      http.StatusForbidden,
+			size:        0,
+		},
+		{
+			desc:        "reserve things with invalid request format",
+			req:         "}",
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+			size:        0,
+		},
+		{
+			desc:        "reserve things without content type",
+			req:         `{"count":3}`,
+			contentType: "",
+			auth:        token,
+			status:      http.StatusUnsupportedMediaType,
+			size:        0,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/things/reservations", ts.URL),
+			contentType: tc.contentType,
+			token:       tc.auth,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+
+		var body reservationsRes
+		json.NewDecoder(res.Body).Decode(&body)
+		assert.Equal(t, tc.size, len(body.Reservations), fmt.Sprintf("%s: expected %d reservations got %d", tc.desc, tc.size, len(body.Reservations)))
+	}
+}
+
+func TestUpdateThingsMetadata(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	for i := 0; i < 3; i++ {
+		svc.AddThing(context.Background(), token, thing)
+	}
+
+	cases := []struct {
+		desc        string
+		req         string
+		contentType string
+		auth        string
+		status      int
+		updated     int
+	}{
+		{
+			desc:        "update metadata of things matching selector",
+			req:         `{"selector":{"test":"data"},"patch":{"interval":60}}`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusOK,
+			updated:     3,
+		},
+		{
+			desc:        "update metadata of things not matching selector",
+			req:         `{"selector":{"test":"other"},"patch":{"interval":60}}`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusOK,
+			updated:     0,
+		},
+		{
+			desc:        "update metadata of all things using empty selector",
+			req:         `{"selector":{},"patch":{"interval":null}}`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusOK,
+			updated:     3,
+		},
+		{
+			desc:        "update metadata without selector",
+			req:         `{"patch":{"interval":60}}`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+			updated:     0,
+		},
+		{
+			desc:        "update metadata with empty patch",
+			req:         `{"selector":{"test":"data"},"patch":{}}`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+			updated:     0,
+		},
+		{
+			desc:        "update metadata with invalid auth token",
+			req:         `{"selector":{"test":"data"},"patch":{"interval":60}}`,
+			contentType: contentType,
+			auth:        wrongValue,
+			status:      http.StatusForbidden,
+			updated:     0,
+		},
+		{
+			desc:        "update metadata with empty auth token",
+			req:         `{"selector":{"test":"data"},"patch":{"interval":60}}`,
+			contentType: contentType,
+			auth:        "",
+			status:      http.StatusForbidden,
+			updated:     0,
+		},
+		{
+			desc:        "update metadata with invalid request format",
+			req:         "}",
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+			updated:     0,
+		},
+		{
+			desc:        "update metadata without content type",
+			req:         `{"selector":{"test":"data"},"patch":{"interval":60}}`,
+			contentType: "",
+			auth:        token,
+			status:      http.StatusUnsupportedMediaType,
+			updated:     0,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPatch,
+			url:         fmt.Sprintf("%s/things/metadata", ts.URL),
+			contentType: tc.contentType,
+			token:       tc.auth,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+
+		var body updateMetadataRes
+		json.NewDecoder(res.Body).Decode(&body)
+		assert.Equal(t, tc.updated, body.Updated, fmt.Sprintf("%s: expected %d updated things got %d", tc.desc, tc.updated, body.Updated))
+	}
+}
+
+func TestUpdateThing(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	data := toJSON(thing)
+	sth, _ := svc.AddThing(context.Background(), token, thing)
+
+	th := thing
+	th.Name = invalidName
+	invalidData := toJSON(th)
+
+	cases := []struct {
+		desc        string
+		req         string
+		id          string
+		contentType string
+		auth        string
+		status      int
+	}{
+		{
+			desc:        "update existing thing",
+			req:         data,
+			id:          sth.ID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusOK,
+		},
+		{
+			desc:        "update thing with empty JSON request",
+			req:         "{}",
+			id:          sth.ID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusOK,
+		},
+		{
+			desc:        "update non-existent thing",
+			req:         data,
+			id:          wrongID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusNotFound,
+		},
+		{
+			desc:        "update thing with invalid id",
+			req:         data,
+			id:          "invalid",
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "update thing with invalid user token",
+			req:         data,
+			id:          sth.ID,
+			contentType: contentType,
+			auth:        wrongValue,
+			status:      http.StatusForbidden,
+		},
+		{
+			desc:        "update thing with empty user token",
+			req:         data,
+			id:          sth.ID,
+			contentType: contentType,
+			auth:        "",
+			status:      http.StatusForbidden,
+		},
+		{
+			desc:        "update thing with invalid data format",
+			req:         "{",
+			id:          sth.ID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "update thing with empty request",
+			req:         "",
+			id:          sth.ID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "update thing without content type",
+			req:         data,
+			id:          sth.ID,
+			contentType: "",
+			auth:        token,
+			status:      http.StatusUnsupportedMediaType,
+		},
+		{
+			desc:        "update thing with invalid name",
+			req:         invalidData,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPut,
+			url:         fmt.Sprintf("%s/things/%s", ts.URL, tc.id),
+			contentType: tc.contentType,
+			token:       tc.auth,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestPatchThing(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	s, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc        string
+		req         string
+		id          string
+		contentType string
+		auth        string
+		status      int
+		name        string
+	}{
+		{
+			desc:        "patch existing thing name",
+			req:         `{"name":"patched"}`,
+			id:          s.ID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusOK,
+			name:        "patched",
+		},
+		{
+			desc:        "patch existing thing using merge patch content type",
+			req:         `{"metadata":{"test":null}}`,
+			id:          s.ID,
+			contentType: "application/merge-patch+json",
+			auth:        token,
+			status:      http.StatusOK,
+			name:        "patched",
+		},
+		{
+			desc:        "patch thing with read-only field",
+			req:         `{"id":"new"}`,
+			id:          s.ID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+			name:        "",
+		},
+		{
+			desc:        "patch thing with invalid name type",
+			req:         `{"name":1}`,
+			id:          s.ID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+			name:        "",
+		},
+		{
+			desc:        "patch thing with empty patch",
+			req:         `{}`,
+			id:          s.ID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+			name:        "",
+		},
+		{
+			desc:        "patch non-existent thing",
+			req:         `{"name":"patched"}`,
+			id:          wrongID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusNotFound,
+			name:        "",
+		},
+		{
+			desc:        "patch thing with invalid token",
+			req:         `{"name":"patched"}`,
+			id:          s.ID,
+			contentType: contentType,
+			auth:        wrongValue,
+			status:      http.StatusForbidden,
+			name:        "",
+		},
+		{
+			desc:        "patch thing with empty token",
+			req:         `{"name":"patched"}`,
+			id:          s.ID,
+			contentType: contentType,
+			auth:        "",
+			status:      http.StatusForbidden,
+			name:        "",
+		},
+		{
+			desc:        "patch thing with invalid request format",
+			req:         "}",
+			id:          s.ID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+			name:        "",
+		},
+		{
+			desc:        "patch thing without content type",
+			req:         `{"name":"patched"}`,
+			id:          s.ID,
+			contentType: "",
+			auth:        token,
+			status:      http.StatusUnsupportedMediaType,
+			name:        "",
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPatch,
+			url:         fmt.Sprintf("%s/things/%s", ts.URL, tc.id),
+			contentType: tc.contentType,
+			token:       tc.auth,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+
+		var body thingRes
+		json.NewDecoder(res.Body).Decode(&body)
+		assert.Equal(t, tc.name, body.Name, fmt.Sprintf("%s: expected name %s got %s", tc.desc, tc.name, body.Name))
+	}
+}
+
+func TestUpdateKey(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	th := thing
+	th.Key = "key"
+	sth, _ := svc.AddThing(context.Background(), token, th)
+
+	sth.Key = "new-key"
+	data := toJSON(sth)
+
+	sth.Key = "key"
+	dummyData := toJSON(sth)
+
+	cases := []struct {
+		desc        string
+		req         string
+		id          string
+		contentType string
+		auth        string
+		status      int
+	}{
+		{
+			desc:        "update key for an existing thing",
+			req:         data,
+			id:          sth.ID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusOK,
+		},
+		{
+			desc:        "update thing with conflicting key",
+			req:         data,
+			id:          sth.ID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusUnprocessableEntity,
+		},
+		{
+			desc:        "update key with empty JSON request",
+			req:         "{}",
+			id:          sth.ID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "update key of non-existent thing",
+			req:         dummyData,
+			id:          wrongID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusNotFound,
+		},
+		{
+			desc:        "update thing with invalid id",
+			req:         dummyData,
+			id:          "invalid",
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "update thing with invalid user token",
+			req:         data,
+			id:          sth.ID,
+			contentType: contentType,
+			auth:        wrongValue,
+			status:      http.StatusForbidden,
+		},
+		{
+			desc:        "update thing with empty user token",
+			req:         data,
+			id:          sth.ID,
+			contentType: contentType,
+			auth:        "",
+			status:      http.StatusForbidden,
+		},
+		{
+			desc:        "update thing with invalid data format",
+			req:         "{",
+			id:          sth.ID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "update thing with empty request",
+			req:         "",
+			id:          sth.ID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "update thing without content type",
+			req:         data,
+			id:          sth.ID,
+			contentType: "",
+			auth:        token,
+			status:      http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPatch,
+			url:         fmt.Sprintf("%s/things/%s/key", ts.URL, tc.id),
+			contentType: tc.contentType,
+			token:       tc.auth,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestViewThing(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	sth, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	thres := thingRes{
+		ID:       sth.ID,
+		Name:     sth.Name,
+		Key:      sth.Key,
+		State:    string(sth.State),
+		Metadata: sth.Metadata,
+	}
+	data := toJSON(thres)
+	selected := toJSON(thingRes{ID: sth.ID, Name: sth.Name})
+
+	cases := []struct {
+		desc   string
+		id     string
+		auth   string
+		status int
+		res    string
+	}{
+		{
+			desc:   "view existing thing",
+			id:     sth.ID,
+			auth:   token,
+			status: http.StatusOK,
+			res:    data,
+		},
+		{
+			desc:   "view existing thing with selected fields",
+			id:     fmt.Sprintf("%s?fields=id,name", sth.ID),
+			auth:   token,
+			status: http.StatusOK,
+			res:    selected,
+		},
+		{
+			desc:   "view existing thing with invalid field",
+			id:     fmt.Sprintf("%s?fields=name,owner", sth.ID),
+			auth:   token,
+			status: http.StatusBadRequest,
+			res:    "",
+		},
+		{
+			desc:   "view non-existent thing",
+			id:     wrongID,
+			auth:   token,
+			status: http.StatusNotFound,
+			res:    "",
+		},
+		{
+			desc:   "view thing by passing invalid token",
+			id:     sth.ID,
+			auth:   wrongValue,
+			status: http.StatusForbidden,
+			res:    "",
+		},
+		{
+			desc:   "view thing by passing empty token",
+			id:     sth.ID,
+			auth:   "",
+			status: http.StatusForbidden,
+			res:    "",
+		},
+		{
+			desc:   "view thing by passing invalid id",
+			id:     "invalid",
+			auth:   token,
+			status: http.StatusBadRequest,
+			res:    "",
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/things/%s", ts.URL, tc.id),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		body, err := ioutil.ReadAll(res.Body)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		data := strings.Trim(string(body), "\n")
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		assert.Equal(t, tc.res, data, fmt.Sprintf("%s: expected body %s got %s", tc.desc, tc.res, data))
+	}
+}
+
+func TestOnboardThing(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	sth, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	sch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = svc.Connect(context.Background(), token, []string{sch.ID}, []string{sth.ID}, things.FullAccess)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc     string
+		id       string
+		auth     string
+		status   int
+		channels []string
+	}{
+		{
+			desc:     "onboard existing thing",
+			id:       sth.ID,
+			auth:     token,
+			status:   http.StatusOK,
+			channels: []string{sch.ID},
+		},
+		{
+			desc:     "onboard non-existent thing",
+			id:       wrongID,
+			auth:     token,
+			status:   http.StatusNotFound,
+			channels: nil,
+		},
+		{
+			desc:     "onboard thing by passing invalid token",
+			id:       sth.ID,
+			auth:     wrongValue,
+			status:   http.StatusForbidden,
+			channels: nil,
+		},
+		{
+			desc:     "onboard thing by passing empty token",
+			id:       sth.ID,
+			auth:     "",
+			status:   http.StatusForbidden,
+			channels: nil,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/things/%s/onboarding", ts.URL, tc.id),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+
+		var body onboardingRes
+		json.NewDecoder(res.Body).Decode(&body)
+		assert.Equal(t, tc.channels, body.Channels, fmt.Sprintf("%s: expected channels %v got %v", tc.desc, tc.channels, body.Channels))
+		if tc.status == http.StatusOK {
+			assert.Equal(t, sth.Key, body.ThingKey, fmt.Sprintf("%s: expected key %s got %s", tc.desc, sth.Key, body.ThingKey))
+			assert.NotEmpty(t, body.Code, fmt.Sprintf("%s: expected non-empty code", tc.desc))
+		}
+	}
+}
+
+func TestIssueKey(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	sth, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	sch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = svc.Connect(context.Background(), token, []string{sch.ID}, []string{sth.ID}, things.FullAccess)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc        string
+		id          string
+		req         string
+		contentType string
+		auth        string
+		status      int
+		channels    []string
+	}{
+		{
+			desc:        "issue key of existing thing",
+			id:          sth.ID,
+			req:         `{"ttl":3600}`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusCreated,
+			channels:    []string{sch.ID},
+		},
+		{
+			desc:        "issue key without ttl",
+			id:          sth.ID,
+			req:         `{}`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+			channels:    nil,
+		},
+		{
+			desc:        "issue key with invalid request format",
+			id:          sth.ID,
+			req:         "}",
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+			channels:    nil,
+		},
+		{
+			desc:        "issue key without content type",
+			id:          sth.ID,
+			req:         `{"ttl":3600}`,
+			contentType: "",
+			auth:        token,
+			status:      http.StatusUnsupportedMediaType,
+			channels:    nil,
+		},
+		{
+			desc:        "issue key of non-existent thing",
+			id:          wrongID,
+			req:         `{"ttl":3600}`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusNotFound,
+			channels:    nil,
+		},
+		{
+			desc:        "issue key by passing invalid token",
+			id:          sth.ID,
+			req:         `{"ttl":3600}`,
+			contentType: contentType,
+			auth:        wrongValue,
+			status:      http.StatusForbidden,
+			channels:    nil,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/things/%s/keys", ts.URL, tc.id),
+			contentType: tc.contentType,
+			token:       tc.auth,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+
+		var body signedKeyRes
+		json.NewDecoder(res.Body).Decode(&body)
+		assert.Equal(t, tc.channels, body.Channels, fmt.Sprintf("%s: expected channels %v got %v", tc.desc, tc.channels, body.Channels))
+		if tc.status == http.StatusCreated {
+			assert.NotEmpty(t, body.Key, fmt.Sprintf("%s: expected non-empty key", tc.desc))
+		}
+	}
+}
+
+func TestOpenSession(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	sth, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	sch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = svc.Connect(context.Background(), token, []string{sch.ID}, []string{sth.ID}, things.FullAccess)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc        string
+		req         string
+		contentType string
+		auth        string
+		status      int
+		channels    []string
+	}{
+		{
+			desc:        "open session using thing key",
+			req:         `{"ttl":3600}`,
+			contentType: contentType,
+			auth:        sth.Key,
+			status:      http.StatusCreated,
+			channels:    []string{sch.ID},
+		},
+		{
+			desc:        "open session without ttl",
+			req:         `{}`,
+			contentType: contentType,
+			auth:        sth.Key,
+			status:      http.StatusBadRequest,
+			channels:    nil,
+		},
+		{
+			desc:        "open session with too long ttl",
+			req:         `{"ttl":172800}`,
+			contentType: contentType,
+			auth:        sth.Key,
+			status:      http.StatusBadRequest,
+			channels:    nil,
+		},
+		{
+			desc:        "open session with invalid request format",
+			req:         "}",
+			contentType: contentType,
+			auth:        sth.Key,
+			status:      http.StatusBadRequest,
+			channels:    nil,
+		},
+		{
+			desc:        "open session without content type",
+			req:         `{"ttl":3600}`,
+			contentType: "",
+			auth:        sth.Key,
+			status:      http.StatusUnsupportedMediaType,
+			channels:    nil,
+		},
+		{
+			desc:        "open session using invalid key",
+			req:         `{"ttl":3600}`,
+			contentType: contentType,
+			auth:        wrongValue,
+			status:      http.StatusForbidden,
+			channels:    nil,
+		},
+		{
+			desc:        "open session without key",
+			req:         `{"ttl":3600}`,
+			contentType: contentType,
+			auth:        "",
+			status:      http.StatusForbidden,
+			channels:    nil,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/things/sessions", ts.URL),
+			contentType: tc.contentType,
+			token:       tc.auth,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+
+		var body signedKeyRes
+		json.NewDecoder(res.Body).Decode(&body)
+		assert.Equal(t, tc.channels, body.Channels, fmt.Sprintf("%s: expected channels %v got %v", tc.desc, tc.channels, body.Channels))
+		if tc.status == http.StatusCreated {
+			assert.Equal(t, sth.ID, body.ThingID, fmt.Sprintf("%s: expected thing %s got %s", tc.desc, sth.ID, body.ThingID))
+			assert.NotEmpty(t, body.Key, fmt.Sprintf("%s: expected non-empty key", tc.desc))
+		}
+	}
+}
+
+func TestRevokeKeys(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	sth, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		id     string
+		auth   string
+		status int
+	}{
+		{
+			desc:   "revoke keys of existing thing",
+			id:     sth.ID,
+			auth:   token,
+			status: http.StatusNoContent,
+		},
+		{
+			desc:   "revoke keys of non-existent thing",
+			id:     wrongID,
+			auth:   token,
+			status: http.StatusNotFound,
+		},
+		{
+			desc:   "revoke keys by passing invalid token",
+			id:     sth.ID,
+			auth:   wrongValue,
+			status: http.StatusForbidden,
+		},
+		{
+			desc:   "revoke keys by passing empty token",
+			id:     sth.ID,
+			auth:   "",
+			status: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodDelete,
+			url:    fmt.Sprintf("%s/things/%s/keys", ts.URL, tc.id),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestIssueThingKey(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	sth, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc        string
+		id          string
+		req         string
+		contentType string
+		auth        string
+		status      int
+		scope       string
+	}{
+		{
+			desc:        "issue publish key",
+			id:          sth.ID,
+			req:         `{"scope":"publish"}`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusCreated,
+			scope:       "publish",
+		},
+		{
+			desc:        "issue subscribe key",
+			id:          sth.ID,
+			req:         `{"scope":"subscribe"}`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusCreated,
+			scope:       "subscribe",
+		},
+		{
+			desc:        "issue key with invalid scope",
+			id:          sth.ID,
+			req:         `{"scope":"manage"}`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "issue key without scope",
+			id:          sth.ID,
+			req:         "{}",
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "issue key with invalid request format",
+			id:          sth.ID,
+			req:         "}",
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "issue key without content type",
+			id:          sth.ID,
+			req:         `{"scope":"publish"}`,
+			contentType: "",
+			auth:        token,
+			status:      http.StatusUnsupportedMediaType,
+		},
+		{
+			desc:        "issue key of non-existent thing",
+			id:          wrongID,
+			req:         `{"scope":"publish"}`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusNotFound,
+		},
+		{
+			desc:        "issue key with invalid token",
+			id:          sth.ID,
+			req:         `{"scope":"publish"}`,
+			contentType: contentType,
+			auth:        wrongValue,
+			status:      http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/things/%s/scoped-keys", ts.URL, tc.id),
+			contentType: tc.contentType,
+			token:       tc.auth,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		var body thingKeyRes
+		json.NewDecoder(res.Body).Decode(&body)
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		assert.Equal(t, tc.scope, body.Scope, fmt.Sprintf("%s: expected scope %s got %s", tc.desc, tc.scope, body.Scope))
+	}
+}
+
+func TestRevokeThingKey(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	sth, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	tk, err := svc.IssueThingKey(context.Background(), token, sth.ID, things.ScopePublish)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		id     string
+		keyID  string
+		auth   string
+		status int
+	}{
+		{
+			desc:   "revoke key by passing invalid token",
+			id:     sth.ID,
+			keyID:  tk.ID,
+			auth:   wrongValue,
+			status: http.StatusForbidden,
+		},
+		{
+			desc:   "revoke existing key",
+			id:     sth.ID,
+			keyID:  tk.ID,
+			auth:   token,
+			status: http.StatusNoContent,
+		},
+		{
+			desc:   "revoke non-existent key",
+			id:     sth.ID,
+			keyID:  tk.ID,
+			auth:   token,
+			status: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodDelete,
+			url:    fmt.Sprintf("%s/things/%s/scoped-keys/%s", ts.URL, tc.id, tc.keyID),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestEnableThing(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	sth, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		id     string
+		auth   string
+		status int
+	}{
+		{
+			desc:   "enable existing thing",
+			id:     sth.ID,
+			auth:   token,
+			status: http.StatusNoContent,
+		},
+		{
+			desc:   "enable non-existent thing",
+			id:     wrongID,
+			auth:   token,
+			status: http.StatusNotFound,
+		},
+		{
+			desc:   "enable thing by passing invalid token",
+			id:     sth.ID,
+			auth:   wrongValue,
+			status: http.StatusForbidden,
+		},
+		{
+			desc:   "enable thing by passing empty token",
+			id:     sth.ID,
+			auth:   "",
+			status: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodPost,
+			url:    fmt.Sprintf("%s/things/%s/enable", ts.URL, tc.id),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestDisableThing(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	sth, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		id     string
+		auth   string
+		status int
+	}{
+		{
+			desc:   "disable existing thing",
+			id:     sth.ID,
+			auth:   token,
+			status: http.StatusNoContent,
+		},
+		{
+			desc:   "disable non-existent thing",
+			id:     wrongID,
+			auth:   token,
+			status: http.StatusNotFound,
+		},
+		{
+			desc:   "disable thing by passing invalid token",
+			id:     sth.ID,
+			auth:   wrongValue,
+			status: http.StatusForbidden,
+		},
+		{
+			desc:   "disable thing by passing empty token",
+			id:     sth.ID,
+			auth:   "",
+			status: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodPost,
+			url:    fmt.Sprintf("%s/things/%s/disable", ts.URL, tc.id),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestListThings(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	data := []thingRes{}
+	selected := []thingRes{}
+	for i := 0; i < 100; i++ {
+		th := thing
+		if i == 0 {
+			th.Tags = []string{"edge", "v2"}
+		}
+		sth, err := svc.AddThing(context.Background(), token, th)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		thres := thingRes{
+			ID:       sth.ID,
+			Name:     sth.Name,
+			Key:      sth.Key,
+			State:    string(sth.State),
+			Tags:     sth.Tags,
+			Metadata: sth.Metadata,
+		}
+		if i == 0 {
+			err := svc.RecordActivity(context.Background(), sth.ID)
+			require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+			seen, err := svc.ViewThing(context.Background(), token, sth.ID)
+			require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+			thres.LastSeen = seen.LastSeen.Unix()
+		}
+		data = append(data, thres)
+		selected = append(selected, thingRes{ID: sth.ID, Name: sth.Name})
+	}
+
+	thingURL := fmt.Sprintf("%s/things", ts.URL)
+	cases := []struct {
+		desc   string
+		auth   string
+		status int
+		url    string
+		res    []thingRes
+	}{
+		{
+			desc:   "get a list of things with selected fields",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s?offset=%d&limit=%d&fields=%s", thingURL, 0, 5, "id,name"),
+			res:    selected[0:5],
+		},
+		{
+			desc:   "get a list of things with invalid field",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s?offset=%d&limit=%d&fields=%s", thingURL, 0, 5, "name,owner"),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of things",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s?offset=%d&limit=%d", thingURL, 0, 5),
+			res:    data[0:5],
+		},
+		{
+			desc:   "get a list of things with invalid token",
+			auth:   wrongValue,
+			status: http.StatusForbidden,
+			url:    fmt.Sprintf("%s?offset=%d&limit=%d", thingURL, 0, 1),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of things with empty token",
+			auth:   "",
+			status: http.StatusForbidden,
+			url:    fmt.Sprintf("%s?offset=%d&limit=%d", thingURL, 0, 1),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of things with negative offset",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s?offset=%d&limit=%d", thingURL, -1, 5),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of things with negative limit",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s?offset=%d&limit=%d", thingURL, 1, -5),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of things with zero limit",
+			auth:   token,
+			status: http.StatusBadRequest,
 			url:    fmt.Sprintf("%s?offset=%d&limit=%d", thingURL, 1, 0),
 			res:    nil,
 		},
 		{
-			desc:   "get a list of things without offset",
+			desc:   "get a list of things without offset",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s?limit=%d", thingURL, 5),
+			res:    data[0:5],
+		},
+		{
+			desc:   "get a list of things without limit",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s?offset=%d", thingURL, 1),
+			res:    data[1:11],
+		},
+		{
+			desc:   "get a list of things with redundant query params",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s?offset=%d&limit=%d&value=something", thingURL, 0, 5),
+			res:    data[0:5],
+		},
+		{
+			desc:   "get a list of things with limit greater than max",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s?offset=%d&limit=%d", thingURL, 0, 110),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of things with default URL",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s%s", thingURL, ""),
+			res:    data[0:10],
+		},
+		{
+			desc:   "get a list of things with invalid number of params",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s%s", thingURL, "?offset=4&limit=4&limit=5&offset=5"),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of things with invalid offset",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s%s", thingURL, "?offset=e&limit=5"),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of things with invalid limit",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s%s", thingURL, "?offset=5&limit=e"),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of things filtering with invalid name",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s?offset=%d&limit=%d&name=%s", thingURL, 0, 5, invalidName),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of things filtering with matching metadata",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s?offset=%d&limit=%d&metadata=%s", thingURL, 0, 5, url.QueryEscape(`{"test":"data"}`)),
+			res:    data[0:5],
+		},
+		{
+			desc:   "get a list of things filtering with non-matching metadata",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s?offset=%d&limit=%d&metadata=%s", thingURL, 0, 5, url.QueryEscape(`{"test":"other"}`)),
+			res:    []thingRes{},
+		},
+		{
+			desc:   "get a list of things filtering with invalid metadata",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s?offset=%d&limit=%d&metadata=%s", thingURL, 0, 5, "invalid"),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of things holding all the tags",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s?offset=%d&limit=%d&tags=%s", thingURL, 0, 5, "edge,v2"),
+			res:    data[0:1],
+		},
+		{
+			desc:   "get a list of things holding any of the tags",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s?offset=%d&limit=%d&tags=%s&match=%s", thingURL, 0, 5, "v2,v3", "any"),
+			res:    data[0:1],
+		},
+		{
+			desc:   "get a list of things holding non-matching tags",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s?offset=%d&limit=%d&tags=%s", thingURL, 0, 5, "v2,v3"),
+			res:    []thingRes{},
+		},
+		{
+			desc:   "get a list of things with invalid tags match",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s?offset=%d&limit=%d&tags=%s&match=%s", thingURL, 0, 5, "edge", "some"),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of online things",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s?offset=%d&limit=%d&status=%s", thingURL, 0, 5, "online"),
+			res:    data[0:1],
+		},
+		{
+			desc:   "get a list of stale things",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s?offset=%d&limit=%d&status=%s", thingURL, 0, 5, "stale"),
+			res:    data[1:5],
+		},
+		{
+			desc:   "get a list of things with invalid status",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s?offset=%d&limit=%d&status=%s", thingURL, 0, 5, "offline"),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of things past the cursor",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s?limit=%d&cursor=%s", thingURL, 5, things.EncodeCursor(data[4].ID)),
+			res:    data[5:10],
+		},
+		{
+			desc:   "get a list of things with malformed cursor",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s?limit=%d&cursor=%s", thingURL, 5, "!"),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of things with both cursor and offset",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s?offset=%d&limit=%d&cursor=%s", thingURL, 5, 5, things.EncodeCursor(data[4].ID)),
+			res:    nil,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    tc.url,
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		var data thingsPageRes
+		json.NewDecoder(res.Body).Decode(&data)
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		assert.ElementsMatch(t, tc.res, data.Things, fmt.Sprintf("%s: expected body %v got %v", tc.desc, tc.res, data.Things))
+	}
+}
+
+func TestListThingsByChannel(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	sch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	data := []thingRes{}
+	for i := 0; i < 101; i++ {
+		sth, err := svc.AddThing(context.Background(), token, thing)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		err = svc.Connect(context.Background(), token, []string{sch.ID}, []string{sth.ID}, things.FullAccess)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+		thres := thingRes{
+			ID:       sth.ID,
+			Name:     sth.Name,
+			Key:      sth.Key,
+			State:    string(sth.State),
+			Metadata: sth.Metadata,
+		}
+		data = append(data, thres)
+	}
+	thingURL := fmt.Sprintf("%s/channels", ts.URL)
+
+	// Wait for things and channels to connect.
+	time.Sleep(time.Second)
+
+	cases := []struct {
+		desc   string
+		auth   string
+		status int
+		url    string
+		res    []thingRes
+	}{
+		{
+			desc:   "get a list of things by channel",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s/%s/things?offset=%d&limit=%d", thingURL, sch.ID, 0, 5),
+			res:    data[0:5],
+		},
+		{
+			desc:   "get a list of things by channel with invalid token",
+			auth:   wrongValue,
+			status: http.StatusForbidden,
+			url:    fmt.Sprintf("%s/%s/things?offset=%d&limit=%d", thingURL, sch.ID, 0, 1),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of things by channel with empty token",
+			auth:   "",
+			status: http.StatusForbidden,
+			url:    fmt.Sprintf("%s/%s/things?offset=%d&limit=%d", thingURL, sch.ID, 0, 1),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of things by channel with negative offset",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s/%s/things?offset=%d&limit=%d", thingURL, sch.ID, -1, 5),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of things by channel with negative limit",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s/%s/things?offset=%d&limit=%d", thingURL, sch.ID, 1, -5),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of things by channel with zero limit",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s/%s/things?offset=%d&limit=%d", thingURL, sch.ID, 1, 0),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of things by channel without offset",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s/%s/things?limit=%d", thingURL, sch.ID, 5),
+			res:    data[0:5],
+		},
+		{
+			desc:   "get a list of things by channel without limit",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s/%s/things?offset=%d", thingURL, sch.ID, 1),
+			res:    data[1:11],
+		},
+		{
+			desc:   "get a list of things by channel with redundant query params",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s/%s/things?offset=%d&limit=%d&value=something", thingURL, sch.ID, 0, 5),
+			res:    data[0:5],
+		},
+		{
+			desc:   "get a list of things by channel with limit greater than max",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s/%s/things?offset=%d&limit=%d", thingURL, sch.ID, 0, 110),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of things by channel with default URL",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s/%s/things", thingURL, sch.ID),
+			res:    data[0:10],
+		},
+		{
+			desc:   "get a list of things by channel with invalid number of params",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s/%s/things%s", thingURL, sch.ID, "?offset=4&limit=4&limit=5&offset=5"),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of things by channel with invalid offset",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s/%s/things%s", thingURL, sch.ID, "?offset=e&limit=5"),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of things by channel with invalid limit",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s/%s/things%s", thingURL, sch.ID, "?offset=5&limit=e"),
+			res:    nil,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    tc.url,
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		var data thingsPageRes
+		json.NewDecoder(res.Body).Decode(&data)
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		assert.ElementsMatch(t, tc.res, data.Things, fmt.Sprintf("%s: expected body %v got %v", tc.desc, tc.res, data.Things))
+	}
+}
+
+func TestRemoveThing(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	sth, _ := svc.AddThing(context.Background(), token, thing)
+
+	cases := []struct {
+		desc   string
+		id     string
+		auth   string
+		status int
+	}{
+		{
+			desc:   "delete existing thing",
+			id:     sth.ID,
+			auth:   token,
+			status: http.StatusNoContent,
+		},
+		{
+			desc:   "delete non-existent thing",
+			id:     wrongID,
+			auth:   token,
+			status: http.StatusNoContent,
+		},
+		{
+			desc:   "delete thing with invalid token",
+			id:     sth.ID,
+			auth:   wrongValue,
+			status: http.StatusForbidden,
+		},
+		{
+			desc:   "delete thing with empty token",
+			id:     sth.ID,
+			auth:   "",
+			status: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodDelete,
+			url:    fmt.Sprintf("%s/things/%s", ts.URL, tc.id),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestCreateChannel(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	data := toJSON(channel)
+
+	th := channel
+	th.Name = invalidName
+	invalidData := toJSON(th)
+
+	cases := []struct {
+		desc        string
+		req         string
+		contentType string
+		auth        string
+		status      int
+		location    string
+	}{
+		{
+			desc:        "create new channel",
+			req:         data,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusCreated,
+			location:    "/channels/123e4567-e89b-12d3-a456-000000000001",
+		},
+		{
+			desc:        "create new channel with invalid token",
+			req:         data,
+			contentType: contentType,
+			auth:        wrongValue,
+			status:      http.StatusForbidden,
+			location:    "",
+		},
+		{
+			desc:        "create new channel with empty token",
+			req:         data,
+			contentType: contentType,
+			auth:        "",
+			status:      http.StatusForbidden,
+			location:    "",
+		},
+		{
+			desc:        "create new channel with invalid data format",
+			req:         "{",
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+			location:    "",
+		},
+		{
+			desc:        "create new channel with empty JSON request",
+			req:         "{}",
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusCreated,
+			location:    "/channels/123e4567-e89b-12d3-a456-000000000002",
+		},
+		{
+			desc:        "create new channel with empty request",
+			req:         "",
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+			location:    "",
+		},
+		{
+			desc:        "create new channel without content type",
+			req:         data,
+			contentType: "",
+			auth:        token,
+			status:      http.StatusUnsupportedMediaType,
+			location:    "",
+		},
+		{
+			desc:        "create new channel with invalid name",
+			req:         invalidData,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+			location:    "",
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/channels", ts.URL),
+			contentType: tc.contentType,
+			token:       tc.auth,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+
+		location := res.Header.Get("Location")
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		assert.Equal(t, tc.location, location, fmt.Sprintf("%s: expected location %s got %s", tc.desc, tc.location, location))
+	}
+}
+
+func TestUpdateChannel(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	sch, _ := svc.CreateChannel(context.Background(), token, channel)
+
+	ch := channel
+	ch.Name = "updated_channel"
+	updateData := toJSON(ch)
+
+	ch.Name = invalidName
+	invalidData := toJSON(ch)
+
+	cases := []struct {
+		desc        string
+		req         string
+		id          string
+		contentType string
+		auth        string
+		status      int
+	}{
+		{
+			desc:        "update existing channel",
+			req:         updateData,
+			id:          sch.ID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusOK,
+		},
+		{
+			desc:        "update non-existing channel",
+			req:         updateData,
+			id:          wrongID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusNotFound,
+		},
+		{
+			desc:        "update channel with invalid id",
+			req:         updateData,
+			id:          "invalid",
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "update channel with invalid token",
+			req:         updateData,
+			id:          sch.ID,
+			contentType: contentType,
+			auth:        wrongValue,
+			status:      http.StatusForbidden,
+		},
+		{
+			desc:        "update channel with empty token",
+			req:         updateData,
+			id:          sch.ID,
+			contentType: contentType,
+			auth:        "",
+			status:      http.StatusForbidden,
+		},
+		{
+			desc:        "update channel with invalid data format",
+			req:         "}",
+			id:          sch.ID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "update channel with empty JSON object",
+			req:         "{}",
+			id:          sch.ID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusOK,
+		},
+		{
+			desc:        "update channel with empty request",
+			req:         "",
+			id:          sch.ID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "update channel with missing content type",
+			req:         updateData,
+			id:          sch.ID,
+			contentType: "",
+			auth:        token,
+			status:      http.StatusUnsupportedMediaType,
+		},
+		{
+			desc:        "update channel with invalid name",
+			req:         invalidData,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPut,
+			url:         fmt.Sprintf("%s/channels/%s", ts.URL, tc.id),
+			contentType: tc.contentType,
+			token:       tc.auth,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestPatchChannel(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	s, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc        string
+		req         string
+		id          string
+		contentType string
+		auth        string
+		status      int
+		name        string
+	}{
+		{
+			desc:        "patch existing channel name",
+			req:         `{"name":"patched"}`,
+			id:          s.ID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusOK,
+			name:        "patched",
+		},
+		{
+			desc:        "patch existing channel using merge patch content type",
+			req:         `{"metadata":{"test":null}}`,
+			id:          s.ID,
+			contentType: "application/merge-patch+json",
+			auth:        token,
+			status:      http.StatusOK,
+			name:        "patched",
+		},
+		{
+			desc:        "patch channel with read-only field",
+			req:         `{"id":"new"}`,
+			id:          s.ID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+			name:        "",
+		},
+		{
+			desc:        "patch channel with invalid name type",
+			req:         `{"name":1}`,
+			id:          s.ID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+			name:        "",
+		},
+		{
+			desc:        "patch channel with empty patch",
+			req:         `{}`,
+			id:          s.ID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+			name:        "",
+		},
+		{
+			desc:        "patch non-existent channel",
+			req:         `{"name":"patched"}`,
+			id:          wrongID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusNotFound,
+			name:        "",
+		},
+		{
+			desc:        "patch channel with invalid token",
+			req:         `{"name":"patched"}`,
+			id:          s.ID,
+			contentType: contentType,
+			auth:        wrongValue,
+			status:      http.StatusForbidden,
+			name:        "",
+		},
+		{
+			desc:        "patch channel with empty token",
+			req:         `{"name":"patched"}`,
+			id:          s.ID,
+			contentType: contentType,
+			auth:        "",
+			status:      http.StatusForbidden,
+			name:        "",
+		},
+		{
+			desc:        "patch channel with invalid request format",
+			req:         "}",
+			id:          s.ID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+			name:        "",
+		},
+		{
+			desc:        "patch channel without content type",
+			req:         `{"name":"patched"}`,
+			id:          s.ID,
+			contentType: "",
+			auth:        token,
+			status:      http.StatusUnsupportedMediaType,
+			name:        "",
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPatch,
+			url:         fmt.Sprintf("%s/channels/%s", ts.URL, tc.id),
+			contentType: tc.contentType,
+			token:       tc.auth,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+
+		var body channelRes
+		json.NewDecoder(res.Body).Decode(&body)
+		assert.Equal(t, tc.name, body.Name, fmt.Sprintf("%s: expected name %s got %s", tc.desc, tc.name, body.Name))
+	}
+}
+
+func TestViewChannel(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	sch, _ := svc.CreateChannel(context.Background(), token, channel)
+
+	sth, _ := svc.AddThing(context.Background(), token, thing)
+	svc.Connect(context.Background(), token, []string{sch.ID}, []string{sth.ID}, things.FullAccess)
+
+	chres := channelRes{
+		ID:       sch.ID,
+		Name:     sch.Name,
+		State:    string(sch.State),
+		Metadata: sch.Metadata,
+	}
+	data := toJSON(chres)
+	selected := toJSON(channelRes{ID: sch.ID, Metadata: sch.Metadata})
+
+	cases := []struct {
+		desc   string
+		id     string
+		auth   string
+		status int
+		res    string
+	}{
+		{
+			desc:   "view existing channel with selected fields",
+			id:     fmt.Sprintf("%s?fields=metadata", sch.ID),
+			auth:   token,
+			status: http.StatusOK,
+			res:    selected,
+		},
+		{
+			desc:   "view existing channel with invalid field",
+			id:     fmt.Sprintf("%s?fields=things", sch.ID),
+			auth:   token,
+			status: http.StatusBadRequest,
+			res:    "",
+		},
+		{
+			desc:   "view existing channel",
+			id:     sch.ID,
+			auth:   token,
+			status: http.StatusOK,
+			res:    data,
+		},
+		{
+			desc:   "view non-existent channel",
+			id:     wrongID,
+			auth:   token,
+			status: http.StatusNotFound,
+			res:    "",
+		},
+		{
+			desc:   "view channel with invalid token",
+			id:     sch.ID,
+			auth:   wrongValue,
+			status: http.StatusForbidden,
+			res:    "",
+		},
+		{
+			desc:   "view channel with empty token",
+			id:     sch.ID,
+			auth:   "",
+			status: http.StatusForbidden,
+			res:    "",
+		},
+		{
+			desc:   "view channel with invalid id",
+			id:     "invalid",
+			auth:   token,
+			status: http.StatusBadRequest,
+			res:    "",
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/channels/%s", ts.URL, tc.id),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		data, err := ioutil.ReadAll(res.Body)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		body := strings.Trim(string(data), "\n")
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		assert.Equal(t, tc.res, body, fmt.Sprintf("%s: expected body %s got %s", tc.desc, tc.res, body))
+	}
+}
+
+func TestCountThings(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	for i := 0; i < 10; i++ {
+		th := thing
+		if i%2 == 0 {
+			th.Tags = []string{"edge"}
+		}
+		_, err := svc.AddThing(context.Background(), token, th)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	countURL := fmt.Sprintf("%s/things/count", ts.URL)
+	cases := []struct {
+		desc   string
+		auth   string
+		url    string
+		status int
+		total  uint64
+	}{
+		{
+			desc:   "count all things",
+			auth:   token,
+			url:    countURL,
+			status: http.StatusOK,
+			total:  10,
+		},
+		{
+			desc:   "count things holding the tag",
+			auth:   token,
+			url:    fmt.Sprintf("%s?tags=%s", countURL, "edge"),
+			status: http.StatusOK,
+			total:  5,
+		},
+		{
+			desc:   "count things with invalid status",
+			auth:   token,
+			url:    fmt.Sprintf("%s?status=%s", countURL, "invalid"),
+			status: http.StatusBadRequest,
+			total:  0,
+		},
+		{
+			desc:   "count things with invalid metadata",
+			auth:   token,
+			url:    fmt.Sprintf("%s?metadata=%s", countURL, "invalid"),
+			status: http.StatusBadRequest,
+			total:  0,
+		},
+		{
+			desc:   "count things with invalid token",
+			auth:   wrongValue,
+			url:    countURL,
+			status: http.StatusForbidden,
+			total:  0,
+		},
+		{
+			desc:   "count things without token",
+			auth:   "",
+			url:    countURL,
+			status: http.StatusForbidden,
+			total:  0,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    tc.url,
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+
+		var body countRes
+		json.NewDecoder(res.Body).Decode(&body)
+		assert.Equal(t, tc.total, body.Total, fmt.Sprintf("%s: expected total %d got %d", tc.desc, tc.total, body.Total))
+	}
+}
+
+func TestCountChannels(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	for i := 0; i < 10; i++ {
+		ch := channel
+		if i%2 == 0 {
+			ch.Metadata = map[string]interface{}{"region": "eu"}
+		}
+		_, err := svc.CreateChannel(context.Background(), token, ch)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	countURL := fmt.Sprintf("%s/channels/count", ts.URL)
+	cases := []struct {
+		desc   string
+		auth   string
+		url    string
+		status int
+		total  uint64
+	}{
+		{
+			desc:   "count all channels",
+			auth:   token,
+			url:    countURL,
+			status: http.StatusOK,
+			total:  10,
+		},
+		{
+			desc:   "count channels containing the metadata",
+			auth:   token,
+			url:    fmt.Sprintf("%s?metadata=%s", countURL, url.QueryEscape(`{"region":"eu"}`)),
+			status: http.StatusOK,
+			total:  5,
+		},
+		{
+			desc:   "count channels with invalid token",
+			auth:   wrongValue,
+			url:    countURL,
+			status: http.StatusForbidden,
+			total:  0,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    tc.url,
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+
+		var body countRes
+		json.NewDecoder(res.Body).Decode(&body)
+		assert.Equal(t, tc.total, body.Total, fmt.Sprintf("%s: expected total %d got %d", tc.desc, tc.total, body.Total))
+	}
+}
+
+func TestListChannels(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	channels := []channelRes{}
+	selected := []channelRes{}
+	for i := 0; i < 101; i++ {
+		ch := channel
+		if i == 0 {
+			ch.Tags = []string{"plant-3"}
+		}
+		sch, err := svc.CreateChannel(context.Background(), token, ch)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		sth, err := svc.AddThing(context.Background(), token, thing)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		svc.Connect(context.Background(), token, []string{sch.ID}, []string{sth.ID}, things.FullAccess)
+
+		chres := channelRes{
+			ID:       sch.ID,
+			Name:     sch.Name,
+			State:    string(sch.State),
+			Tags:     sch.Tags,
+			Metadata: sch.Metadata,
+		}
+		channels = append(channels, chres)
+		selected = append(selected, channelRes{ID: sch.ID, Name: sch.Name})
+	}
+	channelURL := fmt.Sprintf("%s/channels", ts.URL)
+
+	cases := []struct {
+		desc   string
+		auth   string
+		status int
+		url    string
+		res    []channelRes
+	}{
+		{
+			desc:   "get a list of channels",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s?offset=%d&limit=%d", channelURL, 0, 6),
+			res:    channels[0:6],
+		},
+		{
+			desc:   "get a list of channels with selected fields",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s?offset=%d&limit=%d&fields=%s", channelURL, 0, 6, "id,name"),
+			res:    selected[0:6],
+		},
+		{
+			desc:   "get a list of channels filtering with matching metadata",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s?offset=%d&limit=%d&metadata=%s", channelURL, 0, 5, url.QueryEscape(`{"test":"data"}`)),
+			res:    channels[0:5],
+		},
+		{
+			desc:   "get a list of channels filtering with non-matching metadata",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s?offset=%d&limit=%d&metadata=%s", channelURL, 0, 5, url.QueryEscape(`{"test":"other"}`)),
+			res:    []channelRes{},
+		},
+		{
+			desc:   "get a list of channels filtering with invalid metadata",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s?offset=%d&limit=%d&metadata=%s", channelURL, 0, 5, "invalid"),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of channels with invalid token",
+			auth:   wrongValue,
+			status: http.StatusForbidden,
+			url:    fmt.Sprintf("%s?offset=%d&limit=%d", channelURL, 0, 1),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of channels with empty token",
+			auth:   "",
+			status: http.StatusForbidden,
+			url:    fmt.Sprintf("%s?offset=%d&limit=%d", channelURL, 0, 1),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of channels with negative offset",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s?offset=%d&limit=%d", channelURL, -1, 5),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of channels with negative limit",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s?offset=%d&limit=%d", channelURL, -1, 5),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of channels with zero limit",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s?offset=%d&limit=%d", channelURL, 1, 0),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of channels with no offset provided",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s?limit=%d", channelURL, 5),
+			res:    channels[0:5],
+		},
+		{
+			desc:   "get a list of channels with no limit provided",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s?offset=%d", channelURL, 1),
+			res:    channels[1:11],
+		},
+		{
+			desc:   "get a list of channels with redundant query params",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s?offset=%d&limit=%d&value=something", channelURL, 0, 5),
+			res:    channels[0:5],
+		},
+		{
+			desc:   "get a list of channels with limit greater than max",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s?offset=%d&limit=%d", channelURL, 0, 110),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of channels with default URL",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s%s", channelURL, ""),
+			res:    channels[0:10],
+		},
+		{
+			desc:   "get a list of channels with invalid number of params",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s%s", channelURL, "?offset=4&limit=4&limit=5&offset=5"),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of channels with invalid offset",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s%s", channelURL, "?offset=e&limit=5"),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of channels with invalid limit",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s%s", channelURL, "?offset=5&limit=e"),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of channels with invalid name",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s?offset=%d&limit=%d&name=%s", channelURL, 0, 10, invalidName),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of channels holding the tag",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s?offset=%d&limit=%d&tags=%s", channelURL, 0, 10, "plant-3"),
+			res:    channels[0:1],
+		},
+		{
+			desc:   "get a list of channels with invalid tags match",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s?offset=%d&limit=%d&tags=%s&match=%s", channelURL, 0, 10, "plant-3", "some"),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of channels past the cursor",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s?limit=%d&cursor=%s", channelURL, 10, things.EncodeCursor(channels[94].ID)),
+			res:    channels[95:101],
+		},
+		{
+			desc:   "get a list of channels with malformed cursor",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s?limit=%d&cursor=%s", channelURL, 10, "!"),
+			res:    nil,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    tc.url,
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		var body channelsPageRes
+		json.NewDecoder(res.Body).Decode(&body)
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		assert.ElementsMatch(t, tc.res, body.Channels, fmt.Sprintf("%s: expected body %v got %v", tc.desc, tc.res, body.Channels))
+	}
+}
+
+func TestListChannelsByThing(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	sth, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	channels := []channelRes{}
+	for i := 0; i < 101; i++ {
+		sch, err := svc.CreateChannel(context.Background(), token, channel)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		err = svc.Connect(context.Background(), token, []string{sch.ID}, []string{sth.ID}, things.FullAccess)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+		chres := channelRes{
+			ID:       sch.ID,
+			Name:     sch.Name,
+			State:    string(sch.State),
+			Metadata: sch.Metadata,
+		}
+		channels = append(channels, chres)
+	}
+	channelURL := fmt.Sprintf("%s/things", ts.URL)
+
+	cases := []struct {
+		desc   string
+		auth   string
+		status int
+		url    string
+		res    []channelRes
+	}{
+		{
+			desc:   "get a list of channels by thing",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s/%s/channels?offset=%d&limit=%d", channelURL, sth.ID, 0, 6),
+			res:    channels[0:6],
+		},
+		{
+			desc:   "get a list of channels by thing with invalid token",
+			auth:   wrongValue,
+			status: http.StatusForbidden,
+			url:    fmt.Sprintf("%s/%s/channels?offset=%d&limit=%d", channelURL, sth.ID, 0, 1),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of channels by thing with empty token",
+			auth:   "",
+			status: http.StatusForbidden,
+			url:    fmt.Sprintf("%s/%s/channels?offset=%d&limit=%d", channelURL, sth.ID, 0, 1),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of channels by thing with negative offset",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s/%s/channels?offset=%d&limit=%d", channelURL, sth.ID, -1, 5),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of channels by thing with negative limit",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s/%s/channels?offset=%d&limit=%d", channelURL, sth.ID, -1, 5),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of channels by thing with zero limit",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s/%s/channels?offset=%d&limit=%d", channelURL, sth.ID, 1, 0),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of channels by thing with no offset provided",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s/%s/channels?limit=%d", channelURL, sth.ID, 5),
+			res:    channels[0:5],
+		},
+		{
+			desc:   "get a list of channels by thing with no limit provided",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s/%s/channels?offset=%d", channelURL, sth.ID, 1),
+			res:    channels[1:11],
+		},
+		{
+			desc:   "get a list of channels by thing with redundant query params",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s/%s/channels?offset=%d&limit=%d&value=something", channelURL, sth.ID, 0, 5),
+			res:    channels[0:5],
+		},
+		{
+			desc:   "get a list of channels by thing with limit greater than max",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s/%s/channels?offset=%d&limit=%d", channelURL, sth.ID, 0, 110),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of channels by thing with default URL",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s/%s/channels", channelURL, sth.ID),
+			res:    channels[0:10],
+		},
+		{
+			desc:   "get a list of channels by thing with invalid number of params",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s/%s/channels%s", channelURL, sth.ID, "?offset=4&limit=4&limit=5&offset=5"),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of channels by thing with invalid offset",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s/%s/channels%s", channelURL, sth.ID, "?offset=e&limit=5"),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of channels by thing with invalid limit",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s/%s/channels%s", channelURL, sth.ID, "?offset=5&limit=e"),
+			res:    nil,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    tc.url,
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		var body channelsPageRes
+		json.NewDecoder(res.Body).Decode(&body)
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		assert.ElementsMatch(t, tc.res, body.Channels, fmt.Sprintf("%s: expected body %v got %v", tc.desc, tc.res, body.Channels))
+	}
+}
+
+func TestArchiveChannel(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	sch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		id     string
+		action string
+		auth   string
+		status int
+	}{
+		{
+			desc:   "archive existing channel",
+			id:     sch.ID,
+			action: "archive",
+			auth:   token,
+			status: http.StatusNoContent,
+		},
+		{
+			desc:   "unarchive existing channel",
+			id:     sch.ID,
+			action: "unarchive",
+			auth:   token,
+			status: http.StatusNoContent,
+		},
+		{
+			desc:   "archive non-existent channel",
+			id:     wrongID,
+			action: "archive",
+			auth:   token,
+			status: http.StatusNotFound,
+		},
+		{
+			desc:   "archive channel by passing invalid token",
+			id:     sch.ID,
+			action: "archive",
+			auth:   wrongValue,
+			status: http.StatusForbidden,
+		},
+		{
+			desc:   "unarchive channel by passing empty token",
+			id:     sch.ID,
+			action: "unarchive",
+			auth:   "",
+			status: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodPost,
+			url:    fmt.Sprintf("%s/channels/%s/%s", ts.URL, tc.id, tc.action),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestArchiveChannels(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	for i := 0; i < 3; i++ {
+		ch := channel
+		if i == 0 {
+			ch.Tags = []string{"retired"}
+		}
+		svc.CreateChannel(context.Background(), token, ch)
+	}
+
+	cases := []struct {
+		desc        string
+		req         string
+		contentType string
+		auth        string
+		status      int
+		archived    int
+	}{
+		{
+			desc:        "archive channels not matching selector",
+			req:         `{"selector":{"test":"other"}}`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusOK,
+			archived:    0,
+		},
+		{
+			desc:        "archive channels holding the tag",
+			req:         `{"tags":["retired","obsolete"],"match":"any"}`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusOK,
+			archived:    1,
+		},
+		{
+			desc:        "archive channels with invalid tags match",
+			req:         `{"tags":["retired"],"match":"some"}`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+			archived:    0,
+		},
+		{
+			desc:        "archive channels matching selector",
+			req:         `{"selector":{"test":"data"}}`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusOK,
+			archived:    2,
+		},
+		{
+			desc:        "archive channels without selector",
+			req:         `{}`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+			archived:    0,
+		},
+		{
+			desc:        "archive channels with invalid auth token",
+			req:         `{"selector":{"test":"data"}}`,
+			contentType: contentType,
+			auth:        wrongValue,
+			status:      http.StatusForbidden,
+			archived:    0,
+		},
+		{
+			desc:        "archive channels with invalid request format",
+			req:         "}",
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+			archived:    0,
+		},
+		{
+			desc:        "archive channels without content type",
+			req:         `{"selector":{"test":"data"}}`,
+			contentType: "",
+			auth:        token,
+			status:      http.StatusUnsupportedMediaType,
+			archived:    0,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/channels/archive", ts.URL),
+			contentType: tc.contentType,
+			token:       tc.auth,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+
+		var body archiveChannelsRes
+		json.NewDecoder(res.Body).Decode(&body)
+		assert.Equal(t, tc.archived, body.Archived, fmt.Sprintf("%s: expected %d archived channels got %d", tc.desc, tc.archived, body.Archived))
+	}
+}
+
+func TestRemoveChannel(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	sch, _ := svc.CreateChannel(context.Background(), token, channel)
+
+	cases := []struct {
+		desc   string
+		id     string
+		auth   string
+		status int
+	}{
+		{
+			desc:   "remove channel with invalid token",
+			id:     sch.ID,
+			auth:   wrongValue,
+			status: http.StatusForbidden,
+		},
+		{
+			desc:   "remove existing channel",
+			id:     sch.ID,
+			auth:   token,
+			status: http.StatusNoContent,
+		},
+		{
+			desc:   "remove removed channel",
+			id:     sch.ID,
+			auth:   token,
+			status: http.StatusNoContent,
+		},
+		{
+			desc:   "remove channel with invalid token",
+			id:     sch.ID,
+			auth:   wrongValue,
+			status: http.StatusForbidden,
+		},
+		{
+			desc:   "remove channel with empty token",
+			id:     sch.ID,
+			auth:   "",
+			status: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodDelete,
+			url:    fmt.Sprintf("%s/channels/%s", ts.URL, tc.id),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestInviteToChannel(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	sch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc        string
+		id          string
+		req         string
+		contentType string
+		auth        string
+		status      int
+		channelID   string
+	}{
+		{
+			desc:        "invite to existing channel",
+			id:          sch.ID,
+			req:         `{"ttl":3600}`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusCreated,
+			channelID:   sch.ID,
+		},
+		{
+			desc:        "invite without ttl",
+			id:          sch.ID,
+			req:         `{}`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "invite with too long ttl",
+			id:          sch.ID,
+			req:         `{"ttl":31536000}`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "invite with invalid request format",
+			id:          sch.ID,
+			req:         "}",
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "invite without content type",
+			id:          sch.ID,
+			req:         `{"ttl":3600}`,
+			contentType: "",
+			auth:        token,
+			status:      http.StatusUnsupportedMediaType,
+		},
+		{
+			desc:        "invite to non-existent channel",
+			id:          wrongID,
+			req:         `{"ttl":3600}`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusNotFound,
+		},
+		{
+			desc:        "invite by passing invalid token",
+			id:          sch.ID,
+			req:         `{"ttl":3600}`,
+			contentType: contentType,
+			auth:        wrongValue,
+			status:      http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/channels/%s/invitations", ts.URL, tc.id),
+			contentType: tc.contentType,
+			token:       tc.auth,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+
+		var body invitationRes
+		json.NewDecoder(res.Body).Decode(&body)
+		assert.Equal(t, tc.channelID, body.ChannelID, fmt.Sprintf("%s: expected channel %s got %s", tc.desc, tc.channelID, body.ChannelID))
+		if tc.status == http.StatusCreated {
+			assert.NotEmpty(t, body.Invitation, fmt.Sprintf("%s: expected non-empty invitation", tc.desc))
+		}
+	}
+}
+
+func TestIssueChannelToken(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	sch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc        string
+		id          string
+		req         string
+		contentType string
+		auth        string
+		status      int
+		scope       string
+	}{
+		{
+			desc:        "issue publish token",
+			id:          sch.ID,
+			req:         `{"scope":"publish","ttl":3600}`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusCreated,
+			scope:       "publish",
+		},
+		{
+			desc:        "issue token with invalid scope",
+			id:          sch.ID,
+			req:         `{"scope":"manage","ttl":3600}`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "issue token without ttl",
+			id:          sch.ID,
+			req:         `{"scope":"publish"}`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "issue token with invalid request format",
+			id:          sch.ID,
+			req:         "}",
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "issue token without content type",
+			id:          sch.ID,
+			req:         `{"scope":"publish","ttl":3600}`,
+			contentType: "",
+			auth:        token,
+			status:      http.StatusUnsupportedMediaType,
+		},
+		{
+			desc:        "issue token of non-existent channel",
+			id:          wrongID,
+			req:         `{"scope":"publish","ttl":3600}`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusNotFound,
+		},
+		{
+			desc:        "issue token with invalid user token",
+			id:          sch.ID,
+			req:         `{"scope":"publish","ttl":3600}`,
+			contentType: contentType,
+			auth:        wrongValue,
+			status:      http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/channels/%s/tokens", ts.URL, tc.id),
+			contentType: tc.contentType,
+			token:       tc.auth,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		var body channelTokenRes
+		json.NewDecoder(res.Body).Decode(&body)
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		assert.Equal(t, tc.scope, body.Scope, fmt.Sprintf("%s: expected scope %s got %s", tc.desc, tc.scope, body.Scope))
+	}
+}
+
+func TestListChannelTokens(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	sch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	for _, scope := range []things.KeyScope{things.ScopePublish, things.ScopeSubscribe} {
+		_, err := svc.IssueChannelToken(context.Background(), token, sch.ID, scope, time.Hour)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	cases := []struct {
+		desc   string
+		id     string
+		auth   string
+		status int
+		size   int
+	}{
+		{
+			desc:   "list channel tokens",
+			id:     sch.ID,
+			auth:   token,
+			status: http.StatusOK,
+			size:   2,
+		},
+		{
+			desc:   "list tokens of non-existent channel",
+			id:     wrongID,
+			auth:   token,
+			status: http.StatusNotFound,
+		},
+		{
+			desc:   "list tokens with invalid user token",
+			id:     sch.ID,
+			auth:   wrongValue,
+			status: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/channels/%s/tokens", ts.URL, tc.id),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		var body channelTokensRes
+		json.NewDecoder(res.Body).Decode(&body)
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		assert.Len(t, body.Tokens, tc.size, fmt.Sprintf("%s: expected %d tokens got %d", tc.desc, tc.size, len(body.Tokens)))
+	}
+}
+
+func TestRevokeChannelToken(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	sch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	ct, err := svc.IssueChannelToken(context.Background(), token, sch.ID, things.ScopePublish, time.Hour)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc    string
+		id      string
+		tokenID string
+		auth    string
+		status  int
+	}{
+		{
+			desc:    "revoke token by passing invalid user token",
+			id:      sch.ID,
+			tokenID: ct.ID,
+			auth:    wrongValue,
+			status:  http.StatusForbidden,
+		},
+		{
+			desc:    "revoke existing token",
+			id:      sch.ID,
+			tokenID: ct.ID,
+			auth:    token,
+			status:  http.StatusNoContent,
+		},
+		{
+			desc:    "revoke non-existent token",
+			id:      sch.ID,
+			tokenID: ct.ID,
+			auth:    token,
+			status:  http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodDelete,
+			url:    fmt.Sprintf("%s/channels/%s/tokens/%s", ts.URL, tc.id, tc.tokenID),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestConnect(t *testing.T) {
+	otherToken := "other_token"
+	otherEmail := "other_user@example.com"
+	svc := newService(map[string]string{
+		token:      email,
+		otherToken: otherEmail,
+	})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	ath, _ := svc.AddThing(context.Background(), token, thing)
+	ach, _ := svc.CreateChannel(context.Background(), token, channel)
+	bch, _ := svc.CreateChannel(context.Background(), otherToken, channel)
+
+	cases := []struct {
+		desc    string
+		chanID  string
+		thingID string
+		query   string
+		auth    string
+		status  int
+	}{
+		{
+			desc:    "connect existing thing to existing channel",
+			chanID:  ach.ID,
+			thingID: ath.ID,
+			auth:    token,
+			status:  http.StatusOK,
+		},
+		{
+			desc:    "connect existing thing to existing channel with publish-only access",
+			chanID:  ach.ID,
+			thingID: ath.ID,
+			query:   "?access=publish",
+			auth:    token,
+			status:  http.StatusOK,
+		},
+		{
+			desc:    "connect existing thing to existing channel with invalid access",
+			chanID:  ach.ID,
+			thingID: ath.ID,
+			query:   "?access=admin",
+			auth:    token,
+			status:  http.StatusBadRequest,
+		},
+		{
+			desc:    "connect existing thing to non-existent channel",
+			chanID:  wrongID,
+			thingID: ath.ID,
+			auth:    token,
+			status:  http.StatusNotFound,
+		},
+		{
+			desc:    "connect non-existing thing to existing channel",
+			chanID:  ach.ID,
+			thingID: wrongID,
+			auth:    token,
+			status:  http.StatusNotFound,
+		},
+		{
+			desc:    "connect existing thing to channel with invalid id",
+			chanID:  "invalid",
+			thingID: ath.ID,
+			auth:    token,
+			status:  http.StatusBadRequest,
+		},
+		{
+			desc:    "connect thing with invalid id to existing channel",
+			chanID:  ach.ID,
+			thingID: "invalid",
+			auth:    token,
+			status:  http.StatusBadRequest,
+		},
+		{
+			desc:    "connect existing thing to existing channel with invalid token",
+			chanID:  ach.ID,
+			thingID: ath.ID,
+			auth:    wrongValue,
+			status:  http.StatusForbidden,
+		},
+		{
+			desc:    "connect existing thing to existing channel with empty token",
+			chanID:  ach.ID,
+			thingID: ath.ID,
+			auth:    "",
+			status:  http.StatusForbidden,
+		},
+		{
+			desc:    "connect thing from owner to channel of other user",
+			chanID:  bch.ID,
+			thingID: ath.ID,
+			auth:    token,
+			status:  http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodPut,
+			url:    fmt.Sprintf("%s/channels/%s/things/%s%s", ts.URL, tc.chanID, tc.thingID, tc.query),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestDisconnnect(t *testing.T) {
+	otherToken := "other_token"
+	otherEmail := "other_user@example.com"
+	svc := newService(map[string]string{
+		token:      email,
+		otherToken: otherEmail,
+	})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	ath, _ := svc.AddThing(context.Background(), token, thing)
+	ach, _ := svc.CreateChannel(context.Background(), token, channel)
+	svc.Connect(context.Background(), token, []string{ach.ID}, []string{ath.ID}, things.FullAccess)
+	bch, _ := svc.CreateChannel(context.Background(), otherToken, channel)
+
+	cases := []struct {
+		desc    string
+		chanID  string
+		thingID string
+		auth    string
+		status  int
+	}{
+		{
+			desc:    "disconnect connected thing from channel",
+			chanID:  ach.ID,
+			thingID: ath.ID,
+			auth:    token,
+			status:  http.StatusNoContent,
+		},
+		{
+			desc:    "disconnect non-connected thing from channel",
+			chanID:  ach.ID,
+			thingID: ath.ID,
+			auth:    token,
+			status:  http.StatusNotFound,
+		},
+		{
+			desc:    "disconnect non-existent thing from channel",
+			chanID:  ach.ID,
+			thingID: wrongID,
+			auth:    token,
+			status:  http.StatusNotFound,
+		},
+		{
+			desc:    "disconnect thing from non-existent channel",
+			chanID:  wrongID,
+			thingID: ath.ID,
+			auth:    token,
+			status:  http.StatusNotFound,
+		},
+		{
+			desc:    "disconnect thing from channel with invalid token",
+			chanID:  ach.ID,
+			thingID: ath.ID,
+			auth:    wrongValue,
+			status:  http.StatusForbidden,
+		},
+		{
+			desc:    "disconnect thing from channel with empty token",
+			chanID:  ach.ID,
+			thingID: ath.ID,
+			auth:    "",
+			status:  http.StatusForbidden,
+		},
+		{
+			desc:    "disconnect owner's thing from someone elses channel",
+			chanID:  bch.ID,
+			thingID: ath.ID,
+			auth:    token,
+			status:  http.StatusNotFound,
+		},
+		{
+			desc:    "disconnect thing with invalid id from channel",
+			chanID:  ach.ID,
+			thingID: "invalid",
+			auth:    token,
+			status:  http.StatusBadRequest,
+		},
+		{
+			desc:    "disconnect thing from channel with invalid id",
+			chanID:  "invalid",
+			thingID: ath.ID,
+			auth:    token,
+			status:  http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodDelete,
+			url:    fmt.Sprintf("%s/channels/%s/things/%s", ts.URL, tc.chanID, tc.thingID),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestConnectAll(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	ath, _ := svc.AddThing(context.Background(), token, thing)
+	bth, _ := svc.AddThing(context.Background(), token, thing)
+	ach, _ := svc.CreateChannel(context.Background(), token, channel)
+	bch, _ := svc.CreateChannel(context.Background(), token, channel)
+	missing := wrongID
+
+	cases := []struct {
+		desc        string
+		method      string
+		url         string
+		req         string
+		contentType string
+		auth        string
+		status      int
+		failures    []connectionFailureRes
+	}{
+		{
+			desc:        "connect things to channels",
+			method:      http.MethodPost,
+			url:         "connect",
+			req:         toJSON(connectionsReq{ChanIDs: []string{ach.ID, bch.ID}, ThingIDs: []string{ath.ID, bth.ID}}),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusOK,
+		},
+		{
+			desc:        "connect things to channels with subscribe-only access",
+			method:      http.MethodPost,
+			url:         "connect",
+			req:         toJSON(connectionsReq{ChanIDs: []string{ach.ID, bch.ID}, ThingIDs: []string{ath.ID, bth.ID}, Access: "subscribe"}),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusOK,
+		},
+		{
+			desc:        "connect things to channels with invalid access",
+			method:      http.MethodPost,
+			url:         "connect",
+			req:         toJSON(connectionsReq{ChanIDs: []string{ach.ID}, ThingIDs: []string{ath.ID}, Access: "admin"}),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "connect existing and non-existing things to channel",
+			method:      http.MethodPost,
+			url:         "connect",
+			req:         toJSON(connectionsReq{ChanIDs: []string{ach.ID}, ThingIDs: []string{ath.ID, missing}}),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusNotFound,
+			failures:    []connectionFailureRes{{ChanID: ach.ID, ThingID: missing, Error: things.ErrNotFound.Error()}},
+		},
+		{
+			desc:        "connect things without channels",
+			method:      http.MethodPost,
+			url:         "connect",
+			req:         toJSON(connectionsReq{ThingIDs: []string{ath.ID}}),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "connect things with invalid token",
+			method:      http.MethodPost,
+			url:         "connect",
+			req:         toJSON(connectionsReq{ChanIDs: []string{ach.ID}, ThingIDs: []string{ath.ID}}),
+			contentType: contentType,
+			auth:        wrongValue,
+			status:      http.StatusForbidden,
+		},
+		{
+			desc:        "connect things with invalid request format",
+			method:      http.MethodPost,
+			url:         "connect",
+			req:         "}",
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "connect things without content type",
+			method:      http.MethodPost,
+			url:         "connect",
+			req:         toJSON(connectionsReq{ChanIDs: []string{ach.ID}, ThingIDs: []string{ath.ID}}),
+			contentType: "",
+			auth:        token,
+			status:      http.StatusUnsupportedMediaType,
+		},
+		{
+			desc:        "disconnect things from channels",
+			method:      http.MethodPost,
+			url:         "disconnect",
+			req:         toJSON(connectionsReq{ChanIDs: []string{ach.ID, bch.ID}, ThingIDs: []string{ath.ID, bth.ID}}),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusNoContent,
+		},
+		{
+			desc:        "disconnect disconnected things from channel",
+			method:      http.MethodPost,
+			url:         "disconnect",
+			req:         toJSON(connectionsReq{ChanIDs: []string{ach.ID}, ThingIDs: []string{ath.ID}}),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusNotFound,
+			failures:    []connectionFailureRes{{ChanID: ach.ID, ThingID: ath.ID, Error: things.ErrNotFound.Error()}},
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      tc.method,
+			url:         fmt.Sprintf("%s/%s", ts.URL, tc.url),
+			contentType: tc.contentType,
+			token:       tc.auth,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+
+		var body connectionErrorRes
+		json.NewDecoder(res.Body).Decode(&body)
+		assert.Equal(t, tc.failures, body.Failures, fmt.Sprintf("%s: expected failures %v got %v", tc.desc, tc.failures, body.Failures))
+	}
+}
+
+func TestThingUsage(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	sth, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	sch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	size := uint64(100)
+	err = svc.RecordUsage(context.Background(), sth.ID, sch.ID, size)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	to := time.Now().Add(time.Minute).Unix()
+	from := to - 3600
+
+	cases := []struct {
+		desc     string
+		id       string
+		auth     string
+		query    string
+		status   int
+		messages uint64
+		bytes    uint64
+	}{
+		{
+			desc:     "retrieve thing usage",
+			id:       sth.ID,
+			auth:     token,
+			query:    fmt.Sprintf("from=%d&to=%d", from, to),
+			status:   http.StatusOK,
+			messages: 1,
+			bytes:    size,
+		},
+		{
+			desc:     "retrieve thing usage with default period",
+			id:       sth.ID,
+			auth:     token,
+			query:    "",
+			status:   http.StatusOK,
+			messages: 1,
+			bytes:    size,
+		},
+		{
+			desc:   "retrieve thing usage with invalid period",
+			id:     sth.ID,
+			auth:   token,
+			query:  fmt.Sprintf("from=%d&to=%d", to, from),
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "retrieve thing usage with invalid query params",
+			id:     sth.ID,
+			auth:   token,
+			query:  "from=invalid",
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "retrieve non-existent thing usage",
+			id:     wrongID,
+			auth:   token,
+			status: http.StatusNotFound,
+		},
+		{
+			desc:   "retrieve thing usage with invalid token",
+			id:     sth.ID,
+			auth:   wrongValue,
+			status: http.StatusForbidden,
+		},
+		{
+			desc:   "retrieve thing usage with empty token",
+			id:     sth.ID,
+			auth:   "",
+			status: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/things/%s/usage?%s", ts.URL, tc.id, tc.query),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+
+		var body usageRes
+		json.NewDecoder(res.Body).Decode(&body)
+		assert.Equal(t, tc.messages, body.Messages, fmt.Sprintf("%s: expected %d messages got %d", tc.desc, tc.messages, body.Messages))
+		assert.Equal(t, tc.bytes, body.Bytes, fmt.Sprintf("%s: expected %d bytes got %d", tc.desc, tc.bytes, body.Bytes))
+	}
+}
+
+func TestViewHealth(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	sth, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	silent, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	err = svc.RecordDiagnostics(context.Background(), sth.ID, []byte(`{"battery": 87, "firmware": "1.4.2"}`))
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc     string
+		id       string
+		auth     string
+		status   int
+		firmware string
+	}{
+		{
+			desc:     "view thing health",
+			id:       sth.ID,
+			auth:     token,
+			status:   http.StatusOK,
+			firmware: "1.4.2",
+		},
+		{
+			desc:   "view health of thing that never reported",
+			id:     silent.ID,
+			auth:   token,
+			status: http.StatusNotFound,
+		},
+		{
+			desc:   "view health of non-existent thing",
+			id:     wrongID,
+			auth:   token,
+			status: http.StatusNotFound,
+		},
+		{
+			desc:   "view thing health with invalid token",
+			id:     sth.ID,
+			auth:   wrongValue,
+			status: http.StatusForbidden,
+		},
+		{
+			desc:   "view thing health with empty token",
+			id:     sth.ID,
+			auth:   "",
+			status: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/things/%s/health", ts.URL, tc.id),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+
+		var body healthRes
+		json.NewDecoder(res.Body).Decode(&body)
+		assert.Equal(t, tc.firmware, body.Firmware, fmt.Sprintf("%s: expected firmware %s got %s", tc.desc, tc.firmware, body.Firmware))
+	}
+}
+
+func TestListUnhealthy(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	for _, report := range []string{`{"battery": 10}`, `{"rssi": -110}`, `{"battery": 90, "rssi": -70}`} {
+		sth, err := svc.AddThing(context.Background(), token, thing)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		err = svc.RecordDiagnostics(context.Background(), sth.ID, []byte(report))
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	cases := []struct {
+		desc   string
+		auth   string
+		query  string
+		status int
+		size   int
+	}{
+		{
+			desc:   "list unhealthy things",
+			auth:   token,
+			query:  "",
+			status: http.StatusOK,
+			size:   2,
+		},
+		{
+			desc:   "list unhealthy things with custom thresholds",
+			auth:   token,
+			query:  "battery=95&rssi=-120",
+			status: http.StatusOK,
+			size:   2,
+		},
+		{
+			desc:   "list unhealthy things with limit",
+			auth:   token,
+			query:  "limit=1",
+			status: http.StatusOK,
+			size:   1,
+		},
+		{
+			desc:   "list unhealthy things with invalid threshold",
+			auth:   token,
+			query:  "battery=low",
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "list unhealthy things with zero limit",
+			auth:   token,
+			query:  "limit=0",
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "list unhealthy things with invalid token",
+			auth:   wrongValue,
+			status: http.StatusForbidden,
+		},
+		{
+			desc:   "list unhealthy things with empty token",
+			auth:   "",
+			status: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/things/unhealthy?%s", ts.URL, tc.query),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+
+		var body healthPageRes
+		json.NewDecoder(res.Body).Decode(&body)
+		assert.Equal(t, tc.size, len(body.Things), fmt.Sprintf("%s: expected %d things got %d", tc.desc, tc.size, len(body.Things)))
+	}
+}
+
+func TestChannelUsage(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	sth, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	sch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	size := uint64(100)
+	err = svc.RecordUsage(context.Background(), sth.ID, sch.ID, size)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc     string
+		id       string
+		auth     string
+		status   int
+		messages uint64
+		bytes    uint64
+	}{
+		{
+			desc:     "retrieve channel usage",
+			id:       sch.ID,
+			auth:     token,
+			status:   http.StatusOK,
+			messages: 1,
+			bytes:    size,
+		},
+		{
+			desc:   "retrieve non-existent channel usage",
+			id:     wrongID,
+			auth:   token,
+			status: http.StatusNotFound,
+		},
+		{
+			desc:   "retrieve channel usage with invalid token",
+			id:     sch.ID,
+			auth:   wrongValue,
+			status: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/channels/%s/usage", ts.URL, tc.id),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+
+		var body usageRes
+		json.NewDecoder(res.Body).Decode(&body)
+		assert.Equal(t, tc.messages, body.Messages, fmt.Sprintf("%s: expected %d messages got %d", tc.desc, tc.messages, body.Messages))
+		assert.Equal(t, tc.bytes, body.Bytes, fmt.Sprintf("%s: expected %d bytes got %d", tc.desc, tc.bytes, body.Bytes))
+	}
+}
+
+func TestThingHistory(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	sth, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	sth.Name = "updated"
+	err = svc.UpdateThing(context.Background(), token, sth)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = svc.UpdateKey(context.Background(), token, sth.ID, "new-key", time.Time{}, 0)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		id     string
+		auth   string
+		query  string
+		status int
+		fields []string
+	}{
+		{
+			desc:   "retrieve thing history",
+			id:     sth.ID,
+			auth:   token,
+			query:  "offset=0&limit=10",
+			status: http.StatusOK,
+			fields: []string{"key", "name"},
+		},
+		{
+			desc:   "retrieve thing history with default pagination",
+			id:     sth.ID,
+			auth:   token,
+			query:  "",
+			status: http.StatusOK,
+			fields: []string{"key", "name"},
+		},
+		{
+			desc:   "retrieve subset of thing history",
+			id:     sth.ID,
+			auth:   token,
+			query:  "offset=1&limit=1",
+			status: http.StatusOK,
+			fields: []string{"name"},
+		},
+		{
+			desc:   "retrieve thing history with invalid limit",
+			id:     sth.ID,
+			auth:   token,
+			query:  "limit=0",
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "retrieve thing history with invalid query params",
+			id:     sth.ID,
+			auth:   token,
+			query:  "offset=invalid",
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "retrieve non-existent thing history",
+			id:     wrongID,
+			auth:   token,
+			status: http.StatusNotFound,
+		},
+		{
+			desc:   "retrieve thing history with invalid token",
+			id:     sth.ID,
+			auth:   wrongValue,
+			status: http.StatusForbidden,
+		},
+		{
+			desc:   "retrieve thing history with empty token",
+			id:     sth.ID,
+			auth:   "",
+			status: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/things/%s/history?%s", ts.URL, tc.id, tc.query),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+
+		var body historyRes
+		json.NewDecoder(res.Body).Decode(&body)
+		var fields []string
+		for _, c := range body.Changes {
+			fields = append(fields, c.Field)
+		}
+		assert.Equal(t, tc.fields, fields, fmt.Sprintf("%s: expected changed fields %v got %v", tc.desc, tc.fields, fields))
+	}
+}
+
+func TestChannelHistory(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	sch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	sch.Name = "updated"
+	err = svc.UpdateChannel(context.Background(), token, sch)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		id     string
+		auth   string
+		status int
+		fields []string
+	}{
+		{
+			desc:   "retrieve channel history",
+			id:     sch.ID,
 			auth:   token,
 			status: http.StatusOK,
-			url:    fmt.Sprintf("%s?limit=%d", thingURL, 5),
-			res:    data[0:5],
+			fields: []string{"name"},
 		},
 		{
-			desc:   "get a list of things without limit",
+			desc:   "retrieve non-existent channel history",
+			id:     wrongID,
 			auth:   token,
-			status: http.StatusOK,
-			url:    fmt.Sprintf("%s?offset=%d", thingURL, 1),
-			res:    data[1:11],
+			status: http.StatusNotFound,
 		},
 		{
-			desc:   "get a list of things with redundant query params",
+			desc:   "retrieve channel history with invalid token",
+			id:     sch.ID,
+			auth:   wrongValue,
+			status: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/channels/%s/history", ts.URL, tc.id),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+
+		var body historyRes
+		json.NewDecoder(res.Body).Decode(&body)
+		var fields []string
+		for _, c := range body.Changes {
+			fields = append(fields, c.Field)
+		}
+		assert.Equal(t, tc.fields, fields, fmt.Sprintf("%s: expected changed fields %v got %v", tc.desc, tc.fields, fields))
+	}
+}
+
+func TestAudit(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	sth, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	sth.Name = "updated"
+	err = svc.UpdateThing(context.Background(), token, sth)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = svc.UpdateKey(context.Background(), token, sth.ID, "new-key", time.Time{}, 0)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		auth   string
+		query  string
+		status int
+		ops    []string
+	}{
+		{
+			desc:   "retrieve audit events",
 			auth:   token,
+			query:  "offset=0&limit=10",
 			status: http.StatusOK,
-			url:    fmt.Sprintf("%s?offset=%d&limit=%d&value=something", thingURL, 0, 5),
-			res:    data[0:5],
+			ops:    []string{"update_key", "update", "create"},
 		},
 		{
-			desc:   "get a list of things with limit greater than max",
+			desc:   "retrieve audit events with default pagination",
 			auth:   token,
-			status: http.StatusBadRequest,
-			url:    fmt.Sprintf("%s?offset=%d&limit=%d", thingURL, 0, 110),
-			res:    nil,
+			query:  "",
+			status: http.StatusOK,
+			ops:    []string{"update_key", "update", "create"},
 		},
 		{
-			desc:   "get a list of things with default URL",
+			desc:   "retrieve audit events of the thing by operation",
 			auth:   token,
+			query:  fmt.Sprintf("kind=thing&entity=%s&operation=update", sth.ID),
 			status: http.StatusOK,
-			url:    fmt.Sprintf("%s%s", thingURL, ""),
-			res:    data[0:10],
+			ops:    []string{"update"},
 		},
 		{
-			desc:   "get a list of things with invalid number of params",
+			desc:   "retrieve audit events by actor within time period",
 			auth:   token,
-			status: http.StatusBadRequest,
-			url:    fmt.Sprintf("%s%s", thingURL, "?offset=4&limit=4&limit=5&offset=5"),
-			res:    nil,
+			query:  fmt.Sprintf("actor=%s&from=%d&to=%d", email, time.Now().Add(-time.Minute).Unix(), time.Now().Add(time.Minute).Unix()),
+			status: http.StatusOK,
+			ops:    []string{"update_key", "update", "create"},
 		},
 		{
-			desc:   "get a list of things with invalid offset",
+			desc:   "retrieve audit events by unknown operation",
 			auth:   token,
+			query:  "operation=view",
 			status: http.StatusBadRequest,
-			url:    fmt.Sprintf("%s%s", thingURL, "?offset=e&limit=5"),
-			res:    nil,
 		},
 		{
-			desc:   "get a list of things with invalid limit",
+			desc:   "retrieve audit events with invalid limit",
 			auth:   token,
+			query:  "limit=0",
 			status: http.StatusBadRequest,
-			url:    fmt.Sprintf("%s%s", thingURL, "?offset=5&limit=e"),
-			res:    nil,
 		},
 		{
-			desc:   "get a list of things filtering with invalid name",
+			desc:   "retrieve audit events with invalid time",
 			auth:   token,
+			query:  "from=invalid",
 			status: http.StatusBadRequest,
-			url:    fmt.Sprintf("%s?offset=%d&limit=%d&name=%s", thingURL, 0, 5, invalidName),
-			res:    nil,
+		},
+		{
+			desc:   "retrieve audit events with invalid token",
+			auth:   wrongValue,
+			status: http.StatusForbidden,
+		},
+		{
+			desc:   "retrieve audit events with empty token",
+			auth:   "",
+			status: http.StatusForbidden,
 		},
 	}
 
@@ -654,203 +4737,307 @@ func TestListThings(t *testing.T) {
 		req := testRequest{
 			client: ts.Client(),
 			method: http.MethodGet,
-			url:    tc.url,
+			url:    fmt.Sprintf("%s/audit?%s", ts.URL, tc.query),
 			token:  tc.auth,
 		}
 		res, err := req.make()
 		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
-		var data thingsPageRes
-		json.NewDecoder(res.Body).Decode(&data)
 		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
-		assert.ElementsMatch(t, tc.res, data.Things, fmt.Sprintf("%s: expected body %v got %v", tc.desc, tc.res, data.Things))
+
+		var body auditRes
+		json.NewDecoder(res.Body).Decode(&body)
+		var ops []string
+		for _, e := range body.Events {
+			ops = append(ops, e.Operation)
+		}
+		assert.Equal(t, tc.ops, ops, fmt.Sprintf("%s: expected operations %v got %v", tc.desc, tc.ops, ops))
 	}
 }
 
-func TestListThingsByChannel(t *testing.T) {
+func TestExport(t *testing.T) {
 	svc := newService(map[string]string{token: email})
 	ts := newServer(svc)
 	defer ts.Close()
 
-	sch, err := svc.CreateChannel(token, channel)
+	sth, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	sch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = svc.Connect(context.Background(), token, []string{sch.ID}, []string{sth.ID}, things.PublishAccess)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
-	data := []thingRes{}
-	for i := 0; i < 101; i++ {
-		sth, err := svc.AddThing(token, thing)
-		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
-		err = svc.Connect(token, sch.ID, sth.ID)
-		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
-
-		thres := thingRes{
-			ID:       sth.ID,
-			Name:     sth.Name,
-			Key:      sth.Key,
-			Metadata: sth.Metadata,
-		}
-		data = append(data, thres)
-	}
-	thingURL := fmt.Sprintf("%s/channels", ts.URL)
-
-	// Wait for things and channels to connect.
-	time.Sleep(time.Second)
+	data := toJSON(snapshotRes{
+		Things:      []snapshotThing{{ID: sth.ID, Name: sth.Name, Key: sth.Key, State: string(sth.State), Metadata: sth.Metadata}},
+		Channels:    []snapshotChannel{{ID: sch.ID, Name: sch.Name, State: string(sch.State), Metadata: sch.Metadata}},
+		Connections: []snapshotConnection{{ChanID: sch.ID, ThingID: sth.ID, Access: string(things.PublishAccess)}},
+	})
+	doc := strings.Join([]string{
+		"kind,id,name,key,key_expires_at,key_rotation,state,tags,metadata,channel,thing,access",
+		fmt.Sprintf(`thing,%s,%s,%s,,,enabled,,"{""test"":""data""}",,,`, sth.ID, sth.Name, sth.Key),
+		fmt.Sprintf(`channel,%s,%s,,,,active,,"{""test"":""data""}",,,`, sch.ID, sch.Name),
+		fmt.Sprintf("connection,,,,,,,,,%s,%s,publish", sch.ID, sth.ID),
+	}, "\n") + "\n"
 
 	cases := []struct {
 		desc   string
 		auth   string
+		accept string
 		status int
-		url    string
-		res    []thingRes
+		res    string
 	}{
 		{
-			desc:   "get a list of things by channel",
+			desc:   "export things and channels as JSON",
 			auth:   token,
 			status: http.StatusOK,
-			url:    fmt.Sprintf("%s/%s/things?offset=%d&limit=%d", thingURL, sch.ID, 0, 5),
-			res:    data[0:5],
+			res:    data + "\n",
 		},
 		{
-			desc:   "get a list of things by channel with invalid token",
+			desc:   "export things and channels as CSV",
+			auth:   token,
+			accept: csvType,
+			status: http.StatusOK,
+			res:    doc,
+		},
+		{
+			desc:   "export things and channels with invalid token",
 			auth:   wrongValue,
 			status: http.StatusForbidden,
-			url:    fmt.Sprintf("%s/%s/things?offset=%d&limit=%d", thingURL, sch.ID, 0, 1),
-			res:    nil,
+			res:    "",
 		},
 		{
-			desc:   "get a list of things by channel with empty token",
+			desc:   "export things and channels with empty token",
 			auth:   "",
 			status: http.StatusForbidden,
-			url:    fmt.Sprintf("%s/%s/things?offset=%d&limit=%d", thingURL, sch.ID, 0, 1),
-			res:    nil,
-		},
-		{
-			desc:   "get a list of things by channel with negative offset",
-			auth:   token,
-			status: http.StatusBadRequest,
-			url:    fmt.Sprintf("%s/%s/things?offset=%d&limit=%d", thingURL, sch.ID, -1, 5),
-			res:    nil,
+			res:    "",
 		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/export", ts.URL),
+			token:  tc.auth,
+			accept: tc.accept,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+
+		body, err := ioutil.ReadAll(res.Body)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.res, string(body), fmt.Sprintf("%s: expected body %s got %s", tc.desc, tc.res, string(body)))
+	}
+}
+
+func TestImport(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	data := toJSON(snapshotRes{
+		Things:      []snapshotThing{{ID: "thing", Name: "test", Key: "imported-key"}},
+		Channels:    []snapshotChannel{{ID: "channel", Name: "test"}},
+		Connections: []snapshotConnection{{ChanID: "channel", ThingID: "thing", Access: "subscribe"}},
+	})
+	invalidAccess := toJSON(snapshotRes{
+		Things:      []snapshotThing{{ID: "thing"}},
+		Channels:    []snapshotChannel{{ID: "channel"}},
+		Connections: []snapshotConnection{{ChanID: "channel", ThingID: "thing", Access: "read"}},
+	})
+	doc := strings.Join([]string{
+		"kind,id,name,key,key_expires_at,key_rotation,state,tags,metadata,channel,thing,access",
+		`thing,thing,test,csv-key,,,disabled,"edge,v2","{""test"":""data""}",,,`,
+		"channel,channel,test,,,,,,,,,",
+		"connection,,,,,,,,,channel,thing,",
+	}, "\n")
+	invalidDoc := strings.Join([]string{
+		"kind,id,name,key,key_expires_at,key_rotation,state,tags,metadata,channel,thing,access",
+		"group,group,test,,,,,,,,,",
+	}, "\n")
+
+	cases := []struct {
+		desc        string
+		req         string
+		contentType string
+		query       string
+		auth        string
+		status      int
+		things      int
+		key         string
+	}{
 		{
-			desc:   "get a list of things by channel with negative limit",
-			auth:   token,
-			status: http.StatusBadRequest,
-			url:    fmt.Sprintf("%s/%s/things?offset=%d&limit=%d", thingURL, sch.ID, 1, -5),
-			res:    nil,
+			desc:        "import snapshot",
+			req:         data,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusCreated,
+			things:      1,
 		},
 		{
-			desc:   "get a list of things by channel with zero limit",
-			auth:   token,
-			status: http.StatusBadRequest,
-			url:    fmt.Sprintf("%s/%s/things?offset=%d&limit=%d", thingURL, sch.ID, 1, 0),
-			res:    nil,
+			desc:        "import snapshot preserving keys",
+			req:         data,
+			contentType: contentType,
+			query:       "preserve=true",
+			auth:        token,
+			status:      http.StatusCreated,
+			things:      1,
+			key:         "imported-key",
 		},
 		{
-			desc:   "get a list of things by channel without offset",
-			auth:   token,
-			status: http.StatusOK,
-			url:    fmt.Sprintf("%s/%s/things?limit=%d", thingURL, sch.ID, 5),
-			res:    data[0:5],
+			desc:        "import snapshot preserving imported keys",
+			req:         data,
+			contentType: contentType,
+			query:       "preserve=true",
+			auth:        token,
+			status:      http.StatusUnprocessableEntity,
 		},
 		{
-			desc:   "get a list of things by channel without limit",
-			auth:   token,
-			status: http.StatusOK,
-			url:    fmt.Sprintf("%s/%s/things?offset=%d", thingURL, sch.ID, 1),
-			res:    data[1:11],
+			desc:        "import snapshot as CSV",
+			req:         doc,
+			contentType: csvType,
+			query:       "preserve=true",
+			auth:        token,
+			status:      http.StatusCreated,
+			things:      1,
+			key:         "csv-key",
 		},
 		{
-			desc:   "get a list of things by channel with redundant query params",
-			auth:   token,
-			status: http.StatusOK,
-			url:    fmt.Sprintf("%s/%s/things?offset=%d&limit=%d&value=something", thingURL, sch.ID, 0, 5),
-			res:    data[0:5],
+			desc:        "import snapshot with invalid access",
+			req:         invalidAccess,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
 		},
 		{
-			desc:   "get a list of things by channel with limit greater than max",
-			auth:   token,
-			status: http.StatusBadRequest,
-			url:    fmt.Sprintf("%s/%s/things?offset=%d&limit=%d", thingURL, sch.ID, 0, 110),
-			res:    nil,
+			desc:        "import CSV snapshot with unknown kind",
+			req:         invalidDoc,
+			contentType: csvType,
+			auth:        token,
+			status:      http.StatusBadRequest,
 		},
 		{
-			desc:   "get a list of things by channel with default URL",
-			auth:   token,
-			status: http.StatusOK,
-			url:    fmt.Sprintf("%s/%s/things", thingURL, sch.ID),
-			res:    data[0:10],
+			desc:        "import empty snapshot",
+			req:         "{}",
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
 		},
 		{
-			desc:   "get a list of things by channel with invalid number of params",
-			auth:   token,
-			status: http.StatusBadRequest,
-			url:    fmt.Sprintf("%s/%s/things%s", thingURL, sch.ID, "?offset=4&limit=4&limit=5&offset=5"),
-			res:    nil,
+			desc:        "import snapshot with invalid preserve flag",
+			req:         data,
+			contentType: contentType,
+			query:       "preserve=maybe",
+			auth:        token,
+			status:      http.StatusBadRequest,
 		},
 		{
-			desc:   "get a list of things by channel with invalid offset",
-			auth:   token,
-			status: http.StatusBadRequest,
-			url:    fmt.Sprintf("%s/%s/things%s", thingURL, sch.ID, "?offset=e&limit=5"),
-			res:    nil,
+			desc:        "import snapshot with invalid token",
+			req:         data,
+			contentType: contentType,
+			auth:        wrongValue,
+			status:      http.StatusForbidden,
 		},
 		{
-			desc:   "get a list of things by channel with invalid limit",
-			auth:   token,
-			status: http.StatusBadRequest,
-			url:    fmt.Sprintf("%s/%s/things%s", thingURL, sch.ID, "?offset=5&limit=e"),
-			res:    nil,
+			desc:        "import snapshot with invalid content type",
+			req:         data,
+			contentType: "application/xml",
+			auth:        token,
+			status:      http.StatusUnsupportedMediaType,
 		},
 	}
 
 	for _, tc := range cases {
 		req := testRequest{
-			client: ts.Client(),
-			method: http.MethodGet,
-			url:    tc.url,
-			token:  tc.auth,
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/import?%s", ts.URL, tc.query),
+			contentType: tc.contentType,
+			token:       tc.auth,
+			body:        strings.NewReader(tc.req),
 		}
 		res, err := req.make()
 		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
-		var data thingsPageRes
-		json.NewDecoder(res.Body).Decode(&data)
 		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
-		assert.ElementsMatch(t, tc.res, data.Things, fmt.Sprintf("%s: expected body %v got %v", tc.desc, tc.res, data.Things))
+
+		var body snapshotRes
+		json.NewDecoder(res.Body).Decode(&body)
+		assert.Equal(t, tc.things, len(body.Things), fmt.Sprintf("%s: expected %d things got %d", tc.desc, tc.things, len(body.Things)))
+		if tc.key != "" {
+			assert.Equal(t, tc.key, body.Things[0].Key, fmt.Sprintf("%s: expected key %s got %s", tc.desc, tc.key, body.Things[0].Key))
+		}
 	}
 }
 
-func TestRemoveThing(t *testing.T) {
-	svc := newService(map[string]string{token: email})
+func TestCapabilities(t *testing.T) {
+	readerToken := "reader-token"
+	readerEmail := "reader@example.com"
+	svc := newService(map[string]string{token: email, readerToken: readerEmail})
 	ts := newServer(svc)
 	defer ts.Close()
 
-	sth, _ := svc.AddThing(token, thing)
+	sth, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = svc.Share(context.Background(), token, things.Share{Kind: things.ThingKind, EntityID: sth.ID, User: readerEmail, Permission: things.ReadPermission})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
 		desc   string
-		id     string
 		auth   string
+		query  string
 		status int
+		res    capabilitiesRes
 	}{
 		{
-			desc:   "delete existing thing",
-			id:     sth.ID,
+			desc:   "retrieve account capabilities",
 			auth:   token,
-			status: http.StatusNoContent,
+			query:  "",
+			status: http.StatusOK,
+			res:    capabilitiesRes{Operations: []string{"create", "view"}},
 		},
 		{
-			desc:   "delete non-existent thing",
-			id:     strconv.FormatUint(wrongID, 10),
+			desc:   "retrieve capabilities of owned thing",
 			auth:   token,
-			status: http.StatusNoContent,
+			query:  fmt.Sprintf("kind=thing&entity=%s", sth.ID),
+			status: http.StatusOK,
+			res: capabilitiesRes{
+				Kind:       things.ThingKind,
+				Entity:     sth.ID,
+				Operations: []string{"view", "update", "connect", "disconnect", "remove", "share", "transfer", "update_key"},
+			},
 		},
 		{
-			desc:   "delete thing with invalid token",
-			id:     sth.ID,
+			desc:   "retrieve capabilities of shared thing",
+			auth:   readerToken,
+			query:  fmt.Sprintf("kind=thing&entity=%s", sth.ID),
+			status: http.StatusOK,
+			res:    capabilitiesRes{Kind: things.ThingKind, Entity: sth.ID, Operations: []string{"view"}},
+		},
+		{
+			desc:   "retrieve capabilities of non-existing thing",
+			auth:   token,
+			query:  "kind=thing&entity=non-existing",
+			status: http.StatusNotFound,
+		},
+		{
+			desc:   "retrieve capabilities of entity without kind",
+			auth:   token,
+			query:  fmt.Sprintf("entity=%s", sth.ID),
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "retrieve capabilities of entity of unknown kind",
+			auth:   token,
+			query:  fmt.Sprintf("kind=group&entity=%s", sth.ID),
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "retrieve capabilities with invalid token",
 			auth:   wrongValue,
 			status: http.StatusForbidden,
 		},
 		{
-			desc:   "delete thing with empty token",
-			id:     sth.ID,
+			desc:   "retrieve capabilities with empty token",
 			auth:   "",
 			status: http.StatusForbidden,
 		},
@@ -859,26 +5046,30 @@ func TestRemoveThing(t *testing.T) {
 	for _, tc := range cases {
 		req := testRequest{
 			client: ts.Client(),
-			method: http.MethodDelete,
-			url:    fmt.Sprintf("%s/things/%s", ts.URL, tc.id),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/capabilities?%s", ts.URL, tc.query),
 			token:  tc.auth,
 		}
 		res, err := req.make()
 		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
 		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		if tc.status != http.StatusOK {
+			continue
+		}
+
+		var body capabilitiesRes
+		json.NewDecoder(res.Body).Decode(&body)
+		assert.Equal(t, tc.res, body, fmt.Sprintf("%s: expected body %v got %v", tc.desc, tc.res, body))
 	}
 }
 
-func TestCreateChannel(t *testing.T) {
+func TestCreateGroup(t *testing.T) {
 	svc := newService(map[string]string{token: email})
 	ts := newServer(svc)
 	defer ts.Close()
 
-	data := toJSON(channel)
-
-	th := channel
-	th.Name = invalidName
-	invalidData := toJSON(th)
+	parent, err := svc.CreateGroup(context.Background(), token, things.Group{Name: "site"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
 		desc        string
@@ -889,68 +5080,55 @@ func TestCreateChannel(t *testing.T) {
 		location    string
 	}{
 		{
-			desc:        "create new channel",
-			req:         data,
+			desc:        "create valid group",
+			req:         toJSON(map[string]interface{}{"name": "building"}),
 			contentType: contentType,
 			auth:        token,
 			status:      http.StatusCreated,
-			location:    "/channels/1",
+			location:    "/groups/123e4567-e89b-12d3-a456-000000000002",
 		},
 		{
-			desc:        "create new channel with invalid token",
-			req:         data,
+			desc:        "create valid subgroup",
+			req:         toJSON(map[string]interface{}{"name": "floor", "parent": parent.ID}),
 			contentType: contentType,
-			auth:        wrongValue,
-			status:      http.StatusForbidden,
-			location:    "",
+			auth:        token,
+			status:      http.StatusCreated,
+			location:    "/groups/123e4567-e89b-12d3-a456-000000000003",
 		},
 		{
-			desc:        "create new channel with empty token",
-			req:         data,
+			desc:        "create subgroup of non-existing group",
+			req:         toJSON(map[string]interface{}{"name": "floor", "parent": wrongValue}),
 			contentType: contentType,
-			auth:        "",
-			status:      http.StatusForbidden,
-			location:    "",
+			auth:        token,
+			status:      http.StatusNotFound,
 		},
 		{
-			desc:        "create new channel with invalid data format",
-			req:         "{",
+			desc:        "create group with too long name",
+			req:         toJSON(map[string]interface{}{"name": invalidName}),
 			contentType: contentType,
 			auth:        token,
 			status:      http.StatusBadRequest,
-			location:    "",
 		},
 		{
-			desc:        "create new channel with empty JSON request",
-			req:         "{}",
+			desc:        "create group with invalid request format",
+			req:         "}",
 			contentType: contentType,
 			auth:        token,
-			status:      http.StatusCreated,
-			location:    "/channels/2",
+			status:      http.StatusBadRequest,
 		},
 		{
-			desc:        "create new channel with empty request",
-			req:         "",
+			desc:        "create group with invalid auth token",
+			req:         toJSON(map[string]interface{}{"name": "building"}),
 			contentType: contentType,
-			auth:        token,
-			status:      http.StatusBadRequest,
-			location:    "",
+			auth:        wrongValue,
+			status:      http.StatusForbidden,
 		},
 		{
-			desc:        "create new channel without content type",
-			req:         data,
+			desc:        "create group without content type",
+			req:         toJSON(map[string]interface{}{"name": "building"}),
 			contentType: "",
 			auth:        token,
 			status:      http.StatusUnsupportedMediaType,
-			location:    "",
-		},
-		{
-			desc:        "create new channel with invalid name",
-			req:         invalidData,
-			contentType: contentType,
-			auth:        token,
-			status:      http.StatusBadRequest,
-			location:    "",
 		},
 	}
 
@@ -958,120 +5136,268 @@ func TestCreateChannel(t *testing.T) {
 		req := testRequest{
 			client:      ts.Client(),
 			method:      http.MethodPost,
-			url:         fmt.Sprintf("%s/channels", ts.URL),
+			url:         fmt.Sprintf("%s/groups", ts.URL),
 			contentType: tc.contentType,
 			token:       tc.auth,
 			body:        strings.NewReader(tc.req),
 		}
 		res, err := req.make()
 		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
 
 		location := res.Header.Get("Location")
-		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
 		assert.Equal(t, tc.location, location, fmt.Sprintf("%s: expected location %s got %s", tc.desc, tc.location, location))
 	}
 }
 
-func TestUpdateChannel(t *testing.T) {
+func TestViewGroup(t *testing.T) {
 	svc := newService(map[string]string{token: email})
 	ts := newServer(svc)
 	defer ts.Close()
 
-	sch, _ := svc.CreateChannel(token, channel)
+	parent, err := svc.CreateGroup(context.Background(), token, things.Group{Name: "site"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	child, err := svc.CreateGroup(context.Background(), token, things.Group{Name: "floor", Parent: parent.ID, Metadata: map[string]interface{}{"level": "1"}})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
-	ch := channel
-	ch.Name = "updated_channel"
-	updateData := toJSON(ch)
+	cases := []struct {
+		desc   string
+		id     string
+		auth   string
+		status int
+		res    string
+	}{
+		{
+			desc:   "view existing subgroup",
+			id:     child.ID,
+			auth:   token,
+			status: http.StatusOK,
+			res:    toJSON(groupRes{ID: child.ID, Parent: parent.ID, Name: child.Name, Metadata: child.Metadata}),
+		},
+		{
+			desc:   "view non-existent group",
+			id:     wrongID,
+			auth:   token,
+			status: http.StatusNotFound,
+			res:    "",
+		},
+		{
+			desc:   "view group with invalid token",
+			id:     child.ID,
+			auth:   wrongValue,
+			status: http.StatusForbidden,
+			res:    "",
+		},
+	}
 
-	ch.Name = invalidName
-	invalidData := toJSON(ch)
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/groups/%s", ts.URL, tc.id),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		body, err := ioutil.ReadAll(res.Body)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		data := strings.Trim(string(body), "\n")
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		assert.Equal(t, tc.res, data, fmt.Sprintf("%s: expected body %s got %s", tc.desc, tc.res, data))
+	}
+}
+
+func TestCreateProfile(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	sch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
 		desc        string
 		req         string
-		id          string
 		contentType string
 		auth        string
 		status      int
+		location    string
 	}{
 		{
-			desc:        "update existing channel",
-			req:         updateData,
-			id:          sch.ID,
+			desc:        "create valid profile",
+			req:         toJSON(map[string]interface{}{"name": "sensor", "channels": []string{sch.ID}, "bootstrap": "config"}),
 			contentType: contentType,
 			auth:        token,
-			status:      http.StatusOK,
+			status:      http.StatusCreated,
+			location:    "/profiles/123e4567-e89b-12d3-a456-000000000002",
 		},
 		{
-			desc:        "update non-existing channel",
-			req:         updateData,
-			id:          strconv.FormatUint(wrongID, 10),
+			desc:        "create profile with non-existing channel",
+			req:         toJSON(map[string]interface{}{"name": "sensor", "channels": []string{wrongValue}}),
 			contentType: contentType,
 			auth:        token,
 			status:      http.StatusNotFound,
 		},
 		{
-			desc:        "update channel with invalid id",
-			req:         updateData,
-			id:          "invalid",
+			desc:        "create profile with too long name",
+			req:         toJSON(map[string]interface{}{"name": invalidName}),
 			contentType: contentType,
 			auth:        token,
-			status:      http.StatusNotFound,
+			status:      http.StatusBadRequest,
 		},
 		{
-			desc:        "update channel with invalid token",
-			req:         updateData,
-			id:          sch.ID,
+			desc:        "create profile with invalid request format",
+			req:         "}",
 			contentType: contentType,
-			auth:        wrongValue,
-			status:      http.StatusForbidden,
+			auth:        token,
+			status:      http.StatusBadRequest,
 		},
 		{
-			desc:        "update channel with empty token",
-			req:         updateData,
-			id:          sch.ID,
+			desc:        "create profile with invalid auth token",
+			req:         toJSON(map[string]interface{}{"name": "sensor"}),
 			contentType: contentType,
-			auth:        "",
+			auth:        wrongValue,
 			status:      http.StatusForbidden,
 		},
 		{
-			desc:        "update channel with invalid data format",
-			req:         "}",
-			id:          sch.ID,
+			desc:        "create profile without content type",
+			req:         toJSON(map[string]interface{}{"name": "sensor"}),
+			contentType: "",
+			auth:        token,
+			status:      http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/profiles", ts.URL),
+			contentType: tc.contentType,
+			token:       tc.auth,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+
+		location := res.Header.Get("Location")
+		assert.Equal(t, tc.location, location, fmt.Sprintf("%s: expected location %s got %s", tc.desc, tc.location, location))
+	}
+}
+
+func TestViewProfile(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	profile, err := svc.CreateProfile(context.Background(), token, things.Profile{Name: "sensor", Bootstrap: "config"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		id     string
+		auth   string
+		status int
+		res    string
+	}{
+		{
+			desc:   "view existing profile",
+			id:     profile.ID,
+			auth:   token,
+			status: http.StatusOK,
+			res:    toJSON(profileRes{ID: profile.ID, Name: profile.Name, Channels: []string{}, Bootstrap: profile.Bootstrap}),
+		},
+		{
+			desc:   "view non-existent profile",
+			id:     wrongID,
+			auth:   token,
+			status: http.StatusNotFound,
+			res:    "",
+		},
+		{
+			desc:   "view profile with invalid token",
+			id:     profile.ID,
+			auth:   wrongValue,
+			status: http.StatusForbidden,
+			res:    "",
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/profiles/%s", ts.URL, tc.id),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		body, err := ioutil.ReadAll(res.Body)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		data := strings.Trim(string(body), "\n")
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		assert.Equal(t, tc.res, data, fmt.Sprintf("%s: expected body %s got %s", tc.desc, tc.res, data))
+	}
+}
+
+func TestAssignThings(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	gr, err := svc.CreateGroup(context.Background(), token, things.Group{Name: "site"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	th, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc        string
+		id          string
+		req         string
+		contentType string
+		auth        string
+		status      int
+	}{
+		{
+			desc:        "assign thing to group",
+			id:          gr.ID,
+			req:         toJSON(map[string]interface{}{"things": []string{th.ID}}),
 			contentType: contentType,
 			auth:        token,
-			status:      http.StatusBadRequest,
+			status:      http.StatusOK,
 		},
 		{
-			desc:        "update channel with empty JSON object",
-			req:         "{}",
-			id:          sch.ID,
+			desc:        "assign non-existent thing to group",
+			id:          gr.ID,
+			req:         toJSON(map[string]interface{}{"things": []string{wrongID}}),
 			contentType: contentType,
 			auth:        token,
-			status:      http.StatusOK,
+			status:      http.StatusNotFound,
 		},
 		{
-			desc:        "update channel with empty request",
-			req:         "",
-			id:          sch.ID,
+			desc:        "assign thing to non-existent group",
+			id:          wrongID,
+			req:         toJSON(map[string]interface{}{"things": []string{th.ID}}),
 			contentType: contentType,
 			auth:        token,
-			status:      http.StatusBadRequest,
+			status:      http.StatusNotFound,
 		},
 		{
-			desc:        "update channel with missing content type",
-			req:         updateData,
-			id:          sch.ID,
-			contentType: "",
+			desc:        "assign empty list of things to group",
+			id:          gr.ID,
+			req:         toJSON(map[string]interface{}{"things": []string{}}),
+			contentType: contentType,
 			auth:        token,
-			status:      http.StatusUnsupportedMediaType,
+			status:      http.StatusBadRequest,
 		},
 		{
-			desc:        "update channel with invalid name",
-			req:         invalidData,
+			desc:        "assign thing to group with invalid token",
+			id:          gr.ID,
+			req:         toJSON(map[string]interface{}{"things": []string{th.ID}}),
 			contentType: contentType,
-			auth:        token,
-			status:      http.StatusBadRequest,
+			auth:        wrongValue,
+			status:      http.StatusForbidden,
 		},
 	}
 
@@ -1079,7 +5405,7 @@ func TestUpdateChannel(t *testing.T) {
 		req := testRequest{
 			client:      ts.Client(),
 			method:      http.MethodPut,
-			url:         fmt.Sprintf("%s/channels/%s", ts.URL, tc.id),
+			url:         fmt.Sprintf("%s/groups/%s/things", ts.URL, tc.id),
 			contentType: tc.contentType,
 			token:       tc.auth,
 			body:        strings.NewReader(tc.req),
@@ -1090,217 +5416,324 @@ func TestUpdateChannel(t *testing.T) {
 	}
 }
 
-func TestViewChannel(t *testing.T) {
+func TestConnectGroup(t *testing.T) {
 	svc := newService(map[string]string{token: email})
 	ts := newServer(svc)
 	defer ts.Close()
 
-	sch, _ := svc.CreateChannel(token, channel)
-
-	sth, _ := svc.AddThing(token, thing)
-	svc.Connect(token, sch.ID, sth.ID)
-
-	chres := channelRes{
-		ID:       sch.ID,
-		Name:     sch.Name,
-		Metadata: sch.Metadata,
-	}
-	data := toJSON(chres)
+	gr, err := svc.CreateGroup(context.Background(), token, things.Group{Name: "site"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	th, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = svc.AssignThings(context.Background(), token, gr.ID, th.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	ch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
-		desc   string
-		id     string
-		auth   string
-		status int
-		res    string
+		desc    string
+		chanID  string
+		groupID string
+		auth    string
+		status  int
+		res     string
 	}{
 		{
-			desc:   "view existing channel",
-			id:     sch.ID,
-			auth:   token,
-			status: http.StatusOK,
-			res:    data,
+			desc:    "connect group to channel",
+			chanID:  ch.ID,
+			groupID: gr.ID,
+			auth:    token,
+			status:  http.StatusOK,
+			res:     toJSON(map[string]interface{}{"connected": []string{th.ID}}),
 		},
 		{
-			desc:   "view non-existent channel",
-			id:     strconv.FormatUint(wrongID, 10),
-			auth:   token,
-			status: http.StatusNotFound,
-			res:    "",
+			desc:    "connect connected group to channel",
+			chanID:  ch.ID,
+			groupID: gr.ID,
+			auth:    token,
+			status:  http.StatusOK,
+			res:     toJSON(map[string]interface{}{"connected": []string{}}),
 		},
 		{
-			desc:   "view channel with invalid token",
-			id:     sch.ID,
-			auth:   wrongValue,
-			status: http.StatusForbidden,
-			res:    "",
+			desc:    "connect group to non-existent channel",
+			chanID:  wrongID,
+			groupID: gr.ID,
+			auth:    token,
+			status:  http.StatusNotFound,
+			res:     "",
 		},
 		{
-			desc:   "view channel with empty token",
-			id:     sch.ID,
-			auth:   "",
-			status: http.StatusForbidden,
-			res:    "",
+			desc:    "connect non-existent group to channel",
+			chanID:  ch.ID,
+			groupID: wrongID,
+			auth:    token,
+			status:  http.StatusNotFound,
+			res:     "",
 		},
 		{
-			desc:   "view channel with invalid id",
-			id:     "invalid",
-			auth:   token,
-			status: http.StatusNotFound,
-			res:    "",
+			desc:    "connect group to channel with invalid token",
+			chanID:  ch.ID,
+			groupID: gr.ID,
+			auth:    wrongValue,
+			status:  http.StatusForbidden,
+			res:     "",
 		},
 	}
 
 	for _, tc := range cases {
 		req := testRequest{
 			client: ts.Client(),
-			method: http.MethodGet,
-			url:    fmt.Sprintf("%s/channels/%s", ts.URL, tc.id),
+			method: http.MethodPut,
+			url:    fmt.Sprintf("%s/channels/%s/groups/%s", ts.URL, tc.chanID, tc.groupID),
 			token:  tc.auth,
 		}
 		res, err := req.make()
 		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
-		data, err := ioutil.ReadAll(res.Body)
+		body, err := ioutil.ReadAll(res.Body)
 		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
-		body := strings.Trim(string(data), "\n")
+		data := strings.Trim(string(body), "\n")
 		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
-		assert.Equal(t, tc.res, body, fmt.Sprintf("%s: expected body %s got %s", tc.desc, tc.res, body))
+		assert.Equal(t, tc.res, data, fmt.Sprintf("%s: expected body %s got %s", tc.desc, tc.res, data))
 	}
 }
 
-func TestListChannels(t *testing.T) {
+func TestRegisterSubtopic(t *testing.T) {
 	svc := newService(map[string]string{token: email})
 	ts := newServer(svc)
 	defer ts.Close()
 
-	channels := []channelRes{}
-	for i := 0; i < 101; i++ {
-		sch, err := svc.CreateChannel(token, channel)
-		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
-		sth, err := svc.AddThing(token, thing)
-		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
-		svc.Connect(token, sch.ID, sth.ID)
+	ch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
-		chres := channelRes{
-			ID:       sch.ID,
-			Name:     sch.Name,
-			Metadata: sch.Metadata,
-		}
-		channels = append(channels, chres)
-	}
-	channelURL := fmt.Sprintf("%s/channels", ts.URL)
+	data := toJSON(map[string]interface{}{
+		"description": "Temperature in °C",
+		"schema":      map[string]interface{}{"type": "number"},
+	})
 
 	cases := []struct {
-		desc   string
-		auth   string
-		status int
-		url    string
-		res    []channelRes
+		desc        string
+		chanID      string
+		name        string
+		req         string
+		contentType string
+		auth        string
+		status      int
 	}{
 		{
-			desc:   "get a list of channels",
-			auth:   token,
-			status: http.StatusOK,
-			url:    fmt.Sprintf("%s?offset=%d&limit=%d", channelURL, 0, 6),
-			res:    channels[0:6],
+			desc:        "register subtopic",
+			chanID:      ch.ID,
+			name:        "sensors.temp",
+			req:         data,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusOK,
 		},
 		{
-			desc:   "get a list of channels with invalid token",
-			auth:   wrongValue,
-			status: http.StatusForbidden,
-			url:    fmt.Sprintf("%s?offset=%d&limit=%d", channelURL, 0, 1),
-			res:    nil,
+			desc:        "register subtopic with wildcard",
+			chanID:      ch.ID,
+			name:        "sensors.>",
+			req:         data,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusOK,
 		},
 		{
-			desc:   "get a list of channels with empty token",
-			auth:   "",
-			status: http.StatusForbidden,
-			url:    fmt.Sprintf("%s?offset=%d&limit=%d", channelURL, 0, 1),
-			res:    nil,
+			desc:        "register malformed subtopic",
+			chanID:      ch.ID,
+			name:        "sensors.temp*",
+			req:         data,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
 		},
 		{
-			desc:   "get a list of channels with negative offset",
-			auth:   token,
-			status: http.StatusBadRequest,
-			url:    fmt.Sprintf("%s?offset=%d&limit=%d", channelURL, -1, 5),
-			res:    nil,
+			desc:        "register subtopic with invalid request format",
+			chanID:      ch.ID,
+			name:        "sensors.temp",
+			req:         "}",
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
 		},
 		{
-			desc:   "get a list of channels with negative limit",
-			auth:   token,
-			status: http.StatusBadRequest,
-			url:    fmt.Sprintf("%s?offset=%d&limit=%d", channelURL, -1, 5),
-			res:    nil,
+			desc:        "register subtopic without content type",
+			chanID:      ch.ID,
+			name:        "sensors.temp",
+			req:         data,
+			contentType: "",
+			auth:        token,
+			status:      http.StatusUnsupportedMediaType,
 		},
 		{
-			desc:   "get a list of channels with zero limit",
-			auth:   token,
-			status: http.StatusBadRequest,
-			url:    fmt.Sprintf("%s?offset=%d&limit=%d", channelURL, 1, 0),
-			res:    nil,
+			desc:        "register subtopic of non-existent channel",
+			chanID:      wrongID,
+			name:        "sensors.temp",
+			req:         data,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusNotFound,
 		},
 		{
-			desc:   "get a list of channels with no offset provided",
-			auth:   token,
-			status: http.StatusOK,
-			url:    fmt.Sprintf("%s?limit=%d", channelURL, 5),
-			res:    channels[0:5],
+			desc:        "register subtopic with invalid token",
+			chanID:      ch.ID,
+			name:        "sensors.temp",
+			req:         data,
+			contentType: contentType,
+			auth:        wrongValue,
+			status:      http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPut,
+			url:         fmt.Sprintf("%s/channels/%s/subtopics/%s", ts.URL, tc.chanID, tc.name),
+			contentType: tc.contentType,
+			token:       tc.auth,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestAddSubtopicRule(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	sth, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	ch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	data := toJSON(map[string]string{"thing_id": sth.ID, "action": "publish", "pattern": "telemetry.>"})
+
+	cases := []struct {
+		desc        string
+		chanID      string
+		req         string
+		contentType string
+		auth        string
+		status      int
+		pattern     string
+	}{
+		{
+			desc:        "add subtopic rule",
+			chanID:      ch.ID,
+			req:         data,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusCreated,
+			pattern:     "telemetry.>",
 		},
 		{
-			desc:   "get a list of channels with no limit provided",
-			auth:   token,
-			status: http.StatusOK,
-			url:    fmt.Sprintf("%s?offset=%d", channelURL, 1),
-			res:    channels[1:11],
+			desc:        "add subtopic rule with invalid action",
+			chanID:      ch.ID,
+			req:         toJSON(map[string]string{"thing_id": sth.ID, "action": "manage", "pattern": "telemetry.>"}),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
 		},
 		{
-			desc:   "get a list of channels with redundant query params",
-			auth:   token,
-			status: http.StatusOK,
-			url:    fmt.Sprintf("%s?offset=%d&limit=%d&value=something", channelURL, 0, 5),
-			res:    channels[0:5],
+			desc:        "add subtopic rule without pattern",
+			chanID:      ch.ID,
+			req:         toJSON(map[string]string{"thing_id": sth.ID, "action": "publish"}),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
 		},
 		{
-			desc:   "get a list of channels with limit greater than max",
-			auth:   token,
-			status: http.StatusBadRequest,
-			url:    fmt.Sprintf("%s?offset=%d&limit=%d", channelURL, 0, 110),
-			res:    nil,
+			desc:        "add subtopic rule with invalid request format",
+			chanID:      ch.ID,
+			req:         "}",
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
 		},
 		{
-			desc:   "get a list of channels with default URL",
-			auth:   token,
-			status: http.StatusOK,
-			url:    fmt.Sprintf("%s%s", channelURL, ""),
-			res:    channels[0:10],
+			desc:        "add subtopic rule without content type",
+			chanID:      ch.ID,
+			req:         data,
+			contentType: "",
+			auth:        token,
+			status:      http.StatusUnsupportedMediaType,
+		},
+		{
+			desc:        "add subtopic rule to non-existent channel",
+			chanID:      wrongID,
+			req:         data,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusNotFound,
 		},
 		{
-			desc:   "get a list of channels with invalid number of params",
-			auth:   token,
-			status: http.StatusBadRequest,
-			url:    fmt.Sprintf("%s%s", channelURL, "?offset=4&limit=4&limit=5&offset=5"),
-			res:    nil,
+			desc:        "add subtopic rule with invalid token",
+			chanID:      ch.ID,
+			req:         data,
+			contentType: contentType,
+			auth:        wrongValue,
+			status:      http.StatusForbidden,
 		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/channels/%s/subtopic-rules", ts.URL, tc.chanID),
+			contentType: tc.contentType,
+			token:       tc.auth,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		var body subtopicRuleRes
+		json.NewDecoder(res.Body).Decode(&body)
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		assert.Equal(t, tc.pattern, body.Pattern, fmt.Sprintf("%s: expected pattern %s got %s", tc.desc, tc.pattern, body.Pattern))
+	}
+}
+
+func TestListSubtopicRules(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	sth, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	ch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	rule, err := svc.AddSubtopicRule(context.Background(), token, ch.ID, things.SubtopicRule{ThingID: sth.ID, Action: "subscribe", Pattern: "commands.*"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		chanID string
+		auth   string
+		status int
+		res    []subtopicRuleRes
+	}{
 		{
-			desc:   "get a list of channels with invalid offset",
+			desc:   "list subtopic rules",
+			chanID: ch.ID,
 			auth:   token,
-			status: http.StatusBadRequest,
-			url:    fmt.Sprintf("%s%s", channelURL, "?offset=e&limit=5"),
-			res:    nil,
+			status: http.StatusOK,
+			res:    []subtopicRuleRes{{ID: rule.ID, ThingID: sth.ID, Action: "subscribe", Pattern: "commands.*"}},
 		},
 		{
-			desc:   "get a list of channels with invalid limit",
+			desc:   "list subtopic rules of non-existent channel",
+			chanID: wrongID,
 			auth:   token,
-			status: http.StatusBadRequest,
-			url:    fmt.Sprintf("%s%s", channelURL, "?offset=5&limit=e"),
-			res:    nil,
+			status: http.StatusNotFound,
 		},
 		{
-			desc:   "get a list of channels with invalid name",
-			auth:   token,
-			status: http.StatusBadRequest,
-			url:    fmt.Sprintf("%s?offset=%d&limit=%d&name=%s", channelURL, 0, 10, invalidName),
-			res:    nil,
+			desc:   "list subtopic rules with invalid token",
+			chanID: ch.ID,
+			auth:   wrongValue,
+			status: http.StatusForbidden,
 		},
 	}
 
@@ -1308,308 +5741,255 @@ func TestListChannels(t *testing.T) {
 		req := testRequest{
 			client: ts.Client(),
 			method: http.MethodGet,
-			url:    tc.url,
+			url:    fmt.Sprintf("%s/channels/%s/subtopic-rules", ts.URL, tc.chanID),
 			token:  tc.auth,
 		}
 		res, err := req.make()
 		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
-		var body channelsPageRes
+		var body subtopicRulesRes
 		json.NewDecoder(res.Body).Decode(&body)
 		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
-		assert.ElementsMatch(t, tc.res, body.Channels, fmt.Sprintf("%s: expected body %v got %v", tc.desc, tc.res, body.Channels))
+		assert.Equal(t, tc.res, body.Rules, fmt.Sprintf("%s: expected rules %v got %v", tc.desc, tc.res, body.Rules))
 	}
 }
 
-func TestListChannelsByThing(t *testing.T) {
+func TestRemoveSubtopicRule(t *testing.T) {
 	svc := newService(map[string]string{token: email})
 	ts := newServer(svc)
 	defer ts.Close()
 
-	sth, err := svc.AddThing(token, thing)
+	sth, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	ch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	rule, err := svc.AddSubtopicRule(context.Background(), token, ch.ID, things.SubtopicRule{ThingID: sth.ID, Action: "publish", Pattern: "telemetry.>"})
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
-
-	channels := []channelRes{}
-	for i := 0; i < 101; i++ {
-		sch, err := svc.CreateChannel(token, channel)
-		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
-		err = svc.Connect(token, sch.ID, sth.ID)
-		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
-
-		chres := channelRes{
-			ID:       sch.ID,
-			Name:     sch.Name,
-			Metadata: sch.Metadata,
-		}
-		channels = append(channels, chres)
-	}
-	channelURL := fmt.Sprintf("%s/things", ts.URL)
 
 	cases := []struct {
 		desc   string
+		ruleID string
 		auth   string
 		status int
-		url    string
-		res    []channelRes
 	}{
 		{
-			desc:   "get a list of channels by thing",
-			auth:   token,
-			status: http.StatusOK,
-			url:    fmt.Sprintf("%s/%s/channels?offset=%d&limit=%d", channelURL, sth.ID, 0, 6),
-			res:    channels[0:6],
-		},
-		{
-			desc:   "get a list of channels by thing with invalid token",
+			desc:   "remove subtopic rule with invalid token",
+			ruleID: rule.ID,
 			auth:   wrongValue,
 			status: http.StatusForbidden,
-			url:    fmt.Sprintf("%s/%s/channels?offset=%d&limit=%d", channelURL, sth.ID, 0, 1),
-			res:    nil,
-		},
-		{
-			desc:   "get a list of channels by thing with empty token",
-			auth:   "",
-			status: http.StatusForbidden,
-			url:    fmt.Sprintf("%s/%s/channels?offset=%d&limit=%d", channelURL, sth.ID, 0, 1),
-			res:    nil,
-		},
-		{
-			desc:   "get a list of channels by thing with negative offset",
-			auth:   token,
-			status: http.StatusBadRequest,
-			url:    fmt.Sprintf("%s/%s/channels?offset=%d&limit=%d", channelURL, sth.ID, -1, 5),
-			res:    nil,
-		},
-		{
-			desc:   "get a list of channels by thing with negative limit",
-			auth:   token,
-			status: http.StatusBadRequest,
-			url:    fmt.Sprintf("%s/%s/channels?offset=%d&limit=%d", channelURL, sth.ID, -1, 5),
-			res:    nil,
-		},
-		{
-			desc:   "get a list of channels by thing with zero limit",
-			auth:   token,
-			status: http.StatusBadRequest,
-			url:    fmt.Sprintf("%s/%s/channels?offset=%d&limit=%d", channelURL, sth.ID, 1, 0),
-			res:    nil,
-		},
-		{
-			desc:   "get a list of channels by thing with no offset provided",
-			auth:   token,
-			status: http.StatusOK,
-			url:    fmt.Sprintf("%s/%s/channels?limit=%d", channelURL, sth.ID, 5),
-			res:    channels[0:5],
-		},
-		{
-			desc:   "get a list of channels by thing with no limit provided",
-			auth:   token,
-			status: http.StatusOK,
-			url:    fmt.Sprintf("%s/%s/channels?offset=%d", channelURL, sth.ID, 1),
-			res:    channels[1:11],
-		},
-		{
-			desc:   "get a list of channels by thing with redundant query params",
-			auth:   token,
-			status: http.StatusOK,
-			url:    fmt.Sprintf("%s/%s/channels?offset=%d&limit=%d&value=something", channelURL, sth.ID, 0, 5),
-			res:    channels[0:5],
-		},
-		{
-			desc:   "get a list of channels by thing with limit greater than max",
-			auth:   token,
-			status: http.StatusBadRequest,
-			url:    fmt.Sprintf("%s/%s/channels?offset=%d&limit=%d", channelURL, sth.ID, 0, 110),
-			res:    nil,
-		},
-		{
-			desc:   "get a list of channels by thing with default URL",
-			auth:   token,
-			status: http.StatusOK,
-			url:    fmt.Sprintf("%s/%s/channels", channelURL, sth.ID),
-			res:    channels[0:10],
-		},
-		{
-			desc:   "get a list of channels by thing with invalid number of params",
-			auth:   token,
-			status: http.StatusBadRequest,
-			url:    fmt.Sprintf("%s/%s/channels%s", channelURL, sth.ID, "?offset=4&limit=4&limit=5&offset=5"),
-			res:    nil,
 		},
 		{
-			desc:   "get a list of channels by thing with invalid offset",
+			desc:   "remove existing subtopic rule",
+			ruleID: rule.ID,
 			auth:   token,
-			status: http.StatusBadRequest,
-			url:    fmt.Sprintf("%s/%s/channels%s", channelURL, sth.ID, "?offset=e&limit=5"),
-			res:    nil,
+			status: http.StatusNoContent,
 		},
 		{
-			desc:   "get a list of channels by thing with invalid limit",
+			desc:   "remove non-existent subtopic rule",
+			ruleID: rule.ID,
 			auth:   token,
-			status: http.StatusBadRequest,
-			url:    fmt.Sprintf("%s/%s/channels%s", channelURL, sth.ID, "?offset=5&limit=e"),
-			res:    nil,
+			status: http.StatusNotFound,
 		},
 	}
 
 	for _, tc := range cases {
 		req := testRequest{
 			client: ts.Client(),
-			method: http.MethodGet,
-			url:    tc.url,
+			method: http.MethodDelete,
+			url:    fmt.Sprintf("%s/channels/%s/subtopic-rules/%s", ts.URL, ch.ID, tc.ruleID),
 			token:  tc.auth,
 		}
 		res, err := req.make()
 		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
-		var body channelsPageRes
-		json.NewDecoder(res.Body).Decode(&body)
 		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
-		assert.ElementsMatch(t, tc.res, body.Channels, fmt.Sprintf("%s: expected body %v got %v", tc.desc, tc.res, body.Channels))
 	}
 }
 
-func TestRemoveChannel(t *testing.T) {
+func TestObservedSubtopics(t *testing.T) {
 	svc := newService(map[string]string{token: email})
 	ts := newServer(svc)
 	defer ts.Close()
 
-	sch, _ := svc.CreateChannel(token, channel)
+	ch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = svc.RegisterSubtopic(context.Background(), token, ch.ID, things.Subtopic{Name: "sensors.temp"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	for _, subtopic := range []string{"sensors.temp", "debug"} {
+		err := svc.RecordSubtopic(context.Background(), ch.ID, subtopic)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
 
 	cases := []struct {
 		desc   string
-		id     string
+		chanID string
 		auth   string
 		status int
+		res    []observedSubtopicRes
 	}{
 		{
-			desc:   "remove channel with invalid token",
-			id:     sch.ID,
-			auth:   wrongValue,
-			status: http.StatusForbidden,
-		},
-		{
-			desc:   "remove existing channel",
-			id:     sch.ID,
+			desc:   "retrieve observed subtopics",
+			chanID: ch.ID,
 			auth:   token,
-			status: http.StatusNoContent,
+			status: http.StatusOK,
+			res: []observedSubtopicRes{
+				{Name: "debug", Messages: 1, Registered: false},
+				{Name: "sensors.temp", Messages: 1, Registered: true},
+			},
 		},
 		{
-			desc:   "remove removed channel",
-			id:     sch.ID,
+			desc:   "retrieve observed subtopics of non-existent channel",
+			chanID: wrongID,
 			auth:   token,
-			status: http.StatusNoContent,
+			status: http.StatusNotFound,
+			res:    nil,
 		},
 		{
-			desc:   "remove channel with invalid token",
-			id:     sch.ID,
+			desc:   "retrieve observed subtopics with invalid token",
+			chanID: ch.ID,
 			auth:   wrongValue,
 			status: http.StatusForbidden,
-		},
-		{
-			desc:   "remove channel with empty token",
-			id:     sch.ID,
-			auth:   "",
-			status: http.StatusForbidden,
+			res:    nil,
 		},
 	}
 
 	for _, tc := range cases {
 		req := testRequest{
 			client: ts.Client(),
-			method: http.MethodDelete,
-			url:    fmt.Sprintf("%s/channels/%s", ts.URL, tc.id),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/channels/%s/subtopics/observed", ts.URL, tc.chanID),
 			token:  tc.auth,
 		}
 		res, err := req.make()
 		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		var body observedSubtopicsRes
+		json.NewDecoder(res.Body).Decode(&body)
+		for i := range body.Subtopics {
+			assert.NotZero(t, body.Subtopics[i].LastSeen, fmt.Sprintf("%s: expected last seen time of %s", tc.desc, body.Subtopics[i].Name))
+			body.Subtopics[i].LastSeen = 0
+		}
 		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		assert.Equal(t, tc.res, body.Subtopics, fmt.Sprintf("%s: expected subtopics %v got %v", tc.desc, tc.res, body.Subtopics))
 	}
 }
 
-func TestConnect(t *testing.T) {
-	otherToken := "other_token"
-	otherEmail := "other_user@example.com"
-	svc := newService(map[string]string{
-		token:      email,
-		otherToken: otherEmail,
-	})
+func TestTransfer(t *testing.T) {
+	otherEmail := "other@example.com"
+	svc := newService(map[string]string{token: email, "other-token": otherEmail})
 	ts := newServer(svc)
 	defer ts.Close()
 
-	ath, _ := svc.AddThing(token, thing)
-	ach, _ := svc.CreateChannel(token, channel)
-	bch, _ := svc.CreateChannel(otherToken, channel)
+	th, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	ch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	transfer := toJSON(map[string]interface{}{"owner": otherEmail, "keep_connections": true})
 
 	cases := []struct {
-		desc    string
-		chanID  string
-		thingID string
-		auth    string
-		status  int
+		desc        string
+		url         string
+		req         string
+		contentType string
+		auth        string
+		status      int
 	}{
 		{
-			desc:    "connect existing thing to existing channel",
-			chanID:  ach.ID,
-			thingID: ath.ID,
-			auth:    token,
-			status:  http.StatusOK,
+			desc:        "transfer thing without new owner",
+			url:         fmt.Sprintf("%s/things/%s/transfer", ts.URL, th.ID),
+			req:         "{}",
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "transfer thing to its owner",
+			url:         fmt.Sprintf("%s/things/%s/transfer", ts.URL, th.ID),
+			req:         toJSON(map[string]string{"owner": email}),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "transfer thing to non-existent user",
+			url:         fmt.Sprintf("%s/things/%s/transfer", ts.URL, th.ID),
+			req:         toJSON(map[string]string{"owner": wrongValue}),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "transfer thing with invalid request format",
+			url:         fmt.Sprintf("%s/things/%s/transfer", ts.URL, th.ID),
+			req:         "}",
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
 		},
 		{
-			desc:    "connect existing thing to non-existent channel",
-			chanID:  strconv.FormatUint(wrongID, 10),
-			thingID: ath.ID,
-			auth:    token,
-			status:  http.StatusNotFound,
+			desc:        "transfer thing without content type",
+			url:         fmt.Sprintf("%s/things/%s/transfer", ts.URL, th.ID),
+			req:         transfer,
+			contentType: "",
+			auth:        token,
+			status:      http.StatusUnsupportedMediaType,
 		},
 		{
-			desc:    "connect non-existing thing to existing channel",
-			chanID:  ach.ID,
-			thingID: strconv.FormatUint(wrongID, 10),
-			auth:    token,
-			status:  http.StatusNotFound,
+			desc:        "transfer thing with invalid token",
+			url:         fmt.Sprintf("%s/things/%s/transfer", ts.URL, th.ID),
+			req:         transfer,
+			contentType: contentType,
+			auth:        wrongValue,
+			status:      http.StatusForbidden,
 		},
 		{
-			desc:    "connect existing thing to channel with invalid id",
-			chanID:  "invalid",
-			thingID: ath.ID,
-			auth:    token,
-			status:  http.StatusNotFound,
+			desc:        "transfer non-existent thing",
+			url:         fmt.Sprintf("%s/things/%s/transfer", ts.URL, wrongID),
+			req:         transfer,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusNotFound,
 		},
 		{
-			desc:    "connect thing with invalid id to existing channel",
-			chanID:  ach.ID,
-			thingID: "invalid",
-			auth:    token,
-			status:  http.StatusNotFound,
+			desc:        "transfer thing",
+			url:         fmt.Sprintf("%s/things/%s/transfer", ts.URL, th.ID),
+			req:         transfer,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusNoContent,
 		},
 		{
-			desc:    "connect existing thing to existing channel with invalid token",
-			chanID:  ach.ID,
-			thingID: ath.ID,
-			auth:    wrongValue,
-			status:  http.StatusForbidden,
+			desc:        "transfer transferred thing",
+			url:         fmt.Sprintf("%s/things/%s/transfer", ts.URL, th.ID),
+			req:         transfer,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusNotFound,
 		},
 		{
-			desc:    "connect existing thing to existing channel with empty token",
-			chanID:  ach.ID,
-			thingID: ath.ID,
-			auth:    "",
-			status:  http.StatusForbidden,
+			desc:        "transfer channel",
+			url:         fmt.Sprintf("%s/channels/%s/transfer", ts.URL, ch.ID),
+			req:         toJSON(map[string]string{"owner": otherEmail}),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusNoContent,
 		},
 		{
-			desc:    "connect thing from owner to channel of other user",
-			chanID:  bch.ID,
-			thingID: ath.ID,
-			auth:    token,
-			status:  http.StatusNotFound,
+			desc:        "transfer non-existent channel",
+			url:         fmt.Sprintf("%s/channels/%s/transfer", ts.URL, wrongID),
+			req:         transfer,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusNotFound,
 		},
 	}
 
 	for _, tc := range cases {
 		req := testRequest{
-			client: ts.Client(),
-			method: http.MethodPut,
-			url:    fmt.Sprintf("%s/channels/%s/things/%s", ts.URL, tc.chanID, tc.thingID),
-			token:  tc.auth,
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         tc.url,
+			contentType: tc.contentType,
+			token:       tc.auth,
+			body:        strings.NewReader(tc.req),
 		}
 		res, err := req.make()
 		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
@@ -1617,116 +5997,251 @@ func TestConnect(t *testing.T) {
 	}
 }
 
-func TestDisconnnect(t *testing.T) {
-	otherToken := "other_token"
-	otherEmail := "other_user@example.com"
-	svc := newService(map[string]string{
-		token:      email,
-		otherToken: otherEmail,
-	})
+func TestShare(t *testing.T) {
+	otherEmail := "other@example.com"
+	svc := newService(map[string]string{token: email})
 	ts := newServer(svc)
 	defer ts.Close()
 
-	ath, _ := svc.AddThing(token, thing)
-	ach, _ := svc.CreateChannel(token, channel)
-	svc.Connect(token, ach.ID, ath.ID)
-	bch, _ := svc.CreateChannel(otherToken, channel)
+	th, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	ch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	read := toJSON(map[string]string{"permission": "read"})
 
 	cases := []struct {
-		desc    string
-		chanID  string
-		thingID string
-		auth    string
-		status  int
+		desc        string
+		url         string
+		req         string
+		contentType string
+		auth        string
+		status      int
 	}{
 		{
-			desc:    "disconnect connected thing from channel",
-			chanID:  ach.ID,
-			thingID: ath.ID,
-			auth:    token,
-			status:  http.StatusNoContent,
-		},
-		{
-			desc:    "disconnect non-connected thing from channel",
-			chanID:  ach.ID,
-			thingID: ath.ID,
-			auth:    token,
-			status:  http.StatusNotFound,
+			desc:        "share thing",
+			url:         fmt.Sprintf("%s/things/%s/shares/%s", ts.URL, th.ID, otherEmail),
+			req:         read,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusOK,
 		},
 		{
-			desc:    "disconnect non-existent thing from channel",
-			chanID:  ach.ID,
-			thingID: strconv.FormatUint(wrongID, 10),
-			auth:    token,
-			status:  http.StatusNotFound,
+			desc:        "share channel",
+			url:         fmt.Sprintf("%s/channels/%s/shares/%s", ts.URL, ch.ID, otherEmail),
+			req:         toJSON(map[string]string{"permission": "manage"}),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusOK,
 		},
 		{
-			desc:    "disconnect thing from non-existent channel",
-			chanID:  strconv.FormatUint(wrongID, 10),
-			thingID: ath.ID,
-			auth:    token,
-			status:  http.StatusNotFound,
+			desc:        "share thing with invalid permission",
+			url:         fmt.Sprintf("%s/things/%s/shares/%s", ts.URL, th.ID, otherEmail),
+			req:         toJSON(map[string]string{"permission": "admin"}),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
 		},
 		{
-			desc:    "disconnect thing from channel with invalid token",
-			chanID:  ach.ID,
-			thingID: ath.ID,
-			auth:    wrongValue,
-			status:  http.StatusForbidden,
+			desc:        "share thing without permission",
+			url:         fmt.Sprintf("%s/things/%s/shares/%s", ts.URL, th.ID, otherEmail),
+			req:         "{}",
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
 		},
 		{
-			desc:    "disconnect thing from channel with empty token",
-			chanID:  ach.ID,
-			thingID: ath.ID,
-			auth:    "",
-			status:  http.StatusForbidden,
+			desc:        "share thing with invalid request format",
+			url:         fmt.Sprintf("%s/things/%s/shares/%s", ts.URL, th.ID, otherEmail),
+			req:         "}",
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
 		},
 		{
-			desc:    "disconnect owner's thing from someone elses channel",
-			chanID:  bch.ID,
-			thingID: ath.ID,
-			auth:    token,
-			status:  http.StatusNotFound,
+			desc:        "share thing without content type",
+			url:         fmt.Sprintf("%s/things/%s/shares/%s", ts.URL, th.ID, otherEmail),
+			req:         read,
+			contentType: "",
+			auth:        token,
+			status:      http.StatusUnsupportedMediaType,
 		},
 		{
-			desc:    "disconnect thing with invalid id from channel",
-			chanID:  ach.ID,
-			thingID: "invalid",
-			auth:    token,
-			status:  http.StatusNotFound,
+			desc:        "share non-existent thing",
+			url:         fmt.Sprintf("%s/things/%s/shares/%s", ts.URL, wrongID, otherEmail),
+			req:         read,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusNotFound,
 		},
 		{
-			desc:    "disconnect thing from channel with invalid id",
-			chanID:  "invalid",
-			thingID: ath.ID,
-			auth:    token,
-			status:  http.StatusNotFound,
+			desc:        "share thing with invalid token",
+			url:         fmt.Sprintf("%s/things/%s/shares/%s", ts.URL, th.ID, otherEmail),
+			req:         read,
+			contentType: contentType,
+			auth:        wrongValue,
+			status:      http.StatusForbidden,
 		},
 	}
 
 	for _, tc := range cases {
 		req := testRequest{
-			client: ts.Client(),
-			method: http.MethodDelete,
-			url:    fmt.Sprintf("%s/channels/%s/things/%s", ts.URL, tc.chanID, tc.thingID),
-			token:  tc.auth,
+			client:      ts.Client(),
+			method:      http.MethodPut,
+			url:         tc.url,
+			contentType: tc.contentType,
+			token:       tc.auth,
+			body:        strings.NewReader(tc.req),
 		}
 		res, err := req.make()
 		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
 		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
 	}
+
+	req := testRequest{
+		client: ts.Client(),
+		method: http.MethodGet,
+		url:    fmt.Sprintf("%s/things/%s/shares", ts.URL, th.ID),
+		token:  token,
+	}
+	res, err := req.make()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	var body sharesRes
+	json.NewDecoder(res.Body).Decode(&body)
+	assert.Equal(t, http.StatusOK, res.StatusCode, fmt.Sprintf("list shares: expected status code %d got %d", http.StatusOK, res.StatusCode))
+	assert.Equal(t, []shareRes{{User: otherEmail, Permission: "read"}}, body.Shares, fmt.Sprintf("list shares: unexpected shares %v", body.Shares))
+
+	req = testRequest{
+		client: ts.Client(),
+		method: http.MethodDelete,
+		url:    fmt.Sprintf("%s/things/%s/shares/%s", ts.URL, th.ID, otherEmail),
+		token:  token,
+	}
+	res, err = req.make()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, http.StatusNoContent, res.StatusCode, fmt.Sprintf("unshare thing: expected status code %d got %d", http.StatusNoContent, res.StatusCode))
 }
 
 type thingRes struct {
 	ID       string                 `json:"id"`
 	Name     string                 `json:"name,omitempty"`
-	Key      string                 `json:"key"`
+	Key      string                 `json:"key,omitempty"`
+	State    string                 `json:"state,omitempty"`
+	Tags     []string               `json:"tags,omitempty"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	LastSeen int64                  `json:"last_seen,omitempty"`
+}
+
+type shareRes struct {
+	User       string `json:"user"`
+	Permission string `json:"permission"`
+}
+
+type sharesRes struct {
+	Shares []shareRes `json:"shares"`
+}
+
+type createThingsRes struct {
+	Things []thingRes `json:"things"`
+}
+
+type countRes struct {
+	Total uint64 `json:"total"`
+}
+
+type topologyRes struct {
+	Thing    thingRes     `json:"thing"`
+	Channels []channelRes `json:"channels"`
+}
+
+type channelTemplateRes struct {
+	Name     string                 `json:"name,omitempty"`
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
 }
 
+type templateRes struct {
+	Channels []channelTemplateRes `json:"channels"`
+}
+
+type onboardingRes struct {
+	ThingID   string            `json:"thing_id"`
+	ThingKey  string            `json:"thing_key"`
+	Channels  []string          `json:"channels"`
+	Endpoints map[string]string `json:"endpoints"`
+	ExpiresAt int64             `json:"expires_at"`
+	Code      string            `json:"code"`
+}
+
+type signedKeyRes struct {
+	ThingID   string   `json:"thing_id"`
+	Key       string   `json:"key"`
+	Channels  []string `json:"channels"`
+	ExpiresAt int64    `json:"expires_at"`
+}
+
+type invitationRes struct {
+	ID         string `json:"id"`
+	ChannelID  string `json:"channel_id"`
+	Invitation string `json:"invitation"`
+	ExpiresAt  int64  `json:"expires_at"`
+}
+
+type reservationsRes struct {
+	Reservations []struct {
+		ID  string `json:"id"`
+		Key string `json:"key"`
+	} `json:"reservations"`
+}
+
+type updateMetadataRes struct {
+	Updated int `json:"updated"`
+}
+
+type archiveChannelsRes struct {
+	Archived int `json:"archived"`
+}
+
+type connectionsReq struct {
+	ChanIDs  []string `json:"channels,omitempty"`
+	ThingIDs []string `json:"things,omitempty"`
+	Access   string   `json:"access,omitempty"`
+}
+
+type connectionFailureRes struct {
+	ChanID  string `json:"channel"`
+	ThingID string `json:"thing"`
+	Error   string `json:"error"`
+}
+
+type connectionErrorRes struct {
+	Failures []connectionFailureRes `json:"failures"`
+}
+
+type usageRes struct {
+	From     int64  `json:"from"`
+	To       int64  `json:"to"`
+	Messages uint64 `json:"messages"`
+	Bytes    uint64 `json:"bytes"`
+}
+
+type healthRes struct {
+	ThingID  string   `json:"thing_id"`
+	Battery  *float64 `json:"battery,omitempty"`
+	RSSI     *float64 `json:"rssi,omitempty"`
+	Firmware string   `json:"firmware,omitempty"`
+	Reported int64    `json:"reported"`
+}
+
+type healthPageRes struct {
+	Total  uint64      `json:"total"`
+	Things []healthRes `json:"things"`
+}
+
 type channelRes struct {
 	ID       string                 `json:"id"`
 	Name     string                 `json:"name,omitempty"`
+	State    string                 `json:"state,omitempty"`
+	Tags     []string               `json:"tags,omitempty"`
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
 }
 
@@ -1743,3 +6258,132 @@ type channelsPageRes struct {
 	Offset   uint64       `json:"offset"`
 	Limit    uint64       `json:"limit"`
 }
+
+type changeRes struct {
+	Field     string `json:"field"`
+	OldValue  string `json:"old_value"`
+	NewValue  string `json:"new_value"`
+	ChangedBy string `json:"changed_by"`
+	ChangedAt int64  `json:"changed_at"`
+}
+
+type historyRes struct {
+	Total   uint64      `json:"total"`
+	Offset  uint64      `json:"offset"`
+	Limit   uint64      `json:"limit"`
+	Changes []changeRes `json:"changes"`
+}
+
+type diffRes struct {
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+type auditEventRes struct {
+	Actor      string             `json:"actor"`
+	Operation  string             `json:"operation"`
+	Kind       string             `json:"kind"`
+	Entity     string             `json:"entity"`
+	Diff       map[string]diffRes `json:"diff"`
+	OccurredAt int64              `json:"occurred_at"`
+}
+
+type auditRes struct {
+	Total  uint64          `json:"total"`
+	Offset uint64          `json:"offset"`
+	Limit  uint64          `json:"limit"`
+	Events []auditEventRes `json:"events"`
+}
+
+type capabilitiesRes struct {
+	Kind       string   `json:"kind,omitempty"`
+	Entity     string   `json:"entity,omitempty"`
+	Operations []string `json:"operations"`
+}
+
+type groupRes struct {
+	ID       string                 `json:"id"`
+	Parent   string                 `json:"parent,omitempty"`
+	Name     string                 `json:"name,omitempty"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+type profileRes struct {
+	ID        string                 `json:"id"`
+	Name      string                 `json:"name,omitempty"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+	Channels  []string               `json:"channels"`
+	Bootstrap string                 `json:"bootstrap,omitempty"`
+}
+
+type thingKeyRes struct {
+	ID       string `json:"id"`
+	Key      string `json:"key"`
+	Scope    string `json:"scope"`
+	IssuedAt int64  `json:"issued_at"`
+}
+
+type channelTokenRes struct {
+	ID        string `json:"id"`
+	Token     string `json:"token"`
+	Scope     string `json:"scope"`
+	IssuedAt  int64  `json:"issued_at"`
+	ExpiresAt int64  `json:"expires_at"`
+}
+
+type channelTokensRes struct {
+	Tokens []channelTokenRes `json:"tokens"`
+}
+
+type subtopicRuleRes struct {
+	ID      string `json:"id"`
+	ThingID string `json:"thing_id"`
+	Action  string `json:"action"`
+	Pattern string `json:"pattern"`
+}
+
+type subtopicRulesRes struct {
+	Rules []subtopicRuleRes `json:"rules"`
+}
+
+type observedSubtopicRes struct {
+	Name       string `json:"name"`
+	Messages   uint64 `json:"messages"`
+	LastSeen   int64  `json:"last_seen"`
+	Registered bool   `json:"registered"`
+}
+
+type observedSubtopicsRes struct {
+	Subtopics []observedSubtopicRes `json:"subtopics"`
+}
+
+type snapshotThing struct {
+	ID           string                 `json:"id"`
+	Name         string                 `json:"name,omitempty"`
+	Key          string                 `json:"key,omitempty"`
+	KeyExpiresAt int64                  `json:"key_expires_at,omitempty"`
+	KeyRotation  int64                  `json:"key_rotation,omitempty"`
+	State        string                 `json:"state,omitempty"`
+	Tags         []string               `json:"tags,omitempty"`
+	Metadata     map[string]interface{} `json:"metadata,omitempty"`
+}
+
+type snapshotChannel struct {
+	ID       string                 `json:"id"`
+	Name     string                 `json:"name,omitempty"`
+	State    string                 `json:"state,omitempty"`
+	Tags     []string               `json:"tags,omitempty"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+type snapshotConnection struct {
+	ChanID  string `json:"channel"`
+	ThingID string `json:"thing"`
+	Access  string `json:"access,omitempty"`
+}
+
+type snapshotRes struct {
+	Things      []snapshotThing      `json:"things"`
+	Channels    []snapshotChannel    `json:"channels"`
+	Connections []snapshotConnection `json:"connections"`
+}
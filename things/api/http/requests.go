@@ -11,24 +11,191 @@ import "github.com/mainflux/mainflux/things"
 
 const maxLimitSize = 100
 const maxNameSize = 1024
+const maxReservationSize = 1000
+const maxBulkSize = 1000
 
 type apiReq interface {
 	validate() error
 }
 
+// selectable are the fields of the things and channels representations that
+// can be selected. The thing external ID, key and the time the thing was
+// last seen at are ignored when channels are retrieved.
+var selectable = map[string]bool{
+	"id":          true,
+	"external_id": true,
+	"name":        true,
+	"key":         true,
+	"state":       true,
+	"tags":        true,
+	"metadata":    true,
+	"last_seen":   true,
+}
+
+// fieldSet is the set of the fields to return. An empty set selects all of
+// them.
+type fieldSet map[string]bool
+
+func (fs fieldSet) has(field string) bool {
+	return len(fs) == 0 || fs[field]
+}
+
+func (fs fieldSet) validate() error {
+	for f := range fs {
+		if !selectable[f] {
+			return things.ErrMalformedEntity
+		}
+	}
+
+	return nil
+}
+
 type addThingReq struct {
-	token    string
+	token      string
+	ProfileID  string                 `json:"profile_id,omitempty"`
+	ExternalID string                 `json:"external_id,omitempty"`
+	Name       string                 `json:"name,omitempty"`
+	Key        string                 `json:"key,omitempty"`
+	Tags       []string               `json:"tags,omitempty"`
+	Metadata   map[string]interface{} `json:"metadata,omitempty"`
+}
+
+func (req addThingReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	return nil
+}
+
+type createThingsReq struct {
+	token  string
+	Things []addThingReq
+}
+
+func (req createThingsReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if len(req.Things) == 0 || len(req.Things) > maxBulkSize {
+		return things.ErrMalformedEntity
+	}
+
+	// Things are instantiated from the profiles one at a time.
+	for _, thing := range req.Things {
+		if thing.ProfileID != "" {
+			return things.ErrMalformedEntity
+		}
+	}
+
+	return nil
+}
+
+type reserveThingsReq struct {
+	token string
+	Count uint64 `json:"count"`
+}
+
+func (req reserveThingsReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if req.Count == 0 || req.Count > maxReservationSize {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type channelTemplateReq struct {
 	Name     string                 `json:"name,omitempty"`
-	Key      string                 `json:"key,omitempty"`
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
 }
 
-func (req addThingReq) validate() error {
+type saveTemplateReq struct {
+	token    string
+	Channels []channelTemplateReq `json:"channels"`
+}
+
+func (req saveTemplateReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if len(req.Channels) == 0 {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type templateReq struct {
+	token string
+}
+
+func (req templateReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	return nil
+}
+
+type archiveChannelsReq struct {
+	token    string
+	Selector map[string]interface{} `json:"selector"`
+	Tags     []string               `json:"tags"`
+	Match    string                 `json:"match"`
+}
+
+func (req archiveChannelsReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if req.Selector == nil && len(req.Tags) == 0 {
+		return things.ErrMalformedEntity
+	}
+
+	if req.Match != "" && req.Match != matchAll && req.Match != matchAny {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type updateThingsMetadataReq struct {
+	token    string
+	Selector map[string]interface{} `json:"selector"`
+	Patch    map[string]interface{} `json:"patch"`
+}
+
+func (req updateThingsMetadataReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if req.Selector == nil || len(req.Patch) == 0 {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type patchReq struct {
+	token string
+	id    string
+	patch map[string]interface{}
+}
+
+func (req patchReq) validate() error {
 	if req.token == "" {
 		return things.ErrUnauthorizedAccess
 	}
 
-	if len(req.Name) > maxNameSize {
+	if req.id == "" || len(req.patch) == 0 {
 		return things.ErrMalformedEntity
 	}
 
@@ -39,6 +206,7 @@ type updateThingReq struct {
 	token    string
 	id       string
 	Name     string                 `json:"name,omitempty"`
+	Tags     []string               `json:"tags,omitempty"`
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
 }
 
@@ -51,25 +219,60 @@ func (req updateThingReq) validate() error {
 		return things.ErrMalformedEntity
 	}
 
-	if len(req.Name) > maxNameSize {
+	return nil
+}
+
+// updateKeyReq holds the key expiration time as Unix timestamp and the key
+// rotation period in seconds.
+type updateKeyReq struct {
+	token     string
+	id        string
+	Key       string `json:"key"`
+	ExpiresAt int64  `json:"expires_at,omitempty"`
+	Rotation  int64  `json:"rotation,omitempty"`
+}
+
+func (req updateKeyReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" || req.Key == "" || req.ExpiresAt < 0 || req.Rotation < 0 {
 		return things.ErrMalformedEntity
 	}
 
 	return nil
 }
 
-type updateKeyReq struct {
+type issueReq struct {
 	token string
 	id    string
-	Key   string `json:"key"`
+	TTL   uint64 `json:"ttl"`
 }
 
-func (req updateKeyReq) validate() error {
+func (req issueReq) validate() error {
 	if req.token == "" {
 		return things.ErrUnauthorizedAccess
 	}
 
-	if req.id == "" || req.Key == "" {
+	if req.id == "" || req.TTL == 0 {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type openSessionReq struct {
+	key string
+	TTL uint64 `json:"ttl"`
+}
+
+func (req openSessionReq) validate() error {
+	if req.key == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if req.TTL == 0 {
 		return things.ErrMalformedEntity
 	}
 
@@ -79,6 +282,7 @@ func (req updateKeyReq) validate() error {
 type createChannelReq struct {
 	token    string
 	Name     string                 `json:"name,omitempty"`
+	Tags     []string               `json:"tags,omitempty"`
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
 }
 
@@ -87,10 +291,6 @@ func (req createChannelReq) validate() error {
 		return things.ErrUnauthorizedAccess
 	}
 
-	if len(req.Name) > maxNameSize {
-		return things.ErrMalformedEntity
-	}
-
 	return nil
 }
 
@@ -98,6 +298,7 @@ type updateChannelReq struct {
 	token    string
 	id       string
 	Name     string                 `json:"name,omitempty"`
+	Tags     []string               `json:"tags,omitempty"`
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
 }
 
@@ -110,16 +311,13 @@ func (req updateChannelReq) validate() error {
 		return things.ErrMalformedEntity
 	}
 
-	if len(req.Name) > maxNameSize {
-		return things.ErrMalformedEntity
-	}
-
 	return nil
 }
 
 type viewResourceReq struct {
-	token string
-	id    string
+	token  string
+	id     string
+	fields fieldSet
 }
 
 func (req viewResourceReq) validate() error {
@@ -131,14 +329,19 @@ func (req viewResourceReq) validate() error {
 		return things.ErrMalformedEntity
 	}
 
-	return nil
+	return req.fields.validate()
 }
 
 type listResourcesReq struct {
-	token  string
-	offset uint64
-	limit  uint64
-	name   string
+	token    string
+	offset   uint64
+	limit    uint64
+	name     string
+	metadata map[string]interface{}
+	tags     things.TagFilter
+	status   things.Status
+	cursor   string
+	fields   fieldSet
 }
 
 func (req *listResourcesReq) validate() error {
@@ -154,6 +357,42 @@ func (req *listResourcesReq) validate() error {
 		return things.ErrMalformedEntity
 	}
 
+	if req.cursor != "" && req.offset != 0 {
+		return things.ErrMalformedEntity
+	}
+
+	switch req.status {
+	case "", things.Online, things.Stale:
+	default:
+		return things.ErrMalformedEntity
+	}
+
+	return req.fields.validate()
+}
+
+type countResourcesReq struct {
+	token    string
+	name     string
+	metadata map[string]interface{}
+	tags     things.TagFilter
+	status   things.Status
+}
+
+func (req countResourcesReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if len(req.name) > maxNameSize {
+		return things.ErrMalformedEntity
+	}
+
+	switch req.status {
+	case "", things.Online, things.Stale:
+	default:
+		return things.ErrMalformedEntity
+	}
+
 	return nil
 }
 
@@ -162,6 +401,7 @@ type listByConnectionReq struct {
 	id     string
 	offset uint64
 	limit  uint64
+	fields fieldSet
 }
 
 func (req listByConnectionReq) validate() error {
@@ -177,13 +417,14 @@ func (req listByConnectionReq) validate() error {
 		return things.ErrMalformedEntity
 	}
 
-	return nil
+	return req.fields.validate()
 }
 
 type connectionReq struct {
 	token   string
 	chanID  string
 	thingID string
+	access  things.ConnectionAccess
 }
 
 func (req connectionReq) validate() error {
@@ -191,9 +432,535 @@ func (req connectionReq) validate() error {
 		return things.ErrUnauthorizedAccess
 	}
 
-	if req.chanID == "" || req.thingID == "" {
+	if req.chanID == "" || req.thingID == "" || !req.access.Valid() {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type auditReq struct {
+	token     string
+	actor     string
+	operation string
+	kind      string
+	entity    string
+	from      uint64
+	to        uint64
+	offset    uint64
+	limit     uint64
+}
+
+func (req auditReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if req.limit == 0 || req.limit > maxLimitSize {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type capabilitiesReq struct {
+	token  string
+	kind   string
+	entity string
+}
+
+func (req capabilitiesReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if (req.kind == "") != (req.entity == "") {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type connectionsReq struct {
+	token    string
+	ChanIDs  []string                `json:"channels"`
+	ThingIDs []string                `json:"things"`
+	Access   things.ConnectionAccess `json:"access,omitempty"`
+}
+
+func (req connectionsReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if len(req.ChanIDs) == 0 || len(req.ThingIDs) == 0 || !req.Access.Valid() {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type createGroupReq struct {
+	token    string
+	Parent   string                 `json:"parent,omitempty"`
+	Name     string                 `json:"name,omitempty"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+func (req createGroupReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	return nil
+}
+
+type updateGroupReq struct {
+	token    string
+	id       string
+	Name     string                 `json:"name,omitempty"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+func (req updateGroupReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type listGroupsReq struct {
+	token  string
+	parent string
+	offset uint64
+	limit  uint64
+}
+
+func (req listGroupsReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if req.limit == 0 || req.limit > maxLimitSize {
 		return things.ErrMalformedEntity
 	}
 
 	return nil
 }
+
+type assignThingsReq struct {
+	token  string
+	id     string
+	Things []string `json:"things"`
+}
+
+func (req assignThingsReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" || len(req.Things) == 0 {
+		return things.ErrMalformedEntity
+	}
+
+	for _, id := range req.Things {
+		if id == "" {
+			return things.ErrMalformedEntity
+		}
+	}
+
+	return nil
+}
+
+type groupThingReq struct {
+	token   string
+	id      string
+	thingID string
+}
+
+func (req groupThingReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" || req.thingID == "" {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type groupConnectionReq struct {
+	token   string
+	chanID  string
+	groupID string
+}
+
+func (req groupConnectionReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if req.chanID == "" || req.groupID == "" {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type registerSubtopicReq struct {
+	token       string
+	chanID      string
+	name        string
+	Description string                 `json:"description,omitempty"`
+	Schema      map[string]interface{} `json:"schema,omitempty"`
+}
+
+func (req registerSubtopicReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if req.chanID == "" || req.name == "" {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type subtopicReq struct {
+	token  string
+	chanID string
+	name   string
+}
+
+func (req subtopicReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if req.chanID == "" || req.name == "" {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type shareReq struct {
+	token      string
+	id         string
+	user       string
+	Permission string `json:"permission"`
+}
+
+func (req shareReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" || req.user == "" || req.Permission == "" {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type unshareReq struct {
+	token string
+	id    string
+	user  string
+}
+
+func (req unshareReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" || req.user == "" {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type transferReq struct {
+	token           string
+	id              string
+	Owner           string `json:"owner"`
+	KeepConnections bool   `json:"keep_connections"`
+}
+
+func (req transferReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" || req.Owner == "" {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type issueThingKeyReq struct {
+	token string
+	id    string
+	Scope string `json:"scope"`
+}
+
+func (req issueThingKeyReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" || !things.KeyScope(req.Scope).Valid() {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type thingKeyReq struct {
+	token string
+	id    string
+	keyID string
+}
+
+func (req thingKeyReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" || req.keyID == "" {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type issueChannelTokenReq struct {
+	token string
+	id    string
+	Scope string `json:"scope"`
+	TTL   uint64 `json:"ttl"`
+}
+
+func (req issueChannelTokenReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" || req.TTL == 0 || !things.KeyScope(req.Scope).Valid() {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type channelTokenReq struct {
+	token   string
+	id      string
+	tokenID string
+}
+
+func (req channelTokenReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" || req.tokenID == "" {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type addSubtopicRuleReq struct {
+	token   string
+	chanID  string
+	ThingID string `json:"thing_id"`
+	Action  string `json:"action"`
+	Pattern string `json:"pattern"`
+}
+
+func (req addSubtopicRuleReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if req.chanID == "" {
+		return things.ErrMalformedEntity
+	}
+
+	return req.rule().Validate()
+}
+
+func (req addSubtopicRuleReq) rule() things.SubtopicRule {
+	return things.SubtopicRule{
+		ThingID: req.ThingID,
+		Action:  req.Action,
+		Pattern: req.Pattern,
+	}
+}
+
+type subtopicRuleReq struct {
+	token  string
+	chanID string
+	ruleID string
+}
+
+func (req subtopicRuleReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if req.chanID == "" || req.ruleID == "" {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type usageReq struct {
+	token string
+	id    string
+	from  uint64
+	to    uint64
+}
+
+func (req usageReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type listUnhealthyReq struct {
+	token      string
+	thresholds things.HealthThresholds
+	offset     uint64
+	limit      uint64
+}
+
+func (req listUnhealthyReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if req.limit == 0 || req.limit > maxLimitSize {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type profileReq struct {
+	token     string
+	id        string
+	Name      string                 `json:"name,omitempty"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+	Channels  []string               `json:"channels,omitempty"`
+	Bootstrap string                 `json:"bootstrap,omitempty"`
+}
+
+func (req profileReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	return nil
+}
+
+type listProfilesReq struct {
+	token  string
+	offset uint64
+	limit  uint64
+}
+
+func (req listProfilesReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if req.limit == 0 || req.limit > maxLimitSize {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type exportReq struct {
+	token string
+	csv   bool
+}
+
+func (req exportReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	return nil
+}
+
+type snapshotThing struct {
+	ID           string                 `json:"id"`
+	ExternalID   string                 `json:"external_id,omitempty"`
+	Name         string                 `json:"name,omitempty"`
+	Key          string                 `json:"key,omitempty"`
+	KeyExpiresAt int64                  `json:"key_expires_at,omitempty"`
+	KeyRotation  int64                  `json:"key_rotation,omitempty"`
+	State        string                 `json:"state,omitempty"`
+	Tags         []string               `json:"tags,omitempty"`
+	Metadata     map[string]interface{} `json:"metadata,omitempty"`
+}
+
+type snapshotChannel struct {
+	ID       string                 `json:"id"`
+	Name     string                 `json:"name,omitempty"`
+	State    string                 `json:"state,omitempty"`
+	Tags     []string               `json:"tags,omitempty"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+type snapshotConnection struct {
+	ChanID  string `json:"channel"`
+	ThingID string `json:"thing"`
+	Access  string `json:"access,omitempty"`
+}
+
+type importReq struct {
+	token       string
+	preserve    bool
+	csv         bool
+	Things      []snapshotThing      `json:"things"`
+	Channels    []snapshotChannel    `json:"channels"`
+	Connections []snapshotConnection `json:"connections"`
+}
+
+func (req importReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if len(req.Things) == 0 && len(req.Channels) == 0 {
+		return things.ErrMalformedEntity
+	}
+
+	for _, th := range req.Things {
+		if th.KeyExpiresAt < 0 || th.KeyRotation < 0 {
+			return things.ErrMalformedEntity
+		}
+	}
+
+	return nil
+}
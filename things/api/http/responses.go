@@ -19,12 +19,34 @@ var (
 	_ mainflux.Response = (*removeRes)(nil)
 	_ mainflux.Response = (*thingRes)(nil)
 	_ mainflux.Response = (*viewThingRes)(nil)
+	_ mainflux.Response = (*topologyRes)(nil)
+	_ mainflux.Response = (*saveTemplateRes)(nil)
+	_ mainflux.Response = (*templateRes)(nil)
+	_ mainflux.Response = (*reservationsRes)(nil)
+	_ mainflux.Response = (*updateMetadataRes)(nil)
+	_ mainflux.Response = (*onboardingRes)(nil)
+	_ mainflux.Response = (*signedKeyRes)(nil)
+	_ mainflux.Response = (*invitationRes)(nil)
+	_ mainflux.Response = (*channelTokenRes)(nil)
+	_ mainflux.Response = (*channelTokensRes)(nil)
+	_ mainflux.Response = (*usageRes)(nil)
+	_ mainflux.Response = (*historyRes)(nil)
+	_ mainflux.Response = (*auditRes)(nil)
 	_ mainflux.Response = (*thingsPageRes)(nil)
 	_ mainflux.Response = (*channelRes)(nil)
 	_ mainflux.Response = (*viewChannelRes)(nil)
 	_ mainflux.Response = (*channelsPageRes)(nil)
 	_ mainflux.Response = (*connectionRes)(nil)
 	_ mainflux.Response = (*disconnectionRes)(nil)
+	_ mainflux.Response = (*groupRes)(nil)
+	_ mainflux.Response = (*viewGroupRes)(nil)
+	_ mainflux.Response = (*groupsPageRes)(nil)
+	_ mainflux.Response = (*groupConnectionRes)(nil)
+	_ mainflux.Response = (*profileRes)(nil)
+	_ mainflux.Response = (*viewProfileRes)(nil)
+	_ mainflux.Response = (*profilesPageRes)(nil)
+	_ mainflux.Response = (*snapshotRes)(nil)
+	_ mainflux.Response = (*documentRes)(nil)
 )
 
 type identityRes struct {
@@ -45,6 +67,22 @@ func (res identityRes) Empty() bool {
 	return true
 }
 
+// errorRes describes the entity field that failed the validation.
+type errorRes struct {
+	Field string `json:"field"`
+	Error string `json:"error"`
+}
+
+type connectionErrorRes struct {
+	Failures []connectionFailureRes `json:"failures"`
+}
+
+type connectionFailureRes struct {
+	ChanID  string `json:"channel"`
+	ThingID string `json:"thing"`
+	Error   string `json:"error"`
+}
+
 type removeRes struct{}
 
 func (res removeRes) Code() int {
@@ -87,11 +125,17 @@ func (res thingRes) Empty() bool {
 }
 
 type viewThingRes struct {
-	ID       string                 `json:"id"`
-	Owner    string                 `json:"-"`
-	Name     string                 `json:"name,omitempty"`
-	Key      string                 `json:"key"`
-	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	ID           string                 `json:"id"`
+	Owner        string                 `json:"-"`
+	ExternalID   string                 `json:"external_id,omitempty"`
+	Name         string                 `json:"name,omitempty"`
+	Key          string                 `json:"key,omitempty"`
+	KeyExpiresAt int64                  `json:"key_expires_at,omitempty"`
+	KeyRotation  int64                  `json:"key_rotation,omitempty"`
+	State        string                 `json:"state,omitempty"`
+	Tags         []string               `json:"tags,omitempty"`
+	Metadata     map[string]interface{} `json:"metadata,omitempty"`
+	LastSeen     int64                  `json:"last_seen,omitempty"`
 }
 
 func (res viewThingRes) Code() int {
@@ -106,6 +150,188 @@ func (res viewThingRes) Empty() bool {
 	return false
 }
 
+type topologyRes struct {
+	Thing    viewThingRes     `json:"thing"`
+	Channels []viewChannelRes `json:"channels"`
+}
+
+func (res topologyRes) Code() int {
+	return http.StatusCreated
+}
+
+func (res topologyRes) Headers() map[string]string {
+	return map[string]string{
+		"Location": fmt.Sprintf("/things/%s", res.Thing.ID),
+	}
+}
+
+func (res topologyRes) Empty() bool {
+	return false
+}
+
+type saveTemplateRes struct{}
+
+func (res saveTemplateRes) Code() int {
+	return http.StatusOK
+}
+
+func (res saveTemplateRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res saveTemplateRes) Empty() bool {
+	return true
+}
+
+type channelTemplateRes struct {
+	Name     string                 `json:"name,omitempty"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+type templateRes struct {
+	Channels []channelTemplateRes `json:"channels"`
+}
+
+func (res templateRes) Code() int {
+	return http.StatusOK
+}
+
+func (res templateRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res templateRes) Empty() bool {
+	return false
+}
+
+type reservationRes struct {
+	ID  string `json:"id"`
+	Key string `json:"key"`
+}
+
+type createThingsRes struct {
+	Things []viewThingRes `json:"things"`
+}
+
+func (res createThingsRes) Code() int {
+	return http.StatusCreated
+}
+
+func (res createThingsRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res createThingsRes) Empty() bool {
+	return false
+}
+
+type reservationsRes struct {
+	Reservations []reservationRes `json:"reservations"`
+}
+
+func (res reservationsRes) Code() int {
+	return http.StatusCreated
+}
+
+func (res reservationsRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res reservationsRes) Empty() bool {
+	return false
+}
+
+type archiveChannelsRes struct {
+	Archived int `json:"archived"`
+}
+
+func (res archiveChannelsRes) Code() int {
+	return http.StatusOK
+}
+
+func (res archiveChannelsRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res archiveChannelsRes) Empty() bool {
+	return false
+}
+
+type updateMetadataRes struct {
+	Updated int `json:"updated"`
+}
+
+func (res updateMetadataRes) Code() int {
+	return http.StatusOK
+}
+
+func (res updateMetadataRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res updateMetadataRes) Empty() bool {
+	return false
+}
+
+type onboardingRes struct {
+	ThingID   string            `json:"thing_id"`
+	ThingKey  string            `json:"thing_key"`
+	Channels  []string          `json:"channels"`
+	Endpoints map[string]string `json:"endpoints"`
+	ExpiresAt int64             `json:"expires_at"`
+	Encoded   string            `json:"code"`
+}
+
+func (res onboardingRes) Code() int {
+	return http.StatusOK
+}
+
+func (res onboardingRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res onboardingRes) Empty() bool {
+	return false
+}
+
+type signedKeyRes struct {
+	ThingID   string   `json:"thing_id"`
+	Key       string   `json:"key"`
+	Channels  []string `json:"channels"`
+	ExpiresAt int64    `json:"expires_at"`
+}
+
+func (res signedKeyRes) Code() int {
+	return http.StatusCreated
+}
+
+func (res signedKeyRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res signedKeyRes) Empty() bool {
+	return false
+}
+
+type invitationRes struct {
+	ID         string `json:"id"`
+	ChannelID  string `json:"channel_id"`
+	Invitation string `json:"invitation"`
+	ExpiresAt  int64  `json:"expires_at"`
+}
+
+func (res invitationRes) Code() int {
+	return http.StatusCreated
+}
+
+func (res invitationRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res invitationRes) Empty() bool {
+	return false
+}
+
 type thingsPageRes struct {
 	pageRes
 	Things []viewThingRes `json:"things"`
@@ -154,6 +380,8 @@ type viewChannelRes struct {
 	ID       string                 `json:"id"`
 	Owner    string                 `json:"-"`
 	Name     string                 `json:"name,omitempty"`
+	State    string                 `json:"state,omitempty"`
+	Tags     []string               `json:"tags,omitempty"`
 	Things   []viewThingRes         `json:"connected,omitempty"`
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
 }
@@ -215,8 +443,564 @@ func (res disconnectionRes) Empty() bool {
 	return true
 }
 
+type groupRes struct {
+	id      string
+	created bool
+}
+
+func (res groupRes) Code() int {
+	if res.created {
+		return http.StatusCreated
+	}
+
+	return http.StatusOK
+}
+
+func (res groupRes) Headers() map[string]string {
+	if res.created {
+		return map[string]string{
+			"Location": fmt.Sprintf("/groups/%s", res.id),
+		}
+	}
+
+	return map[string]string{}
+}
+
+func (res groupRes) Empty() bool {
+	return true
+}
+
+type viewGroupRes struct {
+	ID       string                 `json:"id"`
+	Parent   string                 `json:"parent,omitempty"`
+	Name     string                 `json:"name,omitempty"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+func (res viewGroupRes) Code() int {
+	return http.StatusOK
+}
+
+func (res viewGroupRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res viewGroupRes) Empty() bool {
+	return false
+}
+
+type groupsPageRes struct {
+	pageRes
+	Groups []viewGroupRes `json:"groups"`
+}
+
+func (res groupsPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res groupsPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res groupsPageRes) Empty() bool {
+	return false
+}
+
+// groupConnectionRes lists the things connected to the channel along with
+// the group, omitting the ones that were connected already.
+type groupConnectionRes struct {
+	Things []string `json:"connected"`
+}
+
+func (res groupConnectionRes) Code() int {
+	return http.StatusOK
+}
+
+func (res groupConnectionRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res groupConnectionRes) Empty() bool {
+	return false
+}
+
 type pageRes struct {
 	Total  uint64 `json:"total"`
 	Offset uint64 `json:"offset"`
 	Limit  uint64 `json:"limit"`
+	Cursor string `json:"cursor,omitempty"`
+}
+
+type countRes struct {
+	Total uint64 `json:"total"`
+}
+
+func (res countRes) Code() int {
+	return http.StatusOK
+}
+
+func (res countRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res countRes) Empty() bool {
+	return false
+}
+
+type usageRes struct {
+	From     int64  `json:"from"`
+	To       int64  `json:"to"`
+	Messages uint64 `json:"messages"`
+	Bytes    uint64 `json:"bytes"`
+}
+
+func (res usageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res usageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res usageRes) Empty() bool {
+	return false
+}
+
+type healthRes struct {
+	ThingID  string   `json:"thing_id"`
+	Battery  *float64 `json:"battery,omitempty"`
+	RSSI     *float64 `json:"rssi,omitempty"`
+	Firmware string   `json:"firmware,omitempty"`
+	Reported int64    `json:"reported"`
+}
+
+func (res healthRes) Code() int {
+	return http.StatusOK
+}
+
+func (res healthRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res healthRes) Empty() bool {
+	return false
+}
+
+type healthPageRes struct {
+	pageRes
+	Things []healthRes `json:"things"`
+}
+
+func (res healthPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res healthPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res healthPageRes) Empty() bool {
+	return false
+}
+
+type thingKeyRes struct {
+	ID       string `json:"id"`
+	Key      string `json:"key"`
+	Scope    string `json:"scope"`
+	IssuedAt int64  `json:"issued_at"`
+	created  bool
+}
+
+func (res thingKeyRes) Code() int {
+	if res.created {
+		return http.StatusCreated
+	}
+
+	return http.StatusOK
+}
+
+func (res thingKeyRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res thingKeyRes) Empty() bool {
+	return false
+}
+
+type thingKeysRes struct {
+	Keys []thingKeyRes `json:"keys"`
+}
+
+func (res thingKeysRes) Code() int {
+	return http.StatusOK
+}
+
+func (res thingKeysRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res thingKeysRes) Empty() bool {
+	return false
+}
+
+type channelTokenRes struct {
+	ID        string `json:"id"`
+	Token     string `json:"token"`
+	Scope     string `json:"scope"`
+	IssuedAt  int64  `json:"issued_at"`
+	ExpiresAt int64  `json:"expires_at"`
+	created   bool
+}
+
+func (res channelTokenRes) Code() int {
+	if res.created {
+		return http.StatusCreated
+	}
+
+	return http.StatusOK
+}
+
+func (res channelTokenRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res channelTokenRes) Empty() bool {
+	return false
+}
+
+type channelTokensRes struct {
+	Tokens []channelTokenRes `json:"tokens"`
+}
+
+func (res channelTokensRes) Code() int {
+	return http.StatusOK
+}
+
+func (res channelTokensRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res channelTokensRes) Empty() bool {
+	return false
+}
+
+type subtopicRuleRes struct {
+	ID      string `json:"id"`
+	ThingID string `json:"thing_id"`
+	Action  string `json:"action"`
+	Pattern string `json:"pattern"`
+	created bool
+}
+
+func (res subtopicRuleRes) Code() int {
+	if res.created {
+		return http.StatusCreated
+	}
+
+	return http.StatusOK
+}
+
+func (res subtopicRuleRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res subtopicRuleRes) Empty() bool {
+	return false
+}
+
+type subtopicRulesRes struct {
+	Rules []subtopicRuleRes `json:"rules"`
+}
+
+func (res subtopicRulesRes) Code() int {
+	return http.StatusOK
+}
+
+func (res subtopicRulesRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res subtopicRulesRes) Empty() bool {
+	return false
+}
+
+type registerSubtopicRes struct{}
+
+func (res registerSubtopicRes) Code() int {
+	return http.StatusOK
+}
+
+func (res registerSubtopicRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res registerSubtopicRes) Empty() bool {
+	return true
+}
+
+type subtopicRes struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	Schema      map[string]interface{} `json:"schema,omitempty"`
+}
+
+type subtopicsRes struct {
+	Subtopics []subtopicRes `json:"subtopics"`
+}
+
+func (res subtopicsRes) Code() int {
+	return http.StatusOK
+}
+
+func (res subtopicsRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res subtopicsRes) Empty() bool {
+	return false
+}
+
+type shareRes struct{}
+
+func (res shareRes) Code() int {
+	return http.StatusOK
+}
+
+func (res shareRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res shareRes) Empty() bool {
+	return true
+}
+
+type sharedWithRes struct {
+	User       string `json:"user"`
+	Permission string `json:"permission"`
+}
+
+type sharesRes struct {
+	Shares []sharedWithRes `json:"shares"`
+}
+
+func (res sharesRes) Code() int {
+	return http.StatusOK
+}
+
+func (res sharesRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res sharesRes) Empty() bool {
+	return false
+}
+
+type observedSubtopicRes struct {
+	Name       string `json:"name"`
+	Messages   uint64 `json:"messages"`
+	LastSeen   int64  `json:"last_seen"`
+	Registered bool   `json:"registered"`
+}
+
+type observedSubtopicsRes struct {
+	Subtopics []observedSubtopicRes `json:"subtopics"`
+}
+
+func (res observedSubtopicsRes) Code() int {
+	return http.StatusOK
+}
+
+func (res observedSubtopicsRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res observedSubtopicsRes) Empty() bool {
+	return false
+}
+
+type changeRes struct {
+	Field     string `json:"field"`
+	OldValue  string `json:"old_value,omitempty"`
+	NewValue  string `json:"new_value,omitempty"`
+	ChangedBy string `json:"changed_by"`
+	ChangedAt int64  `json:"changed_at"`
+}
+
+type historyRes struct {
+	pageRes
+	Changes []changeRes `json:"changes"`
+}
+
+func (res historyRes) Code() int {
+	return http.StatusOK
+}
+
+func (res historyRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res historyRes) Empty() bool {
+	return false
+}
+
+type diffRes struct {
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+}
+
+type auditEventRes struct {
+	Actor      string             `json:"actor"`
+	Operation  string             `json:"operation"`
+	Kind       string             `json:"kind"`
+	Entity     string             `json:"entity"`
+	Diff       map[string]diffRes `json:"diff"`
+	OccurredAt int64              `json:"occurred_at"`
+}
+
+type auditRes struct {
+	pageRes
+	Events []auditEventRes `json:"events"`
+}
+
+func (res auditRes) Code() int {
+	return http.StatusOK
+}
+
+func (res auditRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res auditRes) Empty() bool {
+	return false
+}
+
+type capabilitiesRes struct {
+	Kind       string   `json:"kind,omitempty"`
+	Entity     string   `json:"entity,omitempty"`
+	Operations []string `json:"operations"`
+}
+
+func (res capabilitiesRes) Code() int {
+	return http.StatusOK
+}
+
+func (res capabilitiesRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res capabilitiesRes) Empty() bool {
+	return false
+}
+
+type profileRes struct {
+	id      string
+	created bool
+}
+
+func (res profileRes) Code() int {
+	if res.created {
+		return http.StatusCreated
+	}
+
+	return http.StatusOK
+}
+
+func (res profileRes) Headers() map[string]string {
+	if res.created {
+		return map[string]string{
+			"Location": fmt.Sprintf("/profiles/%s", res.id),
+		}
+	}
+
+	return map[string]string{}
+}
+
+func (res profileRes) Empty() bool {
+	return true
+}
+
+type viewProfileRes struct {
+	ID        string                 `json:"id"`
+	Name      string                 `json:"name,omitempty"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+	Channels  []string               `json:"channels"`
+	Bootstrap string                 `json:"bootstrap,omitempty"`
+}
+
+func (res viewProfileRes) Code() int {
+	return http.StatusOK
+}
+
+func (res viewProfileRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res viewProfileRes) Empty() bool {
+	return false
+}
+
+type profilesPageRes struct {
+	pageRes
+	Profiles []viewProfileRes `json:"profiles"`
+}
+
+func (res profilesPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res profilesPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res profilesPageRes) Empty() bool {
+	return false
+}
+
+type snapshotRes struct {
+	created     bool
+	Things      []snapshotThing      `json:"things"`
+	Channels    []snapshotChannel    `json:"channels"`
+	Connections []snapshotConnection `json:"connections"`
+}
+
+func (res snapshotRes) Code() int {
+	if res.created {
+		return http.StatusCreated
+	}
+
+	return http.StatusOK
+}
+
+func (res snapshotRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res snapshotRes) Empty() bool {
+	return false
+}
+
+type documentRes struct {
+	name        string
+	contentType string
+	created     bool
+	content     []byte
+}
+
+func (res documentRes) Code() int {
+	if res.created {
+		return http.StatusCreated
+	}
+
+	return http.StatusOK
+}
+
+func (res documentRes) Headers() map[string]string {
+	return map[string]string{
+		"Content-Type":        res.contentType,
+		"Content-Disposition": fmt.Sprintf("attachment; filename=%q", res.name),
+	}
+}
+
+func (res documentRes) Empty() bool {
+	return false
 }
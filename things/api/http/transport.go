@@ -9,6 +9,7 @@ package http
 
 import (
 	"context"
+	"encoding/csv"
 	"encoding/json"
 	"errors"
 	"io"
@@ -19,20 +20,50 @@ import (
 	kithttp "github.com/go-kit/kit/transport/http"
 	"github.com/go-zoo/bone"
 	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/identifiers"
 	"github.com/mainflux/mainflux/things"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 const (
-	contentType = "application/json"
-	offset      = "offset"
-	limit       = "limit"
-	name        = "name"
+	contentType    = "application/json"
+	mergePatchType = "application/merge-patch+json"
+	csvContentType = "text/csv"
+	offset         = "offset"
+	limit          = "limit"
+	name           = "name"
+	metadata       = "metadata"
+	tags           = "tags"
+	match          = "match"
+	cursor         = "cursor"
+	status         = "status"
+	fields         = "fields"
+	from           = "from"
+	to             = "to"
+	parent         = "parent"
+	actor          = "actor"
+	operation      = "operation"
+	kind           = "kind"
+	entity         = "entity"
+	access         = "access"
+	preserve       = "preserve"
+	battery        = "battery"
+	rssi           = "rssi"
+
+	matchAll = "all"
+	matchAny = "any"
 
 	defOffset = 0
 	defLimit  = 10
+
+	thingRow      = "thing"
+	channelRow    = "channel"
+	connectionRow = "connection"
 )
 
+// snapshotColumns are the columns of the snapshot's CSV representation.
+var snapshotColumns = []string{"kind", "id", "name", "key", "key_expires_at", "key_rotation", "state", "tags", "metadata", "channel", "thing", "access"}
+
 var (
 	errUnsupportedContentType = errors.New("unsupported content type")
 	errInvalidQueryParams     = errors.New("invalid query params")
@@ -53,6 +84,48 @@ func MakeHandler(svc things.Service) http.Handler {
 		opts...,
 	))
 
+	r.Post("/things/bulk", kithttp.NewServer(
+		createThingsEndpoint(svc),
+		decodeThingsCreation,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Post("/things/reservations", kithttp.NewServer(
+		reserveThingsEndpoint(svc),
+		decodeReservation,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Put("/things/template", kithttp.NewServer(
+		saveTemplateEndpoint(svc),
+		decodeTemplate,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/things/template", kithttp.NewServer(
+		viewTemplateEndpoint(svc),
+		decodeTemplateView,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Delete("/things/template", kithttp.NewServer(
+		removeTemplateEndpoint(svc),
+		decodeTemplateView,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Patch("/things/metadata", kithttp.NewServer(
+		updateThingsMetadataEndpoint(svc),
+		decodeThingsMetadataUpdate,
+		encodeResponse,
+		opts...,
+	))
+
 	r.Patch("/things/:id/key", kithttp.NewServer(
 		updateKeyEndpoint(svc),
 		decodeKeyUpdate,
@@ -60,125 +133,1400 @@ func MakeHandler(svc things.Service) http.Handler {
 		opts...,
 	))
 
-	r.Put("/things/:id", kithttp.NewServer(
-		updateThingEndpoint(svc),
-		decodeThingUpdate,
-		encodeResponse,
-		opts...,
-	))
+	r.Put("/things/:id", kithttp.NewServer(
+		updateThingEndpoint(svc),
+		decodeThingUpdate,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Patch("/things/:id", kithttp.NewServer(
+		patchThingEndpoint(svc),
+		decodePatch,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Delete("/things/:id", kithttp.NewServer(
+		removeThingEndpoint(svc),
+		decodeView,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/things/count", kithttp.NewServer(
+		countThingsEndpoint(svc),
+		decodeCount,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/things/unhealthy", kithttp.NewServer(
+		listUnhealthyEndpoint(svc),
+		decodeListUnhealthy,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/things/:id", kithttp.NewServer(
+		viewThingEndpoint(svc),
+		decodeView,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/things/:id/channels", kithttp.NewServer(
+		listChannelsByThingEndpoint(svc),
+		decodeListByConnection,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/things/:id/onboarding", kithttp.NewServer(
+		onboardThingEndpoint(svc),
+		decodeView,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Post("/things/:id/keys", kithttp.NewServer(
+		issueKeyEndpoint(svc),
+		decodeIssue,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Post("/things/sessions", kithttp.NewServer(
+		openSessionEndpoint(svc),
+		decodeOpenSession,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Post("/things/:id/enable", kithttp.NewServer(
+		enableThingEndpoint(svc),
+		decodeView,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Post("/things/:id/disable", kithttp.NewServer(
+		disableThingEndpoint(svc),
+		decodeView,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Post("/things/:id/transfer", kithttp.NewServer(
+		transferThingEndpoint(svc),
+		decodeTransfer,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Delete("/things/:id/keys", kithttp.NewServer(
+		revokeKeysEndpoint(svc),
+		decodeView,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Post("/things/:id/scoped-keys", kithttp.NewServer(
+		issueThingKeyEndpoint(svc),
+		decodeThingKeyIssuance,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/things/:id/scoped-keys", kithttp.NewServer(
+		listThingKeysEndpoint(svc),
+		decodeView,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Delete("/things/:id/scoped-keys/:keyId", kithttp.NewServer(
+		revokeThingKeyEndpoint(svc),
+		decodeThingKey,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/things/:id/usage", kithttp.NewServer(
+		thingUsageEndpoint(svc),
+		decodeUsage,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/things/:id/health", kithttp.NewServer(
+		viewHealthEndpoint(svc),
+		decodeView,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/things/:id/history", kithttp.NewServer(
+		thingHistoryEndpoint(svc),
+		decodeListByConnection,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/things/:id/shares", kithttp.NewServer(
+		listSharesEndpoint(svc, things.ThingKind),
+		decodeView,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Put("/things/:id/shares/:user", kithttp.NewServer(
+		shareEndpoint(svc, things.ThingKind),
+		decodeShare,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Delete("/things/:id/shares/:user", kithttp.NewServer(
+		unshareEndpoint(svc, things.ThingKind),
+		decodeUnshare,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/things", kithttp.NewServer(
+		listThingsEndpoint(svc),
+		decodeList,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Post("/channels", kithttp.NewServer(
+		createChannelEndpoint(svc),
+		decodeChannelCreation,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Post("/channels/archive", kithttp.NewServer(
+		archiveChannelsEndpoint(svc),
+		decodeArchiveChannels,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Post("/channels/:id/archive", kithttp.NewServer(
+		archiveChannelEndpoint(svc),
+		decodeView,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Post("/channels/:id/unarchive", kithttp.NewServer(
+		unarchiveChannelEndpoint(svc),
+		decodeView,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Post("/channels/:id/transfer", kithttp.NewServer(
+		transferChannelEndpoint(svc),
+		decodeTransfer,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Put("/channels/:id", kithttp.NewServer(
+		updateChannelEndpoint(svc),
+		decodeChannelUpdate,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Patch("/channels/:id", kithttp.NewServer(
+		patchChannelEndpoint(svc),
+		decodePatch,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Delete("/channels/:id", kithttp.NewServer(
+		removeChannelEndpoint(svc),
+		decodeView,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/channels/count", kithttp.NewServer(
+		countChannelsEndpoint(svc),
+		decodeCount,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/channels/:id", kithttp.NewServer(
+		viewChannelEndpoint(svc),
+		decodeView,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/channels/:id/things", kithttp.NewServer(
+		listThingsByChannelEndpoint(svc),
+		decodeListByConnection,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/channels/:id/usage", kithttp.NewServer(
+		channelUsageEndpoint(svc),
+		decodeUsage,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/channels/:id/subtopics/observed", kithttp.NewServer(
+		observedSubtopicsEndpoint(svc),
+		decodeView,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/channels/:id/subtopics", kithttp.NewServer(
+		listSubtopicsEndpoint(svc),
+		decodeView,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Put("/channels/:id/subtopics/:name", kithttp.NewServer(
+		registerSubtopicEndpoint(svc),
+		decodeSubtopicRegistration,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Delete("/channels/:id/subtopics/:name", kithttp.NewServer(
+		removeSubtopicEndpoint(svc),
+		decodeSubtopic,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Post("/channels/:id/subtopic-rules", kithttp.NewServer(
+		addSubtopicRuleEndpoint(svc),
+		decodeSubtopicRuleCreation,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/channels/:id/subtopic-rules", kithttp.NewServer(
+		listSubtopicRulesEndpoint(svc),
+		decodeView,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Delete("/channels/:id/subtopic-rules/:ruleId", kithttp.NewServer(
+		removeSubtopicRuleEndpoint(svc),
+		decodeSubtopicRule,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/channels/:id/shares", kithttp.NewServer(
+		listSharesEndpoint(svc, things.ChannelKind),
+		decodeView,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Put("/channels/:id/shares/:user", kithttp.NewServer(
+		shareEndpoint(svc, things.ChannelKind),
+		decodeShare,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Delete("/channels/:id/shares/:user", kithttp.NewServer(
+		unshareEndpoint(svc, things.ChannelKind),
+		decodeUnshare,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/channels/:id/history", kithttp.NewServer(
+		channelHistoryEndpoint(svc),
+		decodeListByConnection,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Post("/channels/:id/invitations", kithttp.NewServer(
+		inviteToChannelEndpoint(svc),
+		decodeIssue,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Post("/channels/:id/tokens", kithttp.NewServer(
+		issueChannelTokenEndpoint(svc),
+		decodeChannelTokenIssuance,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/channels/:id/tokens", kithttp.NewServer(
+		listChannelTokensEndpoint(svc),
+		decodeView,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Delete("/channels/:id/tokens/:tokenId", kithttp.NewServer(
+		revokeChannelTokenEndpoint(svc),
+		decodeChannelToken,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/channels", kithttp.NewServer(
+		listChannelsEndpoint(svc),
+		decodeList,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Put("/channels/:chanId/things/:thingId", kithttp.NewServer(
+		connectEndpoint(svc),
+		decodeConnection,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Delete("/channels/:chanId/things/:thingId", kithttp.NewServer(
+		disconnectEndpoint(svc),
+		decodeConnection,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Post("/connect", kithttp.NewServer(
+		connectAllEndpoint(svc),
+		decodeConnections,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Post("/disconnect", kithttp.NewServer(
+		disconnectAllEndpoint(svc),
+		decodeConnections,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/export", kithttp.NewServer(
+		exportEndpoint(svc),
+		decodeExport,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Post("/import", kithttp.NewServer(
+		importEndpoint(svc),
+		decodeImport,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/audit", kithttp.NewServer(
+		auditEndpoint(svc),
+		decodeAudit,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/capabilities", kithttp.NewServer(
+		capabilitiesEndpoint(svc),
+		decodeCapabilities,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Put("/channels/:chanId/groups/:groupId", kithttp.NewServer(
+		connectGroupEndpoint(svc),
+		decodeGroupConnection,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Post("/groups", kithttp.NewServer(
+		createGroupEndpoint(svc),
+		decodeGroupCreation,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Put("/groups/:id", kithttp.NewServer(
+		updateGroupEndpoint(svc),
+		decodeGroupUpdate,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Delete("/groups/:id", kithttp.NewServer(
+		removeGroupEndpoint(svc),
+		decodeView,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/groups/:id", kithttp.NewServer(
+		viewGroupEndpoint(svc),
+		decodeView,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/groups", kithttp.NewServer(
+		listGroupsEndpoint(svc),
+		decodeListGroups,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/groups/:id/things", kithttp.NewServer(
+		listThingsByGroupEndpoint(svc),
+		decodeListByConnection,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Put("/groups/:id/things", kithttp.NewServer(
+		assignThingsEndpoint(svc),
+		decodeAssignment,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Delete("/groups/:id/things/:thingId", kithttp.NewServer(
+		unassignThingEndpoint(svc),
+		decodeGroupThing,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Post("/profiles", kithttp.NewServer(
+		createProfileEndpoint(svc),
+		decodeProfileCreation,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Put("/profiles/:id", kithttp.NewServer(
+		updateProfileEndpoint(svc),
+		decodeProfileUpdate,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Delete("/profiles/:id", kithttp.NewServer(
+		removeProfileEndpoint(svc),
+		decodeView,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/profiles/:id", kithttp.NewServer(
+		viewProfileEndpoint(svc),
+		decodeView,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/profiles", kithttp.NewServer(
+		listProfilesEndpoint(svc),
+		decodeListProfiles,
+		encodeResponse,
+		opts...,
+	))
+
+	r.GetFunc("/version", mainflux.Version("things"))
+	r.Handle("/metrics", promhttp.Handler())
+
+	return r
+}
+
+func decodeThingCreation(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+
+	req := addThingReq{token: r.Header.Get("Authorization")}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func decodeThingsCreation(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+
+	req := createThingsReq{token: r.Header.Get("Authorization")}
+	if err := json.NewDecoder(r.Body).Decode(&req.Things); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func decodeReservation(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+
+	req := reserveThingsReq{token: r.Header.Get("Authorization")}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func decodeTemplate(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+
+	req := saveTemplateReq{token: r.Header.Get("Authorization")}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func decodeTemplateView(_ context.Context, r *http.Request) (interface{}, error) {
+	req := templateReq{token: r.Header.Get("Authorization")}
+
+	return req, nil
+}
+
+func decodeThingsMetadataUpdate(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+
+	req := updateThingsMetadataReq{token: r.Header.Get("Authorization")}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func decodeArchiveChannels(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+
+	req := archiveChannelsReq{token: r.Header.Get("Authorization")}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func decodePatch(_ context.Context, r *http.Request) (interface{}, error) {
+	ct := r.Header.Get("Content-Type")
+	if !strings.Contains(ct, contentType) && !strings.Contains(ct, mergePatchType) {
+		return nil, errUnsupportedContentType
+	}
+
+	id, err := readIDParam(r, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	req := patchReq{
+		token: r.Header.Get("Authorization"),
+		id:    id,
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req.patch); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func decodeThingUpdate(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+
+	id, err := readIDParam(r, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	req := updateThingReq{
+		token: r.Header.Get("Authorization"),
+		id:    id,
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func decodeKeyUpdate(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+
+	id, err := readIDParam(r, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	req := updateKeyReq{
+		token: r.Header.Get("Authorization"),
+		id:    id,
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func decodeIssue(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+
+	id, err := readIDParam(r, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	req := issueReq{
+		token: r.Header.Get("Authorization"),
+		id:    id,
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func decodeOpenSession(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+
+	req := openSessionReq{key: r.Header.Get("Authorization")}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func decodeChannelCreation(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+
+	req := createChannelReq{token: r.Header.Get("Authorization")}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func decodeChannelUpdate(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+
+	id, err := readIDParam(r, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	req := updateChannelReq{
+		token: r.Header.Get("Authorization"),
+		id:    id,
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func decodeView(_ context.Context, r *http.Request) (interface{}, error) {
+	id, err := readIDParam(r, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	req := viewResourceReq{
+		token:  r.Header.Get("Authorization"),
+		id:     id,
+		fields: readFieldsQuery(r),
+	}
+
+	return req, nil
+}
+
+func decodeList(_ context.Context, r *http.Request) (interface{}, error) {
+	o, err := readUintQuery(r, offset, defOffset)
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := readUintQuery(r, limit, defLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := readStringQuery(r, name)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := readMetadataQuery(r, metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := readTagsQuery(r)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := readStringQuery(r, cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := readStringQuery(r, status)
+	if err != nil {
+		return nil, err
+	}
+
+	req := listResourcesReq{
+		token:    r.Header.Get("Authorization"),
+		offset:   o,
+		limit:    l,
+		name:     n,
+		metadata: m,
+		tags:     t,
+		status:   things.Status(s),
+		cursor:   c,
+		fields:   readFieldsQuery(r),
+	}
+
+	return req, nil
+}
+
+func decodeCount(_ context.Context, r *http.Request) (interface{}, error) {
+	n, err := readStringQuery(r, name)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := readMetadataQuery(r, metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := readTagsQuery(r)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := readStringQuery(r, status)
+	if err != nil {
+		return nil, err
+	}
+
+	req := countResourcesReq{
+		token:    r.Header.Get("Authorization"),
+		name:     n,
+		metadata: m,
+		tags:     t,
+		status:   things.Status(s),
+	}
+
+	return req, nil
+}
+
+func decodeListByConnection(_ context.Context, r *http.Request) (interface{}, error) {
+	id, err := readIDParam(r, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	o, err := readUintQuery(r, offset, defOffset)
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := readUintQuery(r, limit, defLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	req := listByConnectionReq{
+		token:  r.Header.Get("Authorization"),
+		id:     id,
+		offset: o,
+		limit:  l,
+		fields: readFieldsQuery(r),
+	}
+
+	return req, nil
+}
+
+func decodeUsage(_ context.Context, r *http.Request) (interface{}, error) {
+	id, err := readIDParam(r, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := readUintQuery(r, from, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := readUintQuery(r, to, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	req := usageReq{
+		token: r.Header.Get("Authorization"),
+		id:    id,
+		from:  f,
+		to:    t,
+	}
+
+	return req, nil
+}
+
+func decodeListUnhealthy(_ context.Context, r *http.Request) (interface{}, error) {
+	o, err := readUintQuery(r, offset, defOffset)
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := readUintQuery(r, limit, defLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := readFloatQuery(r, battery, things.DefaultMinBattery)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := readFloatQuery(r, rssi, things.DefaultMinRSSI)
+	if err != nil {
+		return nil, err
+	}
+
+	req := listUnhealthyReq{
+		token: r.Header.Get("Authorization"),
+		thresholds: things.HealthThresholds{
+			Battery: b,
+			RSSI:    s,
+		},
+		offset: o,
+		limit:  l,
+	}
+
+	return req, nil
+}
+
+func decodeSubtopicRegistration(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+
+	id, err := readIDParam(r, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	req := registerSubtopicReq{
+		token:  r.Header.Get("Authorization"),
+		chanID: id,
+		name:   bone.GetValue(r, "name"),
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func decodeSubtopic(_ context.Context, r *http.Request) (interface{}, error) {
+	id, err := readIDParam(r, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	req := subtopicReq{
+		token:  r.Header.Get("Authorization"),
+		chanID: id,
+		name:   bone.GetValue(r, "name"),
+	}
+
+	return req, nil
+}
+
+func decodeSubtopicRuleCreation(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+
+	id, err := readIDParam(r, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	req := addSubtopicRuleReq{
+		token:  r.Header.Get("Authorization"),
+		chanID: id,
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func decodeSubtopicRule(_ context.Context, r *http.Request) (interface{}, error) {
+	id, err := readIDParam(r, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	ruleID, err := readIDParam(r, "ruleId")
+	if err != nil {
+		return nil, err
+	}
+
+	req := subtopicRuleReq{
+		token:  r.Header.Get("Authorization"),
+		chanID: id,
+		ruleID: ruleID,
+	}
+
+	return req, nil
+}
+
+func decodeShare(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+
+	id, err := readIDParam(r, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	req := shareReq{
+		token: r.Header.Get("Authorization"),
+		id:    id,
+		user:  bone.GetValue(r, "user"),
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func decodeTransfer(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+
+	id, err := readIDParam(r, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	req := transferReq{
+		token: r.Header.Get("Authorization"),
+		id:    id,
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func decodeUnshare(_ context.Context, r *http.Request) (interface{}, error) {
+	id, err := readIDParam(r, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	req := unshareReq{
+		token: r.Header.Get("Authorization"),
+		id:    id,
+		user:  bone.GetValue(r, "user"),
+	}
+
+	return req, nil
+}
+
+func decodeThingKeyIssuance(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+
+	id, err := readIDParam(r, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	req := issueThingKeyReq{
+		token: r.Header.Get("Authorization"),
+		id:    id,
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func decodeThingKey(_ context.Context, r *http.Request) (interface{}, error) {
+	id, err := readIDParam(r, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	keyID, err := readIDParam(r, "keyId")
+	if err != nil {
+		return nil, err
+	}
+
+	req := thingKeyReq{
+		token: r.Header.Get("Authorization"),
+		id:    id,
+		keyID: keyID,
+	}
+
+	return req, nil
+}
+
+func decodeChannelTokenIssuance(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+
+	id, err := readIDParam(r, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	req := issueChannelTokenReq{
+		token: r.Header.Get("Authorization"),
+		id:    id,
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func decodeChannelToken(_ context.Context, r *http.Request) (interface{}, error) {
+	id, err := readIDParam(r, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	tokenID, err := readIDParam(r, "tokenId")
+	if err != nil {
+		return nil, err
+	}
+
+	req := channelTokenReq{
+		token:   r.Header.Get("Authorization"),
+		id:      id,
+		tokenID: tokenID,
+	}
+
+	return req, nil
+}
+
+func decodeConnection(_ context.Context, r *http.Request) (interface{}, error) {
+	chanID, err := readIDParam(r, "chanId")
+	if err != nil {
+		return nil, err
+	}
+
+	thingID, err := readIDParam(r, "thingId")
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := readStringQuery(r, access)
+	if err != nil {
+		return nil, err
+	}
+
+	req := connectionReq{
+		token:   r.Header.Get("Authorization"),
+		chanID:  chanID,
+		thingID: thingID,
+		access:  things.ConnectionAccess(a),
+	}
+
+	return req, nil
+}
+
+func decodeCapabilities(_ context.Context, r *http.Request) (interface{}, error) {
+	k, err := readStringQuery(r, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	e, err := readStringQuery(r, entity)
+	if err != nil {
+		return nil, err
+	}
+
+	req := capabilitiesReq{
+		token:  r.Header.Get("Authorization"),
+		kind:   k,
+		entity: e,
+	}
+
+	return req, nil
+}
+
+func decodeAudit(_ context.Context, r *http.Request) (interface{}, error) {
+	o, err := readUintQuery(r, offset, defOffset)
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := readUintQuery(r, limit, defLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := readUintQuery(r, from, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := readUintQuery(r, to, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := readStringQuery(r, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	op, err := readStringQuery(r, operation)
+	if err != nil {
+		return nil, err
+	}
+
+	k, err := readStringQuery(r, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	e, err := readStringQuery(r, entity)
+	if err != nil {
+		return nil, err
+	}
+
+	req := auditReq{
+		token:     r.Header.Get("Authorization"),
+		actor:     a,
+		operation: op,
+		kind:      k,
+		entity:    e,
+		from:      f,
+		to:        t,
+		offset:    o,
+		limit:     l,
+	}
+
+	return req, nil
+}
+
+func decodeConnections(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+
+	req := connectionsReq{token: r.Header.Get("Authorization")}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
 
-	r.Delete("/things/:id", kithttp.NewServer(
-		removeThingEndpoint(svc),
-		decodeView,
-		encodeResponse,
-		opts...,
-	))
+func decodeExport(_ context.Context, r *http.Request) (interface{}, error) {
+	req := exportReq{
+		token: r.Header.Get("Authorization"),
+		csv:   strings.Contains(r.Header.Get("Accept"), csvContentType),
+	}
 
-	r.Get("/things/:id", kithttp.NewServer(
-		viewThingEndpoint(svc),
-		decodeView,
-		encodeResponse,
-		opts...,
-	))
+	return req, nil
+}
 
-	r.Get("/things/:id/channels", kithttp.NewServer(
-		listChannelsByThingEndpoint(svc),
-		decodeListByConnection,
-		encodeResponse,
-		opts...,
-	))
+func decodeImport(_ context.Context, r *http.Request) (interface{}, error) {
+	p, err := readBoolQuery(r, preserve)
+	if err != nil {
+		return nil, err
+	}
 
-	r.Get("/things", kithttp.NewServer(
-		listThingsEndpoint(svc),
-		decodeList,
-		encodeResponse,
-		opts...,
-	))
+	req := importReq{
+		token:    r.Header.Get("Authorization"),
+		preserve: p,
+		csv:      strings.Contains(r.Header.Get("Accept"), csvContentType),
+	}
 
-	r.Post("/channels", kithttp.NewServer(
-		createChannelEndpoint(svc),
-		decodeChannelCreation,
-		encodeResponse,
-		opts...,
-	))
+	ct := r.Header.Get("Content-Type")
+	switch {
+	case strings.Contains(ct, csvContentType):
+		if err := readSnapshotCSV(r.Body, &req); err != nil {
+			return nil, err
+		}
+	case strings.Contains(ct, contentType):
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errUnsupportedContentType
+	}
 
-	r.Put("/channels/:id", kithttp.NewServer(
-		updateChannelEndpoint(svc),
-		decodeChannelUpdate,
-		encodeResponse,
-		opts...,
-	))
+	return req, nil
+}
 
-	r.Delete("/channels/:id", kithttp.NewServer(
-		removeChannelEndpoint(svc),
-		decodeView,
-		encodeResponse,
-		opts...,
-	))
+// readSnapshotCSV reads the snapshot written as CSV document having the
+// snapshot columns, as the header row. Each of the following rows holds the
+// thing, the channel or the connection, depending on its kind.
+func readSnapshotCSV(body io.Reader, req *importReq) error {
+	rows, err := csv.NewReader(body).ReadAll()
+	if err != nil {
+		return err
+	}
 
-	r.Get("/channels/:id", kithttp.NewServer(
-		viewChannelEndpoint(svc),
-		decodeView,
-		encodeResponse,
-		opts...,
-	))
+	if len(rows) == 0 || strings.Join(rows[0], ",") != strings.Join(snapshotColumns, ",") {
+		return things.ErrMalformedEntity
+	}
 
-	r.Get("/channels/:id/things", kithttp.NewServer(
-		listThingsByChannelEndpoint(svc),
-		decodeListByConnection,
-		encodeResponse,
-		opts...,
-	))
+	for _, row := range rows[1:] {
+		switch row[0] {
+		case thingRow:
+			th := snapshotThing{
+				ID:    row[1],
+				Name:  row[2],
+				Key:   row[3],
+				State: row[6],
+				Tags:  readCSVTags(row[7]),
+			}
+			if th.KeyExpiresAt, err = readCSVInt(row[4]); err != nil {
+				return err
+			}
+			if th.KeyRotation, err = readCSVInt(row[5]); err != nil {
+				return err
+			}
+			if th.Metadata, err = readCSVMetadata(row[8]); err != nil {
+				return err
+			}
+			req.Things = append(req.Things, th)
+		case channelRow:
+			ch := snapshotChannel{
+				ID:    row[1],
+				Name:  row[2],
+				State: row[6],
+				Tags:  readCSVTags(row[7]),
+			}
+			if ch.Metadata, err = readCSVMetadata(row[8]); err != nil {
+				return err
+			}
+			req.Channels = append(req.Channels, ch)
+		case connectionRow:
+			req.Connections = append(req.Connections, snapshotConnection{
+				ChanID:  row[9],
+				ThingID: row[10],
+				Access:  row[11],
+			})
+		default:
+			return things.ErrMalformedEntity
+		}
+	}
 
-	r.Get("/channels", kithttp.NewServer(
-		listChannelsEndpoint(svc),
-		decodeList,
-		encodeResponse,
-		opts...,
-	))
+	return nil
+}
 
-	r.Put("/channels/:chanId/things/:thingId", kithttp.NewServer(
-		connectEndpoint(svc),
-		decodeConnection,
-		encodeResponse,
-		opts...,
-	))
+func readCSVInt(val string) (int64, error) {
+	if val == "" {
+		return 0, nil
+	}
 
-	r.Delete("/channels/:chanId/things/:thingId", kithttp.NewServer(
-		disconnectEndpoint(svc),
-		decodeConnection,
-		encodeResponse,
-		opts...,
-	))
+	i, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		return 0, things.ErrMalformedEntity
+	}
 
-	r.GetFunc("/version", mainflux.Version("things"))
-	r.Handle("/metrics", promhttp.Handler())
+	return i, nil
+}
 
-	return r
+func readCSVTags(val string) []string {
+	if val == "" {
+		return nil
+	}
+
+	return strings.Split(val, ",")
 }
 
-func decodeThingCreation(_ context.Context, r *http.Request) (interface{}, error) {
-	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
-		return nil, errUnsupportedContentType
+func readCSVMetadata(val string) (map[string]interface{}, error) {
+	if val == "" {
+		return nil, nil
 	}
 
-	req := addThingReq{token: r.Header.Get("Authorization")}
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		return nil, err
+	m := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(val), &m); err != nil {
+		return nil, things.ErrMalformedEntity
 	}
 
-	return req, nil
+	return m, nil
 }
 
-func decodeThingUpdate(_ context.Context, r *http.Request) (interface{}, error) {
+func decodeGroupCreation(_ context.Context, r *http.Request) (interface{}, error) {
 	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
 		return nil, errUnsupportedContentType
 	}
 
-	req := updateThingReq{
-		token: r.Header.Get("Authorization"),
-		id:    bone.GetValue(r, "id"),
-	}
+	req := createGroupReq{token: r.Header.Get("Authorization")}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		return nil, err
 	}
@@ -186,14 +1534,19 @@ func decodeThingUpdate(_ context.Context, r *http.Request) (interface{}, error)
 	return req, nil
 }
 
-func decodeKeyUpdate(_ context.Context, r *http.Request) (interface{}, error) {
+func decodeGroupUpdate(_ context.Context, r *http.Request) (interface{}, error) {
 	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
 		return nil, errUnsupportedContentType
 	}
 
-	req := updateKeyReq{
+	id, err := readIDParam(r, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	req := updateGroupReq{
 		token: r.Header.Get("Authorization"),
-		id:    bone.GetValue(r, "id"),
+		id:    id,
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		return nil, err
@@ -202,27 +1555,45 @@ func decodeKeyUpdate(_ context.Context, r *http.Request) (interface{}, error) {
 	return req, nil
 }
 
-func decodeChannelCreation(_ context.Context, r *http.Request) (interface{}, error) {
-	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
-		return nil, errUnsupportedContentType
+func decodeListGroups(_ context.Context, r *http.Request) (interface{}, error) {
+	o, err := readUintQuery(r, offset, defOffset)
+	if err != nil {
+		return nil, err
 	}
 
-	req := createChannelReq{token: r.Header.Get("Authorization")}
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	l, err := readUintQuery(r, limit, defLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := readStringQuery(r, parent)
+	if err != nil {
 		return nil, err
 	}
 
+	req := listGroupsReq{
+		token:  r.Header.Get("Authorization"),
+		parent: p,
+		offset: o,
+		limit:  l,
+	}
+
 	return req, nil
 }
 
-func decodeChannelUpdate(_ context.Context, r *http.Request) (interface{}, error) {
+func decodeAssignment(_ context.Context, r *http.Request) (interface{}, error) {
 	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
 		return nil, errUnsupportedContentType
 	}
 
-	req := updateChannelReq{
+	id, err := readIDParam(r, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	req := assignThingsReq{
 		token: r.Header.Get("Authorization"),
-		id:    bone.GetValue(r, "id"),
+		id:    id,
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		return nil, err
@@ -231,42 +1602,81 @@ func decodeChannelUpdate(_ context.Context, r *http.Request) (interface{}, error
 	return req, nil
 }
 
-func decodeView(_ context.Context, r *http.Request) (interface{}, error) {
-	req := viewResourceReq{
-		token: r.Header.Get("Authorization"),
-		id:    bone.GetValue(r, "id"),
+func decodeGroupThing(_ context.Context, r *http.Request) (interface{}, error) {
+	id, err := readIDParam(r, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	thingID, err := readIDParam(r, "thingId")
+	if err != nil {
+		return nil, err
+	}
+
+	req := groupThingReq{
+		token:   r.Header.Get("Authorization"),
+		id:      id,
+		thingID: thingID,
 	}
 
 	return req, nil
 }
 
-func decodeList(_ context.Context, r *http.Request) (interface{}, error) {
-	o, err := readUintQuery(r, offset, defOffset)
+func decodeGroupConnection(_ context.Context, r *http.Request) (interface{}, error) {
+	chanID, err := readIDParam(r, "chanId")
 	if err != nil {
 		return nil, err
 	}
 
-	l, err := readUintQuery(r, limit, defLimit)
+	groupID, err := readIDParam(r, "groupId")
 	if err != nil {
 		return nil, err
 	}
 
-	n, err := readStringQuery(r, name)
+	req := groupConnectionReq{
+		token:   r.Header.Get("Authorization"),
+		chanID:  chanID,
+		groupID: groupID,
+	}
+
+	return req, nil
+}
+
+func decodeProfileCreation(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+
+	req := profileReq{token: r.Header.Get("Authorization")}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func decodeProfileUpdate(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+
+	id, err := readIDParam(r, "id")
 	if err != nil {
 		return nil, err
 	}
 
-	req := listResourcesReq{
-		token:  r.Header.Get("Authorization"),
-		offset: o,
-		limit:  l,
-		name:   n,
+	req := profileReq{
+		token: r.Header.Get("Authorization"),
+		id:    id,
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
 	}
 
 	return req, nil
 }
 
-func decodeListByConnection(_ context.Context, r *http.Request) (interface{}, error) {
+func decodeListProfiles(_ context.Context, r *http.Request) (interface{}, error) {
 	o, err := readUintQuery(r, offset, defOffset)
 	if err != nil {
 		return nil, err
@@ -277,9 +1687,8 @@ func decodeListByConnection(_ context.Context, r *http.Request) (interface{}, er
 		return nil, err
 	}
 
-	req := listByConnectionReq{
+	req := listProfilesReq{
 		token:  r.Header.Get("Authorization"),
-		id:     bone.GetValue(r, "id"),
 		offset: o,
 		limit:  l,
 	}
@@ -287,16 +1696,6 @@ func decodeListByConnection(_ context.Context, r *http.Request) (interface{}, er
 	return req, nil
 }
 
-func decodeConnection(_ context.Context, r *http.Request) (interface{}, error) {
-	req := connectionReq{
-		token:   r.Header.Get("Authorization"),
-		chanID:  bone.GetValue(r, "chanId"),
-		thingID: bone.GetValue(r, "thingId"),
-	}
-
-	return req, nil
-}
-
 func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
 	w.Header().Set("Content-Type", contentType)
 
@@ -312,6 +1711,11 @@ func encodeResponse(_ context.Context, w http.ResponseWriter, response interface
 		}
 	}
 
+	if doc, ok := response.(documentRes); ok {
+		_, err := w.Write(doc.content)
+		return err
+	}
+
 	return json.NewEncoder(w).Encode(response)
 }
 
@@ -319,7 +1723,7 @@ func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 	w.Header().Set("Content-Type", contentType)
 
 	switch err {
-	case things.ErrMalformedEntity:
+	case things.ErrMalformedEntity, identifiers.ErrMalformedID:
 		w.WriteHeader(http.StatusBadRequest)
 	case things.ErrUnauthorizedAccess:
 		w.WriteHeader(http.StatusForbidden)
@@ -327,6 +1731,8 @@ func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 		w.WriteHeader(http.StatusNotFound)
 	case things.ErrConflict:
 		w.WriteHeader(http.StatusUnprocessableEntity)
+	case things.ErrQuotaExceeded:
+		w.WriteHeader(http.StatusTooManyRequests)
 	case errUnsupportedContentType:
 		w.WriteHeader(http.StatusUnsupportedMediaType)
 	case errInvalidQueryParams:
@@ -336,17 +1742,42 @@ func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 	case io.EOF:
 		w.WriteHeader(http.StatusBadRequest)
 	default:
-		switch err.(type) {
+		switch e := err.(type) {
+		case *things.ValidationError:
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(errorRes{
+				Field: e.Field,
+				Error: e.Error(),
+			})
+		case *things.ConnectionError:
+			res := connectionErrorRes{Failures: []connectionFailureRes{}}
+			for _, f := range e.Failures {
+				res.Failures = append(res.Failures, connectionFailureRes{
+					ChanID:  f.ChanID,
+					ThingID: f.ThingID,
+					Error:   f.Err.Error(),
+				})
+			}
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(res)
 		case *json.SyntaxError:
 			w.WriteHeader(http.StatusBadRequest)
 		case *json.UnmarshalTypeError:
 			w.WriteHeader(http.StatusBadRequest)
+		case *csv.ParseError:
+			w.WriteHeader(http.StatusBadRequest)
 		default:
 			w.WriteHeader(http.StatusInternalServerError)
 		}
 	}
 }
 
+// readIDParam reads the identifier passed as the path parameter, and returns
+// its canonical form.
+func readIDParam(r *http.Request, key string) (string, error) {
+	return identifiers.Parse(bone.GetValue(r, key))
+}
+
 func readUintQuery(r *http.Request, key string, def uint64) (uint64, error) {
 	vals := bone.GetQuery(r, key)
 	if len(vals) > 1 {
@@ -366,6 +1797,38 @@ func readUintQuery(r *http.Request, key string, def uint64) (uint64, error) {
 	return val, nil
 }
 
+func readFloatQuery(r *http.Request, key string, def float64) (float64, error) {
+	val, err := readStringQuery(r, key)
+	if err != nil {
+		return 0, err
+	}
+
+	if val == "" {
+		return def, nil
+	}
+
+	f, err := strconv.ParseFloat(val, 64)
+	if err != nil {
+		return 0, errInvalidQueryParams
+	}
+
+	return f, nil
+}
+
+func readBoolQuery(r *http.Request, key string) (bool, error) {
+	val, err := readStringQuery(r, key)
+	if err != nil || val == "" {
+		return false, err
+	}
+
+	b, err := strconv.ParseBool(val)
+	if err != nil {
+		return false, errInvalidQueryParams
+	}
+
+	return b, nil
+}
+
 func readStringQuery(r *http.Request, key string) (string, error) {
 	vals := bone.GetQuery(r, key)
 	if len(vals) > 1 {
@@ -378,3 +1841,60 @@ func readStringQuery(r *http.Request, key string) (string, error) {
 
 	return vals[0], nil
 }
+
+// readMetadataQuery reads the metadata filter passed as JSON object, e.g.
+// metadata={"type":"sensor"}.
+func readMetadataQuery(r *http.Request, key string) (map[string]interface{}, error) {
+	val, err := readStringQuery(r, key)
+	if err != nil || val == "" {
+		return nil, err
+	}
+
+	m := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(val), &m); err != nil {
+		return nil, errInvalidQueryParams
+	}
+
+	return m, nil
+}
+
+// readTagsQuery reads the tags filter passed as a comma-separated list or as
+// the repeated tags parameter, e.g. tags=edge,v2, along with the match
+// parameter telling whether all the tags (the default) or any of them have
+// to be held.
+func readTagsQuery(r *http.Request) (things.TagFilter, error) {
+	m, err := readStringQuery(r, match)
+	if err != nil {
+		return things.TagFilter{}, err
+	}
+
+	if m != "" && m != matchAll && m != matchAny {
+		return things.TagFilter{}, errInvalidQueryParams
+	}
+
+	filter := things.TagFilter{Any: m == matchAny}
+	for _, val := range bone.GetQuery(r, tags) {
+		for _, tag := range strings.Split(val, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				filter.Tags = append(filter.Tags, tag)
+			}
+		}
+	}
+
+	return filter, nil
+}
+
+// readFieldsQuery reads the fields to return, passed as a comma-separated
+// list or as the repeated fields parameter.
+func readFieldsQuery(r *http.Request) fieldSet {
+	fs := fieldSet{}
+	for _, val := range bone.GetQuery(r, fields) {
+		for _, f := range strings.Split(val, ",") {
+			if f = strings.TrimSpace(f); f != "" {
+				fs[f] = true
+			}
+		}
+	}
+
+	return fs
+}
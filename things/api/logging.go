@@ -10,6 +10,7 @@
 package api
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -29,7 +30,7 @@ func LoggingMiddleware(svc things.Service, logger log.Logger) things.Service {
 	return &loggingMiddleware{logger, svc}
 }
 
-func (lm *loggingMiddleware) AddThing(token string, thing things.Thing) (saved things.Thing, err error) {
+func (lm *loggingMiddleware) AddThing(ctx context.Context, token string, thing things.Thing) (saved things.Thing, err error) {
 	defer func(begin time.Time) {
 		message := fmt.Sprintf("Method add_thing for token %s and thing %s took %s to complete", token, saved.ID, time.Since(begin))
 		if err != nil {
@@ -39,10 +40,153 @@ func (lm *loggingMiddleware) AddThing(token string, thing things.Thing) (saved t
 		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
 	}(time.Now())
 
-	return lm.svc.AddThing(token, thing)
+	return lm.svc.AddThing(ctx, token, thing)
 }
 
-func (lm *loggingMiddleware) UpdateThing(token string, thing things.Thing) (err error) {
+func (lm *loggingMiddleware) CreateThings(ctx context.Context, token string, ths ...things.Thing) (saved []things.Thing, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method create_things for token %s and %d things took %s to complete", token, len(ths), time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.CreateThings(ctx, token, ths...)
+}
+
+func (lm *loggingMiddleware) ProvisionThing(ctx context.Context, token, profileID string, thing things.Thing) (top things.Topology, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method provision_thing for token %s, profile %s and thing %s took %s to complete", token, profileID, top.Thing.ID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ProvisionThing(ctx, token, profileID, thing)
+}
+
+func (lm *loggingMiddleware) SaveTemplate(ctx context.Context, token string, tmpl things.Template) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method save_template for token %s took %s to complete", token, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.SaveTemplate(ctx, token, tmpl)
+}
+
+func (lm *loggingMiddleware) ViewTemplate(ctx context.Context, token string) (tmpl things.Template, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method view_template for token %s took %s to complete", token, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ViewTemplate(ctx, token)
+}
+
+func (lm *loggingMiddleware) RemoveTemplate(ctx context.Context, token string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method remove_template for token %s took %s to complete", token, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RemoveTemplate(ctx, token)
+}
+
+func (lm *loggingMiddleware) CreateProfile(ctx context.Context, token string, profile things.Profile) (saved things.Profile, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method create_profile for token %s and profile %s took %s to complete", token, saved.ID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.CreateProfile(ctx, token, profile)
+}
+
+func (lm *loggingMiddleware) UpdateProfile(ctx context.Context, token string, profile things.Profile) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method update_profile for token %s and profile %s took %s to complete", token, profile.ID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.UpdateProfile(ctx, token, profile)
+}
+
+func (lm *loggingMiddleware) ViewProfile(ctx context.Context, token, id string) (profile things.Profile, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method view_profile for token %s and profile %s took %s to complete", token, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ViewProfile(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) ListProfiles(ctx context.Context, token string, offset, limit uint64) (page things.ProfilesPage, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_profiles for token %s took %s to complete", token, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListProfiles(ctx, token, offset, limit)
+}
+
+func (lm *loggingMiddleware) RemoveProfile(ctx context.Context, token, id string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method remove_profile for token %s and profile %s took %s to complete", token, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RemoveProfile(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) ReserveThings(ctx context.Context, token string, n uint64) (reservations []things.Reservation, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method reserve_things for token %s and %d things took %s to complete", token, n, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ReserveThings(ctx, token, n)
+}
+
+func (lm *loggingMiddleware) UpdateThing(ctx context.Context, token string, thing things.Thing) (err error) {
 	defer func(begin time.Time) {
 		message := fmt.Sprintf("Method update_thing for token %s and thing %s took %s to complete", token, thing.ID, time.Since(begin))
 		if err != nil {
@@ -52,10 +196,10 @@ func (lm *loggingMiddleware) UpdateThing(token string, thing things.Thing) (err
 		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
 	}(time.Now())
 
-	return lm.svc.UpdateThing(token, thing)
+	return lm.svc.UpdateThing(ctx, token, thing)
 }
 
-func (lm *loggingMiddleware) UpdateKey(token, id, key string) (err error) {
+func (lm *loggingMiddleware) UpdateKey(ctx context.Context, token, id, key string, expiresAt time.Time, rotation time.Duration) (err error) {
 	defer func(begin time.Time) {
 		message := fmt.Sprintf("Method update_key for thing %s and key %s took %s to complete", id, key, time.Since(begin))
 		if err != nil {
@@ -65,12 +209,12 @@ func (lm *loggingMiddleware) UpdateKey(token, id, key string) (err error) {
 		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
 	}(time.Now())
 
-	return lm.svc.UpdateKey(token, id, key)
+	return lm.svc.UpdateKey(ctx, token, id, key, expiresAt, rotation)
 }
 
-func (lm *loggingMiddleware) ViewThing(token, id string) (thing things.Thing, err error) {
+func (lm *loggingMiddleware) RotateKeys(ctx context.Context, since time.Time) (rotated []things.Thing, err error) {
 	defer func(begin time.Time) {
-		message := fmt.Sprintf("Method view_thing for token %s and thing %s took %s to complete", token, id, time.Since(begin))
+		message := fmt.Sprintf("Method rotate_keys rotated %d keys and took %s to complete", len(rotated), time.Since(begin))
 		if err != nil {
 			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
 			return
@@ -78,16 +222,25 @@ func (lm *loggingMiddleware) ViewThing(token, id string) (thing things.Thing, er
 		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
 	}(time.Now())
 
-	return lm.svc.ViewThing(token, id)
+	return lm.svc.RotateKeys(ctx, since)
 }
 
-func (lm *loggingMiddleware) ListThings(token string, offset, limit uint64, name string) (_ things.ThingsPage, err error) {
+func (lm *loggingMiddleware) PatchThing(ctx context.Context, token, id string, patch map[string]interface{}) (thing things.Thing, err error) {
 	defer func(begin time.Time) {
-		nlog := ""
-		if name != "" {
-			nlog = fmt.Sprintf("with name %s ", name)
+		message := fmt.Sprintf("Method patch_thing for token %s and thing %s took %s to complete", token, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
 		}
-		message := fmt.Sprintf("Method list_things %sfor token %s took %s to complete", nlog, token, time.Since(begin))
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.PatchThing(ctx, token, id, patch)
+}
+
+func (lm *loggingMiddleware) UpdateThingsMetadata(ctx context.Context, token string, selector, patch map[string]interface{}) (updated []things.Thing, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method update_things_metadata for token %s updated %d things and took %s to complete", token, len(updated), time.Since(begin))
 		if err != nil {
 			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
 			return
@@ -95,24 +248,25 @@ func (lm *loggingMiddleware) ListThings(token string, offset, limit uint64, name
 		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
 	}(time.Now())
 
-	return lm.svc.ListThings(token, offset, limit, name)
+	return lm.svc.UpdateThingsMetadata(ctx, token, selector, patch)
 }
 
-func (lm *loggingMiddleware) ListThingsByChannel(token, id string, offset, limit uint64) (_ things.ThingsPage, err error) {
+func (lm *loggingMiddleware) OnboardThing(ctx context.Context, token, id string) (onboarding things.Onboarding, code string, err error) {
 	defer func(begin time.Time) {
-		message := fmt.Sprintf("Method list_things_by_channel for channel %s took %s to complete", id, time.Since(begin))
+		message := fmt.Sprintf("Method onboard_thing for token %s and thing %s took %s to complete", token, id, time.Since(begin))
 		if err != nil {
-			lm.logger.Warn(fmt.Sprintf("%s with error: %s", message, err))
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
 		}
 		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
 	}(time.Now())
 
-	return lm.svc.ListThingsByChannel(token, id, offset, limit)
+	return lm.svc.OnboardThing(ctx, token, id)
 }
 
-func (lm *loggingMiddleware) RemoveThing(token, id string) (err error) {
+func (lm *loggingMiddleware) IssueKey(ctx context.Context, token, id string, ttl time.Duration) (key things.SignedKey, signed string, err error) {
 	defer func(begin time.Time) {
-		message := fmt.Sprintf("Method remove_thing for token %s and thing %s took %s to complete", token, id, time.Since(begin))
+		message := fmt.Sprintf("Method issue_key for token %s and thing %s took %s to complete", token, id, time.Since(begin))
 		if err != nil {
 			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
 			return
@@ -120,12 +274,12 @@ func (lm *loggingMiddleware) RemoveThing(token, id string) (err error) {
 		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
 	}(time.Now())
 
-	return lm.svc.RemoveThing(token, id)
+	return lm.svc.IssueKey(ctx, token, id, ttl)
 }
 
-func (lm *loggingMiddleware) CreateChannel(token string, channel things.Channel) (saved things.Channel, err error) {
+func (lm *loggingMiddleware) OpenSession(ctx context.Context, key string, ttl time.Duration) (session things.SignedKey, signed string, err error) {
 	defer func(begin time.Time) {
-		message := fmt.Sprintf("Method create_channel for token %s and channel %s took %s to complete", token, channel.ID, time.Since(begin))
+		message := fmt.Sprintf("Method open_session for key %s and thing %s took %s to complete", key, session.ThingID, time.Since(begin))
 		if err != nil {
 			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
 			return
@@ -133,12 +287,12 @@ func (lm *loggingMiddleware) CreateChannel(token string, channel things.Channel)
 		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
 	}(time.Now())
 
-	return lm.svc.CreateChannel(token, channel)
+	return lm.svc.OpenSession(ctx, key, ttl)
 }
 
-func (lm *loggingMiddleware) UpdateChannel(token string, channel things.Channel) (err error) {
+func (lm *loggingMiddleware) RevokeKeys(ctx context.Context, token, id string) (err error) {
 	defer func(begin time.Time) {
-		message := fmt.Sprintf("Method update_channel for token %s and channel %s took %s to complete", token, channel.ID, time.Since(begin))
+		message := fmt.Sprintf("Method revoke_keys for token %s and thing %s took %s to complete", token, id, time.Since(begin))
 		if err != nil {
 			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
 			return
@@ -146,12 +300,51 @@ func (lm *loggingMiddleware) UpdateChannel(token string, channel things.Channel)
 		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
 	}(time.Now())
 
-	return lm.svc.UpdateChannel(token, channel)
+	return lm.svc.RevokeKeys(ctx, token, id)
 }
 
-func (lm *loggingMiddleware) ViewChannel(token, id string) (channel things.Channel, err error) {
+func (lm *loggingMiddleware) IssueThingKey(ctx context.Context, token, id string, scope things.KeyScope) (_ things.ThingKey, err error) {
 	defer func(begin time.Time) {
-		message := fmt.Sprintf("Method view_channel for token %s and channel %s took %s to complete", token, id, time.Since(begin))
+		message := fmt.Sprintf("Method issue_thing_key for token %s, thing %s and scope %s took %s to complete", token, id, scope, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.IssueThingKey(ctx, token, id, scope)
+}
+
+func (lm *loggingMiddleware) ListThingKeys(ctx context.Context, token, id string) (_ []things.ThingKey, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_thing_keys for token %s and thing %s took %s to complete", token, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListThingKeys(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) RevokeThingKey(ctx context.Context, token, id, keyID string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method revoke_thing_key for token %s, thing %s and key %s took %s to complete", token, id, keyID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RevokeThingKey(ctx, token, id, keyID)
+}
+
+func (lm *loggingMiddleware) ViewThing(ctx context.Context, token, id string) (thing things.Thing, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method view_thing for token %s and thing %s took %s to complete", token, id, time.Since(begin))
 		if err != nil {
 			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
 			return
@@ -159,16 +352,28 @@ func (lm *loggingMiddleware) ViewChannel(token, id string) (channel things.Chann
 		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
 	}(time.Now())
 
-	return lm.svc.ViewChannel(token, id)
+	return lm.svc.ViewThing(ctx, token, id)
 }
 
-func (lm *loggingMiddleware) ListChannels(token string, offset, limit uint64, name string) (_ things.ChannelsPage, err error) {
+func (lm *loggingMiddleware) ListThings(ctx context.Context, token string, offset, limit uint64, name string, metadata map[string]interface{}, tags things.TagFilter, status things.Status, cursor string) (_ things.ThingsPage, err error) {
 	defer func(begin time.Time) {
 		nlog := ""
 		if name != "" {
 			nlog = fmt.Sprintf("with name %s ", name)
 		}
-		message := fmt.Sprintf("Method list_channels %sfor token %s took %s to complete", nlog, token, time.Since(begin))
+		if len(metadata) > 0 {
+			nlog = fmt.Sprintf("%swith metadata %v ", nlog, metadata)
+		}
+		if len(tags.Tags) > 0 {
+			nlog = fmt.Sprintf("%swith tags %v ", nlog, tags.Tags)
+		}
+		if status != "" {
+			nlog = fmt.Sprintf("%swith status %s ", nlog, status)
+		}
+		if cursor != "" {
+			nlog = fmt.Sprintf("%safter cursor %s ", nlog, cursor)
+		}
+		message := fmt.Sprintf("Method list_things %sfor token %s took %s to complete", nlog, token, time.Since(begin))
 		if err != nil {
 			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
 			return
@@ -176,24 +381,50 @@ func (lm *loggingMiddleware) ListChannels(token string, offset, limit uint64, na
 		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
 	}(time.Now())
 
-	return lm.svc.ListChannels(token, offset, limit, name)
+	return lm.svc.ListThings(ctx, token, offset, limit, name, metadata, tags, status, cursor)
 }
 
-func (lm *loggingMiddleware) ListChannelsByThing(token, id string, offset, limit uint64) (_ things.ChannelsPage, err error) {
+func (lm *loggingMiddleware) CountThings(ctx context.Context, token, name string, metadata map[string]interface{}, tags things.TagFilter, status things.Status) (count uint64, err error) {
 	defer func(begin time.Time) {
-		message := fmt.Sprintf("Method list_channels_by_thing for thing %s took %s to complete", id, time.Since(begin))
+		nlog := ""
+		if name != "" {
+			nlog = fmt.Sprintf("with name %s ", name)
+		}
+		if len(metadata) > 0 {
+			nlog = fmt.Sprintf("%swith metadata %v ", nlog, metadata)
+		}
+		if len(tags.Tags) > 0 {
+			nlog = fmt.Sprintf("%swith tags %v ", nlog, tags.Tags)
+		}
+		if status != "" {
+			nlog = fmt.Sprintf("%swith status %s ", nlog, status)
+		}
+		message := fmt.Sprintf("Method count_things %sfor token %s took %s to complete", nlog, token, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.CountThings(ctx, token, name, metadata, tags, status)
+}
+
+func (lm *loggingMiddleware) ListThingsByChannel(ctx context.Context, token, id string, offset, limit uint64) (_ things.ThingsPage, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_things_by_channel for channel %s took %s to complete", id, time.Since(begin))
 		if err != nil {
 			lm.logger.Warn(fmt.Sprintf("%s with error: %s", message, err))
 		}
 		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
 	}(time.Now())
 
-	return lm.svc.ListChannelsByThing(token, id, offset, limit)
+	return lm.svc.ListThingsByChannel(ctx, token, id, offset, limit)
 }
 
-func (lm *loggingMiddleware) RemoveChannel(token, id string) (err error) {
+func (lm *loggingMiddleware) EnableThing(ctx context.Context, token, id string) (err error) {
 	defer func(begin time.Time) {
-		message := fmt.Sprintf("Method remove_channel for token %s and channel %s took %s to complete", token, id, time.Since(begin))
+		message := fmt.Sprintf("Method enable_thing for token %s and thing %s took %s to complete", token, id, time.Since(begin))
 		if err != nil {
 			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
 			return
@@ -201,12 +432,12 @@ func (lm *loggingMiddleware) RemoveChannel(token, id string) (err error) {
 		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
 	}(time.Now())
 
-	return lm.svc.RemoveChannel(token, id)
+	return lm.svc.EnableThing(ctx, token, id)
 }
 
-func (lm *loggingMiddleware) Connect(token, chanID, thingID string) (err error) {
+func (lm *loggingMiddleware) DisableThing(ctx context.Context, token, id string) (err error) {
 	defer func(begin time.Time) {
-		message := fmt.Sprintf("Method connect for token %s, channel %s and thing %s took %s to complete", token, chanID, thingID, time.Since(begin))
+		message := fmt.Sprintf("Method disable_thing for token %s and thing %s took %s to complete", token, id, time.Since(begin))
 		if err != nil {
 			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
 			return
@@ -214,12 +445,12 @@ func (lm *loggingMiddleware) Connect(token, chanID, thingID string) (err error)
 		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
 	}(time.Now())
 
-	return lm.svc.Connect(token, chanID, thingID)
+	return lm.svc.DisableThing(ctx, token, id)
 }
 
-func (lm *loggingMiddleware) Disconnect(token, chanID, thingID string) (err error) {
+func (lm *loggingMiddleware) TransferThing(ctx context.Context, token, id, newOwner string, keepConns bool) (err error) {
 	defer func(begin time.Time) {
-		message := fmt.Sprintf("Method disconnect for token %s, channel %s and thing %s took %s to complete", token, chanID, thingID, time.Since(begin))
+		message := fmt.Sprintf("Method transfer_thing for token %s and thing %s to %s took %s to complete", token, id, newOwner, time.Since(begin))
 		if err != nil {
 			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
 			return
@@ -227,12 +458,12 @@ func (lm *loggingMiddleware) Disconnect(token, chanID, thingID string) (err erro
 		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
 	}(time.Now())
 
-	return lm.svc.Disconnect(token, chanID, thingID)
+	return lm.svc.TransferThing(ctx, token, id, newOwner, keepConns)
 }
 
-func (lm *loggingMiddleware) CanAccess(id, key string) (thing string, err error) {
+func (lm *loggingMiddleware) RemoveThing(ctx context.Context, token, id string) (err error) {
 	defer func(begin time.Time) {
-		message := fmt.Sprintf("Method can_access for channel %s and thing %s took %s to complete", id, thing, time.Since(begin))
+		message := fmt.Sprintf("Method remove_thing for token %s and thing %s took %s to complete", token, id, time.Since(begin))
 		if err != nil {
 			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
 			return
@@ -240,12 +471,766 @@ func (lm *loggingMiddleware) CanAccess(id, key string) (thing string, err error)
 		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
 	}(time.Now())
 
-	return lm.svc.CanAccess(id, key)
+	return lm.svc.RemoveThing(ctx, token, id)
 }
 
-func (lm *loggingMiddleware) Identify(key string) (id string, err error) {
+func (lm *loggingMiddleware) CreateChannel(ctx context.Context, token string, channel things.Channel) (saved things.Channel, err error) {
 	defer func(begin time.Time) {
-		message := fmt.Sprintf("Method identify for key %s and thing %s took %s to complete", key, id, time.Since(begin))
+		message := fmt.Sprintf("Method create_channel for token %s and channel %s took %s to complete", token, channel.ID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.CreateChannel(ctx, token, channel)
+}
+
+func (lm *loggingMiddleware) UpdateChannel(ctx context.Context, token string, channel things.Channel) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method update_channel for token %s and channel %s took %s to complete", token, channel.ID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.UpdateChannel(ctx, token, channel)
+}
+
+func (lm *loggingMiddleware) PatchChannel(ctx context.Context, token, id string, patch map[string]interface{}) (channel things.Channel, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method patch_channel for token %s and channel %s took %s to complete", token, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.PatchChannel(ctx, token, id, patch)
+}
+
+func (lm *loggingMiddleware) ViewChannel(ctx context.Context, token, id string) (channel things.Channel, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method view_channel for token %s and channel %s took %s to complete", token, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ViewChannel(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) Capabilities(ctx context.Context, token, kind, id string) (_ things.Capabilities, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method capabilities for token %s and %s %s took %s to complete", token, kind, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Capabilities(ctx, token, kind, id)
+}
+
+func (lm *loggingMiddleware) ListChannels(ctx context.Context, token string, offset, limit uint64, name string, metadata map[string]interface{}, tags things.TagFilter, cursor string) (_ things.ChannelsPage, err error) {
+	defer func(begin time.Time) {
+		nlog := ""
+		if name != "" {
+			nlog = fmt.Sprintf("with name %s ", name)
+		}
+		if len(metadata) > 0 {
+			nlog = fmt.Sprintf("%swith metadata %v ", nlog, metadata)
+		}
+		if len(tags.Tags) > 0 {
+			nlog = fmt.Sprintf("%swith tags %v ", nlog, tags.Tags)
+		}
+		if cursor != "" {
+			nlog = fmt.Sprintf("%safter cursor %s ", nlog, cursor)
+		}
+		message := fmt.Sprintf("Method list_channels %sfor token %s took %s to complete", nlog, token, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListChannels(ctx, token, offset, limit, name, metadata, tags, cursor)
+}
+
+func (lm *loggingMiddleware) CountChannels(ctx context.Context, token, name string, metadata map[string]interface{}, tags things.TagFilter) (count uint64, err error) {
+	defer func(begin time.Time) {
+		nlog := ""
+		if name != "" {
+			nlog = fmt.Sprintf("with name %s ", name)
+		}
+		if len(metadata) > 0 {
+			nlog = fmt.Sprintf("%swith metadata %v ", nlog, metadata)
+		}
+		if len(tags.Tags) > 0 {
+			nlog = fmt.Sprintf("%swith tags %v ", nlog, tags.Tags)
+		}
+		message := fmt.Sprintf("Method count_channels %sfor token %s took %s to complete", nlog, token, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.CountChannels(ctx, token, name, metadata, tags)
+}
+
+func (lm *loggingMiddleware) ListChannelsByThing(ctx context.Context, token, id string, offset, limit uint64) (_ things.ChannelsPage, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_channels_by_thing for thing %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s", message, err))
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListChannelsByThing(ctx, token, id, offset, limit)
+}
+
+func (lm *loggingMiddleware) TransferChannel(ctx context.Context, token, id, newOwner string, keepConns bool) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method transfer_channel for token %s and channel %s to %s took %s to complete", token, id, newOwner, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.TransferChannel(ctx, token, id, newOwner, keepConns)
+}
+
+func (lm *loggingMiddleware) RemoveChannel(ctx context.Context, token, id string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method remove_channel for token %s and channel %s took %s to complete", token, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RemoveChannel(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) ArchiveChannel(ctx context.Context, token, id string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method archive_channel for token %s and channel %s took %s to complete", token, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ArchiveChannel(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) UnarchiveChannel(ctx context.Context, token, id string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method unarchive_channel for token %s and channel %s took %s to complete", token, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.UnarchiveChannel(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) ArchiveChannels(ctx context.Context, token string, selector map[string]interface{}, tags things.TagFilter) (archived []things.Channel, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method archive_channels for token %s archived %d channels and took %s to complete", token, len(archived), time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ArchiveChannels(ctx, token, selector, tags)
+}
+
+func (lm *loggingMiddleware) InviteToChannel(ctx context.Context, token, id string, ttl time.Duration) (inv things.Invitation, signed string, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method invite_to_channel for token %s and channel %s took %s to complete", token, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.InviteToChannel(ctx, token, id, ttl)
+}
+
+func (lm *loggingMiddleware) IssueChannelToken(ctx context.Context, token, id string, scope things.KeyScope, ttl time.Duration) (_ things.ChannelToken, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method issue_channel_token for token %s, channel %s and scope %s took %s to complete", token, id, scope, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.IssueChannelToken(ctx, token, id, scope, ttl)
+}
+
+func (lm *loggingMiddleware) ListChannelTokens(ctx context.Context, token, id string) (_ []things.ChannelToken, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_channel_tokens for token %s and channel %s took %s to complete", token, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListChannelTokens(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) RevokeChannelToken(ctx context.Context, token, id, tokenID string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method revoke_channel_token for token %s, channel %s and channel token %s took %s to complete", token, id, tokenID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RevokeChannelToken(ctx, token, id, tokenID)
+}
+
+func (lm *loggingMiddleware) Connect(ctx context.Context, token string, chanIDs, thingIDs []string, access things.ConnectionAccess) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method connect for token %s, %d channels and %d things with %q access took %s to complete", token, len(chanIDs), len(thingIDs), access, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Connect(ctx, token, chanIDs, thingIDs, access)
+}
+
+func (lm *loggingMiddleware) Disconnect(ctx context.Context, token string, chanIDs, thingIDs []string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method disconnect for token %s, %d channels and %d things took %s to complete", token, len(chanIDs), len(thingIDs), time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Disconnect(ctx, token, chanIDs, thingIDs)
+}
+
+func (lm *loggingMiddleware) Export(ctx context.Context, token string) (snapshot things.Snapshot, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method export for token %s took %s to complete", token, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Export(ctx, token)
+}
+
+func (lm *loggingMiddleware) Import(ctx context.Context, token string, snapshot things.Snapshot, preserve bool) (imported things.Snapshot, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method import for token %s, %d things and %d channels took %s to complete", token, len(snapshot.Things), len(snapshot.Channels), time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Import(ctx, token, snapshot, preserve)
+}
+
+func (lm *loggingMiddleware) CreateGroup(ctx context.Context, token string, group things.Group) (saved things.Group, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method create_group for token %s and group %s took %s to complete", token, saved.ID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.CreateGroup(ctx, token, group)
+}
+
+func (lm *loggingMiddleware) UpdateGroup(ctx context.Context, token string, group things.Group) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method update_group for token %s and group %s took %s to complete", token, group.ID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.UpdateGroup(ctx, token, group)
+}
+
+func (lm *loggingMiddleware) ViewGroup(ctx context.Context, token, id string) (_ things.Group, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method view_group for token %s and group %s took %s to complete", token, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ViewGroup(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) ListGroups(ctx context.Context, token, parent string, offset, limit uint64) (_ things.GroupsPage, err error) {
+	defer func(begin time.Time) {
+		plog := ""
+		if parent != "" {
+			plog = fmt.Sprintf("with parent %s ", parent)
+		}
+		message := fmt.Sprintf("Method list_groups %sfor token %s took %s to complete", plog, token, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListGroups(ctx, token, parent, offset, limit)
+}
+
+func (lm *loggingMiddleware) ListThingsByGroup(ctx context.Context, token, id string, offset, limit uint64) (_ things.ThingsPage, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_things_by_group for token %s and group %s took %s to complete", token, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListThingsByGroup(ctx, token, id, offset, limit)
+}
+
+func (lm *loggingMiddleware) RemoveGroup(ctx context.Context, token, id string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method remove_group for token %s and group %s took %s to complete", token, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RemoveGroup(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) AssignThings(ctx context.Context, token, id string, thingIDs ...string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method assign_things for token %s, group %s and %d things took %s to complete", token, id, len(thingIDs), time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.AssignThings(ctx, token, id, thingIDs...)
+}
+
+func (lm *loggingMiddleware) UnassignThing(ctx context.Context, token, id, thingID string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method unassign_thing for token %s, group %s and thing %s took %s to complete", token, id, thingID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.UnassignThing(ctx, token, id, thingID)
+}
+
+func (lm *loggingMiddleware) ConnectGroup(ctx context.Context, token, id, chanID string) (ids []string, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method connect_group for token %s, group %s and channel %s connected %d things and took %s to complete", token, id, chanID, len(ids), time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ConnectGroup(ctx, token, id, chanID)
+}
+
+func (lm *loggingMiddleware) CanAccess(ctx context.Context, id, key, action, subtopic string) (thing string, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method can_access for channel %s, subtopic %s, thing %s and action %s took %s to complete", id, subtopic, thing, action, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.CanAccess(ctx, id, key, action, subtopic)
+}
+
+func (lm *loggingMiddleware) Identify(ctx context.Context, key string) (id string, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method identify for key %s and thing %s took %s to complete", key, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Identify(ctx, key)
+}
+
+func (lm *loggingMiddleware) IdentifyBatch(ctx context.Context, keys []string) (ids []string, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method identify_batch for %d keys took %s to complete", len(keys), time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.IdentifyBatch(ctx, keys)
+}
+
+func (lm *loggingMiddleware) CanRead(ctx context.Context, id, token string) (user string, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method can_read for channel %s and user %s took %s to complete", id, user, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.CanRead(ctx, id, token)
+}
+
+func (lm *loggingMiddleware) ConnectedChannels(ctx context.Context, thingID string) (_ []string, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method connected_channels for thing %s took %s to complete", thingID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ConnectedChannels(ctx, thingID)
+}
+
+func (lm *loggingMiddleware) RemoveUserHandler(ctx context.Context, owner string) (ths []things.Thing, chs []things.Channel, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method remove_user_handler for user %s removed %d things and %d channels and took %s to complete", owner, len(ths), len(chs), time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RemoveUserHandler(ctx, owner)
+}
+
+func (lm *loggingMiddleware) RecordUsage(ctx context.Context, thingID, chanID string, size uint64) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method record_usage for thing %s and channel %s took %s to complete", thingID, chanID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RecordUsage(ctx, thingID, chanID, size)
+}
+
+func (lm *loggingMiddleware) RecordActivity(ctx context.Context, thingID string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method record_activity for thing %s took %s to complete", thingID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RecordActivity(ctx, thingID)
+}
+
+func (lm *loggingMiddleware) RecordDiagnostics(ctx context.Context, thingID string, payload []byte) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method record_diagnostics for thing %s took %s to complete", thingID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RecordDiagnostics(ctx, thingID, payload)
+}
+
+func (lm *loggingMiddleware) ViewHealth(ctx context.Context, token, id string) (_ things.Health, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method view_health for token %s and thing %s took %s to complete", token, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ViewHealth(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) ListUnhealthy(ctx context.Context, token string, thresholds things.HealthThresholds, offset, limit uint64) (_ things.HealthPage, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_unhealthy for token %s took %s to complete", token, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListUnhealthy(ctx, token, thresholds, offset, limit)
+}
+
+func (lm *loggingMiddleware) ThingUsage(ctx context.Context, token, id string, from, to time.Time) (usage things.Usage, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method thing_usage for token %s and thing %s took %s to complete", token, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ThingUsage(ctx, token, id, from, to)
+}
+
+func (lm *loggingMiddleware) ChannelUsage(ctx context.Context, token, id string, from, to time.Time) (usage things.Usage, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method channel_usage for token %s and channel %s took %s to complete", token, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ChannelUsage(ctx, token, id, from, to)
+}
+
+func (lm *loggingMiddleware) RegisterSubtopic(ctx context.Context, token, chanID string, subtopic things.Subtopic) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method register_subtopic for token %s, channel %s and subtopic %s took %s to complete", token, chanID, subtopic.Name, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RegisterSubtopic(ctx, token, chanID, subtopic)
+}
+
+func (lm *loggingMiddleware) ListSubtopics(ctx context.Context, token, chanID string) (_ []things.Subtopic, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_subtopics for token %s and channel %s took %s to complete", token, chanID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListSubtopics(ctx, token, chanID)
+}
+
+func (lm *loggingMiddleware) RemoveSubtopic(ctx context.Context, token, chanID, name string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method remove_subtopic for token %s, channel %s and subtopic %s took %s to complete", token, chanID, name, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RemoveSubtopic(ctx, token, chanID, name)
+}
+
+func (lm *loggingMiddleware) ObservedSubtopics(ctx context.Context, token, chanID string) (_ []things.ObservedSubtopic, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method observed_subtopics for token %s and channel %s took %s to complete", token, chanID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ObservedSubtopics(ctx, token, chanID)
+}
+
+func (lm *loggingMiddleware) RecordSubtopic(ctx context.Context, chanID, subtopic string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method record_subtopic for channel %s and subtopic %s took %s to complete", chanID, subtopic, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RecordSubtopic(ctx, chanID, subtopic)
+}
+
+func (lm *loggingMiddleware) AddSubtopicRule(ctx context.Context, token, chanID string, rule things.SubtopicRule) (saved things.SubtopicRule, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method add_subtopic_rule for token %s, channel %s and rule %s took %s to complete", token, chanID, saved.ID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.AddSubtopicRule(ctx, token, chanID, rule)
+}
+
+func (lm *loggingMiddleware) ListSubtopicRules(ctx context.Context, token, chanID string) (_ []things.SubtopicRule, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_subtopic_rules for token %s and channel %s took %s to complete", token, chanID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListSubtopicRules(ctx, token, chanID)
+}
+
+func (lm *loggingMiddleware) RemoveSubtopicRule(ctx context.Context, token, chanID, id string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method remove_subtopic_rule for token %s, channel %s and rule %s took %s to complete", token, chanID, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RemoveSubtopicRule(ctx, token, chanID, id)
+}
+
+func (lm *loggingMiddleware) Share(ctx context.Context, token string, share things.Share) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method share for token %s, %s %s and user %s took %s to complete", token, share.Kind, share.EntityID, share.User, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Share(ctx, token, share)
+}
+
+func (lm *loggingMiddleware) Unshare(ctx context.Context, token, kind, id, user string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method unshare for token %s, %s %s and user %s took %s to complete", token, kind, id, user, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Unshare(ctx, token, kind, id, user)
+}
+
+func (lm *loggingMiddleware) ListShares(ctx context.Context, token, kind, id string) (_ []things.Share, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_shares for token %s and %s %s took %s to complete", token, kind, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListShares(ctx, token, kind, id)
+}
+
+func (lm *loggingMiddleware) ThingHistory(ctx context.Context, token, id string, offset, limit uint64) (page things.ChangesPage, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method thing_history for token %s and thing %s took %s to complete", token, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ThingHistory(ctx, token, id, offset, limit)
+}
+
+func (lm *loggingMiddleware) ChannelHistory(ctx context.Context, token, id string, offset, limit uint64) (page things.ChangesPage, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method channel_history for token %s and channel %s took %s to complete", token, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ChannelHistory(ctx, token, id, offset, limit)
+}
+
+func (lm *loggingMiddleware) Audit(ctx context.Context, token string, query things.AuditQuery, offset, limit uint64) (page things.AuditPage, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method audit for token %s took %s to complete", token, time.Since(begin))
 		if err != nil {
 			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
 			return
@@ -253,5 +1238,5 @@ func (lm *loggingMiddleware) Identify(key string) (id string, err error) {
 		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
 	}(time.Now())
 
-	return lm.svc.Identify(key)
+	return lm.svc.Audit(ctx, token, query, offset, limit)
 }
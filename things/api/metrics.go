@@ -10,6 +10,7 @@
 package api
 
 import (
+	"context"
 	"time"
 
 	"github.com/go-kit/kit/metrics"
@@ -34,155 +35,803 @@ func MetricsMiddleware(svc things.Service, counter metrics.Counter, latency metr
 	}
 }
 
-func (ms *metricsMiddleware) AddThing(token string, thing things.Thing) (things.Thing, error) {
+func (ms *metricsMiddleware) AddThing(ctx context.Context, token string, thing things.Thing) (things.Thing, error) {
 	defer func(begin time.Time) {
 		ms.counter.With("method", "add_thing").Add(1)
 		ms.latency.With("method", "add_thing").Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
-	return ms.svc.AddThing(token, thing)
+	return ms.svc.AddThing(ctx, token, thing)
 }
 
-func (ms *metricsMiddleware) UpdateThing(token string, thing things.Thing) error {
+func (ms *metricsMiddleware) CreateThings(ctx context.Context, token string, ths ...things.Thing) ([]things.Thing, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "create_things").Add(1)
+		ms.latency.With("method", "create_things").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.CreateThings(ctx, token, ths...)
+}
+
+func (ms *metricsMiddleware) ProvisionThing(ctx context.Context, token, profileID string, thing things.Thing) (things.Topology, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "provision_thing").Add(1)
+		ms.latency.With("method", "provision_thing").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ProvisionThing(ctx, token, profileID, thing)
+}
+
+func (ms *metricsMiddleware) SaveTemplate(ctx context.Context, token string, tmpl things.Template) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "save_template").Add(1)
+		ms.latency.With("method", "save_template").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.SaveTemplate(ctx, token, tmpl)
+}
+
+func (ms *metricsMiddleware) ViewTemplate(ctx context.Context, token string) (things.Template, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_template").Add(1)
+		ms.latency.With("method", "view_template").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewTemplate(ctx, token)
+}
+
+func (ms *metricsMiddleware) RemoveTemplate(ctx context.Context, token string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "remove_template").Add(1)
+		ms.latency.With("method", "remove_template").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RemoveTemplate(ctx, token)
+}
+
+func (ms *metricsMiddleware) CreateProfile(ctx context.Context, token string, profile things.Profile) (things.Profile, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "create_profile").Add(1)
+		ms.latency.With("method", "create_profile").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.CreateProfile(ctx, token, profile)
+}
+
+func (ms *metricsMiddleware) UpdateProfile(ctx context.Context, token string, profile things.Profile) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "update_profile").Add(1)
+		ms.latency.With("method", "update_profile").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.UpdateProfile(ctx, token, profile)
+}
+
+func (ms *metricsMiddleware) ViewProfile(ctx context.Context, token, id string) (things.Profile, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_profile").Add(1)
+		ms.latency.With("method", "view_profile").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewProfile(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) ListProfiles(ctx context.Context, token string, offset, limit uint64) (things.ProfilesPage, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_profiles").Add(1)
+		ms.latency.With("method", "list_profiles").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListProfiles(ctx, token, offset, limit)
+}
+
+func (ms *metricsMiddleware) RemoveProfile(ctx context.Context, token, id string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "remove_profile").Add(1)
+		ms.latency.With("method", "remove_profile").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RemoveProfile(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) ReserveThings(ctx context.Context, token string, n uint64) ([]things.Reservation, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "reserve_things").Add(1)
+		ms.latency.With("method", "reserve_things").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ReserveThings(ctx, token, n)
+}
+
+func (ms *metricsMiddleware) UpdateThing(ctx context.Context, token string, thing things.Thing) error {
 	defer func(begin time.Time) {
 		ms.counter.With("method", "update_thing").Add(1)
 		ms.latency.With("method", "update_thing").Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
-	return ms.svc.UpdateThing(token, thing)
+	return ms.svc.UpdateThing(ctx, token, thing)
 }
 
-func (ms *metricsMiddleware) UpdateKey(token, id, key string) error {
+func (ms *metricsMiddleware) UpdateKey(ctx context.Context, token, id, key string, expiresAt time.Time, rotation time.Duration) error {
 	defer func(begin time.Time) {
 		ms.counter.With("method", "update_key").Add(1)
 		ms.latency.With("method", "update_key").Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
-	return ms.svc.UpdateKey(token, id, key)
+	return ms.svc.UpdateKey(ctx, token, id, key, expiresAt, rotation)
+}
+
+func (ms *metricsMiddleware) RotateKeys(ctx context.Context, since time.Time) ([]things.Thing, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "rotate_keys").Add(1)
+		ms.latency.With("method", "rotate_keys").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RotateKeys(ctx, since)
+}
+
+func (ms *metricsMiddleware) PatchThing(ctx context.Context, token, id string, patch map[string]interface{}) (things.Thing, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "patch_thing").Add(1)
+		ms.latency.With("method", "patch_thing").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.PatchThing(ctx, token, id, patch)
+}
+
+func (ms *metricsMiddleware) UpdateThingsMetadata(ctx context.Context, token string, selector, patch map[string]interface{}) ([]things.Thing, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "update_things_metadata").Add(1)
+		ms.latency.With("method", "update_things_metadata").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.UpdateThingsMetadata(ctx, token, selector, patch)
+}
+
+func (ms *metricsMiddleware) OnboardThing(ctx context.Context, token, id string) (things.Onboarding, string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "onboard_thing").Add(1)
+		ms.latency.With("method", "onboard_thing").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.OnboardThing(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) IssueKey(ctx context.Context, token, id string, ttl time.Duration) (things.SignedKey, string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "issue_key").Add(1)
+		ms.latency.With("method", "issue_key").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.IssueKey(ctx, token, id, ttl)
+}
+
+func (ms *metricsMiddleware) OpenSession(ctx context.Context, key string, ttl time.Duration) (things.SignedKey, string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "open_session").Add(1)
+		ms.latency.With("method", "open_session").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.OpenSession(ctx, key, ttl)
+}
+
+func (ms *metricsMiddleware) RevokeKeys(ctx context.Context, token, id string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "revoke_keys").Add(1)
+		ms.latency.With("method", "revoke_keys").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RevokeKeys(ctx, token, id)
 }
 
-func (ms *metricsMiddleware) ViewThing(token, id string) (things.Thing, error) {
+func (ms *metricsMiddleware) IssueThingKey(ctx context.Context, token, id string, scope things.KeyScope) (things.ThingKey, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "issue_thing_key").Add(1)
+		ms.latency.With("method", "issue_thing_key").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.IssueThingKey(ctx, token, id, scope)
+}
+
+func (ms *metricsMiddleware) ListThingKeys(ctx context.Context, token, id string) ([]things.ThingKey, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_thing_keys").Add(1)
+		ms.latency.With("method", "list_thing_keys").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListThingKeys(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) RevokeThingKey(ctx context.Context, token, id, keyID string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "revoke_thing_key").Add(1)
+		ms.latency.With("method", "revoke_thing_key").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RevokeThingKey(ctx, token, id, keyID)
+}
+
+func (ms *metricsMiddleware) ViewThing(ctx context.Context, token, id string) (things.Thing, error) {
 	defer func(begin time.Time) {
 		ms.counter.With("method", "view_thing").Add(1)
 		ms.latency.With("method", "view_thing").Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
-	return ms.svc.ViewThing(token, id)
+	return ms.svc.ViewThing(ctx, token, id)
 }
 
-func (ms *metricsMiddleware) ListThings(token string, offset, limit uint64, name string) (things.ThingsPage, error) {
+func (ms *metricsMiddleware) ListThings(ctx context.Context, token string, offset, limit uint64, name string, metadata map[string]interface{}, tags things.TagFilter, status things.Status, cursor string) (things.ThingsPage, error) {
 	defer func(begin time.Time) {
 		ms.counter.With("method", "list_things").Add(1)
 		ms.latency.With("method", "list_things").Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
-	return ms.svc.ListThings(token, offset, limit, name)
+	return ms.svc.ListThings(ctx, token, offset, limit, name, metadata, tags, status, cursor)
 }
 
-func (ms *metricsMiddleware) ListThingsByChannel(token, id string, offset, limit uint64) (things.ThingsPage, error) {
+func (ms *metricsMiddleware) CountThings(ctx context.Context, token, name string, metadata map[string]interface{}, tags things.TagFilter, status things.Status) (uint64, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "count_things").Add(1)
+		ms.latency.With("method", "count_things").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.CountThings(ctx, token, name, metadata, tags, status)
+}
+
+func (ms *metricsMiddleware) ListThingsByChannel(ctx context.Context, token, id string, offset, limit uint64) (things.ThingsPage, error) {
 	defer func(begin time.Time) {
 		ms.counter.With("method", "list_things_by_channel").Add(1)
 		ms.latency.With("method", "list_things_by_channel").Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
-	return ms.svc.ListThingsByChannel(token, id, offset, limit)
+	return ms.svc.ListThingsByChannel(ctx, token, id, offset, limit)
 }
 
-func (ms *metricsMiddleware) RemoveThing(token, id string) error {
+func (ms *metricsMiddleware) EnableThing(ctx context.Context, token, id string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "enable_thing").Add(1)
+		ms.latency.With("method", "enable_thing").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.EnableThing(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) DisableThing(ctx context.Context, token, id string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "disable_thing").Add(1)
+		ms.latency.With("method", "disable_thing").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.DisableThing(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) TransferThing(ctx context.Context, token, id, newOwner string, keepConns bool) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "transfer_thing").Add(1)
+		ms.latency.With("method", "transfer_thing").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.TransferThing(ctx, token, id, newOwner, keepConns)
+}
+
+func (ms *metricsMiddleware) RemoveThing(ctx context.Context, token, id string) error {
 	defer func(begin time.Time) {
 		ms.counter.With("method", "remove_thing").Add(1)
 		ms.latency.With("method", "remove_thing").Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
-	return ms.svc.RemoveThing(token, id)
+	return ms.svc.RemoveThing(ctx, token, id)
 }
 
-func (ms *metricsMiddleware) CreateChannel(token string, channel things.Channel) (things.Channel, error) {
+func (ms *metricsMiddleware) CreateChannel(ctx context.Context, token string, channel things.Channel) (things.Channel, error) {
 	defer func(begin time.Time) {
 		ms.counter.With("method", "create_channel").Add(1)
 		ms.latency.With("method", "create_channel").Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
-	return ms.svc.CreateChannel(token, channel)
+	return ms.svc.CreateChannel(ctx, token, channel)
 }
 
-func (ms *metricsMiddleware) UpdateChannel(token string, channel things.Channel) error {
+func (ms *metricsMiddleware) UpdateChannel(ctx context.Context, token string, channel things.Channel) error {
 	defer func(begin time.Time) {
 		ms.counter.With("method", "update_channel").Add(1)
 		ms.latency.With("method", "update_channel").Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
-	return ms.svc.UpdateChannel(token, channel)
+	return ms.svc.UpdateChannel(ctx, token, channel)
+}
+
+func (ms *metricsMiddleware) PatchChannel(ctx context.Context, token, id string, patch map[string]interface{}) (things.Channel, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "patch_channel").Add(1)
+		ms.latency.With("method", "patch_channel").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.PatchChannel(ctx, token, id, patch)
 }
 
-func (ms *metricsMiddleware) ViewChannel(token, id string) (things.Channel, error) {
+func (ms *metricsMiddleware) ViewChannel(ctx context.Context, token, id string) (things.Channel, error) {
 	defer func(begin time.Time) {
 		ms.counter.With("method", "view_channel").Add(1)
 		ms.latency.With("method", "view_channel").Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
-	return ms.svc.ViewChannel(token, id)
+	return ms.svc.ViewChannel(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) Capabilities(ctx context.Context, token, kind, id string) (things.Capabilities, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "capabilities").Add(1)
+		ms.latency.With("method", "capabilities").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Capabilities(ctx, token, kind, id)
 }
 
-func (ms *metricsMiddleware) ListChannels(token string, offset, limit uint64, name string) (things.ChannelsPage, error) {
+func (ms *metricsMiddleware) ListChannels(ctx context.Context, token string, offset, limit uint64, name string, metadata map[string]interface{}, tags things.TagFilter, cursor string) (things.ChannelsPage, error) {
 	defer func(begin time.Time) {
 		ms.counter.With("method", "list_channels").Add(1)
 		ms.latency.With("method", "list_channels").Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
-	return ms.svc.ListChannels(token, offset, limit, name)
+	return ms.svc.ListChannels(ctx, token, offset, limit, name, metadata, tags, cursor)
 }
 
-func (ms *metricsMiddleware) ListChannelsByThing(token, id string, offset, limit uint64) (things.ChannelsPage, error) {
+func (ms *metricsMiddleware) CountChannels(ctx context.Context, token, name string, metadata map[string]interface{}, tags things.TagFilter) (uint64, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "count_channels").Add(1)
+		ms.latency.With("method", "count_channels").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.CountChannels(ctx, token, name, metadata, tags)
+}
+
+func (ms *metricsMiddleware) ListChannelsByThing(ctx context.Context, token, id string, offset, limit uint64) (things.ChannelsPage, error) {
 	defer func(begin time.Time) {
 		ms.counter.With("method", "list_channels_by_thing").Add(1)
 		ms.latency.With("method", "list_channels_by_thing").Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
-	return ms.svc.ListChannelsByThing(token, id, offset, limit)
+	return ms.svc.ListChannelsByThing(ctx, token, id, offset, limit)
+}
+
+func (ms *metricsMiddleware) TransferChannel(ctx context.Context, token, id, newOwner string, keepConns bool) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "transfer_channel").Add(1)
+		ms.latency.With("method", "transfer_channel").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.TransferChannel(ctx, token, id, newOwner, keepConns)
 }
 
-func (ms *metricsMiddleware) RemoveChannel(token, id string) error {
+func (ms *metricsMiddleware) RemoveChannel(ctx context.Context, token, id string) error {
 	defer func(begin time.Time) {
 		ms.counter.With("method", "remove_channel").Add(1)
 		ms.latency.With("method", "remove_channel").Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
-	return ms.svc.RemoveChannel(token, id)
+	return ms.svc.RemoveChannel(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) ArchiveChannel(ctx context.Context, token, id string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "archive_channel").Add(1)
+		ms.latency.With("method", "archive_channel").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ArchiveChannel(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) UnarchiveChannel(ctx context.Context, token, id string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "unarchive_channel").Add(1)
+		ms.latency.With("method", "unarchive_channel").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.UnarchiveChannel(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) ArchiveChannels(ctx context.Context, token string, selector map[string]interface{}, tags things.TagFilter) ([]things.Channel, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "archive_channels").Add(1)
+		ms.latency.With("method", "archive_channels").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ArchiveChannels(ctx, token, selector, tags)
+}
+
+func (ms *metricsMiddleware) InviteToChannel(ctx context.Context, token, id string, ttl time.Duration) (things.Invitation, string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "invite_to_channel").Add(1)
+		ms.latency.With("method", "invite_to_channel").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.InviteToChannel(ctx, token, id, ttl)
+}
+
+func (ms *metricsMiddleware) IssueChannelToken(ctx context.Context, token, id string, scope things.KeyScope, ttl time.Duration) (things.ChannelToken, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "issue_channel_token").Add(1)
+		ms.latency.With("method", "issue_channel_token").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.IssueChannelToken(ctx, token, id, scope, ttl)
+}
+
+func (ms *metricsMiddleware) ListChannelTokens(ctx context.Context, token, id string) ([]things.ChannelToken, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_channel_tokens").Add(1)
+		ms.latency.With("method", "list_channel_tokens").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListChannelTokens(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) RevokeChannelToken(ctx context.Context, token, id, tokenID string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "revoke_channel_token").Add(1)
+		ms.latency.With("method", "revoke_channel_token").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RevokeChannelToken(ctx, token, id, tokenID)
 }
 
-func (ms *metricsMiddleware) Connect(token, chanID, thingID string) error {
+func (ms *metricsMiddleware) Connect(ctx context.Context, token string, chanIDs, thingIDs []string, access things.ConnectionAccess) error {
 	defer func(begin time.Time) {
 		ms.counter.With("method", "connect").Add(1)
 		ms.latency.With("method", "connect").Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
-	return ms.svc.Connect(token, chanID, thingID)
+	return ms.svc.Connect(ctx, token, chanIDs, thingIDs, access)
 }
 
-func (ms *metricsMiddleware) Disconnect(token, chanID, thingID string) error {
+func (ms *metricsMiddleware) Disconnect(ctx context.Context, token string, chanIDs, thingIDs []string) error {
 	defer func(begin time.Time) {
 		ms.counter.With("method", "disconnect").Add(1)
 		ms.latency.With("method", "disconnect").Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
-	return ms.svc.Disconnect(token, chanID, thingID)
+	return ms.svc.Disconnect(ctx, token, chanIDs, thingIDs)
 }
 
-func (ms *metricsMiddleware) CanAccess(id, key string) (string, error) {
+func (ms *metricsMiddleware) Export(ctx context.Context, token string) (things.Snapshot, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "export").Add(1)
+		ms.latency.With("method", "export").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Export(ctx, token)
+}
+
+func (ms *metricsMiddleware) Import(ctx context.Context, token string, snapshot things.Snapshot, preserve bool) (things.Snapshot, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "import").Add(1)
+		ms.latency.With("method", "import").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Import(ctx, token, snapshot, preserve)
+}
+
+func (ms *metricsMiddleware) CreateGroup(ctx context.Context, token string, group things.Group) (things.Group, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "create_group").Add(1)
+		ms.latency.With("method", "create_group").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.CreateGroup(ctx, token, group)
+}
+
+func (ms *metricsMiddleware) UpdateGroup(ctx context.Context, token string, group things.Group) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "update_group").Add(1)
+		ms.latency.With("method", "update_group").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.UpdateGroup(ctx, token, group)
+}
+
+func (ms *metricsMiddleware) ViewGroup(ctx context.Context, token, id string) (things.Group, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_group").Add(1)
+		ms.latency.With("method", "view_group").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewGroup(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) ListGroups(ctx context.Context, token, parent string, offset, limit uint64) (things.GroupsPage, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_groups").Add(1)
+		ms.latency.With("method", "list_groups").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListGroups(ctx, token, parent, offset, limit)
+}
+
+func (ms *metricsMiddleware) ListThingsByGroup(ctx context.Context, token, id string, offset, limit uint64) (things.ThingsPage, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_things_by_group").Add(1)
+		ms.latency.With("method", "list_things_by_group").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListThingsByGroup(ctx, token, id, offset, limit)
+}
+
+func (ms *metricsMiddleware) RemoveGroup(ctx context.Context, token, id string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "remove_group").Add(1)
+		ms.latency.With("method", "remove_group").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RemoveGroup(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) AssignThings(ctx context.Context, token, id string, thingIDs ...string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "assign_things").Add(1)
+		ms.latency.With("method", "assign_things").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.AssignThings(ctx, token, id, thingIDs...)
+}
+
+func (ms *metricsMiddleware) UnassignThing(ctx context.Context, token, id, thingID string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "unassign_thing").Add(1)
+		ms.latency.With("method", "unassign_thing").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.UnassignThing(ctx, token, id, thingID)
+}
+
+func (ms *metricsMiddleware) ConnectGroup(ctx context.Context, token, id, chanID string) ([]string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "connect_group").Add(1)
+		ms.latency.With("method", "connect_group").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ConnectGroup(ctx, token, id, chanID)
+}
+
+func (ms *metricsMiddleware) CanAccess(ctx context.Context, id, key, action, subtopic string) (string, error) {
 	defer func(begin time.Time) {
 		ms.counter.With("method", "can_access").Add(1)
 		ms.latency.With("method", "can_access").Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
-	return ms.svc.CanAccess(id, key)
+	return ms.svc.CanAccess(ctx, id, key, action, subtopic)
 }
 
-func (ms *metricsMiddleware) Identify(key string) (string, error) {
+func (ms *metricsMiddleware) Identify(ctx context.Context, key string) (string, error) {
 	defer func(begin time.Time) {
 		ms.counter.With("method", "identify").Add(1)
 		ms.latency.With("method", "identify").Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
-	return ms.svc.Identify(key)
+	return ms.svc.Identify(ctx, key)
+}
+
+func (ms *metricsMiddleware) IdentifyBatch(ctx context.Context, keys []string) ([]string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "identify_batch").Add(1)
+		ms.latency.With("method", "identify_batch").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.IdentifyBatch(ctx, keys)
+}
+
+func (ms *metricsMiddleware) CanRead(ctx context.Context, id, token string) (string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "can_read").Add(1)
+		ms.latency.With("method", "can_read").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.CanRead(ctx, id, token)
+}
+
+func (ms *metricsMiddleware) ConnectedChannels(ctx context.Context, thingID string) ([]string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "connected_channels").Add(1)
+		ms.latency.With("method", "connected_channels").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ConnectedChannels(ctx, thingID)
+}
+
+func (ms *metricsMiddleware) RemoveUserHandler(ctx context.Context, owner string) ([]things.Thing, []things.Channel, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "remove_user_handler").Add(1)
+		ms.latency.With("method", "remove_user_handler").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RemoveUserHandler(ctx, owner)
+}
+
+func (ms *metricsMiddleware) RecordUsage(ctx context.Context, thingID, chanID string, size uint64) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "record_usage").Add(1)
+		ms.latency.With("method", "record_usage").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RecordUsage(ctx, thingID, chanID, size)
+}
+
+func (ms *metricsMiddleware) RecordActivity(ctx context.Context, thingID string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "record_activity").Add(1)
+		ms.latency.With("method", "record_activity").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RecordActivity(ctx, thingID)
+}
+
+func (ms *metricsMiddleware) RecordDiagnostics(ctx context.Context, thingID string, payload []byte) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "record_diagnostics").Add(1)
+		ms.latency.With("method", "record_diagnostics").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RecordDiagnostics(ctx, thingID, payload)
+}
+
+func (ms *metricsMiddleware) ViewHealth(ctx context.Context, token, id string) (things.Health, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_health").Add(1)
+		ms.latency.With("method", "view_health").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewHealth(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) ListUnhealthy(ctx context.Context, token string, thresholds things.HealthThresholds, offset, limit uint64) (things.HealthPage, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_unhealthy").Add(1)
+		ms.latency.With("method", "list_unhealthy").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListUnhealthy(ctx, token, thresholds, offset, limit)
+}
+
+func (ms *metricsMiddleware) ThingUsage(ctx context.Context, token, id string, from, to time.Time) (things.Usage, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "thing_usage").Add(1)
+		ms.latency.With("method", "thing_usage").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ThingUsage(ctx, token, id, from, to)
+}
+
+func (ms *metricsMiddleware) ChannelUsage(ctx context.Context, token, id string, from, to time.Time) (things.Usage, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "channel_usage").Add(1)
+		ms.latency.With("method", "channel_usage").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ChannelUsage(ctx, token, id, from, to)
+}
+
+func (ms *metricsMiddleware) RegisterSubtopic(ctx context.Context, token, chanID string, subtopic things.Subtopic) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "register_subtopic").Add(1)
+		ms.latency.With("method", "register_subtopic").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RegisterSubtopic(ctx, token, chanID, subtopic)
+}
+
+func (ms *metricsMiddleware) ListSubtopics(ctx context.Context, token, chanID string) ([]things.Subtopic, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_subtopics").Add(1)
+		ms.latency.With("method", "list_subtopics").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListSubtopics(ctx, token, chanID)
+}
+
+func (ms *metricsMiddleware) RemoveSubtopic(ctx context.Context, token, chanID, name string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "remove_subtopic").Add(1)
+		ms.latency.With("method", "remove_subtopic").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RemoveSubtopic(ctx, token, chanID, name)
+}
+
+func (ms *metricsMiddleware) ObservedSubtopics(ctx context.Context, token, chanID string) ([]things.ObservedSubtopic, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "observed_subtopics").Add(1)
+		ms.latency.With("method", "observed_subtopics").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ObservedSubtopics(ctx, token, chanID)
+}
+
+func (ms *metricsMiddleware) RecordSubtopic(ctx context.Context, chanID, subtopic string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "record_subtopic").Add(1)
+		ms.latency.With("method", "record_subtopic").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RecordSubtopic(ctx, chanID, subtopic)
+}
+
+func (ms *metricsMiddleware) AddSubtopicRule(ctx context.Context, token, chanID string, rule things.SubtopicRule) (things.SubtopicRule, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "add_subtopic_rule").Add(1)
+		ms.latency.With("method", "add_subtopic_rule").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.AddSubtopicRule(ctx, token, chanID, rule)
+}
+
+func (ms *metricsMiddleware) ListSubtopicRules(ctx context.Context, token, chanID string) ([]things.SubtopicRule, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_subtopic_rules").Add(1)
+		ms.latency.With("method", "list_subtopic_rules").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListSubtopicRules(ctx, token, chanID)
+}
+
+func (ms *metricsMiddleware) RemoveSubtopicRule(ctx context.Context, token, chanID, id string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "remove_subtopic_rule").Add(1)
+		ms.latency.With("method", "remove_subtopic_rule").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RemoveSubtopicRule(ctx, token, chanID, id)
+}
+
+func (ms *metricsMiddleware) Share(ctx context.Context, token string, share things.Share) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "share").Add(1)
+		ms.latency.With("method", "share").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Share(ctx, token, share)
+}
+
+func (ms *metricsMiddleware) Unshare(ctx context.Context, token, kind, id, user string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "unshare").Add(1)
+		ms.latency.With("method", "unshare").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Unshare(ctx, token, kind, id, user)
+}
+
+func (ms *metricsMiddleware) ListShares(ctx context.Context, token, kind, id string) ([]things.Share, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_shares").Add(1)
+		ms.latency.With("method", "list_shares").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListShares(ctx, token, kind, id)
+}
+
+func (ms *metricsMiddleware) ThingHistory(ctx context.Context, token, id string, offset, limit uint64) (things.ChangesPage, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "thing_history").Add(1)
+		ms.latency.With("method", "thing_history").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ThingHistory(ctx, token, id, offset, limit)
+}
+
+func (ms *metricsMiddleware) ChannelHistory(ctx context.Context, token, id string, offset, limit uint64) (things.ChangesPage, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "channel_history").Add(1)
+		ms.latency.With("method", "channel_history").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ChannelHistory(ctx, token, id, offset, limit)
+}
+
+func (ms *metricsMiddleware) Audit(ctx context.Context, token string, query things.AuditQuery, offset, limit uint64) (things.AuditPage, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "audit").Add(1)
+		ms.latency.With("method", "audit").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Audit(ctx, token, query, offset, limit)
 }
@@ -0,0 +1,197 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package things
+
+import (
+	"context"
+	"time"
+
+	"github.com/mainflux/mainflux"
+)
+
+// Operations recorded in the audit trail.
+const (
+	CreateOperation     = "create"
+	UpdateOperation     = "update"
+	UpdateKeyOperation  = "update_key"
+	RemoveOperation     = "remove"
+	ConnectOperation    = "connect"
+	DisconnectOperation = "disconnect"
+	TransferOperation   = "transfer"
+)
+
+const (
+	channelField = "channel"
+	ownerField   = "owner"
+)
+
+// Diff represents the value of the entity field before and after the
+// audited operation. Values of the thing key are never recorded.
+type Diff struct {
+	Before string
+	After  string
+}
+
+// AuditEvent represents the operation the actor performed on the thing or
+// the channel belonging to the owner. Connections are audited as the
+// operations on the connected things, the channel being recorded as the
+// changed field.
+type AuditEvent struct {
+	Owner      string
+	Actor      string
+	Operation  string
+	EntityKind string
+	EntityID   string
+	Diff       map[string]Diff
+	OccurredAt time.Time
+}
+
+// AuditQuery represents the filter of the audit events. Empty fields match
+// all the events.
+type AuditQuery struct {
+	Actor      string
+	Operation  string
+	EntityKind string
+	EntityID   string
+	From       time.Time
+	To         time.Time
+}
+
+// Validate returns an error if the query refers to unknown entity kind or
+// operation, or if its time period is inverted.
+func (q AuditQuery) Validate() error {
+	switch q.EntityKind {
+	case "", ThingKind, ChannelKind:
+	default:
+		return ErrMalformedEntity
+	}
+
+	switch q.Operation {
+	case "", CreateOperation, UpdateOperation, UpdateKeyOperation, RemoveOperation, ConnectOperation, DisconnectOperation, TransferOperation:
+	default:
+		return ErrMalformedEntity
+	}
+
+	if !q.From.IsZero() && !q.To.IsZero() && q.To.Before(q.From) {
+		return ErrMalformedEntity
+	}
+
+	return nil
+}
+
+// AuditPage contains page related metadata as well as list of audit events
+// that belong to this page.
+type AuditPage struct {
+	PageMetadata
+	Events []AuditEvent
+}
+
+// AuditRepository specifies an audit trail persistence API.
+type AuditRepository interface {
+	// Save persists the audit events. A non-nil error is returned to
+	// indicate operation failure.
+	Save(context.Context, ...AuditEvent) error
+
+	// RetrieveAll retrieves the subset of events of the entities owned by
+	// the specified user, that match the query, ordered from the most
+	// recent one.
+	RetrieveAll(context.Context, string, AuditQuery, uint64, uint64) (AuditPage, error)
+}
+
+// auditActor returns the identifier of the user recorded as the actor of
+// the audited operation, i.e. the administrator acting on behalf of the
+// identified user, if any, or the user itself.
+func auditActor(id *mainflux.UserID) string {
+	if actor := id.GetActor(); actor != "" {
+		return actor
+	}
+
+	return id.GetValue()
+}
+
+func thingEvent(op, actor string, old, new Thing, at time.Time) AuditEvent {
+	return AuditEvent{
+		Owner:      new.Owner,
+		Actor:      actor,
+		Operation:  op,
+		EntityKind: ThingKind,
+		EntityID:   new.ID,
+		Diff:       changesDiff(ThingChanges(old, new, at)),
+		OccurredAt: at,
+	}
+}
+
+func channelEvent(op, actor string, old, new Channel, at time.Time) AuditEvent {
+	return AuditEvent{
+		Owner:      new.Owner,
+		Actor:      actor,
+		Operation:  op,
+		EntityKind: ChannelKind,
+		EntityID:   new.ID,
+		Diff:       changesDiff(ChannelChanges(old, new, at)),
+		OccurredAt: at,
+	}
+}
+
+func connectionEvent(op, actor string, conn Connection, at time.Time) AuditEvent {
+	diff := Diff{After: conn.ChanID}
+	if op == DisconnectOperation {
+		diff = Diff{Before: conn.ChanID}
+	}
+
+	return AuditEvent{
+		Owner:      conn.Owner,
+		Actor:      actor,
+		Operation:  op,
+		EntityKind: ThingKind,
+		EntityID:   conn.ThingID,
+		Diff:       map[string]Diff{channelField: diff},
+		OccurredAt: at,
+	}
+}
+
+func connectionEvents(op, actor string, conns []Connection) []AuditEvent {
+	now := time.Now()
+	events := make([]AuditEvent, len(conns))
+	for i, conn := range conns {
+		events[i] = connectionEvent(op, actor, conn, now)
+	}
+
+	return events
+}
+
+// transferEvents records the transfer in the audit trail of both the previous
+// and the new owner.
+func transferEvents(kind, actor, id, owner, newOwner string) []AuditEvent {
+	now := time.Now()
+	diff := map[string]Diff{ownerField: {Before: owner, After: newOwner}}
+
+	events := []AuditEvent{}
+	for _, o := range []string{owner, newOwner} {
+		events = append(events, AuditEvent{
+			Owner:      o,
+			Actor:      actor,
+			Operation:  TransferOperation,
+			EntityKind: kind,
+			EntityID:   id,
+			Diff:       diff,
+			OccurredAt: now,
+		})
+	}
+
+	return events
+}
+
+func changesDiff(changes []Change) map[string]Diff {
+	diff := map[string]Diff{}
+	for _, c := range changes {
+		diff[c.Field] = Diff{Before: c.OldValue, After: c.NewValue}
+	}
+
+	return diff
+}
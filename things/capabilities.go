@@ -0,0 +1,67 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package things
+
+// Operations reported as capabilities, on top of the audited ones.
+const (
+	ViewOperation  = "view"
+	ShareOperation = "share"
+)
+
+var (
+	// accountOperations are allowed to every identified user.
+	accountOperations = []string{CreateOperation, ViewOperation}
+
+	// readOperations are allowed on the entities shared for reading.
+	readOperations = []string{ViewOperation}
+
+	// manageOperations are allowed on the entities shared for managing.
+	manageOperations = []string{ViewOperation, UpdateOperation, ConnectOperation, DisconnectOperation}
+
+	// ownerOperations are allowed on the owned entities, the thing key
+	// update being allowed only on the things.
+	ownerOperations = []string{ViewOperation, UpdateOperation, ConnectOperation, DisconnectOperation, RemoveOperation, ShareOperation, TransferOperation}
+)
+
+// Capabilities represents the operations the user may perform on the thing
+// or the channel, or on their account if neither is set, so that the clients
+// can hide or warn on the forbidden ones.
+type Capabilities struct {
+	Kind       string
+	EntityID   string
+	Operations []string
+}
+
+// ownerCapabilities returns the capabilities of the owner of the entity of
+// the given kind.
+func ownerCapabilities(kind, id string) Capabilities {
+	ops := append([]string{}, ownerOperations...)
+	if kind == ThingKind {
+		ops = append(ops, UpdateKeyOperation)
+	}
+
+	return Capabilities{
+		Kind:       kind,
+		EntityID:   id,
+		Operations: ops,
+	}
+}
+
+// sharedCapabilities returns the capabilities granted by the share.
+func sharedCapabilities(share Share) Capabilities {
+	ops := readOperations
+	if share.Permission == ManagePermission {
+		ops = manageOperations
+	}
+
+	return Capabilities{
+		Kind:       share.Kind,
+		EntityID:   share.EntityID,
+		Operations: append([]string{}, ops...),
+	}
+}
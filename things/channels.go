@@ -7,20 +7,50 @@
 
 package things
 
+import "context"
+
+const (
+	// Active is the state of the channel accepting messages.
+	Active State = "active"
+
+	// Archived is the state of the decommissioned channel that rejects new
+	// messages, while the messages stored so far remain readable.
+	Archived State = "archived"
+)
+
 // Channel represents a Mainflux "communication group". This group contains the
 // things that can exchange messages between eachother.
 type Channel struct {
 	ID       string
 	Owner    string
 	Name     string
+	State    State
+	Tags     []string
 	Metadata map[string]interface{}
 }
 
 // ChannelsPage contains page related metadata as well as list of channels that
-// belong to this page.
+// belong to this page. Cursor points past the last channel of the page,
+// unless it's the last page.
 type ChannelsPage struct {
 	PageMetadata
 	Channels []Channel
+	Cursor   string
+}
+
+// Validate returns an error if channel representation is invalid, i.e. if
+// it exceeds the provided limits, holds an empty or too long tag, or its
+// metadata holds a malformed adapter section.
+func (c *Channel) Validate(limits Limits) error {
+	if err := limits.validate(c.Name, "", c.Metadata); err != nil {
+		return err
+	}
+
+	if err := validateTags(c.Tags); err != nil {
+		return err
+	}
+
+	return validateChannelMetadata(c.Metadata)
 }
 
 // ChannelRepository specifies a channel persistence API.
@@ -28,51 +58,144 @@ type ChannelRepository interface {
 	// Save persists the channel. Successful operation is indicated by unique
 	// identifier accompanied by nil error response. A non-nil error is
 	// returned to indicate operation failure.
-	Save(Channel) (string, error)
+	Save(context.Context, Channel) (string, error)
 
 	// Update performs an update to the existing channel. A non-nil error is
 	// returned to indicate operation failure.
-	Update(Channel) error
+	Update(context.Context, Channel) error
+
+	// Patch applies the JSON merge patch to the channel having the provided
+	// identifier, that is owned by the specified user, and returns the
+	// patched channel.
+	Patch(context.Context, string, string, map[string]interface{}) (Channel, error)
 
 	// RetrieveByID retrieves the channel having the provided identifier, that is owned
 	// by the specified user.
-	RetrieveByID(string, string) (Channel, error)
-
-	// RetrieveAll retrieves the subset of channels owned by the specified user.
-	RetrieveAll(string, uint64, uint64, string) (ChannelsPage, error)
+	RetrieveByID(context.Context, string, string) (Channel, error)
+
+	// RetrieveState retrieves the state of the channel having the provided
+	// identifier, regardless of its owner.
+	RetrieveState(context.Context, string) (State, error)
+
+	// UpdateState sets the state of the channel having the provided
+	// identifier, that is owned by the specified user.
+	UpdateState(context.Context, string, string, State) error
+
+	// Archive archives all the active channels owned by the specified user
+	// whose metadata contains the provided selector and whose tags match the
+	// provided filter, and returns the archived channels. All the channels
+	// are archived atomically.
+	Archive(context.Context, string, map[string]interface{}, TagFilter) ([]Channel, error)
+
+	// RetrieveAll retrieves the subset of channels owned by the specified
+	// user, optionally filtered by name, by metadata containment and by
+	// tags. The channels are retrieved past the channel having the last
+	// provided identifier, if set, ignoring the offset.
+	RetrieveAll(context.Context, string, uint64, uint64, string, map[string]interface{}, TagFilter, string) (ChannelsPage, error)
+
+	// RetrieveAccessible retrieves the subset of channels either owned by
+	// the specified user or having one of the provided identifiers,
+	// optionally filtered by name, by metadata containment and by tags. The
+	// channels are retrieved past the channel having the last provided
+	// identifier, if set, ignoring the offset.
+	RetrieveAccessible(context.Context, string, []string, uint64, uint64, string, map[string]interface{}, TagFilter, string) (ChannelsPage, error)
+
+	// CountAccessible returns the number of channels either owned by the
+	// specified user or having one of the provided identifiers, filtered
+	// the same way RetrieveAccessible filters them.
+	CountAccessible(context.Context, string, []string, string, map[string]interface{}, TagFilter) (uint64, error)
 
 	// RetrieveByThing retrieves the subset of channels owned by the specified
 	// user and have specified thing connected to them.
-	RetrieveByThing(string, string, uint64, uint64) (ChannelsPage, error)
+	RetrieveByThing(context.Context, string, string, uint64, uint64) (ChannelsPage, error)
 
-	// Remove removes the channel having the provided identifier, that is owned
-	// by the specified user.
-	Remove(string, string) error
+	// RetrieveConnected retrieves the identifiers of all the channels the
+	// thing having the provided identifier is connected to, regardless of
+	// their owner.
+	RetrieveConnected(context.Context, string) ([]string, error)
 
-	// Connect adds thing to the channel's list of connected things.
-	Connect(string, string, string) error
+	// RetrieveRestricted retrieves the access of the connections of the
+	// thing having the provided identifier that don't grant all the
+	// actions, by the channel identifiers.
+	RetrieveRestricted(context.Context, string) (map[string]ConnectionAccess, error)
 
-	// Disconnect removes thing from the channel's list of connected
-	// things.
-	Disconnect(string, string, string) error
+	// Transfer reassigns the channel having the provided identifier, that
+	// is owned by the specified user, to the new owner. The shares of the
+	// channel are dropped, along with its connections unless they are kept.
+	// The channel is transferred atomically.
+	Transfer(context.Context, string, string, string, bool) error
 
-	// HasThing determines whether the thing with the provided access key, is
-	// "connected" to the specified channel. If that's the case, it returns
-	// thing's ID.
-	HasThing(string, string) (string, error)
+	// Remove removes the channel having the provided identifier, that is owned
+	// by the specified user.
+	Remove(context.Context, string, string) error
+
+	// Connect adds the things to the lists of connected things of the
+	// channels, all at once. The pairs whose channel or thing doesn't exist
+	// are reported by ConnectionError.
+	Connect(context.Context, ...Connection) error
+
+	// Disconnect removes the things from the lists of connected things of
+	// the channels, all at once. The pairs that aren't connected are
+	// reported by ConnectionError.
+	Disconnect(context.Context, ...Connection) error
+
+	// HasThing determines whether the enabled thing with the provided access
+	// key, is "connected" to the specified channel. If that's the case, it
+	// returns the thing along with the access the connection grants.
+	HasThing(context.Context, string, string) (Thing, ConnectionAccess, error)
 }
 
 // ChannelCache contains channel-thing connection caching interface.
 type ChannelCache interface {
-	// Connect channel thing connection.
-	Connect(string, string) error
+	// Connect channel thing connection. Only the connections granting all
+	// the actions are cached.
+	Connect(context.Context, string, string) error
 
 	// HasThing checks if thing is connected to channel.
-	HasThing(string, string) bool
+	HasThing(context.Context, string, string) bool
 
 	// Disconnects thing from channel.
-	Disconnect(string, string) error
+	Disconnect(context.Context, string, string) error
+
+	// SaveRateLimit caches the channel's message rate limit for the
+	// adapters. The zero rate limit removes the cached one.
+	SaveRateLimit(context.Context, string, RateLimit) error
+
+	// SaveValidation caches the channel's SenML validation rules for the
+	// normalizer. The zero validation removes the cached one.
+	SaveValidation(context.Context, string, Validation) error
+
+	// SaveTransform caches the channel's payload transformation for the
+	// adapters. The zero transformation removes the cached one.
+	SaveTransform(context.Context, string, Transform) error
+
+	// SaveDelivery caches the channel's delivery mode for the adapters. The
+	// zero delivery removes the cached one.
+	SaveDelivery(context.Context, string, Delivery) error
+
+	// SaveProtocols caches the protocols the channel can be published to
+	// over for the adapters. No protocols remove the cached ones.
+	SaveProtocols(context.Context, string, []string) error
+
+	// SaveState caches the channel's state.
+	SaveState(context.Context, string, State) error
+
+	// State returns the cached state of the channel.
+	State(context.Context, string) (State, error)
+
+	// SaveConnected caches the identifiers of the channels the thing is
+	// connected to.
+	SaveConnected(context.Context, string, []string) error
+
+	// Connected returns the cached identifiers of the channels the thing is
+	// connected to.
+	Connected(context.Context, string) ([]string, error)
+
+	// RemoveConnected removes the cached identifiers of the channels the
+	// thing is connected to.
+	RemoveConnected(context.Context, string) error
 
-	// Removes channel from cache.
-	Remove(string) error
+	// Removes channel from cache, along with the cached identifiers of the
+	// channels of the things connected to it.
+	Remove(context.Context, string) error
 }
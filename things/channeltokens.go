@@ -0,0 +1,47 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package things
+
+import (
+	"context"
+	"time"
+)
+
+// ChannelToken represents the expiring access to a single channel that is
+// independent of any thing, so that the server-side integrations can
+// publish to, or subscribe to, the channel without creating a thing and
+// managing its key. ChannelToken ID identifies the integration instead of
+// the thing ID. The admin scope grants both publishing and subscribing.
+type ChannelToken struct {
+	ID        string
+	ChannelID string
+	Owner     string
+	Token     string
+	Scope     KeyScope
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+// ChannelTokenRepository specifies a channel access tokens persistence API.
+type ChannelTokenRepository interface {
+	// Save persists the channel token. Successful operation is indicated by
+	// unique identifier accompanied by nil error response.
+	Save(context.Context, ChannelToken) (string, error)
+
+	// RetrieveAll retrieves the unexpired tokens of the channel having the
+	// provided identifier, that is owned by the specified user.
+	RetrieveAll(context.Context, string, string) ([]ChannelToken, error)
+
+	// RetrieveByToken retrieves the unexpired token of the specified
+	// channel, having the provided value.
+	RetrieveByToken(context.Context, string, string) (ChannelToken, error)
+
+	// Remove removes the token having the provided identifier from the
+	// tokens of the channel, that is owned by the specified user.
+	Remove(context.Context, string, string, string) error
+}
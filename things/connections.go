@@ -0,0 +1,89 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package things
+
+import (
+	"fmt"
+
+	"github.com/mainflux/mainflux"
+)
+
+// maxBulkConnections is the maximal number of the channel-thing pairs
+// connected or disconnected at once.
+const maxBulkConnections = 1000
+
+// ConnectionAccess represents the actions the connection grants the thing on
+// the channel.
+type ConnectionAccess string
+
+const (
+	// FullAccess grants both publishing and subscribing. Connections grant
+	// it unless they are restricted.
+	FullAccess ConnectionAccess = ""
+
+	// PublishAccess grants publishing only, e.g. to the sensors that mustn't
+	// receive the commands sent over the channel.
+	PublishAccess ConnectionAccess = "publish"
+
+	// SubscribeAccess grants subscribing only, e.g. to the actuators that
+	// mustn't publish on the command channel.
+	SubscribeAccess ConnectionAccess = "subscribe"
+)
+
+// Valid returns true if the access is one of the supported accesses.
+func (a ConnectionAccess) Valid() bool {
+	switch a {
+	case FullAccess, PublishAccess, SubscribeAccess:
+		return true
+	default:
+		return false
+	}
+}
+
+// Allows returns true if the access grants the action. The restricted
+// accesses don't grant the requests that don't specify the action.
+func (a ConnectionAccess) Allows(action string) bool {
+	switch a {
+	case FullAccess:
+		return true
+	case PublishAccess:
+		return action == mainflux.ActionPublish
+	case SubscribeAccess:
+		return action == mainflux.ActionSubscribe
+	default:
+		return false
+	}
+}
+
+// Connection represents the connection of the thing to the channel, both
+// belonging to the same owner.
+type Connection struct {
+	ChanID  string
+	ThingID string
+	Owner   string
+	Access  ConnectionAccess
+}
+
+// ConnectionFailure represents the channel-thing pair that couldn't be
+// connected or disconnected, along with the reason.
+type ConnectionFailure struct {
+	ChanID  string
+	ThingID string
+	Err     error
+}
+
+// ConnectionError reports the channel-thing pairs that couldn't be connected
+// or disconnected. Pairs are connected and disconnected all at once, so none
+// of the requested pairs is affected if any of them fails.
+type ConnectionError struct {
+	Failures []ConnectionFailure
+}
+
+func (e *ConnectionError) Error() string {
+	return fmt.Sprintf("%d of the channel-thing pairs failed", len(e.Failures))
+}
@@ -0,0 +1,33 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package things
+
+import "encoding/base64"
+
+// Things and channels are retrieved ordered by their identifiers, so the
+// cursor holds the identifier of the last entity of the retrieved page and
+// the next page starts right past it. Unlike the offset, the cursor doesn't
+// require skipping the preceding entities, nor it is shifted when entities
+// are added or removed in between the requests.
+
+// EncodeCursor returns the opaque cursor pointing past the entity having
+// the provided identifier.
+func EncodeCursor(id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(id))
+}
+
+// DecodeCursor returns the identifier of the entity the cursor points past.
+// The empty cursor points to the first page.
+func DecodeCursor(cursor string) (string, error) {
+	id, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", ErrMalformedEntity
+	}
+
+	return string(id), nil
+}
@@ -0,0 +1,17 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package things
+
+// EventPublisher specifies an API for publishing the events of the changes
+// of the things, channels and their connections to the services consuming
+// them, so that they needn't poll the API.
+type EventPublisher interface {
+	// Publish publishes the event, represented by its fields. Every event
+	// holds the "operation" field, naming the change (e.g. thing.create).
+	Publish(map[string]interface{}) error
+}
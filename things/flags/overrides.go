@@ -0,0 +1,44 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// Package flags contains the override provider consulting the configuration
+// overrides kept by the flags service.
+package flags
+
+import (
+	"github.com/mainflux/mainflux/flags"
+	"github.com/mainflux/mainflux/things"
+)
+
+var _ things.OverrideProvider = (*overrideProvider)(nil)
+
+type overrideProvider struct {
+	client flags.Client
+}
+
+// NewOverrideProvider returns the override provider backed by the flags
+// service client. A nil client provides no overrides.
+func NewOverrideProvider(client flags.Client) things.OverrideProvider {
+	return overrideProvider{client: client}
+}
+
+func (op overrideProvider) Override(owner string) things.Override {
+	if op.client == nil {
+		return things.Override{}
+	}
+
+	o := op.client.Override(owner)
+	return things.Override{
+		RateLimit: things.RateLimit{
+			Rate:  o.RateLimit,
+			Burst: o.RateBurst,
+		},
+		MaxThings:   o.MaxThings,
+		MaxChannels: o.MaxChannels,
+		Protocols:   o.Protocols,
+	}
+}
@@ -0,0 +1,76 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package flags_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux/flags"
+	"github.com/mainflux/mainflux/things"
+	thingsflags "github.com/mainflux/mainflux/things/flags"
+	"github.com/stretchr/testify/assert"
+)
+
+const owner = "john.doe@example.com"
+
+type clientMock map[string]flags.Override
+
+func (cm clientMock) Enabled(string, string) bool {
+	return false
+}
+
+func (cm clientMock) Override(owner string) flags.Override {
+	return cm[owner]
+}
+
+func TestOverride(t *testing.T) {
+	client := clientMock{
+		owner: {
+			Owner:       owner,
+			RateLimit:   2.5,
+			RateBurst:   5,
+			MaxThings:   100,
+			MaxChannels: 10,
+			Protocols:   []string{"mqtt"},
+		},
+	}
+
+	cases := map[string]struct {
+		client   flags.Client
+		owner    string
+		override things.Override
+	}{
+		"override of owner with override": {
+			client: client,
+			owner:  owner,
+			override: things.Override{
+				RateLimit:   things.RateLimit{Rate: 2.5, Burst: 5},
+				MaxThings:   100,
+				MaxChannels: 10,
+				Protocols:   []string{"mqtt"},
+			},
+		},
+		"override of owner without override": {
+			client:   client,
+			owner:    "jane.doe@example.com",
+			override: things.Override{},
+		},
+		"override without client": {
+			client:   nil,
+			owner:    owner,
+			override: things.Override{},
+		},
+	}
+
+	for desc, tc := range cases {
+		op := thingsflags.NewOverrideProvider(tc.client)
+		override := op.Override(tc.owner)
+		assert.Equal(t, tc.override, override, fmt.Sprintf("%s: expected %v got %v\n", desc, tc.override, override))
+	}
+}
@@ -0,0 +1,78 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package things
+
+import "context"
+
+// Group represents a named collection of things, e.g. the devices deployed
+// at the same site. Groups form a hierarchy, where each group but the top
+// level ones has a parent group. The things of a group comprise the things
+// assigned to it and to all of its subgroups.
+type Group struct {
+	ID       string
+	Owner    string
+	Parent   string
+	Name     string
+	Metadata map[string]interface{}
+}
+
+// GroupsPage contains page related metadata as well as list of groups that
+// belong to this page.
+type GroupsPage struct {
+	PageMetadata
+	Groups []Group
+}
+
+// Validate returns an error if group representation is invalid, i.e. if it
+// exceeds the provided limits.
+func (g *Group) Validate(limits Limits) error {
+	return limits.validate(g.Name, "", g.Metadata)
+}
+
+// GroupRepository specifies a group persistence API.
+type GroupRepository interface {
+	// Save persists the group. Successful operation is indicated by unique
+	// identifier accompanied by nil error response. If the group has a
+	// parent, the parent group must belong to the same owner.
+	Save(context.Context, Group) (string, error)
+
+	// Update performs an update of the existing group name and metadata. A
+	// non-nil error is returned to indicate operation failure.
+	Update(context.Context, Group) error
+
+	// RetrieveByID retrieves the group having the provided identifier, that
+	// is owned by the specified user.
+	RetrieveByID(context.Context, string, string) (Group, error)
+
+	// RetrieveAll retrieves the subset of groups owned by the specified
+	// user. Only the direct subgroups of the provided group are retrieved,
+	// if set.
+	RetrieveAll(context.Context, string, string, uint64, uint64) (GroupsPage, error)
+
+	// RetrieveThings retrieves the subset of things of the group having the
+	// provided identifier, that is owned by the specified user, including
+	// the things of its subgroups.
+	RetrieveThings(context.Context, string, string, uint64, uint64) (ThingsPage, error)
+
+	// Remove removes the group having the provided identifier, that is owned
+	// by the specified user, along with its subgroups. The things of the
+	// removed groups are kept.
+	Remove(context.Context, string, string) error
+
+	// Assign assigns the things having the provided identifiers to the
+	// group. Assigning the thing that is already assigned has no effect.
+	Assign(context.Context, string, string, ...string) error
+
+	// Unassign removes the thing from the group's list of assigned things.
+	Unassign(context.Context, string, string, string) error
+
+	// Connect connects all the things of the group, including the things of
+	// its subgroups, to the channel, and returns the identifiers of the
+	// things that were not connected to it already.
+	Connect(context.Context, string, string, string) ([]string, error)
+}
@@ -0,0 +1,112 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package things
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// DiagnosticsSubtopic is the subtopic reserved for the self-reports of
+	// the devices, e.g. {"battery": 87, "rssi": -71, "firmware": "1.4.2"},
+	// that are collected into the health records of the publishing things.
+	DiagnosticsSubtopic = "diagnostics"
+
+	// DefaultMinBattery is the battery level, in percents, below which the
+	// thing is considered unhealthy unless specified otherwise.
+	DefaultMinBattery = 20
+
+	// DefaultMinRSSI is the signal strength, in dBm, below which the thing
+	// is considered unhealthy unless specified otherwise.
+	DefaultMinRSSI = -100
+
+	maxFirmwareLength = 64
+)
+
+// Health represents the latest diagnostics reported by the thing. The
+// values the thing never reported are nil, or empty for the firmware.
+type Health struct {
+	ThingID  string
+	Owner    string
+	Battery  *float64
+	RSSI     *float64
+	Firmware string
+	Reported time.Time
+}
+
+// HealthThresholds specifies the battery level and the signal strength
+// below which the thing is considered unhealthy.
+type HealthThresholds struct {
+	Battery float64
+	RSSI    float64
+}
+
+// HealthPage contains a page of the health records.
+type HealthPage struct {
+	PageMetadata
+	Records []Health
+}
+
+type diagnostics struct {
+	Battery  *float64 `json:"battery"`
+	RSSI     *float64 `json:"rssi"`
+	Firmware string   `json:"firmware"`
+}
+
+// ParseDiagnostics parses the self-report published to the diagnostics
+// subtopic. The report has to hold at least one of the battery level
+// between 0 and 100, the signal strength and the firmware version.
+func ParseDiagnostics(payload []byte) (Health, error) {
+	var d diagnostics
+	if err := json.Unmarshal(payload, &d); err != nil {
+		return Health{}, ErrMalformedEntity
+	}
+
+	if d.Battery == nil && d.RSSI == nil && d.Firmware == "" {
+		return Health{}, ErrMalformedEntity
+	}
+
+	if d.Battery != nil && (*d.Battery < 0 || *d.Battery > 100) {
+		return Health{}, ErrMalformedEntity
+	}
+
+	if utf8.RuneCountInString(d.Firmware) > maxFirmwareLength {
+		return Health{}, ErrMalformedEntity
+	}
+
+	return Health{
+		Battery:  d.Battery,
+		RSSI:     d.RSSI,
+		Firmware: d.Firmware,
+	}, nil
+}
+
+// Unhealthy returns true if the reported battery level or signal strength
+// is below the given thresholds.
+func (h Health) Unhealthy(t HealthThresholds) bool {
+	return (h.Battery != nil && *h.Battery < t.Battery) || (h.RSSI != nil && *h.RSSI < t.RSSI)
+}
+
+// HealthRepository specifies a health records persistence API.
+type HealthRepository interface {
+	// Save creates the health record of the thing, or updates it with the
+	// reported values, keeping the ones missing from the report.
+	Save(context.Context, Health) error
+
+	// RetrieveByThing retrieves the health record of the thing having the
+	// provided identifier, that is owned by the specified user.
+	RetrieveByThing(context.Context, string, string) (Health, error)
+
+	// RetrieveUnhealthy retrieves the subset of the health records of the
+	// things owned by the specified user, that are unhealthy according to
+	// the given thresholds, starting with the lowest battery level.
+	RetrieveUnhealthy(context.Context, string, HealthThresholds, uint64, uint64) (HealthPage, error)
+}
@@ -0,0 +1,152 @@
+//
+// Copyright (c) 2018
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package things
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+const (
+	keyField   = "key"
+	stateField = "state"
+)
+
+// Change represents a modification of a single field of the thing or the
+// channel, made by its owner. Values of the thing key are never recorded.
+type Change struct {
+	EntityID  string
+	Owner     string
+	Field     string
+	OldValue  string
+	NewValue  string
+	ChangedAt time.Time
+}
+
+// ChangesPage contains page related metadata as well as list of changes
+// that belong to this page.
+type ChangesPage struct {
+	PageMetadata
+	Changes []Change
+}
+
+// HistoryRepository specifies a persistence API of the changes made to
+// things and channels.
+type HistoryRepository interface {
+	// Save persists the changes. A non-nil error is returned to indicate
+	// operation failure.
+	Save(context.Context, ...Change) error
+
+	// RetrieveAll retrieves the subset of changes made to the entity having
+	// the provided identifier, that is owned by the specified user, ordered
+	// from the most recent one.
+	RetrieveAll(context.Context, string, string, uint64, uint64) (ChangesPage, error)
+}
+
+// ThingChanges returns field-level changes between the old and the new
+// representation of the thing.
+func ThingChanges(old, new Thing, at time.Time) []Change {
+	changes := entityChanges(new.ID, new.Owner, old.Name, new.Name, old.Metadata, new.Metadata, at)
+	changes = appendTagsChange(changes, new.ID, new.Owner, old.Tags, new.Tags, at)
+	if old.Key != new.Key {
+		changes = append(changes, keyChange(new.ID, new.Owner, at))
+	}
+
+	return changes
+}
+
+// ChannelChanges returns field-level changes between the old and the new
+// representation of the channel.
+func ChannelChanges(old, new Channel, at time.Time) []Change {
+	changes := entityChanges(new.ID, new.Owner, old.Name, new.Name, old.Metadata, new.Metadata, at)
+	return appendTagsChange(changes, new.ID, new.Owner, old.Tags, new.Tags, at)
+}
+
+func keyChange(id, owner string, at time.Time) Change {
+	return Change{
+		EntityID:  id,
+		Owner:     owner,
+		Field:     keyField,
+		ChangedAt: at,
+	}
+}
+
+func stateChange(id, owner string, old, new State, at time.Time) Change {
+	return Change{
+		EntityID:  id,
+		Owner:     owner,
+		Field:     stateField,
+		OldValue:  string(old),
+		NewValue:  string(new),
+		ChangedAt: at,
+	}
+}
+
+func entityChanges(id, owner, oldName, newName string, oldMeta, newMeta map[string]interface{}, at time.Time) []Change {
+	changes := []Change{}
+
+	if oldName != newName {
+		changes = append(changes, Change{
+			EntityID:  id,
+			Owner:     owner,
+			Field:     nameField,
+			OldValue:  oldName,
+			NewValue:  newName,
+			ChangedAt: at,
+		})
+	}
+
+	oldVal, newVal := encodeMetadata(oldMeta), encodeMetadata(newMeta)
+	if oldVal != newVal {
+		changes = append(changes, Change{
+			EntityID:  id,
+			Owner:     owner,
+			Field:     metadataField,
+			OldValue:  oldVal,
+			NewValue:  newVal,
+			ChangedAt: at,
+		})
+	}
+
+	return changes
+}
+
+// appendTagsChange appends the change of the tags, recorded as the
+// comma-separated list, unless the tags are unchanged.
+func appendTagsChange(changes []Change, id, owner string, oldTags, newTags []string, at time.Time) []Change {
+	oldVal, newVal := strings.Join(oldTags, ","), strings.Join(newTags, ",")
+	if oldVal == newVal {
+		return changes
+	}
+
+	return append(changes, Change{
+		EntityID:  id,
+		Owner:     owner,
+		Field:     tagsField,
+		OldValue:  oldVal,
+		NewValue:  newVal,
+		ChangedAt: at,
+	})
+}
+
+// encodeMetadata returns the canonical JSON representation of the metadata,
+// with map keys sorted, so that it can be compared and stored as text.
+func encodeMetadata(metadata map[string]interface{}) string {
+	if len(metadata) == 0 {
+		return ""
+	}
+
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return ""
+	}
+
+	return string(data)
+}
@@ -0,0 +1,74 @@
+//
+// Copyright (c) 2018
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package things_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/things"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThingChanges(t *testing.T) {
+	now := time.Now()
+	old := things.Thing{
+		ID:       "1",
+		Owner:    "user@example.com",
+		Name:     "name",
+		Key:      "key",
+		Metadata: map[string]interface{}{"a": "b"},
+	}
+
+	cases := []struct {
+		desc    string
+		thing   things.Thing
+		changes []things.Change
+	}{
+		{
+			desc:    "compare unchanged thing",
+			thing:   old,
+			changes: []things.Change{},
+		},
+		{
+			desc:  "compare thing with changed name and metadata",
+			thing: things.Thing{ID: "1", Owner: "user@example.com", Name: "new", Key: "key", Metadata: map[string]interface{}{"a": "c"}},
+			changes: []things.Change{
+				{EntityID: "1", Owner: "user@example.com", Field: "name", OldValue: "name", NewValue: "new", ChangedAt: now},
+				{EntityID: "1", Owner: "user@example.com", Field: "metadata", OldValue: `{"a":"b"}`, NewValue: `{"a":"c"}`, ChangedAt: now},
+			},
+		},
+		{
+			desc:  "compare thing with changed key",
+			thing: things.Thing{ID: "1", Owner: "user@example.com", Name: "name", Key: "new", Metadata: map[string]interface{}{"a": "b"}},
+			changes: []things.Change{
+				{EntityID: "1", Owner: "user@example.com", Field: "key", ChangedAt: now},
+			},
+		},
+		{
+			desc:  "compare thing with changed tags",
+			thing: things.Thing{ID: "1", Owner: "user@example.com", Name: "name", Key: "key", Tags: []string{"edge", "v2"}, Metadata: map[string]interface{}{"a": "b"}},
+			changes: []things.Change{
+				{EntityID: "1", Owner: "user@example.com", Field: "tags", NewValue: "edge,v2", ChangedAt: now},
+			},
+		},
+		{
+			desc:  "compare thing with removed metadata",
+			thing: things.Thing{ID: "1", Owner: "user@example.com", Name: "name", Key: "key"},
+			changes: []things.Change{
+				{EntityID: "1", Owner: "user@example.com", Field: "metadata", OldValue: `{"a":"b"}`, ChangedAt: now},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		changes := things.ThingChanges(old, tc.thing, now)
+		assert.Equal(t, tc.changes, changes, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.changes, changes))
+	}
+}
@@ -0,0 +1,123 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package jwt
+
+import (
+	"context"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/things"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var _ mainflux.ThingsServiceClient = (*thingsClient)(nil)
+
+var errUnauthorizedAccess = status.Error(codes.PermissionDenied, things.ErrUnauthorizedAccess.Error())
+
+type thingsClient struct {
+	client      mainflux.ThingsServiceClient
+	keys        things.KeyProvider
+	revocations things.RevocationRepository
+}
+
+// NewThingsClient returns things service client that verifies the signed
+// thing keys locally, checking them against the provided revocations, and
+// forwards the requests made using the plain keys to the wrapped client.
+func NewThingsClient(client mainflux.ThingsServiceClient, keys things.KeyProvider, revocations things.RevocationRepository) mainflux.ThingsServiceClient {
+	return thingsClient{
+		client:      client,
+		keys:        keys,
+		revocations: revocations,
+	}
+}
+
+func (tc thingsClient) CanAccess(ctx context.Context, req *mainflux.AccessReq, opts ...grpc.CallOption) (*mainflux.ThingID, error) {
+	sk, err := tc.keys.Parse(req.GetToken())
+	if err != nil {
+		return tc.client.CanAccess(ctx, req, opts...)
+	}
+
+	if !sk.CanAccess(req.GetChanID(), req.GetAction(), req.GetSubtopic()) || tc.revoked(ctx, sk) {
+		return nil, errUnauthorizedAccess
+	}
+
+	return &mainflux.ThingID{Value: sk.ThingID}, nil
+}
+
+func (tc thingsClient) Identify(ctx context.Context, req *mainflux.Token, opts ...grpc.CallOption) (*mainflux.ThingID, error) {
+	sk, err := tc.keys.Parse(req.GetValue())
+	if err != nil {
+		return tc.client.Identify(ctx, req, opts...)
+	}
+
+	if tc.revoked(ctx, sk) {
+		return nil, errUnauthorizedAccess
+	}
+
+	return &mainflux.ThingID{Value: sk.ThingID}, nil
+}
+
+// IdentifyBatch resolves the signed keys locally, passing only the remaining
+// ones on to the things service.
+func (tc thingsClient) IdentifyBatch(ctx context.Context, req *mainflux.Tokens, opts ...grpc.CallOption) (*mainflux.ThingIDs, error) {
+	keys := req.GetValues()
+	ids := make([]string, len(keys))
+	plain := []string{}
+	pos := []int{}
+	for i, key := range keys {
+		sk, err := tc.keys.Parse(key)
+		if err != nil {
+			plain = append(plain, key)
+			pos = append(pos, i)
+			continue
+		}
+
+		if !tc.revoked(ctx, sk) {
+			ids[i] = sk.ThingID
+		}
+	}
+
+	if len(plain) == 0 {
+		return &mainflux.ThingIDs{Values: ids}, nil
+	}
+
+	res, err := tc.client.IdentifyBatch(ctx, &mainflux.Tokens{Values: plain}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, id := range res.GetValues() {
+		if i < len(pos) {
+			ids[pos[i]] = id
+		}
+	}
+
+	return &mainflux.ThingIDs{Values: ids}, nil
+}
+
+func (tc thingsClient) CanRead(ctx context.Context, req *mainflux.AccessReq, opts ...grpc.CallOption) (*mainflux.UserID, error) {
+	return tc.client.CanRead(ctx, req, opts...)
+}
+
+func (tc thingsClient) ListChannelsByThing(ctx context.Context, req *mainflux.ThingID, opts ...grpc.CallOption) (*mainflux.ChannelIDs, error) {
+	return tc.client.ListChannelsByThing(ctx, req, opts...)
+}
+
+func (tc thingsClient) revoked(ctx context.Context, key things.SignedKey) bool {
+	r, err := tc.revocations.RetrieveByThing(ctx, key.ThingID)
+	switch err {
+	case nil:
+		return r.Revokes(key)
+	case things.ErrNotFound:
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,130 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package jwt_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	httpmocks "github.com/mainflux/mainflux/http/mocks"
+	"github.com/mainflux/mainflux/things"
+	"github.com/mainflux/mainflux/things/jwt"
+	"github.com/mainflux/mainflux/things/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const plainKey = "plain-key"
+
+func TestClientCanAccess(t *testing.T) {
+	keys := jwt.NewKeyProvider(secret)
+	revocations := mocks.NewRevocationRepository()
+
+	key := signedKey(time.Hour)
+	plainID := "123e4567-e89b-12d3-a456-000000000004"
+	client := jwt.NewThingsClient(httpmocks.NewThingsClient(map[string]string{plainKey: plainID}), keys, revocations)
+
+	valid, err := keys.Issue(key)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	old := key
+	old.Version = 0
+	revoked, err := keys.Issue(old)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	commands := "123e4567-e89b-12d3-a456-000000000006"
+	sub := key
+	sub.Channels = append([]string{commands}, key.Channels...)
+	sub.Restricted = map[string]things.ConnectionAccess{commands: things.SubscribeAccess}
+	restricted, err := keys.Issue(sub)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	limited := key
+	limited.Rules = []things.SubtopicRule{{Channel: key.Channels[0], ThingID: key.ThingID, Action: mainflux.ActionPublish, Pattern: "telemetry.>"}}
+	ruled, err := keys.Issue(limited)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	err = revocations.Save(context.Background(), things.Revocation{ThingID: key.ThingID, Version: key.Version, ExpiresAt: key.ExpiresAt})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := map[string]struct {
+		key      string
+		chanID   string
+		action   string
+		subtopic string
+		thingID  string
+		err      bool
+	}{
+		"access connected channel using signed key": {
+			key:     valid,
+			chanID:  key.Channels[0],
+			thingID: key.ThingID,
+			err:     false,
+		},
+		"access other channel using signed key": {
+			key:    valid,
+			chanID: "123e4567-e89b-12d3-a456-000000000005",
+			err:    true,
+		},
+		"access channel using revoked signed key": {
+			key:    revoked,
+			chanID: key.Channels[0],
+			err:    true,
+		},
+		"subscribe to subscribe-only channel using signed key": {
+			key:     restricted,
+			chanID:  commands,
+			action:  mainflux.ActionSubscribe,
+			thingID: key.ThingID,
+			err:     false,
+		},
+		"publish on subscribe-only channel using signed key": {
+			key:    restricted,
+			chanID: commands,
+			action: mainflux.ActionPublish,
+			err:    true,
+		},
+		"publish on unrestricted channel using restricted signed key": {
+			key:     restricted,
+			chanID:  key.Channels[0],
+			action:  mainflux.ActionPublish,
+			thingID: key.ThingID,
+			err:     false,
+		},
+		"publish to allowed subtopic using signed key with rules": {
+			key:      ruled,
+			chanID:   key.Channels[0],
+			action:   mainflux.ActionPublish,
+			subtopic: "telemetry.temp",
+			thingID:  key.ThingID,
+			err:      false,
+		},
+		"publish to disallowed subtopic using signed key with rules": {
+			key:      ruled,
+			chanID:   key.Channels[0],
+			action:   mainflux.ActionPublish,
+			subtopic: "commands",
+			err:      true,
+		},
+		"access channel using plain key": {
+			key:     plainKey,
+			chanID:  key.Channels[0],
+			thingID: plainID,
+			err:     false,
+		},
+	}
+
+	for desc, tc := range cases {
+		id, err := client.CanAccess(context.Background(), &mainflux.AccessReq{Token: tc.key, ChanID: tc.chanID, Action: tc.action, Subtopic: tc.subtopic})
+		assert.Equal(t, tc.err, err != nil, fmt.Sprintf("%s: unexpected error %s", desc, err))
+		assert.Equal(t, tc.thingID, id.GetValue(), fmt.Sprintf("%s: expected thing %s got %s", desc, tc.thingID, id.GetValue()))
+	}
+}
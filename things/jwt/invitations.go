@@ -0,0 +1,64 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package jwt
+
+import (
+	"context"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/things"
+	"google.golang.org/grpc"
+)
+
+var _ mainflux.ThingsServiceClient = (*invitationsClient)(nil)
+
+type invitationsClient struct {
+	client mainflux.ThingsServiceClient
+	keys   things.KeyProvider
+}
+
+// NewInvitationsClient returns things service client that grants access to
+// the channel using the channel invitations, verifying them locally, and
+// forwards the remaining requests to the wrapped client. Since invitations
+// are subscribe-only, the client must be used by the services that don't
+// publish messages (e.g. readers) only.
+func NewInvitationsClient(client mainflux.ThingsServiceClient, keys things.KeyProvider) mainflux.ThingsServiceClient {
+	return invitationsClient{
+		client: client,
+		keys:   keys,
+	}
+}
+
+func (ic invitationsClient) CanAccess(ctx context.Context, req *mainflux.AccessReq, opts ...grpc.CallOption) (*mainflux.ThingID, error) {
+	inv, err := ic.keys.ParseInvitation(req.GetToken())
+	if err != nil {
+		return ic.client.CanAccess(ctx, req, opts...)
+	}
+
+	if inv.ChannelID != req.GetChanID() {
+		return nil, errUnauthorizedAccess
+	}
+
+	return &mainflux.ThingID{Value: inv.ID}, nil
+}
+
+func (ic invitationsClient) Identify(ctx context.Context, req *mainflux.Token, opts ...grpc.CallOption) (*mainflux.ThingID, error) {
+	return ic.client.Identify(ctx, req, opts...)
+}
+
+func (ic invitationsClient) IdentifyBatch(ctx context.Context, req *mainflux.Tokens, opts ...grpc.CallOption) (*mainflux.ThingIDs, error) {
+	return ic.client.IdentifyBatch(ctx, req, opts...)
+}
+
+func (ic invitationsClient) CanRead(ctx context.Context, req *mainflux.AccessReq, opts ...grpc.CallOption) (*mainflux.UserID, error) {
+	return ic.client.CanRead(ctx, req, opts...)
+}
+
+func (ic invitationsClient) ListChannelsByThing(ctx context.Context, req *mainflux.ThingID, opts ...grpc.CallOption) (*mainflux.ChannelIDs, error) {
+	return ic.client.ListChannelsByThing(ctx, req, opts...)
+}
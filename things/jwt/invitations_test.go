@@ -0,0 +1,71 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package jwt_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	httpmocks "github.com/mainflux/mainflux/http/mocks"
+	"github.com/mainflux/mainflux/things/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvitationsClientCanAccess(t *testing.T) {
+	keys := jwt.NewKeyProvider(secret)
+
+	inv := invitation(time.Hour)
+	plainID := "123e4567-e89b-12d3-a456-000000000004"
+	client := jwt.NewInvitationsClient(httpmocks.NewThingsClient(map[string]string{plainKey: plainID}), keys)
+
+	valid, err := keys.IssueInvitation(inv)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	expired, err := keys.IssueInvitation(invitation(-time.Hour))
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := map[string]struct {
+		key    string
+		chanID string
+		id     string
+		err    bool
+	}{
+		"access invited channel using invitation": {
+			key:    valid,
+			chanID: inv.ChannelID,
+			id:     inv.ID,
+			err:    false,
+		},
+		"access other channel using invitation": {
+			key:    valid,
+			chanID: "123e4567-e89b-12d3-a456-000000000005",
+			err:    true,
+		},
+		"access channel using expired invitation": {
+			key:    expired,
+			chanID: inv.ChannelID,
+			err:    true,
+		},
+		"access channel using plain key": {
+			key:    plainKey,
+			chanID: inv.ChannelID,
+			id:     plainID,
+			err:    false,
+		},
+	}
+
+	for desc, tc := range cases {
+		id, err := client.CanAccess(context.Background(), &mainflux.AccessReq{Token: tc.key, ChanID: tc.chanID})
+		assert.Equal(t, tc.err, err != nil, fmt.Sprintf("%s: unexpected error %s", desc, err))
+		assert.Equal(t, tc.id, id.GetValue(), fmt.Sprintf("%s: expected id %s got %s", desc, tc.id, id.GetValue()))
+	}
+}
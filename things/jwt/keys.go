@@ -0,0 +1,167 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package jwt
+
+import (
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/mainflux/mainflux/things"
+)
+
+const (
+	// keyAudience distinguishes signed thing keys from the onboarding codes.
+	keyAudience string = "mainflux.things.keys"
+
+	// invitationAudience prevents invitations from being used as thing keys.
+	invitationAudience string = "mainflux.things.invitations"
+)
+
+var _ things.KeyProvider = (*keyProvider)(nil)
+
+type keyClaims struct {
+	jwt.StandardClaims
+	Channels   []string          `json:"channels"`
+	Restricted map[string]string `json:"restricted,omitempty"`
+	Rules      []ruleClaims      `json:"rules,omitempty"`
+	Version    uint64            `json:"ver"`
+}
+
+// ruleClaims carry the subtopic rule fields needed to enforce it.
+type ruleClaims struct {
+	Channel string `json:"channel"`
+	Action  string `json:"action"`
+	Pattern string `json:"pattern"`
+}
+
+type invitationClaims struct {
+	jwt.StandardClaims
+	Channel string `json:"channel"`
+}
+
+type keyProvider struct {
+	secret string
+}
+
+// NewKeyProvider instantiates a JWT signed thing key and invitation provider.
+func NewKeyProvider(secret string) things.KeyProvider {
+	return &keyProvider{secret}
+}
+
+func (kp *keyProvider) Issue(key things.SignedKey) (string, error) {
+	claims := keyClaims{
+		StandardClaims: jwt.StandardClaims{
+			Subject:   key.ThingID,
+			Issuer:    issuer,
+			Audience:  keyAudience,
+			IssuedAt:  key.IssuedAt.Unix(),
+			ExpiresAt: key.ExpiresAt.Unix(),
+		},
+		Channels: key.Channels,
+		Version:  key.Version,
+	}
+	if len(key.Restricted) > 0 {
+		claims.Restricted = map[string]string{}
+		for chanID, access := range key.Restricted {
+			claims.Restricted[chanID] = string(access)
+		}
+	}
+	for _, r := range key.Rules {
+		claims.Rules = append(claims.Rules, ruleClaims{Channel: r.Channel, Action: r.Action, Pattern: r.Pattern})
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(kp.secret))
+}
+
+func (kp *keyProvider) Parse(key string) (things.SignedKey, error) {
+	claims := keyClaims{}
+	token, err := jwt.ParseWithClaims(key, &claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, things.ErrUnauthorizedAccess
+		}
+
+		return []byte(kp.secret), nil
+	})
+
+	if err != nil || !token.Valid {
+		return things.SignedKey{}, things.ErrUnauthorizedAccess
+	}
+
+	if !claims.VerifyAudience(keyAudience, true) || claims.Subject == "" {
+		return things.SignedKey{}, things.ErrUnauthorizedAccess
+	}
+
+	sk := things.SignedKey{
+		ThingID:   claims.Subject,
+		Channels:  claims.Channels,
+		Version:   claims.Version,
+		IssuedAt:  time.Unix(claims.IssuedAt, 0).UTC(),
+		ExpiresAt: time.Unix(claims.ExpiresAt, 0).UTC(),
+	}
+	if len(claims.Restricted) > 0 {
+		sk.Restricted = map[string]things.ConnectionAccess{}
+		for chanID, access := range claims.Restricted {
+			sk.Restricted[chanID] = things.ConnectionAccess(access)
+		}
+	}
+	for _, r := range claims.Rules {
+		sk.Rules = append(sk.Rules, things.SubtopicRule{
+			Channel: r.Channel,
+			ThingID: claims.Subject,
+			Action:  r.Action,
+			Pattern: r.Pattern,
+		})
+	}
+
+	return sk, nil
+}
+
+func (kp *keyProvider) IssueInvitation(inv things.Invitation) (string, error) {
+	claims := invitationClaims{
+		StandardClaims: jwt.StandardClaims{
+			Id:        inv.ID,
+			Issuer:    issuer,
+			Audience:  invitationAudience,
+			IssuedAt:  inv.IssuedAt.Unix(),
+			ExpiresAt: inv.ExpiresAt.Unix(),
+		},
+		Channel: inv.ChannelID,
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(kp.secret))
+}
+
+func (kp *keyProvider) ParseInvitation(invitation string) (things.Invitation, error) {
+	claims := invitationClaims{}
+	token, err := jwt.ParseWithClaims(invitation, &claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, things.ErrUnauthorizedAccess
+		}
+
+		return []byte(kp.secret), nil
+	})
+
+	if err != nil || !token.Valid {
+		return things.Invitation{}, things.ErrUnauthorizedAccess
+	}
+
+	if !claims.VerifyAudience(invitationAudience, true) || claims.Id == "" || claims.Channel == "" {
+		return things.Invitation{}, things.ErrUnauthorizedAccess
+	}
+
+	inv := things.Invitation{
+		ID:        claims.Id,
+		ChannelID: claims.Channel,
+		IssuedAt:  time.Unix(claims.IssuedAt, 0).UTC(),
+		ExpiresAt: time.Unix(claims.ExpiresAt, 0).UTC(),
+	}
+
+	return inv, nil
+}
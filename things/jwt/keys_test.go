@@ -0,0 +1,141 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package jwt_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/things"
+	"github.com/mainflux/mainflux/things/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func signedKey(ttl time.Duration) things.SignedKey {
+	now := time.Now().UTC().Truncate(time.Second)
+	return things.SignedKey{
+		ThingID:   "123e4567-e89b-12d3-a456-000000000001",
+		Channels:  []string{"123e4567-e89b-12d3-a456-000000000002"},
+		Version:   1,
+		IssuedAt:  now,
+		ExpiresAt: now.Add(ttl),
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	provider := jwt.NewKeyProvider(secret)
+
+	key := signedKey(time.Hour)
+	valid, err := provider.Issue(key)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	expired, err := provider.Issue(signedKey(-time.Hour))
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	foreign, err := jwt.NewKeyProvider("invalid").Issue(key)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	_, code, err := jwt.New(secret, endpoints, time.Hour).Issue(key.ThingID, "key", key.Channels)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := map[string]struct {
+		key string
+		err error
+	}{
+		"parse valid key": {
+			key: valid,
+			err: nil,
+		},
+		"parse expired key": {
+			key: expired,
+			err: things.ErrUnauthorizedAccess,
+		},
+		"parse key signed using invalid secret": {
+			key: foreign,
+			err: things.ErrUnauthorizedAccess,
+		},
+		"parse onboarding code": {
+			key: code,
+			err: things.ErrUnauthorizedAccess,
+		},
+		"parse plain key": {
+			key: "123e4567-e89b-12d3-a456-000000000003",
+			err: things.ErrUnauthorizedAccess,
+		},
+	}
+
+	for desc, tc := range cases {
+		sk, err := provider.Parse(tc.key)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s", desc, tc.err, err))
+		if tc.err == nil {
+			assert.Equal(t, key, sk, fmt.Sprintf("%s: expected key %v got %v", desc, key, sk))
+		}
+	}
+}
+
+func invitation(ttl time.Duration) things.Invitation {
+	now := time.Now().UTC().Truncate(time.Second)
+	return things.Invitation{
+		ID:        "123e4567-e89b-12d3-a456-000000000006",
+		ChannelID: "123e4567-e89b-12d3-a456-000000000002",
+		IssuedAt:  now,
+		ExpiresAt: now.Add(ttl),
+	}
+}
+
+func TestParseInvitation(t *testing.T) {
+	provider := jwt.NewKeyProvider(secret)
+
+	inv := invitation(time.Hour)
+	valid, err := provider.IssueInvitation(inv)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	expired, err := provider.IssueInvitation(invitation(-time.Hour))
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	foreign, err := jwt.NewKeyProvider("invalid").IssueInvitation(inv)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	key, err := provider.Issue(signedKey(time.Hour))
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := map[string]struct {
+		invitation string
+		err        error
+	}{
+		"parse valid invitation": {
+			invitation: valid,
+			err:        nil,
+		},
+		"parse expired invitation": {
+			invitation: expired,
+			err:        things.ErrUnauthorizedAccess,
+		},
+		"parse invitation signed using invalid secret": {
+			invitation: foreign,
+			err:        things.ErrUnauthorizedAccess,
+		},
+		"parse signed key": {
+			invitation: key,
+			err:        things.ErrUnauthorizedAccess,
+		},
+	}
+
+	for desc, tc := range cases {
+		parsed, err := provider.ParseInvitation(tc.invitation)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s", desc, tc.err, err))
+		if tc.err == nil {
+			assert.Equal(t, inv, parsed, fmt.Sprintf("%s: expected invitation %v got %v", desc, inv, parsed))
+		}
+	}
+
+	_, err = provider.Parse(valid)
+	assert.Equal(t, things.ErrUnauthorizedAccess, err, fmt.Sprintf("parse invitation as signed key: expected %s got %s", things.ErrUnauthorizedAccess, err))
+}
@@ -0,0 +1,77 @@
+//
+// Copyright (c) 2018
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// Package jwt provides JWT onboarding and signed thing key providers, and
+// things service client that verifies the signed keys locally.
+package jwt
+
+import (
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/mainflux/mainflux/things"
+)
+
+const issuer string = "mainflux.things"
+
+var _ things.OnboardingProvider = (*onboardingProvider)(nil)
+
+type onboardingClaims struct {
+	jwt.StandardClaims
+	Key       string            `json:"key"`
+	Channels  []string          `json:"channels"`
+	Endpoints map[string]string `json:"endpoints"`
+}
+
+type onboardingProvider struct {
+	secret    string
+	endpoints map[string]string
+	duration  time.Duration
+}
+
+// New instantiates a JWT onboarding provider. Issued payloads contain the
+// provided platform endpoints and are valid for the specified duration.
+func New(secret string, endpoints map[string]string, duration time.Duration) things.OnboardingProvider {
+	return &onboardingProvider{
+		secret:    secret,
+		endpoints: endpoints,
+		duration:  duration,
+	}
+}
+
+func (op *onboardingProvider) Issue(id, key string, channels []string) (things.Onboarding, string, error) {
+	now := time.Now().UTC()
+	exp := now.Add(op.duration)
+
+	claims := onboardingClaims{
+		StandardClaims: jwt.StandardClaims{
+			Subject:   id,
+			Issuer:    issuer,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: exp.Unix(),
+		},
+		Key:       key,
+		Channels:  channels,
+		Endpoints: op.endpoints,
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	code, err := token.SignedString([]byte(op.secret))
+	if err != nil {
+		return things.Onboarding{}, "", err
+	}
+
+	onboarding := things.Onboarding{
+		ThingID:   id,
+		ThingKey:  key,
+		Channels:  channels,
+		Endpoints: op.endpoints,
+		ExpiresAt: time.Unix(exp.Unix(), 0).UTC(),
+	}
+
+	return onboarding, code, nil
+}
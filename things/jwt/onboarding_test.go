@@ -0,0 +1,66 @@
+//
+// Copyright (c) 2018
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package jwt_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	jwtgo "github.com/dgrijalva/jwt-go"
+	"github.com/mainflux/mainflux/things/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const secret = "secret"
+
+var endpoints = map[string]string{
+	"http": "http://localhost/http",
+	"mqtt": "tcp://localhost:1883",
+}
+
+func TestIssue(t *testing.T) {
+	provider := jwt.New(secret, endpoints, time.Hour)
+
+	id := "123e4567-e89b-12d3-a456-000000000001"
+	key := "123e4567-e89b-12d3-a456-000000000002"
+	channels := []string{"123e4567-e89b-12d3-a456-000000000003"}
+
+	onboarding, code, err := provider.Issue(id, key, channels)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, id, onboarding.ThingID, fmt.Sprintf("expected thing id %s got %s", id, onboarding.ThingID))
+	assert.Equal(t, endpoints, onboarding.Endpoints, fmt.Sprintf("expected endpoints %v got %v", endpoints, onboarding.Endpoints))
+	assert.True(t, onboarding.ExpiresAt.After(time.Now()), "expected expiration time in the future")
+
+	cases := map[string]struct {
+		secret string
+		valid  bool
+	}{
+		"verify code using valid secret": {
+			secret: secret,
+			valid:  true,
+		},
+		"verify code using invalid secret": {
+			secret: "invalid",
+			valid:  false,
+		},
+	}
+
+	for desc, tc := range cases {
+		claims := jwtgo.MapClaims{}
+		_, err := jwtgo.ParseWithClaims(code, claims, func(token *jwtgo.Token) (interface{}, error) {
+			return []byte(tc.secret), nil
+		})
+		assert.Equal(t, tc.valid, err == nil, fmt.Sprintf("%s: unexpected verification result: %s", desc, err))
+		if tc.valid {
+			assert.Equal(t, id, claims["sub"], fmt.Sprintf("%s: expected subject %s got %v", desc, id, claims["sub"]))
+			assert.Equal(t, key, claims["key"], fmt.Sprintf("%s: expected key %s got %v", desc, key, claims["key"]))
+		}
+	}
+}
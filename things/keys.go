@@ -0,0 +1,101 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package things
+
+import (
+	"context"
+	"time"
+)
+
+// SignedKey represents a self-contained thing key that can be verified
+// without the things service round trip. The key grants access to the
+// channels the thing was connected to at the time the key was issued.
+// Restricted holds the access of the connections that don't grant all the
+// actions, by the channel identifiers, and Rules holds the subtopic rules of
+// the thing, identified by the channel, the action and the pattern only.
+type SignedKey struct {
+	ThingID    string
+	Channels   []string
+	Restricted map[string]ConnectionAccess
+	Rules      []SubtopicRule
+	Version    uint64
+	IssuedAt   time.Time
+	ExpiresAt  time.Time
+}
+
+// CanAccess returns true if the key grants access to the subtopic of the
+// channel for the action.
+func (sk SignedKey) CanAccess(chanID, action, subtopic string) bool {
+	for _, ch := range sk.Channels {
+		if ch == chanID {
+			return sk.Restricted[chanID].Allows(action) && AllowsSubtopic(sk.Rules, chanID, action, subtopic)
+		}
+	}
+
+	return false
+}
+
+// Invitation represents an expiring, subscribe-only access to a single
+// channel that can be shared with the external data consumers, who don't
+// have platform accounts. Invitation ID identifies the consumer instead of
+// the thing ID.
+type Invitation struct {
+	ID        string
+	ChannelID string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+// KeyProvider specifies an API for issuing and verifying signed thing keys
+// and channel invitations.
+type KeyProvider interface {
+	// Issue issues the signed representation of the key.
+	Issue(SignedKey) (string, error)
+
+	// Parse verifies the signed key and returns its content. An error is
+	// returned if the key is not a valid signed key, or if it has expired.
+	Parse(string) (SignedKey, error)
+
+	// IssueInvitation issues the signed representation of the invitation.
+	IssueInvitation(Invitation) (string, error)
+
+	// ParseInvitation verifies the signed invitation and returns its
+	// content. An error is returned if the invitation is not valid, or if
+	// it has expired.
+	ParseInvitation(string) (Invitation, error)
+}
+
+// Revocation represents the revocation of all the signed keys of the thing
+// that were issued before it, i.e. of the keys having a lower version. Since
+// the keys expire, the revocation is in effect only until all the revoked
+// keys have expired.
+type Revocation struct {
+	ThingID   string
+	Version   uint64
+	ExpiresAt time.Time
+}
+
+// Revokes returns true if the signed key is revoked by the revocation.
+func (r Revocation) Revokes(key SignedKey) bool {
+	return r.ThingID == key.ThingID && key.Version < r.Version
+}
+
+// RevocationRepository specifies a revocation persistence API.
+type RevocationRepository interface {
+	// Save persists the revocation, replacing the former revocation of the
+	// same thing. A non-nil error is returned to indicate operation failure.
+	Save(context.Context, Revocation) error
+
+	// RetrieveByThing retrieves the latest revocation of the thing
+	// identified by the provided ID, even if it is no longer in effect.
+	RetrieveByThing(context.Context, string) (Revocation, error)
+
+	// RetrieveAll retrieves all the revocations that have not expired by
+	// the provided time.
+	RetrieveAll(context.Context, time.Time) ([]Revocation, error)
+}
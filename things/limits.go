@@ -0,0 +1,69 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package things
+
+import (
+	"encoding/json"
+	"fmt"
+	"unicode/utf8"
+)
+
+// Limits specifies the limits enforced when things and channels are
+// validated. Names and keys are limited in characters, and metadata in bytes
+// of its JSON representation. A zero limit is not enforced.
+type Limits struct {
+	NameLength   int
+	KeyLength    int
+	MetadataSize int
+}
+
+// ValidationError indicates the entity field that failed the validation,
+// e.g. the name exceeding the configured length.
+type ValidationError struct {
+	Field  string
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s %s", e.Field, e.Reason)
+}
+
+func (l Limits) validate(name, key string, metadata map[string]interface{}) error {
+	if l.NameLength > 0 && utf8.RuneCountInString(name) > l.NameLength {
+		return &ValidationError{
+			Field:  nameField,
+			Reason: fmt.Sprintf("exceeds %d characters", l.NameLength),
+		}
+	}
+
+	if l.KeyLength > 0 && utf8.RuneCountInString(key) > l.KeyLength {
+		return &ValidationError{
+			Field:  keyField,
+			Reason: fmt.Sprintf("exceeds %d characters", l.KeyLength),
+		}
+	}
+
+	if l.MetadataSize > 0 && len(metadata) > 0 {
+		data, err := json.Marshal(metadata)
+		if err != nil {
+			return &ValidationError{
+				Field:  metadataField,
+				Reason: "is not valid JSON",
+			}
+		}
+
+		if len(data) > l.MetadataSize {
+			return &ValidationError{
+				Field:  metadataField,
+				Reason: fmt.Sprintf("exceeds %d bytes", l.MetadataSize),
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package things_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mainflux/mainflux/things"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThingValidate(t *testing.T) {
+	limits := things.Limits{NameLength: 4, KeyLength: 8, MetadataSize: 16}
+
+	cases := []struct {
+		desc   string
+		thing  things.Thing
+		limits things.Limits
+		err    error
+	}{
+		{
+			desc:   "validate thing within limits",
+			thing:  things.Thing{Name: "ñame", Key: "key", Metadata: map[string]interface{}{"a": "b"}},
+			limits: limits,
+			err:    nil,
+		},
+		{
+			desc:   "validate thing with too long name",
+			thing:  things.Thing{Name: "names"},
+			limits: limits,
+			err:    &things.ValidationError{Field: "name", Reason: "exceeds 4 characters"},
+		},
+		{
+			desc:   "validate thing with too long key",
+			thing:  things.Thing{Key: strings.Repeat("k", 9)},
+			limits: limits,
+			err:    &things.ValidationError{Field: "key", Reason: "exceeds 8 characters"},
+		},
+		{
+			desc:   "validate thing with too large metadata",
+			thing:  things.Thing{Metadata: map[string]interface{}{"model": "sensor"}},
+			limits: limits,
+			err:    &things.ValidationError{Field: "metadata", Reason: "exceeds 16 bytes"},
+		},
+		{
+			desc:   "validate thing with empty tag",
+			thing:  things.Thing{Tags: []string{"edge", ""}},
+			limits: limits,
+			err:    &things.ValidationError{Field: "tags", Reason: "must hold tags of 1 to 64 characters without commas"},
+		},
+		{
+			desc:   "validate thing with tag holding comma",
+			thing:  things.Thing{Tags: []string{"edge,v2"}},
+			limits: limits,
+			err:    &things.ValidationError{Field: "tags", Reason: "must hold tags of 1 to 64 characters without commas"},
+		},
+		{
+			desc:   "validate thing without limits",
+			thing:  things.Thing{Name: "names", Key: strings.Repeat("k", 9), Metadata: map[string]interface{}{"model": "sensor"}},
+			limits: things.Limits{},
+			err:    nil,
+		},
+		{
+			desc:   "validate thing with malformed adapter metadata",
+			thing:  things.Thing{Metadata: map[string]interface{}{"lora": "x"}},
+			limits: limits,
+			err:    things.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		err := tc.thing.Validate(tc.limits)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestChannelValidate(t *testing.T) {
+	limits := things.Limits{NameLength: 4, MetadataSize: 16}
+
+	cases := []struct {
+		desc    string
+		channel things.Channel
+		err     error
+	}{
+		{
+			desc:    "validate channel within limits",
+			channel: things.Channel{Name: "name", Metadata: map[string]interface{}{"a": "b"}},
+			err:     nil,
+		},
+		{
+			desc:    "validate channel with too long name",
+			channel: things.Channel{Name: "names"},
+			err:     &things.ValidationError{Field: "name", Reason: "exceeds 4 characters"},
+		},
+		{
+			desc:    "validate channel with too large metadata",
+			channel: things.Channel{Metadata: map[string]interface{}{"model": "sensor"}},
+			err:     &things.ValidationError{Field: "metadata", Reason: "exceeds 16 bytes"},
+		},
+	}
+
+	for _, tc := range cases {
+		err := tc.channel.Validate(limits)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
@@ -0,0 +1,126 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package lru
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+type entry struct {
+	key       string
+	value     interface{}
+	expiresAt time.Time
+}
+
+// cache holds up to size entries, each of them for ttl at most. The zero
+// ttl keeps the entries until they are evicted.
+type cache struct {
+	mu    sync.Mutex
+	size  int
+	ttl   time.Duration
+	items map[string]*list.Element
+	order *list.List
+}
+
+func newCache(size int, ttl time.Duration) *cache {
+	return &cache{
+		size:  size,
+		ttl:   ttl,
+		items: make(map[string]*list.Element),
+		order: list.New(),
+	}
+}
+
+func (c *cache) get(key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.lookup(key)
+}
+
+func (c *cache) set(key string, value interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.store(key, value, time.Time{})
+}
+
+// setUntil stores the value that is kept no longer than the provided time,
+// unless the time is zero.
+func (c *cache) setUntil(key string, value interface{}, deadline time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.store(key, value, deadline)
+}
+
+// update stores the value the function derives from the current one, which
+// is nil if the key is not cached.
+func (c *cache) update(key string, fn func(interface{}) interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	value, _ := c.lookup(key)
+	c.store(key, fn(value), time.Time{})
+}
+
+func (c *cache) remove(keys ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		if el, ok := c.items[key]; ok {
+			c.order.Remove(el)
+			delete(c.items, key)
+		}
+	}
+}
+
+func (c *cache) lookup(key string) (interface{}, bool) {
+	el, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	e := el.Value.(*entry)
+	if !e.expiresAt.IsZero() && time.Now().After(e.expiresAt) {
+		c.order.Remove(el)
+		delete(c.items, key)
+		return nil, false
+	}
+
+	c.order.MoveToFront(el)
+	return e.value, true
+}
+
+func (c *cache) store(key string, value interface{}, deadline time.Time) {
+	var expiresAt time.Time
+	if c.ttl > 0 {
+		expiresAt = time.Now().Add(c.ttl)
+	}
+	if !deadline.IsZero() && (expiresAt.IsZero() || deadline.Before(expiresAt)) {
+		expiresAt = deadline
+	}
+
+	if el, ok := c.items[key]; ok {
+		e := el.Value.(*entry)
+		e.value = value
+		e.expiresAt = expiresAt
+		c.order.MoveToFront(el)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&entry{key: key, value: value, expiresAt: expiresAt})
+	for c.order.Len() > c.size {
+		el := c.order.Back()
+		c.order.Remove(el)
+		delete(c.items, el.Value.(*entry).key)
+	}
+}
@@ -0,0 +1,163 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package lru
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mainflux/mainflux/things"
+)
+
+const (
+	chanPrefix      = "channel"
+	statePrefix     = "channel_state"
+	connectedPrefix = "thing_channels"
+)
+
+var _ things.ChannelCache = (*channelCache)(nil)
+
+type channelCache struct {
+	cache *cache
+	next  things.ChannelCache
+}
+
+// NewChannelCache returns in-memory channel cache implementation, holding up
+// to size entries for ttl at most. Rate limits, validation rules and payload
+// transformations are read by the adapters and the normalizer, so they are
+// passed on to the provided cache.
+func NewChannelCache(next things.ChannelCache, size int, ttl time.Duration) things.ChannelCache {
+	return &channelCache{
+		cache: newCache(size, ttl),
+		next:  next,
+	}
+}
+
+func (cc *channelCache) Connect(_ context.Context, chanID, thingID string) error {
+	cc.connect(chanID, thingID)
+	return nil
+}
+
+func (cc *channelCache) HasThing(_ context.Context, chanID, thingID string) bool {
+	val, ok := cc.cache.get(chanKey(chanID))
+	if !ok {
+		return false
+	}
+
+	return val.(map[string]bool)[thingID]
+}
+
+func (cc *channelCache) Disconnect(_ context.Context, chanID, thingID string) error {
+	cc.cache.update(chanKey(chanID), func(val interface{}) interface{} {
+		ids := map[string]bool{}
+		if val != nil {
+			for id := range val.(map[string]bool) {
+				ids[id] = true
+			}
+		}
+		delete(ids, thingID)
+		return ids
+	})
+	return nil
+}
+
+func (cc *channelCache) SaveRateLimit(ctx context.Context, chanID string, rl things.RateLimit) error {
+	return cc.next.SaveRateLimit(ctx, chanID, rl)
+}
+
+func (cc *channelCache) SaveValidation(ctx context.Context, chanID string, v things.Validation) error {
+	return cc.next.SaveValidation(ctx, chanID, v)
+}
+
+func (cc *channelCache) SaveTransform(ctx context.Context, chanID string, tr things.Transform) error {
+	return cc.next.SaveTransform(ctx, chanID, tr)
+}
+
+func (cc *channelCache) SaveDelivery(ctx context.Context, chanID string, d things.Delivery) error {
+	return cc.next.SaveDelivery(ctx, chanID, d)
+}
+
+func (cc *channelCache) SaveProtocols(ctx context.Context, chanID string, protocols []string) error {
+	return cc.next.SaveProtocols(ctx, chanID, protocols)
+}
+
+func (cc *channelCache) SaveState(_ context.Context, chanID string, state things.State) error {
+	cc.cache.set(stateKey(chanID), state)
+	return nil
+}
+
+func (cc *channelCache) State(_ context.Context, chanID string) (things.State, error) {
+	val, ok := cc.cache.get(stateKey(chanID))
+	if !ok {
+		return "", things.ErrNotFound
+	}
+
+	return val.(things.State), nil
+}
+
+func (cc *channelCache) SaveConnected(_ context.Context, thingID string, chanIDs []string) error {
+	cc.cache.set(connectedKey(thingID), append([]string{}, chanIDs...))
+	for _, chanID := range chanIDs {
+		cc.connect(chanID, thingID)
+	}
+
+	return nil
+}
+
+func (cc *channelCache) Connected(_ context.Context, thingID string) ([]string, error) {
+	val, ok := cc.cache.get(connectedKey(thingID))
+	if !ok {
+		return nil, things.ErrNotFound
+	}
+
+	return append([]string{}, val.([]string)...), nil
+}
+
+func (cc *channelCache) RemoveConnected(_ context.Context, thingID string) error {
+	cc.cache.remove(connectedKey(thingID))
+	return nil
+}
+
+func (cc *channelCache) Remove(ctx context.Context, chanID string) error {
+	keys := []string{chanKey(chanID), stateKey(chanID)}
+	if val, ok := cc.cache.get(chanKey(chanID)); ok {
+		for thingID := range val.(map[string]bool) {
+			keys = append(keys, connectedKey(thingID))
+		}
+	}
+	cc.cache.remove(keys...)
+
+	return cc.next.Remove(ctx, chanID)
+}
+
+// connect adds the thing to the set of the things connected to the channel.
+// The sets are copied on write, so that the readers don't need locking.
+func (cc *channelCache) connect(chanID, thingID string) {
+	cc.cache.update(chanKey(chanID), func(val interface{}) interface{} {
+		ids := map[string]bool{thingID: true}
+		if val != nil {
+			for id := range val.(map[string]bool) {
+				ids[id] = true
+			}
+		}
+		return ids
+	})
+}
+
+func chanKey(chanID string) string {
+	return fmt.Sprintf("%s:%s", chanPrefix, chanID)
+}
+
+func stateKey(chanID string) string {
+	return fmt.Sprintf("%s:%s", statePrefix, chanID)
+}
+
+func connectedKey(thingID string) string {
+	return fmt.Sprintf("%s:%s", connectedPrefix, thingID)
+}
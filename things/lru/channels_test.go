@@ -0,0 +1,97 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package lru_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux/things"
+	"github.com/mainflux/mainflux/things/lru"
+	"github.com/mainflux/mainflux/things/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHasThing(t *testing.T) {
+	channelCache := lru.NewChannelCache(mocks.NewChannelCache(), 10, 0)
+
+	err := channelCache.Connect(context.Background(), "1", "1")
+	require.Nil(t, err, fmt.Sprintf("Connect thing to channel: expected nil got %s", err))
+	err = channelCache.Connect(context.Background(), "1", "2")
+	require.Nil(t, err, fmt.Sprintf("Connect thing to channel: expected nil got %s", err))
+	err = channelCache.Disconnect(context.Background(), "1", "2")
+	require.Nil(t, err, fmt.Sprintf("Disconnect thing from channel: expected nil got %s", err))
+
+	cases := []struct {
+		desc     string
+		chanID   string
+		thingID  string
+		hasThing bool
+	}{
+		{
+			desc:     "check if connected thing is in cache",
+			chanID:   "1",
+			thingID:  "1",
+			hasThing: true,
+		},
+		{
+			desc:     "check if disconnected thing is in cache",
+			chanID:   "1",
+			thingID:  "2",
+			hasThing: false,
+		},
+		{
+			desc:     "check if thing of non-cached channel is in cache",
+			chanID:   "2",
+			thingID:  "1",
+			hasThing: false,
+		},
+	}
+
+	for _, tc := range cases {
+		hasThing := channelCache.HasThing(context.Background(), tc.chanID, tc.thingID)
+		assert.Equal(t, tc.hasThing, hasThing, fmt.Sprintf("%s: expected %t got %t", tc.desc, tc.hasThing, hasThing))
+	}
+}
+
+func TestConnected(t *testing.T) {
+	channelCache := lru.NewChannelCache(mocks.NewChannelCache(), 10, 0)
+
+	chanIDs := []string{"1", "2"}
+	err := channelCache.SaveConnected(context.Background(), "1", chanIDs)
+	require.Nil(t, err, fmt.Sprintf("Save connected channels: expected nil got %s", err))
+
+	connected, err := channelCache.Connected(context.Background(), "1")
+	assert.Nil(t, err, fmt.Sprintf("Get connected channels: expected nil got %s", err))
+	assert.Equal(t, chanIDs, connected, fmt.Sprintf("Get connected channels: expected %v got %v", chanIDs, connected))
+
+	hasThing := channelCache.HasThing(context.Background(), "2", "1")
+	assert.True(t, hasThing, "Check connection of saved channel: expected true got false")
+
+	err = channelCache.Remove(context.Background(), "2")
+	require.Nil(t, err, fmt.Sprintf("Remove channel: expected nil got %s", err))
+
+	_, err = channelCache.Connected(context.Background(), "1")
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("Get connected channels of removed channel's thing: expected %s got %s", things.ErrNotFound, err))
+}
+
+func TestState(t *testing.T) {
+	channelCache := lru.NewChannelCache(mocks.NewChannelCache(), 10, 0)
+
+	err := channelCache.SaveState(context.Background(), "1", things.Archived)
+	require.Nil(t, err, fmt.Sprintf("Save channel state: expected nil got %s", err))
+
+	state, err := channelCache.State(context.Background(), "1")
+	assert.Nil(t, err, fmt.Sprintf("Get channel state: expected nil got %s", err))
+	assert.Equal(t, things.Archived, state, fmt.Sprintf("Get channel state: expected %s got %s", things.Archived, state))
+
+	_, err = channelCache.State(context.Background(), "2")
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("Get state of non-cached channel: expected %s got %s", things.ErrNotFound, err))
+}
@@ -0,0 +1,10 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// Package lru contains cache implementations keeping the entries in the
+// memory of the service instance, evicting the least recently used ones.
+package lru
@@ -0,0 +1,79 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package lru
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mainflux/mainflux/things"
+)
+
+const (
+	keyPrefix = "thing_key"
+	idPrefix  = "thing"
+)
+
+var _ things.ThingCache = (*thingCache)(nil)
+
+type thingCache struct {
+	cache *cache
+	next  things.ThingCache
+}
+
+// NewThingCache returns in-memory thing cache implementation, holding up to
+// size keys for ttl at most. Payload transformations are read by the
+// adapters, so they are passed on to the provided cache.
+func NewThingCache(next things.ThingCache, size int, ttl time.Duration) things.ThingCache {
+	return &thingCache{
+		cache: newCache(size, ttl),
+		next:  next,
+	}
+}
+
+func (tc *thingCache) Save(_ context.Context, thingKey, thingID string, expiresAt time.Time) error {
+	tc.cache.setUntil(fmt.Sprintf("%s:%s", keyPrefix, thingKey), thingID, expiresAt)
+	tc.cache.setUntil(fmt.Sprintf("%s:%s", idPrefix, thingID), thingKey, expiresAt)
+	return nil
+}
+
+func (tc *thingCache) ID(_ context.Context, thingKey string) (string, error) {
+	id, ok := tc.cache.get(fmt.Sprintf("%s:%s", keyPrefix, thingKey))
+	if !ok {
+		return "", things.ErrNotFound
+	}
+
+	return id.(string), nil
+}
+
+func (tc *thingCache) IDs(_ context.Context, thingKeys []string) ([]string, error) {
+	ids := make([]string, len(thingKeys))
+	for i, key := range thingKeys {
+		if id, ok := tc.cache.get(fmt.Sprintf("%s:%s", keyPrefix, key)); ok {
+			ids[i] = id.(string)
+		}
+	}
+
+	return ids, nil
+}
+
+func (tc *thingCache) SaveTransform(ctx context.Context, thingID string, tr things.Transform) error {
+	return tc.next.SaveTransform(ctx, thingID, tr)
+}
+
+func (tc *thingCache) Remove(_ context.Context, thingID string) error {
+	tid := fmt.Sprintf("%s:%s", idPrefix, thingID)
+	key, ok := tc.cache.get(tid)
+	if !ok {
+		return nil
+	}
+
+	tc.cache.remove(tid, fmt.Sprintf("%s:%s", keyPrefix, key))
+	return nil
+}
@@ -0,0 +1,128 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package lru_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/things"
+	"github.com/mainflux/mainflux/things/lru"
+	"github.com/mainflux/mainflux/things/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThingID(t *testing.T) {
+	thingCache := lru.NewThingCache(mocks.NewThingCache(), 2, 0)
+
+	err := thingCache.Save(context.Background(), "key", "1", time.Time{})
+	require.Nil(t, err, fmt.Sprintf("Save thing to cache: expected nil got %s", err))
+
+	cases := []struct {
+		desc string
+		ID   string
+		key  string
+		err  error
+	}{
+		{
+			desc: "Get thing ID from cache",
+			ID:   "1",
+			key:  "key",
+			err:  nil,
+		},
+		{
+			desc: "Get thing ID from cache for non-existing thing",
+			ID:   "",
+			key:  "unknown",
+			err:  things.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		id, err := thingCache.ID(context.Background(), tc.key)
+		assert.Equal(t, tc.ID, id, fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.ID, id))
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+	}
+}
+
+func TestThingIDs(t *testing.T) {
+	thingCache := lru.NewThingCache(mocks.NewThingCache(), 2, 0)
+
+	err := thingCache.Save(context.Background(), "key", "1", time.Time{})
+	require.Nil(t, err, fmt.Sprintf("Save thing to cache: expected nil got %s", err))
+
+	ids, err := thingCache.IDs(context.Background(), []string{"unknown", "key"})
+	assert.Nil(t, err, fmt.Sprintf("Get thing IDs from cache: expected nil got %s", err))
+	assert.Equal(t, []string{"", "1"}, ids, fmt.Sprintf("Get thing IDs from cache: expected %v got %v", []string{"", "1"}, ids))
+}
+
+func TestThingRemove(t *testing.T) {
+	thingCache := lru.NewThingCache(mocks.NewThingCache(), 10, 0)
+
+	err := thingCache.Save(context.Background(), "key", "1", time.Time{})
+	require.Nil(t, err, fmt.Sprintf("Save thing to cache: expected nil got %s", err))
+
+	for i := 0; i < 2; i++ {
+		err := thingCache.Remove(context.Background(), "1")
+		assert.Nil(t, err, fmt.Sprintf("#%d: remove thing from cache: expected nil got %s", i, err))
+
+		_, err = thingCache.ID(context.Background(), "key")
+		assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("#%d: expected %s got %s", i, things.ErrNotFound, err))
+	}
+}
+
+func TestThingEviction(t *testing.T) {
+	// Each key takes up two entries, one of them indexing it by thing ID.
+	thingCache := lru.NewThingCache(mocks.NewThingCache(), 4, 0)
+
+	for i := 0; i < 3; i++ {
+		err := thingCache.Save(context.Background(), fmt.Sprintf("key-%d", i), fmt.Sprintf("%d", i), time.Time{})
+		require.Nil(t, err, fmt.Sprintf("Save thing to cache: expected nil got %s", err))
+	}
+
+	_, err := thingCache.ID(context.Background(), "key-0")
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("least recently used key: expected %s got %s", things.ErrNotFound, err))
+
+	id, err := thingCache.ID(context.Background(), "key-2")
+	assert.Nil(t, err, fmt.Sprintf("most recently used key: expected nil got %s", err))
+	assert.Equal(t, "2", id, fmt.Sprintf("most recently used key: expected %s got %s", "2", id))
+}
+
+func TestThingExpiration(t *testing.T) {
+	ttl := 10 * time.Millisecond
+	thingCache := lru.NewThingCache(mocks.NewThingCache(), 10, ttl)
+
+	err := thingCache.Save(context.Background(), "key", "1", time.Time{})
+	require.Nil(t, err, fmt.Sprintf("Save thing to cache: expected nil got %s", err))
+
+	_, err = thingCache.ID(context.Background(), "key")
+	assert.Nil(t, err, fmt.Sprintf("fresh key: expected nil got %s", err))
+
+	time.Sleep(2 * ttl)
+
+	_, err = thingCache.ID(context.Background(), "key")
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("expired key: expected %s got %s", things.ErrNotFound, err))
+}
+
+func TestThingKeyExpiration(t *testing.T) {
+	thingCache := lru.NewThingCache(mocks.NewThingCache(), 10, time.Hour)
+
+	err := thingCache.Save(context.Background(), "key", "1", time.Now().Add(10*time.Millisecond))
+	require.Nil(t, err, fmt.Sprintf("Save thing to cache: expected nil got %s", err))
+
+	_, err = thingCache.ID(context.Background(), "key")
+	assert.Nil(t, err, fmt.Sprintf("unexpired key: expected nil got %s", err))
+
+	time.Sleep(20 * time.Millisecond)
+
+	_, err = thingCache.ID(context.Background(), "key")
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("expired key: expected %s got %s", things.ErrNotFound, err))
+}
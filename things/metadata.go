@@ -0,0 +1,37 @@
+//
+// Copyright (c) 2018
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package things
+
+// MergeMetadata applies the patch to the metadata following the JSON merge
+// patch semantics (RFC 7386): nested objects are merged recursively, null
+// values remove the corresponding keys and all the other values replace the
+// existing ones. The provided metadata is left intact.
+func MergeMetadata(metadata, patch map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(metadata))
+	for k, v := range metadata {
+		merged[k] = v
+	}
+
+	for k, v := range patch {
+		if v == nil {
+			delete(merged, k)
+			continue
+		}
+
+		pv, ok := v.(map[string]interface{})
+		if !ok {
+			merged[k] = v
+			continue
+		}
+
+		mv, _ := merged[k].(map[string]interface{})
+		merged[k] = MergeMetadata(mv, pv)
+	}
+
+	return merged
+}
@@ -0,0 +1,67 @@
+//
+// Copyright (c) 2018
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package things_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux/things"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeMetadata(t *testing.T) {
+	cases := []struct {
+		desc     string
+		metadata map[string]interface{}
+		patch    map[string]interface{}
+		merged   map[string]interface{}
+	}{
+		{
+			desc:     "add new key",
+			metadata: map[string]interface{}{"a": "b"},
+			patch:    map[string]interface{}{"c": "d"},
+			merged:   map[string]interface{}{"a": "b", "c": "d"},
+		},
+		{
+			desc:     "replace existing key",
+			metadata: map[string]interface{}{"a": "b"},
+			patch:    map[string]interface{}{"a": "c"},
+			merged:   map[string]interface{}{"a": "c"},
+		},
+		{
+			desc:     "remove existing key",
+			metadata: map[string]interface{}{"a": "b", "c": "d"},
+			patch:    map[string]interface{}{"a": nil},
+			merged:   map[string]interface{}{"c": "d"},
+		},
+		{
+			desc:     "merge nested object",
+			metadata: map[string]interface{}{"a": map[string]interface{}{"b": "c", "d": "e"}},
+			patch:    map[string]interface{}{"a": map[string]interface{}{"b": nil, "f": "g"}},
+			merged:   map[string]interface{}{"a": map[string]interface{}{"d": "e", "f": "g"}},
+		},
+		{
+			desc:     "replace value with nested object",
+			metadata: map[string]interface{}{"a": "b"},
+			patch:    map[string]interface{}{"a": map[string]interface{}{"c": "d"}},
+			merged:   map[string]interface{}{"a": map[string]interface{}{"c": "d"}},
+		},
+		{
+			desc:     "merge into empty metadata",
+			metadata: nil,
+			patch:    map[string]interface{}{"a": "b", "c": nil},
+			merged:   map[string]interface{}{"a": "b"},
+		},
+	}
+
+	for _, tc := range cases {
+		merged := things.MergeMetadata(tc.metadata, tc.patch)
+		assert.Equal(t, tc.merged, merged, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.merged, merged))
+	}
+}
@@ -0,0 +1,84 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mainflux/mainflux/things"
+)
+
+var _ things.AuditRepository = (*auditRepositoryMock)(nil)
+
+type auditRepositoryMock struct {
+	mu     sync.Mutex
+	events []things.AuditEvent
+}
+
+// NewAuditRepository creates in-memory audit repository.
+func NewAuditRepository() things.AuditRepository {
+	return &auditRepositoryMock{}
+}
+
+func (arm *auditRepositoryMock) Save(_ context.Context, events ...things.AuditEvent) error {
+	arm.mu.Lock()
+	defer arm.mu.Unlock()
+
+	arm.events = append(arm.events, events...)
+
+	return nil
+}
+
+func (arm *auditRepositoryMock) RetrieveAll(_ context.Context, owner string, query things.AuditQuery, offset, limit uint64) (things.AuditPage, error) {
+	arm.mu.Lock()
+	defer arm.mu.Unlock()
+
+	matched := []things.AuditEvent{}
+	for i := len(arm.events) - 1; i >= 0; i-- {
+		e := arm.events[i]
+		if e.Owner == owner && matchesQuery(e, query) {
+			matched = append(matched, e)
+		}
+	}
+
+	page := things.AuditPage{
+		Events: []things.AuditEvent{},
+		PageMetadata: things.PageMetadata{
+			Total:  uint64(len(matched)),
+			Offset: offset,
+			Limit:  limit,
+		},
+	}
+
+	if offset >= uint64(len(matched)) {
+		return page, nil
+	}
+
+	end := offset + limit
+	if end > uint64(len(matched)) {
+		end = uint64(len(matched))
+	}
+	page.Events = matched[offset:end]
+
+	return page, nil
+}
+
+func matchesQuery(e things.AuditEvent, q things.AuditQuery) bool {
+	switch {
+	case q.Actor != "" && e.Actor != q.Actor,
+		q.Operation != "" && e.Operation != q.Operation,
+		q.EntityKind != "" && e.EntityKind != q.EntityKind,
+		q.EntityID != "" && e.EntityID != q.EntityID,
+		!q.From.IsZero() && e.OccurredAt.Before(q.From),
+		!q.To.IsZero() && e.OccurredAt.After(q.To):
+		return false
+	default:
+		return true
+	}
+}
@@ -8,9 +8,9 @@
 package mocks
 
 import (
+	"context"
 	"fmt"
 	"sort"
-	"strconv"
 	"strings"
 	"sync"
 
@@ -33,6 +33,7 @@ type channelRepositoryMock struct {
 	channels map[string]things.Channel
 	tconns   chan Connection                      // used for syncronization with thing repo
 	cconns   map[string]map[string]things.Channel // used to track connections
+	access   map[string]things.ConnectionAccess   // used to track connection access
 	things   things.ThingRepository
 }
 
@@ -42,36 +43,59 @@ func NewChannelRepository(repo things.ThingRepository, tconns chan Connection) t
 		channels: make(map[string]things.Channel),
 		tconns:   tconns,
 		cconns:   make(map[string]map[string]things.Channel),
+		access:   make(map[string]things.ConnectionAccess),
 		things:   repo,
 	}
 }
 
-func (crm *channelRepositoryMock) Save(channel things.Channel) (string, error) {
+func (crm *channelRepositoryMock) Save(_ context.Context, channel things.Channel) (string, error) {
 	crm.mu.Lock()
 	defer crm.mu.Unlock()
 
 	crm.counter++
-	channel.ID = strconv.FormatUint(crm.counter, 10)
+	channel.ID = newID(crm.counter)
 	crm.channels[key(channel.Owner, channel.ID)] = channel
 
 	return channel.ID, nil
 }
 
-func (crm *channelRepositoryMock) Update(channel things.Channel) error {
+func (crm *channelRepositoryMock) Update(_ context.Context, channel things.Channel) error {
 	crm.mu.Lock()
 	defer crm.mu.Unlock()
 
 	dbKey := key(channel.Owner, channel.ID)
 
-	if _, ok := crm.channels[dbKey]; !ok {
+	old, ok := crm.channels[dbKey]
+	if !ok {
 		return things.ErrNotFound
 	}
 
+	channel.State = old.State
 	crm.channels[dbKey] = channel
 	return nil
 }
 
-func (crm *channelRepositoryMock) RetrieveByID(owner, id string) (things.Channel, error) {
+func (crm *channelRepositoryMock) Patch(_ context.Context, owner, id string, patch map[string]interface{}) (things.Channel, error) {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	dbKey := key(owner, id)
+
+	ch, ok := crm.channels[dbKey]
+	if !ok {
+		return things.Channel{}, things.ErrNotFound
+	}
+
+	ch, err := things.PatchChannel(ch, patch)
+	if err != nil {
+		return things.Channel{}, err
+	}
+
+	crm.channels[dbKey] = ch
+	return ch, nil
+}
+
+func (crm *channelRepositoryMock) RetrieveByID(_ context.Context, owner, id string) (things.Channel, error) {
 	if c, ok := crm.channels[key(owner, id)]; ok {
 		return c, nil
 	}
@@ -79,22 +103,102 @@ func (crm *channelRepositoryMock) RetrieveByID(owner, id string) (things.Channel
 	return things.Channel{}, things.ErrNotFound
 }
 
-func (crm *channelRepositoryMock) RetrieveAll(owner string, offset, limit uint64, name string) (things.ChannelsPage, error) {
+func (crm *channelRepositoryMock) RetrieveState(_ context.Context, id string) (things.State, error) {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	for _, ch := range crm.channels {
+		if ch.ID == id {
+			return ch.State, nil
+		}
+	}
+
+	return "", things.ErrNotFound
+}
+
+func (crm *channelRepositoryMock) UpdateState(_ context.Context, owner, id string, state things.State) error {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	dbKey := key(owner, id)
+
+	ch, ok := crm.channels[dbKey]
+	if !ok {
+		return things.ErrNotFound
+	}
+
+	ch.State = state
+	crm.channels[dbKey] = ch
+
+	return nil
+}
+
+func (crm *channelRepositoryMock) Archive(_ context.Context, owner string, selector map[string]interface{}, tags things.TagFilter) ([]things.Channel, error) {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	items := []things.Channel{}
+	prefix := fmt.Sprintf("%s-", owner)
+	for k, ch := range crm.channels {
+		if !strings.HasPrefix(k, prefix) || ch.State != things.Active || !contains(ch.Metadata, selector) || !tags.Matches(ch.Tags) {
+			continue
+		}
+
+		ch.State = things.Archived
+		crm.channels[k] = ch
+		items = append(items, ch)
+	}
+
+	return items, nil
+}
+
+func (crm *channelRepositoryMock) RetrieveAll(_ context.Context, owner string, offset, limit uint64, name string, metadata map[string]interface{}, tags things.TagFilter, after string) (things.ChannelsPage, error) {
+	return crm.retrieve(owner, nil, offset, limit, metadata, tags, after)
+}
+
+func (crm *channelRepositoryMock) RetrieveAccessible(_ context.Context, user string, shared []string, offset, limit uint64, name string, metadata map[string]interface{}, tags things.TagFilter, after string) (things.ChannelsPage, error) {
+	return crm.retrieve(user, shared, offset, limit, metadata, tags, after)
+}
+
+func (crm *channelRepositoryMock) CountAccessible(_ context.Context, user string, shared []string, name string, metadata map[string]interface{}, tags things.TagFilter) (uint64, error) {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	prefix := fmt.Sprintf("%s-", user)
+	count := uint64(0)
+	for k, v := range crm.channels {
+		accessible := strings.HasPrefix(k, prefix) || includes(shared, v.ID)
+		if accessible && contains(v.Metadata, metadata) && tags.Matches(v.Tags) {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (crm *channelRepositoryMock) retrieve(owner string, shared []string, offset, limit uint64, metadata map[string]interface{}, tags things.TagFilter, after string) (things.ChannelsPage, error) {
 	channels := make([]things.Channel, 0)
 
 	if offset < 0 || limit <= 0 {
 		return things.ChannelsPage{}, nil
 	}
 
-	first := uint64(offset) + 1
+	first, err := firstID(offset, after)
+	if err != nil {
+		return things.ChannelsPage{}, err
+	}
+	if after != "" {
+		offset = 0
+	}
 	last := first + uint64(limit)
 
 	// This obscure way to examine map keys is enforced by the key structure
 	// itself (see mocks/commons.go).
 	prefix := fmt.Sprintf("%s-", owner)
 	for k, v := range crm.channels {
-		id, _ := strconv.ParseUint(v.ID, 10, 64)
-		if strings.HasPrefix(k, prefix) && id >= first && id < last {
+		id := seqNumber(v.ID)
+		accessible := strings.HasPrefix(k, prefix) || includes(shared, v.ID)
+		if accessible && id >= first && id < last && contains(v.Metadata, metadata) && tags.Matches(v.Tags) {
 			channels = append(channels, v)
 		}
 	}
@@ -106,16 +210,17 @@ func (crm *channelRepositoryMock) RetrieveAll(owner string, offset, limit uint64
 	page := things.ChannelsPage{
 		Channels: channels,
 		PageMetadata: things.PageMetadata{
-			Total:  crm.counter,
-			Offset: offset,
-			Limit:  limit,
+			Total:    crm.counter,
+			Offset:   offset,
+			Limit:    limit,
+			Metadata: metadata,
 		},
 	}
 
 	return page, nil
 }
 
-func (crm *channelRepositoryMock) RetrieveByThing(owner, thingID string, offset, limit uint64) (things.ChannelsPage, error) {
+func (crm *channelRepositoryMock) RetrieveByThing(_ context.Context, owner, thingID string, offset, limit uint64) (things.ChannelsPage, error) {
 	channels := make([]things.Channel, 0)
 
 	if offset < 0 || limit <= 0 {
@@ -126,7 +231,7 @@ func (crm *channelRepositoryMock) RetrieveByThing(owner, thingID string, offset,
 	last := first + uint64(limit)
 
 	for _, v := range crm.cconns[thingID] {
-		id, _ := strconv.ParseUint(v.ID, 10, 64)
+		id := seqNumber(v.ID)
 		if id >= first && id < last {
 			channels = append(channels, v)
 		}
@@ -148,7 +253,37 @@ func (crm *channelRepositoryMock) RetrieveByThing(owner, thingID string, offset,
 	return page, nil
 }
 
-func (crm *channelRepositoryMock) Remove(owner, id string) error {
+func (crm *channelRepositoryMock) Transfer(_ context.Context, owner, id, newOwner string, keepConns bool) error {
+	channel, ok := crm.channels[key(owner, id)]
+	if !ok {
+		return things.ErrNotFound
+	}
+
+	delete(crm.channels, key(owner, id))
+	channel.Owner = newOwner
+	crm.channels[key(newOwner, id)] = channel
+
+	for thingID, chs := range crm.cconns {
+		if _, ok := chs[id]; !ok {
+			continue
+		}
+		if keepConns {
+			chs[id] = channel
+			continue
+		}
+		delete(crm.cconns[thingID], id)
+	}
+	if !keepConns {
+		crm.tconns <- Connection{
+			chanID:    id,
+			connected: false,
+		}
+	}
+
+	return nil
+}
+
+func (crm *channelRepositoryMock) Remove(_ context.Context, owner, id string) error {
 	delete(crm.channels, key(owner, id))
 	// delete channel from any thing list
 	for thk := range crm.cconns {
@@ -161,78 +296,133 @@ func (crm *channelRepositoryMock) Remove(owner, id string) error {
 	return nil
 }
 
-func (crm *channelRepositoryMock) Connect(owner, chanID, thingID string) error {
-	channel, err := crm.RetrieveByID(owner, chanID)
-	if err != nil {
-		return err
+func (crm *channelRepositoryMock) Connect(ctx context.Context, conns ...things.Connection) error {
+	channels := make([]things.Channel, len(conns))
+	ths := make([]things.Thing, len(conns))
+	failures := []things.ConnectionFailure{}
+	for i, conn := range conns {
+		channel, err := crm.RetrieveByID(ctx, conn.Owner, conn.ChanID)
+		if err == nil {
+			ths[i], err = crm.things.RetrieveByID(ctx, conn.Owner, conn.ThingID)
+		}
+		if err != nil {
+			failures = append(failures, connectionFailure(conn, err))
+			continue
+		}
+		channels[i] = channel
 	}
 
-	thing, err := crm.things.RetrieveByID(owner, thingID)
-	if err != nil {
-		return err
+	if len(failures) > 0 {
+		return &things.ConnectionError{Failures: failures}
 	}
 
-	crm.tconns <- Connection{
-		chanID:    chanID,
-		thing:     thing,
-		connected: true,
-	}
-	if _, ok := crm.cconns[thingID]; !ok {
-		crm.cconns[thingID] = make(map[string]things.Channel)
+	for i, conn := range conns {
+		crm.tconns <- Connection{
+			chanID:    conn.ChanID,
+			thing:     ths[i],
+			connected: true,
+		}
+		if _, ok := crm.cconns[conn.ThingID]; !ok {
+			crm.cconns[conn.ThingID] = make(map[string]things.Channel)
+		}
+		crm.cconns[conn.ThingID][conn.ChanID] = channels[i]
+		crm.access[key(conn.ChanID, conn.ThingID)] = conn.Access
 	}
-	crm.cconns[thingID][chanID] = channel
+
 	return nil
 }
 
-func (crm *channelRepositoryMock) Disconnect(owner, chanID, thingID string) error {
-	if _, ok := crm.cconns[thingID]; !ok {
-		return things.ErrNotFound
+func (crm *channelRepositoryMock) Disconnect(_ context.Context, conns ...things.Connection) error {
+	failures := []things.ConnectionFailure{}
+	for _, conn := range conns {
+		if _, ok := crm.cconns[conn.ThingID][conn.ChanID]; !ok {
+			failures = append(failures, connectionFailure(conn, things.ErrNotFound))
+		}
 	}
 
-	if _, ok := crm.cconns[thingID][chanID]; !ok {
-		return things.ErrNotFound
+	if len(failures) > 0 {
+		return &things.ConnectionError{Failures: failures}
 	}
 
-	crm.tconns <- Connection{
-		chanID:    chanID,
-		thing:     things.Thing{ID: thingID, Owner: owner},
-		connected: false,
+	for _, conn := range conns {
+		crm.tconns <- Connection{
+			chanID:    conn.ChanID,
+			thing:     things.Thing{ID: conn.ThingID, Owner: conn.Owner},
+			connected: false,
+		}
+		delete(crm.cconns[conn.ThingID], conn.ChanID)
+		delete(crm.access, key(conn.ChanID, conn.ThingID))
 	}
-	delete(crm.cconns[thingID], chanID)
+
 	return nil
 }
 
-func (crm *channelRepositoryMock) HasThing(chanID, token string) (string, error) {
-	tid, err := crm.things.RetrieveByKey(token)
+func (crm *channelRepositoryMock) RetrieveConnected(_ context.Context, thingID string) ([]string, error) {
+	ids := []string{}
+	for id := range crm.cconns[thingID] {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	return ids, nil
+}
+
+func (crm *channelRepositoryMock) RetrieveRestricted(_ context.Context, thingID string) (map[string]things.ConnectionAccess, error) {
+	restricted := map[string]things.ConnectionAccess{}
+	for chanID := range crm.cconns[thingID] {
+		if access := crm.access[key(chanID, thingID)]; access != things.FullAccess {
+			restricted[chanID] = access
+		}
+	}
+
+	return restricted, nil
+}
+
+func (crm *channelRepositoryMock) HasThing(ctx context.Context, chanID, token string) (things.Thing, things.ConnectionAccess, error) {
+	th, err := crm.things.RetrieveByKey(ctx, token)
 	if err != nil {
-		return "", things.ErrNotFound
+		return things.Thing{}, things.FullAccess, things.ErrNotFound
 	}
 
-	chans, ok := crm.cconns[tid]
+	chans, ok := crm.cconns[th.ID]
 	if !ok {
-		return "", things.ErrNotFound
+		return things.Thing{}, things.FullAccess, things.ErrNotFound
 	}
 
 	if _, ok := chans[chanID]; !ok {
-		return "", things.ErrNotFound
+		return things.Thing{}, things.FullAccess, things.ErrNotFound
 	}
 
-	return tid, nil
+	return th, crm.access[key(chanID, th.ID)], nil
 }
 
 type channelCacheMock struct {
-	mu       sync.Mutex
-	channels map[string]string
+	mu        sync.Mutex
+	channels  map[string]string
+	limits    map[string]things.RateLimit
+	rules     map[string]things.Validation
+	transform map[string]things.Transform
+	delivery  map[string]things.Delivery
+	protocols map[string][]string
+	states    map[string]things.State
+	connected map[string][]string
 }
 
 // NewChannelCache returns mock cache instance.
 func NewChannelCache() things.ChannelCache {
 	return &channelCacheMock{
-		channels: make(map[string]string),
+		channels:  make(map[string]string),
+		limits:    make(map[string]things.RateLimit),
+		rules:     make(map[string]things.Validation),
+		transform: make(map[string]things.Transform),
+		delivery:  make(map[string]things.Delivery),
+		protocols: make(map[string][]string),
+		states:    make(map[string]things.State),
+		connected: make(map[string][]string),
 	}
 }
 
-func (ccm *channelCacheMock) Connect(chanID, thingID string) error {
+func (ccm *channelCacheMock) Connect(_ context.Context, chanID, thingID string) error {
 	ccm.mu.Lock()
 	defer ccm.mu.Unlock()
 
@@ -240,14 +430,14 @@ func (ccm *channelCacheMock) Connect(chanID, thingID string) error {
 	return nil
 }
 
-func (ccm *channelCacheMock) HasThing(chanID, thingID string) bool {
+func (ccm *channelCacheMock) HasThing(_ context.Context, chanID, thingID string) bool {
 	ccm.mu.Lock()
 	defer ccm.mu.Unlock()
 
 	return ccm.channels[chanID] == thingID
 }
 
-func (ccm *channelCacheMock) Disconnect(chanID, thingID string) error {
+func (ccm *channelCacheMock) Disconnect(_ context.Context, chanID, thingID string) error {
 	ccm.mu.Lock()
 	defer ccm.mu.Unlock()
 
@@ -255,10 +445,133 @@ func (ccm *channelCacheMock) Disconnect(chanID, thingID string) error {
 	return nil
 }
 
-func (ccm *channelCacheMock) Remove(chanID string) error {
+func (ccm *channelCacheMock) SaveRateLimit(_ context.Context, chanID string, rl things.RateLimit) error {
+	ccm.mu.Lock()
+	defer ccm.mu.Unlock()
+
+	if rl.Rate <= 0 {
+		delete(ccm.limits, chanID)
+		return nil
+	}
+
+	ccm.limits[chanID] = rl
+	return nil
+}
+
+func (ccm *channelCacheMock) SaveValidation(_ context.Context, chanID string, v things.Validation) error {
+	ccm.mu.Lock()
+	defer ccm.mu.Unlock()
+
+	if len(v.Units) == 0 && len(v.Ranges) == 0 && v.Mode == "" {
+		delete(ccm.rules, chanID)
+		return nil
+	}
+
+	ccm.rules[chanID] = v
+	return nil
+}
+
+func (ccm *channelCacheMock) SaveTransform(_ context.Context, chanID string, tr things.Transform) error {
+	ccm.mu.Lock()
+	defer ccm.mu.Unlock()
+
+	if tr.Template == "" {
+		delete(ccm.transform, chanID)
+		return nil
+	}
+
+	ccm.transform[chanID] = tr
+	return nil
+}
+
+func (ccm *channelCacheMock) SaveDelivery(_ context.Context, chanID string, d things.Delivery) error {
+	ccm.mu.Lock()
+	defer ccm.mu.Unlock()
+
+	if d.Mode == "" {
+		delete(ccm.delivery, chanID)
+		return nil
+	}
+
+	ccm.delivery[chanID] = d
+	return nil
+}
+
+func (ccm *channelCacheMock) SaveProtocols(_ context.Context, chanID string, protocols []string) error {
+	ccm.mu.Lock()
+	defer ccm.mu.Unlock()
+
+	if len(protocols) == 0 {
+		delete(ccm.protocols, chanID)
+		return nil
+	}
+
+	ccm.protocols[chanID] = protocols
+	return nil
+}
+
+func (ccm *channelCacheMock) SaveState(_ context.Context, chanID string, state things.State) error {
+	ccm.mu.Lock()
+	defer ccm.mu.Unlock()
+
+	ccm.states[chanID] = state
+	return nil
+}
+
+func (ccm *channelCacheMock) State(_ context.Context, chanID string) (things.State, error) {
+	ccm.mu.Lock()
+	defer ccm.mu.Unlock()
+
+	state, ok := ccm.states[chanID]
+	if !ok {
+		return "", things.ErrNotFound
+	}
+
+	return state, nil
+}
+
+func (ccm *channelCacheMock) SaveConnected(_ context.Context, thingID string, chanIDs []string) error {
+	ccm.mu.Lock()
+	defer ccm.mu.Unlock()
+
+	ccm.connected[thingID] = chanIDs
+	return nil
+}
+
+func (ccm *channelCacheMock) Connected(_ context.Context, thingID string) ([]string, error) {
+	ccm.mu.Lock()
+	defer ccm.mu.Unlock()
+
+	chanIDs, ok := ccm.connected[thingID]
+	if !ok {
+		return nil, things.ErrNotFound
+	}
+
+	return chanIDs, nil
+}
+
+func (ccm *channelCacheMock) RemoveConnected(_ context.Context, thingID string) error {
+	ccm.mu.Lock()
+	defer ccm.mu.Unlock()
+
+	delete(ccm.connected, thingID)
+	return nil
+}
+
+func (ccm *channelCacheMock) Remove(_ context.Context, chanID string) error {
 	ccm.mu.Lock()
 	defer ccm.mu.Unlock()
 
 	delete(ccm.channels, chanID)
+	delete(ccm.limits, chanID)
+	delete(ccm.rules, chanID)
+	delete(ccm.delivery, chanID)
+	delete(ccm.protocols, chanID)
+	delete(ccm.states, chanID)
+	for thingID, chanIDs := range ccm.connected {
+		if includes(chanIDs, chanID) {
+			delete(ccm.connected, thingID)
+		}
+	}
 	return nil
 }
@@ -0,0 +1,101 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package mocks
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux/things"
+)
+
+var _ things.ChannelTokenRepository = (*channelTokenRepositoryMock)(nil)
+
+type channelTokenRepositoryMock struct {
+	mu       sync.Mutex
+	tokens   map[string]things.ChannelToken
+	channels things.ChannelRepository
+}
+
+// NewChannelTokenRepository creates in-memory channel access tokens
+// repository.
+func NewChannelTokenRepository(channelsRepo things.ChannelRepository) things.ChannelTokenRepository {
+	return &channelTokenRepositoryMock{
+		tokens:   make(map[string]things.ChannelToken),
+		channels: channelsRepo,
+	}
+}
+
+func (ctrm *channelTokenRepositoryMock) Save(ctx context.Context, ct things.ChannelToken) (string, error) {
+	ctrm.mu.Lock()
+	defer ctrm.mu.Unlock()
+
+	if _, err := ctrm.channels.RetrieveByID(ctx, ct.Owner, ct.ChannelID); err != nil {
+		return "", err
+	}
+
+	for _, t := range ctrm.tokens {
+		if t.ID == ct.ID || t.Token == ct.Token {
+			return "", things.ErrConflict
+		}
+	}
+
+	ctrm.tokens[ct.Token] = ct
+	return ct.ID, nil
+}
+
+func (ctrm *channelTokenRepositoryMock) RetrieveAll(_ context.Context, owner, chanID string) ([]things.ChannelToken, error) {
+	ctrm.mu.Lock()
+	defer ctrm.mu.Unlock()
+
+	now := time.Now()
+	items := []things.ChannelToken{}
+	for _, ct := range ctrm.tokens {
+		if ct.Owner == owner && ct.ChannelID == chanID && ct.ExpiresAt.After(now) {
+			items = append(items, ct)
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].ID < items[j].ID
+	})
+
+	return items, nil
+}
+
+func (ctrm *channelTokenRepositoryMock) RetrieveByToken(ctx context.Context, chanID, token string) (things.ChannelToken, error) {
+	ctrm.mu.Lock()
+	defer ctrm.mu.Unlock()
+
+	ct, ok := ctrm.tokens[token]
+	if !ok || ct.ChannelID != chanID || !ct.ExpiresAt.After(time.Now()) {
+		return things.ChannelToken{}, things.ErrNotFound
+	}
+
+	if _, err := ctrm.channels.RetrieveByID(ctx, ct.Owner, ct.ChannelID); err != nil {
+		return things.ChannelToken{}, things.ErrNotFound
+	}
+
+	return ct, nil
+}
+
+func (ctrm *channelTokenRepositoryMock) Remove(_ context.Context, owner, chanID, id string) error {
+	ctrm.mu.Lock()
+	defer ctrm.mu.Unlock()
+
+	for token, ct := range ctrm.tokens {
+		if ct.Owner == owner && ct.ChannelID == chanID && ct.ID == id {
+			delete(ctrm.tokens, token)
+			return nil
+		}
+	}
+
+	return things.ErrNotFound
+}
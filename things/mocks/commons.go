@@ -7,7 +7,18 @@
 
 package mocks
 
-import "fmt"
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/mainflux/mainflux/things"
+)
+
+// uuidPrefix starts the identifiers generated by the mocks, which are valid
+// UUIDs ending with the sequence number of the entity.
+const uuidPrefix = "123e4567-e89b-12d3-a456-"
 
 // Since mocks will store data in map, and they need to resemble the real
 // identifiers as much as possible, a key will be created as combination of
@@ -16,3 +27,63 @@ import "fmt"
 func key(owner string, id string) string {
 	return fmt.Sprintf("%s-%s", owner, id)
 }
+
+// contains mimics PostgreSQL JSONB containment operator on top level keys.
+func contains(metadata, selector map[string]interface{}) bool {
+	for k, v := range selector {
+		if !reflect.DeepEqual(metadata[k], v) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// includes determines whether the identifiers include the provided one.
+func includes(ids []string, id string) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// firstID returns the identifier of the first entity of the page, which is
+// either the one past the offset, or the one past the cursor, if provided.
+func firstID(offset uint64, after string) (uint64, error) {
+	if after == "" {
+		return offset + 1, nil
+	}
+
+	if !strings.HasPrefix(after, uuidPrefix) {
+		return 0, things.ErrMalformedEntity
+	}
+
+	id, err := strconv.ParseUint(strings.TrimPrefix(after, uuidPrefix), 10, 64)
+	if err != nil {
+		return 0, things.ErrMalformedEntity
+	}
+
+	return id + 1, nil
+}
+
+// newID returns the identifier of the entity having the sequence number.
+func newID(seq uint64) string {
+	return fmt.Sprintf("%s%012d", uuidPrefix, seq)
+}
+
+// seqNumber returns the sequence number of the entity having the identifier.
+func seqNumber(id string) uint64 {
+	seq, _ := strconv.ParseUint(strings.TrimPrefix(id, uuidPrefix), 10, 64)
+	return seq
+}
+
+func connectionFailure(conn things.Connection, err error) things.ConnectionFailure {
+	return things.ConnectionFailure{
+		ChanID:  conn.ChanID,
+		ThingID: conn.ThingID,
+		Err:     err,
+	}
+}
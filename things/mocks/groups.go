@@ -0,0 +1,273 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package mocks
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/mainflux/mainflux/things"
+)
+
+const maxPageSize = 1 << 32
+
+var _ things.GroupRepository = (*groupRepositoryMock)(nil)
+
+type groupRepositoryMock struct {
+	mu       sync.Mutex
+	counter  uint64
+	groups   map[string]things.Group
+	members  map[string]map[string]bool
+	things   things.ThingRepository
+	channels things.ChannelRepository
+}
+
+// NewGroupRepository creates in-memory group repository.
+func NewGroupRepository(thingsRepo things.ThingRepository, channelsRepo things.ChannelRepository) things.GroupRepository {
+	return &groupRepositoryMock{
+		groups:   make(map[string]things.Group),
+		members:  make(map[string]map[string]bool),
+		things:   thingsRepo,
+		channels: channelsRepo,
+	}
+}
+
+func (grm *groupRepositoryMock) Save(_ context.Context, group things.Group) (string, error) {
+	grm.mu.Lock()
+	defer grm.mu.Unlock()
+
+	if group.Parent != "" {
+		if _, ok := grm.groups[key(group.Owner, group.Parent)]; !ok {
+			return "", things.ErrNotFound
+		}
+	}
+
+	grm.counter++
+	group.ID = newID(grm.counter)
+	grm.groups[key(group.Owner, group.ID)] = group
+
+	return group.ID, nil
+}
+
+func (grm *groupRepositoryMock) Update(_ context.Context, group things.Group) error {
+	grm.mu.Lock()
+	defer grm.mu.Unlock()
+
+	dbKey := key(group.Owner, group.ID)
+
+	g, ok := grm.groups[dbKey]
+	if !ok {
+		return things.ErrNotFound
+	}
+
+	g.Name = group.Name
+	g.Metadata = group.Metadata
+	grm.groups[dbKey] = g
+
+	return nil
+}
+
+func (grm *groupRepositoryMock) RetrieveByID(_ context.Context, owner, id string) (things.Group, error) {
+	grm.mu.Lock()
+	defer grm.mu.Unlock()
+
+	if g, ok := grm.groups[key(owner, id)]; ok {
+		return g, nil
+	}
+
+	return things.Group{}, things.ErrNotFound
+}
+
+func (grm *groupRepositoryMock) RetrieveAll(_ context.Context, owner, parent string, offset, limit uint64) (things.GroupsPage, error) {
+	grm.mu.Lock()
+	defer grm.mu.Unlock()
+
+	all := []things.Group{}
+	for _, g := range grm.groups {
+		if g.Owner == owner && (parent == "" || g.Parent == parent) {
+			all = append(all, g)
+		}
+	}
+
+	sort.SliceStable(all, func(i, j int) bool {
+		return all[i].ID < all[j].ID
+	})
+
+	items := []things.Group{}
+	if offset < uint64(len(all)) {
+		end := offset + limit
+		if end > uint64(len(all)) {
+			end = uint64(len(all))
+		}
+		items = all[offset:end]
+	}
+
+	return things.GroupsPage{
+		Groups: items,
+		PageMetadata: things.PageMetadata{
+			Total:  uint64(len(all)),
+			Offset: offset,
+			Limit:  limit,
+		},
+	}, nil
+}
+
+func (grm *groupRepositoryMock) RetrieveThings(ctx context.Context, owner, id string, offset, limit uint64) (things.ThingsPage, error) {
+	grm.mu.Lock()
+	defer grm.mu.Unlock()
+
+	all := []things.Thing{}
+	for thingID := range grm.memberIDs(owner, id) {
+		th, err := grm.things.RetrieveByID(ctx, owner, thingID)
+		if err != nil {
+			continue
+		}
+		all = append(all, th)
+	}
+
+	sort.SliceStable(all, func(i, j int) bool {
+		return all[i].ID < all[j].ID
+	})
+
+	items := []things.Thing{}
+	if offset < uint64(len(all)) {
+		end := offset + limit
+		if end > uint64(len(all)) {
+			end = uint64(len(all))
+		}
+		items = all[offset:end]
+	}
+
+	return things.ThingsPage{
+		Things: items,
+		PageMetadata: things.PageMetadata{
+			Total:  uint64(len(all)),
+			Offset: offset,
+			Limit:  limit,
+		},
+	}, nil
+}
+
+func (grm *groupRepositoryMock) Remove(_ context.Context, owner, id string) error {
+	grm.mu.Lock()
+	defer grm.mu.Unlock()
+
+	for _, sub := range grm.subgroups(owner, id) {
+		delete(grm.groups, key(owner, sub))
+		delete(grm.members, key(owner, sub))
+	}
+
+	return nil
+}
+
+func (grm *groupRepositoryMock) Assign(ctx context.Context, owner, id string, thingIDs ...string) error {
+	grm.mu.Lock()
+	defer grm.mu.Unlock()
+
+	dbKey := key(owner, id)
+	if _, ok := grm.groups[dbKey]; !ok {
+		return things.ErrNotFound
+	}
+
+	for _, thingID := range thingIDs {
+		if _, err := grm.things.RetrieveByID(ctx, owner, thingID); err != nil {
+			return err
+		}
+	}
+
+	if _, ok := grm.members[dbKey]; !ok {
+		grm.members[dbKey] = make(map[string]bool)
+	}
+
+	for _, thingID := range thingIDs {
+		grm.members[dbKey][thingID] = true
+	}
+
+	return nil
+}
+
+func (grm *groupRepositoryMock) Unassign(_ context.Context, owner, id, thingID string) error {
+	grm.mu.Lock()
+	defer grm.mu.Unlock()
+
+	dbKey := key(owner, id)
+	if !grm.members[dbKey][thingID] {
+		return things.ErrNotFound
+	}
+
+	delete(grm.members[dbKey], thingID)
+	return nil
+}
+
+func (grm *groupRepositoryMock) Connect(ctx context.Context, owner, id, chanID string) ([]string, error) {
+	grm.mu.Lock()
+	defer grm.mu.Unlock()
+
+	if _, err := grm.channels.RetrieveByID(ctx, owner, chanID); err != nil {
+		return nil, err
+	}
+
+	ids := []string{}
+	for thingID := range grm.memberIDs(owner, id) {
+		if grm.connected(owner, chanID, thingID) {
+			continue
+		}
+
+		conn := things.Connection{ChanID: chanID, ThingID: thingID, Owner: owner}
+		if err := grm.channels.Connect(ctx, conn); err != nil {
+			continue
+		}
+		ids = append(ids, thingID)
+	}
+
+	sort.Strings(ids)
+	return ids, nil
+}
+
+func (grm *groupRepositoryMock) connected(owner, chanID, thingID string) bool {
+	page, err := grm.channels.RetrieveByThing(context.Background(), owner, thingID, 0, maxPageSize)
+	if err != nil {
+		return false
+	}
+
+	for _, ch := range page.Channels {
+		if ch.ID == chanID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// subgroups returns the identifiers of the group and all of its descendants.
+func (grm *groupRepositoryMock) subgroups(owner, id string) []string {
+	if _, ok := grm.groups[key(owner, id)]; !ok {
+		return nil
+	}
+
+	ids := []string{id}
+	for _, g := range grm.groups {
+		if g.Owner == owner && g.Parent == id {
+			ids = append(ids, grm.subgroups(owner, g.ID)...)
+		}
+	}
+
+	return ids
+}
+
+func (grm *groupRepositoryMock) memberIDs(owner, id string) map[string]bool {
+	ids := make(map[string]bool)
+	for _, sub := range grm.subgroups(owner, id) {
+		for thingID := range grm.members[key(owner, sub)] {
+			ids[thingID] = true
+		}
+	}
+
+	return ids
+}
@@ -0,0 +1,112 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package mocks
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/mainflux/mainflux/things"
+)
+
+var _ things.HealthRepository = (*healthRepositoryMock)(nil)
+
+type healthRepositoryMock struct {
+	mu      sync.Mutex
+	records map[string]things.Health
+}
+
+// NewHealthRepository creates in-memory health records repository.
+func NewHealthRepository() things.HealthRepository {
+	return &healthRepositoryMock{
+		records: make(map[string]things.Health),
+	}
+}
+
+func (hrm *healthRepositoryMock) Save(_ context.Context, health things.Health) error {
+	hrm.mu.Lock()
+	defer hrm.mu.Unlock()
+
+	saved, ok := hrm.records[health.ThingID]
+	if !ok {
+		hrm.records[health.ThingID] = health
+		return nil
+	}
+
+	if health.Battery != nil {
+		saved.Battery = health.Battery
+	}
+	if health.RSSI != nil {
+		saved.RSSI = health.RSSI
+	}
+	if health.Firmware != "" {
+		saved.Firmware = health.Firmware
+	}
+	saved.Owner = health.Owner
+	saved.Reported = health.Reported
+	hrm.records[health.ThingID] = saved
+
+	return nil
+}
+
+func (hrm *healthRepositoryMock) RetrieveByThing(_ context.Context, owner, thingID string) (things.Health, error) {
+	hrm.mu.Lock()
+	defer hrm.mu.Unlock()
+
+	health, ok := hrm.records[thingID]
+	if !ok || health.Owner != owner {
+		return things.Health{}, things.ErrNotFound
+	}
+
+	return health, nil
+}
+
+func (hrm *healthRepositoryMock) RetrieveUnhealthy(_ context.Context, owner string, thresholds things.HealthThresholds, offset, limit uint64) (things.HealthPage, error) {
+	hrm.mu.Lock()
+	defer hrm.mu.Unlock()
+
+	items := []things.Health{}
+	for _, h := range hrm.records {
+		if h.Owner == owner && h.Unhealthy(thresholds) {
+			items = append(items, h)
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		bi, bj := items[i].Battery, items[j].Battery
+		switch {
+		case (bi == nil) != (bj == nil):
+			return bi != nil
+		case bi != nil && *bi != *bj:
+			return *bi < *bj
+		}
+		return items[i].ThingID < items[j].ThingID
+	})
+
+	page := things.HealthPage{
+		PageMetadata: things.PageMetadata{
+			Total:  uint64(len(items)),
+			Offset: offset,
+			Limit:  limit,
+		},
+		Records: []things.Health{},
+	}
+
+	if offset >= uint64(len(items)) {
+		return page, nil
+	}
+
+	end := offset + limit
+	if end > uint64(len(items)) {
+		end = uint64(len(items))
+	}
+	page.Records = items[offset:end]
+
+	return page, nil
+}
@@ -0,0 +1,70 @@
+//
+// Copyright (c) 2018
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mainflux/mainflux/things"
+)
+
+var _ things.HistoryRepository = (*historyRepositoryMock)(nil)
+
+type historyRepositoryMock struct {
+	mu      sync.Mutex
+	changes []things.Change
+}
+
+// NewHistoryRepository creates in-memory history repository.
+func NewHistoryRepository() things.HistoryRepository {
+	return &historyRepositoryMock{}
+}
+
+func (hrm *historyRepositoryMock) Save(_ context.Context, changes ...things.Change) error {
+	hrm.mu.Lock()
+	defer hrm.mu.Unlock()
+
+	hrm.changes = append(hrm.changes, changes...)
+
+	return nil
+}
+
+func (hrm *historyRepositoryMock) RetrieveAll(_ context.Context, owner, id string, offset, limit uint64) (things.ChangesPage, error) {
+	hrm.mu.Lock()
+	defer hrm.mu.Unlock()
+
+	matched := []things.Change{}
+	for i := len(hrm.changes) - 1; i >= 0; i-- {
+		c := hrm.changes[i]
+		if c.Owner == owner && c.EntityID == id {
+			matched = append(matched, c)
+		}
+	}
+
+	page := things.ChangesPage{
+		Changes: []things.Change{},
+		PageMetadata: things.PageMetadata{
+			Total:  uint64(len(matched)),
+			Offset: offset,
+			Limit:  limit,
+		},
+	}
+
+	if offset >= uint64(len(matched)) {
+		return page, nil
+	}
+
+	end := offset + limit
+	if end > uint64(len(matched)) {
+		end = uint64(len(matched))
+	}
+	page.Changes = matched[offset:end]
+
+	return page, nil
+}
@@ -8,7 +8,6 @@
 package mocks
 
 import (
-	"fmt"
 	"sync"
 
 	"github.com/mainflux/mainflux/things"
@@ -18,7 +17,7 @@ var _ things.IdentityProvider = (*identityProviderMock)(nil)
 
 type identityProviderMock struct {
 	mu      sync.Mutex
-	counter int
+	counter uint64
 }
 
 func (idp *identityProviderMock) ID() (string, error) {
@@ -26,7 +25,7 @@ func (idp *identityProviderMock) ID() (string, error) {
 	defer idp.mu.Unlock()
 
 	idp.counter++
-	return fmt.Sprintf("%s%012d", "123e4567-e89b-12d3-a456-", idp.counter), nil
+	return newID(idp.counter), nil
 }
 
 // NewIdentityProvider creates "mirror" identity provider, i.e. generated
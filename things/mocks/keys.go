@@ -0,0 +1,125 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package mocks
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux/things"
+)
+
+var (
+	_ things.KeyProvider          = (*keyProviderMock)(nil)
+	_ things.RevocationRepository = (*revocationRepositoryMock)(nil)
+)
+
+type keyProviderMock struct {
+	mu          sync.Mutex
+	keys        map[string]things.SignedKey
+	invitations map[string]things.Invitation
+}
+
+// NewKeyProvider creates signed key provider that keeps the issued keys and
+// invitations in memory.
+func NewKeyProvider() things.KeyProvider {
+	return &keyProviderMock{
+		keys:        make(map[string]things.SignedKey),
+		invitations: make(map[string]things.Invitation),
+	}
+}
+
+func (kpm *keyProviderMock) Issue(key things.SignedKey) (string, error) {
+	kpm.mu.Lock()
+	defer kpm.mu.Unlock()
+
+	signed := fmt.Sprintf("signed-%d", len(kpm.keys)+1)
+	kpm.keys[signed] = key
+	return signed, nil
+}
+
+func (kpm *keyProviderMock) Parse(signed string) (things.SignedKey, error) {
+	kpm.mu.Lock()
+	defer kpm.mu.Unlock()
+
+	key, ok := kpm.keys[signed]
+	if !ok || key.ExpiresAt.Before(time.Now()) {
+		return things.SignedKey{}, things.ErrUnauthorizedAccess
+	}
+
+	return key, nil
+}
+
+func (kpm *keyProviderMock) IssueInvitation(inv things.Invitation) (string, error) {
+	kpm.mu.Lock()
+	defer kpm.mu.Unlock()
+
+	signed := fmt.Sprintf("invitation-%d", len(kpm.invitations)+1)
+	kpm.invitations[signed] = inv
+	return signed, nil
+}
+
+func (kpm *keyProviderMock) ParseInvitation(signed string) (things.Invitation, error) {
+	kpm.mu.Lock()
+	defer kpm.mu.Unlock()
+
+	inv, ok := kpm.invitations[signed]
+	if !ok || inv.ExpiresAt.Before(time.Now()) {
+		return things.Invitation{}, things.ErrUnauthorizedAccess
+	}
+
+	return inv, nil
+}
+
+type revocationRepositoryMock struct {
+	mu          sync.Mutex
+	revocations map[string]things.Revocation
+}
+
+// NewRevocationRepository creates in-memory revocation repository.
+func NewRevocationRepository() things.RevocationRepository {
+	return &revocationRepositoryMock{
+		revocations: make(map[string]things.Revocation),
+	}
+}
+
+func (rrm *revocationRepositoryMock) Save(_ context.Context, r things.Revocation) error {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	rrm.revocations[r.ThingID] = r
+	return nil
+}
+
+func (rrm *revocationRepositoryMock) RetrieveByThing(_ context.Context, id string) (things.Revocation, error) {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	r, ok := rrm.revocations[id]
+	if !ok {
+		return things.Revocation{}, things.ErrNotFound
+	}
+
+	return r, nil
+}
+
+func (rrm *revocationRepositoryMock) RetrieveAll(_ context.Context, now time.Time) ([]things.Revocation, error) {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	revs := []things.Revocation{}
+	for _, r := range rrm.revocations {
+		if r.ExpiresAt.After(now) {
+			revs = append(revs, r)
+		}
+	}
+
+	return revs, nil
+}
@@ -0,0 +1,37 @@
+//
+// Copyright (c) 2018
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package mocks
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mainflux/mainflux/things"
+)
+
+var _ things.OnboardingProvider = (*onboardingProviderMock)(nil)
+
+type onboardingProviderMock struct{}
+
+// NewOnboardingProvider creates onboarding provider whose codes consist of
+// thing identifier and key.
+func NewOnboardingProvider() things.OnboardingProvider {
+	return onboardingProviderMock{}
+}
+
+func (opm onboardingProviderMock) Issue(id, key string, channels []string) (things.Onboarding, string, error) {
+	onboarding := things.Onboarding{
+		ThingID:   id,
+		ThingKey:  key,
+		Channels:  channels,
+		Endpoints: map[string]string{},
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	return onboarding, fmt.Sprintf("%s:%s", id, key), nil
+}
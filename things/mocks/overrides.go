@@ -0,0 +1,26 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package mocks
+
+import "github.com/mainflux/mainflux/things"
+
+var _ things.OverrideProvider = (*overrideProviderMock)(nil)
+
+type overrideProviderMock struct {
+	overrides map[string]things.Override
+}
+
+// NewOverrideProvider creates override provider of the given overrides,
+// indexed by owner.
+func NewOverrideProvider(overrides map[string]things.Override) things.OverrideProvider {
+	return overrideProviderMock{overrides: overrides}
+}
+
+func (opm overrideProviderMock) Override(owner string) things.Override {
+	return opm.overrides[owner]
+}
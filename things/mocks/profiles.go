@@ -0,0 +1,109 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package mocks
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/mainflux/mainflux/things"
+)
+
+var _ things.ProfileRepository = (*profileRepositoryMock)(nil)
+
+type profileRepositoryMock struct {
+	mu       sync.Mutex
+	profiles map[string]things.Profile
+}
+
+// NewProfileRepository creates in-memory profile repository.
+func NewProfileRepository() things.ProfileRepository {
+	return &profileRepositoryMock{
+		profiles: make(map[string]things.Profile),
+	}
+}
+
+func (prm *profileRepositoryMock) Save(_ context.Context, profile things.Profile) (string, error) {
+	prm.mu.Lock()
+	defer prm.mu.Unlock()
+
+	dbKey := key(profile.Owner, profile.ID)
+	if _, ok := prm.profiles[dbKey]; ok {
+		return "", things.ErrConflict
+	}
+
+	prm.profiles[dbKey] = profile
+	return profile.ID, nil
+}
+
+func (prm *profileRepositoryMock) Update(_ context.Context, profile things.Profile) error {
+	prm.mu.Lock()
+	defer prm.mu.Unlock()
+
+	dbKey := key(profile.Owner, profile.ID)
+	if _, ok := prm.profiles[dbKey]; !ok {
+		return things.ErrNotFound
+	}
+
+	prm.profiles[dbKey] = profile
+	return nil
+}
+
+func (prm *profileRepositoryMock) RetrieveByID(_ context.Context, owner, id string) (things.Profile, error) {
+	prm.mu.Lock()
+	defer prm.mu.Unlock()
+
+	if p, ok := prm.profiles[key(owner, id)]; ok {
+		return p, nil
+	}
+
+	return things.Profile{}, things.ErrNotFound
+}
+
+func (prm *profileRepositoryMock) RetrieveAll(_ context.Context, owner string, offset, limit uint64) (things.ProfilesPage, error) {
+	prm.mu.Lock()
+	defer prm.mu.Unlock()
+
+	all := []things.Profile{}
+	for _, p := range prm.profiles {
+		if p.Owner == owner {
+			all = append(all, p)
+		}
+	}
+
+	sort.SliceStable(all, func(i, j int) bool {
+		return all[i].ID < all[j].ID
+	})
+
+	items := []things.Profile{}
+	if offset < uint64(len(all)) {
+		end := offset + limit
+		if end > uint64(len(all)) {
+			end = uint64(len(all))
+		}
+		items = all[offset:end]
+	}
+
+	return things.ProfilesPage{
+		Profiles: items,
+		PageMetadata: things.PageMetadata{
+			Total:  uint64(len(all)),
+			Offset: offset,
+			Limit:  limit,
+		},
+	}, nil
+}
+
+func (prm *profileRepositoryMock) Remove(_ context.Context, owner, id string) error {
+	prm.mu.Lock()
+	defer prm.mu.Unlock()
+
+	delete(prm.profiles, key(owner, id))
+	return nil
+}
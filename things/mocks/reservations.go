@@ -0,0 +1,84 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package mocks
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/mainflux/mainflux/things"
+)
+
+var _ things.ReservationRepository = (*reservationRepositoryMock)(nil)
+
+type reservationRepositoryMock struct {
+	mu           sync.Mutex
+	reservations map[string]things.Reservation
+}
+
+// NewReservationRepository creates in-memory reservation repository.
+func NewReservationRepository() things.ReservationRepository {
+	return &reservationRepositoryMock{
+		reservations: make(map[string]things.Reservation),
+	}
+}
+
+func (rrm *reservationRepositoryMock) Save(_ context.Context, reservations ...things.Reservation) error {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	for _, r := range reservations {
+		for _, res := range rrm.reservations {
+			if res.Key == r.Key {
+				return things.ErrConflict
+			}
+		}
+	}
+
+	for _, r := range reservations {
+		rrm.reservations[key(r.Owner, r.ID)] = r
+	}
+
+	return nil
+}
+
+func (rrm *reservationRepositoryMock) RetrieveByKey(_ context.Context, owner, k string) (things.Reservation, error) {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	for _, r := range rrm.reservations {
+		if r.Owner == owner && r.Key == k {
+			return r, nil
+		}
+	}
+
+	return things.Reservation{}, things.ErrNotFound
+}
+
+func (rrm *reservationRepositoryMock) Remove(_ context.Context, owner, id string) error {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	delete(rrm.reservations, key(owner, id))
+	return nil
+}
+
+func (rrm *reservationRepositoryMock) RemoveAll(_ context.Context, owner string) error {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	prefix := key(owner, "")
+	for k := range rrm.reservations {
+		if strings.HasPrefix(k, prefix) {
+			delete(rrm.reservations, k)
+		}
+	}
+
+	return nil
+}
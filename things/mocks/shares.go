@@ -0,0 +1,116 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package mocks
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/mainflux/mainflux/things"
+)
+
+var _ things.ShareRepository = (*shareRepositoryMock)(nil)
+
+type shareRepositoryMock struct {
+	mu     sync.Mutex
+	shares map[string]map[string]things.Share
+}
+
+// NewShareRepository creates in-memory shares repository.
+func NewShareRepository() things.ShareRepository {
+	return &shareRepositoryMock{
+		shares: make(map[string]map[string]things.Share),
+	}
+}
+
+func (srm *shareRepositoryMock) Save(_ context.Context, share things.Share) error {
+	srm.mu.Lock()
+	defer srm.mu.Unlock()
+
+	dbKey := key(share.Kind, share.EntityID)
+	if _, ok := srm.shares[dbKey]; !ok {
+		srm.shares[dbKey] = make(map[string]things.Share)
+	}
+
+	srm.shares[dbKey][share.User] = share
+	return nil
+}
+
+func (srm *shareRepositoryMock) Retrieve(_ context.Context, kind, id, user string) (things.Share, error) {
+	srm.mu.Lock()
+	defer srm.mu.Unlock()
+
+	if share, ok := srm.shares[key(kind, id)][user]; ok {
+		return share, nil
+	}
+
+	return things.Share{}, things.ErrNotFound
+}
+
+func (srm *shareRepositoryMock) RetrieveAll(_ context.Context, owner, kind, id string) ([]things.Share, error) {
+	srm.mu.Lock()
+	defer srm.mu.Unlock()
+
+	items := []things.Share{}
+	for _, share := range srm.shares[key(kind, id)] {
+		if share.Owner == owner {
+			items = append(items, share)
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].User < items[j].User
+	})
+
+	return items, nil
+}
+
+func (srm *shareRepositoryMock) RetrieveByUser(_ context.Context, user, kind string) ([]things.Share, error) {
+	srm.mu.Lock()
+	defer srm.mu.Unlock()
+
+	items := []things.Share{}
+	for _, shares := range srm.shares {
+		if share, ok := shares[user]; ok && share.Kind == kind {
+			items = append(items, share)
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].EntityID < items[j].EntityID
+	})
+
+	return items, nil
+}
+
+func (srm *shareRepositoryMock) Remove(_ context.Context, owner, kind, id, user string) error {
+	srm.mu.Lock()
+	defer srm.mu.Unlock()
+
+	if share, ok := srm.shares[key(kind, id)][user]; ok && share.Owner == owner {
+		delete(srm.shares[key(kind, id)], user)
+	}
+
+	return nil
+}
+
+func (srm *shareRepositoryMock) RemoveByUser(_ context.Context, user string) error {
+	srm.mu.Lock()
+	defer srm.mu.Unlock()
+
+	for _, shares := range srm.shares {
+		for u, share := range shares {
+			if u == user || share.Owner == user {
+				delete(shares, u)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package mocks
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/mainflux/mainflux/things"
+)
+
+var _ things.SubtopicRuleRepository = (*subtopicRuleRepositoryMock)(nil)
+
+type subtopicRuleRepositoryMock struct {
+	mu    sync.Mutex
+	rules map[string]things.SubtopicRule
+}
+
+// NewSubtopicRuleRepository creates in-memory subtopic rules repository.
+func NewSubtopicRuleRepository() things.SubtopicRuleRepository {
+	return &subtopicRuleRepositoryMock{
+		rules: make(map[string]things.SubtopicRule),
+	}
+}
+
+func (srrm *subtopicRuleRepositoryMock) Save(_ context.Context, rule things.SubtopicRule) (string, error) {
+	srrm.mu.Lock()
+	defer srrm.mu.Unlock()
+
+	if _, ok := srrm.rules[rule.ID]; ok {
+		return "", things.ErrConflict
+	}
+
+	srrm.rules[rule.ID] = rule
+	return rule.ID, nil
+}
+
+func (srrm *subtopicRuleRepositoryMock) RetrieveAll(_ context.Context, owner, chanID string) ([]things.SubtopicRule, error) {
+	return srrm.retrieve(func(r things.SubtopicRule) bool {
+		return r.Owner == owner && r.Channel == chanID
+	}), nil
+}
+
+func (srrm *subtopicRuleRepositoryMock) RetrieveByThing(_ context.Context, thingID string) ([]things.SubtopicRule, error) {
+	return srrm.retrieve(func(r things.SubtopicRule) bool {
+		return r.ThingID == thingID
+	}), nil
+}
+
+func (srrm *subtopicRuleRepositoryMock) Remove(_ context.Context, owner, chanID, id string) error {
+	srrm.mu.Lock()
+	defer srrm.mu.Unlock()
+
+	r, ok := srrm.rules[id]
+	if !ok || r.Owner != owner || r.Channel != chanID {
+		return things.ErrNotFound
+	}
+
+	delete(srrm.rules, id)
+	return nil
+}
+
+func (srrm *subtopicRuleRepositoryMock) retrieve(match func(things.SubtopicRule) bool) []things.SubtopicRule {
+	srrm.mu.Lock()
+	defer srrm.mu.Unlock()
+
+	items := []things.SubtopicRule{}
+	for _, r := range srrm.rules {
+		if match(r) {
+			items = append(items, r)
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].ID < items[j].ID
+	})
+
+	return items
+}
@@ -0,0 +1,120 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package mocks
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux/things"
+)
+
+var (
+	_ things.SubtopicRepository    = (*subtopicRepositoryMock)(nil)
+	_ things.ObservationRepository = (*observationRepositoryMock)(nil)
+)
+
+type subtopicRepositoryMock struct {
+	mu        sync.Mutex
+	subtopics map[string]map[string]things.Subtopic
+}
+
+// NewSubtopicRepository creates in-memory registered subtopics repository.
+func NewSubtopicRepository() things.SubtopicRepository {
+	return &subtopicRepositoryMock{
+		subtopics: make(map[string]map[string]things.Subtopic),
+	}
+}
+
+func (srm *subtopicRepositoryMock) Save(_ context.Context, subtopic things.Subtopic) error {
+	srm.mu.Lock()
+	defer srm.mu.Unlock()
+
+	dbKey := key(subtopic.Owner, subtopic.Channel)
+	if _, ok := srm.subtopics[dbKey]; !ok {
+		srm.subtopics[dbKey] = make(map[string]things.Subtopic)
+	}
+
+	srm.subtopics[dbKey][subtopic.Name] = subtopic
+	return nil
+}
+
+func (srm *subtopicRepositoryMock) RetrieveAll(_ context.Context, owner, chanID string) ([]things.Subtopic, error) {
+	srm.mu.Lock()
+	defer srm.mu.Unlock()
+
+	items := []things.Subtopic{}
+	for _, st := range srm.subtopics[key(owner, chanID)] {
+		items = append(items, st)
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Name < items[j].Name
+	})
+
+	return items, nil
+}
+
+func (srm *subtopicRepositoryMock) Remove(_ context.Context, owner, chanID, name string) error {
+	srm.mu.Lock()
+	defer srm.mu.Unlock()
+
+	delete(srm.subtopics[key(owner, chanID)], name)
+	return nil
+}
+
+type observationRepositoryMock struct {
+	mu       sync.Mutex
+	observed map[string]map[string]things.ObservedSubtopic
+}
+
+// NewObservationRepository creates in-memory observed subtopics repository.
+func NewObservationRepository() things.ObservationRepository {
+	return &observationRepositoryMock{
+		observed: make(map[string]map[string]things.ObservedSubtopic),
+	}
+}
+
+func (orm *observationRepositoryMock) Observe(_ context.Context, chanID, subtopic string, t time.Time) error {
+	orm.mu.Lock()
+	defer orm.mu.Unlock()
+
+	if _, ok := orm.observed[chanID]; !ok {
+		orm.observed[chanID] = make(map[string]things.ObservedSubtopic)
+	}
+
+	st, ok := orm.observed[chanID][subtopic]
+	if !ok && len(orm.observed[chanID]) >= things.MaxObservedSubtopics {
+		return nil
+	}
+
+	st.Name = subtopic
+	st.Messages++
+	st.LastSeen = t
+	orm.observed[chanID][subtopic] = st
+
+	return nil
+}
+
+func (orm *observationRepositoryMock) RetrieveAll(_ context.Context, chanID string) ([]things.ObservedSubtopic, error) {
+	orm.mu.Lock()
+	defer orm.mu.Unlock()
+
+	items := []things.ObservedSubtopic{}
+	for _, st := range orm.observed[chanID] {
+		items = append(items, st)
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Name < items[j].Name
+	})
+
+	return items, nil
+}
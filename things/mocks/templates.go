@@ -0,0 +1,57 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mainflux/mainflux/things"
+)
+
+var _ things.TemplateRepository = (*templateRepositoryMock)(nil)
+
+type templateRepositoryMock struct {
+	mu        sync.Mutex
+	templates map[string]things.Template
+}
+
+// NewTemplateRepository creates in-memory provisioning template repository.
+func NewTemplateRepository() things.TemplateRepository {
+	return &templateRepositoryMock{
+		templates: make(map[string]things.Template),
+	}
+}
+
+func (trm *templateRepositoryMock) Save(_ context.Context, tmpl things.Template) error {
+	trm.mu.Lock()
+	defer trm.mu.Unlock()
+
+	trm.templates[tmpl.Owner] = tmpl
+	return nil
+}
+
+func (trm *templateRepositoryMock) Retrieve(_ context.Context, owner string) (things.Template, error) {
+	trm.mu.Lock()
+	defer trm.mu.Unlock()
+
+	tmpl, ok := trm.templates[owner]
+	if !ok {
+		return things.Template{}, things.ErrNotFound
+	}
+
+	return tmpl, nil
+}
+
+func (trm *templateRepositoryMock) Remove(_ context.Context, owner string) error {
+	trm.mu.Lock()
+	defer trm.mu.Unlock()
+
+	delete(trm.templates, owner)
+	return nil
+}
@@ -0,0 +1,134 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package mocks
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/mainflux/mainflux/things"
+)
+
+var _ things.ThingKeyRepository = (*thingKeyRepositoryMock)(nil)
+
+type thingKeyRepositoryMock struct {
+	mu       sync.Mutex
+	keys     map[string]things.ThingKey
+	things   things.ThingRepository
+	channels things.ChannelRepository
+}
+
+// NewThingKeyRepository creates in-memory scoped thing keys repository.
+func NewThingKeyRepository(thingsRepo things.ThingRepository, channelsRepo things.ChannelRepository) things.ThingKeyRepository {
+	return &thingKeyRepositoryMock{
+		keys:     make(map[string]things.ThingKey),
+		things:   thingsRepo,
+		channels: channelsRepo,
+	}
+}
+
+func (tkrm *thingKeyRepositoryMock) Save(ctx context.Context, tk things.ThingKey) (string, error) {
+	tkrm.mu.Lock()
+	defer tkrm.mu.Unlock()
+
+	if _, err := tkrm.things.RetrieveByID(ctx, tk.Owner, tk.ThingID); err != nil {
+		return "", err
+	}
+
+	for _, k := range tkrm.keys {
+		if k.ID == tk.ID || k.Key == tk.Key {
+			return "", things.ErrConflict
+		}
+	}
+
+	tkrm.keys[tk.Key] = tk
+	return tk.ID, nil
+}
+
+func (tkrm *thingKeyRepositoryMock) RetrieveAll(_ context.Context, owner, thingID string) ([]things.ThingKey, error) {
+	tkrm.mu.Lock()
+	defer tkrm.mu.Unlock()
+
+	items := []things.ThingKey{}
+	for _, tk := range tkrm.keys {
+		if tk.Owner == owner && tk.ThingID == thingID {
+			items = append(items, tk)
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].ID < items[j].ID
+	})
+
+	return items, nil
+}
+
+func (tkrm *thingKeyRepositoryMock) RetrieveByKey(_ context.Context, key string) (things.ThingKey, error) {
+	tkrm.mu.Lock()
+	defer tkrm.mu.Unlock()
+
+	tk, ok := tkrm.keys[key]
+	if !ok || !tkrm.enabled(tk) {
+		return things.ThingKey{}, things.ErrNotFound
+	}
+
+	return tk, nil
+}
+
+func (tkrm *thingKeyRepositoryMock) HasThing(ctx context.Context, chanID, key string) (things.ThingKey, things.ConnectionAccess, error) {
+	tkrm.mu.Lock()
+	defer tkrm.mu.Unlock()
+
+	tk, ok := tkrm.keys[key]
+	if !ok || !tkrm.enabled(tk) {
+		return things.ThingKey{}, things.FullAccess, things.ErrNotFound
+	}
+
+	page, err := tkrm.channels.RetrieveByThing(ctx, tk.Owner, tk.ThingID, 0, maxPageSize)
+	if err != nil {
+		return things.ThingKey{}, things.FullAccess, err
+	}
+
+	for _, ch := range page.Channels {
+		if ch.ID != chanID {
+			continue
+		}
+
+		restricted, err := tkrm.channels.RetrieveRestricted(ctx, tk.ThingID)
+		if err != nil {
+			return things.ThingKey{}, things.FullAccess, err
+		}
+
+		if access, ok := restricted[chanID]; ok {
+			return tk, access, nil
+		}
+		return tk, things.FullAccess, nil
+	}
+
+	return things.ThingKey{}, things.FullAccess, things.ErrNotFound
+}
+
+func (tkrm *thingKeyRepositoryMock) Remove(_ context.Context, owner, thingID, id string) error {
+	tkrm.mu.Lock()
+	defer tkrm.mu.Unlock()
+
+	for key, tk := range tkrm.keys {
+		if tk.Owner == owner && tk.ThingID == thingID && tk.ID == id {
+			delete(tkrm.keys, key)
+			return nil
+		}
+	}
+
+	return things.ErrNotFound
+}
+
+func (tkrm *thingKeyRepositoryMock) enabled(tk things.ThingKey) bool {
+	th, err := tkrm.things.RetrieveByID(context.Background(), tk.Owner, tk.ThingID)
+	return err == nil && th.State != things.Disabled
+}
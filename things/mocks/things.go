@@ -8,11 +8,12 @@
 package mocks
 
 import (
+	"context"
 	"fmt"
 	"sort"
-	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/mainflux/mainflux/things"
 )
@@ -47,48 +48,163 @@ func NewThingRepository(conns chan Connection) things.ThingRepository {
 	return repo
 }
 
-func (trm *thingRepositoryMock) Save(thing things.Thing) (string, error) {
+func (trm *thingRepositoryMock) Save(_ context.Context, thing things.Thing) (string, error) {
 	trm.mu.Lock()
 	defer trm.mu.Unlock()
 
 	for _, th := range trm.things {
-		if th.Key == thing.Key {
+		if th.Key == thing.Key || sameExternalID(th, thing) {
 			return "", things.ErrConflict
 		}
 	}
 
 	trm.counter++
-	thing.ID = strconv.FormatUint(trm.counter, 10)
+	thing.ID = newID(trm.counter)
+	thing.State = things.Enabled
 	trm.things[key(thing.Owner, thing.ID)] = thing
 
 	return thing.ID, nil
 }
 
-func (trm *thingRepositoryMock) Update(thing things.Thing) error {
+func (trm *thingRepositoryMock) SaveAll(_ context.Context, ths ...things.Thing) ([]string, error) {
+	trm.mu.Lock()
+	defer trm.mu.Unlock()
+
+	keys := map[string]bool{}
+	saved := []things.Thing{}
+	for _, th := range trm.things {
+		keys[th.Key] = true
+		saved = append(saved, th)
+	}
+	for _, thing := range ths {
+		if keys[thing.Key] {
+			return nil, things.ErrConflict
+		}
+		keys[thing.Key] = true
+
+		for _, th := range saved {
+			if sameExternalID(th, thing) {
+				return nil, things.ErrConflict
+			}
+		}
+		saved = append(saved, thing)
+	}
+
+	ids := make([]string, len(ths))
+	for i, thing := range ths {
+		trm.counter++
+		thing.ID = newID(trm.counter)
+		thing.State = things.Enabled
+		trm.things[key(thing.Owner, thing.ID)] = thing
+		ids[i] = thing.ID
+	}
+
+	return ids, nil
+}
+
+func (trm *thingRepositoryMock) Update(_ context.Context, thing things.Thing) error {
 	trm.mu.Lock()
 	defer trm.mu.Unlock()
 
 	dbKey := key(thing.Owner, thing.ID)
 
-	if _, ok := trm.things[dbKey]; !ok {
+	th, ok := trm.things[dbKey]
+	if !ok {
 		return things.ErrNotFound
 	}
 
+	thing.State = th.State
 	trm.things[dbKey] = thing
 
 	return nil
 }
 
-func (trm *thingRepositoryMock) UpdateKey(owner, id, val string) error {
+func (trm *thingRepositoryMock) Patch(_ context.Context, owner, id string, patch map[string]interface{}) (things.Thing, error) {
+	trm.mu.Lock()
+	defer trm.mu.Unlock()
+
+	dbKey := key(owner, id)
+
+	th, ok := trm.things[dbKey]
+	if !ok {
+		return things.Thing{}, things.ErrNotFound
+	}
+
+	th, err := things.PatchThing(th, patch)
+	if err != nil {
+		return things.Thing{}, err
+	}
+
+	trm.things[dbKey] = th
+	return th, nil
+}
+
+func (trm *thingRepositoryMock) UpdateMetadata(_ context.Context, owner string, selector, patch map[string]interface{}) ([]things.Thing, error) {
+	trm.mu.Lock()
+	defer trm.mu.Unlock()
+
+	items := []things.Thing{}
+	prefix := fmt.Sprintf("%s-", owner)
+	for k, th := range trm.things {
+		if !strings.HasPrefix(k, prefix) || !contains(th.Metadata, selector) {
+			continue
+		}
+
+		th.Metadata = things.MergeMetadata(th.Metadata, patch)
+		trm.things[k] = th
+		items = append(items, th)
+	}
+
+	return items, nil
+}
+
+func (trm *thingRepositoryMock) UpdateKey(_ context.Context, thing things.Thing) error {
 	trm.mu.Lock()
 	defer trm.mu.Unlock()
 
 	for _, th := range trm.things {
-		if th.Key == val {
+		if th.Key == thing.Key {
 			return things.ErrConflict
 		}
 	}
 
+	dbKey := key(thing.Owner, thing.ID)
+
+	th, ok := trm.things[dbKey]
+	if !ok {
+		return things.ErrNotFound
+	}
+
+	th.Key = thing.Key
+	th.KeyExpiresAt = thing.KeyExpiresAt
+	th.KeyRotation = thing.KeyRotation
+	trm.things[dbKey] = th
+
+	return nil
+}
+
+func (trm *thingRepositoryMock) RotateKey(_ context.Context, thing things.Thing, old string) error {
+	trm.mu.Lock()
+	defer trm.mu.Unlock()
+
+	dbKey := key(thing.Owner, thing.ID)
+
+	th, ok := trm.things[dbKey]
+	if !ok || th.Key != old {
+		return things.ErrNotFound
+	}
+
+	th.Key = thing.Key
+	th.KeyExpiresAt = thing.KeyExpiresAt
+	trm.things[dbKey] = th
+
+	return nil
+}
+
+func (trm *thingRepositoryMock) UpdateState(_ context.Context, owner, id string, state things.State) error {
+	trm.mu.Lock()
+	defer trm.mu.Unlock()
+
 	dbKey := key(owner, id)
 
 	th, ok := trm.things[dbKey]
@@ -96,13 +212,13 @@ func (trm *thingRepositoryMock) UpdateKey(owner, id, val string) error {
 		return things.ErrNotFound
 	}
 
-	th.Key = val
+	th.State = state
 	trm.things[dbKey] = th
 
 	return nil
 }
 
-func (trm *thingRepositoryMock) RetrieveByID(owner, id string) (things.Thing, error) {
+func (trm *thingRepositoryMock) RetrieveByID(_ context.Context, owner, id string) (things.Thing, error) {
 	trm.mu.Lock()
 	defer trm.mu.Unlock()
 
@@ -113,7 +229,58 @@ func (trm *thingRepositoryMock) RetrieveByID(owner, id string) (things.Thing, er
 	return things.Thing{}, things.ErrNotFound
 }
 
-func (trm *thingRepositoryMock) RetrieveAll(owner string, offset, limit uint64, name string) (things.ThingsPage, error) {
+func (trm *thingRepositoryMock) RetrieveByExternalID(_ context.Context, owner, externalID string) (things.Thing, error) {
+	trm.mu.Lock()
+	defer trm.mu.Unlock()
+
+	for _, th := range trm.things {
+		if sameExternalID(th, things.Thing{Owner: owner, ExternalID: externalID}) {
+			return th, nil
+		}
+	}
+
+	return things.Thing{}, things.ErrNotFound
+}
+
+func (trm *thingRepositoryMock) UpdateLastSeen(_ context.Context, id string, t time.Time) error {
+	trm.mu.Lock()
+	defer trm.mu.Unlock()
+
+	for k, th := range trm.things {
+		if th.ID == id && (th.LastSeen.IsZero() || th.LastSeen.Before(t.Add(-things.LastSeenResolution))) {
+			th.LastSeen = t
+			trm.things[k] = th
+		}
+	}
+
+	return nil
+}
+
+func (trm *thingRepositoryMock) RetrieveAll(_ context.Context, owner string, offset, limit uint64, name string, metadata map[string]interface{}, tags things.TagFilter, status things.StatusFilter, after string) (things.ThingsPage, error) {
+	return trm.retrieve(owner, nil, offset, limit, metadata, tags, status, after)
+}
+
+func (trm *thingRepositoryMock) RetrieveAccessible(_ context.Context, user string, shared []string, offset, limit uint64, name string, metadata map[string]interface{}, tags things.TagFilter, status things.StatusFilter, after string) (things.ThingsPage, error) {
+	return trm.retrieve(user, shared, offset, limit, metadata, tags, status, after)
+}
+
+func (trm *thingRepositoryMock) CountAccessible(_ context.Context, user string, shared []string, name string, metadata map[string]interface{}, tags things.TagFilter, status things.StatusFilter) (uint64, error) {
+	trm.mu.Lock()
+	defer trm.mu.Unlock()
+
+	prefix := fmt.Sprintf("%s-", user)
+	count := uint64(0)
+	for k, v := range trm.things {
+		accessible := strings.HasPrefix(k, prefix) || includes(shared, v.ID)
+		if accessible && contains(v.Metadata, metadata) && tags.Matches(v.Tags) && status.Matches(v.LastSeen) {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (trm *thingRepositoryMock) retrieve(owner string, shared []string, offset, limit uint64, metadata map[string]interface{}, tags things.TagFilter, status things.StatusFilter, after string) (things.ThingsPage, error) {
 	trm.mu.Lock()
 	defer trm.mu.Unlock()
 
@@ -123,15 +290,22 @@ func (trm *thingRepositoryMock) RetrieveAll(owner string, offset, limit uint64,
 		return things.ThingsPage{}, nil
 	}
 
-	first := uint64(offset) + 1
+	first, err := firstID(offset, after)
+	if err != nil {
+		return things.ThingsPage{}, err
+	}
+	if after != "" {
+		offset = 0
+	}
 	last := first + uint64(limit)
 
 	// This obscure way to examine map keys is enforced by the key structure
 	// itself (see mocks/commons.go).
 	prefix := fmt.Sprintf("%s-", owner)
 	for k, v := range trm.things {
-		id, _ := strconv.ParseUint(v.ID, 10, 64)
-		if strings.HasPrefix(k, prefix) && id >= first && id < last {
+		id := seqNumber(v.ID)
+		accessible := strings.HasPrefix(k, prefix) || includes(shared, v.ID)
+		if accessible && id >= first && id < last && contains(v.Metadata, metadata) && tags.Matches(v.Tags) && status.Matches(v.LastSeen) {
 			items = append(items, v)
 		}
 	}
@@ -143,16 +317,17 @@ func (trm *thingRepositoryMock) RetrieveAll(owner string, offset, limit uint64,
 	page := things.ThingsPage{
 		Things: items,
 		PageMetadata: things.PageMetadata{
-			Total:  trm.counter,
-			Offset: offset,
-			Limit:  limit,
+			Total:    trm.counter,
+			Offset:   offset,
+			Limit:    limit,
+			Metadata: metadata,
 		},
 	}
 
 	return page, nil
 }
 
-func (trm *thingRepositoryMock) RetrieveByChannel(owner, chanID string, offset, limit uint64) (things.ThingsPage, error) {
+func (trm *thingRepositoryMock) RetrieveByChannel(_ context.Context, owner, chanID string, offset, limit uint64) (things.ThingsPage, error) {
 	trm.mu.Lock()
 	defer trm.mu.Unlock()
 
@@ -171,7 +346,7 @@ func (trm *thingRepositoryMock) RetrieveByChannel(owner, chanID string, offset,
 	}
 
 	for _, v := range ths {
-		id, _ := strconv.ParseUint(v.ID, 10, 64)
+		id := seqNumber(v.ID)
 		if id >= first && id < last {
 			items = append(items, v)
 		}
@@ -193,20 +368,83 @@ func (trm *thingRepositoryMock) RetrieveByChannel(owner, chanID string, offset,
 	return page, nil
 }
 
-func (trm *thingRepositoryMock) Remove(owner, id string) error {
+func (trm *thingRepositoryMock) Transfer(_ context.Context, owner, id, newOwner string, keepConns bool) error {
+	trm.mu.Lock()
+	defer trm.mu.Unlock()
+
+	thing, ok := trm.things[key(owner, id)]
+	if !ok {
+		return things.ErrNotFound
+	}
+
+	delete(trm.things, key(owner, id))
+	thing.Owner = newOwner
+	trm.things[key(newOwner, id)] = thing
+
+	for chanID, ths := range trm.tconns {
+		if _, ok := ths[id]; !ok {
+			continue
+		}
+		if keepConns {
+			ths[id] = thing
+			continue
+		}
+		delete(trm.tconns[chanID], id)
+	}
+
+	return nil
+}
+
+func (trm *thingRepositoryMock) Remove(_ context.Context, owner, id string) error {
 	trm.mu.Lock()
 	defer trm.mu.Unlock()
 	delete(trm.things, key(owner, id))
 	return nil
 }
 
-func (trm *thingRepositoryMock) RetrieveByKey(key string) (string, error) {
+func (trm *thingRepositoryMock) RetrieveByKey(_ context.Context, key string) (things.Thing, error) {
+	trm.mu.Lock()
+	defer trm.mu.Unlock()
+
+	now := time.Now()
+	for _, thing := range trm.things {
+		if thing.Key == key && thing.State == things.Enabled && !thing.KeyExpired(now) {
+			return thing, nil
+		}
+	}
+
+	return things.Thing{}, things.ErrNotFound
+}
+
+func (trm *thingRepositoryMock) RetrieveExpired(_ context.Context, since, until time.Time) ([]things.Thing, error) {
+	trm.mu.Lock()
+	defer trm.mu.Unlock()
+
+	items := []things.Thing{}
+	for _, thing := range trm.things {
+		if thing.State != things.Enabled || !thing.KeyExpired(until) {
+			continue
+		}
+
+		if thing.KeyExpiresAt.After(since) || thing.KeyRotation > 0 {
+			items = append(items, thing)
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].KeyExpiresAt.Before(items[j].KeyExpiresAt)
+	})
+
+	return items, nil
+}
+
+func (trm *thingRepositoryMock) RetrieveOwner(_ context.Context, id string) (string, error) {
 	trm.mu.Lock()
 	defer trm.mu.Unlock()
 
 	for _, thing := range trm.things {
-		if thing.Key == key {
-			return thing.ID, nil
+		if thing.ID == id {
+			return thing.Owner, nil
 		}
 	}
 
@@ -234,44 +472,79 @@ func (trm *thingRepositoryMock) disconnect(conn Connection) {
 	delete(trm.tconns[conn.chanID], conn.thing.ID)
 }
 
+type cachedThing struct {
+	id        string
+	expiresAt time.Time
+}
+
 type thingCacheMock struct {
-	mu     sync.Mutex
-	things map[string]string
+	mu        sync.Mutex
+	things    map[string]cachedThing
+	transform map[string]things.Transform
 }
 
 // NewThingCache returns mock cache instance.
 func NewThingCache() things.ThingCache {
 	return &thingCacheMock{
-		things: make(map[string]string),
+		things:    make(map[string]cachedThing),
+		transform: make(map[string]things.Transform),
 	}
 }
 
-func (tcm *thingCacheMock) Save(key, id string) error {
+func (tcm *thingCacheMock) Save(_ context.Context, key, id string, expiresAt time.Time) error {
 	tcm.mu.Lock()
 	defer tcm.mu.Unlock()
 
-	tcm.things[key] = id
+	tcm.things[key] = cachedThing{id: id, expiresAt: expiresAt}
 	return nil
 }
 
-func (tcm *thingCacheMock) ID(key string) (string, error) {
+func (tcm *thingCacheMock) ID(_ context.Context, key string) (string, error) {
 	tcm.mu.Lock()
 	defer tcm.mu.Unlock()
 
-	id, ok := tcm.things[key]
-	if !ok {
+	id := tcm.lookup(key)
+	if id == "" {
 		return "", things.ErrNotFound
 	}
 
 	return id, nil
 }
 
-func (tcm *thingCacheMock) Remove(id string) error {
+func (tcm *thingCacheMock) IDs(_ context.Context, keys []string) ([]string, error) {
+	tcm.mu.Lock()
+	defer tcm.mu.Unlock()
+
+	ids := make([]string, len(keys))
+	for i, key := range keys {
+		ids[i] = tcm.lookup(key)
+	}
+
+	return ids, nil
+}
+
+// lookup returns the ID cached for the key, or an empty string if the key
+// isn't cached or it expired.
+func (tcm *thingCacheMock) lookup(key string) string {
+	th, ok := tcm.things[key]
+	if !ok {
+		return ""
+	}
+
+	if !th.expiresAt.IsZero() && !time.Now().Before(th.expiresAt) {
+		delete(tcm.things, key)
+		return ""
+	}
+
+	return th.id
+}
+
+func (tcm *thingCacheMock) Remove(_ context.Context, id string) error {
 	tcm.mu.Lock()
 	defer tcm.mu.Unlock()
 
 	for key, val := range tcm.things {
-		if val == id {
+		if val.id == id {
 			delete(tcm.things, key)
 			return nil
 		}
@@ -279,3 +552,20 @@ func (tcm *thingCacheMock) Remove(id string) error {
 
 	return things.ErrNotFound
 }
+
+func (tcm *thingCacheMock) SaveTransform(_ context.Context, id string, tr things.Transform) error {
+	tcm.mu.Lock()
+	defer tcm.mu.Unlock()
+
+	if tr.Template == "" {
+		delete(tcm.transform, id)
+		return nil
+	}
+
+	tcm.transform[id] = tr
+	return nil
+}
+
+func sameExternalID(a, b things.Thing) bool {
+	return a.ExternalID != "" && a.Owner == b.Owner && a.ExternalID == b.ExternalID
+}
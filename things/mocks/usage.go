@@ -0,0 +1,78 @@
+//
+// Copyright (c) 2018
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package mocks
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux/things"
+)
+
+var _ things.UsageRepository = (*usageRepositoryMock)(nil)
+
+type usageRecord struct {
+	thing   string
+	channel string
+	size    uint64
+	time    time.Time
+}
+
+type usageRepositoryMock struct {
+	mu      sync.Mutex
+	records []usageRecord
+}
+
+// NewUsageRepository creates in-memory usage repository.
+func NewUsageRepository() things.UsageRepository {
+	return &usageRepositoryMock{}
+}
+
+func (urm *usageRepositoryMock) Add(_ context.Context, thingID, chanID string, size uint64, t time.Time) error {
+	urm.mu.Lock()
+	defer urm.mu.Unlock()
+
+	urm.records = append(urm.records, usageRecord{
+		thing:   thingID,
+		channel: chanID,
+		size:    size,
+		time:    t,
+	})
+
+	return nil
+}
+
+func (urm *usageRepositoryMock) RetrieveByThing(_ context.Context, id string, from, to time.Time) (things.Usage, error) {
+	return urm.retrieve(func(r usageRecord) bool { return r.thing == id }, from, to), nil
+}
+
+func (urm *usageRepositoryMock) RetrieveByChannel(_ context.Context, id string, from, to time.Time) (things.Usage, error) {
+	return urm.retrieve(func(r usageRecord) bool { return r.channel == id }, from, to), nil
+}
+
+func (urm *usageRepositoryMock) retrieve(match func(usageRecord) bool, from, to time.Time) things.Usage {
+	urm.mu.Lock()
+	defer urm.mu.Unlock()
+
+	usage := things.Usage{
+		From: from,
+		To:   to,
+	}
+
+	for _, r := range urm.records {
+		if !match(r) || r.time.Before(from) || r.time.After(to) {
+			continue
+		}
+
+		usage.Messages++
+		usage.Bytes += r.size
+	}
+
+	return usage
+}
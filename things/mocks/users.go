@@ -9,12 +9,17 @@ package mocks
 
 import (
 	"context"
+	"strings"
 
 	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/users"
 	"google.golang.org/grpc"
 )
 
+// actorSep separates the token of the impersonated user and the email of the
+// impersonating administrator within the impersonation tokens.
+const actorSep = "|"
+
 var _ mainflux.UsersServiceClient = (*usersServiceMock)(nil)
 
 type usersServiceMock struct {
@@ -27,8 +32,24 @@ func NewUsersService(users map[string]string) mainflux.UsersServiceClient {
 }
 
 func (svc usersServiceMock) Identify(ctx context.Context, in *mainflux.Token, opts ...grpc.CallOption) (*mainflux.UserID, error) {
-	if id, ok := svc.users[in.Value]; ok {
-		return &mainflux.UserID{Value: id}, nil
+	parts := strings.SplitN(in.Value, actorSep, 2)
+	id, ok := svc.users[parts[0]]
+	if !ok {
+		return nil, users.ErrUnauthorizedAccess
+	}
+
+	res := &mainflux.UserID{Value: id}
+	if len(parts) > 1 {
+		res.Actor = parts[1]
+	}
+	return res, nil
+}
+
+func (svc usersServiceMock) Exists(ctx context.Context, in *mainflux.UserID, opts ...grpc.CallOption) (*mainflux.UserID, error) {
+	for _, id := range svc.users {
+		if id == in.Value {
+			return &mainflux.UserID{Value: id}, nil
+		}
 	}
-	return nil, users.ErrUnauthorizedAccess
+	return nil, users.ErrNotFound
 }
@@ -0,0 +1,12 @@
+//
+// Copyright (c) 2018
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// Package nats contains NATS message consumer that records messages usage,
+// things activity, observed subtopics and diagnostics, the distribution of
+// signed thing keys revocations, and the publisher of the things service
+// events.
+package nats
@@ -0,0 +1,47 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package nats
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/things"
+	broker "github.com/nats-io/go-nats"
+)
+
+var _ things.EventPublisher = (*eventPublisher)(nil)
+
+type eventPublisher struct {
+	nc *broker.Conn
+}
+
+// NewEventPublisher returns the publisher of the things service events to
+// NATS. Every event is published as JSON object, holding the fields of the
+// event stream entry apart from the thing keys, to the subject made of the
+// ThingsEvents prefix and the event operation, so that the consumers can
+// subscribe to the events of the things or the channels only (e.g.
+// things.events.thing.>).
+func NewEventPublisher(nc *broker.Conn) things.EventPublisher {
+	return eventPublisher{nc: nc}
+}
+
+func (ep eventPublisher) Publish(event map[string]interface{}) error {
+	op, ok := event["operation"].(string)
+	if !ok || op == "" {
+		return things.ErrMalformedEntity
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return ep.nc.Publish(fmt.Sprintf("%s.%s", mainflux.ThingsEvents, op), data)
+}
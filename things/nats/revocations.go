@@ -0,0 +1,196 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package nats
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	log "github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/things"
+	broker "github.com/nats-io/go-nats"
+)
+
+var (
+	_ things.RevocationRepository = (*revocationRepository)(nil)
+	_ things.RevocationRepository = (*revocationList)(nil)
+)
+
+type revocation struct {
+	ThingID   string `json:"thing_id"`
+	Version   uint64 `json:"version"`
+	ExpiresAt int64  `json:"expires_at"`
+}
+
+type revocationRepository struct {
+	things.RevocationRepository
+	nc     *broker.Conn
+	logger log.Logger
+}
+
+// NewRevocationRepository returns revocation repository that publishes the
+// saved revocations, and replies to the requests for the revocations that
+// are in effect, so that the signed thing keys can be verified without the
+// things service.
+func NewRevocationRepository(repo things.RevocationRepository, nc *broker.Conn, logger log.Logger) (things.RevocationRepository, error) {
+	rr := revocationRepository{
+		RevocationRepository: repo,
+		nc:                   nc,
+		logger:               logger,
+	}
+
+	if _, err := nc.QueueSubscribe(mainflux.RevocationsList, queue, rr.list); err != nil {
+		return nil, err
+	}
+
+	return rr, nil
+}
+
+func (rr revocationRepository) Save(ctx context.Context, r things.Revocation) error {
+	if err := rr.RevocationRepository.Save(ctx, r); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(toRevocation(r))
+	if err != nil {
+		return err
+	}
+
+	return rr.nc.Publish(mainflux.Revocations, data)
+}
+
+func (rr revocationRepository) list(m *broker.Msg) {
+	revs, err := rr.RetrieveAll(context.Background(), time.Now())
+	if err != nil {
+		rr.logger.Warn(fmt.Sprintf("Failed to retrieve revocations: %s", err))
+		return
+	}
+
+	res := []revocation{}
+	for _, r := range revs {
+		res = append(res, toRevocation(r))
+	}
+
+	data, err := json.Marshal(res)
+	if err != nil {
+		rr.logger.Warn(fmt.Sprintf("Failed to marshal revocations: %s", err))
+		return
+	}
+
+	if err := rr.nc.Publish(m.Reply, data); err != nil {
+		rr.logger.Warn(fmt.Sprintf("Failed to publish revocations: %s", err))
+	}
+}
+
+type revocationList struct {
+	mu          sync.RWMutex
+	revocations map[string]things.Revocation
+}
+
+// NewRevocationList returns in-memory revocation repository that is kept in
+// sync with the things service. It is meant to be used by the services that
+// verify signed thing keys on their own.
+func NewRevocationList(nc *broker.Conn, timeout time.Duration, logger log.Logger) (things.RevocationRepository, error) {
+	rl := &revocationList{
+		revocations: map[string]things.Revocation{},
+	}
+
+	_, err := nc.Subscribe(mainflux.Revocations, func(m *broker.Msg) {
+		var r revocation
+		if err := json.Unmarshal(m.Data, &r); err != nil {
+			logger.Warn(fmt.Sprintf("Failed to unmarshal revocation: %s", err))
+			return
+		}
+
+		rl.Save(context.Background(), toThingsRevocation(r))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := nc.Request(mainflux.RevocationsList, nil, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	var revs []revocation
+	if err := json.Unmarshal(res.Data, &revs); err != nil {
+		return nil, err
+	}
+
+	for _, r := range revs {
+		rl.Save(context.Background(), toThingsRevocation(r))
+	}
+
+	return rl, nil
+}
+
+func (rl *revocationList) Save(_ context.Context, r things.Revocation) error {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	if old, ok := rl.revocations[r.ThingID]; ok && old.Version > r.Version {
+		return nil
+	}
+
+	rl.revocations[r.ThingID] = r
+	return nil
+}
+
+func (rl *revocationList) RetrieveByThing(_ context.Context, id string) (things.Revocation, error) {
+	rl.mu.RLock()
+	r, ok := rl.revocations[id]
+	rl.mu.RUnlock()
+
+	if !ok {
+		return things.Revocation{}, things.ErrNotFound
+	}
+
+	if r.ExpiresAt.Before(time.Now()) {
+		rl.mu.Lock()
+		delete(rl.revocations, id)
+		rl.mu.Unlock()
+		return things.Revocation{}, things.ErrNotFound
+	}
+
+	return r, nil
+}
+
+func (rl *revocationList) RetrieveAll(_ context.Context, now time.Time) ([]things.Revocation, error) {
+	rl.mu.RLock()
+	defer rl.mu.RUnlock()
+
+	revs := []things.Revocation{}
+	for _, r := range rl.revocations {
+		if r.ExpiresAt.After(now) {
+			revs = append(revs, r)
+		}
+	}
+
+	return revs, nil
+}
+
+func toRevocation(r things.Revocation) revocation {
+	return revocation{
+		ThingID:   r.ThingID,
+		Version:   r.Version,
+		ExpiresAt: r.ExpiresAt.Unix(),
+	}
+}
+
+func toThingsRevocation(r revocation) things.Revocation {
+	return things.Revocation{
+		ThingID:   r.ThingID,
+		Version:   r.Version,
+		ExpiresAt: time.Unix(r.ExpiresAt, 0).UTC(),
+	}
+}
@@ -0,0 +1,75 @@
+//
+// Copyright (c) 2018
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package nats
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/mainflux/mainflux"
+	log "github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/things"
+	broker "github.com/nats-io/go-nats"
+)
+
+const (
+	queue = "things"
+	input = "channel.>"
+)
+
+type consumer struct {
+	svc    things.Service
+	logger log.Logger
+}
+
+// Subscribe subscribes to the messages published by the adapters and
+// records the number of messages and payload bytes per thing and channel,
+// the time the things were last seen publishing at, the subtopics observed
+// per channel, as well as the diagnostics the things report.
+func Subscribe(svc things.Service, nc *broker.Conn, logger log.Logger) error {
+	c := consumer{
+		svc:    svc,
+		logger: logger,
+	}
+
+	_, err := nc.QueueSubscribe(input, queue, c.consume)
+	return err
+}
+
+func (c consumer) consume(m *broker.Msg) {
+	var msg mainflux.RawMessage
+	if err := proto.Unmarshal(m.Data, &msg); err != nil {
+		c.logger.Warn(fmt.Sprintf("Failed to unmarshal received message: %s", err))
+		return
+	}
+
+	if err := c.svc.RecordUsage(context.Background(), msg.GetPublisher(), msg.GetChannel(), uint64(len(msg.GetPayload()))); err != nil {
+		c.logger.Warn(fmt.Sprintf("Failed to record usage: %s", err))
+	}
+
+	if err := c.svc.RecordActivity(context.Background(), msg.GetPublisher()); err != nil {
+		c.logger.Warn(fmt.Sprintf("Failed to record activity: %s", err))
+	}
+
+	if msg.GetSubtopic() == "" {
+		return
+	}
+
+	if err := c.svc.RecordSubtopic(context.Background(), msg.GetChannel(), msg.GetSubtopic()); err != nil {
+		c.logger.Warn(fmt.Sprintf("Failed to record subtopic: %s", err))
+	}
+
+	if msg.GetSubtopic() != things.DiagnosticsSubtopic {
+		return
+	}
+
+	if err := c.svc.RecordDiagnostics(context.Background(), msg.GetPublisher(), msg.GetPayload()); err != nil {
+		c.logger.Warn(fmt.Sprintf("Failed to record diagnostics: %s", err))
+	}
+}
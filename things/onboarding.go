@@ -0,0 +1,29 @@
+//
+// Copyright (c) 2018
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package things
+
+import "time"
+
+// Onboarding represents the provisioning payload installer applications use
+// to configure a device in the field.
+type Onboarding struct {
+	ThingID   string
+	ThingKey  string
+	Channels  []string
+	Endpoints map[string]string
+	ExpiresAt time.Time
+}
+
+// OnboardingProvider specifies an API for issuing signed provisioning
+// payloads.
+type OnboardingProvider interface {
+	// Issue completes the provisioning payload of the thing, connected to the
+	// provided channels, with platform endpoints and expiration time. It
+	// returns the payload along with its signed, QR-encodable representation.
+	Issue(string, string, []string) (Onboarding, string, error)
+}
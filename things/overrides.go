@@ -0,0 +1,34 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package things
+
+import "errors"
+
+// ErrQuotaExceeded indicates that the owner reached the number of things
+// or channels its configuration override allows.
+var ErrQuotaExceeded = errors.New("quota exceeded")
+
+// Override represents the configuration of the owner overriding the
+// deployment defaults. The rate limit applies to the owner's channels
+// without the rate limit of their own, and the protocols restrict the ones
+// the owner's channels can be published to over. Zero values keep the
+// defaults.
+type Override struct {
+	RateLimit   RateLimit
+	MaxThings   uint64
+	MaxChannels uint64
+	Protocols   []string
+}
+
+// OverrideProvider specifies an API for consulting the configuration
+// overrides of the owners, that are managed centrally.
+type OverrideProvider interface {
+	// Override returns the configuration override of the owner, that is
+	// empty if the owner has none.
+	Override(string) Override
+}
@@ -0,0 +1,101 @@
+//
+// Copyright (c) 2018
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package things
+
+const (
+	nameField     = "name"
+	metadataField = "metadata"
+)
+
+// PatchThing applies the JSON merge patch (RFC 7386) to the thing. Only name,
+// tags and metadata can be patched; patches touching any other field, or
+// holding values of unexpected types, are rejected with ErrMalformedEntity.
+// Tags, being an array, are replaced as a whole.
+func PatchThing(thing Thing, patch map[string]interface{}) (Thing, error) {
+	name, tags, metadata, err := applyPatch(thing.Name, thing.Tags, thing.Metadata, patch)
+	if err != nil {
+		return Thing{}, err
+	}
+
+	thing.Name = name
+	thing.Tags = tags
+	thing.Metadata = metadata
+	return thing, nil
+}
+
+// PatchChannel applies the JSON merge patch (RFC 7386) to the channel. Only
+// name, tags and metadata can be patched; patches touching any other field,
+// or holding values of unexpected types, are rejected with
+// ErrMalformedEntity. Tags, being an array, are replaced as a whole.
+func PatchChannel(channel Channel, patch map[string]interface{}) (Channel, error) {
+	name, tags, metadata, err := applyPatch(channel.Name, channel.Tags, channel.Metadata, patch)
+	if err != nil {
+		return Channel{}, err
+	}
+
+	channel.Name = name
+	channel.Tags = tags
+	channel.Metadata = metadata
+	return channel, nil
+}
+
+func applyPatch(name string, tags []string, metadata, patch map[string]interface{}) (string, []string, map[string]interface{}, error) {
+	if len(patch) == 0 {
+		return "", nil, nil, ErrMalformedEntity
+	}
+
+	for k, v := range patch {
+		switch k {
+		case nameField:
+			if v == nil {
+				name = ""
+				continue
+			}
+
+			n, ok := v.(string)
+			if !ok {
+				return "", nil, nil, ErrMalformedEntity
+			}
+			name = n
+		case tagsField:
+			if v == nil {
+				tags = nil
+				continue
+			}
+
+			vals, ok := v.([]interface{})
+			if !ok {
+				return "", nil, nil, ErrMalformedEntity
+			}
+
+			tags = make([]string, len(vals))
+			for i, val := range vals {
+				tag, ok := val.(string)
+				if !ok {
+					return "", nil, nil, ErrMalformedEntity
+				}
+				tags[i] = tag
+			}
+		case metadataField:
+			if v == nil {
+				metadata = nil
+				continue
+			}
+
+			m, ok := v.(map[string]interface{})
+			if !ok {
+				return "", nil, nil, ErrMalformedEntity
+			}
+			metadata = MergeMetadata(metadata, m)
+		default:
+			return "", nil, nil, ErrMalformedEntity
+		}
+	}
+
+	return name, tags, metadata, nil
+}
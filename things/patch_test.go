@@ -0,0 +1,99 @@
+//
+// Copyright (c) 2018
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package things_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux/things"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThingPatch(t *testing.T) {
+	thing := things.Thing{
+		ID:       "1",
+		Name:     "name",
+		Key:      "key",
+		Metadata: map[string]interface{}{"a": "b", "c": "d"},
+	}
+
+	cases := []struct {
+		desc  string
+		patch map[string]interface{}
+		thing things.Thing
+		err   error
+	}{
+		{
+			desc:  "patch name",
+			patch: map[string]interface{}{"name": "new"},
+			thing: things.Thing{ID: "1", Name: "new", Key: "key", Metadata: map[string]interface{}{"a": "b", "c": "d"}},
+			err:   nil,
+		},
+		{
+			desc:  "remove name",
+			patch: map[string]interface{}{"name": nil},
+			thing: things.Thing{ID: "1", Name: "", Key: "key", Metadata: map[string]interface{}{"a": "b", "c": "d"}},
+			err:   nil,
+		},
+		{
+			desc:  "merge metadata",
+			patch: map[string]interface{}{"metadata": map[string]interface{}{"a": nil, "e": "f"}},
+			thing: things.Thing{ID: "1", Name: "name", Key: "key", Metadata: map[string]interface{}{"c": "d", "e": "f"}},
+			err:   nil,
+		},
+		{
+			desc:  "remove metadata",
+			patch: map[string]interface{}{"metadata": nil},
+			thing: things.Thing{ID: "1", Name: "name", Key: "key", Metadata: nil},
+			err:   nil,
+		},
+		{
+			desc:  "replace tags",
+			patch: map[string]interface{}{"tags": []interface{}{"edge", "v2"}},
+			thing: things.Thing{ID: "1", Name: "name", Key: "key", Tags: []string{"edge", "v2"}, Metadata: map[string]interface{}{"a": "b", "c": "d"}},
+			err:   nil,
+		},
+		{
+			desc:  "patch tags with invalid type",
+			patch: map[string]interface{}{"tags": []interface{}{"edge", 1}},
+			thing: things.Thing{},
+			err:   things.ErrMalformedEntity,
+		},
+		{
+			desc:  "patch name with invalid type",
+			patch: map[string]interface{}{"name": 1},
+			thing: things.Thing{},
+			err:   things.ErrMalformedEntity,
+		},
+		{
+			desc:  "patch metadata with invalid type",
+			patch: map[string]interface{}{"metadata": "value"},
+			thing: things.Thing{},
+			err:   things.ErrMalformedEntity,
+		},
+		{
+			desc:  "patch read-only field",
+			patch: map[string]interface{}{"key": "new"},
+			thing: things.Thing{},
+			err:   things.ErrMalformedEntity,
+		},
+		{
+			desc:  "apply empty patch",
+			patch: map[string]interface{}{},
+			thing: things.Thing{},
+			err:   things.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		th, err := things.PatchThing(thing, tc.patch)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.thing, th, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.thing, th))
+	}
+}
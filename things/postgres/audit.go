@@ -0,0 +1,214 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq" // required for DB access
+	mfpostgres "github.com/mainflux/mainflux/postgres"
+	"github.com/mainflux/mainflux/things"
+)
+
+var _ things.AuditRepository = (*auditRepository)(nil)
+
+type auditRepository struct {
+	db *sqlx.DB
+}
+
+// NewAuditRepository instantiates a PostgreSQL implementation of audit
+// repository.
+func NewAuditRepository(db *sqlx.DB) things.AuditRepository {
+	return &auditRepository{
+		db: db,
+	}
+}
+
+func (ar auditRepository) Save(ctx context.Context, events ...things.AuditEvent) error {
+	q := `INSERT INTO audit (owner, actor, operation, entity_kind, entity_id, diff, occurred_at)
+	      VALUES (:owner, :actor, :operation, :entity_kind, :entity_id, :diff, :occurred_at);`
+
+	return mfpostgres.Transact(ctx, ar.db, func(tx *sqlx.Tx) error {
+		for _, e := range events {
+			dbe, err := toDBAuditEvent(e)
+			if err != nil {
+				return err
+			}
+
+			if _, err := tx.NamedExecContext(ctx, q, dbe); err != nil {
+				pqErr, ok := err.(*pq.Error)
+				if ok {
+					switch pqErr.Code.Name() {
+					case errInvalid, errTruncation:
+						return things.ErrMalformedEntity
+					}
+				}
+
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (ar auditRepository) RetrieveAll(ctx context.Context, owner string, query things.AuditQuery, offset, limit uint64) (things.AuditPage, error) {
+	fq := getAuditQuery(query)
+
+	q := fmt.Sprintf(`SELECT owner, actor, operation, entity_kind, entity_id, diff, occurred_at FROM audit
+	      WHERE owner = :owner %s ORDER BY occurred_at DESC, seq DESC LIMIT :limit OFFSET :offset;`, fq)
+
+	params := map[string]interface{}{
+		"owner":       owner,
+		"actor":       query.Actor,
+		"operation":   query.Operation,
+		"entity_kind": query.EntityKind,
+		"entity_id":   query.EntityID,
+		"from":        query.From.UTC(),
+		"to":          query.To.UTC(),
+		"limit":       limit,
+		"offset":      offset,
+	}
+
+	rows, err := ar.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && errInvalid == pqErr.Code.Name() {
+			return things.AuditPage{}, things.ErrMalformedEntity
+		}
+
+		return things.AuditPage{}, err
+	}
+	defer rows.Close()
+
+	items := []things.AuditEvent{}
+	for rows.Next() {
+		var dbe dbAuditEvent
+		if err := rows.StructScan(&dbe); err != nil {
+			return things.AuditPage{}, err
+		}
+
+		e, err := toAuditEvent(dbe)
+		if err != nil {
+			return things.AuditPage{}, err
+		}
+
+		items = append(items, e)
+	}
+
+	cq := fmt.Sprintf(`SELECT COUNT(*) FROM audit WHERE owner = :owner %s;`, fq)
+
+	total, err := total(ctx, ar.db, cq, params)
+	if err != nil {
+		return things.AuditPage{}, err
+	}
+
+	page := things.AuditPage{
+		Events: items,
+		PageMetadata: things.PageMetadata{
+			Total:  total,
+			Offset: offset,
+			Limit:  limit,
+		},
+	}
+
+	return page, nil
+}
+
+// getAuditQuery returns the conditions of the set query fields.
+func getAuditQuery(query things.AuditQuery) string {
+	conds := []string{}
+	if query.Actor != "" {
+		conds = append(conds, "actor = :actor")
+	}
+	if query.Operation != "" {
+		conds = append(conds, "operation = :operation")
+	}
+	if query.EntityKind != "" {
+		conds = append(conds, "entity_kind = :entity_kind")
+	}
+	if query.EntityID != "" {
+		conds = append(conds, "entity_id = :entity_id")
+	}
+	if !query.From.IsZero() {
+		conds = append(conds, "occurred_at >= :from")
+	}
+	if !query.To.IsZero() {
+		conds = append(conds, "occurred_at <= :to")
+	}
+
+	if len(conds) == 0 {
+		return ""
+	}
+
+	return "AND " + strings.Join(conds, " AND ")
+}
+
+type dbDiff struct {
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+}
+
+type dbAuditEvent struct {
+	Owner      string    `db:"owner"`
+	Actor      string    `db:"actor"`
+	Operation  string    `db:"operation"`
+	EntityKind string    `db:"entity_kind"`
+	EntityID   string    `db:"entity_id"`
+	Diff       string    `db:"diff"`
+	OccurredAt time.Time `db:"occurred_at"`
+}
+
+func toDBAuditEvent(e things.AuditEvent) (dbAuditEvent, error) {
+	diff := map[string]dbDiff{}
+	for field, d := range e.Diff {
+		diff[field] = dbDiff(d)
+	}
+
+	data, err := json.Marshal(diff)
+	if err != nil {
+		return dbAuditEvent{}, err
+	}
+
+	return dbAuditEvent{
+		Owner:      e.Owner,
+		Actor:      e.Actor,
+		Operation:  e.Operation,
+		EntityKind: e.EntityKind,
+		EntityID:   e.EntityID,
+		Diff:       string(data),
+		OccurredAt: e.OccurredAt.UTC(),
+	}, nil
+}
+
+func toAuditEvent(dbe dbAuditEvent) (things.AuditEvent, error) {
+	var diff map[string]dbDiff
+	if err := json.Unmarshal([]byte(dbe.Diff), &diff); err != nil {
+		return things.AuditEvent{}, err
+	}
+
+	e := things.AuditEvent{
+		Owner:      dbe.Owner,
+		Actor:      dbe.Actor,
+		Operation:  dbe.Operation,
+		EntityKind: dbe.EntityKind,
+		EntityID:   dbe.EntityID,
+		Diff:       map[string]things.Diff{},
+		OccurredAt: dbe.OccurredAt.UTC(),
+	}
+	for field, d := range diff {
+		e.Diff[field] = things.Diff(d)
+	}
+
+	return e, nil
+}
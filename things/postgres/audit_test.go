@@ -0,0 +1,177 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/things"
+	"github.com/mainflux/mainflux/things/postgres"
+	"github.com/mainflux/mainflux/things/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditSave(t *testing.T) {
+	auditRepo := postgres.NewAuditRepository(db)
+
+	email := "audit-save@example.com"
+
+	id, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	event := things.AuditEvent{
+		Owner:      email,
+		Actor:      email,
+		Operation:  things.UpdateOperation,
+		EntityKind: things.ThingKind,
+		EntityID:   id,
+		Diff:       map[string]things.Diff{"name": {Before: "old", After: "new"}},
+		OccurredAt: time.Now(),
+	}
+
+	invalid := event
+	invalid.EntityID = "invalid"
+
+	cases := []struct {
+		desc   string
+		events []things.AuditEvent
+		err    error
+	}{
+		{
+			desc:   "save events",
+			events: []things.AuditEvent{event, event},
+			err:    nil,
+		},
+		{
+			desc:   "save event with invalid entity ID",
+			events: []things.AuditEvent{invalid},
+			err:    things.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		err := auditRepo.Save(context.Background(), tc.events...)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestAuditRetrieveAll(t *testing.T) {
+	auditRepo := postgres.NewAuditRepository(db)
+
+	email := "audit-retrieve@example.com"
+	actor := "audit-actor@example.com"
+	n := uint64(10)
+
+	thingID, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	chanID, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	for i := uint64(0); i < n; i++ {
+		e := things.AuditEvent{
+			Owner:      email,
+			Actor:      email,
+			Operation:  things.UpdateOperation,
+			EntityKind: things.ThingKind,
+			EntityID:   thingID,
+			Diff:       map[string]things.Diff{"name": {Before: fmt.Sprintf("name-%d", i), After: fmt.Sprintf("name-%d", i+1)}},
+			OccurredAt: now.Add(time.Duration(i) * time.Second),
+		}
+		if i%2 == 1 {
+			e.Actor = actor
+			e.Operation = things.ConnectOperation
+			e.Diff = map[string]things.Diff{"channel": {After: chanID}}
+		}
+		err := auditRepo.Save(context.Background(), e)
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	}
+
+	cases := map[string]struct {
+		owner  string
+		query  things.AuditQuery
+		offset uint64
+		limit  uint64
+		size   uint64
+		total  uint64
+		err    error
+	}{
+		"retrieve all events": {
+			owner:  email,
+			query:  things.AuditQuery{},
+			offset: 0,
+			limit:  n,
+			size:   n,
+			total:  n,
+		},
+		"retrieve subset of events": {
+			owner:  email,
+			query:  things.AuditQuery{},
+			offset: n / 2,
+			limit:  n,
+			size:   n / 2,
+			total:  n,
+		},
+		"retrieve events by actor": {
+			owner:  email,
+			query:  things.AuditQuery{Actor: actor},
+			offset: 0,
+			limit:  n,
+			size:   n / 2,
+			total:  n / 2,
+		},
+		"retrieve events by operation and entity": {
+			owner:  email,
+			query:  things.AuditQuery{Operation: things.UpdateOperation, EntityKind: things.ThingKind, EntityID: thingID},
+			offset: 0,
+			limit:  n,
+			size:   n / 2,
+			total:  n / 2,
+		},
+		"retrieve events within time period": {
+			owner:  email,
+			query:  things.AuditQuery{From: now, To: now.Add(2 * time.Second)},
+			offset: 0,
+			limit:  n,
+			size:   3,
+			total:  3,
+		},
+		"retrieve events of entity owned by other user": {
+			owner:  "wrong",
+			query:  things.AuditQuery{},
+			offset: 0,
+			limit:  n,
+			size:   0,
+			total:  0,
+		},
+		"retrieve events of entity having invalid ID": {
+			owner:  email,
+			query:  things.AuditQuery{EntityID: "invalid"},
+			offset: 0,
+			limit:  n,
+			err:    things.ErrMalformedEntity,
+		},
+	}
+
+	for desc, tc := range cases {
+		page, err := auditRepo.RetrieveAll(context.Background(), tc.owner, tc.query, tc.offset, tc.limit)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+		size := uint64(len(page.Events))
+		assert.Equal(t, tc.size, size, fmt.Sprintf("%s: expected size %d got %d\n", desc, tc.size, size))
+		assert.Equal(t, tc.total, page.Total, fmt.Sprintf("%s: expected total %d got %d\n", desc, tc.total, page.Total))
+	}
+
+	page, err := auditRepo.RetrieveAll(context.Background(), email, things.AuditQuery{}, 0, 1)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	expected := map[string]things.Diff{"channel": {After: chanID}}
+	assert.Equal(t, expected, page.Events[0].Diff, fmt.Sprintf("expected most recent diff %v got %v\n", expected, page.Events[0].Diff))
+}
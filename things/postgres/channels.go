@@ -8,6 +8,7 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
@@ -15,6 +16,7 @@ import (
 	"github.com/gofrs/uuid"
 	"github.com/jmoiron/sqlx"
 	"github.com/lib/pq"
+	mfpostgres "github.com/mainflux/mainflux/postgres"
 	"github.com/mainflux/mainflux/things"
 )
 
@@ -32,21 +34,23 @@ func NewChannelRepository(db *sqlx.DB) things.ChannelRepository {
 	}
 }
 
-func (cr channelRepository) Save(channel things.Channel) (string, error) {
-	q := `INSERT INTO channels (id, owner, name, metadata)
-        VALUES (:id, :owner, :name, :metadata);`
+func (cr channelRepository) Save(ctx context.Context, channel things.Channel) (string, error) {
+	q := `INSERT INTO channels (id, owner, name, state, tags, metadata)
+        VALUES (:id, :owner, :name, :state, :tags, :metadata);`
 
 	dbch, err := toDBChannel(channel)
 	if err != nil {
 		return "", err
 	}
 
-	if _, err := cr.db.NamedExec(q, dbch); err != nil {
+	if _, err := cr.db.NamedExecContext(ctx, q, dbch); err != nil {
 		pqErr, ok := err.(*pq.Error)
 		if ok {
 			switch pqErr.Code.Name() {
 			case errInvalid, errTruncation:
 				return "", things.ErrMalformedEntity
+			case errDuplicate:
+				return "", things.ErrConflict
 			}
 		}
 
@@ -56,21 +60,23 @@ func (cr channelRepository) Save(channel things.Channel) (string, error) {
 	return channel.ID, nil
 }
 
-func (cr channelRepository) Update(channel things.Channel) error {
-	q := `UPDATE channels SET name = :name, metadata = :metadata WHERE owner = :owner AND id = :id;`
+func (cr channelRepository) Update(ctx context.Context, channel things.Channel) error {
+	q := `UPDATE channels SET name = :name, tags = :tags, metadata = :metadata WHERE owner = :owner AND id = :id;`
 
 	dbch, err := toDBChannel(channel)
 	if err != nil {
 		return err
 	}
 
-	res, err := cr.db.NamedExec(q, dbch)
+	res, err := cr.db.NamedExecContext(ctx, q, dbch)
 	if err != nil {
 		pqErr, ok := err.(*pq.Error)
 		if ok {
 			switch pqErr.Code.Name() {
 			case errInvalid, errTruncation:
 				return things.ErrMalformedEntity
+			case errDuplicate:
+				return things.ErrConflict
 			}
 		}
 
@@ -89,13 +95,66 @@ func (cr channelRepository) Update(channel things.Channel) error {
 	return nil
 }
 
-func (cr channelRepository) RetrieveByID(owner, id string) (things.Channel, error) {
-	q := `SELECT name, metadata FROM channels WHERE id = $1 AND owner = $2;`
+func (cr channelRepository) Patch(ctx context.Context, owner, id string, patch map[string]interface{}) (things.Channel, error) {
+	var ch things.Channel
+	err := mfpostgres.Transact(ctx, cr.db, func(tx *sqlx.Tx) error {
+		q := `SELECT name, state, tags, metadata FROM channels WHERE id = $1 AND owner = $2 FOR UPDATE;`
+
+		dbch := dbChannel{
+			ID:    id,
+			Owner: owner,
+		}
+		if err := tx.QueryRowxContext(ctx, q, id, owner).StructScan(&dbch); err != nil {
+			pqErr, ok := err.(*pq.Error)
+			if err == sql.ErrNoRows || ok && errInvalid == pqErr.Code.Name() {
+				return things.ErrNotFound
+			}
+			return err
+		}
+
+		old, err := toChannel(dbch)
+		if err != nil {
+			return err
+		}
+
+		if ch, err = things.PatchChannel(old, patch); err != nil {
+			return err
+		}
+
+		if dbch, err = toDBChannel(ch); err != nil {
+			return err
+		}
+
+		q = `UPDATE channels SET name = :name, tags = :tags, metadata = :metadata WHERE owner = :owner AND id = :id;`
+		if _, err := tx.NamedExecContext(ctx, q, dbch); err != nil {
+			pqErr, ok := err.(*pq.Error)
+			if ok {
+				switch pqErr.Code.Name() {
+				case errInvalid, errTruncation:
+					return things.ErrMalformedEntity
+				case errDuplicate:
+					return things.ErrConflict
+				}
+			}
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return things.Channel{}, err
+	}
+
+	return ch, nil
+}
+
+func (cr channelRepository) RetrieveByID(ctx context.Context, owner, id string) (things.Channel, error) {
+	q := `SELECT name, state, tags, metadata FROM channels WHERE id = $1 AND owner = $2;`
 	dbch := dbChannel{
 		ID:    id,
 		Owner: owner,
 	}
-	if err := cr.db.QueryRowx(q, id, owner).StructScan(&dbch); err != nil {
+	if err := cr.db.QueryRowxContext(ctx, q, id, owner).StructScan(&dbch); err != nil {
 		empty := things.Channel{}
 		pqErr, ok := err.(*pq.Error)
 		if err == sql.ErrNoRows || ok && errInvalid == pqErr.Code.Name() {
@@ -107,80 +166,208 @@ func (cr channelRepository) RetrieveByID(owner, id string) (things.Channel, erro
 	return toChannel(dbch)
 }
 
-func (cr channelRepository) RetrieveAll(owner string, offset, limit uint64, name string) (things.ChannelsPage, error) {
-	nq := ""
-	if name != "" {
-		name = fmt.Sprintf(`%%%s%%`, name)
-		nq = `AND name LIKE :name`
+func (cr channelRepository) RetrieveState(ctx context.Context, id string) (things.State, error) {
+	q := `SELECT state FROM channels WHERE id = $1;`
+
+	var state string
+	if err := cr.db.QueryRowxContext(ctx, q, id).Scan(&state); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if err == sql.ErrNoRows || ok && errInvalid == pqErr.Code.Name() {
+			return "", things.ErrNotFound
+		}
+		return "", err
 	}
 
-	q := fmt.Sprintf(`SELECT id, name, metadata FROM channels
-	      WHERE owner = :owner %s ORDER BY id LIMIT :limit OFFSET :offset;`, nq)
+	return things.State(state), nil
+}
+
+func (cr channelRepository) UpdateState(ctx context.Context, owner, id string, state things.State) error {
+	q := `UPDATE channels SET state = :state WHERE owner = :owner AND id = :id;`
+	dbch := dbChannel{
+		ID:    id,
+		Owner: owner,
+		State: string(state),
+	}
+
+	res, err := cr.db.NamedExecContext(ctx, q, dbch)
+	if err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && errInvalid == pqErr.Code.Name() {
+			return things.ErrNotFound
+		}
+		return err
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if cnt == 0 {
+		return things.ErrNotFound
+	}
+
+	return nil
+}
+
+func (cr channelRepository) Archive(ctx context.Context, owner string, selector map[string]interface{}, tags things.TagFilter) ([]things.Channel, error) {
+	sel, err := json.Marshal(selector)
+	if err != nil {
+		return []things.Channel{}, things.ErrMalformedEntity
+	}
+
+	// Channels without metadata hold JSON null, which is matched only by
+	// the empty selector.
+	q := fmt.Sprintf(`UPDATE channels SET state = :archived
+	      WHERE owner = :owner AND state = :active
+	      AND (CAST(metadata AS JSONB) @> CAST(:selector AS JSONB) OR CAST(:selector AS JSONB) = '{}') %s
+	      RETURNING id, owner, name, state, tags, metadata;`, getTagsQuery(tags))
 
 	params := map[string]interface{}{
-		"owner":  owner,
-		"limit":  limit,
-		"offset": offset,
-		"name":   name,
+		"archived": string(things.Archived),
+		"active":   string(things.Active),
+		"owner":    owner,
+		"selector": string(sel),
+		"tags":     pq.Array(tags.Tags),
 	}
-	rows, err := cr.db.NamedQuery(q, params)
+
+	rows, err := cr.db.NamedQueryContext(ctx, q, params)
 	if err != nil {
-		return things.ChannelsPage{}, err
+		return []things.Channel{}, err
 	}
 	defer rows.Close()
 
 	items := []things.Channel{}
 	for rows.Next() {
-		dbch := dbChannel{Owner: owner}
+		var dbch dbChannel
 		if err := rows.StructScan(&dbch); err != nil {
-			return things.ChannelsPage{}, err
+			return []things.Channel{}, err
 		}
+
 		ch, err := toChannel(dbch)
 		if err != nil {
-			return things.ChannelsPage{}, err
+			return []things.Channel{}, err
 		}
 
 		items = append(items, ch)
 	}
 
-	cq := ""
-	if name != "" {
-		cq = `AND name = $2`
+	return items, nil
+}
+
+func (cr channelRepository) RetrieveAll(ctx context.Context, owner string, offset, limit uint64, name string, metadata map[string]interface{}, tags things.TagFilter, after string) (things.ChannelsPage, error) {
+	return cr.retrieve(ctx, owner, nil, offset, limit, name, metadata, tags, after)
+}
+
+func (cr channelRepository) RetrieveAccessible(ctx context.Context, user string, shared []string, offset, limit uint64, name string, metadata map[string]interface{}, tags things.TagFilter, after string) (things.ChannelsPage, error) {
+	return cr.retrieve(ctx, user, shared, offset, limit, name, metadata, tags, after)
+}
+
+func (cr channelRepository) CountAccessible(ctx context.Context, user string, shared []string, name string, metadata map[string]interface{}, tags things.TagFilter) (uint64, error) {
+	nq, name := getNameQuery(name)
+	mq, mdata, err := getMetadataQuery(metadata)
+	if err != nil {
+		return 0, err
+	}
+	tq := getTagsQuery(tags)
+
+	oq := `owner = :owner`
+	if len(shared) > 0 {
+		oq = `(owner = :owner OR id = ANY(:shared))`
+	}
+
+	cq := fmt.Sprintf(`SELECT COUNT(*) FROM channels WHERE %s %s %s %s;`, oq, nq, mq, tq)
+
+	params := map[string]interface{}{
+		"owner":    user,
+		"shared":   pq.Array(shared),
+		"name":     name,
+		"metadata": mdata,
+		"tags":     pq.Array(tags.Tags),
 	}
 
-	q = fmt.Sprintf(`SELECT COUNT(*) FROM channels WHERE owner = $1 %s;`, cq)
+	return total(ctx, cr.db, cq, params)
+}
 
-	total := uint64(0)
-	switch name {
-	case "":
-		if err := cr.db.Get(&total, q, owner); err != nil {
+// retrieve retrieves the channels owned by the specified user, along with
+// the ones having the shared identifiers, regardless of their owner.
+func (cr channelRepository) retrieve(ctx context.Context, owner string, shared []string, offset, limit uint64, name string, metadata map[string]interface{}, tags things.TagFilter, after string) (things.ChannelsPage, error) {
+	nq, name := getNameQuery(name)
+	mq, mdata, err := getMetadataQuery(metadata)
+	if err != nil {
+		return things.ChannelsPage{}, err
+	}
+	tq := getTagsQuery(tags)
+	aq, offset, err := getAfterQuery(after, offset)
+	if err != nil {
+		return things.ChannelsPage{}, err
+	}
+
+	oq := `owner = :owner`
+	if len(shared) > 0 {
+		oq = `(owner = :owner OR id = ANY(:shared))`
+	}
+
+	q := fmt.Sprintf(`SELECT id, owner, name, state, tags, metadata FROM channels
+	      WHERE %s %s %s %s %s ORDER BY id LIMIT :limit OFFSET :offset;`, oq, nq, mq, tq, aq)
+
+	params := map[string]interface{}{
+		"owner":    owner,
+		"shared":   pq.Array(shared),
+		"limit":    limit,
+		"offset":   offset,
+		"name":     name,
+		"metadata": mdata,
+		"tags":     pq.Array(tags.Tags),
+		"after":    after,
+	}
+	rows, err := cr.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return things.ChannelsPage{}, err
+	}
+	defer rows.Close()
+
+	items := []things.Channel{}
+	for rows.Next() {
+		var dbch dbChannel
+		if err := rows.StructScan(&dbch); err != nil {
 			return things.ChannelsPage{}, err
 		}
-	default:
-		if err := cr.db.Get(&total, q, owner, name); err != nil {
+		ch, err := toChannel(dbch)
+		if err != nil {
 			return things.ChannelsPage{}, err
 		}
+
+		items = append(items, ch)
+	}
+
+	cq := fmt.Sprintf(`SELECT COUNT(*) FROM channels WHERE %s %s %s %s;`, oq, nq, mq, tq)
+
+	total, err := total(ctx, cr.db, cq, params)
+	if err != nil {
+		return things.ChannelsPage{}, err
 	}
 
 	page := things.ChannelsPage{
 		Channels: items,
 		PageMetadata: things.PageMetadata{
-			Total:  total,
-			Offset: offset,
-			Limit:  limit,
+			Total:    total,
+			Offset:   offset,
+			Limit:    limit,
+			Metadata: metadata,
 		},
 	}
 
 	return page, nil
 }
 
-func (cr channelRepository) RetrieveByThing(owner, thing string, offset, limit uint64) (things.ChannelsPage, error) {
+func (cr channelRepository) RetrieveByThing(ctx context.Context, owner, thing string, offset, limit uint64) (things.ChannelsPage, error) {
 	// Verify if UUID format is valid to avoid internal Postgres error
 	if _, err := uuid.FromString(thing); err != nil {
 		return things.ChannelsPage{}, things.ErrNotFound
 	}
 
-	q := `SELECT id, name, metadata
+	q := `SELECT id, name, state, tags, metadata
 	      FROM channels ch
 	      INNER JOIN connections co
 		  ON ch.id = co.channel_id
@@ -196,7 +383,7 @@ func (cr channelRepository) RetrieveByThing(owner, thing string, offset, limit u
 		"offset": offset,
 	}
 
-	rows, err := cr.db.NamedQuery(q, params)
+	rows, err := cr.db.NamedQueryContext(ctx, q, params)
 	if err != nil {
 		return things.ChannelsPage{}, err
 	}
@@ -224,7 +411,7 @@ func (cr channelRepository) RetrieveByThing(owner, thing string, offset, limit u
 	     WHERE ch.owner = $1 AND co.thing_id = $2`
 
 	var total uint64
-	if err := cr.db.Get(&total, q, owner, thing); err != nil {
+	if err := cr.db.GetContext(ctx, &total, q, owner, thing); err != nil {
 		return things.ChannelsPage{}, err
 	}
 
@@ -238,99 +425,198 @@ func (cr channelRepository) RetrieveByThing(owner, thing string, offset, limit u
 	}, nil
 }
 
-func (cr channelRepository) Remove(owner, id string) error {
-	dbch := dbChannel{
-		ID:    id,
-		Owner: owner,
+// Transfer moves the channel history to the new owner, while the connections
+// kept and the subtopics follow the channel by the cascading update.
+func (cr channelRepository) Transfer(ctx context.Context, owner, id, newOwner string, keepConns bool) error {
+	if _, err := uuid.FromString(id); err != nil {
+		return things.ErrNotFound
 	}
-	q := `DELETE FROM channels WHERE id = :id AND owner = :owner`
-	cr.db.NamedExec(q, dbch)
-	return nil
-}
 
-func (cr channelRepository) Connect(owner, chanID, thingID string) error {
-	q := `INSERT INTO connections (channel_id, channel_owner, thing_id, thing_owner)
-	      VALUES (:channel, :owner, :thing, :owner);`
+	params := map[string]interface{}{
+		"id":        id,
+		"owner":     owner,
+		"new_owner": newOwner,
+	}
 
-	conn := dbConnection{
-		Channel: chanID,
-		Thing:   thingID,
-		Owner:   owner,
+	stmts := []string{
+		`DELETE FROM shares WHERE kind = 'channel' AND entity_id = :id AND owner = :owner;`,
+		`UPDATE history SET owner = :new_owner WHERE entity_id = :id AND owner = :owner;`,
+	}
+	if !keepConns {
+		stmts = append(stmts, `DELETE FROM connections WHERE channel_id = :id AND channel_owner = :owner;`)
 	}
 
-	if _, err := cr.db.NamedExec(q, conn); err != nil {
-		pqErr, ok := err.(*pq.Error)
+	return mfpostgres.Transact(ctx, cr.db, func(tx *sqlx.Tx) error {
+		for _, q := range stmts {
+			if _, err := tx.NamedExecContext(ctx, q, params); err != nil {
+				return err
+			}
+		}
 
-		if ok && errFK == pqErr.Code.Name() {
-			return things.ErrNotFound
+		q := `UPDATE channels SET owner = :new_owner WHERE id = :id AND owner = :owner;`
+		res, err := tx.NamedExecContext(ctx, q, params)
+		if err != nil {
+			pqErr, ok := err.(*pq.Error)
+			if ok {
+				switch pqErr.Code.Name() {
+				case errTruncation:
+					return things.ErrMalformedEntity
+				case errDuplicate:
+					return things.ErrConflict
+				}
+			}
+
+			return err
 		}
 
-		// connect is idempotent
-		if ok && errDuplicate == pqErr.Code.Name() {
-			return nil
+		cnt, err := res.RowsAffected()
+		if err != nil {
+			return err
 		}
 
-		return err
-	}
+		if cnt == 0 {
+			return things.ErrNotFound
+		}
+
+		return nil
+	})
+}
 
+func (cr channelRepository) Remove(ctx context.Context, owner, id string) error {
+	dbch := dbChannel{
+		ID:    id,
+		Owner: owner,
+	}
+	q := `DELETE FROM channels WHERE id = :id AND owner = :owner`
+	cr.db.NamedExecContext(ctx, q, dbch)
 	return nil
 }
 
-func (cr channelRepository) Disconnect(owner, chanID, thingID string) error {
+func (cr channelRepository) Connect(ctx context.Context, conns ...things.Connection) error {
+	// Rows of the deleted things are kept, so they are excluded explicitly.
+	// Connect is idempotent, so the existing connections are counted as
+	// connected, and their access is replaced.
+	q := `INSERT INTO connections (channel_id, channel_owner, thing_id, thing_owner, can_publish, can_subscribe)
+	      SELECT :channel, :owner, :thing, :owner, :can_publish, :can_subscribe
+	      WHERE EXISTS (SELECT 1 FROM channels WHERE id = :channel AND owner = :owner)
+	      AND EXISTS (SELECT 1 FROM things WHERE id = :thing AND owner = :owner AND state <> 'deleted')
+	      ON CONFLICT (channel_id, channel_owner, thing_id, thing_owner)
+	      DO UPDATE SET can_publish = EXCLUDED.can_publish, can_subscribe = EXCLUDED.can_subscribe;`
+
+	return cr.updateConnections(ctx, q, conns)
+}
+
+func (cr channelRepository) Disconnect(ctx context.Context, conns ...things.Connection) error {
 	q := `DELETE FROM connections
 	      WHERE channel_id = :channel AND channel_owner = :owner
 	      AND thing_id = :thing AND thing_owner = :owner`
 
-	conn := dbConnection{
-		Channel: chanID,
-		Thing:   thingID,
-		Owner:   owner,
-	}
+	return cr.updateConnections(ctx, q, conns)
+}
 
-	res, err := cr.db.NamedExec(q, conn)
-	if err != nil {
-		return err
-	}
+// updateConnections executes the query for each of the connections in a
+// single transaction, which is rolled back if any of them isn't affected.
+func (cr channelRepository) updateConnections(ctx context.Context, q string, conns []things.Connection) error {
+	return mfpostgres.Transact(ctx, cr.db, func(tx *sqlx.Tx) error {
+		failures := []things.ConnectionFailure{}
+		for _, c := range conns {
+			res, err := tx.NamedExecContext(ctx, q, toDBConnection(c))
+			if err != nil {
+				pqErr, ok := err.(*pq.Error)
+				if ok && errInvalid == pqErr.Code.Name() {
+					return things.ErrNotFound
+				}
+				return err
+			}
 
-	cnt, err := res.RowsAffected()
-	if err != nil {
-		return err
+			cnt, err := res.RowsAffected()
+			if err != nil {
+				return err
+			}
+
+			if cnt == 0 {
+				failures = append(failures, things.ConnectionFailure{
+					ChanID:  c.ChanID,
+					ThingID: c.ThingID,
+					Err:     things.ErrNotFound,
+				})
+			}
+		}
+
+		if len(failures) > 0 {
+			return &things.ConnectionError{Failures: failures}
+		}
+
+		return nil
+	})
+}
+
+func (cr channelRepository) RetrieveConnected(ctx context.Context, thingID string) ([]string, error) {
+	if _, err := uuid.FromString(thingID); err != nil {
+		return nil, things.ErrNotFound
 	}
 
-	if cnt == 0 {
-		return things.ErrNotFound
+	q := `SELECT channel_id FROM connections WHERE thing_id = $1 ORDER BY channel_id;`
+
+	ids := []string{}
+	if err := cr.db.SelectContext(ctx, &ids, q, thingID); err != nil {
+		return nil, err
 	}
 
-	return nil
+	return ids, nil
 }
 
-func (cr channelRepository) HasThing(chanID, key string) (string, error) {
-	var thingID string
+func (cr channelRepository) RetrieveRestricted(ctx context.Context, thingID string) (map[string]things.ConnectionAccess, error) {
+	if _, err := uuid.FromString(thingID); err != nil {
+		return nil, things.ErrNotFound
+	}
 
-	q := `SELECT id FROM things WHERE key = $1`
-	if err := cr.db.QueryRow(q, key).Scan(&thingID); err != nil {
-		return "", err
+	q := `SELECT channel_id AS channel, can_publish, can_subscribe FROM connections
+	      WHERE thing_id = $1 AND NOT (can_publish AND can_subscribe);`
 
+	rows, err := cr.db.QueryxContext(ctx, q, thingID)
+	if err != nil {
+		return nil, err
 	}
+	defer rows.Close()
 
-	q = `SELECT EXISTS (SELECT 1 FROM connections WHERE channel_id = $1 AND thing_id = $2);`
-	exists := false
-	if err := cr.db.QueryRow(q, chanID, thingID).Scan(&exists); err != nil {
-		return "", err
+	restricted := map[string]things.ConnectionAccess{}
+	for rows.Next() {
+		var conn dbConnection
+		if err := rows.StructScan(&conn); err != nil {
+			return nil, err
+		}
+		restricted[conn.Channel] = toConnectionAccess(conn)
 	}
 
-	if !exists {
-		return "", things.ErrUnauthorizedAccess
+	return restricted, nil
+}
+
+func (cr channelRepository) HasThing(ctx context.Context, chanID, key string) (things.Thing, things.ConnectionAccess, error) {
+	th, err := retrieveByKey(ctx, cr.db, key)
+	if err != nil {
+		return things.Thing{}, things.FullAccess, err
 	}
 
-	return thingID, nil
+	q := `SELECT can_publish, can_subscribe FROM connections WHERE channel_id = $1 AND thing_id = $2;`
+	var conn dbConnection
+	if err := cr.db.QueryRowContext(ctx, q, chanID, th.ID).Scan(&conn.CanPublish, &conn.CanSubscribe); err != nil {
+		if err == sql.ErrNoRows {
+			return things.Thing{}, things.FullAccess, things.ErrUnauthorizedAccess
+		}
+		return things.Thing{}, things.FullAccess, err
+	}
+
+	return th, toConnectionAccess(conn), nil
 }
 
 type dbChannel struct {
-	ID       string `db:"id"`
-	Owner    string `db:"owner"`
-	Name     string `db:"name"`
-	Metadata string `db:"metadata"`
+	ID       string         `db:"id"`
+	Owner    string         `db:"owner"`
+	Name     string         `db:"name"`
+	State    string         `db:"state"`
+	Tags     pq.StringArray `db:"tags"`
+	Metadata string         `db:"metadata"`
 }
 
 func toDBChannel(ch things.Channel) (dbChannel, error) {
@@ -339,10 +625,17 @@ func toDBChannel(ch things.Channel) (dbChannel, error) {
 		return dbChannel{}, err
 	}
 
+	state := ch.State
+	if state == "" {
+		state = things.Active
+	}
+
 	return dbChannel{
 		ID:       ch.ID,
 		Owner:    ch.Owner,
 		Name:     ch.Name,
+		State:    string(state),
+		Tags:     toDBTags(ch.Tags),
 		Metadata: string(data),
 	}, nil
 }
@@ -357,12 +650,37 @@ func toChannel(ch dbChannel) (things.Channel, error) {
 		ID:       ch.ID,
 		Owner:    ch.Owner,
 		Name:     ch.Name,
+		State:    things.State(ch.State),
+		Tags:     toTags(ch.Tags),
 		Metadata: metadata,
 	}, nil
 }
 
 type dbConnection struct {
-	Channel string `db:"channel"`
-	Thing   string `db:"thing"`
-	Owner   string `db:"owner"`
+	Channel      string `db:"channel"`
+	Thing        string `db:"thing"`
+	Owner        string `db:"owner"`
+	CanPublish   bool   `db:"can_publish"`
+	CanSubscribe bool   `db:"can_subscribe"`
+}
+
+func toDBConnection(conn things.Connection) dbConnection {
+	return dbConnection{
+		Channel:      conn.ChanID,
+		Thing:        conn.ThingID,
+		Owner:        conn.Owner,
+		CanPublish:   conn.Access != things.SubscribeAccess,
+		CanSubscribe: conn.Access != things.PublishAccess,
+	}
+}
+
+func toConnectionAccess(conn dbConnection) things.ConnectionAccess {
+	switch {
+	case conn.CanPublish && !conn.CanSubscribe:
+		return things.PublishAccess
+	case conn.CanSubscribe && !conn.CanPublish:
+		return things.SubscribeAccess
+	default:
+		return things.FullAccess
+	}
 }
@@ -8,6 +8,7 @@
 package postgres_test
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -61,7 +62,7 @@ func TestChannelSave(t *testing.T) {
 	}
 
 	for _, tc := range cases {
-		_, err := channelRepo.Save(tc.channel)
+		_, err := channelRepo.Save(context.Background(), tc.channel)
 		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
 	}
 }
@@ -77,7 +78,7 @@ func TestChannelUpdate(t *testing.T) {
 		Owner: email,
 	}
 
-	id, _ := chanRepo.Save(c)
+	id, _ := chanRepo.Save(context.Background(), c)
 	c.ID = id
 
 	nonexistentChanID, err := uuid.New().ID()
@@ -120,11 +121,76 @@ func TestChannelUpdate(t *testing.T) {
 	}
 
 	for _, tc := range cases {
-		err := chanRepo.Update(tc.channel)
+		err := chanRepo.Update(context.Background(), tc.channel)
 		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
 	}
 }
 
+func TestChannelPatch(t *testing.T) {
+	email := "channel-patch@example.com"
+	repo := postgres.NewChannelRepository(db)
+
+	id, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	e := things.Channel{
+		ID:    id,
+		Owner: email,
+	}
+	id, err = repo.Save(context.Background(), e)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	nonexistentID, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		owner string
+		id    string
+		patch map[string]interface{}
+		name  string
+		err   error
+	}{
+		{
+			desc:  "patch existing channel",
+			owner: email,
+			id:    id,
+			patch: map[string]interface{}{"name": "patched", "metadata": map[string]interface{}{"a": "b"}},
+			name:  "patched",
+			err:   nil,
+		},
+		{
+			desc:  "patch existing channel with read-only field",
+			owner: email,
+			id:    id,
+			patch: map[string]interface{}{"owner": wrongValue},
+			name:  "",
+			err:   things.ErrMalformedEntity,
+		},
+		{
+			desc:  "patch non-existing channel",
+			owner: email,
+			id:    nonexistentID,
+			patch: map[string]interface{}{"name": "patched"},
+			name:  "",
+			err:   things.ErrNotFound,
+		},
+		{
+			desc:  "patch existing channel with wrong owner",
+			owner: wrongValue,
+			id:    id,
+			patch: map[string]interface{}{"name": "patched"},
+			name:  "",
+			err:   things.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		res, err := repo.Patch(context.Background(), tc.owner, tc.id, tc.patch)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.name, res.Name, fmt.Sprintf("%s: expected name %s got %s\n", tc.desc, tc.name, res.Name))
+	}
+}
+
 func TestSingleChannelRetrieval(t *testing.T) {
 	email := "channel-single-retrieval@example.com"
 	chanRepo := postgres.NewChannelRepository(db)
@@ -141,7 +207,7 @@ func TestSingleChannelRetrieval(t *testing.T) {
 		Owner: email,
 		Key:   thkey,
 	}
-	th.ID, _ = thingRepo.Save(th)
+	th.ID, _ = thingRepo.Save(context.Background(), th)
 
 	chid, err := uuid.New().ID()
 	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
@@ -150,8 +216,8 @@ func TestSingleChannelRetrieval(t *testing.T) {
 		Owner: email,
 	}
 
-	c.ID, _ = chanRepo.Save(c)
-	chanRepo.Connect(email, c.ID, th.ID)
+	c.ID, _ = chanRepo.Save(context.Background(), c)
+	chanRepo.Connect(context.Background(), things.Connection{ChanID: c.ID, ThingID: th.ID, Owner: email})
 
 	nonexistentChanID, err := uuid.New().ID()
 	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
@@ -184,7 +250,7 @@ func TestSingleChannelRetrieval(t *testing.T) {
 	}
 
 	for desc, tc := range cases {
-		_, err := chanRepo.RetrieveByID(tc.owner, tc.ID)
+		_, err := chanRepo.RetrieveByID(context.Background(), tc.owner, tc.ID)
 		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
 	}
 }
@@ -193,6 +259,7 @@ func TestMultiChannelRetrieval(t *testing.T) {
 	email := "channel-multi-retrieval@example.com"
 	chanRepo := postgres.NewChannelRepository(db)
 	channelName := "channel_name"
+	metadata := map[string]interface{}{"protocol": "mqtt", "region": "eu"}
 
 	n := uint64(10)
 	for i := uint64(0); i < n; i++ {
@@ -206,17 +273,31 @@ func TestMultiChannelRetrieval(t *testing.T) {
 
 		if i == 0 {
 			c.Name = channelName
+			c.Tags = []string{"plant-3", "v2"}
+		}
+
+		if i == 1 {
+			c.Tags = []string{"plant-3"}
+		}
+
+		// Create first two channels with metadata
+		if i < 2 {
+			c.Metadata = metadata
 		}
 
-		chanRepo.Save(c)
+		chanRepo.Save(context.Background(), c)
 	}
 
 	cases := map[string]struct {
-		owner  string
-		offset uint64
-		limit  uint64
-		name   string
-		size   uint64
+		owner    string
+		offset   uint64
+		limit    uint64
+		name     string
+		metadata map[string]interface{}
+		tags     things.TagFilter
+		after    string
+		size     uint64
+		total    uint64
 	}{
 		"retrieve all channels with existing owner": {
 			owner:  email,
@@ -250,16 +331,105 @@ func TestMultiChannelRetrieval(t *testing.T) {
 			name:   "wrong",
 			size:   0,
 		},
+		"retrieve channels with matching metadata": {
+			owner:    email,
+			offset:   0,
+			limit:    n,
+			metadata: map[string]interface{}{"protocol": "mqtt"},
+			size:     2,
+			total:    2,
+		},
+		"retrieve channels with non-matching metadata": {
+			owner:    email,
+			offset:   0,
+			limit:    n,
+			metadata: map[string]interface{}{"protocol": "coap"},
+			size:     0,
+			total:    0,
+		},
+		"retrieve channels holding all the tags": {
+			owner:  email,
+			offset: 0,
+			limit:  n,
+			tags:   things.TagFilter{Tags: []string{"plant-3", "v2"}},
+			size:   1,
+		},
+		"retrieve channels holding any of the tags": {
+			owner:  email,
+			offset: 0,
+			limit:  n,
+			tags:   things.TagFilter{Tags: []string{"plant-3", "v3"}, Any: true},
+			size:   2,
+		},
+		"retrieve channels past the lowest identifier": {
+			owner: email,
+			limit: n,
+			after: "00000000-0000-0000-0000-000000000000",
+			size:  n,
+		},
+		"retrieve channels past the highest identifier": {
+			owner: email,
+			limit: n,
+			after: "ffffffff-ffff-ffff-ffff-ffffffffffff",
+			size:  0,
+		},
 	}
 
 	for desc, tc := range cases {
-		page, err := chanRepo.RetrieveAll(tc.owner, tc.offset, tc.limit, tc.name)
+		page, err := chanRepo.RetrieveAll(context.Background(), tc.owner, tc.offset, tc.limit, tc.name, tc.metadata, tc.tags, tc.after)
 		size := uint64(len(page.Channels))
 		assert.Equal(t, tc.size, size, fmt.Sprintf("%s: expected %d got %d\n", desc, tc.size, size))
+		if tc.metadata != nil {
+			assert.Equal(t, tc.total, page.Total, fmt.Sprintf("%s: expected total %d got %d\n", desc, tc.total, page.Total))
+		}
 		assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %d\n", desc, err))
 	}
 }
 
+func TestChannelCountAccessible(t *testing.T) {
+	email := "channel-count@example.com"
+	chanRepo := postgres.NewChannelRepository(db)
+	metadata := map[string]interface{}{"region": "eu"}
+
+	n := uint64(10)
+	for i := uint64(0); i < n; i++ {
+		chid, err := uuid.New().ID()
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+		c := things.Channel{
+			ID:    chid,
+			Owner: email,
+		}
+		if i < 2 {
+			c.Metadata = metadata
+		}
+		if i < 3 {
+			c.Tags = []string{"plant-3"}
+		}
+
+		_, err = chanRepo.Save(context.Background(), c)
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	}
+
+	cases := map[string]struct {
+		owner    string
+		metadata map[string]interface{}
+		tags     things.TagFilter
+		total    uint64
+	}{
+		"count all channels":                  {email, nil, things.TagFilter{}, n},
+		"count channels with metadata":        {email, metadata, things.TagFilter{}, 2},
+		"count channels with tags":            {email, nil, things.TagFilter{Tags: []string{"plant-3"}}, 3},
+		"count channels of non-existing user": {wrongValue, nil, things.TagFilter{}, 0},
+	}
+
+	for desc, tc := range cases {
+		total, err := chanRepo.CountAccessible(context.Background(), tc.owner, nil, "", tc.metadata, tc.tags)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", desc, err))
+		assert.Equal(t, tc.total, total, fmt.Sprintf("%s: expected %d got %d\n", desc, tc.total, total))
+	}
+}
+
 func TestMultiChannelRetrievalByThing(t *testing.T) {
 	email := "channel-multi-retrieval-by-thing@example.com"
 	idp := uuid.New()
@@ -269,7 +439,7 @@ func TestMultiChannelRetrievalByThing(t *testing.T) {
 	thid, err := idp.ID()
 	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
 
-	tid, err := thingRepo.Save(things.Thing{
+	tid, err := thingRepo.Save(context.Background(), things.Thing{
 		ID:    thid,
 		Owner: email,
 	})
@@ -282,9 +452,9 @@ func TestMultiChannelRetrievalByThing(t *testing.T) {
 			ID:    chid,
 			Owner: email,
 		}
-		cid, err := chanRepo.Save(c)
+		cid, err := chanRepo.Save(context.Background(), c)
 		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
-		err = chanRepo.Connect(email, cid, tid)
+		err = chanRepo.Connect(context.Background(), things.Connection{ChanID: cid, ThingID: tid, Owner: email})
 		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 	}
 
@@ -338,20 +508,149 @@ func TestMultiChannelRetrievalByThing(t *testing.T) {
 	}
 
 	for desc, tc := range cases {
-		page, err := chanRepo.RetrieveByThing(tc.owner, tc.thing, tc.offset, tc.limit)
+		page, err := chanRepo.RetrieveByThing(context.Background(), tc.owner, tc.thing, tc.offset, tc.limit)
 		size := uint64(len(page.Channels))
 		assert.Equal(t, tc.size, size, fmt.Sprintf("%s: expected %d got %d\n", desc, tc.size, size))
 		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected no error got %d\n", desc, err))
 	}
 }
 
+func TestConnectedChannelsRetrieval(t *testing.T) {
+	email := "channel-connected-retrieval@example.com"
+	idp := uuid.New()
+	chanRepo := postgres.NewChannelRepository(db)
+	thingRepo := postgres.NewThingRepository(db)
+
+	thid, err := idp.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	tid, err := thingRepo.Save(context.Background(), things.Thing{ID: thid, Owner: email})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	n := 3
+	for i := 0; i < n; i++ {
+		chid, err := idp.ID()
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		cid, err := chanRepo.Save(context.Background(), things.Channel{ID: chid, Owner: email})
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		err = chanRepo.Connect(context.Background(), things.Connection{ChanID: cid, ThingID: tid, Owner: email})
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	unconnectedID, err := idp.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := map[string]struct {
+		thing string
+		size  int
+		err   error
+	}{
+		"retrieve channels of connected thing": {
+			thing: tid,
+			size:  n,
+			err:   nil,
+		},
+		"retrieve channels of unconnected thing": {
+			thing: unconnectedID,
+			size:  0,
+			err:   nil,
+		},
+		"retrieve channels of thing with malformed UUID": {
+			thing: wrongValue,
+			size:  0,
+			err:   things.ErrNotFound,
+		},
+	}
+
+	for desc, tc := range cases {
+		ids, err := chanRepo.RetrieveConnected(context.Background(), tc.thing)
+		assert.Equal(t, tc.size, len(ids), fmt.Sprintf("%s: expected %d got %d\n", desc, tc.size, len(ids)))
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
+func TestChannelTransfer(t *testing.T) {
+	email := "channel-transfer@example.com"
+	newOwner := "channel-transfer-new@example.com"
+	idp := uuid.New()
+	chanRepo := postgres.NewChannelRepository(db)
+	thingRepo := postgres.NewThingRepository(db)
+
+	thid, err := idp.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	thkey, err := idp.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	thingID, err := thingRepo.Save(context.Background(), things.Thing{ID: thid, Owner: email, Key: thkey})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	ids := []string{}
+	for i := 0; i < 2; i++ {
+		chid, err := idp.ID()
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		id, err := chanRepo.Save(context.Background(), things.Channel{ID: chid, Owner: email})
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		err = chanRepo.Connect(context.Background(), things.Connection{ChanID: id, ThingID: thingID, Owner: email})
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		ids = append(ids, id)
+	}
+	dropped, kept := ids[0], ids[1]
+
+	cases := []struct {
+		desc      string
+		owner     string
+		id        string
+		keepConns bool
+		err       error
+	}{
+		{
+			desc:  "transfer channel with malformed ID",
+			owner: email,
+			id:    wrongValue,
+			err:   things.ErrNotFound,
+		},
+		{
+			desc:  "transfer channel with wrong owner",
+			owner: wrongValue,
+			id:    dropped,
+			err:   things.ErrNotFound,
+		},
+		{
+			desc:  "transfer channel dropping its connections",
+			owner: email,
+			id:    dropped,
+			err:   nil,
+		},
+		{
+			desc:      "transfer channel keeping its connections",
+			owner:     email,
+			id:        kept,
+			keepConns: true,
+			err:       nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := chanRepo.Transfer(context.Background(), tc.owner, tc.id, newOwner, tc.keepConns)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err != nil {
+			continue
+		}
+
+		_, err = chanRepo.RetrieveByID(context.Background(), newOwner, tc.id)
+		assert.Nil(t, err, fmt.Sprintf("%s: retrieve transferred channel: unexpected error: %s\n", tc.desc, err))
+	}
+
+	conns, err := chanRepo.RetrieveConnected(context.Background(), thingID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, []string{kept}, conns, fmt.Sprintf("expected connected channels %v got %v\n", []string{kept}, conns))
+}
+
 func TestChannelRemoval(t *testing.T) {
 	email := "channel-removal@example.com"
 	chanRepo := postgres.NewChannelRepository(db)
 
 	chid, err := uuid.New().ID()
 	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
-	chanID, _ := chanRepo.Save(things.Channel{
+	chanID, _ := chanRepo.Save(context.Background(), things.Channel{
 		ID:    chid,
 		Owner: email,
 	})
@@ -359,14 +658,148 @@ func TestChannelRemoval(t *testing.T) {
 	// show that the removal works the same for both existing and non-existing
 	// (removed) channel
 	for i := 0; i < 2; i++ {
-		err := chanRepo.Remove(email, chanID)
+		err := chanRepo.Remove(context.Background(), email, chanID)
 		require.Nil(t, err, fmt.Sprintf("#%d: failed to remove channel due to: %s", i, err))
 
-		_, err = chanRepo.RetrieveByID(email, chanID)
+		_, err = chanRepo.RetrieveByID(context.Background(), email, chanID)
 		require.Equal(t, things.ErrNotFound, err, fmt.Sprintf("#%d: expected %s got %s", i, things.ErrNotFound, err))
 	}
 }
 
+func TestChannelUpdateState(t *testing.T) {
+	email := "channel-update-state@example.com"
+	chanRepo := postgres.NewChannelRepository(db)
+
+	chid, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	chanID, err := chanRepo.Save(context.Background(), things.Channel{
+		ID:    chid,
+		Owner: email,
+	})
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	nonexistentChanID, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		owner string
+		id    string
+		state things.State
+		err   error
+	}{
+		{
+			desc:  "archive existing channel",
+			owner: email,
+			id:    chanID,
+			state: things.Archived,
+			err:   nil,
+		},
+		{
+			desc:  "unarchive existing channel",
+			owner: email,
+			id:    chanID,
+			state: things.Active,
+			err:   nil,
+		},
+		{
+			desc:  "archive channel with wrong owner",
+			owner: wrongValue,
+			id:    chanID,
+			state: things.Archived,
+			err:   things.ErrNotFound,
+		},
+		{
+			desc:  "archive non-existing channel",
+			owner: email,
+			id:    nonexistentChanID,
+			state: things.Archived,
+			err:   things.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := chanRepo.UpdateState(context.Background(), tc.owner, tc.id, tc.state)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err != nil {
+			continue
+		}
+
+		state, err := chanRepo.RetrieveState(context.Background(), tc.id)
+		require.Nil(t, err, fmt.Sprintf("%s: got unexpected error: %s", tc.desc, err))
+		assert.Equal(t, tc.state, state, fmt.Sprintf("%s: expected state %s got %s\n", tc.desc, tc.state, state))
+	}
+}
+
+func TestChannelArchive(t *testing.T) {
+	email := "channel-archive@example.com"
+	chanRepo := postgres.NewChannelRepository(db)
+
+	channels := []things.Channel{
+		{Metadata: map[string]interface{}{"project": "greenhouse"}},
+		{Metadata: map[string]interface{}{"project": "greenhouse", "zone": "north"}, Tags: []string{"edge"}},
+		{Metadata: map[string]interface{}{"project": "warehouse"}, Tags: []string{"edge", "v2"}},
+		{Metadata: map[string]interface{}{"project": "office"}, Tags: []string{"v2"}},
+	}
+	for _, ch := range channels {
+		chid, err := uuid.New().ID()
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		ch.ID = chid
+		ch.Owner = email
+		_, err = chanRepo.Save(context.Background(), ch)
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	}
+
+	cases := []struct {
+		desc     string
+		owner    string
+		selector map[string]interface{}
+		tags     things.TagFilter
+		size     int
+	}{
+		{
+			desc:     "archive channels with wrong owner",
+			owner:    wrongValue,
+			selector: map[string]interface{}{"project": "greenhouse"},
+			size:     0,
+		},
+		{
+			desc:     "archive channels matching selector",
+			owner:    email,
+			selector: map[string]interface{}{"project": "greenhouse"},
+			size:     2,
+		},
+		{
+			desc:     "archive already archived channels",
+			owner:    email,
+			selector: map[string]interface{}{"project": "greenhouse"},
+			size:     0,
+		},
+		{
+			desc:     "archive channels holding all the tags",
+			owner:    email,
+			selector: map[string]interface{}{},
+			tags:     things.TagFilter{Tags: []string{"edge", "v2"}},
+			size:     1,
+		},
+		{
+			desc:     "archive all the remaining channels",
+			owner:    email,
+			selector: map[string]interface{}{},
+			size:     1,
+		},
+	}
+
+	for _, tc := range cases {
+		archived, err := chanRepo.Archive(context.Background(), tc.owner, tc.selector, tc.tags)
+		assert.Nil(t, err, fmt.Sprintf("%s: got unexpected error: %s", tc.desc, err))
+		assert.Len(t, archived, tc.size, fmt.Sprintf("%s: expected %d archived channels got %d\n", tc.desc, tc.size, len(archived)))
+		for _, ch := range archived {
+			assert.Equal(t, things.Archived, ch.State, fmt.Sprintf("%s: expected state %s got %s\n", tc.desc, things.Archived, ch.State))
+		}
+	}
+}
+
 func TestConnect(t *testing.T) {
 	email := "channel-connect@example.com"
 	thingRepo := postgres.NewThingRepository(db)
@@ -382,13 +815,13 @@ func TestConnect(t *testing.T) {
 		Key:      thkey,
 		Metadata: map[string]interface{}{},
 	}
-	thingID, _ := thingRepo.Save(thing)
+	thingID, _ := thingRepo.Save(context.Background(), thing)
 
 	chanRepo := postgres.NewChannelRepository(db)
 
 	chid, err := uuid.New().ID()
 	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
-	chanID, _ := chanRepo.Save(things.Channel{
+	chanID, _ := chanRepo.Save(context.Background(), things.Channel{
 		ID:    chid,
 		Owner: email,
 	})
@@ -425,28 +858,48 @@ func TestConnect(t *testing.T) {
 			owner:   wrongValue,
 			chanID:  chanID,
 			thingID: thingID,
-			err:     things.ErrNotFound,
+			err:     connectionError(chanID, thingID),
 		},
 		{
 			desc:    "connect non-existing channel",
 			owner:   email,
 			chanID:  nonexistentChanID,
 			thingID: thingID,
-			err:     things.ErrNotFound,
+			err:     connectionError(nonexistentChanID, thingID),
 		},
 		{
 			desc:    "connect non-existing thing",
 			owner:   email,
 			chanID:  chanID,
 			thingID: nonexistentThingID,
-			err:     things.ErrNotFound,
+			err:     connectionError(chanID, nonexistentThingID),
 		},
 	}
 
 	for _, tc := range cases {
-		err := chanRepo.Connect(tc.owner, tc.chanID, tc.thingID)
+		err := chanRepo.Connect(context.Background(), things.Connection{ChanID: tc.chanID, ThingID: tc.thingID, Owner: tc.owner})
 		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
 	}
+
+	// Connections are created all at once, so the failed one prevents the
+	// rest of them.
+	thid2, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	thing.ID = thid2
+	thing.Key = thid2
+	thingID2, err := thingRepo.Save(context.Background(), thing)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	err = chanRepo.Connect(context.Background(),
+		things.Connection{ChanID: chanID, ThingID: thingID2, Owner: email},
+		things.Connection{ChanID: chanID, ThingID: nonexistentThingID, Owner: email},
+	)
+	expected := connectionError(chanID, nonexistentThingID)
+	assert.Equal(t, expected, err, fmt.Sprintf("connect multiple things: expected %s got %s\n", expected, err))
+
+	ids, err := chanRepo.RetrieveConnected(context.Background(), thingID2)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.Empty(t, ids, "expected thing not to be connected")
 }
 
 func TestDisconnect(t *testing.T) {
@@ -464,16 +917,16 @@ func TestDisconnect(t *testing.T) {
 		Key:      thkey,
 		Metadata: map[string]interface{}{},
 	}
-	thingID, _ := thingRepo.Save(thing)
+	thingID, _ := thingRepo.Save(context.Background(), thing)
 
 	chanRepo := postgres.NewChannelRepository(db)
 	chid, err := uuid.New().ID()
 	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
-	chanID, _ := chanRepo.Save(things.Channel{
+	chanID, _ := chanRepo.Save(context.Background(), things.Channel{
 		ID:    chid,
 		Owner: email,
 	})
-	chanRepo.Connect(email, chanID, thingID)
+	chanRepo.Connect(context.Background(), things.Connection{ChanID: chanID, ThingID: thingID, Owner: email})
 
 	nonexistentThingID, err := uuid.New().ID()
 	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
@@ -500,33 +953,33 @@ func TestDisconnect(t *testing.T) {
 			owner:   email,
 			chanID:  chanID,
 			thingID: thingID,
-			err:     things.ErrNotFound,
+			err:     connectionError(chanID, thingID),
 		},
 		{
 			desc:    "disconnect non-existing user",
 			owner:   wrongValue,
 			chanID:  chanID,
 			thingID: thingID,
-			err:     things.ErrNotFound,
+			err:     connectionError(chanID, thingID),
 		},
 		{
 			desc:    "disconnect non-existing channel",
 			owner:   email,
 			chanID:  nonexistentChanID,
 			thingID: thingID,
-			err:     things.ErrNotFound,
+			err:     connectionError(nonexistentChanID, thingID),
 		},
 		{
 			desc:    "disconnect non-existing thing",
 			owner:   email,
 			chanID:  chanID,
 			thingID: nonexistentThingID,
-			err:     things.ErrNotFound,
+			err:     connectionError(chanID, nonexistentThingID),
 		},
 	}
 
 	for _, tc := range cases {
-		err := chanRepo.Disconnect(tc.owner, tc.chanID, tc.thingID)
+		err := chanRepo.Disconnect(context.Background(), things.Connection{ChanID: tc.chanID, ThingID: tc.thingID, Owner: tc.owner})
 		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
 	}
 }
@@ -545,16 +998,24 @@ func TestHasThing(t *testing.T) {
 		Owner: email,
 		Key:   thkey,
 	}
-	thingID, _ := thingRepo.Save(thing)
+	thingID, _ := thingRepo.Save(context.Background(), thing)
 
 	chanRepo := postgres.NewChannelRepository(db)
 	chid, err := uuid.New().ID()
 	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
-	chanID, _ := chanRepo.Save(things.Channel{
+	chanID, _ := chanRepo.Save(context.Background(), things.Channel{
 		ID:    chid,
 		Owner: email,
 	})
-	chanRepo.Connect(email, chanID, thingID)
+	chanRepo.Connect(context.Background(), things.Connection{ChanID: chanID, ThingID: thingID, Owner: email})
+
+	cmdID, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	cmdID, _ = chanRepo.Save(context.Background(), things.Channel{
+		ID:    cmdID,
+		Owner: email,
+	})
+	chanRepo.Connect(context.Background(), things.Connection{ChanID: cmdID, ThingID: thingID, Owner: email, Access: things.SubscribeAccess})
 
 	nonexistentChanID, err := uuid.New().ID()
 	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
@@ -563,11 +1024,19 @@ func TestHasThing(t *testing.T) {
 		chanID    string
 		key       string
 		hasAccess bool
+		access    things.ConnectionAccess
 	}{
 		"access check for thing that has access": {
 			chanID:    chanID,
 			key:       thing.Key,
 			hasAccess: true,
+			access:    things.FullAccess,
+		},
+		"access check for thing that has subscribe-only access": {
+			chanID:    cmdID,
+			key:       thing.Key,
+			hasAccess: true,
+			access:    things.SubscribeAccess,
 		},
 		"access check for thing without access": {
 			chanID:    chanID,
@@ -582,8 +1051,49 @@ func TestHasThing(t *testing.T) {
 	}
 
 	for desc, tc := range cases {
-		_, err := chanRepo.HasThing(tc.chanID, tc.key)
+		_, access, err := chanRepo.HasThing(context.Background(), tc.chanID, tc.key)
 		hasAccess := err == nil
 		assert.Equal(t, tc.hasAccess, hasAccess, fmt.Sprintf("%s: expected %t got %t\n", desc, tc.hasAccess, hasAccess))
+		assert.Equal(t, tc.access, access, fmt.Sprintf("%s: expected access %s got %s\n", desc, tc.access, access))
+	}
+}
+
+func TestRetrieveRestricted(t *testing.T) {
+	email := "channel-restricted@example.com"
+	thingRepo := postgres.NewThingRepository(db)
+	chanRepo := postgres.NewChannelRepository(db)
+
+	thid, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	thkey, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	thingID, err := thingRepo.Save(context.Background(), things.Thing{ID: thid, Owner: email, Key: thkey})
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	accesses := []things.ConnectionAccess{things.FullAccess, things.PublishAccess, things.SubscribeAccess}
+	expected := map[string]things.ConnectionAccess{}
+	for _, access := range accesses {
+		chid, err := uuid.New().ID()
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		chanID, err := chanRepo.Save(context.Background(), things.Channel{ID: chid, Owner: email})
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+		err = chanRepo.Connect(context.Background(), things.Connection{ChanID: chanID, ThingID: thingID, Owner: email, Access: access})
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		if access != things.FullAccess {
+			expected[chanID] = access
+		}
+	}
+
+	restricted, err := chanRepo.RetrieveRestricted(context.Background(), thingID)
+	assert.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.Equal(t, expected, restricted, fmt.Sprintf("expected %v got %v", expected, restricted))
+}
+
+func connectionError(chanID, thingID string) error {
+	return &things.ConnectionError{
+		Failures: []things.ConnectionFailure{
+			{ChanID: chanID, ThingID: thingID, Err: things.ErrNotFound},
+		},
 	}
 }
@@ -0,0 +1,163 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/mainflux/mainflux/things"
+)
+
+var _ things.ChannelTokenRepository = (*channelTokenRepository)(nil)
+
+type channelTokenRepository struct {
+	db *sqlx.DB
+}
+
+// NewChannelTokenRepository instantiates a PostgreSQL implementation of
+// channel access tokens repository.
+func NewChannelTokenRepository(db *sqlx.DB) things.ChannelTokenRepository {
+	return &channelTokenRepository{
+		db: db,
+	}
+}
+
+func (ctr channelTokenRepository) Save(ctx context.Context, ct things.ChannelToken) (string, error) {
+	q := `INSERT INTO channel_tokens (id, channel_id, owner, token, scope, issued_at, expires_at)
+	      VALUES (:id, :channel_id, :owner, :token, :scope, :issued_at, :expires_at);`
+
+	if _, err := ctr.db.NamedExecContext(ctx, q, toDBChannelToken(ct)); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok {
+			switch pqErr.Code.Name() {
+			case errInvalid, errTruncation:
+				return "", things.ErrMalformedEntity
+			case errDuplicate:
+				return "", things.ErrConflict
+			case errFK:
+				return "", things.ErrNotFound
+			}
+		}
+
+		return "", err
+	}
+
+	return ct.ID, nil
+}
+
+func (ctr channelTokenRepository) RetrieveAll(ctx context.Context, owner, chanID string) ([]things.ChannelToken, error) {
+	// Verify if UUID format is valid to avoid internal Postgres error
+	if _, err := uuid.FromString(chanID); err != nil {
+		return nil, things.ErrNotFound
+	}
+
+	q := `SELECT id, channel_id, owner, token, scope, issued_at, expires_at FROM channel_tokens
+	      WHERE channel_id = $1 AND owner = $2 AND expires_at > $3 ORDER BY issued_at, id;`
+
+	rows, err := ctr.db.QueryxContext(ctx, q, chanID, owner, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []things.ChannelToken{}
+	for rows.Next() {
+		var dbct dbChannelToken
+		if err := rows.StructScan(&dbct); err != nil {
+			return nil, err
+		}
+
+		items = append(items, toChannelToken(dbct))
+	}
+
+	return items, nil
+}
+
+func (ctr channelTokenRepository) RetrieveByToken(ctx context.Context, chanID, token string) (things.ChannelToken, error) {
+	if _, err := uuid.FromString(chanID); err != nil {
+		return things.ChannelToken{}, things.ErrNotFound
+	}
+
+	q := `SELECT id, channel_id, owner, token, scope, issued_at, expires_at FROM channel_tokens
+	      WHERE token = $1 AND channel_id = $2 AND expires_at > $3;`
+
+	var dbct dbChannelToken
+	if err := ctr.db.QueryRowxContext(ctx, q, token, chanID, time.Now().UTC()).StructScan(&dbct); err != nil {
+		if err == sql.ErrNoRows {
+			return things.ChannelToken{}, things.ErrNotFound
+		}
+		return things.ChannelToken{}, err
+	}
+
+	return toChannelToken(dbct), nil
+}
+
+func (ctr channelTokenRepository) Remove(ctx context.Context, owner, chanID, id string) error {
+	for _, v := range []string{chanID, id} {
+		if _, err := uuid.FromString(v); err != nil {
+			return things.ErrNotFound
+		}
+	}
+
+	q := `DELETE FROM channel_tokens WHERE id = $1 AND channel_id = $2 AND owner = $3;`
+
+	res, err := ctr.db.ExecContext(ctx, q, id, chanID, owner)
+	if err != nil {
+		return err
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if cnt == 0 {
+		return things.ErrNotFound
+	}
+
+	return nil
+}
+
+type dbChannelToken struct {
+	ID        string    `db:"id"`
+	ChannelID string    `db:"channel_id"`
+	Owner     string    `db:"owner"`
+	Token     string    `db:"token"`
+	Scope     string    `db:"scope"`
+	IssuedAt  time.Time `db:"issued_at"`
+	ExpiresAt time.Time `db:"expires_at"`
+}
+
+func toDBChannelToken(ct things.ChannelToken) dbChannelToken {
+	return dbChannelToken{
+		ID:        ct.ID,
+		ChannelID: ct.ChannelID,
+		Owner:     ct.Owner,
+		Token:     ct.Token,
+		Scope:     string(ct.Scope),
+		IssuedAt:  ct.IssuedAt,
+		ExpiresAt: ct.ExpiresAt,
+	}
+}
+
+func toChannelToken(dbct dbChannelToken) things.ChannelToken {
+	return things.ChannelToken{
+		ID:        dbct.ID,
+		ChannelID: dbct.ChannelID,
+		Owner:     dbct.Owner,
+		Token:     dbct.Token,
+		Scope:     things.KeyScope(dbct.Scope),
+		IssuedAt:  dbct.IssuedAt.UTC(),
+		ExpiresAt: dbct.ExpiresAt.UTC(),
+	}
+}
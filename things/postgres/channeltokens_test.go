@@ -0,0 +1,213 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/things"
+	"github.com/mainflux/mainflux/things/postgres"
+	"github.com/mainflux/mainflux/things/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChannelTokenSave(t *testing.T) {
+	email := "channel-token-save@example.com"
+	chanRepo := postgres.NewChannelRepository(db)
+	chanTokenRepo := postgres.NewChannelTokenRepository(db)
+
+	chanID := saveChannel(t, chanRepo, email)
+	missing, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	ct := newChannelToken(t, chanID, email, things.ScopePublish, time.Hour)
+	conflicting := newChannelToken(t, chanID, email, things.ScopeSubscribe, time.Hour)
+	conflicting.Token = ct.Token
+
+	cases := []struct {
+		desc  string
+		token things.ChannelToken
+		err   error
+	}{
+		{
+			desc:  "save channel token",
+			token: ct,
+			err:   nil,
+		},
+		{
+			desc:  "save channel token with conflicting token",
+			token: conflicting,
+			err:   things.ErrConflict,
+		},
+		{
+			desc:  "save token of non-existing channel",
+			token: newChannelToken(t, missing, email, things.ScopePublish, time.Hour),
+			err:   things.ErrNotFound,
+		},
+		{
+			desc:  "save token of channel owned by other user",
+			token: newChannelToken(t, chanID, "other@example.com", things.ScopePublish, time.Hour),
+			err:   things.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := chanTokenRepo.Save(context.Background(), tc.token)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestChannelTokenRetrieveAll(t *testing.T) {
+	email := "channel-token-retrieve-all@example.com"
+	chanRepo := postgres.NewChannelRepository(db)
+	chanTokenRepo := postgres.NewChannelTokenRepository(db)
+
+	chanID := saveChannel(t, chanRepo, email)
+	for _, ttl := range []time.Duration{time.Hour, 2 * time.Hour, -time.Hour} {
+		_, err := chanTokenRepo.Save(context.Background(), newChannelToken(t, chanID, email, things.ScopeAdmin, ttl))
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	}
+
+	cases := map[string]struct {
+		owner  string
+		chanID string
+		size   int
+		err    error
+	}{
+		"retrieve unexpired channel tokens":          {email, chanID, 2, nil},
+		"retrieve tokens of channel of another user": {"other@example.com", chanID, 0, nil},
+		"retrieve tokens with malformed channel":     {email, wrongValue, 0, things.ErrNotFound},
+	}
+
+	for desc, tc := range cases {
+		tokens, err := chanTokenRepo.RetrieveAll(context.Background(), tc.owner, tc.chanID)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+		assert.Len(t, tokens, tc.size, fmt.Sprintf("%s: expected %d tokens got %d\n", desc, tc.size, len(tokens)))
+	}
+}
+
+func TestChannelTokenRetrieveByToken(t *testing.T) {
+	email := "channel-token-retrieve@example.com"
+	chanRepo := postgres.NewChannelRepository(db)
+	chanTokenRepo := postgres.NewChannelTokenRepository(db)
+
+	chanID := saveChannel(t, chanRepo, email)
+	other := saveChannel(t, chanRepo, email)
+
+	active := newChannelToken(t, chanID, email, things.ScopePublish, time.Hour)
+	expired := newChannelToken(t, chanID, email, things.ScopePublish, -time.Hour)
+	for _, ct := range []things.ChannelToken{active, expired} {
+		_, err := chanTokenRepo.Save(context.Background(), ct)
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	}
+
+	cases := map[string]struct {
+		chanID string
+		token  string
+		err    error
+	}{
+		"retrieve active token":                 {chanID, active.Token, nil},
+		"retrieve expired token":                {chanID, expired.Token, things.ErrNotFound},
+		"retrieve token of another channel":     {other, active.Token, things.ErrNotFound},
+		"retrieve token with malformed channel": {wrongValue, active.Token, things.ErrNotFound},
+		"retrieve unknown token":                {chanID, wrongValue, things.ErrNotFound},
+	}
+
+	for desc, tc := range cases {
+		ct, err := chanTokenRepo.RetrieveByToken(context.Background(), tc.chanID, tc.token)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+		if err == nil {
+			assert.Equal(t, active.ID, ct.ID, fmt.Sprintf("%s: expected %s got %s\n", desc, active.ID, ct.ID))
+		}
+	}
+
+	err := chanRepo.Remove(context.Background(), email, chanID)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	_, err = chanTokenRepo.RetrieveByToken(context.Background(), chanID, active.Token)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("retrieve token of removed channel: expected %s got %s\n", things.ErrNotFound, err))
+}
+
+func TestChannelTokenRemove(t *testing.T) {
+	email := "channel-token-remove@example.com"
+	chanRepo := postgres.NewChannelRepository(db)
+	chanTokenRepo := postgres.NewChannelTokenRepository(db)
+
+	chanID := saveChannel(t, chanRepo, email)
+	ct := newChannelToken(t, chanID, email, things.ScopeSubscribe, time.Hour)
+	_, err := chanTokenRepo.Save(context.Background(), ct)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		owner string
+		id    string
+		err   error
+	}{
+		{
+			desc:  "remove token of channel owned by other user",
+			owner: "other@example.com",
+			id:    ct.ID,
+			err:   things.ErrNotFound,
+		},
+		{
+			desc:  "remove existing token",
+			owner: email,
+			id:    ct.ID,
+			err:   nil,
+		},
+		{
+			desc:  "remove removed token",
+			owner: email,
+			id:    ct.ID,
+			err:   things.ErrNotFound,
+		},
+		{
+			desc:  "remove token with malformed id",
+			owner: email,
+			id:    wrongValue,
+			err:   things.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := chanTokenRepo.Remove(context.Background(), tc.owner, chanID, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func saveChannel(t *testing.T, repo things.ChannelRepository, owner string) string {
+	id, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	chanID, err := repo.Save(context.Background(), things.Channel{ID: id, Owner: owner})
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	return chanID
+}
+
+func newChannelToken(t *testing.T, chanID, owner string, scope things.KeyScope, ttl time.Duration) things.ChannelToken {
+	id, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	token, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	now := time.Now().UTC()
+	return things.ChannelToken{
+		ID:        id,
+		ChannelID: chanID,
+		Owner:     owner,
+		Token:     token,
+		Scope:     scope,
+		IssuedAt:  now,
+		ExpiresAt: now.Add(ttl),
+	}
+}
@@ -0,0 +1,417 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	mfpostgres "github.com/mainflux/mainflux/postgres"
+	"github.com/mainflux/mainflux/things"
+)
+
+// subgroupsQuery selects the identifiers of the group and all of its
+// descendants.
+const subgroupsQuery = `WITH RECURSIVE subgroups AS (
+	SELECT id FROM groups WHERE id = :group AND owner = :owner
+	UNION
+	SELECT g.id FROM groups g INNER JOIN subgroups s ON g.parent_id = s.id WHERE g.owner = :owner
+)`
+
+// groupThingsQuery restricts things to the ones assigned to the group or to
+// any of its descendants.
+const groupThingsQuery = `AND id IN (SELECT thing_id FROM group_things
+	WHERE owner = :owner AND group_id IN (SELECT id FROM subgroups))`
+
+var _ things.GroupRepository = (*groupRepository)(nil)
+
+type groupRepository struct {
+	db *sqlx.DB
+}
+
+// NewGroupRepository instantiates a PostgreSQL implementation of group
+// repository.
+func NewGroupRepository(db *sqlx.DB) things.GroupRepository {
+	return &groupRepository{
+		db: db,
+	}
+}
+
+func (gr groupRepository) Save(ctx context.Context, group things.Group) (string, error) {
+	q := `INSERT INTO groups (id, owner, parent_id, name, metadata)
+	      VALUES (:id, :owner, :parent_id, :name, :metadata);`
+
+	dbg, err := toDBGroup(group)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := gr.db.NamedExecContext(ctx, q, dbg); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok {
+			switch pqErr.Code.Name() {
+			case errInvalid, errTruncation:
+				return "", things.ErrMalformedEntity
+			case errDuplicate:
+				return "", things.ErrConflict
+			case errFK:
+				return "", things.ErrNotFound
+			}
+		}
+
+		return "", err
+	}
+
+	return group.ID, nil
+}
+
+func (gr groupRepository) Update(ctx context.Context, group things.Group) error {
+	q := `UPDATE groups SET name = :name, metadata = :metadata WHERE owner = :owner AND id = :id;`
+
+	dbg, err := toDBGroup(group)
+	if err != nil {
+		return err
+	}
+
+	res, err := gr.db.NamedExecContext(ctx, q, dbg)
+	if err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok {
+			switch pqErr.Code.Name() {
+			case errInvalid, errTruncation:
+				return things.ErrMalformedEntity
+			}
+		}
+
+		return err
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if cnt == 0 {
+		return things.ErrNotFound
+	}
+
+	return nil
+}
+
+func (gr groupRepository) RetrieveByID(ctx context.Context, owner, id string) (things.Group, error) {
+	q := `SELECT parent_id, name, metadata FROM groups WHERE id = $1 AND owner = $2;`
+
+	dbg := dbGroup{
+		ID:    id,
+		Owner: owner,
+	}
+	if err := gr.db.QueryRowxContext(ctx, q, id, owner).StructScan(&dbg); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if err == sql.ErrNoRows || ok && errInvalid == pqErr.Code.Name() {
+			return things.Group{}, things.ErrNotFound
+		}
+		return things.Group{}, err
+	}
+
+	return toGroup(dbg)
+}
+
+func (gr groupRepository) RetrieveAll(ctx context.Context, owner, parent string, offset, limit uint64) (things.GroupsPage, error) {
+	parentQuery := ""
+	if parent != "" {
+		if _, err := uuid.FromString(parent); err != nil {
+			return things.GroupsPage{}, things.ErrNotFound
+		}
+		parentQuery = `AND parent_id = :parent`
+	}
+
+	q := `SELECT id, parent_id, name, metadata FROM groups
+	      WHERE owner = :owner ` + parentQuery + ` ORDER BY id LIMIT :limit OFFSET :offset;`
+
+	params := map[string]interface{}{
+		"owner":  owner,
+		"parent": parent,
+		"limit":  limit,
+		"offset": offset,
+	}
+
+	rows, err := gr.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return things.GroupsPage{}, err
+	}
+	defer rows.Close()
+
+	items := []things.Group{}
+	for rows.Next() {
+		dbg := dbGroup{Owner: owner}
+		if err := rows.StructScan(&dbg); err != nil {
+			return things.GroupsPage{}, err
+		}
+
+		g, err := toGroup(dbg)
+		if err != nil {
+			return things.GroupsPage{}, err
+		}
+
+		items = append(items, g)
+	}
+
+	cq := `SELECT COUNT(*) FROM groups WHERE owner = :owner ` + parentQuery + `;`
+	total, err := total(ctx, gr.db, cq, params)
+	if err != nil {
+		return things.GroupsPage{}, err
+	}
+
+	return things.GroupsPage{
+		Groups: items,
+		PageMetadata: things.PageMetadata{
+			Total:  total,
+			Offset: offset,
+			Limit:  limit,
+		},
+	}, nil
+}
+
+func (gr groupRepository) RetrieveThings(ctx context.Context, owner, id string, offset, limit uint64) (things.ThingsPage, error) {
+	// Verify if UUID format is valid to avoid internal Postgres error
+	if _, err := uuid.FromString(id); err != nil {
+		return things.ThingsPage{}, things.ErrNotFound
+	}
+
+	q := subgroupsQuery + `
+	      SELECT id, name, key, state, metadata FROM things
+	      WHERE owner = :owner AND state <> 'deleted' ` + groupThingsQuery + `
+	      ORDER BY id LIMIT :limit OFFSET :offset;`
+
+	params := map[string]interface{}{
+		"owner":  owner,
+		"group":  id,
+		"limit":  limit,
+		"offset": offset,
+	}
+
+	rows, err := gr.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return things.ThingsPage{}, err
+	}
+	defer rows.Close()
+
+	items := []things.Thing{}
+	for rows.Next() {
+		dbth := dbThing{Owner: owner}
+		if err := rows.StructScan(&dbth); err != nil {
+			return things.ThingsPage{}, err
+		}
+
+		th, err := toThing(dbth)
+		if err != nil {
+			return things.ThingsPage{}, err
+		}
+
+		items = append(items, th)
+	}
+
+	cq := subgroupsQuery + `
+	       SELECT COUNT(*) FROM things
+	       WHERE owner = :owner AND state <> 'deleted' ` + groupThingsQuery + `;`
+
+	total, err := total(ctx, gr.db, cq, params)
+	if err != nil {
+		return things.ThingsPage{}, err
+	}
+
+	return things.ThingsPage{
+		Things: items,
+		PageMetadata: things.PageMetadata{
+			Total:  total,
+			Offset: offset,
+			Limit:  limit,
+		},
+	}, nil
+}
+
+// Remove relies on the cascading foreign keys to remove the subgroups and
+// the assignments of the removed groups.
+func (gr groupRepository) Remove(ctx context.Context, owner, id string) error {
+	dbg := dbGroup{
+		ID:    id,
+		Owner: owner,
+	}
+	q := `DELETE FROM groups WHERE id = :id AND owner = :owner`
+	gr.db.NamedExecContext(ctx, q, dbg)
+	return nil
+}
+
+func (gr groupRepository) Assign(ctx context.Context, owner, id string, thingIDs ...string) error {
+	for _, thingID := range append([]string{id}, thingIDs...) {
+		if _, err := uuid.FromString(thingID); err != nil {
+			return things.ErrNotFound
+		}
+	}
+
+	return mfpostgres.Transact(ctx, gr.db, func(tx *sqlx.Tx) error {
+		// The things have to exist, whereas the group is checked by the
+		// foreign key.
+		q := `SELECT COUNT(DISTINCT id) FROM things WHERE owner = $1 AND id = ANY($2) AND state <> 'deleted';`
+
+		var cnt int
+		if err := tx.GetContext(ctx, &cnt, q, owner, pq.Array(thingIDs)); err != nil {
+			return err
+		}
+
+		if cnt != len(unique(thingIDs)) {
+			return things.ErrNotFound
+		}
+
+		q = `INSERT INTO group_things (group_id, owner, thing_id)
+		     SELECT $1, $2, id FROM things WHERE owner = $2 AND id = ANY($3)
+		     ON CONFLICT DO NOTHING;`
+
+		if _, err := tx.ExecContext(ctx, q, id, owner, pq.Array(thingIDs)); err != nil {
+			pqErr, ok := err.(*pq.Error)
+			if ok && errFK == pqErr.Code.Name() {
+				return things.ErrNotFound
+			}
+			return err
+		}
+
+		return nil
+	})
+}
+
+func (gr groupRepository) Unassign(ctx context.Context, owner, id, thingID string) error {
+	q := `DELETE FROM group_things WHERE group_id = $1 AND owner = $2 AND thing_id = $3;`
+
+	res, err := gr.db.ExecContext(ctx, q, id, owner, thingID)
+	if err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && errInvalid == pqErr.Code.Name() {
+			return things.ErrNotFound
+		}
+		return err
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if cnt == 0 {
+		return things.ErrNotFound
+	}
+
+	return nil
+}
+
+func (gr groupRepository) Connect(ctx context.Context, owner, id, chanID string) ([]string, error) {
+	for _, v := range []string{id, chanID} {
+		if _, err := uuid.FromString(v); err != nil {
+			return nil, things.ErrNotFound
+		}
+	}
+
+	q := subgroupsQuery + `
+	      INSERT INTO connections (channel_id, channel_owner, thing_id, thing_owner)
+	      SELECT CAST(:channel AS UUID), owner, id, owner FROM things
+	      WHERE owner = :owner AND state <> 'deleted' ` + groupThingsQuery + `
+	      ON CONFLICT DO NOTHING
+	      RETURNING thing_id;`
+
+	params := map[string]interface{}{
+		"owner":   owner,
+		"group":   id,
+		"channel": chanID,
+	}
+
+	rows, err := gr.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return nil, connectError(err)
+	}
+	defer rows.Close()
+
+	ids := []string{}
+	for rows.Next() {
+		var thingID string
+		if err := rows.Scan(&thingID); err != nil {
+			return nil, err
+		}
+		ids = append(ids, thingID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, connectError(err)
+	}
+
+	return ids, nil
+}
+
+// connectError reports the missing channel, which is detected by the
+// connections foreign key either on query or on reading its results.
+func connectError(err error) error {
+	pqErr, ok := err.(*pq.Error)
+	if ok && errFK == pqErr.Code.Name() {
+		return things.ErrNotFound
+	}
+
+	return err
+}
+
+type dbGroup struct {
+	ID       string         `db:"id"`
+	Owner    string         `db:"owner"`
+	Parent   sql.NullString `db:"parent_id"`
+	Name     string         `db:"name"`
+	Metadata string         `db:"metadata"`
+}
+
+func toDBGroup(g things.Group) (dbGroup, error) {
+	data, err := json.Marshal(g.Metadata)
+	if err != nil {
+		return dbGroup{}, err
+	}
+
+	return dbGroup{
+		ID:       g.ID,
+		Owner:    g.Owner,
+		Parent:   sql.NullString{String: g.Parent, Valid: g.Parent != ""},
+		Name:     g.Name,
+		Metadata: string(data),
+	}, nil
+}
+
+func toGroup(dbg dbGroup) (things.Group, error) {
+	var metadata map[string]interface{}
+	if err := json.Unmarshal([]byte(dbg.Metadata), &metadata); err != nil {
+		return things.Group{}, err
+	}
+
+	return things.Group{
+		ID:       dbg.ID,
+		Owner:    dbg.Owner,
+		Parent:   dbg.Parent.String,
+		Name:     dbg.Name,
+		Metadata: metadata,
+	}, nil
+}
+
+func unique(ids []string) []string {
+	seen := make(map[string]bool)
+	res := []string{}
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			res = append(res, id)
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,291 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux/things"
+	"github.com/mainflux/mainflux/things/postgres"
+	"github.com/mainflux/mainflux/things/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func saveGroup(t *testing.T, repo things.GroupRepository, owner, parent string) string {
+	id, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	_, err = repo.Save(context.Background(), things.Group{ID: id, Owner: owner, Parent: parent})
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	return id
+}
+
+func saveThing(t *testing.T, repo things.ThingRepository, owner string) string {
+	id, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	key, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	_, err = repo.Save(context.Background(), things.Thing{ID: id, Owner: owner, Key: key})
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	return id
+}
+
+func TestGroupSave(t *testing.T) {
+	email := "group-save@example.com"
+	groupRepo := postgres.NewGroupRepository(db)
+
+	parent := saveGroup(t, groupRepo, email, "")
+
+	id, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	missing, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		group things.Group
+		err   error
+	}{
+		{
+			desc:  "create valid subgroup",
+			group: things.Group{ID: id, Owner: email, Parent: parent},
+			err:   nil,
+		},
+		{
+			desc:  "create existing group",
+			group: things.Group{ID: id, Owner: email},
+			err:   things.ErrConflict,
+		},
+		{
+			desc:  "create group with invalid ID",
+			group: things.Group{ID: "invalid", Owner: email},
+			err:   things.ErrMalformedEntity,
+		},
+		{
+			desc:  "create subgroup of non-existing group",
+			group: things.Group{ID: missing, Owner: email, Parent: missing},
+			err:   things.ErrNotFound,
+		},
+		{
+			desc:  "create subgroup of group owned by other user",
+			group: things.Group{ID: missing, Owner: "other@example.com", Parent: parent},
+			err:   things.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := groupRepo.Save(context.Background(), tc.group)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestGroupRetrieval(t *testing.T) {
+	email := "group-retrieval@example.com"
+	groupRepo := postgres.NewGroupRepository(db)
+
+	parent := saveGroup(t, groupRepo, email, "")
+	n := uint64(5)
+	for i := uint64(0); i < n; i++ {
+		saveGroup(t, groupRepo, email, parent)
+	}
+
+	gr, err := groupRepo.RetrieveByID(context.Background(), email, parent)
+	assert.Nil(t, err, fmt.Sprintf("retrieve group: unexpected error %s", err))
+	assert.Equal(t, parent, gr.ID, fmt.Sprintf("retrieve group: expected %s got %s\n", parent, gr.ID))
+
+	_, err = groupRepo.RetrieveByID(context.Background(), email, wrongID)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("retrieve group with invalid ID: expected %s got %s\n", things.ErrNotFound, err))
+
+	cases := []struct {
+		desc   string
+		parent string
+		offset uint64
+		limit  uint64
+		size   uint64
+		total  uint64
+	}{
+		{
+			desc:  "retrieve all groups",
+			limit: n + 1,
+			size:  n + 1,
+			total: n + 1,
+		},
+		{
+			desc:   "retrieve subgroups",
+			parent: parent,
+			limit:  n,
+			size:   n,
+			total:  n,
+		},
+		{
+			desc:   "retrieve last page of subgroups",
+			parent: parent,
+			offset: n - 1,
+			limit:  n,
+			size:   1,
+			total:  n,
+		},
+	}
+
+	for _, tc := range cases {
+		page, err := groupRepo.RetrieveAll(context.Background(), email, tc.parent, tc.offset, tc.limit)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		size := uint64(len(page.Groups))
+		assert.Equal(t, tc.size, size, fmt.Sprintf("%s: expected %d got %d\n", tc.desc, tc.size, size))
+		assert.Equal(t, tc.total, page.Total, fmt.Sprintf("%s: expected total %d got %d\n", tc.desc, tc.total, page.Total))
+	}
+}
+
+func TestGroupAssignment(t *testing.T) {
+	email := "group-assignment@example.com"
+	groupRepo := postgres.NewGroupRepository(db)
+	thingRepo := postgres.NewThingRepository(db)
+
+	parent := saveGroup(t, groupRepo, email, "")
+	child := saveGroup(t, groupRepo, email, parent)
+	th1 := saveThing(t, thingRepo, email)
+	th2 := saveThing(t, thingRepo, email)
+	removed := saveThing(t, thingRepo, email)
+	err := thingRepo.Remove(context.Background(), email, removed)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		group  string
+		things []string
+		err    error
+	}{
+		{
+			desc:   "assign thing to group",
+			group:  parent,
+			things: []string{th1},
+			err:    nil,
+		},
+		{
+			desc:   "assign things to subgroup",
+			group:  child,
+			things: []string{th1, th2, th2},
+			err:    nil,
+		},
+		{
+			desc:   "assign removed thing to group",
+			group:  parent,
+			things: []string{removed},
+			err:    things.ErrNotFound,
+		},
+		{
+			desc:   "assign thing to group with invalid ID",
+			group:  wrongID,
+			things: []string{th1},
+			err:    things.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := groupRepo.Assign(context.Background(), email, tc.group, tc.things...)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	page, err := groupRepo.RetrieveThings(context.Background(), email, parent, 0, 10)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.Equal(t, uint64(2), page.Total, fmt.Sprintf("retrieve things of group: expected 2 got %d\n", page.Total))
+
+	err = groupRepo.Unassign(context.Background(), email, child, th2)
+	assert.Nil(t, err, fmt.Sprintf("unassign thing: unexpected error %s", err))
+	err = groupRepo.Unassign(context.Background(), email, child, th2)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("unassign unassigned thing: expected %s got %s\n", things.ErrNotFound, err))
+
+	page, err = groupRepo.RetrieveThings(context.Background(), email, child, 0, 10)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.Equal(t, uint64(1), page.Total, fmt.Sprintf("retrieve things of subgroup: expected 1 got %d\n", page.Total))
+
+	err = groupRepo.Remove(context.Background(), email, parent)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	_, err = groupRepo.RetrieveByID(context.Background(), email, child)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("retrieve subgroup of removed group: expected %s got %s\n", things.ErrNotFound, err))
+}
+
+func TestGroupConnect(t *testing.T) {
+	email := "group-connect@example.com"
+	groupRepo := postgres.NewGroupRepository(db)
+	thingRepo := postgres.NewThingRepository(db)
+	chanRepo := postgres.NewChannelRepository(db)
+
+	parent := saveGroup(t, groupRepo, email, "")
+	child := saveGroup(t, groupRepo, email, parent)
+	th1 := saveThing(t, thingRepo, email)
+	th2 := saveThing(t, thingRepo, email)
+	err := groupRepo.Assign(context.Background(), email, parent, th1)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	err = groupRepo.Assign(context.Background(), email, child, th2)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	chid, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	chanID, err := chanRepo.Save(context.Background(), things.Channel{ID: chid, Owner: email})
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	missing, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		group  string
+		chanID string
+		things []string
+		err    error
+	}{
+		{
+			desc:   "connect subgroup to channel",
+			group:  child,
+			chanID: chanID,
+			things: []string{th2},
+			err:    nil,
+		},
+		{
+			desc:   "connect group to channel",
+			group:  parent,
+			chanID: chanID,
+			things: []string{th1},
+			err:    nil,
+		},
+		{
+			desc:   "connect connected group to channel",
+			group:  parent,
+			chanID: chanID,
+			things: []string{},
+			err:    nil,
+		},
+		{
+			desc:   "connect group to non-existing channel",
+			group:  parent,
+			chanID: missing,
+			things: nil,
+			err:    things.ErrNotFound,
+		},
+		{
+			desc:   "connect group to channel with invalid ID",
+			group:  parent,
+			chanID: wrongID,
+			things: nil,
+			err:    things.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		ids, err := groupRepo.Connect(context.Background(), email, tc.group, tc.chanID)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.ElementsMatch(t, tc.things, ids, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.things, ids))
+	}
+}
@@ -0,0 +1,175 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/mainflux/mainflux/things"
+)
+
+var _ things.HealthRepository = (*healthRepository)(nil)
+
+type healthRepository struct {
+	db *sqlx.DB
+}
+
+// NewHealthRepository instantiates a PostgreSQL implementation of health
+// records repository.
+func NewHealthRepository(db *sqlx.DB) things.HealthRepository {
+	return &healthRepository{
+		db: db,
+	}
+}
+
+func (hr healthRepository) Save(ctx context.Context, health things.Health) error {
+	q := `INSERT INTO health (thing_id, owner, battery, rssi, firmware, reported)
+	      VALUES (:thing_id, :owner, :battery, :rssi, :firmware, :reported)
+	      ON CONFLICT (thing_id) DO UPDATE SET
+	          owner = EXCLUDED.owner,
+	          battery = COALESCE(EXCLUDED.battery, health.battery),
+	          rssi = COALESCE(EXCLUDED.rssi, health.rssi),
+	          firmware = COALESCE(NULLIF(EXCLUDED.firmware, ''), health.firmware),
+	          reported = EXCLUDED.reported;`
+
+	if _, err := hr.db.NamedExecContext(ctx, q, toDBHealth(health)); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok {
+			switch pqErr.Code.Name() {
+			case errInvalid, errTruncation:
+				return things.ErrMalformedEntity
+			case errFK:
+				return things.ErrNotFound
+			}
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (hr healthRepository) RetrieveByThing(ctx context.Context, owner, thingID string) (things.Health, error) {
+	if _, err := uuid.FromString(thingID); err != nil {
+		return things.Health{}, things.ErrNotFound
+	}
+
+	q := `SELECT thing_id, owner, battery, rssi, firmware, reported FROM health
+	      WHERE thing_id = $1 AND owner = $2;`
+
+	dbh := dbHealth{}
+	if err := hr.db.QueryRowxContext(ctx, q, thingID, owner).StructScan(&dbh); err != nil {
+		if err == sql.ErrNoRows {
+			return things.Health{}, things.ErrNotFound
+		}
+		return things.Health{}, err
+	}
+
+	return toHealth(dbh), nil
+}
+
+func (hr healthRepository) RetrieveUnhealthy(ctx context.Context, owner string, thresholds things.HealthThresholds, offset, limit uint64) (things.HealthPage, error) {
+	where := `FROM health h JOIN things t ON t.id = h.thing_id AND t.owner = h.owner
+	          WHERE h.owner = :owner AND t.state <> 'deleted' AND (h.battery < :battery OR h.rssi < :rssi)`
+
+	q := `SELECT h.thing_id, h.owner, h.battery, h.rssi, h.firmware, h.reported ` + where + `
+	      ORDER BY h.battery ASC NULLS LAST, h.thing_id LIMIT :limit OFFSET :offset;`
+
+	params := map[string]interface{}{
+		"owner":   owner,
+		"battery": thresholds.Battery,
+		"rssi":    thresholds.RSSI,
+		"limit":   limit,
+		"offset":  offset,
+	}
+
+	rows, err := hr.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return things.HealthPage{}, err
+	}
+	defer rows.Close()
+
+	items := []things.Health{}
+	for rows.Next() {
+		dbh := dbHealth{}
+		if err := rows.StructScan(&dbh); err != nil {
+			return things.HealthPage{}, err
+		}
+
+		items = append(items, toHealth(dbh))
+	}
+
+	cq := `SELECT COUNT(*) ` + where + `;`
+	total, err := total(ctx, hr.db, cq, params)
+	if err != nil {
+		return things.HealthPage{}, err
+	}
+
+	page := things.HealthPage{
+		PageMetadata: things.PageMetadata{
+			Total:  total,
+			Offset: offset,
+			Limit:  limit,
+		},
+		Records: items,
+	}
+
+	return page, nil
+}
+
+type dbHealth struct {
+	ThingID  string          `db:"thing_id"`
+	Owner    string          `db:"owner"`
+	Battery  sql.NullFloat64 `db:"battery"`
+	RSSI     sql.NullFloat64 `db:"rssi"`
+	Firmware string          `db:"firmware"`
+	Reported time.Time       `db:"reported"`
+}
+
+func toDBHealth(h things.Health) dbHealth {
+	dbh := dbHealth{
+		ThingID:  h.ThingID,
+		Owner:    h.Owner,
+		Firmware: h.Firmware,
+		Reported: h.Reported,
+	}
+
+	if h.Battery != nil {
+		dbh.Battery = sql.NullFloat64{Float64: *h.Battery, Valid: true}
+	}
+	if h.RSSI != nil {
+		dbh.RSSI = sql.NullFloat64{Float64: *h.RSSI, Valid: true}
+	}
+
+	return dbh
+}
+
+func toHealth(dbh dbHealth) things.Health {
+	h := things.Health{
+		ThingID:  dbh.ThingID,
+		Owner:    dbh.Owner,
+		Firmware: dbh.Firmware,
+		Reported: dbh.Reported,
+	}
+
+	if dbh.Battery.Valid {
+		battery := dbh.Battery.Float64
+		h.Battery = &battery
+	}
+	if dbh.RSSI.Valid {
+		rssi := dbh.RSSI.Float64
+		h.RSSI = &rssi
+	}
+
+	return h
+}
@@ -0,0 +1,151 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/things"
+	"github.com/mainflux/mainflux/things/postgres"
+	"github.com/mainflux/mainflux/things/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newHealth(thingID, owner string, battery, rssi *float64, firmware string) things.Health {
+	return things.Health{
+		ThingID:  thingID,
+		Owner:    owner,
+		Battery:  battery,
+		RSSI:     rssi,
+		Firmware: firmware,
+		Reported: time.Now().Round(time.Second),
+	}
+}
+
+func float(v float64) *float64 {
+	return &v
+}
+
+func TestHealthSave(t *testing.T) {
+	email := "health-save@example.com"
+	thingRepo := postgres.NewThingRepository(db)
+	healthRepo := postgres.NewHealthRepository(db)
+
+	thID := saveThing(t, thingRepo, email)
+	missing, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		health things.Health
+		err    error
+	}{
+		{
+			desc:   "save health record",
+			health: newHealth(thID, email, float(87), float(-71), "1.4.2"),
+			err:    nil,
+		},
+		{
+			desc:   "update health record with partial report",
+			health: newHealth(thID, email, float(15), nil, ""),
+			err:    nil,
+		},
+		{
+			desc:   "save health record of non-existing thing",
+			health: newHealth(missing, email, float(15), nil, ""),
+			err:    things.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := healthRepo.Save(context.Background(), tc.health)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	health, err := healthRepo.RetrieveByThing(context.Background(), email, thID)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	require.NotNil(t, health.Battery, "expected battery level to be saved")
+	require.NotNil(t, health.RSSI, "expected signal strength to be kept")
+	assert.Equal(t, float64(15), *health.Battery, fmt.Sprintf("expected battery level 15 got %f", *health.Battery))
+	assert.Equal(t, float64(-71), *health.RSSI, fmt.Sprintf("expected signal strength -71 got %f", *health.RSSI))
+	assert.Equal(t, "1.4.2", health.Firmware, fmt.Sprintf("expected firmware 1.4.2 got %s", health.Firmware))
+}
+
+func TestHealthRetrieveByThing(t *testing.T) {
+	email := "health-retrieve@example.com"
+	thingRepo := postgres.NewThingRepository(db)
+	healthRepo := postgres.NewHealthRepository(db)
+
+	thID := saveThing(t, thingRepo, email)
+	silent := saveThing(t, thingRepo, email)
+	err := healthRepo.Save(context.Background(), newHealth(thID, email, nil, nil, "1.4.2"))
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := map[string]struct {
+		owner   string
+		thingID string
+		err     error
+	}{
+		"retrieve health record":                      {email, thID, nil},
+		"retrieve health record of another owner":     {wrongValue, thID, things.ErrNotFound},
+		"retrieve health record of silent thing":      {email, silent, things.ErrNotFound},
+		"retrieve health record with malformed thing": {email, wrongValue, things.ErrNotFound},
+	}
+
+	for desc, tc := range cases {
+		_, err := healthRepo.RetrieveByThing(context.Background(), tc.owner, tc.thingID)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
+func TestHealthRetrieveUnhealthy(t *testing.T) {
+	email := "health-unhealthy@example.com"
+	thingRepo := postgres.NewThingRepository(db)
+	healthRepo := postgres.NewHealthRepository(db)
+
+	reports := []things.Health{
+		{Battery: float(10), RSSI: float(-70)},
+		{Battery: float(90), RSSI: float(-110)},
+		{Battery: float(5)},
+		{Battery: float(90), RSSI: float(-70)},
+		{Firmware: "1.4.2"},
+	}
+	for _, r := range reports {
+		thID := saveThing(t, thingRepo, email)
+		err := healthRepo.Save(context.Background(), newHealth(thID, email, r.Battery, r.RSSI, r.Firmware))
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	}
+
+	defaults := things.HealthThresholds{Battery: things.DefaultMinBattery, RSSI: things.DefaultMinRSSI}
+
+	cases := map[string]struct {
+		owner      string
+		thresholds things.HealthThresholds
+		offset     uint64
+		limit      uint64
+		size       uint64
+		total      uint64
+	}{
+		"retrieve unhealthy things":                  {email, defaults, 0, 10, 3, 3},
+		"retrieve unhealthy things page":             {email, defaults, 1, 1, 1, 3},
+		"retrieve things with low battery only":      {email, things.HealthThresholds{Battery: 8, RSSI: -200}, 0, 10, 1, 1},
+		"retrieve unhealthy things of another owner": {wrongValue, defaults, 0, 10, 0, 0},
+	}
+
+	for desc, tc := range cases {
+		page, err := healthRepo.RetrieveUnhealthy(context.Background(), tc.owner, tc.thresholds, tc.offset, tc.limit)
+		size := uint64(len(page.Records))
+		assert.Nil(t, err, fmt.Sprintf("%s: got unexpected error: %s", desc, err))
+		assert.Equal(t, tc.size, size, fmt.Sprintf("%s: expected size %d got %d\n", desc, tc.size, size))
+		assert.Equal(t, tc.total, page.Total, fmt.Sprintf("%s: expected total %d got %d\n", desc, tc.total, page.Total))
+	}
+}
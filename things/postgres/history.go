@@ -0,0 +1,138 @@
+//
+// Copyright (c) 2018
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq" // required for DB access
+	mfpostgres "github.com/mainflux/mainflux/postgres"
+	"github.com/mainflux/mainflux/things"
+)
+
+var _ things.HistoryRepository = (*historyRepository)(nil)
+
+type historyRepository struct {
+	db *sqlx.DB
+}
+
+// NewHistoryRepository instantiates a PostgreSQL implementation of history
+// repository.
+func NewHistoryRepository(db *sqlx.DB) things.HistoryRepository {
+	return &historyRepository{
+		db: db,
+	}
+}
+
+func (hr historyRepository) Save(ctx context.Context, changes ...things.Change) error {
+	q := `INSERT INTO history (entity_id, owner, field, old_value, new_value, changed_at)
+	      VALUES (:entity_id, :owner, :field, :old_value, :new_value, :changed_at);`
+
+	return mfpostgres.Transact(ctx, hr.db, func(tx *sqlx.Tx) error {
+		for _, c := range changes {
+			if _, err := tx.NamedExecContext(ctx, q, toDBChange(c)); err != nil {
+				pqErr, ok := err.(*pq.Error)
+				if ok {
+					switch pqErr.Code.Name() {
+					case errInvalid, errTruncation:
+						return things.ErrMalformedEntity
+					}
+				}
+
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (hr historyRepository) RetrieveAll(ctx context.Context, owner, id string, offset, limit uint64) (things.ChangesPage, error) {
+	q := `SELECT entity_id, owner, field, old_value, new_value, changed_at FROM history
+	      WHERE owner = :owner AND entity_id = :entity_id
+	      ORDER BY changed_at DESC, seq DESC LIMIT :limit OFFSET :offset;`
+
+	params := map[string]interface{}{
+		"owner":     owner,
+		"entity_id": id,
+		"limit":     limit,
+		"offset":    offset,
+	}
+
+	rows, err := hr.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && errInvalid == pqErr.Code.Name() {
+			return things.ChangesPage{}, things.ErrNotFound
+		}
+
+		return things.ChangesPage{}, err
+	}
+	defer rows.Close()
+
+	items := []things.Change{}
+	for rows.Next() {
+		var dbc dbChange
+		if err := rows.StructScan(&dbc); err != nil {
+			return things.ChangesPage{}, err
+		}
+
+		items = append(items, toChange(dbc))
+	}
+
+	q = `SELECT COUNT(*) FROM history WHERE owner = $1 AND entity_id = $2;`
+
+	total := uint64(0)
+	if err := hr.db.GetContext(ctx, &total, q, owner, id); err != nil {
+		return things.ChangesPage{}, err
+	}
+
+	page := things.ChangesPage{
+		Changes: items,
+		PageMetadata: things.PageMetadata{
+			Total:  total,
+			Offset: offset,
+			Limit:  limit,
+		},
+	}
+
+	return page, nil
+}
+
+type dbChange struct {
+	EntityID  string    `db:"entity_id"`
+	Owner     string    `db:"owner"`
+	Field     string    `db:"field"`
+	OldValue  string    `db:"old_value"`
+	NewValue  string    `db:"new_value"`
+	ChangedAt time.Time `db:"changed_at"`
+}
+
+func toDBChange(c things.Change) dbChange {
+	return dbChange{
+		EntityID:  c.EntityID,
+		Owner:     c.Owner,
+		Field:     c.Field,
+		OldValue:  c.OldValue,
+		NewValue:  c.NewValue,
+		ChangedAt: c.ChangedAt,
+	}
+}
+
+func toChange(dbc dbChange) things.Change {
+	return things.Change{
+		EntityID:  dbc.EntityID,
+		Owner:     dbc.Owner,
+		Field:     dbc.Field,
+		OldValue:  dbc.OldValue,
+		NewValue:  dbc.NewValue,
+		ChangedAt: dbc.ChangedAt.UTC(),
+	}
+}
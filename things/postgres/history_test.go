@@ -0,0 +1,136 @@
+//
+// Copyright (c) 2018
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/things"
+	"github.com/mainflux/mainflux/things/postgres"
+	"github.com/mainflux/mainflux/things/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistorySave(t *testing.T) {
+	historyRepo := postgres.NewHistoryRepository(db)
+
+	email := "history-save@example.com"
+
+	id, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	change := things.Change{
+		EntityID:  id,
+		Owner:     email,
+		Field:     "name",
+		OldValue:  "old",
+		NewValue:  "new",
+		ChangedAt: time.Now(),
+	}
+
+	invalid := change
+	invalid.EntityID = "invalid"
+
+	cases := []struct {
+		desc    string
+		changes []things.Change
+		err     error
+	}{
+		{
+			desc:    "save changes",
+			changes: []things.Change{change, change},
+			err:     nil,
+		},
+		{
+			desc:    "save change with invalid entity ID",
+			changes: []things.Change{invalid},
+			err:     things.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		err := historyRepo.Save(context.Background(), tc.changes...)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestHistoryRetrieveAll(t *testing.T) {
+	historyRepo := postgres.NewHistoryRepository(db)
+
+	email := "history-retrieve@example.com"
+	n := uint64(10)
+
+	id, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	for i := uint64(0); i < n; i++ {
+		c := things.Change{
+			EntityID:  id,
+			Owner:     email,
+			Field:     "name",
+			OldValue:  fmt.Sprintf("name-%d", i),
+			NewValue:  fmt.Sprintf("name-%d", i+1),
+			ChangedAt: now.Add(time.Duration(i) * time.Second),
+		}
+		err := historyRepo.Save(context.Background(), c)
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	}
+
+	cases := map[string]struct {
+		owner  string
+		id     string
+		offset uint64
+		limit  uint64
+		size   uint64
+		total  uint64
+		last   string
+	}{
+		"retrieve all changes": {
+			owner:  email,
+			id:     id,
+			offset: 0,
+			limit:  n,
+			size:   n,
+			total:  n,
+			last:   fmt.Sprintf("name-%d", n),
+		},
+		"retrieve subset of changes": {
+			owner:  email,
+			id:     id,
+			offset: n / 2,
+			limit:  n,
+			size:   n / 2,
+			total:  n,
+			last:   fmt.Sprintf("name-%d", n/2),
+		},
+		"retrieve changes of entity owned by other user": {
+			owner:  "wrong",
+			id:     id,
+			offset: 0,
+			limit:  n,
+			size:   0,
+			total:  0,
+		},
+	}
+
+	for desc, tc := range cases {
+		page, err := historyRepo.RetrieveAll(context.Background(), tc.owner, tc.id, tc.offset, tc.limit)
+		require.Nil(t, err, fmt.Sprintf("%s: got unexpected error: %s", desc, err))
+		size := uint64(len(page.Changes))
+		assert.Equal(t, tc.size, size, fmt.Sprintf("%s: expected size %d got %d\n", desc, tc.size, size))
+		assert.Equal(t, tc.total, page.Total, fmt.Sprintf("%s: expected total %d got %d\n", desc, tc.total, page.Total))
+		if size > 0 {
+			assert.Equal(t, tc.last, page.Changes[0].NewValue, fmt.Sprintf("%s: expected most recent change %s got %s\n", desc, tc.last, page.Changes[0].NewValue))
+		}
+	}
+}
@@ -8,13 +8,33 @@
 package postgres
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq" // required for SQL access
+	mfpostgres "github.com/mainflux/mainflux/postgres"
 	migrate "github.com/rubenv/sql-migrate"
 )
 
+const (
+	// Postgres is the flavor of the PostgreSQL instance, used by default.
+	Postgres = "postgres"
+
+	// CockroachDB is the flavor of the CockroachDB cluster, which speaks
+	// the PostgreSQL protocol and spans several regions.
+	CockroachDB = "cockroachdb"
+
+	// dropKeyConstraint drops the unique constraint of the thing keys,
+	// which CockroachDB backs by the index that has to be dropped instead.
+	dropKeyConstraint = `ALTER TABLE things DROP CONSTRAINT IF EXISTS things_key_key`
+	dropKeyIndex      = `DROP INDEX IF EXISTS things@things_key_key CASCADE`
+)
+
+// ErrUnsupportedFlavor indicates that the database flavor is neither
+// PostgreSQL nor CockroachDB.
+var ErrUnsupportedFlavor = errors.New("unsupported database flavor")
+
 // Config defines the options that are used when connecting to a PostgreSQL instance
 type Config struct {
 	Host        string
@@ -26,27 +46,56 @@ type Config struct {
 	SSLCert     string
 	SSLKey      string
 	SSLRootCert string
+	Target      string
+	SyncCommit  string
+	UniqueNames bool
+	Flavor      string
 }
 
 // Connect creates a connection to the PostgreSQL instance and applies any
-// unapplied database migrations. A non-nil error is returned to indicate
-// failure.
+// unapplied database migrations. The names of the things and channels are
+// unique per owner if UniqueNames is set, not counting the deleted things.
+// The CockroachDB flavor ignores the synchronous commit level, since its
+// writes are always replicated synchronously. A non-nil error is returned
+// to indicate failure.
 func Connect(cfg Config) (*sqlx.DB, error) {
-	url := fmt.Sprintf("host=%s port=%s user=%s dbname=%s password=%s sslmode=%s sslcert=%s sslkey=%s sslrootcert=%s", cfg.Host, cfg.Port, cfg.User, cfg.Name, cfg.Pass, cfg.SSLMode, cfg.SSLCert, cfg.SSLKey, cfg.SSLRootCert)
+	switch cfg.Flavor {
+	case "", Postgres:
+	case CockroachDB:
+		cfg.SyncCommit = ""
+	default:
+		return nil, ErrUnsupportedFlavor
+	}
 
-	db, err := sqlx.Open("postgres", url)
+	url := fmt.Sprintf("user=%s dbname=%s password=%s sslmode=%s sslcert=%s sslkey=%s sslrootcert=%s", cfg.User, cfg.Name, cfg.Pass, cfg.SSLMode, cfg.SSLCert, cfg.SSLKey, cfg.SSLRootCert)
+
+	db, err := mfpostgres.Open(mfpostgres.Config{
+		Hosts:      cfg.Host,
+		Port:       cfg.Port,
+		DSN:        url,
+		Target:     cfg.Target,
+		SyncCommit: cfg.SyncCommit,
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	if err := migrateDB(db); err != nil {
+	if err := migrateDB(db, cfg.Flavor); err != nil {
+		return nil, err
+	}
+
+	if err := uniqueNames(db, cfg.UniqueNames); err != nil {
 		return nil, err
 	}
 
 	return db, nil
 }
 
-func migrateDB(db *sqlx.DB) error {
+// migrateDB applies the migrations, which sql-migrate orders by their IDs.
+// The IDs without the numeric prefix are compared as strings, so the
+// migrations following things_9 are identified by the two-digit number
+// appended to its ID (e.g. things_9_10), to keep them applied in order.
+func migrateDB(db *sqlx.DB, flavor string) error {
 	migrations := &migrate.MemoryMigrationSource{
 		Migrations: []*migrate.Migration{
 			{
@@ -83,9 +132,376 @@ func migrateDB(db *sqlx.DB) error {
 					"DROP TABLE channels",
 				},
 			},
+			{
+				Id: "things_2",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS reservations (
+						id    UUID,
+						owner VARCHAR(254),
+						key   VARCHAR(4096) UNIQUE NOT NULL,
+						PRIMARY KEY (id, owner)
+					)`,
+				},
+				Down: []string{
+					"DROP TABLE reservations",
+				},
+			},
+			{
+				Id: "things_3",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS history (
+						seq        BIGSERIAL PRIMARY KEY,
+						entity_id  UUID NOT NULL,
+						owner      VARCHAR(254) NOT NULL,
+						field      VARCHAR(254) NOT NULL,
+						old_value  TEXT NOT NULL,
+						new_value  TEXT NOT NULL,
+						changed_at TIMESTAMP NOT NULL
+					)`,
+					`CREATE INDEX IF NOT EXISTS history_entity_idx ON history (entity_id, owner, changed_at)`,
+				},
+				Down: []string{
+					"DROP TABLE history",
+				},
+			},
+			{
+				Id: "things_4",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS revocations (
+						thing_id   UUID PRIMARY KEY,
+						version    BIGINT NOT NULL,
+						expires_at TIMESTAMP NOT NULL
+					)`,
+				},
+				Down: []string{
+					"DROP TABLE revocations",
+				},
+			},
+			{
+				Id: "things_5",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS templates (
+						owner    VARCHAR(254) PRIMARY KEY,
+						channels JSONB NOT NULL
+					)`,
+				},
+				Down: []string{
+					"DROP TABLE templates",
+				},
+			},
+			{
+				Id: "things_6",
+				Up: []string{
+					`ALTER TABLE things ADD COLUMN IF NOT EXISTS state VARCHAR(16) NOT NULL DEFAULT 'enabled'`,
+					dropKeyConstraint,
+					`CREATE UNIQUE INDEX IF NOT EXISTS things_key_idx ON things (key) WHERE state <> 'deleted'`,
+				},
+				Down: []string{
+					"DROP INDEX things_key_idx",
+					"DELETE FROM things WHERE state = 'deleted'",
+					"ALTER TABLE things ADD CONSTRAINT things_key_key UNIQUE (key)",
+					"ALTER TABLE things DROP COLUMN state",
+				},
+			},
+			{
+				Id: "things_7",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS groups (
+						id        UUID,
+						owner     VARCHAR(254),
+						parent_id UUID,
+						name      VARCHAR(1024),
+						metadata  JSON,
+						PRIMARY KEY (id, owner),
+						FOREIGN KEY (parent_id, owner) REFERENCES groups (id, owner) ON DELETE CASCADE
+					)`,
+					`CREATE TABLE IF NOT EXISTS group_things (
+						group_id UUID,
+						owner    VARCHAR(254),
+						thing_id UUID,
+						FOREIGN KEY (group_id, owner) REFERENCES groups (id, owner) ON DELETE CASCADE,
+						FOREIGN KEY (thing_id, owner) REFERENCES things (id, owner) ON DELETE CASCADE,
+						PRIMARY KEY (group_id, owner, thing_id)
+					)`,
+					`CREATE INDEX IF NOT EXISTS groups_parent_idx ON groups (parent_id, owner)`,
+				},
+				Down: []string{
+					"DROP TABLE group_things",
+					"DROP TABLE groups",
+				},
+			},
+			{
+				Id: "things_8",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS subtopics (
+						channel_id  UUID,
+						owner       VARCHAR(254),
+						name        VARCHAR(1024),
+						description VARCHAR(1024),
+						schema      JSON,
+						FOREIGN KEY (channel_id, owner) REFERENCES channels (id, owner) ON DELETE CASCADE ON UPDATE CASCADE,
+						PRIMARY KEY (channel_id, owner, name)
+					)`,
+				},
+				Down: []string{
+					"DROP TABLE subtopics",
+				},
+			},
+			{
+				Id: "things_9",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS thing_keys (
+						id        UUID PRIMARY KEY,
+						thing_id  UUID NOT NULL,
+						owner     VARCHAR(254) NOT NULL,
+						key       VARCHAR(4096) UNIQUE NOT NULL,
+						scope     VARCHAR(16) NOT NULL,
+						issued_at TIMESTAMP NOT NULL,
+						FOREIGN KEY (thing_id, owner) REFERENCES things (id, owner) ON DELETE CASCADE ON UPDATE CASCADE
+					)`,
+					`CREATE INDEX IF NOT EXISTS thing_keys_thing_idx ON thing_keys (thing_id, owner)`,
+				},
+				Down: []string{
+					"DROP TABLE thing_keys",
+				},
+			},
+			{
+				Id: "things_9_10",
+				Up: []string{
+					`ALTER TABLE things ADD COLUMN IF NOT EXISTS key_expires_at TIMESTAMP`,
+					`ALTER TABLE things ADD COLUMN IF NOT EXISTS key_rotation BIGINT NOT NULL DEFAULT 0`,
+					`CREATE INDEX IF NOT EXISTS things_key_expires_idx ON things (key_expires_at) WHERE key_expires_at IS NOT NULL`,
+				},
+				Down: []string{
+					"DROP INDEX things_key_expires_idx",
+					"ALTER TABLE things DROP COLUMN key_rotation",
+					"ALTER TABLE things DROP COLUMN key_expires_at",
+				},
+			},
+			{
+				Id: "things_9_11",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS shares (
+						kind       VARCHAR(16),
+						entity_id  UUID,
+						owner      VARCHAR(254) NOT NULL,
+						user_id    VARCHAR(254),
+						permission VARCHAR(16) NOT NULL,
+						PRIMARY KEY (kind, entity_id, user_id)
+					)`,
+					`CREATE INDEX IF NOT EXISTS shares_user_idx ON shares (user_id, kind)`,
+					`CREATE INDEX IF NOT EXISTS shares_owner_idx ON shares (owner)`,
+				},
+				Down: []string{
+					"DROP TABLE shares",
+				},
+			},
+			{
+				Id: "things_9_12",
+				Up: []string{
+					`ALTER TABLE channels ADD COLUMN IF NOT EXISTS state VARCHAR(16) NOT NULL DEFAULT 'active'`,
+				},
+				Down: []string{
+					"ALTER TABLE channels DROP COLUMN state",
+				},
+			},
+			{
+				Id: "things_9_13",
+				Up: []string{
+					`ALTER TABLE things ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}'`,
+					`ALTER TABLE channels ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}'`,
+					`CREATE INDEX IF NOT EXISTS things_tags_idx ON things USING GIN (tags)`,
+					`CREATE INDEX IF NOT EXISTS channels_tags_idx ON channels USING GIN (tags)`,
+				},
+				Down: []string{
+					"DROP INDEX channels_tags_idx",
+					"DROP INDEX things_tags_idx",
+					"ALTER TABLE channels DROP COLUMN tags",
+					"ALTER TABLE things DROP COLUMN tags",
+				},
+			},
+			{
+				Id: "things_9_14",
+				Up: []string{
+					`CREATE INDEX IF NOT EXISTS things_owner_id_idx ON things (owner, id)`,
+					`CREATE INDEX IF NOT EXISTS channels_owner_id_idx ON channels (owner, id)`,
+				},
+				Down: []string{
+					"DROP INDEX channels_owner_id_idx",
+					"DROP INDEX things_owner_id_idx",
+				},
+			},
+			{
+				Id: "things_9_15",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS audit (
+						seq         BIGSERIAL PRIMARY KEY,
+						owner       VARCHAR(254) NOT NULL,
+						actor       VARCHAR(254) NOT NULL,
+						operation   VARCHAR(32) NOT NULL,
+						entity_kind VARCHAR(32) NOT NULL,
+						entity_id   UUID NOT NULL,
+						diff        JSONB NOT NULL,
+						occurred_at TIMESTAMP NOT NULL
+					)`,
+					`CREATE INDEX IF NOT EXISTS audit_owner_idx ON audit (owner, occurred_at)`,
+					`CREATE INDEX IF NOT EXISTS audit_entity_idx ON audit (entity_id, owner)`,
+				},
+				Down: []string{
+					"DROP TABLE audit",
+				},
+			},
+			{
+				Id: "things_9_16",
+				Up: []string{
+					`ALTER TABLE things ADD COLUMN IF NOT EXISTS last_seen TIMESTAMP`,
+				},
+				Down: []string{
+					"ALTER TABLE things DROP COLUMN last_seen",
+				},
+			},
+			{
+				Id: "things_9_17",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS profiles (
+						id        UUID,
+						owner     VARCHAR(254),
+						name      VARCHAR(1024),
+						metadata  JSON,
+						channels  TEXT[] NOT NULL DEFAULT '{}',
+						bootstrap TEXT NOT NULL DEFAULT '',
+						PRIMARY KEY (id, owner)
+					)`,
+				},
+				Down: []string{
+					"DROP TABLE profiles",
+				},
+			},
+			{
+				Id: "things_9_18",
+				Up: []string{
+					`ALTER TABLE connections ADD COLUMN IF NOT EXISTS can_publish BOOLEAN NOT NULL DEFAULT TRUE`,
+					`ALTER TABLE connections ADD COLUMN IF NOT EXISTS can_subscribe BOOLEAN NOT NULL DEFAULT TRUE`,
+				},
+				Down: []string{
+					"ALTER TABLE connections DROP COLUMN can_publish",
+					"ALTER TABLE connections DROP COLUMN can_subscribe",
+				},
+			},
+			{
+				Id: "things_9_19",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS subtopic_rules (
+						id         UUID PRIMARY KEY,
+						channel_id UUID NOT NULL,
+						owner      VARCHAR(254) NOT NULL,
+						thing_id   UUID NOT NULL,
+						action     VARCHAR(16) NOT NULL,
+						pattern    VARCHAR(1024) NOT NULL,
+						FOREIGN KEY (channel_id, owner) REFERENCES channels (id, owner) ON DELETE CASCADE ON UPDATE CASCADE,
+						FOREIGN KEY (thing_id, owner) REFERENCES things (id, owner) ON DELETE CASCADE ON UPDATE CASCADE
+					)`,
+					`CREATE INDEX IF NOT EXISTS subtopic_rules_channel_idx ON subtopic_rules (channel_id, owner)`,
+					`CREATE INDEX IF NOT EXISTS subtopic_rules_thing_idx ON subtopic_rules (thing_id)`,
+				},
+				Down: []string{
+					"DROP TABLE subtopic_rules",
+				},
+			},
+			{
+				Id: "things_9_20",
+				Up: []string{
+					`ALTER TABLE things ADD COLUMN IF NOT EXISTS external_id VARCHAR(254) NOT NULL DEFAULT ''`,
+					`CREATE UNIQUE INDEX IF NOT EXISTS things_owner_external_id_idx ON things (owner, external_id) WHERE external_id <> '' AND state <> 'deleted'`,
+				},
+				Down: []string{
+					"DROP INDEX things_owner_external_id_idx",
+					"ALTER TABLE things DROP COLUMN external_id",
+				},
+			},
+			{
+				Id: "things_9_21",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS health (
+						thing_id UUID PRIMARY KEY,
+						owner    VARCHAR(254) NOT NULL,
+						battery  DOUBLE PRECISION,
+						rssi     DOUBLE PRECISION,
+						firmware VARCHAR(64) NOT NULL DEFAULT '',
+						reported TIMESTAMP NOT NULL,
+						FOREIGN KEY (thing_id, owner) REFERENCES things (id, owner) ON DELETE CASCADE ON UPDATE CASCADE
+					)`,
+					`CREATE INDEX IF NOT EXISTS health_owner_battery_idx ON health (owner, battery)`,
+				},
+				Down: []string{
+					"DROP TABLE health",
+				},
+			},
+			{
+				Id: "things_9_22",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS channel_tokens (
+						id         UUID PRIMARY KEY,
+						channel_id UUID NOT NULL,
+						owner      VARCHAR(254) NOT NULL,
+						token      VARCHAR(4096) UNIQUE NOT NULL,
+						scope      VARCHAR(16) NOT NULL,
+						issued_at  TIMESTAMP NOT NULL,
+						expires_at TIMESTAMP NOT NULL,
+						FOREIGN KEY (channel_id, owner) REFERENCES channels (id, owner) ON DELETE CASCADE ON UPDATE CASCADE
+					)`,
+					`CREATE INDEX IF NOT EXISTS channel_tokens_channel_idx ON channel_tokens (channel_id, owner)`,
+				},
+				Down: []string{
+					"DROP TABLE channel_tokens",
+				},
+			},
 		},
 	}
 
+	if flavor == CockroachDB {
+		compatible(migrations.Migrations)
+	}
+
 	_, err := migrate.Exec(db.DB, "postgres", migrations, migrate.Up)
 	return err
 }
+
+// compatible adapts the migrations to CockroachDB, which doesn't let the
+// columns added within the transaction be indexed before it's committed.
+// The migrations are therefore applied statement by statement, which is
+// safe to resume since all of them are idempotent.
+func compatible(migrations []*migrate.Migration) {
+	for _, m := range migrations {
+		m.DisableTransactionUp = true
+		m.DisableTransactionDown = true
+		for i, stmt := range m.Up {
+			if stmt == dropKeyConstraint {
+				m.Up[i] = dropKeyIndex
+			}
+		}
+	}
+}
+
+// uniqueNames creates or drops the indexes keeping the non-empty names unique
+// per owner. Creating the indexes fails if there already are duplicates.
+func uniqueNames(db *sqlx.DB, unique bool) error {
+	stmts := []string{
+		`DROP INDEX IF EXISTS things_owner_name_idx`,
+		`DROP INDEX IF EXISTS channels_owner_name_idx`,
+	}
+	if unique {
+		stmts = []string{
+			`CREATE UNIQUE INDEX IF NOT EXISTS things_owner_name_idx ON things (owner, name) WHERE name <> '' AND state <> 'deleted'`,
+			`CREATE UNIQUE INDEX IF NOT EXISTS channels_owner_name_idx ON channels (owner, name) WHERE name <> ''`,
+		}
+	}
+
+	for _, stmt := range stmts {
+		if _, err := db.Exec(stmt); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
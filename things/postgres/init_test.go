@@ -0,0 +1,156 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux/things"
+	"github.com/mainflux/mainflux/things/postgres"
+	"github.com/mainflux/mainflux/things/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrateEmptyDatabase(t *testing.T) {
+	_, err := db.Exec("CREATE DATABASE empty_migrations")
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cfg := dbConfig
+	cfg.Name = "empty_migrations"
+	edb, err := postgres.Connect(cfg)
+	require.Nil(t, err, fmt.Sprintf("migrate empty database: got unexpected error: %s", err))
+	defer edb.Close()
+
+	var applied []string
+	err = edb.Select(&applied, "SELECT id FROM gorp_migrations")
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.Contains(t, applied, "things_9_22", fmt.Sprintf("migrate empty database: expected last migration to be applied, got %v\n", applied))
+
+	var idx string
+	err = edb.Get(&idx, "SELECT indexname FROM pg_indexes WHERE indexname = 'things_owner_external_id_idx'")
+	assert.Nil(t, err, fmt.Sprintf("migrate empty database: expected external ID index, got error: %s", err))
+}
+
+func TestUniqueNames(t *testing.T) {
+	_, err := db.Exec("CREATE DATABASE unique_names")
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cfg := dbConfig
+	cfg.Name = "unique_names"
+	cfg.UniqueNames = true
+	udb, err := postgres.Connect(cfg)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	defer udb.Close()
+
+	thingRepo := postgres.NewThingRepository(udb)
+	channelRepo := postgres.NewChannelRepository(udb)
+
+	email := "unique-names@example.com"
+	other := "other-unique-names@example.com"
+
+	newThing := func(owner, name string) things.Thing {
+		id, err := uuid.New().ID()
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		key, err := uuid.New().ID()
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		return things.Thing{ID: id, Owner: owner, Key: key, Name: name}
+	}
+
+	newChannel := func(owner, name string) things.Channel {
+		id, err := uuid.New().ID()
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		return things.Channel{ID: id, Owner: owner, Name: name}
+	}
+
+	thingCases := []struct {
+		desc  string
+		thing things.Thing
+		err   error
+	}{
+		{
+			desc:  "create named thing",
+			thing: newThing(email, "sensor"),
+			err:   nil,
+		},
+		{
+			desc:  "create thing with duplicate name",
+			thing: newThing(email, "sensor"),
+			err:   things.ErrConflict,
+		},
+		{
+			desc:  "create thing with name used by other owner",
+			thing: newThing(other, "sensor"),
+			err:   nil,
+		},
+		{
+			desc:  "create first unnamed thing",
+			thing: newThing(email, ""),
+			err:   nil,
+		},
+		{
+			desc:  "create second unnamed thing",
+			thing: newThing(email, ""),
+			err:   nil,
+		},
+	}
+
+	for _, tc := range thingCases {
+		_, err := thingRepo.Save(context.Background(), tc.thing)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	th := newThing(email, "actuator")
+	_, err = thingRepo.Save(context.Background(), th)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	th.Name = "sensor"
+	err = thingRepo.Update(context.Background(), th)
+	assert.Equal(t, things.ErrConflict, err, fmt.Sprintf("rename thing to duplicate name: expected %s got %s\n", things.ErrConflict, err))
+
+	channelCases := []struct {
+		desc    string
+		channel things.Channel
+		err     error
+	}{
+		{
+			desc:    "create named channel",
+			channel: newChannel(email, "telemetry"),
+			err:     nil,
+		},
+		{
+			desc:    "create channel with duplicate name",
+			channel: newChannel(email, "telemetry"),
+			err:     things.ErrConflict,
+		},
+		{
+			desc:    "create channel with name used by other owner",
+			channel: newChannel(other, "telemetry"),
+			err:     nil,
+		},
+	}
+
+	for _, tc := range channelCases {
+		_, err := channelRepo.Save(context.Background(), tc.channel)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	ch := newChannel(email, "commands")
+	_, err = channelRepo.Save(context.Background(), ch)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	_, err = channelRepo.Patch(context.Background(), email, ch.ID, map[string]interface{}{"name": "telemetry"})
+	assert.Equal(t, things.ErrConflict, err, fmt.Sprintf("rename channel to duplicate name: expected %s got %s\n", things.ErrConflict, err))
+}
+
+func TestUnsupportedFlavor(t *testing.T) {
+	cfg := dbConfig
+	cfg.Flavor = "mysql"
+	_, err := postgres.Connect(cfg)
+	assert.Equal(t, postgres.ErrUnsupportedFlavor, err, fmt.Sprintf("expected %s got %s\n", postgres.ErrUnsupportedFlavor, err))
+}
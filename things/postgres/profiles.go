@@ -0,0 +1,215 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/mainflux/mainflux/things"
+)
+
+var _ things.ProfileRepository = (*profileRepository)(nil)
+
+type profileRepository struct {
+	db *sqlx.DB
+}
+
+// NewProfileRepository instantiates a PostgreSQL implementation of profile
+// repository.
+func NewProfileRepository(db *sqlx.DB) things.ProfileRepository {
+	return &profileRepository{
+		db: db,
+	}
+}
+
+func (pr profileRepository) Save(ctx context.Context, profile things.Profile) (string, error) {
+	q := `INSERT INTO profiles (id, owner, name, metadata, channels, bootstrap)
+	      VALUES (:id, :owner, :name, :metadata, :channels, :bootstrap);`
+
+	dbp, err := toDBProfile(profile)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := pr.db.NamedExecContext(ctx, q, dbp); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok {
+			switch pqErr.Code.Name() {
+			case errInvalid, errTruncation:
+				return "", things.ErrMalformedEntity
+			case errDuplicate:
+				return "", things.ErrConflict
+			}
+		}
+
+		return "", err
+	}
+
+	return profile.ID, nil
+}
+
+func (pr profileRepository) Update(ctx context.Context, profile things.Profile) error {
+	q := `UPDATE profiles SET name = :name, metadata = :metadata, channels = :channels, bootstrap = :bootstrap
+	      WHERE owner = :owner AND id = :id;`
+
+	dbp, err := toDBProfile(profile)
+	if err != nil {
+		return err
+	}
+
+	res, err := pr.db.NamedExecContext(ctx, q, dbp)
+	if err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok {
+			switch pqErr.Code.Name() {
+			case errInvalid:
+				return things.ErrNotFound
+			case errTruncation:
+				return things.ErrMalformedEntity
+			}
+		}
+
+		return err
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if cnt == 0 {
+		return things.ErrNotFound
+	}
+
+	return nil
+}
+
+func (pr profileRepository) RetrieveByID(ctx context.Context, owner, id string) (things.Profile, error) {
+	q := `SELECT name, metadata, channels, bootstrap FROM profiles WHERE id = $1 AND owner = $2;`
+
+	dbp := dbProfile{
+		ID:    id,
+		Owner: owner,
+	}
+	if err := pr.db.QueryRowxContext(ctx, q, id, owner).StructScan(&dbp); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if err == sql.ErrNoRows || ok && errInvalid == pqErr.Code.Name() {
+			return things.Profile{}, things.ErrNotFound
+		}
+		return things.Profile{}, err
+	}
+
+	return toProfile(dbp)
+}
+
+func (pr profileRepository) RetrieveAll(ctx context.Context, owner string, offset, limit uint64) (things.ProfilesPage, error) {
+	q := `SELECT id, name, metadata, channels, bootstrap FROM profiles
+	      WHERE owner = :owner ORDER BY id LIMIT :limit OFFSET :offset;`
+
+	params := map[string]interface{}{
+		"owner":  owner,
+		"limit":  limit,
+		"offset": offset,
+	}
+
+	rows, err := pr.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return things.ProfilesPage{}, err
+	}
+	defer rows.Close()
+
+	items := []things.Profile{}
+	for rows.Next() {
+		dbp := dbProfile{Owner: owner}
+		if err := rows.StructScan(&dbp); err != nil {
+			return things.ProfilesPage{}, err
+		}
+
+		p, err := toProfile(dbp)
+		if err != nil {
+			return things.ProfilesPage{}, err
+		}
+
+		items = append(items, p)
+	}
+
+	cq := `SELECT COUNT(*) FROM profiles WHERE owner = :owner;`
+	total, err := total(ctx, pr.db, cq, params)
+	if err != nil {
+		return things.ProfilesPage{}, err
+	}
+
+	return things.ProfilesPage{
+		Profiles: items,
+		PageMetadata: things.PageMetadata{
+			Total:  total,
+			Offset: offset,
+			Limit:  limit,
+		},
+	}, nil
+}
+
+func (pr profileRepository) Remove(ctx context.Context, owner, id string) error {
+	dbp := dbProfile{
+		ID:    id,
+		Owner: owner,
+	}
+	q := `DELETE FROM profiles WHERE id = :id AND owner = :owner`
+	pr.db.NamedExecContext(ctx, q, dbp)
+	return nil
+}
+
+type dbProfile struct {
+	ID        string         `db:"id"`
+	Owner     string         `db:"owner"`
+	Name      string         `db:"name"`
+	Metadata  string         `db:"metadata"`
+	Channels  pq.StringArray `db:"channels"`
+	Bootstrap string         `db:"bootstrap"`
+}
+
+func toDBProfile(p things.Profile) (dbProfile, error) {
+	data, err := json.Marshal(p.Metadata)
+	if err != nil {
+		return dbProfile{}, err
+	}
+
+	channels := p.Channels
+	if channels == nil {
+		channels = []string{}
+	}
+
+	return dbProfile{
+		ID:        p.ID,
+		Owner:     p.Owner,
+		Name:      p.Name,
+		Metadata:  string(data),
+		Channels:  pq.StringArray(channels),
+		Bootstrap: p.Bootstrap,
+	}, nil
+}
+
+func toProfile(dbp dbProfile) (things.Profile, error) {
+	var metadata map[string]interface{}
+	if err := json.Unmarshal([]byte(dbp.Metadata), &metadata); err != nil {
+		return things.Profile{}, err
+	}
+
+	return things.Profile{
+		ID:        dbp.ID,
+		Owner:     dbp.Owner,
+		Name:      dbp.Name,
+		Metadata:  metadata,
+		Channels:  []string(dbp.Channels),
+		Bootstrap: dbp.Bootstrap,
+	}, nil
+}
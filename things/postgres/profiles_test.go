@@ -0,0 +1,171 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux/things"
+	"github.com/mainflux/mainflux/things/postgres"
+	"github.com/mainflux/mainflux/things/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfileSave(t *testing.T) {
+	email := "profile-save@example.com"
+	profileRepo := postgres.NewProfileRepository(db)
+
+	id, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := []struct {
+		desc    string
+		profile things.Profile
+		err     error
+	}{
+		{
+			desc:    "create valid profile",
+			profile: things.Profile{ID: id, Owner: email, Name: "sensor", Channels: []string{"1"}},
+			err:     nil,
+		},
+		{
+			desc:    "create existing profile",
+			profile: things.Profile{ID: id, Owner: email},
+			err:     things.ErrConflict,
+		},
+		{
+			desc:    "create profile with invalid ID",
+			profile: things.Profile{ID: "invalid", Owner: email},
+			err:     things.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := profileRepo.Save(context.Background(), tc.profile)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestProfileUpdate(t *testing.T) {
+	email := "profile-update@example.com"
+	profileRepo := postgres.NewProfileRepository(db)
+
+	id, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	_, err = profileRepo.Save(context.Background(), things.Profile{ID: id, Owner: email})
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := []struct {
+		desc    string
+		profile things.Profile
+		err     error
+	}{
+		{
+			desc:    "update existing profile",
+			profile: things.Profile{ID: id, Owner: email, Name: "updated", Bootstrap: "config"},
+			err:     nil,
+		},
+		{
+			desc:    "update profile owned by other user",
+			profile: things.Profile{ID: id, Owner: "other@example.com"},
+			err:     things.ErrNotFound,
+		},
+		{
+			desc:    "update profile with invalid ID",
+			profile: things.Profile{ID: wrongID, Owner: email},
+			err:     things.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := profileRepo.Update(context.Background(), tc.profile)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	p, err := profileRepo.RetrieveByID(context.Background(), email, id)
+	assert.Nil(t, err, fmt.Sprintf("retrieve profile: unexpected error %s", err))
+	assert.Equal(t, "config", p.Bootstrap, fmt.Sprintf("retrieve profile: expected %s got %s\n", "config", p.Bootstrap))
+}
+
+func TestProfileRetrieval(t *testing.T) {
+	email := "profile-retrieval@example.com"
+	profileRepo := postgres.NewProfileRepository(db)
+
+	n := uint64(5)
+	ids := []string{}
+	for i := uint64(0); i < n; i++ {
+		id, err := uuid.New().ID()
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		_, err = profileRepo.Save(context.Background(), things.Profile{ID: id, Owner: email, Channels: []string{id}})
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		ids = append(ids, id)
+	}
+
+	p, err := profileRepo.RetrieveByID(context.Background(), email, ids[0])
+	assert.Nil(t, err, fmt.Sprintf("retrieve profile: unexpected error %s", err))
+	assert.Equal(t, []string{ids[0]}, p.Channels, fmt.Sprintf("retrieve profile: expected %v got %v\n", []string{ids[0]}, p.Channels))
+
+	_, err = profileRepo.RetrieveByID(context.Background(), email, wrongID)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("retrieve profile with invalid ID: expected %s got %s\n", things.ErrNotFound, err))
+
+	cases := []struct {
+		desc   string
+		owner  string
+		offset uint64
+		limit  uint64
+		size   uint64
+	}{
+		{
+			desc:  "retrieve all profiles",
+			owner: email,
+			limit: n + 1,
+			size:  n,
+		},
+		{
+			desc:   "retrieve subset of profiles",
+			owner:  email,
+			offset: 1,
+			limit:  2,
+			size:   2,
+		},
+		{
+			desc:  "retrieve profiles owned by other user",
+			owner: "other@example.com",
+			limit: n,
+			size:  0,
+		},
+	}
+
+	for _, tc := range cases {
+		page, err := profileRepo.RetrieveAll(context.Background(), tc.owner, tc.offset, tc.limit)
+		size := uint64(len(page.Profiles))
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.size, size, fmt.Sprintf("%s: expected %d got %d\n", tc.desc, tc.size, size))
+	}
+}
+
+func TestProfileRemove(t *testing.T) {
+	email := "profile-remove@example.com"
+	profileRepo := postgres.NewProfileRepository(db)
+
+	id, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	_, err = profileRepo.Save(context.Background(), things.Profile{ID: id, Owner: email})
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	for i := 0; i < 2; i++ {
+		err := profileRepo.Remove(context.Background(), email, id)
+		assert.Nil(t, err, fmt.Sprintf("#%d: failed to remove profile due to: %s", i, err))
+
+		_, err = profileRepo.RetrieveByID(context.Background(), email, id)
+		assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("#%d: expected %s got %s", i, things.ErrNotFound, err))
+	}
+}
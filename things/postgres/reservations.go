@@ -0,0 +1,109 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq" // required for DB access
+	mfpostgres "github.com/mainflux/mainflux/postgres"
+	"github.com/mainflux/mainflux/things"
+)
+
+var _ things.ReservationRepository = (*reservationRepository)(nil)
+
+type reservationRepository struct {
+	db *sqlx.DB
+}
+
+// NewReservationRepository instantiates a PostgreSQL implementation of
+// reservation repository.
+func NewReservationRepository(db *sqlx.DB) things.ReservationRepository {
+	return &reservationRepository{
+		db: db,
+	}
+}
+
+func (rr reservationRepository) Save(ctx context.Context, reservations ...things.Reservation) error {
+	q := `INSERT INTO reservations (id, owner, key) VALUES (:id, :owner, :key);`
+
+	return mfpostgres.Transact(ctx, rr.db, func(tx *sqlx.Tx) error {
+		for _, r := range reservations {
+			if _, err := tx.NamedExecContext(ctx, q, toDBReservation(r)); err != nil {
+				pqErr, ok := err.(*pq.Error)
+				if ok {
+					switch pqErr.Code.Name() {
+					case errInvalid, errTruncation:
+						return things.ErrMalformedEntity
+					case errDuplicate:
+						return things.ErrConflict
+					}
+				}
+
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (rr reservationRepository) RetrieveByKey(ctx context.Context, owner, key string) (things.Reservation, error) {
+	q := `SELECT id, owner, key FROM reservations WHERE key = $1 AND owner = $2;`
+
+	var dbr dbReservation
+	if err := rr.db.QueryRowxContext(ctx, q, key, owner).StructScan(&dbr); err != nil {
+		if err == sql.ErrNoRows {
+			return things.Reservation{}, things.ErrNotFound
+		}
+
+		return things.Reservation{}, err
+	}
+
+	return toReservation(dbr), nil
+}
+
+func (rr reservationRepository) Remove(ctx context.Context, owner, id string) error {
+	dbr := dbReservation{
+		ID:    id,
+		Owner: owner,
+	}
+	q := `DELETE FROM reservations WHERE id = :id AND owner = :owner;`
+	_, err := rr.db.NamedExecContext(ctx, q, dbr)
+	return err
+}
+
+func (rr reservationRepository) RemoveAll(ctx context.Context, owner string) error {
+	q := `DELETE FROM reservations WHERE owner = $1;`
+	_, err := rr.db.ExecContext(ctx, q, owner)
+	return err
+}
+
+type dbReservation struct {
+	ID    string `db:"id"`
+	Owner string `db:"owner"`
+	Key   string `db:"key"`
+}
+
+func toDBReservation(r things.Reservation) dbReservation {
+	return dbReservation{
+		ID:    r.ID,
+		Owner: r.Owner,
+		Key:   r.Key,
+	}
+}
+
+func toReservation(dbr dbReservation) things.Reservation {
+	return things.Reservation{
+		ID:    dbr.ID,
+		Owner: dbr.Owner,
+		Key:   dbr.Key,
+	}
+}
@@ -0,0 +1,167 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux/things"
+	"github.com/mainflux/mainflux/things/postgres"
+	"github.com/mainflux/mainflux/things/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReservationSave(t *testing.T) {
+	reservationRepo := postgres.NewReservationRepository(db)
+
+	email := "reservation-save@example.com"
+
+	id, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	key, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	reservation := things.Reservation{
+		ID:    id,
+		Owner: email,
+		Key:   key,
+	}
+
+	cases := []struct {
+		desc        string
+		reservation things.Reservation
+		err         error
+	}{
+		{
+			desc:        "save new reservation",
+			reservation: reservation,
+			err:         nil,
+		},
+		{
+			desc:        "save reservation with conflicting key",
+			reservation: reservation,
+			err:         things.ErrConflict,
+		},
+		{
+			desc: "save reservation with invalid ID",
+			reservation: things.Reservation{
+				ID:    "invalid",
+				Owner: email,
+				Key:   wrongValue,
+			},
+			err: things.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		err := reservationRepo.Save(context.Background(), tc.reservation)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestReservationRetrieveByKey(t *testing.T) {
+	reservationRepo := postgres.NewReservationRepository(db)
+
+	email := "reservation-retrieve-by-key@example.com"
+
+	id, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	key, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	reservation := things.Reservation{
+		ID:    id,
+		Owner: email,
+		Key:   key,
+	}
+	err = reservationRepo.Save(context.Background(), reservation)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := map[string]struct {
+		owner string
+		key   string
+		err   error
+	}{
+		"retrieve reservation by key": {
+			owner: email,
+			key:   key,
+			err:   nil,
+		},
+		"retrieve reservation with wrong owner": {
+			owner: wrongValue,
+			key:   key,
+			err:   things.ErrNotFound,
+		},
+		"retrieve reservation with non-existing key": {
+			owner: email,
+			key:   wrongValue,
+			err:   things.ErrNotFound,
+		},
+	}
+
+	for desc, tc := range cases {
+		_, err := reservationRepo.RetrieveByKey(context.Background(), tc.owner, tc.key)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
+func TestReservationRemove(t *testing.T) {
+	reservationRepo := postgres.NewReservationRepository(db)
+
+	email := "reservation-remove@example.com"
+
+	id, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	key, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	reservation := things.Reservation{
+		ID:    id,
+		Owner: email,
+		Key:   key,
+	}
+	err = reservationRepo.Save(context.Background(), reservation)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	err = reservationRepo.Remove(context.Background(), email, id)
+	assert.Nil(t, err, fmt.Sprintf("remove reservation: got unexpected error: %s", err))
+
+	_, err = reservationRepo.RetrieveByKey(context.Background(), email, key)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("retrieve removed reservation: expected %s got %s", things.ErrNotFound, err))
+}
+
+func TestReservationConsume(t *testing.T) {
+	reservationRepo := postgres.NewReservationRepository(db)
+	thingRepo := postgres.NewThingRepository(db)
+
+	email := "reservation-consume@example.com"
+
+	rs := []things.Reservation{}
+	for i := 0; i < 2; i++ {
+		id, err := uuid.New().ID()
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		key, err := uuid.New().ID()
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		rs = append(rs, things.Reservation{ID: id, Owner: email, Key: key})
+	}
+	err := reservationRepo.Save(context.Background(), rs...)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	_, err = thingRepo.Save(context.Background(), things.Thing{ID: rs[0].ID, Owner: email, Key: rs[0].Key})
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	_, err = thingRepo.SaveAll(context.Background(), things.Thing{ID: rs[1].ID, Owner: email, Key: rs[1].Key})
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	for _, r := range rs {
+		_, err := reservationRepo.RetrieveByKey(context.Background(), email, r.Key)
+		assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("retrieve consumed reservation: expected %s got %s", things.ErrNotFound, err))
+	}
+}
@@ -0,0 +1,115 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq" // required for DB access
+	"github.com/mainflux/mainflux/things"
+)
+
+var _ things.RevocationRepository = (*revocationRepository)(nil)
+
+type revocationRepository struct {
+	db *sqlx.DB
+}
+
+// NewRevocationRepository instantiates a PostgreSQL implementation of
+// revocation repository.
+func NewRevocationRepository(db *sqlx.DB) things.RevocationRepository {
+	return &revocationRepository{
+		db: db,
+	}
+}
+
+func (rr revocationRepository) Save(ctx context.Context, r things.Revocation) error {
+	q := `INSERT INTO revocations (thing_id, version, expires_at) VALUES (:thing_id, :version, :expires_at)
+	      ON CONFLICT (thing_id) DO UPDATE SET version = :version, expires_at = :expires_at;`
+
+	if _, err := rr.db.NamedExecContext(ctx, q, toDBRevocation(r)); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok {
+			switch pqErr.Code.Name() {
+			case errInvalid, errTruncation:
+				return things.ErrMalformedEntity
+			}
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (rr revocationRepository) RetrieveByThing(ctx context.Context, id string) (things.Revocation, error) {
+	q := `SELECT thing_id, version, expires_at FROM revocations WHERE thing_id = $1;`
+
+	var dbr dbRevocation
+	if err := rr.db.QueryRowxContext(ctx, q, id).StructScan(&dbr); err != nil {
+		if err == sql.ErrNoRows {
+			return things.Revocation{}, things.ErrNotFound
+		}
+
+		pqErr, ok := err.(*pq.Error)
+		if ok && errInvalid == pqErr.Code.Name() {
+			return things.Revocation{}, things.ErrNotFound
+		}
+
+		return things.Revocation{}, err
+	}
+
+	return toRevocation(dbr), nil
+}
+
+func (rr revocationRepository) RetrieveAll(ctx context.Context, now time.Time) ([]things.Revocation, error) {
+	q := `SELECT thing_id, version, expires_at FROM revocations WHERE expires_at > $1;`
+
+	rows, err := rr.db.QueryxContext(ctx, q, now.UTC())
+	if err != nil {
+		return []things.Revocation{}, err
+	}
+	defer rows.Close()
+
+	revs := []things.Revocation{}
+	for rows.Next() {
+		var dbr dbRevocation
+		if err := rows.StructScan(&dbr); err != nil {
+			return []things.Revocation{}, err
+		}
+
+		revs = append(revs, toRevocation(dbr))
+	}
+
+	return revs, nil
+}
+
+type dbRevocation struct {
+	ThingID   string    `db:"thing_id"`
+	Version   uint64    `db:"version"`
+	ExpiresAt time.Time `db:"expires_at"`
+}
+
+func toDBRevocation(r things.Revocation) dbRevocation {
+	return dbRevocation{
+		ThingID:   r.ThingID,
+		Version:   r.Version,
+		ExpiresAt: r.ExpiresAt.UTC(),
+	}
+}
+
+func toRevocation(dbr dbRevocation) things.Revocation {
+	return things.Revocation{
+		ThingID:   dbr.ThingID,
+		Version:   dbr.Version,
+		ExpiresAt: dbr.ExpiresAt.UTC(),
+	}
+}
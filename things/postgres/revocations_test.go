@@ -0,0 +1,94 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/things"
+	"github.com/mainflux/mainflux/things/postgres"
+	"github.com/mainflux/mainflux/things/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRevocationSave(t *testing.T) {
+	revocationRepo := postgres.NewRevocationRepository(db)
+
+	id, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	exp := time.Now().UTC().Truncate(time.Second).Add(time.Hour)
+
+	cases := []struct {
+		desc       string
+		revocation things.Revocation
+		err        error
+	}{
+		{
+			desc:       "save new revocation",
+			revocation: things.Revocation{ThingID: id, Version: 1, ExpiresAt: exp},
+			err:        nil,
+		},
+		{
+			desc:       "save revocation of already revoked thing",
+			revocation: things.Revocation{ThingID: id, Version: 2, ExpiresAt: exp},
+			err:        nil,
+		},
+		{
+			desc:       "save revocation with invalid thing ID",
+			revocation: things.Revocation{ThingID: "invalid", Version: 1, ExpiresAt: exp},
+			err:        things.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		err := revocationRepo.Save(context.Background(), tc.revocation)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	r, err := revocationRepo.RetrieveByThing(context.Background(), id)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.Equal(t, uint64(2), r.Version, fmt.Sprintf("expected latest revocation version %d got %d\n", 2, r.Version))
+}
+
+func TestRevocationRetrieveAll(t *testing.T) {
+	revocationRepo := postgres.NewRevocationRepository(db)
+
+	now := time.Now().UTC().Truncate(time.Second)
+
+	active, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	expired, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	err = revocationRepo.Save(context.Background(), things.Revocation{ThingID: active, Version: 1, ExpiresAt: now.Add(time.Hour)})
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	err = revocationRepo.Save(context.Background(), things.Revocation{ThingID: expired, Version: 1, ExpiresAt: now.Add(-time.Minute)})
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	revs, err := revocationRepo.RetrieveAll(context.Background(), now)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	ids := map[string]bool{}
+	for _, r := range revs {
+		ids[r.ThingID] = true
+	}
+	assert.True(t, ids[active], "expected revocation in effect to be retrieved")
+	assert.False(t, ids[expired], "expected expired revocation not to be retrieved")
+
+	r, err := revocationRepo.RetrieveByThing(context.Background(), expired)
+	assert.Nil(t, err, fmt.Sprintf("retrieve expired revocation: unexpected error %s\n", err))
+	assert.Equal(t, uint64(1), r.Version, fmt.Sprintf("retrieve expired revocation: expected version %d got %d\n", 1, r.Version))
+
+	_, err = revocationRepo.RetrieveByThing(context.Background(), wrongID)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("retrieve revocation with invalid ID: expected %s got %s\n", things.ErrNotFound, err))
+}
@@ -29,6 +29,7 @@ const (
 var (
 	testLog, _ = logger.New(os.Stdout, logger.Info.String())
 	db         *sqlx.DB
+	dbConfig   postgres.Config
 )
 
 func TestMain(m *testing.M) {
@@ -60,7 +61,7 @@ func TestMain(m *testing.M) {
 		log.Fatalf("Could not connect to docker: %s", err)
 	}
 
-	dbConfig := postgres.Config{
+	dbConfig = postgres.Config{
 		Host:        "localhost",
 		Port:        port,
 		User:        "test",
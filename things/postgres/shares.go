@@ -0,0 +1,154 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/mainflux/mainflux/things"
+)
+
+var _ things.ShareRepository = (*shareRepository)(nil)
+
+type shareRepository struct {
+	db *sqlx.DB
+}
+
+// NewShareRepository instantiates a PostgreSQL implementation of shares
+// repository.
+func NewShareRepository(db *sqlx.DB) things.ShareRepository {
+	return &shareRepository{
+		db: db,
+	}
+}
+
+func (sr shareRepository) Save(ctx context.Context, share things.Share) error {
+	q := `INSERT INTO shares (kind, entity_id, owner, user_id, permission)
+	      VALUES (:kind, :entity_id, :owner, :user_id, :permission)
+	      ON CONFLICT (kind, entity_id, user_id) DO UPDATE SET permission = :permission;`
+
+	if _, err := sr.db.NamedExecContext(ctx, q, toDBShare(share)); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok {
+			switch pqErr.Code.Name() {
+			case errInvalid, errTruncation:
+				return things.ErrMalformedEntity
+			}
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (sr shareRepository) Retrieve(ctx context.Context, kind, id, user string) (things.Share, error) {
+	// Verify if UUID format is valid to avoid internal Postgres error
+	if _, err := uuid.FromString(id); err != nil {
+		return things.Share{}, things.ErrNotFound
+	}
+
+	q := `SELECT kind, entity_id, owner, user_id, permission FROM shares
+	      WHERE kind = $1 AND entity_id = $2 AND user_id = $3;`
+
+	var dbs dbShare
+	if err := sr.db.QueryRowxContext(ctx, q, kind, id, user).StructScan(&dbs); err != nil {
+		if err == sql.ErrNoRows {
+			return things.Share{}, things.ErrNotFound
+		}
+		return things.Share{}, err
+	}
+
+	return toShare(dbs), nil
+}
+
+func (sr shareRepository) RetrieveAll(ctx context.Context, owner, kind, id string) ([]things.Share, error) {
+	if _, err := uuid.FromString(id); err != nil {
+		return []things.Share{}, nil
+	}
+
+	q := `SELECT kind, entity_id, owner, user_id, permission FROM shares
+	      WHERE owner = $1 AND kind = $2 AND entity_id = $3 ORDER BY user_id;`
+
+	return sr.retrieve(ctx, q, owner, kind, id)
+}
+
+func (sr shareRepository) RetrieveByUser(ctx context.Context, user, kind string) ([]things.Share, error) {
+	q := `SELECT kind, entity_id, owner, user_id, permission FROM shares
+	      WHERE user_id = $1 AND kind = $2 ORDER BY entity_id;`
+
+	return sr.retrieve(ctx, q, user, kind)
+}
+
+func (sr shareRepository) Remove(ctx context.Context, owner, kind, id, user string) error {
+	if _, err := uuid.FromString(id); err != nil {
+		return nil
+	}
+
+	q := `DELETE FROM shares WHERE owner = $1 AND kind = $2 AND entity_id = $3 AND user_id = $4;`
+	_, err := sr.db.ExecContext(ctx, q, owner, kind, id, user)
+	return err
+}
+
+func (sr shareRepository) RemoveByUser(ctx context.Context, user string) error {
+	q := `DELETE FROM shares WHERE owner = $1 OR user_id = $1;`
+	_, err := sr.db.ExecContext(ctx, q, user)
+	return err
+}
+
+func (sr shareRepository) retrieve(ctx context.Context, q string, args ...interface{}) ([]things.Share, error) {
+	rows, err := sr.db.QueryxContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []things.Share{}
+	for rows.Next() {
+		var dbs dbShare
+		if err := rows.StructScan(&dbs); err != nil {
+			return nil, err
+		}
+
+		items = append(items, toShare(dbs))
+	}
+
+	return items, nil
+}
+
+type dbShare struct {
+	Kind       string `db:"kind"`
+	EntityID   string `db:"entity_id"`
+	Owner      string `db:"owner"`
+	User       string `db:"user_id"`
+	Permission string `db:"permission"`
+}
+
+func toDBShare(s things.Share) dbShare {
+	return dbShare{
+		Kind:       s.Kind,
+		EntityID:   s.EntityID,
+		Owner:      s.Owner,
+		User:       s.User,
+		Permission: string(s.Permission),
+	}
+}
+
+func toShare(dbs dbShare) things.Share {
+	return things.Share{
+		Kind:       dbs.Kind,
+		EntityID:   dbs.EntityID,
+		Owner:      dbs.Owner,
+		User:       dbs.User,
+		Permission: things.Permission(dbs.Permission),
+	}
+}
@@ -0,0 +1,100 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux/things"
+	"github.com/mainflux/mainflux/things/postgres"
+	"github.com/mainflux/mainflux/things/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShareSave(t *testing.T) {
+	email := "share-save@example.com"
+	user := "share-save-user@example.com"
+	shareRepo := postgres.NewShareRepository(db)
+
+	id, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		share things.Share
+		err   error
+	}{
+		{
+			desc:  "save share",
+			share: things.Share{Kind: things.ThingKind, EntityID: id, Owner: email, User: user, Permission: things.ReadPermission},
+			err:   nil,
+		},
+		{
+			desc:  "save existing share",
+			share: things.Share{Kind: things.ThingKind, EntityID: id, Owner: email, User: user, Permission: things.ManagePermission},
+			err:   nil,
+		},
+		{
+			desc:  "save share with invalid entity ID",
+			share: things.Share{Kind: things.ThingKind, EntityID: wrongValue, Owner: email, User: user, Permission: things.ReadPermission},
+			err:   things.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		err := shareRepo.Save(context.Background(), tc.share)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	share, err := shareRepo.Retrieve(context.Background(), things.ThingKind, id, user)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.Equal(t, things.ManagePermission, share.Permission, fmt.Sprintf("retrieve share: expected updated permission got %s\n", share.Permission))
+
+	_, err = shareRepo.Retrieve(context.Background(), things.ChannelKind, id, user)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("retrieve share of other kind: expected %s got %s\n", things.ErrNotFound, err))
+}
+
+func TestShareRetrieval(t *testing.T) {
+	email := "share-retrieval@example.com"
+	user := "share-retrieval-user@example.com"
+	shareRepo := postgres.NewShareRepository(db)
+
+	n := 5
+	id := ""
+	for i := 0; i < n; i++ {
+		eid, err := uuid.New().ID()
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		id = eid
+
+		share := things.Share{Kind: things.ChannelKind, EntityID: eid, Owner: email, User: user, Permission: things.ReadPermission}
+		err = shareRepo.Save(context.Background(), share)
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	}
+
+	shares, err := shareRepo.RetrieveByUser(context.Background(), user, things.ChannelKind)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.Len(t, shares, n, fmt.Sprintf("retrieve by user: expected %d shares got %d\n", n, len(shares)))
+
+	shares, err = shareRepo.RetrieveAll(context.Background(), email, things.ChannelKind, id)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.Len(t, shares, 1, fmt.Sprintf("retrieve all: expected 1 share got %d\n", len(shares)))
+
+	err = shareRepo.Remove(context.Background(), email, things.ChannelKind, id, user)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	_, err = shareRepo.Retrieve(context.Background(), things.ChannelKind, id, user)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("retrieve removed share: expected %s got %s\n", things.ErrNotFound, err))
+
+	err = shareRepo.RemoveByUser(context.Background(), user)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	shares, err = shareRepo.RetrieveByUser(context.Background(), user, things.ChannelKind)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.Empty(t, shares, fmt.Sprintf("retrieve by user after removal: expected none got %v\n", shares))
+}
@@ -0,0 +1,154 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package postgres
+
+import (
+	"context"
+
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/mainflux/mainflux/things"
+)
+
+var _ things.SubtopicRuleRepository = (*subtopicRuleRepository)(nil)
+
+type subtopicRuleRepository struct {
+	db *sqlx.DB
+}
+
+// NewSubtopicRuleRepository instantiates a PostgreSQL implementation of
+// subtopic rules repository.
+func NewSubtopicRuleRepository(db *sqlx.DB) things.SubtopicRuleRepository {
+	return &subtopicRuleRepository{
+		db: db,
+	}
+}
+
+func (srr subtopicRuleRepository) Save(ctx context.Context, rule things.SubtopicRule) (string, error) {
+	q := `INSERT INTO subtopic_rules (id, channel_id, owner, thing_id, action, pattern)
+	      VALUES (:id, :channel_id, :owner, :thing_id, :action, :pattern);`
+
+	if _, err := srr.db.NamedExecContext(ctx, q, toDBSubtopicRule(rule)); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok {
+			switch pqErr.Code.Name() {
+			case errInvalid, errTruncation:
+				return "", things.ErrMalformedEntity
+			case errDuplicate:
+				return "", things.ErrConflict
+			case errFK:
+				return "", things.ErrNotFound
+			}
+		}
+
+		return "", err
+	}
+
+	return rule.ID, nil
+}
+
+func (srr subtopicRuleRepository) RetrieveAll(ctx context.Context, owner, chanID string) ([]things.SubtopicRule, error) {
+	// Verify if UUID format is valid to avoid internal Postgres error
+	if _, err := uuid.FromString(chanID); err != nil {
+		return nil, things.ErrNotFound
+	}
+
+	q := `SELECT id, channel_id, owner, thing_id, action, pattern FROM subtopic_rules
+	      WHERE channel_id = $1 AND owner = $2 ORDER BY thing_id, action, pattern;`
+
+	return srr.retrieve(ctx, q, chanID, owner)
+}
+
+func (srr subtopicRuleRepository) RetrieveByThing(ctx context.Context, thingID string) ([]things.SubtopicRule, error) {
+	if _, err := uuid.FromString(thingID); err != nil {
+		return []things.SubtopicRule{}, nil
+	}
+
+	q := `SELECT id, channel_id, owner, thing_id, action, pattern FROM subtopic_rules
+	      WHERE thing_id = $1 ORDER BY channel_id, action, pattern;`
+
+	return srr.retrieve(ctx, q, thingID)
+}
+
+func (srr subtopicRuleRepository) Remove(ctx context.Context, owner, chanID, id string) error {
+	for _, v := range []string{chanID, id} {
+		if _, err := uuid.FromString(v); err != nil {
+			return things.ErrNotFound
+		}
+	}
+
+	q := `DELETE FROM subtopic_rules WHERE id = $1 AND channel_id = $2 AND owner = $3;`
+
+	res, err := srr.db.ExecContext(ctx, q, id, chanID, owner)
+	if err != nil {
+		return err
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if cnt == 0 {
+		return things.ErrNotFound
+	}
+
+	return nil
+}
+
+func (srr subtopicRuleRepository) retrieve(ctx context.Context, q string, args ...interface{}) ([]things.SubtopicRule, error) {
+	rows, err := srr.db.QueryxContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []things.SubtopicRule{}
+	for rows.Next() {
+		var dbr dbSubtopicRule
+		if err := rows.StructScan(&dbr); err != nil {
+			return nil, err
+		}
+
+		items = append(items, toSubtopicRule(dbr))
+	}
+
+	return items, nil
+}
+
+type dbSubtopicRule struct {
+	ID      string `db:"id"`
+	Channel string `db:"channel_id"`
+	Owner   string `db:"owner"`
+	ThingID string `db:"thing_id"`
+	Action  string `db:"action"`
+	Pattern string `db:"pattern"`
+}
+
+func toDBSubtopicRule(r things.SubtopicRule) dbSubtopicRule {
+	return dbSubtopicRule{
+		ID:      r.ID,
+		Channel: r.Channel,
+		Owner:   r.Owner,
+		ThingID: r.ThingID,
+		Action:  r.Action,
+		Pattern: r.Pattern,
+	}
+}
+
+func toSubtopicRule(dbr dbSubtopicRule) things.SubtopicRule {
+	return things.SubtopicRule{
+		ID:      dbr.ID,
+		Channel: dbr.Channel,
+		Owner:   dbr.Owner,
+		ThingID: dbr.ThingID,
+		Action:  dbr.Action,
+		Pattern: dbr.Pattern,
+	}
+}
@@ -0,0 +1,149 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/things"
+	"github.com/mainflux/mainflux/things/postgres"
+	"github.com/mainflux/mainflux/things/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSubtopicRule(t *testing.T, chanID, thingID, owner, pattern string) things.SubtopicRule {
+	id, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	return things.SubtopicRule{
+		ID:      id,
+		Channel: chanID,
+		Owner:   owner,
+		ThingID: thingID,
+		Action:  mainflux.ActionPublish,
+		Pattern: pattern,
+	}
+}
+
+func TestSubtopicRuleSave(t *testing.T) {
+	email := "subtopic-rule-save@example.com"
+	thingRepo := postgres.NewThingRepository(db)
+	chanRepo := postgres.NewChannelRepository(db)
+	ruleRepo := postgres.NewSubtopicRuleRepository(db)
+
+	thID := saveThing(t, thingRepo, email)
+	chid, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	chanID, err := chanRepo.Save(context.Background(), things.Channel{ID: chid, Owner: email})
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	missing, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	rule := newSubtopicRule(t, chanID, thID, email, "telemetry.>")
+	conflicting := newSubtopicRule(t, chanID, thID, email, "status")
+	conflicting.ID = rule.ID
+
+	cases := []struct {
+		desc string
+		rule things.SubtopicRule
+		err  error
+	}{
+		{
+			desc: "save subtopic rule",
+			rule: rule,
+			err:  nil,
+		},
+		{
+			desc: "save subtopic rule with conflicting ID",
+			rule: conflicting,
+			err:  things.ErrConflict,
+		},
+		{
+			desc: "save subtopic rule of non-existing channel",
+			rule: newSubtopicRule(t, missing, thID, email, "telemetry.>"),
+			err:  things.ErrNotFound,
+		},
+		{
+			desc: "save subtopic rule of non-existing thing",
+			rule: newSubtopicRule(t, chanID, missing, email, "telemetry.>"),
+			err:  things.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := ruleRepo.Save(context.Background(), tc.rule)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	rules, err := ruleRepo.RetrieveAll(context.Background(), email, chanID)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.Equal(t, []things.SubtopicRule{rule}, rules, fmt.Sprintf("retrieve subtopic rules: expected %v got %v\n", []things.SubtopicRule{rule}, rules))
+
+	rules, err = ruleRepo.RetrieveByThing(context.Background(), thID)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.Equal(t, []things.SubtopicRule{rule}, rules, fmt.Sprintf("retrieve subtopic rules by thing: expected %v got %v\n", []things.SubtopicRule{rule}, rules))
+}
+
+func TestSubtopicRuleRemove(t *testing.T) {
+	email := "subtopic-rule-remove@example.com"
+	thingRepo := postgres.NewThingRepository(db)
+	chanRepo := postgres.NewChannelRepository(db)
+	ruleRepo := postgres.NewSubtopicRuleRepository(db)
+
+	thID := saveThing(t, thingRepo, email)
+	chid, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	chanID, err := chanRepo.Save(context.Background(), things.Channel{ID: chid, Owner: email})
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	rule := newSubtopicRule(t, chanID, thID, email, "telemetry.>")
+	_, err = ruleRepo.Save(context.Background(), rule)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		owner string
+		id    string
+		err   error
+	}{
+		{
+			desc:  "remove subtopic rule owned by other user",
+			owner: "other@example.com",
+			id:    rule.ID,
+			err:   things.ErrNotFound,
+		},
+		{
+			desc:  "remove subtopic rule",
+			owner: email,
+			id:    rule.ID,
+			err:   nil,
+		},
+		{
+			desc:  "remove removed subtopic rule",
+			owner: email,
+			id:    rule.ID,
+			err:   things.ErrNotFound,
+		},
+		{
+			desc:  "remove subtopic rule with malformed ID",
+			owner: email,
+			id:    "malformed",
+			err:   things.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := ruleRepo.Remove(context.Background(), tc.owner, chanID, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
@@ -0,0 +1,140 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/mainflux/mainflux/things"
+)
+
+var _ things.SubtopicRepository = (*subtopicRepository)(nil)
+
+type subtopicRepository struct {
+	db *sqlx.DB
+}
+
+// NewSubtopicRepository instantiates a PostgreSQL implementation of
+// registered subtopics repository.
+func NewSubtopicRepository(db *sqlx.DB) things.SubtopicRepository {
+	return &subtopicRepository{
+		db: db,
+	}
+}
+
+func (sr subtopicRepository) Save(ctx context.Context, subtopic things.Subtopic) error {
+	q := `INSERT INTO subtopics (channel_id, owner, name, description, schema)
+	      VALUES (:channel_id, :owner, :name, :description, :schema)
+	      ON CONFLICT (channel_id, owner, name) DO UPDATE SET description = :description, schema = :schema;`
+
+	dbs, err := toDBSubtopic(subtopic)
+	if err != nil {
+		return things.ErrMalformedEntity
+	}
+
+	if _, err := sr.db.NamedExecContext(ctx, q, dbs); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok {
+			switch pqErr.Code.Name() {
+			case errInvalid, errTruncation:
+				return things.ErrMalformedEntity
+			case errFK:
+				return things.ErrNotFound
+			}
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (sr subtopicRepository) RetrieveAll(ctx context.Context, owner, chanID string) ([]things.Subtopic, error) {
+	// Verify if UUID format is valid to avoid internal Postgres error
+	if _, err := uuid.FromString(chanID); err != nil {
+		return nil, things.ErrNotFound
+	}
+
+	q := `SELECT channel_id, owner, name, description, schema FROM subtopics
+	      WHERE channel_id = $1 AND owner = $2 ORDER BY name;`
+
+	rows, err := sr.db.QueryxContext(ctx, q, chanID, owner)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []things.Subtopic{}
+	for rows.Next() {
+		var dbs dbSubtopic
+		if err := rows.StructScan(&dbs); err != nil {
+			return nil, err
+		}
+
+		st, err := toSubtopic(dbs)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, st)
+	}
+
+	return items, nil
+}
+
+func (sr subtopicRepository) Remove(ctx context.Context, owner, chanID, name string) error {
+	if _, err := uuid.FromString(chanID); err != nil {
+		return nil
+	}
+
+	q := `DELETE FROM subtopics WHERE channel_id = $1 AND owner = $2 AND name = $3;`
+	_, err := sr.db.ExecContext(ctx, q, chanID, owner, name)
+	return err
+}
+
+type dbSubtopic struct {
+	Channel     string `db:"channel_id"`
+	Owner       string `db:"owner"`
+	Name        string `db:"name"`
+	Description string `db:"description"`
+	Schema      string `db:"schema"`
+}
+
+func toDBSubtopic(st things.Subtopic) (dbSubtopic, error) {
+	data, err := json.Marshal(st.Schema)
+	if err != nil {
+		return dbSubtopic{}, err
+	}
+
+	return dbSubtopic{
+		Channel:     st.Channel,
+		Owner:       st.Owner,
+		Name:        st.Name,
+		Description: st.Description,
+		Schema:      string(data),
+	}, nil
+}
+
+func toSubtopic(dbs dbSubtopic) (things.Subtopic, error) {
+	var schema map[string]interface{}
+	if err := json.Unmarshal([]byte(dbs.Schema), &schema); err != nil {
+		return things.Subtopic{}, err
+	}
+
+	return things.Subtopic{
+		Channel:     dbs.Channel,
+		Owner:       dbs.Owner,
+		Name:        dbs.Name,
+		Description: dbs.Description,
+		Schema:      schema,
+	}, nil
+}
@@ -0,0 +1,99 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux/things"
+	"github.com/mainflux/mainflux/things/postgres"
+	"github.com/mainflux/mainflux/things/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubtopicSave(t *testing.T) {
+	email := "subtopic-save@example.com"
+	chanRepo := postgres.NewChannelRepository(db)
+	subtopicRepo := postgres.NewSubtopicRepository(db)
+
+	chid, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	chanID, err := chanRepo.Save(context.Background(), things.Channel{ID: chid, Owner: email})
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	missing, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := []struct {
+		desc     string
+		subtopic things.Subtopic
+		err      error
+	}{
+		{
+			desc:     "save subtopic",
+			subtopic: things.Subtopic{Channel: chanID, Owner: email, Name: "sensors.temp", Description: "Temperature"},
+			err:      nil,
+		},
+		{
+			desc:     "save existing subtopic",
+			subtopic: things.Subtopic{Channel: chanID, Owner: email, Name: "sensors.temp", Description: "Temperature in °C"},
+			err:      nil,
+		},
+		{
+			desc:     "save subtopic of non-existing channel",
+			subtopic: things.Subtopic{Channel: missing, Owner: email, Name: "sensors.temp"},
+			err:      things.ErrNotFound,
+		},
+		{
+			desc:     "save subtopic of channel owned by other user",
+			subtopic: things.Subtopic{Channel: chanID, Owner: "other@example.com", Name: "sensors.temp"},
+			err:      things.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := subtopicRepo.Save(context.Background(), tc.subtopic)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	subtopics, err := subtopicRepo.RetrieveAll(context.Background(), email, chanID)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	require.Len(t, subtopics, 1, fmt.Sprintf("retrieve subtopics: expected 1 subtopic got %d\n", len(subtopics)))
+	assert.Equal(t, "Temperature in °C", subtopics[0].Description, fmt.Sprintf("retrieve subtopics: expected updated description got %s\n", subtopics[0].Description))
+}
+
+func TestSubtopicRemove(t *testing.T) {
+	email := "subtopic-remove@example.com"
+	chanRepo := postgres.NewChannelRepository(db)
+	subtopicRepo := postgres.NewSubtopicRepository(db)
+
+	chid, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	chanID, err := chanRepo.Save(context.Background(), things.Channel{ID: chid, Owner: email})
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	for _, name := range []string{"sensors.temp", "sensors.humidity"} {
+		err := subtopicRepo.Save(context.Background(), things.Subtopic{Channel: chanID, Owner: email, Name: name})
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	}
+
+	err = subtopicRepo.Remove(context.Background(), email, chanID, "sensors.temp")
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	subtopics, err := subtopicRepo.RetrieveAll(context.Background(), email, chanID)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.Len(t, subtopics, 1, fmt.Sprintf("retrieve subtopics after removal: expected 1 subtopic got %d\n", len(subtopics)))
+
+	err = chanRepo.Remove(context.Background(), email, chanID)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	subtopics, err = subtopicRepo.RetrieveAll(context.Background(), email, chanID)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.Empty(t, subtopics, fmt.Sprintf("retrieve subtopics of removed channel: expected none got %d\n", len(subtopics)))
+}
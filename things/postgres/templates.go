@@ -0,0 +1,118 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq" // required for DB access
+	"github.com/mainflux/mainflux/things"
+)
+
+var _ things.TemplateRepository = (*templateRepository)(nil)
+
+type templateRepository struct {
+	db *sqlx.DB
+}
+
+// NewTemplateRepository instantiates a PostgreSQL implementation of
+// provisioning template repository.
+func NewTemplateRepository(db *sqlx.DB) things.TemplateRepository {
+	return &templateRepository{
+		db: db,
+	}
+}
+
+func (tr templateRepository) Save(ctx context.Context, tmpl things.Template) error {
+	q := `INSERT INTO templates (owner, channels) VALUES (:owner, :channels)
+	      ON CONFLICT (owner) DO UPDATE SET channels = :channels;`
+
+	dbt, err := toDBTemplate(tmpl)
+	if err != nil {
+		return things.ErrMalformedEntity
+	}
+
+	if _, err := tr.db.NamedExecContext(ctx, q, dbt); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok {
+			switch pqErr.Code.Name() {
+			case errInvalid, errTruncation:
+				return things.ErrMalformedEntity
+			}
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (tr templateRepository) Retrieve(ctx context.Context, owner string) (things.Template, error) {
+	q := `SELECT owner, channels FROM templates WHERE owner = $1;`
+
+	var dbt dbTemplate
+	if err := tr.db.QueryRowxContext(ctx, q, owner).StructScan(&dbt); err != nil {
+		if err == sql.ErrNoRows {
+			return things.Template{}, things.ErrNotFound
+		}
+
+		return things.Template{}, err
+	}
+
+	return toTemplate(dbt)
+}
+
+func (tr templateRepository) Remove(ctx context.Context, owner string) error {
+	q := `DELETE FROM templates WHERE owner = $1;`
+	_, err := tr.db.ExecContext(ctx, q, owner)
+	return err
+}
+
+type dbTemplate struct {
+	Owner    string `db:"owner"`
+	Channels string `db:"channels"`
+}
+
+type dbChannelTemplate struct {
+	Name     string                 `json:"name,omitempty"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+func toDBTemplate(tmpl things.Template) (dbTemplate, error) {
+	channels := []dbChannelTemplate{}
+	for _, ct := range tmpl.Channels {
+		channels = append(channels, dbChannelTemplate(ct))
+	}
+
+	data, err := json.Marshal(channels)
+	if err != nil {
+		return dbTemplate{}, err
+	}
+
+	return dbTemplate{
+		Owner:    tmpl.Owner,
+		Channels: string(data),
+	}, nil
+}
+
+func toTemplate(dbt dbTemplate) (things.Template, error) {
+	var channels []dbChannelTemplate
+	if err := json.Unmarshal([]byte(dbt.Channels), &channels); err != nil {
+		return things.Template{}, err
+	}
+
+	tmpl := things.Template{Owner: dbt.Owner}
+	for _, ct := range channels {
+		tmpl.Channels = append(tmpl.Channels, things.ChannelTemplate(ct))
+	}
+
+	return tmpl, nil
+}
@@ -0,0 +1,115 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux/things"
+	"github.com/mainflux/mainflux/things/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplateSave(t *testing.T) {
+	templateRepo := postgres.NewTemplateRepository(db)
+
+	email := "template-save@example.com"
+
+	tmpl := things.Template{
+		Owner: email,
+		Channels: []things.ChannelTemplate{
+			{Name: "{name}-data"},
+			{Name: "{name}-control", Metadata: map[string]interface{}{"role": "control"}},
+		},
+	}
+
+	updated := tmpl
+	updated.Channels = tmpl.Channels[:1]
+
+	cases := []struct {
+		desc string
+		tmpl things.Template
+		err  error
+	}{
+		{
+			desc: "save new template",
+			tmpl: tmpl,
+			err:  nil,
+		},
+		{
+			desc: "replace existing template",
+			tmpl: updated,
+			err:  nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := templateRepo.Save(context.Background(), tc.tmpl)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	saved, err := templateRepo.Retrieve(context.Background(), email)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.Equal(t, updated, saved, fmt.Sprintf("expected %v got %v\n", updated, saved))
+}
+
+func TestTemplateRetrieve(t *testing.T) {
+	templateRepo := postgres.NewTemplateRepository(db)
+
+	email := "template-retrieve@example.com"
+
+	tmpl := things.Template{
+		Owner:    email,
+		Channels: []things.ChannelTemplate{{Name: "{id}"}},
+	}
+	err := templateRepo.Save(context.Background(), tmpl)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := map[string]struct {
+		owner string
+		err   error
+	}{
+		"retrieve existing template": {
+			owner: email,
+			err:   nil,
+		},
+		"retrieve template of user without one": {
+			owner: wrongValue,
+			err:   things.ErrNotFound,
+		},
+	}
+
+	for desc, tc := range cases {
+		_, err := templateRepo.Retrieve(context.Background(), tc.owner)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
+func TestTemplateRemove(t *testing.T) {
+	templateRepo := postgres.NewTemplateRepository(db)
+
+	email := "template-remove@example.com"
+
+	tmpl := things.Template{
+		Owner:    email,
+		Channels: []things.ChannelTemplate{{Name: "{id}"}},
+	}
+	err := templateRepo.Save(context.Background(), tmpl)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	for i := 0; i < 2; i++ {
+		err := templateRepo.Remove(context.Background(), email)
+		assert.Nil(t, err, fmt.Sprintf("#%d: failed to remove template due to: %s", i, err))
+
+		_, err = templateRepo.Retrieve(context.Background(), email)
+		assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("#%d: expected %s got %s", i, things.ErrNotFound, err))
+	}
+}
@@ -0,0 +1,201 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/mainflux/mainflux/things"
+)
+
+var _ things.ThingKeyRepository = (*thingKeyRepository)(nil)
+
+type thingKeyRepository struct {
+	db *sqlx.DB
+}
+
+// NewThingKeyRepository instantiates a PostgreSQL implementation of scoped
+// thing keys repository.
+func NewThingKeyRepository(db *sqlx.DB) things.ThingKeyRepository {
+	return &thingKeyRepository{
+		db: db,
+	}
+}
+
+func (tkr thingKeyRepository) Save(ctx context.Context, tk things.ThingKey) (string, error) {
+	// The deleted things are kept, so the thing state is checked explicitly.
+	q := `INSERT INTO thing_keys (id, thing_id, owner, key, scope, issued_at)
+	      SELECT :id, :thing_id, :owner, :key, :scope, :issued_at
+	      WHERE EXISTS (SELECT 1 FROM things WHERE id = :thing_id AND owner = :owner AND state <> 'deleted');`
+
+	res, err := tkr.db.NamedExecContext(ctx, q, toDBThingKey(tk))
+	if err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok {
+			switch pqErr.Code.Name() {
+			case errInvalid, errTruncation:
+				return "", things.ErrMalformedEntity
+			case errDuplicate:
+				return "", things.ErrConflict
+			}
+		}
+
+		return "", err
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return "", err
+	}
+
+	if cnt == 0 {
+		return "", things.ErrNotFound
+	}
+
+	return tk.ID, nil
+}
+
+func (tkr thingKeyRepository) RetrieveAll(ctx context.Context, owner, thingID string) ([]things.ThingKey, error) {
+	// Verify if UUID format is valid to avoid internal Postgres error
+	if _, err := uuid.FromString(thingID); err != nil {
+		return nil, things.ErrNotFound
+	}
+
+	q := `SELECT id, thing_id, owner, key, scope, issued_at FROM thing_keys
+	      WHERE thing_id = $1 AND owner = $2 ORDER BY issued_at, id;`
+
+	rows, err := tkr.db.QueryxContext(ctx, q, thingID, owner)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []things.ThingKey{}
+	for rows.Next() {
+		var dbtk dbThingKey
+		if err := rows.StructScan(&dbtk); err != nil {
+			return nil, err
+		}
+
+		items = append(items, toThingKey(dbtk))
+	}
+
+	return items, nil
+}
+
+func (tkr thingKeyRepository) RetrieveByKey(ctx context.Context, key string) (things.ThingKey, error) {
+	q := `SELECT k.id, k.thing_id, k.owner, k.key, k.scope, k.issued_at FROM thing_keys k
+	      INNER JOIN things t ON t.id = k.thing_id AND t.owner = k.owner
+	      WHERE k.key = $1 AND t.state = 'enabled';`
+
+	return tkr.retrieve(ctx, q, key)
+}
+
+func (tkr thingKeyRepository) HasThing(ctx context.Context, chanID, key string) (things.ThingKey, things.ConnectionAccess, error) {
+	if _, err := uuid.FromString(chanID); err != nil {
+		return things.ThingKey{}, things.FullAccess, things.ErrNotFound
+	}
+
+	q := `SELECT k.id, k.thing_id, k.owner, k.key, k.scope, k.issued_at, c.can_publish, c.can_subscribe FROM thing_keys k
+	      INNER JOIN things t ON t.id = k.thing_id AND t.owner = k.owner
+	      INNER JOIN connections c ON c.thing_id = k.thing_id AND c.thing_owner = k.owner
+	      WHERE k.key = $1 AND t.state = 'enabled' AND c.channel_id = $2;`
+
+	var dbtk dbConnectedThingKey
+	if err := tkr.db.QueryRowxContext(ctx, q, key, chanID).StructScan(&dbtk); err != nil {
+		if err == sql.ErrNoRows {
+			return things.ThingKey{}, things.FullAccess, things.ErrNotFound
+		}
+		return things.ThingKey{}, things.FullAccess, err
+	}
+
+	access := toConnectionAccess(dbConnection{CanPublish: dbtk.CanPublish, CanSubscribe: dbtk.CanSubscribe})
+	return toThingKey(dbtk.dbThingKey), access, nil
+}
+
+func (tkr thingKeyRepository) retrieve(ctx context.Context, q string, args ...interface{}) (things.ThingKey, error) {
+	var dbtk dbThingKey
+	if err := tkr.db.QueryRowxContext(ctx, q, args...).StructScan(&dbtk); err != nil {
+		if err == sql.ErrNoRows {
+			return things.ThingKey{}, things.ErrNotFound
+		}
+		return things.ThingKey{}, err
+	}
+
+	return toThingKey(dbtk), nil
+}
+
+func (tkr thingKeyRepository) Remove(ctx context.Context, owner, thingID, id string) error {
+	for _, v := range []string{thingID, id} {
+		if _, err := uuid.FromString(v); err != nil {
+			return things.ErrNotFound
+		}
+	}
+
+	q := `DELETE FROM thing_keys WHERE id = $1 AND thing_id = $2 AND owner = $3;`
+
+	res, err := tkr.db.ExecContext(ctx, q, id, thingID, owner)
+	if err != nil {
+		return err
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if cnt == 0 {
+		return things.ErrNotFound
+	}
+
+	return nil
+}
+
+type dbThingKey struct {
+	ID       string    `db:"id"`
+	ThingID  string    `db:"thing_id"`
+	Owner    string    `db:"owner"`
+	Key      string    `db:"key"`
+	Scope    string    `db:"scope"`
+	IssuedAt time.Time `db:"issued_at"`
+}
+
+// dbConnectedThingKey is the thing key along with the access of the
+// connection of its thing to the requested channel.
+type dbConnectedThingKey struct {
+	dbThingKey
+	CanPublish   bool `db:"can_publish"`
+	CanSubscribe bool `db:"can_subscribe"`
+}
+
+func toDBThingKey(tk things.ThingKey) dbThingKey {
+	return dbThingKey{
+		ID:       tk.ID,
+		ThingID:  tk.ThingID,
+		Owner:    tk.Owner,
+		Key:      tk.Key,
+		Scope:    string(tk.Scope),
+		IssuedAt: tk.IssuedAt,
+	}
+}
+
+func toThingKey(dbtk dbThingKey) things.ThingKey {
+	return things.ThingKey{
+		ID:       dbtk.ID,
+		ThingID:  dbtk.ThingID,
+		Owner:    dbtk.Owner,
+		Key:      dbtk.Key,
+		Scope:    things.KeyScope(dbtk.Scope),
+		IssuedAt: dbtk.IssuedAt.UTC(),
+	}
+}
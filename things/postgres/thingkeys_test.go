@@ -0,0 +1,210 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/things"
+	"github.com/mainflux/mainflux/things/postgres"
+	"github.com/mainflux/mainflux/things/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThingKeySave(t *testing.T) {
+	email := "thing-key-save@example.com"
+	thingRepo := postgres.NewThingRepository(db)
+	thingKeyRepo := postgres.NewThingKeyRepository(db)
+
+	thID := saveThing(t, thingRepo, email)
+	missing, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	tk := newThingKey(t, thID, email, things.ScopePublish)
+	conflicting := newThingKey(t, thID, email, things.ScopeSubscribe)
+	conflicting.Key = tk.Key
+
+	cases := []struct {
+		desc string
+		key  things.ThingKey
+		err  error
+	}{
+		{
+			desc: "save thing key",
+			key:  tk,
+			err:  nil,
+		},
+		{
+			desc: "save thing key with conflicting key",
+			key:  conflicting,
+			err:  things.ErrConflict,
+		},
+		{
+			desc: "save key of non-existing thing",
+			key:  newThingKey(t, missing, email, things.ScopePublish),
+			err:  things.ErrNotFound,
+		},
+		{
+			desc: "save key of thing owned by other user",
+			key:  newThingKey(t, thID, "other@example.com", things.ScopePublish),
+			err:  things.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := thingKeyRepo.Save(context.Background(), tc.key)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	keys, err := thingKeyRepo.RetrieveAll(context.Background(), email, thID)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	require.Len(t, keys, 1, fmt.Sprintf("retrieve thing keys: expected 1 key got %d\n", len(keys)))
+	assert.Equal(t, tk.Scope, keys[0].Scope, fmt.Sprintf("retrieve thing keys: expected scope %s got %s\n", tk.Scope, keys[0].Scope))
+}
+
+func TestThingKeyHasThing(t *testing.T) {
+	email := "thing-key-access@example.com"
+	thingRepo := postgres.NewThingRepository(db)
+	chanRepo := postgres.NewChannelRepository(db)
+	thingKeyRepo := postgres.NewThingKeyRepository(db)
+
+	thID := saveThing(t, thingRepo, email)
+	chid, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	chanID, err := chanRepo.Save(context.Background(), things.Channel{ID: chid, Owner: email})
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	err = chanRepo.Connect(context.Background(), things.Connection{ChanID: chanID, ThingID: thID, Owner: email})
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	rchid, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	restrictedID, err := chanRepo.Save(context.Background(), things.Channel{ID: rchid, Owner: email})
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	err = chanRepo.Connect(context.Background(), things.Connection{ChanID: restrictedID, ThingID: thID, Owner: email, Access: things.PublishAccess})
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	tk := newThingKey(t, thID, email, things.ScopeSubscribe)
+	_, err = thingKeyRepo.Save(context.Background(), tk)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	unknown, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := map[string]struct {
+		chanID string
+		key    string
+		access things.ConnectionAccess
+		err    error
+	}{
+		"access connected channel using thing key": {
+			chanID: chanID,
+			key:    tk.Key,
+			access: things.FullAccess,
+			err:    nil,
+		},
+		"access publish-only channel using thing key": {
+			chanID: restrictedID,
+			key:    tk.Key,
+			access: things.PublishAccess,
+			err:    nil,
+		},
+		"access non-connected channel using thing key": {
+			chanID: unknown,
+			key:    tk.Key,
+			err:    things.ErrNotFound,
+		},
+		"access connected channel using unknown key": {
+			chanID: chanID,
+			key:    unknown,
+			err:    things.ErrNotFound,
+		},
+	}
+
+	for desc, tc := range cases {
+		key, access, err := thingKeyRepo.HasThing(context.Background(), tc.chanID, tc.key)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+		if err == nil {
+			assert.Equal(t, thID, key.ThingID, fmt.Sprintf("%s: expected %s got %s\n", desc, thID, key.ThingID))
+			assert.Equal(t, tc.access, access, fmt.Sprintf("%s: expected %v got %v\n", desc, tc.access, access))
+		}
+	}
+
+	_, access, err := thingKeyRepo.HasThing(context.Background(), restrictedID, tk.Key)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.False(t, access.Allows(mainflux.ActionSubscribe), "subscribe to publish-only channel using subscribe key: expected access to be denied\n")
+
+	err = thingRepo.UpdateState(context.Background(), email, thID, things.Disabled)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	_, _, err = thingKeyRepo.HasThing(context.Background(), chanID, tk.Key)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("access using key of disabled thing: expected %s got %s\n", things.ErrNotFound, err))
+}
+
+func TestThingKeyRemove(t *testing.T) {
+	email := "thing-key-remove@example.com"
+	thingRepo := postgres.NewThingRepository(db)
+	thingKeyRepo := postgres.NewThingKeyRepository(db)
+
+	thID := saveThing(t, thingRepo, email)
+	tk := newThingKey(t, thID, email, things.ScopeAdmin)
+	_, err := thingKeyRepo.Save(context.Background(), tk)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		owner string
+		id    string
+		err   error
+	}{
+		{
+			desc:  "remove key of thing owned by other user",
+			owner: "other@example.com",
+			id:    tk.ID,
+			err:   things.ErrNotFound,
+		},
+		{
+			desc:  "remove existing key",
+			owner: email,
+			id:    tk.ID,
+			err:   nil,
+		},
+		{
+			desc:  "remove removed key",
+			owner: email,
+			id:    tk.ID,
+			err:   things.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := thingKeyRepo.Remove(context.Background(), tc.owner, thID, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	_, err = thingKeyRepo.RetrieveByKey(context.Background(), tk.Key)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("retrieve removed key: expected %s got %s\n", things.ErrNotFound, err))
+}
+
+func newThingKey(t *testing.T, thingID, owner string, scope things.KeyScope) things.ThingKey {
+	id, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	key, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	return things.ThingKey{
+		ID:       id,
+		ThingID:  thingID,
+		Owner:    owner,
+		Key:      key,
+		Scope:    scope,
+		IssuedAt: time.Now().UTC(),
+	}
+}
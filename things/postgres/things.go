@@ -8,13 +8,16 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/gofrs/uuid"
 	"github.com/jmoiron/sqlx"
 	"github.com/lib/pq" // required for DB access
+	mfpostgres "github.com/mainflux/mainflux/postgres"
 	"github.com/mainflux/mainflux/things"
 )
 
@@ -39,48 +42,91 @@ func NewThingRepository(db *sqlx.DB) things.ThingRepository {
 	}
 }
 
-func (tr thingRepository) Save(thing things.Thing) (string, error) {
-	q := `INSERT INTO things (id, owner, name, key, metadata)
-	      VALUES (:id, :owner, :name, :key, :metadata);`
-
+func (tr thingRepository) Save(ctx context.Context, thing things.Thing) (string, error) {
 	dbth, err := toDBThing(thing)
 	if err != nil {
 		return "", err
 	}
 
-	_, err = tr.db.NamedExec(q, dbth)
+	err = mfpostgres.Transact(ctx, tr.db, func(tx *sqlx.Tx) error {
+		return saveThing(ctx, tx, dbth)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return dbth.ID, nil
+}
+
+func (tr thingRepository) SaveAll(ctx context.Context, ths ...things.Thing) ([]string, error) {
+	ids := make([]string, len(ths))
+	err := mfpostgres.Transact(ctx, tr.db, func(tx *sqlx.Tx) error {
+		for i, thing := range ths {
+			dbth, err := toDBThing(thing)
+			if err != nil {
+				return err
+			}
+
+			if err := saveThing(ctx, tx, dbth); err != nil {
+				return err
+			}
+
+			ids[i] = dbth.ID
+		}
+
+		return nil
+	})
 	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// saveThing inserts the thing and consumes the reservation of its
+// identifier, if any, within the transaction, so that the reserved key can't
+// be bound to another thing.
+func saveThing(ctx context.Context, tx *sqlx.Tx, dbth dbThing) error {
+	q := `INSERT INTO things (id, owner, name, external_id, key, tags, metadata)
+	      VALUES (:id, :owner, :name, :external_id, :key, :tags, :metadata);`
+
+	if _, err := tx.NamedExecContext(ctx, q, dbth); err != nil {
 		pqErr, ok := err.(*pq.Error)
 		if ok {
 			switch pqErr.Code.Name() {
 			case errInvalid, errTruncation:
-				return "", things.ErrMalformedEntity
+				return things.ErrMalformedEntity
 			case errDuplicate:
-				return "", things.ErrConflict
+				return things.ErrConflict
 			}
 		}
 
-		return "", err
+		return err
 	}
 
-	return dbth.ID, nil
+	q = `DELETE FROM reservations WHERE id = $1 AND owner = $2;`
+	_, err := tx.ExecContext(ctx, q, dbth.ID, dbth.Owner)
+	return err
 }
 
-func (tr thingRepository) Update(thing things.Thing) error {
-	q := `UPDATE things SET name = :name, metadata = :metadata WHERE owner = :owner AND id = :id;`
+func (tr thingRepository) Update(ctx context.Context, thing things.Thing) error {
+	q := `UPDATE things SET name = :name, tags = :tags, metadata = :metadata
+	      WHERE owner = :owner AND id = :id AND state <> 'deleted';`
 
 	dbth, err := toDBThing(thing)
 	if err != nil {
 		return err
 	}
 
-	res, err := tr.db.NamedExec(q, dbth)
+	res, err := tr.db.NamedExecContext(ctx, q, dbth)
 	if err != nil {
 		pqErr, ok := err.(*pq.Error)
 		if ok {
 			switch pqErr.Code.Name() {
 			case errInvalid, errTruncation:
 				return things.ErrMalformedEntity
+			case errDuplicate:
+				return things.ErrConflict
 			}
 		}
 
@@ -99,15 +145,16 @@ func (tr thingRepository) Update(thing things.Thing) error {
 	return nil
 }
 
-func (tr thingRepository) UpdateKey(owner, id, key string) error {
-	q := `UPDATE things SET key = :key WHERE owner = :owner AND id = :id;`
-	dbth := dbThing{
-		ID:    id,
-		Owner: owner,
-		Key:   key,
+func (tr thingRepository) UpdateKey(ctx context.Context, thing things.Thing) error {
+	q := `UPDATE things SET key = :key, key_expires_at = :key_expires_at, key_rotation = :key_rotation
+	      WHERE owner = :owner AND id = :id AND state <> 'deleted';`
+
+	dbth, err := toDBThing(thing)
+	if err != nil {
+		return err
 	}
 
-	res, err := tr.db.NamedExec(q, dbth)
+	res, err := tr.db.NamedExecContext(ctx, q, dbth)
 	if err != nil {
 		pqErr, ok := err.(*pq.Error)
 		if ok {
@@ -134,15 +181,156 @@ func (tr thingRepository) UpdateKey(owner, id, key string) error {
 	return nil
 }
 
-func (tr thingRepository) RetrieveByID(owner, id string) (things.Thing, error) {
-	q := `SELECT name, key, metadata FROM things WHERE id = $1 AND owner = $2;`
+func (tr thingRepository) RotateKey(ctx context.Context, thing things.Thing, old string) error {
+	q := `UPDATE things SET key = $1, key_expires_at = $2
+	      WHERE owner = $3 AND id = $4 AND key = $5 AND state <> 'deleted';`
+
+	res, err := tr.db.ExecContext(ctx, q, thing.Key, thing.KeyExpiresAt.UTC(), thing.Owner, thing.ID, old)
+	if err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && pqErr.Code.Name() == errDuplicate {
+			return things.ErrConflict
+		}
+
+		return err
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if cnt == 0 {
+		return things.ErrNotFound
+	}
+
+	return nil
+}
+
+func (tr thingRepository) Patch(ctx context.Context, owner, id string, patch map[string]interface{}) (things.Thing, error) {
+	var th things.Thing
+	err := mfpostgres.Transact(ctx, tr.db, func(tx *sqlx.Tx) error {
+		q := `SELECT name, external_id, key, key_expires_at, key_rotation, state, tags, metadata FROM things
+		      WHERE id = $1 AND owner = $2 AND state <> 'deleted' FOR UPDATE;`
+
+		dbth := dbThing{
+			ID:    id,
+			Owner: owner,
+		}
+		if err := tx.QueryRowxContext(ctx, q, id, owner).StructScan(&dbth); err != nil {
+			pqErr, ok := err.(*pq.Error)
+			if err == sql.ErrNoRows || ok && errInvalid == pqErr.Code.Name() {
+				return things.ErrNotFound
+			}
+			return err
+		}
+
+		old, err := toThing(dbth)
+		if err != nil {
+			return err
+		}
+
+		if th, err = things.PatchThing(old, patch); err != nil {
+			return err
+		}
+
+		if dbth, err = toDBThing(th); err != nil {
+			return err
+		}
+
+		q = `UPDATE things SET name = :name, tags = :tags, metadata = :metadata WHERE owner = :owner AND id = :id;`
+		if _, err := tx.NamedExecContext(ctx, q, dbth); err != nil {
+			pqErr, ok := err.(*pq.Error)
+			if ok {
+				switch pqErr.Code.Name() {
+				case errInvalid, errTruncation:
+					return things.ErrMalformedEntity
+				case errDuplicate:
+					return things.ErrConflict
+				}
+			}
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return things.Thing{}, err
+	}
+
+	return th, nil
+}
+
+func (tr thingRepository) UpdateMetadata(ctx context.Context, owner string, selector, patch map[string]interface{}) ([]things.Thing, error) {
+	sel, err := json.Marshal(selector)
+	if err != nil {
+		return []things.Thing{}, things.ErrMalformedEntity
+	}
+
+	var items []things.Thing
+	err = mfpostgres.Transact(ctx, tr.db, func(tx *sqlx.Tx) error {
+		// Things without metadata hold JSON null, which is matched only by
+		// the empty selector.
+		q := `SELECT id, name, external_id, key, key_expires_at, key_rotation, state, tags, metadata FROM things
+		      WHERE owner = $1 AND state <> 'deleted'
+		      AND (metadata::jsonb @> $2::jsonb OR $2::jsonb = '{}'::jsonb)
+		      FOR UPDATE;`
+
+		rows, err := tx.QueryxContext(ctx, q, owner, string(sel))
+		if err != nil {
+			return err
+		}
+
+		items = []things.Thing{}
+		for rows.Next() {
+			dbth := dbThing{Owner: owner}
+			if err := rows.StructScan(&dbth); err != nil {
+				rows.Close()
+				return err
+			}
+
+			th, err := toThing(dbth)
+			if err != nil {
+				rows.Close()
+				return err
+			}
+
+			th.Metadata = things.MergeMetadata(th.Metadata, patch)
+			items = append(items, th)
+		}
+		rows.Close()
+
+		q = `UPDATE things SET metadata = :metadata WHERE owner = :owner AND id = :id;`
+		for _, th := range items {
+			dbth, err := toDBThing(th)
+			if err != nil {
+				return err
+			}
+
+			if _, err := tx.NamedExecContext(ctx, q, dbth); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return []things.Thing{}, err
+	}
+
+	return items, nil
+}
+
+func (tr thingRepository) RetrieveByID(ctx context.Context, owner, id string) (things.Thing, error) {
+	q := `SELECT name, external_id, key, key_expires_at, key_rotation, state, tags, metadata, last_seen FROM things
+	      WHERE id = $1 AND owner = $2 AND state <> 'deleted';`
 
 	dbth := dbThing{
 		ID:    id,
 		Owner: owner,
 	}
 
-	if err := tr.db.QueryRowx(q, id, owner).StructScan(&dbth); err != nil {
+	if err := tr.db.QueryRowxContext(ctx, q, id, owner).StructScan(&dbth); err != nil {
 		empty := things.Thing{}
 
 		pqErr, ok := err.(*pq.Error)
@@ -156,37 +344,141 @@ func (tr thingRepository) RetrieveByID(owner, id string) (things.Thing, error) {
 	return toThing(dbth)
 }
 
-func (tr thingRepository) RetrieveByKey(key string) (string, error) {
-	q := `SELECT id FROM things WHERE key = $1;`
-	var id string
-	if err := tr.db.QueryRowx(q, key).Scan(&id); err != nil {
+func (tr thingRepository) RetrieveByExternalID(ctx context.Context, owner, externalID string) (things.Thing, error) {
+	q := `SELECT id, name, external_id, key, key_expires_at, key_rotation, state, tags, metadata, last_seen FROM things
+	      WHERE owner = $1 AND external_id = $2 AND state <> 'deleted';`
+
+	dbth := dbThing{Owner: owner}
+	if err := tr.db.QueryRowxContext(ctx, q, owner, externalID).StructScan(&dbth); err != nil {
 		if err == sql.ErrNoRows {
+			return things.Thing{}, things.ErrNotFound
+		}
+		return things.Thing{}, err
+	}
+
+	return toThing(dbth)
+}
+
+func (tr thingRepository) RetrieveByKey(ctx context.Context, key string) (things.Thing, error) {
+	return retrieveByKey(ctx, tr.db, key)
+}
+
+func (tr thingRepository) RetrieveExpired(ctx context.Context, since, until time.Time) ([]things.Thing, error) {
+	q := `SELECT id, owner, name, external_id, key, key_expires_at, key_rotation, state, tags, metadata FROM things
+	      WHERE state = 'enabled' AND key_expires_at <= $2
+	      AND (key_expires_at > $1 OR key_rotation > 0)
+	      ORDER BY key_expires_at;`
+
+	rows, err := tr.db.QueryxContext(ctx, q, since.UTC(), until.UTC())
+	if err != nil {
+		return []things.Thing{}, err
+	}
+	defer rows.Close()
+
+	items := []things.Thing{}
+	for rows.Next() {
+		var dbth dbThing
+		if err := rows.StructScan(&dbth); err != nil {
+			return []things.Thing{}, err
+		}
+
+		th, err := toThing(dbth)
+		if err != nil {
+			return []things.Thing{}, err
+		}
+
+		items = append(items, th)
+	}
+
+	return items, nil
+}
+
+func (tr thingRepository) RetrieveOwner(ctx context.Context, id string) (string, error) {
+	q := `SELECT owner FROM things WHERE id = $1 AND state <> 'deleted';`
+	var owner string
+	if err := tr.db.QueryRowxContext(ctx, q, id).Scan(&owner); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if err == sql.ErrNoRows || ok && errInvalid == pqErr.Code.Name() {
 			return "", things.ErrNotFound
 		}
 		return "", err
 	}
 
-	return id, nil
+	return owner, nil
+}
+
+func (tr thingRepository) RetrieveAll(ctx context.Context, owner string, offset, limit uint64, name string, metadata map[string]interface{}, tags things.TagFilter, status things.StatusFilter, after string) (things.ThingsPage, error) {
+	return tr.retrieve(ctx, owner, nil, offset, limit, name, metadata, tags, status, after)
+}
+
+func (tr thingRepository) RetrieveAccessible(ctx context.Context, user string, shared []string, offset, limit uint64, name string, metadata map[string]interface{}, tags things.TagFilter, status things.StatusFilter, after string) (things.ThingsPage, error) {
+	return tr.retrieve(ctx, user, shared, offset, limit, name, metadata, tags, status, after)
+}
+
+func (tr thingRepository) CountAccessible(ctx context.Context, user string, shared []string, name string, metadata map[string]interface{}, tags things.TagFilter, status things.StatusFilter) (uint64, error) {
+	nq, name := getNameQuery(name)
+	mq, mdata, err := getMetadataQuery(metadata)
+	if err != nil {
+		return 0, err
+	}
+	tq := getTagsQuery(tags)
+	sq := getStatusQuery(status)
+
+	oq := `owner = :owner`
+	if len(shared) > 0 {
+		oq = `(owner = :owner OR id = ANY(:shared))`
+	}
+
+	cq := fmt.Sprintf(`SELECT COUNT(*) FROM things WHERE %s AND state <> 'deleted' %s %s %s %s;`, oq, nq, mq, tq, sq)
+
+	params := map[string]interface{}{
+		"owner":    user,
+		"shared":   pq.Array(shared),
+		"name":     name,
+		"metadata": mdata,
+		"tags":     pq.Array(tags.Tags),
+		"since":    status.Since.UTC(),
+	}
+
+	return total(ctx, tr.db, cq, params)
 }
 
-func (tr thingRepository) RetrieveAll(owner string, offset, limit uint64, name string) (things.ThingsPage, error) {
-	nq := ""
-	if name != "" {
-		name = fmt.Sprintf(`%%%s%%`, name)
-		nq = `AND name LIKE :name`
+// retrieve retrieves the things owned by the specified user, along with the
+// ones having the shared identifiers, regardless of their owner.
+func (tr thingRepository) retrieve(ctx context.Context, owner string, shared []string, offset, limit uint64, name string, metadata map[string]interface{}, tags things.TagFilter, status things.StatusFilter, after string) (things.ThingsPage, error) {
+	nq, name := getNameQuery(name)
+	mq, mdata, err := getMetadataQuery(metadata)
+	if err != nil {
+		return things.ThingsPage{}, err
+	}
+	tq := getTagsQuery(tags)
+	sq := getStatusQuery(status)
+	aq, offset, err := getAfterQuery(after, offset)
+	if err != nil {
+		return things.ThingsPage{}, err
+	}
+
+	oq := `owner = :owner`
+	if len(shared) > 0 {
+		oq = `(owner = :owner OR id = ANY(:shared))`
 	}
 
-	q := fmt.Sprintf(`SELECT id, name, key, metadata FROM things
-	      WHERE owner = :owner %s ORDER BY id LIMIT :limit OFFSET :offset;`, nq)
+	q := fmt.Sprintf(`SELECT id, owner, name, external_id, key, key_expires_at, key_rotation, state, tags, metadata, last_seen FROM things
+	      WHERE %s AND state <> 'deleted' %s %s %s %s %s ORDER BY id LIMIT :limit OFFSET :offset;`, oq, nq, mq, tq, sq, aq)
 
 	params := map[string]interface{}{
-		"owner":  owner,
-		"limit":  limit,
-		"offset": offset,
-		"name":   name,
+		"owner":    owner,
+		"shared":   pq.Array(shared),
+		"limit":    limit,
+		"offset":   offset,
+		"name":     name,
+		"metadata": mdata,
+		"tags":     pq.Array(tags.Tags),
+		"since":    status.Since.UTC(),
+		"after":    after,
 	}
 
-	rows, err := tr.db.NamedQuery(q, params)
+	rows, err := tr.db.NamedQueryContext(ctx, q, params)
 	if err != nil {
 		return things.ThingsPage{}, err
 	}
@@ -194,7 +486,7 @@ func (tr thingRepository) RetrieveAll(owner string, offset, limit uint64, name s
 
 	items := []things.Thing{}
 	for rows.Next() {
-		dbth := dbThing{Owner: owner}
+		var dbth dbThing
 		if err := rows.StructScan(&dbth); err != nil {
 			return things.ThingsPage{}, err
 		}
@@ -207,44 +499,33 @@ func (tr thingRepository) RetrieveAll(owner string, offset, limit uint64, name s
 		items = append(items, th)
 	}
 
-	cq := ""
-	if name != "" {
-		cq = `AND name = $2`
-	}
+	cq := fmt.Sprintf(`SELECT COUNT(*) FROM things WHERE %s AND state <> 'deleted' %s %s %s %s;`, oq, nq, mq, tq, sq)
 
-	q = fmt.Sprintf(`SELECT COUNT(*) FROM things WHERE owner = $1 %s;`, cq)
-
-	total := uint64(0)
-	switch name {
-	case "":
-		if err := tr.db.Get(&total, q, owner); err != nil {
-			return things.ThingsPage{}, err
-		}
-	default:
-		if err := tr.db.Get(&total, q, owner, name); err != nil {
-			return things.ThingsPage{}, err
-		}
+	total, err := total(ctx, tr.db, cq, params)
+	if err != nil {
+		return things.ThingsPage{}, err
 	}
 
 	page := things.ThingsPage{
 		Things: items,
 		PageMetadata: things.PageMetadata{
-			Total:  total,
-			Offset: offset,
-			Limit:  limit,
+			Total:    total,
+			Offset:   offset,
+			Limit:    limit,
+			Metadata: metadata,
 		},
 	}
 
 	return page, nil
 }
 
-func (tr thingRepository) RetrieveByChannel(owner, channel string, offset, limit uint64) (things.ThingsPage, error) {
+func (tr thingRepository) RetrieveByChannel(ctx context.Context, owner, channel string, offset, limit uint64) (things.ThingsPage, error) {
 	// Verify if UUID format is valid to avoid internal Postgres error
 	if _, err := uuid.FromString(channel); err != nil {
 		return things.ThingsPage{}, things.ErrNotFound
 	}
 
-	q := `SELECT id, name, key, metadata
+	q := `SELECT id, name, external_id, key, key_expires_at, key_rotation, state, tags, metadata, last_seen
 	      FROM things th
 	      INNER JOIN connections co
 		  ON th.id = co.thing_id
@@ -260,7 +541,7 @@ func (tr thingRepository) RetrieveByChannel(owner, channel string, offset, limit
 		"offset":  offset,
 	}
 
-	rows, err := tr.db.NamedQuery(q, params)
+	rows, err := tr.db.NamedQueryContext(ctx, q, params)
 	if err != nil {
 		return things.ThingsPage{}, err
 	}
@@ -288,7 +569,7 @@ func (tr thingRepository) RetrieveByChannel(owner, channel string, offset, limit
 	     WHERE th.owner = $1 AND co.channel_id = $2;`
 
 	var total uint64
-	if err := tr.db.Get(&total, q, owner, channel); err != nil {
+	if err := tr.db.GetContext(ctx, &total, q, owner, channel); err != nil {
 		return things.ThingsPage{}, err
 	}
 
@@ -302,22 +583,161 @@ func (tr thingRepository) RetrieveByChannel(owner, channel string, offset, limit
 	}, nil
 }
 
-func (tr thingRepository) Remove(owner, id string) error {
+func (tr thingRepository) UpdateState(ctx context.Context, owner, id string, state things.State) error {
+	q := `UPDATE things SET state = :state WHERE owner = :owner AND id = :id AND state <> 'deleted';`
 	dbth := dbThing{
 		ID:    id,
 		Owner: owner,
+		State: string(state),
+	}
+
+	res, err := tr.db.NamedExecContext(ctx, q, dbth)
+	if err != nil {
+		return err
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if cnt == 0 {
+		return things.ErrNotFound
 	}
-	q := `DELETE FROM things WHERE id = :id AND owner = :owner;`
-	tr.db.NamedExec(q, dbth)
+
 	return nil
 }
 
+func (tr thingRepository) UpdateLastSeen(ctx context.Context, id string, t time.Time) error {
+	if _, err := uuid.FromString(id); err != nil {
+		return nil
+	}
+
+	q := `UPDATE things SET last_seen = $2
+	      WHERE id = $1 AND (last_seen IS NULL OR last_seen < $3);`
+
+	_, err := tr.db.ExecContext(ctx, q, id, t.UTC(), t.Add(-things.LastSeenResolution).UTC())
+	return err
+}
+
+// Transfer moves the thing history to the new owner, while the connections
+// kept and the scoped keys follow the thing by the cascading update.
+func (tr thingRepository) Transfer(ctx context.Context, owner, id, newOwner string, keepConns bool) error {
+	if _, err := uuid.FromString(id); err != nil {
+		return things.ErrNotFound
+	}
+
+	params := map[string]interface{}{
+		"id":        id,
+		"owner":     owner,
+		"new_owner": newOwner,
+	}
+
+	stmts := []string{
+		`DELETE FROM group_things WHERE thing_id = :id AND owner = :owner;`,
+		`DELETE FROM shares WHERE kind = 'thing' AND entity_id = :id AND owner = :owner;`,
+		`UPDATE history SET owner = :new_owner WHERE entity_id = :id AND owner = :owner;`,
+	}
+	if !keepConns {
+		stmts = append(stmts, `DELETE FROM connections WHERE thing_id = :id AND thing_owner = :owner;`)
+	}
+
+	return mfpostgres.Transact(ctx, tr.db, func(tx *sqlx.Tx) error {
+		for _, q := range stmts {
+			if _, err := tx.NamedExecContext(ctx, q, params); err != nil {
+				return err
+			}
+		}
+
+		q := `UPDATE things SET owner = :new_owner WHERE id = :id AND owner = :owner AND state <> 'deleted';`
+		res, err := tx.NamedExecContext(ctx, q, params)
+		if err != nil {
+			pqErr, ok := err.(*pq.Error)
+			if ok {
+				switch pqErr.Code.Name() {
+				case errTruncation:
+					return things.ErrMalformedEntity
+				case errDuplicate:
+					return things.ErrConflict
+				}
+			}
+
+			return err
+		}
+
+		cnt, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if cnt == 0 {
+			return things.ErrNotFound
+		}
+
+		return nil
+	})
+}
+
+// Remove keeps the row of the deleted thing, so that the thing history
+// remains resolvable, and drops its connections, group assignments and
+// scoped keys.
+func (tr thingRepository) Remove(ctx context.Context, owner, id string) error {
+	// Removal of the non-existent thing is not an error.
+	if _, err := uuid.FromString(id); err != nil {
+		return nil
+	}
+
+	dbth := dbThing{
+		ID:    id,
+		Owner: owner,
+	}
+
+	stmts := []string{
+		`DELETE FROM connections WHERE thing_id = :id AND thing_owner = :owner;`,
+		`DELETE FROM group_things WHERE thing_id = :id AND owner = :owner;`,
+		`DELETE FROM thing_keys WHERE thing_id = :id AND owner = :owner;`,
+		`UPDATE things SET state = 'deleted' WHERE id = :id AND owner = :owner;`,
+	}
+
+	return mfpostgres.Transact(ctx, tr.db, func(tx *sqlx.Tx) error {
+		for _, q := range stmts {
+			if _, err := tx.NamedExecContext(ctx, q, dbth); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// retrieveByKey retrieves the enabled thing having the unexpired key.
+func retrieveByKey(ctx context.Context, db *sqlx.DB, key string) (things.Thing, error) {
+	q := `SELECT id, owner, name, external_id, key, key_expires_at, key_rotation, state, tags, metadata FROM things
+	      WHERE key = $1 AND state = 'enabled' AND (key_expires_at IS NULL OR key_expires_at > $2);`
+
+	dbth := dbThing{}
+	if err := db.QueryRowxContext(ctx, q, key, time.Now().UTC()).StructScan(&dbth); err != nil {
+		if err == sql.ErrNoRows {
+			return things.Thing{}, things.ErrNotFound
+		}
+		return things.Thing{}, err
+	}
+
+	return toThing(dbth)
+}
+
 type dbThing struct {
-	ID       string `db:"id"`
-	Owner    string `db:"owner"`
-	Name     string `db:"name"`
-	Key      string `db:"key"`
-	Metadata string `db:"metadata"`
+	ID           string         `db:"id"`
+	Owner        string         `db:"owner"`
+	Name         string         `db:"name"`
+	ExternalID   string         `db:"external_id"`
+	Key          string         `db:"key"`
+	KeyExpiresAt pq.NullTime    `db:"key_expires_at"`
+	KeyRotation  int64          `db:"key_rotation"`
+	State        string         `db:"state"`
+	Tags         pq.StringArray `db:"tags"`
+	Metadata     string         `db:"metadata"`
+	LastSeen     pq.NullTime    `db:"last_seen"`
 }
 
 func toDBThing(th things.Thing) (dbThing, error) {
@@ -326,12 +746,18 @@ func toDBThing(th things.Thing) (dbThing, error) {
 		return dbThing{}, err
 	}
 
+	// Rotation period is stored in seconds.
 	return dbThing{
-		ID:       th.ID,
-		Owner:    th.Owner,
-		Name:     th.Name,
-		Key:      th.Key,
-		Metadata: string(data),
+		ID:           th.ID,
+		Owner:        th.Owner,
+		Name:         th.Name,
+		ExternalID:   th.ExternalID,
+		Key:          th.Key,
+		KeyExpiresAt: pq.NullTime{Time: th.KeyExpiresAt.UTC(), Valid: !th.KeyExpiresAt.IsZero()},
+		KeyRotation:  int64(th.KeyRotation / time.Second),
+		State:        string(th.State),
+		Tags:         toDBTags(th.Tags),
+		Metadata:     string(data),
 	}, nil
 }
 
@@ -341,11 +767,123 @@ func toThing(dbth dbThing) (things.Thing, error) {
 		return things.Thing{}, err
 	}
 
-	return things.Thing{
-		ID:       dbth.ID,
-		Owner:    dbth.Owner,
-		Name:     dbth.Name,
-		Key:      dbth.Key,
-		Metadata: metadata,
-	}, nil
+	th := things.Thing{
+		ID:          dbth.ID,
+		Owner:       dbth.Owner,
+		Name:        dbth.Name,
+		ExternalID:  dbth.ExternalID,
+		Key:         dbth.Key,
+		KeyRotation: time.Duration(dbth.KeyRotation) * time.Second,
+		State:       things.State(dbth.State),
+		Tags:        toTags(dbth.Tags),
+		Metadata:    metadata,
+	}
+	if dbth.KeyExpiresAt.Valid {
+		th.KeyExpiresAt = dbth.KeyExpiresAt.Time.UTC()
+	}
+	if dbth.LastSeen.Valid {
+		th.LastSeen = dbth.LastSeen.Time.UTC()
+	}
+
+	return th, nil
+}
+
+func getNameQuery(name string) (string, string) {
+	if name == "" {
+		return "", ""
+	}
+
+	return `AND name LIKE :name`, fmt.Sprintf(`%%%s%%`, name)
+}
+
+// getMetadataQuery returns the condition matching the metadata that contains
+// the provided one. Metadata is stored as JSON, so it's cast to JSONB in
+// order to use the containment operator.
+func getMetadataQuery(metadata map[string]interface{}) (string, string, error) {
+	if len(metadata) == 0 {
+		return "", "", nil
+	}
+
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return "", "", things.ErrMalformedEntity
+	}
+
+	return `AND CAST(metadata AS JSONB) @> :metadata`, string(data), nil
+}
+
+// getAfterQuery returns the condition matching the entities past the one
+// having the provided identifier, along with the offset to apply, which is
+// ignored when the entities are retrieved by keyset pagination. The total
+// count is not restricted by the condition.
+func getAfterQuery(after string, offset uint64) (string, uint64, error) {
+	if after == "" {
+		return "", offset, nil
+	}
+
+	if _, err := uuid.FromString(after); err != nil {
+		return "", 0, things.ErrMalformedEntity
+	}
+
+	return `AND id > :after`, 0, nil
+}
+
+// getTagsQuery returns the condition matching the entities holding all the
+// provided tags, or any of them.
+func getTagsQuery(tags things.TagFilter) string {
+	if len(tags.Tags) == 0 {
+		return ""
+	}
+
+	if tags.Any {
+		return `AND tags && :tags`
+	}
+
+	return `AND tags @> :tags`
+}
+
+func getStatusQuery(status things.StatusFilter) string {
+	switch status.Status {
+	case things.Online:
+		return `AND last_seen >= :since`
+	case things.Stale:
+		return `AND (last_seen IS NULL OR last_seen < :since)`
+	default:
+		return ""
+	}
+}
+
+// toDBTags returns the non-nil tags, since the missing tags are stored as
+// the empty array.
+func toDBTags(tags []string) pq.StringArray {
+	if tags == nil {
+		return pq.StringArray{}
+	}
+
+	return pq.StringArray(tags)
+}
+
+func toTags(tags pq.StringArray) []string {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	return []string(tags)
+}
+
+func total(ctx context.Context, db *sqlx.DB, query string, params map[string]interface{}) (uint64, error) {
+	rows, err := db.NamedQueryContext(ctx, query, params)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	total := uint64(0)
+	if rows.Next() {
+		if err := rows.Scan(&total); err != nil {
+			return 0, err
+		}
+	}
+
+	return total, nil
 }
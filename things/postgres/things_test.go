@@ -8,9 +8,11 @@
 package postgres_test
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -89,11 +91,69 @@ func TestThingSave(t *testing.T) {
 	}
 
 	for _, tc := range cases {
-		_, err := thingRepo.Save(tc.thing)
+		_, err := thingRepo.Save(context.Background(), tc.thing)
 		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
 	}
 }
 
+func TestThingSaveAll(t *testing.T) {
+	email := "thing-save-all@example.com"
+	thingRepo := postgres.NewThingRepository(db)
+
+	ths := []things.Thing{}
+	for i := 0; i < 3; i++ {
+		thid, err := uuid.New().ID()
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		thkey, err := uuid.New().ID()
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+		ths = append(ths, things.Thing{
+			ID:    thid,
+			Owner: email,
+			Key:   thkey,
+		})
+	}
+
+	cases := []struct {
+		desc   string
+		things []things.Thing
+		err    error
+	}{
+		{
+			desc:   "save new things",
+			things: ths[:2],
+			err:    nil,
+		},
+		{
+			desc:   "save things with existing key",
+			things: []things.Thing{ths[2], ths[0]},
+			err:    things.ErrConflict,
+		},
+		{
+			desc:   "save things with invalid ID",
+			things: []things.Thing{{ID: "invalid", Owner: email, Key: "key"}},
+			err:    things.ErrMalformedEntity,
+		},
+		{
+			desc:   "save things after rolled back transaction",
+			things: []things.Thing{ths[2]},
+			err:    nil,
+		},
+	}
+
+	for _, tc := range cases {
+		ids, err := thingRepo.SaveAll(context.Background(), tc.things...)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err != nil {
+			continue
+		}
+
+		for i, th := range tc.things {
+			assert.Equal(t, th.ID, ids[i], fmt.Sprintf("%s: expected %s got %s\n", tc.desc, th.ID, ids[i]))
+		}
+	}
+}
+
 func TestThingUpdate(t *testing.T) {
 	thingRepo := postgres.NewThingRepository(db)
 
@@ -111,7 +171,7 @@ func TestThingUpdate(t *testing.T) {
 		Key:   thkey,
 	}
 
-	id, _ := thingRepo.Save(thing)
+	id, _ := thingRepo.Save(context.Background(), thing)
 	thing.ID = id
 
 	nonexistentThingID, err := uuid.New().ID()
@@ -174,7 +234,7 @@ func TestThingUpdate(t *testing.T) {
 	}
 
 	for _, tc := range cases {
-		err := thingRepo.Update(tc.thing)
+		err := thingRepo.Update(context.Background(), tc.thing)
 		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
 	}
 }
@@ -194,7 +254,7 @@ func TestUpdateKey(t *testing.T) {
 		Owner: email,
 		Key:   ethkey,
 	}
-	existingID, _ := thingRepo.Save(existingThing)
+	existingID, _ := thingRepo.Save(context.Background(), existingThing)
 	existingThing.ID = existingID
 
 	thid, err := uuid.New().ID()
@@ -208,7 +268,7 @@ func TestUpdateKey(t *testing.T) {
 		Key:   thkey,
 	}
 
-	id, _ := thingRepo.Save(thing)
+	id, _ := thingRepo.Save(context.Background(), thing)
 	thing.ID = id
 
 	nonexistentThingID, err := uuid.New().ID()
@@ -259,8 +319,357 @@ func TestUpdateKey(t *testing.T) {
 	}
 
 	for _, tc := range cases {
-		err := thingRepo.UpdateKey(tc.owner, tc.id, tc.key)
+		err := thingRepo.UpdateKey(context.Background(), things.Thing{ID: tc.id, Owner: tc.owner, Key: tc.key})
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestRotateKey(t *testing.T) {
+	email := "thing-rotate-key@example.com"
+	thingRepo := postgres.NewThingRepository(db)
+
+	since := time.Now()
+	id := saveThing(t, thingRepo, email)
+	th, err := thingRepo.RetrieveByID(context.Background(), email, id)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	th.KeyExpiresAt = since.Add(time.Second)
+	th.KeyRotation = time.Hour
+	err = thingRepo.UpdateKey(context.Background(), th)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	expired, err := thingRepo.RetrieveExpired(context.Background(), since, since.Add(time.Second/2))
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.NotContains(t, ids(expired), id, "retrieve things with expired keys before expiration: expected thing to be omitted\n")
+
+	expired, err = thingRepo.RetrieveExpired(context.Background(), since, since.Add(2*time.Second))
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.Contains(t, ids(expired), id, "retrieve things with expired keys: expected thing to be retrieved\n")
+
+	newKey, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	rotated := th
+	rotated.Key = newKey
+	rotated.KeyExpiresAt = since.Add(time.Hour)
+
+	cases := []struct {
+		desc string
+		old  string
+		err  error
+	}{
+		{
+			desc: "rotate key",
+			old:  th.Key,
+			err:  nil,
+		},
+		{
+			desc: "rotate already rotated key",
+			old:  th.Key,
+			err:  things.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := thingRepo.RotateKey(context.Background(), rotated, tc.old)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	saved, err := thingRepo.RetrieveByID(context.Background(), email, id)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.Equal(t, newKey, saved.Key, fmt.Sprintf("retrieve rotated thing: expected key %s got %s\n", newKey, saved.Key))
+	assert.Equal(t, time.Hour, saved.KeyRotation, fmt.Sprintf("retrieve rotated thing: expected rotation %s got %s\n", time.Hour, saved.KeyRotation))
+	assert.WithinDuration(t, rotated.KeyExpiresAt, saved.KeyExpiresAt, time.Millisecond, "retrieve rotated thing: expected new expiration time\n")
+}
+
+func ids(ths []things.Thing) []string {
+	ids := []string{}
+	for _, th := range ths {
+		ids = append(ids, th.ID)
+	}
+
+	return ids
+}
+
+func TestThingPatch(t *testing.T) {
+	email := "thing-patch@example.com"
+	repo := postgres.NewThingRepository(db)
+
+	id, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	key, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	e := things.Thing{
+		ID:    id,
+		Owner: email,
+		Key:   key,
+	}
+	id, err = repo.Save(context.Background(), e)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	nonexistentID, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		owner string
+		id    string
+		patch map[string]interface{}
+		name  string
+		err   error
+	}{
+		{
+			desc:  "patch existing thing",
+			owner: email,
+			id:    id,
+			patch: map[string]interface{}{"name": "patched", "metadata": map[string]interface{}{"a": "b"}},
+			name:  "patched",
+			err:   nil,
+		},
+		{
+			desc:  "patch existing thing with read-only field",
+			owner: email,
+			id:    id,
+			patch: map[string]interface{}{"owner": wrongValue},
+			name:  "",
+			err:   things.ErrMalformedEntity,
+		},
+		{
+			desc:  "patch non-existing thing",
+			owner: email,
+			id:    nonexistentID,
+			patch: map[string]interface{}{"name": "patched"},
+			name:  "",
+			err:   things.ErrNotFound,
+		},
+		{
+			desc:  "patch existing thing with wrong owner",
+			owner: wrongValue,
+			id:    id,
+			patch: map[string]interface{}{"name": "patched"},
+			name:  "",
+			err:   things.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		res, err := repo.Patch(context.Background(), tc.owner, tc.id, tc.patch)
 		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.name, res.Name, fmt.Sprintf("%s: expected name %s got %s\n", tc.desc, tc.name, res.Name))
+	}
+}
+
+func TestThingUpdateMetadata(t *testing.T) {
+	thingRepo := postgres.NewThingRepository(db)
+
+	email := "thing-update-metadata@example.com"
+	metadata := map[string]interface{}{"model": "sensor", "interval": float64(10)}
+
+	for i := 0; i < 2; i++ {
+		thid, err := uuid.New().ID()
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		thkey, err := uuid.New().ID()
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+		thing := things.Thing{
+			ID:       thid,
+			Owner:    email,
+			Key:      thkey,
+			Metadata: metadata,
+		}
+		_, err = thingRepo.Save(context.Background(), thing)
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	}
+
+	cases := []struct {
+		desc     string
+		owner    string
+		selector map[string]interface{}
+		patch    map[string]interface{}
+		size     int
+	}{
+		{
+			desc:     "update metadata of matching things",
+			owner:    email,
+			selector: map[string]interface{}{"model": "sensor"},
+			patch:    map[string]interface{}{"interval": float64(60)},
+			size:     2,
+		},
+		{
+			desc:     "update metadata of non-matching things",
+			owner:    email,
+			selector: map[string]interface{}{"model": "gateway"},
+			patch:    map[string]interface{}{"interval": float64(60)},
+			size:     0,
+		},
+		{
+			desc:     "update metadata of things with wrong owner",
+			owner:    wrongValue,
+			selector: map[string]interface{}{},
+			patch:    map[string]interface{}{"interval": float64(60)},
+			size:     0,
+		},
+	}
+
+	for _, tc := range cases {
+		updated, err := thingRepo.UpdateMetadata(context.Background(), tc.owner, tc.selector, tc.patch)
+		assert.Nil(t, err, fmt.Sprintf("%s: got unexpected error: %s\n", tc.desc, err))
+		assert.Equal(t, tc.size, len(updated), fmt.Sprintf("%s: expected %d things got %d\n", tc.desc, tc.size, len(updated)))
+	}
+}
+
+func TestThingUpdateLastSeen(t *testing.T) {
+	email := "thing-update-last-seen@example.com"
+	thingRepo := postgres.NewThingRepository(db)
+
+	thid, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	thkey, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	th := things.Thing{
+		ID:    thid,
+		Owner: email,
+		Key:   thkey,
+	}
+	_, err = thingRepo.Save(context.Background(), th)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	nonexistentThingID, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	cases := []struct {
+		desc string
+		id   string
+		at   time.Time
+		seen time.Time
+	}{
+		{
+			desc: "record thing seen for the first time",
+			id:   thid,
+			at:   now,
+			seen: now,
+		},
+		{
+			desc: "record thing seen within the resolution",
+			id:   thid,
+			at:   now.Add(things.LastSeenResolution / 2),
+			seen: now,
+		},
+		{
+			desc: "record thing seen past the resolution",
+			id:   thid,
+			at:   now.Add(2 * things.LastSeenResolution),
+			seen: now.Add(2 * things.LastSeenResolution),
+		},
+		{
+			desc: "record thing seen earlier",
+			id:   thid,
+			at:   now,
+			seen: now.Add(2 * things.LastSeenResolution),
+		},
+		{
+			desc: "record non-existing thing seen",
+			id:   nonexistentThingID,
+			at:   now,
+		},
+		{
+			desc: "record thing with invalid ID seen",
+			id:   "invalid",
+			at:   now,
+		},
+	}
+
+	for _, tc := range cases {
+		err := thingRepo.UpdateLastSeen(context.Background(), tc.id, tc.at)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s\n", tc.desc, err))
+		if tc.seen.IsZero() {
+			continue
+		}
+
+		saved, err := thingRepo.RetrieveByID(context.Background(), email, tc.id)
+		require.Nil(t, err, fmt.Sprintf("%s: got unexpected error: %s", tc.desc, err))
+		assert.True(t, tc.seen.Equal(saved.LastSeen), fmt.Sprintf("%s: expected last seen %s got %s\n", tc.desc, tc.seen, saved.LastSeen))
+	}
+}
+
+func TestThingUpdateState(t *testing.T) {
+	email := "thing-update-state@example.com"
+	thingRepo := postgres.NewThingRepository(db)
+
+	ths := []things.Thing{}
+	for i := 0; i < 2; i++ {
+		thid, err := uuid.New().ID()
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		thkey, err := uuid.New().ID()
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+		th := things.Thing{
+			ID:    thid,
+			Owner: email,
+			Key:   thkey,
+		}
+		th.ID, err = thingRepo.Save(context.Background(), th)
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		ths = append(ths, th)
+	}
+	active, removed := ths[0], ths[1]
+
+	err := thingRepo.Remove(context.Background(), email, removed.ID)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	nonexistentThingID, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := []struct {
+		desc     string
+		id       string
+		state    things.State
+		identity string
+		err      error
+	}{
+		{
+			desc:     "disable existing thing",
+			id:       active.ID,
+			state:    things.Disabled,
+			identity: "",
+			err:      nil,
+		},
+		{
+			desc:     "enable existing thing",
+			id:       active.ID,
+			state:    things.Enabled,
+			identity: active.ID,
+			err:      nil,
+		},
+		{
+			desc:  "enable removed thing",
+			id:    removed.ID,
+			state: things.Enabled,
+			err:   things.ErrNotFound,
+		},
+		{
+			desc:  "disable non-existing thing",
+			id:    nonexistentThingID,
+			state: things.Disabled,
+			err:   things.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := thingRepo.UpdateState(context.Background(), email, tc.id, tc.state)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err != nil {
+			continue
+		}
+
+		th, err := thingRepo.RetrieveByID(context.Background(), email, tc.id)
+		require.Nil(t, err, fmt.Sprintf("%s: got unexpected error: %s", tc.desc, err))
+		assert.Equal(t, tc.state, th.State, fmt.Sprintf("%s: expected state %s got %s\n", tc.desc, tc.state, th.State))
+
+		identified, _ := thingRepo.RetrieveByKey(context.Background(), active.Key)
+		assert.Equal(t, tc.identity, identified.ID, fmt.Sprintf("%s: expected key to identify %s got %s\n", tc.desc, tc.identity, identified.ID))
 	}
 }
 
@@ -279,7 +688,7 @@ func TestSingleThingRetrieval(t *testing.T) {
 		Key:   thkey,
 	}
 
-	id, _ := thingRepo.Save(thing)
+	id, _ := thingRepo.Save(context.Background(), thing)
 	thing.ID = id
 
 	nonexistentThingID, err := uuid.New().ID()
@@ -313,8 +722,66 @@ func TestSingleThingRetrieval(t *testing.T) {
 	}
 
 	for desc, tc := range cases {
-		_, err := thingRepo.RetrieveByID(tc.owner, tc.ID)
+		_, err := thingRepo.RetrieveByID(context.Background(), tc.owner, tc.ID)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
+func TestThingRetrieveByExternalID(t *testing.T) {
+	email := "thing-external-id-retrieval@example.com"
+	thingRepo := postgres.NewThingRepository(db)
+
+	thid, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	thkey, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	thing := things.Thing{
+		ID:         thid,
+		Owner:      email,
+		ExternalID: "serial-1",
+		Key:        thkey,
+	}
+
+	_, err = thingRepo.Save(context.Background(), thing)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	dupid, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	dupkey, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	dup := things.Thing{ID: dupid, Owner: email, ExternalID: thing.ExternalID, Key: dupkey}
+	_, err = thingRepo.Save(context.Background(), dup)
+	assert.Equal(t, things.ErrConflict, err, fmt.Sprintf("save thing with existing external ID: expected %s got %s\n", things.ErrConflict, err))
+
+	cases := map[string]struct {
+		owner      string
+		externalID string
+		err        error
+	}{
+		"retrieve thing by existing external ID": {
+			owner:      email,
+			externalID: thing.ExternalID,
+			err:        nil,
+		},
+		"retrieve thing by non-existing external ID": {
+			owner:      email,
+			externalID: "serial-2",
+			err:        things.ErrNotFound,
+		},
+		"retrieve thing by external ID of other owner": {
+			owner:      wrongValue,
+			externalID: thing.ExternalID,
+			err:        things.ErrNotFound,
+		},
+	}
+
+	for desc, tc := range cases {
+		th, err := thingRepo.RetrieveByExternalID(context.Background(), tc.owner, tc.externalID)
 		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+		if err == nil {
+			assert.Equal(t, thing.ID, th.ID, fmt.Sprintf("%s: expected thing %s got %s\n", desc, thing.ID, th.ID))
+		}
 	}
 }
 
@@ -333,7 +800,7 @@ func TestThingRetrieveByKey(t *testing.T) {
 		Key:   thkey,
 	}
 
-	id, _ := thingRepo.Save(thing)
+	id, _ := thingRepo.Save(context.Background(), thing)
 	thing.ID = id
 
 	cases := map[string]struct {
@@ -354,8 +821,57 @@ func TestThingRetrieveByKey(t *testing.T) {
 	}
 
 	for desc, tc := range cases {
-		id, err := thingRepo.RetrieveByKey(tc.key)
-		assert.Equal(t, tc.ID, id, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.ID, id))
+		th, err := thingRepo.RetrieveByKey(context.Background(), tc.key)
+		assert.Equal(t, tc.ID, th.ID, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.ID, th.ID))
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
+func TestThingRetrieveOwner(t *testing.T) {
+	email := "thing-retrieved-owner@example.com"
+	thingRepo := postgres.NewThingRepository(db)
+
+	thid, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	thkey, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	thing := things.Thing{
+		ID:    thid,
+		Owner: email,
+		Key:   thkey,
+	}
+
+	id, _ := thingRepo.Save(context.Background(), thing)
+
+	nonexistentThingID, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := map[string]struct {
+		ID    string
+		owner string
+		err   error
+	}{
+		"retrieve owner of existing thing": {
+			ID:    id,
+			owner: email,
+			err:   nil,
+		},
+		"retrieve owner of non-existing thing": {
+			ID:    nonexistentThingID,
+			owner: "",
+			err:   things.ErrNotFound,
+		},
+		"retrieve owner of thing with malformed ID": {
+			ID:    wrongValue,
+			owner: "",
+			err:   things.ErrNotFound,
+		},
+	}
+
+	for desc, tc := range cases {
+		owner, err := thingRepo.RetrieveOwner(context.Background(), tc.ID)
+		assert.Equal(t, tc.owner, owner, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.owner, owner))
 		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
 	}
 }
@@ -363,6 +879,7 @@ func TestThingRetrieveByKey(t *testing.T) {
 func TestMultiThingRetrieval(t *testing.T) {
 	email := "thing-multi-retrieval@example.com"
 	name := "mainflux"
+	metadata := map[string]interface{}{"type": "sensor", "site": "plant-3"}
 	idp := uuid.New()
 	thingRepo := postgres.NewThingRepository(db)
 
@@ -384,15 +901,41 @@ func TestMultiThingRetrieval(t *testing.T) {
 			th.Name = name
 		}
 
-		thingRepo.Save(th)
+		// Create first two Things with metadata
+		if i < 2 {
+			th.Metadata = metadata
+		}
+
+		// Create first three Things with tags
+		if i < 3 {
+			th.Tags = []string{"edge"}
+		}
+		if i == 0 {
+			th.Tags = []string{"edge", "v2"}
+		}
+
+		thingRepo.Save(context.Background(), th)
+
+		// Record first four Things seen
+		if i < 4 {
+			err := thingRepo.UpdateLastSeen(context.Background(), thid, time.Now())
+			require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		}
 	}
 
+	since := time.Now().Add(-time.Minute)
+
 	cases := map[string]struct {
-		owner  string
-		offset uint64
-		limit  uint64
-		name   string
-		size   uint64
+		owner    string
+		offset   uint64
+		limit    uint64
+		name     string
+		metadata map[string]interface{}
+		tags     things.TagFilter
+		status   things.StatusFilter
+		after    string
+		size     uint64
+		total    uint64
 	}{
 		"retrieve all things with existing owner": {
 			owner:  email,
@@ -426,16 +969,160 @@ func TestMultiThingRetrieval(t *testing.T) {
 			name:   "wrong",
 			size:   0,
 		},
+		"retrieve things with matching metadata": {
+			owner:    email,
+			offset:   0,
+			limit:    n,
+			metadata: map[string]interface{}{"type": "sensor"},
+			size:     2,
+			total:    2,
+		},
+		"retrieve things with non-matching metadata": {
+			owner:    email,
+			offset:   0,
+			limit:    n,
+			metadata: map[string]interface{}{"type": "actuator"},
+			size:     0,
+			total:    0,
+		},
+		"retrieve things holding the tag": {
+			owner:  email,
+			offset: 0,
+			limit:  n,
+			tags:   things.TagFilter{Tags: []string{"edge"}},
+			size:   3,
+			total:  3,
+		},
+		"retrieve things holding all the tags": {
+			owner:  email,
+			offset: 0,
+			limit:  n,
+			tags:   things.TagFilter{Tags: []string{"edge", "v2"}},
+			size:   1,
+			total:  1,
+		},
+		"retrieve things holding any of the tags": {
+			owner:  email,
+			offset: 0,
+			limit:  n,
+			tags:   things.TagFilter{Tags: []string{"v2", "v3"}, Any: true},
+			size:   1,
+			total:  1,
+		},
+		"retrieve things with non-matching tags": {
+			owner:  email,
+			offset: 0,
+			limit:  n,
+			tags:   things.TagFilter{Tags: []string{"cloud"}, Any: true},
+			size:   0,
+			total:  0,
+		},
+		"retrieve online things": {
+			owner:  email,
+			offset: 0,
+			limit:  n,
+			status: things.StatusFilter{Status: things.Online, Since: since},
+			size:   4,
+			total:  4,
+		},
+		"retrieve stale things": {
+			owner:  email,
+			offset: 0,
+			limit:  n,
+			status: things.StatusFilter{Status: things.Stale, Since: since},
+			size:   n - 4,
+			total:  n - 4,
+		},
+		"retrieve things past the lowest identifier": {
+			owner: email,
+			limit: n,
+			after: "00000000-0000-0000-0000-000000000000",
+			size:  n,
+		},
+		"retrieve things past the highest identifier": {
+			owner: email,
+			limit: n,
+			after: "ffffffff-ffff-ffff-ffff-ffffffffffff",
+			size:  0,
+		},
 	}
 
 	for desc, tc := range cases {
-		page, err := thingRepo.RetrieveAll(tc.owner, tc.offset, tc.limit, tc.name)
+		page, err := thingRepo.RetrieveAll(context.Background(), tc.owner, tc.offset, tc.limit, tc.name, tc.metadata, tc.tags, tc.status, tc.after)
 		size := uint64(len(page.Things))
 		assert.Equal(t, tc.size, size, fmt.Sprintf("%s: expected %d got %d\n", desc, tc.size, size))
+		if tc.metadata != nil || tc.tags.Tags != nil || tc.status.Status != "" {
+			assert.Equal(t, tc.total, page.Total, fmt.Sprintf("%s: expected total %d got %d\n", desc, tc.total, page.Total))
+		}
 		assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %d\n", desc, err))
 	}
 }
 
+func TestThingCountAccessible(t *testing.T) {
+	email := "thing-count@example.com"
+	metadata := map[string]interface{}{"type": "sensor"}
+	idp := uuid.New()
+	thingRepo := postgres.NewThingRepository(db)
+
+	n := uint64(10)
+	shared := []string{}
+	for i := uint64(0); i <= n; i++ {
+		thid, err := idp.ID()
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		thkey, err := idp.ID()
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+		th := things.Thing{
+			Owner: email,
+			ID:    thid,
+			Key:   thkey,
+		}
+		if i < 2 {
+			th.Metadata = metadata
+		}
+		if i < 3 {
+			th.Tags = []string{"edge"}
+		}
+		// Create the last thing owned by another user and shared.
+		if i == n {
+			th.Owner = "thing-count-other@example.com"
+			shared = append(shared, thid)
+		}
+
+		_, err = thingRepo.Save(context.Background(), th)
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+		if i < 4 {
+			err := thingRepo.UpdateLastSeen(context.Background(), thid, time.Now())
+			require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		}
+	}
+
+	since := time.Now().Add(-time.Minute)
+
+	cases := map[string]struct {
+		owner    string
+		shared   []string
+		metadata map[string]interface{}
+		tags     things.TagFilter
+		status   things.StatusFilter
+		total    uint64
+	}{
+		"count all things":                  {email, nil, nil, things.TagFilter{}, things.StatusFilter{}, n},
+		"count all things with shared ones": {email, shared, nil, things.TagFilter{}, things.StatusFilter{}, n + 1},
+		"count things with metadata":        {email, nil, metadata, things.TagFilter{}, things.StatusFilter{}, 2},
+		"count things with tags":            {email, nil, nil, things.TagFilter{Tags: []string{"edge"}}, things.StatusFilter{}, 3},
+		"count online things":               {email, nil, nil, things.TagFilter{}, things.StatusFilter{Status: things.Online, Since: since}, 4},
+		"count things of non-existing user": {wrongValue, nil, nil, things.TagFilter{}, things.StatusFilter{}, 0},
+	}
+
+	for desc, tc := range cases {
+		total, err := thingRepo.CountAccessible(context.Background(), tc.owner, tc.shared, "", tc.metadata, tc.tags, tc.status)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", desc, err))
+		assert.Equal(t, tc.total, total, fmt.Sprintf("%s: expected %d got %d\n", desc, tc.total, total))
+	}
+}
+
 func TestMultiThingRetrievalByChannel(t *testing.T) {
 	email := "thing-multi-retrieval-by-channel@example.com"
 	idp := uuid.New()
@@ -447,7 +1134,7 @@ func TestMultiThingRetrievalByChannel(t *testing.T) {
 	chid, err := idp.ID()
 	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
 
-	cid, err := channelRepo.Save(things.Channel{
+	cid, err := channelRepo.Save(context.Background(), things.Channel{
 		ID:    chid,
 		Owner: email,
 	})
@@ -463,9 +1150,9 @@ func TestMultiThingRetrievalByChannel(t *testing.T) {
 			Key:   thkey,
 		}
 
-		tid, err := thingRepo.Save(th)
+		tid, err := thingRepo.Save(context.Background(), th)
 		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
-		err = channelRepo.Connect(email, cid, tid)
+		err = channelRepo.Connect(context.Background(), things.Connection{ChanID: cid, ThingID: tid, Owner: email})
 		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 	}
 
@@ -519,13 +1206,91 @@ func TestMultiThingRetrievalByChannel(t *testing.T) {
 	}
 
 	for desc, tc := range cases {
-		page, err := thingRepo.RetrieveByChannel(tc.owner, tc.channel, tc.offset, tc.limit)
+		page, err := thingRepo.RetrieveByChannel(context.Background(), tc.owner, tc.channel, tc.offset, tc.limit)
 		size := uint64(len(page.Things))
 		assert.Equal(t, tc.size, size, fmt.Sprintf("%s: expected %d got %d\n", desc, tc.size, size))
 		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected no error got %d\n", desc, err))
 	}
 }
 
+func TestThingTransfer(t *testing.T) {
+	email := "thing-transfer@example.com"
+	newOwner := "thing-transfer-new@example.com"
+	idp := uuid.New()
+	thingRepo := postgres.NewThingRepository(db)
+	chanRepo := postgres.NewChannelRepository(db)
+
+	chid, err := idp.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	chanID, err := chanRepo.Save(context.Background(), things.Channel{ID: chid, Owner: email})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	ids := []string{}
+	for i := 0; i < 2; i++ {
+		thid, err := idp.ID()
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		thkey, err := idp.ID()
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		id, err := thingRepo.Save(context.Background(), things.Thing{ID: thid, Owner: email, Key: thkey})
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		err = chanRepo.Connect(context.Background(), things.Connection{ChanID: chanID, ThingID: id, Owner: email})
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		ids = append(ids, id)
+	}
+	dropped, kept := ids[0], ids[1]
+
+	cases := []struct {
+		desc      string
+		owner     string
+		id        string
+		keepConns bool
+		conns     int
+		err       error
+	}{
+		{
+			desc:  "transfer thing with malformed ID",
+			owner: email,
+			id:    wrongValue,
+			err:   things.ErrNotFound,
+		},
+		{
+			desc:  "transfer thing with wrong owner",
+			owner: wrongValue,
+			id:    dropped,
+			err:   things.ErrNotFound,
+		},
+		{
+			desc:  "transfer thing dropping its connections",
+			owner: email,
+			id:    dropped,
+			conns: 0,
+			err:   nil,
+		},
+		{
+			desc:      "transfer thing keeping its connections",
+			owner:     email,
+			id:        kept,
+			keepConns: true,
+			conns:     1,
+			err:       nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := thingRepo.Transfer(context.Background(), tc.owner, tc.id, newOwner, tc.keepConns)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err != nil {
+			continue
+		}
+
+		_, err = thingRepo.RetrieveByID(context.Background(), newOwner, tc.id)
+		assert.Nil(t, err, fmt.Sprintf("%s: retrieve transferred thing: unexpected error: %s\n", tc.desc, err))
+		conns, err := chanRepo.RetrieveConnected(context.Background(), tc.id)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", tc.desc, err))
+		assert.Equal(t, tc.conns, len(conns), fmt.Sprintf("%s: expected %d connections got %d\n", tc.desc, tc.conns, len(conns)))
+	}
+}
+
 func TestThingRemoval(t *testing.T) {
 	email := "thing-removal@example.com"
 	thingRepo := postgres.NewThingRepository(db)
@@ -541,16 +1306,21 @@ func TestThingRemoval(t *testing.T) {
 		Key:   thkey,
 	}
 
-	id, _ := thingRepo.Save(thing)
+	id, _ := thingRepo.Save(context.Background(), thing)
 	thing.ID = id
 
 	// show that the removal works the same for both existing and non-existing
 	// (removed) thing
 	for i := 0; i < 2; i++ {
-		err := thingRepo.Remove(email, thing.ID)
+		err := thingRepo.Remove(context.Background(), email, thing.ID)
 		require.Nil(t, err, fmt.Sprintf("#%d: failed to remove thing due to: %s", i, err))
 
-		_, err = thingRepo.RetrieveByID(email, thing.ID)
+		_, err = thingRepo.RetrieveByID(context.Background(), email, thing.ID)
 		require.Equal(t, things.ErrNotFound, err, fmt.Sprintf("#%d: expected %s got %s", i, things.ErrNotFound, err))
 	}
+	// The key of the removed thing can be assigned again.
+	thid, err = uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	_, err = thingRepo.Save(context.Background(), things.Thing{ID: thid, Owner: email, Key: thkey})
+	assert.Nil(t, err, fmt.Sprintf("save thing with the key of removed thing: unexpected error: %s", err))
 }
@@ -0,0 +1,103 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package things
+
+import "context"
+
+const maxProfileChannels = 100
+
+// Profile represents the owner's description of a device type, that the
+// things are instantiated from when added. Things added using the profile
+// are given its metadata, unless they set the same fields themselves, are
+// connected to its channels, and are given the bootstrap configuration of
+// the provided content, if set.
+type Profile struct {
+	ID        string
+	Owner     string
+	Name      string
+	Metadata  map[string]interface{}
+	Channels  []string
+	Bootstrap string
+}
+
+// ProfilesPage contains page related metadata as well as list of profiles
+// that belong to this page.
+type ProfilesPage struct {
+	PageMetadata
+	Profiles []Profile
+}
+
+// Validate returns an error if profile representation is invalid, i.e. if
+// it exceeds the provided limits, its metadata holds a malformed adapter
+// section, or it refers to too many, empty or repeated channels.
+func (p *Profile) Validate(limits Limits) error {
+	if err := limits.validate(p.Name, "", p.Metadata); err != nil {
+		return err
+	}
+
+	if err := validateThingMetadata(p.Metadata); err != nil {
+		return err
+	}
+
+	if len(p.Channels) > maxProfileChannels {
+		return ErrMalformedEntity
+	}
+
+	seen := map[string]bool{}
+	for _, id := range p.Channels {
+		if id == "" || seen[id] {
+			return ErrMalformedEntity
+		}
+		seen[id] = true
+	}
+
+	return nil
+}
+
+// instantiate returns the thing given the profile defaults. Metadata fields
+// set by the thing take precedence over the ones of the profile.
+func (p Profile) instantiate(thing Thing) Thing {
+	if len(p.Metadata) == 0 {
+		return thing
+	}
+
+	metadata := map[string]interface{}{}
+	for k, v := range p.Metadata {
+		metadata[k] = v
+	}
+	for k, v := range thing.Metadata {
+		metadata[k] = v
+	}
+	thing.Metadata = metadata
+
+	return thing
+}
+
+// ProfileRepository specifies a profile persistence API.
+type ProfileRepository interface {
+	// Save persists the profile. Successful operation is indicated by unique
+	// identifier accompanied by nil error response.
+	Save(context.Context, Profile) (string, error)
+
+	// Update performs an update of the existing profile. A non-nil error is
+	// returned to indicate operation failure.
+	Update(context.Context, Profile) error
+
+	// RetrieveByID retrieves the profile having the provided identifier,
+	// that is owned by the specified user.
+	RetrieveByID(context.Context, string, string) (Profile, error)
+
+	// RetrieveAll retrieves the subset of profiles owned by the specified
+	// user.
+	RetrieveAll(context.Context, string, uint64, uint64) (ProfilesPage, error)
+
+	// Remove removes the profile having the provided identifier, that is
+	// owned by the specified user. The things instantiated from the profile
+	// are kept.
+	Remove(context.Context, string, string) error
+}
@@ -8,43 +8,220 @@
 package redis
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/go-redis/redis"
 	"github.com/mainflux/mainflux/things"
 )
 
-const chanPrefix = "channel"
+const (
+	chanPrefix = "channel"
+
+	// Rate limits are kept in the hashes that the adapters also use for
+	// the state of the channels' token buckets.
+	rateLimitPrefix = "ratelimit"
+	rateField       = "rate"
+	burstField      = "burst"
+
+	// Validation rules are kept as JSON documents, read by the normalizer.
+	validationPrefix = "validation"
+
+	// Payload transformations are kept as JSON documents, read by the
+	// adapters.
+	transformPrefix = "transform"
+
+	// Delivery modes are kept as plain strings, read by the adapters.
+	deliveryPrefix = "delivery"
+
+	// Allowed protocols are kept as comma separated plain strings, read by
+	// the adapters.
+	protocolsPrefix = "protocols"
+
+	statePrefix = "channel_state"
+
+	// The identifiers of the channels the thing is connected to are kept as
+	// JSON arrays, so that the thing not connected to any channel is cached
+	// too. The sets of the things connected to the channels serve to remove
+	// them along with the channels.
+	connectedPrefix = "thing_channels"
+)
 
 var _ things.ChannelCache = (*channelCache)(nil)
 
 type channelCache struct {
-	client *redis.Client
+	client redis.UniversalClient
+	ttl    time.Duration
 }
 
-// NewChannelCache returns redis channel cache implementation.
-func NewChannelCache(client *redis.Client) things.ChannelCache {
-	return channelCache{client: client}
+// NewChannelCache returns redis channel cache implementation, keeping the
+// connections and the states of the channels for ttl at most. The zero ttl
+// keeps them until they are removed. Rate limits, validation rules, delivery
+// modes and payload transformations are not refilled from the database, so
+// they don't expire.
+func NewChannelCache(client redis.UniversalClient, ttl time.Duration) things.ChannelCache {
+	return channelCache{
+		client: client,
+		ttl:    ttl,
+	}
 }
 
-func (cc channelCache) Connect(chanID, thingID string) error {
+func (cc channelCache) Connect(_ context.Context, chanID, thingID string) error {
 	cid, tid := kv(chanID, thingID)
-	return cc.client.SAdd(cid, tid).Err()
+	if err := cc.client.SAdd(cid, tid).Err(); err != nil {
+		return err
+	}
+
+	return cc.expire(cid)
 }
 
-func (cc channelCache) HasThing(chanID, thingID string) bool {
+func (cc channelCache) HasThing(_ context.Context, chanID, thingID string) bool {
 	cid, tid := kv(chanID, thingID)
 	return cc.client.SIsMember(cid, tid).Val()
 }
 
-func (cc channelCache) Disconnect(chanID, thingID string) error {
+func (cc channelCache) Disconnect(_ context.Context, chanID, thingID string) error {
 	cid, tid := kv(chanID, thingID)
 	return cc.client.SRem(cid, tid).Err()
 }
 
-func (cc channelCache) Remove(chanID string) error {
+func (cc channelCache) SaveRateLimit(_ context.Context, chanID string, rl things.RateLimit) error {
+	key := rateLimitKey(chanID)
+	if rl.Rate <= 0 {
+		return cc.client.Del(key).Err()
+	}
+
+	limit := map[string]interface{}{
+		rateField:  rl.Rate,
+		burstField: rl.Burst,
+	}
+	return cc.client.HMSet(key, limit).Err()
+}
+
+func (cc channelCache) SaveValidation(_ context.Context, chanID string, v things.Validation) error {
+	key := validationKey(chanID)
+	if len(v.Units) == 0 && len(v.Ranges) == 0 && v.Mode == "" {
+		return cc.client.Del(key).Err()
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return cc.client.Set(key, data, 0).Err()
+}
+
+func (cc channelCache) SaveTransform(_ context.Context, chanID string, tr things.Transform) error {
+	return saveTransform(cc.client, transformKey(chanID), tr)
+}
+
+func (cc channelCache) SaveDelivery(_ context.Context, chanID string, d things.Delivery) error {
+	key := deliveryKey(chanID)
+	if d.Mode == "" {
+		return cc.client.Del(key).Err()
+	}
+
+	return cc.client.Set(key, d.Mode, 0).Err()
+}
+
+func (cc channelCache) SaveProtocols(_ context.Context, chanID string, protocols []string) error {
+	key := protocolsKey(chanID)
+	if len(protocols) == 0 {
+		return cc.client.Del(key).Err()
+	}
+
+	return cc.client.Set(key, strings.Join(protocols, ","), 0).Err()
+}
+
+func (cc channelCache) SaveState(_ context.Context, chanID string, state things.State) error {
+	return cc.client.Set(stateKey(chanID), string(state), cc.ttl).Err()
+}
+
+func (cc channelCache) State(_ context.Context, chanID string) (things.State, error) {
+	val, err := cc.client.Get(stateKey(chanID)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return "", things.ErrNotFound
+		}
+		return "", err
+	}
+
+	return things.State(val), nil
+}
+
+func (cc channelCache) SaveConnected(_ context.Context, thingID string, chanIDs []string) error {
+	data, err := json.Marshal(chanIDs)
+	if err != nil {
+		return err
+	}
+
+	if err := cc.client.Set(connectedKey(thingID), data, cc.ttl).Err(); err != nil {
+		return err
+	}
+
+	for _, chanID := range chanIDs {
+		cid, tid := kv(chanID, thingID)
+		if err := cc.client.SAdd(cid, tid).Err(); err != nil {
+			return err
+		}
+
+		if err := cc.expire(cid); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (cc channelCache) Connected(_ context.Context, thingID string) ([]string, error) {
+	data, err := cc.client.Get(connectedKey(thingID)).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, things.ErrNotFound
+		}
+		return nil, err
+	}
+
+	var chanIDs []string
+	if err := json.Unmarshal(data, &chanIDs); err != nil {
+		return nil, err
+	}
+
+	return chanIDs, nil
+}
+
+func (cc channelCache) RemoveConnected(_ context.Context, thingID string) error {
+	return cc.client.Del(connectedKey(thingID)).Err()
+}
+
+func (cc channelCache) Remove(_ context.Context, chanID string) error {
 	cid, _ := kv(chanID, "0")
-	return cc.client.Del(cid).Err()
+
+	thingIDs, err := cc.client.SMembers(cid).Result()
+	if err != nil {
+		return err
+	}
+
+	keys := []string{cid, rateLimitKey(chanID), validationKey(chanID), transformKey(chanID), deliveryKey(chanID), protocolsKey(chanID), stateKey(chanID)}
+	for _, thingID := range thingIDs {
+		keys = append(keys, connectedKey(thingID))
+	}
+
+	return cc.client.Del(keys...).Err()
+}
+
+// expire sets the expiration of the set of the things connected to the
+// channel. Each new connection prolongs the whole set, which is refilled
+// from the database once it expires.
+func (cc channelCache) expire(cid string) error {
+	if cc.ttl <= 0 {
+		return nil
+	}
+
+	return cc.client.Expire(cid, cc.ttl).Err()
 }
 
 // Generates key-value pair
@@ -52,3 +229,31 @@ func kv(chanID, thingID string) (string, string) {
 	cid := fmt.Sprintf("%s:%s", chanPrefix, chanID)
 	return cid, thingID
 }
+
+func rateLimitKey(chanID string) string {
+	return fmt.Sprintf("%s:%s", rateLimitPrefix, chanID)
+}
+
+func validationKey(chanID string) string {
+	return fmt.Sprintf("%s:%s", validationPrefix, chanID)
+}
+
+func transformKey(chanID string) string {
+	return fmt.Sprintf("%s:%s", transformPrefix, chanID)
+}
+
+func deliveryKey(chanID string) string {
+	return fmt.Sprintf("%s:%s", deliveryPrefix, chanID)
+}
+
+func protocolsKey(chanID string) string {
+	return fmt.Sprintf("%s:%s", protocolsPrefix, chanID)
+}
+
+func stateKey(chanID string) string {
+	return fmt.Sprintf("%s:%s", statePrefix, chanID)
+}
+
+func connectedKey(thingID string) string {
+	return fmt.Sprintf("%s:%s", connectedPrefix, thingID)
+}
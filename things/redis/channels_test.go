@@ -8,16 +8,19 @@
 package redis_test
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/things"
 	"github.com/mainflux/mainflux/things/redis"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
 func TestConnect(t *testing.T) {
-	channelCache := redis.NewChannelCache(redisClient)
+	channelCache := redis.NewChannelCache(redisClient, 0)
 
 	cid := "123"
 	tid := "321"
@@ -39,18 +42,18 @@ func TestConnect(t *testing.T) {
 		},
 	}
 	for _, tc := range cases {
-		err := channelCache.Connect(cid, tid)
+		err := channelCache.Connect(context.Background(), cid, tid)
 		assert.Nil(t, err, fmt.Sprintf("%s: fail to connect due to: %s\n", tc.desc, err))
 	}
 }
 
 func TestHasThing(t *testing.T) {
-	channelCache := redis.NewChannelCache(redisClient)
+	channelCache := redis.NewChannelCache(redisClient, 0)
 
 	cid := "123"
 	tid := "321"
 
-	err := channelCache.Connect(cid, tid)
+	err := channelCache.Connect(context.Background(), cid, tid)
 	require.Nil(t, err, fmt.Sprintf("connect thing to channel: fail to connect due to: %s\n", err))
 
 	cases := map[string]struct {
@@ -76,18 +79,18 @@ func TestHasThing(t *testing.T) {
 	}
 
 	for desc, tc := range cases {
-		hasAccess := channelCache.HasThing(tc.cid, tc.tid)
+		hasAccess := channelCache.HasThing(context.Background(), tc.cid, tc.tid)
 		assert.Equal(t, tc.hasAccess, hasAccess, fmt.Sprintf("%s: expected %t got %t\n", desc, tc.hasAccess, hasAccess))
 	}
 }
 func TestDisconnect(t *testing.T) {
-	channelCache := redis.NewChannelCache(redisClient)
+	channelCache := redis.NewChannelCache(redisClient, 0)
 
 	cid := "123"
 	tid := "321"
 	tid2 := "322"
 
-	err := channelCache.Connect(cid, tid)
+	err := channelCache.Connect(context.Background(), cid, tid)
 	require.Nil(t, err, fmt.Sprintf("connect thing to channel: fail to connect due to: %s\n", err))
 
 	cases := []struct {
@@ -110,22 +113,229 @@ func TestDisconnect(t *testing.T) {
 		},
 	}
 	for _, tc := range cases {
-		err := channelCache.Disconnect(tc.cid, tc.tid)
+		err := channelCache.Disconnect(context.Background(), tc.cid, tc.tid)
 		assert.Nil(t, err, fmt.Sprintf("%s: fail due to: %s\n", tc.desc, err))
 
-		hasAccess := channelCache.HasThing(tc.cid, tc.tid)
+		hasAccess := channelCache.HasThing(context.Background(), tc.cid, tc.tid)
 		assert.Equal(t, tc.hasAccess, hasAccess, fmt.Sprintf("access check after %s: expected %t got %t\n", tc.desc, tc.hasAccess, hasAccess))
 	}
 }
 
+func TestSaveRateLimit(t *testing.T) {
+	channelCache := redis.NewChannelCache(redisClient, 0)
+
+	cid := "125"
+	key := fmt.Sprintf("ratelimit:%s", cid)
+
+	cases := []struct {
+		desc  string
+		limit things.RateLimit
+		saved map[string]string
+	}{
+		{
+			desc:  "save channel rate limit",
+			limit: things.RateLimit{Rate: 2.5, Burst: 10},
+			saved: map[string]string{"rate": "2.5", "burst": "10"},
+		},
+		{
+			desc:  "update channel rate limit",
+			limit: things.RateLimit{Rate: 1, Burst: 1},
+			saved: map[string]string{"rate": "1", "burst": "1"},
+		},
+		{
+			desc:  "remove channel rate limit",
+			limit: things.RateLimit{},
+			saved: map[string]string{},
+		},
+	}
+
+	for _, tc := range cases {
+		err := channelCache.SaveRateLimit(context.Background(), cid, tc.limit)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s\n", tc.desc, err))
+		saved := redisClient.HGetAll(key).Val()
+		assert.Equal(t, tc.saved, saved, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.saved, saved))
+	}
+}
+
+func TestSaveValidation(t *testing.T) {
+	channelCache := redis.NewChannelCache(redisClient, 0)
+
+	cid := "126"
+	key := fmt.Sprintf("validation:%s", cid)
+	max := 100.0
+
+	cases := []struct {
+		desc  string
+		rules things.Validation
+		saved string
+	}{
+		{
+			desc:  "save channel validation rules",
+			rules: things.Validation{Units: []string{"Cel"}},
+			saved: `{"units":["Cel"]}`,
+		},
+		{
+			desc:  "update channel validation rules",
+			rules: things.Validation{Ranges: map[string]things.Range{"%RH": {Max: &max}}, Flag: true},
+			saved: `{"ranges":{"%RH":{"max":100}},"flag":true}`,
+		},
+		{
+			desc:  "remove channel validation rules",
+			rules: things.Validation{},
+			saved: "",
+		},
+	}
+
+	for _, tc := range cases {
+		err := channelCache.SaveValidation(context.Background(), cid, tc.rules)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s\n", tc.desc, err))
+		saved := redisClient.Get(key).Val()
+		assert.Equal(t, tc.saved, saved, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.saved, saved))
+	}
+}
+
+func TestSaveDelivery(t *testing.T) {
+	channelCache := redis.NewChannelCache(redisClient, 0)
+
+	cid := "127"
+	key := fmt.Sprintf("delivery:%s", cid)
+
+	cases := []struct {
+		desc     string
+		delivery things.Delivery
+		saved    string
+	}{
+		{
+			desc:     "save channel delivery mode",
+			delivery: things.Delivery{Mode: mainflux.DeliveryAtLeastOnce},
+			saved:    mainflux.DeliveryAtLeastOnce,
+		},
+		{
+			desc:     "update channel delivery mode",
+			delivery: things.Delivery{Mode: mainflux.DeliveryAtMostOnce},
+			saved:    mainflux.DeliveryAtMostOnce,
+		},
+		{
+			desc:     "remove channel delivery mode",
+			delivery: things.Delivery{},
+			saved:    "",
+		},
+	}
+
+	for _, tc := range cases {
+		err := channelCache.SaveDelivery(context.Background(), cid, tc.delivery)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s\n", tc.desc, err))
+		saved := redisClient.Get(key).Val()
+		assert.Equal(t, tc.saved, saved, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.saved, saved))
+	}
+}
+
+func TestSaveProtocols(t *testing.T) {
+	channelCache := redis.NewChannelCache(redisClient, 0)
+
+	cid := "131"
+	key := fmt.Sprintf("protocols:%s", cid)
+
+	cases := []struct {
+		desc      string
+		protocols []string
+		saved     string
+	}{
+		{
+			desc:      "save channel protocols",
+			protocols: []string{"http", "mqtt"},
+			saved:     "http,mqtt",
+		},
+		{
+			desc:      "remove channel protocols",
+			protocols: nil,
+			saved:     "",
+		},
+	}
+
+	for _, tc := range cases {
+		err := channelCache.SaveProtocols(context.Background(), cid, tc.protocols)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s\n", tc.desc, err))
+		saved := redisClient.Get(key).Val()
+		assert.Equal(t, tc.saved, saved, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.saved, saved))
+	}
+}
+
+func TestSaveChannelTransform(t *testing.T) {
+	channelCache := redis.NewChannelCache(redisClient, 0)
+
+	cid := "128"
+	key := fmt.Sprintf("transform:%s", cid)
+
+	cases := []struct {
+		desc  string
+		tr    things.Transform
+		saved string
+	}{
+		{
+			desc:  "save channel transformation",
+			tr:    things.Transform{Template: `{"v":{{t}}}`},
+			saved: `{"template":"{\"v\":{{t}}}"}`,
+		},
+		{
+			desc:  "update channel transformation",
+			tr:    things.Transform{Template: `{{}}`, ContentType: "application/json"},
+			saved: `{"template":"{{}}","contentType":"application/json"}`,
+		},
+		{
+			desc:  "remove channel transformation",
+			tr:    things.Transform{},
+			saved: "",
+		},
+	}
+
+	for _, tc := range cases {
+		err := channelCache.SaveTransform(context.Background(), cid, tc.tr)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s\n", tc.desc, err))
+		saved := redisClient.Get(key).Val()
+		assert.Equal(t, tc.saved, saved, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.saved, saved))
+	}
+}
+
+func TestSaveState(t *testing.T) {
+	channelCache := redis.NewChannelCache(redisClient, 0)
+
+	cid := "127"
+
+	_, err := channelCache.State(context.Background(), cid)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("retrieve non-cached state: expected %s got %s\n", things.ErrNotFound, err))
+
+	cases := []struct {
+		desc  string
+		state things.State
+	}{
+		{
+			desc:  "save archived channel state",
+			state: things.Archived,
+		},
+		{
+			desc:  "save active channel state",
+			state: things.Active,
+		},
+	}
+
+	for _, tc := range cases {
+		err := channelCache.SaveState(context.Background(), cid, tc.state)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s\n", tc.desc, err))
+		state, err := channelCache.State(context.Background(), cid)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s\n", tc.desc, err))
+		assert.Equal(t, tc.state, state, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.state, state))
+	}
+}
+
 func TestRemove(t *testing.T) {
-	channelCache := redis.NewChannelCache(redisClient)
+	channelCache := redis.NewChannelCache(redisClient, 0)
 
 	cid := "123"
 	cid2 := "124"
 	tid := "321"
 
-	err := channelCache.Connect(cid, tid)
+	err := channelCache.Connect(context.Background(), cid, tid)
 	require.Nil(t, err, fmt.Sprintf("connect thing to channel: fail to connect due to: %s\n", err))
 
 	cases := []struct {
@@ -152,9 +362,53 @@ func TestRemove(t *testing.T) {
 	}
 
 	for _, tc := range cases {
-		err := channelCache.Remove(tc.cid)
+		err := channelCache.Remove(context.Background(), tc.cid)
 		assert.Nil(t, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
-		hasAcces := channelCache.HasThing(tc.cid, tc.tid)
+		hasAcces := channelCache.HasThing(context.Background(), tc.cid, tc.tid)
 		assert.Equal(t, tc.hasAccess, hasAcces, "%s - check access after removing channel: expected %t got %t\n", tc.desc, tc.hasAccess, hasAcces)
 	}
 }
+
+func TestSaveConnected(t *testing.T) {
+	channelCache := redis.NewChannelCache(redisClient, 0)
+
+	tid := "322"
+	cid := "128"
+
+	_, err := channelCache.Connected(context.Background(), tid)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("retrieve non-cached channels: expected %s got %s\n", things.ErrNotFound, err))
+
+	cases := []struct {
+		desc    string
+		chanIDs []string
+	}{
+		{
+			desc:    "save channels of unconnected thing",
+			chanIDs: []string{},
+		},
+		{
+			desc:    "save channels of connected thing",
+			chanIDs: []string{cid, "129"},
+		},
+	}
+
+	for _, tc := range cases {
+		err := channelCache.SaveConnected(context.Background(), tid, tc.chanIDs)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s\n", tc.desc, err))
+		chanIDs, err := channelCache.Connected(context.Background(), tid)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s\n", tc.desc, err))
+		assert.Equal(t, tc.chanIDs, chanIDs, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.chanIDs, chanIDs))
+	}
+
+	err = channelCache.Remove(context.Background(), cid)
+	require.Nil(t, err, fmt.Sprintf("remove channel: unexpected error %s\n", err))
+	_, err = channelCache.Connected(context.Background(), tid)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("retrieve channels after removing channel: expected %s got %s\n", things.ErrNotFound, err))
+
+	err = channelCache.SaveConnected(context.Background(), tid, []string{cid})
+	require.Nil(t, err, fmt.Sprintf("save channels: unexpected error %s\n", err))
+	err = channelCache.RemoveConnected(context.Background(), tid)
+	require.Nil(t, err, fmt.Sprintf("remove channels: unexpected error %s\n", err))
+	_, err = channelCache.Connected(context.Background(), tid)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("retrieve removed channels: expected %s got %s\n", things.ErrNotFound, err))
+}
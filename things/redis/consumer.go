@@ -0,0 +1,68 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package redis
+
+import (
+	"context"
+
+	"github.com/go-redis/redis"
+	"github.com/mainflux/mainflux/events"
+	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/things"
+)
+
+const (
+	group = "mainflux.things"
+
+	userPrefix = "user."
+	userRemove = userPrefix + "remove"
+)
+
+// EventStore represents event source for users lifecycle events.
+type EventStore interface {
+	// Subscribe subscribes to the given stream and handles received events.
+	Subscribe(string) error
+}
+
+type usersEventStore struct {
+	svc      things.Service
+	client   redis.UniversalClient
+	consumer string
+	logger   logger.Logger
+}
+
+// NewUsersEventStore returns new event store instance that consumes users
+// service events.
+func NewUsersEventStore(svc things.Service, client redis.UniversalClient, consumer string, log logger.Logger) EventStore {
+	return usersEventStore{
+		svc:      svc,
+		client:   client,
+		consumer: consumer,
+		logger:   log,
+	}
+}
+
+func (es usersEventStore) Subscribe(stream string) error {
+	sub := events.NewSubscriber(es.client, events.Config{
+		Stream:   stream,
+		Group:    group,
+		Consumer: es.consumer,
+	}, es.logger)
+
+	return sub.Subscribe(events.HandlerFunc(es.handle))
+}
+
+func (es usersEventStore) handle(event events.Event) error {
+	var err error
+	switch event.Read("operation", "") {
+	case userRemove:
+		_, _, err = es.svc.RemoveUserHandler(context.Background(), event.Read("email", ""))
+	}
+
+	return err
+}
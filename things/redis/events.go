@@ -1,21 +1,67 @@
 package redis
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/mainflux/mainflux/things"
+)
 
 const (
 	thingPrefix     = "thing."
 	thingCreate     = thingPrefix + "create"
 	thingUpdate     = thingPrefix + "update"
+	thingUpdateKey  = thingPrefix + "update_key"
+	thingRotateKey  = thingPrefix + "rotate_key"
+	thingEnable     = thingPrefix + "enable"
+	thingDisable    = thingPrefix + "disable"
 	thingRemove     = thingPrefix + "remove"
+	thingTransfer   = thingPrefix + "transfer"
 	thingConnect    = thingPrefix + "connect"
 	thingDisconnect = thingPrefix + "disconnect"
-
-	channelPrefix = "channel."
-	channelCreate = channelPrefix + "create"
-	channelUpdate = channelPrefix + "update"
-	channelRemove = channelPrefix + "remove"
+	thingIssueKey   = thingPrefix + "issue_key"
+	thingRevokeKey  = thingPrefix + "revoke_key"
+	thingRevokeKeys = thingPrefix + "revoke_signed_keys"
+	thingShare      = thingPrefix + "share"
+	thingUnshare    = thingPrefix + "unshare"
+
+	channelPrefix             = "channel."
+	channelCreate             = channelPrefix + "create"
+	channelUpdate             = channelPrefix + "update"
+	channelArchive            = channelPrefix + "archive"
+	channelUnarchive          = channelPrefix + "unarchive"
+	channelRemove             = channelPrefix + "remove"
+	channelTransfer           = channelPrefix + "transfer"
+	channelIssueToken         = channelPrefix + "issue_token"
+	channelRevokeToken        = channelPrefix + "revoke_token"
+	channelShare              = channelPrefix + "share"
+	channelUnshare            = channelPrefix + "unshare"
+	channelRegisterSubtopic   = channelPrefix + "register_subtopic"
+	channelRemoveSubtopic     = channelPrefix + "remove_subtopic"
+	channelAddSubtopicRule    = channelPrefix + "add_subtopic_rule"
+	channelRemoveSubtopicRule = channelPrefix + "remove_subtopic_rule"
+
+	groupPrefix   = "group."
+	groupCreate   = groupPrefix + "create"
+	groupUpdate   = groupPrefix + "update"
+	groupRemove   = groupPrefix + "remove"
+	groupAssign   = groupPrefix + "assign"
+	groupUnassign = groupPrefix + "unassign"
+
+	profilePrefix = "profile."
+	profileCreate = profilePrefix + "create"
+	profileUpdate = profilePrefix + "update"
+	profileRemove = profilePrefix + "remove"
+
+	ownerRemove = "owner.remove"
 )
 
+// secretFields are the event fields holding the thing keys. They are
+// passed to the Bootstrap service over the stream, but never published to
+// NATS, whose subjects any service can subscribe to.
+var secretFields = []string{"key"}
+
 type event interface {
 	Encode() map[string]interface{}
 }
@@ -23,19 +69,58 @@ type event interface {
 var (
 	_ event = (*createThingEvent)(nil)
 	_ event = (*updateThingEvent)(nil)
+	_ event = (*updateKeyEvent)(nil)
+	_ event = (*rotateKeyEvent)(nil)
+	_ event = (*enableThingEvent)(nil)
+	_ event = (*disableThingEvent)(nil)
 	_ event = (*removeThingEvent)(nil)
 	_ event = (*createChannelEvent)(nil)
 	_ event = (*updateChannelEvent)(nil)
+	_ event = (*archiveChannelEvent)(nil)
+	_ event = (*unarchiveChannelEvent)(nil)
 	_ event = (*removeChannelEvent)(nil)
 	_ event = (*connectThingEvent)(nil)
 	_ event = (*disconnectThingEvent)(nil)
+	_ event = (*transferThingEvent)(nil)
+	_ event = (*transferChannelEvent)(nil)
+	_ event = (*issueThingKeyEvent)(nil)
+	_ event = (*revokeThingKeyEvent)(nil)
+	_ event = (*revokeSignedKeysEvent)(nil)
+	_ event = (*issueChannelTokenEvent)(nil)
+	_ event = (*revokeChannelTokenEvent)(nil)
+	_ event = (*shareEvent)(nil)
+	_ event = (*unshareEvent)(nil)
+	_ event = (*registerSubtopicEvent)(nil)
+	_ event = (*removeSubtopicEvent)(nil)
+	_ event = (*addSubtopicRuleEvent)(nil)
+	_ event = (*removeSubtopicRuleEvent)(nil)
+	_ event = (*createGroupEvent)(nil)
+	_ event = (*updateGroupEvent)(nil)
+	_ event = (*removeGroupEvent)(nil)
+	_ event = (*assignThingEvent)(nil)
+	_ event = (*unassignThingEvent)(nil)
+	_ event = (*createProfileEvent)(nil)
+	_ event = (*updateProfileEvent)(nil)
+	_ event = (*removeProfileEvent)(nil)
+	_ event = (*removeOwnerEvent)(nil)
 )
 
 type createThingEvent struct {
-	id       string
-	owner    string
-	name     string
-	metadata map[string]interface{}
+	id         string
+	owner      string
+	externalID string
+	name       string
+	metadata   map[string]interface{}
+	tags       []string
+	key        string
+	bootstrap  string
+	channels   []bootstrapChannel
+}
+
+type bootstrapChannel struct {
+	ID       string                 `json:"id"`
+	Name     string                 `json:"name,omitempty"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
 }
 
 func (cte createThingEvent) Encode() map[string]interface{} {
@@ -45,6 +130,10 @@ func (cte createThingEvent) Encode() map[string]interface{} {
 		"operation": thingCreate,
 	}
 
+	if cte.externalID != "" {
+		val["external_id"] = cte.externalID
+	}
+
 	if cte.name != "" {
 		val["name"] = cte.name
 	}
@@ -58,13 +147,38 @@ func (cte createThingEvent) Encode() map[string]interface{} {
 		val["metadata"] = string(metadata)
 	}
 
+	if cte.tags != nil {
+		tags, err := json.Marshal(cte.tags)
+		if err != nil {
+			return val
+		}
+
+		val["tags"] = string(tags)
+	}
+
+	// Things instantiated from the profiles carrying the bootstrap content
+	// pass it, along with their key and channels, on to the Bootstrap
+	// service.
+	if cte.bootstrap != "" {
+		data, err := json.Marshal(cte.channels)
+		if err != nil {
+			return val
+		}
+
+		val["key"] = cte.key
+		val["bootstrap"] = cte.bootstrap
+		val["channels"] = string(data)
+	}
+
 	return val
 }
 
 type updateThingEvent struct {
 	id       string
+	owner    string
 	name     string
 	metadata map[string]interface{}
+	tags     []string
 }
 
 func (ute updateThingEvent) Encode() map[string]interface{} {
@@ -73,6 +187,10 @@ func (ute updateThingEvent) Encode() map[string]interface{} {
 		"operation": thingUpdate,
 	}
 
+	if ute.owner != "" {
+		val["owner"] = ute.owner
+	}
+
 	if ute.name != "" {
 		val["name"] = ute.name
 	}
@@ -86,18 +204,86 @@ func (ute updateThingEvent) Encode() map[string]interface{} {
 		val["metadata"] = string(metadata)
 	}
 
+	if ute.tags != nil {
+		tags, err := json.Marshal(ute.tags)
+		if err != nil {
+			return val
+		}
+
+		val["tags"] = string(tags)
+	}
+
 	return val
 }
 
-type removeThingEvent struct {
+type updateKeyEvent struct {
+	id    string
+	owner string
+}
+
+func (uke updateKeyEvent) Encode() map[string]interface{} {
+	return map[string]interface{}{
+		"id":        uke.id,
+		"owner":     uke.owner,
+		"operation": thingUpdateKey,
+	}
+}
+
+type rotateKeyEvent struct {
+	id        string
+	owner     string
+	key       string
+	expiresAt time.Time
+}
+
+func (rke rotateKeyEvent) Encode() map[string]interface{} {
+	return map[string]interface{}{
+		"id":         rke.id,
+		"owner":      rke.owner,
+		"key":        rke.key,
+		"expires_at": rke.expiresAt.Unix(),
+		"operation":  thingRotateKey,
+	}
+}
+
+type enableThingEvent struct {
 	id string
 }
 
-func (rte removeThingEvent) Encode() map[string]interface{} {
+func (ete enableThingEvent) Encode() map[string]interface{} {
+	return map[string]interface{}{
+		"id":        ete.id,
+		"operation": thingEnable,
+	}
+}
+
+type disableThingEvent struct {
+	id string
+}
+
+func (dte disableThingEvent) Encode() map[string]interface{} {
 	return map[string]interface{}{
+		"id":        dte.id,
+		"operation": thingDisable,
+	}
+}
+
+type removeThingEvent struct {
+	id    string
+	owner string
+}
+
+func (rte removeThingEvent) Encode() map[string]interface{} {
+	val := map[string]interface{}{
 		"id":        rte.id,
 		"operation": thingRemove,
 	}
+
+	if rte.owner != "" {
+		val["owner"] = rte.owner
+	}
+
+	return val
 }
 
 type createChannelEvent struct {
@@ -105,6 +291,7 @@ type createChannelEvent struct {
 	owner    string
 	name     string
 	metadata map[string]interface{}
+	tags     []string
 }
 
 func (cce createChannelEvent) Encode() map[string]interface{} {
@@ -127,13 +314,24 @@ func (cce createChannelEvent) Encode() map[string]interface{} {
 		val["metadata"] = string(metadata)
 	}
 
+	if cce.tags != nil {
+		tags, err := json.Marshal(cce.tags)
+		if err != nil {
+			return val
+		}
+
+		val["tags"] = string(tags)
+	}
+
 	return val
 }
 
 type updateChannelEvent struct {
 	id       string
+	owner    string
 	name     string
 	metadata map[string]interface{}
+	tags     []string
 }
 
 func (uce updateChannelEvent) Encode() map[string]interface{} {
@@ -142,6 +340,10 @@ func (uce updateChannelEvent) Encode() map[string]interface{} {
 		"operation": channelUpdate,
 	}
 
+	if uce.owner != "" {
+		val["owner"] = uce.owner
+	}
+
 	if uce.name != "" {
 		val["name"] = uce.name
 	}
@@ -155,42 +357,640 @@ func (uce updateChannelEvent) Encode() map[string]interface{} {
 		val["metadata"] = string(metadata)
 	}
 
+	if uce.tags != nil {
+		tags, err := json.Marshal(uce.tags)
+		if err != nil {
+			return val
+		}
+
+		val["tags"] = string(tags)
+	}
+
 	return val
 }
 
-type removeChannelEvent struct {
+type archiveChannelEvent struct {
 	id string
 }
 
-func (rce removeChannelEvent) Encode() map[string]interface{} {
+func (ace archiveChannelEvent) Encode() map[string]interface{} {
+	return map[string]interface{}{
+		"id":        ace.id,
+		"operation": channelArchive,
+	}
+}
+
+type unarchiveChannelEvent struct {
+	id string
+}
+
+func (uce unarchiveChannelEvent) Encode() map[string]interface{} {
 	return map[string]interface{}{
+		"id":        uce.id,
+		"operation": channelUnarchive,
+	}
+}
+
+type removeChannelEvent struct {
+	id    string
+	owner string
+}
+
+func (rce removeChannelEvent) Encode() map[string]interface{} {
+	val := map[string]interface{}{
 		"id":        rce.id,
 		"operation": channelRemove,
 	}
+
+	if rce.owner != "" {
+		val["owner"] = rce.owner
+	}
+
+	return val
 }
 
 type connectThingEvent struct {
 	chanID  string
 	thingID string
+	owner   string
+	access  string
 }
 
 func (cte connectThingEvent) Encode() map[string]interface{} {
-	return map[string]interface{}{
+	val := map[string]interface{}{
 		"chan_id":   cte.chanID,
 		"thing_id":  cte.thingID,
 		"operation": thingConnect,
 	}
+
+	if cte.owner != "" {
+		val["owner"] = cte.owner
+	}
+
+	if cte.access != "" {
+		val["access"] = cte.access
+	}
+
+	return val
 }
 
 type disconnectThingEvent struct {
 	chanID  string
 	thingID string
+	owner   string
 }
 
 func (dte disconnectThingEvent) Encode() map[string]interface{} {
-	return map[string]interface{}{
+	val := map[string]interface{}{
 		"chan_id":   dte.chanID,
 		"thing_id":  dte.thingID,
 		"operation": thingDisconnect,
 	}
+
+	if dte.owner != "" {
+		val["owner"] = dte.owner
+	}
+
+	return val
+}
+
+type transferThingEvent struct {
+	id        string
+	owner     string
+	newOwner  string
+	keepConns bool
+}
+
+func (tte transferThingEvent) Encode() map[string]interface{} {
+	val := map[string]interface{}{
+		"id":               tte.id,
+		"new_owner":        tte.newOwner,
+		"keep_connections": strconv.FormatBool(tte.keepConns),
+		"operation":        thingTransfer,
+	}
+
+	if tte.owner != "" {
+		val["owner"] = tte.owner
+	}
+
+	return val
+}
+
+type transferChannelEvent struct {
+	id        string
+	owner     string
+	newOwner  string
+	keepConns bool
+}
+
+func (tce transferChannelEvent) Encode() map[string]interface{} {
+	val := map[string]interface{}{
+		"id":               tce.id,
+		"new_owner":        tce.newOwner,
+		"keep_connections": strconv.FormatBool(tce.keepConns),
+		"operation":        channelTransfer,
+	}
+
+	if tce.owner != "" {
+		val["owner"] = tce.owner
+	}
+
+	return val
+}
+
+// issueThingKeyEvent doesn't carry the value of the issued key, since the
+// key shouldn't be sent over stream.
+type issueThingKeyEvent struct {
+	id      string
+	thingID string
+	owner   string
+	scope   string
+}
+
+func (ike issueThingKeyEvent) Encode() map[string]interface{} {
+	return map[string]interface{}{
+		"id":        ike.id,
+		"thing_id":  ike.thingID,
+		"owner":     ike.owner,
+		"scope":     ike.scope,
+		"operation": thingIssueKey,
+	}
+}
+
+type revokeThingKeyEvent struct {
+	id      string
+	thingID string
+	owner   string
+}
+
+func (rke revokeThingKeyEvent) Encode() map[string]interface{} {
+	val := map[string]interface{}{
+		"id":        rke.id,
+		"thing_id":  rke.thingID,
+		"operation": thingRevokeKey,
+	}
+
+	if rke.owner != "" {
+		val["owner"] = rke.owner
+	}
+
+	return val
+}
+
+type revokeSignedKeysEvent struct {
+	id    string
+	owner string
+}
+
+func (rke revokeSignedKeysEvent) Encode() map[string]interface{} {
+	val := map[string]interface{}{
+		"id":        rke.id,
+		"operation": thingRevokeKeys,
+	}
+
+	if rke.owner != "" {
+		val["owner"] = rke.owner
+	}
+
+	return val
+}
+
+// issueChannelTokenEvent doesn't carry the value of the issued token, since
+// the token shouldn't be sent over stream.
+type issueChannelTokenEvent struct {
+	id        string
+	chanID    string
+	owner     string
+	scope     string
+	expiresAt time.Time
+}
+
+func (ite issueChannelTokenEvent) Encode() map[string]interface{} {
+	return map[string]interface{}{
+		"id":         ite.id,
+		"chan_id":    ite.chanID,
+		"owner":      ite.owner,
+		"scope":      ite.scope,
+		"expires_at": ite.expiresAt.Unix(),
+		"operation":  channelIssueToken,
+	}
+}
+
+type revokeChannelTokenEvent struct {
+	id     string
+	chanID string
+	owner  string
+}
+
+func (rte revokeChannelTokenEvent) Encode() map[string]interface{} {
+	val := map[string]interface{}{
+		"id":        rte.id,
+		"chan_id":   rte.chanID,
+		"operation": channelRevokeToken,
+	}
+
+	if rte.owner != "" {
+		val["owner"] = rte.owner
+	}
+
+	return val
+}
+
+// shareEvent is sent as the thing or the channel event, depending on the
+// kind of the shared entity.
+type shareEvent struct {
+	kind       string
+	id         string
+	owner      string
+	user       string
+	permission string
+}
+
+func (se shareEvent) Encode() map[string]interface{} {
+	val := map[string]interface{}{
+		"id":         se.id,
+		"user":       se.user,
+		"permission": se.permission,
+		"operation":  thingShare,
+	}
+
+	if se.kind == things.ChannelKind {
+		val["operation"] = channelShare
+	}
+
+	if se.owner != "" {
+		val["owner"] = se.owner
+	}
+
+	return val
+}
+
+type unshareEvent struct {
+	kind  string
+	id    string
+	owner string
+	user  string
+}
+
+func (ue unshareEvent) Encode() map[string]interface{} {
+	val := map[string]interface{}{
+		"id":        ue.id,
+		"user":      ue.user,
+		"operation": thingUnshare,
+	}
+
+	if ue.kind == things.ChannelKind {
+		val["operation"] = channelUnshare
+	}
+
+	if ue.owner != "" {
+		val["owner"] = ue.owner
+	}
+
+	return val
+}
+
+type registerSubtopicEvent struct {
+	chanID      string
+	owner       string
+	subtopic    string
+	description string
+	schema      map[string]interface{}
+}
+
+func (rse registerSubtopicEvent) Encode() map[string]interface{} {
+	val := map[string]interface{}{
+		"id":        rse.chanID,
+		"subtopic":  rse.subtopic,
+		"operation": channelRegisterSubtopic,
+	}
+
+	if rse.owner != "" {
+		val["owner"] = rse.owner
+	}
+
+	if rse.description != "" {
+		val["description"] = rse.description
+	}
+
+	if rse.schema != nil {
+		schema, err := json.Marshal(rse.schema)
+		if err != nil {
+			return val
+		}
+
+		val["schema"] = string(schema)
+	}
+
+	return val
+}
+
+type removeSubtopicEvent struct {
+	chanID   string
+	owner    string
+	subtopic string
+}
+
+func (rse removeSubtopicEvent) Encode() map[string]interface{} {
+	val := map[string]interface{}{
+		"id":        rse.chanID,
+		"subtopic":  rse.subtopic,
+		"operation": channelRemoveSubtopic,
+	}
+
+	if rse.owner != "" {
+		val["owner"] = rse.owner
+	}
+
+	return val
+}
+
+type addSubtopicRuleEvent struct {
+	id      string
+	chanID  string
+	owner   string
+	thingID string
+	action  string
+	pattern string
+}
+
+func (are addSubtopicRuleEvent) Encode() map[string]interface{} {
+	return map[string]interface{}{
+		"id":        are.chanID,
+		"rule_id":   are.id,
+		"owner":     are.owner,
+		"thing_id":  are.thingID,
+		"action":    are.action,
+		"pattern":   are.pattern,
+		"operation": channelAddSubtopicRule,
+	}
+}
+
+type removeSubtopicRuleEvent struct {
+	id     string
+	chanID string
+	owner  string
+}
+
+func (rre removeSubtopicRuleEvent) Encode() map[string]interface{} {
+	val := map[string]interface{}{
+		"id":        rre.chanID,
+		"rule_id":   rre.id,
+		"operation": channelRemoveSubtopicRule,
+	}
+
+	if rre.owner != "" {
+		val["owner"] = rre.owner
+	}
+
+	return val
+}
+
+type createGroupEvent struct {
+	id       string
+	owner    string
+	parent   string
+	name     string
+	metadata map[string]interface{}
+}
+
+func (cge createGroupEvent) Encode() map[string]interface{} {
+	val := map[string]interface{}{
+		"id":        cge.id,
+		"owner":     cge.owner,
+		"operation": groupCreate,
+	}
+
+	if cge.parent != "" {
+		val["parent"] = cge.parent
+	}
+
+	if cge.name != "" {
+		val["name"] = cge.name
+	}
+
+	if cge.metadata != nil {
+		metadata, err := json.Marshal(cge.metadata)
+		if err != nil {
+			return val
+		}
+
+		val["metadata"] = string(metadata)
+	}
+
+	return val
+}
+
+type updateGroupEvent struct {
+	id       string
+	owner    string
+	parent   string
+	name     string
+	metadata map[string]interface{}
+}
+
+func (uge updateGroupEvent) Encode() map[string]interface{} {
+	val := map[string]interface{}{
+		"id":        uge.id,
+		"operation": groupUpdate,
+	}
+
+	if uge.owner != "" {
+		val["owner"] = uge.owner
+	}
+
+	if uge.parent != "" {
+		val["parent"] = uge.parent
+	}
+
+	if uge.name != "" {
+		val["name"] = uge.name
+	}
+
+	if uge.metadata != nil {
+		metadata, err := json.Marshal(uge.metadata)
+		if err != nil {
+			return val
+		}
+
+		val["metadata"] = string(metadata)
+	}
+
+	return val
+}
+
+type removeGroupEvent struct {
+	id    string
+	owner string
+}
+
+func (rge removeGroupEvent) Encode() map[string]interface{} {
+	val := map[string]interface{}{
+		"id":        rge.id,
+		"operation": groupRemove,
+	}
+
+	if rge.owner != "" {
+		val["owner"] = rge.owner
+	}
+
+	return val
+}
+
+type assignThingEvent struct {
+	groupID string
+	thingID string
+	owner   string
+}
+
+func (ate assignThingEvent) Encode() map[string]interface{} {
+	val := map[string]interface{}{
+		"id":        ate.groupID,
+		"thing_id":  ate.thingID,
+		"operation": groupAssign,
+	}
+
+	if ate.owner != "" {
+		val["owner"] = ate.owner
+	}
+
+	return val
+}
+
+type unassignThingEvent struct {
+	groupID string
+	thingID string
+	owner   string
+}
+
+func (ute unassignThingEvent) Encode() map[string]interface{} {
+	val := map[string]interface{}{
+		"id":        ute.groupID,
+		"thing_id":  ute.thingID,
+		"operation": groupUnassign,
+	}
+
+	if ute.owner != "" {
+		val["owner"] = ute.owner
+	}
+
+	return val
+}
+
+// createProfileEvent doesn't carry the bootstrap content of the profile,
+// which is passed on to the Bootstrap service along with the things
+// provisioned from the profile.
+type createProfileEvent struct {
+	id       string
+	owner    string
+	name     string
+	metadata map[string]interface{}
+	channels []string
+}
+
+func (cpe createProfileEvent) Encode() map[string]interface{} {
+	val := map[string]interface{}{
+		"id":        cpe.id,
+		"owner":     cpe.owner,
+		"operation": profileCreate,
+	}
+
+	if cpe.name != "" {
+		val["name"] = cpe.name
+	}
+
+	if cpe.metadata != nil {
+		metadata, err := json.Marshal(cpe.metadata)
+		if err != nil {
+			return val
+		}
+
+		val["metadata"] = string(metadata)
+	}
+
+	if cpe.channels != nil {
+		channels, err := json.Marshal(cpe.channels)
+		if err != nil {
+			return val
+		}
+
+		val["channels"] = string(channels)
+	}
+
+	return val
+}
+
+type updateProfileEvent struct {
+	id       string
+	owner    string
+	name     string
+	metadata map[string]interface{}
+	channels []string
+}
+
+func (upe updateProfileEvent) Encode() map[string]interface{} {
+	val := map[string]interface{}{
+		"id":        upe.id,
+		"operation": profileUpdate,
+	}
+
+	if upe.owner != "" {
+		val["owner"] = upe.owner
+	}
+
+	if upe.name != "" {
+		val["name"] = upe.name
+	}
+
+	if upe.metadata != nil {
+		metadata, err := json.Marshal(upe.metadata)
+		if err != nil {
+			return val
+		}
+
+		val["metadata"] = string(metadata)
+	}
+
+	if upe.channels != nil {
+		channels, err := json.Marshal(upe.channels)
+		if err != nil {
+			return val
+		}
+
+		val["channels"] = string(channels)
+	}
+
+	return val
+}
+
+type removeProfileEvent struct {
+	id    string
+	owner string
+}
+
+func (rpe removeProfileEvent) Encode() map[string]interface{} {
+	val := map[string]interface{}{
+		"id":        rpe.id,
+		"operation": profileRemove,
+	}
+
+	if rpe.owner != "" {
+		val["owner"] = rpe.owner
+	}
+
+	return val
+}
+
+// removeOwnerEvent is sent once all the entities of the removed user are
+// removed, so that the consumers drop the groups, profiles, templates and
+// shares of the user, whose removal isn't sent as separate events.
+type removeOwnerEvent struct {
+	owner string
+}
+
+func (roe removeOwnerEvent) Encode() map[string]interface{} {
+	return map[string]interface{}{
+		"owner":     roe.owner,
+		"operation": ownerRemove,
+	}
 }
@@ -0,0 +1,83 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package redis
+
+import (
+	"context"
+
+	"github.com/go-redis/redis"
+	"github.com/mainflux/mainflux/events"
+	"github.com/mainflux/mainflux/things"
+)
+
+// CacheInvalidator keeps the caches local to the service instance in sync
+// with the changes made through the other instances.
+type CacheInvalidator interface {
+	// Subscribe reads the things events and evicts the entries they affect
+	// from the caches.
+	Subscribe() error
+}
+
+type cacheInvalidator struct {
+	client       redis.UniversalClient
+	thingCache   things.ThingCache
+	channelCache things.ChannelCache
+}
+
+// NewCacheInvalidator returns new cache invalidator instance, reading the
+// events from the stream the things events are sent to. Unlike the event
+// store consumers, every instance reads all the events.
+func NewCacheInvalidator(client redis.UniversalClient, tc things.ThingCache, cc things.ChannelCache) CacheInvalidator {
+	return cacheInvalidator{
+		client:       client,
+		thingCache:   tc,
+		channelCache: cc,
+	}
+}
+
+func (ci cacheInvalidator) Subscribe() error {
+	lastID := "$"
+	for {
+		streams, err := ci.client.XRead(&redis.XReadArgs{
+			Streams: []string{streamID, lastID},
+			Count:   100,
+			Block:   0,
+		}).Result()
+		if err != nil || len(streams) == 0 {
+			continue
+		}
+
+		for _, msg := range streams[0].Messages {
+			ci.invalidate(events.Event(msg.Values))
+			lastID = msg.ID
+		}
+	}
+}
+
+func (ci cacheInvalidator) invalidate(event events.Event) {
+	ctx := context.Background()
+
+	switch event.Read("operation", "") {
+	case thingUpdateKey, thingRotateKey, thingDisable:
+		ci.thingCache.Remove(ctx, event.Read("id", ""))
+	case thingRemove:
+		id := event.Read("id", "")
+		ci.thingCache.Remove(ctx, id)
+		ci.channelCache.RemoveConnected(ctx, id)
+	case thingDisconnect:
+		thingID := event.Read("thing_id", "")
+		ci.channelCache.Disconnect(ctx, event.Read("chan_id", ""), thingID)
+		ci.channelCache.RemoveConnected(ctx, thingID)
+	case channelArchive:
+		ci.channelCache.SaveState(ctx, event.Read("id", ""), things.Archived)
+	case channelUnarchive:
+		ci.channelCache.SaveState(ctx, event.Read("id", ""), things.Active)
+	case channelRemove:
+		ci.channelCache.Remove(ctx, event.Read("id", ""))
+	}
+}
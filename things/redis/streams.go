@@ -8,6 +8,9 @@
 package redis
 
 import (
+	"context"
+	"time"
+
 	"github.com/go-redis/redis"
 	"github.com/mainflux/mainflux/things"
 )
@@ -20,100 +23,412 @@ const (
 var _ things.Service = (*eventStore)(nil)
 
 type eventStore struct {
-	svc    things.Service
-	client *redis.Client
+	svc        things.Service
+	client     redis.UniversalClient
+	publishers []things.EventPublisher
 }
 
 // NewEventStoreMiddleware returns wrapper around things service that sends
-// events to event store.
-func NewEventStoreMiddleware(svc things.Service, client *redis.Client) things.Service {
+// events to event store. Every event is passed to the provided publishers as
+// well, after it is added to the stream.
+func NewEventStoreMiddleware(svc things.Service, client redis.UniversalClient, publishers ...things.EventPublisher) things.Service {
 	return eventStore{
-		svc:    svc,
-		client: client,
+		svc:        svc,
+		client:     client,
+		publishers: publishers,
 	}
 }
 
-func (es eventStore) AddThing(token string, thing things.Thing) (things.Thing, error) {
-	sth, err := es.svc.AddThing(token, thing)
+func (es eventStore) AddThing(ctx context.Context, token string, thing things.Thing) (things.Thing, error) {
+	sth, err := es.svc.AddThing(ctx, token, thing)
 	if err != nil {
 		return sth, err
 	}
 
 	event := createThingEvent{
-		id:       sth.ID,
-		owner:    sth.Owner,
-		name:     sth.Name,
-		metadata: sth.Metadata,
-	}
-	record := &redis.XAddArgs{
-		Stream:       streamID,
-		MaxLenApprox: streamLen,
-		Values:       event.Encode(),
+		id:         sth.ID,
+		owner:      sth.Owner,
+		externalID: sth.ExternalID,
+		name:       sth.Name,
+		metadata:   sth.Metadata,
+		tags:       sth.Tags,
 	}
-	es.client.XAdd(record).Err()
+	es.add(event)
 
 	return sth, err
 }
 
-func (es eventStore) UpdateThing(token string, thing things.Thing) error {
-	if err := es.svc.UpdateThing(token, thing); err != nil {
+func (es eventStore) CreateThings(ctx context.Context, token string, ths ...things.Thing) ([]things.Thing, error) {
+	sths, err := es.svc.CreateThings(ctx, token, ths...)
+	if err != nil {
+		return sths, err
+	}
+
+	for _, sth := range sths {
+		event := createThingEvent{
+			id:         sth.ID,
+			owner:      sth.Owner,
+			externalID: sth.ExternalID,
+			name:       sth.Name,
+			metadata:   sth.Metadata,
+			tags:       sth.Tags,
+		}
+		es.add(event)
+	}
+
+	return sths, nil
+}
+
+// ProvisionThing sends the events of creating the thing and its channels,
+// and of connecting the thing to them, in that order. No events are sent if
+// the existing thing is returned.
+func (es eventStore) ProvisionThing(ctx context.Context, token, profileID string, thing things.Thing) (things.Topology, error) {
+	top, err := es.svc.ProvisionThing(ctx, token, profileID, thing)
+	if err != nil || top.Existing {
+		return top, err
+	}
+
+	connected := append(append([]things.Channel{}, top.Channels...), top.Connected...)
+	cte := createThingEvent{
+		id:         top.Thing.ID,
+		owner:      top.Thing.Owner,
+		externalID: top.Thing.ExternalID,
+		name:       top.Thing.Name,
+		metadata:   top.Thing.Metadata,
+		tags:       top.Thing.Tags,
+	}
+	if top.Bootstrap != "" {
+		cte.key = top.Thing.Key
+		cte.bootstrap = top.Bootstrap
+		cte.channels = []bootstrapChannel{}
+		for _, ch := range connected {
+			cte.channels = append(cte.channels, bootstrapChannel{
+				ID:       ch.ID,
+				Name:     ch.Name,
+				Metadata: ch.Metadata,
+			})
+		}
+	}
+
+	events := []event{cte}
+	for _, channel := range top.Channels {
+		events = append(events, createChannelEvent{
+			id:       channel.ID,
+			owner:    channel.Owner,
+			name:     channel.Name,
+			metadata: channel.Metadata,
+			tags:     channel.Tags,
+		})
+	}
+	for _, channel := range connected {
+		events = append(events, connectThingEvent{
+			chanID:  channel.ID,
+			thingID: top.Thing.ID,
+			owner:   channel.Owner,
+		})
+	}
+
+	es.add(events...)
+
+	return top, nil
+}
+
+func (es eventStore) SaveTemplate(ctx context.Context, token string, tmpl things.Template) error {
+	return es.svc.SaveTemplate(ctx, token, tmpl)
+}
+
+func (es eventStore) ViewTemplate(ctx context.Context, token string) (things.Template, error) {
+	return es.svc.ViewTemplate(ctx, token)
+}
+
+func (es eventStore) RemoveTemplate(ctx context.Context, token string) error {
+	return es.svc.RemoveTemplate(ctx, token)
+}
+
+func (es eventStore) CreateProfile(ctx context.Context, token string, profile things.Profile) (things.Profile, error) {
+	sp, err := es.svc.CreateProfile(ctx, token, profile)
+	if err != nil {
+		return sp, err
+	}
+
+	event := createProfileEvent{
+		id:       sp.ID,
+		owner:    sp.Owner,
+		name:     sp.Name,
+		metadata: sp.Metadata,
+		channels: sp.Channels,
+	}
+	es.add(event)
+
+	return sp, nil
+}
+
+func (es eventStore) UpdateProfile(ctx context.Context, token string, profile things.Profile) error {
+	if err := es.svc.UpdateProfile(ctx, token, profile); err != nil {
+		return err
+	}
+
+	event := updateProfileEvent{
+		id:       profile.ID,
+		owner:    es.profileOwner(ctx, token, profile.ID),
+		name:     profile.Name,
+		metadata: profile.Metadata,
+		channels: profile.Channels,
+	}
+	es.add(event)
+
+	return nil
+}
+
+func (es eventStore) ViewProfile(ctx context.Context, token, id string) (things.Profile, error) {
+	return es.svc.ViewProfile(ctx, token, id)
+}
+
+func (es eventStore) ListProfiles(ctx context.Context, token string, offset, limit uint64) (things.ProfilesPage, error) {
+	return es.svc.ListProfiles(ctx, token, offset, limit)
+}
+
+// RemoveProfile looks the owner of the profile up before removing it, since
+// the removed profile can't be viewed.
+func (es eventStore) RemoveProfile(ctx context.Context, token, id string) error {
+	owner := es.profileOwner(ctx, token, id)
+	if err := es.svc.RemoveProfile(ctx, token, id); err != nil {
+		return err
+	}
+
+	event := removeProfileEvent{
+		id:    id,
+		owner: owner,
+	}
+	es.add(event)
+
+	return nil
+}
+
+func (es eventStore) ReserveThings(ctx context.Context, token string, n uint64) ([]things.Reservation, error) {
+	return es.svc.ReserveThings(ctx, token, n)
+}
+
+func (es eventStore) UpdateThing(ctx context.Context, token string, thing things.Thing) error {
+	if err := es.svc.UpdateThing(ctx, token, thing); err != nil {
 		return err
 	}
 
 	event := updateThingEvent{
 		id:       thing.ID,
+		owner:    es.thingOwner(ctx, token, thing.ID),
 		name:     thing.Name,
 		metadata: thing.Metadata,
+		tags:     thing.Tags,
+	}
+	es.add(event)
+
+	return nil
+}
+
+func (es eventStore) PatchThing(ctx context.Context, token, id string, patch map[string]interface{}) (things.Thing, error) {
+	thing, err := es.svc.PatchThing(ctx, token, id, patch)
+	if err != nil {
+		return thing, err
+	}
+
+	event := updateThingEvent{
+		id:       thing.ID,
+		owner:    thing.Owner,
+		name:     thing.Name,
+		metadata: thing.Metadata,
+		tags:     thing.Tags,
+	}
+	es.add(event)
+
+	return thing, nil
+}
+
+func (es eventStore) UpdateThingsMetadata(ctx context.Context, token string, selector, patch map[string]interface{}) ([]things.Thing, error) {
+	updated, err := es.svc.UpdateThingsMetadata(ctx, token, selector, patch)
+	if err != nil {
+		return updated, err
+	}
+
+	for _, thing := range updated {
+		event := updateThingEvent{
+			id:       thing.ID,
+			owner:    thing.Owner,
+			name:     thing.Name,
+			metadata: thing.Metadata,
+			tags:     thing.Tags,
+		}
+		es.add(event)
+	}
+
+	return updated, nil
+}
+
+// UpdateKey sends the event without the key value, since the key shouldn't
+// be sent over stream.
+func (es eventStore) UpdateKey(ctx context.Context, token, id, key string, expiresAt time.Time, rotation time.Duration) error {
+	if err := es.svc.UpdateKey(ctx, token, id, key, expiresAt, rotation); err != nil {
+		return err
 	}
-	record := &redis.XAddArgs{
-		Stream:       streamID,
-		MaxLenApprox: streamLen,
-		Values:       event.Encode(),
+
+	thing, err := es.svc.ViewThing(ctx, token, id)
+	if err != nil {
+		return err
 	}
-	es.client.XAdd(record).Err()
+
+	event := updateKeyEvent{
+		id:    id,
+		owner: thing.Owner,
+	}
+	es.add(event)
 
 	return nil
 }
 
-// UpdateKey doesn't send event because key shouldn't be sent over stream.
-// Maybe we can start publishing this event at some point, without key value
-// in order to notify adapters to disconnect connected things after key update.
-func (es eventStore) UpdateKey(token, id, key string) error {
-	return es.svc.UpdateKey(token, id, key)
+// RotateKeys sends the events holding the new keys, so that the bootstrap
+// service serves them to the devices. The events are sent for the rotated
+// keys even if the rotation of the remaining ones failed.
+func (es eventStore) RotateKeys(ctx context.Context, since time.Time) ([]things.Thing, error) {
+	rotated, err := es.svc.RotateKeys(ctx, since)
+
+	for _, thing := range rotated {
+		event := rotateKeyEvent{
+			id:        thing.ID,
+			owner:     thing.Owner,
+			key:       thing.Key,
+			expiresAt: thing.KeyExpiresAt,
+		}
+		es.add(event)
+	}
+
+	return rotated, err
 }
 
-func (es eventStore) ViewThing(token, id string) (things.Thing, error) {
-	return es.svc.ViewThing(token, id)
+func (es eventStore) OnboardThing(ctx context.Context, token, id string) (things.Onboarding, string, error) {
+	return es.svc.OnboardThing(ctx, token, id)
 }
 
-func (es eventStore) ListThings(token string, offset, limit uint64, name string) (things.ThingsPage, error) {
-	return es.svc.ListThings(token, offset, limit, name)
+func (es eventStore) IssueKey(ctx context.Context, token, id string, ttl time.Duration) (things.SignedKey, string, error) {
+	return es.svc.IssueKey(ctx, token, id, ttl)
 }
 
-func (es eventStore) ListThingsByChannel(token, id string, offset, limit uint64) (things.ThingsPage, error) {
-	return es.svc.ListThingsByChannel(token, id, offset, limit)
+func (es eventStore) OpenSession(ctx context.Context, key string, ttl time.Duration) (things.SignedKey, string, error) {
+	return es.svc.OpenSession(ctx, key, ttl)
 }
 
-func (es eventStore) RemoveThing(token, id string) error {
-	if err := es.svc.RemoveThing(token, id); err != nil {
+func (es eventStore) RevokeKeys(ctx context.Context, token, id string) error {
+	if err := es.svc.RevokeKeys(ctx, token, id); err != nil {
 		return err
 	}
 
-	event := removeThingEvent{
+	event := revokeSignedKeysEvent{
+		id:    id,
+		owner: es.thingOwner(ctx, token, id),
+	}
+	es.add(event)
+
+	return nil
+}
+
+// IssueThingKey sends the event without the key value, since the key
+// shouldn't be sent over stream.
+func (es eventStore) IssueThingKey(ctx context.Context, token, id string, scope things.KeyScope) (things.ThingKey, error) {
+	tk, err := es.svc.IssueThingKey(ctx, token, id, scope)
+	if err != nil {
+		return tk, err
+	}
+
+	event := issueThingKeyEvent{
+		id:      tk.ID,
+		thingID: tk.ThingID,
+		owner:   tk.Owner,
+		scope:   string(tk.Scope),
+	}
+	es.add(event)
+
+	return tk, nil
+}
+
+func (es eventStore) ListThingKeys(ctx context.Context, token, id string) ([]things.ThingKey, error) {
+	return es.svc.ListThingKeys(ctx, token, id)
+}
+
+func (es eventStore) RevokeThingKey(ctx context.Context, token, id, keyID string) error {
+	if err := es.svc.RevokeThingKey(ctx, token, id, keyID); err != nil {
+		return err
+	}
+
+	event := revokeThingKeyEvent{
+		id:      keyID,
+		thingID: id,
+		owner:   es.thingOwner(ctx, token, id),
+	}
+	es.add(event)
+
+	return nil
+}
+
+func (es eventStore) ViewThing(ctx context.Context, token, id string) (things.Thing, error) {
+	return es.svc.ViewThing(ctx, token, id)
+}
+
+func (es eventStore) ListThings(ctx context.Context, token string, offset, limit uint64, name string, metadata map[string]interface{}, tags things.TagFilter, status things.Status, cursor string) (things.ThingsPage, error) {
+	return es.svc.ListThings(ctx, token, offset, limit, name, metadata, tags, status, cursor)
+}
+
+func (es eventStore) CountThings(ctx context.Context, token, name string, metadata map[string]interface{}, tags things.TagFilter, status things.Status) (uint64, error) {
+	return es.svc.CountThings(ctx, token, name, metadata, tags, status)
+}
+
+func (es eventStore) ListThingsByChannel(ctx context.Context, token, id string, offset, limit uint64) (things.ThingsPage, error) {
+	return es.svc.ListThingsByChannel(ctx, token, id, offset, limit)
+}
+
+func (es eventStore) EnableThing(ctx context.Context, token, id string) error {
+	if err := es.svc.EnableThing(ctx, token, id); err != nil {
+		return err
+	}
+
+	event := enableThingEvent{
+		id: id,
+	}
+	es.add(event)
+
+	return nil
+}
+
+func (es eventStore) DisableThing(ctx context.Context, token, id string) error {
+	if err := es.svc.DisableThing(ctx, token, id); err != nil {
+		return err
+	}
+
+	event := disableThingEvent{
 		id: id,
 	}
-	record := &redis.XAddArgs{
-		Stream:       streamID,
-		MaxLenApprox: streamLen,
-		Values:       event.Encode(),
+	es.add(event)
+
+	return nil
+}
+
+// RemoveThing looks the owner of the thing up before removing it, since the
+// removed thing can't be viewed.
+func (es eventStore) RemoveThing(ctx context.Context, token, id string) error {
+	owner := es.thingOwner(ctx, token, id)
+	if err := es.svc.RemoveThing(ctx, token, id); err != nil {
+		return err
+	}
+
+	event := removeThingEvent{
+		id:    id,
+		owner: owner,
 	}
-	es.client.XAdd(record).Err()
+	es.add(event)
 
 	return nil
 }
 
-func (es eventStore) CreateChannel(token string, channel things.Channel) (things.Channel, error) {
-	sch, err := es.svc.CreateChannel(token, channel)
+func (es eventStore) CreateChannel(ctx context.Context, token string, channel things.Channel) (things.Channel, error) {
+	sch, err := es.svc.CreateChannel(ctx, token, channel)
 	if err != nil {
 		return sch, err
 	}
@@ -123,109 +438,712 @@ func (es eventStore) CreateChannel(token string, channel things.Channel) (things
 		owner:    sch.Owner,
 		name:     sch.Name,
 		metadata: sch.Metadata,
+		tags:     sch.Tags,
 	}
-	record := &redis.XAddArgs{
-		Stream:       streamID,
-		MaxLenApprox: streamLen,
-		Values:       event.Encode(),
-	}
-	es.client.XAdd(record).Err()
+	es.add(event)
 
 	return sch, err
 }
 
-func (es eventStore) UpdateChannel(token string, channel things.Channel) error {
-	if err := es.svc.UpdateChannel(token, channel); err != nil {
+func (es eventStore) UpdateChannel(ctx context.Context, token string, channel things.Channel) error {
+	if err := es.svc.UpdateChannel(ctx, token, channel); err != nil {
 		return err
 	}
 
 	event := updateChannelEvent{
 		id:       channel.ID,
+		owner:    es.channelOwner(ctx, token, channel.ID),
 		name:     channel.Name,
 		metadata: channel.Metadata,
+		tags:     channel.Tags,
 	}
-	record := &redis.XAddArgs{
-		Stream:       streamID,
-		MaxLenApprox: streamLen,
-		Values:       event.Encode(),
-	}
-	es.client.XAdd(record).Err()
+	es.add(event)
 
 	return nil
 }
 
-func (es eventStore) ViewChannel(token, id string) (things.Channel, error) {
-	return es.svc.ViewChannel(token, id)
+func (es eventStore) PatchChannel(ctx context.Context, token, id string, patch map[string]interface{}) (things.Channel, error) {
+	channel, err := es.svc.PatchChannel(ctx, token, id, patch)
+	if err != nil {
+		return channel, err
+	}
+
+	event := updateChannelEvent{
+		id:       channel.ID,
+		owner:    channel.Owner,
+		name:     channel.Name,
+		metadata: channel.Metadata,
+		tags:     channel.Tags,
+	}
+	es.add(event)
+
+	return channel, nil
+}
+
+func (es eventStore) ViewChannel(ctx context.Context, token, id string) (things.Channel, error) {
+	return es.svc.ViewChannel(ctx, token, id)
+}
+
+func (es eventStore) Capabilities(ctx context.Context, token, kind, id string) (things.Capabilities, error) {
+	return es.svc.Capabilities(ctx, token, kind, id)
 }
 
-func (es eventStore) ListChannels(token string, offset, limit uint64, name string) (things.ChannelsPage, error) {
-	return es.svc.ListChannels(token, offset, limit, name)
+func (es eventStore) ListChannels(ctx context.Context, token string, offset, limit uint64, name string, metadata map[string]interface{}, tags things.TagFilter, cursor string) (things.ChannelsPage, error) {
+	return es.svc.ListChannels(ctx, token, offset, limit, name, metadata, tags, cursor)
 }
 
-func (es eventStore) ListChannelsByThing(token, id string, offset, limit uint64) (things.ChannelsPage, error) {
-	return es.svc.ListChannelsByThing(token, id, offset, limit)
+func (es eventStore) CountChannels(ctx context.Context, token, name string, metadata map[string]interface{}, tags things.TagFilter) (uint64, error) {
+	return es.svc.CountChannels(ctx, token, name, metadata, tags)
 }
 
-func (es eventStore) RemoveChannel(token, id string) error {
-	if err := es.svc.RemoveChannel(token, id); err != nil {
+func (es eventStore) ListChannelsByThing(ctx context.Context, token, id string, offset, limit uint64) (things.ChannelsPage, error) {
+	return es.svc.ListChannelsByThing(ctx, token, id, offset, limit)
+}
+
+// RemoveChannel looks the owner of the channel up before removing it, since
+// the removed channel can't be viewed.
+func (es eventStore) RemoveChannel(ctx context.Context, token, id string) error {
+	owner := es.channelOwner(ctx, token, id)
+	if err := es.svc.RemoveChannel(ctx, token, id); err != nil {
 		return err
 	}
 
 	event := removeChannelEvent{
+		id:    id,
+		owner: owner,
+	}
+	es.add(event)
+
+	return nil
+}
+
+func (es eventStore) ArchiveChannel(ctx context.Context, token, id string) error {
+	if err := es.svc.ArchiveChannel(ctx, token, id); err != nil {
+		return err
+	}
+
+	event := archiveChannelEvent{
 		id: id,
 	}
-	record := &redis.XAddArgs{
-		Stream:       streamID,
-		MaxLenApprox: streamLen,
-		Values:       event.Encode(),
+	es.add(event)
+
+	return nil
+}
+
+func (es eventStore) UnarchiveChannel(ctx context.Context, token, id string) error {
+	if err := es.svc.UnarchiveChannel(ctx, token, id); err != nil {
+		return err
+	}
+
+	event := unarchiveChannelEvent{
+		id: id,
 	}
-	es.client.XAdd(record).Err()
+	es.add(event)
 
 	return nil
 }
 
-func (es eventStore) Connect(token, chanID, thingID string) error {
-	if err := es.svc.Connect(token, chanID, thingID); err != nil {
+func (es eventStore) ArchiveChannels(ctx context.Context, token string, selector map[string]interface{}, tags things.TagFilter) ([]things.Channel, error) {
+	archived, err := es.svc.ArchiveChannels(ctx, token, selector, tags)
+	if err != nil {
+		return archived, err
+	}
+
+	for _, channel := range archived {
+		event := archiveChannelEvent{
+			id: channel.ID,
+		}
+		es.add(event)
+	}
+
+	return archived, nil
+}
+
+func (es eventStore) InviteToChannel(ctx context.Context, token, id string, ttl time.Duration) (things.Invitation, string, error) {
+	return es.svc.InviteToChannel(ctx, token, id, ttl)
+}
+
+// IssueChannelToken sends the event without the token value, since the
+// token shouldn't be sent over stream.
+func (es eventStore) IssueChannelToken(ctx context.Context, token, id string, scope things.KeyScope, ttl time.Duration) (things.ChannelToken, error) {
+	ct, err := es.svc.IssueChannelToken(ctx, token, id, scope, ttl)
+	if err != nil {
+		return ct, err
+	}
+
+	event := issueChannelTokenEvent{
+		id:        ct.ID,
+		chanID:    ct.ChannelID,
+		owner:     ct.Owner,
+		scope:     string(ct.Scope),
+		expiresAt: ct.ExpiresAt,
+	}
+	es.add(event)
+
+	return ct, nil
+}
+
+func (es eventStore) ListChannelTokens(ctx context.Context, token, id string) ([]things.ChannelToken, error) {
+	return es.svc.ListChannelTokens(ctx, token, id)
+}
+
+func (es eventStore) RevokeChannelToken(ctx context.Context, token, id, tokenID string) error {
+	if err := es.svc.RevokeChannelToken(ctx, token, id, tokenID); err != nil {
 		return err
 	}
 
-	event := connectThingEvent{
-		chanID:  chanID,
-		thingID: thingID,
+	event := revokeChannelTokenEvent{
+		id:     tokenID,
+		chanID: id,
+		owner:  es.channelOwner(ctx, token, id),
+	}
+	es.add(event)
+
+	return nil
+}
+
+func (es eventStore) Connect(ctx context.Context, token string, chanIDs, thingIDs []string, access things.ConnectionAccess) error {
+	if err := es.svc.Connect(ctx, token, chanIDs, thingIDs, access); err != nil {
+		return err
 	}
-	record := &redis.XAddArgs{
-		Stream:       streamID,
-		MaxLenApprox: streamLen,
-		Values:       event.Encode(),
+
+	for _, chanID := range chanIDs {
+		owner := es.channelOwner(ctx, token, chanID)
+		for _, thingID := range thingIDs {
+			event := connectThingEvent{
+				chanID:  chanID,
+				thingID: thingID,
+				owner:   owner,
+				access:  string(access),
+			}
+			es.add(event)
+		}
 	}
-	es.client.XAdd(record).Err()
 
 	return nil
 }
 
-func (es eventStore) Disconnect(token, chanID, thingID string) error {
-	if err := es.svc.Disconnect(token, chanID, thingID); err != nil {
+func (es eventStore) Disconnect(ctx context.Context, token string, chanIDs, thingIDs []string) error {
+	if err := es.svc.Disconnect(ctx, token, chanIDs, thingIDs); err != nil {
 		return err
 	}
 
-	event := disconnectThingEvent{
-		chanID:  chanID,
+	for _, chanID := range chanIDs {
+		owner := es.channelOwner(ctx, token, chanID)
+		for _, thingID := range thingIDs {
+			event := disconnectThingEvent{
+				chanID:  chanID,
+				thingID: thingID,
+				owner:   owner,
+			}
+			es.add(event)
+		}
+	}
+
+	return nil
+}
+
+func (es eventStore) Export(ctx context.Context, token string) (things.Snapshot, error) {
+	return es.svc.Export(ctx, token)
+}
+
+// Import sends the events of creating the imported things and channels,
+// and of connecting them, in that order.
+func (es eventStore) Import(ctx context.Context, token string, snapshot things.Snapshot, preserve bool) (things.Snapshot, error) {
+	imported, err := es.svc.Import(ctx, token, snapshot, preserve)
+	if err != nil {
+		return imported, err
+	}
+
+	events := []event{}
+	for _, th := range imported.Things {
+		events = append(events, createThingEvent{
+			id:       th.ID,
+			owner:    th.Owner,
+			name:     th.Name,
+			metadata: th.Metadata,
+			tags:     th.Tags,
+		})
+	}
+	for _, ch := range imported.Channels {
+		events = append(events, createChannelEvent{
+			id:       ch.ID,
+			owner:    ch.Owner,
+			name:     ch.Name,
+			metadata: ch.Metadata,
+			tags:     ch.Tags,
+		})
+	}
+	for _, conn := range imported.Connections {
+		events = append(events, connectThingEvent{
+			chanID:  conn.ChanID,
+			thingID: conn.ThingID,
+			owner:   conn.Owner,
+			access:  string(conn.Access),
+		})
+	}
+
+	es.add(events...)
+
+	return imported, nil
+}
+
+func (es eventStore) CreateGroup(ctx context.Context, token string, group things.Group) (things.Group, error) {
+	sg, err := es.svc.CreateGroup(ctx, token, group)
+	if err != nil {
+		return sg, err
+	}
+
+	event := createGroupEvent{
+		id:       sg.ID,
+		owner:    sg.Owner,
+		parent:   sg.Parent,
+		name:     sg.Name,
+		metadata: sg.Metadata,
+	}
+	es.add(event)
+
+	return sg, nil
+}
+
+func (es eventStore) UpdateGroup(ctx context.Context, token string, group things.Group) error {
+	if err := es.svc.UpdateGroup(ctx, token, group); err != nil {
+		return err
+	}
+
+	event := updateGroupEvent{
+		id:       group.ID,
+		owner:    es.groupOwner(ctx, token, group.ID),
+		parent:   group.Parent,
+		name:     group.Name,
+		metadata: group.Metadata,
+	}
+	es.add(event)
+
+	return nil
+}
+
+func (es eventStore) ViewGroup(ctx context.Context, token, id string) (things.Group, error) {
+	return es.svc.ViewGroup(ctx, token, id)
+}
+
+func (es eventStore) ListGroups(ctx context.Context, token, parent string, offset, limit uint64) (things.GroupsPage, error) {
+	return es.svc.ListGroups(ctx, token, parent, offset, limit)
+}
+
+func (es eventStore) ListThingsByGroup(ctx context.Context, token, id string, offset, limit uint64) (things.ThingsPage, error) {
+	return es.svc.ListThingsByGroup(ctx, token, id, offset, limit)
+}
+
+// RemoveGroup looks the owner of the group up before removing it, since the
+// removed group can't be viewed. The event is sent for the removed group
+// only, the removal of its subgroups is implied.
+func (es eventStore) RemoveGroup(ctx context.Context, token, id string) error {
+	owner := es.groupOwner(ctx, token, id)
+	if err := es.svc.RemoveGroup(ctx, token, id); err != nil {
+		return err
+	}
+
+	event := removeGroupEvent{
+		id:    id,
+		owner: owner,
+	}
+	es.add(event)
+
+	return nil
+}
+
+func (es eventStore) AssignThings(ctx context.Context, token, id string, thingIDs ...string) error {
+	if err := es.svc.AssignThings(ctx, token, id, thingIDs...); err != nil {
+		return err
+	}
+
+	owner := es.groupOwner(ctx, token, id)
+	for _, thingID := range thingIDs {
+		event := assignThingEvent{
+			groupID: id,
+			thingID: thingID,
+			owner:   owner,
+		}
+		es.add(event)
+	}
+
+	return nil
+}
+
+func (es eventStore) UnassignThing(ctx context.Context, token, id, thingID string) error {
+	if err := es.svc.UnassignThing(ctx, token, id, thingID); err != nil {
+		return err
+	}
+
+	event := unassignThingEvent{
+		groupID: id,
 		thingID: thingID,
+		owner:   es.groupOwner(ctx, token, id),
+	}
+	es.add(event)
+
+	return nil
+}
+
+// ConnectGroup emits the regular connect event for each newly connected
+// thing, so that the consumers needn't be aware of groups.
+func (es eventStore) ConnectGroup(ctx context.Context, token, id, chanID string) ([]string, error) {
+	ids, err := es.svc.ConnectGroup(ctx, token, id, chanID)
+	if err != nil {
+		return nil, err
+	}
+
+	owner := es.channelOwner(ctx, token, chanID)
+	for _, thingID := range ids {
+		event := connectThingEvent{
+			chanID:  chanID,
+			thingID: thingID,
+			owner:   owner,
+		}
+		es.add(event)
+	}
+
+	return ids, nil
+}
+
+func (es eventStore) CanAccess(ctx context.Context, chanID, key, action, subtopic string) (string, error) {
+	return es.svc.CanAccess(ctx, chanID, key, action, subtopic)
+}
+
+func (es eventStore) Identify(ctx context.Context, key string) (string, error) {
+	return es.svc.Identify(ctx, key)
+}
+
+func (es eventStore) IdentifyBatch(ctx context.Context, keys []string) ([]string, error) {
+	return es.svc.IdentifyBatch(ctx, keys)
+}
+
+func (es eventStore) CanRead(ctx context.Context, chanID, token string) (string, error) {
+	return es.svc.CanRead(ctx, chanID, token)
+}
+
+func (es eventStore) ConnectedChannels(ctx context.Context, thingID string) ([]string, error) {
+	return es.svc.ConnectedChannels(ctx, thingID)
+}
+
+// RemoveUserHandler sends the events of removing the user's things and
+// channels, so that the consumers drop the state they keep about them. The
+// events are sent for the removed entities even if removal of the remaining
+// ones failed, while the owner removal event is sent only once all the
+// entities of the user are removed.
+func (es eventStore) RemoveUserHandler(ctx context.Context, owner string) ([]things.Thing, []things.Channel, error) {
+	ths, chs, err := es.svc.RemoveUserHandler(ctx, owner)
+
+	events := []event{}
+	for _, thing := range ths {
+		events = append(events, removeThingEvent{
+			id:    thing.ID,
+			owner: owner,
+		})
 	}
-	record := &redis.XAddArgs{
-		Stream:       streamID,
-		MaxLenApprox: streamLen,
-		Values:       event.Encode(),
+	for _, channel := range chs {
+		events = append(events, removeChannelEvent{
+			id:    channel.ID,
+			owner: owner,
+		})
 	}
-	es.client.XAdd(record).Err()
+	if err == nil {
+		events = append(events, removeOwnerEvent{
+			owner: owner,
+		})
+	}
+	es.add(events...)
+
+	return ths, chs, err
+}
+
+func (es eventStore) RecordUsage(ctx context.Context, thingID, chanID string, size uint64) error {
+	return es.svc.RecordUsage(ctx, thingID, chanID, size)
+}
+
+func (es eventStore) RecordActivity(ctx context.Context, thingID string) error {
+	return es.svc.RecordActivity(ctx, thingID)
+}
+
+func (es eventStore) RecordDiagnostics(ctx context.Context, thingID string, payload []byte) error {
+	return es.svc.RecordDiagnostics(ctx, thingID, payload)
+}
+
+func (es eventStore) ViewHealth(ctx context.Context, token, id string) (things.Health, error) {
+	return es.svc.ViewHealth(ctx, token, id)
+}
+
+func (es eventStore) ListUnhealthy(ctx context.Context, token string, thresholds things.HealthThresholds, offset, limit uint64) (things.HealthPage, error) {
+	return es.svc.ListUnhealthy(ctx, token, thresholds, offset, limit)
+}
+
+func (es eventStore) ThingUsage(ctx context.Context, token, id string, from, to time.Time) (things.Usage, error) {
+	return es.svc.ThingUsage(ctx, token, id, from, to)
+}
+
+func (es eventStore) ChannelUsage(ctx context.Context, token, id string, from, to time.Time) (things.Usage, error) {
+	return es.svc.ChannelUsage(ctx, token, id, from, to)
+}
+
+func (es eventStore) RegisterSubtopic(ctx context.Context, token, chanID string, subtopic things.Subtopic) error {
+	if err := es.svc.RegisterSubtopic(ctx, token, chanID, subtopic); err != nil {
+		return err
+	}
+
+	event := registerSubtopicEvent{
+		chanID:      chanID,
+		owner:       es.channelOwner(ctx, token, chanID),
+		subtopic:    subtopic.Name,
+		description: subtopic.Description,
+		schema:      subtopic.Schema,
+	}
+	es.add(event)
 
 	return nil
 }
 
-func (es eventStore) CanAccess(chanID string, key string) (string, error) {
-	return es.svc.CanAccess(chanID, key)
+func (es eventStore) ListSubtopics(ctx context.Context, token, chanID string) ([]things.Subtopic, error) {
+	return es.svc.ListSubtopics(ctx, token, chanID)
 }
 
-func (es eventStore) Identify(key string) (string, error) {
-	return es.svc.Identify(key)
+func (es eventStore) RemoveSubtopic(ctx context.Context, token, chanID, name string) error {
+	if err := es.svc.RemoveSubtopic(ctx, token, chanID, name); err != nil {
+		return err
+	}
+
+	event := removeSubtopicEvent{
+		chanID:   chanID,
+		owner:    es.channelOwner(ctx, token, chanID),
+		subtopic: name,
+	}
+	es.add(event)
+
+	return nil
+}
+
+func (es eventStore) ObservedSubtopics(ctx context.Context, token, chanID string) ([]things.ObservedSubtopic, error) {
+	return es.svc.ObservedSubtopics(ctx, token, chanID)
+}
+
+func (es eventStore) RecordSubtopic(ctx context.Context, chanID, subtopic string) error {
+	return es.svc.RecordSubtopic(ctx, chanID, subtopic)
+}
+
+func (es eventStore) AddSubtopicRule(ctx context.Context, token, chanID string, rule things.SubtopicRule) (things.SubtopicRule, error) {
+	sr, err := es.svc.AddSubtopicRule(ctx, token, chanID, rule)
+	if err != nil {
+		return sr, err
+	}
+
+	event := addSubtopicRuleEvent{
+		id:      sr.ID,
+		chanID:  sr.Channel,
+		owner:   sr.Owner,
+		thingID: sr.ThingID,
+		action:  sr.Action,
+		pattern: sr.Pattern,
+	}
+	es.add(event)
+
+	return sr, nil
+}
+
+func (es eventStore) ListSubtopicRules(ctx context.Context, token, chanID string) ([]things.SubtopicRule, error) {
+	return es.svc.ListSubtopicRules(ctx, token, chanID)
+}
+
+func (es eventStore) RemoveSubtopicRule(ctx context.Context, token, chanID, id string) error {
+	if err := es.svc.RemoveSubtopicRule(ctx, token, chanID, id); err != nil {
+		return err
+	}
+
+	event := removeSubtopicRuleEvent{
+		id:     id,
+		chanID: chanID,
+		owner:  es.channelOwner(ctx, token, chanID),
+	}
+	es.add(event)
+
+	return nil
+}
+
+func (es eventStore) Share(ctx context.Context, token string, share things.Share) error {
+	if err := es.svc.Share(ctx, token, share); err != nil {
+		return err
+	}
+
+	event := shareEvent{
+		kind:       share.Kind,
+		id:         share.EntityID,
+		owner:      es.entityOwner(ctx, token, share.Kind, share.EntityID),
+		user:       share.User,
+		permission: string(share.Permission),
+	}
+	es.add(event)
+
+	return nil
+}
+
+func (es eventStore) Unshare(ctx context.Context, token, kind, id, user string) error {
+	if err := es.svc.Unshare(ctx, token, kind, id, user); err != nil {
+		return err
+	}
+
+	event := unshareEvent{
+		kind:  kind,
+		id:    id,
+		owner: es.entityOwner(ctx, token, kind, id),
+		user:  user,
+	}
+	es.add(event)
+
+	return nil
+}
+
+func (es eventStore) ListShares(ctx context.Context, token, kind, id string) ([]things.Share, error) {
+	return es.svc.ListShares(ctx, token, kind, id)
+}
+
+func (es eventStore) ThingHistory(ctx context.Context, token, id string, offset, limit uint64) (things.ChangesPage, error) {
+	return es.svc.ThingHistory(ctx, token, id, offset, limit)
+}
+
+func (es eventStore) ChannelHistory(ctx context.Context, token, id string, offset, limit uint64) (things.ChangesPage, error) {
+	return es.svc.ChannelHistory(ctx, token, id, offset, limit)
+}
+
+// TransferThing looks the owner of the thing up before transferring it, so
+// that the event names both the previous and the new owner.
+func (es eventStore) TransferThing(ctx context.Context, token, id, newOwner string, keepConns bool) error {
+	owner := es.thingOwner(ctx, token, id)
+	if err := es.svc.TransferThing(ctx, token, id, newOwner, keepConns); err != nil {
+		return err
+	}
+
+	es.add(transferThingEvent{
+		id:        id,
+		owner:     owner,
+		newOwner:  newOwner,
+		keepConns: keepConns,
+	})
+
+	return nil
+}
+
+// TransferChannel looks the owner of the channel up before transferring it,
+// so that the event names both the previous and the new owner.
+func (es eventStore) TransferChannel(ctx context.Context, token, id, newOwner string, keepConns bool) error {
+	owner := es.channelOwner(ctx, token, id)
+	if err := es.svc.TransferChannel(ctx, token, id, newOwner, keepConns); err != nil {
+		return err
+	}
+
+	es.add(transferChannelEvent{
+		id:        id,
+		owner:     owner,
+		newOwner:  newOwner,
+		keepConns: keepConns,
+	})
+
+	return nil
+}
+
+func (es eventStore) Audit(ctx context.Context, token string, query things.AuditQuery, offset, limit uint64) (things.AuditPage, error) {
+	return es.svc.Audit(ctx, token, query, offset, limit)
+}
+
+// add adds the events to the stream and passes them, stripped of the
+// secret fields, to the publishers. The failures are ignored, since the
+// change the events describe has already been made.
+func (es eventStore) add(events ...event) {
+	for _, event := range events {
+		values := event.Encode()
+		record := &redis.XAddArgs{
+			Stream:       streamID,
+			MaxLenApprox: streamLen,
+			Values:       values,
+		}
+		es.client.XAdd(record).Err()
+
+		if len(es.publishers) == 0 {
+			continue
+		}
+
+		public := stripSecrets(values)
+		for _, pub := range es.publishers {
+			pub.Publish(public)
+		}
+	}
+}
+
+// stripSecrets returns the copy of the event values without the secret
+// fields.
+func stripSecrets(values map[string]interface{}) map[string]interface{} {
+	public := make(map[string]interface{}, len(values))
+	for k, v := range values {
+		public[k] = v
+	}
+
+	for _, field := range secretFields {
+		delete(public, field)
+	}
+
+	return public
+}
+
+// thingOwner returns the owner of the thing, which the events of the
+// operations that don't return the thing are stamped with, so that the
+// consumers can tell whose thing changed. An empty string is returned if the
+// thing can't be viewed.
+func (es eventStore) thingOwner(ctx context.Context, token, id string) string {
+	thing, err := es.svc.ViewThing(ctx, token, id)
+	if err != nil {
+		return ""
+	}
+
+	return thing.Owner
+}
+
+// channelOwner returns the owner of the channel, or an empty string if the
+// channel can't be viewed.
+func (es eventStore) channelOwner(ctx context.Context, token, id string) string {
+	channel, err := es.svc.ViewChannel(ctx, token, id)
+	if err != nil {
+		return ""
+	}
+
+	return channel.Owner
+}
+
+// groupOwner returns the owner of the group, or an empty string if the group
+// can't be viewed.
+func (es eventStore) groupOwner(ctx context.Context, token, id string) string {
+	group, err := es.svc.ViewGroup(ctx, token, id)
+	if err != nil {
+		return ""
+	}
+
+	return group.Owner
+}
+
+// profileOwner returns the owner of the profile, or an empty string if the
+// profile can't be viewed.
+func (es eventStore) profileOwner(ctx context.Context, token, id string) string {
+	profile, err := es.svc.ViewProfile(ctx, token, id)
+	if err != nil {
+		return ""
+	}
+
+	return profile.Owner
+}
+
+// entityOwner returns the owner of the thing or the channel, depending on
+// the kind of the entity.
+func (es eventStore) entityOwner(ctx context.Context, token, kind, id string) string {
+	if kind == things.ChannelKind {
+		return es.channelOwner(ctx, token, id)
+	}
+
+	return es.thingOwner(ctx, token, id)
 }
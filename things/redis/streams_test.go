@@ -8,6 +8,7 @@
 package redis_test
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"strconv"
@@ -15,6 +16,7 @@ import (
 	"time"
 
 	r "github.com/go-redis/redis"
+	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/things"
 	"github.com/mainflux/mainflux/things/mocks"
 	"github.com/mainflux/mainflux/things/redis"
@@ -29,14 +31,46 @@ const (
 	thingPrefix     = "thing."
 	thingCreate     = thingPrefix + "create"
 	thingUpdate     = thingPrefix + "update"
+	thingUpdateKey  = thingPrefix + "update_key"
+	thingRotateKey  = thingPrefix + "rotate_key"
+	thingEnable     = thingPrefix + "enable"
+	thingDisable    = thingPrefix + "disable"
 	thingRemove     = thingPrefix + "remove"
+	thingTransfer   = thingPrefix + "transfer"
 	thingConnect    = thingPrefix + "connect"
 	thingDisconnect = thingPrefix + "disconnect"
-
-	channelPrefix = "channel."
-	channelCreate = channelPrefix + "create"
-	channelUpdate = channelPrefix + "update"
-	channelRemove = channelPrefix + "remove"
+	thingIssueKey   = thingPrefix + "issue_key"
+	thingRevokeKey  = thingPrefix + "revoke_key"
+	thingRevokeKeys = thingPrefix + "revoke_signed_keys"
+	thingShare      = thingPrefix + "share"
+	thingUnshare    = thingPrefix + "unshare"
+
+	channelPrefix             = "channel."
+	channelCreate             = channelPrefix + "create"
+	channelUpdate             = channelPrefix + "update"
+	channelRemove             = channelPrefix + "remove"
+	channelIssueToken         = channelPrefix + "issue_token"
+	channelRevokeToken        = channelPrefix + "revoke_token"
+	channelShare              = channelPrefix + "share"
+	channelUnshare            = channelPrefix + "unshare"
+	channelRegisterSubtopic   = channelPrefix + "register_subtopic"
+	channelRemoveSubtopic     = channelPrefix + "remove_subtopic"
+	channelAddSubtopicRule    = channelPrefix + "add_subtopic_rule"
+	channelRemoveSubtopicRule = channelPrefix + "remove_subtopic_rule"
+
+	groupPrefix   = "group."
+	groupCreate   = groupPrefix + "create"
+	groupUpdate   = groupPrefix + "update"
+	groupRemove   = groupPrefix + "remove"
+	groupAssign   = groupPrefix + "assign"
+	groupUnassign = groupPrefix + "unassign"
+
+	profilePrefix = "profile."
+	profileCreate = profilePrefix + "create"
+	profileUpdate = profilePrefix + "update"
+	profileRemove = profilePrefix + "remove"
+
+	ownerRemove = "owner.remove"
 )
 
 func newService(tokens map[string]string) things.Service {
@@ -44,11 +78,27 @@ func newService(tokens map[string]string) things.Service {
 	conns := make(chan mocks.Connection)
 	thingsRepo := mocks.NewThingRepository(conns)
 	channelsRepo := mocks.NewChannelRepository(thingsRepo, conns)
+	reservationsRepo := mocks.NewReservationRepository()
+	usageRepo := mocks.NewUsageRepository()
+	historyRepo := mocks.NewHistoryRepository()
+	auditRepo := mocks.NewAuditRepository()
 	chanCache := mocks.NewChannelCache()
 	thingCache := mocks.NewThingCache()
 	idp := mocks.NewIdentityProvider()
-
-	return things.New(users, thingsRepo, channelsRepo, chanCache, thingCache, idp)
+	onboarding := mocks.NewOnboardingProvider()
+	keys := mocks.NewKeyProvider()
+	revocations := mocks.NewRevocationRepository()
+	templates := mocks.NewTemplateRepository()
+	groups := mocks.NewGroupRepository(thingsRepo, channelsRepo)
+	subtopics := mocks.NewSubtopicRepository()
+	observations := mocks.NewObservationRepository()
+	thingKeys := mocks.NewThingKeyRepository(thingsRepo, channelsRepo)
+	shares := mocks.NewShareRepository()
+	profiles := mocks.NewProfileRepository()
+	rules := mocks.NewSubtopicRuleRepository()
+	health := mocks.NewHealthRepository()
+
+	return things.New(users, thingsRepo, channelsRepo, reservationsRepo, usageRepo, historyRepo, auditRepo, chanCache, thingCache, idp, onboarding, keys, revocations, templates, groups, subtopics, observations, thingKeys, shares, profiles, rules, health, mocks.NewChannelTokenRepository(channelsRepo), mocks.NewOverrideProvider(nil), things.Limits{}, 0)
 }
 
 func TestAddThing(t *testing.T) {
@@ -73,7 +123,7 @@ func TestAddThing(t *testing.T) {
 			key: token,
 			err: nil,
 			event: map[string]interface{}{
-				"id":        "1",
+				"id":        "123e4567-e89b-12d3-a456-000000000001",
 				"name":      "a",
 				"owner":     email,
 				"metadata":  "{\"test\":\"test\"}",
@@ -84,7 +134,7 @@ func TestAddThing(t *testing.T) {
 
 	lastID := "0"
 	for _, tc := range cases {
-		_, err := svc.AddThing(tc.key, tc.thing)
+		_, err := svc.AddThing(context.Background(), tc.key, tc.thing)
 		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
 
 		streams := redisClient.XRead(&r.XReadArgs{
@@ -104,13 +154,62 @@ func TestAddThing(t *testing.T) {
 	}
 }
 
+func TestProvisionThing(t *testing.T) {
+	redisClient.FlushAll().Err()
+
+	svc := newService(map[string]string{token: email})
+	tmpl := things.Template{Channels: []things.ChannelTemplate{{Name: "{name}-data"}}}
+	err := svc.SaveTemplate(context.Background(), token, tmpl)
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+
+	svc = redis.NewEventStoreMiddleware(svc, redisClient)
+
+	top, err := svc.ProvisionThing(context.Background(), token, "", things.Thing{Name: "a"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	require.Len(t, top.Channels, 1, fmt.Sprintf("expected one channel got %d", len(top.Channels)))
+
+	expected := []map[string]interface{}{
+		{
+			"id":        top.Thing.ID,
+			"name":      "a",
+			"owner":     email,
+			"operation": thingCreate,
+		},
+		{
+			"id":        top.Channels[0].ID,
+			"name":      "a-data",
+			"owner":     email,
+			"operation": channelCreate,
+		},
+		{
+			"chan_id":   top.Channels[0].ID,
+			"thing_id":  top.Thing.ID,
+			"owner":     email,
+			"operation": thingConnect,
+		},
+	}
+
+	streams := redisClient.XRead(&r.XReadArgs{
+		Streams: []string{streamID, "0"},
+		Count:   int64(len(expected)),
+		Block:   time.Second,
+	}).Val()
+	require.Len(t, streams, 1, "expected events stream")
+
+	events := []map[string]interface{}{}
+	for _, msg := range streams[0].Messages {
+		events = append(events, msg.Values)
+	}
+	assert.Equal(t, expected, events, fmt.Sprintf("expected %v got %v\n", expected, events))
+}
+
 func TestUpdateThing(t *testing.T) {
 	redisClient.FlushAll().Err()
 
 	svc := newService(map[string]string{token: email})
 	// Create thing without sending event.
 	th := things.Thing{Name: "a", Metadata: map[string]interface{}{"test": "test"}}
-	sth, err := svc.AddThing(token, th)
+	sth, err := svc.AddThing(context.Background(), token, th)
 	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
 
 	svc = redis.NewEventStoreMiddleware(svc, redisClient)
@@ -135,6 +234,7 @@ func TestUpdateThing(t *testing.T) {
 				"id":        sth.ID,
 				"name":      "a",
 				"metadata":  "{\"test\":\"test\"}",
+				"owner":     email,
 				"operation": thingUpdate,
 			},
 		},
@@ -142,7 +242,7 @@ func TestUpdateThing(t *testing.T) {
 
 	lastID := "0"
 	for _, tc := range cases {
-		err := svc.UpdateThing(tc.key, tc.thing)
+		err := svc.UpdateThing(context.Background(), tc.key, tc.thing)
 		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
 
 		streams := redisClient.XRead(&r.XReadArgs{
@@ -167,12 +267,12 @@ func TestViewThing(t *testing.T) {
 
 	svc := newService(map[string]string{token: email})
 	// Create thing without sending event.
-	sth, err := svc.AddThing(token, things.Thing{Name: "a"})
+	sth, err := svc.AddThing(context.Background(), token, things.Thing{Name: "a"})
 	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
 
 	essvc := redis.NewEventStoreMiddleware(svc, redisClient)
-	esth, eserr := essvc.ViewThing(token, sth.ID)
-	th, err := svc.ViewThing(token, sth.ID)
+	esth, eserr := essvc.ViewThing(context.Background(), token, sth.ID)
+	th, err := svc.ViewThing(context.Background(), token, sth.ID)
 	assert.Equal(t, th, esth, fmt.Sprintf("event sourcing changed service behaviour: expected %v got %v", th, esth))
 	assert.Equal(t, err, eserr, fmt.Sprintf("event sourcing changed service behaviour: expected %v got %v", err, eserr))
 }
@@ -182,12 +282,12 @@ func TestListThings(t *testing.T) {
 
 	svc := newService(map[string]string{token: email})
 	// Create thing without sending event.
-	_, err := svc.AddThing(token, things.Thing{Name: "a"})
+	_, err := svc.AddThing(context.Background(), token, things.Thing{Name: "a"})
 	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
 
 	essvc := redis.NewEventStoreMiddleware(svc, redisClient)
-	esths, eserr := essvc.ListThings(token, 0, 10, "")
-	ths, err := svc.ListThings(token, 0, 10, "")
+	esths, eserr := essvc.ListThings(context.Background(), token, 0, 10, "", nil, things.TagFilter{}, "", "")
+	ths, err := svc.ListThings(context.Background(), token, 0, 10, "", nil, things.TagFilter{}, "", "")
 	assert.Equal(t, ths, esths, fmt.Sprintf("event sourcing changed service behaviour: expected %v got %v", ths, esths))
 	assert.Equal(t, err, eserr, fmt.Sprintf("event sourcing changed service behaviour: expected %v got %v", err, eserr))
 }
@@ -197,26 +297,247 @@ func TestListThingsByChannel(t *testing.T) {
 
 	svc := newService(map[string]string{token: email})
 	// Create thing without sending event.
-	sth, err := svc.AddThing(token, things.Thing{Name: "a"})
+	sth, err := svc.AddThing(context.Background(), token, things.Thing{Name: "a"})
 	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
-	sch, err := svc.CreateChannel(token, things.Channel{Name: "a"})
+	sch, err := svc.CreateChannel(context.Background(), token, things.Channel{Name: "a"})
 	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
-	err = svc.Connect(token, sch.ID, sth.ID)
+	err = svc.Connect(context.Background(), token, []string{sch.ID}, []string{sth.ID}, things.FullAccess)
 	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
 
 	essvc := redis.NewEventStoreMiddleware(svc, redisClient)
-	esths, eserr := essvc.ListThingsByChannel(token, sch.ID, 0, 10)
-	ths, err := svc.ListThingsByChannel(token, sch.ID, 0, 10)
+	esths, eserr := essvc.ListThingsByChannel(context.Background(), token, sch.ID, 0, 10)
+	ths, err := svc.ListThingsByChannel(context.Background(), token, sch.ID, 0, 10)
 	assert.Equal(t, ths, esths, fmt.Sprintf("event sourcing changed service behaviour: expected %v got %v", ths, esths))
 	assert.Equal(t, err, eserr, fmt.Sprintf("event sourcing changed service behaviour: expected %v got %v", err, eserr))
 }
 
+func TestUpdateKey(t *testing.T) {
+	redisClient.FlushAll().Err()
+
+	svc := newService(map[string]string{token: email})
+	// Create thing without sending event.
+	sth, err := svc.AddThing(context.Background(), token, things.Thing{Name: "a"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+
+	svc = redis.NewEventStoreMiddleware(svc, redisClient)
+
+	cases := []struct {
+		desc  string
+		id    string
+		key   string
+		err   error
+		event map[string]interface{}
+	}{
+		{
+			desc: "update key of existing thing successfully",
+			id:   sth.ID,
+			key:  token,
+			err:  nil,
+			event: map[string]interface{}{
+				"id":        sth.ID,
+				"owner":     email,
+				"operation": thingUpdateKey,
+			},
+		},
+		{
+			desc:  "update key with invalid credentials",
+			id:    sth.ID,
+			key:   "",
+			err:   things.ErrUnauthorizedAccess,
+			event: nil,
+		},
+	}
+
+	lastID := "0"
+	for _, tc := range cases {
+		err := svc.UpdateKey(context.Background(), tc.key, tc.id, "new-key", time.Time{}, 0)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+
+		streams := redisClient.XRead(&r.XReadArgs{
+			Streams: []string{streamID, lastID},
+			Count:   1,
+			Block:   time.Second,
+		}).Val()
+
+		var event map[string]interface{}
+		if len(streams) > 0 && len(streams[0].Messages) > 0 {
+			msg := streams[0].Messages[0]
+			event = msg.Values
+			lastID = msg.ID
+		}
+
+		assert.Equal(t, tc.event, event, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.event, event))
+	}
+}
+
+func TestRotateKeys(t *testing.T) {
+	redisClient.FlushAll().Err()
+
+	svc := newService(map[string]string{token: email})
+	// Create thing without sending event.
+	sth, err := svc.AddThing(context.Background(), token, things.Thing{Name: "a"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	since := time.Now()
+	err = svc.UpdateKey(context.Background(), token, sth.ID, "expiring-key", since.Add(100*time.Millisecond), time.Hour)
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	time.Sleep(200 * time.Millisecond)
+
+	publisher := &publisherMock{}
+	svc = redis.NewEventStoreMiddleware(svc, redisClient, publisher)
+
+	rotated, err := svc.RotateKeys(context.Background(), since)
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	require.Len(t, rotated, 1, fmt.Sprintf("expected 1 rotated key got %d", len(rotated)))
+
+	streams := redisClient.XRead(&r.XReadArgs{
+		Streams: []string{streamID, "0"},
+		Count:   1,
+		Block:   time.Second,
+	}).Val()
+
+	var event map[string]interface{}
+	if len(streams) > 0 && len(streams[0].Messages) > 0 {
+		event = streams[0].Messages[0].Values
+	}
+
+	expected := map[string]interface{}{
+		"id":         sth.ID,
+		"owner":      email,
+		"key":        rotated[0].Key,
+		"expires_at": strconv.FormatInt(rotated[0].KeyExpiresAt.Unix(), 10),
+		"operation":  thingRotateKey,
+	}
+	assert.Equal(t, expected, event, fmt.Sprintf("rotate keys: expected %v got %v\n", expected, event))
+
+	delete(expected, "key")
+	require.Len(t, publisher.events, 1, fmt.Sprintf("expected single published event got %d", len(publisher.events)))
+	assert.Equal(t, expected, publisher.events[0], fmt.Sprintf("rotate keys: expected published event %v got %v\n", expected, publisher.events[0]))
+}
+
+func TestEnableThing(t *testing.T) {
+	redisClient.FlushAll().Err()
+
+	svc := newService(map[string]string{token: email})
+	// Create thing without sending event.
+	sth, err := svc.AddThing(context.Background(), token, things.Thing{Name: "a"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	err = svc.DisableThing(context.Background(), token, sth.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+
+	svc = redis.NewEventStoreMiddleware(svc, redisClient)
+
+	cases := []struct {
+		desc  string
+		id    string
+		key   string
+		err   error
+		event map[string]interface{}
+	}{
+		{
+			desc: "enable existing thing successfully",
+			id:   sth.ID,
+			key:  token,
+			err:  nil,
+			event: map[string]interface{}{
+				"id":        sth.ID,
+				"operation": thingEnable,
+			},
+		},
+		{
+			desc:  "enable thing with invalid credentials",
+			id:    sth.ID,
+			key:   "",
+			err:   things.ErrUnauthorizedAccess,
+			event: nil,
+		},
+	}
+
+	lastID := "0"
+	for _, tc := range cases {
+		err := svc.EnableThing(context.Background(), tc.key, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+
+		streams := redisClient.XRead(&r.XReadArgs{
+			Streams: []string{streamID, lastID},
+			Count:   1,
+			Block:   time.Second,
+		}).Val()
+
+		var event map[string]interface{}
+		if len(streams) > 0 && len(streams[0].Messages) > 0 {
+			msg := streams[0].Messages[0]
+			event = msg.Values
+			lastID = msg.ID
+		}
+
+		assert.Equal(t, tc.event, event, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.event, event))
+	}
+}
+
+func TestDisableThing(t *testing.T) {
+	redisClient.FlushAll().Err()
+
+	svc := newService(map[string]string{token: email})
+	// Create thing without sending event.
+	sth, err := svc.AddThing(context.Background(), token, things.Thing{Name: "a"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+
+	svc = redis.NewEventStoreMiddleware(svc, redisClient)
+
+	cases := []struct {
+		desc  string
+		id    string
+		key   string
+		err   error
+		event map[string]interface{}
+	}{
+		{
+			desc: "disable existing thing successfully",
+			id:   sth.ID,
+			key:  token,
+			err:  nil,
+			event: map[string]interface{}{
+				"id":        sth.ID,
+				"operation": thingDisable,
+			},
+		},
+		{
+			desc:  "disable thing with invalid credentials",
+			id:    sth.ID,
+			key:   "",
+			err:   things.ErrUnauthorizedAccess,
+			event: nil,
+		},
+	}
+
+	lastID := "0"
+	for _, tc := range cases {
+		err := svc.DisableThing(context.Background(), tc.key, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+
+		streams := redisClient.XRead(&r.XReadArgs{
+			Streams: []string{streamID, lastID},
+			Count:   1,
+			Block:   time.Second,
+		}).Val()
+
+		var event map[string]interface{}
+		if len(streams) > 0 && len(streams[0].Messages) > 0 {
+			msg := streams[0].Messages[0]
+			event = msg.Values
+			lastID = msg.ID
+		}
+
+		assert.Equal(t, tc.event, event, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.event, event))
+	}
+}
+
 func TestRemoveThing(t *testing.T) {
 	redisClient.FlushAll().Err()
 
 	svc := newService(map[string]string{token: email})
 	// Create thing without sending event.
-	sth, err := svc.AddThing(token, things.Thing{Name: "a"})
+	sth, err := svc.AddThing(context.Background(), token, things.Thing{Name: "a"})
 	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
 
 	svc = redis.NewEventStoreMiddleware(svc, redisClient)
@@ -235,6 +556,7 @@ func TestRemoveThing(t *testing.T) {
 			err:  nil,
 			event: map[string]interface{}{
 				"id":        sth.ID,
+				"owner":     email,
 				"operation": thingRemove,
 			},
 		},
@@ -249,7 +571,81 @@ func TestRemoveThing(t *testing.T) {
 
 	lastID := "0"
 	for _, tc := range cases {
-		err := svc.RemoveThing(tc.key, tc.id)
+		err := svc.RemoveThing(context.Background(), tc.key, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+
+		streams := redisClient.XRead(&r.XReadArgs{
+			Streams: []string{streamID, lastID},
+			Count:   1,
+			Block:   time.Second,
+		}).Val()
+
+		var event map[string]interface{}
+		if len(streams) > 0 && len(streams[0].Messages) > 0 {
+			msg := streams[0].Messages[0]
+			event = msg.Values
+			lastID = msg.ID
+		}
+
+		assert.Equal(t, tc.event, event, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.event, event))
+	}
+}
+
+type publisherMock struct {
+	events []map[string]interface{}
+}
+
+func (pm *publisherMock) Publish(event map[string]interface{}) error {
+	pm.events = append(pm.events, event)
+	return nil
+}
+
+func TestTransferThing(t *testing.T) {
+	redisClient.FlushAll().Err()
+
+	svc := newService(map[string]string{token: email, "new-owner-token": "new.owner@example.com"})
+	// Create thing without sending event.
+	sth, err := svc.AddThing(context.Background(), token, things.Thing{Name: "a"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+
+	publisher := &publisherMock{}
+	svc = redis.NewEventStoreMiddleware(svc, redisClient, publisher)
+
+	cases := []struct {
+		desc     string
+		id       string
+		key      string
+		newOwner string
+		err      error
+		event    map[string]interface{}
+	}{
+		{
+			desc:     "transfer existing thing successfully",
+			id:       sth.ID,
+			key:      token,
+			newOwner: "new.owner@example.com",
+			err:      nil,
+			event: map[string]interface{}{
+				"id":               sth.ID,
+				"owner":            email,
+				"new_owner":        "new.owner@example.com",
+				"keep_connections": "false",
+				"operation":        thingTransfer,
+			},
+		},
+		{
+			desc:     "transfer thing with invalid credentials",
+			id:       sth.ID,
+			key:      "",
+			newOwner: "new.owner@example.com",
+			err:      things.ErrUnauthorizedAccess,
+			event:    nil,
+		},
+	}
+
+	lastID := "0"
+	for _, tc := range cases {
+		err := svc.TransferThing(context.Background(), tc.key, tc.id, tc.newOwner, false)
 		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
 
 		streams := redisClient.XRead(&r.XReadArgs{
@@ -267,6 +663,9 @@ func TestRemoveThing(t *testing.T) {
 
 		assert.Equal(t, tc.event, event, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.event, event))
 	}
+
+	require.Len(t, publisher.events, 1, fmt.Sprintf("expected single published event got %d", len(publisher.events)))
+	assert.Equal(t, cases[0].event, publisher.events[0], fmt.Sprintf("expected published event %v got %v", cases[0].event, publisher.events[0]))
 }
 
 func TestCreateChannel(t *testing.T) {
@@ -288,7 +687,7 @@ func TestCreateChannel(t *testing.T) {
 			key:     token,
 			err:     nil,
 			event: map[string]interface{}{
-				"id":        "1",
+				"id":        "123e4567-e89b-12d3-a456-000000000001",
 				"name":      "a",
 				"metadata":  "{\"test\":\"test\"}",
 				"owner":     email,
@@ -306,7 +705,7 @@ func TestCreateChannel(t *testing.T) {
 
 	lastID := "0"
 	for _, tc := range cases {
-		_, err := svc.CreateChannel(tc.key, tc.channel)
+		_, err := svc.CreateChannel(context.Background(), tc.key, tc.channel)
 		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
 
 		streams := redisClient.XRead(&r.XReadArgs{
@@ -331,7 +730,7 @@ func TestUpdateChannel(t *testing.T) {
 
 	svc := newService(map[string]string{token: email})
 	// Create channel without sending event.
-	sch, err := svc.CreateChannel(token, things.Channel{Name: "a"})
+	sch, err := svc.CreateChannel(context.Background(), token, things.Channel{Name: "a"})
 	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
 
 	svc = redis.NewEventStoreMiddleware(svc, redisClient)
@@ -356,6 +755,7 @@ func TestUpdateChannel(t *testing.T) {
 				"id":        sch.ID,
 				"name":      "b",
 				"metadata":  "{\"test\":\"test\"}",
+				"owner":     email,
 				"operation": channelUpdate,
 			},
 		},
@@ -373,7 +773,7 @@ func TestUpdateChannel(t *testing.T) {
 
 	lastID := "0"
 	for _, tc := range cases {
-		err := svc.UpdateChannel(tc.key, tc.channel)
+		err := svc.UpdateChannel(context.Background(), tc.key, tc.channel)
 		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
 
 		streams := redisClient.XRead(&r.XReadArgs{
@@ -398,12 +798,12 @@ func TestViewChannel(t *testing.T) {
 
 	svc := newService(map[string]string{token: email})
 	// Create channel without sending event.
-	sch, err := svc.CreateChannel(token, things.Channel{Name: "a"})
+	sch, err := svc.CreateChannel(context.Background(), token, things.Channel{Name: "a"})
 	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
 
 	essvc := redis.NewEventStoreMiddleware(svc, redisClient)
-	esch, eserr := essvc.ViewChannel(token, sch.ID)
-	ch, err := svc.ViewChannel(token, sch.ID)
+	esch, eserr := essvc.ViewChannel(context.Background(), token, sch.ID)
+	ch, err := svc.ViewChannel(context.Background(), token, sch.ID)
 	assert.Equal(t, ch, esch, fmt.Sprintf("event sourcing changed service behaviour: expected %v got %v", ch, esch))
 	assert.Equal(t, err, eserr, fmt.Sprintf("event sourcing changed service behaviour: expected %v got %v", err, eserr))
 }
@@ -413,12 +813,12 @@ func TestListChannels(t *testing.T) {
 
 	svc := newService(map[string]string{token: email})
 	// Create thing without sending event.
-	_, err := svc.CreateChannel(token, things.Channel{Name: "a"})
+	_, err := svc.CreateChannel(context.Background(), token, things.Channel{Name: "a"})
 	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
 
 	essvc := redis.NewEventStoreMiddleware(svc, redisClient)
-	eschs, eserr := essvc.ListChannels(token, 0, 10, "")
-	chs, err := svc.ListChannels(token, 0, 10, "")
+	eschs, eserr := essvc.ListChannels(context.Background(), token, 0, 10, "", nil, things.TagFilter{}, "")
+	chs, err := svc.ListChannels(context.Background(), token, 0, 10, "", nil, things.TagFilter{}, "")
 	assert.Equal(t, chs, eschs, fmt.Sprintf("event sourcing changed service behaviour: expected %v got %v", chs, eschs))
 	assert.Equal(t, err, eserr, fmt.Sprintf("event sourcing changed service behaviour: expected %v got %v", err, eserr))
 }
@@ -428,16 +828,16 @@ func TestListChannelsByThing(t *testing.T) {
 
 	svc := newService(map[string]string{token: email})
 	// Create thing without sending event.
-	sth, err := svc.AddThing(token, things.Thing{Name: "a"})
+	sth, err := svc.AddThing(context.Background(), token, things.Thing{Name: "a"})
 	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
-	sch, err := svc.CreateChannel(token, things.Channel{Name: "a"})
+	sch, err := svc.CreateChannel(context.Background(), token, things.Channel{Name: "a"})
 	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
-	err = svc.Connect(token, sch.ID, sth.ID)
+	err = svc.Connect(context.Background(), token, []string{sch.ID}, []string{sth.ID}, things.FullAccess)
 	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
 
 	essvc := redis.NewEventStoreMiddleware(svc, redisClient)
-	eschs, eserr := essvc.ListChannelsByThing(token, sth.ID, 0, 10)
-	chs, err := svc.ListChannelsByThing(token, sth.ID, 0, 10)
+	eschs, eserr := essvc.ListChannelsByThing(context.Background(), token, sth.ID, 0, 10)
+	chs, err := svc.ListChannelsByThing(context.Background(), token, sth.ID, 0, 10)
 	assert.Equal(t, chs, eschs, fmt.Sprintf("event sourcing changed service behaviour: expected %v got %v", chs, eschs))
 	assert.Equal(t, err, eserr, fmt.Sprintf("event sourcing changed service behaviour: expected %v got %v", err, eserr))
 }
@@ -447,7 +847,7 @@ func TestRemoveChannel(t *testing.T) {
 
 	svc := newService(map[string]string{token: email})
 	// Create channel without sending event.
-	sch, err := svc.CreateChannel(token, things.Channel{Name: "a"})
+	sch, err := svc.CreateChannel(context.Background(), token, things.Channel{Name: "a"})
 	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
 
 	svc = redis.NewEventStoreMiddleware(svc, redisClient)
@@ -466,6 +866,7 @@ func TestRemoveChannel(t *testing.T) {
 			err:  nil,
 			event: map[string]interface{}{
 				"id":        sch.ID,
+				"owner":     email,
 				"operation": channelRemove,
 			},
 		},
@@ -480,7 +881,7 @@ func TestRemoveChannel(t *testing.T) {
 
 	lastID := "0"
 	for _, tc := range cases {
-		err := svc.RemoveChannel(tc.key, tc.id)
+		err := svc.RemoveChannel(context.Background(), tc.key, tc.id)
 		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
 
 		streams := redisClient.XRead(&r.XReadArgs{
@@ -500,14 +901,61 @@ func TestRemoveChannel(t *testing.T) {
 	}
 }
 
+func TestRemoveUserHandler(t *testing.T) {
+	redisClient.FlushAll().Err()
+
+	svc := newService(map[string]string{token: email})
+	// Create thing and channel without sending events.
+	sth, err := svc.AddThing(context.Background(), token, things.Thing{Name: "a"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	sch, err := svc.CreateChannel(context.Background(), token, things.Channel{Name: "a"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+
+	svc = redis.NewEventStoreMiddleware(svc, redisClient)
+
+	_, _, err = svc.RemoveUserHandler(context.Background(), email)
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+
+	streams := redisClient.XRead(&r.XReadArgs{
+		Streams: []string{streamID, "0"},
+		Count:   3,
+		Block:   time.Second,
+	}).Val()
+
+	events := []map[string]interface{}{}
+	if len(streams) > 0 {
+		for _, msg := range streams[0].Messages {
+			events = append(events, msg.Values)
+		}
+	}
+
+	expected := []map[string]interface{}{
+		{
+			"id":        sth.ID,
+			"owner":     email,
+			"operation": thingRemove,
+		},
+		{
+			"id":        sch.ID,
+			"owner":     email,
+			"operation": channelRemove,
+		},
+		{
+			"owner":     email,
+			"operation": ownerRemove,
+		},
+	}
+	assert.Equal(t, expected, events, fmt.Sprintf("remove user: expected %v got %v\n", expected, events))
+}
+
 func TestConnectEvent(t *testing.T) {
 	redisClient.FlushAll().Err()
 
 	svc := newService(map[string]string{token: email})
 	// Create thing and channel that will be connected.
-	sth, err := svc.AddThing(token, things.Thing{Name: "a"})
+	sth, err := svc.AddThing(context.Background(), token, things.Thing{Name: "a"})
 	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
-	sch, err := svc.CreateChannel(token, things.Channel{Name: "a"})
+	sch, err := svc.CreateChannel(context.Background(), token, things.Channel{Name: "a"})
 	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
 
 	svc = redis.NewEventStoreMiddleware(svc, redisClient)
@@ -529,6 +977,7 @@ func TestConnectEvent(t *testing.T) {
 			event: map[string]interface{}{
 				"chan_id":   sch.ID,
 				"thing_id":  sth.ID,
+				"owner":     email,
 				"operation": thingConnect,
 			},
 		},
@@ -537,6 +986,83 @@ func TestConnectEvent(t *testing.T) {
 			thingID: strconv.FormatUint(math.MaxUint64, 10),
 			chanID:  sch.ID,
 			key:     token,
+			err:     connectionError(sch.ID, strconv.FormatUint(math.MaxUint64, 10)),
+			event:   nil,
+		},
+	}
+
+	lastID := "0"
+	for _, tc := range cases {
+		err := svc.Connect(context.Background(), tc.key, []string{tc.chanID}, []string{tc.thingID}, things.FullAccess)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+
+		streams := redisClient.XRead(&r.XReadArgs{
+			Streams: []string{streamID, lastID},
+			Count:   1,
+			Block:   time.Second,
+		}).Val()
+
+		var event map[string]interface{}
+		if len(streams) > 0 && len(streams[0].Messages) > 0 {
+			msg := streams[0].Messages[0]
+			event = msg.Values
+			lastID = msg.ID
+		}
+
+		assert.Equal(t, tc.event, event, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.event, event))
+	}
+}
+
+func TestConnectGroupEvent(t *testing.T) {
+	redisClient.FlushAll().Err()
+
+	svc := newService(map[string]string{token: email})
+	// Create group of single thing and channel that will be connected.
+	sth, err := svc.AddThing(context.Background(), token, things.Thing{Name: "a"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	sgr, err := svc.CreateGroup(context.Background(), token, things.Group{Name: "a"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	err = svc.AssignThings(context.Background(), token, sgr.ID, sth.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	sch, err := svc.CreateChannel(context.Background(), token, things.Channel{Name: "a"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+
+	svc = redis.NewEventStoreMiddleware(svc, redisClient)
+
+	cases := []struct {
+		desc    string
+		groupID string
+		chanID  string
+		key     string
+		err     error
+		event   map[string]interface{}
+	}{
+		{
+			desc:    "connect existing group to existing channel",
+			groupID: sgr.ID,
+			chanID:  sch.ID,
+			key:     token,
+			err:     nil,
+			event: map[string]interface{}{
+				"chan_id":   sch.ID,
+				"thing_id":  sth.ID,
+				"owner":     email,
+				"operation": thingConnect,
+			},
+		},
+		{
+			desc:    "connect connected group to existing channel",
+			groupID: sgr.ID,
+			chanID:  sch.ID,
+			key:     token,
+			err:     nil,
+			event:   nil,
+		},
+		{
+			desc:    "connect non-existent group to channel",
+			groupID: strconv.FormatUint(math.MaxUint64, 10),
+			chanID:  sch.ID,
+			key:     token,
 			err:     things.ErrNotFound,
 			event:   nil,
 		},
@@ -544,7 +1070,7 @@ func TestConnectEvent(t *testing.T) {
 
 	lastID := "0"
 	for _, tc := range cases {
-		err := svc.Connect(tc.key, tc.chanID, tc.thingID)
+		_, err := svc.ConnectGroup(context.Background(), tc.key, tc.groupID, tc.chanID)
 		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
 
 		streams := redisClient.XRead(&r.XReadArgs{
@@ -569,11 +1095,11 @@ func TestDisconnectEvent(t *testing.T) {
 
 	svc := newService(map[string]string{token: email})
 	// Create thing and channel that will be connected.
-	sth, err := svc.AddThing(token, things.Thing{Name: "a"})
+	sth, err := svc.AddThing(context.Background(), token, things.Thing{Name: "a"})
 	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
-	sch, err := svc.CreateChannel(token, things.Channel{Name: "a"})
+	sch, err := svc.CreateChannel(context.Background(), token, things.Channel{Name: "a"})
 	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
-	err = svc.Connect(token, sch.ID, sth.ID)
+	err = svc.Connect(context.Background(), token, []string{sch.ID}, []string{sth.ID}, things.FullAccess)
 	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
 
 	svc = redis.NewEventStoreMiddleware(svc, redisClient)
@@ -595,6 +1121,7 @@ func TestDisconnectEvent(t *testing.T) {
 			event: map[string]interface{}{
 				"chan_id":   sch.ID,
 				"thing_id":  sth.ID,
+				"owner":     email,
 				"operation": thingDisconnect,
 			},
 		},
@@ -603,14 +1130,14 @@ func TestDisconnectEvent(t *testing.T) {
 			thingID: strconv.FormatUint(math.MaxUint64, 10),
 			chanID:  sch.ID,
 			key:     token,
-			err:     things.ErrNotFound,
+			err:     connectionError(sch.ID, strconv.FormatUint(math.MaxUint64, 10)),
 			event:   nil,
 		},
 	}
 
 	lastID := "0"
 	for _, tc := range cases {
-		err := svc.Disconnect(tc.key, tc.chanID, tc.thingID)
+		err := svc.Disconnect(context.Background(), tc.key, []string{tc.chanID}, []string{tc.thingID})
 		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
 
 		streams := redisClient.XRead(&r.XReadArgs{
@@ -629,3 +1156,309 @@ func TestDisconnectEvent(t *testing.T) {
 		assert.Equal(t, tc.event, event, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.event, event))
 	}
 }
+
+func connectionError(chanID, thingID string) error {
+	return &things.ConnectionError{
+		Failures: []things.ConnectionFailure{
+			{ChanID: chanID, ThingID: thingID, Err: things.ErrNotFound},
+		},
+	}
+}
+
+func TestThingKeyEvents(t *testing.T) {
+	redisClient.FlushAll().Err()
+
+	svc := newService(map[string]string{token: email})
+	// Create thing without sending event.
+	sth, err := svc.AddThing(context.Background(), token, things.Thing{Name: "a"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+
+	svc = redis.NewEventStoreMiddleware(svc, redisClient)
+
+	tk, err := svc.IssueThingKey(context.Background(), token, sth.ID, things.ScopePublish)
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	err = svc.RevokeThingKey(context.Background(), token, sth.ID, tk.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	err = svc.RevokeKeys(context.Background(), token, sth.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	_, err = svc.IssueThingKey(context.Background(), "", sth.ID, things.ScopePublish)
+	assert.Equal(t, things.ErrUnauthorizedAccess, err, fmt.Sprintf("issue key with invalid credentials: expected %s got %s\n", things.ErrUnauthorizedAccess, err))
+
+	expected := []map[string]interface{}{
+		{
+			"id":        tk.ID,
+			"thing_id":  sth.ID,
+			"owner":     email,
+			"scope":     string(things.ScopePublish),
+			"operation": thingIssueKey,
+		},
+		{
+			"id":        tk.ID,
+			"thing_id":  sth.ID,
+			"owner":     email,
+			"operation": thingRevokeKey,
+		},
+		{
+			"id":        sth.ID,
+			"owner":     email,
+			"operation": thingRevokeKeys,
+		},
+	}
+	events := readEvents(len(expected) + 1)
+	assert.Equal(t, expected, events, fmt.Sprintf("thing key events: expected %v got %v\n", expected, events))
+}
+
+func TestChannelTokenEvents(t *testing.T) {
+	redisClient.FlushAll().Err()
+
+	svc := newService(map[string]string{token: email})
+	// Create channel without sending event.
+	sch, err := svc.CreateChannel(context.Background(), token, things.Channel{Name: "a"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+
+	svc = redis.NewEventStoreMiddleware(svc, redisClient)
+
+	ct, err := svc.IssueChannelToken(context.Background(), token, sch.ID, things.ScopeSubscribe, time.Hour)
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	err = svc.RevokeChannelToken(context.Background(), token, sch.ID, ct.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+
+	expected := []map[string]interface{}{
+		{
+			"id":         ct.ID,
+			"chan_id":    sch.ID,
+			"owner":      email,
+			"scope":      string(things.ScopeSubscribe),
+			"expires_at": strconv.FormatInt(ct.ExpiresAt.Unix(), 10),
+			"operation":  channelIssueToken,
+		},
+		{
+			"id":        ct.ID,
+			"chan_id":   sch.ID,
+			"owner":     email,
+			"operation": channelRevokeToken,
+		},
+	}
+	events := readEvents(len(expected))
+	assert.Equal(t, expected, events, fmt.Sprintf("channel token events: expected %v got %v\n", expected, events))
+}
+
+func TestShareEvents(t *testing.T) {
+	redisClient.FlushAll().Err()
+
+	user := "other@example.com"
+	svc := newService(map[string]string{token: email})
+	// Create thing and channel without sending events.
+	sth, err := svc.AddThing(context.Background(), token, things.Thing{Name: "a"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	sch, err := svc.CreateChannel(context.Background(), token, things.Channel{Name: "a"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+
+	svc = redis.NewEventStoreMiddleware(svc, redisClient)
+
+	err = svc.Share(context.Background(), token, things.Share{Kind: things.ThingKind, EntityID: sth.ID, User: user, Permission: things.ReadPermission})
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	err = svc.Share(context.Background(), token, things.Share{Kind: things.ChannelKind, EntityID: sch.ID, User: user, Permission: things.ManagePermission})
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	err = svc.Unshare(context.Background(), token, things.ThingKind, sth.ID, user)
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	err = svc.Unshare(context.Background(), token, things.ChannelKind, sch.ID, user)
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+
+	expected := []map[string]interface{}{
+		{
+			"id":         sth.ID,
+			"owner":      email,
+			"user":       user,
+			"permission": string(things.ReadPermission),
+			"operation":  thingShare,
+		},
+		{
+			"id":         sch.ID,
+			"owner":      email,
+			"user":       user,
+			"permission": string(things.ManagePermission),
+			"operation":  channelShare,
+		},
+		{
+			"id":        sth.ID,
+			"owner":     email,
+			"user":      user,
+			"operation": thingUnshare,
+		},
+		{
+			"id":        sch.ID,
+			"owner":     email,
+			"user":      user,
+			"operation": channelUnshare,
+		},
+	}
+	events := readEvents(len(expected))
+	assert.Equal(t, expected, events, fmt.Sprintf("share events: expected %v got %v\n", expected, events))
+}
+
+func TestSubtopicEvents(t *testing.T) {
+	redisClient.FlushAll().Err()
+
+	svc := newService(map[string]string{token: email})
+	// Create thing and channel without sending events.
+	sth, err := svc.AddThing(context.Background(), token, things.Thing{Name: "a"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	sch, err := svc.CreateChannel(context.Background(), token, things.Channel{Name: "a"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+
+	svc = redis.NewEventStoreMiddleware(svc, redisClient)
+
+	err = svc.RegisterSubtopic(context.Background(), token, sch.ID, things.Subtopic{Name: "temp", Description: "temperature"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	rule, err := svc.AddSubtopicRule(context.Background(), token, sch.ID, things.SubtopicRule{ThingID: sth.ID, Action: mainflux.ActionPublish, Pattern: "temp"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	err = svc.RemoveSubtopicRule(context.Background(), token, sch.ID, rule.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	err = svc.RemoveSubtopic(context.Background(), token, sch.ID, "temp")
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+
+	expected := []map[string]interface{}{
+		{
+			"id":          sch.ID,
+			"owner":       email,
+			"subtopic":    "temp",
+			"description": "temperature",
+			"operation":   channelRegisterSubtopic,
+		},
+		{
+			"id":        sch.ID,
+			"rule_id":   rule.ID,
+			"owner":     email,
+			"thing_id":  sth.ID,
+			"action":    mainflux.ActionPublish,
+			"pattern":   "temp",
+			"operation": channelAddSubtopicRule,
+		},
+		{
+			"id":        sch.ID,
+			"rule_id":   rule.ID,
+			"owner":     email,
+			"operation": channelRemoveSubtopicRule,
+		},
+		{
+			"id":        sch.ID,
+			"owner":     email,
+			"subtopic":  "temp",
+			"operation": channelRemoveSubtopic,
+		},
+	}
+	events := readEvents(len(expected))
+	assert.Equal(t, expected, events, fmt.Sprintf("subtopic events: expected %v got %v\n", expected, events))
+}
+
+func TestGroupEvents(t *testing.T) {
+	redisClient.FlushAll().Err()
+
+	svc := newService(map[string]string{token: email})
+	// Create thing without sending event.
+	sth, err := svc.AddThing(context.Background(), token, things.Thing{Name: "a"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+
+	svc = redis.NewEventStoreMiddleware(svc, redisClient)
+
+	sg, err := svc.CreateGroup(context.Background(), token, things.Group{Name: "a"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	err = svc.UpdateGroup(context.Background(), token, things.Group{ID: sg.ID, Name: "b"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	err = svc.AssignThings(context.Background(), token, sg.ID, sth.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	err = svc.UnassignThing(context.Background(), token, sg.ID, sth.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	err = svc.RemoveGroup(context.Background(), token, sg.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+
+	expected := []map[string]interface{}{
+		{
+			"id":        sg.ID,
+			"owner":     email,
+			"name":      "a",
+			"operation": groupCreate,
+		},
+		{
+			"id":        sg.ID,
+			"owner":     email,
+			"name":      "b",
+			"operation": groupUpdate,
+		},
+		{
+			"id":        sg.ID,
+			"thing_id":  sth.ID,
+			"owner":     email,
+			"operation": groupAssign,
+		},
+		{
+			"id":        sg.ID,
+			"thing_id":  sth.ID,
+			"owner":     email,
+			"operation": groupUnassign,
+		},
+		{
+			"id":        sg.ID,
+			"owner":     email,
+			"operation": groupRemove,
+		},
+	}
+	events := readEvents(len(expected))
+	assert.Equal(t, expected, events, fmt.Sprintf("group events: expected %v got %v\n", expected, events))
+}
+
+func TestProfileEvents(t *testing.T) {
+	redisClient.FlushAll().Err()
+
+	svc := newService(map[string]string{token: email})
+	svc = redis.NewEventStoreMiddleware(svc, redisClient)
+
+	sp, err := svc.CreateProfile(context.Background(), token, things.Profile{Name: "a", Bootstrap: "content"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	err = svc.UpdateProfile(context.Background(), token, things.Profile{ID: sp.ID, Name: "b", Bootstrap: "content"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	err = svc.RemoveProfile(context.Background(), token, sp.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+
+	expected := []map[string]interface{}{
+		{
+			"id":        sp.ID,
+			"owner":     email,
+			"name":      "a",
+			"operation": profileCreate,
+		},
+		{
+			"id":        sp.ID,
+			"owner":     email,
+			"name":      "b",
+			"operation": profileUpdate,
+		},
+		{
+			"id":        sp.ID,
+			"owner":     email,
+			"operation": profileRemove,
+		},
+	}
+	events := readEvents(len(expected))
+	assert.Equal(t, expected, events, fmt.Sprintf("profile events: expected %v got %v\n", expected, events))
+}
+
+// readEvents reads up to the given number of events from the beginning of
+// the stream.
+func readEvents(count int) []map[string]interface{} {
+	streams := redisClient.XRead(&r.XReadArgs{
+		Streams: []string{streamID, "0"},
+		Count:   int64(count),
+		Block:   time.Second,
+	}).Val()
+
+	events := []map[string]interface{}{}
+	if len(streams) > 0 {
+		for _, msg := range streams[0].Messages {
+			events = append(events, msg.Values)
+		}
+	}
+
+	return events
+}
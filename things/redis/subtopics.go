@@ -0,0 +1,112 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package redis
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/mainflux/mainflux/things"
+)
+
+const (
+	observedPrefix   = "subtopics"
+	observedMessages = "messages"
+	observedSeen     = "seen"
+
+	// Subtopics of the channel are forgotten once no messages are published
+	// to the channel for this long.
+	observedTTL = 30 * 24 * time.Hour
+)
+
+var _ things.ObservationRepository = (*observationRepository)(nil)
+
+type observationRepository struct {
+	client redis.UniversalClient
+}
+
+// NewObservationRepository returns redis observed subtopics repository
+// implementation.
+func NewObservationRepository(client redis.UniversalClient) things.ObservationRepository {
+	return &observationRepository{
+		client: client,
+	}
+}
+
+func (or *observationRepository) Observe(_ context.Context, chanID, subtopic string, t time.Time) error {
+	msgsKey := observedKey(chanID, observedMessages)
+	seenKey := observedKey(chanID, observedSeen)
+
+	// The new subtopics are dropped once the channel reaches the limit. The
+	// check is not atomic, so the limit may be slightly exceeded under the
+	// concurrent publishing, which is acceptable.
+	known, err := or.client.HExists(msgsKey, subtopic).Result()
+	if err != nil {
+		return err
+	}
+
+	if !known {
+		cnt, err := or.client.HLen(msgsKey).Result()
+		if err != nil {
+			return err
+		}
+
+		if cnt >= things.MaxObservedSubtopics {
+			return nil
+		}
+	}
+
+	pipe := or.client.TxPipeline()
+	pipe.HIncrBy(msgsKey, subtopic, 1)
+	pipe.HSet(seenKey, subtopic, t.UnixNano())
+	pipe.Expire(msgsKey, observedTTL)
+	pipe.Expire(seenKey, observedTTL)
+
+	_, err = pipe.Exec()
+	return err
+}
+
+func (or *observationRepository) RetrieveAll(_ context.Context, chanID string) ([]things.ObservedSubtopic, error) {
+	msgs, err := or.client.HGetAll(observedKey(chanID, observedMessages)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	seen, err := or.client.HGetAll(observedKey(chanID, observedSeen)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	items := []things.ObservedSubtopic{}
+	for name, cnt := range msgs {
+		st := things.ObservedSubtopic{
+			Name:     name,
+			Messages: parseCounter(cnt),
+		}
+
+		if ns, err := strconv.ParseInt(seen[name], 10, 64); err == nil {
+			st.LastSeen = time.Unix(0, ns)
+		}
+
+		items = append(items, st)
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Name < items[j].Name
+	})
+
+	return items, nil
+}
+
+func observedKey(chanID, kind string) string {
+	return fmt.Sprintf("%s:%s:%s", observedPrefix, chanID, kind)
+}
@@ -0,0 +1,62 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package redis_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/things"
+	"github.com/mainflux/mainflux/things/redis"
+	"github.com/mainflux/mainflux/things/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObservationRetrieve(t *testing.T) {
+	observationRepo := redis.NewObservationRepository(redisClient)
+	chanID, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	now := time.Now()
+	yesterday := now.Add(-24 * time.Hour)
+
+	for _, ts := range []time.Time{yesterday, now} {
+		err := observationRepo.Observe(context.Background(), chanID, "sensors.temp", ts)
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	}
+	err = observationRepo.Observe(context.Background(), chanID, "debug", yesterday)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	observed, err := observationRepo.RetrieveAll(context.Background(), chanID)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	expected := []things.ObservedSubtopic{
+		{Name: "debug", Messages: 1, LastSeen: time.Unix(0, yesterday.UnixNano())},
+		{Name: "sensors.temp", Messages: 2, LastSeen: time.Unix(0, now.UnixNano())},
+	}
+	assert.Equal(t, expected, observed, fmt.Sprintf("retrieve observed subtopics: expected %v got %v", expected, observed))
+}
+
+func TestObservationLimit(t *testing.T) {
+	observationRepo := redis.NewObservationRepository(redisClient)
+	chanID, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	now := time.Now()
+	for i := 0; i <= things.MaxObservedSubtopics; i++ {
+		err := observationRepo.Observe(context.Background(), chanID, fmt.Sprintf("sensors.%d", i), now)
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	}
+
+	observed, err := observationRepo.RetrieveAll(context.Background(), chanID)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.Len(t, observed, things.MaxObservedSubtopics, fmt.Sprintf("retrieve observed subtopics: expected %d got %d", things.MaxObservedSubtopics, len(observed)))
+}
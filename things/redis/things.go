@@ -8,41 +8,61 @@
 package redis
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/go-redis/redis"
 	"github.com/mainflux/mainflux/things"
 )
 
 const (
-	keyPrefix = "thing_key"
-	idPrefix  = "thing"
+	keyPrefix            = "thing_key"
+	idPrefix             = "thing"
+	thingTransformPrefix = "thing_transform"
 )
 
 var _ things.ThingCache = (*thingCache)(nil)
 
 type thingCache struct {
-	client *redis.Client
+	client redis.UniversalClient
+	ttl    time.Duration
 }
 
-// NewThingCache returns redis thing cache implementation.
-func NewThingCache(client *redis.Client) things.ThingCache {
+// NewThingCache returns redis thing cache implementation, keeping the thing
+// keys for ttl at most. The zero ttl keeps them until they are removed.
+func NewThingCache(client redis.UniversalClient, ttl time.Duration) things.ThingCache {
 	return &thingCache{
 		client: client,
+		ttl:    ttl,
 	}
 }
 
-func (tc *thingCache) Save(thingKey string, thingID string) error {
+func (tc *thingCache) Save(_ context.Context, thingKey string, thingID string, expiresAt time.Time) error {
+	ttl := tc.ttl
+	if !expiresAt.IsZero() {
+		// The key is kept no longer than it is valid. The keys expiring
+		// within a millisecond are not cached at all.
+		left := time.Until(expiresAt)
+		if left < time.Millisecond {
+			return nil
+		}
+		if ttl == 0 || left < ttl {
+			ttl = left
+		}
+	}
+
 	tkey := fmt.Sprintf("%s:%s", keyPrefix, thingKey)
-	if err := tc.client.Set(tkey, thingID, 0).Err(); err != nil {
+	if err := tc.client.Set(tkey, thingID, ttl).Err(); err != nil {
 		return err
 	}
 
 	tid := fmt.Sprintf("%s:%s", idPrefix, thingID)
-	return tc.client.Set(tid, thingKey, 0).Err()
+	return tc.client.Set(tid, thingKey, ttl).Err()
 }
 
-func (tc *thingCache) ID(thingKey string) (string, error) {
+func (tc *thingCache) ID(_ context.Context, thingKey string) (string, error) {
 	tkey := fmt.Sprintf("%s:%s", keyPrefix, thingKey)
 	thingID, err := tc.client.Get(tkey).Result()
 	if err != nil {
@@ -52,7 +72,36 @@ func (tc *thingCache) ID(thingKey string) (string, error) {
 	return thingID, nil
 }
 
-func (tc *thingCache) Remove(thingID string) error {
+func (tc *thingCache) IDs(_ context.Context, thingKeys []string) ([]string, error) {
+	ids := make([]string, len(thingKeys))
+	if len(thingKeys) == 0 {
+		return ids, nil
+	}
+
+	// The keys are fetched by the pipelined GETs rather than MGET, since the
+	// keys of the clustered deployment don't share the hash slot.
+	pipe := tc.client.Pipeline()
+	cmds := make([]*redis.StringCmd, len(thingKeys))
+	for i, key := range thingKeys {
+		cmds[i] = pipe.Get(fmt.Sprintf("%s:%s", keyPrefix, key))
+	}
+
+	if _, err := pipe.Exec(); err != nil && err != redis.Nil {
+		return nil, err
+	}
+
+	for i, cmd := range cmds {
+		id, err := cmd.Result()
+		if err != nil && err != redis.Nil {
+			return nil, err
+		}
+		ids[i] = id
+	}
+
+	return ids, nil
+}
+
+func (tc *thingCache) Remove(_ context.Context, thingID string) error {
 	tid := fmt.Sprintf("%s:%s", idPrefix, thingID)
 	key, err := tc.client.Get(tid).Result()
 	if err != nil {
@@ -63,3 +112,21 @@ func (tc *thingCache) Remove(thingID string) error {
 
 	return tc.client.Del(tkey, tid).Err()
 }
+
+func (tc *thingCache) SaveTransform(_ context.Context, thingID string, tr things.Transform) error {
+	return saveTransform(tc.client, fmt.Sprintf("%s:%s", thingTransformPrefix, thingID), tr)
+}
+
+// saveTransform stores the payload transformation under the provided key, or
+// removes it if the transformation is the zero one.
+func saveTransform(client redis.UniversalClient, key string, tr things.Transform) error {
+	if tr.Template == "" {
+		return client.Del(key).Err()
+	}
+
+	data, err := json.Marshal(tr)
+	if err != nil {
+		return err
+	}
+	return client.Set(key, data, 0).Err()
+}
@@ -8,10 +8,13 @@
 package redis_test
 
 import (
+	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	r "github.com/go-redis/redis"
+	"github.com/mainflux/mainflux/things"
 	"github.com/mainflux/mainflux/things/redis"
 	"github.com/mainflux/mainflux/things/uuid"
 	"github.com/stretchr/testify/assert"
@@ -19,13 +22,13 @@ import (
 )
 
 func TestThingSave(t *testing.T) {
-	thingCache := redis.NewThingCache(redisClient)
+	thingCache := redis.NewThingCache(redisClient, 0)
 	key, err := uuid.New().ID()
 	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
 	id := "123"
 	id2 := "124"
 
-	err = thingCache.Save(key, id2)
+	err = thingCache.Save(context.Background(), key, id2, time.Time{})
 	require.Nil(t, err, fmt.Sprintf("Save thing to cache: expected nil got %s", err))
 
 	cases := []struct {
@@ -49,19 +52,19 @@ func TestThingSave(t *testing.T) {
 	}
 
 	for _, tc := range cases {
-		err := thingCache.Save(tc.key, tc.ID)
+		err := thingCache.Save(context.Background(), tc.key, tc.ID, time.Time{})
 		assert.Nil(t, err, fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
 
 	}
 }
 
 func TestThingID(t *testing.T) {
-	thingCache := redis.NewThingCache(redisClient)
+	thingCache := redis.NewThingCache(redisClient, 0)
 
 	key, err := uuid.New().ID()
 	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
 	id := "123"
-	err = thingCache.Save(key, id)
+	err = thingCache.Save(context.Background(), key, id, time.Time{})
 	require.Nil(t, err, fmt.Sprintf("Save thing to cache: expected nil got %s", err))
 
 	cases := map[string]struct {
@@ -82,20 +85,71 @@ func TestThingID(t *testing.T) {
 	}
 
 	for desc, tc := range cases {
-		cacheID, err := thingCache.ID(tc.key)
+		cacheID, err := thingCache.ID(context.Background(), tc.key)
 		assert.Equal(t, tc.ID, cacheID, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.ID, cacheID))
 		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
 	}
 }
 
+func TestThingKeyExpiration(t *testing.T) {
+	thingCache := redis.NewThingCache(redisClient, time.Hour)
+
+	key, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	err = thingCache.Save(context.Background(), key, "123", time.Now().Add(100*time.Millisecond))
+	require.Nil(t, err, fmt.Sprintf("Save thing to cache: expected nil got %s", err))
+
+	_, err = thingCache.ID(context.Background(), key)
+	assert.Nil(t, err, fmt.Sprintf("unexpired key: expected nil got %s", err))
+
+	time.Sleep(200 * time.Millisecond)
+
+	_, err = thingCache.ID(context.Background(), key)
+	assert.Equal(t, r.Nil, err, fmt.Sprintf("expired key: expected %s got %s", r.Nil, err))
+}
+
+func TestThingIDs(t *testing.T) {
+	thingCache := redis.NewThingCache(redisClient, 0)
+
+	key, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	id := "123"
+	err = thingCache.Save(context.Background(), key, id, time.Time{})
+	require.Nil(t, err, fmt.Sprintf("Save thing to cache: expected nil got %s", err))
+
+	cases := map[string]struct {
+		IDs  []string
+		keys []string
+	}{
+		"Get IDs by existing thing-keys": {
+			IDs:  []string{id, id},
+			keys: []string{key, key},
+		},
+		"Get IDs by partially existing thing-keys": {
+			IDs:  []string{"", id},
+			keys: []string{wrongValue, key},
+		},
+		"Get IDs by no thing-keys": {
+			IDs:  []string{},
+			keys: []string{},
+		},
+	}
+
+	for desc, tc := range cases {
+		ids, err := thingCache.IDs(context.Background(), tc.keys)
+		assert.Nil(t, err, fmt.Sprintf("%s: expected nil got %s", desc, err))
+		assert.Equal(t, tc.IDs, ids, fmt.Sprintf("%s: expected %v got %v", desc, tc.IDs, ids))
+	}
+}
+
 func TestThingRemove(t *testing.T) {
-	thingCache := redis.NewThingCache(redisClient)
+	thingCache := redis.NewThingCache(redisClient, 0)
 
 	key, err := uuid.New().ID()
 	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
 	id := "123"
 	id2 := "321"
-	thingCache.Save(key, id)
+	thingCache.Save(context.Background(), key, id, time.Time{})
 
 	cases := []struct {
 		desc string
@@ -115,8 +169,39 @@ func TestThingRemove(t *testing.T) {
 	}
 
 	for _, tc := range cases {
-		err := thingCache.Remove(tc.ID)
+		err := thingCache.Remove(context.Background(), tc.ID)
 		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
 	}
 
 }
+
+func TestSaveThingTransform(t *testing.T) {
+	thingCache := redis.NewThingCache(redisClient, 0)
+
+	id := "125"
+	key := fmt.Sprintf("thing_transform:%s", id)
+
+	cases := []struct {
+		desc  string
+		tr    things.Transform
+		saved string
+	}{
+		{
+			desc:  "save thing transformation",
+			tr:    things.Transform{Template: `{{}}`, ContentType: "application/json"},
+			saved: `{"template":"{{}}","contentType":"application/json"}`,
+		},
+		{
+			desc:  "remove thing transformation",
+			tr:    things.Transform{},
+			saved: "",
+		},
+	}
+
+	for _, tc := range cases {
+		err := thingCache.SaveTransform(context.Background(), id, tc.tr)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s\n", tc.desc, err))
+		saved := redisClient.Get(key).Val()
+		assert.Equal(t, tc.saved, saved, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.saved, saved))
+	}
+}
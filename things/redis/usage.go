@@ -0,0 +1,112 @@
+//
+// Copyright (c) 2018
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/mainflux/mainflux/things"
+)
+
+const (
+	usagePrefix   = "usage"
+	usageThing    = "thing"
+	usageChannel  = "channel"
+	usageMessages = "messages"
+	usageBytes    = "bytes"
+	dayFormat     = "2006-01-02"
+
+	// Daily usage buckets are kept for a bit longer than a year, so that
+	// yearly usage can always be retrieved.
+	usageTTL = 400 * 24 * time.Hour
+)
+
+var _ things.UsageRepository = (*usageRepository)(nil)
+
+type usageRepository struct {
+	client redis.UniversalClient
+}
+
+// NewUsageRepository returns redis usage repository implementation.
+func NewUsageRepository(client redis.UniversalClient) things.UsageRepository {
+	return &usageRepository{
+		client: client,
+	}
+}
+
+func (ur *usageRepository) Add(_ context.Context, thingID, chanID string, size uint64, t time.Time) error {
+	pipe := ur.client.TxPipeline()
+
+	for _, key := range []string{usageKey(usageThing, thingID, t), usageKey(usageChannel, chanID, t)} {
+		pipe.HIncrBy(key, usageMessages, 1)
+		pipe.HIncrBy(key, usageBytes, int64(size))
+		pipe.Expire(key, usageTTL)
+	}
+
+	_, err := pipe.Exec()
+	return err
+}
+
+func (ur *usageRepository) RetrieveByThing(_ context.Context, id string, from, to time.Time) (things.Usage, error) {
+	return ur.retrieve(usageThing, id, from, to)
+}
+
+func (ur *usageRepository) RetrieveByChannel(_ context.Context, id string, from, to time.Time) (things.Usage, error) {
+	return ur.retrieve(usageChannel, id, from, to)
+}
+
+func (ur *usageRepository) retrieve(kind, id string, from, to time.Time) (things.Usage, error) {
+	usage := things.Usage{
+		From: from,
+		To:   to,
+	}
+
+	pipe := ur.client.Pipeline()
+	cmds := []*redis.SliceCmd{}
+	for day := from.UTC().Truncate(24 * time.Hour); !day.After(to); day = day.Add(24 * time.Hour) {
+		cmds = append(cmds, pipe.HMGet(usageKey(kind, id, day), usageMessages, usageBytes))
+	}
+
+	if _, err := pipe.Exec(); err != nil && err != redis.Nil {
+		return things.Usage{}, err
+	}
+
+	for _, cmd := range cmds {
+		vals, err := cmd.Result()
+		if err != nil {
+			return things.Usage{}, err
+		}
+
+		usage.Messages += parseCounter(vals[0])
+		usage.Bytes += parseCounter(vals[1])
+	}
+
+	return usage, nil
+}
+
+func usageKey(kind, id string, t time.Time) string {
+	return fmt.Sprintf("%s:%s:%s:%s", usagePrefix, kind, id, t.UTC().Format(dayFormat))
+}
+
+func parseCounter(val interface{}) uint64 {
+	str, ok := val.(string)
+	if !ok {
+		return 0
+	}
+
+	cnt, err := strconv.ParseUint(str, 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	return cnt
+}
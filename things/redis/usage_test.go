@@ -0,0 +1,79 @@
+//
+// Copyright (c) 2018
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package redis_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/things/redis"
+	"github.com/mainflux/mainflux/things/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsageRetrieve(t *testing.T) {
+	usageRepo := redis.NewUsageRepository(redisClient)
+	thingID, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	chanID, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	now := time.Now()
+	yesterday := now.Add(-24 * time.Hour)
+	size := uint64(100)
+
+	for _, ts := range []time.Time{now, now, yesterday} {
+		err := usageRepo.Add(context.Background(), thingID, chanID, size, ts)
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	}
+
+	cases := []struct {
+		desc     string
+		from     time.Time
+		to       time.Time
+		messages uint64
+		bytes    uint64
+	}{
+		{
+			desc:     "retrieve today's usage",
+			from:     now,
+			to:       now,
+			messages: 2,
+			bytes:    2 * size,
+		},
+		{
+			desc:     "retrieve usage for the last two days",
+			from:     yesterday,
+			to:       now,
+			messages: 3,
+			bytes:    3 * size,
+		},
+		{
+			desc:     "retrieve usage for the period without messages",
+			from:     now.Add(-72 * time.Hour),
+			to:       now.Add(-48 * time.Hour),
+			messages: 0,
+			bytes:    0,
+		},
+	}
+
+	for _, tc := range cases {
+		thingUsage, err := usageRepo.RetrieveByThing(context.Background(), thingID, tc.from, tc.to)
+		assert.Nil(t, err, fmt.Sprintf("%s: got unexpected error: %s", tc.desc, err))
+		assert.Equal(t, tc.messages, thingUsage.Messages, fmt.Sprintf("%s: expected %d thing messages got %d", tc.desc, tc.messages, thingUsage.Messages))
+		assert.Equal(t, tc.bytes, thingUsage.Bytes, fmt.Sprintf("%s: expected %d thing bytes got %d", tc.desc, tc.bytes, thingUsage.Bytes))
+
+		chanUsage, err := usageRepo.RetrieveByChannel(context.Background(), chanID, tc.from, tc.to)
+		assert.Nil(t, err, fmt.Sprintf("%s: got unexpected error: %s", tc.desc, err))
+		assert.Equal(t, tc.messages, chanUsage.Messages, fmt.Sprintf("%s: expected %d channel messages got %d", tc.desc, tc.messages, chanUsage.Messages))
+		assert.Equal(t, tc.bytes, chanUsage.Bytes, fmt.Sprintf("%s: expected %d channel bytes got %d", tc.desc, tc.bytes, chanUsage.Bytes))
+	}
+}
@@ -0,0 +1,38 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package things
+
+import "context"
+
+// Reservation represents a thing identifier and key pair that is generated
+// ahead of the thing itself, e.g. to be flashed onto a device during
+// manufacturing. A reservation is bound to a thing once a thing with the
+// reserved key is added by the same owner.
+type Reservation struct {
+	ID    string
+	Owner string
+	Key   string
+}
+
+// ReservationRepository specifies a reservation persistence API.
+type ReservationRepository interface {
+	// Save persists the reservations. A non-nil error is returned to
+	// indicate operation failure.
+	Save(context.Context, ...Reservation) error
+
+	// RetrieveByKey retrieves the reservation having the provided key, that
+	// is owned by the specified user.
+	RetrieveByKey(context.Context, string, string) (Reservation, error)
+
+	// Remove removes the reservation having the provided identifier, that is
+	// owned by the specified user.
+	Remove(context.Context, string, string) error
+
+	// RemoveAll removes all reservations owned by the specified user.
+	RemoveAll(context.Context, string) error
+}
@@ -32,376 +32,3509 @@ var (
 )
 
 // Service specifies an API that must be fullfiled by the domain service
-// implementation, and all of its decorators (e.g. logging & metrics).
+// implementation, and all of its decorators (e.g. logging & metrics). The
+// provided context is passed down to the repositories and the users service,
+// so that the request deadline and cancellation apply to all of the calls.
 type Service interface {
 	// AddThing adds new thing to the user identified by the provided key.
-	AddThing(string, Thing) (Thing, error)
+	// If the thing key matches one of the user's reservations, the thing is
+	// bound to the reserved identifier and the reservation is consumed. If
+	// the user already has the thing of the same external ID, the existing
+	// thing is returned instead, so that the creation can be retried safely.
+	// ErrQuotaExceeded is returned if the user has reached its things quota.
+	AddThing(context.Context, string, Thing) (Thing, error)
+
+	// ProvisionThing adds new thing to the user identified by the provided
+	// key, the same way AddThing does, and creates and connects it to the
+	// channels described by the user's provisioning template, if any. If
+	// the profile ID is set, the thing is instantiated from the profile and
+	// connected to its channels as well. If the user already has the thing
+	// of the same external ID, only the existing thing is returned.
+	ProvisionThing(context.Context, string, string, Thing) (Topology, error)
+
+	// SaveTemplate saves the provisioning template of the user identified by
+	// the provided key, replacing the existing one.
+	SaveTemplate(context.Context, string, Template) error
+
+	// ViewTemplate retrieves the provisioning template of the user
+	// identified by the provided key.
+	ViewTemplate(context.Context, string) (Template, error)
+
+	// RemoveTemplate removes the provisioning template of the user
+	// identified by the provided key.
+	RemoveTemplate(context.Context, string) error
+
+	// CreateProfile adds new profile to the user identified by the provided
+	// key. The channels of the profile must belong to the same user.
+	CreateProfile(context.Context, string, Profile) (Profile, error)
+
+	// UpdateProfile updates the profile identified by the provided ID, that
+	// belongs to the user identified by the provided key.
+	UpdateProfile(context.Context, string, Profile) error
+
+	// ViewProfile retrieves data about the profile identified by the
+	// provided ID, that belongs to the user identified by the provided key.
+	ViewProfile(context.Context, string, string) (Profile, error)
+
+	// ListProfiles retrieves data about subset of profiles that belong to
+	// the user identified by the provided key.
+	ListProfiles(context.Context, string, uint64, uint64) (ProfilesPage, error)
+
+	// RemoveProfile removes the profile identified by the provided ID, that
+	// belongs to the user identified by the provided key. The things
+	// instantiated from the profile are kept.
+	RemoveProfile(context.Context, string, string) error
+
+	// CreateThings adds the things to the user identified by the provided
+	// key, all of them in a single transaction, and returns them along with
+	// their generated identifiers and keys. None of the things is added if
+	// they would exceed the user's things quota.
+	CreateThings(context.Context, string, ...Thing) ([]Thing, error)
+
+	// ReserveThings generates the given number of thing identifier and key
+	// pairs for the user identified by the provided key, to be bound to the
+	// things added later on.
+	ReserveThings(context.Context, string, uint64) ([]Reservation, error)
 
 	// UpdateThing updates the thing identified by the provided ID, that
 	// belongs to the user identified by the provided key.
-	UpdateThing(string, Thing) error
-
-	// UpdateKey updates key value of the existing thing. A non-nil error is
-	// returned to indicate operation failure.
-	UpdateKey(string, string, string) error
+	UpdateThing(context.Context, string, Thing) error
+
+	// PatchThing applies the JSON merge patch to the thing identified by the
+	// provided ID, that belongs to the user identified by the provided key,
+	// and returns the patched thing.
+	PatchThing(context.Context, string, string, map[string]interface{}) (Thing, error)
+
+	// UpdateKey updates key value of the existing thing, along with the
+	// time the key expires at and the period of its automatic rotation,
+	// both optional. A non-nil error is returned to indicate operation
+	// failure.
+	UpdateKey(context.Context, string, string, string, time.Time, time.Duration) error
+
+	// RotateKeys issues the new keys to the things having the rotation
+	// period whose keys expired, and evicts the keys expired since the
+	// provided time from the cache. The things holding the new keys are
+	// returned.
+	RotateKeys(context.Context, time.Time) ([]Thing, error)
+
+	// UpdateThingsMetadata applies the metadata merge patch to all things
+	// whose metadata contains the provided selector, that belong to the user
+	// identified by the provided key, and returns the updated things.
+	UpdateThingsMetadata(context.Context, string, map[string]interface{}, map[string]interface{}) ([]Thing, error)
 
 	// ViewThing retrieves data about the thing identified with the provided
 	// ID, that belongs to the user identified by the provided key.
-	ViewThing(string, string) (Thing, error)
+	ViewThing(context.Context, string, string) (Thing, error)
+
+	// OnboardThing issues the signed provisioning payload of the thing
+	// identified by the provided ID, that belongs to the user identified by
+	// the provided key. The payload is returned along with its QR-encodable
+	// representation.
+	OnboardThing(context.Context, string, string) (Onboarding, string, error)
+
+	// IssueKey issues the signed key of the thing identified by the provided
+	// ID, that belongs to the user identified by the provided key, valid for
+	// the given duration. The key is returned along with its signed
+	// representation.
+	IssueKey(context.Context, string, string, time.Duration) (SignedKey, string, error)
+
+	// OpenSession issues the short-lived signed key of the thing identified
+	// by the provided plain key, valid for the given duration. The session
+	// key is returned along with its signed representation.
+	OpenSession(context.Context, string, time.Duration) (SignedKey, string, error)
+
+	// RevokeKeys revokes all the signed keys issued so far to the thing
+	// identified by the provided ID, that belongs to the user identified by
+	// the provided key.
+	RevokeKeys(context.Context, string, string) error
+
+	// IssueThingKey issues the additional key of the thing identified by
+	// the provided ID, that belongs to the user identified by the provided
+	// key, granting access for the actions of the given scope.
+	IssueThingKey(context.Context, string, string, KeyScope) (ThingKey, error)
+
+	// ListThingKeys retrieves the additional keys of the thing identified
+	// by the provided ID, that belongs to the user identified by the
+	// provided key.
+	ListThingKeys(context.Context, string, string) ([]ThingKey, error)
+
+	// RevokeThingKey revokes the additional key identified by the provided
+	// ID, leaving the thing's primary key and its other keys intact.
+	RevokeThingKey(context.Context, string, string, string) error
+
+	// ThingHistory retrieves the subset of changes made to the thing
+	// identified by the provided ID, that belongs to the user identified by
+	// the provided key.
+	ThingHistory(context.Context, string, string, uint64, uint64) (ChangesPage, error)
 
 	// ListThings retrieves data about subset of things that belongs to the
-	// user identified by the provided key.
-	ListThings(string, uint64, uint64, string) (ThingsPage, error)
+	// user identified by the provided key, or that are shared with the
+	// user. Keys of the shared things are omitted. Only the things whose name
+	// contains the provided one, whose metadata contains the provided
+	// metadata, whose tags match the provided filter and whose connectivity
+	// status is the provided one are retrieved, if set. The things are
+	// retrieved past the provided cursor, if set, ignoring the offset.
+	ListThings(context.Context, string, uint64, uint64, string, map[string]interface{}, TagFilter, Status, string) (ThingsPage, error)
+
+	// CountThings returns the number of things ListThings would retrieve
+	// using the same filters, without retrieving them.
+	CountThings(context.Context, string, string, map[string]interface{}, TagFilter, Status) (uint64, error)
 
 	// ListThingsByChannel retrieves data about subset of things that are
 	// connected to specified channel and belong to the user identified by
 	// the provided key.
-	ListThingsByChannel(string, string, uint64, uint64) (ThingsPage, error)
+	ListThingsByChannel(context.Context, string, string, uint64, uint64) (ThingsPage, error)
+
+	// EnableThing enables the thing identified with the provided ID, that
+	// belongs to the user identified by the provided key.
+	EnableThing(context.Context, string, string) error
+
+	// DisableThing disables the thing identified with the provided ID, that
+	// belongs to the user identified by the provided key. Disabled thing is
+	// denied access to the channels and the signed keys issued to it are
+	// revoked, while its connections and history are kept.
+	DisableThing(context.Context, string, string) error
+
+	// TransferThing reassigns the thing identified with the provided ID,
+	// that belongs to the user identified by the provided key, to the new
+	// owner, which has to be an existing user. The thing keeps its key
+	// and history, while its group assignments and shares are dropped. Its
+	// connections are dropped as well, unless they are kept, in which case
+	// they span both owners.
+	TransferThing(context.Context, string, string, string, bool) error
 
 	// RemoveThing removes the thing identified with the provided ID, that
 	// belongs to the user identified by the provided key.
-	RemoveThing(string, string) error
+	RemoveThing(context.Context, string, string) error
 
 	// CreateChannel adds new channel to the user identified by the provided key.
-	CreateChannel(string, Channel) (Channel, error)
+	// ErrQuotaExceeded is returned if the user has reached its channels quota.
+	CreateChannel(context.Context, string, Channel) (Channel, error)
 
 	// UpdateChannel updates the channel identified by the provided ID, that
 	// belongs to the user identified by the provided key.
-	UpdateChannel(string, Channel) error
+	UpdateChannel(context.Context, string, Channel) error
+
+	// PatchChannel applies the JSON merge patch to the channel identified by
+	// the provided ID, that belongs to the user identified by the provided
+	// key, and returns the patched channel.
+	PatchChannel(context.Context, string, string, map[string]interface{}) (Channel, error)
 
 	// ViewChannel retrieves data about the channel identified by the provided
 	// ID, that belongs to the user identified by the provided key.
-	ViewChannel(string, string) (Channel, error)
+	ViewChannel(context.Context, string, string) (Channel, error)
+
+	// ChannelHistory retrieves the subset of changes made to the channel
+	// identified by the provided ID, that belongs to the user identified by
+	// the provided key.
+	ChannelHistory(context.Context, string, string, uint64, uint64) (ChangesPage, error)
+
+	// Audit retrieves the subset of audit events of the things and the
+	// channels that belong to the user identified by the provided key,
+	// matching the provided query.
+	Audit(context.Context, string, AuditQuery, uint64, uint64) (AuditPage, error)
+
+	// Capabilities returns the operations the user identified by the
+	// provided key may perform on the entity of the given kind having the
+	// provided identifier, or on their account if neither is provided.
+	Capabilities(context.Context, string, string, string) (Capabilities, error)
 
 	// ListChannels retrieves data about subset of channels that belongs to the
-	// user identified by the provided key.
-	ListChannels(string, uint64, uint64, string) (ChannelsPage, error)
+	// user identified by the provided key, or that are shared with the user.
+	// Only the channels whose name contains the provided one, whose metadata
+	// contains the provided metadata and whose tags match the provided
+	// filter are retrieved, if set. The channels are retrieved past the
+	// provided cursor, if set, ignoring the offset.
+	ListChannels(context.Context, string, uint64, uint64, string, map[string]interface{}, TagFilter, string) (ChannelsPage, error)
+
+	// CountChannels returns the number of channels ListChannels would
+	// retrieve using the same filters, without retrieving them.
+	CountChannels(context.Context, string, string, map[string]interface{}, TagFilter) (uint64, error)
 
 	// ListChannelsByThing retrieves data about subset of channels that have
 	// specified thing connected to them and belong to the user identified by
 	// the provided key.
-	ListChannelsByThing(string, string, uint64, uint64) (ChannelsPage, error)
+	ListChannelsByThing(context.Context, string, string, uint64, uint64) (ChannelsPage, error)
+
+	// TransferChannel reassigns the channel identified by the provided ID,
+	// that belongs to the user identified by the provided key, to the new
+	// owner, which has to be an existing user. The channel keeps its
+	// subtopics and history, while its shares are dropped. Its connections
+	// are dropped as well, unless they are kept, in which case they span
+	// both owners.
+	TransferChannel(context.Context, string, string, string, bool) error
 
 	// RemoveChannel removes the thing identified by the provided ID, that
 	// belongs to the user identified by the provided key.
-	RemoveChannel(string, string) error
+	RemoveChannel(context.Context, string, string) error
+
+	// ArchiveChannel archives the channel identified by the provided ID,
+	// that belongs to the user identified by the provided key. Archived
+	// channel rejects new messages, while the messages stored so far remain
+	// readable.
+	ArchiveChannel(context.Context, string, string) error
+
+	// UnarchiveChannel makes the archived channel identified by the
+	// provided ID, that belongs to the user identified by the provided key,
+	// accept messages again.
+	UnarchiveChannel(context.Context, string, string) error
+
+	// ArchiveChannels archives all the channels whose metadata contains the
+	// provided selector and whose tags match the provided filter, that
+	// belong to the user identified by the provided key, and returns the
+	// archived channels. Either the selector or the tags must be provided.
+	ArchiveChannels(context.Context, string, map[string]interface{}, TagFilter) ([]Channel, error)
+
+	// InviteToChannel issues the subscribe-only invitation to the channel
+	// identified by the provided ID, that belongs to the user identified by
+	// the provided key, valid for the given duration. The invitation is
+	// returned along with its signed representation.
+	InviteToChannel(context.Context, string, string, time.Duration) (Invitation, string, error)
+
+	// IssueChannelToken issues the access token of the channel identified by
+	// the provided ID, that belongs to the user identified by the provided
+	// key, granting the actions of the given scope for the given duration.
+	IssueChannelToken(context.Context, string, string, KeyScope, time.Duration) (ChannelToken, error)
+
+	// ListChannelTokens retrieves the unexpired access tokens of the channel
+	// identified by the provided ID, that belongs to the user identified by
+	// the provided key.
+	ListChannelTokens(context.Context, string, string) ([]ChannelToken, error)
+
+	// RevokeChannelToken removes the access token having the provided ID
+	// from the tokens of the channel identified by the provided ID, that
+	// belongs to the user identified by the provided key.
+	RevokeChannelToken(context.Context, string, string, string) error
+
+	// Connect connects all the things to all the channels, in a single
+	// transaction, granting them the provided access. The user has to
+	// either own or manage all of them, and the connected channel and thing
+	// have to belong to the same owner. Connecting the connected pairs
+	// replaces their access. The pairs that can't be connected are reported
+	// by ConnectionError, in which case none of the pairs is connected.
+	Connect(context.Context, string, []string, []string, ConnectionAccess) error
+
+	// Disconnect disconnects all the things from all the channels, in a
+	// single transaction. The pairs that can't be disconnected are reported
+	// by ConnectionError, in which case none of the pairs is disconnected.
+	Disconnect(context.Context, string, []string, []string) error
+
+	// Export retrieves all the things and channels that belong to the user
+	// identified by the provided key, along with the connections between
+	// them.
+	Export(context.Context, string) (Snapshot, error)
+
+	// Import adds the things and the channels of the snapshot to the user
+	// identified by the provided key, connects them as the snapshot
+	// describes and returns them. The imported things and channels are
+	// assigned the new identifiers and keys, unless the existing ones are
+	// preserved. The things are added in a single transaction, while the
+	// channels are added one at a time.
+	Import(context.Context, string, Snapshot, bool) (Snapshot, error)
+
+	// CreateGroup adds new group to the user identified by the provided key.
+	// The parent group, if set, must belong to the same user.
+	CreateGroup(context.Context, string, Group) (Group, error)
+
+	// UpdateGroup updates the group identified by the provided ID, that
+	// belongs to the user identified by the provided key.
+	UpdateGroup(context.Context, string, Group) error
+
+	// ViewGroup retrieves data about the group identified by the provided
+	// ID, that belongs to the user identified by the provided key.
+	ViewGroup(context.Context, string, string) (Group, error)
+
+	// ListGroups retrieves data about subset of groups that belong to the
+	// user identified by the provided key. Only the direct subgroups of the
+	// provided group are retrieved, if set.
+	ListGroups(context.Context, string, string, uint64, uint64) (GroupsPage, error)
+
+	// ListThingsByGroup retrieves data about subset of things of the group
+	// identified by the provided ID, including the things of its subgroups,
+	// that belongs to the user identified by the provided key.
+	ListThingsByGroup(context.Context, string, string, uint64, uint64) (ThingsPage, error)
 
-	// Connect adds thing to the channel's list of connected things.
-	Connect(string, string, string) error
+	// RemoveGroup removes the group identified by the provided ID, that
+	// belongs to the user identified by the provided key, along with its
+	// subgroups. The things of the removed groups are kept.
+	RemoveGroup(context.Context, string, string) error
 
-	// Disconnect removes thing from the channel's list of connected
+	// AssignThings assigns the things identified by the provided IDs to the
+	// group identified by the provided ID. The group and the things belong
+	// to the user identified by the provided key.
+	AssignThings(context.Context, string, string, ...string) error
+
+	// UnassignThing removes the thing from the group's list of assigned
 	// things.
-	Disconnect(string, string, string) error
+	UnassignThing(context.Context, string, string, string) error
+
+	// ConnectGroup connects all the things of the group identified by the
+	// provided ID, including the things of its subgroups, to the channel,
+	// and returns the IDs of the newly connected things.
+	ConnectGroup(context.Context, string, string, string) ([]string, error)
+
+	// CanAccess determines whether the subtopic of the channel can be
+	// accessed for the given action using the provided (plain, scoped or
+	// signed) key and returns thing's id if access is allowed. Archived
+	// channels can be accessed for subscribing only.
+	CanAccess(context.Context, string, string, string, string) (string, error)
+
+	// Identify returns thing ID for given (plain, scoped or signed) thing
+	// key.
+	Identify(context.Context, string) (string, error)
+
+	// IdentifyBatch returns the IDs of the things having the given keys, in
+	// the order of the keys. The IDs of the unknown or revoked keys are
+	// empty.
+	IdentifyBatch(context.Context, []string) ([]string, error)
+
+	// CanRead determines whether the messages published to the channel can
+	// be read by the user identified by the provided token, i.e. whether the
+	// user owns the channel, and returns user's id if access is allowed.
+	CanRead(context.Context, string, string) (string, error)
+
+	// ConnectedChannels returns the identifiers of all the channels the
+	// thing having the provided identifier is connected to, regardless of
+	// their owner. It serves the internal services routing the thing's
+	// messages, and is backed by the cache.
+	ConnectedChannels(context.Context, string) ([]string, error)
+
+	// RemoveUserHandler removes all things and channels owned by the user
+	// with the provided identifier, received from an event. The removed
+	// things and channels are returned, even if removal of the remaining
+	// entities failed.
+	RemoveUserHandler(context.Context, string) ([]Thing, []Channel, error)
+
+	// RecordUsage records the message of the given size, published by the
+	// thing to the channel.
+	RecordUsage(context.Context, string, string, uint64) error
+
+	// RecordActivity records the thing having the provided identifier as
+	// seen publishing.
+	RecordActivity(context.Context, string) error
+
+	// RecordDiagnostics records the self-report the thing having the
+	// provided identifier published to the diagnostics subtopic into its
+	// health record.
+	RecordDiagnostics(context.Context, string, []byte) error
+
+	// ViewHealth retrieves the health record of the thing identified by the
+	// provided ID, that belongs to the user identified by the provided key.
+	ViewHealth(context.Context, string, string) (Health, error)
+
+	// ListUnhealthy retrieves the subset of the health records of the
+	// things that belong to the user identified by the provided key, whose
+	// reported battery level or signal strength is below the given
+	// thresholds.
+	ListUnhealthy(context.Context, string, HealthThresholds, uint64, uint64) (HealthPage, error)
+
+	// ThingUsage retrieves usage of the thing identified by the provided ID,
+	// that belongs to the user identified by the provided key, within the
+	// given time period.
+	ThingUsage(context.Context, string, string, time.Time, time.Time) (Usage, error)
+
+	// ChannelUsage retrieves usage of the channel identified by the provided
+	// ID, that belongs to the user identified by the provided key, within
+	// the given time period.
+	ChannelUsage(context.Context, string, string, time.Time, time.Time) (Usage, error)
+
+	// RegisterSubtopic registers the subtopic of the channel identified by
+	// the provided ID, that belongs to the user identified by the provided
+	// key. Registering the subtopic again updates its description and
+	// schema.
+	RegisterSubtopic(context.Context, string, string, Subtopic) error
+
+	// ListSubtopics retrieves the subtopics registered for the channel
+	// identified by the provided ID, that belongs to the user identified by
+	// the provided key.
+	ListSubtopics(context.Context, string, string) ([]Subtopic, error)
+
+	// RemoveSubtopic removes the subtopic having the provided name from the
+	// channel's registered subtopics.
+	RemoveSubtopic(context.Context, string, string, string) error
+
+	// ObservedSubtopics retrieves the subtopics messages were published to
+	// on the channel identified by the provided ID, that belongs to the
+	// user identified by the provided key.
+	ObservedSubtopics(context.Context, string, string) ([]ObservedSubtopic, error)
+
+	// RecordSubtopic records the message published to the channel and to
+	// the given subtopic.
+	RecordSubtopic(context.Context, string, string) error
 
-	// CanAccess determines whether the channel can be accessed using the
-	// provided key and returns thing's id if access is allowed.
-	CanAccess(string, string) (string, error)
+	// AddSubtopicRule adds the rule limiting the subtopics the thing can
+	// access on the channel identified by the provided ID. Both the channel
+	// and the thing have to belong to the user identified by the provided
+	// key.
+	AddSubtopicRule(context.Context, string, string, SubtopicRule) (SubtopicRule, error)
 
-	// Identify returns thing ID for given thing key.
-	Identify(string) (string, error)
+	// ListSubtopicRules retrieves the subtopic rules of the channel
+	// identified by the provided ID, that belongs to the user identified by
+	// the provided key.
+	ListSubtopicRules(context.Context, string, string) ([]SubtopicRule, error)
+
+	// RemoveSubtopicRule removes the rule having the provided ID from the
+	// channel's subtopic rules.
+	RemoveSubtopicRule(context.Context, string, string, string) error
+
+	// Share grants the permission on the thing or the channel, that belongs
+	// to the user identified by the provided key, to another user. The
+	// permission previously granted to the same user is replaced.
+	Share(context.Context, string, Share) error
+
+	// Unshare revokes the permission on the entity of the given kind having
+	// the provided ID, that belongs to the user identified by the provided
+	// key, granted to the specified user.
+	Unshare(context.Context, string, string, string, string) error
+
+	// ListShares retrieves the permissions on the entity of the given kind
+	// having the provided ID, that belongs to the user identified by the
+	// provided key, granted to other users.
+	ListShares(context.Context, string, string, string) ([]Share, error)
 }
 
 // PageMetadata contains page metadata that helps navigation.
 type PageMetadata struct {
-	Total  uint64
-	Offset uint64
-	Limit  uint64
-	Name   string
+	Total    uint64
+	Offset   uint64
+	Limit    uint64
+	Name     string
+	Metadata map[string]interface{}
 }
 
+const (
+	removeBatchSize      = 100
+	connectionsBatchSize = 100
+	maxUsagePeriod       = 366 * 24 * time.Hour
+	maxKeyTTL            = 30 * 24 * time.Hour
+	maxSessionTTL        = 24 * time.Hour
+	maxBulkThings        = 1000
+	maxIdentifyBatch     = 1000
+	exportBatchSize      = 100
+	maxInvitationTTL     = 7 * 24 * time.Hour
+	maxChannelTokenTTL   = 365 * 24 * time.Hour
+	minKeyRotation       = time.Hour
+)
+
 var _ Service = (*thingsService)(nil)
 
 type thingsService struct {
 	users        mainflux.UsersServiceClient
 	things       ThingRepository
 	channels     ChannelRepository
+	reservations ReservationRepository
+	usage        UsageRepository
+	history      HistoryRepository
+	audit        AuditRepository
 	channelCache ChannelCache
 	thingCache   ThingCache
 	idp          IdentityProvider
+	onboarding   OnboardingProvider
+	keys         KeyProvider
+	revocations  RevocationRepository
+	templates    TemplateRepository
+	groups       GroupRepository
+	subtopics    SubtopicRepository
+	observations ObservationRepository
+	thingKeys    ThingKeyRepository
+	shares       ShareRepository
+	profiles     ProfileRepository
+	rules        SubtopicRuleRepository
+	health       HealthRepository
+	chanTokens   ChannelTokenRepository
+	overrides    OverrideProvider
+	limits       Limits
+	staleAfter   time.Duration
 }
 
-// New instantiates the things service implementation.
-func New(users mainflux.UsersServiceClient, things ThingRepository, channels ChannelRepository, ccache ChannelCache, tcache ThingCache, idp IdentityProvider) Service {
+// New instantiates the things service implementation. Things and channels
+// are validated against the provided limits, while the owners' quotas and
+// default channel rate limits are consulted with the override provider.
+// Things not seen publishing for the stale period are considered stale.
+func New(users mainflux.UsersServiceClient, things ThingRepository, channels ChannelRepository, reservations ReservationRepository, usage UsageRepository, history HistoryRepository, audit AuditRepository, ccache ChannelCache, tcache ThingCache, idp IdentityProvider, onboarding OnboardingProvider, keys KeyProvider, revocations RevocationRepository, templates TemplateRepository, groups GroupRepository, subtopics SubtopicRepository, observations ObservationRepository, thingKeys ThingKeyRepository, shares ShareRepository, profiles ProfileRepository, rules SubtopicRuleRepository, health HealthRepository, chanTokens ChannelTokenRepository, overrides OverrideProvider, limits Limits, staleAfter time.Duration) Service {
 	return &thingsService{
 		users:        users,
 		things:       things,
 		channels:     channels,
+		reservations: reservations,
+		usage:        usage,
+		history:      history,
+		audit:        audit,
 		channelCache: ccache,
 		thingCache:   tcache,
 		idp:          idp,
+		onboarding:   onboarding,
+		keys:         keys,
+		revocations:  revocations,
+		templates:    templates,
+		groups:       groups,
+		subtopics:    subtopics,
+		observations: observations,
+		thingKeys:    thingKeys,
+		shares:       shares,
+		profiles:     profiles,
+		rules:        rules,
+		health:       health,
+		chanTokens:   chanTokens,
+		overrides:    overrides,
+		limits:       limits,
+		staleAfter:   staleAfter,
 	}
 }
 
-func (ts *thingsService) AddThing(token string, thing Thing) (Thing, error) {
-	if err := thing.Validate(); err != nil {
-		return Thing{}, ErrMalformedEntity
+func (ts *thingsService) AddThing(ctx context.Context, token string, thing Thing) (Thing, error) {
+	if err := thing.Validate(ts.limits); err != nil {
+		return Thing{}, err
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
-	defer cancel()
-
 	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
 	if err != nil {
 		return Thing{}, ErrUnauthorizedAccess
 	}
 
-	thing.ID, err = ts.idp.ID()
-	if err != nil {
-		return Thing{}, err
+	thing, _, err = ts.addThing(ctx, res.GetValue(), auditActor(res), thing)
+	return thing, err
+}
+
+// addThing saves the thing, unless the owner already has the thing of the
+// same external ID, in which case the existing thing and false are returned.
+func (ts *thingsService) addThing(ctx context.Context, owner, actor string, thing Thing) (Thing, bool, error) {
+	if existing, err := ts.thingByExternalID(ctx, owner, thing.ExternalID); err != ErrNotFound {
+		return existing, false, err
 	}
 
-	thing.Owner = res.GetValue()
+	if err := ts.checkThingsQuota(ctx, owner, 1); err != nil {
+		return Thing{}, false, err
+	}
 
-	if thing.Key == "" {
-		thing.Key, err = ts.idp.ID()
-		if err != nil {
-			return Thing{}, err
-		}
+	if err := ts.prepareThing(ctx, owner, &thing); err != nil {
+		return Thing{}, false, err
 	}
 
-	id, err := ts.things.Save(thing)
+	id, err := ts.things.Save(ctx, thing)
+	if err == ErrConflict {
+		// The thing might have been saved by the concurrent request.
+		if existing, err := ts.thingByExternalID(ctx, owner, thing.ExternalID); err != ErrNotFound {
+			return existing, false, err
+		}
+	}
 	if err != nil {
-		return Thing{}, err
+		return Thing{}, false, err
 	}
 
 	thing.ID = id
-	return thing, nil
+	if err := ts.cacheTransform(ctx, thing); err != nil {
+		return Thing{}, false, err
+	}
+
+	if err := ts.recordEvents(ctx, thingEvent(CreateOperation, actor, Thing{}, thing, time.Now())); err != nil {
+		return Thing{}, false, err
+	}
+
+	return thing, true, nil
 }
 
-func (ts *thingsService) UpdateThing(token string, thing Thing) error {
-	if err := thing.Validate(); err != nil {
-		return ErrMalformedEntity
+// thingByExternalID retrieves the thing of the owner having the provided
+// external ID, returning ErrNotFound if the external ID is empty.
+func (ts *thingsService) thingByExternalID(ctx context.Context, owner, externalID string) (Thing, error) {
+	if externalID == "" {
+		return Thing{}, ErrNotFound
+	}
+
+	return ts.things.RetrieveByExternalID(ctx, owner, externalID)
+}
+
+func (ts *thingsService) CreateThings(ctx context.Context, token string, things ...Thing) ([]Thing, error) {
+	if len(things) == 0 || len(things) > maxBulkThings {
+		return []Thing{}, ErrMalformedEntity
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
-	defer cancel()
+	for _, thing := range things {
+		if err := thing.Validate(ts.limits); err != nil {
+			return []Thing{}, err
+		}
+	}
 
 	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
 	if err != nil {
-		return ErrUnauthorizedAccess
+		return []Thing{}, ErrUnauthorizedAccess
 	}
+	owner := res.GetValue()
 
-	thing.Owner = res.GetValue()
+	if err := ts.checkThingsQuota(ctx, owner, uint64(len(things))); err != nil {
+		return []Thing{}, err
+	}
 
-	return ts.things.Update(thing)
-}
+	created := make([]Thing, len(things))
+	for i, thing := range things {
+		if err := ts.prepareThing(ctx, owner, &thing); err != nil {
+			return []Thing{}, err
+		}
+		created[i] = thing
+	}
 
-func (ts *thingsService) UpdateKey(token, id, key string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
-	defer cancel()
+	ids, err := ts.things.SaveAll(ctx, created...)
+	if err != nil {
+		return []Thing{}, err
+	}
 
-	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	now := time.Now()
+	events := make([]AuditEvent, len(created))
+	for i := range created {
+		created[i].ID = ids[i]
+		if err := ts.cacheTransform(ctx, created[i]); err != nil {
+			return []Thing{}, err
+		}
+		events[i] = thingEvent(CreateOperation, auditActor(res), Thing{}, created[i], now)
+	}
+
+	if err := ts.recordEvents(ctx, events...); err != nil {
+		return []Thing{}, err
+	}
+
+	return created, nil
+}
+
+// prepareThing assigns the owner, the identifier and, unless provided, the
+// key to the thing. If the provided key is reserved, the thing is assigned
+// the reserved identifier, and the reservation is consumed once the thing
+// is saved.
+func (ts *thingsService) prepareThing(ctx context.Context, owner string, thing *Thing) error {
+	var err error
+	thing.ID, err = ts.idp.ID()
 	if err != nil {
-		return ErrUnauthorizedAccess
+		return err
 	}
 
-	owner := res.GetValue()
+	thing.Owner = owner
+	thing.State = Enabled
 
-	return ts.things.UpdateKey(owner, id, key)
+	if thing.Key == "" {
+		thing.Key, err = ts.idp.ID()
+		return err
+	}
 
+	r, err := ts.reservations.RetrieveByKey(ctx, thing.Owner, thing.Key)
+	switch err {
+	case nil:
+		thing.ID = r.ID
+		return nil
+	case ErrNotFound:
+		return nil
+	default:
+		return err
+	}
 }
 
-func (ts *thingsService) ViewThing(token, id string) (Thing, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
-	defer cancel()
+func (ts *thingsService) ProvisionThing(ctx context.Context, token, profileID string, thing Thing) (Topology, error) {
+	if err := thing.Validate(ts.limits); err != nil {
+		return Topology{}, err
+	}
 
 	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
 	if err != nil {
-		return Thing{}, ErrUnauthorizedAccess
+		return Topology{}, ErrUnauthorizedAccess
 	}
+	owner := res.GetValue()
 
-	return ts.things.RetrieveByID(res.GetValue(), id)
-}
+	profile := Profile{}
+	if profileID != "" {
+		profile, err = ts.profiles.RetrieveByID(ctx, owner, profileID)
+		if err != nil {
+			return Topology{}, err
+		}
 
-func (ts *thingsService) ListThings(token string, offset, limit uint64, name string) (ThingsPage, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
-	defer cancel()
+		thing = profile.instantiate(thing)
+		if err := thing.Validate(ts.limits); err != nil {
+			return Topology{}, err
+		}
+	}
 
-	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	connected, err := ts.profileChannels(ctx, owner, profile.Channels)
 	if err != nil {
-		return ThingsPage{}, ErrUnauthorizedAccess
+		return Topology{}, err
+	}
+
+	tmpl, err := ts.templates.Retrieve(ctx, owner)
+	if err != nil && err != ErrNotFound {
+		return Topology{}, err
+	}
+
+	thing, created, err := ts.addThing(ctx, owner, auditActor(res), thing)
+	if err != nil {
+		return Topology{}, err
+	}
+
+	if !created {
+		return Topology{
+			Thing:     thing,
+			Channels:  []Channel{},
+			Connected: []Channel{},
+			Existing:  true,
+		}, nil
+	}
+
+	top := Topology{
+		Thing:     thing,
+		Channels:  []Channel{},
+		Connected: connected,
+		Bootstrap: profile.Bootstrap,
+	}
+	for _, ct := range tmpl.Channels {
+		channel, err := ts.createChannel(ctx, auditActor(res), ct.channel(thing))
+		if err == nil {
+			top.Channels = append(top.Channels, channel)
+			err = ts.channels.Connect(ctx, Connection{ChanID: channel.ID, ThingID: thing.ID, Owner: owner})
+		}
+		if err != nil {
+			ts.unprovision(ctx, top)
+			return Topology{}, err
+		}
+	}
+
+	for _, channel := range connected {
+		if err := ts.channels.Connect(ctx, Connection{ChanID: channel.ID, ThingID: thing.ID, Owner: owner}); err != nil {
+			ts.unprovision(ctx, top)
+			return Topology{}, err
+		}
+	}
+
+	return top, nil
+}
+
+// profileChannels retrieves the channels of the profile, that have to belong
+// to the profile owner.
+func (ts *thingsService) profileChannels(ctx context.Context, owner string, ids []string) ([]Channel, error) {
+	channels := []Channel{}
+	for _, id := range ids {
+		channel, err := ts.channels.RetrieveByID(ctx, owner, id)
+		if err != nil {
+			return nil, err
+		}
+		channels = append(channels, channel)
+	}
+
+	return channels, nil
+}
+
+// unprovision removes the partially provisioned thing and its channels.
+func (ts *thingsService) unprovision(ctx context.Context, top Topology) {
+	for _, channel := range top.Channels {
+		ts.channelCache.Remove(ctx, channel.ID)
+		ts.channels.Remove(ctx, channel.Owner, channel.ID)
 	}
 
-	return ts.things.RetrieveAll(res.GetValue(), offset, limit, name)
+	ts.things.Remove(ctx, top.Thing.Owner, top.Thing.ID)
 }
 
-func (ts *thingsService) ListThingsByChannel(token, channel string, offset, limit uint64) (ThingsPage, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
-	defer cancel()
+func (ts *thingsService) SaveTemplate(ctx context.Context, token string, tmpl Template) error {
+	if err := tmpl.Validate(ts.limits); err != nil {
+		return err
+	}
 
 	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
 	if err != nil {
-		return ThingsPage{}, ErrUnauthorizedAccess
+		return ErrUnauthorizedAccess
 	}
 
-	return ts.things.RetrieveByChannel(res.GetValue(), channel, offset, limit)
+	tmpl.Owner = res.GetValue()
+	return ts.templates.Save(ctx, tmpl)
 }
 
-func (ts *thingsService) RemoveThing(token, id string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
-	defer cancel()
+func (ts *thingsService) ViewTemplate(ctx context.Context, token string) (Template, error) {
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return Template{}, ErrUnauthorizedAccess
+	}
+
+	return ts.templates.Retrieve(ctx, res.GetValue())
+}
 
+func (ts *thingsService) RemoveTemplate(ctx context.Context, token string) error {
 	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
 	if err != nil {
 		return ErrUnauthorizedAccess
 	}
 
-	ts.thingCache.Remove(id)
-	return ts.things.Remove(res.GetValue(), id)
+	return ts.templates.Remove(ctx, res.GetValue())
 }
 
-func (ts *thingsService) CreateChannel(token string, channel Channel) (Channel, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
-	defer cancel()
+func (ts *thingsService) CreateProfile(ctx context.Context, token string, profile Profile) (Profile, error) {
+	if err := profile.Validate(ts.limits); err != nil {
+		return Profile{}, err
+	}
 
 	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
 	if err != nil {
-		return Channel{}, ErrUnauthorizedAccess
+		return Profile{}, ErrUnauthorizedAccess
 	}
 
-	channel.ID, err = ts.idp.ID()
-	if err != nil {
-		return Channel{}, err
+	profile.Owner = res.GetValue()
+
+	if _, err := ts.profileChannels(ctx, profile.Owner, profile.Channels); err != nil {
+		return Profile{}, err
 	}
 
-	channel.Owner = res.GetValue()
+	profile.ID, err = ts.idp.ID()
+	if err != nil {
+		return Profile{}, err
+	}
 
-	id, err := ts.channels.Save(channel)
+	id, err := ts.profiles.Save(ctx, profile)
 	if err != nil {
-		return Channel{}, err
+		return Profile{}, err
 	}
 
-	channel.ID = id
-	return channel, nil
+	profile.ID = id
+	return profile, nil
 }
 
-func (ts *thingsService) UpdateChannel(token string, channel Channel) error {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
-	defer cancel()
+func (ts *thingsService) UpdateProfile(ctx context.Context, token string, profile Profile) error {
+	if err := profile.Validate(ts.limits); err != nil {
+		return err
+	}
 
 	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
 	if err != nil {
 		return ErrUnauthorizedAccess
 	}
 
-	channel.Owner = res.GetValue()
-	return ts.channels.Update(channel)
-}
+	profile.Owner = res.GetValue()
+
+	if _, err := ts.profileChannels(ctx, profile.Owner, profile.Channels); err != nil {
+		return err
+	}
 
-func (ts *thingsService) ViewChannel(token, id string) (Channel, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
-	defer cancel()
+	return ts.profiles.Update(ctx, profile)
+}
 
+func (ts *thingsService) ViewProfile(ctx context.Context, token, id string) (Profile, error) {
 	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
 	if err != nil {
-		return Channel{}, ErrUnauthorizedAccess
+		return Profile{}, ErrUnauthorizedAccess
 	}
 
-	return ts.channels.RetrieveByID(res.GetValue(), id)
+	return ts.profiles.RetrieveByID(ctx, res.GetValue(), id)
 }
 
-func (ts *thingsService) ListChannels(token string, offset, limit uint64, name string) (ChannelsPage, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
-	defer cancel()
-
+func (ts *thingsService) ListProfiles(ctx context.Context, token string, offset, limit uint64) (ProfilesPage, error) {
 	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
 	if err != nil {
-		return ChannelsPage{}, ErrUnauthorizedAccess
+		return ProfilesPage{}, ErrUnauthorizedAccess
 	}
 
-	return ts.channels.RetrieveAll(res.GetValue(), offset, limit, name)
+	return ts.profiles.RetrieveAll(ctx, res.GetValue(), offset, limit)
 }
 
-func (ts *thingsService) ListChannelsByThing(token, thing string, offset, limit uint64) (ChannelsPage, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
-	defer cancel()
-
+func (ts *thingsService) RemoveProfile(ctx context.Context, token, id string) error {
 	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
 	if err != nil {
-		return ChannelsPage{}, ErrUnauthorizedAccess
+		return ErrUnauthorizedAccess
 	}
 
-	return ts.channels.RetrieveByThing(res.GetValue(), thing, offset, limit)
+	return ts.profiles.Remove(ctx, res.GetValue(), id)
 }
 
-func (ts *thingsService) RemoveChannel(token, id string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
-	defer cancel()
-
+func (ts *thingsService) ReserveThings(ctx context.Context, token string, n uint64) ([]Reservation, error) {
 	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
 	if err != nil {
-		return ErrUnauthorizedAccess
+		return []Reservation{}, ErrUnauthorizedAccess
+	}
+
+	reservations := make([]Reservation, n)
+	for i := range reservations {
+		id, err := ts.idp.ID()
+		if err != nil {
+			return []Reservation{}, err
+		}
+
+		key, err := ts.idp.ID()
+		if err != nil {
+			return []Reservation{}, err
+		}
+
+		reservations[i] = Reservation{
+			ID:    id,
+			Owner: res.GetValue(),
+			Key:   key,
+		}
+	}
+
+	if err := ts.reservations.Save(ctx, reservations...); err != nil {
+		return []Reservation{}, err
 	}
 
-	ts.channelCache.Remove(id)
-	return ts.channels.Remove(res.GetValue(), id)
+	return reservations, nil
 }
 
-func (ts *thingsService) Connect(token, chanID, thingID string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
-	defer cancel()
+func (ts *thingsService) UpdateThing(ctx context.Context, token string, thing Thing) error {
+	if err := thing.Validate(ts.limits); err != nil {
+		return err
+	}
 
 	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
 	if err != nil {
 		return ErrUnauthorizedAccess
 	}
 
-	return ts.channels.Connect(res.GetValue(), chanID, thingID)
-}
+	thing.Owner, err = ts.accessOwner(ctx, res.GetValue(), ThingKind, thing.ID, ManagePermission)
+	if err != nil {
+		return err
+	}
+
+	old, err := ts.things.RetrieveByID(ctx, thing.Owner, thing.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := ts.things.Update(ctx, thing); err != nil {
+		return err
+	}
+
+	if err := ts.cacheTransform(ctx, thing); err != nil {
+		return err
+	}
 
-func (ts *thingsService) Disconnect(token, chanID, thingID string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
-	defer cancel()
+	// Thing key is updated separately.
+	thing.Key = old.Key
+	now := time.Now()
+	if err := ts.record(ctx, ThingChanges(old, thing, now)); err != nil {
+		return err
+	}
+
+	return ts.recordEvents(ctx, thingEvent(UpdateOperation, auditActor(res), old, thing, now))
+}
 
+func (ts *thingsService) PatchThing(ctx context.Context, token, id string, patch map[string]interface{}) (Thing, error) {
 	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
 	if err != nil {
-		return ErrUnauthorizedAccess
+		return Thing{}, ErrUnauthorizedAccess
 	}
 
-	ts.channelCache.Disconnect(chanID, thingID)
-	return ts.channels.Disconnect(res.GetValue(), chanID, thingID)
-}
+	owner, err := ts.accessOwner(ctx, res.GetValue(), ThingKind, id, ManagePermission)
+	if err != nil {
+		return Thing{}, err
+	}
 
-func (ts *thingsService) CanAccess(chanID, key string) (string, error) {
-	thingID, err := ts.hasThing(chanID, key)
-	if err == nil {
-		return thingID, nil
+	old, err := ts.things.RetrieveByID(ctx, owner, id)
+	if err != nil {
+		return Thing{}, err
 	}
 
-	thingID, err = ts.channels.HasThing(chanID, key)
+	patched, err := PatchThing(old, patch)
 	if err != nil {
-		return "", ErrUnauthorizedAccess
+		return Thing{}, err
 	}
 
-	ts.thingCache.Save(key, thingID)
-	ts.channelCache.Connect(chanID, thingID)
-	return thingID, nil
+	if err := patched.Validate(ts.limits); err != nil {
+		return Thing{}, err
+	}
+
+	thing, err := ts.things.Patch(ctx, owner, id, patch)
+	if err != nil {
+		return Thing{}, err
+	}
+
+	if err := ts.cacheTransform(ctx, thing); err != nil {
+		return Thing{}, err
+	}
+
+	now := time.Now()
+	if err := ts.record(ctx, ThingChanges(old, thing, now)); err != nil {
+		return Thing{}, err
+	}
+
+	if err := ts.recordEvents(ctx, thingEvent(UpdateOperation, auditActor(res), old, thing, now)); err != nil {
+		return Thing{}, err
+	}
+
+	return hideKey(thing, res.GetValue()), nil
 }
 
-func (ts *thingsService) Identify(key string) (string, error) {
-	id, err := ts.thingCache.ID(key)
-	if err == nil {
-		return id, nil
+func (ts *thingsService) UpdateKey(ctx context.Context, token, id, key string, expiresAt time.Time, rotation time.Duration) error {
+	thing := Thing{
+		ID:           id,
+		Key:          key,
+		KeyExpiresAt: expiresAt,
+		KeyRotation:  rotation,
+	}
+	if err := thing.Validate(ts.limits); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if thing.KeyExpired(now) || rotation < 0 || rotation > 0 && rotation < minKeyRotation {
+		return ErrMalformedEntity
+	}
+
+	// The key rotated periodically has to expire in the first place.
+	if rotation > 0 && expiresAt.IsZero() {
+		thing.KeyExpiresAt = now.Add(rotation)
 	}
 
-	id, err = ts.things.RetrieveByKey(key)
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
 	if err != nil {
-		return "", ErrUnauthorizedAccess
+		return ErrUnauthorizedAccess
+	}
+
+	thing.Owner = res.GetValue()
+
+	if err := ts.things.UpdateKey(ctx, thing); err != nil {
+		return err
+	}
+
+	ts.thingCache.Remove(ctx, id)
+	change := keyChange(id, thing.Owner, now)
+	if err := ts.record(ctx, []Change{change}); err != nil {
+		return err
+	}
+
+	event := AuditEvent{
+		Owner:      thing.Owner,
+		Actor:      auditActor(res),
+		Operation:  UpdateKeyOperation,
+		EntityKind: ThingKind,
+		EntityID:   id,
+		Diff:       changesDiff([]Change{change}),
+		OccurredAt: now,
 	}
 
-	ts.thingCache.Save(key, id)
-	return id, nil
+	return ts.recordEvents(ctx, event)
 }
 
-func (ts *thingsService) hasThing(chanID, key string) (string, error) {
-	thingID, err := ts.thingCache.ID(key)
+func (ts *thingsService) RotateKeys(ctx context.Context, since time.Time) ([]Thing, error) {
+	now := time.Now()
+
+	expired, err := ts.things.RetrieveExpired(ctx, since, now)
 	if err != nil {
-		return "", err
+		return []Thing{}, err
 	}
 
-	if connected := ts.channelCache.HasThing(chanID, thingID); !connected {
-		return "", ErrUnauthorizedAccess
+	rotated := []Thing{}
+	changes := []Change{}
+	for _, th := range expired {
+		ts.thingCache.Remove(ctx, th.ID)
+		if th.KeyRotation <= 0 {
+			continue
+		}
+
+		old := th.Key
+		th.Key, err = ts.idp.ID()
+		if err != nil {
+			return rotated, err
+		}
+		th.KeyExpiresAt = now.Add(th.KeyRotation)
+
+		if err := ts.things.RotateKey(ctx, th, old); err != nil {
+			// The key is already rotated by the other instance.
+			if err == ErrNotFound {
+				continue
+			}
+			return rotated, err
+		}
+
+		rotated = append(rotated, th)
+		changes = append(changes, keyChange(th.ID, th.Owner, now))
 	}
 
-	return thingID, nil
+	if err := ts.record(ctx, changes); err != nil {
+		return rotated, err
+	}
+
+	return rotated, nil
+}
+
+func (ts *thingsService) UpdateThingsMetadata(ctx context.Context, token string, selector, patch map[string]interface{}) ([]Thing, error) {
+	if selector == nil || len(patch) == 0 {
+		return []Thing{}, ErrMalformedEntity
+	}
+
+	// Metadata of the selected things is merged within the repository, so
+	// the adapter sections present in the patch have to be complete.
+	patched := Thing{Metadata: patch}
+	if err := patched.Validate(ts.limits); err != nil {
+		return []Thing{}, err
+	}
+
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return []Thing{}, ErrUnauthorizedAccess
+	}
+
+	updated, err := ts.things.UpdateMetadata(ctx, res.GetValue(), selector, patch)
+	if err != nil {
+		return []Thing{}, err
+	}
+
+	// Previous metadata of the updated things is not known at this point,
+	// so only the new value is recorded.
+	now := time.Now()
+	changes := []Change{}
+	events := []AuditEvent{}
+	for _, thing := range updated {
+		if err := ts.cacheTransform(ctx, thing); err != nil {
+			return []Thing{}, err
+		}
+
+		change := Change{
+			EntityID:  thing.ID,
+			Owner:     thing.Owner,
+			Field:     metadataField,
+			NewValue:  encodeMetadata(thing.Metadata),
+			ChangedAt: now,
+		}
+		changes = append(changes, change)
+		events = append(events, AuditEvent{
+			Owner:      thing.Owner,
+			Actor:      auditActor(res),
+			Operation:  UpdateOperation,
+			EntityKind: ThingKind,
+			EntityID:   thing.ID,
+			Diff:       changesDiff([]Change{change}),
+			OccurredAt: now,
+		})
+	}
+
+	if err := ts.record(ctx, changes); err != nil {
+		return []Thing{}, err
+	}
+
+	if err := ts.recordEvents(ctx, events...); err != nil {
+		return []Thing{}, err
+	}
+
+	return updated, nil
+}
+
+func (ts *thingsService) ViewThing(ctx context.Context, token, id string) (Thing, error) {
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return Thing{}, ErrUnauthorizedAccess
+	}
+
+	owner, err := ts.accessOwner(ctx, res.GetValue(), ThingKind, id, ReadPermission)
+	if err != nil {
+		return Thing{}, err
+	}
+
+	thing, err := ts.things.RetrieveByID(ctx, owner, id)
+	if err != nil {
+		return Thing{}, err
+	}
+
+	return hideKey(thing, res.GetValue()), nil
+}
+
+func (ts *thingsService) OnboardThing(ctx context.Context, token, id string) (Onboarding, string, error) {
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return Onboarding{}, "", ErrUnauthorizedAccess
+	}
+
+	thing, err := ts.things.RetrieveByID(ctx, res.GetValue(), id)
+	if err != nil {
+		return Onboarding{}, "", err
+	}
+
+	channels, err := ts.connections(ctx, res.GetValue(), id)
+	if err != nil {
+		return Onboarding{}, "", err
+	}
+
+	return ts.onboarding.Issue(thing.ID, thing.Key, channels)
+}
+
+func (ts *thingsService) IssueKey(ctx context.Context, token, id string, ttl time.Duration) (SignedKey, string, error) {
+	if ttl <= 0 || ttl > maxKeyTTL {
+		return SignedKey{}, "", ErrMalformedEntity
+	}
+
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return SignedKey{}, "", ErrUnauthorizedAccess
+	}
+
+	thing, err := ts.things.RetrieveByID(ctx, res.GetValue(), id)
+	if err != nil {
+		return SignedKey{}, "", err
+	}
+
+	if thing.State != Enabled {
+		return SignedKey{}, "", ErrUnauthorizedAccess
+	}
+
+	return ts.issueKey(ctx, res.GetValue(), id, ttl)
+}
+
+func (ts *thingsService) OpenSession(ctx context.Context, key string, ttl time.Duration) (SignedKey, string, error) {
+	if ttl <= 0 || ttl > maxSessionTTL {
+		return SignedKey{}, "", ErrMalformedEntity
+	}
+
+	// Only the plain key opens a session, so that the session can't be
+	// used to extend itself.
+	th, err := ts.things.RetrieveByKey(ctx, key)
+	if err != nil {
+		return SignedKey{}, "", ErrUnauthorizedAccess
+	}
+
+	return ts.issueKey(ctx, th.Owner, th.ID, ttl)
+}
+
+func (ts *thingsService) issueKey(ctx context.Context, owner, id string, ttl time.Duration) (SignedKey, string, error) {
+	channels, err := ts.connections(ctx, owner, id)
+	if err != nil {
+		return SignedKey{}, "", err
+	}
+
+	restricted, err := ts.channels.RetrieveRestricted(ctx, id)
+	if err != nil {
+		return SignedKey{}, "", err
+	}
+
+	rules, err := ts.rules.RetrieveByThing(ctx, id)
+	if err != nil {
+		return SignedKey{}, "", err
+	}
+
+	version, err := ts.keysVersion(ctx, id)
+	if err != nil {
+		return SignedKey{}, "", err
+	}
+
+	// Signed keys carry the time with the second precision.
+	now := time.Now().UTC().Truncate(time.Second)
+	key := SignedKey{
+		ThingID:    id,
+		Channels:   channels,
+		Restricted: restricted,
+		Rules:      rules,
+		Version:    version,
+		IssuedAt:   now,
+		ExpiresAt:  now.Add(ttl),
+	}
+
+	signed, err := ts.keys.Issue(key)
+	if err != nil {
+		return SignedKey{}, "", err
+	}
+
+	return key, signed, nil
+}
+
+func (ts *thingsService) RevokeKeys(ctx context.Context, token, id string) error {
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return ErrUnauthorizedAccess
+	}
+
+	if _, err := ts.things.RetrieveByID(ctx, res.GetValue(), id); err != nil {
+		return err
+	}
+
+	return ts.revoke(ctx, id)
+}
+
+func (ts *thingsService) IssueThingKey(ctx context.Context, token, id string, scope KeyScope) (ThingKey, error) {
+	if !scope.Valid() {
+		return ThingKey{}, ErrMalformedEntity
+	}
+
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return ThingKey{}, ErrUnauthorizedAccess
+	}
+
+	if _, err := ts.things.RetrieveByID(ctx, res.GetValue(), id); err != nil {
+		return ThingKey{}, err
+	}
+
+	tk := ThingKey{
+		ThingID:  id,
+		Owner:    res.GetValue(),
+		Scope:    scope,
+		IssuedAt: time.Now().UTC(),
+	}
+
+	tk.ID, err = ts.idp.ID()
+	if err != nil {
+		return ThingKey{}, err
+	}
+
+	tk.Key, err = ts.idp.ID()
+	if err != nil {
+		return ThingKey{}, err
+	}
+
+	if _, err := ts.thingKeys.Save(ctx, tk); err != nil {
+		return ThingKey{}, err
+	}
+
+	return tk, nil
+}
+
+func (ts *thingsService) ListThingKeys(ctx context.Context, token, id string) ([]ThingKey, error) {
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return nil, ErrUnauthorizedAccess
+	}
+
+	if _, err := ts.things.RetrieveByID(ctx, res.GetValue(), id); err != nil {
+		return nil, err
+	}
+
+	return ts.thingKeys.RetrieveAll(ctx, res.GetValue(), id)
+}
+
+func (ts *thingsService) RevokeThingKey(ctx context.Context, token, id, keyID string) error {
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return ErrUnauthorizedAccess
+	}
+
+	return ts.thingKeys.Remove(ctx, res.GetValue(), id, keyID)
+}
+
+func (ts *thingsService) ThingHistory(ctx context.Context, token, id string, offset, limit uint64) (ChangesPage, error) {
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return ChangesPage{}, ErrUnauthorizedAccess
+	}
+
+	owner, err := ts.accessOwner(ctx, res.GetValue(), ThingKind, id, ReadPermission)
+	if err != nil {
+		return ChangesPage{}, err
+	}
+
+	if _, err := ts.things.RetrieveByID(ctx, owner, id); err != nil {
+		return ChangesPage{}, err
+	}
+
+	return ts.history.RetrieveAll(ctx, owner, id, offset, limit)
+}
+
+func (ts *thingsService) ListThings(ctx context.Context, token string, offset, limit uint64, name string, metadata map[string]interface{}, tags TagFilter, status Status, cursor string) (ThingsPage, error) {
+	switch status {
+	case "", Online, Stale:
+	default:
+		return ThingsPage{}, ErrMalformedEntity
+	}
+
+	after, err := DecodeCursor(cursor)
+	if err != nil {
+		return ThingsPage{}, err
+	}
+
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return ThingsPage{}, ErrUnauthorizedAccess
+	}
+
+	shared, err := ts.sharedIDs(ctx, res.GetValue(), ThingKind)
+	if err != nil {
+		return ThingsPage{}, err
+	}
+
+	sf := StatusFilter{
+		Status: status,
+		Since:  time.Now().Add(-ts.staleAfter),
+	}
+
+	page, err := ts.things.RetrieveAccessible(ctx, res.GetValue(), shared, offset, limit, name, metadata, tags, sf, after)
+	if err != nil {
+		return ThingsPage{}, err
+	}
+
+	for i, thing := range page.Things {
+		page.Things[i] = hideKey(thing, res.GetValue())
+	}
+
+	if n := len(page.Things); n > 0 && uint64(n) == limit {
+		page.Cursor = EncodeCursor(page.Things[n-1].ID)
+	}
+
+	return page, nil
+}
+
+func (ts *thingsService) CountThings(ctx context.Context, token, name string, metadata map[string]interface{}, tags TagFilter, status Status) (uint64, error) {
+	switch status {
+	case "", Online, Stale:
+	default:
+		return 0, ErrMalformedEntity
+	}
+
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return 0, ErrUnauthorizedAccess
+	}
+
+	shared, err := ts.sharedIDs(ctx, res.GetValue(), ThingKind)
+	if err != nil {
+		return 0, err
+	}
+
+	sf := StatusFilter{
+		Status: status,
+		Since:  time.Now().Add(-ts.staleAfter),
+	}
+
+	return ts.things.CountAccessible(ctx, res.GetValue(), shared, name, metadata, tags, sf)
+}
+
+func (ts *thingsService) ListThingsByChannel(ctx context.Context, token, channel string, offset, limit uint64) (ThingsPage, error) {
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return ThingsPage{}, ErrUnauthorizedAccess
+	}
+
+	owner, err := ts.accessOwner(ctx, res.GetValue(), ChannelKind, channel, ReadPermission)
+	if err != nil {
+		return ThingsPage{}, err
+	}
+
+	page, err := ts.things.RetrieveByChannel(ctx, owner, channel, offset, limit)
+	if err != nil {
+		return ThingsPage{}, err
+	}
+
+	for i, thing := range page.Things {
+		page.Things[i] = hideKey(thing, res.GetValue())
+	}
+
+	return page, nil
+}
+
+func (ts *thingsService) EnableThing(ctx context.Context, token, id string) error {
+	return ts.changeState(ctx, token, id, Enabled)
+}
+
+func (ts *thingsService) DisableThing(ctx context.Context, token, id string) error {
+	return ts.changeState(ctx, token, id, Disabled)
+}
+
+func (ts *thingsService) changeState(ctx context.Context, token, id string, state State) error {
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return ErrUnauthorizedAccess
+	}
+
+	owner := res.GetValue()
+
+	thing, err := ts.things.RetrieveByID(ctx, owner, id)
+	if err != nil {
+		return err
+	}
+
+	if thing.State == state {
+		return nil
+	}
+
+	if err := ts.things.UpdateState(ctx, owner, id, state); err != nil {
+		return err
+	}
+
+	if state == Disabled {
+		if err := ts.revoke(ctx, id); err != nil {
+			return err
+		}
+		ts.thingCache.Remove(ctx, id)
+	}
+
+	return ts.record(ctx, []Change{stateChange(id, owner, thing.State, state, time.Now())})
+}
+
+func (ts *thingsService) RemoveThing(ctx context.Context, token, id string) error {
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return ErrUnauthorizedAccess
+	}
+
+	owner := res.GetValue()
+
+	// Removal of the non-existing thing succeeds, but isn't audited.
+	old, err := ts.things.RetrieveByID(ctx, owner, id)
+	existed := err == nil
+	if existed {
+		if err := ts.revoke(ctx, id); err != nil {
+			return err
+		}
+	}
+
+	ts.thingCache.Remove(ctx, id)
+	ts.thingCache.SaveTransform(ctx, id, Transform{})
+	ts.channelCache.RemoveConnected(ctx, id)
+	if err := ts.things.Remove(ctx, owner, id); err != nil {
+		return err
+	}
+
+	if !existed {
+		return nil
+	}
+
+	return ts.recordEvents(ctx, thingEvent(RemoveOperation, auditActor(res), old, Thing{ID: id, Owner: owner}, time.Now()))
+}
+
+func (ts *thingsService) TransferThing(ctx context.Context, token, id, newOwner string, keepConns bool) error {
+	if newOwner == "" {
+		return ErrMalformedEntity
+	}
+
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return ErrUnauthorizedAccess
+	}
+
+	owner := res.GetValue()
+	if newOwner == owner {
+		return ErrMalformedEntity
+	}
+
+	if _, err := ts.things.RetrieveByID(ctx, owner, id); err != nil {
+		return err
+	}
+
+	if _, err := ts.users.Exists(ctx, &mainflux.UserID{Value: newOwner}); err != nil {
+		return ErrMalformedEntity
+	}
+
+	chanIDs, err := ts.channels.RetrieveConnected(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := ts.things.Transfer(ctx, owner, id, newOwner, keepConns); err != nil {
+		return err
+	}
+
+	if err := ts.revoke(ctx, id); err != nil {
+		return err
+	}
+
+	if !keepConns {
+		for _, chanID := range chanIDs {
+			ts.channelCache.Disconnect(ctx, chanID, id)
+		}
+		ts.channelCache.RemoveConnected(ctx, id)
+	}
+
+	return ts.recordEvents(ctx, transferEvents(ThingKind, auditActor(res), id, owner, newOwner)...)
+}
+
+func (ts *thingsService) CreateChannel(ctx context.Context, token string, channel Channel) (Channel, error) {
+	if err := channel.Validate(ts.limits); err != nil {
+		return Channel{}, err
+	}
+
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return Channel{}, ErrUnauthorizedAccess
+	}
+
+	channel.Owner = res.GetValue()
+	return ts.createChannel(ctx, auditActor(res), channel)
+}
+
+func (ts *thingsService) createChannel(ctx context.Context, actor string, channel Channel) (Channel, error) {
+	if err := ts.checkChannelsQuota(ctx, channel.Owner, 1); err != nil {
+		return Channel{}, err
+	}
+
+	var err error
+	channel.ID, err = ts.idp.ID()
+	if err != nil {
+		return Channel{}, err
+	}
+
+	channel.State = Active
+
+	id, err := ts.channels.Save(ctx, channel)
+	if err != nil {
+		return Channel{}, err
+	}
+
+	channel.ID = id
+	if err := ts.cacheLimits(ctx, channel); err != nil {
+		return Channel{}, err
+	}
+
+	if err := ts.recordEvents(ctx, channelEvent(CreateOperation, actor, Channel{}, channel, time.Now())); err != nil {
+		return Channel{}, err
+	}
+
+	return channel, nil
+}
+
+func (ts *thingsService) UpdateChannel(ctx context.Context, token string, channel Channel) error {
+	if err := channel.Validate(ts.limits); err != nil {
+		return err
+	}
+
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return ErrUnauthorizedAccess
+	}
+
+	channel.Owner, err = ts.accessOwner(ctx, res.GetValue(), ChannelKind, channel.ID, ManagePermission)
+	if err != nil {
+		return err
+	}
+
+	old, err := ts.channels.RetrieveByID(ctx, channel.Owner, channel.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := ts.channels.Update(ctx, channel); err != nil {
+		return err
+	}
+
+	if err := ts.cacheLimits(ctx, channel); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if err := ts.record(ctx, ChannelChanges(old, channel, now)); err != nil {
+		return err
+	}
+
+	return ts.recordEvents(ctx, channelEvent(UpdateOperation, auditActor(res), old, channel, now))
+}
+
+func (ts *thingsService) PatchChannel(ctx context.Context, token, id string, patch map[string]interface{}) (Channel, error) {
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return Channel{}, ErrUnauthorizedAccess
+	}
+
+	owner, err := ts.accessOwner(ctx, res.GetValue(), ChannelKind, id, ManagePermission)
+	if err != nil {
+		return Channel{}, err
+	}
+
+	old, err := ts.channels.RetrieveByID(ctx, owner, id)
+	if err != nil {
+		return Channel{}, err
+	}
+
+	patched, err := PatchChannel(old, patch)
+	if err != nil {
+		return Channel{}, err
+	}
+
+	if err := patched.Validate(ts.limits); err != nil {
+		return Channel{}, err
+	}
+
+	channel, err := ts.channels.Patch(ctx, owner, id, patch)
+	if err != nil {
+		return Channel{}, err
+	}
+
+	if err := ts.cacheLimits(ctx, channel); err != nil {
+		return Channel{}, err
+	}
+
+	now := time.Now()
+	if err := ts.record(ctx, ChannelChanges(old, channel, now)); err != nil {
+		return Channel{}, err
+	}
+
+	if err := ts.recordEvents(ctx, channelEvent(UpdateOperation, auditActor(res), old, channel, now)); err != nil {
+		return Channel{}, err
+	}
+
+	return channel, nil
+}
+
+func (ts *thingsService) ViewChannel(ctx context.Context, token, id string) (Channel, error) {
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return Channel{}, ErrUnauthorizedAccess
+	}
+
+	owner, err := ts.accessOwner(ctx, res.GetValue(), ChannelKind, id, ReadPermission)
+	if err != nil {
+		return Channel{}, err
+	}
+
+	return ts.channels.RetrieveByID(ctx, owner, id)
+}
+
+func (ts *thingsService) ChannelHistory(ctx context.Context, token, id string, offset, limit uint64) (ChangesPage, error) {
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return ChangesPage{}, ErrUnauthorizedAccess
+	}
+
+	owner, err := ts.accessOwner(ctx, res.GetValue(), ChannelKind, id, ReadPermission)
+	if err != nil {
+		return ChangesPage{}, err
+	}
+
+	if _, err := ts.channels.RetrieveByID(ctx, owner, id); err != nil {
+		return ChangesPage{}, err
+	}
+
+	return ts.history.RetrieveAll(ctx, owner, id, offset, limit)
+}
+
+func (ts *thingsService) Audit(ctx context.Context, token string, query AuditQuery, offset, limit uint64) (AuditPage, error) {
+	if err := query.Validate(); err != nil {
+		return AuditPage{}, err
+	}
+
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return AuditPage{}, ErrUnauthorizedAccess
+	}
+
+	return ts.audit.RetrieveAll(ctx, res.GetValue(), query, offset, limit)
+}
+
+func (ts *thingsService) Capabilities(ctx context.Context, token, kind, id string) (Capabilities, error) {
+	if kind == "" && id != "" || kind != "" && id == "" {
+		return Capabilities{}, ErrMalformedEntity
+	}
+
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return Capabilities{}, ErrUnauthorizedAccess
+	}
+
+	user := res.GetValue()
+	if kind == "" {
+		return Capabilities{Operations: append([]string{}, accountOperations...)}, nil
+	}
+
+	switch err := ts.retrieveEntity(ctx, user, kind, id); err {
+	case nil:
+		return ownerCapabilities(kind, id), nil
+	case ErrNotFound:
+	default:
+		return Capabilities{}, err
+	}
+
+	share, err := ts.shares.Retrieve(ctx, kind, id, user)
+	if err != nil {
+		return Capabilities{}, err
+	}
+
+	if err := ts.retrieveEntity(ctx, share.Owner, kind, id); err != nil {
+		return Capabilities{}, err
+	}
+
+	return sharedCapabilities(share), nil
+}
+
+func (ts *thingsService) ListChannels(ctx context.Context, token string, offset, limit uint64, name string, metadata map[string]interface{}, tags TagFilter, cursor string) (ChannelsPage, error) {
+	after, err := DecodeCursor(cursor)
+	if err != nil {
+		return ChannelsPage{}, err
+	}
+
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return ChannelsPage{}, ErrUnauthorizedAccess
+	}
+
+	shared, err := ts.sharedIDs(ctx, res.GetValue(), ChannelKind)
+	if err != nil {
+		return ChannelsPage{}, err
+	}
+
+	page, err := ts.channels.RetrieveAccessible(ctx, res.GetValue(), shared, offset, limit, name, metadata, tags, after)
+	if err != nil {
+		return ChannelsPage{}, err
+	}
+
+	if n := len(page.Channels); n > 0 && uint64(n) == limit {
+		page.Cursor = EncodeCursor(page.Channels[n-1].ID)
+	}
+
+	return page, nil
+}
+
+func (ts *thingsService) CountChannels(ctx context.Context, token, name string, metadata map[string]interface{}, tags TagFilter) (uint64, error) {
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return 0, ErrUnauthorizedAccess
+	}
+
+	shared, err := ts.sharedIDs(ctx, res.GetValue(), ChannelKind)
+	if err != nil {
+		return 0, err
+	}
+
+	return ts.channels.CountAccessible(ctx, res.GetValue(), shared, name, metadata, tags)
+}
+
+func (ts *thingsService) ListChannelsByThing(ctx context.Context, token, thing string, offset, limit uint64) (ChannelsPage, error) {
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return ChannelsPage{}, ErrUnauthorizedAccess
+	}
+
+	owner, err := ts.accessOwner(ctx, res.GetValue(), ThingKind, thing, ReadPermission)
+	if err != nil {
+		return ChannelsPage{}, err
+	}
+
+	return ts.channels.RetrieveByThing(ctx, owner, thing, offset, limit)
+}
+
+func (ts *thingsService) RemoveChannel(ctx context.Context, token, id string) error {
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return ErrUnauthorizedAccess
+	}
+
+	owner := res.GetValue()
+
+	// Removal of the non-existing channel succeeds, but isn't audited.
+	old, err := ts.channels.RetrieveByID(ctx, owner, id)
+	existed := err == nil
+	if existed {
+		if err := ts.revokeConnected(ctx, owner, id); err != nil {
+			return err
+		}
+	}
+
+	ts.channelCache.Remove(ctx, id)
+	if err := ts.channels.Remove(ctx, owner, id); err != nil {
+		return err
+	}
+
+	if !existed {
+		return nil
+	}
+
+	return ts.recordEvents(ctx, channelEvent(RemoveOperation, auditActor(res), old, Channel{ID: id, Owner: owner}, time.Now()))
+}
+
+func (ts *thingsService) TransferChannel(ctx context.Context, token, id, newOwner string, keepConns bool) error {
+	if newOwner == "" {
+		return ErrMalformedEntity
+	}
+
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return ErrUnauthorizedAccess
+	}
+
+	owner := res.GetValue()
+	if newOwner == owner {
+		return ErrMalformedEntity
+	}
+
+	channel, err := ts.channels.RetrieveByID(ctx, owner, id)
+	if err != nil {
+		return err
+	}
+
+	if _, err := ts.users.Exists(ctx, &mainflux.UserID{Value: newOwner}); err != nil {
+		return ErrMalformedEntity
+	}
+
+	// The connections dropped by the transfer are held by the signed keys
+	// of the connected things.
+	if !keepConns {
+		if err := ts.revokeConnected(ctx, owner, id); err != nil {
+			return err
+		}
+	}
+
+	if err := ts.channels.Transfer(ctx, owner, id, newOwner, keepConns); err != nil {
+		return err
+	}
+
+	// The cached connections are dropped along with the channel limits,
+	// which are cached anew, as the new owner's override applies.
+	channel.Owner = newOwner
+	if !keepConns {
+		ts.channelCache.Remove(ctx, id)
+	}
+	if err := ts.cacheLimits(ctx, channel); err != nil {
+		return err
+	}
+
+	return ts.recordEvents(ctx, transferEvents(ChannelKind, auditActor(res), id, owner, newOwner)...)
+}
+
+func (ts *thingsService) ArchiveChannel(ctx context.Context, token, id string) error {
+	return ts.changeChannelState(ctx, token, id, Archived)
+}
+
+func (ts *thingsService) UnarchiveChannel(ctx context.Context, token, id string) error {
+	return ts.changeChannelState(ctx, token, id, Active)
+}
+
+func (ts *thingsService) changeChannelState(ctx context.Context, token, id string, state State) error {
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return ErrUnauthorizedAccess
+	}
+
+	owner := res.GetValue()
+
+	channel, err := ts.channels.RetrieveByID(ctx, owner, id)
+	if err != nil {
+		return err
+	}
+
+	if channel.State == state {
+		return nil
+	}
+
+	if err := ts.channels.UpdateState(ctx, owner, id, state); err != nil {
+		return err
+	}
+
+	if err := ts.channelCache.SaveState(ctx, id, state); err != nil {
+		return err
+	}
+
+	return ts.record(ctx, []Change{stateChange(id, owner, channel.State, state, time.Now())})
+}
+
+func (ts *thingsService) ArchiveChannels(ctx context.Context, token string, selector map[string]interface{}, tags TagFilter) ([]Channel, error) {
+	if selector == nil && len(tags.Tags) == 0 {
+		return []Channel{}, ErrMalformedEntity
+	}
+
+	if selector == nil {
+		selector = map[string]interface{}{}
+	}
+
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return []Channel{}, ErrUnauthorizedAccess
+	}
+
+	archived, err := ts.channels.Archive(ctx, res.GetValue(), selector, tags)
+	if err != nil {
+		return []Channel{}, err
+	}
+
+	now := time.Now()
+	changes := []Change{}
+	for _, channel := range archived {
+		if err := ts.channelCache.SaveState(ctx, channel.ID, Archived); err != nil {
+			return []Channel{}, err
+		}
+		changes = append(changes, stateChange(channel.ID, channel.Owner, Active, Archived, now))
+	}
+
+	if err := ts.record(ctx, changes); err != nil {
+		return []Channel{}, err
+	}
+
+	return archived, nil
+}
+
+func (ts *thingsService) InviteToChannel(ctx context.Context, token, id string, ttl time.Duration) (Invitation, string, error) {
+	if ttl <= 0 || ttl > maxInvitationTTL {
+		return Invitation{}, "", ErrMalformedEntity
+	}
+
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return Invitation{}, "", ErrUnauthorizedAccess
+	}
+
+	if _, err := ts.channels.RetrieveByID(ctx, res.GetValue(), id); err != nil {
+		return Invitation{}, "", err
+	}
+
+	invID, err := ts.idp.ID()
+	if err != nil {
+		return Invitation{}, "", err
+	}
+
+	// Invitations carry the time with the second precision.
+	now := time.Now().UTC().Truncate(time.Second)
+	inv := Invitation{
+		ID:        invID,
+		ChannelID: id,
+		IssuedAt:  now,
+		ExpiresAt: now.Add(ttl),
+	}
+
+	signed, err := ts.keys.IssueInvitation(inv)
+	if err != nil {
+		return Invitation{}, "", err
+	}
+
+	return inv, signed, nil
+}
+
+func (ts *thingsService) IssueChannelToken(ctx context.Context, token, id string, scope KeyScope, ttl time.Duration) (ChannelToken, error) {
+	if !scope.Valid() || ttl <= 0 || ttl > maxChannelTokenTTL {
+		return ChannelToken{}, ErrMalformedEntity
+	}
+
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return ChannelToken{}, ErrUnauthorizedAccess
+	}
+
+	if _, err := ts.channels.RetrieveByID(ctx, res.GetValue(), id); err != nil {
+		return ChannelToken{}, err
+	}
+
+	now := time.Now().UTC()
+	ct := ChannelToken{
+		ChannelID: id,
+		Owner:     res.GetValue(),
+		Scope:     scope,
+		IssuedAt:  now,
+		ExpiresAt: now.Add(ttl),
+	}
+
+	ct.ID, err = ts.idp.ID()
+	if err != nil {
+		return ChannelToken{}, err
+	}
+
+	ct.Token, err = ts.idp.ID()
+	if err != nil {
+		return ChannelToken{}, err
+	}
+
+	if _, err := ts.chanTokens.Save(ctx, ct); err != nil {
+		return ChannelToken{}, err
+	}
+
+	return ct, nil
+}
+
+func (ts *thingsService) ListChannelTokens(ctx context.Context, token, id string) ([]ChannelToken, error) {
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return nil, ErrUnauthorizedAccess
+	}
+
+	if _, err := ts.channels.RetrieveByID(ctx, res.GetValue(), id); err != nil {
+		return nil, err
+	}
+
+	return ts.chanTokens.RetrieveAll(ctx, res.GetValue(), id)
+}
+
+func (ts *thingsService) RevokeChannelToken(ctx context.Context, token, id, tokenID string) error {
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return ErrUnauthorizedAccess
+	}
+
+	return ts.chanTokens.Remove(ctx, res.GetValue(), id, tokenID)
+}
+
+func (ts *thingsService) Connect(ctx context.Context, token string, chanIDs, thingIDs []string, access ConnectionAccess) error {
+	if !access.Valid() {
+		return ErrMalformedEntity
+	}
+
+	if err := validateConnections(chanIDs, thingIDs); err != nil {
+		return err
+	}
+
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return ErrUnauthorizedAccess
+	}
+
+	conns, err := ts.pairConnections(ctx, res.GetValue(), chanIDs, thingIDs)
+	if err != nil {
+		return err
+	}
+
+	for i := range conns {
+		conns[i].Access = access
+	}
+
+	if err := ts.channels.Connect(ctx, conns...); err != nil {
+		return err
+	}
+
+	// The signed keys hold the access of the connections, which may have
+	// been restricted by the reconnection.
+	if err := ts.revokeAll(ctx, thingIDs); err != nil {
+		return err
+	}
+
+	// Only the unrestricted connections are cached, so the restricted
+	// access must not be bypassed by the connection cached before.
+	if access != FullAccess {
+		for _, conn := range conns {
+			ts.channelCache.Disconnect(ctx, conn.ChanID, conn.ThingID)
+		}
+	}
+
+	for _, thingID := range thingIDs {
+		ts.channelCache.RemoveConnected(ctx, thingID)
+	}
+
+	return ts.recordEvents(ctx, connectionEvents(ConnectOperation, auditActor(res), conns)...)
+}
+
+func (ts *thingsService) Disconnect(ctx context.Context, token string, chanIDs, thingIDs []string) error {
+	if err := validateConnections(chanIDs, thingIDs); err != nil {
+		return err
+	}
+
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return ErrUnauthorizedAccess
+	}
+
+	conns, err := ts.pairConnections(ctx, res.GetValue(), chanIDs, thingIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, conn := range conns {
+		ts.channelCache.Disconnect(ctx, conn.ChanID, conn.ThingID)
+	}
+
+	if err := ts.channels.Disconnect(ctx, conns...); err != nil {
+		return err
+	}
+
+	if err := ts.revokeAll(ctx, thingIDs); err != nil {
+		return err
+	}
+
+	for _, thingID := range thingIDs {
+		ts.channelCache.RemoveConnected(ctx, thingID)
+	}
+
+	return ts.recordEvents(ctx, connectionEvents(DisconnectOperation, auditActor(res), conns)...)
+}
+
+func (ts *thingsService) Export(ctx context.Context, token string) (Snapshot, error) {
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return Snapshot{}, ErrUnauthorizedAccess
+	}
+
+	owner := res.GetValue()
+	snapshot := Snapshot{
+		Things:      []Thing{},
+		Channels:    []Channel{},
+		Connections: []Connection{},
+	}
+
+	for after := ""; ; {
+		page, err := ts.things.RetrieveAll(ctx, owner, 0, exportBatchSize, "", nil, TagFilter{}, StatusFilter{}, after)
+		if err != nil {
+			return Snapshot{}, err
+		}
+
+		snapshot.Things = append(snapshot.Things, page.Things...)
+		if len(page.Things) < exportBatchSize {
+			break
+		}
+		after = page.Things[len(page.Things)-1].ID
+	}
+
+	for after := ""; ; {
+		page, err := ts.channels.RetrieveAll(ctx, owner, 0, exportBatchSize, "", nil, TagFilter{}, after)
+		if err != nil {
+			return Snapshot{}, err
+		}
+
+		snapshot.Channels = append(snapshot.Channels, page.Channels...)
+		if len(page.Channels) < exportBatchSize {
+			break
+		}
+		after = page.Channels[len(page.Channels)-1].ID
+	}
+
+	for _, thing := range snapshot.Things {
+		chanIDs, err := ts.connections(ctx, owner, thing.ID)
+		if err != nil {
+			return Snapshot{}, err
+		}
+
+		restricted, err := ts.channels.RetrieveRestricted(ctx, thing.ID)
+		if err != nil {
+			return Snapshot{}, err
+		}
+
+		for _, chanID := range chanIDs {
+			snapshot.Connections = append(snapshot.Connections, Connection{
+				ChanID:  chanID,
+				ThingID: thing.ID,
+				Owner:   owner,
+				Access:  restricted[chanID],
+			})
+		}
+	}
+
+	return snapshot, nil
+}
+
+func (ts *thingsService) Import(ctx context.Context, token string, snapshot Snapshot, preserve bool) (Snapshot, error) {
+	if err := snapshot.Validate(ts.limits); err != nil {
+		return Snapshot{}, err
+	}
+
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return Snapshot{}, ErrUnauthorizedAccess
+	}
+
+	owner := res.GetValue()
+
+	if err := ts.checkThingsQuota(ctx, owner, uint64(len(snapshot.Things))); err != nil {
+		return Snapshot{}, err
+	}
+
+	if err := ts.checkChannelsQuota(ctx, owner, uint64(len(snapshot.Channels))); err != nil {
+		return Snapshot{}, err
+	}
+
+	things, thingIDs, err := ts.importThings(ctx, owner, auditActor(res), snapshot.Things, preserve)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	channels, chanIDs, err := ts.importChannels(ctx, owner, auditActor(res), snapshot.Channels, preserve)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	conns := make([]Connection, len(snapshot.Connections))
+	for i, conn := range snapshot.Connections {
+		conns[i] = Connection{
+			ChanID:  chanIDs[conn.ChanID],
+			ThingID: thingIDs[conn.ThingID],
+			Owner:   owner,
+			Access:  conn.Access,
+		}
+	}
+
+	if len(conns) > 0 {
+		if err := ts.channels.Connect(ctx, conns...); err != nil {
+			return Snapshot{}, err
+		}
+
+		if err := ts.recordEvents(ctx, connectionEvents(ConnectOperation, auditActor(res), conns)...); err != nil {
+			return Snapshot{}, err
+		}
+	}
+
+	return Snapshot{
+		Things:      things,
+		Channels:    channels,
+		Connections: conns,
+	}, nil
+}
+
+// importThings saves the snapshot's things as the things of the owner and
+// returns them, along with their identifiers by the snapshot's ones. The
+// things that don't preserve their identifiers and keys are given the new
+// ones, dropping the expiration and the rotation of their keys.
+func (ts *thingsService) importThings(ctx context.Context, owner, actor string, snapshot []Thing, preserve bool) ([]Thing, map[string]string, error) {
+	ids := make(map[string]string, len(snapshot))
+	if len(snapshot) == 0 {
+		return []Thing{}, ids, nil
+	}
+
+	things := make([]Thing, len(snapshot))
+	for i, thing := range snapshot {
+		var err error
+		if !preserve {
+			if thing.ID, err = ts.idp.ID(); err != nil {
+				return nil, nil, err
+			}
+			thing.Key = ""
+			thing.KeyExpiresAt = time.Time{}
+			thing.KeyRotation = 0
+		}
+
+		if thing.Key == "" {
+			if thing.Key, err = ts.idp.ID(); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		if thing.State == "" {
+			thing.State = Enabled
+		}
+
+		thing.Owner = owner
+		thing.LastSeen = time.Time{}
+		things[i] = thing
+	}
+
+	saved, err := ts.things.SaveAll(ctx, things...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	events := make([]AuditEvent, len(things))
+	for i := range things {
+		things[i].ID = saved[i]
+		ids[snapshot[i].ID] = saved[i]
+
+		if things[i].State == Disabled {
+			if err := ts.things.UpdateState(ctx, owner, saved[i], Disabled); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		if !things[i].KeyExpiresAt.IsZero() || things[i].KeyRotation > 0 {
+			if err := ts.things.UpdateKey(ctx, things[i]); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		if err := ts.cacheTransform(ctx, things[i]); err != nil {
+			return nil, nil, err
+		}
+		events[i] = thingEvent(CreateOperation, actor, Thing{}, things[i], now)
+	}
+
+	if err := ts.recordEvents(ctx, events...); err != nil {
+		return nil, nil, err
+	}
+
+	return things, ids, nil
+}
+
+// importChannels saves the snapshot's channels as the channels of the owner
+// and returns them, along with their identifiers by the snapshot's ones.
+func (ts *thingsService) importChannels(ctx context.Context, owner, actor string, snapshot []Channel, preserve bool) ([]Channel, map[string]string, error) {
+	ids := make(map[string]string, len(snapshot))
+	channels := make([]Channel, len(snapshot))
+	events := make([]AuditEvent, len(snapshot))
+	now := time.Now()
+
+	for i, channel := range snapshot {
+		if !preserve {
+			var err error
+			if channel.ID, err = ts.idp.ID(); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		if channel.State == "" {
+			channel.State = Active
+		}
+		channel.Owner = owner
+
+		id, err := ts.channels.Save(ctx, channel)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		channel.ID = id
+		ids[snapshot[i].ID] = id
+
+		if err := ts.cacheLimits(ctx, channel); err != nil {
+			return nil, nil, err
+		}
+
+		if channel.State == Archived {
+			if err := ts.channelCache.SaveState(ctx, id, Archived); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		channels[i] = channel
+		events[i] = channelEvent(CreateOperation, actor, Channel{}, channel, now)
+	}
+
+	if err := ts.recordEvents(ctx, events...); err != nil {
+		return nil, nil, err
+	}
+
+	return channels, ids, nil
+}
+
+func (ts *thingsService) CreateGroup(ctx context.Context, token string, group Group) (Group, error) {
+	if err := group.Validate(ts.limits); err != nil {
+		return Group{}, err
+	}
+
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return Group{}, ErrUnauthorizedAccess
+	}
+
+	group.Owner = res.GetValue()
+
+	if group.Parent != "" {
+		if _, err := ts.groups.RetrieveByID(ctx, group.Owner, group.Parent); err != nil {
+			return Group{}, err
+		}
+	}
+
+	group.ID, err = ts.idp.ID()
+	if err != nil {
+		return Group{}, err
+	}
+
+	id, err := ts.groups.Save(ctx, group)
+	if err != nil {
+		return Group{}, err
+	}
+
+	group.ID = id
+	return group, nil
+}
+
+func (ts *thingsService) UpdateGroup(ctx context.Context, token string, group Group) error {
+	if err := group.Validate(ts.limits); err != nil {
+		return err
+	}
+
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return ErrUnauthorizedAccess
+	}
+
+	group.Owner = res.GetValue()
+	return ts.groups.Update(ctx, group)
+}
+
+func (ts *thingsService) ViewGroup(ctx context.Context, token, id string) (Group, error) {
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return Group{}, ErrUnauthorizedAccess
+	}
+
+	return ts.groups.RetrieveByID(ctx, res.GetValue(), id)
+}
+
+func (ts *thingsService) ListGroups(ctx context.Context, token, parent string, offset, limit uint64) (GroupsPage, error) {
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return GroupsPage{}, ErrUnauthorizedAccess
+	}
+
+	return ts.groups.RetrieveAll(ctx, res.GetValue(), parent, offset, limit)
+}
+
+func (ts *thingsService) ListThingsByGroup(ctx context.Context, token, id string, offset, limit uint64) (ThingsPage, error) {
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return ThingsPage{}, ErrUnauthorizedAccess
+	}
+
+	return ts.groups.RetrieveThings(ctx, res.GetValue(), id, offset, limit)
+}
+
+func (ts *thingsService) RemoveGroup(ctx context.Context, token, id string) error {
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return ErrUnauthorizedAccess
+	}
+
+	return ts.groups.Remove(ctx, res.GetValue(), id)
+}
+
+func (ts *thingsService) AssignThings(ctx context.Context, token, id string, thingIDs ...string) error {
+	if len(thingIDs) == 0 || len(thingIDs) > maxBulkThings {
+		return ErrMalformedEntity
+	}
+
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return ErrUnauthorizedAccess
+	}
+
+	return ts.groups.Assign(ctx, res.GetValue(), id, thingIDs...)
+}
+
+func (ts *thingsService) UnassignThing(ctx context.Context, token, id, thingID string) error {
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return ErrUnauthorizedAccess
+	}
+
+	return ts.groups.Unassign(ctx, res.GetValue(), id, thingID)
+}
+
+func (ts *thingsService) ConnectGroup(ctx context.Context, token, id, chanID string) ([]string, error) {
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return nil, ErrUnauthorizedAccess
+	}
+
+	owner := res.GetValue()
+
+	if _, err := ts.groups.RetrieveByID(ctx, owner, id); err != nil {
+		return nil, err
+	}
+
+	if _, err := ts.channels.RetrieveByID(ctx, owner, chanID); err != nil {
+		return nil, err
+	}
+
+	connected, err := ts.groups.Connect(ctx, owner, id, chanID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, thingID := range connected {
+		ts.channelCache.RemoveConnected(ctx, thingID)
+	}
+
+	return connected, nil
+}
+
+func (ts *thingsService) CanAccess(ctx context.Context, chanID, key, action, subtopic string) (string, error) {
+	thingID, err := ts.canAccess(ctx, chanID, key, action, subtopic)
+	if err != nil {
+		return "", err
+	}
+
+	// Adapters that don't provide the action are treated as publishers.
+	if action == mainflux.ActionSubscribe {
+		return thingID, nil
+	}
+
+	state, err := ts.channelState(ctx, chanID)
+	if err != nil {
+		return "", err
+	}
+
+	if state == Archived {
+		return "", ErrUnauthorizedAccess
+	}
+
+	return thingID, nil
+}
+
+func (ts *thingsService) canAccess(ctx context.Context, chanID, key, action, subtopic string) (string, error) {
+	if sk, err := ts.keys.Parse(key); err == nil {
+		if !sk.CanAccess(chanID, action, subtopic) || ts.revoked(ctx, sk) {
+			return "", ErrUnauthorizedAccess
+		}
+
+		return sk.ThingID, nil
+	}
+
+	thingID, err := ts.isConnected(ctx, chanID, key, action)
+	if err != nil {
+		// Channel tokens aren't bound to any thing, so the subtopic rules
+		// don't apply to them.
+		if id, err := ts.hasChannelToken(ctx, chanID, key, action); err == nil {
+			return id, nil
+		}
+		return "", err
+	}
+
+	// The rules are not cached, so that the changes apply immediately.
+	rules, err := ts.rules.RetrieveByThing(ctx, thingID)
+	if err != nil {
+		return "", err
+	}
+
+	if !AllowsSubtopic(rules, chanID, action, subtopic) {
+		return "", ErrUnauthorizedAccess
+	}
+
+	return thingID, nil
+}
+
+func (ts *thingsService) isConnected(ctx context.Context, chanID, key, action string) (string, error) {
+	thingID, err := ts.hasThing(ctx, chanID, key)
+	if err == nil {
+		return thingID, nil
+	}
+
+	th, access, err := ts.channels.HasThing(ctx, chanID, key)
+	if err != nil {
+		return ts.hasScopedKey(ctx, chanID, key, action)
+	}
+
+	if !access.Allows(action) {
+		return "", ErrUnauthorizedAccess
+	}
+
+	ts.thingCache.Save(ctx, key, th.ID, th.KeyExpiresAt)
+	if access == FullAccess {
+		ts.channelCache.Connect(ctx, chanID, th.ID)
+	}
+	return th.ID, nil
+}
+
+func (ts *thingsService) Identify(ctx context.Context, key string) (string, error) {
+	if sk, err := ts.keys.Parse(key); err == nil {
+		if ts.revoked(ctx, sk) {
+			return "", ErrUnauthorizedAccess
+		}
+
+		return sk.ThingID, nil
+	}
+
+	id, err := ts.thingCache.ID(ctx, key)
+	if err == nil {
+		return id, nil
+	}
+
+	return ts.identifyStored(ctx, key)
+}
+
+// identifyStored looks up the thing having the plain or the additional key
+// in the repositories, caching the plain one.
+func (ts *thingsService) identifyStored(ctx context.Context, key string) (string, error) {
+	th, err := ts.things.RetrieveByKey(ctx, key)
+	if err != nil {
+		tk, err := ts.thingKeys.RetrieveByKey(ctx, key)
+		if err != nil {
+			return "", ErrUnauthorizedAccess
+		}
+
+		return tk.ThingID, nil
+	}
+
+	// The key is cached until it expires, so that the expired key is not
+	// identified by the cache.
+	ts.thingCache.Save(ctx, key, th.ID, th.KeyExpiresAt)
+	return th.ID, nil
+}
+
+func (ts *thingsService) IdentifyBatch(ctx context.Context, keys []string) ([]string, error) {
+	if len(keys) == 0 || len(keys) > maxIdentifyBatch {
+		return nil, ErrMalformedEntity
+	}
+
+	ids := make([]string, len(keys))
+	plain := []string{}
+	pos := []int{}
+	for i, key := range keys {
+		if sk, err := ts.keys.Parse(key); err == nil {
+			if !ts.revoked(ctx, sk) {
+				ids[i] = sk.ThingID
+			}
+			continue
+		}
+		plain = append(plain, key)
+		pos = append(pos, i)
+	}
+
+	if len(plain) == 0 {
+		return ids, nil
+	}
+
+	cached, err := ts.thingCache.IDs(ctx, plain)
+	if err != nil {
+		cached = make([]string, len(plain))
+	}
+
+	for i, key := range plain {
+		if cached[i] != "" {
+			ids[pos[i]] = cached[i]
+			continue
+		}
+
+		if id, err := ts.identifyStored(ctx, key); err == nil {
+			ids[pos[i]] = id
+		}
+	}
+
+	return ids, nil
+}
+
+func (ts *thingsService) CanRead(ctx context.Context, chanID, token string) (string, error) {
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return "", ErrUnauthorizedAccess
+	}
+
+	if _, err := ts.channels.RetrieveByID(ctx, res.GetValue(), chanID); err != nil {
+		if err == ErrNotFound {
+			return "", ErrUnauthorizedAccess
+		}
+		return "", err
+	}
+
+	return res.GetValue(), nil
+}
+
+func (ts *thingsService) ConnectedChannels(ctx context.Context, thingID string) ([]string, error) {
+	if thingID == "" {
+		return nil, ErrMalformedEntity
+	}
+
+	if chanIDs, err := ts.channelCache.Connected(ctx, thingID); err == nil {
+		return chanIDs, nil
+	}
+
+	chanIDs, err := ts.channels.RetrieveConnected(ctx, thingID)
+	if err != nil {
+		return nil, err
+	}
+
+	ts.channelCache.SaveConnected(ctx, thingID, chanIDs)
+	return chanIDs, nil
+}
+
+func (ts *thingsService) RemoveUserHandler(ctx context.Context, owner string) ([]Thing, []Channel, error) {
+	removedThings, removedChannels := []Thing{}, []Channel{}
+
+	for {
+		page, err := ts.things.RetrieveAll(ctx, owner, 0, removeBatchSize, "", nil, TagFilter{}, StatusFilter{}, "")
+		if err != nil {
+			return removedThings, removedChannels, err
+		}
+
+		if len(page.Things) == 0 {
+			break
+		}
+
+		for _, thing := range page.Things {
+			if err := ts.revoke(ctx, thing.ID); err != nil {
+				return removedThings, removedChannels, err
+			}
+			ts.thingCache.Remove(ctx, thing.ID)
+			ts.thingCache.SaveTransform(ctx, thing.ID, Transform{})
+			ts.channelCache.RemoveConnected(ctx, thing.ID)
+			if err := ts.things.Remove(ctx, owner, thing.ID); err != nil {
+				return removedThings, removedChannels, err
+			}
+			removedThings = append(removedThings, thing)
+		}
+	}
+
+	for {
+		page, err := ts.channels.RetrieveAll(ctx, owner, 0, removeBatchSize, "", nil, TagFilter{}, "")
+		if err != nil {
+			return removedThings, removedChannels, err
+		}
+
+		if len(page.Channels) == 0 {
+			break
+		}
+
+		for _, channel := range page.Channels {
+			ts.channelCache.Remove(ctx, channel.ID)
+			if err := ts.channels.Remove(ctx, owner, channel.ID); err != nil {
+				return removedThings, removedChannels, err
+			}
+			removedChannels = append(removedChannels, channel)
+		}
+	}
+
+	for {
+		page, err := ts.groups.RetrieveAll(ctx, owner, "", 0, removeBatchSize)
+		if err != nil {
+			return removedThings, removedChannels, err
+		}
+
+		if len(page.Groups) == 0 {
+			break
+		}
+
+		for _, group := range page.Groups {
+			if err := ts.groups.Remove(ctx, owner, group.ID); err != nil {
+				return removedThings, removedChannels, err
+			}
+		}
+	}
+
+	if err := ts.templates.Remove(ctx, owner); err != nil {
+		return removedThings, removedChannels, err
+	}
+
+	for {
+		page, err := ts.profiles.RetrieveAll(ctx, owner, 0, removeBatchSize)
+		if err != nil {
+			return removedThings, removedChannels, err
+		}
+
+		if len(page.Profiles) == 0 {
+			break
+		}
+
+		for _, profile := range page.Profiles {
+			if err := ts.profiles.Remove(ctx, owner, profile.ID); err != nil {
+				return removedThings, removedChannels, err
+			}
+		}
+	}
+
+	if err := ts.shares.RemoveByUser(ctx, owner); err != nil {
+		return removedThings, removedChannels, err
+	}
+
+	return removedThings, removedChannels, ts.reservations.RemoveAll(ctx, owner)
+}
+
+func (ts *thingsService) RecordUsage(ctx context.Context, thingID, chanID string, size uint64) error {
+	return ts.usage.Add(ctx, thingID, chanID, size, time.Now())
+}
+
+func (ts *thingsService) RecordActivity(ctx context.Context, thingID string) error {
+	return ts.things.UpdateLastSeen(ctx, thingID, time.Now())
+}
+
+func (ts *thingsService) RecordDiagnostics(ctx context.Context, thingID string, payload []byte) error {
+	health, err := ParseDiagnostics(payload)
+	if err != nil {
+		return err
+	}
+
+	owner, err := ts.things.RetrieveOwner(ctx, thingID)
+	if err != nil {
+		return err
+	}
+
+	health.ThingID = thingID
+	health.Owner = owner
+	health.Reported = time.Now()
+	return ts.health.Save(ctx, health)
+}
+
+func (ts *thingsService) ViewHealth(ctx context.Context, token, id string) (Health, error) {
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return Health{}, ErrUnauthorizedAccess
+	}
+
+	owner, err := ts.accessOwner(ctx, res.GetValue(), ThingKind, id, ReadPermission)
+	if err != nil {
+		return Health{}, err
+	}
+
+	if _, err := ts.things.RetrieveByID(ctx, owner, id); err != nil {
+		return Health{}, err
+	}
+
+	return ts.health.RetrieveByThing(ctx, owner, id)
+}
+
+func (ts *thingsService) ListUnhealthy(ctx context.Context, token string, thresholds HealthThresholds, offset, limit uint64) (HealthPage, error) {
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return HealthPage{}, ErrUnauthorizedAccess
+	}
+
+	return ts.health.RetrieveUnhealthy(ctx, res.GetValue(), thresholds, offset, limit)
+}
+
+func (ts *thingsService) ThingUsage(ctx context.Context, token, id string, from, to time.Time) (Usage, error) {
+	if to.Before(from) || to.Sub(from) > maxUsagePeriod {
+		return Usage{}, ErrMalformedEntity
+	}
+
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return Usage{}, ErrUnauthorizedAccess
+	}
+
+	owner, err := ts.accessOwner(ctx, res.GetValue(), ThingKind, id, ReadPermission)
+	if err != nil {
+		return Usage{}, err
+	}
+
+	if _, err := ts.things.RetrieveByID(ctx, owner, id); err != nil {
+		return Usage{}, err
+	}
+
+	return ts.usage.RetrieveByThing(ctx, id, from, to)
+}
+
+func (ts *thingsService) ChannelUsage(ctx context.Context, token, id string, from, to time.Time) (Usage, error) {
+	if to.Before(from) || to.Sub(from) > maxUsagePeriod {
+		return Usage{}, ErrMalformedEntity
+	}
+
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return Usage{}, ErrUnauthorizedAccess
+	}
+
+	owner, err := ts.accessOwner(ctx, res.GetValue(), ChannelKind, id, ReadPermission)
+	if err != nil {
+		return Usage{}, err
+	}
+
+	if _, err := ts.channels.RetrieveByID(ctx, owner, id); err != nil {
+		return Usage{}, err
+	}
+
+	return ts.usage.RetrieveByChannel(ctx, id, from, to)
+}
+
+func (ts *thingsService) RegisterSubtopic(ctx context.Context, token, chanID string, subtopic Subtopic) error {
+	if err := subtopic.Validate(ts.limits); err != nil {
+		return err
+	}
+
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return ErrUnauthorizedAccess
+	}
+
+	if _, err := ts.channels.RetrieveByID(ctx, res.GetValue(), chanID); err != nil {
+		return err
+	}
+
+	subtopic.Channel = chanID
+	subtopic.Owner = res.GetValue()
+	return ts.subtopics.Save(ctx, subtopic)
+}
+
+func (ts *thingsService) ListSubtopics(ctx context.Context, token, chanID string) ([]Subtopic, error) {
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return nil, ErrUnauthorizedAccess
+	}
+
+	if _, err := ts.channels.RetrieveByID(ctx, res.GetValue(), chanID); err != nil {
+		return nil, err
+	}
+
+	return ts.subtopics.RetrieveAll(ctx, res.GetValue(), chanID)
+}
+
+func (ts *thingsService) RemoveSubtopic(ctx context.Context, token, chanID, name string) error {
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return ErrUnauthorizedAccess
+	}
+
+	return ts.subtopics.Remove(ctx, res.GetValue(), chanID, name)
+}
+
+func (ts *thingsService) ObservedSubtopics(ctx context.Context, token, chanID string) ([]ObservedSubtopic, error) {
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return nil, ErrUnauthorizedAccess
+	}
+
+	if _, err := ts.channels.RetrieveByID(ctx, res.GetValue(), chanID); err != nil {
+		return nil, err
+	}
+
+	registered, err := ts.subtopics.RetrieveAll(ctx, res.GetValue(), chanID)
+	if err != nil {
+		return nil, err
+	}
+
+	observed, err := ts.observations.RetrieveAll(ctx, chanID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range observed {
+		for _, st := range registered {
+			if st.Matches(observed[i].Name) {
+				observed[i].Registered = true
+				break
+			}
+		}
+	}
+
+	return observed, nil
+}
+
+func (ts *thingsService) RecordSubtopic(ctx context.Context, chanID, subtopic string) error {
+	return ts.observations.Observe(ctx, chanID, subtopic, time.Now())
+}
+
+func (ts *thingsService) AddSubtopicRule(ctx context.Context, token, chanID string, rule SubtopicRule) (SubtopicRule, error) {
+	if err := rule.Validate(); err != nil {
+		return SubtopicRule{}, err
+	}
+
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return SubtopicRule{}, ErrUnauthorizedAccess
+	}
+
+	if _, err := ts.channels.RetrieveByID(ctx, res.GetValue(), chanID); err != nil {
+		return SubtopicRule{}, err
+	}
+
+	if _, err := ts.things.RetrieveByID(ctx, res.GetValue(), rule.ThingID); err != nil {
+		return SubtopicRule{}, err
+	}
+
+	rule.ID, err = ts.idp.ID()
+	if err != nil {
+		return SubtopicRule{}, err
+	}
+
+	rule.Channel = chanID
+	rule.Owner = res.GetValue()
+	if _, err := ts.rules.Save(ctx, rule); err != nil {
+		return SubtopicRule{}, err
+	}
+
+	// The signed keys hold the rules of the thing, which the new rule
+	// restricts.
+	if err := ts.revoke(ctx, rule.ThingID); err != nil {
+		return SubtopicRule{}, err
+	}
+
+	return rule, nil
+}
+
+func (ts *thingsService) ListSubtopicRules(ctx context.Context, token, chanID string) ([]SubtopicRule, error) {
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return nil, ErrUnauthorizedAccess
+	}
+
+	if _, err := ts.channels.RetrieveByID(ctx, res.GetValue(), chanID); err != nil {
+		return nil, err
+	}
+
+	return ts.rules.RetrieveAll(ctx, res.GetValue(), chanID)
+}
+
+func (ts *thingsService) RemoveSubtopicRule(ctx context.Context, token, chanID, id string) error {
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return ErrUnauthorizedAccess
+	}
+
+	return ts.rules.Remove(ctx, res.GetValue(), chanID, id)
+}
+
+func (ts *thingsService) Share(ctx context.Context, token string, share Share) error {
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return ErrUnauthorizedAccess
+	}
+
+	share.Owner = res.GetValue()
+	if err := share.Validate(); err != nil {
+		return err
+	}
+
+	if err := ts.retrieveEntity(ctx, share.Owner, share.Kind, share.EntityID); err != nil {
+		return err
+	}
+
+	return ts.shares.Save(ctx, share)
+}
+
+func (ts *thingsService) Unshare(ctx context.Context, token, kind, id, user string) error {
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return ErrUnauthorizedAccess
+	}
+
+	return ts.shares.Remove(ctx, res.GetValue(), kind, id, user)
+}
+
+func (ts *thingsService) ListShares(ctx context.Context, token, kind, id string) ([]Share, error) {
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return nil, ErrUnauthorizedAccess
+	}
+
+	if err := ts.retrieveEntity(ctx, res.GetValue(), kind, id); err != nil {
+		return nil, err
+	}
+
+	return ts.shares.RetrieveAll(ctx, res.GetValue(), kind, id)
+}
+
+func (ts *thingsService) record(ctx context.Context, changes []Change) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	return ts.history.Save(ctx, changes...)
+}
+
+func (ts *thingsService) recordEvents(ctx context.Context, events ...AuditEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	return ts.audit.Save(ctx, events...)
+}
+
+// cacheLimits caches the channel's rate limit, validation rules, delivery
+// mode, payload transformation and allowed protocols, which are enforced by
+// the services that have no access to the channel metadata. The channels
+// without the rate limit of their own get the rate limit of the owner's
+// override.
+func (ts *thingsService) cacheLimits(ctx context.Context, channel Channel) error {
+	override := ts.overrides.Override(channel.Owner)
+
+	rl, err := channel.RateLimit()
+	switch err {
+	case nil:
+	case ErrMetadataNotFound:
+		rl = override.RateLimit
+	default:
+		return err
+	}
+
+	if err := ts.channelCache.SaveRateLimit(ctx, channel.ID, rl); err != nil {
+		return err
+	}
+
+	if err := ts.channelCache.SaveProtocols(ctx, channel.ID, override.Protocols); err != nil {
+		return err
+	}
+
+	v, err := channel.Validation()
+	if err != nil && err != ErrMetadataNotFound {
+		return err
+	}
+
+	if err := ts.channelCache.SaveValidation(ctx, channel.ID, v); err != nil {
+		return err
+	}
+
+	d, err := channel.Delivery()
+	if err != nil && err != ErrMetadataNotFound {
+		return err
+	}
+
+	if err := ts.channelCache.SaveDelivery(ctx, channel.ID, d); err != nil {
+		return err
+	}
+
+	tr, err := channel.Transform()
+	if err != nil && err != ErrMetadataNotFound {
+		return err
+	}
+
+	return ts.channelCache.SaveTransform(ctx, channel.ID, tr)
+}
+
+// checkThingsQuota returns ErrQuotaExceeded if the owner can't have n more
+// things under its override.
+func (ts *thingsService) checkThingsQuota(ctx context.Context, owner string, n uint64) error {
+	max := ts.overrides.Override(owner).MaxThings
+	if max == 0 {
+		return nil
+	}
+
+	page, err := ts.things.RetrieveAll(ctx, owner, 0, 1, "", nil, TagFilter{}, StatusFilter{}, "")
+	if err != nil {
+		return err
+	}
+
+	if page.Total+n > max {
+		return ErrQuotaExceeded
+	}
+
+	return nil
+}
+
+// checkChannelsQuota returns ErrQuotaExceeded if the owner can't have n
+// more channels under its override.
+func (ts *thingsService) checkChannelsQuota(ctx context.Context, owner string, n uint64) error {
+	max := ts.overrides.Override(owner).MaxChannels
+	if max == 0 {
+		return nil
+	}
+
+	page, err := ts.channels.RetrieveAll(ctx, owner, 0, 1, "", nil, TagFilter{}, "")
+	if err != nil {
+		return err
+	}
+
+	if page.Total+n > max {
+		return ErrQuotaExceeded
+	}
+
+	return nil
+}
+
+// cacheTransform caches the thing's payload transformation, which is applied
+// by the adapters.
+func (ts *thingsService) cacheTransform(ctx context.Context, thing Thing) error {
+	tr, err := thing.Transform()
+	if err != nil && err != ErrMetadataNotFound {
+		return err
+	}
+
+	return ts.thingCache.SaveTransform(ctx, thing.ID, tr)
+}
+
+// channelState returns the state of the channel, caching the state found
+// in the repository.
+func (ts *thingsService) channelState(ctx context.Context, chanID string) (State, error) {
+	if state, err := ts.channelCache.State(ctx, chanID); err == nil {
+		return state, nil
+	}
+
+	state, err := ts.channels.RetrieveState(ctx, chanID)
+	if err != nil {
+		if err == ErrNotFound {
+			return "", ErrUnauthorizedAccess
+		}
+		return "", err
+	}
+
+	ts.channelCache.SaveState(ctx, chanID, state)
+	return state, nil
+}
+
+func (ts *thingsService) hasThing(ctx context.Context, chanID, key string) (string, error) {
+	thingID, err := ts.thingCache.ID(ctx, key)
+	if err != nil {
+		return "", err
+	}
+
+	if connected := ts.channelCache.HasThing(ctx, chanID, thingID); !connected {
+		return "", ErrUnauthorizedAccess
+	}
+
+	return thingID, nil
+}
+
+// hasScopedKey checks the scoped keys, which are not cached, so that their
+// revocation takes effect immediately. The action has to be allowed by both
+// the key scope and the connection access.
+func (ts *thingsService) hasScopedKey(ctx context.Context, chanID, key, action string) (string, error) {
+	tk, access, err := ts.thingKeys.HasThing(ctx, chanID, key)
+	if err != nil || !access.Allows(action) || !tk.Scope.Allows(action) {
+		return "", ErrUnauthorizedAccess
+	}
+
+	return tk.ThingID, nil
+}
+
+// hasChannelToken returns the identifier of the unexpired token of the
+// channel, having the provided value, if its scope allows the action.
+func (ts *thingsService) hasChannelToken(ctx context.Context, chanID, token, action string) (string, error) {
+	ct, err := ts.chanTokens.RetrieveByToken(ctx, chanID, token)
+	if err != nil || !ct.Scope.Allows(action) {
+		return "", ErrUnauthorizedAccess
+	}
+
+	return ct.ID, nil
+}
+
+// connections returns the identifiers of all the channels the thing is
+// connected to.
+func (ts *thingsService) connections(ctx context.Context, owner, id string) ([]string, error) {
+	channels := []string{}
+	for {
+		page, err := ts.channels.RetrieveByThing(ctx, owner, id, uint64(len(channels)), connectionsBatchSize)
+		if err != nil {
+			return []string{}, err
+		}
+
+		for _, channel := range page.Channels {
+			channels = append(channels, channel.ID)
+		}
+
+		if len(page.Channels) == 0 || uint64(len(channels)) >= page.Total {
+			break
+		}
+	}
+
+	return channels, nil
+}
+
+// keysVersion returns the version of the signed keys issued to the thing,
+// which is incremented by each revocation.
+func (ts *thingsService) keysVersion(ctx context.Context, id string) (uint64, error) {
+	r, err := ts.revocations.RetrieveByThing(ctx, id)
+	switch err {
+	case nil:
+		return r.Version, nil
+	case ErrNotFound:
+		return 0, nil
+	default:
+		return 0, err
+	}
+}
+
+func (ts *thingsService) revoke(ctx context.Context, id string) error {
+	version, err := ts.keysVersion(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	r := Revocation{
+		ThingID:   id,
+		Version:   version + 1,
+		ExpiresAt: time.Now().UTC().Add(maxKeyTTL),
+	}
+
+	return ts.revocations.Save(ctx, r)
+}
+
+// revokeAll revokes the signed keys of the things identified by the provided
+// IDs.
+func (ts *thingsService) revokeAll(ctx context.Context, ids []string) error {
+	for _, id := range ids {
+		if err := ts.revoke(ctx, id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// revokeConnected revokes the signed keys of the things connected to the
+// channel, which hold the connection to it.
+func (ts *thingsService) revokeConnected(ctx context.Context, owner, chanID string) error {
+	for offset := uint64(0); ; offset += removeBatchSize {
+		page, err := ts.things.RetrieveByChannel(ctx, owner, chanID, offset, removeBatchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, thing := range page.Things {
+			if err := ts.revoke(ctx, thing.ID); err != nil {
+				return err
+			}
+		}
+
+		if len(page.Things) < removeBatchSize {
+			return nil
+		}
+	}
+}
+
+// revoked returns true if the signed key is revoked. The key is considered
+// revoked if the revocation can't be retrieved.
+func (ts *thingsService) revoked(ctx context.Context, key SignedKey) bool {
+	r, err := ts.revocations.RetrieveByThing(ctx, key.ThingID)
+	switch err {
+	case nil:
+		return r.Revokes(key)
+	case ErrNotFound:
+		return false
+	default:
+		return true
+	}
+}
+
+// accessOwner returns the owner the entity is accessed on behalf of, i.e.
+// the owner that shared the entity with the user granting the required
+// permission, or the user itself otherwise. Owner-scoped retrieval of the
+// entity fails with ErrNotFound if the user has no access to it.
+func (ts *thingsService) accessOwner(ctx context.Context, user, kind, id string, perm Permission) (string, error) {
+	share, err := ts.shares.Retrieve(ctx, kind, id, user)
+	switch err {
+	case nil:
+		if share.Permission.Allows(perm) {
+			return share.Owner, nil
+		}
+		return user, nil
+	case ErrNotFound:
+		return user, nil
+	default:
+		return "", err
+	}
+}
+
+// pairConnections returns the connections of all the things to all the channels
+// the user manages. The pairs whose channel and thing belong to different
+// owners are reported by ConnectionError.
+func (ts *thingsService) pairConnections(ctx context.Context, user string, chanIDs, thingIDs []string) ([]Connection, error) {
+	chOwners, err := ts.accessOwners(ctx, user, ChannelKind, chanIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	thOwners, err := ts.accessOwners(ctx, user, ThingKind, thingIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	conns := []Connection{}
+	failures := []ConnectionFailure{}
+	for _, chanID := range chanIDs {
+		for _, thingID := range thingIDs {
+			owner := chOwners[chanID]
+			if owner != thOwners[thingID] {
+				failures = append(failures, ConnectionFailure{ChanID: chanID, ThingID: thingID, Err: ErrNotFound})
+				continue
+			}
+			conns = append(conns, Connection{ChanID: chanID, ThingID: thingID, Owner: owner})
+		}
+	}
+
+	if len(failures) > 0 {
+		return nil, &ConnectionError{Failures: failures}
+	}
+
+	return conns, nil
+}
+
+// accessOwners returns the owners of the entities of the given kind the user
+// manages, by their identifiers.
+func (ts *thingsService) accessOwners(ctx context.Context, user, kind string, ids []string) (map[string]string, error) {
+	owners := make(map[string]string, len(ids))
+	for _, id := range ids {
+		owner, err := ts.accessOwner(ctx, user, kind, id, ManagePermission)
+		if err != nil {
+			return nil, err
+		}
+		owners[id] = owner
+	}
+
+	return owners, nil
+}
+
+// validateConnections rejects the empty and the duplicated identifiers, as
+// well as connecting more than maxBulkConnections pairs at once.
+func validateConnections(chanIDs, thingIDs []string) error {
+	if len(chanIDs) == 0 || len(thingIDs) == 0 || len(chanIDs)*len(thingIDs) > maxBulkConnections {
+		return ErrMalformedEntity
+	}
+
+	for _, ids := range [][]string{chanIDs, thingIDs} {
+		seen := make(map[string]bool, len(ids))
+		for _, id := range ids {
+			if id == "" || seen[id] {
+				return ErrMalformedEntity
+			}
+			seen[id] = true
+		}
+	}
+
+	return nil
+}
+
+// sharedIDs returns the identifiers of the entities of the given kind shared
+// with the user.
+func (ts *thingsService) sharedIDs(ctx context.Context, user, kind string) ([]string, error) {
+	shares, err := ts.shares.RetrieveByUser(ctx, user, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(shares))
+	for i, share := range shares {
+		ids[i] = share.EntityID
+	}
+
+	return ids, nil
+}
+
+// retrieveEntity verifies that the entity of the given kind having the
+// provided identifier exists and is owned by the specified user.
+func (ts *thingsService) retrieveEntity(ctx context.Context, owner, kind, id string) error {
+	switch kind {
+	case ThingKind:
+		_, err := ts.things.RetrieveByID(ctx, owner, id)
+		return err
+	case ChannelKind:
+		_, err := ts.channels.RetrieveByID(ctx, owner, id)
+		return err
+	default:
+		return ErrMalformedEntity
+	}
+}
+
+// hideKey omits the key of the thing that isn't owned by the user, so that
+// the users the thing is shared with can't impersonate it.
+func hideKey(thing Thing, user string) Thing {
+	if thing.Owner != user {
+		thing.Key = ""
+	}
+
+	return thing
 }
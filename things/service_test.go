@@ -8,10 +8,13 @@
 package things_test
 
 import (
+	"context"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/things"
 	"github.com/mainflux/mainflux/things/mocks"
 	"github.com/stretchr/testify/assert"
@@ -23,11 +26,24 @@ const (
 	wrongValue = "wrong-value"
 	email      = "user@example.com"
 	token      = "token"
+	staleAfter = time.Minute
 )
 
 var (
 	thing   = things.Thing{Name: "test"}
 	channel = things.Channel{Name: "test"}
+	limits  = things.Limits{NameLength: 64, KeyLength: 64, MetadataSize: 256}
+
+	quotaEmail = "quota@example.com"
+	quotaToken = "quota-token"
+	overrides  = map[string]things.Override{
+		quotaEmail: {
+			RateLimit:   things.RateLimit{Rate: 5, Burst: 10},
+			MaxThings:   2,
+			MaxChannels: 1,
+			Protocols:   []string{"mqtt"},
+		},
+	}
 )
 
 func newService(tokens map[string]string) things.Service {
@@ -35,11 +51,27 @@ func newService(tokens map[string]string) things.Service {
 	conns := make(chan mocks.Connection)
 	thingsRepo := mocks.NewThingRepository(conns)
 	channelsRepo := mocks.NewChannelRepository(thingsRepo, conns)
+	reservationsRepo := mocks.NewReservationRepository()
+	usageRepo := mocks.NewUsageRepository()
+	historyRepo := mocks.NewHistoryRepository()
+	auditRepo := mocks.NewAuditRepository()
 	chanCache := mocks.NewChannelCache()
 	thingCache := mocks.NewThingCache()
 	idp := mocks.NewIdentityProvider()
+	onboarding := mocks.NewOnboardingProvider()
+	keys := mocks.NewKeyProvider()
+	revocations := mocks.NewRevocationRepository()
+	templates := mocks.NewTemplateRepository()
+	groups := mocks.NewGroupRepository(thingsRepo, channelsRepo)
+	subtopics := mocks.NewSubtopicRepository()
+	observations := mocks.NewObservationRepository()
+	thingKeys := mocks.NewThingKeyRepository(thingsRepo, channelsRepo)
+	shares := mocks.NewShareRepository()
+	profiles := mocks.NewProfileRepository()
+	rules := mocks.NewSubtopicRuleRepository()
+	health := mocks.NewHealthRepository()
 
-	return things.New(users, thingsRepo, channelsRepo, chanCache, thingCache, idp)
+	return things.New(users, thingsRepo, channelsRepo, reservationsRepo, usageRepo, historyRepo, auditRepo, chanCache, thingCache, idp, onboarding, keys, revocations, templates, groups, subtopics, observations, thingKeys, shares, profiles, rules, health, mocks.NewChannelTokenRepository(channelsRepo), mocks.NewOverrideProvider(overrides), limits, staleAfter)
 }
 
 func TestAddThing(t *testing.T) {
@@ -63,597 +95,581 @@ func TestAddThing(t *testing.T) {
 			token: wrongValue,
 			err:   things.ErrUnauthorizedAccess,
 		},
+		{
+			desc:  "add thing with valid adapter metadata",
+			thing: things.Thing{Name: "e", Metadata: map[string]interface{}{"lora": map[string]interface{}{"devEUI": "0123456789abcdef"}}},
+			token: token,
+			err:   nil,
+		},
+		{
+			desc:  "add thing with malformed adapter metadata",
+			thing: things.Thing{Name: "f", Metadata: map[string]interface{}{"lora": map[string]interface{}{"devEUI": "invalid"}}},
+			token: token,
+			err:   things.ErrMalformedEntity,
+		},
+		{
+			desc:  "add thing with too long name",
+			thing: things.Thing{Name: strings.Repeat("g", limits.NameLength+1)},
+			token: token,
+			err:   &things.ValidationError{Field: "name", Reason: fmt.Sprintf("exceeds %d characters", limits.NameLength)},
+		},
 	}
 
 	for _, tc := range cases {
-		_, err := svc.AddThing(tc.token, tc.thing)
+		_, err := svc.AddThing(context.Background(), tc.token, tc.thing)
 		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
 	}
 }
 
-func TestUpdateThing(t *testing.T) {
-	svc := newService(map[string]string{token: email})
-	saved, _ := svc.AddThing(token, thing)
-	other := things.Thing{ID: wrongID, Key: "x"}
+func TestAddThingWithExternalID(t *testing.T) {
+	svc := newService(map[string]string{token: email, "other": "other@example.com"})
+
+	sth, err := svc.AddThing(context.Background(), token, things.Thing{Name: "a", ExternalID: "serial-1"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
-		desc  string
-		thing things.Thing
-		token string
-		err   error
+		desc     string
+		thing    things.Thing
+		token    string
+		existing bool
+		err      error
 	}{
 		{
-			desc:  "update existing thing",
-			thing: saved,
-			token: token,
-			err:   nil,
+			desc:     "add thing with existing external ID",
+			thing:    things.Thing{Name: "b", ExternalID: "serial-1"},
+			token:    token,
+			existing: true,
+			err:      nil,
 		},
 		{
-			desc:  "update thing with wrong credentials",
-			thing: saved,
-			token: wrongValue,
-			err:   things.ErrUnauthorizedAccess,
+			desc:     "add thing with new external ID",
+			thing:    things.Thing{Name: "c", ExternalID: "serial-2"},
+			token:    token,
+			existing: false,
+			err:      nil,
 		},
 		{
-			desc:  "update non-existing thing",
-			thing: other,
+			desc:     "add thing with external ID of other user's thing",
+			thing:    things.Thing{Name: "d", ExternalID: "serial-1"},
+			token:    "other",
+			existing: false,
+			err:      nil,
+		},
+		{
+			desc:  "add thing with too long external ID",
+			thing: things.Thing{Name: "e", ExternalID: strings.Repeat("e", 255)},
 			token: token,
-			err:   things.ErrNotFound,
+			err:   &things.ValidationError{Field: "external_id", Reason: "exceeds 254 characters"},
 		},
 	}
 
 	for _, tc := range cases {
-		err := svc.UpdateThing(tc.token, tc.thing)
+		th, err := svc.AddThing(context.Background(), tc.token, tc.thing)
 		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err != nil {
+			continue
+		}
+
+		assert.Equal(t, tc.existing, th.ID == sth.ID, fmt.Sprintf("%s: expected existing thing %t got %t\n", tc.desc, tc.existing, th.ID == sth.ID))
+		assert.Equal(t, tc.thing.ExternalID, th.ExternalID, fmt.Sprintf("%s: expected external ID %s got %s\n", tc.desc, tc.thing.ExternalID, th.ExternalID))
 	}
+
+	top, err := svc.ProvisionThing(context.Background(), token, "", things.Thing{Name: "f", ExternalID: "serial-1"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.True(t, top.Existing, "expected existing thing to be provisioned")
+	assert.Equal(t, sth.ID, top.Thing.ID, fmt.Sprintf("expected thing %s got %s", sth.ID, top.Thing.ID))
+
+	_, err = svc.CreateThings(context.Background(), token, things.Thing{Name: "g", ExternalID: "serial-1"})
+	assert.Equal(t, things.ErrConflict, err, fmt.Sprintf("expected %s got %s", things.ErrConflict, err))
 }
 
-func TestUpdateKey(t *testing.T) {
-	key := "new-key"
+func TestCreateThings(t *testing.T) {
 	svc := newService(map[string]string{token: email})
-	saved, err := svc.AddThing(token, thing)
+	rs, err := svc.ReserveThings(context.Background(), token, 1)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
 
 	cases := []struct {
-		desc  string
-		token string
-		id    string
-		key   string
-		err   error
+		desc   string
+		things []things.Thing
+		token  string
+		err    error
 	}{
 		{
-			desc:  "update key of an existing thing",
-			token: token,
-			id:    saved.ID,
-			key:   key,
-			err:   nil,
+			desc:   "create new things",
+			things: []things.Thing{{Name: "a"}, {Name: "b"}},
+			token:  token,
+			err:    nil,
 		},
 		{
-			desc:  "update key with invalid credentials",
-			token: wrongValue,
-			id:    saved.ID,
-			key:   key,
-			err:   things.ErrUnauthorizedAccess,
+			desc:   "create things using reserved key",
+			things: []things.Thing{{Name: "c", Key: rs[0].Key}, {Name: "d"}},
+			token:  token,
+			err:    nil,
 		},
 		{
-			desc:  "update key of non-existing thing",
-			token: token,
-			id:    wrongID,
-			key:   wrongValue,
-			err:   things.ErrNotFound,
+			desc:   "create things with duplicate keys",
+			things: []things.Thing{{Name: "e", Key: "key"}, {Name: "f", Key: "key"}},
+			token:  token,
+			err:    things.ErrConflict,
+		},
+		{
+			desc:   "create things with wrong credentials",
+			things: []things.Thing{{Name: "g"}},
+			token:  wrongValue,
+			err:    things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:   "create things with malformed adapter metadata",
+			things: []things.Thing{{Name: "h"}, {Name: "i", Metadata: map[string]interface{}{"lora": map[string]interface{}{"devEUI": "invalid"}}}},
+			token:  token,
+			err:    things.ErrMalformedEntity,
+		},
+		{
+			desc:   "create empty list of things",
+			things: []things.Thing{},
+			token:  token,
+			err:    things.ErrMalformedEntity,
 		},
 	}
 
 	for _, tc := range cases {
-		err := svc.UpdateKey(tc.token, tc.id, tc.key)
+		saved, err := svc.CreateThings(context.Background(), tc.token, tc.things...)
 		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err != nil {
+			continue
+		}
+
+		assert.Equal(t, len(tc.things), len(saved), fmt.Sprintf("%s: expected %d things got %d\n", tc.desc, len(tc.things), len(saved)))
+		for i, th := range saved {
+			assert.NotEmpty(t, th.ID, fmt.Sprintf("%s: expected non-empty ID\n", tc.desc))
+			assert.NotEmpty(t, th.Key, fmt.Sprintf("%s: expected non-empty key\n", tc.desc))
+			assert.Equal(t, tc.things[i].Name, th.Name, fmt.Sprintf("%s: expected name %s got %s\n", tc.desc, tc.things[i].Name, th.Name))
+
+			id, err := svc.Identify(context.Background(), th.Key)
+			assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", tc.desc, err))
+			assert.Equal(t, th.ID, id, fmt.Sprintf("%s: expected thing %s got %s\n", tc.desc, th.ID, id))
+		}
 	}
 }
 
-func TestViewThing(t *testing.T) {
+func TestReserveThings(t *testing.T) {
 	svc := newService(map[string]string{token: email})
-	saved, _ := svc.AddThing(token, thing)
 
-	cases := map[string]struct {
-		id    string
+	cases := []struct {
+		desc  string
+		count uint64
 		token string
+		size  int
 		err   error
 	}{
-		"view existing thing": {
-			id:    saved.ID,
+		{
+			desc:  "reserve things",
+			count: 5,
 			token: token,
+			size:  5,
 			err:   nil,
 		},
-		"view thing with wrong credentials": {
-			id:    saved.ID,
+		{
+			desc:  "reserve things with wrong credentials",
+			count: 5,
 			token: wrongValue,
+			size:  0,
 			err:   things.ErrUnauthorizedAccess,
 		},
-		"view non-existing thing": {
-			id:    wrongID,
-			token: token,
-			err:   things.ErrNotFound,
-		},
 	}
 
-	for desc, tc := range cases {
-		_, err := svc.ViewThing(tc.token, tc.id)
-		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	for _, tc := range cases {
+		reservations, err := svc.ReserveThings(context.Background(), tc.token, tc.count)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.size, len(reservations), fmt.Sprintf("%s: expected %d got %d\n", tc.desc, tc.size, len(reservations)))
 	}
 }
 
-func TestListThings(t *testing.T) {
+func TestAddReservedThing(t *testing.T) {
 	svc := newService(map[string]string{token: email})
+	reservations, err := svc.ReserveThings(context.Background(), token, 1)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	key := reservations[0].Key
 
-	n := uint64(10)
-	for i := uint64(0); i < n; i++ {
-		svc.AddThing(token, thing)
-	}
-
-	cases := map[string]struct {
-		token  string
-		offset uint64
-		limit  uint64
-		name   string
-		size   uint64
-		err    error
+	cases := []struct {
+		desc  string
+		thing things.Thing
+		err   error
 	}{
-		"list all things": {
-			token:  token,
-			offset: 0,
-			limit:  n,
-			size:   n,
-			err:    nil,
-		},
-		"list half": {
-			token:  token,
-			offset: n / 2,
-			limit:  n,
-			size:   n / 2,
-			err:    nil,
-		},
-		"list last thing": {
-			token:  token,
-			offset: n - 1,
-			limit:  n,
-			size:   1,
-			err:    nil,
-		},
-		"list empty set": {
-			token:  token,
-			offset: n + 1,
-			limit:  n,
-			size:   0,
-			err:    nil,
-		},
-		"list with zero limit": {
-			token:  token,
-			offset: 1,
-			limit:  0,
-			size:   0,
-			err:    nil,
+		{
+			desc:  "add thing with reserved key",
+			thing: things.Thing{Name: "a", Key: key},
+			err:   nil,
 		},
-		"list with wrong credentials": {
-			token:  wrongValue,
-			offset: 0,
-			limit:  0,
-			size:   0,
-			err:    things.ErrUnauthorizedAccess,
+		{
+			desc:  "add thing with already bound reserved key",
+			thing: things.Thing{Name: "b", Key: key},
+			err:   things.ErrConflict,
 		},
 	}
 
-	for desc, tc := range cases {
-		page, err := svc.ListThings(tc.token, tc.offset, tc.limit, tc.name)
-		size := uint64(len(page.Things))
-		assert.Equal(t, tc.size, size, fmt.Sprintf("%s: expected %d got %d\n", desc, tc.size, size))
-		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	for _, tc := range cases {
+		_, err := svc.AddThing(context.Background(), token, tc.thing)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
 	}
 }
 
-func TestListThingsByChannel(t *testing.T) {
-	svc := newService(map[string]string{token: email})
+func TestProvisionThing(t *testing.T) {
+	svc := newService(map[string]string{token: email, "other": "other@example.com"})
 
-	sch, err := svc.CreateChannel(token, channel)
-	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
-	n := uint64(10)
-	for i := uint64(0); i < n; i++ {
-		sth, err := svc.AddThing(token, thing)
-		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
-		svc.Connect(token, sch.ID, sth.ID)
+	tmpl := things.Template{
+		Channels: []things.ChannelTemplate{
+			{Name: "{name}-data"},
+			{Name: "{name}-control", Metadata: map[string]interface{}{"role": "control"}},
+		},
 	}
+	err := svc.SaveTemplate(context.Background(), token, tmpl)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
-	// Wait for things and channels to connect
-	time.Sleep(time.Second)
+	sch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	profile := things.Profile{
+		Name:      "sensor",
+		Metadata:  map[string]interface{}{"model": "a", "rev": "1"},
+		Channels:  []string{sch.ID},
+		Bootstrap: "config",
+	}
+	profile, err = svc.CreateProfile(context.Background(), token, profile)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
-	cases := map[string]struct {
-		token   string
-		channel string
-		offset  uint64
-		limit   uint64
-		size    uint64
-		err     error
+	cases := []struct {
+		desc      string
+		thing     things.Thing
+		token     string
+		profileID string
+		channels  []string
+		connected int
+		err       error
 	}{
-		"list all things by existing channel": {
-			token:   token,
-			channel: sch.ID,
-			offset:  0,
-			limit:   n,
-			size:    n,
-			err:     nil,
+		{
+			desc:      "provision thing using template and profile",
+			thing:     things.Thing{Name: "sensor", Metadata: map[string]interface{}{"rev": "2"}},
+			token:     token,
+			profileID: profile.ID,
+			channels:  []string{"sensor-data", "sensor-control"},
+			connected: 1,
+			err:       nil,
 		},
-		"list half of things by existing channel": {
-			token:   token,
-			channel: sch.ID,
-			offset:  n / 2,
-			limit:   n,
-			size:    n / 2,
-			err:     nil,
+		{
+			desc:      "provision thing using non-existing profile",
+			thing:     things.Thing{Name: "sensor"},
+			token:     token,
+			profileID: "non-existing",
+			channels:  nil,
+			err:       things.ErrNotFound,
 		},
-		"list last thing by existing channel": {
-			token:   token,
-			channel: sch.ID,
-			offset:  n - 1,
-			limit:   n,
-			size:    1,
-			err:     nil,
+		{
+			desc:      "provision thing using profile of other user",
+			thing:     things.Thing{Name: "sensor"},
+			token:     "other",
+			profileID: profile.ID,
+			channels:  nil,
+			err:       things.ErrNotFound,
 		},
-		"list empty set of things by existing channel": {
-			token:   token,
-			channel: sch.ID,
-			offset:  n + 1,
-			limit:   n,
-			size:    0,
-			err:     nil,
+		{
+			desc:     "provision thing using template",
+			thing:    things.Thing{Name: "sensor"},
+			token:    token,
+			channels: []string{"sensor-data", "sensor-control"},
+			err:      nil,
 		},
-		"list things by existing channel with zero limit": {
-			token:   token,
-			channel: sch.ID,
-			offset:  1,
-			limit:   0,
-			size:    0,
-			err:     nil,
+		{
+			desc:     "provision thing without template",
+			thing:    things.Thing{Name: "sensor"},
+			token:    "other",
+			channels: []string{},
+			err:      nil,
 		},
-		"list things by existing channel with wrong credentials": {
-			token:   wrongValue,
-			channel: sch.ID,
-			offset:  0,
-			limit:   0,
-			size:    0,
-			err:     things.ErrUnauthorizedAccess,
+		{
+			desc:     "provision thing with wrong credentials",
+			thing:    things.Thing{Name: "sensor"},
+			token:    wrongValue,
+			channels: nil,
+			err:      things.ErrUnauthorizedAccess,
 		},
-		"list things by non-existent channel with wrong credentials": {
-			token:   token,
-			channel: "non-existent",
-			offset:  0,
-			limit:   10,
-			size:    0,
-			err:     nil,
+		{
+			desc:     "provision thing with malformed adapter metadata",
+			thing:    things.Thing{Name: "sensor", Metadata: map[string]interface{}{"lora": map[string]interface{}{"devEUI": "invalid"}}},
+			token:    token,
+			channels: nil,
+			err:      things.ErrMalformedEntity,
 		},
 	}
 
-	for desc, tc := range cases {
-		page, err := svc.ListThingsByChannel(tc.token, tc.channel, tc.offset, tc.limit)
-		size := uint64(len(page.Things))
-		assert.Equal(t, tc.size, size, fmt.Sprintf("%s: expected %d got %d\n", desc, tc.size, size))
-		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	for _, tc := range cases {
+		top, err := svc.ProvisionThing(context.Background(), tc.token, tc.profileID, tc.thing)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err != nil {
+			continue
+		}
+
+		names := []string{}
+		for _, channel := range top.Channels {
+			names = append(names, channel.Name)
+		}
+		assert.Equal(t, tc.channels, names, fmt.Sprintf("%s: expected channels %v got %v\n", tc.desc, tc.channels, names))
+		assert.Len(t, top.Connected, tc.connected, fmt.Sprintf("%s: expected %d profile channels got %d\n", tc.desc, tc.connected, len(top.Connected)))
+
+		page, err := svc.ListChannelsByThing(context.Background(), tc.token, top.Thing.ID, 0, 10)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		total := len(tc.channels) + tc.connected
+		assert.Len(t, page.Channels, total, fmt.Sprintf("%s: expected %d connections got %d\n", tc.desc, total, len(page.Channels)))
+
+		if tc.profileID != "" {
+			expected := map[string]interface{}{"model": "a", "rev": "2"}
+			assert.Equal(t, expected, top.Thing.Metadata, fmt.Sprintf("%s: expected metadata %v got %v\n", tc.desc, expected, top.Thing.Metadata))
+			assert.Equal(t, profile.Bootstrap, top.Bootstrap, fmt.Sprintf("%s: expected bootstrap %s got %s\n", tc.desc, profile.Bootstrap, top.Bootstrap))
+		}
 	}
 }
 
-func TestRemoveThing(t *testing.T) {
+func TestSaveTemplate(t *testing.T) {
 	svc := newService(map[string]string{token: email})
-	saved, _ := svc.AddThing(token, thing)
+
+	tmpl := things.Template{Channels: []things.ChannelTemplate{{Name: "{id}"}}}
+	tooLarge := things.Template{}
+	for i := 0; i < 11; i++ {
+		tooLarge.Channels = append(tooLarge.Channels, things.ChannelTemplate{Name: fmt.Sprintf("%d", i)})
+	}
 
 	cases := []struct {
 		desc  string
-		id    string
+		tmpl  things.Template
 		token string
 		err   error
 	}{
 		{
-			desc:  "remove thing with wrong credentials",
-			id:    saved.ID,
+			desc:  "save template",
+			tmpl:  tmpl,
+			token: token,
+			err:   nil,
+		},
+		{
+			desc:  "save template with wrong credentials",
+			tmpl:  tmpl,
 			token: wrongValue,
 			err:   things.ErrUnauthorizedAccess,
 		},
 		{
-			desc:  "remove existing thing",
-			id:    saved.ID,
+			desc:  "save template without channels",
+			tmpl:  things.Template{},
 			token: token,
-			err:   nil,
+			err:   things.ErrMalformedEntity,
 		},
 		{
-			desc:  "remove removed thing",
-			id:    saved.ID,
+			desc:  "save template with too many channels",
+			tmpl:  tooLarge,
 			token: token,
-			err:   nil,
+			err:   things.ErrMalformedEntity,
 		},
 		{
-			desc:  "remove non-existing thing",
-			id:    wrongID,
+			desc: "save template with malformed adapter metadata",
+			tmpl: things.Template{Channels: []things.ChannelTemplate{
+				{Metadata: map[string]interface{}{"lora": map[string]interface{}{"appID": 1}}},
+			}},
 			token: token,
-			err:   nil,
+			err:   things.ErrMalformedEntity,
 		},
 	}
 
 	for _, tc := range cases {
-		err := svc.RemoveThing(tc.token, tc.id)
+		err := svc.SaveTemplate(context.Background(), tc.token, tc.tmpl)
 		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
 	}
+
+	saved, err := svc.ViewTemplate(context.Background(), token)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, tmpl.Channels, saved.Channels, fmt.Sprintf("expected %v got %v\n", tmpl.Channels, saved.Channels))
 }
 
-func TestCreateChannel(t *testing.T) {
+func TestRemoveTemplate(t *testing.T) {
 	svc := newService(map[string]string{token: email})
 
+	err := svc.SaveTemplate(context.Background(), token, things.Template{Channels: []things.ChannelTemplate{{Name: "{id}"}}})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
 	cases := []struct {
-		desc    string
-		channel things.Channel
-		token   string
-		err     error
+		desc  string
+		token string
+		err   error
 	}{
 		{
-			desc:    "create channel",
-			channel: channel,
-			token:   token,
-			err:     nil,
+			desc:  "remove template with wrong credentials",
+			token: wrongValue,
+			err:   things.ErrUnauthorizedAccess,
 		},
 		{
-			desc:    "create channel with wrong credentials",
-			channel: channel,
-			token:   wrongValue,
-			err:     things.ErrUnauthorizedAccess,
+			desc:  "remove template",
+			token: token,
+			err:   nil,
+		},
+		{
+			desc:  "remove removed template",
+			token: token,
+			err:   nil,
 		},
 	}
 
 	for _, tc := range cases {
-		_, err := svc.CreateChannel(tc.token, tc.channel)
+		err := svc.RemoveTemplate(context.Background(), tc.token)
 		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
 	}
+
+	_, err = svc.ViewTemplate(context.Background(), token)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("expected %s got %s\n", things.ErrNotFound, err))
 }
 
-func TestUpdateChannel(t *testing.T) {
-	svc := newService(map[string]string{token: email})
-	saved, _ := svc.CreateChannel(token, channel)
-	other := things.Channel{ID: wrongID}
+func TestCreateProfile(t *testing.T) {
+	svc := newService(map[string]string{token: email, "other": "other@example.com"})
+
+	sch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
 		desc    string
-		channel things.Channel
+		profile things.Profile
 		token   string
 		err     error
 	}{
 		{
-			desc:    "update existing channel",
-			channel: saved,
+			desc:    "create valid profile",
+			profile: things.Profile{Name: "sensor", Channels: []string{sch.ID}, Bootstrap: "config"},
 			token:   token,
 			err:     nil,
 		},
 		{
-			desc:    "update channel with wrong credentials",
-			channel: saved,
+			desc:    "create profile with wrong credentials",
+			profile: things.Profile{Name: "sensor"},
 			token:   wrongValue,
 			err:     things.ErrUnauthorizedAccess,
 		},
 		{
-			desc:    "update non-existing channel",
-			channel: other,
+			desc:    "create profile with repeated channels",
+			profile: things.Profile{Channels: []string{sch.ID, sch.ID}},
+			token:   token,
+			err:     things.ErrMalformedEntity,
+		},
+		{
+			desc:    "create profile with non-existing channel",
+			profile: things.Profile{Channels: []string{"non-existing"}},
 			token:   token,
 			err:     things.ErrNotFound,
 		},
+		{
+			desc:    "create profile with channel of other user",
+			profile: things.Profile{Channels: []string{sch.ID}},
+			token:   "other",
+			err:     things.ErrNotFound,
+		},
 	}
 
 	for _, tc := range cases {
-		err := svc.UpdateChannel(tc.token, tc.channel)
+		_, err := svc.CreateProfile(context.Background(), tc.token, tc.profile)
 		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
 	}
 }
 
-func TestViewChannel(t *testing.T) {
+func TestUpdateProfile(t *testing.T) {
 	svc := newService(map[string]string{token: email})
-	saved, _ := svc.CreateChannel(token, channel)
-
-	cases := map[string]struct {
-		id    string
-		token string
-		err   error
-	}{
-		"view existing channel": {
-			id:    saved.ID,
-			token: token,
-			err:   nil,
-		},
-		"view channel with wrong credentials": {
-			id:    saved.ID,
-			token: wrongValue,
-			err:   things.ErrUnauthorizedAccess,
-		},
-		"view non-existing channel": {
-			id:    wrongID,
-			token: token,
-			err:   things.ErrNotFound,
-		},
-	}
-
-	for desc, tc := range cases {
-		_, err := svc.ViewChannel(tc.token, tc.id)
-		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
-	}
-}
 
-func TestListChannels(t *testing.T) {
-	svc := newService(map[string]string{token: email})
+	saved, err := svc.CreateProfile(context.Background(), token, things.Profile{Name: "sensor"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
-	n := uint64(10)
-	for i := uint64(0); i < n; i++ {
-		svc.CreateChannel(token, channel)
-	}
-	cases := map[string]struct {
-		token  string
-		offset uint64
-		limit  uint64
-		size   uint64
-		name   string
-		err    error
+	cases := []struct {
+		desc    string
+		profile things.Profile
+		token   string
+		err     error
 	}{
-		"list all channels": {
-			token:  token,
-			offset: 0,
-			limit:  n,
-			size:   n,
-			err:    nil,
-		},
-		"list half": {
-			token:  token,
-			offset: n / 2,
-			limit:  n,
-			size:   n / 2,
-			err:    nil,
-		},
-		"list last channel": {
-			token:  token,
-			offset: n - 1,
-			limit:  n,
-			size:   1,
-			err:    nil,
-		},
-		"list empty set": {
-			token:  token,
-			offset: n + 1,
-			limit:  n,
-			size:   0,
-			err:    nil,
-		},
-		"list with zero limit": {
-			token:  token,
-			offset: 1,
-			limit:  0,
-			size:   0,
-			err:    nil,
-		},
-		"list with wrong credentials": {
-			token:  wrongValue,
-			offset: 0,
-			limit:  0,
-			size:   0,
-			err:    things.ErrUnauthorizedAccess,
+		{
+			desc:    "update existing profile",
+			profile: things.Profile{ID: saved.ID, Name: "updated", Bootstrap: "config"},
+			token:   token,
+			err:     nil,
 		},
-		"list with existing name": {
-			token:  token,
-			offset: 0,
-			limit:  n,
-			size:   n,
-			name:   "chanel_name",
-			err:    nil,
+		{
+			desc:    "update profile with wrong credentials",
+			profile: things.Profile{ID: saved.ID},
+			token:   wrongValue,
+			err:     things.ErrUnauthorizedAccess,
 		},
-		"list with non-existent name": {
-			token:  token,
-			offset: 0,
-			limit:  n,
-			size:   n,
-			name:   "wrong",
-			err:    nil,
+		{
+			desc:    "update non-existing profile",
+			profile: things.Profile{ID: "non-existing"},
+			token:   token,
+			err:     things.ErrNotFound,
 		},
 	}
 
-	for desc, tc := range cases {
-		page, err := svc.ListChannels(tc.token, tc.offset, tc.limit, tc.name)
-		size := uint64(len(page.Channels))
-		assert.Equal(t, tc.size, size, fmt.Sprintf("%s: expected %d got %d\n", desc, tc.size, size))
-		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	for _, tc := range cases {
+		err := svc.UpdateProfile(context.Background(), tc.token, tc.profile)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
 	}
+
+	profile, err := svc.ViewProfile(context.Background(), token, saved.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, "updated", profile.Name, fmt.Sprintf("expected name %s got %s\n", "updated", profile.Name))
 }
 
-func TestListChannelsByThing(t *testing.T) {
+func TestListProfiles(t *testing.T) {
 	svc := newService(map[string]string{token: email})
 
-	sth, err := svc.AddThing(token, thing)
-	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 	n := uint64(10)
 	for i := uint64(0); i < n; i++ {
-		sch, err := svc.CreateChannel(token, channel)
+		_, err := svc.CreateProfile(context.Background(), token, things.Profile{Name: fmt.Sprintf("profile-%d", i)})
 		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
-		svc.Connect(token, sch.ID, sth.ID)
 	}
 
-	// Wait for things and channels to connect.
-	time.Sleep(time.Second)
-
-	cases := map[string]struct {
+	cases := []struct {
+		desc   string
 		token  string
-		thing  string
 		offset uint64
 		limit  uint64
 		size   uint64
 		err    error
 	}{
-		"list all channels by existing thing": {
-			token:  token,
-			thing:  sth.ID,
-			offset: 0,
-			limit:  n,
-			size:   n,
-			err:    nil,
-		},
-		"list half of channels by existing thing": {
-			token:  token,
-			thing:  sth.ID,
-			offset: n / 2,
-			limit:  n,
-			size:   n / 2,
-			err:    nil,
+		{
+			desc:  "list all profiles",
+			token: token,
+			limit: n,
+			size:  n,
+			err:   nil,
 		},
-		"list last channel by existing thing": {
+		{
+			desc:   "list last profile",
 			token:  token,
-			thing:  sth.ID,
 			offset: n - 1,
 			limit:  n,
 			size:   1,
 			err:    nil,
 		},
-		"list empty set of channels by existing thing": {
-			token:  token,
-			thing:  sth.ID,
-			offset: n + 1,
-			limit:  n,
-			size:   0,
-			err:    nil,
-		},
-		"list channels by existing thing with zero limit": {
-			token:  token,
-			thing:  sth.ID,
-			offset: 1,
-			limit:  0,
-			size:   0,
-			err:    nil,
-		},
-		"list channels by existing thing with wrong credentials": {
-			token:  wrongValue,
-			thing:  sth.ID,
-			offset: 0,
-			limit:  0,
-			size:   0,
-			err:    things.ErrUnauthorizedAccess,
-		},
-		"list channels by non-existent thing": {
-			token:  token,
-			thing:  "non-existent",
-			offset: 0,
-			limit:  10,
-			size:   0,
-			err:    nil,
+		{
+			desc:  "list profiles with wrong credentials",
+			token: wrongValue,
+			limit: n,
+			size:  0,
+			err:   things.ErrUnauthorizedAccess,
 		},
 	}
 
-	for desc, tc := range cases {
-		page, err := svc.ListChannelsByThing(tc.token, tc.thing, tc.offset, tc.limit)
-		size := uint64(len(page.Channels))
-		assert.Equal(t, tc.size, size, fmt.Sprintf("%s: expected %d got %d\n", desc, tc.size, size))
-		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	for _, tc := range cases {
+		page, err := svc.ListProfiles(context.Background(), tc.token, tc.offset, tc.limit)
+		size := uint64(len(page.Profiles))
+		assert.Equal(t, tc.size, size, fmt.Sprintf("%s: expected %d got %d\n", tc.desc, tc.size, size))
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
 	}
 }
 
-func TestRemoveChannel(t *testing.T) {
+func TestRemoveProfile(t *testing.T) {
 	svc := newService(map[string]string{token: email})
-	saved, _ := svc.CreateChannel(token, channel)
+
+	saved, err := svc.CreateProfile(context.Background(), token, things.Profile{Name: "sensor"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
 		desc  string
@@ -662,204 +678,4823 @@ func TestRemoveChannel(t *testing.T) {
 		err   error
 	}{
 		{
-			desc:  "remove channel with wrong credentials",
+			desc:  "remove profile with wrong credentials",
 			id:    saved.ID,
 			token: wrongValue,
 			err:   things.ErrUnauthorizedAccess,
 		},
 		{
-			desc:  "remove existing channel",
+			desc:  "remove existing profile",
 			id:    saved.ID,
 			token: token,
 			err:   nil,
 		},
 		{
-			desc:  "remove removed channel",
+			desc:  "remove removed profile",
 			id:    saved.ID,
 			token: token,
 			err:   nil,
 		},
-		{
-			desc:  "remove non-existing channel",
-			id:    saved.ID,
-			token: token,
-			err:   nil,
+	}
+
+	for _, tc := range cases {
+		err := svc.RemoveProfile(context.Background(), tc.token, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	_, err = svc.ViewProfile(context.Background(), token, saved.ID)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("expected %s got %s\n", things.ErrNotFound, err))
+}
+
+func TestUpdateThing(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	saved, _ := svc.AddThing(context.Background(), token, thing)
+	other := things.Thing{ID: wrongID, Key: "x"}
+
+	cases := []struct {
+		desc  string
+		thing things.Thing
+		token string
+		err   error
+	}{
+		{
+			desc:  "update existing thing",
+			thing: saved,
+			token: token,
+			err:   nil,
+		},
+		{
+			desc:  "update thing with wrong credentials",
+			thing: saved,
+			token: wrongValue,
+			err:   things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "update non-existing thing",
+			thing: other,
+			token: token,
+			err:   things.ErrNotFound,
 		},
 	}
 
 	for _, tc := range cases {
-		err := svc.RemoveChannel(tc.token, tc.id)
+		err := svc.UpdateThing(context.Background(), tc.token, tc.thing)
 		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
 	}
 }
 
-func TestConnect(t *testing.T) {
+func TestPatchThing(t *testing.T) {
 	svc := newService(map[string]string{token: email})
+	saved, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc  string
+		token string
+		id    string
+		patch map[string]interface{}
+		name  string
+		err   error
+	}{
+		{
+			desc:  "patch name of an existing thing",
+			token: token,
+			id:    saved.ID,
+			patch: map[string]interface{}{"name": "patched"},
+			name:  "patched",
+			err:   nil,
+		},
+		{
+			desc:  "patch read-only field of an existing thing",
+			token: token,
+			id:    saved.ID,
+			patch: map[string]interface{}{"id": "new"},
+			name:  "",
+			err:   things.ErrMalformedEntity,
+		},
+		{
+			desc:  "patch thing with malformed adapter metadata",
+			token: token,
+			id:    saved.ID,
+			patch: map[string]interface{}{"metadata": map[string]interface{}{"modbus": map[string]interface{}{"unitID": 0}}},
+			name:  "",
+			err:   things.ErrMalformedEntity,
+		},
+		{
+			desc:  "patch thing with invalid credentials",
+			token: wrongValue,
+			id:    saved.ID,
+			patch: map[string]interface{}{"name": "patched"},
+			name:  "",
+			err:   things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "patch non-existing thing",
+			token: token,
+			id:    wrongID,
+			patch: map[string]interface{}{"name": "patched"},
+			name:  "",
+			err:   things.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		res, err := svc.PatchThing(context.Background(), tc.token, tc.id, tc.patch)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.name, res.Name, fmt.Sprintf("%s: expected name %s got %s\n", tc.desc, tc.name, res.Name))
+	}
+}
 
-	sth, _ := svc.AddThing(token, thing)
-	sch, _ := svc.CreateChannel(token, channel)
+func TestUpdateKey(t *testing.T) {
+	key := "new-key"
+	svc := newService(map[string]string{token: email})
+	saved, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
 
 	cases := []struct {
-		desc    string
-		token   string
-		chanID  string
-		thingID string
-		err     error
+		desc      string
+		token     string
+		id        string
+		key       string
+		expiresAt time.Time
+		rotation  time.Duration
+		err       error
 	}{
 		{
-			desc:    "connect thing",
-			token:   token,
-			chanID:  sch.ID,
-			thingID: sth.ID,
-			err:     nil,
+			desc:  "update key of an existing thing",
+			token: token,
+			id:    saved.ID,
+			key:   key,
+			err:   nil,
 		},
 		{
-			desc:    "connect thing with wrong credentials",
-			token:   wrongValue,
-			chanID:  sch.ID,
-			thingID: sth.ID,
-			err:     things.ErrUnauthorizedAccess,
+			desc:      "update key with expiration time",
+			token:     token,
+			id:        saved.ID,
+			key:       "expiring-key",
+			expiresAt: time.Now().Add(time.Hour),
+			err:       nil,
 		},
 		{
-			desc:    "connect thing to non-existing channel",
-			token:   token,
-			chanID:  wrongID,
-			thingID: sth.ID,
-			err:     things.ErrNotFound,
+			desc:      "update key with rotation period",
+			token:     token,
+			id:        saved.ID,
+			key:       "rotated-key",
+			expiresAt: time.Now().Add(time.Hour),
+			rotation:  24 * time.Hour,
+			err:       nil,
 		},
 		{
-			desc:    "connect non-existing thing to channel",
-			token:   token,
-			chanID:  sch.ID,
-			thingID: wrongID,
-			err:     things.ErrNotFound,
+			desc:      "update key with expiration time in the past",
+			token:     token,
+			id:        saved.ID,
+			key:       "expired-key",
+			expiresAt: time.Now().Add(-time.Hour),
+			err:       things.ErrMalformedEntity,
+		},
+		{
+			desc:     "update key with too short rotation period",
+			token:    token,
+			id:       saved.ID,
+			key:      "rotated-key",
+			rotation: time.Minute,
+			err:      things.ErrMalformedEntity,
+		},
+		{
+			desc:  "update key with invalid credentials",
+			token: wrongValue,
+			id:    saved.ID,
+			key:   key,
+			err:   things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "update key of non-existing thing",
+			token: token,
+			id:    wrongID,
+			key:   wrongValue,
+			err:   things.ErrNotFound,
+		},
+		{
+			desc:  "update key with too long key",
+			token: token,
+			id:    saved.ID,
+			key:   strings.Repeat("k", limits.KeyLength+1),
+			err:   &things.ValidationError{Field: "key", Reason: fmt.Sprintf("exceeds %d characters", limits.KeyLength)},
 		},
 	}
 
 	for _, tc := range cases {
-		err := svc.Connect(tc.token, tc.chanID, tc.thingID)
+		err := svc.UpdateKey(context.Background(), tc.token, tc.id, tc.key, tc.expiresAt, tc.rotation)
 		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
 	}
 }
 
-func TestDisconnect(t *testing.T) {
+func TestRotateKeys(t *testing.T) {
 	svc := newService(map[string]string{token: email})
 
-	sth, _ := svc.AddThing(token, thing)
-	sch, _ := svc.CreateChannel(token, channel)
-	svc.Connect(token, sch.ID, sth.ID)
+	sch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	expiring, err := svc.AddThing(context.Background(), token, things.Thing{Name: "expiring"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	rotated, err := svc.AddThing(context.Background(), token, things.Thing{Name: "rotated"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	err = svc.Connect(context.Background(), token, []string{sch.ID}, []string{expiring.ID}, things.FullAccess)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	err = svc.Connect(context.Background(), token, []string{sch.ID}, []string{rotated.ID}, things.FullAccess)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	since := time.Now()
+	expiresAt := since.Add(100 * time.Millisecond)
+	err = svc.UpdateKey(context.Background(), token, expiring.ID, "expiring-key", expiresAt, 0)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	err = svc.UpdateKey(context.Background(), token, rotated.ID, "rotated-key", expiresAt, time.Hour)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	for _, key := range []string{"expiring-key", "rotated-key"} {
+		_, err := svc.CanAccess(context.Background(), sch.ID, key, mainflux.ActionPublish, "")
+		require.Nil(t, err, fmt.Sprintf("access using %s before expiration: unexpected error %s\n", key, err))
+	}
+
+	time.Sleep(200 * time.Millisecond)
+
+	ths, err := svc.RotateKeys(context.Background(), since)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	require.Len(t, ths, 1, fmt.Sprintf("expected 1 rotated key got %d\n", len(ths)))
+	assert.Equal(t, rotated.ID, ths[0].ID, fmt.Sprintf("expected key of %s to be rotated got %s\n", rotated.ID, ths[0].ID))
+	assert.NotEqual(t, "rotated-key", ths[0].Key, "expected new key to be issued\n")
+	assert.True(t, ths[0].KeyExpiresAt.After(time.Now().Add(59*time.Minute)), fmt.Sprintf("expected new key to be valid for the rotation period, expires at %s\n", ths[0].KeyExpiresAt))
+
+	for _, key := range []string{"expiring-key", "rotated-key"} {
+		_, err := svc.CanAccess(context.Background(), sch.ID, key, mainflux.ActionPublish, "")
+		assert.Equal(t, things.ErrUnauthorizedAccess, err, fmt.Sprintf("access using expired %s: expected %s got %s\n", key, things.ErrUnauthorizedAccess, err))
+		_, err = svc.Identify(context.Background(), key)
+		assert.Equal(t, things.ErrUnauthorizedAccess, err, fmt.Sprintf("identify using expired %s: expected %s got %s\n", key, things.ErrUnauthorizedAccess, err))
+	}
+
+	id, err := svc.CanAccess(context.Background(), sch.ID, ths[0].Key, mainflux.ActionPublish, "")
+	assert.Nil(t, err, fmt.Sprintf("access using rotated key: unexpected error %s\n", err))
+	assert.Equal(t, rotated.ID, id, fmt.Sprintf("access using rotated key: expected %s got %s\n", rotated.ID, id))
+
+	ths, err = svc.RotateKeys(context.Background(), since)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.Len(t, ths, 0, fmt.Sprintf("expected no keys to be rotated again got %d\n", len(ths)))
+}
+
+func TestUpdateThingsMetadata(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	th := thing
+	th.Metadata = map[string]interface{}{"model": "sensor", "interval": 10}
+	matched, err := svc.AddThing(context.Background(), token, th)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	th.Metadata = map[string]interface{}{"model": "actuator"}
+	other, err := svc.AddThing(context.Background(), token, th)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
 
 	cases := []struct {
-		desc    string
-		token   string
-		chanID  string
-		thingID string
-		err     error
+		desc     string
+		token    string
+		selector map[string]interface{}
+		patch    map[string]interface{}
+		size     int
+		err      error
 	}{
 		{
-			desc:    "disconnect connected thing",
-			token:   token,
-			chanID:  sch.ID,
-			thingID: sth.ID,
-			err:     nil,
+			desc:     "update metadata of things matching selector",
+			token:    token,
+			selector: map[string]interface{}{"model": "sensor"},
+			patch:    map[string]interface{}{"interval": 60},
+			size:     1,
+			err:      nil,
 		},
 		{
-			desc:    "disconnect disconnected thing",
-			token:   token,
-			chanID:  sch.ID,
-			thingID: sth.ID,
-			err:     things.ErrNotFound,
+			desc:     "update metadata of things not matching selector",
+			token:    token,
+			selector: map[string]interface{}{"model": "gateway"},
+			patch:    map[string]interface{}{"interval": 60},
+			size:     0,
+			err:      nil,
 		},
 		{
-			desc:    "disconnect with wrong credentials",
-			token:   wrongValue,
-			chanID:  sch.ID,
-			thingID: sth.ID,
-			err:     things.ErrUnauthorizedAccess,
+			desc:     "update metadata with empty patch",
+			token:    token,
+			selector: map[string]interface{}{"model": "sensor"},
+			patch:    map[string]interface{}{},
+			size:     0,
+			err:      things.ErrMalformedEntity,
 		},
 		{
-			desc:    "disconnect from non-existing channel",
-			token:   token,
-			chanID:  wrongID,
-			thingID: sth.ID,
-			err:     things.ErrNotFound,
+			desc:     "update metadata with incomplete adapter metadata",
+			token:    token,
+			selector: map[string]interface{}{"model": "sensor"},
+			patch:    map[string]interface{}{"lora": map[string]interface{}{}},
+			size:     0,
+			err:      things.ErrMalformedEntity,
 		},
 		{
-			desc:    "disconnect non-existing thing",
-			token:   token,
-			chanID:  sch.ID,
-			thingID: wrongID,
-			err:     things.ErrNotFound,
+			desc:     "update metadata with invalid credentials",
+			token:    wrongValue,
+			selector: map[string]interface{}{"model": "sensor"},
+			patch:    map[string]interface{}{"interval": 60},
+			size:     0,
+			err:      things.ErrUnauthorizedAccess,
 		},
 	}
 
 	for _, tc := range cases {
-		err := svc.Disconnect(tc.token, tc.chanID, tc.thingID)
+		updated, err := svc.UpdateThingsMetadata(context.Background(), tc.token, tc.selector, tc.patch)
 		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.size, len(updated), fmt.Sprintf("%s: expected %d things got %d\n", tc.desc, tc.size, len(updated)))
 	}
 
+	res, err := svc.ViewThing(context.Background(), token, matched.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.Equal(t, 60, res.Metadata["interval"], fmt.Sprintf("expected patched interval 60 got %v\n", res.Metadata["interval"]))
+
+	res, err = svc.ViewThing(context.Background(), token, other.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.Nil(t, res.Metadata["interval"], fmt.Sprintf("expected unmatched thing to stay unchanged got %v\n", res.Metadata["interval"]))
 }
 
-func TestCanAccess(t *testing.T) {
+func TestViewThing(t *testing.T) {
 	svc := newService(map[string]string{token: email})
+	saved, _ := svc.AddThing(context.Background(), token, thing)
+
+	cases := map[string]struct {
+		id    string
+		token string
+		err   error
+	}{
+		"view existing thing": {
+			id:    saved.ID,
+			token: token,
+			err:   nil,
+		},
+		"view thing with wrong credentials": {
+			id:    saved.ID,
+			token: wrongValue,
+			err:   things.ErrUnauthorizedAccess,
+		},
+		"view non-existing thing": {
+			id:    wrongID,
+			token: token,
+			err:   things.ErrNotFound,
+		},
+	}
+
+	for desc, tc := range cases {
+		_, err := svc.ViewThing(context.Background(), tc.token, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
 
-	sth, _ := svc.AddThing(token, thing)
-	sch, _ := svc.CreateChannel(token, channel)
-	svc.Connect(token, sch.ID, sth.ID)
+func TestOnboardThing(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	sth, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	sch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	err = svc.Connect(context.Background(), token, []string{sch.ID}, []string{sth.ID}, things.FullAccess)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
 
 	cases := map[string]struct {
-		token   string
-		channel string
-		err     error
+		id       string
+		token    string
+		channels []string
+		err      error
 	}{
-		"allowed access": {
-			token:   sth.Key,
-			channel: sch.ID,
-			err:     nil,
+		"onboard existing thing": {
+			id:       sth.ID,
+			token:    token,
+			channels: []string{sch.ID},
+			err:      nil,
 		},
-		"not-connected cannot access": {
-			token:   wrongValue,
-			channel: sch.ID,
-			err:     things.ErrUnauthorizedAccess,
+		"onboard thing with wrong credentials": {
+			id:       sth.ID,
+			token:    wrongValue,
+			channels: nil,
+			err:      things.ErrUnauthorizedAccess,
 		},
-		"access to non-existing channel": {
-			token:   sth.Key,
-			channel: wrongID,
-			err:     things.ErrUnauthorizedAccess,
+		"onboard non-existing thing": {
+			id:       wrongID,
+			token:    token,
+			channels: nil,
+			err:      things.ErrNotFound,
 		},
 	}
 
 	for desc, tc := range cases {
-		_, err := svc.CanAccess(tc.channel, tc.token)
+		onboarding, _, err := svc.OnboardThing(context.Background(), tc.token, tc.id)
 		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+		assert.Equal(t, tc.channels, onboarding.Channels, fmt.Sprintf("%s: expected channels %v got %v\n", desc, tc.channels, onboarding.Channels))
 	}
 }
 
-func TestIdentify(t *testing.T) {
+func TestIssueKey(t *testing.T) {
 	svc := newService(map[string]string{token: email})
+	sth, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	sch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	err = svc.Connect(context.Background(), token, []string{sch.ID}, []string{sth.ID}, things.FullAccess)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := map[string]struct {
+		id       string
+		token    string
+		ttl      time.Duration
+		channels []string
+		err      error
+	}{
+		"issue key of existing thing": {
+			id:       sth.ID,
+			token:    token,
+			ttl:      time.Hour,
+			channels: []string{sch.ID},
+			err:      nil,
+		},
+		"issue key with too long ttl": {
+			id:       sth.ID,
+			token:    token,
+			ttl:      365 * 24 * time.Hour,
+			channels: nil,
+			err:      things.ErrMalformedEntity,
+		},
+		"issue key with wrong credentials": {
+			id:       sth.ID,
+			token:    wrongValue,
+			ttl:      time.Hour,
+			channels: nil,
+			err:      things.ErrUnauthorizedAccess,
+		},
+		"issue key of non-existing thing": {
+			id:       wrongID,
+			token:    token,
+			ttl:      time.Hour,
+			channels: nil,
+			err:      things.ErrNotFound,
+		},
+	}
+
+	for desc, tc := range cases {
+		key, signed, err := svc.IssueKey(context.Background(), tc.token, tc.id, tc.ttl)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+		assert.Equal(t, tc.channels, key.Channels, fmt.Sprintf("%s: expected channels %v got %v\n", desc, tc.channels, key.Channels))
+		if err != nil {
+			continue
+		}
+
+		id, err := svc.CanAccess(context.Background(), sch.ID, signed, mainflux.ActionPublish, "")
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", desc, err))
+		assert.Equal(t, sth.ID, id, fmt.Sprintf("%s: expected thing %s got %s\n", desc, sth.ID, id))
+	}
+}
 
-	sth, _ := svc.AddThing(token, thing)
+func TestOpenSession(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	sth, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	sch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	err = svc.Connect(context.Background(), token, []string{sch.ID}, []string{sth.ID}, things.FullAccess)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	_, signed, err := svc.IssueKey(context.Background(), token, sth.ID, time.Hour)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
 
 	cases := map[string]struct {
-		token string
-		id    string
-		err   error
+		key      string
+		ttl      time.Duration
+		channels []string
+		err      error
 	}{
-		"identify existing thing": {
-			token: sth.Key,
-			id:    sth.ID,
-			err:   nil,
+		"open session using plain key": {
+			key:      sth.Key,
+			ttl:      time.Hour,
+			channels: []string{sch.ID},
+			err:      nil,
 		},
-		"identify non-existing thing": {
-			token: wrongValue,
-			id:    wrongID,
-			err:   things.ErrUnauthorizedAccess,
+		"open session with too long ttl": {
+			key:      sth.Key,
+			ttl:      48 * time.Hour,
+			channels: nil,
+			err:      things.ErrMalformedEntity,
+		},
+		"open session using signed key": {
+			key:      signed,
+			ttl:      time.Hour,
+			channels: nil,
+			err:      things.ErrUnauthorizedAccess,
+		},
+		"open session using wrong key": {
+			key:      wrongValue,
+			ttl:      time.Hour,
+			channels: nil,
+			err:      things.ErrUnauthorizedAccess,
 		},
 	}
 
 	for desc, tc := range cases {
-		id, err := svc.Identify(tc.token)
-		assert.Equal(t, tc.id, id, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.id, id))
+		session, signed, err := svc.OpenSession(context.Background(), tc.key, tc.ttl)
 		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+		assert.Equal(t, tc.channels, session.Channels, fmt.Sprintf("%s: expected channels %v got %v\n", desc, tc.channels, session.Channels))
+		if err != nil {
+			continue
+		}
+
+		id, err := svc.CanAccess(context.Background(), sch.ID, signed, mainflux.ActionPublish, "")
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", desc, err))
+		assert.Equal(t, sth.ID, id, fmt.Sprintf("%s: expected thing %s got %s\n", desc, sth.ID, id))
 	}
 }
+
+func TestRevokeKeys(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	sth, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	_, revoked, err := svc.IssueKey(context.Background(), token, sth.ID, time.Hour)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := map[string]struct {
+		id    string
+		token string
+		err   error
+	}{
+		"revoke keys with wrong credentials": {
+			id:    sth.ID,
+			token: wrongValue,
+			err:   things.ErrUnauthorizedAccess,
+		},
+		"revoke keys of non-existing thing": {
+			id:    wrongID,
+			token: token,
+			err:   things.ErrNotFound,
+		},
+		"revoke keys of existing thing": {
+			id:    sth.ID,
+			token: token,
+			err:   nil,
+		},
+	}
+
+	for desc, tc := range cases {
+		err := svc.RevokeKeys(context.Background(), tc.token, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+
+	_, err = svc.Identify(context.Background(), revoked)
+	assert.Equal(t, things.ErrUnauthorizedAccess, err, fmt.Sprintf("identify using revoked key: expected %s got %s\n", things.ErrUnauthorizedAccess, err))
+
+	_, issued, err := svc.IssueKey(context.Background(), token, sth.ID, time.Hour)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	id, err := svc.Identify(context.Background(), issued)
+	assert.Nil(t, err, fmt.Sprintf("identify using key issued after revocation: unexpected error %s\n", err))
+	assert.Equal(t, sth.ID, id, fmt.Sprintf("identify using key issued after revocation: expected %s got %s\n", sth.ID, id))
+}
+
+func TestSignedKeyRevocation(t *testing.T) {
+	newOwner := "new.owner@example.com"
+	svc := newService(map[string]string{token: email, "new-owner-token": newOwner})
+
+	issue := func() (things.Thing, things.Channel, string) {
+		sth, err := svc.AddThing(context.Background(), token, thing)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+		sch, err := svc.CreateChannel(context.Background(), token, channel)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+		err = svc.Connect(context.Background(), token, []string{sch.ID}, []string{sth.ID}, things.FullAccess)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+		_, signed, err := svc.IssueKey(context.Background(), token, sth.ID, time.Hour)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+		return sth, sch, signed
+	}
+
+	cases := map[string]func(things.Thing, things.Channel) error{
+		"disconnect thing": func(th things.Thing, ch things.Channel) error {
+			return svc.Disconnect(context.Background(), token, []string{ch.ID}, []string{th.ID})
+		},
+		"restrict connection access": func(th things.Thing, ch things.Channel) error {
+			return svc.Connect(context.Background(), token, []string{ch.ID}, []string{th.ID}, things.SubscribeAccess)
+		},
+		"add subtopic rule": func(th things.Thing, ch things.Channel) error {
+			_, err := svc.AddSubtopicRule(context.Background(), token, ch.ID, things.SubtopicRule{ThingID: th.ID, Action: mainflux.ActionPublish, Pattern: "temp"})
+			return err
+		},
+		"transfer thing": func(th things.Thing, ch things.Channel) error {
+			return svc.TransferThing(context.Background(), token, th.ID, newOwner, true)
+		},
+		"transfer channel without connections": func(th things.Thing, ch things.Channel) error {
+			return svc.TransferChannel(context.Background(), token, ch.ID, newOwner, false)
+		},
+		"remove channel": func(th things.Thing, ch things.Channel) error {
+			return svc.RemoveChannel(context.Background(), token, ch.ID)
+		},
+	}
+
+	for desc, change := range cases {
+		sth, sch, signed := issue()
+		_, err := svc.CanAccess(context.Background(), sch.ID, signed, mainflux.ActionSubscribe, "")
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", desc, err))
+
+		err = change(sth, sch)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", desc, err))
+
+		_, err = svc.Identify(context.Background(), signed)
+		assert.Equal(t, things.ErrUnauthorizedAccess, err, fmt.Sprintf("%s: expected signed key to be revoked got %s\n", desc, err))
+	}
+}
+
+func TestIssueThingKey(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	sth, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := map[string]struct {
+		id    string
+		token string
+		scope things.KeyScope
+		err   error
+	}{
+		"issue publish key": {
+			id:    sth.ID,
+			token: token,
+			scope: things.ScopePublish,
+			err:   nil,
+		},
+		"issue subscribe key": {
+			id:    sth.ID,
+			token: token,
+			scope: things.ScopeSubscribe,
+			err:   nil,
+		},
+		"issue admin key": {
+			id:    sth.ID,
+			token: token,
+			scope: things.ScopeAdmin,
+			err:   nil,
+		},
+		"issue key with invalid scope": {
+			id:    sth.ID,
+			token: token,
+			scope: things.KeyScope(wrongValue),
+			err:   things.ErrMalformedEntity,
+		},
+		"issue key with wrong credentials": {
+			id:    sth.ID,
+			token: wrongValue,
+			scope: things.ScopePublish,
+			err:   things.ErrUnauthorizedAccess,
+		},
+		"issue key of non-existing thing": {
+			id:    wrongID,
+			token: token,
+			scope: things.ScopePublish,
+			err:   things.ErrNotFound,
+		},
+	}
+
+	for desc, tc := range cases {
+		tk, err := svc.IssueThingKey(context.Background(), tc.token, tc.id, tc.scope)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+		if err == nil {
+			assert.NotEqual(t, sth.Key, tk.Key, fmt.Sprintf("%s: expected key different from the primary one\n", desc))
+			assert.Equal(t, tc.scope, tk.Scope, fmt.Sprintf("%s: expected scope %s got %s\n", desc, tc.scope, tk.Scope))
+		}
+	}
+
+	keys, err := svc.ListThingKeys(context.Background(), token, sth.ID)
+	assert.Nil(t, err, fmt.Sprintf("list thing keys: unexpected error %s\n", err))
+	assert.Len(t, keys, 3, fmt.Sprintf("list thing keys: expected 3 keys got %d\n", len(keys)))
+}
+
+func TestRevokeThingKey(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	sth, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	tk, err := svc.IssueThingKey(context.Background(), token, sth.ID, things.ScopePublish)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	_, err = svc.Identify(context.Background(), tk.Key)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc  string
+		id    string
+		keyID string
+		token string
+		err   error
+	}{
+		{
+			desc:  "revoke key with wrong credentials",
+			id:    sth.ID,
+			keyID: tk.ID,
+			token: wrongValue,
+			err:   things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "revoke non-existing key",
+			id:    sth.ID,
+			keyID: wrongValue,
+			token: token,
+			err:   things.ErrNotFound,
+		},
+		{
+			desc:  "revoke existing key",
+			id:    sth.ID,
+			keyID: tk.ID,
+			token: token,
+			err:   nil,
+		},
+		{
+			desc:  "revoke already revoked key",
+			id:    sth.ID,
+			keyID: tk.ID,
+			token: token,
+			err:   things.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.RevokeThingKey(context.Background(), tc.token, tc.id, tc.keyID)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	_, err = svc.Identify(context.Background(), tk.Key)
+	assert.Equal(t, things.ErrUnauthorizedAccess, err, fmt.Sprintf("identify using revoked key: expected %s got %s\n", things.ErrUnauthorizedAccess, err))
+}
+
+func TestListThings(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	n := uint64(10)
+	ids := []string{}
+	for i := uint64(0); i < n; i++ {
+		th := thing
+		if i%2 == 0 {
+			th.Metadata = map[string]interface{}{"type": "sensor", "site": "plant-3"}
+			th.Tags = []string{"edge"}
+		}
+		if i == 0 {
+			th.Tags = []string{"edge", "v2"}
+		}
+		sth, err := svc.AddThing(context.Background(), token, th)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+		ids = append(ids, sth.ID)
+		if i < 3 {
+			err := svc.RecordActivity(context.Background(), sth.ID)
+			require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+		}
+	}
+
+	cases := map[string]struct {
+		token    string
+		offset   uint64
+		limit    uint64
+		name     string
+		metadata map[string]interface{}
+		tags     things.TagFilter
+		status   things.Status
+		cursor   string
+		size     uint64
+		err      error
+	}{
+		"list all things": {
+			token:  token,
+			offset: 0,
+			limit:  n,
+			size:   n,
+			err:    nil,
+		},
+		"list half": {
+			token:  token,
+			offset: n / 2,
+			limit:  n,
+			size:   n / 2,
+			err:    nil,
+		},
+		"list last thing": {
+			token:  token,
+			offset: n - 1,
+			limit:  n,
+			size:   1,
+			err:    nil,
+		},
+		"list empty set": {
+			token:  token,
+			offset: n + 1,
+			limit:  n,
+			size:   0,
+			err:    nil,
+		},
+		"list with zero limit": {
+			token:  token,
+			offset: 1,
+			limit:  0,
+			size:   0,
+			err:    nil,
+		},
+		"list with wrong credentials": {
+			token:  wrongValue,
+			offset: 0,
+			limit:  0,
+			size:   0,
+			err:    things.ErrUnauthorizedAccess,
+		},
+		"list things with matching metadata": {
+			token:    token,
+			offset:   0,
+			limit:    n,
+			metadata: map[string]interface{}{"type": "sensor", "site": "plant-3"},
+			size:     n / 2,
+			err:      nil,
+		},
+		"list things with non-matching metadata": {
+			token:    token,
+			offset:   0,
+			limit:    n,
+			metadata: map[string]interface{}{"type": "actuator"},
+			size:     0,
+			err:      nil,
+		},
+		"list things holding all the tags": {
+			token:  token,
+			offset: 0,
+			limit:  n,
+			tags:   things.TagFilter{Tags: []string{"edge", "v2"}},
+			size:   1,
+			err:    nil,
+		},
+		"list things holding any of the tags": {
+			token:  token,
+			offset: 0,
+			limit:  n,
+			tags:   things.TagFilter{Tags: []string{"edge", "v3"}, Any: true},
+			size:   n / 2,
+			err:    nil,
+		},
+		"list online things": {
+			token:  token,
+			offset: 0,
+			limit:  n,
+			status: things.Online,
+			size:   3,
+			err:    nil,
+		},
+		"list stale things": {
+			token:  token,
+			offset: 0,
+			limit:  n,
+			status: things.Stale,
+			size:   n - 3,
+			err:    nil,
+		},
+		"list things with unknown status": {
+			token:  token,
+			offset: 0,
+			limit:  n,
+			status: "offline",
+			size:   0,
+			err:    things.ErrMalformedEntity,
+		},
+		"list things past the cursor": {
+			token:  token,
+			limit:  n,
+			cursor: things.EncodeCursor(ids[2]),
+			size:   n - 3,
+			err:    nil,
+		},
+		"list things past the last thing": {
+			token:  token,
+			limit:  n,
+			cursor: things.EncodeCursor(ids[n-1]),
+			size:   0,
+			err:    nil,
+		},
+		"list things with malformed cursor": {
+			token:  token,
+			limit:  n,
+			cursor: "!",
+			size:   0,
+			err:    things.ErrMalformedEntity,
+		},
+	}
+
+	for desc, tc := range cases {
+		page, err := svc.ListThings(context.Background(), tc.token, tc.offset, tc.limit, tc.name, tc.metadata, tc.tags, tc.status, tc.cursor)
+		size := uint64(len(page.Things))
+		assert.Equal(t, tc.size, size, fmt.Sprintf("%s: expected %d got %d\n", desc, tc.size, size))
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
+func TestListThingsByCursor(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	n := 7
+	for i := 0; i < n; i++ {
+		_, err := svc.AddThing(context.Background(), token, thing)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	listed, pages := 0, 0
+	cursor := ""
+	for {
+		page, err := svc.ListThings(context.Background(), token, 0, 3, "", nil, things.TagFilter{}, "", cursor)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		listed += len(page.Things)
+		pages++
+		if page.Cursor == "" {
+			break
+		}
+		cursor = page.Cursor
+	}
+
+	assert.Equal(t, n, listed, fmt.Sprintf("expected %d things got %d\n", n, listed))
+	assert.Equal(t, 3, pages, fmt.Sprintf("expected 3 pages got %d\n", pages))
+}
+
+func TestListThingsByChannel(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	sch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	n := uint64(10)
+	for i := uint64(0); i < n; i++ {
+		sth, err := svc.AddThing(context.Background(), token, thing)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		svc.Connect(context.Background(), token, []string{sch.ID}, []string{sth.ID}, things.FullAccess)
+	}
+
+	// Wait for things and channels to connect
+	time.Sleep(time.Second)
+
+	cases := map[string]struct {
+		token   string
+		channel string
+		offset  uint64
+		limit   uint64
+		size    uint64
+		err     error
+	}{
+		"list all things by existing channel": {
+			token:   token,
+			channel: sch.ID,
+			offset:  0,
+			limit:   n,
+			size:    n,
+			err:     nil,
+		},
+		"list half of things by existing channel": {
+			token:   token,
+			channel: sch.ID,
+			offset:  n / 2,
+			limit:   n,
+			size:    n / 2,
+			err:     nil,
+		},
+		"list last thing by existing channel": {
+			token:   token,
+			channel: sch.ID,
+			offset:  n - 1,
+			limit:   n,
+			size:    1,
+			err:     nil,
+		},
+		"list empty set of things by existing channel": {
+			token:   token,
+			channel: sch.ID,
+			offset:  n + 1,
+			limit:   n,
+			size:    0,
+			err:     nil,
+		},
+		"list things by existing channel with zero limit": {
+			token:   token,
+			channel: sch.ID,
+			offset:  1,
+			limit:   0,
+			size:    0,
+			err:     nil,
+		},
+		"list things by existing channel with wrong credentials": {
+			token:   wrongValue,
+			channel: sch.ID,
+			offset:  0,
+			limit:   0,
+			size:    0,
+			err:     things.ErrUnauthorizedAccess,
+		},
+		"list things by non-existent channel with wrong credentials": {
+			token:   token,
+			channel: "non-existent",
+			offset:  0,
+			limit:   10,
+			size:    0,
+			err:     nil,
+		},
+	}
+
+	for desc, tc := range cases {
+		page, err := svc.ListThingsByChannel(context.Background(), tc.token, tc.channel, tc.offset, tc.limit)
+		size := uint64(len(page.Things))
+		assert.Equal(t, tc.size, size, fmt.Sprintf("%s: expected %d got %d\n", desc, tc.size, size))
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
+func TestEnableThing(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	sth, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	sch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	err = svc.Connect(context.Background(), token, []string{sch.ID}, []string{sth.ID}, things.FullAccess)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	err = svc.DisableThing(context.Background(), token, sth.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc  string
+		id    string
+		token string
+		err   error
+	}{
+		{
+			desc:  "enable thing with wrong credentials",
+			id:    sth.ID,
+			token: wrongValue,
+			err:   things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "enable non-existing thing",
+			id:    wrongID,
+			token: token,
+			err:   things.ErrNotFound,
+		},
+		{
+			desc:  "enable disabled thing",
+			id:    sth.ID,
+			token: token,
+			err:   nil,
+		},
+		{
+			desc:  "enable enabled thing",
+			id:    sth.ID,
+			token: token,
+			err:   nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.EnableThing(context.Background(), tc.token, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	th, err := svc.ViewThing(context.Background(), token, sth.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.Equal(t, things.Enabled, th.State, fmt.Sprintf("expected state %s got %s\n", things.Enabled, th.State))
+
+	id, err := svc.CanAccess(context.Background(), sch.ID, sth.Key, mainflux.ActionPublish, "")
+	assert.Nil(t, err, fmt.Sprintf("access by enabled thing: unexpected error %s\n", err))
+	assert.Equal(t, sth.ID, id, fmt.Sprintf("access by enabled thing: expected %s got %s\n", sth.ID, id))
+
+	page, err := svc.ThingHistory(context.Background(), token, sth.ID, 0, 10)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.Len(t, page.Changes, 2, fmt.Sprintf("expected 2 recorded state changes got %d\n", len(page.Changes)))
+}
+
+func TestDisableThing(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	sth, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	sch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	err = svc.Connect(context.Background(), token, []string{sch.ID}, []string{sth.ID}, things.FullAccess)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	_, err = svc.CanAccess(context.Background(), sch.ID, sth.Key, mainflux.ActionPublish, "")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	_, signed, err := svc.IssueKey(context.Background(), token, sth.ID, time.Hour)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc  string
+		id    string
+		token string
+		err   error
+	}{
+		{
+			desc:  "disable thing with wrong credentials",
+			id:    sth.ID,
+			token: wrongValue,
+			err:   things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "disable non-existing thing",
+			id:    wrongID,
+			token: token,
+			err:   things.ErrNotFound,
+		},
+		{
+			desc:  "disable enabled thing",
+			id:    sth.ID,
+			token: token,
+			err:   nil,
+		},
+		{
+			desc:  "disable disabled thing",
+			id:    sth.ID,
+			token: token,
+			err:   nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.DisableThing(context.Background(), tc.token, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	th, err := svc.ViewThing(context.Background(), token, sth.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.Equal(t, things.Disabled, th.State, fmt.Sprintf("expected state %s got %s\n", things.Disabled, th.State))
+
+	for desc, key := range map[string]string{"plain key": sth.Key, "signed key": signed} {
+		_, err := svc.Identify(context.Background(), key)
+		assert.Equal(t, things.ErrUnauthorizedAccess, err, fmt.Sprintf("identify disabled thing using %s: expected %s got %s\n", desc, things.ErrUnauthorizedAccess, err))
+		_, err = svc.CanAccess(context.Background(), sch.ID, key, mainflux.ActionPublish, "")
+		assert.Equal(t, things.ErrUnauthorizedAccess, err, fmt.Sprintf("access by disabled thing using %s: expected %s got %s\n", desc, things.ErrUnauthorizedAccess, err))
+	}
+
+	_, _, err = svc.IssueKey(context.Background(), token, sth.ID, time.Hour)
+	assert.Equal(t, things.ErrUnauthorizedAccess, err, fmt.Sprintf("issue key to disabled thing: expected %s got %s\n", things.ErrUnauthorizedAccess, err))
+
+	page, err := svc.ListThingsByChannel(context.Background(), token, sch.ID, 0, 10)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.Len(t, page.Things, 1, "expected disabled thing to stay connected")
+}
+
+func TestTransferThing(t *testing.T) {
+	otherToken := "other-token"
+	otherEmail := "other@example.com"
+	svc := newService(map[string]string{token: email, otherToken: otherEmail})
+
+	sch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	dropped, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	kept, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	err = svc.Connect(context.Background(), token, []string{sch.ID}, []string{dropped.ID, kept.ID}, things.FullAccess)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc      string
+		id        string
+		token     string
+		owner     string
+		keepConns bool
+		err       error
+	}{
+		{
+			desc:  "transfer thing with wrong credentials",
+			id:    dropped.ID,
+			token: wrongValue,
+			owner: otherEmail,
+			err:   things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "transfer thing without new owner",
+			id:    dropped.ID,
+			token: token,
+			owner: "",
+			err:   things.ErrMalformedEntity,
+		},
+		{
+			desc:  "transfer thing to its owner",
+			id:    dropped.ID,
+			token: token,
+			owner: email,
+			err:   things.ErrMalformedEntity,
+		},
+		{
+			desc:  "transfer thing to non-existing user",
+			id:    dropped.ID,
+			token: token,
+			owner: wrongValue,
+			err:   things.ErrMalformedEntity,
+		},
+		{
+			desc:  "transfer non-existing thing",
+			id:    wrongID,
+			token: token,
+			owner: otherEmail,
+			err:   things.ErrNotFound,
+		},
+		{
+			desc:  "transfer thing dropping its connections",
+			id:    dropped.ID,
+			token: token,
+			owner: otherEmail,
+			err:   nil,
+		},
+		{
+			desc:  "transfer transferred thing",
+			id:    dropped.ID,
+			token: token,
+			owner: otherEmail,
+			err:   things.ErrNotFound,
+		},
+		{
+			desc:      "transfer thing keeping its connections",
+			id:        kept.ID,
+			token:     token,
+			owner:     otherEmail,
+			keepConns: true,
+			err:       nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.TransferThing(context.Background(), tc.token, tc.id, tc.owner, tc.keepConns)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	_, err = svc.ViewThing(context.Background(), token, dropped.ID)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("view transferred thing by previous owner: expected %s got %s\n", things.ErrNotFound, err))
+	th, err := svc.ViewThing(context.Background(), otherToken, dropped.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.Equal(t, otherEmail, th.Owner, fmt.Sprintf("expected owner %s got %s\n", otherEmail, th.Owner))
+	assert.Equal(t, dropped.Key, th.Key, "expected transferred thing to keep its key")
+
+	_, err = svc.CanAccess(context.Background(), sch.ID, kept.Key, mainflux.ActionPublish, "")
+	assert.Nil(t, err, fmt.Sprintf("access by thing keeping its connections: unexpected error %s\n", err))
+
+	audit, err := svc.Audit(context.Background(), otherToken, things.AuditQuery{Operation: things.TransferOperation}, 0, 10)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.Equal(t, uint64(2), audit.Total, fmt.Sprintf("expected %d transfers audited got %d\n", 2, audit.Total))
+}
+
+func TestRemoveThing(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	saved, _ := svc.AddThing(context.Background(), token, thing)
+
+	cases := []struct {
+		desc  string
+		id    string
+		token string
+		err   error
+	}{
+		{
+			desc:  "remove thing with wrong credentials",
+			id:    saved.ID,
+			token: wrongValue,
+			err:   things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "remove existing thing",
+			id:    saved.ID,
+			token: token,
+			err:   nil,
+		},
+		{
+			desc:  "remove removed thing",
+			id:    saved.ID,
+			token: token,
+			err:   nil,
+		},
+		{
+			desc:  "remove non-existing thing",
+			id:    wrongID,
+			token: token,
+			err:   nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.RemoveThing(context.Background(), tc.token, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestCreateChannel(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	cases := []struct {
+		desc    string
+		channel things.Channel
+		token   string
+		err     error
+	}{
+		{
+			desc:    "create channel",
+			channel: channel,
+			token:   token,
+			err:     nil,
+		},
+		{
+			desc:    "create channel with wrong credentials",
+			channel: channel,
+			token:   wrongValue,
+			err:     things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:    "create channel with malformed adapter metadata",
+			channel: things.Channel{Metadata: map[string]interface{}{"opcua": map[string]interface{}{"serverURI": "http://localhost"}}},
+			token:   token,
+			err:     things.ErrMalformedEntity,
+		},
+		{
+			desc:    "create channel with rate limit",
+			channel: things.Channel{Metadata: map[string]interface{}{"rateLimit": map[string]interface{}{"rate": 10, "burst": 20}}},
+			token:   token,
+			err:     nil,
+		},
+		{
+			desc:    "create channel with malformed rate limit",
+			channel: things.Channel{Metadata: map[string]interface{}{"rateLimit": map[string]interface{}{"rate": -1}}},
+			token:   token,
+			err:     things.ErrMalformedEntity,
+		},
+		{
+			desc:    "create channel with validation rules",
+			channel: things.Channel{Metadata: map[string]interface{}{"validation": map[string]interface{}{"units": []string{"Cel"}}}},
+			token:   token,
+			err:     nil,
+		},
+		{
+			desc:    "create channel with empty validation rules",
+			channel: things.Channel{Metadata: map[string]interface{}{"validation": map[string]interface{}{}}},
+			token:   token,
+			err:     things.ErrMalformedEntity,
+		},
+		{
+			desc:    "create channel with delivery mode",
+			channel: things.Channel{Metadata: map[string]interface{}{"delivery": map[string]interface{}{"mode": mainflux.DeliveryAtLeastOnce}}},
+			token:   token,
+			err:     nil,
+		},
+		{
+			desc:    "create channel with unknown delivery mode",
+			channel: things.Channel{Metadata: map[string]interface{}{"delivery": map[string]interface{}{"mode": "exactly-once"}}},
+			token:   token,
+			err:     things.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := svc.CreateChannel(context.Background(), tc.token, tc.channel)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestUpdateChannel(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	saved, _ := svc.CreateChannel(context.Background(), token, channel)
+	other := things.Channel{ID: wrongID}
+
+	cases := []struct {
+		desc    string
+		channel things.Channel
+		token   string
+		err     error
+	}{
+		{
+			desc:    "update existing channel",
+			channel: saved,
+			token:   token,
+			err:     nil,
+		},
+		{
+			desc:    "update channel with wrong credentials",
+			channel: saved,
+			token:   wrongValue,
+			err:     things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:    "update non-existing channel",
+			channel: other,
+			token:   token,
+			err:     things.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.UpdateChannel(context.Background(), tc.token, tc.channel)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestPatchChannel(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	saved, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc  string
+		token string
+		id    string
+		patch map[string]interface{}
+		name  string
+		err   error
+	}{
+		{
+			desc:  "patch name of an existing channel",
+			token: token,
+			id:    saved.ID,
+			patch: map[string]interface{}{"name": "patched"},
+			name:  "patched",
+			err:   nil,
+		},
+		{
+			desc:  "patch read-only field of an existing channel",
+			token: token,
+			id:    saved.ID,
+			patch: map[string]interface{}{"id": "new"},
+			name:  "",
+			err:   things.ErrMalformedEntity,
+		},
+		{
+			desc:  "patch channel with invalid credentials",
+			token: wrongValue,
+			id:    saved.ID,
+			patch: map[string]interface{}{"name": "patched"},
+			name:  "",
+			err:   things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "patch non-existing channel",
+			token: token,
+			id:    wrongID,
+			patch: map[string]interface{}{"name": "patched"},
+			name:  "",
+			err:   things.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		res, err := svc.PatchChannel(context.Background(), tc.token, tc.id, tc.patch)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.name, res.Name, fmt.Sprintf("%s: expected name %s got %s\n", tc.desc, tc.name, res.Name))
+	}
+}
+
+func TestViewChannel(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	saved, _ := svc.CreateChannel(context.Background(), token, channel)
+
+	cases := map[string]struct {
+		id    string
+		token string
+		err   error
+	}{
+		"view existing channel": {
+			id:    saved.ID,
+			token: token,
+			err:   nil,
+		},
+		"view channel with wrong credentials": {
+			id:    saved.ID,
+			token: wrongValue,
+			err:   things.ErrUnauthorizedAccess,
+		},
+		"view non-existing channel": {
+			id:    wrongID,
+			token: token,
+			err:   things.ErrNotFound,
+		},
+	}
+
+	for desc, tc := range cases {
+		_, err := svc.ViewChannel(context.Background(), tc.token, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
+func TestCountThings(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	n := uint64(10)
+	for i := uint64(0); i < n; i++ {
+		th := thing
+		if i%2 == 0 {
+			th.Metadata = map[string]interface{}{"type": "sensor"}
+			th.Tags = []string{"edge"}
+		}
+		sth, err := svc.AddThing(context.Background(), token, th)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+		if i < 3 {
+			err := svc.RecordActivity(context.Background(), sth.ID)
+			require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+		}
+	}
+
+	cases := map[string]struct {
+		token    string
+		metadata map[string]interface{}
+		tags     things.TagFilter
+		status   things.Status
+		total    uint64
+		err      error
+	}{
+		"count all things": {
+			token: token,
+			total: n,
+			err:   nil,
+		},
+		"count things with metadata": {
+			token:    token,
+			metadata: map[string]interface{}{"type": "sensor"},
+			total:    n / 2,
+			err:      nil,
+		},
+		"count things with tags": {
+			token: token,
+			tags:  things.TagFilter{Tags: []string{"edge"}},
+			total: n / 2,
+			err:   nil,
+		},
+		"count online things": {
+			token:  token,
+			status: things.Online,
+			total:  3,
+			err:    nil,
+		},
+		"count things with invalid status": {
+			token:  token,
+			status: things.Status("invalid"),
+			total:  0,
+			err:    things.ErrMalformedEntity,
+		},
+		"count things with wrong credentials": {
+			token: wrongValue,
+			total: 0,
+			err:   things.ErrUnauthorizedAccess,
+		},
+	}
+
+	for desc, tc := range cases {
+		total, err := svc.CountThings(context.Background(), tc.token, "", tc.metadata, tc.tags, tc.status)
+		assert.Equal(t, tc.total, total, fmt.Sprintf("%s: expected %d got %d\n", desc, tc.total, total))
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
+func TestCountChannels(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	n := uint64(10)
+	for i := uint64(0); i < n; i++ {
+		ch := channel
+		if i%2 == 0 {
+			ch.Tags = []string{"plant-3"}
+			ch.Metadata = map[string]interface{}{"region": "eu"}
+		}
+		_, err := svc.CreateChannel(context.Background(), token, ch)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	}
+
+	cases := map[string]struct {
+		token    string
+		metadata map[string]interface{}
+		tags     things.TagFilter
+		total    uint64
+		err      error
+	}{
+		"count all channels": {
+			token: token,
+			total: n,
+			err:   nil,
+		},
+		"count channels with metadata": {
+			token:    token,
+			metadata: map[string]interface{}{"region": "eu"},
+			total:    n / 2,
+			err:      nil,
+		},
+		"count channels with tags": {
+			token: token,
+			tags:  things.TagFilter{Tags: []string{"plant-3"}},
+			total: n / 2,
+			err:   nil,
+		},
+		"count channels with wrong credentials": {
+			token: wrongValue,
+			total: 0,
+			err:   things.ErrUnauthorizedAccess,
+		},
+	}
+
+	for desc, tc := range cases {
+		total, err := svc.CountChannels(context.Background(), tc.token, "", tc.metadata, tc.tags)
+		assert.Equal(t, tc.total, total, fmt.Sprintf("%s: expected %d got %d\n", desc, tc.total, total))
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
+func TestListChannels(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	n := uint64(10)
+	ids := []string{}
+	for i := uint64(0); i < n; i++ {
+		ch := channel
+		if i%2 == 0 {
+			ch.Tags = []string{"plant-3"}
+			ch.Metadata = map[string]interface{}{"protocol": "mqtt", "region": "eu"}
+		}
+		sch, err := svc.CreateChannel(context.Background(), token, ch)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+		ids = append(ids, sch.ID)
+	}
+	cases := map[string]struct {
+		token    string
+		offset   uint64
+		limit    uint64
+		size     uint64
+		name     string
+		metadata map[string]interface{}
+		tags     things.TagFilter
+		cursor   string
+		err      error
+	}{
+		"list all channels": {
+			token:  token,
+			offset: 0,
+			limit:  n,
+			size:   n,
+			err:    nil,
+		},
+		"list half": {
+			token:  token,
+			offset: n / 2,
+			limit:  n,
+			size:   n / 2,
+			err:    nil,
+		},
+		"list last channel": {
+			token:  token,
+			offset: n - 1,
+			limit:  n,
+			size:   1,
+			err:    nil,
+		},
+		"list empty set": {
+			token:  token,
+			offset: n + 1,
+			limit:  n,
+			size:   0,
+			err:    nil,
+		},
+		"list with zero limit": {
+			token:  token,
+			offset: 1,
+			limit:  0,
+			size:   0,
+			err:    nil,
+		},
+		"list with wrong credentials": {
+			token:  wrongValue,
+			offset: 0,
+			limit:  0,
+			size:   0,
+			err:    things.ErrUnauthorizedAccess,
+		},
+		"list with existing name": {
+			token:  token,
+			offset: 0,
+			limit:  n,
+			size:   n,
+			name:   "chanel_name",
+			err:    nil,
+		},
+		"list with non-existent name": {
+			token:  token,
+			offset: 0,
+			limit:  n,
+			size:   n,
+			name:   "wrong",
+			err:    nil,
+		},
+		"list channels with matching metadata": {
+			token:    token,
+			offset:   0,
+			limit:    n,
+			size:     n / 2,
+			metadata: map[string]interface{}{"protocol": "mqtt", "region": "eu"},
+			err:      nil,
+		},
+		"list channels with non-matching metadata": {
+			token:    token,
+			offset:   0,
+			limit:    n,
+			size:     0,
+			metadata: map[string]interface{}{"protocol": "coap"},
+			err:      nil,
+		},
+		"list channels holding the tag": {
+			token:  token,
+			offset: 0,
+			limit:  n,
+			size:   n / 2,
+			tags:   things.TagFilter{Tags: []string{"plant-3"}},
+			err:    nil,
+		},
+		"list channels holding none of the tags": {
+			token:  token,
+			offset: 0,
+			limit:  n,
+			size:   0,
+			tags:   things.TagFilter{Tags: []string{"plant-4", "v2"}, Any: true},
+			err:    nil,
+		},
+		"list channels past the cursor": {
+			token:  token,
+			limit:  n,
+			size:   n - 3,
+			cursor: things.EncodeCursor(ids[2]),
+			err:    nil,
+		},
+		"list channels with malformed cursor": {
+			token:  token,
+			limit:  n,
+			size:   0,
+			cursor: "!",
+			err:    things.ErrMalformedEntity,
+		},
+	}
+
+	for desc, tc := range cases {
+		page, err := svc.ListChannels(context.Background(), tc.token, tc.offset, tc.limit, tc.name, tc.metadata, tc.tags, tc.cursor)
+		size := uint64(len(page.Channels))
+		assert.Equal(t, tc.size, size, fmt.Sprintf("%s: expected %d got %d\n", desc, tc.size, size))
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
+func TestListChannelsByThing(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	sth, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	n := uint64(10)
+	for i := uint64(0); i < n; i++ {
+		sch, err := svc.CreateChannel(context.Background(), token, channel)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		svc.Connect(context.Background(), token, []string{sch.ID}, []string{sth.ID}, things.FullAccess)
+	}
+
+	// Wait for things and channels to connect.
+	time.Sleep(time.Second)
+
+	cases := map[string]struct {
+		token  string
+		thing  string
+		offset uint64
+		limit  uint64
+		size   uint64
+		err    error
+	}{
+		"list all channels by existing thing": {
+			token:  token,
+			thing:  sth.ID,
+			offset: 0,
+			limit:  n,
+			size:   n,
+			err:    nil,
+		},
+		"list half of channels by existing thing": {
+			token:  token,
+			thing:  sth.ID,
+			offset: n / 2,
+			limit:  n,
+			size:   n / 2,
+			err:    nil,
+		},
+		"list last channel by existing thing": {
+			token:  token,
+			thing:  sth.ID,
+			offset: n - 1,
+			limit:  n,
+			size:   1,
+			err:    nil,
+		},
+		"list empty set of channels by existing thing": {
+			token:  token,
+			thing:  sth.ID,
+			offset: n + 1,
+			limit:  n,
+			size:   0,
+			err:    nil,
+		},
+		"list channels by existing thing with zero limit": {
+			token:  token,
+			thing:  sth.ID,
+			offset: 1,
+			limit:  0,
+			size:   0,
+			err:    nil,
+		},
+		"list channels by existing thing with wrong credentials": {
+			token:  wrongValue,
+			thing:  sth.ID,
+			offset: 0,
+			limit:  0,
+			size:   0,
+			err:    things.ErrUnauthorizedAccess,
+		},
+		"list channels by non-existent thing": {
+			token:  token,
+			thing:  "non-existent",
+			offset: 0,
+			limit:  10,
+			size:   0,
+			err:    nil,
+		},
+	}
+
+	for desc, tc := range cases {
+		page, err := svc.ListChannelsByThing(context.Background(), tc.token, tc.thing, tc.offset, tc.limit)
+		size := uint64(len(page.Channels))
+		assert.Equal(t, tc.size, size, fmt.Sprintf("%s: expected %d got %d\n", desc, tc.size, size))
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
+func TestTransferChannel(t *testing.T) {
+	otherToken := "other-token"
+	otherEmail := "other@example.com"
+	svc := newService(map[string]string{token: email, otherToken: otherEmail})
+
+	sth, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	dropped, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	kept, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	err = svc.Connect(context.Background(), token, []string{dropped.ID, kept.ID}, []string{sth.ID}, things.FullAccess)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc      string
+		id        string
+		token     string
+		owner     string
+		keepConns bool
+		err       error
+	}{
+		{
+			desc:  "transfer channel with wrong credentials",
+			id:    dropped.ID,
+			token: wrongValue,
+			owner: otherEmail,
+			err:   things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "transfer channel without new owner",
+			id:    dropped.ID,
+			token: token,
+			owner: "",
+			err:   things.ErrMalformedEntity,
+		},
+		{
+			desc:  "transfer channel to its owner",
+			id:    dropped.ID,
+			token: token,
+			owner: email,
+			err:   things.ErrMalformedEntity,
+		},
+		{
+			desc:  "transfer channel to non-existing user",
+			id:    dropped.ID,
+			token: token,
+			owner: wrongValue,
+			err:   things.ErrMalformedEntity,
+		},
+		{
+			desc:  "transfer non-existing channel",
+			id:    wrongID,
+			token: token,
+			owner: otherEmail,
+			err:   things.ErrNotFound,
+		},
+		{
+			desc:  "transfer channel dropping its connections",
+			id:    dropped.ID,
+			token: token,
+			owner: otherEmail,
+			err:   nil,
+		},
+		{
+			desc:  "transfer transferred channel",
+			id:    dropped.ID,
+			token: token,
+			owner: otherEmail,
+			err:   things.ErrNotFound,
+		},
+		{
+			desc:      "transfer channel keeping its connections",
+			id:        kept.ID,
+			token:     token,
+			owner:     otherEmail,
+			keepConns: true,
+			err:       nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.TransferChannel(context.Background(), tc.token, tc.id, tc.owner, tc.keepConns)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	_, err = svc.ViewChannel(context.Background(), token, dropped.ID)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("view transferred channel by previous owner: expected %s got %s\n", things.ErrNotFound, err))
+	ch, err := svc.ViewChannel(context.Background(), otherToken, dropped.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.Equal(t, otherEmail, ch.Owner, fmt.Sprintf("expected owner %s got %s\n", otherEmail, ch.Owner))
+
+	_, err = svc.CanAccess(context.Background(), dropped.ID, sth.Key, mainflux.ActionPublish, "")
+	assert.Equal(t, things.ErrUnauthorizedAccess, err, fmt.Sprintf("access channel dropping its connections: expected %s got %s\n", things.ErrUnauthorizedAccess, err))
+	_, err = svc.CanAccess(context.Background(), kept.ID, sth.Key, mainflux.ActionPublish, "")
+	assert.Nil(t, err, fmt.Sprintf("access channel keeping its connections: unexpected error %s\n", err))
+}
+
+func TestRemoveChannel(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	saved, _ := svc.CreateChannel(context.Background(), token, channel)
+
+	cases := []struct {
+		desc  string
+		id    string
+		token string
+		err   error
+	}{
+		{
+			desc:  "remove channel with wrong credentials",
+			id:    saved.ID,
+			token: wrongValue,
+			err:   things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "remove existing channel",
+			id:    saved.ID,
+			token: token,
+			err:   nil,
+		},
+		{
+			desc:  "remove removed channel",
+			id:    saved.ID,
+			token: token,
+			err:   nil,
+		},
+		{
+			desc:  "remove non-existing channel",
+			id:    saved.ID,
+			token: token,
+			err:   nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.RemoveChannel(context.Background(), tc.token, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestArchiveChannel(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	sth, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	sch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	err = svc.Connect(context.Background(), token, []string{sch.ID}, []string{sth.ID}, things.FullAccess)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	_, err = svc.CanAccess(context.Background(), sch.ID, sth.Key, mainflux.ActionPublish, "")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc  string
+		id    string
+		token string
+		err   error
+	}{
+		{
+			desc:  "archive channel with wrong credentials",
+			id:    sch.ID,
+			token: wrongValue,
+			err:   things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "archive non-existing channel",
+			id:    wrongID,
+			token: token,
+			err:   things.ErrNotFound,
+		},
+		{
+			desc:  "archive active channel",
+			id:    sch.ID,
+			token: token,
+			err:   nil,
+		},
+		{
+			desc:  "archive archived channel",
+			id:    sch.ID,
+			token: token,
+			err:   nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.ArchiveChannel(context.Background(), tc.token, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	ch, err := svc.ViewChannel(context.Background(), token, sch.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.Equal(t, things.Archived, ch.State, fmt.Sprintf("expected state %s got %s\n", things.Archived, ch.State))
+
+	_, err = svc.CanAccess(context.Background(), sch.ID, sth.Key, mainflux.ActionPublish, "")
+	assert.Equal(t, things.ErrUnauthorizedAccess, err, fmt.Sprintf("publish to archived channel: expected %s got %s\n", things.ErrUnauthorizedAccess, err))
+	_, err = svc.CanAccess(context.Background(), sch.ID, sth.Key, mainflux.ActionSubscribe, "")
+	assert.Nil(t, err, fmt.Sprintf("subscribe to archived channel: unexpected error %s\n", err))
+
+	err = svc.UnarchiveChannel(context.Background(), token, sch.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	_, err = svc.CanAccess(context.Background(), sch.ID, sth.Key, mainflux.ActionPublish, "")
+	assert.Nil(t, err, fmt.Sprintf("publish to unarchived channel: unexpected error %s\n", err))
+
+	page, err := svc.ChannelHistory(context.Background(), token, sch.ID, 0, 10)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.Len(t, page.Changes, 2, fmt.Sprintf("expected 2 state changes got %d\n", len(page.Changes)))
+}
+
+func TestArchiveChannels(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ch := channel
+	ch.Metadata = map[string]interface{}{"project": "greenhouse"}
+	matched, err := svc.CreateChannel(context.Background(), token, ch)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	ch.Metadata = map[string]interface{}{"project": "warehouse"}
+	other, err := svc.CreateChannel(context.Background(), token, ch)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	ch.Metadata = map[string]interface{}{"project": "office"}
+	ch.Tags = []string{"retired"}
+	_, err = svc.CreateChannel(context.Background(), token, ch)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc     string
+		token    string
+		selector map[string]interface{}
+		tags     things.TagFilter
+		size     int
+		err      error
+	}{
+		{
+			desc:     "archive channels with invalid credentials",
+			token:    wrongValue,
+			selector: map[string]interface{}{"project": "greenhouse"},
+			size:     0,
+			err:      things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:     "archive channels without selector",
+			token:    token,
+			selector: nil,
+			size:     0,
+			err:      things.ErrMalformedEntity,
+		},
+		{
+			desc:     "archive channels matching selector",
+			token:    token,
+			selector: map[string]interface{}{"project": "greenhouse"},
+			size:     1,
+			err:      nil,
+		},
+		{
+			desc:     "archive archived channels",
+			token:    token,
+			selector: map[string]interface{}{"project": "greenhouse"},
+			size:     0,
+			err:      nil,
+		},
+		{
+			desc:     "archive channels holding the tag",
+			token:    token,
+			selector: nil,
+			tags:     things.TagFilter{Tags: []string{"retired"}},
+			size:     1,
+			err:      nil,
+		},
+	}
+
+	for _, tc := range cases {
+		archived, err := svc.ArchiveChannels(context.Background(), tc.token, tc.selector, tc.tags)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.size, len(archived), fmt.Sprintf("%s: expected %d channels got %d\n", tc.desc, tc.size, len(archived)))
+	}
+
+	res, err := svc.ViewChannel(context.Background(), token, matched.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.Equal(t, things.Archived, res.State, fmt.Sprintf("expected state %s got %s\n", things.Archived, res.State))
+
+	res, err = svc.ViewChannel(context.Background(), token, other.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.Equal(t, things.Active, res.State, fmt.Sprintf("expected state %s got %s\n", things.Active, res.State))
+}
+
+func TestInviteToChannel(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	sth, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	sch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	err = svc.Connect(context.Background(), token, []string{sch.ID}, []string{sth.ID}, things.FullAccess)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := map[string]struct {
+		id    string
+		token string
+		ttl   time.Duration
+		err   error
+	}{
+		"invite to existing channel": {
+			id:    sch.ID,
+			token: token,
+			ttl:   time.Hour,
+			err:   nil,
+		},
+		"invite with too long ttl": {
+			id:    sch.ID,
+			token: token,
+			ttl:   30 * 24 * time.Hour,
+			err:   things.ErrMalformedEntity,
+		},
+		"invite with wrong credentials": {
+			id:    sch.ID,
+			token: wrongValue,
+			ttl:   time.Hour,
+			err:   things.ErrUnauthorizedAccess,
+		},
+		"invite to non-existing channel": {
+			id:    wrongID,
+			token: token,
+			ttl:   time.Hour,
+			err:   things.ErrNotFound,
+		},
+	}
+
+	for desc, tc := range cases {
+		inv, signed, err := svc.InviteToChannel(context.Background(), tc.token, tc.id, tc.ttl)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+		if err != nil {
+			continue
+		}
+		assert.Equal(t, tc.id, inv.ChannelID, fmt.Sprintf("%s: expected channel %s got %s\n", desc, tc.id, inv.ChannelID))
+
+		// Invitations are subscribe-only, so they can't be used to publish.
+		_, err = svc.CanAccess(context.Background(), sch.ID, signed, mainflux.ActionPublish, "")
+		assert.Equal(t, things.ErrUnauthorizedAccess, err, fmt.Sprintf("%s: expected %s got %s\n", desc, things.ErrUnauthorizedAccess, err))
+	}
+}
+
+func TestIssueChannelToken(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	sch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := map[string]struct {
+		id    string
+		token string
+		scope things.KeyScope
+		ttl   time.Duration
+		err   error
+	}{
+		"issue publish token": {
+			id:    sch.ID,
+			token: token,
+			scope: things.ScopePublish,
+			ttl:   time.Hour,
+			err:   nil,
+		},
+		"issue admin token": {
+			id:    sch.ID,
+			token: token,
+			scope: things.ScopeAdmin,
+			ttl:   time.Hour,
+			err:   nil,
+		},
+		"issue token with invalid scope": {
+			id:    sch.ID,
+			token: token,
+			scope: things.KeyScope(wrongValue),
+			ttl:   time.Hour,
+			err:   things.ErrMalformedEntity,
+		},
+		"issue token with too long ttl": {
+			id:    sch.ID,
+			token: token,
+			scope: things.ScopePublish,
+			ttl:   2 * 365 * 24 * time.Hour,
+			err:   things.ErrMalformedEntity,
+		},
+		"issue token with wrong credentials": {
+			id:    sch.ID,
+			token: wrongValue,
+			scope: things.ScopePublish,
+			ttl:   time.Hour,
+			err:   things.ErrUnauthorizedAccess,
+		},
+		"issue token of non-existing channel": {
+			id:    wrongID,
+			token: token,
+			scope: things.ScopePublish,
+			ttl:   time.Hour,
+			err:   things.ErrNotFound,
+		},
+	}
+
+	for desc, tc := range cases {
+		ct, err := svc.IssueChannelToken(context.Background(), tc.token, tc.id, tc.scope, tc.ttl)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+		if err == nil {
+			assert.Equal(t, tc.id, ct.ChannelID, fmt.Sprintf("%s: expected channel %s got %s\n", desc, tc.id, ct.ChannelID))
+			assert.Equal(t, tc.scope, ct.Scope, fmt.Sprintf("%s: expected scope %s got %s\n", desc, tc.scope, ct.Scope))
+			assert.Equal(t, tc.ttl, ct.ExpiresAt.Sub(ct.IssuedAt), fmt.Sprintf("%s: expected validity %s got %s\n", desc, tc.ttl, ct.ExpiresAt.Sub(ct.IssuedAt)))
+		}
+	}
+
+	tokens, err := svc.ListChannelTokens(context.Background(), token, sch.ID)
+	assert.Nil(t, err, fmt.Sprintf("list channel tokens: unexpected error %s\n", err))
+	assert.Len(t, tokens, 2, fmt.Sprintf("list channel tokens: expected 2 tokens got %d\n", len(tokens)))
+
+	_, err = svc.ListChannelTokens(context.Background(), token, wrongID)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("list tokens of non-existing channel: expected %s got %s\n", things.ErrNotFound, err))
+}
+
+func TestRevokeChannelToken(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	sch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	ct, err := svc.IssueChannelToken(context.Background(), token, sch.ID, things.ScopePublish, time.Hour)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	_, err = svc.CanAccess(context.Background(), sch.ID, ct.Token, mainflux.ActionPublish, "")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc    string
+		id      string
+		tokenID string
+		token   string
+		err     error
+	}{
+		{
+			desc:    "revoke token with wrong credentials",
+			id:      sch.ID,
+			tokenID: ct.ID,
+			token:   wrongValue,
+			err:     things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:    "revoke non-existing token",
+			id:      sch.ID,
+			tokenID: wrongValue,
+			token:   token,
+			err:     things.ErrNotFound,
+		},
+		{
+			desc:    "revoke existing token",
+			id:      sch.ID,
+			tokenID: ct.ID,
+			token:   token,
+			err:     nil,
+		},
+		{
+			desc:    "revoke already revoked token",
+			id:      sch.ID,
+			tokenID: ct.ID,
+			token:   token,
+			err:     things.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.RevokeChannelToken(context.Background(), tc.token, tc.id, tc.tokenID)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	_, err = svc.CanAccess(context.Background(), sch.ID, ct.Token, mainflux.ActionPublish, "")
+	assert.Equal(t, things.ErrUnauthorizedAccess, err, fmt.Sprintf("publish using revoked token: expected %s got %s\n", things.ErrUnauthorizedAccess, err))
+}
+
+func TestConnect(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	sth, _ := svc.AddThing(context.Background(), token, thing)
+	sth2, _ := svc.AddThing(context.Background(), token, thing)
+	sch, _ := svc.CreateChannel(context.Background(), token, channel)
+	sch2, _ := svc.CreateChannel(context.Background(), token, channel)
+
+	cases := []struct {
+		desc     string
+		token    string
+		chanIDs  []string
+		thingIDs []string
+		err      error
+	}{
+		{
+			desc:     "connect thing",
+			token:    token,
+			chanIDs:  []string{sch.ID},
+			thingIDs: []string{sth.ID},
+			err:      nil,
+		},
+		{
+			desc:     "connect multiple things to multiple channels",
+			token:    token,
+			chanIDs:  []string{sch.ID, sch2.ID},
+			thingIDs: []string{sth.ID, sth2.ID},
+			err:      nil,
+		},
+		{
+			desc:     "connect thing with wrong credentials",
+			token:    wrongValue,
+			chanIDs:  []string{sch.ID},
+			thingIDs: []string{sth.ID},
+			err:      things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:     "connect thing to non-existing channel",
+			token:    token,
+			chanIDs:  []string{wrongValue},
+			thingIDs: []string{sth.ID},
+			err:      connectionError(wrongValue, sth.ID),
+		},
+		{
+			desc:     "connect non-existing thing to channel",
+			token:    token,
+			chanIDs:  []string{sch.ID},
+			thingIDs: []string{wrongValue},
+			err:      connectionError(sch.ID, wrongValue),
+		},
+		{
+			desc:     "connect existing and non-existing things to channel",
+			token:    token,
+			chanIDs:  []string{sch.ID},
+			thingIDs: []string{sth.ID, wrongValue},
+			err:      connectionError(sch.ID, wrongValue),
+		},
+		{
+			desc:     "connect thing without channels",
+			token:    token,
+			chanIDs:  []string{},
+			thingIDs: []string{sth.ID},
+			err:      things.ErrMalformedEntity,
+		},
+		{
+			desc:     "connect thing with empty channel ID",
+			token:    token,
+			chanIDs:  []string{wrongID},
+			thingIDs: []string{sth.ID},
+			err:      things.ErrMalformedEntity,
+		},
+		{
+			desc:     "connect duplicated things",
+			token:    token,
+			chanIDs:  []string{sch.ID},
+			thingIDs: []string{sth.ID, sth.ID},
+			err:      things.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.Connect(context.Background(), tc.token, tc.chanIDs, tc.thingIDs, things.FullAccess)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	// The failed connection doesn't connect the rest of the pairs.
+	sth3, _ := svc.AddThing(context.Background(), token, thing)
+	err := svc.Connect(context.Background(), token, []string{sch.ID}, []string{sth3.ID, wrongValue}, things.FullAccess)
+	assert.Equal(t, connectionError(sch.ID, wrongValue), err, fmt.Sprintf("expected connection error got %s\n", err))
+	page, err := svc.ListChannelsByThing(context.Background(), token, sth3.ID, 0, 10)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.Empty(t, page.Channels, "expected thing not to be connected")
+}
+
+func TestDisconnect(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	sth, _ := svc.AddThing(context.Background(), token, thing)
+	sth2, _ := svc.AddThing(context.Background(), token, thing)
+	sch, _ := svc.CreateChannel(context.Background(), token, channel)
+	sch2, _ := svc.CreateChannel(context.Background(), token, channel)
+	svc.Connect(context.Background(), token, []string{sch.ID}, []string{sth.ID}, things.FullAccess)
+	svc.Connect(context.Background(), token, []string{sch2.ID}, []string{sth.ID, sth2.ID}, things.FullAccess)
+
+	cases := []struct {
+		desc     string
+		token    string
+		chanIDs  []string
+		thingIDs []string
+		err      error
+	}{
+		{
+			desc:     "disconnect connected thing",
+			token:    token,
+			chanIDs:  []string{sch.ID},
+			thingIDs: []string{sth.ID},
+			err:      nil,
+		},
+		{
+			desc:     "disconnect disconnected thing",
+			token:    token,
+			chanIDs:  []string{sch.ID},
+			thingIDs: []string{sth.ID},
+			err:      connectionError(sch.ID, sth.ID),
+		},
+		{
+			desc:     "disconnect with wrong credentials",
+			token:    wrongValue,
+			chanIDs:  []string{sch2.ID},
+			thingIDs: []string{sth.ID},
+			err:      things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:     "disconnect from non-existing channel",
+			token:    token,
+			chanIDs:  []string{wrongValue},
+			thingIDs: []string{sth.ID},
+			err:      connectionError(wrongValue, sth.ID),
+		},
+		{
+			desc:     "disconnect non-existing thing",
+			token:    token,
+			chanIDs:  []string{sch2.ID},
+			thingIDs: []string{wrongValue},
+			err:      connectionError(sch2.ID, wrongValue),
+		},
+		{
+			desc:     "disconnect without things",
+			token:    token,
+			chanIDs:  []string{sch2.ID},
+			thingIDs: []string{},
+			err:      things.ErrMalformedEntity,
+		},
+		{
+			desc:     "disconnect multiple things",
+			token:    token,
+			chanIDs:  []string{sch2.ID},
+			thingIDs: []string{sth.ID, sth2.ID},
+			err:      nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.Disconnect(context.Background(), tc.token, tc.chanIDs, tc.thingIDs)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestExport(t *testing.T) {
+	svc := newService(map[string]string{token: email, "other": "other@example.com"})
+
+	sth, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	sth2, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	sch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = svc.Connect(context.Background(), token, []string{sch.ID}, []string{sth.ID}, things.FullAccess)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = svc.Connect(context.Background(), token, []string{sch.ID}, []string{sth2.ID}, things.PublishAccess)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc     string
+		token    string
+		snapshot things.Snapshot
+		err      error
+	}{
+		{
+			desc:  "export things and channels",
+			token: token,
+			snapshot: things.Snapshot{
+				Things:   []things.Thing{sth, sth2},
+				Channels: []things.Channel{sch},
+				Connections: []things.Connection{
+					{ChanID: sch.ID, ThingID: sth.ID, Owner: email, Access: things.FullAccess},
+					{ChanID: sch.ID, ThingID: sth2.ID, Owner: email, Access: things.PublishAccess},
+				},
+			},
+			err: nil,
+		},
+		{
+			desc:  "export things and channels of user without them",
+			token: "other",
+			snapshot: things.Snapshot{
+				Things:      []things.Thing{},
+				Channels:    []things.Channel{},
+				Connections: []things.Connection{},
+			},
+			err: nil,
+		},
+		{
+			desc:     "export things and channels with wrong credentials",
+			token:    wrongValue,
+			snapshot: things.Snapshot{},
+			err:      things.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		snapshot, err := svc.Export(context.Background(), tc.token)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.snapshot, snapshot, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.snapshot, snapshot))
+	}
+}
+
+func TestImport(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	snapshot := things.Snapshot{
+		Things: []things.Thing{
+			{ID: "thing", Name: "test", Key: "imported-key", State: things.Disabled},
+		},
+		Channels: []things.Channel{
+			{ID: "channel", Name: "test", State: things.Archived},
+		},
+		Connections: []things.Connection{
+			{ChanID: "channel", ThingID: "thing", Access: things.PublishAccess},
+		},
+	}
+
+	dangling := things.Snapshot{
+		Things:      snapshot.Things,
+		Channels:    snapshot.Channels,
+		Connections: []things.Connection{{ChanID: "channel", ThingID: wrongValue}},
+	}
+
+	duplicate := things.Snapshot{
+		Things: []things.Thing{{ID: "thing"}, {ID: "thing"}},
+	}
+
+	cases := []struct {
+		desc     string
+		token    string
+		snapshot things.Snapshot
+		preserve bool
+		key      string
+		err      error
+	}{
+		{
+			desc:     "import snapshot",
+			token:    token,
+			snapshot: snapshot,
+			preserve: false,
+			err:      nil,
+		},
+		{
+			desc:     "import snapshot preserving keys",
+			token:    token,
+			snapshot: snapshot,
+			preserve: true,
+			key:      "imported-key",
+			err:      nil,
+		},
+		{
+			desc:     "import snapshot preserving imported keys",
+			token:    token,
+			snapshot: snapshot,
+			preserve: true,
+			err:      things.ErrConflict,
+		},
+		{
+			desc:     "import snapshot with wrong credentials",
+			token:    wrongValue,
+			snapshot: snapshot,
+			err:      things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:     "import empty snapshot",
+			token:    token,
+			snapshot: things.Snapshot{},
+			err:      things.ErrMalformedEntity,
+		},
+		{
+			desc:     "import snapshot with connection of unknown thing",
+			token:    token,
+			snapshot: dangling,
+			err:      things.ErrMalformedEntity,
+		},
+		{
+			desc:     "import snapshot with duplicate things",
+			token:    token,
+			snapshot: duplicate,
+			err:      things.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		imported, err := svc.Import(context.Background(), tc.token, tc.snapshot, tc.preserve)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err != nil {
+			continue
+		}
+
+		th := imported.Things[0]
+		ch := imported.Channels[0]
+		assert.Equal(t, things.Disabled, th.State, fmt.Sprintf("%s: expected thing state %s got %s\n", tc.desc, things.Disabled, th.State))
+		assert.Equal(t, things.Archived, ch.State, fmt.Sprintf("%s: expected channel state %s got %s\n", tc.desc, things.Archived, ch.State))
+		if tc.key != "" {
+			assert.Equal(t, tc.key, th.Key, fmt.Sprintf("%s: expected key %s got %s\n", tc.desc, tc.key, th.Key))
+		} else {
+			assert.NotEqual(t, "imported-key", th.Key, fmt.Sprintf("%s: expected key to be replaced\n", tc.desc))
+		}
+
+		conn := things.Connection{ChanID: ch.ID, ThingID: th.ID, Owner: email, Access: things.PublishAccess}
+		assert.Equal(t, []things.Connection{conn}, imported.Connections, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, []things.Connection{conn}, imported.Connections))
+	}
+}
+
+func TestCanAccess(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	sth, _ := svc.AddThing(context.Background(), token, thing)
+	sch, _ := svc.CreateChannel(context.Background(), token, channel)
+	svc.Connect(context.Background(), token, []string{sch.ID}, []string{sth.ID}, things.FullAccess)
+
+	cases := map[string]struct {
+		token   string
+		channel string
+		err     error
+	}{
+		"allowed access": {
+			token:   sth.Key,
+			channel: sch.ID,
+			err:     nil,
+		},
+		"not-connected cannot access": {
+			token:   wrongValue,
+			channel: sch.ID,
+			err:     things.ErrUnauthorizedAccess,
+		},
+		"access to non-existing channel": {
+			token:   sth.Key,
+			channel: wrongID,
+			err:     things.ErrUnauthorizedAccess,
+		},
+	}
+
+	for desc, tc := range cases {
+		_, err := svc.CanAccess(context.Background(), tc.channel, tc.token, mainflux.ActionPublish, "")
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
+func TestCanAccessScopedKey(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	sth, _ := svc.AddThing(context.Background(), token, thing)
+	sch, _ := svc.CreateChannel(context.Background(), token, channel)
+	svc.Connect(context.Background(), token, []string{sch.ID}, []string{sth.ID}, things.FullAccess)
+	och, _ := svc.CreateChannel(context.Background(), token, channel)
+	rch, _ := svc.CreateChannel(context.Background(), token, channel)
+	svc.Connect(context.Background(), token, []string{rch.ID}, []string{sth.ID}, things.SubscribeAccess)
+
+	pub, _ := svc.IssueThingKey(context.Background(), token, sth.ID, things.ScopePublish)
+	sub, _ := svc.IssueThingKey(context.Background(), token, sth.ID, things.ScopeSubscribe)
+	admin, _ := svc.IssueThingKey(context.Background(), token, sth.ID, things.ScopeAdmin)
+
+	cases := map[string]struct {
+		key     string
+		channel string
+		action  string
+		err     error
+	}{
+		"publish using publish key": {
+			key:     pub.Key,
+			channel: sch.ID,
+			action:  mainflux.ActionPublish,
+			err:     nil,
+		},
+		"subscribe using publish key": {
+			key:     pub.Key,
+			channel: sch.ID,
+			action:  mainflux.ActionSubscribe,
+			err:     things.ErrUnauthorizedAccess,
+		},
+		"subscribe using subscribe key": {
+			key:     sub.Key,
+			channel: sch.ID,
+			action:  mainflux.ActionSubscribe,
+			err:     nil,
+		},
+		"publish using subscribe key": {
+			key:     sub.Key,
+			channel: sch.ID,
+			action:  mainflux.ActionPublish,
+			err:     things.ErrUnauthorizedAccess,
+		},
+		"access without action using subscribe key": {
+			key:     sub.Key,
+			channel: sch.ID,
+			action:  "",
+			err:     things.ErrUnauthorizedAccess,
+		},
+		"access without action using admin key": {
+			key:     admin.Key,
+			channel: sch.ID,
+			action:  "",
+			err:     nil,
+		},
+		"publish to not connected channel": {
+			key:     pub.Key,
+			channel: och.ID,
+			action:  mainflux.ActionPublish,
+			err:     things.ErrUnauthorizedAccess,
+		},
+		"publish to subscribe-only channel using publish key": {
+			key:     pub.Key,
+			channel: rch.ID,
+			action:  mainflux.ActionPublish,
+			err:     things.ErrUnauthorizedAccess,
+		},
+		"subscribe to subscribe-only channel using subscribe key": {
+			key:     sub.Key,
+			channel: rch.ID,
+			action:  mainflux.ActionSubscribe,
+			err:     nil,
+		},
+	}
+
+	for desc, tc := range cases {
+		id, err := svc.CanAccess(context.Background(), tc.channel, tc.key, tc.action, "")
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+		if err == nil {
+			assert.Equal(t, sth.ID, id, fmt.Sprintf("%s: expected %s got %s\n", desc, sth.ID, id))
+		}
+	}
+}
+
+func TestCanAccessChannelToken(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	sch, _ := svc.CreateChannel(context.Background(), token, channel)
+	och, _ := svc.CreateChannel(context.Background(), token, channel)
+	ach, _ := svc.CreateChannel(context.Background(), token, channel)
+
+	pub, _ := svc.IssueChannelToken(context.Background(), token, sch.ID, things.ScopePublish, time.Hour)
+	sub, _ := svc.IssueChannelToken(context.Background(), token, sch.ID, things.ScopeSubscribe, time.Hour)
+	admin, _ := svc.IssueChannelToken(context.Background(), token, sch.ID, things.ScopeAdmin, time.Hour)
+	archived, _ := svc.IssueChannelToken(context.Background(), token, ach.ID, things.ScopeAdmin, time.Hour)
+	svc.ArchiveChannel(context.Background(), token, ach.ID)
+
+	cases := map[string]struct {
+		token   things.ChannelToken
+		channel string
+		action  string
+		err     error
+	}{
+		"publish using publish token": {
+			token:   pub,
+			channel: sch.ID,
+			action:  mainflux.ActionPublish,
+			err:     nil,
+		},
+		"subscribe using publish token": {
+			token:   pub,
+			channel: sch.ID,
+			action:  mainflux.ActionSubscribe,
+			err:     things.ErrUnauthorizedAccess,
+		},
+		"subscribe using subscribe token": {
+			token:   sub,
+			channel: sch.ID,
+			action:  mainflux.ActionSubscribe,
+			err:     nil,
+		},
+		"publish using subscribe token": {
+			token:   sub,
+			channel: sch.ID,
+			action:  mainflux.ActionPublish,
+			err:     things.ErrUnauthorizedAccess,
+		},
+		"publish using admin token": {
+			token:   admin,
+			channel: sch.ID,
+			action:  mainflux.ActionPublish,
+			err:     nil,
+		},
+		"publish to other channel": {
+			token:   admin,
+			channel: och.ID,
+			action:  mainflux.ActionPublish,
+			err:     things.ErrUnauthorizedAccess,
+		},
+		"publish to archived channel": {
+			token:   archived,
+			channel: ach.ID,
+			action:  mainflux.ActionPublish,
+			err:     things.ErrUnauthorizedAccess,
+		},
+		"subscribe to archived channel": {
+			token:   archived,
+			channel: ach.ID,
+			action:  mainflux.ActionSubscribe,
+			err:     nil,
+		},
+	}
+
+	for desc, tc := range cases {
+		id, err := svc.CanAccess(context.Background(), tc.channel, tc.token.Token, tc.action, "")
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+		if err == nil {
+			assert.Equal(t, tc.token.ID, id, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.token.ID, id))
+		}
+	}
+
+	err := svc.RemoveChannel(context.Background(), token, sch.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	_, err = svc.CanAccess(context.Background(), sch.ID, admin.Token, mainflux.ActionPublish, "")
+	assert.Equal(t, things.ErrUnauthorizedAccess, err, fmt.Sprintf("publish to removed channel: expected %s got %s\n", things.ErrUnauthorizedAccess, err))
+}
+
+func TestCanAccessRestrictedConnection(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	sth, _ := svc.AddThing(context.Background(), token, thing)
+	data, _ := svc.CreateChannel(context.Background(), token, channel)
+	cmds, _ := svc.CreateChannel(context.Background(), token, channel)
+
+	// Connection cached before it was restricted must not grant publishing.
+	err := svc.Connect(context.Background(), token, []string{cmds.ID}, []string{sth.ID}, things.FullAccess)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	_, err = svc.CanAccess(context.Background(), cmds.ID, sth.Key, mainflux.ActionPublish, "")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	err = svc.Connect(context.Background(), token, []string{data.ID}, []string{sth.ID}, things.PublishAccess)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = svc.Connect(context.Background(), token, []string{cmds.ID}, []string{sth.ID}, things.SubscribeAccess)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	_, signed, err := svc.IssueKey(context.Background(), token, sth.ID, time.Hour)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc    string
+		key     string
+		channel string
+		action  string
+		err     error
+	}{
+		{
+			desc:    "publish on publish-only channel",
+			key:     sth.Key,
+			channel: data.ID,
+			action:  mainflux.ActionPublish,
+			err:     nil,
+		},
+		{
+			desc:    "subscribe to publish-only channel",
+			key:     sth.Key,
+			channel: data.ID,
+			action:  mainflux.ActionSubscribe,
+			err:     things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:    "subscribe to subscribe-only channel",
+			key:     sth.Key,
+			channel: cmds.ID,
+			action:  mainflux.ActionSubscribe,
+			err:     nil,
+		},
+		{
+			desc:    "publish on subscribe-only channel",
+			key:     sth.Key,
+			channel: cmds.ID,
+			action:  mainflux.ActionPublish,
+			err:     things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:    "access subscribe-only channel without action",
+			key:     sth.Key,
+			channel: cmds.ID,
+			action:  "",
+			err:     things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:    "publish on subscribe-only channel using signed key",
+			key:     signed,
+			channel: cmds.ID,
+			action:  mainflux.ActionPublish,
+			err:     things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:    "publish on publish-only channel using signed key",
+			key:     signed,
+			channel: data.ID,
+			action:  mainflux.ActionPublish,
+			err:     nil,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := svc.CanAccess(context.Background(), tc.channel, tc.key, tc.action, "")
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	err = svc.Connect(context.Background(), token, []string{cmds.ID}, []string{sth.ID}, things.ConnectionAccess("admin"))
+	assert.Equal(t, things.ErrMalformedEntity, err, fmt.Sprintf("connect with invalid access: expected %s got %s\n", things.ErrMalformedEntity, err))
+}
+
+func TestCanAccessSubtopicRules(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	sth, _ := svc.AddThing(context.Background(), token, thing)
+	other, _ := svc.AddThing(context.Background(), token, thing)
+	sch, _ := svc.CreateChannel(context.Background(), token, channel)
+	err := svc.Connect(context.Background(), token, []string{sch.ID}, []string{sth.ID, other.ID}, things.FullAccess)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	// Connection cached before the rules were added must not bypass them.
+	_, err = svc.CanAccess(context.Background(), sch.ID, sth.Key, mainflux.ActionPublish, "commands")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	rules := []things.SubtopicRule{
+		{ThingID: sth.ID, Action: mainflux.ActionPublish, Pattern: "telemetry.>"},
+		{ThingID: sth.ID, Action: mainflux.ActionSubscribe, Pattern: fmt.Sprintf("commands.%s", sth.ID)},
+	}
+	for _, r := range rules {
+		_, err := svc.AddSubtopicRule(context.Background(), token, sch.ID, r)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	_, signed, err := svc.IssueKey(context.Background(), token, sth.ID, time.Hour)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc     string
+		key      string
+		action   string
+		subtopic string
+		err      error
+	}{
+		{
+			desc:     "publish to allowed subtopic",
+			key:      sth.Key,
+			action:   mainflux.ActionPublish,
+			subtopic: "telemetry.temp",
+			err:      nil,
+		},
+		{
+			desc:     "publish to disallowed subtopic",
+			key:      sth.Key,
+			action:   mainflux.ActionPublish,
+			subtopic: "commands",
+			err:      things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:     "publish without subtopic",
+			key:      sth.Key,
+			action:   mainflux.ActionPublish,
+			subtopic: "",
+			err:      things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:     "access allowed subtopic without action",
+			key:      sth.Key,
+			action:   "",
+			subtopic: "telemetry.temp",
+			err:      nil,
+		},
+		{
+			desc:     "subscribe to allowed subtopic",
+			key:      sth.Key,
+			action:   mainflux.ActionSubscribe,
+			subtopic: fmt.Sprintf("commands.%s", sth.ID),
+			err:      nil,
+		},
+		{
+			desc:     "subscribe to wildcard covering disallowed subtopics",
+			key:      sth.Key,
+			action:   mainflux.ActionSubscribe,
+			subtopic: "commands.*",
+			err:      things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:     "publish to disallowed subtopic using signed key",
+			key:      signed,
+			action:   mainflux.ActionPublish,
+			subtopic: "commands",
+			err:      things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:     "publish to allowed subtopic using signed key",
+			key:      signed,
+			action:   mainflux.ActionPublish,
+			subtopic: "telemetry.temp",
+			err:      nil,
+		},
+		{
+			desc:     "publish to any subtopic as thing without rules",
+			key:      other.Key,
+			action:   mainflux.ActionPublish,
+			subtopic: "commands",
+			err:      nil,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := svc.CanAccess(context.Background(), sch.ID, tc.key, tc.action, tc.subtopic)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestCanRead(t *testing.T) {
+	svc := newService(map[string]string{token: email, "other": "other@example.com"})
+
+	sch, _ := svc.CreateChannel(context.Background(), token, channel)
+	och, _ := svc.CreateChannel(context.Background(), "other", channel)
+
+	cases := map[string]struct {
+		token   string
+		channel string
+		user    string
+		err     error
+	}{
+		"owner can read": {
+			token:   token,
+			channel: sch.ID,
+			user:    email,
+			err:     nil,
+		},
+		"user cannot read other user's channel": {
+			token:   token,
+			channel: och.ID,
+			user:    "",
+			err:     things.ErrUnauthorizedAccess,
+		},
+		"user cannot read non-existing channel": {
+			token:   token,
+			channel: wrongID,
+			user:    "",
+			err:     things.ErrUnauthorizedAccess,
+		},
+		"invalid token cannot read": {
+			token:   wrongValue,
+			channel: sch.ID,
+			user:    "",
+			err:     things.ErrUnauthorizedAccess,
+		},
+	}
+
+	for desc, tc := range cases {
+		user, err := svc.CanRead(context.Background(), tc.channel, tc.token)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+		assert.Equal(t, tc.user, user, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.user, user))
+	}
+}
+
+func TestIdentify(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	sth, _ := svc.AddThing(context.Background(), token, thing)
+
+	cases := map[string]struct {
+		token string
+		id    string
+		err   error
+	}{
+		"identify existing thing": {
+			token: sth.Key,
+			id:    sth.ID,
+			err:   nil,
+		},
+		"identify non-existing thing": {
+			token: wrongValue,
+			id:    wrongID,
+			err:   things.ErrUnauthorizedAccess,
+		},
+	}
+
+	for desc, tc := range cases {
+		id, err := svc.Identify(context.Background(), tc.token)
+		assert.Equal(t, tc.id, id, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.id, id))
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
+func TestIdentifyExpiredCachedKey(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	sth, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	sch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	err = svc.Connect(context.Background(), token, []string{sch.ID}, []string{sth.ID}, things.FullAccess)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	err = svc.UpdateKey(context.Background(), token, sth.ID, "expiring-key", time.Now().Add(100*time.Millisecond), 0)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	// Cache the key before it expires.
+	_, err = svc.Identify(context.Background(), "expiring-key")
+	require.Nil(t, err, fmt.Sprintf("identify before expiration: unexpected error: %s\n", err))
+	_, err = svc.CanAccess(context.Background(), sch.ID, "expiring-key", mainflux.ActionPublish, "")
+	require.Nil(t, err, fmt.Sprintf("access before expiration: unexpected error: %s\n", err))
+
+	time.Sleep(200 * time.Millisecond)
+
+	_, err = svc.Identify(context.Background(), "expiring-key")
+	assert.Equal(t, things.ErrUnauthorizedAccess, err, fmt.Sprintf("identify using expired cached key: expected %s got %s\n", things.ErrUnauthorizedAccess, err))
+	_, err = svc.CanAccess(context.Background(), sch.ID, "expiring-key", mainflux.ActionPublish, "")
+	assert.Equal(t, things.ErrUnauthorizedAccess, err, fmt.Sprintf("access using expired cached key: expected %s got %s\n", things.ErrUnauthorizedAccess, err))
+}
+
+func TestIdentifyBatch(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	th1, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	th2, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	// Identify the first thing so that its key is cached.
+	_, err = svc.Identify(context.Background(), th1.Key)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	tooMany := make([]string, 1001)
+	for i := range tooMany {
+		tooMany[i] = th1.Key
+	}
+
+	cases := map[string]struct {
+		keys []string
+		ids  []string
+		err  error
+	}{
+		"identify batch of existing things": {
+			keys: []string{th1.Key, th2.Key},
+			ids:  []string{th1.ID, th2.ID},
+			err:  nil,
+		},
+		"identify batch with non-existing thing": {
+			keys: []string{th2.Key, wrongValue, th1.Key},
+			ids:  []string{th2.ID, wrongID, th1.ID},
+			err:  nil,
+		},
+		"identify empty batch": {
+			keys: []string{},
+			ids:  nil,
+			err:  things.ErrMalformedEntity,
+		},
+		"identify too large batch": {
+			keys: tooMany,
+			ids:  nil,
+			err:  things.ErrMalformedEntity,
+		},
+	}
+
+	for desc, tc := range cases {
+		ids, err := svc.IdentifyBatch(context.Background(), tc.keys)
+		assert.Equal(t, tc.ids, ids, fmt.Sprintf("%s: expected %v got %v\n", desc, tc.ids, ids))
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
+func TestConnectedChannels(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	th, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	ch1, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	ch2, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	err = svc.Connect(context.Background(), token, []string{ch1.ID}, []string{th.ID}, things.FullAccess)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	chanIDs, err := svc.ConnectedChannels(context.Background(), th.ID)
+	assert.Nil(t, err, fmt.Sprintf("list connected channels: unexpected error %s", err))
+	assert.Equal(t, []string{ch1.ID}, chanIDs, fmt.Sprintf("list connected channels: expected %v got %v", []string{ch1.ID}, chanIDs))
+
+	err = svc.Connect(context.Background(), token, []string{ch2.ID}, []string{th.ID}, things.FullAccess)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	chanIDs, err = svc.ConnectedChannels(context.Background(), th.ID)
+	assert.Nil(t, err, fmt.Sprintf("list channels after connecting: unexpected error %s", err))
+	assert.Equal(t, []string{ch1.ID, ch2.ID}, chanIDs, fmt.Sprintf("list channels after connecting: expected %v got %v", []string{ch1.ID, ch2.ID}, chanIDs))
+
+	err = svc.Disconnect(context.Background(), token, []string{ch1.ID}, []string{th.ID})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	chanIDs, err = svc.ConnectedChannels(context.Background(), th.ID)
+	assert.Nil(t, err, fmt.Sprintf("list channels after disconnecting: unexpected error %s", err))
+	assert.Equal(t, []string{ch2.ID}, chanIDs, fmt.Sprintf("list channels after disconnecting: expected %v got %v", []string{ch2.ID}, chanIDs))
+
+	_, err = svc.ConnectedChannels(context.Background(), "")
+	assert.Equal(t, things.ErrMalformedEntity, err, fmt.Sprintf("list channels of thing with empty id: expected %s got %s", things.ErrMalformedEntity, err))
+}
+
+func TestRemoveUserHandler(t *testing.T) {
+	otherToken := "other-token"
+	otherEmail := "other@example.com"
+	svc := newService(map[string]string{token: email, otherToken: otherEmail})
+
+	sth, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	sch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	err = svc.Connect(context.Background(), token, []string{sch.ID}, []string{sth.ID}, things.FullAccess)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	oth, err := svc.AddThing(context.Background(), otherToken, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	_, signed, err := svc.IssueKey(context.Background(), token, sth.ID, time.Hour)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	ths, chs, err := svc.RemoveUserHandler(context.Background(), email)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.Equal(t, 1, len(ths), fmt.Sprintf("expected 1 removed thing got %d\n", len(ths)))
+	assert.Equal(t, 1, len(chs), fmt.Sprintf("expected 1 removed channel got %d\n", len(chs)))
+
+	_, err = svc.ViewThing(context.Background(), token, sth.ID)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("view removed user's thing: expected %s got %s\n", things.ErrNotFound, err))
+
+	_, err = svc.ViewChannel(context.Background(), token, sch.ID)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("view removed user's channel: expected %s got %s\n", things.ErrNotFound, err))
+
+	_, err = svc.CanAccess(context.Background(), sch.ID, sth.Key, mainflux.ActionPublish, "")
+	assert.Equal(t, things.ErrUnauthorizedAccess, err, fmt.Sprintf("access with removed user's thing: expected %s got %s\n", things.ErrUnauthorizedAccess, err))
+
+	_, err = svc.Identify(context.Background(), signed)
+	assert.Equal(t, things.ErrUnauthorizedAccess, err, fmt.Sprintf("identify with removed user's thing signed key: expected %s got %s\n", things.ErrUnauthorizedAccess, err))
+
+	_, err = svc.ViewThing(context.Background(), otherToken, oth.ID)
+	assert.Nil(t, err, fmt.Sprintf("view other user's thing: unexpected error: %s\n", err))
+}
+
+func TestRecordActivity(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	sth, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	th, err := svc.ViewThing(context.Background(), token, sth.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.True(t, th.LastSeen.IsZero(), "expected thing never to be seen")
+
+	before := time.Now()
+	err = svc.RecordActivity(context.Background(), sth.ID)
+	assert.Nil(t, err, fmt.Sprintf("record activity: unexpected error %s\n", err))
+
+	th, err = svc.ViewThing(context.Background(), token, sth.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.False(t, th.LastSeen.Before(before), fmt.Sprintf("expected thing to be seen after %s got %s\n", before, th.LastSeen))
+
+	// Activity within the resolution doesn't update the thing.
+	seen := th.LastSeen
+	err = svc.RecordActivity(context.Background(), sth.ID)
+	assert.Nil(t, err, fmt.Sprintf("record repeated activity: unexpected error %s\n", err))
+
+	th, err = svc.ViewThing(context.Background(), token, sth.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.Equal(t, seen, th.LastSeen, fmt.Sprintf("expected thing to be seen at %s got %s\n", seen, th.LastSeen))
+
+	err = svc.RecordActivity(context.Background(), "non-existing")
+	assert.Nil(t, err, fmt.Sprintf("record activity of non-existing thing: unexpected error %s\n", err))
+}
+
+func TestRecordDiagnostics(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	sth, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc    string
+		thingID string
+		payload string
+		err     error
+	}{
+		{
+			desc:    "record full report",
+			thingID: sth.ID,
+			payload: `{"battery": 87, "rssi": -71, "firmware": "1.4.2"}`,
+			err:     nil,
+		},
+		{
+			desc:    "record partial report",
+			thingID: sth.ID,
+			payload: `{"battery": 15}`,
+			err:     nil,
+		},
+		{
+			desc:    "record report of non-existing thing",
+			thingID: wrongValue,
+			payload: `{"battery": 15}`,
+			err:     things.ErrNotFound,
+		},
+		{
+			desc:    "record empty report",
+			thingID: sth.ID,
+			payload: `{}`,
+			err:     things.ErrMalformedEntity,
+		},
+		{
+			desc:    "record report with invalid battery level",
+			thingID: sth.ID,
+			payload: `{"battery": 120}`,
+			err:     things.ErrMalformedEntity,
+		},
+		{
+			desc:    "record malformed report",
+			thingID: sth.ID,
+			payload: `battery=15`,
+			err:     things.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.RecordDiagnostics(context.Background(), tc.thingID, []byte(tc.payload))
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	health, err := svc.ViewHealth(context.Background(), token, sth.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	require.NotNil(t, health.Battery, "expected battery level to be reported")
+	require.NotNil(t, health.RSSI, "expected signal strength to be kept")
+	assert.Equal(t, float64(15), *health.Battery, fmt.Sprintf("expected battery level 15 got %f\n", *health.Battery))
+	assert.Equal(t, float64(-71), *health.RSSI, fmt.Sprintf("expected signal strength -71 got %f\n", *health.RSSI))
+	assert.Equal(t, "1.4.2", health.Firmware, fmt.Sprintf("expected firmware 1.4.2 got %s\n", health.Firmware))
+}
+
+func TestViewHealth(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	sth, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	silent, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	err = svc.RecordDiagnostics(context.Background(), sth.ID, []byte(`{"firmware": "1.4.2"}`))
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := map[string]struct {
+		token string
+		id    string
+		err   error
+	}{
+		"view health of existing thing":            {token, sth.ID, nil},
+		"view health of thing that never reported": {token, silent.ID, things.ErrNotFound},
+		"view health of non-existing thing":        {token, wrongValue, things.ErrNotFound},
+		"view health with wrong credentials":       {wrongValue, sth.ID, things.ErrUnauthorizedAccess},
+	}
+
+	for desc, tc := range cases {
+		_, err := svc.ViewHealth(context.Background(), tc.token, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
+func TestListUnhealthy(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	reports := []string{
+		`{"battery": 10, "rssi": -70}`,
+		`{"battery": 90, "rssi": -110}`,
+		`{"battery": 5}`,
+		`{"battery": 90, "rssi": -70}`,
+		`{"firmware": "1.4.2"}`,
+	}
+	for _, report := range reports {
+		sth, err := svc.AddThing(context.Background(), token, thing)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+		err = svc.RecordDiagnostics(context.Background(), sth.ID, []byte(report))
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	}
+
+	defaults := things.HealthThresholds{Battery: things.DefaultMinBattery, RSSI: things.DefaultMinRSSI}
+
+	cases := map[string]struct {
+		token      string
+		thresholds things.HealthThresholds
+		offset     uint64
+		limit      uint64
+		size       uint64
+		total      uint64
+		err        error
+	}{
+		"list unhealthy things": {
+			token:      token,
+			thresholds: defaults,
+			offset:     0,
+			limit:      10,
+			size:       3,
+			total:      3,
+			err:        nil,
+		},
+		"list unhealthy things page": {
+			token:      token,
+			thresholds: defaults,
+			offset:     1,
+			limit:      1,
+			size:       1,
+			total:      3,
+			err:        nil,
+		},
+		"list things with low battery only": {
+			token:      token,
+			thresholds: things.HealthThresholds{Battery: 8, RSSI: -200},
+			offset:     0,
+			limit:      10,
+			size:       1,
+			total:      1,
+			err:        nil,
+		},
+		"list unhealthy things with wrong credentials": {
+			token:      wrongValue,
+			thresholds: defaults,
+			offset:     0,
+			limit:      10,
+			size:       0,
+			total:      0,
+			err:        things.ErrUnauthorizedAccess,
+		},
+	}
+
+	for desc, tc := range cases {
+		page, err := svc.ListUnhealthy(context.Background(), tc.token, tc.thresholds, tc.offset, tc.limit)
+		size := uint64(len(page.Records))
+		assert.Equal(t, tc.size, size, fmt.Sprintf("%s: expected %d got %d\n", desc, tc.size, size))
+		assert.Equal(t, tc.total, page.Total, fmt.Sprintf("%s: expected total %d got %d\n", desc, tc.total, page.Total))
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+
+	page, err := svc.ListUnhealthy(context.Background(), token, defaults, 0, 10)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	require.Len(t, page.Records, 3, fmt.Sprintf("expected 3 records got %d\n", len(page.Records)))
+	assert.Equal(t, float64(5), *page.Records[0].Battery, "expected the lowest battery level to be listed first")
+}
+
+func TestThingUsage(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	sth, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	sch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	n := uint64(10)
+	size := uint64(100)
+	for i := uint64(0); i < n; i++ {
+		err := svc.RecordUsage(context.Background(), sth.ID, sch.ID, size)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	}
+
+	now := time.Now()
+
+	cases := []struct {
+		desc     string
+		token    string
+		id       string
+		from     time.Time
+		to       time.Time
+		messages uint64
+		bytes    uint64
+		err      error
+	}{
+		{
+			desc:     "retrieve thing usage",
+			token:    token,
+			id:       sth.ID,
+			from:     now.Add(-time.Hour),
+			to:       now,
+			messages: n,
+			bytes:    n * size,
+			err:      nil,
+		},
+		{
+			desc:     "retrieve thing usage outside of the period",
+			token:    token,
+			id:       sth.ID,
+			from:     now.Add(-2 * time.Hour),
+			to:       now.Add(-time.Hour),
+			messages: 0,
+			bytes:    0,
+			err:      nil,
+		},
+		{
+			desc:  "retrieve thing usage with invalid period",
+			token: token,
+			id:    sth.ID,
+			from:  now,
+			to:    now.Add(-time.Hour),
+			err:   things.ErrMalformedEntity,
+		},
+		{
+			desc:  "retrieve thing usage with wrong credentials",
+			token: wrongValue,
+			id:    sth.ID,
+			from:  now.Add(-time.Hour),
+			to:    now,
+			err:   things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "retrieve non-existing thing usage",
+			token: token,
+			id:    wrongID,
+			from:  now.Add(-time.Hour),
+			to:    now,
+			err:   things.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		usage, err := svc.ThingUsage(context.Background(), tc.token, tc.id, tc.from, tc.to)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.messages, usage.Messages, fmt.Sprintf("%s: expected %d messages got %d\n", tc.desc, tc.messages, usage.Messages))
+		assert.Equal(t, tc.bytes, usage.Bytes, fmt.Sprintf("%s: expected %d bytes got %d\n", tc.desc, tc.bytes, usage.Bytes))
+	}
+}
+
+func TestChannelUsage(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	sth, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	sch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	n := uint64(10)
+	size := uint64(100)
+	for i := uint64(0); i < n; i++ {
+		err := svc.RecordUsage(context.Background(), sth.ID, sch.ID, size)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	}
+
+	now := time.Now()
+
+	cases := []struct {
+		desc     string
+		token    string
+		id       string
+		from     time.Time
+		to       time.Time
+		messages uint64
+		bytes    uint64
+		err      error
+	}{
+		{
+			desc:     "retrieve channel usage",
+			token:    token,
+			id:       sch.ID,
+			from:     now.Add(-time.Hour),
+			to:       now,
+			messages: n,
+			bytes:    n * size,
+			err:      nil,
+		},
+		{
+			desc:  "retrieve channel usage with too long period",
+			token: token,
+			id:    sch.ID,
+			from:  now.Add(-400 * 24 * time.Hour),
+			to:    now,
+			err:   things.ErrMalformedEntity,
+		},
+		{
+			desc:  "retrieve channel usage with wrong credentials",
+			token: wrongValue,
+			id:    sch.ID,
+			from:  now.Add(-time.Hour),
+			to:    now,
+			err:   things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "retrieve non-existing channel usage",
+			token: token,
+			id:    wrongID,
+			from:  now.Add(-time.Hour),
+			to:    now,
+			err:   things.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		usage, err := svc.ChannelUsage(context.Background(), tc.token, tc.id, tc.from, tc.to)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.messages, usage.Messages, fmt.Sprintf("%s: expected %d messages got %d\n", tc.desc, tc.messages, usage.Messages))
+		assert.Equal(t, tc.bytes, usage.Bytes, fmt.Sprintf("%s: expected %d bytes got %d\n", tc.desc, tc.bytes, usage.Bytes))
+	}
+}
+
+func TestThingHistory(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	sth, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	sth.Name = "updated"
+	err = svc.UpdateThing(context.Background(), token, sth)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	err = svc.UpdateKey(context.Background(), token, sth.ID, "new-key", time.Time{}, 0)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	_, err = svc.PatchThing(context.Background(), token, sth.ID, map[string]interface{}{"metadata": map[string]interface{}{"serial": "123"}})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc   string
+		token  string
+		id     string
+		offset uint64
+		limit  uint64
+		fields []string
+		err    error
+	}{
+		{
+			desc:   "retrieve thing history",
+			token:  token,
+			id:     sth.ID,
+			offset: 0,
+			limit:  10,
+			fields: []string{"metadata", "key", "name"},
+			err:    nil,
+		},
+		{
+			desc:   "retrieve subset of thing history",
+			token:  token,
+			id:     sth.ID,
+			offset: 1,
+			limit:  1,
+			fields: []string{"key"},
+			err:    nil,
+		},
+		{
+			desc:   "retrieve thing history with wrong credentials",
+			token:  wrongValue,
+			id:     sth.ID,
+			offset: 0,
+			limit:  10,
+			err:    things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:   "retrieve history of non-existing thing",
+			token:  token,
+			id:     wrongID,
+			offset: 0,
+			limit:  10,
+			err:    things.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		page, err := svc.ThingHistory(context.Background(), tc.token, tc.id, tc.offset, tc.limit)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		fields := []string{}
+		for _, c := range page.Changes {
+			fields = append(fields, c.Field)
+		}
+		if tc.err == nil {
+			assert.Equal(t, tc.fields, fields, fmt.Sprintf("%s: expected changed fields %v got %v\n", tc.desc, tc.fields, fields))
+		}
+	}
+}
+
+func TestChannelHistory(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	sch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	sch.Name = "updated"
+	err = svc.UpdateChannel(context.Background(), token, sch)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	_, err = svc.PatchChannel(context.Background(), token, sch.ID, map[string]interface{}{"name": "patched"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc  string
+		token string
+		id    string
+		names []string
+		err   error
+	}{
+		{
+			desc:  "retrieve channel history",
+			token: token,
+			id:    sch.ID,
+			names: []string{"patched", "updated"},
+			err:   nil,
+		},
+		{
+			desc:  "retrieve channel history with wrong credentials",
+			token: wrongValue,
+			id:    sch.ID,
+			err:   things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "retrieve history of non-existing channel",
+			token: token,
+			id:    wrongID,
+			err:   things.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		page, err := svc.ChannelHistory(context.Background(), tc.token, tc.id, 0, 10)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		names := []string{}
+		for _, c := range page.Changes {
+			names = append(names, c.NewValue)
+		}
+		if tc.err == nil {
+			assert.Equal(t, tc.names, names, fmt.Sprintf("%s: expected new values %v got %v\n", tc.desc, tc.names, names))
+		}
+	}
+}
+
+func TestAudit(t *testing.T) {
+	managerToken := "manager-token"
+	managerEmail := "manager@example.com"
+	adminEmail := "admin@example.com"
+	svc := newService(map[string]string{token: email, managerToken: managerEmail})
+
+	begin := time.Now().Add(-time.Second)
+
+	sth, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	sch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	err = svc.Share(context.Background(), token, things.Share{Kind: things.ThingKind, EntityID: sth.ID, User: managerEmail, Permission: things.ManagePermission})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	sth.Name = "updated"
+	err = svc.UpdateThing(context.Background(), managerToken, sth)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	// The key is updated by the administrator impersonating the owner.
+	err = svc.UpdateKey(context.Background(), token+"|"+adminEmail, sth.ID, "new-key", time.Time{}, 0)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	err = svc.Connect(context.Background(), token, []string{sch.ID}, []string{sth.ID}, things.FullAccess)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	err = svc.Disconnect(context.Background(), token, []string{sch.ID}, []string{sth.ID})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	err = svc.RemoveChannel(context.Background(), token, sch.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	err = svc.RemoveChannel(context.Background(), token, sch.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc  string
+		token string
+		query things.AuditQuery
+		ops   []string
+		err   error
+	}{
+		{
+			desc:  "retrieve all events",
+			token: token,
+			query: things.AuditQuery{},
+			ops:   []string{"remove", "disconnect", "connect", "update_key", "update", "create", "create"},
+			err:   nil,
+		},
+		{
+			desc:  "retrieve events of the thing",
+			token: token,
+			query: things.AuditQuery{EntityKind: things.ThingKind, EntityID: sth.ID},
+			ops:   []string{"disconnect", "connect", "update_key", "update", "create"},
+			err:   nil,
+		},
+		{
+			desc:  "retrieve events by actor",
+			token: token,
+			query: things.AuditQuery{Actor: managerEmail},
+			ops:   []string{"update"},
+			err:   nil,
+		},
+		{
+			desc:  "retrieve events by impersonating administrator",
+			token: token,
+			query: things.AuditQuery{Actor: adminEmail},
+			ops:   []string{"update_key"},
+			err:   nil,
+		},
+		{
+			desc:  "retrieve events by operation",
+			token: token,
+			query: things.AuditQuery{Operation: things.CreateOperation},
+			ops:   []string{"create", "create"},
+			err:   nil,
+		},
+		{
+			desc:  "retrieve events within time period",
+			token: token,
+			query: things.AuditQuery{From: begin, To: time.Now()},
+			ops:   []string{"remove", "disconnect", "connect", "update_key", "update", "create", "create"},
+			err:   nil,
+		},
+		{
+			desc:  "retrieve events past the time period",
+			token: token,
+			query: things.AuditQuery{To: begin},
+			ops:   []string{},
+			err:   nil,
+		},
+		{
+			desc:  "retrieve events of the entities owned by other user",
+			token: managerToken,
+			query: things.AuditQuery{},
+			ops:   []string{},
+			err:   nil,
+		},
+		{
+			desc:  "retrieve events by unknown operation",
+			token: token,
+			query: things.AuditQuery{Operation: "view"},
+			err:   things.ErrMalformedEntity,
+		},
+		{
+			desc:  "retrieve events by unknown entity kind",
+			token: token,
+			query: things.AuditQuery{EntityKind: "group"},
+			err:   things.ErrMalformedEntity,
+		},
+		{
+			desc:  "retrieve events within inverted time period",
+			token: token,
+			query: things.AuditQuery{From: time.Now(), To: begin},
+			err:   things.ErrMalformedEntity,
+		},
+		{
+			desc:  "retrieve events with wrong credentials",
+			token: wrongValue,
+			query: things.AuditQuery{},
+			err:   things.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		page, err := svc.Audit(context.Background(), tc.token, tc.query, 0, 10)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if tc.err != nil {
+			continue
+		}
+
+		ops := []string{}
+		for _, e := range page.Events {
+			ops = append(ops, e.Operation)
+		}
+		assert.Equal(t, tc.ops, ops, fmt.Sprintf("%s: expected operations %v got %v\n", tc.desc, tc.ops, ops))
+	}
+
+	page, err := svc.Audit(context.Background(), token, things.AuditQuery{EntityKind: things.ThingKind, EntityID: sth.ID}, 0, 10)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	require.Len(t, page.Events, 5)
+
+	disconnect, connect, key, update := page.Events[0], page.Events[1], page.Events[2], page.Events[3]
+	assert.Equal(t, map[string]things.Diff{"channel": {Before: sch.ID}}, disconnect.Diff, "expected disconnected channel to be recorded")
+	assert.Equal(t, map[string]things.Diff{"channel": {After: sch.ID}}, connect.Diff, "expected connected channel to be recorded")
+	assert.Equal(t, map[string]things.Diff{"key": {}}, key.Diff, "expected key value not to be recorded")
+	assert.Equal(t, map[string]things.Diff{"name": {Before: thing.Name, After: "updated"}}, update.Diff, "expected updated name to be recorded")
+	assert.Equal(t, email, update.Owner, fmt.Sprintf("expected owner %s got %s\n", email, update.Owner))
+}
+
+func TestCapabilities(t *testing.T) {
+	readerToken := "reader-token"
+	readerEmail := "reader@example.com"
+	managerToken := "manager-token"
+	managerEmail := "manager@example.com"
+	svc := newService(map[string]string{token: email, readerToken: readerEmail, managerToken: managerEmail})
+
+	sth, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	sch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	for _, share := range []things.Share{
+		{Kind: things.ThingKind, EntityID: sth.ID, User: readerEmail, Permission: things.ReadPermission},
+		{Kind: things.ChannelKind, EntityID: sch.ID, User: managerEmail, Permission: things.ManagePermission},
+	} {
+		err := svc.Share(context.Background(), token, share)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	}
+
+	cases := []struct {
+		desc  string
+		token string
+		kind  string
+		id    string
+		ops   []string
+		err   error
+	}{
+		{
+			desc:  "retrieve account capabilities",
+			token: readerToken,
+			ops:   []string{"create", "view"},
+			err:   nil,
+		},
+		{
+			desc:  "retrieve capabilities of owned thing",
+			token: token,
+			kind:  things.ThingKind,
+			id:    sth.ID,
+			ops:   []string{"view", "update", "connect", "disconnect", "remove", "share", "transfer", "update_key"},
+			err:   nil,
+		},
+		{
+			desc:  "retrieve capabilities of owned channel",
+			token: token,
+			kind:  things.ChannelKind,
+			id:    sch.ID,
+			ops:   []string{"view", "update", "connect", "disconnect", "remove", "share", "transfer"},
+			err:   nil,
+		},
+		{
+			desc:  "retrieve capabilities of thing shared for reading",
+			token: readerToken,
+			kind:  things.ThingKind,
+			id:    sth.ID,
+			ops:   []string{"view"},
+			err:   nil,
+		},
+		{
+			desc:  "retrieve capabilities of channel shared for managing",
+			token: managerToken,
+			kind:  things.ChannelKind,
+			id:    sch.ID,
+			ops:   []string{"view", "update", "connect", "disconnect"},
+			err:   nil,
+		},
+		{
+			desc:  "retrieve capabilities of entity not shared with user",
+			token: managerToken,
+			kind:  things.ThingKind,
+			id:    sth.ID,
+			err:   things.ErrNotFound,
+		},
+		{
+			desc:  "retrieve capabilities of non-existing entity",
+			token: token,
+			kind:  things.ThingKind,
+			id:    "non-existing",
+			err:   things.ErrNotFound,
+		},
+		{
+			desc:  "retrieve capabilities of entity without kind",
+			token: token,
+			id:    sth.ID,
+			err:   things.ErrMalformedEntity,
+		},
+		{
+			desc:  "retrieve capabilities of entity without ID",
+			token: token,
+			kind:  things.ThingKind,
+			err:   things.ErrMalformedEntity,
+		},
+		{
+			desc:  "retrieve capabilities with wrong credentials",
+			token: wrongValue,
+			err:   things.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		caps, err := svc.Capabilities(context.Background(), tc.token, tc.kind, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.ops, caps.Operations, fmt.Sprintf("%s: expected operations %v got %v\n", tc.desc, tc.ops, caps.Operations))
+	}
+}
+
+func TestCreateGroup(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	parent, err := svc.CreateGroup(context.Background(), token, things.Group{Name: "site"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		group things.Group
+		token string
+		err   error
+	}{
+		{
+			desc:  "create new group",
+			group: things.Group{Name: "building"},
+			token: token,
+			err:   nil,
+		},
+		{
+			desc:  "create new subgroup",
+			group: things.Group{Name: "floor", Parent: parent.ID},
+			token: token,
+			err:   nil,
+		},
+		{
+			desc:  "create subgroup of non-existing group",
+			group: things.Group{Name: "floor", Parent: wrongValue},
+			token: token,
+			err:   things.ErrNotFound,
+		},
+		{
+			desc:  "create group with too long name",
+			group: things.Group{Name: strings.Repeat("a", limits.NameLength+1)},
+			token: token,
+			err:   &things.ValidationError{Field: "name", Reason: fmt.Sprintf("exceeds %d characters", limits.NameLength)},
+		},
+		{
+			desc:  "create group with wrong credentials",
+			group: things.Group{Name: "building"},
+			token: wrongValue,
+			err:   things.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := svc.CreateGroup(context.Background(), tc.token, tc.group)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestUpdateGroup(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	saved, err := svc.CreateGroup(context.Background(), token, things.Group{Name: "site"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		group things.Group
+		token string
+		err   error
+	}{
+		{
+			desc:  "update existing group",
+			group: things.Group{ID: saved.ID, Name: "renamed"},
+			token: token,
+			err:   nil,
+		},
+		{
+			desc:  "update group with wrong credentials",
+			group: things.Group{ID: saved.ID, Name: "renamed"},
+			token: wrongValue,
+			err:   things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "update non-existing group",
+			group: things.Group{ID: wrongID, Name: "renamed"},
+			token: token,
+			err:   things.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.UpdateGroup(context.Background(), tc.token, tc.group)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestListGroups(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	parent, err := svc.CreateGroup(context.Background(), token, things.Group{Name: "site"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	n := uint64(5)
+	for i := uint64(0); i < n; i++ {
+		_, err := svc.CreateGroup(context.Background(), token, things.Group{Name: fmt.Sprintf("floor-%d", i), Parent: parent.ID})
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	cases := []struct {
+		desc   string
+		token  string
+		parent string
+		offset uint64
+		limit  uint64
+		size   uint64
+		err    error
+	}{
+		{
+			desc:  "list all groups",
+			token: token,
+			limit: n + 1,
+			size:  n + 1,
+			err:   nil,
+		},
+		{
+			desc:   "list subgroups",
+			token:  token,
+			parent: parent.ID,
+			limit:  n,
+			size:   n,
+			err:    nil,
+		},
+		{
+			desc:   "list last page of subgroups",
+			token:  token,
+			parent: parent.ID,
+			offset: n - 1,
+			limit:  n,
+			size:   1,
+			err:    nil,
+		},
+		{
+			desc:  "list groups with wrong credentials",
+			token: wrongValue,
+			limit: n,
+			size:  0,
+			err:   things.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		page, err := svc.ListGroups(context.Background(), tc.token, tc.parent, tc.offset, tc.limit)
+		size := uint64(len(page.Groups))
+		assert.Equal(t, tc.size, size, fmt.Sprintf("%s: expected %d got %d\n", tc.desc, tc.size, size))
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestRemoveGroup(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	parent, err := svc.CreateGroup(context.Background(), token, things.Group{Name: "site"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	child, err := svc.CreateGroup(context.Background(), token, things.Group{Name: "floor", Parent: parent.ID})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	sth, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = svc.AssignThings(context.Background(), token, child.ID, sth.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	err = svc.RemoveGroup(context.Background(), wrongValue, parent.ID)
+	assert.Equal(t, things.ErrUnauthorizedAccess, err, fmt.Sprintf("remove group with wrong credentials: expected %s got %s\n", things.ErrUnauthorizedAccess, err))
+
+	err = svc.RemoveGroup(context.Background(), token, parent.ID)
+	assert.Nil(t, err, fmt.Sprintf("remove group: unexpected error %s", err))
+
+	_, err = svc.ViewGroup(context.Background(), token, child.ID)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("view subgroup of removed group: expected %s got %s\n", things.ErrNotFound, err))
+
+	_, err = svc.ViewThing(context.Background(), token, sth.ID)
+	assert.Nil(t, err, fmt.Sprintf("view thing of removed group: unexpected error %s", err))
+}
+
+func TestAssignThings(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	gr, err := svc.CreateGroup(context.Background(), token, things.Group{Name: "site"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	sth, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		token  string
+		id     string
+		things []string
+		err    error
+	}{
+		{
+			desc:   "assign thing to group",
+			token:  token,
+			id:     gr.ID,
+			things: []string{sth.ID},
+			err:    nil,
+		},
+		{
+			desc:   "assign thing to group again",
+			token:  token,
+			id:     gr.ID,
+			things: []string{sth.ID},
+			err:    nil,
+		},
+		{
+			desc:   "assign non-existing thing to group",
+			token:  token,
+			id:     gr.ID,
+			things: []string{sth.ID, wrongValue},
+			err:    things.ErrNotFound,
+		},
+		{
+			desc:   "assign thing to non-existing group",
+			token:  token,
+			id:     wrongValue,
+			things: []string{sth.ID},
+			err:    things.ErrNotFound,
+		},
+		{
+			desc:   "assign no things to group",
+			token:  token,
+			id:     gr.ID,
+			things: []string{},
+			err:    things.ErrMalformedEntity,
+		},
+		{
+			desc:   "assign thing with wrong credentials",
+			token:  wrongValue,
+			id:     gr.ID,
+			things: []string{sth.ID},
+			err:    things.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.AssignThings(context.Background(), tc.token, tc.id, tc.things...)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestUnassignThing(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	gr, err := svc.CreateGroup(context.Background(), token, things.Group{Name: "site"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	sth, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = svc.AssignThings(context.Background(), token, gr.ID, sth.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc    string
+		token   string
+		id      string
+		thingID string
+		err     error
+	}{
+		{
+			desc:    "unassign thing with wrong credentials",
+			token:   wrongValue,
+			id:      gr.ID,
+			thingID: sth.ID,
+			err:     things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:    "unassign thing",
+			token:   token,
+			id:      gr.ID,
+			thingID: sth.ID,
+			err:     nil,
+		},
+		{
+			desc:    "unassign unassigned thing",
+			token:   token,
+			id:      gr.ID,
+			thingID: sth.ID,
+			err:     things.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.UnassignThing(context.Background(), tc.token, tc.id, tc.thingID)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestListThingsByGroup(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	parent, err := svc.CreateGroup(context.Background(), token, things.Group{Name: "site"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	child, err := svc.CreateGroup(context.Background(), token, things.Group{Name: "floor", Parent: parent.ID})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	n := uint64(4)
+	for i := uint64(0); i < n; i++ {
+		th, err := svc.AddThing(context.Background(), token, thing)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+		gr := parent
+		if i%2 == 0 {
+			gr = child
+		}
+		err = svc.AssignThings(context.Background(), token, gr.ID, th.ID)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	cases := []struct {
+		desc   string
+		token  string
+		id     string
+		offset uint64
+		limit  uint64
+		size   uint64
+		err    error
+	}{
+		{
+			desc:  "list things of group and its subgroups",
+			token: token,
+			id:    parent.ID,
+			limit: n,
+			size:  n,
+			err:   nil,
+		},
+		{
+			desc:  "list things of subgroup",
+			token: token,
+			id:    child.ID,
+			limit: n,
+			size:  n / 2,
+			err:   nil,
+		},
+		{
+			desc:   "list last page of things of group",
+			token:  token,
+			id:     parent.ID,
+			offset: n - 1,
+			limit:  n,
+			size:   1,
+			err:    nil,
+		},
+		{
+			desc:  "list things of group with wrong credentials",
+			token: wrongValue,
+			id:    parent.ID,
+			limit: n,
+			size:  0,
+			err:   things.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		page, err := svc.ListThingsByGroup(context.Background(), tc.token, tc.id, tc.offset, tc.limit)
+		size := uint64(len(page.Things))
+		assert.Equal(t, tc.size, size, fmt.Sprintf("%s: expected %d got %d\n", tc.desc, tc.size, size))
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestConnectGroup(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	parent, err := svc.CreateGroup(context.Background(), token, things.Group{Name: "site"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	child, err := svc.CreateGroup(context.Background(), token, things.Group{Name: "floor", Parent: parent.ID})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	sch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	th1, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	th2, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = svc.AssignThings(context.Background(), token, parent.ID, th1.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = svc.AssignThings(context.Background(), token, child.ID, th2.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		token  string
+		id     string
+		chanID string
+		things []string
+		err    error
+	}{
+		{
+			desc:   "connect group with wrong credentials",
+			token:  wrongValue,
+			id:     parent.ID,
+			chanID: sch.ID,
+			err:    things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:   "connect non-existing group",
+			token:  token,
+			id:     wrongValue,
+			chanID: sch.ID,
+			err:    things.ErrNotFound,
+		},
+		{
+			desc:   "connect group to non-existing channel",
+			token:  token,
+			id:     parent.ID,
+			chanID: wrongValue,
+			err:    things.ErrNotFound,
+		},
+		{
+			desc:   "connect subgroup",
+			token:  token,
+			id:     child.ID,
+			chanID: sch.ID,
+			things: []string{th2.ID},
+			err:    nil,
+		},
+		{
+			desc:   "connect group with connected subgroup",
+			token:  token,
+			id:     parent.ID,
+			chanID: sch.ID,
+			things: []string{th1.ID},
+			err:    nil,
+		},
+	}
+
+	for _, tc := range cases {
+		ids, err := svc.ConnectGroup(context.Background(), tc.token, tc.id, tc.chanID)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.ElementsMatch(t, tc.things, ids, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.things, ids))
+	}
+}
+
+func TestRegisterSubtopic(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	sch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc     string
+		token    string
+		chanID   string
+		subtopic things.Subtopic
+		err      error
+	}{
+		{
+			desc:     "register subtopic",
+			token:    token,
+			chanID:   sch.ID,
+			subtopic: things.Subtopic{Name: "sensors.temp", Description: "Temperature"},
+			err:      nil,
+		},
+		{
+			desc:     "register registered subtopic",
+			token:    token,
+			chanID:   sch.ID,
+			subtopic: things.Subtopic{Name: "sensors.temp", Description: "Temperature in °C"},
+			err:      nil,
+		},
+		{
+			desc:     "register malformed subtopic",
+			token:    token,
+			chanID:   sch.ID,
+			subtopic: things.Subtopic{Name: "sensors..temp"},
+			err:      things.ErrMalformedEntity,
+		},
+		{
+			desc:     "register subtopic with wrong credentials",
+			token:    wrongValue,
+			chanID:   sch.ID,
+			subtopic: things.Subtopic{Name: "sensors.humidity"},
+			err:      things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:     "register subtopic of non-existing channel",
+			token:    token,
+			chanID:   wrongID,
+			subtopic: things.Subtopic{Name: "sensors.humidity"},
+			err:      things.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.RegisterSubtopic(context.Background(), tc.token, tc.chanID, tc.subtopic)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	subtopics, err := svc.ListSubtopics(context.Background(), token, sch.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	require.Len(t, subtopics, 1, fmt.Sprintf("list subtopics: expected 1 subtopic got %d\n", len(subtopics)))
+	assert.Equal(t, "Temperature in °C", subtopics[0].Description, fmt.Sprintf("list subtopics: expected updated description got %s\n", subtopics[0].Description))
+
+	err = svc.RemoveSubtopic(context.Background(), token, sch.ID, "sensors.temp")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	subtopics, err = svc.ListSubtopics(context.Background(), token, sch.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Empty(t, subtopics, fmt.Sprintf("list subtopics after removal: expected none got %v\n", subtopics))
+}
+
+func TestAddSubtopicRule(t *testing.T) {
+	svc := newService(map[string]string{token: email, "other": "other@example.com"})
+
+	sth, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	foreign, err := svc.AddThing(context.Background(), "other", thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	sch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		token  string
+		chanID string
+		rule   things.SubtopicRule
+		err    error
+	}{
+		{
+			desc:   "add subtopic rule",
+			token:  token,
+			chanID: sch.ID,
+			rule:   things.SubtopicRule{ThingID: sth.ID, Action: mainflux.ActionPublish, Pattern: "telemetry.>"},
+			err:    nil,
+		},
+		{
+			desc:   "add subtopic rule with invalid action",
+			token:  token,
+			chanID: sch.ID,
+			rule:   things.SubtopicRule{ThingID: sth.ID, Action: "admin", Pattern: "telemetry.>"},
+			err:    things.ErrMalformedEntity,
+		},
+		{
+			desc:   "add subtopic rule with malformed pattern",
+			token:  token,
+			chanID: sch.ID,
+			rule:   things.SubtopicRule{ThingID: sth.ID, Action: mainflux.ActionPublish, Pattern: "telemetry.>.temp"},
+			err:    things.ErrMalformedEntity,
+		},
+		{
+			desc:   "add subtopic rule with wrong credentials",
+			token:  wrongValue,
+			chanID: sch.ID,
+			rule:   things.SubtopicRule{ThingID: sth.ID, Action: mainflux.ActionPublish, Pattern: "telemetry.>"},
+			err:    things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:   "add subtopic rule to non-existing channel",
+			token:  token,
+			chanID: wrongID,
+			rule:   things.SubtopicRule{ThingID: sth.ID, Action: mainflux.ActionPublish, Pattern: "telemetry.>"},
+			err:    things.ErrNotFound,
+		},
+		{
+			desc:   "add subtopic rule for thing owned by other user",
+			token:  token,
+			chanID: sch.ID,
+			rule:   things.SubtopicRule{ThingID: foreign.ID, Action: mainflux.ActionPublish, Pattern: "telemetry.>"},
+			err:    things.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := svc.AddSubtopicRule(context.Background(), tc.token, tc.chanID, tc.rule)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	rules, err := svc.ListSubtopicRules(context.Background(), token, sch.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	require.Len(t, rules, 1, fmt.Sprintf("list subtopic rules: expected 1 rule got %d\n", len(rules)))
+	assert.Equal(t, sch.ID, rules[0].Channel, fmt.Sprintf("list subtopic rules: expected channel %s got %s\n", sch.ID, rules[0].Channel))
+
+	err = svc.RemoveSubtopicRule(context.Background(), token, sch.ID, rules[0].ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = svc.RemoveSubtopicRule(context.Background(), token, sch.ID, rules[0].ID)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("remove removed subtopic rule: expected %s got %s\n", things.ErrNotFound, err))
+	rules, err = svc.ListSubtopicRules(context.Background(), token, sch.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Empty(t, rules, fmt.Sprintf("list subtopic rules after removal: expected none got %v\n", rules))
+}
+
+func TestShare(t *testing.T) {
+	otherToken := "other-token"
+	otherEmail := "other@example.com"
+	svc := newService(map[string]string{token: email, otherToken: otherEmail})
+
+	sth, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	sch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		token string
+		share things.Share
+		err   error
+	}{
+		{
+			desc:  "share thing",
+			token: token,
+			share: things.Share{Kind: things.ThingKind, EntityID: sth.ID, User: otherEmail, Permission: things.ReadPermission},
+			err:   nil,
+		},
+		{
+			desc:  "share channel",
+			token: token,
+			share: things.Share{Kind: things.ChannelKind, EntityID: sch.ID, User: otherEmail, Permission: things.ManagePermission},
+			err:   nil,
+		},
+		{
+			desc:  "share thing with wrong credentials",
+			token: wrongValue,
+			share: things.Share{Kind: things.ThingKind, EntityID: sth.ID, User: otherEmail, Permission: things.ReadPermission},
+			err:   things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "share thing with invalid permission",
+			token: token,
+			share: things.Share{Kind: things.ThingKind, EntityID: sth.ID, User: otherEmail, Permission: "admin"},
+			err:   things.ErrMalformedEntity,
+		},
+		{
+			desc:  "share thing with its owner",
+			token: token,
+			share: things.Share{Kind: things.ThingKind, EntityID: sth.ID, User: email, Permission: things.ReadPermission},
+			err:   things.ErrMalformedEntity,
+		},
+		{
+			desc:  "share non-existing thing",
+			token: token,
+			share: things.Share{Kind: things.ThingKind, EntityID: wrongValue, User: otherEmail, Permission: things.ReadPermission},
+			err:   things.ErrNotFound,
+		},
+		{
+			desc:  "share thing of another user",
+			token: otherToken,
+			share: things.Share{Kind: things.ThingKind, EntityID: sth.ID, User: "third@example.com", Permission: things.ReadPermission},
+			err:   things.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.Share(context.Background(), tc.token, tc.share)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	shares, err := svc.ListShares(context.Background(), token, things.ThingKind, sth.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	require.Len(t, shares, 1, fmt.Sprintf("list shares: expected 1 share got %d\n", len(shares)))
+	assert.Equal(t, otherEmail, shares[0].User, fmt.Sprintf("list shares: expected user %s got %s\n", otherEmail, shares[0].User))
+
+	_, err = svc.ListShares(context.Background(), otherToken, things.ThingKind, sth.ID)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("list shares of another user: expected %s got %s\n", things.ErrNotFound, err))
+
+	err = svc.Unshare(context.Background(), token, things.ThingKind, sth.ID, otherEmail)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	shares, err = svc.ListShares(context.Background(), token, things.ThingKind, sth.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Empty(t, shares, fmt.Sprintf("list shares after unsharing: expected none got %v\n", shares))
+}
+
+func TestSharedAccess(t *testing.T) {
+	readerToken := "reader-token"
+	readerEmail := "reader@example.com"
+	managerToken := "manager-token"
+	managerEmail := "manager@example.com"
+	svc := newService(map[string]string{token: email, readerToken: readerEmail, managerToken: managerEmail})
+
+	sth, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	sch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	for _, share := range []things.Share{
+		{Kind: things.ThingKind, EntityID: sth.ID, User: readerEmail, Permission: things.ReadPermission},
+		{Kind: things.ChannelKind, EntityID: sch.ID, User: readerEmail, Permission: things.ReadPermission},
+		{Kind: things.ThingKind, EntityID: sth.ID, User: managerEmail, Permission: things.ManagePermission},
+		{Kind: things.ChannelKind, EntityID: sch.ID, User: managerEmail, Permission: things.ManagePermission},
+	} {
+		err := svc.Share(context.Background(), token, share)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	cases := []struct {
+		desc    string
+		token   string
+		err     error
+		connErr error
+	}{
+		{
+			desc:    "access entities as owner",
+			token:   token,
+			err:     nil,
+			connErr: nil,
+		},
+		{
+			desc:    "access entities with read permission",
+			token:   readerToken,
+			err:     things.ErrNotFound,
+			connErr: connectionError(sch.ID, sth.ID),
+		},
+		{
+			desc:    "access entities with manage permission",
+			token:   managerToken,
+			err:     nil,
+			connErr: nil,
+		},
+	}
+
+	for _, tc := range cases {
+		th, err := svc.ViewThing(context.Background(), tc.token, sth.ID)
+		assert.Nil(t, err, fmt.Sprintf("%s: view thing: unexpected error %s\n", tc.desc, err))
+		assert.Equal(t, tc.token == token, th.Key != "", fmt.Sprintf("%s: view thing: expected key disclosed only to owner\n", tc.desc))
+
+		_, err = svc.ViewChannel(context.Background(), tc.token, sch.ID)
+		assert.Nil(t, err, fmt.Sprintf("%s: view channel: unexpected error %s\n", tc.desc, err))
+
+		tp, err := svc.ListThings(context.Background(), tc.token, 0, 10, "", nil, things.TagFilter{}, "", "")
+		assert.Nil(t, err, fmt.Sprintf("%s: list things: unexpected error %s\n", tc.desc, err))
+		assert.Len(t, tp.Things, 1, fmt.Sprintf("%s: list things: expected 1 thing got %d\n", tc.desc, len(tp.Things)))
+
+		cp, err := svc.ListChannels(context.Background(), tc.token, 0, 10, "", nil, things.TagFilter{}, "")
+		assert.Nil(t, err, fmt.Sprintf("%s: list channels: unexpected error %s\n", tc.desc, err))
+		assert.Len(t, cp.Channels, 1, fmt.Sprintf("%s: list channels: expected 1 channel got %d\n", tc.desc, len(cp.Channels)))
+
+		err = svc.UpdateThing(context.Background(), tc.token, things.Thing{ID: sth.ID, Name: "updated"})
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: update thing: expected %s got %s\n", tc.desc, tc.err, err))
+
+		err = svc.Connect(context.Background(), tc.token, []string{sch.ID}, []string{sth.ID}, things.FullAccess)
+		assert.Equal(t, tc.connErr, err, fmt.Sprintf("%s: connect: expected %s got %s\n", tc.desc, tc.connErr, err))
+	}
+
+	// Removal remains reserved to the owner.
+	err = svc.RemoveThing(context.Background(), managerToken, sth.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	_, err = svc.ViewThing(context.Background(), token, sth.ID)
+	assert.Nil(t, err, fmt.Sprintf("view thing removed by manager: unexpected error %s\n", err))
+
+	// Connecting the managed thing to the manager's own channel mixes the
+	// owners, so it's not allowed.
+	own, err := svc.CreateChannel(context.Background(), managerToken, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = svc.Connect(context.Background(), managerToken, []string{own.ID}, []string{sth.ID}, things.FullAccess)
+	connErr := connectionError(own.ID, sth.ID)
+	assert.Equal(t, connErr, err, fmt.Sprintf("connect to own channel: expected %s got %s\n", connErr, err))
+
+	// Shares are removed along with the user.
+	_, _, err = svc.RemoveUserHandler(context.Background(), readerEmail)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	_, err = svc.ViewThing(context.Background(), readerToken, sth.ID)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("view thing after user removal: expected %s got %s\n", things.ErrNotFound, err))
+}
+
+func TestObservedSubtopics(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	sch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = svc.RegisterSubtopic(context.Background(), token, sch.ID, things.Subtopic{Name: "sensors.*"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	for _, subtopic := range []string{"sensors.temp", "sensors.temp", "debug"} {
+		err := svc.RecordSubtopic(context.Background(), sch.ID, subtopic)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	cases := []struct {
+		desc     string
+		token    string
+		chanID   string
+		observed []things.ObservedSubtopic
+		err      error
+	}{
+		{
+			desc:   "retrieve observed subtopics",
+			token:  token,
+			chanID: sch.ID,
+			observed: []things.ObservedSubtopic{
+				{Name: "debug", Messages: 1, Registered: false},
+				{Name: "sensors.temp", Messages: 2, Registered: true},
+			},
+			err: nil,
+		},
+		{
+			desc:   "retrieve observed subtopics with wrong credentials",
+			token:  wrongValue,
+			chanID: sch.ID,
+			err:    things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:   "retrieve observed subtopics of non-existing channel",
+			token:  token,
+			chanID: wrongID,
+			err:    things.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		observed, err := svc.ObservedSubtopics(context.Background(), tc.token, tc.chanID)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		require.Len(t, observed, len(tc.observed), fmt.Sprintf("%s: expected %d subtopics got %d\n", tc.desc, len(tc.observed), len(observed)))
+		for i, st := range observed {
+			assert.Equal(t, tc.observed[i].Name, st.Name, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.observed[i].Name, st.Name))
+			assert.Equal(t, tc.observed[i].Messages, st.Messages, fmt.Sprintf("%s: expected %d messages got %d\n", tc.desc, tc.observed[i].Messages, st.Messages))
+			assert.Equal(t, tc.observed[i].Registered, st.Registered, fmt.Sprintf("%s: expected registered %t got %t\n", tc.desc, tc.observed[i].Registered, st.Registered))
+		}
+	}
+}
+
+func connectionError(chanID, thingID string) error {
+	return &things.ConnectionError{
+		Failures: []things.ConnectionFailure{
+			{ChanID: chanID, ThingID: thingID, Err: things.ErrNotFound},
+		},
+	}
+}
+
+func TestQuotas(t *testing.T) {
+	svc := newService(map[string]string{token: email, quotaToken: quotaEmail})
+
+	_, err := svc.CreateThings(context.Background(), quotaToken, thing, thing, thing)
+	assert.Equal(t, things.ErrQuotaExceeded, err, fmt.Sprintf("create things over quota: expected %s got %s\n", things.ErrQuotaExceeded, err))
+
+	_, err = svc.CreateThings(context.Background(), quotaToken, thing, thing)
+	assert.Nil(t, err, fmt.Sprintf("create things within quota: unexpected error %s\n", err))
+
+	_, err = svc.AddThing(context.Background(), quotaToken, thing)
+	assert.Equal(t, things.ErrQuotaExceeded, err, fmt.Sprintf("add thing over quota: expected %s got %s\n", things.ErrQuotaExceeded, err))
+
+	_, err = svc.CreateChannel(context.Background(), quotaToken, channel)
+	assert.Nil(t, err, fmt.Sprintf("create channel within quota: unexpected error %s\n", err))
+
+	_, err = svc.CreateChannel(context.Background(), quotaToken, channel)
+	assert.Equal(t, things.ErrQuotaExceeded, err, fmt.Sprintf("create channel over quota: expected %s got %s\n", things.ErrQuotaExceeded, err))
+
+	_, err = svc.AddThing(context.Background(), token, thing)
+	assert.Nil(t, err, fmt.Sprintf("add thing without quota: unexpected error %s\n", err))
+}
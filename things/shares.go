@@ -0,0 +1,93 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package things
+
+import "context"
+
+const (
+	// ThingKind denotes the shared thing.
+	ThingKind = "thing"
+
+	// ChannelKind denotes the shared channel.
+	ChannelKind = "channel"
+)
+
+// Permission represents the access to the shared thing or channel granted
+// to the user.
+type Permission string
+
+const (
+	// ReadPermission allows viewing the entity, its connections, history
+	// and usage.
+	ReadPermission Permission = "read"
+
+	// ManagePermission allows updating the entity and connecting it, on top
+	// of the read permission.
+	ManagePermission Permission = "manage"
+)
+
+// Allows determines whether the permission covers the required one.
+func (p Permission) Allows(required Permission) bool {
+	return p == ManagePermission || p == required
+}
+
+// Share represents the permission on the thing or the channel, granted by
+// its owner to another user. Removing the entity, changing its keys and
+// sharing it further remain reserved to the owner.
+type Share struct {
+	Kind       string
+	EntityID   string
+	Owner      string
+	User       string
+	Permission Permission
+}
+
+// Validate returns an error if share representation is invalid.
+func (s Share) Validate() error {
+	if s.Kind != ThingKind && s.Kind != ChannelKind {
+		return ErrMalformedEntity
+	}
+
+	if s.Permission != ReadPermission && s.Permission != ManagePermission {
+		return ErrMalformedEntity
+	}
+
+	if s.EntityID == "" || s.User == "" || s.User == s.Owner {
+		return ErrMalformedEntity
+	}
+
+	return nil
+}
+
+// ShareRepository specifies a shares persistence API.
+type ShareRepository interface {
+	// Save persists the share, replacing the permission previously
+	// granted to the same user on the same entity.
+	Save(context.Context, Share) error
+
+	// Retrieve retrieves the share of the entity of the given kind having
+	// the provided identifier, granted to the specified user.
+	Retrieve(context.Context, string, string, string) (Share, error)
+
+	// RetrieveAll retrieves the shares of the entity of the given kind
+	// having the provided identifier, that is owned by the specified user.
+	RetrieveAll(context.Context, string, string, string) ([]Share, error)
+
+	// RetrieveByUser retrieves the shares of the entities of the given kind
+	// granted to the specified user.
+	RetrieveByUser(context.Context, string, string) ([]Share, error)
+
+	// Remove removes the share of the entity of the given kind having the
+	// provided identifier, that is owned by the specified user, granted to
+	// the given user.
+	Remove(context.Context, string, string, string, string) error
+
+	// RemoveByUser removes all the shares granted either by or to the
+	// specified user.
+	RemoveByUser(context.Context, string) error
+}
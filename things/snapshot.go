@@ -0,0 +1,81 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package things
+
+const maxSnapshotEntities = 10000
+
+// Snapshot represents all the things and channels of the owner, along with
+// the connections between them, exported in order to be imported into
+// another environment. Connections refer to the things and the channels by
+// their identifiers within the snapshot.
+type Snapshot struct {
+	Things      []Thing
+	Channels    []Channel
+	Connections []Connection
+}
+
+// Validate returns an error if the snapshot is empty or too large, if any
+// of its things or channels is invalid or lacks the identifier, or if any
+// of its connections refers to the thing or the channel it doesn't hold.
+func (s Snapshot) Validate(limits Limits) error {
+	if len(s.Things) == 0 && len(s.Channels) == 0 {
+		return ErrMalformedEntity
+	}
+
+	if len(s.Things) > maxSnapshotEntities || len(s.Channels) > maxSnapshotEntities || len(s.Connections) > maxSnapshotEntities {
+		return ErrMalformedEntity
+	}
+
+	things := map[string]bool{}
+	for _, thing := range s.Things {
+		if thing.ID == "" || things[thing.ID] {
+			return ErrMalformedEntity
+		}
+		things[thing.ID] = true
+
+		switch thing.State {
+		case "", Enabled, Disabled:
+		default:
+			return ErrMalformedEntity
+		}
+
+		if thing.KeyRotation < 0 || (thing.KeyRotation > 0 && thing.KeyRotation < minKeyRotation) {
+			return ErrMalformedEntity
+		}
+
+		if err := thing.Validate(limits); err != nil {
+			return err
+		}
+	}
+
+	channels := map[string]bool{}
+	for _, channel := range s.Channels {
+		if channel.ID == "" || channels[channel.ID] {
+			return ErrMalformedEntity
+		}
+		channels[channel.ID] = true
+
+		switch channel.State {
+		case "", Active, Archived:
+		default:
+			return ErrMalformedEntity
+		}
+
+		if err := channel.Validate(limits); err != nil {
+			return err
+		}
+	}
+
+	for _, conn := range s.Connections {
+		if !things[conn.ThingID] || !channels[conn.ChanID] || !conn.Access.Valid() {
+			return ErrMalformedEntity
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package things
+
+import (
+	"context"
+	"strings"
+
+	"github.com/mainflux/mainflux"
+)
+
+// SubtopicRule limits the subtopics of the channel the thing can publish or
+// subscribe to, e.g. so that the sensor publishes to "telemetry.>" only. The
+// pattern may contain the NATS wildcards, the same as the registered
+// subtopics. Once the thing has a rule for the action on the channel, the
+// action is granted only on the subtopics covered by its rules, while the
+// things without the rules are not limited.
+type SubtopicRule struct {
+	ID      string
+	Channel string
+	Owner   string
+	ThingID string
+	Action  string
+	Pattern string
+}
+
+// Validate returns an error if the rule representation is invalid.
+func (r SubtopicRule) Validate() error {
+	if r.ThingID == "" || !validSubtopic(r.Pattern) {
+		return ErrMalformedEntity
+	}
+
+	if r.Action != mainflux.ActionPublish && r.Action != mainflux.ActionSubscribe {
+		return ErrMalformedEntity
+	}
+
+	return nil
+}
+
+// Covers determines whether the rule pattern covers the provided subtopic.
+// The subtopic may contain the wildcards too, in which case every subtopic
+// it matches has to be matched by the pattern, so that the subscriptions
+// can't be widened past the rule.
+func (r SubtopicRule) Covers(subtopic string) bool {
+	if subtopic == "" {
+		return false
+	}
+
+	pattern := strings.Split(r.Pattern, ".")
+	elems := strings.Split(subtopic, ".")
+
+	for i, p := range pattern {
+		if p == ">" {
+			return len(elems) > i
+		}
+
+		if i >= len(elems) || elems[i] == ">" {
+			return false
+		}
+
+		if p != "*" && p != elems[i] {
+			return false
+		}
+	}
+
+	return len(pattern) == len(elems)
+}
+
+// AllowsSubtopic determines whether the rules grant the action on the
+// subtopic of the channel. The action is granted on any subtopic unless
+// there are rules for it. Access requested without the action is treated as
+// publishing.
+func AllowsSubtopic(rules []SubtopicRule, chanID, action, subtopic string) bool {
+	if action == "" {
+		action = mainflux.ActionPublish
+	}
+
+	limited := false
+	for _, r := range rules {
+		if r.Channel != chanID || r.Action != action {
+			continue
+		}
+
+		if r.Covers(subtopic) {
+			return true
+		}
+		limited = true
+	}
+
+	return !limited
+}
+
+// SubtopicRuleRepository specifies a subtopic rules persistence API.
+type SubtopicRuleRepository interface {
+	// Save persists the rule. Successful operation is indicated by unique
+	// identifier accompanied by nil error response.
+	Save(context.Context, SubtopicRule) (string, error)
+
+	// RetrieveAll retrieves the rules of the channel having the provided
+	// identifier, that is owned by the specified user.
+	RetrieveAll(context.Context, string, string) ([]SubtopicRule, error)
+
+	// RetrieveByThing retrieves the rules of the thing having the provided
+	// identifier, across all the channels.
+	RetrieveByThing(context.Context, string) ([]SubtopicRule, error)
+
+	// Remove removes the rule having the provided identifier from the
+	// channel having the provided identifier, that is owned by the
+	// specified user.
+	Remove(context.Context, string, string, string) error
+}
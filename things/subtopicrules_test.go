@@ -0,0 +1,163 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package things_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/things"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubtopicRuleCovers(t *testing.T) {
+	cases := []struct {
+		desc     string
+		pattern  string
+		subtopic string
+		covers   bool
+	}{
+		{
+			desc:     "literal pattern covers same subtopic",
+			pattern:  "telemetry.temp",
+			subtopic: "telemetry.temp",
+			covers:   true,
+		},
+		{
+			desc:     "literal pattern doesn't cover longer subtopic",
+			pattern:  "telemetry",
+			subtopic: "telemetry.temp",
+			covers:   false,
+		},
+		{
+			desc:     "single wildcard covers any element",
+			pattern:  "commands.*",
+			subtopic: "commands.thing",
+			covers:   true,
+		},
+		{
+			desc:     "single wildcard covers single wildcard",
+			pattern:  "commands.*",
+			subtopic: "commands.*",
+			covers:   true,
+		},
+		{
+			desc:     "literal pattern doesn't cover wildcard",
+			pattern:  "commands.thing",
+			subtopic: "commands.*",
+			covers:   false,
+		},
+		{
+			desc:     "full wildcard covers rest of subtopic",
+			pattern:  "telemetry.>",
+			subtopic: "telemetry.room.temp",
+			covers:   true,
+		},
+		{
+			desc:     "full wildcard covers full wildcard",
+			pattern:  "telemetry.>",
+			subtopic: "telemetry.>",
+			covers:   true,
+		},
+		{
+			desc:     "single wildcard doesn't cover full wildcard",
+			pattern:  "telemetry.*",
+			subtopic: "telemetry.>",
+			covers:   false,
+		},
+		{
+			desc:     "full wildcard doesn't cover parent subtopic",
+			pattern:  "telemetry.>",
+			subtopic: "telemetry",
+			covers:   false,
+		},
+		{
+			desc:     "pattern doesn't cover empty subtopic",
+			pattern:  ">",
+			subtopic: "",
+			covers:   false,
+		},
+	}
+
+	for _, tc := range cases {
+		covers := things.SubtopicRule{Pattern: tc.pattern}.Covers(tc.subtopic)
+		assert.Equal(t, tc.covers, covers, fmt.Sprintf("%s: expected %t got %t\n", tc.desc, tc.covers, covers))
+	}
+}
+
+func TestAllowsSubtopic(t *testing.T) {
+	rules := []things.SubtopicRule{
+		{Channel: "1", Action: mainflux.ActionPublish, Pattern: "telemetry.>"},
+		{Channel: "1", Action: mainflux.ActionPublish, Pattern: "status"},
+		{Channel: "2", Action: mainflux.ActionSubscribe, Pattern: "commands.thing"},
+	}
+
+	cases := []struct {
+		desc     string
+		chanID   string
+		action   string
+		subtopic string
+		allowed  bool
+	}{
+		{
+			desc:     "publish to subtopic covered by first rule",
+			chanID:   "1",
+			action:   mainflux.ActionPublish,
+			subtopic: "telemetry.temp",
+			allowed:  true,
+		},
+		{
+			desc:     "publish to subtopic covered by second rule",
+			chanID:   "1",
+			action:   mainflux.ActionPublish,
+			subtopic: "status",
+			allowed:  true,
+		},
+		{
+			desc:     "publish to subtopic not covered by rules",
+			chanID:   "1",
+			action:   mainflux.ActionPublish,
+			subtopic: "commands",
+			allowed:  false,
+		},
+		{
+			desc:     "access subtopic not covered by rules without action",
+			chanID:   "1",
+			action:   "",
+			subtopic: "commands",
+			allowed:  false,
+		},
+		{
+			desc:     "subscribe to subtopic without subscribe rules",
+			chanID:   "1",
+			action:   mainflux.ActionSubscribe,
+			subtopic: "commands",
+			allowed:  true,
+		},
+		{
+			desc:     "publish on channel without publish rules",
+			chanID:   "2",
+			action:   mainflux.ActionPublish,
+			subtopic: "commands.thing",
+			allowed:  true,
+		},
+		{
+			desc:     "subscribe to subtopic not covered by rules",
+			chanID:   "2",
+			action:   mainflux.ActionSubscribe,
+			subtopic: "commands.other",
+			allowed:  false,
+		},
+	}
+
+	for _, tc := range cases {
+		allowed := things.AllowsSubtopic(rules, tc.chanID, tc.action, tc.subtopic)
+		assert.Equal(t, tc.allowed, allowed, fmt.Sprintf("%s: expected %t got %t\n", tc.desc, tc.allowed, allowed))
+	}
+}
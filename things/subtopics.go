@@ -0,0 +1,132 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package things
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	descriptionField     = "description"
+	maxDescriptionLength = 1024
+
+	// MaxObservedSubtopics is the maximum number of distinct subtopics
+	// recorded per channel, so that the subtopics carrying the unique
+	// values, e.g. timestamps, don't exhaust the storage.
+	MaxObservedSubtopics = 1000
+)
+
+// Subtopic describes the subtopic of the channel things are expected to
+// publish to, e.g. "sensors.temperature". The name may contain the NATS
+// wildcards, "*" matching any single element and ">" matching the rest of
+// the subtopic, so that a single entry describes a family of subtopics.
+type Subtopic struct {
+	Channel     string
+	Owner       string
+	Name        string
+	Description string
+	Schema      map[string]interface{}
+}
+
+// ObservedSubtopic represents the subtopic messages were actually published
+// to, along with the number of messages and the time of the last one.
+// Registered indicates whether the subtopic matches a registered one.
+type ObservedSubtopic struct {
+	Name       string
+	Messages   uint64
+	LastSeen   time.Time
+	Registered bool
+}
+
+// Validate returns an error if subtopic representation is invalid, i.e. if
+// its name is not a valid subtopic or it exceeds the provided limits. The
+// schema is subject to the metadata size limit.
+func (s *Subtopic) Validate(limits Limits) error {
+	if !validSubtopic(s.Name) {
+		return ErrMalformedEntity
+	}
+
+	if utf8.RuneCountInString(s.Description) > maxDescriptionLength {
+		return &ValidationError{
+			Field:  descriptionField,
+			Reason: fmt.Sprintf("exceeds %d characters", maxDescriptionLength),
+		}
+	}
+
+	return limits.validate(s.Name, "", s.Schema)
+}
+
+// Matches determines whether the messages published to the provided
+// subtopic are described by the subtopic.
+func (s Subtopic) Matches(subtopic string) bool {
+	pattern := strings.Split(s.Name, ".")
+	elems := strings.Split(subtopic, ".")
+
+	for i, p := range pattern {
+		if p == ">" {
+			return len(elems) > i
+		}
+
+		if i >= len(elems) || p != "*" && p != elems[i] {
+			return false
+		}
+	}
+
+	return len(pattern) == len(elems)
+}
+
+func validSubtopic(name string) bool {
+	if name == "" {
+		return false
+	}
+
+	elems := strings.Split(name, ".")
+	for i, elem := range elems {
+		switch {
+		case elem == "":
+			return false
+		case elem == ">" && i != len(elems)-1:
+			return false
+		case len(elem) > 1 && strings.ContainsAny(elem, "*>"):
+			return false
+		}
+	}
+
+	return true
+}
+
+// SubtopicRepository specifies a registered subtopics persistence API.
+type SubtopicRepository interface {
+	// Save persists the subtopic, replacing the description and the schema
+	// of the subtopic registered under the same name.
+	Save(context.Context, Subtopic) error
+
+	// RetrieveAll retrieves the subtopics registered for the channel having
+	// the provided identifier, that is owned by the specified user.
+	RetrieveAll(context.Context, string, string) ([]Subtopic, error)
+
+	// Remove removes the subtopic having the provided name from the channel
+	// having the provided identifier, that is owned by the specified user.
+	Remove(context.Context, string, string, string) error
+}
+
+// ObservationRepository specifies a persistence API of the subtopics
+// observed in traffic.
+type ObservationRepository interface {
+	// Observe records the message published to the channel having the
+	// provided identifier and to the given subtopic, at the given time.
+	Observe(context.Context, string, string, time.Time) error
+
+	// RetrieveAll retrieves the subtopics observed in the traffic of the
+	// channel having the provided identifier.
+	RetrieveAll(context.Context, string) ([]ObservedSubtopic, error)
+}
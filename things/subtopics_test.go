@@ -0,0 +1,135 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package things_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mainflux/mainflux/things"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubtopicValidate(t *testing.T) {
+	limits := things.Limits{NameLength: 16, MetadataSize: 16}
+
+	cases := []struct {
+		desc     string
+		subtopic things.Subtopic
+		err      error
+	}{
+		{
+			desc:     "validate subtopic",
+			subtopic: things.Subtopic{Name: "sensors.temp", Description: "Temperature in °C"},
+			err:      nil,
+		},
+		{
+			desc:     "validate subtopic with wildcards",
+			subtopic: things.Subtopic{Name: "sensors.*.>"},
+			err:      nil,
+		},
+		{
+			desc:     "validate subtopic without name",
+			subtopic: things.Subtopic{},
+			err:      things.ErrMalformedEntity,
+		},
+		{
+			desc:     "validate subtopic with empty element",
+			subtopic: things.Subtopic{Name: "sensors..temp"},
+			err:      things.ErrMalformedEntity,
+		},
+		{
+			desc:     "validate subtopic with partial wildcard",
+			subtopic: things.Subtopic{Name: "sensors.temp*"},
+			err:      things.ErrMalformedEntity,
+		},
+		{
+			desc:     "validate subtopic with non-trailing full wildcard",
+			subtopic: things.Subtopic{Name: "sensors.>.temp"},
+			err:      things.ErrMalformedEntity,
+		},
+		{
+			desc:     "validate subtopic with too long name",
+			subtopic: things.Subtopic{Name: "sensors.temperature"},
+			err:      &things.ValidationError{Field: "name", Reason: "exceeds 16 characters"},
+		},
+		{
+			desc:     "validate subtopic with too long description",
+			subtopic: things.Subtopic{Name: "temp", Description: strings.Repeat("d", 1025)},
+			err:      &things.ValidationError{Field: "description", Reason: "exceeds 1024 characters"},
+		},
+		{
+			desc:     "validate subtopic with too large schema",
+			subtopic: things.Subtopic{Name: "temp", Schema: map[string]interface{}{"type": "number"}},
+			err:      &things.ValidationError{Field: "metadata", Reason: "exceeds 16 bytes"},
+		},
+	}
+
+	for _, tc := range cases {
+		err := tc.subtopic.Validate(limits)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestSubtopicMatches(t *testing.T) {
+	cases := []struct {
+		desc     string
+		name     string
+		subtopic string
+		matches  bool
+	}{
+		{
+			desc:     "match equal subtopic",
+			name:     "sensors.temp",
+			subtopic: "sensors.temp",
+			matches:  true,
+		},
+		{
+			desc:     "match different subtopic",
+			name:     "sensors.temp",
+			subtopic: "sensors.humidity",
+			matches:  false,
+		},
+		{
+			desc:     "match longer subtopic",
+			name:     "sensors.temp",
+			subtopic: "sensors.temp.raw",
+			matches:  false,
+		},
+		{
+			desc:     "match subtopic using single element wildcard",
+			name:     "sensors.*.temp",
+			subtopic: "sensors.1.temp",
+			matches:  true,
+		},
+		{
+			desc:     "match shorter subtopic using single element wildcard",
+			name:     "sensors.*",
+			subtopic: "sensors",
+			matches:  false,
+		},
+		{
+			desc:     "match subtopic using full wildcard",
+			name:     "sensors.>",
+			subtopic: "sensors.1.temp",
+			matches:  true,
+		},
+		{
+			desc:     "match subtopic without elements using full wildcard",
+			name:     "sensors.>",
+			subtopic: "sensors",
+			matches:  false,
+		},
+	}
+
+	for _, tc := range cases {
+		matches := things.Subtopic{Name: tc.name}.Matches(tc.subtopic)
+		assert.Equal(t, tc.matches, matches, fmt.Sprintf("%s: expected %t got %t\n", tc.desc, tc.matches, matches))
+	}
+}
@@ -0,0 +1,63 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package things
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	tagsField    = "tags"
+	maxTagLength = 64
+)
+
+// TagFilter filters things and channels by their tags. The entities holding
+// all the provided tags are matched, unless Any is set, in which case holding
+// any of them is enough. The filter without tags matches all the entities.
+type TagFilter struct {
+	Tags []string
+	Any  bool
+}
+
+// Matches determines whether the entity holding the provided tags is
+// matched by the filter.
+func (f TagFilter) Matches(tags []string) bool {
+	if len(f.Tags) == 0 {
+		return true
+	}
+
+	held := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		held[tag] = true
+	}
+
+	for _, tag := range f.Tags {
+		if held[tag] == f.Any {
+			return f.Any
+		}
+	}
+
+	return !f.Any
+}
+
+// validateTags rejects the empty and too long tags, along with the ones
+// holding commas, since tags are filtered by the comma-separated list.
+func validateTags(tags []string) error {
+	for _, tag := range tags {
+		if tag == "" || utf8.RuneCountInString(tag) > maxTagLength || strings.Contains(tag, ",") {
+			return &ValidationError{
+				Field:  tagsField,
+				Reason: fmt.Sprintf("must hold tags of 1 to %d characters without commas", maxTagLength),
+			}
+		}
+	}
+
+	return nil
+}
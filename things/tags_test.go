@@ -0,0 +1,57 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package things_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux/things"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTagFilterMatches(t *testing.T) {
+	tags := []string{"edge", "v2"}
+
+	cases := []struct {
+		desc    string
+		filter  things.TagFilter
+		matches bool
+	}{
+		{
+			desc:    "match empty filter",
+			filter:  things.TagFilter{},
+			matches: true,
+		},
+		{
+			desc:    "match all held tags",
+			filter:  things.TagFilter{Tags: []string{"edge", "v2"}},
+			matches: true,
+		},
+		{
+			desc:    "match all tags with one missing",
+			filter:  things.TagFilter{Tags: []string{"edge", "v3"}},
+			matches: false,
+		},
+		{
+			desc:    "match any tag with one held",
+			filter:  things.TagFilter{Tags: []string{"edge", "v3"}, Any: true},
+			matches: true,
+		},
+		{
+			desc:    "match any tag with none held",
+			filter:  things.TagFilter{Tags: []string{"cloud", "v3"}, Any: true},
+			matches: false,
+		},
+	}
+
+	for _, tc := range cases {
+		matches := tc.filter.Matches(tags)
+		assert.Equal(t, tc.matches, matches, fmt.Sprintf("%s: expected %t got %t\n", tc.desc, tc.matches, matches))
+	}
+}
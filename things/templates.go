@@ -0,0 +1,86 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package things
+
+import (
+	"context"
+	"strings"
+)
+
+const maxTemplateChannels = 10
+
+// ChannelTemplate describes a channel created for each thing added by the
+// template owner. The {id} and {name} placeholders in the channel name are
+// replaced with the ID and the name of the thing.
+type ChannelTemplate struct {
+	Name     string
+	Metadata map[string]interface{}
+}
+
+// Template represents the owner's policy of provisioning things, i.e. the
+// channels (e.g. the data and the control channel) that are created and
+// connected to each thing the owner adds.
+type Template struct {
+	Owner    string
+	Channels []ChannelTemplate
+}
+
+// Validate returns an error if the template is empty, has too many channels,
+// or if any of its channels exceeds the provided limits or holds a malformed
+// adapter section in its metadata.
+func (t Template) Validate(limits Limits) error {
+	if len(t.Channels) == 0 || len(t.Channels) > maxTemplateChannels {
+		return ErrMalformedEntity
+	}
+
+	for _, ct := range t.Channels {
+		ch := Channel{Name: ct.Name, Metadata: ct.Metadata}
+		if err := ch.Validate(limits); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (ct ChannelTemplate) channel(thing Thing) Channel {
+	r := strings.NewReplacer("{id}", thing.ID, "{name}", thing.Name)
+
+	return Channel{
+		Owner:    thing.Owner,
+		Name:     r.Replace(ct.Name),
+		Metadata: ct.Metadata,
+	}
+}
+
+// Topology represents a provisioned thing along with the channels that were
+// created for it and it was connected to. Connected holds the existing
+// channels of the thing's profile it was connected to, and Bootstrap the
+// content of the bootstrap configuration it is given by the profile.
+// Existing is set if the owner already had the thing of the same external
+// ID, that is returned without provisioning anything.
+type Topology struct {
+	Thing     Thing
+	Channels  []Channel
+	Connected []Channel
+	Bootstrap string
+	Existing  bool
+}
+
+// TemplateRepository specifies a provisioning template persistence API.
+type TemplateRepository interface {
+	// Save persists the template, replacing the one of the same owner, if
+	// any. A non-nil error is returned to indicate operation failure.
+	Save(context.Context, Template) error
+
+	// Retrieve retrieves the template of the specified user.
+	Retrieve(context.Context, string) (Template, error)
+
+	// Remove removes the template of the specified user.
+	Remove(context.Context, string) error
+}
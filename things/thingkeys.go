@@ -0,0 +1,92 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package things
+
+import (
+	"context"
+	"time"
+
+	"github.com/mainflux/mainflux"
+)
+
+// KeyScope represents the actions the thing key grants access for.
+type KeyScope string
+
+const (
+	// ScopeAdmin grants access for all the actions, same as the thing's
+	// primary key.
+	ScopeAdmin KeyScope = "admin"
+
+	// ScopePublish grants access for publishing only.
+	ScopePublish KeyScope = "publish"
+
+	// ScopeSubscribe grants access for subscribing only.
+	ScopeSubscribe KeyScope = "subscribe"
+)
+
+// Valid returns true if the scope is one of the supported scopes.
+func (s KeyScope) Valid() bool {
+	switch s {
+	case ScopeAdmin, ScopePublish, ScopeSubscribe:
+		return true
+	default:
+		return false
+	}
+}
+
+// Allows returns true if the scope grants access for the action. Access
+// requested without the action is granted to the admin scope only, so that
+// the adapters that don't provide the action can't be used to bypass the
+// scope.
+func (s KeyScope) Allows(action string) bool {
+	switch s {
+	case ScopeAdmin:
+		return true
+	case ScopePublish:
+		return action == mainflux.ActionPublish
+	case ScopeSubscribe:
+		return action == mainflux.ActionSubscribe
+	default:
+		return false
+	}
+}
+
+// ThingKey represents the additional, scoped key of the thing. A thing can
+// hold several keys, e.g. one per integration, so that a compromised key is
+// rotated without changing the others.
+type ThingKey struct {
+	ID       string
+	ThingID  string
+	Owner    string
+	Key      string
+	Scope    KeyScope
+	IssuedAt time.Time
+}
+
+// ThingKeyRepository specifies a scoped thing keys persistence API.
+type ThingKeyRepository interface {
+	// Save persists the thing key. Successful operation is indicated by
+	// unique identifier accompanied by nil error response.
+	Save(context.Context, ThingKey) (string, error)
+
+	// RetrieveAll retrieves the keys of the thing having the provided
+	// identifier, that is owned by the specified user.
+	RetrieveAll(context.Context, string, string) ([]ThingKey, error)
+
+	// RetrieveByKey retrieves the thing key having the provided value.
+	RetrieveByKey(context.Context, string) (ThingKey, error)
+
+	// HasThing retrieves the thing key having the provided value, along
+	// with the access of the connection, if its enabled thing is connected
+	// to the specified channel.
+	HasThing(context.Context, string, string) (ThingKey, ConnectionAccess, error)
+
+	// Remove removes the key having the provided identifier from the keys
+	// of the thing, that is owned by the specified user.
+	Remove(context.Context, string, string, string) error
+}
@@ -7,69 +7,250 @@
 
 package things
 
+import (
+	"context"
+	"fmt"
+	"time"
+	"unicode/utf8"
+)
+
+// State represents the lifecycle state of the thing or the channel.
+type State string
+
+const (
+	// Enabled is the state of the thing that can access its channels.
+	Enabled State = "enabled"
+
+	// Disabled is the state of the thing that is denied access, but keeps
+	// its key, connections and history so that it can be enabled again.
+	Disabled State = "disabled"
+
+	// Deleted is the state of the removed thing. Deleted things are kept
+	// only for the record and are never retrieved.
+	Deleted State = "deleted"
+)
+
+// Status represents the connectivity status of the thing, determined by the
+// time it was last seen publishing.
+type Status string
+
+const (
+	// Online is the status of the thing seen publishing recently.
+	Online Status = "online"
+
+	// Stale is the status of the thing not seen publishing recently,
+	// including the thing that never published.
+	Stale Status = "stale"
+)
+
+// LastSeenResolution is the resolution the time the thing was last seen at
+// is recorded with, so that the things publishing frequently don't update
+// their records on every message.
+const LastSeenResolution = time.Minute
+
+const (
+	externalIDField     = "external_id"
+	maxExternalIDLength = 254
+)
+
 // Thing represents a Mainflux thing. Each thing is owned by one user, and
 // it is assigned with the unique identifier and (temporary) access key.
+// The key expires at KeyExpiresAt, unless it's zero. Once the key expires,
+// the thing having the non-zero KeyRotation is issued the new key, valid
+// for the rotation period. LastSeen is the time the thing last published a
+// message at, zero if it never did. ExternalID is the optional identifier
+// assigned to the thing by the owner, unique among the owner's things.
 type Thing struct {
-	ID       string
-	Owner    string
-	Name     string
-	Key      string
-	Metadata map[string]interface{}
+	ID           string
+	Owner        string
+	ExternalID   string
+	Name         string
+	Key          string
+	KeyExpiresAt time.Time
+	KeyRotation  time.Duration
+	State        State
+	Tags         []string
+	Metadata     map[string]interface{}
+	LastSeen     time.Time
+}
+
+// KeyExpired returns true if the thing key is expired at the provided time.
+func (c Thing) KeyExpired(t time.Time) bool {
+	return !c.KeyExpiresAt.IsZero() && !c.KeyExpiresAt.After(t)
+}
+
+// StatusFilter filters things by their connectivity status. The things seen
+// at or after Since are online, while the others are stale. The filter
+// without status matches all the things.
+type StatusFilter struct {
+	Status Status
+	Since  time.Time
+}
+
+// Matches determines whether the thing last seen at the provided time is
+// matched by the filter.
+func (f StatusFilter) Matches(lastSeen time.Time) bool {
+	switch f.Status {
+	case Online:
+		return !lastSeen.IsZero() && !lastSeen.Before(f.Since)
+	case Stale:
+		return lastSeen.IsZero() || lastSeen.Before(f.Since)
+	default:
+		return true
+	}
 }
 
 // ThingsPage contains page related metadata as well as list of things that
-// belong to this page.
+// belong to this page. Cursor points past the last thing of the page, unless
+// it's the last page.
 type ThingsPage struct {
 	PageMetadata
 	Things []Thing
+	Cursor string
 }
 
-// Validate returns an error if thing representation is invalid.
-func (c *Thing) Validate() error {
-	return nil
+// Validate returns an error if thing representation is invalid, i.e. if
+// it exceeds the provided limits, holds an empty or too long tag or too long
+// external ID, or its metadata holds a malformed adapter section.
+func (c *Thing) Validate(limits Limits) error {
+	if err := limits.validate(c.Name, c.Key, c.Metadata); err != nil {
+		return err
+	}
+
+	if utf8.RuneCountInString(c.ExternalID) > maxExternalIDLength {
+		return &ValidationError{
+			Field:  externalIDField,
+			Reason: fmt.Sprintf("exceeds %d characters", maxExternalIDLength),
+		}
+	}
+
+	if err := validateTags(c.Tags); err != nil {
+		return err
+	}
+
+	return validateThingMetadata(c.Metadata)
 }
 
 // ThingRepository specifies a thing persistence API.
 type ThingRepository interface {
-	// Save persists the thing. Successful operation is indicated by non-nil
-	// error response.
-	Save(Thing) (string, error)
+	// Save persists the thing, consuming the reservation of its identifier,
+	// if any, in the same transaction. Successful operation is indicated by
+	// non-nil error response.
+	Save(context.Context, Thing) (string, error)
+
+	// SaveAll persists all the things in a single transaction, so either
+	// all of them or none are saved, consuming the reservations of their
+	// identifiers as well. Identifiers of the saved things are returned in
+	// the given order.
+	SaveAll(context.Context, ...Thing) ([]string, error)
 
 	// Update performs an update to the existing thing. A non-nil error is
 	// returned to indicate operation failure.
-	Update(Thing) error
+	Update(context.Context, Thing) error
 
-	// UpdateKey updates key value of the existing thing. A non-nil error is
-	// returned to indicate operation failure.
-	UpdateKey(string, string, string) error
+	// UpdateKey updates key value of the existing thing, along with the key
+	// expiration time and rotation period. A non-nil error is returned to
+	// indicate operation failure.
+	UpdateKey(context.Context, Thing) error
+
+	// RotateKey replaces the key of the existing thing with the provided
+	// thing's key and expiration time, provided that the thing still holds
+	// the given key. Otherwise, ErrNotFound is returned, so the key is
+	// rotated once even if several instances attempt the rotation.
+	RotateKey(context.Context, Thing, string) error
+
+	// Patch applies the JSON merge patch to the thing having the provided
+	// identifier, that is owned by the specified user, and returns the
+	// patched thing.
+	Patch(context.Context, string, string, map[string]interface{}) (Thing, error)
+
+	// UpdateMetadata applies the metadata merge patch to all the things
+	// owned by the specified user whose metadata contains the provided
+	// selector, and returns the updated things. All the things are updated
+	// atomically.
+	UpdateMetadata(context.Context, string, map[string]interface{}, map[string]interface{}) ([]Thing, error)
 
 	// RetrieveByID retrieves the thing having the provided identifier, that is owned
 	// by the specified user.
-	RetrieveByID(string, string) (Thing, error)
+	RetrieveByID(context.Context, string, string) (Thing, error)
+
+	// RetrieveByExternalID retrieves the thing of the specified user having
+	// the provided external ID.
+	RetrieveByExternalID(context.Context, string, string) (Thing, error)
+
+	// UpdateState sets the state of the thing having the provided
+	// identifier, that is owned by the specified user.
+	UpdateState(context.Context, string, string, State) error
+
+	// UpdateLastSeen records the thing having the provided identifier as
+	// seen at the given time, unless it was seen within LastSeenResolution
+	// before that time, or later. Unknown things are ignored.
+	UpdateLastSeen(context.Context, string, time.Time) error
 
-	// RetrieveByKey returns thing ID for given thing key.
-	RetrieveByKey(string) (string, error)
+	// RetrieveByKey retrieves the enabled thing having the given key,
+	// unless the key is expired.
+	RetrieveByKey(context.Context, string) (Thing, error)
 
-	// RetrieveAll retrieves the subset of things owned by the specified user.
-	RetrieveAll(string, uint64, uint64, string) (ThingsPage, error)
+	// RetrieveExpired retrieves the enabled things whose keys expired
+	// within the provided time range, along with the things having the
+	// rotation period whose keys expired before the end of the range.
+	RetrieveExpired(context.Context, time.Time, time.Time) ([]Thing, error)
+
+	// RetrieveOwner returns the owner of the thing having the provided
+	// identifier.
+	RetrieveOwner(context.Context, string) (string, error)
+
+	// RetrieveAll retrieves the subset of things owned by the specified user,
+	// optionally filtered by name, by metadata containment, by tags and by
+	// connectivity status. The things are retrieved past the thing having
+	// the last provided identifier, if set, ignoring the offset.
+	RetrieveAll(context.Context, string, uint64, uint64, string, map[string]interface{}, TagFilter, StatusFilter, string) (ThingsPage, error)
+
+	// RetrieveAccessible retrieves the subset of things either owned by the
+	// specified user or having one of the provided identifiers, optionally
+	// filtered by name, by metadata containment, by tags and by
+	// connectivity status. The things are retrieved past the thing having
+	// the last provided identifier, if set, ignoring the offset.
+	RetrieveAccessible(context.Context, string, []string, uint64, uint64, string, map[string]interface{}, TagFilter, StatusFilter, string) (ThingsPage, error)
+
+	// CountAccessible returns the number of things either owned by the
+	// specified user or having one of the provided identifiers, filtered
+	// the same way RetrieveAccessible filters them.
+	CountAccessible(context.Context, string, []string, string, map[string]interface{}, TagFilter, StatusFilter) (uint64, error)
 
 	// RetrieveByChannel retrieves the subset of things owned by the specified
 	// user and connected to specified channel.
-	RetrieveByChannel(string, string, uint64, uint64) (ThingsPage, error)
+	RetrieveByChannel(context.Context, string, string, uint64, uint64) (ThingsPage, error)
 
-	// Remove removes the thing having the provided identifier, that is owned
-	// by the specified user.
-	Remove(string, string) error
+	// Transfer reassigns the thing having the provided identifier, that is
+	// owned by the specified user, to the new owner. The group assignments
+	// and the shares of the thing are dropped, along with its connections
+	// unless they are kept. The thing is transferred atomically.
+	Transfer(context.Context, string, string, string, bool) error
+
+	// Remove marks the thing having the provided identifier, that is owned
+	// by the specified user, as deleted and disconnects it from all the
+	// channels.
+	Remove(context.Context, string, string) error
 }
 
 // ThingCache contains thing caching interface.
 type ThingCache interface {
-	// Save stores pair thing key, thing id.
-	Save(string, string) error
+	// Save stores pair thing key, thing id. The pair is kept until the
+	// provided time the key expires at, unless the time is zero.
+	Save(context.Context, string, string, time.Time) error
 
 	// ID returns thing ID for given key.
-	ID(string) (string, error)
+	ID(context.Context, string) (string, error)
+
+	// IDs returns the IDs of the things having the given keys, in the order
+	// of the keys. The IDs of the keys that aren't cached are empty.
+	IDs(context.Context, []string) ([]string, error)
+
+	// SaveTransform caches the thing's payload transformation for the
+	// adapters. The zero transformation removes the cached one.
+	SaveTransform(context.Context, string, Transform) error
 
 	// Removes thing from cache.
-	Remove(string) error
+	Remove(context.Context, string) error
 }
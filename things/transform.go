@@ -0,0 +1,177 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package things
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// TransformKey is the thing and channel metadata key reserved for the
+// payload transformation, that's applied by the adapters.
+const TransformKey = "transform"
+
+const (
+	openDelim  = "{{"
+	closeDelim = "}}"
+)
+
+// ErrTransformFailed indicates that the payload can't be transformed using
+// the template, either because it's not JSON or because it lacks the value
+// one of the placeholders refers to.
+var ErrTransformFailed = errors.New("payload doesn't match the transformation template")
+
+// Transform represents the reserved transform section of the thing or the
+// channel metadata, used to adapt the payloads of the devices that can't be
+// updated to the format expected by the platform. Template is the payload
+// the adapters publish in place of the received JSON one, with each {{path}}
+// placeholder replaced by the JSON encoding of the value the dot separated
+// path selects, numeric path elements indexing arrays. The {{}} placeholder
+// stands for the entire received payload. ContentType, if set, replaces the
+// content type of the transformed messages. The thing's transformation takes
+// precedence over the channel's one.
+type Transform struct {
+	Template    string `json:"template"`
+	ContentType string `json:"contentType,omitempty"`
+}
+
+type segment struct {
+	text string
+	path []string
+	// placeholder distinguishes the {{}} placeholder from the empty text.
+	placeholder bool
+}
+
+func (t Transform) validate() error {
+	if t.Template == "" {
+		return ErrMalformedEntity
+	}
+
+	if _, err := parseTemplate(t.Template); err != nil {
+		return ErrMalformedEntity
+	}
+
+	return nil
+}
+
+// Apply returns the payload produced by the template out of the provided
+// JSON payload.
+func (t Transform) Apply(payload []byte) ([]byte, error) {
+	segments, err := parseTemplate(t.Template)
+	if err != nil {
+		return nil, ErrTransformFailed
+	}
+
+	var doc interface{}
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return nil, ErrTransformFailed
+	}
+
+	var buf bytes.Buffer
+	for _, s := range segments {
+		if !s.placeholder {
+			buf.WriteString(s.text)
+			continue
+		}
+
+		val, ok := lookup(doc, s.path)
+		if !ok {
+			return nil, ErrTransformFailed
+		}
+
+		data, err := json.Marshal(val)
+		if err != nil {
+			return nil, ErrTransformFailed
+		}
+		buf.Write(data)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Transform returns the transform section of the thing metadata.
+func (t Thing) Transform() (Transform, error) {
+	var tr Transform
+	err := decodeSection(t.Metadata, TransformKey, &tr)
+	return tr, err
+}
+
+// Transform returns the transform section of the channel metadata.
+func (c Channel) Transform() (Transform, error) {
+	var tr Transform
+	err := decodeSection(c.Metadata, TransformKey, &tr)
+	return tr, err
+}
+
+func parseTemplate(tmpl string) ([]segment, error) {
+	segments := []segment{}
+	for tmpl != "" {
+		start := strings.Index(tmpl, openDelim)
+		if start < 0 {
+			segments = append(segments, segment{text: tmpl})
+			break
+		}
+		if start > 0 {
+			segments = append(segments, segment{text: tmpl[:start]})
+		}
+
+		tmpl = tmpl[start+len(openDelim):]
+		end := strings.Index(tmpl, closeDelim)
+		if end < 0 {
+			return nil, ErrMalformedEntity
+		}
+
+		path := strings.TrimSpace(tmpl[:end])
+		if strings.Contains(path, openDelim) {
+			return nil, ErrMalformedEntity
+		}
+
+		s := segment{placeholder: true}
+		if path != "" {
+			s.path = strings.Split(path, ".")
+		}
+		for _, elem := range s.path {
+			if elem == "" {
+				return nil, ErrMalformedEntity
+			}
+		}
+		segments = append(segments, s)
+
+		tmpl = tmpl[end+len(closeDelim):]
+	}
+
+	return segments, nil
+}
+
+func lookup(doc interface{}, path []string) (interface{}, bool) {
+	for _, elem := range path {
+		switch val := doc.(type) {
+		case map[string]interface{}:
+			v, ok := val[elem]
+			if !ok {
+				return nil, false
+			}
+			doc = v
+		case []interface{}:
+			i, err := strconv.Atoi(elem)
+			if err != nil || i < 0 || i >= len(val) {
+				return nil, false
+			}
+			doc = val[i]
+		default:
+			return nil, false
+		}
+	}
+
+	return doc, true
+}
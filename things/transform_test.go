@@ -0,0 +1,131 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package things_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux/things"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransformMetadata(t *testing.T) {
+	cases := []struct {
+		desc     string
+		metadata map[string]interface{}
+		tr       things.Transform
+		err      error
+	}{
+		{
+			desc: "retrieve valid transform metadata",
+			metadata: map[string]interface{}{
+				"transform": map[string]interface{}{"template": `[{"n":"temp","v":{{t}}}]`, "contentType": "application/senml+json"},
+			},
+			tr:  things.Transform{Template: `[{"n":"temp","v":{{t}}}]`, ContentType: "application/senml+json"},
+			err: nil,
+		},
+		{
+			desc:     "retrieve missing transform metadata",
+			metadata: map[string]interface{}{},
+			err:      things.ErrMetadataNotFound,
+		},
+		{
+			desc:     "retrieve transform metadata without template",
+			metadata: map[string]interface{}{"transform": map[string]interface{}{"contentType": "application/json"}},
+			err:      things.ErrMalformedEntity,
+		},
+		{
+			desc:     "retrieve transform metadata with unterminated placeholder",
+			metadata: map[string]interface{}{"transform": map[string]interface{}{"template": `{"v":{{t}`}},
+			err:      things.ErrMalformedEntity,
+		},
+		{
+			desc:     "retrieve transform metadata with empty path element",
+			metadata: map[string]interface{}{"transform": map[string]interface{}{"template": `{"v":{{a..t}}}`}},
+			err:      things.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		th := things.Thing{Metadata: tc.metadata}
+		tr, err := th.Transform()
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err == nil {
+			assert.Equal(t, tc.tr, tr, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.tr, tr))
+		}
+
+		ch := things.Channel{Metadata: tc.metadata}
+		tr, err = ch.Transform()
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err == nil {
+			assert.Equal(t, tc.tr, tr, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.tr, tr))
+		}
+	}
+}
+
+func TestTransformApply(t *testing.T) {
+	payload := []byte(`{"id":"dev-1","readings":[{"t":21.50,"h":40},{"t":22}]}`)
+
+	cases := []struct {
+		desc     string
+		template string
+		payload  []byte
+		res      string
+		err      error
+	}{
+		{
+			desc:     "transform payload using paths",
+			template: `[{"bn":{{id}},"n":"temp","v":{{readings.0.t}}},{"n":"hum","v":{{ readings.0.h }}}]`,
+			payload:  payload,
+			res:      `[{"bn":"dev-1","n":"temp","v":21.50},{"n":"hum","v":40}]`,
+			err:      nil,
+		},
+		{
+			desc:     "transform payload using entire payload",
+			template: `{"legacy":{{}}}`,
+			payload:  []byte(`[1,2]`),
+			res:      `{"legacy":[1,2]}`,
+			err:      nil,
+		},
+		{
+			desc:     "transform payload using template without placeholders",
+			template: `{"alive":true}`,
+			payload:  payload,
+			res:      `{"alive":true}`,
+			err:      nil,
+		},
+		{
+			desc:     "transform payload missing value",
+			template: `{"v":{{readings.1.h}}}`,
+			payload:  payload,
+			err:      things.ErrTransformFailed,
+		},
+		{
+			desc:     "transform payload using out of range index",
+			template: `{"v":{{readings.2.t}}}`,
+			payload:  payload,
+			err:      things.ErrTransformFailed,
+		},
+		{
+			desc:     "transform non-JSON payload",
+			template: `{"v":{{t}}}`,
+			payload:  []byte("t=21.5"),
+			err:      things.ErrTransformFailed,
+		},
+	}
+
+	for _, tc := range cases {
+		tr := things.Transform{Template: tc.template}
+		res, err := tr.Apply(tc.payload)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err == nil {
+			assert.Equal(t, tc.res, string(res), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.res, res))
+		}
+	}
+}
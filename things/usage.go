@@ -0,0 +1,38 @@
+//
+// Copyright (c) 2018
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package things
+
+import (
+	"context"
+	"time"
+)
+
+// Usage represents the number of messages and the number of payload bytes
+// published within the given time period.
+type Usage struct {
+	From     time.Time
+	To       time.Time
+	Messages uint64
+	Bytes    uint64
+}
+
+// UsageRepository specifies a message usage persistence API. Usage is
+// aggregated with a daily granularity.
+type UsageRepository interface {
+	// Add records the message of the given size, published by the thing
+	// to the channel at the given time.
+	Add(context.Context, string, string, uint64, time.Time) error
+
+	// RetrieveByThing retrieves usage of the thing with the provided
+	// identifier within the given time period.
+	RetrieveByThing(context.Context, string, time.Time, time.Time) (Usage, error)
+
+	// RetrieveByChannel retrieves usage of the channel with the provided
+	// identifier within the given time period.
+	RetrieveByChannel(context.Context, string, time.Time, time.Time) (Usage, error)
+}
@@ -40,3 +40,11 @@ func (repo singleUserRepo) Identify(_ context.Context, token *mainflux.Token, op
 
 	return &mainflux.UserID{Value: repo.email}, nil
 }
+
+func (repo singleUserRepo) Exists(_ context.Context, id *mainflux.UserID, opts ...grpc.CallOption) (*mainflux.UserID, error) {
+	if repo.email != id.GetValue() {
+		return nil, things.ErrNotFound
+	}
+
+	return &mainflux.UserID{Value: repo.email}, nil
+}
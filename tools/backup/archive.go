@@ -0,0 +1,87 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"time"
+)
+
+// archiveWriter writes entries of a gzipped tarball.
+type archiveWriter struct {
+	gw *gzip.Writer
+	tw *tar.Writer
+}
+
+func newArchiveWriter(w io.Writer) *archiveWriter {
+	gw := gzip.NewWriter(w)
+	return &archiveWriter{
+		gw: gw,
+		tw: tar.NewWriter(gw),
+	}
+}
+
+func (aw *archiveWriter) writeJSON(name string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	hdr := &tar.Header{
+		Name:    name,
+		Mode:    0600,
+		Size:    int64(len(data)),
+		ModTime: time.Now(),
+	}
+	if err := aw.tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+
+	_, err = aw.tw.Write(data)
+	return err
+}
+
+func (aw *archiveWriter) close() error {
+	if err := aw.tw.Close(); err != nil {
+		return err
+	}
+
+	return aw.gw.Close()
+}
+
+// readArchive reads all the entries of a gzipped tarball.
+func readArchive(r io.Reader) (map[string][]byte, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", ErrMalformedArchive, err)
+	}
+	defer gr.Close()
+
+	entries := map[string][]byte{}
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", ErrMalformedArchive, err)
+		}
+
+		data, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", ErrMalformedArchive, err)
+		}
+		entries[hdr.Name] = data
+	}
+}
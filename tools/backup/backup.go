@@ -0,0 +1,222 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// Package backup contains the tooling used to snapshot the state of a
+// Mainflux deployment into a single archive and to restore it into a fresh
+// one.
+package backup
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+const (
+	// Version of the archive format.
+	formatVersion = 1
+
+	manifestEntry   = "manifest.json"
+	routeMapsEntry  = "redis/routemaps.json"
+	referencesEntry = "readers/references.json"
+)
+
+var (
+	// ErrMalformedArchive indicates that the archive is not a valid backup.
+	ErrMalformedArchive = errors.New("malformed backup archive")
+
+	// ErrMissingDatabase indicates that the archive holds a database that
+	// is not configured for restore.
+	ErrMissingDatabase = errors.New("database not configured")
+)
+
+// Database is the Postgres database of a single service, e.g. users, things
+// or bootstrap.
+type Database struct {
+	Name string
+	DB   *sql.DB
+}
+
+// Config holds the sources a backup is created from, or the destinations it
+// is restored into. Cache and Messages are optional.
+type Config struct {
+	Databases []Database
+
+	// Cache is the Redis instance holding LoRa route maps.
+	Cache redis.UniversalClient
+
+	// Messages is the Postgres database the readers serve messages from.
+	Messages *sql.DB
+}
+
+// Manifest describes the content of a backup archive.
+type Manifest struct {
+	Format     int                 `json:"format"`
+	Created    time.Time           `json:"created"`
+	Databases  map[string][]string `json:"databases"`
+	RouteMaps  int                 `json:"route_maps"`
+	References int                 `json:"references"`
+}
+
+// Reference summarizes the messages of a single channel stored by the
+// readers. The messages themselves are not part of the backup, references
+// are used to verify that the restored deployment points to the same data.
+type Reference struct {
+	Channel string  `json:"channel"`
+	Count   uint64  `json:"count"`
+	First   float64 `json:"first"`
+	Last    float64 `json:"last"`
+}
+
+// Create snapshots the configured sources and writes the archive to w.
+func Create(cfg Config, w io.Writer) (Manifest, error) {
+	m := Manifest{
+		Format:    formatVersion,
+		Created:   time.Now().UTC(),
+		Databases: map[string][]string{},
+	}
+
+	aw := newArchiveWriter(w)
+
+	for _, db := range cfg.Databases {
+		tables, err := listTables(db.DB)
+		if err != nil {
+			return Manifest{}, fmt.Errorf("failed to list %s tables: %s", db.Name, err)
+		}
+
+		for _, t := range tables {
+			rows, err := dumpTable(db.DB, t)
+			if err != nil {
+				return Manifest{}, fmt.Errorf("failed to dump %s.%s: %s", db.Name, t, err)
+			}
+			if err := aw.writeJSON(tableEntry(db.Name, t), rows); err != nil {
+				return Manifest{}, err
+			}
+		}
+		m.Databases[db.Name] = tables
+	}
+
+	if cfg.Cache != nil {
+		rms, err := dumpRouteMaps(cfg.Cache)
+		if err != nil {
+			return Manifest{}, fmt.Errorf("failed to dump route maps: %s", err)
+		}
+		if err := aw.writeJSON(routeMapsEntry, rms); err != nil {
+			return Manifest{}, err
+		}
+		m.RouteMaps = len(rms)
+	}
+
+	if cfg.Messages != nil {
+		refs, err := dumpReferences(cfg.Messages)
+		if err != nil {
+			return Manifest{}, fmt.Errorf("failed to dump reader data references: %s", err)
+		}
+		if err := aw.writeJSON(referencesEntry, refs); err != nil {
+			return Manifest{}, err
+		}
+		m.References = len(refs)
+	}
+
+	if err := aw.writeJSON(manifestEntry, m); err != nil {
+		return Manifest{}, err
+	}
+
+	return m, aw.close()
+}
+
+// Restore loads the archive read from r into the configured destinations.
+// Tables of each database are restored in a single transaction. Reader data
+// is not restored, instead the references that do not match the configured
+// messages database are returned.
+func Restore(cfg Config, r io.Reader) (Manifest, []Reference, error) {
+	entries, err := readArchive(r)
+	if err != nil {
+		return Manifest{}, nil, err
+	}
+
+	var m Manifest
+	if err := unmarshalEntry(entries, manifestEntry, &m); err != nil {
+		return Manifest{}, nil, err
+	}
+	if m.Format != formatVersion {
+		return Manifest{}, nil, fmt.Errorf("%s: unsupported format %d", ErrMalformedArchive, m.Format)
+	}
+
+	dbs := map[string]*sql.DB{}
+	for _, db := range cfg.Databases {
+		dbs[db.Name] = db.DB
+	}
+
+	for name, tables := range m.Databases {
+		db, ok := dbs[name]
+		if !ok {
+			return Manifest{}, nil, fmt.Errorf("%s: %s", ErrMissingDatabase, name)
+		}
+
+		data := map[string][]json.RawMessage{}
+		for _, t := range tables {
+			var rows []json.RawMessage
+			if err := unmarshalEntry(entries, tableEntry(name, t), &rows); err != nil {
+				return Manifest{}, nil, err
+			}
+			data[t] = rows
+		}
+
+		if err := loadTables(db, tables, data); err != nil {
+			return Manifest{}, nil, fmt.Errorf("failed to restore %s: %s", name, err)
+		}
+	}
+
+	if cfg.Cache != nil && m.RouteMaps > 0 {
+		var rms map[string]string
+		if err := unmarshalEntry(entries, routeMapsEntry, &rms); err != nil {
+			return Manifest{}, nil, err
+		}
+		if err := loadRouteMaps(cfg.Cache, rms); err != nil {
+			return Manifest{}, nil, fmt.Errorf("failed to restore route maps: %s", err)
+		}
+	}
+
+	if cfg.Messages == nil || m.References == 0 {
+		return m, nil, nil
+	}
+
+	var refs []Reference
+	if err := unmarshalEntry(entries, referencesEntry, &refs); err != nil {
+		return Manifest{}, nil, err
+	}
+
+	missing, err := verifyReferences(cfg.Messages, refs)
+	if err != nil {
+		return Manifest{}, nil, fmt.Errorf("failed to verify reader data references: %s", err)
+	}
+
+	return m, missing, nil
+}
+
+func tableEntry(db, table string) string {
+	return fmt.Sprintf("postgres/%s/%s.json", db, table)
+}
+
+func unmarshalEntry(entries map[string][]byte, name string, v interface{}) error {
+	data, ok := entries[name]
+	if !ok {
+		return fmt.Errorf("%s: missing %s", ErrMalformedArchive, name)
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%s: %s", ErrMalformedArchive, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,37 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package backup_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mainflux/mainflux/tools/backup"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateRestore(t *testing.T) {
+	var buf bytes.Buffer
+	created, err := backup.Create(backup.Config{}, &buf)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	restored, missing, err := backup.Restore(backup.Config{}, &buf)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Empty(t, missing, "expected no missing references")
+	assert.True(t, created.Created.Equal(restored.Created), fmt.Sprintf("expected %s got %s", created.Created, restored.Created))
+	assert.Equal(t, created.Format, restored.Format, fmt.Sprintf("expected %d got %d", created.Format, restored.Format))
+}
+
+func TestRestoreMalformed(t *testing.T) {
+	_, _, err := backup.Restore(backup.Config{}, strings.NewReader("not an archive"))
+	assert.NotNil(t, err, "expected error restoring malformed archive")
+	assert.True(t, strings.HasPrefix(err.Error(), backup.ErrMalformedArchive.Error()), fmt.Sprintf("expected %s got %s", backup.ErrMalformedArchive, err))
+}
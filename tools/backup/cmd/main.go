@@ -0,0 +1,164 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+
+	_ "github.com/lib/pq" // required for SQL access
+	mfredis "github.com/mainflux/mainflux/redis"
+	"github.com/mainflux/mainflux/tools/backup"
+	"github.com/spf13/cobra"
+)
+
+type config struct {
+	usersDB     string
+	thingsDB    string
+	bootstrapDB string
+	cacheURL    string
+	cachePass   string
+	cacheDB     string
+	messagesDB  string
+}
+
+func main() {
+	var cfg config
+
+	rootCmd := &cobra.Command{
+		Use:   "mainflux-backup",
+		Short: "Backup and restore of a Mainflux deployment",
+	}
+
+	createCmd := &cobra.Command{
+		Use:   "create <archive>",
+		Short: "Create backup archive",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			bc, closeAll := connect(cfg)
+			defer closeAll()
+
+			f, err := os.Create(args[0])
+			if err != nil {
+				log.Fatalf("Failed to create archive: %s", err)
+			}
+			defer f.Close()
+
+			m, err := backup.Create(bc, f)
+			if err != nil {
+				log.Fatalf("Failed to create backup: %s", err)
+			}
+			report(m)
+		},
+	}
+
+	restoreCmd := &cobra.Command{
+		Use:   "restore <archive>",
+		Short: "Restore backup archive into a fresh deployment",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			bc, closeAll := connect(cfg)
+			defer closeAll()
+
+			f, err := os.Open(args[0])
+			if err != nil {
+				log.Fatalf("Failed to open archive: %s", err)
+			}
+			defer f.Close()
+
+			m, missing, err := backup.Restore(bc, f)
+			if err != nil {
+				log.Fatalf("Failed to restore backup: %s", err)
+			}
+			report(m)
+
+			for _, ref := range missing {
+				fmt.Printf("Missing reader data of channel %s: %d messages between %f and %f\n", ref.Channel, ref.Count, ref.First, ref.Last)
+			}
+		},
+	}
+
+	rootCmd.AddCommand(createCmd)
+	rootCmd.AddCommand(restoreCmd)
+
+	flags := rootCmd.PersistentFlags()
+	flags.StringVar(&cfg.usersDB, "users-db", "", "Users database connection string")
+	flags.StringVar(&cfg.thingsDB, "things-db", "", "Things database connection string")
+	flags.StringVar(&cfg.bootstrapDB, "bootstrap-db", "", "Bootstrap database connection string")
+	flags.StringVar(&cfg.cacheURL, "cache-url", "", "LoRa route map cache URL, route maps are skipped if empty")
+	flags.StringVar(&cfg.cachePass, "cache-pass", "", "LoRa route map cache password")
+	flags.StringVar(&cfg.cacheDB, "cache-db", "0", "LoRa route map cache instance")
+	flags.StringVar(&cfg.messagesDB, "messages-db", "", "Postgres reader database connection string, reader data references are skipped if empty")
+
+	if err := rootCmd.Execute(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func connect(cfg config) (backup.Config, func()) {
+	var bc backup.Config
+	var dbs []*sql.DB
+
+	for _, d := range []struct{ name, url string }{
+		{"users", cfg.usersDB},
+		{"things", cfg.thingsDB},
+		{"bootstrap", cfg.bootstrapDB},
+	} {
+		if d.url == "" {
+			continue
+		}
+		db := openDB(d.name, d.url)
+		dbs = append(dbs, db)
+		bc.Databases = append(bc.Databases, backup.Database{Name: d.name, DB: db})
+	}
+
+	if cfg.messagesDB != "" {
+		bc.Messages = openDB("messages", cfg.messagesDB)
+		dbs = append(dbs, bc.Messages)
+	}
+
+	if cfg.cacheURL != "" {
+		client, err := mfredis.Connect(cfg.cacheURL, cfg.cachePass, cfg.cacheDB)
+		if err != nil {
+			log.Fatalf("Failed to connect to cache: %s", err)
+		}
+		bc.Cache = client
+	}
+
+	return bc, func() {
+		for _, db := range dbs {
+			db.Close()
+		}
+		if bc.Cache != nil {
+			bc.Cache.Close()
+		}
+	}
+}
+
+func openDB(name, url string) *sql.DB {
+	db, err := sql.Open("postgres", url)
+	if err != nil {
+		log.Fatalf("Failed to open %s database: %s", name, err)
+	}
+
+	if err := db.Ping(); err != nil {
+		log.Fatalf("Failed to connect to %s database: %s", name, err)
+	}
+
+	return db
+}
+
+func report(m backup.Manifest) {
+	for name, tables := range m.Databases {
+		fmt.Printf("Database %s: %d tables\n", name, len(tables))
+	}
+	fmt.Printf("Route maps: %d\n", m.RouteMaps)
+	fmt.Printf("Reader data references: %d\n", m.References)
+}
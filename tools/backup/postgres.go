@@ -0,0 +1,102 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package backup
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// Migrations are applied by the services on start, so the migrations table
+// of the fresh deployment is already populated.
+const migrationsTable = "gorp_migrations"
+
+// listTables returns the tables of the public schema in the order they were
+// created in, which satisfies the foreign keys between them on restore.
+func listTables(db *sql.DB) ([]string, error) {
+	q := `SELECT c.relname FROM pg_class c
+	      JOIN pg_namespace n ON n.oid = c.relnamespace
+	      WHERE n.nspname = 'public' AND c.relkind = 'r' AND c.relname <> $1
+	      ORDER BY c.oid`
+
+	rows, err := db.Query(q, migrationsTable)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tables := []string{}
+	for rows.Next() {
+		var t string
+		if err := rows.Scan(&t); err != nil {
+			return nil, err
+		}
+		tables = append(tables, t)
+	}
+
+	return tables, rows.Err()
+}
+
+func dumpTable(db *sql.DB, table string) ([]json.RawMessage, error) {
+	q := fmt.Sprintf(`SELECT row_to_json(t) FROM %s t`, pq.QuoteIdentifier(table))
+
+	rows, err := db.Query(q)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []json.RawMessage{}
+	for rows.Next() {
+		var row []byte
+		if err := rows.Scan(&row); err != nil {
+			return nil, err
+		}
+		items = append(items, json.RawMessage(row))
+	}
+
+	return items, rows.Err()
+}
+
+func loadTables(db *sql.DB, tables []string, data map[string][]json.RawMessage) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+
+	for _, t := range tables {
+		if err := loadTable(tx, t, data[t]); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("table %s: %s", t, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func loadTable(tx *sql.Tx, table string, rows []json.RawMessage) error {
+	t := pq.QuoteIdentifier(table)
+	q := fmt.Sprintf(`INSERT INTO %s SELECT * FROM json_populate_record(NULL::%s, $1)`, t, t)
+
+	stmt, err := tx.Prepare(q)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, row := range rows {
+		if _, err := stmt.Exec(string(row)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package backup
+
+import "github.com/go-redis/redis"
+
+// Route maps are stored by the LoRa adapter under the keys of the
+// <prefix>:mfx:lora:<id> and <prefix>:lora:mfx:<id> form.
+var routeMapPatterns = []string{"*:mfx:lora:*", "*:lora:mfx:*"}
+
+const scanCount = 1000
+
+func dumpRouteMaps(client redis.UniversalClient) (map[string]string, error) {
+	rms := map[string]string{}
+	for _, pattern := range routeMapPatterns {
+		var cursor uint64
+		for {
+			keys, next, err := client.Scan(cursor, pattern, scanCount).Result()
+			if err != nil {
+				return nil, err
+			}
+
+			for _, key := range keys {
+				val, err := client.Get(key).Result()
+				if err == redis.Nil {
+					continue
+				}
+				if err != nil {
+					return nil, err
+				}
+				rms[key] = val
+			}
+
+			if next == 0 {
+				break
+			}
+			cursor = next
+		}
+	}
+
+	return rms, nil
+}
+
+func loadRouteMaps(client redis.UniversalClient, rms map[string]string) error {
+	for key, val := range rms {
+		if err := client.Set(key, val, 0).Err(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
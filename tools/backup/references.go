@@ -0,0 +1,51 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package backup
+
+import "database/sql"
+
+func dumpReferences(db *sql.DB) ([]Reference, error) {
+	q := `SELECT channel, COUNT(*), MIN(time), MAX(time) FROM messages GROUP BY channel ORDER BY channel`
+
+	rows, err := db.Query(q)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	refs := []Reference{}
+	for rows.Next() {
+		var ref Reference
+		if err := rows.Scan(&ref.Channel, &ref.Count, &ref.First, &ref.Last); err != nil {
+			return nil, err
+		}
+		refs = append(refs, ref)
+	}
+
+	return refs, rows.Err()
+}
+
+// verifyReferences returns the references whose messages are not present
+// in the database. Messages received after the backup was created are
+// allowed, so only the backed up time range is checked.
+func verifyReferences(db *sql.DB, refs []Reference) ([]Reference, error) {
+	q := `SELECT COUNT(*) FROM messages WHERE channel = $1 AND time >= $2 AND time <= $3`
+
+	missing := []Reference{}
+	for _, ref := range refs {
+		var count uint64
+		if err := db.QueryRow(q, ref.Channel, ref.First, ref.Last).Scan(&count); err != nil {
+			return nil, err
+		}
+		if count < ref.Count {
+			missing = append(missing, ref)
+		}
+	}
+
+	return missing, nil
+}
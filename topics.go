@@ -11,3 +11,37 @@ package mainflux
 
 // OutputSenML represents subject SenML messages will be published to.
 const OutputSenML = "out.senml"
+
+// OutputFlagged represents subject SenML messages violating the validation
+// rules of their channel will be published to, when the rules are set to
+// flag such messages instead of rejecting them. Writers don't save these.
+const OutputFlagged = "out.flagged"
+
+// OutputRaw represents subject the malformed SenML messages the normalizer
+// passes through in the lenient mode will be published to, as JSON-encoded
+// WarnedMessage. Writers don't save these.
+const OutputRaw = "out.raw"
+
+// DeadLetters represents subject prefix messages that failed processing will
+// be published to, followed by the name of the processing stage.
+const DeadLetters = "deadletter"
+
+// Redrive represents subject prefix processing stages listen on for
+// re-driven dead letters, followed by the stage's queue name.
+const Redrive = "redrive"
+
+// Import represents subject historical messages are published to. Writers
+// save these messages as they are, without sampling them.
+const Import = "import"
+
+// Revocations represents subject revocations of signed thing keys will be
+// published to.
+const Revocations = "revocations"
+
+// RevocationsList represents subject the things service replies on with all
+// the revocations of signed thing keys that are in effect.
+const RevocationsList = "revocations.list"
+
+// ThingsEvents represents subject prefix the things service events will be
+// published to, followed by the event operation (e.g. thing.create).
+const ThingsEvents = "things.events"
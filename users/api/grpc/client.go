@@ -8,6 +8,8 @@
 package grpc
 
 import (
+	"time"
+
 	"github.com/go-kit/kit/endpoint"
 	kitgrpc "github.com/go-kit/kit/transport/grpc"
 	"github.com/mainflux/mainflux"
@@ -15,32 +17,63 @@ import (
 	"google.golang.org/grpc"
 )
 
+// timeout bounds the calls whose context doesn't have an earlier deadline,
+// so that the callers don't hang on the unresponsive users service.
+const timeout = time.Second
+
 var _ mainflux.UsersServiceClient = (*grpcClient)(nil)
 
 type grpcClient struct {
 	identify endpoint.Endpoint
+	exists   endpoint.Endpoint
 }
 
 // NewClient returns new gRPC client instance.
 func NewClient(conn *grpc.ClientConn) mainflux.UsersServiceClient {
-	endpoint := kitgrpc.NewClient(
-		conn,
-		"mainflux.UsersService",
-		"Identify",
-		encodeIdentifyRequest,
-		decodeIdentifyResponse,
-		mainflux.UserID{},
-	).Endpoint()
-
-	return &grpcClient{endpoint}
+	svcName := "mainflux.UsersService"
+
+	return &grpcClient{
+		identify: kitgrpc.NewClient(
+			conn,
+			svcName,
+			"Identify",
+			encodeIdentifyRequest,
+			decodeIdentifyResponse,
+			mainflux.UserID{},
+		).Endpoint(),
+		exists: kitgrpc.NewClient(
+			conn,
+			svcName,
+			"Exists",
+			encodeExistsRequest,
+			decodeIdentifyResponse,
+			mainflux.UserID{},
+		).Endpoint(),
+	}
 }
 
 func (client grpcClient) Identify(ctx context.Context, token *mainflux.Token, _ ...grpc.CallOption) (*mainflux.UserID, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
 	res, err := client.identify(ctx, identityReq{token.GetValue()})
 	if err != nil {
 		return nil, err
 	}
 
+	ir := res.(identityRes)
+	return &mainflux.UserID{Value: ir.id, Actor: ir.actor}, ir.err
+}
+
+func (client grpcClient) Exists(ctx context.Context, id *mainflux.UserID, _ ...grpc.CallOption) (*mainflux.UserID, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	res, err := client.exists(ctx, existsReq{id.GetValue()})
+	if err != nil {
+		return nil, err
+	}
+
 	ir := res.(identityRes)
 	return &mainflux.UserID{Value: ir.id}, ir.err
 }
@@ -52,5 +85,10 @@ func encodeIdentifyRequest(_ context.Context, grpcReq interface{}) (interface{},
 
 func decodeIdentifyResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
 	res := grpcRes.(*mainflux.UserID)
-	return identityRes{res.GetValue(), nil}, nil
+	return identityRes{res.GetValue(), res.GetActor(), nil}, nil
+}
+
+func encodeExistsRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(existsReq)
+	return &mainflux.UserID{Value: req.email}, nil
 }
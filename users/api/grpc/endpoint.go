@@ -24,6 +24,25 @@ func identifyEndpoint(svc users.Service) endpoint.Endpoint {
 		if err != nil {
 			return identityRes{}, err
 		}
-		return identityRes{id, nil}, nil
+
+		actor, err := svc.Impersonator(req.token)
+		if err != nil {
+			return identityRes{}, err
+		}
+		return identityRes{id, actor, nil}, nil
+	}
+}
+
+func existsEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(existsReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.Exists(req.email); err != nil {
+			return identityRes{}, err
+		}
+		return identityRes{id: req.email}, nil
 	}
 }
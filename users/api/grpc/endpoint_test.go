@@ -19,6 +19,7 @@ import (
 	grpcapi "github.com/mainflux/mainflux/users/api/grpc"
 	"github.com/mainflux/mainflux/users/mocks"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -27,8 +28,9 @@ import (
 const port = 8081
 
 var (
-	user = users.User{"john.doe@email.com", "pass"}
-	svc  users.Service
+	user  = users.User{"john.doe@email.com", "pass"}
+	admin = users.User{"admin@email.com", "pass"}
+	svc   users.Service
 )
 
 func newService() users.Service {
@@ -36,7 +38,7 @@ func newService() users.Service {
 	hasher := mocks.NewHasher()
 	idp := mocks.NewIdentityProvider()
 
-	return users.New(repo, hasher, idp)
+	return users.New(repo, mocks.NewSessionRepository(), mocks.NewResetTokenRepository(), hasher, idp, mocks.NewMailer(), []string{admin.Email}, time.Minute)
 }
 
 func startGRPCServer(svc users.Service, port int) {
@@ -48,6 +50,11 @@ func startGRPCServer(svc users.Service, port int) {
 
 func TestIdentify(t *testing.T) {
 	svc.Register(user)
+	svc.Register(admin)
+	adminKey, err := svc.Login(admin, "", "")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	impKey, err := svc.Impersonate(adminKey, user.Email)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	usersAddr := fmt.Sprintf("localhost:%d", port)
 	conn, _ := grpc.Dial(usersAddr, grpc.WithInsecure())
@@ -58,15 +65,42 @@ func TestIdentify(t *testing.T) {
 	cases := map[string]struct {
 		token string
 		id    string
+		actor string
 		err   error
 	}{
-		"identify user with valid token":   {user.Email, user.Email, nil},
-		"identify user that doesn't exist": {"", "", status.Error(codes.InvalidArgument, "received invalid token request")},
+		"identify user with valid token":         {user.Email, user.Email, "", nil},
+		"identify user with impersonation token": {impKey, user.Email, admin.Email, nil},
+		"identify user that doesn't exist":       {"", "", "", status.Error(codes.InvalidArgument, "received invalid token request")},
 	}
 
 	for desc, tc := range cases {
 		id, err := client.Identify(ctx, &mainflux.Token{Value: tc.token})
 		assert.Equal(t, tc.id, id.GetValue(), fmt.Sprintf("%s: expected %s got %s", desc, tc.id, id.GetValue()))
+		assert.Equal(t, tc.actor, id.GetActor(), fmt.Sprintf("%s: expected actor %s got %s", desc, tc.actor, id.GetActor()))
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s", desc, tc.err, err))
+	}
+}
+
+func TestExists(t *testing.T) {
+	svc.Register(user)
+
+	usersAddr := fmt.Sprintf("localhost:%d", port)
+	conn, _ := grpc.Dial(usersAddr, grpc.WithInsecure())
+	client := grpcapi.NewClient(conn)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	cases := map[string]struct {
+		email string
+		err   error
+	}{
+		"check existing user":     {user.Email, nil},
+		"check non-existing user": {"unknown@example.com", status.Error(codes.NotFound, "user doesn't exist")},
+		"check empty email":       {"", status.Error(codes.InvalidArgument, "received invalid token request")},
+	}
+
+	for desc, tc := range cases {
+		_, err := client.Exists(ctx, &mainflux.UserID{Value: tc.email})
 		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s", desc, tc.err, err))
 	}
 }
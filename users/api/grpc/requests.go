@@ -21,3 +21,14 @@ func (req identityReq) validate() error {
 	}
 	return nil
 }
+
+type existsReq struct {
+	email string
+}
+
+func (req existsReq) validate() error {
+	if req.email == "" {
+		return users.ErrMalformedEntity
+	}
+	return nil
+}
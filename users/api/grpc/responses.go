@@ -8,6 +8,7 @@
 package grpc
 
 type identityRes struct {
-	id  string
-	err error
+	id    string
+	actor string
+	err   error
 }
@@ -19,21 +19,36 @@ import (
 var _ mainflux.UsersServiceServer = (*grpcServer)(nil)
 
 type grpcServer struct {
-	handler kitgrpc.Handler
+	identify kitgrpc.Handler
+	exists   kitgrpc.Handler
 }
 
 // NewServer returns new UsersServiceServer instance.
 func NewServer(svc users.Service) mainflux.UsersServiceServer {
-	handler := kitgrpc.NewServer(
-		identifyEndpoint(svc),
-		decodeIdentifyRequest,
-		encodeIdentifyResponse,
-	)
-	return &grpcServer{handler}
+	return &grpcServer{
+		identify: kitgrpc.NewServer(
+			identifyEndpoint(svc),
+			decodeIdentifyRequest,
+			encodeIdentifyResponse,
+		),
+		exists: kitgrpc.NewServer(
+			existsEndpoint(svc),
+			decodeExistsRequest,
+			encodeIdentifyResponse,
+		),
+	}
 }
 
 func (s *grpcServer) Identify(ctx context.Context, token *mainflux.Token) (*mainflux.UserID, error) {
-	_, res, err := s.handler.ServeGRPC(ctx, token)
+	_, res, err := s.identify.ServeGRPC(ctx, token)
+	if err != nil {
+		return nil, encodeError(err)
+	}
+	return res.(*mainflux.UserID), nil
+}
+
+func (s *grpcServer) Exists(ctx context.Context, id *mainflux.UserID) (*mainflux.UserID, error) {
+	_, res, err := s.exists.ServeGRPC(ctx, id)
 	if err != nil {
 		return nil, encodeError(err)
 	}
@@ -45,9 +60,14 @@ func decodeIdentifyRequest(_ context.Context, grpcReq interface{}) (interface{},
 	return identityReq{req.GetValue()}, nil
 }
 
+func decodeExistsRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*mainflux.UserID)
+	return existsReq{req.GetValue()}, nil
+}
+
 func encodeIdentifyResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
 	res := grpcRes.(identityRes)
-	return &mainflux.UserID{Value: res.id}, encodeError(res.err)
+	return &mainflux.UserID{Value: res.id, Actor: res.actor}, encodeError(res.err)
 }
 
 func encodeError(err error) error {
@@ -60,6 +80,8 @@ func encodeError(err error) error {
 		return status.Error(codes.InvalidArgument, "received invalid token request")
 	case users.ErrUnauthorizedAccess:
 		return status.Error(codes.Unauthenticated, "failed to identify user from token")
+	case users.ErrNotFound:
+		return status.Error(codes.NotFound, "user doesn't exist")
 	default:
 		return status.Error(codes.Internal, "internal server error")
 	}
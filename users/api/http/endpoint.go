@@ -27,15 +27,31 @@ func registrationEndpoint(svc users.Service) endpoint.Endpoint {
 	}
 }
 
+func removeEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(tokenReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.Remove(req.token); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
 func loginEndpoint(svc users.Service) endpoint.Endpoint {
 	return func(_ context.Context, request interface{}) (interface{}, error) {
-		req := request.(userReq)
+		req := request.(loginReq)
 
 		if err := req.validate(); err != nil {
 			return nil, err
 		}
 
-		token, err := svc.Login(req.user)
+		token, err := svc.Login(req.user, req.ip, req.client)
 		if err != nil {
 			return nil, err
 		}
@@ -43,3 +59,97 @@ func loginEndpoint(svc users.Service) endpoint.Endpoint {
 		return tokenRes{token}, nil
 	}
 }
+
+func impersonateEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(impersonateReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		token, err := svc.Impersonate(req.token, req.Email)
+		if err != nil {
+			return nil, err
+		}
+
+		return tokenRes{token}, nil
+	}
+}
+
+func listSessionsEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(tokenReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		sessions, err := svc.ListSessions(req.token)
+		if err != nil {
+			return nil, err
+		}
+
+		res := sessionsRes{Sessions: []sessionRes{}}
+		for _, s := range sessions {
+			res.Sessions = append(res.Sessions, sessionRes{
+				ID:        s.ID,
+				Actor:     s.Actor,
+				IssuedAt:  s.IssuedAt.Unix(),
+				ExpiresAt: s.ExpiresAt.Unix(),
+				IP:        s.IP,
+				Client:    s.Client,
+			})
+		}
+
+		return res, nil
+	}
+}
+
+func revokeSessionEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(sessionReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RevokeSession(req.token, req.id); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func requestPasswordResetEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(resetRequestReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RequestPasswordReset(req.Email); err != nil {
+			return nil, err
+		}
+
+		return resetRequestRes{}, nil
+	}
+}
+
+func resetPasswordEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(resetPasswordReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if _, err := svc.ResetPassword(req.Token, req.Password); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
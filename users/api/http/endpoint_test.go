@@ -11,12 +11,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	log "github.com/mainflux/mainflux/logger"
 	"github.com/mainflux/mainflux/users"
@@ -32,7 +32,10 @@ const (
 	id           = "123e4567-e89b-12d3-a456-000000000001"
 )
 
-var user = users.User{"user@example.com", "password"}
+var (
+	user  = users.User{"user@example.com", "password"}
+	admin = users.User{Email: "admin@example.com", Password: "password"}
+)
 
 type testRequest struct {
 	client      *http.Client
@@ -62,7 +65,7 @@ func newService() users.Service {
 	hasher := mocks.NewHasher()
 	idp := mocks.NewIdentityProvider()
 
-	return users.New(repo, hasher, idp)
+	return users.New(repo, mocks.NewSessionRepository(), mocks.NewResetTokenRepository(), hasher, idp, mocks.NewMailer(), []string{admin.Email}, time.Minute)
 }
 
 func newServer(svc users.Service) *httptest.Server {
@@ -122,7 +125,6 @@ func TestLogin(t *testing.T) {
 	defer ts.Close()
 	client := ts.Client()
 
-	tokenData := toJSON(map[string]string{"token": user.Email})
 	data := toJSON(user)
 	invalidEmailData := toJSON(users.User{Email: invalidEmail, Password: "password"})
 	invalidData := toJSON(users.User{"user@example.com", "invalid_password"})
@@ -136,7 +138,7 @@ func TestLogin(t *testing.T) {
 		status      int
 		res         string
 	}{
-		{"login with valid credentials", data, contentType, http.StatusCreated, tokenData},
+		{"login with valid credentials", data, contentType, http.StatusCreated, user.Email},
 		{"login with invalid credentials", invalidData, contentType, http.StatusForbidden, ""},
 		{"login with invalid email address", invalidEmailData, contentType, http.StatusBadRequest, ""},
 		{"login non-existent user", nonexistentData, contentType, http.StatusForbidden, ""},
@@ -156,11 +158,264 @@ func TestLogin(t *testing.T) {
 		}
 		res, err := req.make()
 		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
-		body, err := ioutil.ReadAll(res.Body)
+		var body map[string]string
+		json.NewDecoder(res.Body).Decode(&body)
+		email, _ := svc.Identify(body["token"])
+
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		assert.Equal(t, tc.res, email, fmt.Sprintf("%s: expected token of %s got token of %s", tc.desc, tc.res, email))
+	}
+}
+
+func TestRemove(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+	client := ts.Client()
+
+	svc.Register(user)
+	token, err := svc.Login(user, "", "")
+	assert.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+
+	cases := []struct {
+		desc   string
+		token  string
+		status int
+	}{
+		{"remove user with empty token", "", http.StatusForbidden},
+		{"remove non-existent user", "unknown@example.com", http.StatusNotFound},
+		{"remove existing user", token, http.StatusNoContent},
+		{"remove already removed user", token, http.StatusNotFound},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: client,
+			method: http.MethodDelete,
+			url:    fmt.Sprintf("%s/users", ts.URL),
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestImpersonate(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+	client := ts.Client()
+
+	svc.Register(user)
+	svc.Register(admin)
+	userToken, err := svc.Login(user, "", "")
+	assert.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	adminToken, err := svc.Login(admin, "", "")
+	assert.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+
+	data := toJSON(map[string]string{"email": user.Email})
+
+	cases := []struct {
+		desc        string
+		req         string
+		contentType string
+		token       string
+		status      int
+	}{
+		{"impersonate existing user", data, contentType, adminToken, http.StatusCreated},
+		{"impersonate non-existent user", toJSON(map[string]string{"email": "unknown@example.com"}), contentType, adminToken, http.StatusNotFound},
+		{"impersonate user without admin privileges", toJSON(map[string]string{"email": admin.Email}), contentType, userToken, http.StatusForbidden},
+		{"impersonate user with empty token", data, contentType, "", http.StatusForbidden},
+		{"impersonate user with empty email", "{}", contentType, adminToken, http.StatusBadRequest},
+		{"impersonate user with invalid request format", "{", contentType, adminToken, http.StatusBadRequest},
+		{"impersonate user with missing content type", data, "", adminToken, http.StatusUnsupportedMediaType},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      client,
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/impersonations", ts.URL),
+			contentType: tc.contentType,
+			token:       tc.token,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestListSessions(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+	client := ts.Client()
+
+	svc.Register(user)
+	token, err := svc.Login(user, "192.168.0.1", "curl/7.58.0")
+	assert.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+
+	cases := []struct {
+		desc   string
+		token  string
+		status int
+		size   int
+	}{
+		{"list sessions with valid token", token, http.StatusOK, 1},
+		{"list sessions with empty token", "", http.StatusForbidden, 0},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: client,
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/sessions", ts.URL),
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+
+		var body struct {
+			Sessions []map[string]interface{} `json:"sessions"`
+		}
+		json.NewDecoder(res.Body).Decode(&body)
+		assert.Len(t, body.Sessions, tc.size, fmt.Sprintf("%s: expected %d sessions got %d", tc.desc, tc.size, len(body.Sessions)))
+	}
+}
+
+func TestRevokeSession(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+	client := ts.Client()
+
+	svc.Register(user)
+	token, err := svc.Login(user, "", "")
+	assert.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	sessions, err := svc.ListSessions(token)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	session := sessions[0].ID
+
+	cases := []struct {
+		desc    string
+		token   string
+		session string
+		status  int
+	}{
+		{"revoke session with empty token", "", session, http.StatusForbidden},
+		{"revoke non-existent session", token, wrongID, http.StatusNotFound},
+		{"revoke session with malformed id", token, "invalid", http.StatusBadRequest},
+		{"revoke existing session", token, session, http.StatusNoContent},
+		{"revoke session with revoked token", token, session, http.StatusForbidden},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: client,
+			method: http.MethodDelete,
+			url:    fmt.Sprintf("%s/sessions/%s", ts.URL, tc.session),
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func newResetService(mailer users.Mailer) users.Service {
+	repo := mocks.NewUserRepository()
+	hasher := mocks.NewHasher()
+	idp := mocks.NewIdentityProvider()
+
+	return users.New(repo, mocks.NewSessionRepository(), mocks.NewResetTokenRepository(), hasher, idp, mailer, []string{admin.Email}, time.Minute)
+}
+
+func TestRequestPasswordReset(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+	client := ts.Client()
+
+	svc.Register(user)
+
+	unavailable := newServer(newResetService(nil))
+	defer unavailable.Close()
+
+	data := toJSON(map[string]string{"email": user.Email})
+	unknownData := toJSON(map[string]string{"email": "unknown@example.com"})
+
+	cases := []struct {
+		desc        string
+		url         string
+		req         string
+		contentType string
+		status      int
+	}{
+		{"request password reset", ts.URL, data, contentType, http.StatusAccepted},
+		{"request password reset of unknown user", ts.URL, unknownData, contentType, http.StatusAccepted},
+		{"request password reset with empty JSON request", ts.URL, "{}", contentType, http.StatusBadRequest},
+		{"request password reset with invalid request format", ts.URL, "{", contentType, http.StatusBadRequest},
+		{"request password reset with missing content type", ts.URL, data, "", http.StatusUnsupportedMediaType},
+		{"request password reset without mailer", unavailable.URL, data, contentType, http.StatusNotImplemented},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      client,
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/password/reset-request", tc.url),
+			contentType: tc.contentType,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
 		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
-		token := strings.Trim(string(body), "\n")
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestResetPassword(t *testing.T) {
+	mailer := mocks.NewMailer()
+	svc := newResetService(mailer)
+	ts := newServer(svc)
+	defer ts.Close()
+	client := ts.Client()
 
+	svc.Register(user)
+	err := svc.RequestPasswordReset(user.Email)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	token := mailer.Token(user.Email)
+
+	data := toJSON(map[string]string{"token": token, "password": "new-password"})
+	invalidData := toJSON(map[string]string{"token": "invalid", "password": "new-password"})
+	emptyPassData := toJSON(map[string]string{"token": token})
+
+	cases := []struct {
+		desc        string
+		req         string
+		contentType string
+		status      int
+	}{
+		{"reset password with invalid token", invalidData, contentType, http.StatusForbidden},
+		{"reset password with empty password", emptyPassData, contentType, http.StatusBadRequest},
+		{"reset password with invalid request format", "{", contentType, http.StatusBadRequest},
+		{"reset password with missing content type", data, "", http.StatusUnsupportedMediaType},
+		{"reset password", data, contentType, http.StatusNoContent},
+		{"reset password with used token", data, contentType, http.StatusForbidden},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      client,
+			method:      http.MethodPut,
+			url:         fmt.Sprintf("%s/password/reset", ts.URL),
+			contentType: tc.contentType,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
 		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
-		assert.Equal(t, tc.res, token, fmt.Sprintf("%s: expected body %s got %s", tc.desc, tc.res, token))
 	}
 }
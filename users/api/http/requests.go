@@ -20,3 +20,84 @@ type userReq struct {
 func (req userReq) validate() error {
 	return req.user.Validate()
 }
+
+type loginReq struct {
+	user   users.User
+	ip     string
+	client string
+}
+
+func (req loginReq) validate() error {
+	return req.user.Validate()
+}
+
+type sessionReq struct {
+	token string
+	id    string
+}
+
+func (req sessionReq) validate() error {
+	if req.token == "" {
+		return users.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" {
+		return users.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type tokenReq struct {
+	token string
+}
+
+func (req tokenReq) validate() error {
+	if req.token == "" {
+		return users.ErrUnauthorizedAccess
+	}
+
+	return nil
+}
+
+type impersonateReq struct {
+	token string
+	Email string `json:"email"`
+}
+
+func (req impersonateReq) validate() error {
+	if req.token == "" {
+		return users.ErrUnauthorizedAccess
+	}
+
+	if req.Email == "" {
+		return users.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type resetRequestReq struct {
+	Email string `json:"email"`
+}
+
+func (req resetRequestReq) validate() error {
+	if req.Email == "" {
+		return users.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type resetPasswordReq struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+func (req resetPasswordReq) validate() error {
+	if req.Token == "" || req.Password == "" {
+		return users.ErrMalformedEntity
+	}
+
+	return nil
+}
@@ -13,7 +13,12 @@ import (
 	"github.com/mainflux/mainflux"
 )
 
-var _ mainflux.Response = (*tokenRes)(nil)
+var (
+	_ mainflux.Response = (*tokenRes)(nil)
+	_ mainflux.Response = (*removeRes)(nil)
+	_ mainflux.Response = (*sessionsRes)(nil)
+	_ mainflux.Response = (*resetRequestRes)(nil)
+)
 
 type tokenRes struct {
 	Token string `json:"token,omitempty"`
@@ -30,3 +35,56 @@ func (res tokenRes) Headers() map[string]string {
 func (res tokenRes) Empty() bool {
 	return res.Token == ""
 }
+
+type removeRes struct{}
+
+func (res removeRes) Code() int {
+	return http.StatusNoContent
+}
+
+func (res removeRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res removeRes) Empty() bool {
+	return true
+}
+
+type resetRequestRes struct{}
+
+func (res resetRequestRes) Code() int {
+	return http.StatusAccepted
+}
+
+func (res resetRequestRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res resetRequestRes) Empty() bool {
+	return true
+}
+
+type sessionRes struct {
+	ID        string `json:"id"`
+	Actor     string `json:"actor,omitempty"`
+	IssuedAt  int64  `json:"issued_at"`
+	ExpiresAt int64  `json:"expires_at"`
+	IP        string `json:"ip,omitempty"`
+	Client    string `json:"client,omitempty"`
+}
+
+type sessionsRes struct {
+	Sessions []sessionRes `json:"sessions"`
+}
+
+func (res sessionsRes) Code() int {
+	return http.StatusOK
+}
+
+func (res sessionsRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res sessionsRes) Empty() bool {
+	return false
+}
@@ -13,12 +13,14 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"strings"
 
 	kithttp "github.com/go-kit/kit/transport/http"
 	"github.com/go-zoo/bone"
 	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/identifiers"
 	log "github.com/mainflux/mainflux/logger"
 	"github.com/mainflux/mainflux/users"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -48,9 +50,51 @@ func MakeHandler(svc users.Service, l log.Logger) http.Handler {
 		opts...,
 	))
 
+	mux.Delete("/users", kithttp.NewServer(
+		removeEndpoint(svc),
+		decodeToken,
+		encodeResponse,
+		opts...,
+	))
+
 	mux.Post("/tokens", kithttp.NewServer(
 		loginEndpoint(svc),
-		decodeCredentials,
+		decodeLogin,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Get("/sessions", kithttp.NewServer(
+		listSessionsEndpoint(svc),
+		decodeToken,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Delete("/sessions/:id", kithttp.NewServer(
+		revokeSessionEndpoint(svc),
+		decodeSession,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Post("/impersonations", kithttp.NewServer(
+		impersonateEndpoint(svc),
+		decodeImpersonation,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Post("/password/reset-request", kithttp.NewServer(
+		requestPasswordResetEndpoint(svc),
+		decodeResetRequest,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Put("/password/reset", kithttp.NewServer(
+		resetPasswordEndpoint(svc),
+		decodePasswordReset,
 		encodeResponse,
 		opts...,
 	))
@@ -76,6 +120,103 @@ func decodeCredentials(_ context.Context, r *http.Request) (interface{}, error)
 	return userReq{user}, nil
 }
 
+func decodeLogin(ctx context.Context, r *http.Request) (interface{}, error) {
+	req, err := decodeCredentials(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	return loginReq{
+		user:   req.(userReq).user,
+		ip:     clientIP(r),
+		client: r.UserAgent(),
+	}, nil
+}
+
+// clientIP returns the address of the client, preferring the one recorded
+// by the reverse proxy, if any.
+func clientIP(r *http.Request) string {
+	if fwd := r.Header.Get("X-Forwarded-For"); fwd != "" {
+		return strings.TrimSpace(strings.Split(fwd, ",")[0])
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+func decodeSession(_ context.Context, r *http.Request) (interface{}, error) {
+	id, err := identifiers.Parse(bone.GetValue(r, "id"))
+	if err != nil {
+		return nil, err
+	}
+
+	req := sessionReq{
+		token: r.Header.Get("Authorization"),
+		id:    id,
+	}
+
+	return req, nil
+}
+
+func decodeToken(_ context.Context, r *http.Request) (interface{}, error) {
+	req := tokenReq{
+		token: r.Header.Get("Authorization"),
+	}
+
+	return req, nil
+}
+
+func decodeImpersonation(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		logger.Warn("Invalid or missing content type.")
+		return nil, errUnsupportedContentType
+	}
+
+	req := impersonateReq{
+		token: r.Header.Get("Authorization"),
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn(fmt.Sprintf("Failed to decode impersonation request: %s", err))
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func decodeResetRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		logger.Warn("Invalid or missing content type.")
+		return nil, errUnsupportedContentType
+	}
+
+	var req resetRequestReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn(fmt.Sprintf("Failed to decode password reset request: %s", err))
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func decodePasswordReset(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		logger.Warn("Invalid or missing content type.")
+		return nil, errUnsupportedContentType
+	}
+
+	var req resetPasswordReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn(fmt.Sprintf("Failed to decode password reset: %s", err))
+		return nil, err
+	}
+
+	return req, nil
+}
+
 func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
 	w.Header().Set("Content-Type", contentType)
 
@@ -98,12 +239,16 @@ func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 	w.Header().Set("Content-Type", contentType)
 
 	switch err {
-	case users.ErrMalformedEntity:
+	case users.ErrMalformedEntity, identifiers.ErrMalformedID:
 		w.WriteHeader(http.StatusBadRequest)
 	case users.ErrUnauthorizedAccess:
 		w.WriteHeader(http.StatusForbidden)
+	case users.ErrNotFound:
+		w.WriteHeader(http.StatusNotFound)
 	case users.ErrConflict:
 		w.WriteHeader(http.StatusConflict)
+	case users.ErrResetUnavailable:
+		w.WriteHeader(http.StatusNotImplemented)
 	case errUnsupportedContentType:
 		w.WriteHeader(http.StatusUnsupportedMediaType)
 	case io.ErrUnexpectedEOF:
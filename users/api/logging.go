@@ -41,9 +41,9 @@ func (lm *loggingMiddleware) Register(user users.User) (err error) {
 	return lm.svc.Register(user)
 }
 
-func (lm *loggingMiddleware) Login(user users.User) (token string, err error) {
+func (lm *loggingMiddleware) Login(user users.User, ip, client string) (token string, err error) {
 	defer func(begin time.Time) {
-		message := fmt.Sprintf("Method login for user %s took %s to complete", user.Email, time.Since(begin))
+		message := fmt.Sprintf("Method login for user %s from %s took %s to complete", user.Email, ip, time.Since(begin))
 		if err != nil {
 			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
 			return
@@ -51,7 +51,7 @@ func (lm *loggingMiddleware) Login(user users.User) (token string, err error) {
 		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
 	}(time.Now())
 
-	return lm.svc.Login(user)
+	return lm.svc.Login(user, ip, client)
 }
 
 func (lm *loggingMiddleware) Identify(key string) (id string, err error) {
@@ -66,3 +66,108 @@ func (lm *loggingMiddleware) Identify(key string) (id string, err error) {
 
 	return lm.svc.Identify(key)
 }
+
+func (lm *loggingMiddleware) Exists(email string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method exists for user %s took %s to complete", email, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Exists(email)
+}
+
+func (lm *loggingMiddleware) Remove(token string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method remove took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Remove(token)
+}
+
+func (lm *loggingMiddleware) Impersonate(token, email string) (key string, err error) {
+	admin, _ := lm.svc.Identify(token)
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method impersonate for user %s by admin %s took %s to complete", email, admin, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Impersonate(token, email)
+}
+
+func (lm *loggingMiddleware) Impersonator(token string) (admin string, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method impersonator for admin %s took %s to complete", admin, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Impersonator(token)
+}
+
+func (lm *loggingMiddleware) ListSessions(token string) (_ []users.Session, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_sessions took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListSessions(token)
+}
+
+func (lm *loggingMiddleware) RevokeSession(token, session string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method revoke_session for session %s took %s to complete", session, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RevokeSession(token, session)
+}
+
+func (lm *loggingMiddleware) RequestPasswordReset(email string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method request_password_reset for user %s took %s to complete", email, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RequestPasswordReset(email)
+}
+
+func (lm *loggingMiddleware) ResetPassword(token, password string) (email string, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method reset_password for user %s took %s to complete", email, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ResetPassword(token, password)
+}
@@ -41,13 +41,13 @@ func (ms *metricsMiddleware) Register(user users.User) error {
 	return ms.svc.Register(user)
 }
 
-func (ms *metricsMiddleware) Login(user users.User) (string, error) {
+func (ms *metricsMiddleware) Login(user users.User, ip, client string) (string, error) {
 	defer func(begin time.Time) {
 		ms.counter.With("method", "login").Add(1)
 		ms.latency.With("method", "login").Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
-	return ms.svc.Login(user)
+	return ms.svc.Login(user, ip, client)
 }
 
 func (ms *metricsMiddleware) Identify(key string) (string, error) {
@@ -58,3 +58,75 @@ func (ms *metricsMiddleware) Identify(key string) (string, error) {
 
 	return ms.svc.Identify(key)
 }
+
+func (ms *metricsMiddleware) Exists(email string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "exists").Add(1)
+		ms.latency.With("method", "exists").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Exists(email)
+}
+
+func (ms *metricsMiddleware) Remove(token string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "remove").Add(1)
+		ms.latency.With("method", "remove").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Remove(token)
+}
+
+func (ms *metricsMiddleware) Impersonate(token, email string) (string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "impersonate").Add(1)
+		ms.latency.With("method", "impersonate").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Impersonate(token, email)
+}
+
+func (ms *metricsMiddleware) Impersonator(token string) (string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "impersonator").Add(1)
+		ms.latency.With("method", "impersonator").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Impersonator(token)
+}
+
+func (ms *metricsMiddleware) ListSessions(token string) ([]users.Session, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_sessions").Add(1)
+		ms.latency.With("method", "list_sessions").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListSessions(token)
+}
+
+func (ms *metricsMiddleware) RevokeSession(token, session string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "revoke_session").Add(1)
+		ms.latency.With("method", "revoke_session").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RevokeSession(token, session)
+}
+
+func (ms *metricsMiddleware) RequestPasswordReset(email string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "request_password_reset").Add(1)
+		ms.latency.With("method", "request_password_reset").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RequestPasswordReset(email)
+}
+
+func (ms *metricsMiddleware) ResetPassword(token, password string) (string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "reset_password").Add(1)
+		ms.latency.With("method", "reset_password").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ResetPassword(token, password)
+}
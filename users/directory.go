@@ -0,0 +1,29 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package users
+
+// DirectoryEntry represents the user account found in the external
+// directory.
+type DirectoryEntry struct {
+	Email  string
+	Groups []string
+}
+
+// Directory specifies an API of the external user directory (e.g. LDAP or
+// Active Directory) that the users lacking the local accounts authenticate
+// against.
+type Directory interface {
+	// Authenticate verifies the user's credentials against the directory
+	// and returns the user's entry. ErrUnauthorizedAccess is returned if
+	// the credentials are invalid.
+	Authenticate(string, string) (DirectoryEntry, error)
+
+	// Lookup retrieves the entry of the user having the provided email.
+	// ErrNotFound is returned if there is no such user in the directory.
+	Lookup(string) (DirectoryEntry, error)
+}
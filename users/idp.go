@@ -10,9 +10,24 @@ package users
 // IdentityProvider specifies an API for identity management via security
 // tokens.
 type IdentityProvider interface {
-	// TemporaryKey generates the temporary access token.
-	TemporaryKey(string) (string, error)
+	// TemporaryKey generates the access token of the session, valid until
+	// the session expires.
+	TemporaryKey(Session) (string, error)
 
 	// Identity extracts the entity identifier given its secret key.
 	Identity(string) (string, error)
+
+	// ImpersonationKey generates the access token of the impersonation
+	// session, that identifies the entity while recording the identifier of
+	// the actor acting on its behalf, valid until the session expires.
+	ImpersonationKey(Session) (string, error)
+
+	// Impersonator extracts the identifier of the actor given the secret
+	// key. An empty identifier is returned for the keys that are not
+	// impersonation keys.
+	Impersonator(string) (string, error)
+
+	// Session extracts the session identifier given the secret key. An
+	// empty identifier is returned for the keys not bound to a session.
+	Session(string) (string, error)
 }
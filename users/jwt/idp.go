@@ -9,19 +9,25 @@
 package jwt
 
 import (
-	"time"
-
 	jwt "github.com/dgrijalva/jwt-go"
 	"github.com/mainflux/mainflux/users"
 )
 
-const (
-	issuer   string        = "mainflux"
-	duration time.Duration = 10 * time.Hour
-)
+const issuer string = "mainflux"
 
 var _ users.IdentityProvider = (*jwtIdentityProvider)(nil)
 
+// impersonationClaims holds the actor claim (RFC 8693) identifying the
+// administrator acting on behalf of the token subject.
+type impersonationClaims struct {
+	jwt.StandardClaims
+	Actor actorClaim `json:"act"`
+}
+
+type actorClaim struct {
+	Subject string `json:"sub"`
+}
+
 type jwtIdentityProvider struct {
 	secret string
 }
@@ -31,21 +37,78 @@ func New(secret string) users.IdentityProvider {
 	return &jwtIdentityProvider{secret}
 }
 
-func (idp *jwtIdentityProvider) TemporaryKey(id string) (string, error) {
-	now := time.Now().UTC()
-	exp := now.Add(duration)
-
+func (idp *jwtIdentityProvider) TemporaryKey(session users.Session) (string, error) {
 	claims := jwt.StandardClaims{
-		Subject:   id,
+		Id:        session.ID,
+		Subject:   session.User,
 		Issuer:    issuer,
-		IssuedAt:  now.Unix(),
-		ExpiresAt: exp.Unix(),
+		IssuedAt:  session.IssuedAt.Unix(),
+		ExpiresAt: session.ExpiresAt.Unix(),
 	}
 
 	return idp.jwt(claims)
 }
 
 func (idp *jwtIdentityProvider) Identity(key string) (string, error) {
+	claims, err := idp.parse(key)
+	if err != nil {
+		return "", err
+	}
+
+	if sub, ok := claims["sub"].(string); ok {
+		return sub, nil
+	}
+
+	return "", users.ErrUnauthorizedAccess
+}
+
+func (idp *jwtIdentityProvider) ImpersonationKey(session users.Session) (string, error) {
+	claims := impersonationClaims{
+		StandardClaims: jwt.StandardClaims{
+			Id:        session.ID,
+			Subject:   session.User,
+			Issuer:    issuer,
+			IssuedAt:  session.IssuedAt.Unix(),
+			ExpiresAt: session.ExpiresAt.Unix(),
+		},
+		Actor: actorClaim{Subject: session.Actor},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(idp.secret))
+}
+
+func (idp *jwtIdentityProvider) Impersonator(key string) (string, error) {
+	claims, err := idp.parse(key)
+	if err != nil {
+		return "", err
+	}
+
+	act, ok := claims["act"].(map[string]interface{})
+	if !ok {
+		return "", nil
+	}
+
+	if sub, ok := act["sub"].(string); ok {
+		return sub, nil
+	}
+
+	return "", users.ErrUnauthorizedAccess
+}
+
+func (idp *jwtIdentityProvider) Session(key string) (string, error) {
+	claims, err := idp.parse(key)
+	if err != nil {
+		return "", err
+	}
+
+	// Tokens issued before the sessions were introduced carry no session
+	// identifier, and remain valid until they expire.
+	jti, _ := claims["jti"].(string)
+	return jti, nil
+}
+
+func (idp *jwtIdentityProvider) parse(key string) (jwt.MapClaims, error) {
 	token, err := jwt.Parse(key, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, users.ErrUnauthorizedAccess
@@ -55,14 +118,14 @@ func (idp *jwtIdentityProvider) Identity(key string) (string, error) {
 	})
 
 	if err != nil {
-		return "", users.ErrUnauthorizedAccess
+		return nil, users.ErrUnauthorizedAccess
 	}
 
 	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		return claims["sub"].(string), nil
+		return claims, nil
 	}
 
-	return "", users.ErrUnauthorizedAccess
+	return nil, users.ErrUnauthorizedAccess
 }
 
 func (idp *jwtIdentityProvider) jwt(claims jwt.StandardClaims) (string, error) {
@@ -0,0 +1,181 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package ldap
+
+import (
+	"bufio"
+	"errors"
+	"io"
+)
+
+// BER tags of the LDAP protocol elements used by the directory client.
+const (
+	tagBoolean       byte = 0x01
+	tagInteger       byte = 0x02
+	tagOctetString   byte = 0x04
+	tagEnumerated    byte = 0x0a
+	tagSequence      byte = 0x30
+	tagSet           byte = 0x31
+	tagBindRequest   byte = 0x60
+	tagBindResponse  byte = 0x61
+	tagUnbind        byte = 0x42
+	tagSearch        byte = 0x63
+	tagSearchEntry   byte = 0x64
+	tagSearchDone    byte = 0x65
+	tagSearchRef     byte = 0x73
+	tagSimpleAuth    byte = 0x80
+	tagEqualityMatch byte = 0xa3
+)
+
+// maxElementSize limits the size of the element read from the server.
+const maxElementSize = 1 << 24
+
+var errMalformedElement = errors.New("malformed BER element")
+
+type element struct {
+	tag     byte
+	content []byte
+}
+
+func encode(tag byte, content []byte) []byte {
+	buf := append([]byte{tag}, encodeLength(len(content))...)
+	return append(buf, content...)
+}
+
+func encodeLength(n int) []byte {
+	if n < 0x80 {
+		return []byte{byte(n)}
+	}
+
+	var buf []byte
+	for ; n > 0; n >>= 8 {
+		buf = append([]byte{byte(n)}, buf...)
+	}
+	return append([]byte{0x80 | byte(len(buf))}, buf...)
+}
+
+func encodeInt(tag byte, n int) []byte {
+	var buf []byte
+	for {
+		buf = append([]byte{byte(n)}, buf...)
+		n >>= 8
+		if n == 0 {
+			break
+		}
+	}
+
+	if buf[0]&0x80 != 0 {
+		buf = append([]byte{0}, buf...)
+	}
+	return encode(tag, buf)
+}
+
+func encodeString(tag byte, s string) []byte {
+	return encode(tag, []byte(s))
+}
+
+func encodeBool(b bool) []byte {
+	if b {
+		return encode(tagBoolean, []byte{0xff})
+	}
+	return encode(tagBoolean, []byte{0})
+}
+
+func encodeSeq(tag byte, parts ...[]byte) []byte {
+	var content []byte
+	for _, p := range parts {
+		content = append(content, p...)
+	}
+	return encode(tag, content)
+}
+
+func readElement(r *bufio.Reader) (element, error) {
+	tag, err := r.ReadByte()
+	if err != nil {
+		return element{}, err
+	}
+
+	l, err := r.ReadByte()
+	if err != nil {
+		return element{}, err
+	}
+
+	length := int(l)
+	if l&0x80 != 0 {
+		n := int(l & 0x7f)
+		if n == 0 || n > 4 {
+			return element{}, errMalformedElement
+		}
+
+		length = 0
+		for i := 0; i < n; i++ {
+			b, err := r.ReadByte()
+			if err != nil {
+				return element{}, err
+			}
+			length = length<<8 | int(b)
+		}
+	}
+
+	if length > maxElementSize {
+		return element{}, errMalformedElement
+	}
+
+	content := make([]byte, length)
+	if _, err := io.ReadFull(r, content); err != nil {
+		return element{}, err
+	}
+
+	return element{tag: tag, content: content}, nil
+}
+
+func parseElements(data []byte) ([]element, error) {
+	var elems []element
+	for len(data) > 0 {
+		if len(data) < 2 {
+			return nil, errMalformedElement
+		}
+
+		tag, l := data[0], data[1]
+		data = data[2:]
+
+		length := int(l)
+		if l&0x80 != 0 {
+			n := int(l & 0x7f)
+			if n == 0 || n > 4 || len(data) < n {
+				return nil, errMalformedElement
+			}
+
+			length = 0
+			for _, b := range data[:n] {
+				length = length<<8 | int(b)
+			}
+			data = data[n:]
+		}
+
+		if length > len(data) {
+			return nil, errMalformedElement
+		}
+
+		elems = append(elems, element{tag: tag, content: data[:length]})
+		data = data[length:]
+	}
+
+	return elems, nil
+}
+
+func decodeInt(content []byte) int {
+	n := 0
+	for i, b := range content {
+		if i == 0 && b&0x80 != 0 {
+			n = -1
+		}
+		n = n<<8 | int(b)
+	}
+	return n
+}
@@ -0,0 +1,389 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package ldap
+
+import (
+	"bufio"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mainflux/mainflux/users"
+)
+
+const (
+	version3           = 3
+	scopeWholeSubtree  = 2
+	derefNever         = 0
+	resultSuccess      = 0
+	invalidCredentials = 49
+	defaultEmailAttr   = "mail"
+	defaultGroupsAttr  = "memberOf"
+	defaultTimeout     = 5 * time.Second
+)
+
+var (
+	errUnsupportedScheme = errors.New("unsupported LDAP URL scheme")
+	errUnexpectedMessage = errors.New("unexpected LDAP message")
+	errAmbiguousEntry    = errors.New("multiple LDAP entries match the email")
+	errServiceBind       = errors.New("invalid LDAP service account credentials")
+)
+
+// Config represents the directory connection and the attribute mapping
+// parameters.
+type Config struct {
+	// URL of the server, using either the ldap or the ldaps scheme.
+	URL string
+
+	// BindDN and BindPassword are the credentials of the service account
+	// used for searching the directory. Anonymous bind is used if BindDN
+	// is empty.
+	BindDN       string
+	BindPassword string
+
+	// BaseDN is the root of the subtree holding the user entries.
+	BaseDN string
+
+	// EmailAttr is the attribute holding the user's email, "mail" by
+	// default.
+	EmailAttr string
+
+	// GroupsAttr is the attribute listing the DNs of the user's groups,
+	// "memberOf" by default.
+	GroupsAttr string
+
+	// Timeout limits the duration of a single directory operation.
+	Timeout time.Duration
+}
+
+var _ users.Directory = (*directory)(nil)
+
+type directory struct {
+	cfg Config
+}
+
+// New instantiates the LDAP (or Active Directory) directory. The user entry
+// is found by its email, using the service account, after which the user's
+// password is verified by binding as the entry.
+func New(cfg Config) users.Directory {
+	if cfg.EmailAttr == "" {
+		cfg.EmailAttr = defaultEmailAttr
+	}
+	if cfg.GroupsAttr == "" {
+		cfg.GroupsAttr = defaultGroupsAttr
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = defaultTimeout
+	}
+
+	return directory{cfg: cfg}
+}
+
+func (d directory) Authenticate(email, password string) (users.DirectoryEntry, error) {
+	// Empty password would result in the unauthenticated bind, which
+	// succeeds for any DN.
+	if password == "" {
+		return users.DirectoryEntry{}, users.ErrUnauthorizedAccess
+	}
+
+	c, err := d.connect()
+	if err != nil {
+		return users.DirectoryEntry{}, err
+	}
+	defer c.close()
+
+	dn, de, err := d.find(c, email)
+	if err != nil {
+		if err == users.ErrNotFound {
+			return users.DirectoryEntry{}, users.ErrUnauthorizedAccess
+		}
+		return users.DirectoryEntry{}, err
+	}
+
+	if err := c.bind(dn, password); err != nil {
+		return users.DirectoryEntry{}, err
+	}
+
+	return de, nil
+}
+
+func (d directory) Lookup(email string) (users.DirectoryEntry, error) {
+	c, err := d.connect()
+	if err != nil {
+		return users.DirectoryEntry{}, err
+	}
+	defer c.close()
+
+	_, de, err := d.find(c, email)
+	return de, err
+}
+
+func (d directory) connect() (*conn, error) {
+	u, err := url.Parse(d.cfg.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	dialer := &net.Dialer{Timeout: d.cfg.Timeout}
+
+	var nc net.Conn
+	switch u.Scheme {
+	case "ldap":
+		nc, err = dialer.Dial("tcp", hostPort(u, "389"))
+	case "ldaps":
+		nc, err = tls.DialWithDialer(dialer, "tcp", hostPort(u, "636"), &tls.Config{ServerName: u.Hostname()})
+	default:
+		return nil, errUnsupportedScheme
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	nc.SetDeadline(time.Now().Add(d.cfg.Timeout))
+	return &conn{nc: nc, r: bufio.NewReader(nc)}, nil
+}
+
+func (d directory) find(c *conn, email string) (string, users.DirectoryEntry, error) {
+	if err := c.bind(d.cfg.BindDN, d.cfg.BindPassword); err != nil {
+		if err == users.ErrUnauthorizedAccess {
+			return "", users.DirectoryEntry{}, errServiceBind
+		}
+		return "", users.DirectoryEntry{}, err
+	}
+
+	entries, err := c.search(d.cfg.BaseDN, d.cfg.EmailAttr, email, d.cfg.EmailAttr, d.cfg.GroupsAttr)
+	if err != nil {
+		return "", users.DirectoryEntry{}, err
+	}
+
+	switch len(entries) {
+	case 0:
+		return "", users.DirectoryEntry{}, users.ErrNotFound
+	case 1:
+	default:
+		return "", users.DirectoryEntry{}, errAmbiguousEntry
+	}
+
+	e := entries[0]
+	de := users.DirectoryEntry{
+		Email:  email,
+		Groups: e.attr(d.cfg.GroupsAttr),
+	}
+
+	return e.dn, de, nil
+}
+
+func hostPort(u *url.URL, port string) string {
+	if u.Port() != "" {
+		return u.Host
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+// resultError represents the unsuccessful LDAP operation result.
+type resultError struct {
+	code int
+	msg  string
+}
+
+func (e resultError) Error() string {
+	return fmt.Sprintf("LDAP result code %d: %s", e.code, e.msg)
+}
+
+type entry struct {
+	dn    string
+	attrs map[string][]string
+}
+
+func (e entry) attr(name string) []string {
+	for k, v := range e.attrs {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+	return nil
+}
+
+type conn struct {
+	nc    net.Conn
+	r     *bufio.Reader
+	msgID int
+}
+
+func (c *conn) close() {
+	c.send(encode(tagUnbind, nil))
+	c.nc.Close()
+}
+
+func (c *conn) send(op []byte) error {
+	c.msgID++
+	_, err := c.nc.Write(encodeSeq(tagSequence, encodeInt(tagInteger, c.msgID), op))
+	return err
+}
+
+func (c *conn) receive() (element, error) {
+	msg, err := readElement(c.r)
+	if err != nil {
+		return element{}, err
+	}
+
+	if msg.tag != tagSequence {
+		return element{}, errUnexpectedMessage
+	}
+
+	elems, err := parseElements(msg.content)
+	if err != nil {
+		return element{}, err
+	}
+
+	if len(elems) < 2 || elems[0].tag != tagInteger || decodeInt(elems[0].content) != c.msgID {
+		return element{}, errUnexpectedMessage
+	}
+
+	return elems[1], nil
+}
+
+func (c *conn) bind(dn, password string) error {
+	req := encodeSeq(tagBindRequest,
+		encodeInt(tagInteger, version3),
+		encodeString(tagOctetString, dn),
+		encodeString(tagSimpleAuth, password),
+	)
+	if err := c.send(req); err != nil {
+		return err
+	}
+
+	op, err := c.receive()
+	if err != nil {
+		return err
+	}
+
+	if op.tag != tagBindResponse {
+		return errUnexpectedMessage
+	}
+
+	if err := result(op); err != nil {
+		if re, ok := err.(resultError); ok && re.code == invalidCredentials {
+			return users.ErrUnauthorizedAccess
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (c *conn) search(base, attr, value string, attrs ...string) ([]entry, error) {
+	var sel [][]byte
+	for _, a := range attrs {
+		sel = append(sel, encodeString(tagOctetString, a))
+	}
+
+	req := encodeSeq(tagSearch,
+		encodeString(tagOctetString, base),
+		encodeInt(tagEnumerated, scopeWholeSubtree),
+		encodeInt(tagEnumerated, derefNever),
+		encodeInt(tagInteger, 2),
+		encodeInt(tagInteger, 0),
+		encodeBool(false),
+		encodeSeq(tagEqualityMatch,
+			encodeString(tagOctetString, attr),
+			encodeString(tagOctetString, value),
+		),
+		encodeSeq(tagSequence, sel...),
+	)
+	if err := c.send(req); err != nil {
+		return nil, err
+	}
+
+	var entries []entry
+	for {
+		op, err := c.receive()
+		if err != nil {
+			return nil, err
+		}
+
+		switch op.tag {
+		case tagSearchEntry:
+			e, err := parseEntry(op)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, e)
+		case tagSearchRef:
+		case tagSearchDone:
+			return entries, result(op)
+		default:
+			return nil, errUnexpectedMessage
+		}
+	}
+}
+
+func result(op element) error {
+	elems, err := parseElements(op.content)
+	if err != nil {
+		return err
+	}
+
+	if len(elems) < 3 || elems[0].tag != tagEnumerated {
+		return errUnexpectedMessage
+	}
+
+	if code := decodeInt(elems[0].content); code != resultSuccess {
+		return resultError{code: code, msg: string(elems[2].content)}
+	}
+
+	return nil
+}
+
+func parseEntry(op element) (entry, error) {
+	elems, err := parseElements(op.content)
+	if err != nil {
+		return entry{}, err
+	}
+
+	if len(elems) < 2 {
+		return entry{}, errUnexpectedMessage
+	}
+
+	e := entry{
+		dn:    string(elems[0].content),
+		attrs: make(map[string][]string),
+	}
+
+	attrs, err := parseElements(elems[1].content)
+	if err != nil {
+		return entry{}, err
+	}
+
+	for _, a := range attrs {
+		parts, err := parseElements(a.content)
+		if err != nil {
+			return entry{}, err
+		}
+
+		if len(parts) < 2 {
+			return entry{}, errUnexpectedMessage
+		}
+
+		vals, err := parseElements(parts[1].content)
+		if err != nil {
+			return entry{}, err
+		}
+
+		name := string(parts[0].content)
+		for _, v := range vals {
+			e.attrs[name] = append(e.attrs[name], string(v.content))
+		}
+	}
+
+	return e, nil
+}
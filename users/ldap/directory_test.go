@@ -0,0 +1,260 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package ldap
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/mainflux/mainflux/users"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	serviceDN   = "cn=service,dc=example,dc=com"
+	servicePass = "service-secret"
+	baseDN      = "dc=example,dc=com"
+	email       = "john@example.com"
+	password    = "john-secret"
+	adminsGroup = "cn=admins,ou=groups,dc=example,dc=com"
+)
+
+type fakeEntry struct {
+	password string
+	attrs    map[string][]string
+}
+
+var entries = map[string]fakeEntry{
+	"uid=john,ou=people,dc=example,dc=com": {
+		password: password,
+		attrs: map[string][]string{
+			"mail":     {email},
+			"memberOf": {adminsGroup},
+		},
+	},
+	"uid=dup1,ou=people,dc=example,dc=com": {
+		password: password,
+		attrs:    map[string][]string{"mail": {"dup@example.com"}},
+	},
+	"uid=dup2,ou=people,dc=example,dc=com": {
+		password: password,
+		attrs:    map[string][]string{"mail": {"dup@example.com"}},
+	},
+}
+
+func startServer(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	go func() {
+		for {
+			c, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go serve(c)
+		}
+	}()
+
+	return fmt.Sprintf("ldap://%s", l.Addr())
+}
+
+func serve(c net.Conn) {
+	defer c.Close()
+	r := bufio.NewReader(c)
+
+	for {
+		msg, err := readElement(r)
+		if err != nil {
+			return
+		}
+
+		elems, err := parseElements(msg.content)
+		if err != nil || len(elems) < 2 {
+			return
+		}
+
+		id := decodeInt(elems[0].content)
+		op := elems[1]
+
+		switch op.tag {
+		case tagBindRequest:
+			parts, _ := parseElements(op.content)
+			dn, pass := string(parts[1].content), string(parts[2].content)
+
+			code := invalidCredentials
+			if dn == serviceDN && pass == servicePass {
+				code = resultSuccess
+			}
+			if e, ok := entries[dn]; ok && e.password == pass {
+				code = resultSuccess
+			}
+			reply(c, id, encodeSeq(tagBindResponse, ldapResult(code)...))
+		case tagSearch:
+			parts, _ := parseElements(op.content)
+			filter, _ := parseElements(parts[6].content)
+			attr, value := string(filter[0].content), string(filter[1].content)
+
+			for dn, e := range entries {
+				for _, v := range e.attrs[attr] {
+					if v == value {
+						reply(c, id, encodeEntry(dn, e.attrs))
+					}
+				}
+			}
+			reply(c, id, encodeSeq(tagSearchDone, ldapResult(resultSuccess)...))
+		default:
+			return
+		}
+	}
+}
+
+func reply(c net.Conn, id int, op []byte) {
+	c.Write(encodeSeq(tagSequence, encodeInt(tagInteger, id), op))
+}
+
+func ldapResult(code int) [][]byte {
+	return [][]byte{
+		encodeInt(tagEnumerated, code),
+		encodeString(tagOctetString, ""),
+		encodeString(tagOctetString, ""),
+	}
+}
+
+func encodeEntry(dn string, attrs map[string][]string) []byte {
+	var list [][]byte
+	for name, vals := range attrs {
+		var encoded [][]byte
+		for _, v := range vals {
+			encoded = append(encoded, encodeString(tagOctetString, v))
+		}
+		list = append(list, encodeSeq(tagSequence, encodeString(tagOctetString, name), encodeSeq(tagSet, encoded...)))
+	}
+
+	return encodeSeq(tagSearchEntry, encodeString(tagOctetString, dn), encodeSeq(tagSequence, list...))
+}
+
+func TestAuthenticate(t *testing.T) {
+	url := startServer(t)
+	dir := New(Config{URL: url, BindDN: serviceDN, BindPassword: servicePass, BaseDN: baseDN})
+
+	cases := []struct {
+		desc     string
+		email    string
+		password string
+		entry    users.DirectoryEntry
+		err      error
+	}{
+		{
+			desc:     "authenticate with valid credentials",
+			email:    email,
+			password: password,
+			entry:    users.DirectoryEntry{Email: email, Groups: []string{adminsGroup}},
+			err:      nil,
+		},
+		{
+			desc:     "authenticate with wrong password",
+			email:    email,
+			password: "wrong",
+			err:      users.ErrUnauthorizedAccess,
+		},
+		{
+			desc:     "authenticate with empty password",
+			email:    email,
+			password: "",
+			err:      users.ErrUnauthorizedAccess,
+		},
+		{
+			desc:     "authenticate unknown user",
+			email:    "unknown@example.com",
+			password: password,
+			err:      users.ErrUnauthorizedAccess,
+		},
+		{
+			desc:     "authenticate user with ambiguous email",
+			email:    "dup@example.com",
+			password: password,
+			err:      errAmbiguousEntry,
+		},
+	}
+
+	for _, tc := range cases {
+		entry, err := dir.Authenticate(tc.email, tc.password)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+		assert.Equal(t, tc.entry, entry, fmt.Sprintf("%s: expected %v got %v", tc.desc, tc.entry, entry))
+	}
+}
+
+func TestLookup(t *testing.T) {
+	url := startServer(t)
+
+	cases := []struct {
+		desc  string
+		cfg   Config
+		email string
+		err   error
+	}{
+		{
+			desc:  "lookup existing user",
+			cfg:   Config{URL: url, BindDN: serviceDN, BindPassword: servicePass, BaseDN: baseDN},
+			email: email,
+			err:   nil,
+		},
+		{
+			desc:  "lookup non-existing user",
+			cfg:   Config{URL: url, BindDN: serviceDN, BindPassword: servicePass, BaseDN: baseDN},
+			email: "unknown@example.com",
+			err:   users.ErrNotFound,
+		},
+		{
+			desc:  "lookup with invalid service account",
+			cfg:   Config{URL: url, BindDN: serviceDN, BindPassword: "wrong", BaseDN: baseDN},
+			email: email,
+			err:   errServiceBind,
+		},
+		{
+			desc:  "lookup with unsupported URL scheme",
+			cfg:   Config{URL: "http://localhost", BaseDN: baseDN},
+			email: email,
+			err:   errUnsupportedScheme,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := New(tc.cfg).Lookup(tc.email)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+	}
+}
+
+func TestBER(t *testing.T) {
+	long := make([]byte, 300)
+	for i := range long {
+		long[i] = 'a'
+	}
+
+	cases := []struct {
+		desc string
+		data []byte
+	}{
+		{"short form length", encodeString(tagOctetString, "value")},
+		{"long form length", encodeString(tagOctetString, string(long))},
+		{"integer with high bit set", encodeInt(tagInteger, 200)},
+	}
+
+	for _, tc := range cases {
+		elems, err := parseElements(tc.data)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		assert.Len(t, elems, 1, fmt.Sprintf("%s: expected single element", tc.desc))
+	}
+
+	elems, _ := parseElements(encodeInt(tagInteger, 200))
+	assert.Equal(t, 200, decodeInt(elems[0].content), "integer decoded incorrectly")
+}
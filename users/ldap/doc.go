@@ -0,0 +1,10 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// Package ldap contains the LDAP (and Active Directory) implementation of the
+// users directory, relying on the simple bind for verifying the credentials.
+package ldap
@@ -0,0 +1,42 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package mocks
+
+import "github.com/mainflux/mainflux/users"
+
+var _ users.Directory = (*directoryMock)(nil)
+
+type directoryMock struct {
+	passwords map[string]string
+	groups    map[string][]string
+}
+
+// NewDirectory creates the directory mock holding the users having the
+// provided passwords and group memberships.
+func NewDirectory(passwords map[string]string, groups map[string][]string) users.Directory {
+	return directoryMock{
+		passwords: passwords,
+		groups:    groups,
+	}
+}
+
+func (dm directoryMock) Authenticate(email, password string) (users.DirectoryEntry, error) {
+	if p, ok := dm.passwords[email]; !ok || p != password || password == "" {
+		return users.DirectoryEntry{}, users.ErrUnauthorizedAccess
+	}
+
+	return users.DirectoryEntry{Email: email, Groups: dm.groups[email]}, nil
+}
+
+func (dm directoryMock) Lookup(email string) (users.DirectoryEntry, error) {
+	if _, ok := dm.passwords[email]; !ok {
+		return users.DirectoryEntry{}, users.ErrNotFound
+	}
+
+	return users.DirectoryEntry{Email: email, Groups: dm.groups[email]}, nil
+}
@@ -7,7 +7,21 @@
 
 package mocks
 
-import "github.com/mainflux/mainflux/users"
+import (
+	"strings"
+
+	"github.com/mainflux/mainflux/users"
+)
+
+const (
+	// actorSep separates the identifiers of the subject and the actor within
+	// the impersonation keys.
+	actorSep = "|"
+
+	// sessionSep separates the identifiers of the subject and the session
+	// within the session keys.
+	sessionSep = "#"
+)
 
 var _ users.IdentityProvider = (*identityProviderMock)(nil)
 
@@ -19,7 +33,16 @@ func NewIdentityProvider() users.IdentityProvider {
 	return &identityProviderMock{}
 }
 
-func (idp *identityProviderMock) TemporaryKey(id string) (string, error) {
+func (idp *identityProviderMock) TemporaryKey(session users.Session) (string, error) {
+	if session.User == "" {
+		return "", users.ErrUnauthorizedAccess
+	}
+
+	return session.User + sessionSep + session.ID, nil
+}
+
+func (idp *identityProviderMock) Identity(key string) (string, error) {
+	id := strings.Split(strings.Split(key, actorSep)[0], sessionSep)[0]
 	if id == "" {
 		return "", users.ErrUnauthorizedAccess
 	}
@@ -27,6 +50,28 @@ func (idp *identityProviderMock) TemporaryKey(id string) (string, error) {
 	return id, nil
 }
 
-func (idp *identityProviderMock) Identity(key string) (string, error) {
-	return idp.TemporaryKey(key)
+func (idp *identityProviderMock) ImpersonationKey(session users.Session) (string, error) {
+	if session.User == "" || session.Actor == "" {
+		return "", users.ErrUnauthorizedAccess
+	}
+
+	return session.User + sessionSep + session.ID + actorSep + session.Actor, nil
+}
+
+func (idp *identityProviderMock) Impersonator(key string) (string, error) {
+	parts := strings.Split(key, actorSep)
+	if len(parts) < 2 {
+		return "", nil
+	}
+
+	return parts[1], nil
+}
+
+func (idp *identityProviderMock) Session(key string) (string, error) {
+	parts := strings.Split(strings.Split(key, actorSep)[0], sessionSep)
+	if len(parts) < 2 {
+		return "", nil
+	}
+
+	return parts[1], nil
 }
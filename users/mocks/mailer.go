@@ -0,0 +1,47 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package mocks
+
+import (
+	"sync"
+
+	"github.com/mainflux/mainflux/users"
+)
+
+var _ users.Mailer = (*Mailer)(nil)
+
+// Mailer is the mailer mock keeping the last reset token sent to every
+// user, instead of delivering it.
+type Mailer struct {
+	mu     sync.Mutex
+	tokens map[string]string
+}
+
+// NewMailer creates the mailer mock.
+func NewMailer() *Mailer {
+	return &Mailer{
+		tokens: make(map[string]string),
+	}
+}
+
+// SendResetToken records the reset token sent to the user.
+func (m *Mailer) SendResetToken(email, token string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.tokens[email] = token
+	return nil
+}
+
+// Token returns the last reset token sent to the user.
+func (m *Mailer) Token(email string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.tokens[email]
+}
@@ -0,0 +1,57 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package mocks
+
+import (
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux/users"
+)
+
+var _ users.ResetTokenRepository = (*resetTokenRepositoryMock)(nil)
+
+type resetTokenRepositoryMock struct {
+	mu     sync.Mutex
+	tokens map[string]users.ResetToken
+}
+
+// NewResetTokenRepository creates in-memory password reset tokens
+// repository.
+func NewResetTokenRepository() users.ResetTokenRepository {
+	return &resetTokenRepositoryMock{
+		tokens: make(map[string]users.ResetToken),
+	}
+}
+
+func (rtrm *resetTokenRepositoryMock) Save(token users.ResetToken) error {
+	rtrm.mu.Lock()
+	defer rtrm.mu.Unlock()
+
+	for hash, t := range rtrm.tokens {
+		if t.User == token.User {
+			delete(rtrm.tokens, hash)
+		}
+	}
+
+	rtrm.tokens[token.Hash] = token
+	return nil
+}
+
+func (rtrm *resetTokenRepositoryMock) Consume(hash string) (string, error) {
+	rtrm.mu.Lock()
+	defer rtrm.mu.Unlock()
+
+	t, ok := rtrm.tokens[hash]
+	if !ok || t.ExpiresAt.Before(time.Now()) {
+		return "", users.ErrNotFound
+	}
+
+	delete(rtrm.tokens, hash)
+	return t.User, nil
+}
@@ -0,0 +1,100 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package mocks
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux/users"
+)
+
+var _ users.SessionRepository = (*sessionRepositoryMock)(nil)
+
+type sessionRepositoryMock struct {
+	mu       sync.Mutex
+	sessions map[string]map[string]users.Session
+}
+
+// NewSessionRepository creates in-memory sessions repository.
+func NewSessionRepository() users.SessionRepository {
+	return &sessionRepositoryMock{
+		sessions: make(map[string]map[string]users.Session),
+	}
+}
+
+func (srm *sessionRepositoryMock) Save(session users.Session) error {
+	srm.mu.Lock()
+	defer srm.mu.Unlock()
+
+	if _, ok := srm.sessions[session.User]; !ok {
+		srm.sessions[session.User] = make(map[string]users.Session)
+	}
+
+	now := time.Now()
+	for id, s := range srm.sessions[session.User] {
+		if s.ExpiresAt.Before(now) {
+			delete(srm.sessions[session.User], id)
+		}
+	}
+
+	srm.sessions[session.User][session.ID] = session
+	return nil
+}
+
+func (srm *sessionRepositoryMock) RetrieveByID(user, id string) (users.Session, error) {
+	srm.mu.Lock()
+	defer srm.mu.Unlock()
+
+	s, ok := srm.sessions[user][id]
+	if !ok || s.ExpiresAt.Before(time.Now()) {
+		return users.Session{}, users.ErrNotFound
+	}
+
+	return s, nil
+}
+
+func (srm *sessionRepositoryMock) RetrieveAll(user string) ([]users.Session, error) {
+	srm.mu.Lock()
+	defer srm.mu.Unlock()
+
+	now := time.Now()
+	items := []users.Session{}
+	for _, s := range srm.sessions[user] {
+		if !s.ExpiresAt.Before(now) {
+			items = append(items, s)
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].IssuedAt.After(items[j].IssuedAt)
+	})
+
+	return items, nil
+}
+
+func (srm *sessionRepositoryMock) Remove(user, id string) error {
+	srm.mu.Lock()
+	defer srm.mu.Unlock()
+
+	if _, ok := srm.sessions[user][id]; !ok {
+		return users.ErrNotFound
+	}
+
+	delete(srm.sessions[user], id)
+	return nil
+}
+
+func (srm *sessionRepositoryMock) RemoveAll(user string) error {
+	srm.mu.Lock()
+	defer srm.mu.Unlock()
+
+	delete(srm.sessions, user)
+	return nil
+}
@@ -50,3 +50,27 @@ func (urm *userRepositoryMock) RetrieveByID(email string) (users.User, error) {
 
 	return val, nil
 }
+
+func (urm *userRepositoryMock) UpdatePassword(user users.User) error {
+	urm.mu.Lock()
+	defer urm.mu.Unlock()
+
+	if _, ok := urm.users[user.Email]; !ok {
+		return users.ErrNotFound
+	}
+
+	urm.users[user.Email] = user
+	return nil
+}
+
+func (urm *userRepositoryMock) Remove(email string) error {
+	urm.mu.Lock()
+	defer urm.mu.Unlock()
+
+	if _, ok := urm.users[email]; !ok {
+		return users.ErrNotFound
+	}
+
+	delete(urm.users, email)
+	return nil
+}
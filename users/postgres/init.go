@@ -11,6 +11,7 @@ import (
 	"fmt"
 
 	"github.com/jmoiron/sqlx"
+	mfpostgres "github.com/mainflux/mainflux/postgres"
 
 	_ "github.com/lib/pq" // required for SQL access
 	migrate "github.com/rubenv/sql-migrate"
@@ -27,15 +28,23 @@ type Config struct {
 	SSLCert     string
 	SSLKey      string
 	SSLRootCert string
+	Target      string
+	SyncCommit  string
 }
 
 // Connect creates a connection to the PostgreSQL instance and applies any
 // unapplied database migrations. A non-nil error is returned to indicate
 // failure.
 func Connect(cfg Config) (*sqlx.DB, error) {
-	url := fmt.Sprintf("host=%s port=%s user=%s dbname=%s password=%s sslmode=%s sslcert=%s sslkey=%s sslrootcert=%s", cfg.Host, cfg.Port, cfg.User, cfg.Name, cfg.Pass, cfg.SSLMode, cfg.SSLCert, cfg.SSLKey, cfg.SSLRootCert)
+	url := fmt.Sprintf("user=%s dbname=%s password=%s sslmode=%s sslcert=%s sslkey=%s sslrootcert=%s", cfg.User, cfg.Name, cfg.Pass, cfg.SSLMode, cfg.SSLCert, cfg.SSLKey, cfg.SSLRootCert)
 
-	db, err := sqlx.Open("postgres", url)
+	db, err := mfpostgres.Open(mfpostgres.Config{
+		Hosts:      cfg.Host,
+		Port:       cfg.Port,
+		DSN:        url,
+		Target:     cfg.Target,
+		SyncCommit: cfg.SyncCommit,
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -59,6 +68,39 @@ func migrateDB(db *sqlx.DB) error {
 				},
 				Down: []string{"DROP TABLE users"},
 			},
+			{
+				Id: "users_2",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS sessions (
+						id         UUID         PRIMARY KEY,
+						email      VARCHAR(254) NOT NULL REFERENCES users (email) ON DELETE CASCADE,
+						issued_at  TIMESTAMP    NOT NULL,
+						expires_at TIMESTAMP    NOT NULL,
+						ip         TEXT         NOT NULL DEFAULT '',
+						client     TEXT         NOT NULL DEFAULT ''
+					)`,
+					`CREATE INDEX IF NOT EXISTS sessions_email_idx ON sessions (email)`,
+				},
+				Down: []string{"DROP TABLE sessions"},
+			},
+			{
+				Id: "users_3",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS resets (
+						email      VARCHAR(254) PRIMARY KEY REFERENCES users (email) ON DELETE CASCADE,
+						hash       CHAR(64)     UNIQUE NOT NULL,
+						expires_at TIMESTAMP    NOT NULL
+					)`,
+				},
+				Down: []string{"DROP TABLE resets"},
+			},
+			{
+				Id: "users_4",
+				Up: []string{
+					`ALTER TABLE sessions ADD COLUMN IF NOT EXISTS actor VARCHAR(254) NOT NULL DEFAULT ''`,
+				},
+				Down: []string{"ALTER TABLE sessions DROP COLUMN actor"},
+			},
 		},
 	}
 
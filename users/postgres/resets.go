@@ -0,0 +1,71 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/mainflux/mainflux/users"
+)
+
+var _ users.ResetTokenRepository = (*resetTokenRepository)(nil)
+
+type resetTokenRepository struct {
+	db *sqlx.DB
+}
+
+// NewResetTokenRepository instantiates a PostgreSQL implementation of
+// password reset tokens repository.
+func NewResetTokenRepository(db *sqlx.DB) users.ResetTokenRepository {
+	return &resetTokenRepository{db}
+}
+
+func (rr resetTokenRepository) Save(token users.ResetToken) error {
+	q := `INSERT INTO resets (email, hash, expires_at) VALUES (:email, :hash, :expires_at)
+	      ON CONFLICT (email) DO UPDATE SET hash = EXCLUDED.hash, expires_at = EXCLUDED.expires_at`
+
+	if _, err := rr.db.NamedExec(q, toDBReset(token)); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && errFK == pqErr.Code.Name() {
+			return users.ErrNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (rr resetTokenRepository) Consume(hash string) (string, error) {
+	q := `DELETE FROM resets WHERE hash = $1 AND expires_at >= $2 RETURNING email`
+
+	var email string
+	if err := rr.db.QueryRowx(q, hash, time.Now().UTC()).Scan(&email); err != nil {
+		if err == sql.ErrNoRows {
+			return "", users.ErrNotFound
+		}
+		return "", err
+	}
+
+	return email, nil
+}
+
+type dbReset struct {
+	Email     string    `db:"email"`
+	Hash      string    `db:"hash"`
+	ExpiresAt time.Time `db:"expires_at"`
+}
+
+func toDBReset(t users.ResetToken) dbReset {
+	return dbReset{
+		Email:     t.User,
+		Hash:      t.Hash,
+		ExpiresAt: t.ExpiresAt,
+	}
+}
@@ -0,0 +1,105 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package postgres_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/users"
+	"github.com/mainflux/mainflux/users/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newResetToken(email, token string, expiresAt time.Time) users.ResetToken {
+	sum := sha256.Sum256([]byte(token))
+	return users.ResetToken{
+		Hash:      hex.EncodeToString(sum[:]),
+		User:      email,
+		ExpiresAt: expiresAt,
+	}
+}
+
+func TestResetTokenSave(t *testing.T) {
+	email := "reset-save@example.com"
+	err := postgres.New(db).Save(users.User{Email: email, Password: "pass"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	expiresAt := time.Now().UTC().Add(time.Hour)
+
+	cases := []struct {
+		desc  string
+		token users.ResetToken
+		err   error
+	}{
+		{
+			desc:  "save reset token",
+			token: newResetToken(email, "first", expiresAt),
+			err:   nil,
+		},
+		{
+			desc:  "replace reset token",
+			token: newResetToken(email, "second", expiresAt),
+			err:   nil,
+		},
+		{
+			desc:  "save reset token of non-existing user",
+			token: newResetToken("unknown@example.com", "third", expiresAt),
+			err:   users.ErrNotFound,
+		},
+	}
+
+	repo := postgres.NewResetTokenRepository(db)
+
+	for _, tc := range cases {
+		err := repo.Save(tc.token)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestResetTokenConsume(t *testing.T) {
+	email := "reset-consume@example.com"
+	expiredEmail := "reset-consume-expired@example.com"
+	for _, e := range []string{email, expiredEmail} {
+		err := postgres.New(db).Save(users.User{Email: e, Password: "pass"})
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	repo := postgres.NewResetTokenRepository(db)
+
+	now := time.Now().UTC()
+	replaced := newResetToken(email, "replaced", now.Add(time.Hour))
+	active := newResetToken(email, "active", now.Add(time.Hour))
+	expired := newResetToken(expiredEmail, "expired", now.Add(-time.Hour))
+	for _, token := range []users.ResetToken{replaced, active, expired} {
+		err := repo.Save(token)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	cases := []struct {
+		desc  string
+		hash  string
+		email string
+		err   error
+	}{
+		{"consume replaced token", replaced.Hash, "", users.ErrNotFound},
+		{"consume expired token", expired.Hash, "", users.ErrNotFound},
+		{"consume active token", active.Hash, email, nil},
+		{"consume consumed token", active.Hash, "", users.ErrNotFound},
+	}
+
+	for _, tc := range cases {
+		email, err := repo.Consume(tc.hash)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.email, email, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.email, email))
+	}
+}
@@ -0,0 +1,163 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/mainflux/mainflux/users"
+)
+
+const (
+	errFK      = "foreign_key_violation"
+	errInvalid = "invalid_text_representation"
+)
+
+var _ users.SessionRepository = (*sessionRepository)(nil)
+
+type sessionRepository struct {
+	db *sqlx.DB
+}
+
+// NewSessionRepository instantiates a PostgreSQL implementation of sessions
+// repository.
+func NewSessionRepository(db *sqlx.DB) users.SessionRepository {
+	return &sessionRepository{db}
+}
+
+func (sr sessionRepository) Save(session users.Session) error {
+	tx, err := sr.db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	q := `DELETE FROM sessions WHERE email = $1 AND expires_at < $2`
+	if _, err := tx.Exec(q, session.User, time.Now().UTC()); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	q = `INSERT INTO sessions (id, email, actor, issued_at, expires_at, ip, client)
+	     VALUES (:id, :email, :actor, :issued_at, :expires_at, :ip, :client)`
+
+	if _, err := tx.NamedExec(q, toDBSession(session)); err != nil {
+		tx.Rollback()
+		if pqErr, ok := err.(*pq.Error); ok && errFK == pqErr.Code.Name() {
+			return users.ErrNotFound
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (sr sessionRepository) RetrieveByID(email, id string) (users.Session, error) {
+	q := `SELECT id, email, actor, issued_at, expires_at, ip, client FROM sessions
+	      WHERE id = $1 AND email = $2 AND expires_at >= $3`
+
+	var dbs dbSession
+	if err := sr.db.QueryRowx(q, id, email, time.Now().UTC()).StructScan(&dbs); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if err == sql.ErrNoRows || ok && errInvalid == pqErr.Code.Name() {
+			return users.Session{}, users.ErrNotFound
+		}
+		return users.Session{}, err
+	}
+
+	return toSession(dbs), nil
+}
+
+func (sr sessionRepository) RetrieveAll(email string) ([]users.Session, error) {
+	q := `SELECT id, email, actor, issued_at, expires_at, ip, client FROM sessions
+	      WHERE email = $1 AND expires_at >= $2 ORDER BY issued_at DESC`
+
+	rows, err := sr.db.Queryx(q, email, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []users.Session{}
+	for rows.Next() {
+		var dbs dbSession
+		if err := rows.StructScan(&dbs); err != nil {
+			return nil, err
+		}
+
+		items = append(items, toSession(dbs))
+	}
+
+	return items, nil
+}
+
+func (sr sessionRepository) Remove(email, id string) error {
+	q := `DELETE FROM sessions WHERE id = $1 AND email = $2`
+
+	res, err := sr.db.Exec(q, id, email)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && errInvalid == pqErr.Code.Name() {
+			return users.ErrNotFound
+		}
+		return err
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if cnt == 0 {
+		return users.ErrNotFound
+	}
+
+	return nil
+}
+
+func (sr sessionRepository) RemoveAll(email string) error {
+	q := `DELETE FROM sessions WHERE email = $1`
+
+	_, err := sr.db.Exec(q, email)
+	return err
+}
+
+type dbSession struct {
+	ID        string    `db:"id"`
+	Email     string    `db:"email"`
+	Actor     string    `db:"actor"`
+	IssuedAt  time.Time `db:"issued_at"`
+	ExpiresAt time.Time `db:"expires_at"`
+	IP        string    `db:"ip"`
+	Client    string    `db:"client"`
+}
+
+func toDBSession(s users.Session) dbSession {
+	return dbSession{
+		ID:        s.ID,
+		Email:     s.User,
+		Actor:     s.Actor,
+		IssuedAt:  s.IssuedAt,
+		ExpiresAt: s.ExpiresAt,
+		IP:        s.IP,
+		Client:    s.Client,
+	}
+}
+
+func toSession(dbs dbSession) users.Session {
+	return users.Session{
+		ID:        dbs.ID,
+		User:      dbs.Email,
+		Actor:     dbs.Actor,
+		IssuedAt:  dbs.IssuedAt.UTC(),
+		ExpiresAt: dbs.ExpiresAt.UTC(),
+		IP:        dbs.IP,
+		Client:    dbs.Client,
+	}
+}
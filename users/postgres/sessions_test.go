@@ -0,0 +1,170 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package postgres_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/mainflux/mainflux/users"
+	"github.com/mainflux/mainflux/users/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSession(t *testing.T, email string, expiresAt time.Time) users.Session {
+	id, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	return users.Session{
+		ID:        id.String(),
+		User:      email,
+		IssuedAt:  expiresAt.Add(-time.Hour),
+		ExpiresAt: expiresAt,
+		IP:        "192.168.0.1",
+		Client:    "curl/7.58.0",
+	}
+}
+
+func TestSessionSave(t *testing.T) {
+	email := "session-save@example.com"
+	err := postgres.New(db).Save(users.User{Email: email, Password: "pass"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	expiresAt := time.Now().UTC().Add(time.Hour).Truncate(time.Second)
+	session := newSession(t, email, expiresAt)
+
+	cases := []struct {
+		desc    string
+		session users.Session
+		err     error
+	}{
+		{
+			desc:    "save new session",
+			session: session,
+			err:     nil,
+		},
+		{
+			desc:    "save session of non-existing user",
+			session: newSession(t, "unknown@example.com", expiresAt),
+			err:     users.ErrNotFound,
+		},
+	}
+
+	repo := postgres.NewSessionRepository(db)
+
+	for _, tc := range cases {
+		err := repo.Save(tc.session)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestSessionRetrieveByID(t *testing.T) {
+	email := "session-retrieval@example.com"
+	err := postgres.New(db).Save(users.User{Email: email, Password: "pass"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	repo := postgres.NewSessionRepository(db)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	active := newSession(t, email, now.Add(time.Hour))
+	expired := newSession(t, email, now.Add(-time.Hour))
+	impersonation := newSession(t, email, now.Add(time.Hour))
+	impersonation.Actor = "admin@example.com"
+	for _, s := range []users.Session{expired, active, impersonation} {
+		err := repo.Save(s)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	cases := map[string]struct {
+		email string
+		id    string
+		err   error
+	}{
+		"retrieve active session":               {email, active.ID, nil},
+		"retrieve expired session":              {email, expired.ID, users.ErrNotFound},
+		"retrieve session of another user":      {"unknown@example.com", active.ID, users.ErrNotFound},
+		"retrieve session with malformed id":    {email, wrong, users.ErrNotFound},
+		"retrieve session with non-existing id": {email, "123e4567-e89b-12d3-a456-000000000042", users.ErrNotFound},
+	}
+
+	for desc, tc := range cases {
+		_, err := repo.RetrieveByID(tc.email, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+
+	s, err := repo.RetrieveByID(email, impersonation.ID)
+	assert.Nil(t, err, fmt.Sprintf("retrieve impersonation session: unexpected error %s\n", err))
+	assert.Equal(t, impersonation, s, fmt.Sprintf("retrieve impersonation session: expected %v got %v\n", impersonation, s))
+}
+
+func TestSessionRetrieveAll(t *testing.T) {
+	email := "session-retrieval-all@example.com"
+	err := postgres.New(db).Save(users.User{Email: email, Password: "pass"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	repo := postgres.NewSessionRepository(db)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	n := 3
+	for i := 0; i < n; i++ {
+		err := repo.Save(newSession(t, email, now.Add(time.Duration(i+1)*time.Hour)))
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	sessions, err := repo.RetrieveAll(email)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Len(t, sessions, n, fmt.Sprintf("expected %d sessions got %d", n, len(sessions)))
+}
+
+func TestSessionRemove(t *testing.T) {
+	email := "session-removal@example.com"
+	err := postgres.New(db).Save(users.User{Email: email, Password: "pass"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	repo := postgres.NewSessionRepository(db)
+	session := newSession(t, email, time.Now().UTC().Add(time.Hour))
+	err = repo.Save(session)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc string
+		id   string
+		err  error
+	}{
+		{"remove existing session", session.ID, nil},
+		{"remove removed session", session.ID, users.ErrNotFound},
+		{"remove session with malformed id", wrong, users.ErrNotFound},
+	}
+
+	for _, tc := range cases {
+		err := repo.Remove(email, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestSessionRemoveAll(t *testing.T) {
+	email := "session-removal-all@example.com"
+	err := postgres.New(db).Save(users.User{Email: email, Password: "pass"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	repo := postgres.NewSessionRepository(db)
+	for i := 0; i < 3; i++ {
+		err := repo.Save(newSession(t, email, time.Now().UTC().Add(time.Hour)))
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	err = repo.RemoveAll(email)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	sessions, err := repo.RetrieveAll(email)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Empty(t, sessions, fmt.Sprintf("expected no sessions got %d", len(sessions)))
+}
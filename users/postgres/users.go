@@ -62,6 +62,46 @@ func (ur userRepository) RetrieveByID(email string) (users.User, error) {
 	return user, nil
 }
 
+func (ur userRepository) UpdatePassword(user users.User) error {
+	q := `UPDATE users SET password = :password WHERE email = :email`
+
+	res, err := ur.db.NamedExec(q, toDBUser(user))
+	if err != nil {
+		return err
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if cnt == 0 {
+		return users.ErrNotFound
+	}
+
+	return nil
+}
+
+func (ur userRepository) Remove(email string) error {
+	q := `DELETE FROM users WHERE email = $1`
+
+	res, err := ur.db.Exec(q, email)
+	if err != nil {
+		return err
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if cnt == 0 {
+		return users.ErrNotFound
+	}
+
+	return nil
+}
+
 type dbUser struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
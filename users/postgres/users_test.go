@@ -74,3 +74,58 @@ func TestSingleUserRetrieval(t *testing.T) {
 		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
 	}
 }
+
+func TestUserRemove(t *testing.T) {
+	email := "user-remove@example.com"
+
+	repo := postgres.New(db)
+	err := repo.Save(users.User{
+		Email:    email,
+		Password: "pass",
+	})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		email string
+		err   error
+	}{
+		{"remove existing user", email, nil},
+		{"remove removed user", email, users.ErrNotFound},
+		{"remove non-existing user", "unknown@example.com", users.ErrNotFound},
+	}
+
+	for _, tc := range cases {
+		err := repo.Remove(tc.email)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestUserUpdatePassword(t *testing.T) {
+	email := "user-update-password@example.com"
+
+	repo := postgres.New(db)
+	err := repo.Save(users.User{
+		Email:    email,
+		Password: "pass",
+	})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc string
+		user users.User
+		err  error
+	}{
+		{"update password of existing user", users.User{Email: email, Password: "new"}, nil},
+		{"update password of non-existing user", users.User{Email: "unknown@example.com", Password: "new"}, users.ErrNotFound},
+	}
+
+	for _, tc := range cases {
+		err := repo.UpdatePassword(tc.user)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	user, err := repo.RetrieveByID(email)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, "new", user.Password, fmt.Sprintf("expected password new got %s", user.Password))
+}
@@ -0,0 +1,10 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// Package redis contains the event store implementation using Redis Streams
+// as the underlying transport.
+package redis
@@ -0,0 +1,88 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package redis
+
+const (
+	userPrefix        = "user."
+	userRegister      = userPrefix + "register"
+	userRemove        = userPrefix + "remove"
+	userImpersonate   = userPrefix + "impersonate"
+	userRevokeSession = userPrefix + "revoke_session"
+	userResetPassword = userPrefix + "password_reset"
+)
+
+type event interface {
+	Encode() map[string]interface{}
+}
+
+var (
+	_ event = (*registerUserEvent)(nil)
+	_ event = (*removeUserEvent)(nil)
+	_ event = (*impersonateUserEvent)(nil)
+	_ event = (*revokeSessionEvent)(nil)
+	_ event = (*resetPasswordEvent)(nil)
+)
+
+type registerUserEvent struct {
+	email string
+}
+
+func (rue registerUserEvent) Encode() map[string]interface{} {
+	return map[string]interface{}{
+		"email":     rue.email,
+		"operation": userRegister,
+	}
+}
+
+type removeUserEvent struct {
+	email string
+}
+
+func (rue removeUserEvent) Encode() map[string]interface{} {
+	return map[string]interface{}{
+		"email":     rue.email,
+		"operation": userRemove,
+	}
+}
+
+type impersonateUserEvent struct {
+	email string
+	admin string
+}
+
+func (iue impersonateUserEvent) Encode() map[string]interface{} {
+	return map[string]interface{}{
+		"email":     iue.email,
+		"admin":     iue.admin,
+		"operation": userImpersonate,
+	}
+}
+
+type revokeSessionEvent struct {
+	email   string
+	session string
+}
+
+func (rse revokeSessionEvent) Encode() map[string]interface{} {
+	return map[string]interface{}{
+		"email":     rse.email,
+		"session":   rse.session,
+		"operation": userRevokeSession,
+	}
+}
+
+type resetPasswordEvent struct {
+	email string
+}
+
+func (rpe resetPasswordEvent) Encode() map[string]interface{} {
+	return map[string]interface{}{
+		"email":     rpe.email,
+		"operation": userResetPassword,
+	}
+}
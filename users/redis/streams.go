@@ -0,0 +1,152 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package redis
+
+import (
+	"github.com/go-redis/redis"
+	"github.com/mainflux/mainflux/users"
+)
+
+const (
+	streamID  = "mainflux.users"
+	streamLen = 1000
+)
+
+var _ users.Service = (*eventStore)(nil)
+
+type eventStore struct {
+	svc    users.Service
+	client redis.UniversalClient
+}
+
+// NewEventStoreMiddleware returns wrapper around users service that sends
+// events to event store.
+func NewEventStoreMiddleware(svc users.Service, client redis.UniversalClient) users.Service {
+	return eventStore{
+		svc:    svc,
+		client: client,
+	}
+}
+
+func (es eventStore) Register(user users.User) error {
+	if err := es.svc.Register(user); err != nil {
+		return err
+	}
+
+	event := registerUserEvent{
+		email: user.Email,
+	}
+	es.add(event)
+
+	return nil
+}
+
+func (es eventStore) Login(user users.User, ip, client string) (string, error) {
+	return es.svc.Login(user, ip, client)
+}
+
+func (es eventStore) Identify(token string) (string, error) {
+	return es.svc.Identify(token)
+}
+
+func (es eventStore) Exists(email string) error {
+	return es.svc.Exists(email)
+}
+
+func (es eventStore) Remove(token string) error {
+	email, err := es.svc.Identify(token)
+	if err != nil {
+		return err
+	}
+
+	if err := es.svc.Remove(token); err != nil {
+		return err
+	}
+
+	event := removeUserEvent{
+		email: email,
+	}
+	es.add(event)
+
+	return nil
+}
+
+func (es eventStore) Impersonate(token, email string) (string, error) {
+	admin, err := es.svc.Identify(token)
+	if err != nil {
+		return "", err
+	}
+
+	key, err := es.svc.Impersonate(token, email)
+	if err != nil {
+		return "", err
+	}
+
+	event := impersonateUserEvent{
+		email: email,
+		admin: admin,
+	}
+	es.add(event)
+
+	return key, nil
+}
+
+func (es eventStore) Impersonator(token string) (string, error) {
+	return es.svc.Impersonator(token)
+}
+
+func (es eventStore) ListSessions(token string) ([]users.Session, error) {
+	return es.svc.ListSessions(token)
+}
+
+func (es eventStore) RevokeSession(token, session string) error {
+	email, err := es.svc.Identify(token)
+	if err != nil {
+		return err
+	}
+
+	if err := es.svc.RevokeSession(token, session); err != nil {
+		return err
+	}
+
+	event := revokeSessionEvent{
+		email:   email,
+		session: session,
+	}
+	es.add(event)
+
+	return nil
+}
+
+func (es eventStore) RequestPasswordReset(email string) error {
+	return es.svc.RequestPasswordReset(email)
+}
+
+func (es eventStore) ResetPassword(token, password string) (string, error) {
+	email, err := es.svc.ResetPassword(token, password)
+	if err != nil {
+		return "", err
+	}
+
+	event := resetPasswordEvent{
+		email: email,
+	}
+	es.add(event)
+
+	return email, nil
+}
+
+func (es eventStore) add(ev event) error {
+	record := &redis.XAddArgs{
+		Stream:       streamID,
+		MaxLenApprox: streamLen,
+		Values:       ev.Encode(),
+	}
+
+	return es.client.XAdd(record).Err()
+}
@@ -0,0 +1,275 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package redis_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	r "github.com/go-redis/redis"
+	"github.com/mainflux/mainflux/users"
+	"github.com/mainflux/mainflux/users/mocks"
+	"github.com/mainflux/mainflux/users/redis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	streamID          = "mainflux.users"
+	userPrefix        = "user."
+	userRegister      = userPrefix + "register"
+	userRemove        = userPrefix + "remove"
+	userRevokeSession = userPrefix + "revoke_session"
+	userResetPassword = userPrefix + "password_reset"
+)
+
+var user = users.User{Email: "user@example.com", Password: "password"}
+
+func newService() users.Service {
+	return newResetService(mocks.NewMailer())
+}
+
+func newResetService(mailer *mocks.Mailer) users.Service {
+	repo := mocks.NewUserRepository()
+	hasher := mocks.NewHasher()
+	idp := mocks.NewIdentityProvider()
+
+	return users.New(repo, mocks.NewSessionRepository(), mocks.NewResetTokenRepository(), hasher, idp, mailer, []string{}, time.Minute)
+}
+
+func TestRegister(t *testing.T) {
+	redisClient.FlushAll().Err()
+
+	svc := newService()
+	svc = redis.NewEventStoreMiddleware(svc, redisClient)
+
+	cases := []struct {
+		desc  string
+		user  users.User
+		err   error
+		event map[string]interface{}
+	}{
+		{
+			desc: "register new user",
+			user: user,
+			err:  nil,
+			event: map[string]interface{}{
+				"email":     user.Email,
+				"operation": userRegister,
+			},
+		},
+		{
+			desc:  "register existing user",
+			user:  user,
+			err:   users.ErrConflict,
+			event: nil,
+		},
+	}
+
+	lastID := "0"
+	for _, tc := range cases {
+		err := svc.Register(tc.user)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+
+		streams := redisClient.XRead(&r.XReadArgs{
+			Streams: []string{streamID, lastID},
+			Count:   1,
+			Block:   time.Second,
+		}).Val()
+
+		var event map[string]interface{}
+		if len(streams) > 0 && len(streams[0].Messages) > 0 {
+			msg := streams[0].Messages[0]
+			event = msg.Values
+			lastID = msg.ID
+		}
+
+		assert.Equal(t, tc.event, event, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.event, event))
+	}
+}
+
+func TestRemove(t *testing.T) {
+	redisClient.FlushAll().Err()
+
+	svc := newService()
+	// Register user without sending event.
+	err := svc.Register(user)
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	token, err := svc.Login(user, "", "")
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+
+	svc = redis.NewEventStoreMiddleware(svc, redisClient)
+
+	cases := []struct {
+		desc  string
+		token string
+		err   error
+		event map[string]interface{}
+	}{
+		{
+			desc:  "remove user with invalid token",
+			token: "",
+			err:   users.ErrUnauthorizedAccess,
+			event: nil,
+		},
+		{
+			desc:  "remove existing user",
+			token: token,
+			err:   nil,
+			event: map[string]interface{}{
+				"email":     user.Email,
+				"operation": userRemove,
+			},
+		},
+	}
+
+	lastID := "0"
+	for _, tc := range cases {
+		err := svc.Remove(tc.token)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+
+		streams := redisClient.XRead(&r.XReadArgs{
+			Streams: []string{streamID, lastID},
+			Count:   1,
+			Block:   time.Second,
+		}).Val()
+
+		var event map[string]interface{}
+		if len(streams) > 0 && len(streams[0].Messages) > 0 {
+			msg := streams[0].Messages[0]
+			event = msg.Values
+			lastID = msg.ID
+		}
+
+		assert.Equal(t, tc.event, event, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.event, event))
+	}
+}
+
+func TestRevokeSession(t *testing.T) {
+	redisClient.FlushAll().Err()
+
+	svc := newService()
+	// Register user and log in without sending events.
+	err := svc.Register(user)
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	token, err := svc.Login(user, "", "")
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	sessions, err := svc.ListSessions(token)
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	require.Len(t, sessions, 1, fmt.Sprintf("expected 1 session got %d", len(sessions)))
+
+	svc = redis.NewEventStoreMiddleware(svc, redisClient)
+
+	cases := []struct {
+		desc    string
+		token   string
+		session string
+		err     error
+		event   map[string]interface{}
+	}{
+		{
+			desc:    "revoke session with invalid token",
+			token:   "",
+			session: sessions[0].ID,
+			err:     users.ErrUnauthorizedAccess,
+			event:   nil,
+		},
+		{
+			desc:    "revoke existing session",
+			token:   token,
+			session: sessions[0].ID,
+			err:     nil,
+			event: map[string]interface{}{
+				"email":     user.Email,
+				"session":   sessions[0].ID,
+				"operation": userRevokeSession,
+			},
+		},
+	}
+
+	lastID := "0"
+	for _, tc := range cases {
+		err := svc.RevokeSession(tc.token, tc.session)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+
+		streams := redisClient.XRead(&r.XReadArgs{
+			Streams: []string{streamID, lastID},
+			Count:   1,
+			Block:   time.Second,
+		}).Val()
+
+		var event map[string]interface{}
+		if len(streams) > 0 && len(streams[0].Messages) > 0 {
+			msg := streams[0].Messages[0]
+			event = msg.Values
+			lastID = msg.ID
+		}
+
+		assert.Equal(t, tc.event, event, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.event, event))
+	}
+}
+
+func TestResetPassword(t *testing.T) {
+	redisClient.FlushAll().Err()
+
+	mailer := mocks.NewMailer()
+	svc := newResetService(mailer)
+	// Register user and request the reset without sending events.
+	err := svc.Register(user)
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	err = svc.RequestPasswordReset(user.Email)
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	token := mailer.Token(user.Email)
+
+	svc = redis.NewEventStoreMiddleware(svc, redisClient)
+
+	cases := []struct {
+		desc  string
+		token string
+		err   error
+		event map[string]interface{}
+	}{
+		{
+			desc:  "reset password with invalid token",
+			token: "invalid",
+			err:   users.ErrUnauthorizedAccess,
+			event: nil,
+		},
+		{
+			desc:  "reset password",
+			token: token,
+			err:   nil,
+			event: map[string]interface{}{
+				"email":     user.Email,
+				"operation": userResetPassword,
+			},
+		},
+	}
+
+	lastID := "0"
+	for _, tc := range cases {
+		_, err := svc.ResetPassword(tc.token, "new-password")
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+
+		streams := redisClient.XRead(&r.XReadArgs{
+			Streams: []string{streamID, lastID},
+			Count:   1,
+			Block:   time.Second,
+		}).Val()
+
+		var event map[string]interface{}
+		if len(streams) > 0 && len(streams[0].Messages) > 0 {
+			msg := streams[0].Messages[0]
+			event = msg.Values
+			lastID = msg.ID
+		}
+
+		assert.Equal(t, tc.event, event, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.event, event))
+	}
+}
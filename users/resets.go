@@ -0,0 +1,37 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package users
+
+import "time"
+
+// ResetToken represents the password reset token issued to the user. Only
+// the hash of the token is kept, so that the leaked records can't be used
+// to reset the passwords.
+type ResetToken struct {
+	Hash      string
+	User      string
+	ExpiresAt time.Time
+}
+
+// ResetTokenRepository specifies a password reset tokens persistence API.
+type ResetTokenRepository interface {
+	// Save persists the reset token, replacing the token previously issued
+	// to the same user.
+	Save(ResetToken) error
+
+	// Consume removes the unexpired reset token having the provided hash and
+	// returns the user it was issued to, so that every token is used once.
+	Consume(string) (string, error)
+}
+
+// Mailer specifies an API for delivering the password reset tokens.
+type Mailer interface {
+	// SendResetToken sends the password reset token to the user having the
+	// provided email.
+	SendResetToken(string, string) error
+}
@@ -7,7 +7,26 @@
 
 package users
 
-import "errors"
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+const (
+	// sessionDuration is the validity of the access tokens issued on login.
+	sessionDuration = 10 * time.Hour
+
+	// resetTokenDuration is the validity of the password reset tokens.
+	resetTokenDuration = time.Hour
+
+	resetTokenSize = 32
+)
 
 var (
 	// ErrConflict indicates usage of the existing email during account
@@ -24,6 +43,10 @@ var (
 
 	// ErrNotFound indicates a non-existent entity request.
 	ErrNotFound = errors.New("non-existent entity")
+
+	// ErrResetUnavailable indicates the password reset requested while the
+	// service has no mailer to deliver the reset tokens with.
+	ErrResetUnavailable = errors.New("password reset unavailable")
 )
 
 // Service specifies an API that must be fullfiled by the domain service
@@ -34,30 +57,132 @@ type Service interface {
 	Register(User) error
 
 	// Login authenticates the user given its credentials. Successful
-	// authentication generates new access token. Failed invocations are
-	// identified by the non-nil error values in the response.
-	Login(User) (string, error)
+	// authentication starts a new session, recording the provided IP address
+	// and user agent of the client, and generates its access token. Failed
+	// invocations are identified by the non-nil error values in the
+	// response. Users lacking the local account are authenticated against
+	// the directory, if any.
+	Login(User, string, string) (string, error)
 
-	// Identify validates user's token. If token is valid, user's id
-	// is returned. If token is invalid, or invocation failed for some
-	// other reason, non-nil error values are returned in response.
+	// Identify validates user's token. If token is valid and its session
+	// wasn't revoked, user's id is returned. If token is invalid, or
+	// invocation failed for some other reason, non-nil error values are
+	// returned in response.
 	Identify(string) (string, error)
+
+	// Exists checks whether the user having the provided email exists,
+	// either with the local account or in the directory. ErrNotFound is
+	// returned for the unknown users.
+	Exists(string) error
+
+	// Remove removes the account of the user identified by the provided
+	// token. Entities owned by the removed user are cleaned up by the
+	// services consuming the users event stream. Impersonation tokens can't
+	// be used to remove the account.
+	Remove(string) error
+
+	// Impersonate starts the time-limited session of the user having the
+	// provided email on behalf of the administrator, and issues its access
+	// token. The session is listed and revoked along with the user's other
+	// sessions. Only administrators are allowed to impersonate other users,
+	// using their own (not impersonation) token.
+	Impersonate(string, string) (string, error)
+
+	// Impersonator returns the email of the administrator acting on behalf
+	// of the user identified by the provided token, or an empty string if
+	// the token is not an impersonation token.
+	Impersonator(string) (string, error)
+
+	// ListSessions retrieves the active sessions of the user identified by
+	// the provided token.
+	ListSessions(string) ([]Session, error)
+
+	// RevokeSession revokes the session having the provided identifier,
+	// that belongs to the user identified by the provided token, so that
+	// its access token is no longer accepted. Impersonation tokens can't be
+	// used to revoke the sessions.
+	RevokeSession(string, string) error
+
+	// RequestPasswordReset issues the password reset token to the user
+	// having the provided email, and delivers it using the mailer. Requests
+	// for the unknown emails and the directory accounts are ignored, so
+	// that the registered emails aren't disclosed.
+	RequestPasswordReset(string) error
+
+	// ResetPassword sets the new password of the user the provided reset
+	// token was issued to, revokes all of the user's sessions and returns
+	// the user's email. The token can be used once, until it expires.
+	ResetPassword(string, string) (string, error)
 }
 
 var _ Service = (*usersService)(nil)
 
 type usersService struct {
-	users  UserRepository
-	hasher Hasher
-	idp    IdentityProvider
+	users         UserRepository
+	sessions      SessionRepository
+	resets        ResetTokenRepository
+	hasher        Hasher
+	idp           IdentityProvider
+	mailer        Mailer
+	admins        map[string]bool
+	impersonation time.Duration
+	dir           Directory
+	adminGroups   map[string]bool
 }
 
-// New instantiates the users service implementation.
-func New(users UserRepository, hasher Hasher, idp IdentityProvider) Service {
-	return &usersService{users: users, hasher: hasher, idp: idp}
+// New instantiates the users service implementation. Users having the
+// provided emails are administrators, allowed to impersonate other users
+// for the given duration. Password reset tokens are delivered using the
+// provided mailer, and the password reset is unavailable if it is nil.
+func New(users UserRepository, sessions SessionRepository, resets ResetTokenRepository, hasher Hasher, idp IdentityProvider, mailer Mailer, admins []string, impersonation time.Duration) Service {
+	return NewWithDirectory(users, sessions, resets, hasher, idp, mailer, nil, admins, nil, impersonation)
+}
+
+// NewWithDirectory instantiates the users service implementation that,
+// besides the local accounts, accepts the users of the provided directory.
+// The local account of the directory user is created on the first login,
+// without the password, and the user is granted the administrator role while
+// being a member of any of the admin groups. The membership is looked up in
+// the directory on every privileged call, so that the role granted or
+// revoked in the directory applies immediately, regardless of the restarts
+// and the replicas of the service.
+func NewWithDirectory(users UserRepository, sessions SessionRepository, resets ResetTokenRepository, hasher Hasher, idp IdentityProvider, mailer Mailer, dir Directory, admins, adminGroups []string, impersonation time.Duration) Service {
+	return &usersService{
+		users:         users,
+		sessions:      sessions,
+		resets:        resets,
+		hasher:        hasher,
+		idp:           idp,
+		mailer:        mailer,
+		admins:        toSet(admins),
+		impersonation: impersonation,
+		dir:           dir,
+		adminGroups:   toSet(adminGroups),
+	}
+}
+
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		if v != "" {
+			set[v] = true
+		}
+	}
+	return set
 }
 
 func (svc usersService) Register(user User) error {
+	if svc.dir != nil {
+		_, err := svc.dir.Lookup(user.Email)
+		switch err {
+		case nil:
+			return ErrConflict
+		case ErrNotFound:
+		default:
+			return err
+		}
+	}
+
 	hash, err := svc.hasher.Hash(user.Password)
 	if err != nil {
 		return ErrMalformedEntity
@@ -67,23 +192,302 @@ func (svc usersService) Register(user User) error {
 	return svc.users.Save(user)
 }
 
-func (svc usersService) Login(user User) (string, error) {
+func (svc usersService) Login(user User, ip, client string) (string, error) {
 	dbUser, err := svc.users.RetrieveByID(user.Email)
-	if err != nil {
+	if err == nil && !directoryAccount(dbUser) {
+		if err := svc.hasher.Compare(user.Password, dbUser.Password); err != nil {
+			return "", ErrUnauthorizedAccess
+		}
+		return svc.startSession(user.Email, ip, client)
+	}
+
+	if svc.dir == nil || (err != nil && err != ErrNotFound) {
 		return "", ErrUnauthorizedAccess
 	}
 
-	if err := svc.hasher.Compare(user.Password, dbUser.Password); err != nil {
+	if _, err := svc.dir.Authenticate(user.Email, user.Password); err != nil {
 		return "", ErrUnauthorizedAccess
 	}
 
-	return svc.idp.TemporaryKey(user.Email)
+	if dbUser.Email == "" {
+		if err := svc.users.Save(User{Email: user.Email}); err != nil && err != ErrConflict {
+			return "", err
+		}
+	}
+
+	return svc.startSession(user.Email, ip, client)
 }
 
 func (svc usersService) Identify(token string) (string, error) {
+	return svc.identify(token)
+}
+
+func (svc usersService) Exists(email string) error {
+	_, err := svc.users.RetrieveByID(email)
+	if err != ErrNotFound || svc.dir == nil {
+		return err
+	}
+
+	_, err = svc.dir.Lookup(email)
+	return err
+}
+
+func (svc usersService) Remove(token string) error {
+	id, err := svc.identify(token)
+	if err != nil {
+		return err
+	}
+
+	if actor, err := svc.idp.Impersonator(token); err != nil || actor != "" {
+		return ErrUnauthorizedAccess
+	}
+
+	return svc.users.Remove(id)
+}
+
+func (svc usersService) Impersonate(token, email string) (string, error) {
+	admin, err := svc.identify(token)
+	if err != nil {
+		return "", err
+	}
+
+	if actor, err := svc.idp.Impersonator(token); err != nil || actor != "" {
+		return "", ErrUnauthorizedAccess
+	}
+
+	isAdmin, err := svc.isAdmin(admin)
+	if err != nil {
+		return "", err
+	}
+	if !isAdmin {
+		return "", ErrUnauthorizedAccess
+	}
+
+	if _, err := svc.users.RetrieveByID(email); err != nil {
+		return "", err
+	}
+
+	session, err := newSession(email, svc.impersonation)
+	if err != nil {
+		return "", err
+	}
+	session.Actor = admin
+
+	if err := svc.sessions.Save(session); err != nil {
+		return "", err
+	}
+
+	return svc.idp.ImpersonationKey(session)
+}
+
+func (svc usersService) Impersonator(token string) (string, error) {
+	if _, err := svc.identify(token); err != nil {
+		return "", err
+	}
+
+	actor, err := svc.idp.Impersonator(token)
+	if err != nil {
+		return "", ErrUnauthorizedAccess
+	}
+
+	return actor, nil
+}
+
+func (svc usersService) ListSessions(token string) ([]Session, error) {
+	id, err := svc.identify(token)
+	if err != nil {
+		return nil, err
+	}
+
+	return svc.sessions.RetrieveAll(id)
+}
+
+func (svc usersService) RevokeSession(token, session string) error {
+	id, err := svc.identify(token)
+	if err != nil {
+		return err
+	}
+
+	if actor, err := svc.idp.Impersonator(token); err != nil || actor != "" {
+		return ErrUnauthorizedAccess
+	}
+
+	return svc.sessions.Remove(id, session)
+}
+
+func (svc usersService) RequestPasswordReset(email string) error {
+	if svc.mailer == nil {
+		return ErrResetUnavailable
+	}
+
+	user, err := svc.users.RetrieveByID(email)
+	switch {
+	case err == ErrNotFound:
+		return nil
+	case err != nil:
+		return err
+	case directoryAccount(user):
+		return nil
+	}
+
+	b := make([]byte, resetTokenSize)
+	if _, err := rand.Read(b); err != nil {
+		return err
+	}
+	token := hex.EncodeToString(b)
+
+	rt := ResetToken{
+		Hash:      hashResetToken(token),
+		User:      email,
+		ExpiresAt: time.Now().UTC().Add(resetTokenDuration),
+	}
+	if err := svc.resets.Save(rt); err != nil {
+		return err
+	}
+
+	return svc.mailer.SendResetToken(email, token)
+}
+
+func (svc usersService) ResetPassword(token, password string) (string, error) {
+	if svc.mailer == nil {
+		return "", ErrResetUnavailable
+	}
+
+	if token == "" || password == "" {
+		return "", ErrMalformedEntity
+	}
+
+	email, err := svc.resets.Consume(hashResetToken(token))
+	if err != nil {
+		if err == ErrNotFound {
+			return "", ErrUnauthorizedAccess
+		}
+		return "", err
+	}
+
+	hash, err := svc.hasher.Hash(password)
+	if err != nil {
+		return "", ErrMalformedEntity
+	}
+
+	if err := svc.users.UpdatePassword(User{Email: email, Password: hash}); err != nil {
+		return "", err
+	}
+
+	if err := svc.sessions.RemoveAll(email); err != nil {
+		return "", err
+	}
+
+	return email, nil
+}
+
+func hashResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// startSession records the new session of the user and issues its access
+// token.
+func (svc usersService) startSession(email, ip, client string) (string, error) {
+	session, err := newSession(email, sessionDuration)
+	if err != nil {
+		return "", err
+	}
+	session.IP = ip
+	session.Client = client
+
+	if err := svc.sessions.Save(session); err != nil {
+		return "", err
+	}
+
+	return svc.idp.TemporaryKey(session)
+}
+
+func newSession(email string, ttl time.Duration) (Session, error) {
+	id, err := uuid.NewV4()
+	if err != nil {
+		return Session{}, err
+	}
+
+	// Tokens carry the time with the second precision.
+	now := time.Now().UTC().Truncate(time.Second)
+	return Session{
+		ID:        id.String(),
+		User:      email,
+		IssuedAt:  now,
+		ExpiresAt: now.Add(ttl),
+	}, nil
+}
+
+// identify returns the identifier of the user the token was issued to,
+// provided that the token's session, if any, wasn't revoked.
+func (svc usersService) identify(token string) (string, error) {
 	id, err := svc.idp.Identity(token)
 	if err != nil {
 		return "", ErrUnauthorizedAccess
 	}
-	return id, nil
+
+	session, err := svc.idp.Session(token)
+	if err != nil {
+		return "", ErrUnauthorizedAccess
+	}
+
+	if session == "" {
+		return id, nil
+	}
+
+	switch _, err := svc.sessions.RetrieveByID(id, session); err {
+	case nil:
+		return id, nil
+	case ErrNotFound:
+		return "", ErrUnauthorizedAccess
+	default:
+		return "", err
+	}
+}
+
+// isAdmin reports whether the user is the administrator, either listed as
+// one or being the directory user that is a member of any of the admin
+// groups.
+func (svc usersService) isAdmin(email string) (bool, error) {
+	if svc.admins[email] {
+		return true, nil
+	}
+
+	if svc.dir == nil || len(svc.adminGroups) == 0 {
+		return false, nil
+	}
+
+	// The local accounts aren't authenticated against the directory, so
+	// they don't get the role of the directory entry having the same email.
+	user, err := svc.users.RetrieveByID(email)
+	if err != nil {
+		return false, err
+	}
+	if !directoryAccount(user) {
+		return false, nil
+	}
+
+	entry, err := svc.dir.Lookup(email)
+	switch err {
+	case nil:
+	case ErrNotFound:
+		return false, nil
+	default:
+		return false, err
+	}
+
+	for _, g := range entry.Groups {
+		if svc.adminGroups[g] {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// directoryAccount reports whether the account was created for the directory
+// user, i.e. has no local password.
+func directoryAccount(user User) bool {
+	return strings.TrimSpace(user.Password) == ""
 }
@@ -8,24 +8,36 @@
 package users_test
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/mainflux/mainflux/users"
 	"github.com/mainflux/mainflux/users/mocks"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
-const wrong string = "wrong-value"
+const (
+	wrong       string = "wrong-value"
+	adminsGroup string = "cn=admins,dc=example,dc=com"
+)
 
-var user = users.User{"user@example.com", "password"}
+var (
+	user     = users.User{"user@example.com", "password"}
+	admin    = users.User{Email: "admin@example.com", Password: "password"}
+	dirUser  = users.User{Email: "john@example.com", Password: "directory-password"}
+	dirAdmin = users.User{Email: "jane@example.com", Password: "directory-password"}
+)
 
 func newService() users.Service {
 	repo := mocks.NewUserRepository()
 	hasher := mocks.NewHasher()
 	idp := mocks.NewIdentityProvider()
 
-	return users.New(repo, hasher, idp)
+	return users.New(repo, mocks.NewSessionRepository(), mocks.NewResetTokenRepository(), hasher, idp, mocks.NewMailer(), []string{admin.Email}, time.Minute)
 }
 
 func TestRegister(t *testing.T) {
@@ -61,7 +73,7 @@ func TestLogin(t *testing.T) {
 	}
 
 	for desc, tc := range cases {
-		_, err := svc.Login(tc.user)
+		_, err := svc.Login(tc.user, "", "")
 		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
 	}
 }
@@ -69,7 +81,7 @@ func TestLogin(t *testing.T) {
 func TestIdentify(t *testing.T) {
 	svc := newService()
 	svc.Register(user)
-	key, _ := svc.Login(user)
+	key, _ := svc.Login(user, "", "")
 
 	cases := map[string]struct {
 		key string
@@ -84,3 +96,407 @@ func TestIdentify(t *testing.T) {
 		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
 	}
 }
+
+func TestExists(t *testing.T) {
+	svc := newService()
+	svc.Register(user)
+
+	cases := map[string]struct {
+		email string
+		err   error
+	}{
+		"existing user":     {user.Email, nil},
+		"non-existing user": {wrong, users.ErrNotFound},
+	}
+
+	for desc, tc := range cases {
+		err := svc.Exists(tc.email)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
+func TestRemove(t *testing.T) {
+	svc := newService()
+	svc.Register(user)
+	key, _ := svc.Login(user, "", "")
+
+	svc.Register(admin)
+	adminKey, _ := svc.Login(admin, "", "")
+	impKey, _ := svc.Impersonate(adminKey, user.Email)
+
+	cases := []struct {
+		desc string
+		key  string
+		err  error
+	}{
+		{"remove user with invalid token", "", users.ErrUnauthorizedAccess},
+		{"remove user with impersonation token", impKey, users.ErrUnauthorizedAccess},
+		{"remove existing user", key, nil},
+		{"remove already removed user", key, users.ErrNotFound},
+	}
+
+	for _, tc := range cases {
+		err := svc.Remove(tc.key)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestImpersonate(t *testing.T) {
+	svc := newService()
+	svc.Register(user)
+	svc.Register(admin)
+	userKey, _ := svc.Login(user, "", "")
+	adminKey, _ := svc.Login(admin, "", "")
+	impKey, _ := svc.Impersonate(adminKey, user.Email)
+
+	cases := []struct {
+		desc  string
+		key   string
+		email string
+		err   error
+	}{
+		{"impersonate existing user", adminKey, user.Email, nil},
+		{"impersonate non-existing user", adminKey, wrong, users.ErrNotFound},
+		{"impersonate user with invalid token", "", user.Email, users.ErrUnauthorizedAccess},
+		{"impersonate user without admin privileges", userKey, admin.Email, users.ErrUnauthorizedAccess},
+		{"impersonate user with impersonation token", impKey, user.Email, users.ErrUnauthorizedAccess},
+	}
+
+	for _, tc := range cases {
+		key, err := svc.Impersonate(tc.key, tc.email)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err != nil {
+			continue
+		}
+
+		id, err := svc.Identify(key)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s\n", tc.desc, err))
+		assert.Equal(t, tc.email, id, fmt.Sprintf("%s: expected identity %s got %s\n", tc.desc, tc.email, id))
+	}
+}
+
+func TestImpersonator(t *testing.T) {
+	svc := newService()
+	svc.Register(user)
+	svc.Register(admin)
+	userKey, _ := svc.Login(user, "", "")
+	adminKey, _ := svc.Login(admin, "", "")
+	impKey, _ := svc.Impersonate(adminKey, user.Email)
+
+	cases := []struct {
+		desc  string
+		key   string
+		actor string
+		err   error
+	}{
+		{"impersonator of impersonation token", impKey, admin.Email, nil},
+		{"impersonator of session token", userKey, "", nil},
+		{"impersonator of invalid token", "", "", users.ErrUnauthorizedAccess},
+	}
+
+	for _, tc := range cases {
+		actor, err := svc.Impersonator(tc.key)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.actor, actor, fmt.Sprintf("%s: expected actor %s got %s\n", tc.desc, tc.actor, actor))
+	}
+}
+
+func TestRevokeImpersonationSession(t *testing.T) {
+	svc := newService()
+	svc.Register(user)
+	svc.Register(admin)
+	userKey, _ := svc.Login(user, "", "")
+	adminKey, _ := svc.Login(admin, "", "")
+	impKey, err := svc.Impersonate(adminKey, user.Email)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	sessions, err := svc.ListSessions(userKey)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	impersonation := ""
+	for _, s := range sessions {
+		if s.Actor == admin.Email {
+			impersonation = s.ID
+		}
+	}
+	require.NotEmpty(t, impersonation, "impersonation session isn't listed among the user's sessions\n")
+
+	err = svc.RevokeSession(userKey, impersonation)
+	assert.Nil(t, err, fmt.Sprintf("revoke impersonation session: unexpected error %s\n", err))
+
+	_, err = svc.Identify(impKey)
+	assert.Equal(t, users.ErrUnauthorizedAccess, err, fmt.Sprintf("identify revoked impersonation session: expected %s got %s\n", users.ErrUnauthorizedAccess, err))
+}
+
+func TestListSessions(t *testing.T) {
+	svc := newService()
+	svc.Register(user)
+	key, _ := svc.Login(user, "192.168.0.1", "curl/7.58.0")
+	svc.Login(user, "192.168.0.2", "Mozilla/5.0")
+
+	cases := []struct {
+		desc string
+		key  string
+		size int
+		err  error
+	}{
+		{"list sessions with valid token", key, 2, nil},
+		{"list sessions with invalid token", "", 0, users.ErrUnauthorizedAccess},
+	}
+
+	for _, tc := range cases {
+		sessions, err := svc.ListSessions(tc.key)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Len(t, sessions, tc.size, fmt.Sprintf("%s: expected %d sessions got %d\n", tc.desc, tc.size, len(sessions)))
+	}
+}
+
+func TestRevokeSession(t *testing.T) {
+	svc := newService()
+	svc.Register(user)
+	key, _ := svc.Login(user, "", "")
+	otherKey, _ := svc.Login(user, "", "")
+
+	svc.Register(admin)
+	adminKey, _ := svc.Login(admin, "", "")
+	impKey, _ := svc.Impersonate(adminKey, user.Email)
+
+	other, _ := mocks.NewIdentityProvider().Session(otherKey)
+
+	cases := []struct {
+		desc    string
+		key     string
+		session string
+		err     error
+	}{
+		{"revoke session with invalid token", "", other, users.ErrUnauthorizedAccess},
+		{"revoke session with impersonation token", impKey, other, users.ErrUnauthorizedAccess},
+		{"revoke non-existing session", key, wrong, users.ErrNotFound},
+		{"revoke existing session", key, other, nil},
+		{"revoke revoked session", key, other, users.ErrNotFound},
+	}
+
+	for _, tc := range cases {
+		err := svc.RevokeSession(tc.key, tc.session)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	_, err := svc.Identify(otherKey)
+	assert.Equal(t, users.ErrUnauthorizedAccess, err, fmt.Sprintf("identify revoked session: expected %s got %s\n", users.ErrUnauthorizedAccess, err))
+	_, err = svc.Identify(key)
+	assert.Nil(t, err, fmt.Sprintf("identify active session: unexpected error %s\n", err))
+}
+
+func newResetService(resets users.ResetTokenRepository, mailer users.Mailer) users.Service {
+	repo := mocks.NewUserRepository()
+	hasher := mocks.NewHasher()
+	idp := mocks.NewIdentityProvider()
+
+	return users.New(repo, mocks.NewSessionRepository(), resets, hasher, idp, mailer, []string{admin.Email}, time.Minute)
+}
+
+func TestRequestPasswordReset(t *testing.T) {
+	mailer := mocks.NewMailer()
+	svc := newResetService(mocks.NewResetTokenRepository(), mailer)
+	svc.Register(user)
+
+	cases := []struct {
+		desc  string
+		email string
+		sent  bool
+	}{
+		{"request password reset", user.Email, true},
+		{"request password reset of unknown user", "unknown@example.com", false},
+	}
+
+	for _, tc := range cases {
+		err := svc.RequestPasswordReset(tc.email)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s\n", tc.desc, err))
+		sent := mailer.Token(tc.email) != ""
+		assert.Equal(t, tc.sent, sent, fmt.Sprintf("%s: expected token sent %t got %t\n", tc.desc, tc.sent, sent))
+	}
+
+	svc = newResetService(mocks.NewResetTokenRepository(), nil)
+	svc.Register(user)
+	err := svc.RequestPasswordReset(user.Email)
+	assert.Equal(t, users.ErrResetUnavailable, err, fmt.Sprintf("request password reset without mailer: expected %s got %s\n", users.ErrResetUnavailable, err))
+}
+
+func TestResetPassword(t *testing.T) {
+	resets := mocks.NewResetTokenRepository()
+	mailer := mocks.NewMailer()
+	svc := newResetService(resets, mailer)
+	svc.Register(user)
+	key, _ := svc.Login(user, "", "")
+
+	svc.RequestPasswordReset(user.Email)
+	replaced := mailer.Token(user.Email)
+	svc.RequestPasswordReset(user.Email)
+	token := mailer.Token(user.Email)
+
+	expired := "expired-token"
+	sum := sha256.Sum256([]byte(expired))
+	resets.Save(users.ResetToken{
+		Hash:      hex.EncodeToString(sum[:]),
+		User:      admin.Email,
+		ExpiresAt: time.Now().Add(-time.Minute),
+	})
+
+	password := "new-password"
+
+	cases := []struct {
+		desc     string
+		token    string
+		password string
+		err      error
+	}{
+		{"reset password with empty token", "", password, users.ErrMalformedEntity},
+		{"reset password with empty password", token, "", users.ErrMalformedEntity},
+		{"reset password with replaced token", replaced, password, users.ErrUnauthorizedAccess},
+		{"reset password with expired token", expired, password, users.ErrUnauthorizedAccess},
+		{"reset password with invalid token", wrong, password, users.ErrUnauthorizedAccess},
+		{"reset password", token, password, nil},
+		{"reset password with used token", token, password, users.ErrUnauthorizedAccess},
+	}
+
+	for _, tc := range cases {
+		_, err := svc.ResetPassword(tc.token, tc.password)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	_, err := svc.Identify(key)
+	assert.Equal(t, users.ErrUnauthorizedAccess, err, fmt.Sprintf("identify session started before reset: expected %s got %s\n", users.ErrUnauthorizedAccess, err))
+	_, err = svc.Login(user, "", "")
+	assert.Equal(t, users.ErrUnauthorizedAccess, err, fmt.Sprintf("login with old password: expected %s got %s\n", users.ErrUnauthorizedAccess, err))
+	_, err = svc.Login(users.User{Email: user.Email, Password: password}, "", "")
+	assert.Nil(t, err, fmt.Sprintf("login with new password: unexpected error %s\n", err))
+}
+
+func newDirectoryService() users.Service {
+	repo := mocks.NewUserRepository()
+	hasher := mocks.NewHasher()
+	idp := mocks.NewIdentityProvider()
+	dir := mocks.NewDirectory(
+		map[string]string{dirUser.Email: dirUser.Password, dirAdmin.Email: dirAdmin.Password},
+		map[string][]string{dirAdmin.Email: {adminsGroup}},
+	)
+
+	return users.NewWithDirectory(repo, mocks.NewSessionRepository(), mocks.NewResetTokenRepository(), hasher, idp, mocks.NewMailer(), dir, []string{admin.Email}, []string{adminsGroup}, time.Minute)
+}
+
+func TestRegisterWithDirectory(t *testing.T) {
+	svc := newDirectoryService()
+
+	cases := []struct {
+		desc string
+		user users.User
+		err  error
+	}{
+		{"register new local user", user, nil},
+		{"register directory user", users.User{Email: dirUser.Email, Password: "password"}, users.ErrConflict},
+	}
+
+	for _, tc := range cases {
+		err := svc.Register(tc.user)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestLoginWithDirectory(t *testing.T) {
+	svc := newDirectoryService()
+	svc.Register(user)
+
+	cases := []struct {
+		desc string
+		user users.User
+		err  error
+	}{
+		{"login local user", user, nil},
+		{"login local user with wrong password", users.User{Email: user.Email, Password: wrong}, users.ErrUnauthorizedAccess},
+		{"login directory user", dirUser, nil},
+		{"login directory user again", dirUser, nil},
+		{"login directory user with wrong password", users.User{Email: dirUser.Email, Password: wrong}, users.ErrUnauthorizedAccess},
+		{"login directory user with empty password", users.User{Email: dirUser.Email, Password: ""}, users.ErrUnauthorizedAccess},
+		{"login unknown user", users.User{Email: wrong, Password: wrong}, users.ErrUnauthorizedAccess},
+	}
+
+	for _, tc := range cases {
+		_, err := svc.Login(tc.user, "", "")
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestExistsWithDirectory(t *testing.T) {
+	svc := newDirectoryService()
+	svc.Register(user)
+
+	cases := []struct {
+		desc  string
+		email string
+		err   error
+	}{
+		{"local user", user.Email, nil},
+		{"directory user without local account", dirUser.Email, nil},
+		{"unknown user", wrong, users.ErrNotFound},
+	}
+
+	for _, tc := range cases {
+		err := svc.Exists(tc.email)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestImpersonateWithDirectory(t *testing.T) {
+	svc := newDirectoryService()
+	svc.Register(user)
+	userKey, _ := svc.Login(dirUser, "", "")
+	adminKey, _ := svc.Login(dirAdmin, "", "")
+
+	cases := []struct {
+		desc  string
+		key   string
+		email string
+		err   error
+	}{
+		{"impersonate as admin group member", adminKey, user.Email, nil},
+		{"impersonate as directory user outside admin groups", userKey, user.Email, users.ErrUnauthorizedAccess},
+		{"impersonate directory user", adminKey, dirUser.Email, nil},
+	}
+
+	for _, tc := range cases {
+		_, err := svc.Impersonate(tc.key, tc.email)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestDirectoryAdminRole(t *testing.T) {
+	repo := mocks.NewUserRepository()
+	sessions := mocks.NewSessionRepository()
+	idp := mocks.NewIdentityProvider()
+	groups := map[string][]string{dirAdmin.Email: {adminsGroup}, admin.Email: {adminsGroup}}
+	dir := mocks.NewDirectory(map[string]string{dirAdmin.Email: dirAdmin.Password, admin.Email: dirAdmin.Password}, groups)
+	newService := func() users.Service {
+		return users.NewWithDirectory(repo, sessions, mocks.NewResetTokenRepository(), mocks.NewHasher(), idp, mocks.NewMailer(), dir, []string{}, []string{adminsGroup}, time.Minute)
+	}
+
+	err := newService().Register(user)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	adminKey, err := newService().Login(dirAdmin, "", "")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	// The local account registered before the directory entry of the same
+	// email was added isn't granted the role of the entry.
+	err = repo.Save(admin)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	localKey, err := newService().Login(admin, "", "")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	_, err = newService().Impersonate(localKey, user.Email)
+	assert.Equal(t, users.ErrUnauthorizedAccess, err, fmt.Sprintf("impersonate as local user in admin group: expected %s got %s\n", users.ErrUnauthorizedAccess, err))
+
+	_, err = newService().Impersonate(adminKey, user.Email)
+	assert.Nil(t, err, fmt.Sprintf("impersonate using restarted service: unexpected error %s\n", err))
+
+	groups[dirAdmin.Email] = []string{}
+	_, err = newService().Impersonate(adminKey, user.Email)
+	assert.Equal(t, users.ErrUnauthorizedAccess, err, fmt.Sprintf("impersonate after leaving admin group: expected %s got %s\n", users.ErrUnauthorizedAccess, err))
+}
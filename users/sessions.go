@@ -0,0 +1,45 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package users
+
+import "time"
+
+// Session represents the access token issued to the user on login, along
+// with the IP address and the user agent of the client that logged in. The
+// sessions started by impersonation record the administrator as the actor.
+type Session struct {
+	ID        string
+	User      string
+	Actor     string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+	IP        string
+	Client    string
+}
+
+// SessionRepository specifies a sessions persistence API.
+type SessionRepository interface {
+	// Save persists the session. The expired sessions of the same user are
+	// removed along the way.
+	Save(Session) error
+
+	// RetrieveByID retrieves the unexpired session having the provided
+	// identifier, that belongs to the specified user.
+	RetrieveByID(string, string) (Session, error)
+
+	// RetrieveAll retrieves the unexpired sessions of the specified user,
+	// starting from the most recent one.
+	RetrieveAll(string) ([]Session, error)
+
+	// Remove removes the session having the provided identifier, that
+	// belongs to the specified user.
+	Remove(string, string) error
+
+	// RemoveAll removes all the sessions of the specified user.
+	RemoveAll(string) error
+}
@@ -0,0 +1,92 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// Package smtp contains the mailer that delivers the password reset tokens
+// by email.
+package smtp
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/smtp"
+	"net/url"
+
+	"github.com/mainflux/mainflux/users"
+)
+
+var _ users.Mailer = (*mailer)(nil)
+
+// Config defines the options that are used when connecting to the SMTP
+// server. If the reset URL is provided, the token is sent as its token
+// query parameter, so that the user can follow the link.
+type Config struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+	ResetURL string
+}
+
+type mailer struct {
+	addr     string
+	auth     smtp.Auth
+	from     string
+	resetURL string
+}
+
+// New instantiates the email mailer. Plain authentication is used only if
+// the username is provided.
+func New(cfg Config) users.Mailer {
+	var auth smtp.Auth
+	if cfg.Username != "" {
+		auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+
+	return &mailer{
+		addr:     net.JoinHostPort(cfg.Host, cfg.Port),
+		auth:     auth,
+		from:     cfg.From,
+		resetURL: cfg.ResetURL,
+	}
+}
+
+func (m *mailer) SendResetToken(email, token string) error {
+	msg, err := m.message(email, token)
+	if err != nil {
+		return err
+	}
+
+	return smtp.SendMail(m.addr, m.auth, m.from, []string{email}, msg)
+}
+
+func (m *mailer) message(to, token string) ([]byte, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", m.from)
+	fmt.Fprintf(&buf, "To: %s\r\n", to)
+	fmt.Fprint(&buf, "Subject: Mainflux: password reset\r\n")
+	fmt.Fprint(&buf, "Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	fmt.Fprint(&buf, "A password reset was requested for your account. ")
+	fmt.Fprint(&buf, "If it wasn't you, ignore this message.\r\n\r\n")
+
+	if m.resetURL == "" {
+		fmt.Fprintf(&buf, "Reset token: %s\r\n", token)
+		return buf.Bytes(), nil
+	}
+
+	u, err := url.Parse(m.resetURL)
+	if err != nil {
+		return nil, err
+	}
+	q := u.Query()
+	q.Set("token", token)
+	u.RawQuery = q.Encode()
+	fmt.Fprintf(&buf, "Reset your password: %s\r\n", u.String())
+
+	return buf.Bytes(), nil
+}
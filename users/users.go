@@ -37,4 +37,11 @@ type UserRepository interface {
 
 	// RetrieveByID retrieves user by its unique identifier (i.e. email).
 	RetrieveByID(string) (User, error)
+
+	// UpdatePassword replaces the password of the existing user.
+	UpdatePassword(User) error
+
+	// Remove removes the user account having the provided identifier (i.e.
+	// email).
+	Remove(string) error
 }
@@ -7,7 +7,11 @@
 
 package cassandra
 
-import "github.com/gocql/gocql"
+import (
+	"fmt"
+
+	"github.com/gocql/gocql"
+)
 
 const table = `CREATE TABLE IF NOT EXISTS messages (
         id uuid,
@@ -25,9 +29,23 @@ const table = `CREATE TABLE IF NOT EXISTS messages (
     	time double,
     	update_time double,
     	link text,
+    	remote_addr text,
+    	received double,
+    	seq bigint,
         PRIMARY KEY (channel, time, id)
 	) WITH CLUSTERING ORDER BY (time DESC)`
 
+// addedColumns are the columns added to the messages table after its first
+// release, that the tables created before have to be altered with.
+var addedColumns = []struct {
+	name string
+	typ  string
+}{
+	{"remote_addr", "text"},
+	{"received", "double"},
+	{"seq", "bigint"},
+}
+
 // DBConfig contains Cassandra DB specific parameters.
 type DBConfig struct {
 	Hosts    []string
@@ -57,5 +75,27 @@ func Connect(cfg DBConfig) (*gocql.Session, error) {
 		return nil, err
 	}
 
+	for _, col := range addedColumns {
+		if err := addColumn(session, cfg.Keyspace, col.name, col.typ); err != nil {
+			return nil, err
+		}
+	}
+
 	return session, nil
 }
+
+func addColumn(session *gocql.Session, keyspace, name, typ string) error {
+	cql := `SELECT column_name FROM system_schema.columns
+	WHERE keyspace_name = ? AND table_name = 'messages' AND column_name = ?`
+
+	var col string
+	err := session.Query(cql, keyspace, name).Scan(&col)
+	if err == nil {
+		return nil
+	}
+	if err != gocql.ErrNotFound {
+		return err
+	}
+
+	return session.Query(fmt.Sprintf("ALTER TABLE messages ADD %s %s", name, typ)).Exec()
+}
@@ -27,9 +27,9 @@ func New(session *gocql.Session) writers.MessageRepository {
 func (cr *cassandraRepository) Save(msg mainflux.Message) error {
 	cql := `INSERT INTO messages (id, channel, subtopic, publisher, protocol,
 			name, unit, value, string_value, bool_value, data_value, value_sum,
-			time, update_time, link)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
-	id := gocql.TimeUUID()
+			time, update_time, link, remote_addr, received, seq)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	id := writers.MessageID(msg)
 
 	var floatVal, valSum *float64
 	var strVal, dataVal *string
@@ -56,5 +56,6 @@ func (cr *cassandraRepository) Save(msg mainflux.Message) error {
 
 	return cr.session.Query(cql, id, msg.GetChannel(), msg.GetSubtopic(), msg.GetPublisher(),
 		msg.GetProtocol(), msg.GetName(), msg.GetUnit(), floatVal,
-		strVal, boolVal, dataVal, valSum, msg.GetTime(), msg.GetUpdateTime(), msg.GetLink()).Exec()
+		strVal, boolVal, dataVal, valSum, msg.GetTime(), msg.GetUpdateTime(), msg.GetLink(),
+		msg.GetRemoteAddr(), msg.GetReceived(), msg.GetSeq()).Exec()
 }
@@ -0,0 +1,68 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package writers
+
+import (
+	"fmt"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/compression"
+)
+
+// compressor compresses the string and data values of the messages of the
+// channels having the compression algorithm set. The algorithm set for the
+// "*" channel applies to all the other channels.
+type compressor struct {
+	algs map[string]string
+}
+
+func newCompressor(algs map[string]string) (*compressor, error) {
+	for ch, alg := range algs {
+		if err := compression.Validate(alg); err != nil {
+			return nil, fmt.Errorf("channel %s: %s", ch, err)
+		}
+	}
+
+	return &compressor{algs: algs}, nil
+}
+
+func (c *compressor) compress(msg mainflux.Message) (mainflux.Message, error) {
+	alg, ok := c.algs[msg.Channel]
+	if !ok {
+		if alg, ok = c.algs["*"]; !ok {
+			return msg, nil
+		}
+	}
+
+	switch v := msg.Value.(type) {
+	case *mainflux.Message_StringValue:
+		val, err := compressValue(alg, v.StringValue)
+		if err != nil {
+			return msg, err
+		}
+		msg.Value = &mainflux.Message_StringValue{StringValue: val}
+	case *mainflux.Message_DataValue:
+		val, err := compressValue(alg, v.DataValue)
+		if err != nil {
+			return msg, err
+		}
+		msg.Value = &mainflux.Message_DataValue{DataValue: val}
+	}
+
+	return msg, nil
+}
+
+// compressValue compresses the value unless it's already compressed, e.g.
+// by the writer that failed to save the redriven message.
+func compressValue(alg, value string) (string, error) {
+	if compression.Compressed(value) {
+		return value, nil
+	}
+
+	return compression.Compress(alg, value)
+}
@@ -0,0 +1,64 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package writers
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/compression"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCompressor(t *testing.T) {
+	cases := map[string]struct {
+		alg string
+		err bool
+	}{
+		"create compressor using snappy":                {compression.Snappy, false},
+		"create compressor using gzip":                  {compression.Gzip, false},
+		"create compressor using unsupported algorithm": {"lzw", true},
+	}
+
+	for desc, tc := range cases {
+		_, err := newCompressor(map[string]string{chanID: tc.alg})
+		assert.Equal(t, tc.err, err != nil, fmt.Sprintf("%s: unexpected error %v", desc, err))
+	}
+}
+
+func TestCompress(t *testing.T) {
+	payload := strings.Repeat(`{"temperature":21.5,"humidity":40}`, 20)
+
+	c, err := newCompressor(map[string]string{chanID: compression.Snappy})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	msg := mainflux.Message{Channel: chanID, Value: &mainflux.Message_StringValue{StringValue: payload}}
+	compressed, err := c.compress(msg)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.True(t, compression.Compressed(compressed.GetStringValue()), "expected string value to be compressed")
+	assert.Equal(t, payload, msg.GetStringValue(), "expected original message to be kept intact")
+
+	recompressed, err := c.compress(compressed)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, compressed.GetStringValue(), recompressed.GetStringValue(), "expected compressed value not to be compressed again")
+
+	msg.Channel = "other"
+	kept, err := c.compress(msg)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, payload, kept.GetStringValue(), "expected value of channel without compression to be kept")
+
+	all, err := newCompressor(map[string]string{"*": compression.Gzip})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	data := mainflux.Message{Channel: "other", Value: &mainflux.Message_DataValue{DataValue: payload}}
+	compressed, err = all.compress(data)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.True(t, compression.Compressed(compressed.GetDataValue()), "expected data value of any channel to be compressed")
+}
@@ -136,11 +136,16 @@ func (repo *influxRepo) tagsOf(msg *mainflux.Message) tags {
 
 func (repo *influxRepo) fieldsOf(msg *mainflux.Message) fields {
 	updateTime := strconv.FormatFloat(msg.UpdateTime, 'f', -1, 64)
+	received := strconv.FormatFloat(msg.Received, 'f', -1, 64)
+	seq := strconv.FormatUint(msg.Seq, 10)
 	ret := fields{
 		"protocol":   msg.Protocol,
 		"unit":       msg.Unit,
 		"link":       msg.Link,
 		"updateTime": updateTime,
+		"remoteAddr": msg.RemoteAddr,
+		"received":   received,
+		"seq":        seq,
 	}
 
 	switch msg.Value.(type) {
@@ -7,7 +7,16 @@
 
 package writers
 
-import "github.com/mainflux/mainflux"
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gofrs/uuid"
+	"github.com/mainflux/mainflux"
+)
+
+// namespace is the UUID namespace of the stored message IDs.
+var namespace = uuid.Must(uuid.FromString("6e0a5f1c-4b3e-5d7a-9c2f-8e1d0b4a7c63"))
 
 // MessageRepository specifies message writing API.
 type MessageRepository interface {
@@ -16,3 +25,19 @@ type MessageRepository interface {
 	// error is returned to indicate  operation failure.
 	Save(mainflux.Message) error
 }
+
+// MessageID returns the ID the message is stored with. The ID is derived
+// from the message channel, publisher, time, name and the ID the message
+// has been assigned at ingress, so that the repositories overwrite, rather
+// than duplicate, the messages that are delivered more than once.
+func MessageID(msg mainflux.Message) string {
+	key := strings.Join([]string{
+		msg.Channel,
+		msg.Publisher,
+		strconv.FormatFloat(msg.Time, 'f', -1, 64),
+		msg.Name,
+		msg.Id,
+	}, "\x00")
+
+	return uuid.NewV5(namespace, key).String()
+}
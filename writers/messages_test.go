@@ -0,0 +1,74 @@
+//
+// Copyright (c) 2019
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package writers_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/writers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessageID(t *testing.T) {
+	msg := mainflux.Message{
+		Channel:   "45",
+		Publisher: "2580",
+		Protocol:  "http",
+		Name:      "temperature",
+		Time:      1563372354.25,
+		Id:        "123e4567-e89b-12d3-a456-426655440000",
+		Value:     &mainflux.Message_FloatValue{FloatValue: 24},
+	}
+	id := writers.MessageID(msg)
+
+	redelivered := msg
+	redelivered.Received = 1563372355
+
+	otherName := msg
+	otherName.Name = "humidity"
+
+	otherTime := msg
+	otherTime.Time = 1563372354.5
+
+	otherID := msg
+	otherID.Id = "123e4567-e89b-12d3-a456-426655440001"
+
+	cases := []struct {
+		desc  string
+		msg   mainflux.Message
+		equal bool
+	}{
+		{
+			desc:  "redelivered message",
+			msg:   redelivered,
+			equal: true,
+		},
+		{
+			desc:  "message with different name",
+			msg:   otherName,
+			equal: false,
+		},
+		{
+			desc:  "message with different time",
+			msg:   otherTime,
+			equal: false,
+		},
+		{
+			desc:  "message with different ingress ID",
+			msg:   otherID,
+			equal: false,
+		},
+	}
+
+	for _, tc := range cases {
+		got := writers.MessageID(tc.msg)
+		assert.Equal(t, tc.equal, got == id, fmt.Sprintf("%s: expected equal IDs %t got %s and %s", tc.desc, tc.equal, id, got))
+	}
+}
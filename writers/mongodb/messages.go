@@ -10,7 +10,9 @@ package mongodb
 import (
 	"context"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/writers"
@@ -26,6 +28,7 @@ type mongoRepo struct {
 
 // Message struct is used as a MongoDB representation of Mainflux message.
 type message struct {
+	ID          string   `bson:"_id"`
 	Channel     string   `bson:"channel,omitempty"`
 	Subtopic    string   `bson:"subtopic,omitempty"`
 	Publisher   string   `bson:"publisher,omitempty"`
@@ -40,6 +43,9 @@ type message struct {
 	Time        float64  `bson:"time,omitempty"`
 	UpdateTime  float64  `bson:"updateTime,omitempty"`
 	Link        string   `bson:"link,omitempty"`
+	RemoteAddr  string   `bson:"remoteAddr,omitempty"`
+	Received    float64  `bson:"received,omitempty"`
+	Seq         uint64   `bson:"seq,omitempty"`
 }
 
 // New returns new MongoDB writer.
@@ -50,6 +56,7 @@ func New(db *mongo.Database) writers.MessageRepository {
 func (repo *mongoRepo) Save(msg mainflux.Message) error {
 	coll := repo.db.Collection(collectionName)
 	m := message{
+		ID:         writers.MessageID(msg),
 		Channel:    msg.Channel,
 		Subtopic:   msg.Subtopic,
 		Publisher:  msg.Publisher,
@@ -59,6 +66,9 @@ func (repo *mongoRepo) Save(msg mainflux.Message) error {
 		Time:       msg.Time,
 		UpdateTime: msg.UpdateTime,
 		Link:       msg.Link,
+		RemoteAddr: msg.RemoteAddr,
+		Received:   msg.Received,
+		Seq:        msg.Seq,
 	}
 
 	switch msg.Value.(type) {
@@ -81,6 +91,7 @@ func (repo *mongoRepo) Save(msg mainflux.Message) error {
 		m.ValueSum = &valueSum
 	}
 
-	_, err := coll.InsertOne(context.Background(), m)
+	opts := options.Replace().SetUpsert(true)
+	_, err := coll.ReplaceOne(context.Background(), bson.M{"_id": m.ID}, m, opts)
 	return err
 }
@@ -10,8 +10,6 @@ package postgres
 import (
 	"errors"
 
-	"github.com/gofrs/uuid"
-
 	"github.com/jmoiron/sqlx"
 	"github.com/lib/pq" // required for DB access
 	"github.com/mainflux/mainflux"
@@ -38,17 +36,17 @@ func New(db *sqlx.DB) writers.MessageRepository {
 func (pr postgresRepo) Save(msg mainflux.Message) error {
 	q := `INSERT INTO messages (id, channel, subtopic, publisher, protocol,
     name, unit, value, string_value, bool_value, data_value, value_sum,
-    time, update_time, link)
+    time, update_time, link, remote_addr, received, seq)
     VALUES (:id, :channel, :subtopic, :publisher, :protocol, :name, :unit,
     :value, :string_value, :bool_value, :data_value, :value_sum,
-    :time, :update_time, :link);`
-
-	dbth, err := toDBMessage(msg)
-	if err != nil {
-		return err
-	}
-
-	if _, err := pr.db.NamedExec(q, dbth); err != nil {
+    :time, :update_time, :link, :remote_addr, :received, :seq)
+    ON CONFLICT (id) DO UPDATE SET subtopic = :subtopic, protocol = :protocol,
+    unit = :unit, value = :value, string_value = :string_value,
+    bool_value = :bool_value, data_value = :data_value, value_sum = :value_sum,
+    update_time = :update_time, link = :link, remote_addr = :remote_addr,
+    received = :received, seq = :seq;`
+
+	if _, err := pr.db.NamedExec(q, toDBMessage(msg)); err != nil {
 		pqErr, ok := err.(*pq.Error)
 		if ok {
 			switch pqErr.Code.Name() {
@@ -79,9 +77,12 @@ type dbMessage struct {
 	Time        float64  `db:"time"`
 	UpdateTime  float64  `db:"update_time"`
 	Link        string   `db:"link"`
+	RemoteAddr  string   `db:"remote_addr"`
+	Received    float64  `db:"received"`
+	Seq         uint64   `db:"seq"`
 }
 
-func toDBMessage(msg mainflux.Message) (dbMessage, error) {
+func toDBMessage(msg mainflux.Message) dbMessage {
 	var floatVal, valSum *float64
 	var strVal, dataVal *string
 	var boolVal *bool
@@ -106,13 +107,8 @@ func toDBMessage(msg mainflux.Message) (dbMessage, error) {
 		valSum = &v
 	}
 
-	id, err := uuid.NewV4()
-	if err != nil {
-		return dbMessage{}, err
-	}
-
 	return dbMessage{
-		ID:          id.String(),
+		ID:          writers.MessageID(msg),
 		Channel:     msg.Channel,
 		Subtopic:    msg.Subtopic,
 		Publisher:   msg.Publisher,
@@ -127,5 +123,8 @@ func toDBMessage(msg mainflux.Message) (dbMessage, error) {
 		Time:        msg.Time,
 		UpdateTime:  msg.UpdateTime,
 		Link:        msg.Link,
-	}, nil
+		RemoteAddr:  msg.RemoteAddr,
+		Received:    msg.Received,
+		Seq:         msg.Seq,
+	}
 }
@@ -0,0 +1,131 @@
+//
+// Copyright (c) 2018
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package writers
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux"
+)
+
+// ErrInvalidSampling indicates a sampling rule that specifies none or both
+// of the sampling modes.
+var ErrInvalidSampling = errors.New("sampling rule must specify either every or interval")
+
+// SamplingRule defines how messages of a single channel are decimated before
+// being saved. Messages are sampled per series, i.e. per publisher, subtopic
+// and record name.
+type SamplingRule struct {
+	// Every stores only the first of every N messages of the series.
+	Every uint64
+
+	// Interval stores only the messages holding the minimal and the maximal
+	// numeric value of the series received within the interval. Messages
+	// that do not hold a numeric value are not sampled.
+	Interval time.Duration
+}
+
+func (sr SamplingRule) validate() error {
+	if (sr.Every > 0) == (sr.Interval > 0) {
+		return ErrInvalidSampling
+	}
+
+	return nil
+}
+
+type series struct {
+	count uint64
+	min   *mainflux.Message
+	max   *mainflux.Message
+}
+
+type sampler struct {
+	mu     sync.Mutex
+	rules  map[string]SamplingRule
+	series map[string]*series
+	save   func(mainflux.Message)
+}
+
+func newSampler(rules map[string]SamplingRule, save func(mainflux.Message)) (*sampler, error) {
+	for ch, rule := range rules {
+		if err := rule.validate(); err != nil {
+			return nil, fmt.Errorf("channel %s: %s", ch, err)
+		}
+	}
+
+	return &sampler{
+		rules:  rules,
+		series: map[string]*series{},
+		save:   save,
+	}, nil
+}
+
+// sample returns true if the message should be saved right away. Messages
+// that are held back within the interval are saved once it elapses.
+func (s *sampler) sample(msg mainflux.Message) bool {
+	rule, ok := s.rules[msg.Channel]
+	if !ok {
+		return true
+	}
+
+	key := fmt.Sprintf("%s:%s:%s:%s", msg.Channel, msg.Publisher, msg.Subtopic, msg.Name)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if rule.Every > 0 {
+		sr, ok := s.series[key]
+		if !ok {
+			sr = &series{}
+			s.series[key] = sr
+		}
+		sr.count++
+
+		return (sr.count-1)%rule.Every == 0
+	}
+
+	if _, ok := msg.Value.(*mainflux.Message_FloatValue); !ok {
+		return true
+	}
+
+	sr, ok := s.series[key]
+	if !ok {
+		s.series[key] = &series{min: &msg, max: &msg}
+		time.AfterFunc(rule.Interval, func() { s.flush(key) })
+		return false
+	}
+
+	if msg.GetFloatValue() < sr.min.GetFloatValue() {
+		sr.min = &msg
+	}
+	if msg.GetFloatValue() > sr.max.GetFloatValue() {
+		sr.max = &msg
+	}
+
+	return false
+}
+
+func (s *sampler) flush(key string) {
+	s.mu.Lock()
+	sr := s.series[key]
+	delete(s.series, key)
+	s.mu.Unlock()
+
+	first, second := sr.min, sr.max
+	if second.Time < first.Time {
+		first, second = second, first
+	}
+
+	s.save(*first)
+	if first != second {
+		s.save(*second)
+	}
+}
@@ -0,0 +1,105 @@
+//
+// Copyright (c) 2018
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package writers
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	chanID    = "1"
+	publisher = "2"
+)
+
+func floatMsg(name string, v, t float64) mainflux.Message {
+	return mainflux.Message{
+		Channel:   chanID,
+		Publisher: publisher,
+		Name:      name,
+		Value:     &mainflux.Message_FloatValue{FloatValue: v},
+		Time:      t,
+	}
+}
+
+func TestNewSampler(t *testing.T) {
+	cases := map[string]struct {
+		rule SamplingRule
+		err  bool
+	}{
+		"create sampler with every rule":    {SamplingRule{Every: 10}, false},
+		"create sampler with interval rule": {SamplingRule{Interval: time.Second}, false},
+		"create sampler with empty rule":    {SamplingRule{}, true},
+		"create sampler with both modes":    {SamplingRule{Every: 10, Interval: time.Second}, true},
+	}
+
+	for desc, tc := range cases {
+		_, err := newSampler(map[string]SamplingRule{chanID: tc.rule}, func(mainflux.Message) {})
+		assert.Equal(t, tc.err, err != nil, fmt.Sprintf("%s: unexpected error %v", desc, err))
+	}
+}
+
+func TestSampleEvery(t *testing.T) {
+	s, err := newSampler(map[string]SamplingRule{chanID: {Every: 3}}, func(mainflux.Message) {})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	saved := map[string]int{}
+	for i := 0; i < 9; i++ {
+		for _, name := range []string{"a", "b"} {
+			if s.sample(floatMsg(name, float64(i), float64(i))) {
+				saved[name]++
+			}
+		}
+	}
+	assert.Equal(t, map[string]int{"a": 3, "b": 3}, saved, "expected 1 of 3 messages of each series to be saved")
+
+	msg := floatMsg("a", 1, 1)
+	msg.Channel = "other"
+	assert.True(t, s.sample(msg), "expected message of channel without rule to be saved")
+}
+
+func TestSampleInterval(t *testing.T) {
+	var mu sync.Mutex
+	saved := []mainflux.Message{}
+	save := func(msg mainflux.Message) {
+		mu.Lock()
+		defer mu.Unlock()
+		saved = append(saved, msg)
+	}
+
+	interval := 50 * time.Millisecond
+	s, err := newSampler(map[string]SamplingRule{chanID: {Interval: interval}}, save)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	values := []float64{5, 9, 1, 7}
+	for i, v := range values {
+		assert.False(t, s.sample(floatMsg("a", v, float64(i))), "expected numeric message to be held back")
+	}
+	assert.False(t, s.sample(floatMsg("b", 3, 0)), "expected numeric message to be held back")
+
+	str := floatMsg("a", 0, 0)
+	str.Value = &mainflux.Message_StringValue{StringValue: "value"}
+	assert.True(t, s.sample(str), "expected non-numeric message to be saved")
+
+	time.Sleep(3 * interval)
+
+	mu.Lock()
+	defer mu.Unlock()
+	byName := map[string][]float64{}
+	for _, msg := range saved {
+		byName[msg.Name] = append(byName[msg.Name], msg.GetFloatValue())
+	}
+	assert.Equal(t, []float64{9, 1}, byName["a"], "expected max and min to be saved in time order")
+	assert.Equal(t, []float64{3}, byName["b"], "expected single message to be saved once")
+}
@@ -8,7 +8,9 @@
 package writers
 
 import (
+	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/gogo/protobuf/proto"
 	"github.com/mainflux/mainflux"
@@ -17,22 +19,51 @@ import (
 )
 
 type consumer struct {
-	nc       *nats.Conn
-	channels map[string]bool
-	repo     MessageRepository
-	logger   log.Logger
+	nc         *nats.Conn
+	queue      string
+	channels   map[string]bool
+	sampler    *sampler
+	compressor *compressor
+	repo       MessageRepository
+	logger     log.Logger
 }
 
 // Start method starts to consume normalized messages received from NATS.
-func Start(nc *nats.Conn, repo MessageRepository, queue string, channels map[string]bool, logger log.Logger) error {
-	c := consumer{
+// Messages that could not be saved are published as dead letters, and can
+// be re-driven to the writer using the queue-specific redrive subject.
+// Messages delivered at most once are dropped instead.
+// Messages of the channels having a sampling rule are decimated before
+// they are saved. Imported historical messages are saved without sampling.
+// String and data values of the channels having the compression algorithm
+// set are stored compressed, which the readers undo transparently.
+func Start(nc *nats.Conn, repo MessageRepository, queue string, channels map[string]bool, sampling map[string]SamplingRule, compression map[string]string, logger log.Logger) error {
+	c := &consumer{
 		nc:       nc,
+		queue:    queue,
 		channels: channels,
 		repo:     repo,
 		logger:   logger,
 	}
 
-	_, err := nc.QueueSubscribe(mainflux.OutputSenML, queue, c.consume)
+	s, err := newSampler(sampling, c.save)
+	if err != nil {
+		return err
+	}
+	c.sampler = s
+
+	if c.compressor, err = newCompressor(compression); err != nil {
+		return err
+	}
+
+	if _, err := nc.QueueSubscribe(mainflux.OutputSenML, queue, c.consume); err != nil {
+		return err
+	}
+
+	if _, err := nc.QueueSubscribe(mainflux.Import, queue, c.consumeImported); err != nil {
+		return err
+	}
+
+	_, err = nc.QueueSubscribe(fmt.Sprintf("%s.%s", mainflux.Redrive, queue), queue, c.consume)
 	return err
 }
 
@@ -40,6 +71,26 @@ func (c *consumer) consume(m *nats.Msg) {
 	msg := &mainflux.Message{}
 	if err := proto.Unmarshal(m.Data, msg); err != nil {
 		c.logger.Warn(fmt.Sprintf("Failed to unmarshal received message: %s", err))
+		c.deadLetter(m.Data, err)
+		return
+	}
+
+	if !c.channelExists(msg.GetChannel()) {
+		return
+	}
+
+	if !c.sampler.sample(*msg) {
+		return
+	}
+
+	c.save(*msg)
+}
+
+func (c *consumer) consumeImported(m *nats.Msg) {
+	msg := &mainflux.Message{}
+	if err := proto.Unmarshal(m.Data, msg); err != nil {
+		c.logger.Warn(fmt.Sprintf("Failed to unmarshal imported message: %s", err))
+		c.deadLetter(m.Data, err)
 		return
 	}
 
@@ -47,10 +98,50 @@ func (c *consumer) consume(m *nats.Msg) {
 		return
 	}
 
-	if err := c.repo.Save(*msg); err != nil {
+	c.save(*msg)
+}
+
+func (c *consumer) save(msg mainflux.Message) {
+	stored, err := c.compressor.compress(msg)
+	if err != nil {
+		c.logger.Warn(fmt.Sprintf("Failed to compress message: %s", err))
+		stored = msg
+	}
+
+	if err := c.repo.Save(stored); err != nil {
 		c.logger.Warn(fmt.Sprintf("Failed to save message: %s", err))
+		if msg.Delivery == mainflux.DeliveryAtMostOnce {
+			return
+		}
+
+		data, mErr := proto.Marshal(&msg)
+		if mErr != nil {
+			c.logger.Warn(fmt.Sprintf("Failed to marshal message: %s", mErr))
+			return
+		}
+		c.deadLetter(data, err)
+	}
+}
+
+func (c *consumer) deadLetter(data []byte, reason error) {
+	dl := mainflux.DeadLetter{
+		Stage:   c.queue,
+		Reason:  reason.Error(),
+		Subject: fmt.Sprintf("%s.%s", mainflux.Redrive, c.queue),
+		Payload: data,
+		Created: time.Now(),
+	}
+
+	payload, err := json.Marshal(dl)
+	if err != nil {
+		c.logger.Warn(fmt.Sprintf("Failed to marshal dead letter: %s", err))
 		return
 	}
+
+	subject := fmt.Sprintf("%s.%s", mainflux.DeadLetters, c.queue)
+	if err := c.nc.Publish(subject, payload); err != nil {
+		c.logger.Warn(fmt.Sprintf("Failed to publish dead letter: %s", err))
+	}
 }
 
 func (c *consumer) channelExists(channel string) bool {
@@ -79,14 +79,22 @@ var _ Service = (*adapterService)(nil)
 
 type adapterService struct {
 	pubsub Service
+	pub    mainflux.MessagePublisher
 }
 
-// New instantiates the WS adapter implementation.
-func New(pubsub Service) Service {
-	return &adapterService{pubsub}
+// New instantiates the WS adapter implementation. Messages are passed
+// through the publish hooks, in the given order, before being published.
+func New(pubsub Service, hooks ...mainflux.PublishHook) Service {
+	as := &adapterService{pubsub: pubsub}
+	as.pub = mainflux.Chain(mainflux.PublisherFunc(as.publish), hooks...)
+	return as
 }
 
 func (as *adapterService) Publish(msg mainflux.RawMessage) error {
+	return as.pub.Publish(msg)
+}
+
+func (as *adapterService) publish(msg mainflux.RawMessage) error {
 	if err := as.pubsub.Publish(msg); err != nil {
 		switch err {
 		case broker.ErrConnectionClosed, broker.ErrInvalidConnection:
@@ -20,6 +20,7 @@ import (
 	"github.com/go-zoo/bone"
 	"github.com/gorilla/websocket"
 	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/identifiers"
 	log "github.com/mainflux/mainflux/logger"
 	"github.com/mainflux/mainflux/things"
 	"github.com/mainflux/mainflux/ws"
@@ -28,7 +29,11 @@ import (
 	"google.golang.org/grpc/status"
 )
 
-const protocol = "ws"
+const (
+	protocol    = "ws"
+	resumeQuery = "resume"
+	resumeToken = "Resume-Token"
+)
 
 var (
 	errUnauthorizedAccess = errors.New("missing or invalid credentials provided")
@@ -44,39 +49,31 @@ var (
 		},
 	}
 	auth              mainflux.ThingsServiceClient
+	invitations       things.KeyProvider
 	logger            log.Logger
 	channelPartRegExp = regexp.MustCompile(`^/channels/([\w\-]+)/messages(/[^?]*)?(\?.*)?$`)
 )
 
-// MakeHandler returns http handler with handshake endpoint.
-func MakeHandler(svc ws.Service, tc mainflux.ThingsServiceClient, l log.Logger) http.Handler {
+// MakeHandler returns http handler with handshake endpoint. Subscriptions
+// of disconnected clients are kept alive by the provided sessions, if
+// enabled, so that the clients can resume them. Channel invitations are
+// verified using the provided key provider, unless it is nil.
+func MakeHandler(svc ws.Service, s *ws.Sessions, tc mainflux.ThingsServiceClient, keys things.KeyProvider, l log.Logger) http.Handler {
 	auth = tc
+	invitations = keys
 	logger = l
 
 	mux := bone.New()
-	mux.GetFunc("/channels/:id/messages", handshake(svc))
-	mux.GetFunc("/channels/:id/messages/*", handshake(svc))
+	mux.GetFunc("/channels/:id/messages", handshake(svc, s))
+	mux.GetFunc("/channels/:id/messages/*", handshake(svc, s))
 	mux.GetFunc("/version", mainflux.Version("websocket"))
 	mux.Handle("/metrics", promhttp.Handler())
 
 	return mux
 }
 
-func handshake(svc ws.Service) http.HandlerFunc {
+func handshake(svc ws.Service, sessions *ws.Sessions) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		sub, err := authorize(r)
-		if err != nil {
-			switch err {
-			case things.ErrUnauthorizedAccess:
-				w.WriteHeader(http.StatusForbidden)
-				return
-			default:
-				logger.Warn(fmt.Sprintf("Failed to authorize: %s", err))
-				w.WriteHeader(http.StatusServiceUnavailable)
-				return
-			}
-		}
-
 		channelParts := channelPartRegExp.FindStringSubmatch(r.RequestURI)
 		if len(channelParts) < 2 {
 			logger.Warn(fmt.Sprintf("Empty channel id or malformed url"))
@@ -84,28 +81,68 @@ func handshake(svc ws.Service) http.HandlerFunc {
 			return
 		}
 
-		sub.subtopic, err = parseSubtopic(channelParts[2])
+		subtopic, err := parseSubtopic(channelParts[2])
 		if err != nil {
 			logger.Warn(fmt.Sprintf("Empty channel id or malformed url"))
 			w.WriteHeader(http.StatusBadRequest)
 			return
 		}
 
+		sub, err := authorize(r, subtopic)
+		if err != nil {
+			switch err {
+			case things.ErrUnauthorizedAccess:
+				w.WriteHeader(http.StatusForbidden)
+				return
+			case identifiers.ErrMalformedID:
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			default:
+				logger.Warn(fmt.Sprintf("Failed to authorize: %s", err))
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+		}
+		sub.subtopic = subtopic
+
+		sub.remoteAddr = remoteAddr(r)
+		sub.sessions = sessions
+		var header http.Header
+		if sessions.Enabled() {
+			sub.token = bone.GetQuery(r, resumeQuery)
+			if len(sub.token) == 0 {
+				t, err := sessions.Token()
+				if err != nil {
+					logger.Warn(fmt.Sprintf("Failed to generate resume token: %s", err))
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+				sub.token = []string{t}
+			}
+			header = http.Header{resumeToken: sub.token[:1]}
+		}
+
 		// Create new ws connection.
-		conn, err := upgrader.Upgrade(w, r, nil)
+		conn, err := upgrader.Upgrade(w, r, header)
 		if err != nil {
 			logger.Warn(fmt.Sprintf("Failed to upgrade connection to websocket: %s", err))
 			return
 		}
 		sub.conn = conn
 
-		sub.channel = ws.NewChannel()
-		if err := svc.Subscribe(sub.chanID, sub.subtopic, sub.channel); err != nil {
-			logger.Warn(fmt.Sprintf("Failed to subscribe to NATS subject: %s", err))
-			conn.Close()
-			return
+		missed, resumed := sub.resume()
+		if !resumed {
+			sub.channel = ws.NewChannel()
+			if err := svc.Subscribe(sub.chanID, sub.subtopic, sub.channel); err != nil {
+				logger.Warn(fmt.Sprintf("Failed to subscribe to NATS subject: %s", err))
+				conn.Close()
+				return
+			}
 		}
-		go sub.listen()
+
+		sub.stop = make(chan struct{})
+		sub.stopped = make(chan struct{})
+		go sub.listen(missed)
 
 		// Start listening for messages from NATS.
 		go sub.broadcast(svc)
@@ -144,7 +181,7 @@ func parseSubtopic(subtopic string) (string, error) {
 	return subtopic, nil
 }
 
-func authorize(r *http.Request) (subscription, error) {
+func authorize(r *http.Request, subtopic string) (subscription, error) {
 	authKey := r.Header.Get("Authorization")
 	if authKey == "" {
 		authKeys := bone.GetQuery(r, "authorization")
@@ -154,12 +191,56 @@ func authorize(r *http.Request) (subscription, error) {
 		authKey = authKeys[0]
 	}
 
-	chanID := bone.GetValue(r, "id")
+	chanID, err := identifiers.Parse(bone.GetValue(r, "id"))
+	if err != nil {
+		return subscription{}, err
+	}
+
+	if invitations != nil {
+		if inv, err := invitations.ParseInvitation(authKey); err == nil {
+			if inv.ChannelID != chanID {
+				return subscription{}, things.ErrUnauthorizedAccess
+			}
+
+			sub := subscription{
+				pubID:         inv.ID,
+				chanID:        chanID,
+				subscribeOnly: true,
+			}
+			return sub, nil
+		}
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 	defer cancel()
 
-	id, err := auth.CanAccess(ctx, &mainflux.AccessReq{Token: authKey, ChanID: chanID})
+	req := &mainflux.AccessReq{Token: authKey, ChanID: chanID, Action: mainflux.ActionPublish, Subtopic: subtopic}
+	id, err := auth.CanAccess(ctx, req)
+	if err == nil {
+		sub := subscription{
+			pubID:  id.GetValue(),
+			chanID: chanID,
+		}
+		return sub, nil
+	}
+
+	if e, ok := status.FromError(err); !ok || e.Code() != codes.PermissionDenied {
+		return subscription{}, err
+	}
+
+	// Things using subscribe-only keys can't publish over the connection.
+	req.Action = mainflux.ActionSubscribe
+	if id, err := auth.CanAccess(ctx, req); err == nil {
+		sub := subscription{
+			pubID:         id.GetValue(),
+			chanID:        chanID,
+			subscribeOnly: true,
+		}
+		return sub, nil
+	}
+
+	// Users owning the channel can subscribe to it using their tokens.
+	user, err := auth.CanRead(ctx, req)
 	if err != nil {
 		e, ok := status.FromError(err)
 		if ok && e.Code() == codes.PermissionDenied {
@@ -169,38 +250,93 @@ func authorize(r *http.Request) (subscription, error) {
 	}
 
 	sub := subscription{
-		pubID:  id.GetValue(),
-		chanID: chanID,
+		pubID:         user.GetValue(),
+		chanID:        chanID,
+		subscribeOnly: true,
 	}
 
 	return sub, nil
 }
 
 type subscription struct {
-	pubID    string
-	chanID   string
-	subtopic string
-	conn     *websocket.Conn
-	channel  *ws.Channel
+	pubID         string
+	chanID        string
+	subtopic      string
+	subscribeOnly bool
+	remoteAddr    string
+	token         []string
+	sessions      *ws.Sessions
+	conn          *websocket.Conn
+	channel       *ws.Channel
+	stop          chan struct{}
+	stopped       chan struct{}
+}
+
+// remoteAddr returns the address of the client, as forwarded by the reverse
+// proxy in front of the adapter, or the address of the peer otherwise.
+func remoteAddr(r *http.Request) string {
+	if ip := r.Header.Get("X-Real-IP"); ip != "" {
+		return ip
+	}
+	return r.RemoteAddr
+}
+
+func (sub subscription) key() string {
+	return fmt.Sprintf("%s:%s:%s", sub.pubID, sub.chanID, sub.subtopic)
+}
+
+// resume takes over the channel of the subscription identified by the resume
+// token, and returns the messages missed while the client was disconnected.
+func (sub *subscription) resume() ([]mainflux.RawMessage, bool) {
+	if !sub.sessions.Enabled() {
+		return nil, false
+	}
+
+	channel, missed, err := sub.sessions.Resume(sub.token[0], sub.key())
+	if err != nil {
+		return nil, false
+	}
+	sub.channel = channel
+
+	return missed, true
+}
+
+// disconnect stops message delivery and either keeps the subscription alive
+// for resumption or closes it.
+func (sub subscription) disconnect() {
+	close(sub.stop)
+	<-sub.stopped
+
+	if sub.sessions.Enabled() {
+		sub.sessions.Detach(sub.token[0], sub.key(), sub.channel)
+		return
+	}
+	sub.channel.Close()
 }
 
 func (sub subscription) broadcast(svc ws.Service) {
 	for {
 		_, payload, err := sub.conn.ReadMessage()
 		if websocket.IsUnexpectedCloseError(err) {
-			sub.channel.Close()
+			sub.disconnect()
 			return
 		}
 		if err != nil {
 			logger.Warn(fmt.Sprintf("Failed to read message: %s", err))
+			sub.disconnect()
 			return
 		}
+		if sub.subscribeOnly {
+			logger.Warn(fmt.Sprintf("Dropped message published by subscriber %s", sub.pubID))
+			continue
+		}
 		msg := mainflux.RawMessage{
-			Channel:   sub.chanID,
-			Subtopic:  sub.subtopic,
-			Publisher: sub.pubID,
-			Protocol:  protocol,
-			Payload:   payload,
+			Channel:    sub.chanID,
+			Subtopic:   sub.subtopic,
+			Publisher:  sub.pubID,
+			Protocol:   protocol,
+			Payload:    payload,
+			RemoteAddr: sub.remoteAddr,
 		}
 		if err := svc.Publish(msg); err != nil {
 			logger.Warn(fmt.Sprintf("Failed to publish message to NATS: %s", err))
@@ -213,10 +349,28 @@ func (sub subscription) broadcast(svc ws.Service) {
 	}
 }
 
-func (sub subscription) listen() {
-	for msg := range sub.channel.Messages {
-		if err := sub.conn.WriteMessage(websocket.TextMessage, msg.Payload); err != nil {
-			logger.Warn(fmt.Sprintf("Failed to broadcast message to thing: %s", err))
+func (sub subscription) listen(missed []mainflux.RawMessage) {
+	defer close(sub.stopped)
+
+	for _, msg := range missed {
+		sub.write(msg)
+	}
+
+	for {
+		select {
+		case msg, ok := <-sub.channel.Messages:
+			if !ok {
+				return
+			}
+			sub.write(msg)
+		case <-sub.stop:
+			return
 		}
 	}
 }
+
+func (sub subscription) write(msg mainflux.RawMessage) {
+	if err := sub.conn.WriteMessage(websocket.TextMessage, msg.Payload); err != nil {
+		logger.Warn(fmt.Sprintf("Failed to broadcast message to thing: %s", err))
+	}
+}
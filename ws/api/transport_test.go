@@ -14,26 +14,34 @@ import (
 	"net/url"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/mainflux/mainflux"
 	log "github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/things"
+	thmocks "github.com/mainflux/mainflux/things/mocks"
 	"github.com/mainflux/mainflux/ws"
 	"github.com/mainflux/mainflux/ws/api"
 	"github.com/mainflux/mainflux/ws/mocks"
 	broker "github.com/nats-io/go-nats"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 const (
-	id       = "1"
-	token    = "token"
-	protocol = "ws"
+	id        = "123e4567-e89b-12d3-a456-000000000001"
+	wrongID   = "123e4567-e89b-12d3-a456-000000000000"
+	token     = "token"
+	userToken = "userToken"
+	protocol  = "ws"
+	window    = time.Minute
 )
 
 var (
 	msg     = []byte(`[{"n":"current","t":-5,"v":1.2}]`)
 	channel = ws.NewChannel()
+	keys    = thmocks.NewKeyProvider()
 )
 
 func newService() ws.Service {
@@ -44,12 +52,12 @@ func newService() ws.Service {
 
 func newHTTPServer(svc ws.Service, tc mainflux.ThingsServiceClient) *httptest.Server {
 	logger, _ := log.New(os.Stdout, log.Info.String())
-	mux := api.MakeHandler(svc, tc, logger)
+	mux := api.MakeHandler(svc, ws.NewSessions(window, 10), tc, keys, logger)
 	return httptest.NewServer(mux)
 }
 
 func newThingsClient() mainflux.ThingsServiceClient {
-	return mocks.NewThingsClient(map[string]string{token: id})
+	return mocks.NewThingsClient(map[string]string{token: id}, map[string]string{userToken: id})
 }
 
 func makeURL(tsURL, chanID, subtopic, auth string, header bool) string {
@@ -90,8 +98,9 @@ func TestHandshake(t *testing.T) {
 		msg      []byte
 	}{
 		{"connect and send message", id, "", true, token, http.StatusSwitchingProtocols, msg},
-		{"connect to non-existent channel", "0", "", true, token, http.StatusSwitchingProtocols, []byte{}},
+		{"connect to non-existent channel", wrongID, "", true, token, http.StatusSwitchingProtocols, []byte{}},
 		{"connect to invalid channel id", "", "", true, token, http.StatusBadRequest, []byte{}},
+		{"connect to malformed channel id", "invalid", "", true, token, http.StatusBadRequest, []byte{}},
 		{"connect with empty token", id, "", true, "", http.StatusForbidden, []byte{}},
 		{"connect with invalid token", id, "", true, "invalid", http.StatusForbidden, []byte{}},
 		{"connect unable to authorize", id, "", true, mocks.ServiceErrToken, http.StatusServiceUnavailable, []byte{}},
@@ -113,3 +122,128 @@ func TestHandshake(t *testing.T) {
 		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s\n", tc.desc, err))
 	}
 }
+
+func TestResume(t *testing.T) {
+	thingsClient := newThingsClient()
+	svc := newService()
+	ts := newHTTPServer(svc, thingsClient)
+	defer ts.Close()
+
+	conn, res, err := handshake(ts.URL, id, "", token, true)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	resume := res.Header.Get("Resume-Token")
+	assert.NotEmpty(t, resume, "expected non-empty resume token")
+
+	conn.Close()
+	// Wait for the adapter to detect disconnection.
+	time.Sleep(100 * time.Millisecond)
+
+	err = svc.Publish(mainflux.RawMessage{Channel: id, Payload: msg})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		resume string
+		token  string
+	}{
+		{
+			desc:   "resume subscription using valid resume token",
+			resume: resume,
+			token:  resume,
+		},
+		{
+			desc:   "resume subscription using invalid resume token",
+			resume: "invalid",
+			token:  "invalid",
+		},
+	}
+
+	for _, tc := range cases {
+		url := fmt.Sprintf("%s&resume=%s", makeURL(ts.URL, id, "", token, false), tc.resume)
+		conn, res, err := websocket.DefaultDialer.Dial(url, http.Header{})
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		assert.Equal(t, tc.token, res.Header.Get("Resume-Token"), fmt.Sprintf("%s: expected resume token %s got %s", tc.desc, tc.token, res.Header.Get("Resume-Token")))
+
+		if tc.resume == resume {
+			conn.SetReadDeadline(time.Now().Add(time.Second))
+			_, payload, err := conn.ReadMessage()
+			assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+			assert.Equal(t, msg, payload, fmt.Sprintf("%s: expected replayed message %s got %s", tc.desc, msg, payload))
+		}
+		conn.Close()
+	}
+}
+
+func TestInvitation(t *testing.T) {
+	thingsClient := newThingsClient()
+	svc := newService()
+	ts := newHTTPServer(svc, thingsClient)
+	defer ts.Close()
+
+	valid, err := keys.IssueInvitation(things.Invitation{ID: "invitation", ChannelID: id, ExpiresAt: time.Now().Add(time.Hour)})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	other, err := keys.IssueInvitation(things.Invitation{ID: "other", ChannelID: wrongID, ExpiresAt: time.Now().Add(time.Hour)})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc       string
+		invitation string
+		status     int
+	}{
+		{"subscribe using invitation", valid, http.StatusSwitchingProtocols},
+		{"subscribe using invitation to other channel", other, http.StatusForbidden},
+	}
+
+	for _, tc := range cases {
+		_, res, _ := handshake(ts.URL, id, "", tc.invitation, true)
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d\n", tc.desc, tc.status, res.StatusCode))
+	}
+
+	conn, _, err := handshake(ts.URL, id, "", valid, true)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	defer conn.Close()
+
+	// Message published using the invitation would be delivered back to the
+	// connection by the mock service.
+	err = conn.WriteMessage(websocket.TextMessage, msg)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	conn.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+	_, _, err = conn.ReadMessage()
+	assert.NotNil(t, err, "expected message published using invitation to be dropped")
+}
+
+func TestUserSubscriber(t *testing.T) {
+	thingsClient := newThingsClient()
+	svc := newService()
+	ts := newHTTPServer(svc, thingsClient)
+	defer ts.Close()
+
+	cases := []struct {
+		desc   string
+		chanID string
+		token  string
+		status int
+	}{
+		{"subscribe using user token", id, userToken, http.StatusSwitchingProtocols},
+		{"subscribe using user token to other user's channel", wrongID, userToken, http.StatusForbidden},
+		{"subscribe using invalid user token", id, "invalid", http.StatusForbidden},
+	}
+
+	for _, tc := range cases {
+		_, res, _ := handshake(ts.URL, tc.chanID, "", tc.token, true)
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d\n", tc.desc, tc.status, res.StatusCode))
+	}
+
+	conn, _, err := handshake(ts.URL, id, "", userToken, true)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	defer conn.Close()
+
+	// Message published using the user token would be delivered back to the
+	// connection by the mock service.
+	err = conn.WriteMessage(websocket.TextMessage, msg)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	conn.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+	_, _, err = conn.ReadMessage()
+	assert.NotNil(t, err, "expected message published using user token to be dropped")
+}
@@ -25,11 +25,17 @@ const ServiceErrToken = "unavailable"
 
 type thingsClient struct {
 	things map[string]string
+	users  map[string]string
 }
 
-// NewThingsClient returns mock implementation of things service client.
-func NewThingsClient(data map[string]string) mainflux.ThingsServiceClient {
-	return &thingsClient{data}
+// NewThingsClient returns mock implementation of things service client. The
+// things are mapped by their keys, while the channels are mapped by the
+// tokens of the users owning them.
+func NewThingsClient(things, users map[string]string) mainflux.ThingsServiceClient {
+	return &thingsClient{
+		things: things,
+		users:  users,
+	}
 }
 
 func (tc thingsClient) CanAccess(ctx context.Context, req *mainflux.AccessReq, opts ...grpc.CallOption) (*mainflux.ThingID, error) {
@@ -56,3 +62,20 @@ func (tc thingsClient) CanAccess(ctx context.Context, req *mainflux.AccessReq, o
 func (tc thingsClient) Identify(ctx context.Context, req *mainflux.Token, opts ...grpc.CallOption) (*mainflux.ThingID, error) {
 	return nil, nil
 }
+
+func (tc thingsClient) IdentifyBatch(ctx context.Context, req *mainflux.Tokens, opts ...grpc.CallOption) (*mainflux.ThingIDs, error) {
+	return nil, nil
+}
+
+func (tc thingsClient) CanRead(ctx context.Context, req *mainflux.AccessReq, opts ...grpc.CallOption) (*mainflux.UserID, error) {
+	chanID, ok := tc.users[req.GetToken()]
+	if !ok || chanID != req.GetChanID() {
+		return nil, status.Error(codes.PermissionDenied, "invalid credentials provided")
+	}
+
+	return &mainflux.UserID{Value: req.GetToken()}, nil
+}
+
+func (tc thingsClient) ListChannelsByThing(ctx context.Context, req *mainflux.ThingID, opts ...grpc.CallOption) (*mainflux.ChannelIDs, error) {
+	return &mainflux.ChannelIDs{}, nil
+}
@@ -10,11 +10,13 @@ package nats
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/sony/gobreaker"
 
 	"github.com/gogo/protobuf/proto"
 	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/publish"
 	"github.com/mainflux/mainflux/ws"
 	broker "github.com/nats-io/go-nats"
 )
@@ -28,12 +30,15 @@ const (
 var _ ws.Service = (*natsPubSub)(nil)
 
 type natsPubSub struct {
-	nc *broker.Conn
-	cb *gobreaker.CircuitBreaker
+	nc         *broker.Conn
+	cb         *gobreaker.CircuitBreaker
+	ackTimeout time.Duration
 }
 
-// New instantiates NATS message publisher.
-func New(nc *broker.Conn) ws.Service {
+// New instantiates NATS message publisher. Messages of the channels with the
+// at-least-once delivery are published only once they are acknowledged,
+// which has to happen within the given timeout.
+func New(nc *broker.Conn, ackTimeout time.Duration) ws.Service {
 	st := gobreaker.Settings{
 		Name: "NATS",
 		ReadyToTrip: func(counts gobreaker.Counts) bool {
@@ -42,7 +47,7 @@ func New(nc *broker.Conn) ws.Service {
 		},
 	}
 	cb := gobreaker.NewCircuitBreaker(st)
-	return &natsPubSub{nc, cb}
+	return &natsPubSub{nc, cb, ackTimeout}
 }
 
 func (pubsub *natsPubSub) fmtSubject(chanID, subtopic string) string {
@@ -54,13 +59,8 @@ func (pubsub *natsPubSub) fmtSubject(chanID, subtopic string) string {
 }
 
 func (pubsub *natsPubSub) Publish(msg mainflux.RawMessage) error {
-	data, err := proto.Marshal(&msg)
-	if err != nil {
-		return err
-	}
-
 	subject := pubsub.fmtSubject(msg.Channel, msg.Subtopic)
-	return pubsub.nc.Publish(subject, data)
+	return publish.Deliver(pubsub.nc, subject, msg, pubsub.ackTimeout)
 }
 
 func (pubsub *natsPubSub) Subscribe(chanID, subtopic string, channel *ws.Channel) error {
@@ -0,0 +1,164 @@
+//
+// Copyright (c) 2018
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package ws
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux"
+)
+
+const tokenSize = 16
+
+// ErrSessionNotFound indicates that there is no session to resume.
+var ErrSessionNotFound = errors.New("session not found")
+
+// Sessions keeps subscriptions of disconnected clients alive for the
+// configured window, buffering the messages they miss, so that the clients
+// can resume their subscriptions and replay the missed messages.
+type Sessions struct {
+	mu       sync.Mutex
+	window   time.Duration
+	size     int
+	sessions map[string]*session
+}
+
+type session struct {
+	key     string
+	channel *Channel
+	buffer  []mainflux.RawMessage
+	stop    chan struct{}
+	discard chan struct{}
+	done    chan struct{}
+}
+
+// NewSessions instantiates session registry. Subscriptions of disconnected
+// clients are kept alive for the provided window, and at most size most
+// recent messages are buffered per session. Zero window disables resumption.
+func NewSessions(window time.Duration, size int) *Sessions {
+	return &Sessions{
+		window:   window,
+		size:     size,
+		sessions: make(map[string]*session),
+	}
+}
+
+// Enabled determines whether subscriptions can be resumed.
+func (s *Sessions) Enabled() bool {
+	return s != nil && s.window > 0
+}
+
+// Token generates new resume token.
+func (s *Sessions) Token() (string, error) {
+	b := make([]byte, tokenSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+// Detach keeps the subscription of the disconnected client, identified by
+// the provided resume token and subscription key, alive for the resume
+// window. The channel is closed once the window expires.
+func (s *Sessions) Detach(token, key string, channel *Channel) {
+	sess := &session{
+		key:     key,
+		channel: channel,
+		buffer:  []mainflux.RawMessage{},
+		stop:    make(chan struct{}),
+		discard: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+
+	s.mu.Lock()
+	if prev, ok := s.sessions[token]; ok {
+		delete(s.sessions, token)
+		close(prev.discard)
+	}
+	s.sessions[token] = sess
+	s.mu.Unlock()
+
+	go s.buffer(token, sess)
+}
+
+// Resume returns the channel of the session identified by the provided
+// resume token, along with the messages buffered while the client was
+// disconnected. The session must belong to the same subscription key.
+func (s *Sessions) Resume(token, key string) (*Channel, []mainflux.RawMessage, error) {
+	s.mu.Lock()
+	sess, ok := s.sessions[token]
+	if !ok || sess.key != key {
+		s.mu.Unlock()
+		return nil, nil, ErrSessionNotFound
+	}
+	delete(s.sessions, token)
+	s.mu.Unlock()
+
+	close(sess.stop)
+	<-sess.done
+
+	return sess.channel, sess.buffer, nil
+}
+
+func (s *Sessions) buffer(token string, sess *session) {
+	defer close(sess.done)
+
+	timer := time.NewTimer(s.window)
+	defer timer.Stop()
+
+	discard := sess.discard
+	expired := false
+	for {
+		select {
+		case msg, ok := <-sess.channel.Messages:
+			if !ok {
+				return
+			}
+			if expired {
+				continue
+			}
+
+			sess.buffer = append(sess.buffer, msg)
+			if len(sess.buffer) > s.size {
+				sess.buffer = sess.buffer[len(sess.buffer)-s.size:]
+			}
+		case <-sess.stop:
+			return
+		case <-discard:
+			discard = nil
+			expired = s.expire(sess, expired)
+		case <-timer.C:
+			s.mu.Lock()
+			if s.sessions[token] != sess {
+				// Session is being resumed.
+				s.mu.Unlock()
+				continue
+			}
+			delete(s.sessions, token)
+			s.mu.Unlock()
+
+			expired = s.expire(sess, expired)
+		}
+	}
+}
+
+// expire closes the session channel. Messages are drained until the channel
+// is closed, so that pending sends don't block closing.
+func (s *Sessions) expire(sess *session, expired bool) bool {
+	if !expired {
+		sess.buffer = nil
+		go sess.channel.Close()
+	}
+
+	return true
+}
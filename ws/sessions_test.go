@@ -0,0 +1,102 @@
+//
+// Copyright (c) 2018
+// Mainflux
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package ws_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/ws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	sessionKey = "1:1:"
+	bufferSize = 2
+)
+
+func TestResume(t *testing.T) {
+	sessions := ws.NewSessions(time.Minute, bufferSize)
+	token, err := sessions.Token()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	channel := ws.NewChannel()
+	sessions.Detach(token, sessionKey, channel)
+
+	msgs := []mainflux.RawMessage{}
+	for i := 0; i < bufferSize+1; i++ {
+		m := msg
+		m.Payload = []byte(fmt.Sprintf("%d", i))
+		msgs = append(msgs, m)
+		channel.Send(m)
+	}
+
+	cases := []struct {
+		desc   string
+		token  string
+		key    string
+		missed []mainflux.RawMessage
+		err    error
+	}{
+		{
+			desc:   "resume session with wrong key",
+			token:  token,
+			key:    "wrong",
+			missed: nil,
+			err:    ws.ErrSessionNotFound,
+		},
+		{
+			desc:   "resume session with wrong token",
+			token:  "wrong",
+			key:    sessionKey,
+			missed: nil,
+			err:    ws.ErrSessionNotFound,
+		},
+		{
+			desc:   "resume session",
+			token:  token,
+			key:    sessionKey,
+			missed: msgs[1:],
+			err:    nil,
+		},
+		{
+			desc:   "resume already resumed session",
+			token:  token,
+			key:    sessionKey,
+			missed: nil,
+			err:    ws.ErrSessionNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		ch, missed, err := sessions.Resume(tc.token, tc.key)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.missed, missed, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.missed, missed))
+		if err == nil {
+			assert.Equal(t, channel, ch, fmt.Sprintf("%s: expected resumed channel", tc.desc))
+		}
+	}
+}
+
+func TestResumeExpired(t *testing.T) {
+	sessions := ws.NewSessions(10*time.Millisecond, bufferSize)
+	token, err := sessions.Token()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	channel := ws.NewChannel()
+	sessions.Detach(token, sessionKey, channel)
+
+	closed := <-channel.Closed
+	assert.True(t, closed, "expected expired session channel to be closed")
+
+	_, _, err = sessions.Resume(token, sessionKey)
+	assert.Equal(t, ws.ErrSessionNotFound, err, fmt.Sprintf("expected %s got %s\n", ws.ErrSessionNotFound, err))
+}